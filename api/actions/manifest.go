@@ -7,6 +7,7 @@ import (
 	"code.cloudfoundry.org/korifi/api/actions/manifest"
 	"code.cloudfoundry.org/korifi/api/actions/shared"
 	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/config"
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	"code.cloudfoundry.org/korifi/api/payloads"
 	"code.cloudfoundry.org/korifi/api/repositories"
@@ -30,13 +31,13 @@ type Applier interface {
 
 type Manifest struct {
 	domainRepo        shared.CFDomainRepository
-	defaultDomainName string
+	defaultDomainName *config.Reloadable[string]
 	stateCollector    StateCollector
 	normalizer        Normalizer
 	applier           Applier
 }
 
-func NewManifest(domainRepo shared.CFDomainRepository, defaultDomainName string, stateCollector StateCollector, normalizer Normalizer, applier Applier,
+func NewManifest(domainRepo shared.CFDomainRepository, defaultDomainName *config.Reloadable[string], stateCollector StateCollector, normalizer Normalizer, applier Applier,
 ) *Manifest {
 	return &Manifest{
 		domainRepo:        domainRepo,
@@ -70,7 +71,7 @@ func (a *Manifest) Apply(ctx context.Context, authInfo authorization.Info, space
 
 func (a *Manifest) ensureDefaultDomainConfigured(ctx context.Context, authInfo authorization.Info) error {
 	domains, err := a.domainRepo.ListDomains(ctx, authInfo, repositories.ListDomainsMessage{
-		Names: []string{a.defaultDomainName},
+		Names: []string{a.defaultDomainName.Get()},
 	})
 	if err != nil {
 		return apierrors.FromK8sError(err, repositories.DomainResourceType)
@@ -80,7 +81,7 @@ func (a *Manifest) ensureDefaultDomainConfigured(ctx context.Context, authInfo a
 	if err != nil {
 		return apierrors.AsUnprocessableEntity(
 			err,
-			fmt.Sprintf("The configured default domain %q was not found", a.defaultDomainName),
+			fmt.Sprintf("The configured default domain %q was not found", a.defaultDomainName.Get()),
 			apierrors.NotFoundError{},
 		)
 	}
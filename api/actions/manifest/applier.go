@@ -14,6 +14,7 @@ import (
 	"code.cloudfoundry.org/korifi/api/repositories"
 	"code.cloudfoundry.org/korifi/api/tools/singleton"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools"
 )
 
 type Applier struct {
@@ -84,18 +85,60 @@ func (a *Applier) applyProcesses(
 ) error {
 	for _, processInfo := range appInfo.Processes {
 		if process, ok := appState.Processes[processInfo.Type]; ok {
-			if _, err := a.processRepo.PatchProcess(ctx, authInfo, processInfo.ToProcessPatchMessage(process.GUID, appState.App.SpaceGUID)); err != nil {
+			patchMessage := processInfo.ToProcessPatchMessage(process.GUID, appState.App.SpaceGUID)
+			if appInfo.Sidecars != nil {
+				patchMessage.Sidecars = tools.PtrTo(appInfo.SidecarsForProcess(processInfo.Type))
+			}
+
+			if _, err := a.processRepo.PatchProcess(ctx, authInfo, patchMessage); err != nil {
 				return err
 			}
 			continue
 		}
 
-		if err := a.processRepo.CreateProcess(ctx, authInfo, processInfo.ToProcessCreateMessage(appState.App.GUID, appState.App.SpaceGUID)); err != nil {
+		createMessage := processInfo.ToProcessCreateMessage(appState.App.GUID, appState.App.SpaceGUID)
+		createMessage.Sidecars = appInfo.SidecarsForProcess(processInfo.Type)
+		if err := a.processRepo.CreateProcess(ctx, authInfo, createMessage); err != nil {
 			return err
 		}
 
 	}
 
+	return a.applyProcessSidecars(ctx, authInfo, appInfo, appState)
+}
+
+// applyProcessSidecars applies the sidecars of the existing processes that are
+// not listed in the manifest, e.g. processes from the Procfile. The sidecars
+// are left alone when the manifest has none.
+func (a *Applier) applyProcessSidecars(
+	ctx context.Context,
+	authInfo authorization.Info,
+	appInfo payloads.ManifestApplication,
+	appState AppState,
+) error {
+	if appInfo.Sidecars == nil {
+		return nil
+	}
+
+	for processType, process := range appState.Processes {
+		if slices.ContainsFunc(appInfo.Processes, func(p payloads.ManifestApplicationProcess) bool { return p.Type == processType }) {
+			continue
+		}
+
+		sidecars := appInfo.SidecarsForProcess(processType)
+		if len(sidecars) == 0 && len(process.Sidecars) == 0 {
+			continue
+		}
+
+		if _, err := a.processRepo.PatchProcess(ctx, authInfo, repositories.PatchProcessMessage{
+			ProcessGUID: process.GUID,
+			SpaceGUID:   appState.App.SpaceGUID,
+			Sidecars:    &sidecars,
+		}); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -153,9 +196,8 @@ func (a *Applier) createOrUpdateRoute(ctx context.Context, authInfo authorizatio
 	}
 
 	_, err = a.routeRepo.AddDestinationsToRoute(ctx, authInfo, repositories.AddDestinationsMessage{
-		RouteGUID:            routeRecord.GUID,
-		SpaceGUID:            routeRecord.SpaceGUID,
-		ExistingDestinations: routeRecord.Destinations,
+		RouteGUID: routeRecord.GUID,
+		SpaceGUID: routeRecord.SpaceGUID,
 		NewDestinations: []repositories.DesiredDestination{{
 			AppGUID:     appState.App.GUID,
 			ProcessType: korifiv1alpha1.ProcessTypeWeb,
@@ -230,9 +272,9 @@ func (a *Applier) applyServices(ctx context.Context, authInfo authorization.Info
 		serviceNameToServiceInstance[serviceInstance.Name] = serviceInstance
 	}
 
-	serviceNameToServiceBinding := map[string]*string{}
+	serviceNameToManifestService := map[string]payloads.ManifestApplicationService{}
 	for _, manifestService := range appInfo.Services {
-		serviceNameToServiceBinding[manifestService.Name] = manifestService.BindingName
+		serviceNameToManifestService[manifestService.Name] = manifestService
 	}
 
 	for serviceName := range desiredServiceNames {
@@ -246,8 +288,9 @@ func (a *Applier) applyServices(ctx context.Context, authInfo authorization.Info
 			)
 		}
 
+		manifestService := serviceNameToManifestService[serviceName]
 		_, err := a.serviceBindingRepo.CreateServiceBinding(ctx, authInfo, repositories.CreateServiceBindingMessage{
-			Name:                serviceNameToServiceBinding[serviceName],
+			Name:                manifestService.BindingName,
 			ServiceInstanceGUID: serviceInstance.GUID,
 			AppGUID:             appState.App.GUID,
 			SpaceGUID:           appState.App.SpaceGUID,
@@ -251,6 +251,11 @@ var _ = Describe("Applier", func() {
 				Expect(patchMsg.HealthCheckTimeoutSeconds).To(Equal(tools.PtrTo(int32(45))))
 			})
 
+			It("leaves the sidecars of the process alone", func() {
+				_, _, patchMsg := processRepo.PatchProcessArgsForCall(0)
+				Expect(patchMsg.Sidecars).To(BeNil())
+			})
+
 			When("patching the process fails", func() {
 				BeforeEach(func() {
 					processRepo.PatchProcessReturns(repositories.ProcessRecord{}, errors.New("process-patch-error"))
@@ -261,6 +266,48 @@ var _ = Describe("Applier", func() {
 				})
 			})
 		})
+
+		When("the app has sidecars", func() {
+			BeforeEach(func() {
+				appInfo.Sidecars = []payloads.ManifestApplicationSidecar{
+					{Name: "agent", Command: "run-agent", ProcessTypes: []string{"bob", "worker"}, Memory: tools.PtrTo("64M")},
+				}
+				appState.Processes = map[string]repositories.ProcessRecord{
+					"ben":    {GUID: "ben-guid", Sidecars: []repositories.Sidecar{{Name: "old", Command: "run-old"}}},
+					"worker": {GUID: "worker-guid"},
+					"clock":  {GUID: "clock-guid"},
+				}
+			})
+
+			It("adds the sidecars to the processes of their process types", func() {
+				Expect(applierErr).NotTo(HaveOccurred())
+
+				Expect(processRepo.CreateProcessCallCount()).To(Equal(1))
+				_, _, createMsg := processRepo.CreateProcessArgsForCall(0)
+				Expect(createMsg.Type).To(Equal("bob"))
+				Expect(createMsg.Sidecars).To(Equal([]repositories.Sidecar{
+					{Name: "agent", Command: "run-agent", MemoryMB: 64},
+				}))
+
+				var patchMsgs []repositories.PatchProcessMessage
+				for i := range processRepo.PatchProcessCallCount() {
+					_, _, patchMsg := processRepo.PatchProcessArgsForCall(i)
+					patchMsgs = append(patchMsgs, patchMsg)
+				}
+				Expect(patchMsgs).To(ConsistOf(
+					MatchFields(IgnoreExtras, Fields{
+						"ProcessGUID": Equal("ben-guid"),
+						"Sidecars":    PointTo(BeEmpty()),
+					}),
+					MatchFields(IgnoreExtras, Fields{
+						"ProcessGUID": Equal("worker-guid"),
+						"Sidecars": PointTo(Equal([]repositories.Sidecar{
+							{Name: "agent", Command: "run-agent", MemoryMB: 64},
+						})),
+					}),
+				))
+			})
+		})
 	})
 
 	Describe("applying routes", func() {
@@ -307,9 +354,8 @@ var _ = Describe("Applier", func() {
 			Expect(routeRepo.AddDestinationsToRouteCallCount()).To(Equal(1))
 			_, _, addDestinationMessage := routeRepo.AddDestinationsToRouteArgsForCall(0)
 			Expect(addDestinationMessage).To(Equal(repositories.AddDestinationsMessage{
-				RouteGUID:            "route-guid",
-				SpaceGUID:            "space-guid",
-				ExistingDestinations: []repositories.DestinationRecord{{GUID: "dest-guid"}},
+				RouteGUID: "route-guid",
+				SpaceGUID: "space-guid",
 				NewDestinations: []repositories.DesiredDestination{{
 					AppGUID:     "app-guid",
 					ProcessType: "web",
@@ -2,17 +2,19 @@ package manifest
 
 import (
 	"math/rand"
+	"regexp"
 	"strings"
 
+	"code.cloudfoundry.org/korifi/api/config"
 	"code.cloudfoundry.org/korifi/api/payloads"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 )
 
 type Normalizer struct {
-	defaultDomainName string
+	defaultDomainName *config.Reloadable[string]
 }
 
-func NewNormalizer(defaultDomainName string) Normalizer {
+func NewNormalizer(defaultDomainName *config.Reloadable[string]) Normalizer {
 	return Normalizer{
 		defaultDomainName: defaultDomainName,
 	}
@@ -32,6 +34,7 @@ func (n Normalizer) Normalize(appInfo payloads.ManifestApplication, appState App
 		NoRoute:    appInfo.NoRoute,
 		Metadata:   appInfo.Metadata,
 		Services:   appInfo.Services,
+		Sidecars:   appInfo.Sidecars,
 		Docker:     appInfo.Docker,
 	}
 }
@@ -83,7 +86,8 @@ func (n Normalizer) normalizeProcesses(appInfo payloads.ManifestApplication, app
 	}
 
 	if appInfo.Memory != nil || appInfo.DiskQuota != nil || appInfo.Instances != nil || appInfo.Command != nil ||
-		appInfo.HealthCheckHTTPEndpoint != nil || appInfo.HealthCheckType != nil || appInfo.HealthCheckInvocationTimeout != nil || appInfo.Timeout != nil {
+		appInfo.HealthCheckHTTPEndpoint != nil || appInfo.HealthCheckType != nil || appInfo.HealthCheckInvocationTimeout != nil || appInfo.Timeout != nil ||
+		appInfo.ReadinessHealthCheckHTTPEndpoint != nil || appInfo.ReadinessHealthCheckType != nil || appInfo.ReadinessHealthCheckInvocationTimeout != nil || appInfo.ReadinessHealthCheckInterval != nil {
 
 		webProc.Memory = procValIfSet(appInfo.Memory, webProc.Memory)
 		webProc.DiskQuota = procValIfSet(appInfo.DiskQuota, webProc.DiskQuota)
@@ -93,6 +97,10 @@ func (n Normalizer) normalizeProcesses(appInfo payloads.ManifestApplication, app
 		webProc.HealthCheckType = procValIfSet(appInfo.HealthCheckType, webProc.HealthCheckType)
 		webProc.HealthCheckInvocationTimeout = procValIfSet(appInfo.HealthCheckInvocationTimeout, webProc.HealthCheckInvocationTimeout)
 		webProc.Timeout = procValIfSet(appInfo.Timeout, webProc.Timeout)
+		webProc.ReadinessHealthCheckHTTPEndpoint = procValIfSet(appInfo.ReadinessHealthCheckHTTPEndpoint, webProc.ReadinessHealthCheckHTTPEndpoint)
+		webProc.ReadinessHealthCheckType = procValIfSet(appInfo.ReadinessHealthCheckType, webProc.ReadinessHealthCheckType)
+		webProc.ReadinessHealthCheckInvocationTimeout = procValIfSet(appInfo.ReadinessHealthCheckInvocationTimeout, webProc.ReadinessHealthCheckInvocationTimeout)
+		webProc.ReadinessHealthCheckInterval = procValIfSet(appInfo.ReadinessHealthCheckInterval, webProc.ReadinessHealthCheckInterval)
 	}
 
 	return processes
@@ -120,20 +128,52 @@ func (n Normalizer) normalizeRoutes(appInfo payloads.ManifestApplication, appSta
 }
 
 func (n Normalizer) configureDefaultRoute(appName string) payloads.ManifestRoute {
-	defaultRouteString := appName + "." + n.defaultDomainName
+	host := hostForAppName(appName)
+	if host == "" {
+		return n.configureRandomRoute(appName)
+	}
+
+	defaultRouteString := host + "." + n.defaultDomainName.Get()
 	return payloads.ManifestRoute{
 		Route: &defaultRouteString,
 	}
 }
 
 func (n Normalizer) configureRandomRoute(appName string) payloads.ManifestRoute {
-	randomHostname := appName + "-" + generateRandomRoute()
-	routeString := randomHostname + "." + n.defaultDomainName
+	randomHostname := generateRandomRoute()
+	if host := hostForAppName(appName); host != "" {
+		host = strings.TrimRight(host[:min(len(host), maxHostLength-len(randomHostname)-1)], "-")
+		randomHostname = host + "-" + randomHostname
+	}
+
+	routeString := randomHostname + "." + n.defaultDomainName.Get()
 	return payloads.ManifestRoute{
 		Route: &routeString,
 	}
 }
 
+const maxHostLength = 63
+
+var (
+	hostSeparatorChars = regexp.MustCompile(`[\s_.]+`)
+	invalidHostChars   = regexp.MustCompile(`[^a-z0-9-]`)
+	invalidHostPrefix  = regexp.MustCompile(`^[^a-z]+`)
+)
+
+// hostForAppName derives a DNS label from the app name like the CF CLI does:
+// separators become dashes, other invalid characters are dropped and the
+// result is truncated to the maximum host length. Hosts must start with a
+// letter, so an empty string is returned for names without any.
+func hostForAppName(appName string) string {
+	host := strings.ToLower(appName)
+	host = hostSeparatorChars.ReplaceAllString(host, "-")
+	host = invalidHostChars.ReplaceAllString(host, "")
+	host = invalidHostPrefix.ReplaceAllString(host, "")
+	host = host[:min(len(host), maxHostLength)]
+
+	return strings.TrimRight(host, "-")
+}
+
 func generateRandomRoute() string {
 	suffix := string('a'+rune(rand.Intn(26))) + string('a'+rune(rand.Intn(26)))
 	return adjectives[rand.Intn(len(adjectives))] + "-" + nouns[rand.Intn(len(nouns))] + "-" + suffix
@@ -1,7 +1,10 @@
 package manifest_test
 
 import (
+	"strings"
+
 	"code.cloudfoundry.org/korifi/api/actions/manifest"
+	"code.cloudfoundry.org/korifi/api/config"
 	"code.cloudfoundry.org/korifi/api/payloads"
 	"code.cloudfoundry.org/korifi/api/repositories"
 	"code.cloudfoundry.org/korifi/tools"
@@ -11,14 +14,18 @@ import (
 )
 
 type prcParams struct {
-	Command                      *string
-	Memory                       *string
-	DiskQuota                    *string
-	Instances                    *int32
-	HealthCheckHTTPEndpoint      *string
-	HealthCheckInvocationTimeout *int32
-	HealthCheckType              *string
-	Timeout                      *int32
+	Command                               *string
+	Memory                                *string
+	DiskQuota                             *string
+	Instances                             *int32
+	HealthCheckHTTPEndpoint               *string
+	HealthCheckInvocationTimeout          *int32
+	HealthCheckType                       *string
+	Timeout                               *int32
+	ReadinessHealthCheckHTTPEndpoint      *string
+	ReadinessHealthCheckInvocationTimeout *int32
+	ReadinessHealthCheckInterval          *int32
+	ReadinessHealthCheckType              *string
 }
 
 type (
@@ -50,13 +57,18 @@ var _ = Describe("Normalizer", func() {
 				Name:        "my-service",
 				BindingName: tools.PtrTo("my-binding"),
 			}},
+			Sidecars: []payloads.ManifestApplicationSidecar{{
+				Name:         "my-sidecar",
+				Command:      "run-sidecar",
+				ProcessTypes: []string{"web"},
+			}},
 		}
 		appState = manifest.AppState{
 			App:       repositories.AppRecord{},
 			Processes: nil,
 			Routes:    nil,
 		}
-		normalizer = manifest.NewNormalizer(defaultDomainName)
+		normalizer = manifest.NewNormalizer(config.NewReloadable(defaultDomainName))
 	})
 
 	JustBeforeEach(func() {
@@ -74,6 +86,7 @@ var _ = Describe("Normalizer", func() {
 				Name:        "my-service",
 				BindingName: tools.PtrTo("my-binding"),
 			}}))
+			Expect(normalizedAppInfo.Sidecars).To(Equal(appInfo.Sidecars))
 		})
 
 		When("no-route is set", func() {
@@ -129,12 +142,12 @@ var _ = Describe("Normalizer", func() {
 			BeforeEach(func() {
 				appInfo = payloads.ManifestApplication{
 					Name:   "my-app",
-					Docker: struct{}{},
+					Docker: &payloads.ManifestApplicationDocker{Image: "my/image", Username: tools.PtrTo("user")},
 				}
 			})
 
 			It("preserves the necessary app fields", func() {
-				Expect(normalizedAppInfo.Docker).To(Equal(struct{}{}))
+				Expect(normalizedAppInfo.Docker).To(Equal(&payloads.ManifestApplicationDocker{Image: "my/image", Username: tools.PtrTo("user")}))
 			})
 		})
 	})
@@ -167,18 +180,26 @@ var _ = Describe("Normalizer", func() {
 				appInfo.HealthCheckType = app.HealthCheckType
 				appInfo.HealthCheckInvocationTimeout = app.HealthCheckInvocationTimeout
 				appInfo.Timeout = app.Timeout
+				appInfo.ReadinessHealthCheckHTTPEndpoint = app.ReadinessHealthCheckHTTPEndpoint
+				appInfo.ReadinessHealthCheckInvocationTimeout = app.ReadinessHealthCheckInvocationTimeout
+				appInfo.ReadinessHealthCheckInterval = app.ReadinessHealthCheckInterval
+				appInfo.ReadinessHealthCheckType = app.ReadinessHealthCheckType
 
 				if (process != prcParams{}) {
 					appInfo.Processes = append(appInfo.Processes, payloads.ManifestApplicationProcess{
-						Type:                         "web",
-						Memory:                       process.Memory,
-						DiskQuota:                    process.DiskQuota,
-						Instances:                    process.Instances,
-						Command:                      process.Command,
-						HealthCheckHTTPEndpoint:      process.HealthCheckHTTPEndpoint,
-						HealthCheckType:              process.HealthCheckType,
-						HealthCheckInvocationTimeout: process.HealthCheckInvocationTimeout,
-						Timeout:                      process.Timeout,
+						Type:                                  "web",
+						Memory:                                process.Memory,
+						DiskQuota:                             process.DiskQuota,
+						Instances:                             process.Instances,
+						Command:                               process.Command,
+						HealthCheckHTTPEndpoint:               process.HealthCheckHTTPEndpoint,
+						HealthCheckType:                       process.HealthCheckType,
+						HealthCheckInvocationTimeout:          process.HealthCheckInvocationTimeout,
+						Timeout:                               process.Timeout,
+						ReadinessHealthCheckHTTPEndpoint:      process.ReadinessHealthCheckHTTPEndpoint,
+						ReadinessHealthCheckInvocationTimeout: process.ReadinessHealthCheckInvocationTimeout,
+						ReadinessHealthCheckInterval:          process.ReadinessHealthCheckInterval,
+						ReadinessHealthCheckType:              process.ReadinessHealthCheckType,
 					})
 				}
 
@@ -193,6 +214,10 @@ var _ = Describe("Normalizer", func() {
 				Expect(webProc.HealthCheckType).To(Equal(effective.HealthCheckType))
 				Expect(webProc.HealthCheckInvocationTimeout).To(Equal(effective.HealthCheckInvocationTimeout))
 				Expect(webProc.Timeout).To(Equal(effective.Timeout))
+				Expect(webProc.ReadinessHealthCheckHTTPEndpoint).To(Equal(effective.ReadinessHealthCheckHTTPEndpoint))
+				Expect(webProc.ReadinessHealthCheckInvocationTimeout).To(Equal(effective.ReadinessHealthCheckInvocationTimeout))
+				Expect(webProc.ReadinessHealthCheckInterval).To(Equal(effective.ReadinessHealthCheckInterval))
+				Expect(webProc.ReadinessHealthCheckType).To(Equal(effective.ReadinessHealthCheckType))
 			},
 
 			// without an explicit web process in the manifest
@@ -220,6 +245,12 @@ var _ = Describe("Normalizer", func() {
 			Entry("app-level timeout only",
 				appParams{Timeout: tools.PtrTo(int32(12))}, prcParams{},
 				expParams{Timeout: tools.PtrTo(int32(12))}),
+			Entry("app-level readiness healthcheck type only",
+				appParams{ReadinessHealthCheckType: tools.PtrTo("http")}, prcParams{},
+				expParams{ReadinessHealthCheckType: tools.PtrTo("http")}),
+			Entry("app-level readiness healthcheck interval only",
+				appParams{ReadinessHealthCheckInterval: tools.PtrTo(int32(5))}, prcParams{},
+				expParams{ReadinessHealthCheckInterval: tools.PtrTo(int32(5))}),
 			Entry("a combination of fields",
 				appParams{Memory: tools.PtrTo("512M"), DiskQuota: tools.PtrTo("2G")}, prcParams{},
 				expParams{Memory: tools.PtrTo("512M"), DiskQuota: tools.PtrTo("2G")}),
@@ -258,6 +289,11 @@ var _ = Describe("Normalizer", func() {
 				prcParams{Instances: tools.PtrTo[int32](3)},
 				expParams{Timeout: tools.PtrTo(int32(32)), Instances: tools.PtrTo[int32](3)}),
 
+			Entry("empty proc with readiness healthcheck endpoint",
+				appParams{ReadinessHealthCheckHTTPEndpoint: tools.PtrTo("/ready")},
+				prcParams{Instances: tools.PtrTo[int32](3)},
+				expParams{ReadinessHealthCheckHTTPEndpoint: tools.PtrTo("/ready"), Instances: tools.PtrTo[int32](3)}),
+
 			// with an existing web process with the given value set
 			Entry("value from proc memory used",
 				appParams{Memory: tools.PtrTo("256M")},
@@ -291,6 +327,10 @@ var _ = Describe("Normalizer", func() {
 				appParams{Timeout: tools.PtrTo(int32(25))},
 				prcParams{Timeout: tools.PtrTo(int32(2))},
 				expParams{Timeout: tools.PtrTo(int32(2))}),
+			Entry("value from proc readiness healthcheck invocation timeout used",
+				appParams{ReadinessHealthCheckInvocationTimeout: tools.PtrTo(int32(10))},
+				prcParams{ReadinessHealthCheckInvocationTimeout: tools.PtrTo(int32(3))},
+				expParams{ReadinessHealthCheckInvocationTimeout: tools.PtrTo(int32(3))}),
 		)
 	})
 
@@ -335,6 +375,48 @@ var _ = Describe("Normalizer", func() {
 					Expect(normalizedAppInfo.Routes).To(BeEmpty())
 				})
 			})
+
+			When("the app name is not a valid host", func() {
+				BeforeEach(func() {
+					appInfo.Name = "1 My_App.v2!"
+				})
+
+				It("derives the host from the app name", func() {
+					Expect(normalizedAppInfo.Routes).To(ConsistOf(
+						payloads.ManifestRoute{
+							Route: tools.PtrTo("my-app-v2.my.domain"),
+						}),
+					)
+				})
+			})
+
+			When("the app name is longer than a host", func() {
+				BeforeEach(func() {
+					appInfo.Name = strings.Repeat("a", 70)
+				})
+
+				It("truncates the host", func() {
+					Expect(normalizedAppInfo.Routes).To(ConsistOf(
+						payloads.ManifestRoute{
+							Route: tools.PtrTo(strings.Repeat("a", 63) + ".my.domain"),
+						}),
+					)
+				})
+			})
+
+			When("the app name has no letters", func() {
+				BeforeEach(func() {
+					appInfo.Name = "123"
+				})
+
+				It("creates a random route", func() {
+					Expect(normalizedAppInfo.Routes).To(ConsistOf(
+						gstruct.MatchFields(gstruct.IgnoreExtras, gstruct.Fields{
+							"Route": gstruct.PointTo(MatchRegexp(`^[a-z]+-[a-z]+-[a-z]{2}\.my\.domain$`)),
+						}),
+					))
+				})
+			})
 		})
 
 		When("random route is set", func() {
@@ -342,8 +424,25 @@ var _ = Describe("Normalizer", func() {
 				appInfo.RandomRoute = true
 			})
 
-			It("creates a random route", func() {
-				Expect(normalizedAppInfo.Routes).To(HaveLen(1))
+			It("creates a random route on the default domain", func() {
+				Expect(normalizedAppInfo.Routes).To(ConsistOf(
+					gstruct.MatchFields(gstruct.IgnoreExtras, gstruct.Fields{
+						"Route": gstruct.PointTo(MatchRegexp(`^my-app-[a-z]+-[a-z]+-[a-z]{2}\.my\.domain$`)),
+					}),
+				))
+			})
+
+			When("the app name is longer than a host", func() {
+				BeforeEach(func() {
+					appInfo.Name = strings.Repeat("a", 70)
+				})
+
+				It("truncates the app name so that the host is valid", func() {
+					Expect(normalizedAppInfo.Routes).To(HaveLen(1))
+					host, _, _ := strings.Cut(*normalizedAppInfo.Routes[0].Route, ".")
+					Expect(len(host)).To(BeNumerically("<=", 63))
+					Expect(host).To(MatchRegexp(`^a+-[a-z]+-[a-z]+-[a-z]{2}$`))
+				})
 			})
 
 			When("there is already a route in the manifest", func() {
@@ -9,6 +9,7 @@ import (
 	"code.cloudfoundry.org/korifi/api/actions/manifest"
 	reposfake "code.cloudfoundry.org/korifi/api/actions/shared/fake"
 	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/config"
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	"code.cloudfoundry.org/korifi/api/payloads"
 	"code.cloudfoundry.org/korifi/api/repositories"
@@ -64,7 +65,7 @@ var _ = Describe("ApplyManifest", func() {
 			}},
 		}
 
-		manifestAction = actions.NewManifest(domainRepository, "my.domain", stateCollector, normalizer, applier)
+		manifestAction = actions.NewManifest(domainRepository, config.NewReloadable("my.domain"), stateCollector, normalizer, applier)
 	})
 
 	JustBeforeEach(func() {
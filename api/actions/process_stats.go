@@ -3,7 +3,9 @@ package actions
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"code.cloudfoundry.org/korifi/api/actions/shared"
@@ -11,6 +13,7 @@ import (
 	"code.cloudfoundry.org/korifi/api/repositories"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/tools"
+	"code.cloudfoundry.org/korifi/tools/k8s"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
@@ -45,6 +48,7 @@ type (
 		Index     int
 		State     string `default:"DOWN"`
 		Usage     Usage
+		Details   *string
 		MemQuota  *int64
 		DiskQuota *int64
 	}
@@ -104,22 +108,38 @@ func (a *ProcessStats) FetchStats(ctx context.Context, authInfo authorization.In
 		}
 	}
 
+	// Pods of runners that do not assign instance indexes (e.g. the
+	// deployment-runner) are numbered in the order of their names, so that
+	// an instance keeps its index regardless of the order pods are listed in
+	metrics = slices.Clone(metrics)
+	slices.SortStableFunc(metrics, func(a, b repositories.PodMetrics) int {
+		return strings.Compare(a.Pod.Name, b.Pod.Name)
+	})
+
+	unindexedPods := 0
 	for _, m := range metrics {
-		index, err := extractIndex(m.Pod)
+		index, err := extractIndex(m.Pod, &unindexedPods)
 		if err != nil {
 			return nil, err
 		}
 
-		podState := getPodState(m.Pod)
-		if podState == stateDown {
+		if index >= len(records) {
 			continue
 		}
 
-		if index >= len(records) {
+		// instances that cannot start stay DOWN rather than STARTING forever
+		if reason, message, failing := k8s.PodStartFailure(m.Pod); failing {
+			records[index].Details = tools.PtrTo(fmt.Sprintf("%s: %s", reason, message))
+			continue
+		}
+
+		podState := getPodState(m.Pod)
+		if podState == stateDown {
 			continue
 		}
 
 		records[index].State = podState
+		records[index].Details = crashDetails(m.Pod)
 
 		metricsMap := aggregateContainerMetrics(m.Metrics.Containers)
 		if len(metricsMap) == 0 {
@@ -153,10 +173,12 @@ func (a *ProcessStats) FetchStats(ctx context.Context, authInfo authorization.In
 	return records, nil
 }
 
-func extractIndex(pod corev1.Pod) (int, error) {
+func extractIndex(pod corev1.Pod, unindexedPods *int) (int, error) {
 	indexString, exists := pod.ObjectMeta.Labels[korifiv1alpha1.PodIndexLabelKey]
 	if !exists {
-		return 0, fmt.Errorf("%s label not found", korifiv1alpha1.PodIndexLabelKey)
+		index := *unindexedPods
+		*unindexedPods++
+		return index, nil
 	}
 
 	index, err := strconv.Atoi(indexString)
@@ -204,6 +226,25 @@ func podHasCrashedContainer(pod corev1.Pod) bool {
 	return false
 }
 
+// crashDetails describes how often the app container of the pod has crashed,
+// which is how flapping instances show up in cf app
+func crashDetails(pod corev1.Pod) *string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != ApplicationContainerName || status.RestartCount == 0 {
+			continue
+		}
+
+		details := fmt.Sprintf("crash count: %d", status.RestartCount)
+		if terminated := status.LastTerminationState.Terminated; terminated != nil {
+			details = fmt.Sprintf("%s, last exit status: %d (%s)", details, terminated.ExitCode, terminated.Reason)
+		}
+
+		return &details
+	}
+
+	return nil
+}
+
 func podConditionStatus(pod corev1.Pod, conditionType corev1.PodConditionType) bool {
 	for _, cond := range pod.Status.Conditions {
 		if cond.Type == conditionType {
@@ -209,10 +209,34 @@ var _ = Describe("ProcessStats", func() {
 	When("the pod-index label is not set", func() {
 		BeforeEach(func() {
 			delete(podMetrics[0].Pod.ObjectMeta.Labels, korifiv1alpha1.PodIndexLabelKey)
+			delete(podMetrics[1].Pod.ObjectMeta.Labels, korifiv1alpha1.PodIndexLabelKey)
+			podMetrics[0].Pod.Name = "pod-b"
+			podMetrics[1].Pod.Name = "pod-a"
 		})
 
-		It("returns an error", func() {
-			Expect(responseErr).To(MatchError(ContainSubstring("label not found")))
+		It("indexes the instances in the order of their names", func() {
+			Expect(responseErr).NotTo(HaveOccurred())
+			Expect(responseRecords).To(HaveLen(2))
+
+			Expect(responseRecords[0].Index).To(Equal(0))
+			Expect(responseRecords[0].Usage.CPU).To(Equal(tools.PtrTo(0.124)))
+
+			Expect(responseRecords[1].Index).To(Equal(1))
+			Expect(responseRecords[1].Usage.CPU).To(Equal(tools.PtrTo(0.123)))
+		})
+
+		When("the pods are listed in a different order", func() {
+			BeforeEach(func() {
+				podMetrics[0], podMetrics[1] = podMetrics[1], podMetrics[0]
+			})
+
+			It("keeps the instance indexes", func() {
+				Expect(responseErr).NotTo(HaveOccurred())
+				Expect(responseRecords).To(HaveLen(2))
+
+				Expect(responseRecords[0].Usage.CPU).To(Equal(tools.PtrTo(0.124)))
+				Expect(responseRecords[1].Usage.CPU).To(Equal(tools.PtrTo(0.123)))
+			})
 		})
 	})
 
@@ -250,6 +274,22 @@ var _ = Describe("ProcessStats", func() {
 			It("is down", func() {
 				Expect(responseRecords[0].State).To(Equal("DOWN"))
 			})
+
+			When("the pod is unschedulable", func() {
+				BeforeEach(func() {
+					podMetrics[0].Pod.Status.Conditions = []corev1.PodCondition{{
+						Type:    corev1.PodScheduled,
+						Status:  corev1.ConditionFalse,
+						Reason:  corev1.PodReasonUnschedulable,
+						Message: "0/3 nodes are available: 3 Insufficient memory.",
+					}}
+				})
+
+				It("reports the reason in the details", func() {
+					Expect(responseRecords[0].State).To(Equal("DOWN"))
+					Expect(responseRecords[0].Details).To(Equal(tools.PtrTo("Unschedulable: 0/3 nodes are available: 3 Insufficient memory.")))
+				})
+			})
 		})
 
 		When("the pod has a container in waiting state", func() {
@@ -269,6 +309,18 @@ var _ = Describe("ProcessStats", func() {
 				Expect(responseRecords[0].State).To(Equal("STARTING"))
 			})
 
+			When("the image cannot be pulled", func() {
+				BeforeEach(func() {
+					podMetrics[0].Pod.Status.ContainerStatuses[0].State.Waiting.Reason = "ImagePullBackOff"
+					podMetrics[0].Pod.Status.ContainerStatuses[0].State.Waiting.Message = `Back-off pulling image "some-image"`
+				})
+
+				It("is down and reports the reason in the details", func() {
+					Expect(responseRecords[0].State).To(Equal("DOWN"))
+					Expect(responseRecords[0].Details).To(Equal(tools.PtrTo(`ImagePullBackOff: Back-off pulling image "some-image"`)))
+				})
+			})
+
 			When("the reason is CrashLoopBackoff", func() {
 				BeforeEach(func() {
 					podMetrics[0].Pod.Status.ContainerStatuses[0].State.Waiting.Reason = "CrashLoopBackOff"
@@ -277,6 +329,26 @@ var _ = Describe("ProcessStats", func() {
 				It("is crashed", func() {
 					Expect(responseRecords[0].State).To(Equal("CRASHED"))
 				})
+
+				It("has no crash details", func() {
+					Expect(responseRecords[0].Details).To(BeNil())
+				})
+
+				When("the application container has restarted", func() {
+					BeforeEach(func() {
+						podMetrics[0].Pod.Status.ContainerStatuses[0].RestartCount = 3
+						podMetrics[0].Pod.Status.ContainerStatuses[0].LastTerminationState = corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								ExitCode: 1,
+								Reason:   "Error",
+							},
+						}
+					})
+
+					It("reports the crashes in the details", func() {
+						Expect(responseRecords[0].Details).To(Equal(tools.PtrTo("crash count: 3, last exit status: 1 (Error)")))
+					})
+				})
 			})
 		})
 
@@ -0,0 +1,59 @@
+package authorization
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+// Access describes what a subject has been authorized for. An Access with
+// only a Namespace set stands for holding any role in that namespace.
+type Access struct {
+	Verb      string
+	Group     string
+	Resource  string
+	Namespace string
+}
+
+// AccessCache caches authorization decisions per subject for a short TTL, so
+// that repeated requests do not each need a new access review. Changing a
+// role through the API invalidates all decisions; role changes made directly
+// in Kubernetes are picked up once the cached decisions expire.
+type AccessCache struct {
+	decisions  *cache.Expiring
+	ttl        time.Duration
+	generation atomic.Uint64
+}
+
+func NewAccessCache(decisions *cache.Expiring, ttl time.Duration) *AccessCache {
+	return &AccessCache{
+		decisions: decisions,
+		ttl:       ttl,
+	}
+}
+
+func (c *AccessCache) Get(subjectHash string, access Access) (bool, bool) {
+	allowed, ok := c.decisions.Get(c.key(subjectHash, access))
+	if !ok {
+		return false, false
+	}
+
+	allowedBool, ok := allowed.(bool)
+	return allowedBool, ok
+}
+
+func (c *AccessCache) Set(subjectHash string, access Access, allowed bool) {
+	c.decisions.Set(c.key(subjectHash, access), allowed, c.ttl)
+}
+
+// Invalidate drops all cached decisions. Entries cached so far are no longer
+// returned and expire on their own.
+func (c *AccessCache) Invalidate() {
+	c.generation.Add(1)
+}
+
+func (c *AccessCache) key(subjectHash string, access Access) string {
+	return fmt.Sprintf("%d/%s/%s/%s/%s/%s", c.generation.Load(), subjectHash, access.Verb, access.Group, access.Resource, access.Namespace)
+}
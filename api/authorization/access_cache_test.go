@@ -0,0 +1,80 @@
+package authorization_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/utils/clock/testing"
+)
+
+var _ = Describe("AccessCache", func() {
+	var (
+		clock       *testing.FakeClock
+		accessCache *authorization.AccessCache
+		access      authorization.Access
+	)
+
+	BeforeEach(func() {
+		clock = testing.NewFakeClock(time.Now())
+		accessCache = authorization.NewAccessCache(cache.NewExpiringWithClock(clock), time.Minute)
+		access = authorization.Access{Verb: "patch", Group: "korifi.cloudfoundry.org", Resource: "cfpackages", Namespace: "space-guid"}
+
+		accessCache.Set("alice", access, true)
+	})
+
+	It("returns cached decisions", func() {
+		allowed, found := accessCache.Get("alice", access)
+		Expect(found).To(BeTrue())
+		Expect(allowed).To(BeTrue())
+	})
+
+	It("caches decisions per subject", func() {
+		_, found := accessCache.Get("bob", access)
+		Expect(found).To(BeFalse())
+	})
+
+	It("caches decisions per access", func() {
+		access.Namespace = "other-space-guid"
+		_, found := accessCache.Get("alice", access)
+		Expect(found).To(BeFalse())
+	})
+
+	It("caches denials", func() {
+		accessCache.Set("bob", access, false)
+		allowed, found := accessCache.Get("bob", access)
+		Expect(found).To(BeTrue())
+		Expect(allowed).To(BeFalse())
+	})
+
+	When("the ttl elapses", func() {
+		BeforeEach(func() {
+			clock.Step(2 * time.Minute)
+		})
+
+		It("forgets the decision", func() {
+			_, found := accessCache.Get("alice", access)
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	When("the cache is invalidated", func() {
+		BeforeEach(func() {
+			accessCache.Invalidate()
+		})
+
+		It("forgets the decision", func() {
+			_, found := accessCache.Get("alice", access)
+			Expect(found).To(BeFalse())
+		})
+
+		It("caches new decisions", func() {
+			accessCache.Set("alice", access, false)
+			allowed, found := accessCache.Get("alice", access)
+			Expect(found).To(BeTrue())
+			Expect(allowed).To(BeFalse())
+		})
+	})
+})
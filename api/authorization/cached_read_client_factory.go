@@ -0,0 +1,157 @@
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	authv1 "k8s.io/api/authorization/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfapps;cfbuilds;cfcrontasks;cfdomains;cfpackages;cfprocesses;cfroutes;cfservicebindings;cfserviceinstances;cftasks,verbs=list;watch
+
+// CachedReadClientFactory builds user clients that serve namespaced reads of
+// the cached types from a shared informer cache rather than the Kubernetes
+// API. The cache is read with the API's own privileges, so every read is
+// first checked against the user's permissions with an access review, whose
+// outcome is cached in the AccessCache. Reads the user is not allowed to
+// perform namespace-wide, as well as writes and watches, go to the Kubernetes
+// API with the user's credentials as usual.
+type CachedReadClientFactory struct {
+	UserK8sClientFactory
+	reader      client.Reader
+	mapper      meta.RESTMapper
+	accessCache *AccessCache
+	cachedKinds map[schema.GroupVersionKind]bool
+}
+
+func NewCachedReadClientFactory(
+	userClientFactory UserK8sClientFactory,
+	reader client.Reader,
+	mapper meta.RESTMapper,
+	accessCache *AccessCache,
+	cachedTypes ...client.Object,
+) (*CachedReadClientFactory, error) {
+	cachedKinds := map[schema.GroupVersionKind]bool{}
+	for _, cachedType := range cachedTypes {
+		gvk, err := apiutil.GVKForObject(cachedType, scheme.Scheme)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the kind of cached type %T: %w", cachedType, err)
+		}
+		cachedKinds[gvk] = true
+	}
+
+	return &CachedReadClientFactory{
+		UserK8sClientFactory: userClientFactory,
+		reader:               reader,
+		mapper:               mapper,
+		accessCache:          accessCache,
+		cachedKinds:          cachedKinds,
+	}, nil
+}
+
+func (f *CachedReadClientFactory) BuildClient(authInfo Info) (client.WithWatch, error) {
+	userClient, err := f.UserK8sClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cachedReadClient{
+		WithWatch: userClient,
+		factory:   f,
+		authInfo:  authInfo,
+	}, nil
+}
+
+type cachedReadClient struct {
+	client.WithWatch
+	factory  *CachedReadClientFactory
+	authInfo Info
+}
+
+func (c *cachedReadClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if key.Namespace == "" || !c.canReadFromCache(ctx, obj, "get", key.Namespace) {
+		return c.WithWatch.Get(ctx, key, obj, opts...)
+	}
+
+	err := c.factory.reader.Get(ctx, key, obj, opts...)
+	if k8serrors.IsNotFound(err) {
+		// the object might have just been created and not be in the cache yet
+		return c.WithWatch.Get(ctx, key, obj, opts...)
+	}
+
+	return err
+}
+
+func (c *cachedReadClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+
+	// the cache only indexes objects by namespace and cannot continue lists
+	if listOpts.Namespace == "" || listOpts.FieldSelector != nil || listOpts.Continue != "" ||
+		!c.canReadFromCache(ctx, list, "list", listOpts.Namespace) {
+		return c.WithWatch.List(ctx, list, opts...)
+	}
+
+	// the cached objects are in memory already, so there is no point in
+	// paginating them; return them all as a single page instead
+	listOpts.Limit = 0
+	return c.factory.reader.List(ctx, list, listOpts)
+}
+
+func (c *cachedReadClient) canReadFromCache(ctx context.Context, obj runtime.Object, verb, namespace string) bool {
+	gvk, err := apiutil.GVKForObject(obj, scheme.Scheme)
+	if err != nil {
+		return false
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+
+	if !c.factory.cachedKinds[gvk] {
+		return false
+	}
+
+	allowed, err := c.isAllowed(ctx, gvk, verb, namespace)
+	return err == nil && allowed
+}
+
+func (c *cachedReadClient) isAllowed(ctx context.Context, gvk schema.GroupVersionKind, verb, namespace string) (bool, error) {
+	mapping, err := c.factory.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, err
+	}
+
+	access := Access{
+		Verb:      verb,
+		Group:     gvk.Group,
+		Resource:  mapping.Resource.Resource,
+		Namespace: namespace,
+	}
+	if allowed, ok := c.factory.accessCache.Get(c.authInfo.Hash(), access); ok {
+		return allowed, nil
+	}
+
+	review := authv1.SelfSubjectAccessReview{
+		Spec: authv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Namespace: access.Namespace,
+				Verb:      access.Verb,
+				Group:     access.Group,
+				Resource:  access.Resource,
+			},
+		},
+	}
+	if err := c.WithWatch.Create(ctx, &review); err != nil {
+		return false, fmt.Errorf("failed to create self subject access review: %w", apierrors.FromK8sError(err, ""))
+	}
+
+	c.factory.accessCache.Set(c.authInfo.Hash(), access, review.Status.Allowed)
+	return review.Status.Allowed, nil
+}
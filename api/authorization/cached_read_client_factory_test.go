@@ -0,0 +1,151 @@
+package authorization_test
+
+import (
+	"context"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/authorization/testhelpers"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+var _ = Describe("CachedReadClientFactory", func() {
+	var (
+		ctx             context.Context
+		namespace       string
+		userName        string
+		cachedConfigMap *corev1.ConfigMap
+		userClient      client.Client
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		userName = uuid.NewString()
+		namespace = uuid.NewString()
+		Expect(k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})).To(Succeed())
+
+		// the config map only exists in the cache, so reading it proves the
+		// read was served from the cache
+		cachedConfigMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cached-config-map",
+				Namespace: namespace,
+			},
+		}
+		cacheReader := configMapReader{configMap: cachedConfigMap}
+
+		httpClient, err := rest.HTTPClientFor(k8sConfig)
+		Expect(err).NotTo(HaveOccurred())
+		mapper, err := apiutil.NewDynamicRESTMapper(k8sConfig, httpClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		clientFactory, err := authorization.NewCachedReadClientFactory(
+			authorization.NewUnprivilegedClientFactory(k8sConfig, mapper, wait.Backoff{Steps: 1, Duration: time.Millisecond}),
+			cacheReader,
+			mapper,
+			authorization.NewAccessCache(cache.NewExpiring(), time.Minute),
+			&corev1.ConfigMap{},
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		cert, key := testhelpers.ObtainClientCert(testEnv, userName)
+		userClient, err = clientFactory.BuildClient(authorization.Info{CertData: testhelpers.JoinCertAndKey(cert, key)})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	allowReading := func(resource string) {
+		role := &rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: "read-" + resource, Namespace: namespace},
+			Rules: []rbacv1.PolicyRule{{
+				Verbs:     []string{"get", "list"},
+				APIGroups: []string{""},
+				Resources: []string{resource},
+			}},
+		}
+		Expect(k8sClient.Create(ctx, role)).To(Succeed())
+		Expect(k8sClient.Create(ctx, &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "read-" + resource, Namespace: namespace},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: userName}},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: role.Name},
+		})).To(Succeed())
+	}
+
+	It("reads from the Kubernetes API when the user is not allowed to read", func() {
+		err := userClient.List(ctx, &corev1.ConfigMapList{}, client.InNamespace(namespace))
+		Expect(k8serrors.IsForbidden(err)).To(BeTrue())
+
+		err = userClient.Get(ctx, client.ObjectKeyFromObject(cachedConfigMap), &corev1.ConfigMap{})
+		Expect(k8serrors.IsForbidden(err)).To(BeTrue())
+	})
+
+	When("the user is allowed to read", func() {
+		BeforeEach(func() {
+			allowReading("configmaps")
+			allowReading("secrets")
+		})
+
+		It("lists cached types from the cache", func() {
+			configMaps := &corev1.ConfigMapList{}
+			Expect(userClient.List(ctx, configMaps, client.InNamespace(namespace))).To(Succeed())
+			Expect(configMaps.Items).To(ConsistOf(HaveField("Name", "cached-config-map")))
+		})
+
+		It("returns all cached objects as a single page", func() {
+			configMaps := &corev1.ConfigMapList{}
+			Expect(userClient.List(ctx, configMaps, client.InNamespace(namespace), client.Limit(1))).To(Succeed())
+			Expect(configMaps.Items).To(ConsistOf(HaveField("Name", "cached-config-map")))
+			Expect(configMaps.Continue).To(BeEmpty())
+		})
+
+		It("gets cached types from the cache", func() {
+			Expect(userClient.Get(ctx, client.ObjectKeyFromObject(cachedConfigMap), &corev1.ConfigMap{})).To(Succeed())
+		})
+
+		It("gets objects missing from the cache from the Kubernetes API", func() {
+			Expect(k8sClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "new-config-map", Namespace: namespace},
+			})).To(Succeed())
+
+			Expect(userClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "new-config-map"}, &corev1.ConfigMap{})).To(Succeed())
+		})
+
+		It("reads other types from the Kubernetes API", func() {
+			Expect(k8sClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "the-secret", Namespace: namespace},
+			})).To(Succeed())
+
+			secrets := &corev1.SecretList{}
+			Expect(userClient.List(ctx, secrets, client.InNamespace(namespace))).To(Succeed())
+			Expect(secrets.Items).To(ConsistOf(HaveField("Name", "the-secret")))
+		})
+	})
+})
+
+type configMapReader struct {
+	configMap *corev1.ConfigMap
+}
+
+func (r configMapReader) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	if key != client.ObjectKeyFromObject(r.configMap) {
+		return k8serrors.NewNotFound(corev1.Resource("configmaps"), key.Name)
+	}
+
+	r.configMap.DeepCopyInto(obj.(*corev1.ConfigMap))
+	return nil
+}
+
+func (r configMapReader) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	list.(*corev1.ConfigMapList).Items = []corev1.ConfigMap{*r.configMap}
+	return nil
+}
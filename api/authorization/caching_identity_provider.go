@@ -8,19 +8,17 @@ import (
 	"k8s.io/apimachinery/pkg/util/cache"
 )
 
-const (
-	cacheTTL = 120 * time.Second
-)
-
 type CachingIdentityProvider struct {
 	identityProvider IdentityProvider
 	identityCache    *cache.Expiring
+	cacheTTL         time.Duration
 }
 
-func NewCachingIdentityProvider(identityProvider IdentityProvider, identityCache *cache.Expiring) *CachingIdentityProvider {
+func NewCachingIdentityProvider(identityProvider IdentityProvider, identityCache *cache.Expiring, cacheTTL time.Duration) *CachingIdentityProvider {
 	return &CachingIdentityProvider{
 		identityProvider: identityProvider,
 		identityCache:    identityCache,
+		cacheTTL:         cacheTTL,
 	}
 }
 
@@ -36,7 +34,7 @@ func (p *CachingIdentityProvider) GetIdentity(ctx context.Context, info Info) (I
 
 	identity, err := p.identityProvider.GetIdentity(ctx, info)
 	if err == nil {
-		p.identityCache.Set(info.Hash(), identity, cacheTTL)
+		p.identityCache.Set(info.Hash(), identity, p.cacheTTL)
 	}
 
 	return identity, err
@@ -21,12 +21,14 @@ var _ = Describe("IdentityProvider", func() {
 		idProvider    *authorization.CachingIdentityProvider
 		aliceId, id   authorization.Identity
 		identityCache *cache.Expiring
+		clock         *testing.FakeClock
 		getErr        error
 	)
 
 	BeforeEach(func() {
 		fakeProvider = new(fake.IdentityProvider)
-		identityCache = cache.NewExpiringWithClock(testing.NewFakeClock(time.Now()))
+		clock = testing.NewFakeClock(time.Now())
+		identityCache = cache.NewExpiringWithClock(clock)
 
 		aliceId = authorization.Identity{Kind: rbacv1.UserKind, Name: "alice"}
 		authInfo = authorization.Info{
@@ -34,7 +36,7 @@ var _ = Describe("IdentityProvider", func() {
 		}
 		fakeProvider.GetIdentityReturns(aliceId, nil)
 
-		idProvider = authorization.NewCachingIdentityProvider(fakeProvider, identityCache)
+		idProvider = authorization.NewCachingIdentityProvider(fakeProvider, identityCache, time.Minute)
 	})
 
 	JustBeforeEach(func() {
@@ -81,6 +83,18 @@ var _ = Describe("IdentityProvider", func() {
 			Expect(ok).To(BeTrue())
 		})
 
+		When("the cache ttl elapses", func() {
+			BeforeEach(func() {
+				clock.Step(2 * time.Minute)
+				_, err := idProvider.GetIdentity(context.Background(), authInfo)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("gets the identity again", func() {
+				Expect(fakeProvider.GetIdentityCallCount()).To(Equal(2))
+			})
+		})
+
 		When("a different auth info is sent", func() {
 			BeforeEach(func() {
 				newAuthInfo := authorization.Info{
@@ -5,6 +5,10 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"slices"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
 )
 
 const (
@@ -17,14 +21,17 @@ const (
 //counterfeiter:generate -o fake -fake-name CertIdentityInspector . CertIdentityInspector
 
 type Identity struct {
-	Name string
-	Kind string
+	Name   string
+	Kind   string
+	Groups []string
 }
 
 func (i *Identity) Hash() string {
-	key := append([]byte(i.Name), []byte(i.Kind)...)
-	hasher := sha256.New()
-	return hex.EncodeToString(hasher.Sum(key))
+	groups := slices.Clone(i.Groups)
+	slices.Sort(groups)
+
+	sum := sha256.Sum256([]byte(strings.Join(append([]string{i.Kind, i.Name}, groups...), "\x00")))
+	return hex.EncodeToString(sum[:])
 }
 
 type TokenIdentityInspector interface {
@@ -48,6 +55,19 @@ func NewCertTokenIdentityProvider(tokenInspector TokenIdentityInspector, certIns
 }
 
 func (p *CertTokenIdentityProvider) GetIdentity(ctx context.Context, info Info) (Identity, error) {
+	identity, err := p.getAuthenticatedIdentity(ctx, info)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	if info.Impersonate != "" {
+		return impersonatedIdentity(info.Impersonate, info.ImpersonateGroups), nil
+	}
+
+	return identity, nil
+}
+
+func (p *CertTokenIdentityProvider) getAuthenticatedIdentity(ctx context.Context, info Info) (Identity, error) {
 	if info.Token != "" {
 		return p.tokenInspector.WhoAmI(ctx, info.Token)
 	}
@@ -58,3 +78,11 @@ func (p *CertTokenIdentityProvider) GetIdentity(ctx context.Context, info Info)
 
 	return Identity{}, fmt.Errorf("invalid authorization info")
 }
+
+func impersonatedIdentity(userName string, groups []string) Identity {
+	if HasServiceAccountPrefix(userName) {
+		return Identity{Name: userName, Kind: rbacv1.ServiceAccountKind, Groups: groups}
+	}
+
+	return Identity{Name: userName, Kind: rbacv1.UserKind, Groups: groups}
+}
@@ -53,6 +53,53 @@ var _ = Describe("IdentityProvider", func() {
 			Expect(id).To(Equal(aliceId))
 		})
 
+		When("the authorization.Info impersonates a user", func() {
+			BeforeEach(func() {
+				authInfo.Impersonate = "bob"
+			})
+
+			It("still authenticates the token", func() {
+				Expect(tokenInspector.WhoAmICallCount()).To(Equal(1))
+			})
+
+			It("returns the identity of the impersonated user", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(id).To(Equal(authorization.Identity{Kind: rbacv1.UserKind, Name: "bob"}))
+			})
+
+			When("the authorization.Info impersonates groups", func() {
+				BeforeEach(func() {
+					authInfo.ImpersonateGroups = []string{"developers"}
+				})
+
+				It("returns the identity with the impersonated groups", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(id).To(Equal(authorization.Identity{Kind: rbacv1.UserKind, Name: "bob", Groups: []string{"developers"}}))
+				})
+			})
+
+			When("the impersonated user is a service account", func() {
+				BeforeEach(func() {
+					authInfo.Impersonate = "system:serviceaccount:ns:bob"
+				})
+
+				It("returns a service account identity", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(id).To(Equal(authorization.Identity{Kind: rbacv1.ServiceAccountKind, Name: "system:serviceaccount:ns:bob"}))
+				})
+			})
+
+			When("token inspector fails", func() {
+				BeforeEach(func() {
+					tokenInspector.WhoAmIReturns(authorization.Identity{}, errors.New("boom"))
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError(ContainSubstring("boom")))
+				})
+			})
+		})
+
 		When("token inspector fails", func() {
 			BeforeEach(func() {
 				tokenInspector.WhoAmIReturns(authorization.Identity{}, errors.New("boom"))
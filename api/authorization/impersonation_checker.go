@@ -0,0 +1,76 @@
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	authv1 "k8s.io/api/authorization/v1"
+)
+
+type ImpersonationChecker struct {
+	userClientFactory UserK8sClientFactory
+}
+
+func NewImpersonationChecker(userClientFactory UserK8sClientFactory) *ImpersonationChecker {
+	return &ImpersonationChecker{
+		userClientFactory: userClientFactory,
+	}
+}
+
+// CanImpersonate asks Kubernetes whether the owner of the authorization info
+// may impersonate the given user and groups, i.e. whether the impersonating
+// requests would be accepted by the Kubernetes API.
+func (c *ImpersonationChecker) CanImpersonate(ctx context.Context, authInfo Info, userName string, groups []string) (bool, error) {
+	userClient, err := c.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return false, fmt.Errorf("failed to create user k8s client: %w", err)
+	}
+
+	attributes := []*authv1.ResourceAttributes{impersonationAttributes(userName)}
+	for _, group := range groups {
+		attributes = append(attributes, &authv1.ResourceAttributes{
+			Verb:     "impersonate",
+			Resource: "groups",
+			Name:     group,
+		})
+	}
+
+	for _, attrs := range attributes {
+		review := authv1.SelfSubjectAccessReview{
+			Spec: authv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: attrs,
+			},
+		}
+		if err := userClient.Create(ctx, &review); err != nil {
+			return false, fmt.Errorf("failed to create self subject access review: %w", apierrors.FromK8sError(err, ""))
+		}
+
+		if !review.Status.Allowed {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func impersonationAttributes(userName string) *authv1.ResourceAttributes {
+	if HasServiceAccountPrefix(userName) {
+		namespace, name, found := strings.Cut(strings.TrimPrefix(userName, serviceAccountNamePrefix), ":")
+		if found {
+			return &authv1.ResourceAttributes{
+				Verb:      "impersonate",
+				Resource:  "serviceaccounts",
+				Namespace: namespace,
+				Name:      name,
+			}
+		}
+	}
+
+	return &authv1.ResourceAttributes{
+		Verb:     "impersonate",
+		Resource: "users",
+		Name:     userName,
+	}
+}
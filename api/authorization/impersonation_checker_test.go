@@ -0,0 +1,137 @@
+package authorization_test
+
+import (
+	"context"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/authorization/testhelpers"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+var _ = Describe("ImpersonationChecker", func() {
+	var (
+		ctx                  context.Context
+		userName             string
+		impersonatedUserName string
+		impersonatedGroups   []string
+		authInfo             authorization.Info
+		checker              *authorization.ImpersonationChecker
+		allowed              bool
+		err                  error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		userName = uuid.NewString()
+		impersonatedUserName = uuid.NewString()
+		impersonatedGroups = nil
+
+		cert, key := testhelpers.ObtainClientCert(testEnv, userName)
+		authInfo = authorization.Info{CertData: testhelpers.JoinCertAndKey(cert, key)}
+
+		httpClient, err := rest.HTTPClientFor(k8sConfig)
+		Expect(err).NotTo(HaveOccurred())
+		mapper, err := apiutil.NewDynamicRESTMapper(k8sConfig, httpClient)
+		Expect(err).NotTo(HaveOccurred())
+		checker = authorization.NewImpersonationChecker(
+			authorization.NewUnprivilegedClientFactory(k8sConfig, mapper, wait.Backoff{Steps: 1, Duration: time.Millisecond}),
+		)
+	})
+
+	JustBeforeEach(func() {
+		allowed, err = checker.CanImpersonate(ctx, authInfo, impersonatedUserName, impersonatedGroups)
+	})
+
+	It("does not allow users without the impersonate permission", func() {
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+	})
+
+	When("the user may impersonate the user", func() {
+		BeforeEach(func() {
+			clusterRole := rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{Name: userName},
+				Rules: []rbacv1.PolicyRule{{
+					Verbs:         []string{"impersonate"},
+					APIGroups:     []string{""},
+					Resources:     []string{"users"},
+					ResourceNames: []string{impersonatedUserName},
+				}},
+			}
+			Expect(k8sClient.Create(ctx, &clusterRole)).To(Succeed())
+			Expect(k8sClient.Create(ctx, &rbacv1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: userName},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: userName}},
+				RoleRef: rbacv1.RoleRef{
+					APIGroup: rbacv1.GroupName,
+					Kind:     "ClusterRole",
+					Name:     clusterRole.Name,
+				},
+			})).To(Succeed())
+		})
+
+		It("allows impersonation", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+		})
+
+		When("impersonating groups too", func() {
+			BeforeEach(func() {
+				impersonatedGroups = []string{uuid.NewString()}
+			})
+
+			It("does not allow impersonation", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(allowed).To(BeFalse())
+			})
+
+			When("the user may impersonate the groups", func() {
+				BeforeEach(func() {
+					clusterRole := rbacv1.ClusterRole{
+						ObjectMeta: metav1.ObjectMeta{Name: userName + "-groups"},
+						Rules: []rbacv1.PolicyRule{{
+							Verbs:         []string{"impersonate"},
+							APIGroups:     []string{""},
+							Resources:     []string{"groups"},
+							ResourceNames: impersonatedGroups,
+						}},
+					}
+					Expect(k8sClient.Create(ctx, &clusterRole)).To(Succeed())
+					Expect(k8sClient.Create(ctx, &rbacv1.ClusterRoleBinding{
+						ObjectMeta: metav1.ObjectMeta{Name: userName + "-groups"},
+						Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: userName}},
+						RoleRef: rbacv1.RoleRef{
+							APIGroup: rbacv1.GroupName,
+							Kind:     "ClusterRole",
+							Name:     clusterRole.Name,
+						},
+					})).To(Succeed())
+				})
+
+				It("allows impersonation", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(allowed).To(BeTrue())
+				})
+			})
+		})
+
+		When("impersonating a different user", func() {
+			BeforeEach(func() {
+				impersonatedUserName = uuid.NewString()
+			})
+
+			It("does not allow impersonation", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(allowed).To(BeFalse())
+			})
+		})
+	})
+})
@@ -3,12 +3,26 @@ package authorization
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
 	"encoding/hex"
+	"encoding/pem"
+	"hash"
+	"time"
+
+	"github.com/golang-jwt/jwt"
 )
 
 type Info struct {
 	Token    string
 	CertData []byte
+	// Impersonate is the name of the user the request acts as. Requests are
+	// still authenticated with Token or CertData, and Kubernetes checks that
+	// their owner is allowed to impersonate.
+	Impersonate string
+	// ImpersonateGroups are the groups of the impersonated user, so that the
+	// roles bound to these groups apply to the requests as well
+	ImpersonateGroups []string
 }
 
 type key int
@@ -41,7 +55,55 @@ func (i Info) Scheme() string {
 }
 
 func (i Info) Hash() string {
-	key := append([]byte(i.Token), i.CertData...)
 	hasher := sha256.New()
-	return hex.EncodeToString(hasher.Sum(key))
+	writeHashField(hasher, []byte(i.Token))
+	writeHashField(hasher, i.CertData)
+	writeHashField(hasher, []byte(i.Impersonate))
+	for _, group := range i.ImpersonateGroups {
+		writeHashField(hasher, []byte(group))
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// writeHashField prefixes the field with its length, so that different
+// combinations of fields never hash the same bytes
+func writeHashField(hasher hash.Hash, field []byte) {
+	hasher.Write(binary.BigEndian.AppendUint64(nil, uint64(len(field))))
+	hasher.Write(field)
+}
+
+// ExpiresAt returns when the credentials expire, or nil if that cannot be
+// determined, e.g. for opaque tokens. Tokens are not verified here, as
+// Kubernetes validates them when authenticating the request.
+func (i Info) ExpiresAt() *time.Time {
+	switch i.Scheme() {
+	case BearerScheme:
+		claims := jwt.MapClaims{}
+		if _, _, err := new(jwt.Parser).ParseUnverified(i.Token, claims); err != nil {
+			return nil
+		}
+
+		exp, ok := claims["exp"].(float64)
+		if !ok {
+			return nil
+		}
+
+		expiresAt := time.Unix(int64(exp), 0)
+		return &expiresAt
+
+	case CertScheme:
+		certBlock, _ := pem.Decode(i.CertData)
+		if certBlock == nil {
+			return nil
+		}
+
+		cert, err := x509.ParseCertificate(certBlock.Bytes)
+		if err != nil {
+			return nil
+		}
+
+		return &cert.NotAfter
+	}
+
+	return nil
 }
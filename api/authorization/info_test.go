@@ -0,0 +1,111 @@
+package authorization_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"github.com/golang-jwt/jwt"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("Info", func() {
+	var (
+		info      authorization.Info
+		expiresAt *time.Time
+	)
+
+	BeforeEach(func() {
+		info = authorization.Info{}
+	})
+
+	JustBeforeEach(func() {
+		expiresAt = info.ExpiresAt()
+	})
+
+	Describe("Hash", func() {
+		It("is stable for the same identity", func() {
+			info = authorization.Info{Token: "token", Impersonate: "alice", ImpersonateGroups: []string{"devs"}}
+			Expect(info.Hash()).To(Equal(authorization.Info{Token: "token", Impersonate: "alice", ImpersonateGroups: []string{"devs"}}.Hash()))
+		})
+
+		It("distinguishes identities whose fields concatenate to the same bytes", func() {
+			Expect(authorization.Info{Token: "token", Impersonate: "alice"}.Hash()).NotTo(Equal(
+				authorization.Info{Token: "tokenalice"}.Hash(),
+			))
+			Expect(authorization.Info{Impersonate: "alice", ImpersonateGroups: []string{"devs"}}.Hash()).NotTo(Equal(
+				authorization.Info{Impersonate: "alicedevs"}.Hash(),
+			))
+			Expect(authorization.Info{ImpersonateGroups: []string{"a", "b"}}.Hash()).NotTo(Equal(
+				authorization.Info{ImpersonateGroups: []string{"ab"}}.Hash(),
+			))
+		})
+	})
+
+	Describe("ExpiresAt", func() {
+		When("the token is a JWT with an expiry", func() {
+			var expiry time.Time
+
+			BeforeEach(func() {
+				expiry = time.Now().Add(time.Hour).Truncate(time.Second)
+				token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+					"sub": "alice",
+					"aud": []string{"korifi"},
+					"exp": expiry.Unix(),
+				}).SignedString([]byte("secret"))
+				Expect(err).NotTo(HaveOccurred())
+				info.Token = token
+			})
+
+			It("returns the token expiry", func() {
+				Expect(expiresAt).To(PointTo(BeTemporally("==", expiry)))
+			})
+		})
+
+		When("the token is opaque", func() {
+			BeforeEach(func() {
+				info.Token = "the-token"
+			})
+
+			It("returns nil", func() {
+				Expect(expiresAt).To(BeNil())
+			})
+		})
+
+		When("the info contains a client certificate", func() {
+			var notAfter time.Time
+
+			BeforeEach(func() {
+				notAfter = time.Now().Add(time.Hour).Truncate(time.Second).UTC()
+				key, err := rsa.GenerateKey(rand.Reader, 2048)
+				Expect(err).NotTo(HaveOccurred())
+				template := x509.Certificate{
+					SerialNumber: big.NewInt(1),
+					Subject:      pkix.Name{CommonName: "alice"},
+					NotBefore:    time.Now(),
+					NotAfter:     notAfter,
+				}
+				certDER, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+				Expect(err).NotTo(HaveOccurred())
+				info.CertData = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+			})
+
+			It("returns the certificate expiry", func() {
+				Expect(expiresAt).To(PointTo(BeTemporally("==", notAfter)))
+			})
+		})
+
+		When("the info is empty", func() {
+			It("returns nil", func() {
+				Expect(expiresAt).To(BeNil())
+			})
+		})
+	})
+})
@@ -2,6 +2,7 @@ package authorization
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -10,11 +11,14 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=list
-//+kubebuilder:rbac:groups="",resources=namespaces,verbs=list
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=list;watch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=list;watch
+
+const roleBindingSubjectsIndex = "roleBindingSubjects"
 
 //counterfeiter:generate -o fake -fake-name IdentityProvider . IdentityProvider
 
@@ -23,15 +27,39 @@ type IdentityProvider interface {
 }
 
 type NamespacePermissions struct {
-	privilegedClient client.Client
+	privilegedClient client.Reader
 	identityProvider IdentityProvider
+	accessCache      *AccessCache
+	subjectIndexed   bool
 }
 
-func NewNamespacePermissions(privilegedClient client.Client, identityProvider IdentityProvider) *NamespacePermissions {
+func NewNamespacePermissions(privilegedClient client.Reader, identityProvider IdentityProvider, accessCache *AccessCache) *NamespacePermissions {
 	return &NamespacePermissions{
 		privilegedClient: privilegedClient,
 		identityProvider: identityProvider,
+		accessCache:      accessCache,
+	}
+}
+
+// NewInformerNamespacePermissions reads role bindings and namespaces from the
+// informer cache, where role bindings are indexed by their subjects. Looking
+// up the namespaces of a user then only touches the bindings of that user and
+// their groups, and the index is kept up to date by RoleBinding events.
+func NewInformerNamespacePermissions(ctx context.Context, informerCache cache.Cache, identityProvider IdentityProvider, accessCache *AccessCache) (*NamespacePermissions, error) {
+	if err := informerCache.IndexField(ctx, &rbacv1.RoleBinding{}, roleBindingSubjectsIndex, roleBindingSubjectKeys); err != nil {
+		return nil, fmt.Errorf("failed to index rolebindings by subject: %w", err)
 	}
+
+	if _, err := informerCache.GetInformer(ctx, &corev1.Namespace{}); err != nil {
+		return nil, fmt.Errorf("failed to create namespace informer: %w", err)
+	}
+
+	return &NamespacePermissions{
+		privilegedClient: informerCache,
+		identityProvider: identityProvider,
+		accessCache:      accessCache,
+		subjectIndexed:   true,
+	}, nil
 }
 
 func (o *NamespacePermissions) GetAuthorizedOrgNamespaces(ctx context.Context, info Info) (map[string]bool, error) {
@@ -48,8 +76,8 @@ func (o *NamespacePermissions) getAuthorizedNamespaces(ctx context.Context, info
 		return nil, fmt.Errorf("failed to get identity: %w", err)
 	}
 
-	var rolebindings rbacv1.RoleBindingList
-	if err := o.privilegedClient.List(ctx, &rolebindings); err != nil {
+	rolebindings, err := o.listRoleBindings(ctx, identity)
+	if err != nil {
 		return nil, fmt.Errorf("failed to list rolebindings: %w", apierrors.FromK8sError(err, resourceType))
 	}
 
@@ -65,7 +93,7 @@ func (o *NamespacePermissions) getAuthorizedNamespaces(ctx context.Context, info
 
 	authorizedNamespaces := map[string]bool{}
 
-	for _, roleBinding := range rolebindings.Items {
+	for _, roleBinding := range rolebindings {
 		for _, subject := range roleBinding.Subjects {
 			isMatch, err := SameSubject(subject, identity)
 			if err != nil {
@@ -83,13 +111,55 @@ func (o *NamespacePermissions) getAuthorizedNamespaces(ctx context.Context, info
 }
 
 func (o *NamespacePermissions) AuthorizedIn(ctx context.Context, identity Identity, namespace string) (bool, error) {
-	var rolebindings rbacv1.RoleBindingList
-	err := o.privilegedClient.List(ctx, &rolebindings, client.InNamespace(namespace))
+	access := Access{Namespace: namespace}
+	if authorized, ok := o.accessCache.Get(identity.Hash(), access); ok {
+		return authorized, nil
+	}
+
+	authorized, err := o.authorizedIn(ctx, identity, namespace)
+	if err != nil {
+		return false, err
+	}
+
+	o.accessCache.Set(identity.Hash(), access, authorized)
+	return authorized, nil
+}
+
+// ForbiddenOrNotFound decides how a forbidden error of an operation on a
+// resource in namespace is reported. Users cannot see resources in namespaces
+// they have no role in, so these are reported as not found in order not to
+// leak their existence. Operations the user is not allowed to perform on
+// resources they can see remain forbidden.
+func (o *NamespacePermissions) ForbiddenOrNotFound(ctx context.Context, info Info, namespace string, err error) error {
+	var forbiddenErr apierrors.ForbiddenError
+	if !errors.As(err, &forbiddenErr) {
+		return err
+	}
+
+	identity, identityErr := o.identityProvider.GetIdentity(ctx, info)
+	if identityErr != nil {
+		return fmt.Errorf("failed to get identity: %w", identityErr)
+	}
+
+	visible, authorizedErr := o.AuthorizedIn(ctx, identity, namespace)
+	if authorizedErr != nil {
+		return fmt.Errorf("failed to check the visibility of namespace %q: %w", namespace, authorizedErr)
+	}
+
+	if visible {
+		return err
+	}
+
+	return apierrors.NewNotFoundError(forbiddenErr.Unwrap(), forbiddenErr.ResourceType())
+}
+
+func (o *NamespacePermissions) authorizedIn(ctx context.Context, identity Identity, namespace string) (bool, error) {
+	rolebindings, err := o.listRoleBindings(ctx, identity, client.InNamespace(namespace))
 	if err != nil {
 		return false, fmt.Errorf("failed to list rolebindings: %w", apierrors.FromK8sError(err, ""))
 	}
 
-	for _, roleBinding := range rolebindings.Items {
+	for _, roleBinding := range rolebindings {
 		for _, subject := range roleBinding.Subjects {
 			isMatch, err := SameSubject(subject, identity)
 			if err != nil {
@@ -104,7 +174,88 @@ func (o *NamespacePermissions) AuthorizedIn(ctx context.Context, identity Identi
 	return false, nil
 }
 
+// listRoleBindings returns the role bindings that may bind the identity.
+// Without the subject index these are all role bindings, so callers still
+// need to match the subjects against the identity.
+func (o *NamespacePermissions) listRoleBindings(ctx context.Context, identity Identity, opts ...client.ListOption) ([]rbacv1.RoleBinding, error) {
+	if !o.subjectIndexed {
+		var rolebindings rbacv1.RoleBindingList
+		if err := o.privilegedClient.List(ctx, &rolebindings, opts...); err != nil {
+			return nil, err
+		}
+		return rolebindings.Items, nil
+	}
+
+	subjectKeys, err := identitySubjectKeys(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	result := []rbacv1.RoleBinding{}
+	for _, subjectKey := range subjectKeys {
+		var rolebindings rbacv1.RoleBindingList
+		if err := o.privilegedClient.List(ctx, &rolebindings, append(opts, client.MatchingFields{roleBindingSubjectsIndex: subjectKey})...); err != nil {
+			return nil, err
+		}
+
+		for _, rb := range rolebindings.Items {
+			key := rb.Namespace + "/" + rb.Name
+			if !seen[key] {
+				seen[key] = true
+				result = append(result, rb)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func roleBindingSubjectKeys(obj client.Object) []string {
+	roleBinding, ok := obj.(*rbacv1.RoleBinding)
+	if !ok {
+		return nil
+	}
+
+	keys := []string{}
+	for _, subject := range roleBinding.Subjects {
+		keys = append(keys, subjectKey(subject.Kind, subject.Namespace, subject.Name))
+	}
+	return keys
+}
+
+func identitySubjectKeys(identity Identity) ([]string, error) {
+	keys := []string{}
+	switch identity.Kind {
+	case rbacv1.ServiceAccountKind:
+		if !HasServiceAccountPrefix(identity.Name) {
+			return nil, fmt.Errorf("expected user identifier %q to have prefix %q", identity.Name, serviceAccountNamePrefix)
+		}
+		saNamespace, saName := ServiceAccountNSAndName(identity.Name)
+		keys = append(keys, subjectKey(rbacv1.ServiceAccountKind, saNamespace, saName))
+	default:
+		keys = append(keys, subjectKey(identity.Kind, "", identity.Name))
+	}
+
+	for _, group := range identity.Groups {
+		keys = append(keys, subjectKey(rbacv1.GroupKind, "", group))
+	}
+
+	return keys, nil
+}
+
+func subjectKey(kind, namespace, name string) string {
+	if kind != rbacv1.ServiceAccountKind {
+		namespace = ""
+	}
+	return kind + "/" + namespace + "/" + name
+}
+
 func SameSubject(subject rbacv1.Subject, identity Identity) (bool, error) {
+	if subject.Kind == rbacv1.GroupKind {
+		return contains(identity.Groups, subject.Name), nil
+	}
+
 	if identity.Kind != subject.Kind {
 		return false, nil
 	}
@@ -120,6 +271,12 @@ func SameSubject(subject rbacv1.Subject, identity Identity) (bool, error) {
 	}
 }
 
+// ServiceAccountUserName returns the user name the service account
+// authenticates as, which is the name of its identity
+func ServiceAccountUserName(serviceAccountNS, serviceAccountName string) string {
+	return serviceAccountNamePrefix + serviceAccountNS + ":" + serviceAccountName
+}
+
 func ServiceAccountNSAndName(serviceAccountSubjectName string) (string, string) {
 	nameSegments := strings.Split(serviceAccountSubjectName, ":")
 
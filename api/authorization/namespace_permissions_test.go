@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"code.cloudfoundry.org/korifi/api/authorization"
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	"code.cloudfoundry.org/korifi/api/handlers/fake"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 
@@ -16,6 +17,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
 )
 
 var _ = Describe("Namespace Permissions", func() {
@@ -28,6 +32,7 @@ var _ = Describe("Namespace Permissions", func() {
 		userIdentity           authorization.Identity
 		serviceAccountIdentity authorization.Identity
 		identityProvider       *fake.IdentityProvider
+		accessCache            *authorization.AccessCache
 
 		space1NS, space2NS   string
 		org1NS, org2NS       string
@@ -108,7 +113,8 @@ var _ = Describe("Namespace Permissions", func() {
 		}
 		identityProvider = new(fake.IdentityProvider)
 
-		nsPerms = authorization.NewNamespacePermissions(k8sClient, identityProvider)
+		accessCache = authorization.NewAccessCache(cache.NewExpiring(), time.Minute)
+		nsPerms = authorization.NewNamespacePermissions(k8sClient, identityProvider, accessCache)
 
 		nonCFNS = createNamespace("non-cf", nil)
 
@@ -369,6 +375,34 @@ var _ = Describe("Namespace Permissions", func() {
 					Expect(authorized).To(BeFalse())
 				})
 			})
+
+			When("the decision is cached", func() {
+				BeforeEach(func() {
+					authorized, err := nsPerms.AuthorizedIn(ctx, userIdentity, org2NS)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(authorized).To(BeFalse())
+
+					createRoleBindingForUser(userName, roleName2, org2NS)
+				})
+
+				It("returns the cached decision", func() {
+					authorized, err := nsPerms.AuthorizedIn(ctx, userIdentity, org2NS)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(authorized).To(BeFalse())
+				})
+
+				When("the cache is invalidated", func() {
+					BeforeEach(func() {
+						accessCache.Invalidate()
+					})
+
+					It("checks the role bindings again", func() {
+						authorized, err := nsPerms.AuthorizedIn(ctx, userIdentity, org2NS)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(authorized).To(BeTrue())
+					})
+				})
+			})
 		})
 
 		When("a service account is authenticated", func() {
@@ -405,6 +439,194 @@ var _ = Describe("Namespace Permissions", func() {
 				})
 			})
 		})
+
+		When("a group of the identity is bound", func() {
+			BeforeEach(func() {
+				userIdentity.Groups = []string{"some-group", "oidc:developers"}
+				createRoleBindingForSubject(rbacv1.Subject{Name: "oidc:developers", Kind: rbacv1.GroupKind}, roleName1, org1NS)
+				createRoleBindingForSubject(rbacv1.Subject{Name: "oidc:other-group", Kind: rbacv1.GroupKind}, roleName1, org2NS)
+			})
+
+			It("authorizes the identity in the namespace bound to its group", func() {
+				authorized, err := nsPerms.AuthorizedIn(ctx, userIdentity, org1NS)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(authorized).To(BeTrue())
+			})
+
+			It("does not authorize the identity in namespaces bound to other groups", func() {
+				authorized, err := nsPerms.AuthorizedIn(ctx, userIdentity, org2NS)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(authorized).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("Forbidden Or Not Found", func() {
+		var (
+			opErr  error
+			result error
+		)
+
+		BeforeEach(func() {
+			org1NS = createNamespace("org1", map[string]string{korifiv1alpha1.OrgNameKey: "org1"})
+			org2NS = createNamespace("org2", map[string]string{korifiv1alpha1.OrgNameKey: "org2"})
+			createRoleBindingForUser(userName, roleName1, org1NS)
+			identityProvider.GetIdentityReturns(userIdentity, nil)
+
+			opErr = apierrors.NewForbiddenError(errors.New("forbidden"), "App")
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: org1NS}})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: org2NS}})).To(Succeed())
+		})
+
+		When("the user has a role in the namespace", func() {
+			JustBeforeEach(func() {
+				result = nsPerms.ForbiddenOrNotFound(ctx, authInfo, org1NS, opErr)
+			})
+
+			It("keeps the forbidden error", func() {
+				Expect(result).To(Equal(opErr))
+			})
+		})
+
+		When("the user has no role in the namespace", func() {
+			JustBeforeEach(func() {
+				result = nsPerms.ForbiddenOrNotFound(ctx, authInfo, org2NS, opErr)
+			})
+
+			It("returns a not found error", func() {
+				Expect(result).To(BeAssignableToTypeOf(apierrors.NotFoundError{}))
+				Expect(result.(apierrors.NotFoundError).Detail()).To(Equal("App not found. Ensure it exists and you have access to it."))
+			})
+
+			When("the error is not a forbidden error", func() {
+				BeforeEach(func() {
+					opErr = errors.New("boom")
+				})
+
+				It("returns the error", func() {
+					Expect(result).To(MatchError("boom"))
+				})
+			})
+		})
+
+		When("getting the identity fails", func() {
+			BeforeEach(func() {
+				identityProvider.GetIdentityReturns(authorization.Identity{}, errors.New("identity-boom"))
+			})
+
+			JustBeforeEach(func() {
+				result = nsPerms.ForbiddenOrNotFound(ctx, authInfo, org2NS, opErr)
+			})
+
+			It("returns the error", func() {
+				Expect(result).To(MatchError(ContainSubstring("identity-boom")))
+			})
+		})
+	})
+})
+
+var _ = Describe("Informer Namespace Permissions", func() {
+	var (
+		ctx              context.Context
+		nsPerms          *authorization.NamespacePermissions
+		identityProvider *fake.IdentityProvider
+		userName         string
+		roleName         string
+		org1NS, org2NS   string
+		userBinding      *rbacv1.RoleBinding
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		userName = generateGUID("alice")
+		identityProvider = new(fake.IdentityProvider)
+		identityProvider.GetIdentityReturns(authorization.Identity{
+			Name:   userName,
+			Kind:   rbacv1.UserKind,
+			Groups: []string{"developers"},
+		}, nil)
+
+		informerCache, err := ctrlcache.New(k8sConfig, ctrlcache.Options{Scheme: scheme.Scheme})
+		Expect(err).NotTo(HaveOccurred())
+		nsPerms, err = authorization.NewInformerNamespacePermissions(ctx, informerCache, identityProvider, authorization.NewAccessCache(cache.NewExpiring(), 0))
+		Expect(err).NotTo(HaveOccurred())
+		go func() {
+			defer GinkgoRecover()
+			Expect(informerCache.Start(ctx)).To(Succeed())
+		}()
+		Expect(informerCache.WaitForCacheSync(ctx)).To(BeTrue())
+
+		roleName = generateGUID("org-user")
+		Expect(k8sClient.Create(ctx, &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: roleName}})).To(Succeed())
+
+		org1NS = generateGUID("org1")
+		org2NS = generateGUID("org2")
+		for _, ns := range []string{org1NS, org2NS} {
+			Expect(k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   ns,
+				Labels: map[string]string{korifiv1alpha1.OrgNameKey: ns},
+			}})).To(Succeed())
+		}
+
+		userBinding = &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "user-binding", Namespace: org1NS},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: userName}},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: roleName},
+		}
+		Expect(k8sClient.Create(ctx, userBinding)).To(Succeed())
+		Expect(k8sClient.Create(ctx, &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "group-binding", Namespace: org2NS},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "developers"}},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: roleName},
+		})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Delete(context.Background(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: org1NS}})).To(Succeed())
+		Expect(k8sClient.Delete(context.Background(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: org2NS}})).To(Succeed())
+		Expect(k8sClient.Delete(context.Background(), &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: roleName}})).To(Succeed())
+	})
+
+	It("lists the namespaces bound to the user and its groups", func() {
+		Eventually(func(g Gomega) {
+			namespaces, err := nsPerms.GetAuthorizedOrgNamespaces(ctx, authorization.Info{Token: "the-token"})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(namespaces).To(Equal(map[string]bool{org1NS: true, org2NS: true}))
+		}).Should(Succeed())
+	})
+
+	It("checks whether the user is authorized in a namespace", func() {
+		Eventually(func(g Gomega) {
+			authorized, err := nsPerms.AuthorizedIn(ctx, authorization.Identity{Name: userName, Kind: rbacv1.UserKind}, org1NS)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(authorized).To(BeTrue())
+		}).Should(Succeed())
+	})
+
+	When("a role binding is deleted", func() {
+		BeforeEach(func() {
+			Eventually(func(g Gomega) {
+				namespaces, err := nsPerms.GetAuthorizedOrgNamespaces(ctx, authorization.Info{Token: "the-token"})
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(namespaces).To(HaveKey(org1NS))
+			}).Should(Succeed())
+
+			Expect(k8sClient.Delete(ctx, userBinding)).To(Succeed())
+		})
+
+		It("no longer lists its namespace", func() {
+			Eventually(func(g Gomega) {
+				namespaces, err := nsPerms.GetAuthorizedOrgNamespaces(ctx, authorization.Info{Token: "the-token"})
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(namespaces).To(Equal(map[string]bool{org2NS: true}))
+			}).Should(Succeed())
+		})
 	})
 })
 
@@ -0,0 +1,212 @@
+package authorization
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	PasswordGrant          = "password"
+	RefreshTokenGrant      = "refresh_token"
+	ClientCredentialsGrant = "client_credentials"
+)
+
+type TokenRequest struct {
+	GrantType    string
+	Username     string
+	Password     string
+	RefreshToken string
+	ClientID     string
+	ClientSecret string
+	Scope        string
+}
+
+type TokenResponse struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64
+}
+
+// OIDCTokenExchanger exchanges IdP credentials for tokens of the configured
+// OIDC provider. The ID token is returned as access token, as this is what
+// the Kubernetes API server authenticates when it is configured with the
+// same provider, so the provider must return an ID token for every grant,
+// including refresh token grants. Password and refresh token grants are
+// requested with the configured client; client credentials grants with the
+// client of the request.
+type OIDCTokenExchanger struct {
+	httpClient   *http.Client
+	issuerURL    string
+	clientID     string
+	clientSecret string
+
+	tokenEndpointMutex sync.Mutex
+	tokenEndpoint      string
+}
+
+func NewOIDCTokenExchanger(httpClient *http.Client, issuerURL, clientID, clientSecret string) *OIDCTokenExchanger {
+	return &OIDCTokenExchanger{
+		httpClient:   httpClient,
+		issuerURL:    strings.TrimSuffix(issuerURL, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+	}
+}
+
+func (e *OIDCTokenExchanger) ExchangeToken(ctx context.Context, tokenRequest TokenRequest) (TokenResponse, error) {
+	form, clientID, clientSecret, err := e.grantForm(tokenRequest)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	tokenEndpoint, err := e.getTokenEndpoint(ctx)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return TokenResponse{}, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return TokenResponse{}, fmt.Errorf("failed to request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tokenResp struct {
+		AccessToken      string `json:"access_token"`
+		IDToken          string `json:"id_token"`
+		RefreshToken     string `json:"refresh_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return TokenResponse{}, fmt.Errorf("failed to decode token response with status %d: %w", resp.StatusCode, err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized:
+		return TokenResponse{}, apierrors.NewInvalidAuthError(fmt.Errorf("%s: %s", tokenResp.Error, tokenResp.ErrorDescription))
+	case resp.StatusCode != http.StatusOK:
+		return TokenResponse{}, fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, tokenResp.Error)
+	}
+
+	// the OAuth access token is not accepted by the Kubernetes API server,
+	// so the client has to authenticate again to get an ID token
+	if tokenResp.IDToken == "" {
+		return TokenResponse{}, apierrors.NewInvalidAuthError(fmt.Errorf("the OIDC provider did not return an ID token for the %s grant", tokenRequest.GrantType))
+	}
+
+	expiresIn, err := idTokenExpiresIn(tokenResp.IDToken, tokenResp.ExpiresIn)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	return TokenResponse{
+		AccessToken:  tokenResp.IDToken,
+		RefreshToken: tokenResp.RefreshToken,
+		ExpiresIn:    expiresIn,
+	}, nil
+}
+
+// idTokenExpiresIn returns the lifetime of the ID token, which can differ
+// from the lifetime of the access token the provider returns as expires_in.
+// The token is not verified, as it comes straight from the provider.
+func idTokenExpiresIn(idToken string, accessTokenExpiresIn int64) (int64, error) {
+	claims := jwt.StandardClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(idToken, &claims); err != nil {
+		return 0, fmt.Errorf("failed to parse the ID token: %w", err)
+	}
+
+	if claims.ExpiresAt == 0 {
+		return accessTokenExpiresIn, nil
+	}
+
+	return max(claims.ExpiresAt-time.Now().Unix(), 0), nil
+}
+
+func (e *OIDCTokenExchanger) grantForm(tokenRequest TokenRequest) (url.Values, string, string, error) {
+	form := url.Values{"grant_type": {tokenRequest.GrantType}}
+
+	switch tokenRequest.GrantType {
+	case PasswordGrant:
+		form.Set("username", tokenRequest.Username)
+		form.Set("password", tokenRequest.Password)
+		form.Set("scope", withOpenIDScope(tokenRequest.Scope))
+		return form, e.clientID, e.clientSecret, nil
+	case RefreshTokenGrant:
+		form.Set("refresh_token", tokenRequest.RefreshToken)
+		return form, e.clientID, e.clientSecret, nil
+	case ClientCredentialsGrant:
+		if tokenRequest.Scope != "" {
+			form.Set("scope", tokenRequest.Scope)
+		}
+		return form, tokenRequest.ClientID, tokenRequest.ClientSecret, nil
+	}
+
+	return nil, "", "", apierrors.NewInvalidAuthError(fmt.Errorf("unsupported grant type %q", tokenRequest.GrantType))
+}
+
+func withOpenIDScope(scope string) string {
+	for _, s := range strings.Fields(scope) {
+		if s == "openid" {
+			return scope
+		}
+	}
+
+	return strings.TrimSpace("openid " + scope)
+}
+
+func (e *OIDCTokenExchanger) getTokenEndpoint(ctx context.Context) (string, error) {
+	e.tokenEndpointMutex.Lock()
+	defer e.tokenEndpointMutex.Unlock()
+
+	if e.tokenEndpoint != "" {
+		return e.tokenEndpoint, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.issuerURL+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create discovery request: %w", err)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to discover the OIDC provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("OIDC provider discovery failed with status %d", resp.StatusCode)
+	}
+
+	var discovery struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&discovery); err != nil {
+		return "", fmt.Errorf("failed to decode the OIDC provider discovery: %w", err)
+	}
+
+	if discovery.TokenEndpoint == "" {
+		return "", errors.New("the OIDC provider does not have a token endpoint")
+	}
+
+	e.tokenEndpoint = discovery.TokenEndpoint
+	return e.tokenEndpoint, nil
+}
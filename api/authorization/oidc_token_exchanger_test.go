@@ -0,0 +1,211 @@
+package authorization_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"github.com/golang-jwt/jwt"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("OIDCTokenExchanger", func() {
+	var (
+		idp          *ghttp.Server
+		exchanger    *authorization.OIDCTokenExchanger
+		tokenRequest authorization.TokenRequest
+		tokenStatus  int
+		tokenBody    map[string]any
+		tokenForm    url.Values
+		tokenResp    authorization.TokenResponse
+		exchangeErr  error
+		idToken      string
+	)
+
+	BeforeEach(func() {
+		idp = ghttp.NewServer()
+		DeferCleanup(idp.Close)
+
+		var err error
+		idToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte("the-signing-key"))
+		Expect(err).NotTo(HaveOccurred())
+
+		tokenStatus = http.StatusOK
+		tokenBody = map[string]any{
+			"access_token":  "the-access-token",
+			"id_token":      idToken,
+			"refresh_token": "the-refresh-token",
+			"expires_in":    300,
+		}
+
+		idp.RouteToHandler(http.MethodGet, "/.well-known/openid-configuration", ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]string{
+			"issuer":         idp.URL(),
+			"token_endpoint": idp.URL() + "/token",
+		}))
+		idp.RouteToHandler(http.MethodPost, "/token", func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.ParseForm()).To(Succeed())
+			tokenForm = r.PostForm
+			clientID, clientSecret, ok := r.BasicAuth()
+			Expect(ok).To(BeTrue())
+			tokenForm.Set("basic_client_id", clientID)
+			tokenForm.Set("basic_client_secret", clientSecret)
+			ghttp.RespondWithJSONEncodedPtr(&tokenStatus, &tokenBody)(w, r)
+		})
+
+		exchanger = authorization.NewOIDCTokenExchanger(&http.Client{}, idp.URL()+"/", "korifi", "korifi-secret")
+
+		tokenRequest = authorization.TokenRequest{
+			GrantType: authorization.PasswordGrant,
+			Username:  "alice",
+			Password:  "alice-password",
+		}
+	})
+
+	JustBeforeEach(func() {
+		tokenResp, exchangeErr = exchanger.ExchangeToken(context.Background(), tokenRequest)
+	})
+
+	It("exchanges the password for the id token with the configured client", func() {
+		Expect(exchangeErr).NotTo(HaveOccurred())
+		Expect(tokenResp.AccessToken).To(Equal(idToken))
+		Expect(tokenResp.RefreshToken).To(Equal("the-refresh-token"))
+
+		Expect(tokenForm.Get("grant_type")).To(Equal("password"))
+		Expect(tokenForm.Get("username")).To(Equal("alice"))
+		Expect(tokenForm.Get("password")).To(Equal("alice-password"))
+		Expect(tokenForm.Get("scope")).To(Equal("openid"))
+		Expect(tokenForm.Get("basic_client_id")).To(Equal("korifi"))
+		Expect(tokenForm.Get("basic_client_secret")).To(Equal("korifi-secret"))
+	})
+
+	It("returns the lifetime of the id token", func() {
+		Expect(exchangeErr).NotTo(HaveOccurred())
+		Expect(tokenResp.ExpiresIn).To(BeNumerically("~", 3600, 5))
+	})
+
+	It("discovers the token endpoint once", func() {
+		_, err := exchanger.ExchangeToken(context.Background(), tokenRequest)
+		Expect(err).NotTo(HaveOccurred())
+
+		discoveryRequests := 0
+		for _, req := range idp.ReceivedRequests() {
+			if req.URL.Path == "/.well-known/openid-configuration" {
+				discoveryRequests++
+			}
+		}
+		Expect(discoveryRequests).To(Equal(1))
+	})
+
+	When("the idp does not return an id token", func() {
+		BeforeEach(func() {
+			delete(tokenBody, "id_token")
+		})
+
+		It("returns an invalid auth error", func() {
+			Expect(exchangeErr).To(BeAssignableToTypeOf(apierrors.InvalidAuthError{}))
+			Expect(exchangeErr).To(MatchError(ContainSubstring("did not return an ID token")))
+		})
+	})
+
+	When("the id token does not expire", func() {
+		BeforeEach(func() {
+			var err error
+			idToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{}).SignedString([]byte("the-signing-key"))
+			Expect(err).NotTo(HaveOccurred())
+			tokenBody["id_token"] = idToken
+		})
+
+		It("returns the lifetime of the access token", func() {
+			Expect(exchangeErr).NotTo(HaveOccurred())
+			Expect(tokenResp.ExpiresIn).To(BeEquivalentTo(300))
+		})
+	})
+
+	When("the id token is not a jwt", func() {
+		BeforeEach(func() {
+			tokenBody["id_token"] = "not-a-jwt"
+		})
+
+		It("returns an error", func() {
+			Expect(exchangeErr).To(MatchError(ContainSubstring("failed to parse the ID token")))
+		})
+	})
+
+	When("the grant is a refresh token", func() {
+		BeforeEach(func() {
+			tokenRequest = authorization.TokenRequest{
+				GrantType:    authorization.RefreshTokenGrant,
+				RefreshToken: "the-old-refresh-token",
+			}
+		})
+
+		It("refreshes the token with the configured client", func() {
+			Expect(exchangeErr).NotTo(HaveOccurred())
+			Expect(tokenForm.Get("grant_type")).To(Equal("refresh_token"))
+			Expect(tokenForm.Get("refresh_token")).To(Equal("the-old-refresh-token"))
+			Expect(tokenForm.Get("basic_client_id")).To(Equal("korifi"))
+		})
+	})
+
+	When("the grant is client credentials", func() {
+		BeforeEach(func() {
+			tokenRequest = authorization.TokenRequest{
+				GrantType:    authorization.ClientCredentialsGrant,
+				ClientID:     "my-client",
+				ClientSecret: "my-client-secret",
+			}
+		})
+
+		It("requests the token with the client of the request", func() {
+			Expect(exchangeErr).NotTo(HaveOccurred())
+			Expect(tokenForm.Get("grant_type")).To(Equal("client_credentials"))
+			Expect(tokenForm.Get("basic_client_id")).To(Equal("my-client"))
+			Expect(tokenForm.Get("basic_client_secret")).To(Equal("my-client-secret"))
+		})
+	})
+
+	When("the grant type is not supported", func() {
+		BeforeEach(func() {
+			tokenRequest.GrantType = "authorization_code"
+		})
+
+		It("returns an invalid auth error", func() {
+			Expect(exchangeErr).To(BeAssignableToTypeOf(apierrors.InvalidAuthError{}))
+		})
+	})
+
+	When("the idp rejects the credentials", func() {
+		BeforeEach(func() {
+			tokenStatus = http.StatusUnauthorized
+			tokenBody = map[string]any{
+				"error":             "invalid_grant",
+				"error_description": "Invalid user credentials",
+			}
+		})
+
+		It("returns an invalid auth error", func() {
+			Expect(exchangeErr).To(BeAssignableToTypeOf(apierrors.InvalidAuthError{}))
+			Expect(exchangeErr).To(MatchError(ContainSubstring("Invalid user credentials")))
+		})
+	})
+
+	When("the idp fails", func() {
+		BeforeEach(func() {
+			tokenStatus = http.StatusInternalServerError
+			tokenBody = map[string]any{"error": "server_error"}
+		})
+
+		It("returns an error", func() {
+			Expect(exchangeErr).To(MatchError(ContainSubstring("status 500")))
+			Expect(exchangeErr).NotTo(BeAssignableToTypeOf(apierrors.InvalidAuthError{}))
+		})
+	})
+})
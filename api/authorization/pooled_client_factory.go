@@ -0,0 +1,63 @@
+package authorization
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/cache"
+	k8sclient "k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PooledClientFactory reuses the clients built for a user across requests,
+// so that the transport and its TLS connections are not set up again on
+// every request. The pool holds the most recently used clients; clients are
+// dropped after the TTL, so that they do not outlive credentials that
+// Kubernetes would no longer accept.
+type PooledClientFactory struct {
+	UserK8sClientFactory
+	clients *cache.LRUExpireCache
+	ttl     time.Duration
+}
+
+type pooledClientKey struct {
+	authInfoHash string
+	k8sClient    bool
+}
+
+func NewPooledClientFactory(userClientFactory UserK8sClientFactory, clients *cache.LRUExpireCache, ttl time.Duration) *PooledClientFactory {
+	return &PooledClientFactory{
+		UserK8sClientFactory: userClientFactory,
+		clients:              clients,
+		ttl:                  ttl,
+	}
+}
+
+func (f *PooledClientFactory) BuildClient(authInfo Info) (client.WithWatch, error) {
+	key := pooledClientKey{authInfoHash: authInfo.Hash()}
+	if pooledClient, ok := f.clients.Get(key); ok {
+		return pooledClient.(client.WithWatch), nil
+	}
+
+	userClient, err := f.UserK8sClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	f.clients.Add(key, userClient, f.ttl)
+	return userClient, nil
+}
+
+func (f *PooledClientFactory) BuildK8sClient(authInfo Info) (k8sclient.Interface, error) {
+	key := pooledClientKey{authInfoHash: authInfo.Hash(), k8sClient: true}
+	if pooledClient, ok := f.clients.Get(key); ok {
+		return pooledClient.(k8sclient.Interface), nil
+	}
+
+	userK8sClient, err := f.UserK8sClientFactory.BuildK8sClient(authInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	f.clients.Add(key, userK8sClient, f.ttl)
+	return userK8sClient, nil
+}
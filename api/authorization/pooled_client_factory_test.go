@@ -0,0 +1,102 @@
+package authorization_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/authorization/fake"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/cache"
+	k8sclient "k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("PooledClientFactory", func() {
+	var (
+		clock          *testing.FakeClock
+		delegate       *fake.UserK8sClientFactory
+		factory        *authorization.PooledClientFactory
+		authInfo       authorization.Info
+		builtClient    client.WithWatch
+		builtK8sClient k8sclient.Interface
+	)
+
+	BeforeEach(func() {
+		clock = testing.NewFakeClock(time.Now())
+		delegate = new(fake.UserK8sClientFactory)
+		builtClient = &struct{ client.WithWatch }{}
+		delegate.BuildClientReturns(builtClient, nil)
+		builtK8sClient = &k8sclient.Clientset{}
+		delegate.BuildK8sClientReturns(builtK8sClient, nil)
+
+		factory = authorization.NewPooledClientFactory(delegate, cache.NewLRUExpireCacheWithClock(2, clock), time.Minute)
+		authInfo = authorization.Info{Token: "alice"}
+	})
+
+	It("reuses clients built for the same auth info", func() {
+		for range 2 {
+			userClient, err := factory.BuildClient(authInfo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(userClient).To(BeIdenticalTo(builtClient))
+		}
+		Expect(delegate.BuildClientCallCount()).To(Equal(1))
+	})
+
+	It("pools k8s clients separately", func() {
+		_, err := factory.BuildClient(authInfo)
+		Expect(err).NotTo(HaveOccurred())
+
+		userK8sClient, err := factory.BuildK8sClient(authInfo)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(userK8sClient).To(BeIdenticalTo(builtK8sClient))
+		Expect(delegate.BuildK8sClientCallCount()).To(Equal(1))
+	})
+
+	It("builds new clients for other auth info", func() {
+		_, err := factory.BuildClient(authInfo)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = factory.BuildClient(authorization.Info{Token: "bob"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(delegate.BuildClientCallCount()).To(Equal(2))
+	})
+
+	It("builds new clients once pooled clients expire", func() {
+		_, err := factory.BuildClient(authInfo)
+		Expect(err).NotTo(HaveOccurred())
+
+		clock.Step(2 * time.Minute)
+
+		_, err = factory.BuildClient(authInfo)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delegate.BuildClientCallCount()).To(Equal(2))
+	})
+
+	It("evicts the least recently used clients when the pool is full", func() {
+		for _, token := range []string{"alice", "bob", "carol", "alice"} {
+			_, err := factory.BuildClient(authorization.Info{Token: token})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(delegate.BuildClientCallCount()).To(Equal(4))
+	})
+
+	When("building the client fails", func() {
+		BeforeEach(func() {
+			delegate.BuildClientReturns(nil, errors.New("boom"))
+		})
+
+		It("does not pool the failure", func() {
+			_, err := factory.BuildClient(authInfo)
+			Expect(err).To(MatchError("boom"))
+
+			delegate.BuildClientReturns(builtClient, nil)
+			_, err = factory.BuildClient(authInfo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(delegate.BuildClientCallCount()).To(Equal(2))
+		})
+	})
+})
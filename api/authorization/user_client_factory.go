@@ -16,9 +16,12 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+//counterfeiter:generate -o fake -fake-name UserK8sClientFactory . UserK8sClientFactory
+
 type UserK8sClientFactory interface {
 	BuildClient(Info) (client.WithWatch, error)
 	BuildK8sClient(info Info) (k8sclient.Interface, error)
@@ -38,6 +41,14 @@ func NewUnprivilegedClientFactory(config *rest.Config, mapper meta.RESTMapper, b
 	}
 }
 
+// WithTransportWrapper returns a factory whose clients send their requests
+// through the wrapped transport
+func (f UnprivilegedClientFactory) WithTransportWrapper(wrapper transport.WrapperFunc) UnprivilegedClientFactory {
+	f.config = rest.CopyConfig(f.config)
+	f.config.Wrap(wrapper)
+	return f
+}
+
 func (f UnprivilegedClientFactory) BuildClient(authInfo Info) (client.WithWatch, error) {
 	config := rest.CopyConfig(f.config)
 
@@ -63,6 +74,9 @@ func (f UnprivilegedClientFactory) BuildClient(authInfo Info) (client.WithWatch,
 		return nil, apierrors.NewNotAuthenticatedError(errors.New("unsupported Authorization header scheme"))
 	}
 
+	config.Impersonate.UserName = authInfo.Impersonate
+	config.Impersonate.Groups = authInfo.ImpersonateGroups
+
 	userClient, err := client.NewWithWatch(config, client.Options{
 		Scheme: scheme.Scheme,
 		Mapper: f.mapper,
@@ -115,6 +129,9 @@ func (f UnprivilegedClientFactory) BuildK8sClient(authInfo Info) (k8sclient.Inte
 		return nil, apierrors.NewNotAuthenticatedError(errors.New("unsupported Authorization header scheme"))
 	}
 
+	config.Impersonate.UserName = authInfo.Impersonate
+	config.Impersonate.Groups = authInfo.ImpersonateGroups
+
 	userK8sClient, err := k8sclient.NewForConfig(config)
 	if err != nil {
 		return nil, apierrors.FromK8sError(err, "")
@@ -86,6 +86,39 @@ var _ = Describe("Unprivileged User Client Factory", func() {
 		})).To(Succeed())
 	}
 
+	allowImpersonatingUsers := func(user string) {
+		impersonateClusterRole := rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: userName + "-impersonate",
+			},
+			Rules: []rbacv1.PolicyRule{
+				{
+					Verbs:     []string{"impersonate"},
+					APIGroups: []string{""},
+					Resources: []string{"users", "groups"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, &impersonateClusterRole)).To(Succeed())
+
+		Expect(k8sClient.Create(ctx, &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: userName + "-impersonate",
+			},
+			Subjects: []rbacv1.Subject{
+				{
+					Kind: rbacv1.UserKind,
+					Name: user,
+				},
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "ClusterRole",
+				Name:     impersonateClusterRole.Name,
+			},
+		})).To(Succeed())
+	}
+
 	Describe("using the client", func() {
 		var podListErr error
 
@@ -119,6 +152,69 @@ var _ = Describe("Unprivileged User Client Factory", func() {
 			})
 		})
 
+		When("impersonating another user", func() {
+			var impersonatedName string
+
+			BeforeEach(func() {
+				impersonatedName = uuid.NewString()
+				cert, key := testhelpers.ObtainClientCert(testEnv, userName)
+				authInfo.CertData = testhelpers.JoinCertAndKey(cert, key)
+				authInfo.Impersonate = impersonatedName
+				allowListingPods(impersonatedName)
+			})
+
+			It("forbids access to users who cannot impersonate", func() {
+				Expect(k8serrors.IsForbidden(podListErr)).To(BeTrue())
+			})
+
+			When("the user can impersonate", func() {
+				BeforeEach(func() {
+					allowImpersonatingUsers(userName)
+				})
+
+				It("acts as the impersonated user", func() {
+					Expect(podListErr).NotTo(HaveOccurred())
+				})
+			})
+		})
+
+		When("impersonating another user with their groups", func() {
+			BeforeEach(func() {
+				cert, key := testhelpers.ObtainClientCert(testEnv, userName)
+				authInfo.CertData = testhelpers.JoinCertAndKey(cert, key)
+				authInfo.Impersonate = uuid.NewString()
+				authInfo.ImpersonateGroups = []string{uuid.NewString()}
+				allowImpersonatingUsers(userName)
+
+				listPodClusterRole := rbacv1.ClusterRole{
+					ObjectMeta: metav1.ObjectMeta{Name: userName + "-group-list-pods"},
+					Rules: []rbacv1.PolicyRule{{
+						Verbs:     []string{"list"},
+						APIGroups: []string{""},
+						Resources: []string{"pods"},
+					}},
+				}
+				Expect(k8sClient.Create(ctx, &listPodClusterRole)).To(Succeed())
+				Expect(k8sClient.Create(ctx, &rbacv1.ClusterRoleBinding{
+					ObjectMeta: metav1.ObjectMeta{Name: userName + "-group-list-pods"},
+					Subjects: []rbacv1.Subject{{
+						Kind:     rbacv1.GroupKind,
+						APIGroup: rbacv1.GroupName,
+						Name:     authInfo.ImpersonateGroups[0],
+					}},
+					RoleRef: rbacv1.RoleRef{
+						APIGroup: rbacv1.GroupName,
+						Kind:     "ClusterRole",
+						Name:     listPodClusterRole.Name,
+					},
+				})).To(Succeed())
+			})
+
+			It("acts with the roles of the impersonated groups", func() {
+				Expect(podListErr).NotTo(HaveOccurred())
+			})
+		})
+
 		Context("tokens", func() {
 			BeforeEach(func() {
 				token := authProvider.GenerateJWTToken(userName)
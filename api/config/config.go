@@ -15,6 +15,9 @@ const (
 	defaultExternalProtocol           = "https"
 	OrgRole                 RoleLevel = "org"
 	SpaceRole               RoleLevel = "space"
+
+	RegistryBlobstore = "registry"
+	S3Blobstore       = "s3"
 )
 
 type (
@@ -24,6 +27,8 @@ type (
 		ReadTimeout       int `yaml:"readTimeout"`
 		ReadHeaderTimeout int `yaml:"readHeaderTimeout"`
 		WriteTimeout      int `yaml:"writeTimeout"`
+		DrainTimeout      int `yaml:"drainTimeout"`
+		ShutdownDelay     int `yaml:"shutdownDelay"`
 
 		ExternalFQDN string `yaml:"externalFQDN"`
 		ExternalPort int    `yaml:"externalPort"`
@@ -36,19 +41,52 @@ type (
 		BuilderName                              string                 `yaml:"builderName"`
 		RunnerName                               string                 `yaml:"runnerName"`
 		ContainerRepositoryPrefix                string                 `yaml:"containerRepositoryPrefix"`
+		ContainerRepositoryTemplate              string                 `yaml:"containerRepositoryTemplate"`
 		ContainerRegistryType                    string                 `yaml:"containerRegistryType"`
 		PackageRegistrySecretNames               []string               `yaml:"packageRegistrySecretNames"`
+		InsecureContainerRegistries              []string               `yaml:"insecureContainerRegistries"`
+		PackageBlobstore                         PackageBlobstoreConfig `yaml:"packageBlobstore"`
 		DefaultDomainName                        string                 `yaml:"defaultDomainName"`
 		UserCertificateExpirationWarningDuration string                 `yaml:"userCertificateExpirationWarningDuration"`
 		DefaultLifecycleConfig                   DefaultLifecycleConfig `yaml:"defaultLifecycleConfig"`
 
-		RoleMappings map[string]Role `yaml:"roleMappings"`
+		// AuthCacheTTL is how long authenticated identities and authorization
+		// decisions are cached for. Defaults to 120s.
+		AuthCacheTTL string `yaml:"authCacheTTL"`
+
+		// UserClientPoolSize is how many user clients are kept for reuse
+		// across requests. Pooled clients expire after AuthCacheTTL.
+		// Defaults to 500.
+		UserClientPoolSize int `yaml:"userClientPoolSize"`
+
+		// MaxPackageUploadSizeMB is the largest request body accepted when
+		// uploading package bits. Defaults to 1024.
+		MaxPackageUploadSizeMB int64 `yaml:"maxPackageUploadSizeMB"`
+
+		RoleMappings      map[string]Role    `yaml:"roleMappings"`
+		GroupRoleMappings []GroupRoleMapping `yaml:"groupRoleMappings"`
 
 		AuthProxyHost   string        `yaml:"authProxyHost"`
 		AuthProxyCACert string        `yaml:"authProxyCACert"`
 		LogLevel        zapcore.Level `yaml:"logLevel"`
 
 		ExperimentalManagedServicesEnabled bool `yaml:"experimentalManagedServicesEnabled"`
+
+		// CachedReadsEnabled makes repositories read CF resources from a
+		// shared informer cache instead of the Kubernetes API
+		CachedReadsEnabled bool `yaml:"cachedReadsEnabled"`
+
+		AccessLog AccessLogConfig `yaml:"accessLog"`
+
+		// PrometheusURL is the base URL of the Prometheus serving the log-cache
+		// PromQL endpoints. When empty, these endpoints return no metrics.
+		PrometheusURL string `yaml:"prometheusURL"`
+
+		OAuthShim OAuthShimConfig `yaml:"oauthShim"`
+
+		// StrictManifestFields rejects manifests with fields that Korifi does
+		// not support instead of ignoring them
+		StrictManifestFields bool `yaml:"strictManifestFields"`
 	}
 
 	RoleLevel string
@@ -59,11 +97,51 @@ type (
 		Propagate bool      `yaml:"propagate"`
 	}
 
+	// GroupRoleMapping grants a CF role to every member of an identity
+	// provider group. Org and Space hold the GUIDs the role applies to and
+	// must match the level of the role.
+	GroupRoleMapping struct {
+		Group string `yaml:"group"`
+		Role  string `yaml:"role"`
+		Org   string `yaml:"org"`
+		Space string `yaml:"space"`
+	}
+
 	// DefaultLifecycleConfig contains default values of the Lifecycle block of CFApps and Builds created by the Shim
 	DefaultLifecycleConfig struct {
-		Type            string `yaml:"type"`
-		Stack           string `yaml:"stack"`
-		StagingMemoryMB int    `yaml:"stagingMemoryMB"`
+		Type  string `yaml:"type"`
+		Stack string `yaml:"stack"`
+	}
+
+	// AccessLogConfig configures the gorouter style access log. Path can be
+	// "stdout", "stderr" or a file path; it defaults to stdout.
+	AccessLogConfig struct {
+		Enabled bool   `yaml:"enabled"`
+		Path    string `yaml:"path"`
+	}
+
+	// OAuthShimConfig configures the login and token endpoints that exchange
+	// IdP credentials with the OIDC provider at IssuerURL. They are disabled
+	// when IssuerURL is empty. The client secret is taken from the
+	// environment.
+	OAuthShimConfig struct {
+		IssuerURL string `yaml:"issuerURL"`
+		ClientID  string `yaml:"clientID"`
+	}
+
+	// PackageBlobstoreConfig configures where package bits are stored. Type
+	// is either "registry" (the default), which pushes the bits as images to
+	// the container registry, or "s3", which uploads them to an S3-compatible
+	// bucket. The credentials for the bucket are taken from the environment.
+	PackageBlobstoreConfig struct {
+		Type string            `yaml:"type"`
+		S3   S3BlobstoreConfig `yaml:"s3"`
+	}
+
+	S3BlobstoreConfig struct {
+		Endpoint string `yaml:"endpoint"`
+		Bucket   string `yaml:"bucket"`
+		Region   string `yaml:"region"`
 	}
 
 	InfoConfig struct {
@@ -124,13 +202,132 @@ func (c *APIConfig) validate() error {
 		}
 	}
 
+	if c.AuthCacheTTL != "" {
+		if _, err := time.ParseDuration(c.AuthCacheTTL); err != nil {
+			return errors.New(`invalid duration format for authCacheTTL. Use a format like "2m"`)
+		}
+	}
+
+	if c.UserClientPoolSize < 0 {
+		return errors.New("userClientPoolSize must not be negative")
+	}
+
+	if c.MaxPackageUploadSizeMB < 0 {
+		return errors.New("maxPackageUploadSizeMB must not be negative")
+	}
+
+	if c.OAuthShim.IssuerURL != "" && c.OAuthShim.ClientID == "" {
+		return errors.New("oauthShim.issuerURL requires a value for oauthShim.clientID")
+	}
+
+	if c.DrainTimeout < 0 {
+		return errors.New("drainTimeout must not be negative")
+	}
+
+	if c.ShutdownDelay < 0 {
+		return errors.New("shutdownDelay must not be negative")
+	}
+
 	if c.BuilderName == "" {
 		return errors.New("BuilderName must have a value")
 	}
 
+	if err := c.validatePackageBlobstore(); err != nil {
+		return err
+	}
+
+	if err := c.validateRoleMappings(); err != nil {
+		return err
+	}
+
+	for _, mapping := range c.GroupRoleMappings {
+		if err := c.validateGroupRoleMapping(mapping); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+func (c *APIConfig) validatePackageBlobstore() error {
+	switch c.PackageBlobstore.Type {
+	case "", RegistryBlobstore:
+		return nil
+	case S3Blobstore:
+		if c.PackageBlobstore.S3.Endpoint == "" || c.PackageBlobstore.S3.Bucket == "" {
+			return errors.New("packageBlobstore: the s3 blobstore requires an endpoint and a bucket")
+		}
+		return nil
+	default:
+		return fmt.Errorf("packageBlobstore: invalid type %q, must be %q or %q", c.PackageBlobstore.Type, RegistryBlobstore, S3Blobstore)
+	}
+}
+
+func (c *APIConfig) validateRoleMappings() error {
+	clusterRoles := map[string]string{}
+	for cfRole, role := range c.RoleMappings {
+		if role.Name == "" {
+			return fmt.Errorf("roleMappings: role %q must have a ClusterRole name", cfRole)
+		}
+
+		if role.Level != "" && role.Level != OrgRole && role.Level != SpaceRole {
+			return fmt.Errorf("roleMappings: role %q has invalid level %q, must be %q or %q", cfRole, role.Level, OrgRole, SpaceRole)
+		}
+
+		// the ClusterRole of a role binding is all that identifies its CF role
+		if otherCFRole, ok := clusterRoles[role.Name]; ok {
+			return fmt.Errorf("roleMappings: roles %q and %q map to the same ClusterRole %q", otherCFRole, cfRole, role.Name)
+		}
+		clusterRoles[role.Name] = cfRole
+	}
+
+	return nil
+}
+
+// AssignableRoles returns the level of every role that can be assigned
+// through the roles API, i.e. every org and space role including the custom
+// ones defined by the operator
+func (c *APIConfig) AssignableRoles() map[string]RoleLevel {
+	roles := map[string]RoleLevel{}
+	for cfRole, role := range c.RoleMappings {
+		if role.Level == OrgRole || role.Level == SpaceRole {
+			roles[cfRole] = role.Level
+		}
+	}
+
+	return roles
+}
+
+func (c *APIConfig) validateGroupRoleMapping(mapping GroupRoleMapping) error {
+	if mapping.Group == "" {
+		return errors.New("groupRoleMappings: group must have a value")
+	}
+
+	role, ok := c.RoleMappings[mapping.Role]
+	if !ok {
+		return fmt.Errorf("groupRoleMappings: unknown role %q for group %q", mapping.Role, mapping.Group)
+	}
+
+	switch role.Level {
+	case OrgRole:
+		if mapping.Org == "" || mapping.Space != "" {
+			return fmt.Errorf("groupRoleMappings: org role %q for group %q requires an org and no space", mapping.Role, mapping.Group)
+		}
+	case SpaceRole:
+		if mapping.Space == "" || mapping.Org != "" {
+			return fmt.Errorf("groupRoleMappings: space role %q for group %q requires a space and no org", mapping.Role, mapping.Group)
+		}
+	default:
+		return fmt.Errorf("groupRoleMappings: role %q for group %q is neither an org nor a space role", mapping.Role, mapping.Group)
+	}
+
+	return nil
+}
+
+func (c OAuthShimConfig) Enabled() bool {
+	return c.IssuerURL != ""
+}
+
 func (c *APIConfig) GetUserCertificateDuration() time.Duration {
 	if c.UserCertificateExpirationWarningDuration == "" {
 		return time.Hour * 24 * 7
@@ -139,6 +336,41 @@ func (c *APIConfig) GetUserCertificateDuration() time.Duration {
 	return d
 }
 
+func (c *APIConfig) GetAuthCacheTTL() time.Duration {
+	if c.AuthCacheTTL == "" {
+		return 120 * time.Second
+	}
+	d, _ := time.ParseDuration(c.AuthCacheTTL)
+	return d
+}
+
+func (c *APIConfig) GetUserClientPoolSize() int {
+	if c.UserClientPoolSize == 0 {
+		return 500
+	}
+	return c.UserClientPoolSize
+}
+
+func (c *APIConfig) GetMaxPackageUploadSize() int64 {
+	if c.MaxPackageUploadSizeMB == 0 {
+		return 1024 * 1024 * 1024
+	}
+	return c.MaxPackageUploadSizeMB * 1024 * 1024
+}
+
+// GetShutdownDelay is how long the API keeps serving requests after it
+// started failing readiness, so that it is removed from the API endpoints
+// before it stops accepting connections
+func (c *APIConfig) GetShutdownDelay() time.Duration {
+	return time.Duration(c.ShutdownDelay) * time.Second
+}
+
+// GetDrainTimeout is how long the API waits for in-flight requests, e.g.
+// package uploads, to complete on shutdown
+func (c *APIConfig) GetDrainTimeout() time.Duration {
+	return time.Duration(c.DrainTimeout) * time.Second
+}
+
 func (c *APIConfig) composeServerURL() (string, error) {
 	toReturn := defaultExternalProtocol + "://" + c.ExternalFQDN
 
@@ -2,6 +2,7 @@ package config_test
 
 import (
 	"os"
+	"time"
 
 	"go.uber.org/zap/zapcore"
 
@@ -42,9 +43,8 @@ var _ = Describe("Config", func() {
 			"defaultDomainName":                        "default.domain",
 			"userCertificateExpirationWarningDuration": "10s",
 			"defaultLifecycleConfig": config.DefaultLifecycleConfig{
-				Type:            "lc-type",
-				Stack:           "lc-stack",
-				StagingMemoryMB: 10,
+				Type:  "lc-type",
+				Stack: "lc-stack",
 			},
 			"experimentalManagedServicesEnabled": true,
 		}
@@ -84,14 +84,182 @@ var _ = Describe("Config", func() {
 		Expect(cfg.DefaultDomainName).To(Equal("default.domain"))
 		Expect(cfg.UserCertificateExpirationWarningDuration).To(Equal("10s"))
 		Expect(cfg.DefaultLifecycleConfig).To(Equal(config.DefaultLifecycleConfig{
-			Type:            "lc-type",
-			Stack:           "lc-stack",
-			StagingMemoryMB: 10,
+			Type:  "lc-type",
+			Stack: "lc-stack",
 		}))
 		Expect(cfg.ContainerRegistryType).To(BeEmpty())
 		Expect(cfg.ExperimentalManagedServicesEnabled).To(BeTrue())
 	})
 
+	When("the access log is configured", func() {
+		BeforeEach(func() {
+			configMap["accessLog"] = map[string]any{
+				"enabled": true,
+				"path":    "/var/log/access.log",
+			}
+		})
+
+		It("sets it in the config", func() {
+			Expect(loadErr).NotTo(HaveOccurred())
+			Expect(cfg.AccessLog).To(Equal(config.AccessLogConfig{
+				Enabled: true,
+				Path:    "/var/log/access.log",
+			}))
+		})
+	})
+
+	When("the prometheus URL is configured", func() {
+		BeforeEach(func() {
+			configMap["prometheusURL"] = "http://prometheus.monitoring:9090"
+		})
+
+		It("sets it in the config", func() {
+			Expect(loadErr).NotTo(HaveOccurred())
+			Expect(cfg.PrometheusURL).To(Equal("http://prometheus.monitoring:9090"))
+		})
+	})
+
+	When("role mappings are configured", func() {
+		BeforeEach(func() {
+			configMap["roleMappings"] = map[string]config.Role{
+				"admin":           {Name: "admin", Propagate: true},
+				"space_developer": {Name: "space-developer", Level: config.SpaceRole},
+				"org_operator":    {Name: "custom-org-operator", Level: config.OrgRole, Propagate: true},
+			}
+		})
+
+		It("loads the role mappings", func() {
+			Expect(loadErr).NotTo(HaveOccurred())
+			Expect(cfg.RoleMappings).To(HaveKeyWithValue("org_operator", config.Role{Name: "custom-org-operator", Level: config.OrgRole, Propagate: true}))
+		})
+
+		It("returns the org and space roles as assignable", func() {
+			Expect(cfg.AssignableRoles()).To(Equal(map[string]config.RoleLevel{
+				"space_developer": config.SpaceRole,
+				"org_operator":    config.OrgRole,
+			}))
+		})
+
+		When("a role has no ClusterRole name", func() {
+			BeforeEach(func() {
+				configMap["roleMappings"] = map[string]config.Role{
+					"space_developer": {Level: config.SpaceRole},
+				}
+			})
+
+			It("returns an error", func() {
+				Expect(loadErr).To(MatchError(ContainSubstring(`role "space_developer" must have a ClusterRole name`)))
+			})
+		})
+
+		When("a role has an invalid level", func() {
+			BeforeEach(func() {
+				configMap["roleMappings"] = map[string]config.Role{
+					"space_developer": {Name: "space-developer", Level: "foundation"},
+				}
+			})
+
+			It("returns an error", func() {
+				Expect(loadErr).To(MatchError(ContainSubstring(`invalid level "foundation"`)))
+			})
+		})
+
+		When("two roles map to the same ClusterRole", func() {
+			BeforeEach(func() {
+				configMap["roleMappings"] = map[string]config.Role{
+					"space_developer": {Name: "space-developer", Level: config.SpaceRole},
+					"space_operator":  {Name: "space-developer", Level: config.SpaceRole},
+				}
+			})
+
+			It("returns an error", func() {
+				Expect(loadErr).To(MatchError(ContainSubstring(`map to the same ClusterRole "space-developer"`)))
+			})
+		})
+	})
+
+	When("group role mappings are configured", func() {
+		BeforeEach(func() {
+			configMap["roleMappings"] = map[string]config.Role{
+				"organization_manager": {Name: "org-manager", Level: config.OrgRole},
+				"space_developer":      {Name: "space-developer", Level: config.SpaceRole},
+				"admin":                {Name: "admin"},
+			}
+			configMap["groupRoleMappings"] = []config.GroupRoleMapping{
+				{Group: "managers", Role: "organization_manager", Org: "org-guid"},
+				{Group: "developers", Role: "space_developer", Space: "space-guid"},
+			}
+		})
+
+		It("loads the group role mappings", func() {
+			Expect(loadErr).NotTo(HaveOccurred())
+			Expect(cfg.GroupRoleMappings).To(ConsistOf(
+				config.GroupRoleMapping{Group: "managers", Role: "organization_manager", Org: "org-guid"},
+				config.GroupRoleMapping{Group: "developers", Role: "space_developer", Space: "space-guid"},
+			))
+		})
+
+		When("a mapping references an unknown role", func() {
+			BeforeEach(func() {
+				configMap["groupRoleMappings"] = []config.GroupRoleMapping{
+					{Group: "managers", Role: "no_such_role", Org: "org-guid"},
+				}
+			})
+
+			It("returns an error", func() {
+				Expect(loadErr).To(MatchError(ContainSubstring(`unknown role "no_such_role"`)))
+			})
+		})
+
+		When("a mapping has no group", func() {
+			BeforeEach(func() {
+				configMap["groupRoleMappings"] = []config.GroupRoleMapping{
+					{Role: "organization_manager", Org: "org-guid"},
+				}
+			})
+
+			It("returns an error", func() {
+				Expect(loadErr).To(MatchError(ContainSubstring("group must have a value")))
+			})
+		})
+
+		When("an org role mapping specifies a space", func() {
+			BeforeEach(func() {
+				configMap["groupRoleMappings"] = []config.GroupRoleMapping{
+					{Group: "managers", Role: "organization_manager", Space: "space-guid"},
+				}
+			})
+
+			It("returns an error", func() {
+				Expect(loadErr).To(MatchError(ContainSubstring("requires an org")))
+			})
+		})
+
+		When("a space role mapping specifies an org", func() {
+			BeforeEach(func() {
+				configMap["groupRoleMappings"] = []config.GroupRoleMapping{
+					{Group: "developers", Role: "space_developer", Org: "org-guid"},
+				}
+			})
+
+			It("returns an error", func() {
+				Expect(loadErr).To(MatchError(ContainSubstring("requires a space")))
+			})
+		})
+
+		When("a mapping references a global role", func() {
+			BeforeEach(func() {
+				configMap["groupRoleMappings"] = []config.GroupRoleMapping{
+					{Group: "admins", Role: "admin"},
+				}
+			})
+
+			It("returns an error", func() {
+				Expect(loadErr).To(MatchError(ContainSubstring("neither an org nor a space role")))
+			})
+		})
+	})
+
 	When("the FQDN is not specified", func() {
 		BeforeEach(func() {
 			delete(configMap, "externalFQDN")
@@ -112,6 +280,16 @@ var _ = Describe("Config", func() {
 		})
 	})
 
+	When("insecure container registries are configured", func() {
+		BeforeEach(func() {
+			configMap["insecureContainerRegistries"] = []string{"registry.internal:5000"}
+		})
+
+		It("sets them in the config", func() {
+			Expect(cfg.InsecureContainerRegistries).To(ConsistOf("registry.internal:5000"))
+		})
+	})
+
 	When("the auth proxy is configured", func() {
 		BeforeEach(func() {
 			configMap["authProxyHost"] = "my-auth-proxy"
@@ -177,6 +355,181 @@ var _ = Describe("Config", func() {
 		})
 	})
 
+	It("defaults the auth cache ttl", func() {
+		Expect(loadErr).NotTo(HaveOccurred())
+		Expect(cfg.GetAuthCacheTTL()).To(Equal(120 * time.Second))
+	})
+
+	When("the auth cache ttl is configured", func() {
+		BeforeEach(func() {
+			configMap["authCacheTTL"] = "30s"
+		})
+
+		It("uses it", func() {
+			Expect(loadErr).NotTo(HaveOccurred())
+			Expect(cfg.GetAuthCacheTTL()).To(Equal(30 * time.Second))
+		})
+
+		When("it is invalid", func() {
+			BeforeEach(func() {
+				configMap["authCacheTTL"] = "invalid-duration"
+			})
+
+			It("returns an error", func() {
+				Expect(loadErr).To(MatchError(ContainSubstring("invalid duration format for authCacheTTL")))
+			})
+		})
+	})
+
+	It("defaults the user client pool size", func() {
+		Expect(loadErr).NotTo(HaveOccurred())
+		Expect(cfg.GetUserClientPoolSize()).To(Equal(500))
+	})
+
+	When("the user client pool size is configured", func() {
+		BeforeEach(func() {
+			configMap["userClientPoolSize"] = 10
+		})
+
+		It("uses it", func() {
+			Expect(loadErr).NotTo(HaveOccurred())
+			Expect(cfg.GetUserClientPoolSize()).To(Equal(10))
+		})
+
+		When("it is negative", func() {
+			BeforeEach(func() {
+				configMap["userClientPoolSize"] = -1
+			})
+
+			It("returns an error", func() {
+				Expect(loadErr).To(MatchError(ContainSubstring("userClientPoolSize must not be negative")))
+			})
+		})
+	})
+
+	It("defaults the max package upload size to 1GB", func() {
+		Expect(loadErr).NotTo(HaveOccurred())
+		Expect(cfg.GetMaxPackageUploadSize()).To(BeEquivalentTo(1024 * 1024 * 1024))
+	})
+
+	When("the max package upload size is configured", func() {
+		BeforeEach(func() {
+			configMap["maxPackageUploadSizeMB"] = 10
+		})
+
+		It("uses it", func() {
+			Expect(loadErr).NotTo(HaveOccurred())
+			Expect(cfg.GetMaxPackageUploadSize()).To(BeEquivalentTo(10 * 1024 * 1024))
+		})
+
+		When("it is negative", func() {
+			BeforeEach(func() {
+				configMap["maxPackageUploadSizeMB"] = -1
+			})
+
+			It("returns an error", func() {
+				Expect(loadErr).To(MatchError(ContainSubstring("maxPackageUploadSizeMB must not be negative")))
+			})
+		})
+	})
+
+	When("the oauth shim issuer is set without a client", func() {
+		BeforeEach(func() {
+			configMap["oauthShim"] = map[string]any{"issuerURL": "https://idp.example.org"}
+		})
+
+		It("returns an error", func() {
+			Expect(loadErr).To(MatchError(ContainSubstring("oauthShim.issuerURL requires a value for oauthShim.clientID")))
+		})
+	})
+
+	It("does not wait on shutdown by default", func() {
+		Expect(loadErr).NotTo(HaveOccurred())
+		Expect(cfg.GetShutdownDelay()).To(BeZero())
+		Expect(cfg.GetDrainTimeout()).To(BeZero())
+	})
+
+	When("the shutdown delay and drain timeout are configured", func() {
+		BeforeEach(func() {
+			configMap["shutdownDelay"] = 5
+			configMap["drainTimeout"] = 900
+		})
+
+		It("uses them", func() {
+			Expect(loadErr).NotTo(HaveOccurred())
+			Expect(cfg.GetShutdownDelay()).To(Equal(5 * time.Second))
+			Expect(cfg.GetDrainTimeout()).To(Equal(900 * time.Second))
+		})
+
+		When("the drain timeout is negative", func() {
+			BeforeEach(func() {
+				configMap["drainTimeout"] = -1
+			})
+
+			It("returns an error", func() {
+				Expect(loadErr).To(MatchError(ContainSubstring("drainTimeout must not be negative")))
+			})
+		})
+
+		When("the shutdown delay is negative", func() {
+			BeforeEach(func() {
+				configMap["shutdownDelay"] = -1
+			})
+
+			It("returns an error", func() {
+				Expect(loadErr).To(MatchError(ContainSubstring("shutdownDelay must not be negative")))
+			})
+		})
+	})
+
+	When("the package blobstore is s3", func() {
+		BeforeEach(func() {
+			configMap["packageBlobstore"] = map[string]any{
+				"type": "s3",
+				"s3": map[string]any{
+					"endpoint": "https://s3.example.org",
+					"bucket":   "packages",
+					"region":   "eu-west-1",
+				},
+			}
+		})
+
+		It("loads the s3 config", func() {
+			Expect(loadErr).NotTo(HaveOccurred())
+			Expect(cfg.PackageBlobstore).To(Equal(config.PackageBlobstoreConfig{
+				Type: "s3",
+				S3: config.S3BlobstoreConfig{
+					Endpoint: "https://s3.example.org",
+					Bucket:   "packages",
+					Region:   "eu-west-1",
+				},
+			}))
+		})
+
+		When("the bucket is missing", func() {
+			BeforeEach(func() {
+				configMap["packageBlobstore"] = map[string]any{
+					"type": "s3",
+					"s3":   map[string]any{"endpoint": "https://s3.example.org"},
+				}
+			})
+
+			It("returns an error", func() {
+				Expect(loadErr).To(MatchError(ContainSubstring("requires an endpoint and a bucket")))
+			})
+		})
+	})
+
+	When("the package blobstore type is invalid", func() {
+		BeforeEach(func() {
+			configMap["packageBlobstore"] = map[string]any{"type": "floppy"}
+		})
+
+		It("returns an error", func() {
+			Expect(loadErr).To(MatchError(ContainSubstring(`invalid type "floppy"`)))
+		})
+	})
+
 	When("the builder is not specified", func() {
 		BeforeEach(func() {
 			delete(configMap, "builderName")
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap"
+)
+
+// Reloadable is a config value that changes when the config file is
+// reloaded. It is safe for concurrent use.
+type Reloadable[T any] struct {
+	value atomic.Pointer[T]
+}
+
+func NewReloadable[T any](value T) *Reloadable[T] {
+	r := &Reloadable[T]{}
+	r.Set(value)
+	return r
+}
+
+func (r *Reloadable[T]) Get() T {
+	return *r.value.Load()
+}
+
+func (r *Reloadable[T]) Set(value T) {
+	r.value.Store(&value)
+}
+
+// Reloader reloads the config whenever the config file changes and applies
+// the settings that can change without restarting the API: the log level,
+// the default domain, the builder name and the role mappings. Changes to any
+// other setting require a restart. Configs that fail validation are logged
+// and ignored.
+type Reloader struct {
+	logger      logr.Logger
+	atomicLevel zap.AtomicLevel
+	current     *APIConfig
+	hooks       []func(context.Context, *APIConfig)
+
+	DefaultDomainName *Reloadable[string]
+	BuilderName       *Reloadable[string]
+	RoleMappings      *Reloadable[map[string]Role]
+}
+
+func NewReloader(cfg *APIConfig, atomicLevel zap.AtomicLevel, logger logr.Logger) *Reloader {
+	return &Reloader{
+		logger:            logger,
+		atomicLevel:       atomicLevel,
+		current:           cfg,
+		DefaultDomainName: NewReloadable(cfg.DefaultDomainName),
+		BuilderName:       NewReloadable(cfg.BuilderName),
+		RoleMappings:      NewReloadable(cfg.RoleMappings),
+	}
+}
+
+// OnReload registers a hook that is called with the reloaded config whenever
+// it changed, e.g. to apply a setting kept outside of the reloader
+func (r *Reloader) OnReload(hook func(ctx context.Context, cfg *APIConfig)) {
+	r.hooks = append(r.hooks, hook)
+}
+
+// Run reloads the config from the paths sent on eventChan, e.g. by
+// tools.WatchForConfigChangeEvents, until the context is done
+func (r *Reloader) Run(ctx context.Context, eventChan <-chan string) {
+	for {
+		select {
+		case configPath := <-eventChan:
+			cfg, err := LoadFromPath(configPath)
+			if err != nil {
+				r.logger.Error(err, "error reloading config, keeping the current config")
+				continue
+			}
+
+			r.apply(ctx, cfg)
+
+		case <-ctx.Done():
+			r.logger.Info("stopping config reloader")
+			return
+		}
+	}
+}
+
+func (r *Reloader) apply(ctx context.Context, cfg *APIConfig) {
+	if r.atomicLevel.Level() != cfg.LogLevel {
+		r.logger.Info("updating logging level", "originalLevel", r.atomicLevel.Level(), "newLevel", cfg.LogLevel)
+		r.atomicLevel.SetLevel(cfg.LogLevel)
+	}
+
+	if reflect.DeepEqual(r.current, cfg) {
+		return
+	}
+
+	r.logger.Info("reloading config")
+	r.DefaultDomainName.Set(cfg.DefaultDomainName)
+	r.BuilderName.Set(cfg.BuilderName)
+	r.RoleMappings.Set(cfg.RoleMappings)
+
+	for _, hook := range r.hooks {
+		hook(ctx, cfg)
+	}
+
+	r.current = cfg
+}
@@ -0,0 +1,121 @@
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/korifi/api/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("Reloader", func() {
+	var (
+		configMap     map[string]any
+		configPath    string
+		atomicLevel   zap.AtomicLevel
+		reloader      *config.Reloader
+		eventChan     chan string
+		reloadedCfgs  chan *config.APIConfig
+		cancelContext context.CancelFunc
+	)
+
+	writeConfig := func() {
+		configBytes, err := yaml.Marshal(configMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(configPath, "config.yaml"), configBytes, 0o644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		configPath = GinkgoT().TempDir()
+		configMap = map[string]any{
+			"externalFQDN":      "api.foo",
+			"builderName":       "my-builder",
+			"defaultDomainName": "default.domain",
+			"logLevel":          "info",
+			"roleMappings": map[string]any{
+				"space_developer": map[string]any{"name": "korifi-space-developer", "level": "space"},
+			},
+		}
+		writeConfig()
+
+		cfg, err := config.LoadFromPath(configPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		atomicLevel = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+		reloader = config.NewReloader(cfg, atomicLevel, ctrl.Log)
+
+		reloadedCfgs = make(chan *config.APIConfig, 10)
+		reloader.OnReload(func(_ context.Context, cfg *config.APIConfig) {
+			reloadedCfgs <- cfg
+		})
+
+		eventChan = make(chan string)
+		var ctx context.Context
+		ctx, cancelContext = context.WithCancel(context.Background())
+		DeferCleanup(cancelContext)
+		go reloader.Run(ctx, eventChan)
+	})
+
+	It("starts with the settings of the config", func() {
+		Expect(reloader.DefaultDomainName.Get()).To(Equal("default.domain"))
+		Expect(reloader.BuilderName.Get()).To(Equal("my-builder"))
+		Expect(reloader.RoleMappings.Get()).To(HaveKey("space_developer"))
+	})
+
+	When("the config changes", func() {
+		BeforeEach(func() {
+			configMap["defaultDomainName"] = "new.domain"
+			configMap["builderName"] = "new-builder"
+			configMap["logLevel"] = "debug"
+			configMap["roleMappings"] = map[string]any{
+				"space_operator": map[string]any{"name": "korifi-space-operator", "level": "space"},
+			}
+			writeConfig()
+			eventChan <- configPath
+		})
+
+		It("applies the reloadable settings", func() {
+			Eventually(reloader.DefaultDomainName.Get).Should(Equal("new.domain"))
+			Eventually(reloader.BuilderName.Get).Should(Equal("new-builder"))
+			Eventually(reloader.RoleMappings.Get).Should(SatisfyAll(
+				HaveKey("space_operator"),
+				Not(HaveKey("space_developer")),
+			))
+			Eventually(atomicLevel.Level).Should(Equal(zapcore.DebugLevel))
+		})
+
+		It("calls the reload hooks", func() {
+			Eventually(reloadedCfgs).Should(Receive(HaveField("DefaultDomainName", "new.domain")))
+		})
+	})
+
+	When("the config did not change", func() {
+		BeforeEach(func() {
+			eventChan <- configPath
+		})
+
+		It("does not call the reload hooks", func() {
+			Consistently(reloadedCfgs).ShouldNot(Receive())
+		})
+	})
+
+	When("the changed config is invalid", func() {
+		BeforeEach(func() {
+			configMap["defaultDomainName"] = "new.domain"
+			configMap["builderName"] = ""
+			writeConfig()
+			eventChan <- configPath
+		})
+
+		It("keeps the current config", func() {
+			Consistently(reloader.DefaultDomainName.Get).Should(Equal("default.domain"))
+			Expect(reloadedCfgs).NotTo(Receive())
+		})
+	})
+})
@@ -255,11 +255,16 @@ type UnknownKeyError struct {
 }
 
 func NewUnknownKeyError(cause error, validKeys []string) UnknownKeyError {
+	detail := fmt.Sprintf("The query parameter is invalid: Valid parameters are: %s", quotedCommaSeparatedList(validKeys))
+	if len(validKeys) == 0 {
+		detail = "The query parameter is invalid: This endpoint does not accept query parameters"
+	}
+
 	return UnknownKeyError{
 		apiError: apiError{
 			cause:      cause,
 			title:      "CF-BadQueryParameter",
-			detail:     fmt.Sprintf("The query parameter is invalid: Valid parameters are: %s", quotedCommaSeparatedList(validKeys)),
+			detail:     detail,
 			code:       10005,
 			httpStatus: http.StatusBadRequest,
 		},
@@ -370,12 +375,74 @@ func NewRollingDeployNotSupportedError(runnerName string) RollingDeployNotSuppor
 	}
 }
 
+type ServiceUnavailableError struct {
+	apiError
+	retryAfterSeconds int
+}
+
+func NewServiceUnavailableError(cause error, retryAfterSeconds int) ServiceUnavailableError {
+	return ServiceUnavailableError{
+		apiError: apiError{
+			cause:      cause,
+			title:      "CF-ServiceUnavailable",
+			detail:     "The Kubernetes API is throttling requests. Please retry later.",
+			code:       10015,
+			httpStatus: http.StatusServiceUnavailable,
+		},
+		retryAfterSeconds: retryAfterSeconds,
+	}
+}
+
+// RetryAfterSeconds is the delay after which the client may retry the
+// request, sent in the Retry-After header
+func (e ServiceUnavailableError) RetryAfterSeconds() int {
+	return e.retryAfterSeconds
+}
+
+// defaultRetryAfterSeconds is the delay suggested to clients when the
+// Kubernetes API throttles a request without suggesting one itself
+const defaultRetryAfterSeconds = 1
+
+// clientRateLimiterErrorMessage prefixes the errors returned by client-go when
+// its client-side rate limiter does not admit a request in time
+const clientRateLimiterErrorMessage = "client rate limiter Wait returned an error"
+
+// IsThrottled returns true when the Kubernetes API, or the client-side rate
+// limiter of client-go, throttled the request
+func IsThrottled(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return k8serrors.IsTooManyRequests(err) ||
+		k8serrors.IsServerTimeout(err) ||
+		strings.Contains(err.Error(), clientRateLimiterErrorMessage)
+}
+
+// ThrottledAsServiceUnavailable converts throttling errors into a service
+// unavailable error carrying the delay suggested by the Kubernetes API. Any
+// other error is returned as is.
+func ThrottledAsServiceUnavailable(err error) error {
+	if !IsThrottled(err) {
+		return err
+	}
+
+	retryAfterSeconds := defaultRetryAfterSeconds
+	if delay, ok := k8serrors.SuggestsClientDelay(err); ok && delay > 0 {
+		retryAfterSeconds = delay
+	}
+
+	return NewServiceUnavailableError(err, retryAfterSeconds)
+}
+
 func FromK8sError(err error, resourceType string) error {
 	if webhookValidationError, ok := validation.WebhookErrorToValidationError(err); ok {
 		return NewUnprocessableEntityError(err, webhookValidationError.GetMessage())
 	}
 
 	switch {
+	case IsThrottled(err):
+		return ThrottledAsServiceUnavailable(err)
 	case k8serrors.IsUnauthorized(err):
 		return NewInvalidAuthError(err)
 	case k8serrors.IsNotFound(err):
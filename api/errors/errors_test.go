@@ -62,6 +62,36 @@ var _ = Describe("FromK8sError", func() {
 		})
 	})
 
+	When("too many requests k8s error", func() {
+		BeforeEach(func() {
+			err = k8serrors.NewTooManyRequests("slow down", 7)
+		})
+
+		It("translates it to service unavailable api error with the suggested delay", func() {
+			Expect(actualErr).To(Equal(apierrors.NewServiceUnavailableError(err, 7)))
+		})
+	})
+
+	When("server timeout k8s error without a suggested delay", func() {
+		BeforeEach(func() {
+			err = k8serrors.NewServerTimeout(schema.GroupResource{}, "get", 0)
+		})
+
+		It("translates it to service unavailable api error with the default delay", func() {
+			Expect(actualErr).To(Equal(apierrors.NewServiceUnavailableError(err, 1)))
+		})
+	})
+
+	When("client rate limiter error", func() {
+		BeforeEach(func() {
+			err = errors.New("client rate limiter Wait returned an error: context deadline exceeded")
+		})
+
+		It("translates it to service unavailable api error", func() {
+			Expect(actualErr).To(Equal(apierrors.NewServiceUnavailableError(err, 1)))
+		})
+	})
+
 	When("unknown error", func() {
 		BeforeEach(func() {
 			err = errors.New("bar")
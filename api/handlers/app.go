@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"sort"
 	"strconv"
 	"time"
@@ -35,10 +36,14 @@ const (
 	AppStartPath                      = "/v3/apps/{guid}/actions/start"
 	AppStopPath                       = "/v3/apps/{guid}/actions/stop"
 	AppRestartPath                    = "/v3/apps/{guid}/actions/restart"
+	AppRestagePath                    = "/v3/apps/{guid}/actions/restage"
 	AppEnvVarsPath                    = "/v3/apps/{guid}/environment_variables"
+	AppStagingEnvVarsPath             = "/v3/apps/{guid}/staging_environment_variables"
 	AppEnvPath                        = "/v3/apps/{guid}/env"
 	AppFeaturePath                    = "/v3/apps/{guid}/features/{name}"
 	AppPackagesPath                   = "/v3/apps/{guid}/packages"
+	AppRevisionsPath                  = "/v3/apps/{guid}/revisions"
+	AppDeployedRevisionsPath          = "/v3/apps/{guid}/revisions/deployed"
 	AppSSHEnabledPath                 = "/v3/apps/{guid}/ssh_enabled"
 	AppInstanceRestartPath            = "/v3/apps/{guid}/processes/{processType}/instances/{instance}"
 	invalidDropletMsg                 = "Unable to assign current droplet. Ensure the droplet exists and belongs to this app."
@@ -53,6 +58,7 @@ type CFAppRepository interface {
 	GetApp(context.Context, authorization.Info, string) (repositories.AppRecord, error)
 	ListApps(context.Context, authorization.Info, repositories.ListAppsMessage) ([]repositories.AppRecord, error)
 	PatchAppEnvVars(context.Context, authorization.Info, repositories.PatchAppEnvVarsMessage) (repositories.AppEnvVarsRecord, error)
+	PatchAppStagingEnvVars(context.Context, authorization.Info, repositories.PatchAppEnvVarsMessage) (repositories.AppEnvVarsRecord, error)
 	CreateApp(context.Context, authorization.Info, repositories.CreateAppMessage) (repositories.AppRecord, error)
 	SetCurrentDroplet(context.Context, authorization.Info, repositories.SetCurrentDropletMessage) (repositories.CurrentDropletRecord, error)
 	SetAppDesiredState(context.Context, authorization.Info, repositories.SetAppDesiredStateMessage) (repositories.AppRecord, error)
@@ -76,6 +82,7 @@ type App struct {
 	domainRepo       CFDomainRepository
 	spaceRepo        CFSpaceRepository
 	packageRepo      CFPackageRepository
+	buildRepo        CFBuildRepository
 	requestValidator RequestValidator
 	podRepo          PodRepository
 }
@@ -90,6 +97,7 @@ func NewApp(
 	domainRepo CFDomainRepository,
 	spaceRepo CFSpaceRepository,
 	packageRepo CFPackageRepository,
+	buildRepo CFBuildRepository,
 	requestValidator RequestValidator,
 	podRepo PodRepository,
 ) *App {
@@ -103,6 +111,7 @@ func NewApp(
 		domainRepo:       domainRepo,
 		spaceRepo:        spaceRepo,
 		packageRepo:      packageRepo,
+		buildRepo:        buildRepo,
 		requestValidator: requestValidator,
 		podRepo:          podRepo,
 	}
@@ -251,6 +260,31 @@ func (h *App) setCurrentDroplet(r *http.Request) (*routing.Response, error) {
 	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForCurrentDroplet(currentDroplet, h.serverURL)), nil
 }
 
+func (h *App) getCurrentDropletRelationship(r *http.Request) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.app.get-current-droplet-relationship")
+	appGUID := routing.URLParam(r, "guid")
+
+	app, err := h.appRepo.GetApp(r.Context(), authInfo, appGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch app from Kubernetes", "AppGUID", appGUID)
+	}
+
+	if app.DropletGUID == "" {
+		return nil, apierrors.LogAndReturn(
+			logger,
+			apierrors.DropletForbiddenAsNotFound(apierrors.NewNotFoundError(nil, repositories.DropletResourceType)),
+			"App does not have a current droplet assigned",
+			"appGUID", app.GUID,
+		)
+	}
+
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForCurrentDroplet(repositories.CurrentDropletRecord{
+		AppGUID:     app.GUID,
+		DropletGUID: app.DropletGUID,
+	}, h.serverURL)), nil
+}
+
 func (h *App) getCurrentDroplet(r *http.Request) (*routing.Response, error) {
 	authInfo, _ := authorization.InfoFromContext(r.Context())
 	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.app.get-current-droplet")
@@ -464,6 +498,54 @@ func (h *App) restart(r *http.Request) (*routing.Response, error) {
 	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForApp(app, h.serverURL)), nil
 }
 
+// restage stages the newest package of the app with the current lifecycle of
+// the app. The droplet of the build becomes the current droplet of the app
+// once staging succeeds
+func (h *App) restage(r *http.Request) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.app.restage")
+	appGUID := routing.URLParam(r, "guid")
+
+	app, err := h.appRepo.GetApp(r.Context(), authInfo, appGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch app from Kubernetes", "AppGUID", appGUID)
+	}
+
+	packages, err := h.packageRepo.ListPackages(r.Context(), authInfo, repositories.ListPackagesMessage{
+		AppGUIDs: []string{appGUID},
+		States:   []string{repositories.PackageStateReady},
+	})
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Failed to fetch app Package(s) from Kubernetes", "AppGUID", appGUID)
+	}
+
+	if len(packages) == 0 {
+		return nil, apierrors.LogAndReturn(
+			logger,
+			apierrors.NewUnprocessableEntityError(nil, "Unable to restage the app as it has no ready package. Upload a package first."),
+			"App has no ready package", "AppGUID", appGUID,
+		)
+	}
+
+	newestPackage := slices.MaxFunc(packages, func(a, b repositories.PackageRecord) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	buildCreateMessage := (&payloads.BuildCreate{
+		Package: &payloads.RelationshipData{GUID: newestPackage.GUID},
+	}).ToMessage(app)
+	buildCreateMessage.Annotations = map[string]string{
+		korifiv1alpha1.SetCurrentDropletAnnotationKey: "true",
+	}
+
+	build, err := h.buildRepo.CreateBuild(r.Context(), authInfo, buildCreateMessage)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Failed to create build", "AppGUID", appGUID, "PackageGUID", newestPackage.GUID)
+	}
+
+	return routing.NewResponse(http.StatusCreated).WithBody(presenter.ForBuild(build, h.serverURL)), nil
+}
+
 func (h *App) delete(r *http.Request) (*routing.Response, error) {
 	authInfo, _ := authorization.InfoFromContext(r.Context())
 	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.app.delete")
@@ -537,6 +619,29 @@ func (h *App) updateEnvVars(r *http.Request) (*routing.Response, error) {
 	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForAppEnvVars(envVarsRecord, h.serverURL)), nil
 }
 
+func (h *App) updateStagingEnvVars(r *http.Request) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.app.update-staging-env-vars")
+	appGUID := routing.URLParam(r, "guid")
+
+	var payload payloads.AppPatchEnvVars
+	if err := h.requestValidator.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to decode payload")
+	}
+
+	app, err := h.appRepo.GetApp(r.Context(), authInfo, appGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch app from Kubernetes", "AppGUID", appGUID)
+	}
+
+	envVarsRecord, err := h.appRepo.PatchAppStagingEnvVars(r.Context(), authInfo, payload.ToMessage(appGUID, app.SpaceGUID))
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Error updating app staging environment variables")
+	}
+
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForAppStagingEnvVars(envVarsRecord, h.serverURL)), nil
+}
+
 func (h *App) getEnvironment(r *http.Request) (*routing.Response, error) {
 	authInfo, _ := authorization.InfoFromContext(r.Context())
 	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.app.get-environment")
@@ -635,6 +740,44 @@ func (h *App) getPackages(r *http.Request) (*routing.Response, error) {
 	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForList(presenter.ForPackage, packageList, h.serverURL, *r.URL)), nil
 }
 
+func (h *App) getRevisions(r *http.Request) (*routing.Response, error) {
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.app.get-revisions")
+
+	payload := new(payloads.AppRevisionList)
+	if err := h.requestValidator.DecodeAndValidateURLValues(r, payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Unable to decode request query parameters")
+	}
+
+	return h.listRevisions(r, logger, payload.ToMessage())
+}
+
+func (h *App) getDeployedRevisions(r *http.Request) (*routing.Response, error) {
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.app.get-deployed-revisions")
+
+	return h.listRevisions(r, logger, repositories.ListRevisionsMessage{Deployed: true})
+}
+
+func (h *App) listRevisions(r *http.Request, logger logr.Logger, message repositories.ListRevisionsMessage) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	appGUID := routing.URLParam(r, "guid")
+
+	app, err := h.appRepo.GetApp(r.Context(), authInfo, appGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch app from Kubernetes", "AppGUID", appGUID)
+	}
+
+	droplets, err := h.dropletRepo.ListDroplets(r.Context(), authInfo, repositories.ListDropletsMessage{
+		AppGUIDs: []string{appGUID},
+	})
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Failed to fetch app droplets from Kubernetes", "AppGUID", appGUID)
+	}
+
+	revisions := repositories.AppRevisions(droplets, app.DropletGUID, message)
+
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForList(presenter.ForRevision, revisions, h.serverURL, *r.URL)), nil
+}
+
 //nolint:dupl
 func (h *App) update(r *http.Request) (*routing.Response, error) {
 	authInfo, _ := authorization.InfoFromContext(r.Context())
@@ -678,7 +821,7 @@ func (h *App) getAppFeature(r *http.Request) (*routing.Response, error) {
 		return routing.NewResponse(http.StatusOK).WithBody(map[string]any{
 			"name":        "revisions",
 			"description": "Enable versioning of an application",
-			"enabled":     false,
+			"enabled":     true,
 		}), nil
 	default:
 		return nil, apierrors.NewNotFoundError(nil, "Feature")
@@ -741,10 +884,12 @@ func (h *App) AuthenticatedRoutes() []routing.Route {
 		{Method: "GET", Pattern: AppsPath, Handler: h.list},
 		{Method: "POST", Pattern: AppsPath, Handler: h.create},
 		{Method: "PATCH", Pattern: AppCurrentDropletRelationshipPath, Handler: h.setCurrentDroplet},
+		{Method: "GET", Pattern: AppCurrentDropletRelationshipPath, Handler: h.getCurrentDropletRelationship},
 		{Method: "GET", Pattern: AppCurrentDropletPath, Handler: h.getCurrentDroplet},
 		{Method: "POST", Pattern: AppStartPath, Handler: h.start},
 		{Method: "POST", Pattern: AppStopPath, Handler: h.stop},
 		{Method: "POST", Pattern: AppRestartPath, Handler: h.restart},
+		{Method: "POST", Pattern: AppRestagePath, Handler: h.restage},
 		{Method: "POST", Pattern: AppProcessScalePath, Handler: h.scaleProcess},
 		{Method: "GET", Pattern: AppProcessesPath, Handler: h.getProcesses},
 		{Method: "GET", Pattern: AppProcessByTypePath, Handler: h.getProcess},
@@ -752,8 +897,11 @@ func (h *App) AuthenticatedRoutes() []routing.Route {
 		{Method: "GET", Pattern: AppRoutesPath, Handler: h.getRoutes},
 		{Method: "DELETE", Pattern: AppPath, Handler: h.delete},
 		{Method: "PATCH", Pattern: AppEnvVarsPath, Handler: h.updateEnvVars},
+		{Method: "PATCH", Pattern: AppStagingEnvVarsPath, Handler: h.updateStagingEnvVars},
 		{Method: "GET", Pattern: AppEnvPath, Handler: h.getEnvironment},
 		{Method: "GET", Pattern: AppPackagesPath, Handler: h.getPackages},
+		{Method: "GET", Pattern: AppRevisionsPath, Handler: h.getRevisions},
+		{Method: "GET", Pattern: AppDeployedRevisionsPath, Handler: h.getDeployedRevisions},
 		{Method: "GET", Pattern: AppFeaturePath, Handler: h.getAppFeature},
 		{Method: "PATCH", Pattern: AppPath, Handler: h.update},
 		{Method: "GET", Pattern: AppSSHEnabledPath, Handler: h.getSSHEnabled},
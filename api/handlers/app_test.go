@@ -14,6 +14,7 @@ import (
 	"code.cloudfoundry.org/korifi/api/handlers/fake"
 	"code.cloudfoundry.org/korifi/api/payloads"
 	"code.cloudfoundry.org/korifi/api/repositories"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	. "code.cloudfoundry.org/korifi/tests/matchers"
 	"code.cloudfoundry.org/korifi/tools"
 
@@ -39,6 +40,7 @@ var _ = Describe("App", func() {
 		domainRepo       *fake.CFDomainRepository
 		spaceRepo        *fake.CFSpaceRepository
 		packageRepo      *fake.CFPackageRepository
+		buildRepo        *fake.CFBuildRepository
 		podRepo          *fake.PodRepository
 		requestValidator *fake.RequestValidator
 		req              *http.Request
@@ -55,6 +57,7 @@ var _ = Describe("App", func() {
 		domainRepo = new(fake.CFDomainRepository)
 		spaceRepo = new(fake.CFSpaceRepository)
 		packageRepo = new(fake.CFPackageRepository)
+		buildRepo = new(fake.CFBuildRepository)
 		requestValidator = new(fake.RequestValidator)
 		podRepo = new(fake.PodRepository)
 
@@ -68,6 +71,7 @@ var _ = Describe("App", func() {
 			domainRepo,
 			spaceRepo,
 			packageRepo,
+			buildRepo,
 			requestValidator,
 			podRepo,
 		)
@@ -1276,6 +1280,60 @@ var _ = Describe("App", func() {
 		})
 	})
 
+	Describe("GET /v3/apps/:guid/relationships/current_droplet", func() {
+		BeforeEach(func() {
+			req = createHttpRequest("GET", "/v3/apps/"+appGUID+"/relationships/current_droplet", nil)
+		})
+
+		It("returns the current droplet relationship", func() {
+			Expect(appRepo.GetAppCallCount()).To(Equal(1))
+			_, actualAuthInfo, actualAppGUID := appRepo.GetAppArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(actualAppGUID).To(Equal(appGUID))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.data.guid", dropletGUID),
+				MatchJSONPath("$.links.self.href", "https://api.example.org/v3/apps/"+appGUID+"/relationships/current_droplet"),
+				MatchJSONPath("$.links.related.href", "https://api.example.org/v3/apps/"+appGUID+"/droplets/current"),
+			)))
+		})
+
+		When("the app is not accessible", func() {
+			BeforeEach(func() {
+				appRepo.GetAppReturns(repositories.AppRecord{}, apierrors.NewForbiddenError(nil, repositories.AppResourceType))
+			})
+
+			It("returns an error", func() {
+				expectNotFoundError("App")
+			})
+		})
+
+		When("getting the app fails", func() {
+			BeforeEach(func() {
+				appRepo.GetAppReturns(repositories.AppRecord{}, errors.New("get-app"))
+			})
+
+			It("returns an error", func() {
+				expectUnknownError()
+			})
+		})
+
+		When("the app doesn't have a current droplet assigned", func() {
+			BeforeEach(func() {
+				appRepo.GetAppReturns(repositories.AppRecord{
+					GUID:      appGUID,
+					SpaceGUID: spaceGUID,
+				}, nil)
+			})
+
+			It("returns a NotFound error", func() {
+				expectErrorResponse(http.StatusNotFound, "CF-ResourceNotFound", "Droplet not found", 10010)
+			})
+		})
+	})
+
 	Describe("GET /v3/apps/:guid/droplets/current", func() {
 		BeforeEach(func() {
 			dropletRepo.GetDropletReturns(repositories.DropletRecord{
@@ -1361,6 +1419,89 @@ var _ = Describe("App", func() {
 		})
 	})
 
+	Describe("POST /v3/apps/:guid/actions/restage", func() {
+		BeforeEach(func() {
+			packageRepo.ListPackagesReturns([]repositories.PackageRecord{
+				{GUID: "old-package-guid", CreatedAt: time.UnixMilli(1000)},
+				{GUID: "new-package-guid", CreatedAt: time.UnixMilli(2000)},
+			}, nil)
+			buildRepo.CreateBuildReturns(repositories.BuildRecord{GUID: "build-guid", State: "STAGING"}, nil)
+
+			req = createHttpRequest("POST", "/v3/apps/"+appGUID+"/actions/restage", nil)
+		})
+
+		It("stages the newest ready package of the app", func() {
+			Expect(appRepo.GetAppCallCount()).To(Equal(1))
+			_, actualAuthInfo, actualAppGUID := appRepo.GetAppArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(actualAppGUID).To(Equal(appGUID))
+
+			Expect(packageRepo.ListPackagesCallCount()).To(Equal(1))
+			_, _, listMessage := packageRepo.ListPackagesArgsForCall(0)
+			Expect(listMessage).To(Equal(repositories.ListPackagesMessage{
+				AppGUIDs: []string{appGUID},
+				States:   []string{repositories.PackageStateReady},
+			}))
+
+			Expect(buildRepo.CreateBuildCallCount()).To(Equal(1))
+			_, actualAuthInfo, createMessage := buildRepo.CreateBuildArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(createMessage.AppGUID).To(Equal(appGUID))
+			Expect(createMessage.SpaceGUID).To(Equal(spaceGUID))
+			Expect(createMessage.PackageGUID).To(Equal("new-package-guid"))
+			Expect(createMessage.Lifecycle).To(Equal(appRecord.Lifecycle))
+			Expect(createMessage.Annotations).To(HaveKeyWithValue(korifiv1alpha1.SetCurrentDropletAnnotationKey, "true"))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusCreated))
+			Expect(rr).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.guid", "build-guid"),
+				MatchJSONPath("$.state", "STAGING"),
+			)))
+		})
+
+		When("the app is not accessible", func() {
+			BeforeEach(func() {
+				appRepo.GetAppReturns(repositories.AppRecord{}, apierrors.NewForbiddenError(nil, repositories.AppResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError("App")
+			})
+		})
+
+		When("the app has no ready package", func() {
+			BeforeEach(func() {
+				packageRepo.ListPackagesReturns([]repositories.PackageRecord{}, nil)
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("Unable to restage the app as it has no ready package. Upload a package first.")
+				Expect(buildRepo.CreateBuildCallCount()).To(BeZero())
+			})
+		})
+
+		When("listing the packages fails", func() {
+			BeforeEach(func() {
+				packageRepo.ListPackagesReturns(nil, errors.New("list-packages-err"))
+			})
+
+			It("returns an error", func() {
+				expectUnknownError()
+			})
+		})
+
+		When("creating the build fails", func() {
+			BeforeEach(func() {
+				buildRepo.CreateBuildReturns(repositories.BuildRecord{}, errors.New("create-build-err"))
+			})
+
+			It("returns an error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+
 	Describe("GET /v3/apps/:guid/actions/restart", func() {
 		BeforeEach(func() {
 			updatedAppRecord := appRecord
@@ -1635,6 +1776,84 @@ var _ = Describe("App", func() {
 		})
 	})
 
+	Describe("PATCH /v3/apps/:guid/staging_environment_variables", func() {
+		var payload *payloads.AppPatchEnvVars
+
+		BeforeEach(func() {
+			appRepo.PatchAppStagingEnvVarsReturns(repositories.AppEnvVarsRecord{
+				Name:      appGUID + "-staging-env",
+				AppGUID:   appGUID,
+				SpaceGUID: spaceGUID,
+				EnvironmentVariables: map[string]string{
+					"KEY0": "VAL0",
+				},
+			}, nil)
+			payload = &payloads.AppPatchEnvVars{
+				Var: map[string]interface{}{
+					"KEY0": "VAL0",
+				},
+			}
+			requestValidator.DecodeAndValidateJSONPayloadStub = decodeAndValidatePayloadStub(payload)
+
+			req = createHttpRequest("PATCH", "/v3/apps/"+appGUID+"/staging_environment_variables", strings.NewReader("the-json-body"))
+		})
+
+		It("updates the app staging environment", func() {
+			Expect(appRepo.GetAppCallCount()).To(Equal(1))
+			_, _, actualAppGUID := appRepo.GetAppArgsForCall(0)
+			Expect(actualAppGUID).To(Equal(appGUID))
+
+			Expect(appRepo.PatchAppStagingEnvVarsCallCount()).To(Equal(1))
+			_, _, message := appRepo.PatchAppStagingEnvVarsArgsForCall(0)
+			Expect(message.AppGUID).To(Equal(appGUID))
+			Expect(message.SpaceGUID).To(Equal(spaceGUID))
+			Expect(message.EnvironmentVariables).To(Equal(map[string]*string{
+				"KEY0": tools.PtrTo("VAL0"),
+			}))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.var.KEY0", "VAL0"),
+				MatchJSONPath("$.links.self.href", "https://api.example.org/v3/apps/"+appGUID+"/staging_environment_variables"),
+			)))
+		})
+
+		It("does not update the app runtime environment", func() {
+			Expect(appRepo.PatchAppEnvVarsCallCount()).To(BeZero())
+		})
+
+		When("the request body is invalid", func() {
+			BeforeEach(func() {
+				requestValidator.DecodeAndValidateJSONPayloadReturns(apierrors.NewUnprocessableEntityError(errors.New("validation-err"), "validation error"))
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("validation error")
+			})
+		})
+
+		When("the app cannot be found", func() {
+			BeforeEach(func() {
+				appRepo.GetAppReturns(repositories.AppRecord{}, apierrors.NewForbiddenError(nil, repositories.AppResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError(repositories.AppResourceType)
+			})
+		})
+
+		When("there is some error updating the app staging environment variables", func() {
+			BeforeEach(func() {
+				appRepo.PatchAppStagingEnvVarsReturns(repositories.AppEnvVarsRecord{}, errors.New("unknown!"))
+			})
+
+			It("returns an error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+
 	Describe("GET /v3/apps/:guid/packages", func() {
 		var (
 			package1Record repositories.PackageRecord
@@ -1714,6 +1933,139 @@ var _ = Describe("App", func() {
 		})
 	})
 
+	Describe("GET /v3/apps/:guid/revisions", func() {
+		BeforeEach(func() {
+			dropletRepo.ListDropletsReturns([]repositories.DropletRecord{
+				{
+					GUID:            "test-droplet-guid",
+					AppGUID:         appGUID,
+					CreatedAt:       time.UnixMilli(1000),
+					RevisionVersion: 5,
+				},
+				{
+					GUID:            "old-droplet-guid",
+					AppGUID:         appGUID,
+					CreatedAt:       time.UnixMilli(2000),
+					RevisionVersion: 3,
+				},
+				{
+					GUID:      "unnumbered-droplet-guid",
+					AppGUID:   appGUID,
+					CreatedAt: time.UnixMilli(3000),
+				},
+			}, nil)
+
+			requestValidator.DecodeAndValidateURLValuesStub = decodeAndValidateURLValuesStub(&payloads.AppRevisionList{})
+
+			req = createHttpRequest("GET", "/v3/apps/"+appGUID+"/revisions", nil)
+		})
+
+		It("lists the app droplets as its revisions", func() {
+			Expect(dropletRepo.ListDropletsCallCount()).To(Equal(1))
+			_, _, message := dropletRepo.ListDropletsArgsForCall(0)
+			Expect(message.AppGUIDs).To(ConsistOf(appGUID))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.pagination.first.href", "https://api.example.org/v3/apps/test-app-guid/revisions"),
+				MatchJSONPath("$.resources", HaveLen(2)),
+				MatchJSONPath("$.resources[0].guid", "old-droplet-guid"),
+				MatchJSONPath("$.resources[0].version", BeEquivalentTo(3)),
+				MatchJSONPath("$.resources[1].guid", "test-droplet-guid"),
+				MatchJSONPath("$.resources[1].version", BeEquivalentTo(5)),
+				MatchJSONPath("$.resources[1].droplet.guid", "test-droplet-guid"),
+			)))
+		})
+
+		When("filtering by version", func() {
+			BeforeEach(func() {
+				requestValidator.DecodeAndValidateURLValuesStub = decodeAndValidateURLValuesStub(&payloads.AppRevisionList{
+					Versions: "3",
+				})
+			})
+
+			It("returns the matching revisions", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+				Expect(rr).To(HaveHTTPBody(SatisfyAll(
+					MatchJSONPath("$.resources", HaveLen(1)),
+					MatchJSONPath("$.resources[0].guid", "old-droplet-guid"),
+				)))
+			})
+		})
+
+		When("the request is invalid", func() {
+			BeforeEach(func() {
+				requestValidator.DecodeAndValidateURLValuesReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				expectUnknownError()
+			})
+		})
+
+		When("the app cannot be accessed", func() {
+			BeforeEach(func() {
+				appRepo.GetAppReturns(repositories.AppRecord{}, apierrors.NewForbiddenError(nil, repositories.AppResourceType))
+			})
+
+			It("returns an error", func() {
+				expectNotFoundError("App")
+			})
+		})
+
+		When("there is some error fetching the app's droplets", func() {
+			BeforeEach(func() {
+				dropletRepo.ListDropletsReturns(nil, errors.New("unknown!"))
+			})
+
+			It("returns an error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+
+	Describe("GET /v3/apps/:guid/revisions/deployed", func() {
+		BeforeEach(func() {
+			dropletRepo.ListDropletsReturns([]repositories.DropletRecord{
+				{
+					GUID:            "old-droplet-guid",
+					AppGUID:         appGUID,
+					CreatedAt:       time.UnixMilli(1000),
+					RevisionVersion: 1,
+				},
+				{
+					GUID:            "test-droplet-guid",
+					AppGUID:         appGUID,
+					CreatedAt:       time.UnixMilli(2000),
+					RevisionVersion: 2,
+				},
+			}, nil)
+
+			req = createHttpRequest("GET", "/v3/apps/"+appGUID+"/revisions/deployed", nil)
+		})
+
+		It("returns the revision of the current droplet", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.resources", HaveLen(1)),
+				MatchJSONPath("$.resources[0].guid", "test-droplet-guid"),
+				MatchJSONPath("$.resources[0].version", BeEquivalentTo(2)),
+			)))
+		})
+
+		When("the app cannot be accessed", func() {
+			BeforeEach(func() {
+				appRepo.GetAppReturns(repositories.AppRecord{}, apierrors.NewForbiddenError(nil, repositories.AppResourceType))
+			})
+
+			It("returns an error", func() {
+				expectNotFoundError("App")
+			})
+		})
+	})
+
 	Describe("GET /v3/apps/GUID/ssh_enabled", func() {
 		BeforeEach(func() {
 			req = createHttpRequest("GET", "/v3/apps/"+appGUID+"/ssh_enabled", nil)
@@ -1750,13 +2102,13 @@ var _ = Describe("App", func() {
 				req = createHttpRequest("GET", "/v3/apps/"+appGUID+"/features/revisions", nil)
 			})
 
-			It("returns revisions enabled false", func() {
+			It("returns revisions enabled true", func() {
 				Expect(rr).To(HaveHTTPStatus(http.StatusOK))
 				Expect(rr).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
 				Expect(rr).To(HaveHTTPBody(SatisfyAll(
 					MatchJSONPath("$.name", Equal("revisions")),
 					MatchJSONPath("$.description", Equal("Enable versioning of an application")),
-					MatchJSONPath("$.enabled", BeFalse()),
+					MatchJSONPath("$.enabled", BeTrue()),
 				)))
 			})
 		})
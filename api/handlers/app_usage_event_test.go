@@ -0,0 +1,162 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	. "code.cloudfoundry.org/korifi/tests/matchers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AppUsageEvent", func() {
+	var (
+		appUsageEventRepo *fake.CFAppUsageEventRepository
+		requestValidator  *fake.RequestValidator
+		req               *http.Request
+	)
+
+	BeforeEach(func() {
+		appUsageEventRepo = new(fake.CFAppUsageEventRepository)
+		requestValidator = new(fake.RequestValidator)
+
+		apiHandler := handlers.NewAppUsageEvent(*serverURL, appUsageEventRepo, requestValidator)
+		routerBuilder.LoadRoutes(apiHandler)
+	})
+
+	JustBeforeEach(func() {
+		routerBuilder.Build().ServeHTTP(rr, req)
+	})
+
+	Describe("GET /v3/app_usage_events", func() {
+		BeforeEach(func() {
+			appUsageEventRepo.ListAppUsageEventsReturns([]repositories.AppUsageEventRecord{
+				{GUID: "app-usage-event-1", State: "STARTED"},
+			}, nil)
+
+			requestValidator.DecodeAndValidateURLValuesStub = decodeAndValidateURLValuesStub(&payloads.AppUsageEventList{
+				AfterGUID: "app-usage-event-0",
+			})
+
+			var err error
+			req, err = http.NewRequestWithContext(ctx, http.MethodGet, "/v3/app_usage_events?after_guid=app-usage-event-0", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("lists the app usage events", func() {
+			Expect(appUsageEventRepo.ListAppUsageEventsCallCount()).To(Equal(1))
+			_, actualAuthInfo, listMessage := appUsageEventRepo.ListAppUsageEventsArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(listMessage).To(Equal(repositories.ListAppUsageEventsMessage{
+				AfterGUID: "app-usage-event-0",
+			}))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.pagination.total_results", BeEquivalentTo(1)),
+				MatchJSONPath("$.resources[0].guid", "app-usage-event-1"),
+				MatchJSONPath("$.resources[0].state.current", "STARTED"),
+			)))
+		})
+
+		When("the request is invalid", func() {
+			BeforeEach(func() {
+				requestValidator.DecodeAndValidateURLValuesReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				expectUnknownError()
+			})
+		})
+
+		When("listing the app usage events fails", func() {
+			BeforeEach(func() {
+				appUsageEventRepo.ListAppUsageEventsReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an Internal Server Error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+
+	Describe("GET /v3/app_usage_events/{guid}", func() {
+		BeforeEach(func() {
+			appUsageEventRepo.GetAppUsageEventReturns(repositories.AppUsageEventRecord{
+				GUID:  "app-usage-event-guid",
+				State: "STOPPED",
+			}, nil)
+
+			var err error
+			req, err = http.NewRequestWithContext(ctx, http.MethodGet, "/v3/app_usage_events/app-usage-event-guid", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the app usage event", func() {
+			Expect(appUsageEventRepo.GetAppUsageEventCallCount()).To(Equal(1))
+			_, actualAuthInfo, actualGUID := appUsageEventRepo.GetAppUsageEventArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(actualGUID).To(Equal("app-usage-event-guid"))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.guid", "app-usage-event-guid"),
+				MatchJSONPath("$.state.current", "STOPPED"),
+				MatchJSONPath("$.links.self.href", "https://api.example.org/v3/app_usage_events/app-usage-event-guid"),
+			)))
+		})
+
+		When("the app usage event does not exist", func() {
+			BeforeEach(func() {
+				appUsageEventRepo.GetAppUsageEventReturns(repositories.AppUsageEventRecord{}, apierrors.NewNotFoundError(nil, repositories.AppUsageEventResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError(repositories.AppUsageEventResourceType)
+			})
+		})
+
+		When("the user is not allowed to read app usage events", func() {
+			BeforeEach(func() {
+				appUsageEventRepo.GetAppUsageEventReturns(repositories.AppUsageEventRecord{}, apierrors.NewForbiddenError(nil, repositories.AppUsageEventResourceType))
+			})
+
+			It("returns a not authorized error", func() {
+				expectNotAuthorizedError()
+			})
+		})
+	})
+
+	Describe("POST /v3/app_usage_events/actions/destructively_purge_all_and_reseed", func() {
+		BeforeEach(func() {
+			var err error
+			req, err = http.NewRequestWithContext(ctx, http.MethodPost, "/v3/app_usage_events/actions/destructively_purge_all_and_reseed", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("purges and reseeds the app usage events", func() {
+			Expect(appUsageEventRepo.PurgeAndReseedAppUsageEventsCallCount()).To(Equal(1))
+			_, actualAuthInfo := appUsageEventRepo.PurgeAndReseedAppUsageEventsArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(MatchJSON("{}")))
+		})
+
+		When("purging fails", func() {
+			BeforeEach(func() {
+				appUsageEventRepo.PurgeAndReseedAppUsageEventsReturns(errors.New("boom"))
+			})
+
+			It("returns an Internal Server Error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+})
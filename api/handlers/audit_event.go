@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/api/routing"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	AuditEventsPath = "/v3/audit_events"
+)
+
+//counterfeiter:generate -o fake -fake-name CFAuditEventRepository . CFAuditEventRepository
+type CFAuditEventRepository interface {
+	ListAuditEvents(context.Context, authorization.Info, repositories.ListAuditEventsMessage) ([]repositories.AuditEventRecord, error)
+}
+
+// AuditEvent serves the audit events korifi records, which are currently the
+// crashes of app instances
+type AuditEvent struct {
+	serverURL        url.URL
+	auditEventRepo   CFAuditEventRepository
+	requestValidator RequestValidator
+}
+
+func NewAuditEvent(
+	serverURL url.URL,
+	auditEventRepo CFAuditEventRepository,
+	requestValidator RequestValidator,
+) *AuditEvent {
+	return &AuditEvent{
+		serverURL:        serverURL,
+		auditEventRepo:   auditEventRepo,
+		requestValidator: requestValidator,
+	}
+}
+
+func (h *AuditEvent) list(r *http.Request) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.audit-event.list")
+
+	payload := new(payloads.AuditEventList)
+	if err := h.requestValidator.DecodeAndValidateURLValues(r, payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "unable to decode request query parameters")
+	}
+
+	auditEvents, err := h.auditEventRepo.ListAuditEvents(r.Context(), authInfo, payload.ToMessage())
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to list audit events")
+	}
+
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForList(presenter.ForAuditEvent, auditEvents, h.serverURL, *r.URL)), nil
+}
+
+func (h *AuditEvent) UnauthenticatedRoutes() []routing.Route {
+	return nil
+}
+
+func (h *AuditEvent) AuthenticatedRoutes() []routing.Route {
+	return []routing.Route{
+		{Method: "GET", Pattern: AuditEventsPath, Handler: h.list},
+	}
+}
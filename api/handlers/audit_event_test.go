@@ -0,0 +1,85 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	. "code.cloudfoundry.org/korifi/tests/matchers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AuditEvent", func() {
+	var (
+		auditEventRepo   *fake.CFAuditEventRepository
+		requestValidator *fake.RequestValidator
+	)
+
+	BeforeEach(func() {
+		auditEventRepo = new(fake.CFAuditEventRepository)
+		auditEventRepo.ListAuditEventsReturns([]repositories.AuditEventRecord{
+			{GUID: "audit-event-1", Type: "app.crash"},
+		}, nil)
+
+		requestValidator = new(fake.RequestValidator)
+		requestValidator.DecodeAndValidateURLValuesStub = decodeAndValidateURLValuesStub(&payloads.AuditEventList{
+			Types:       "app.crash",
+			TargetGUIDs: "the-app-guid",
+			OrderBy:     "-created_at",
+		})
+
+		apiHandler := handlers.NewAuditEvent(*serverURL, auditEventRepo, requestValidator)
+		routerBuilder.LoadRoutes(apiHandler)
+	})
+
+	JustBeforeEach(func() {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/v3/audit_events?types=app.crash&target_guids=the-app-guid&order_by=-created_at", nil)
+		Expect(err).NotTo(HaveOccurred())
+		routerBuilder.Build().ServeHTTP(rr, req)
+	})
+
+	Describe("GET /v3/audit_events", func() {
+		It("lists the audit events", func() {
+			Expect(auditEventRepo.ListAuditEventsCallCount()).To(Equal(1))
+			_, actualAuthInfo, listMessage := auditEventRepo.ListAuditEventsArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(listMessage).To(Equal(repositories.ListAuditEventsMessage{
+				Types:       []string{"app.crash"},
+				TargetGUIDs: []string{"the-app-guid"},
+				OrderBy:     "-created_at",
+			}))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.pagination.total_results", BeEquivalentTo(1)),
+				MatchJSONPath("$.resources[0].guid", "audit-event-1"),
+				MatchJSONPath("$.resources[0].type", "app.crash"),
+			)))
+		})
+
+		When("the request is invalid", func() {
+			BeforeEach(func() {
+				requestValidator.DecodeAndValidateURLValuesReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				expectUnknownError()
+			})
+		})
+
+		When("listing the audit events fails", func() {
+			BeforeEach(func() {
+				auditEventRepo.ListAuditEventsReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an Internal Server Error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+})
@@ -26,6 +26,7 @@ type CFBuildRepository interface {
 	GetBuild(context.Context, authorization.Info, string) (repositories.BuildRecord, error)
 	GetLatestBuildByAppGUID(context.Context, authorization.Info, string, string) (repositories.BuildRecord, error)
 	CreateBuild(context.Context, authorization.Info, repositories.CreateBuildMessage) (repositories.BuildRecord, error)
+	CancelBuild(context.Context, authorization.Info, string) (repositories.BuildRecord, error)
 }
 
 type Build struct {
@@ -110,8 +111,37 @@ func (h *Build) create(r *http.Request) (*routing.Response, error) {
 	return routing.NewResponse(http.StatusCreated).WithBody(presenter.ForBuild(record, h.serverURL)), nil
 }
 
-func (h *Build) update(r *http.Request) (*routing.Response, error) { //nolint:dupl
-	return nil, apierrors.NewUnprocessableEntityError(errors.New("update build failed"), "Labels and annotations are not supported for builds.")
+// update only supports failing a build that is still queued or staging,
+// which is how clients cancel builds (e.g. when a push is interrupted)
+func (h *Build) update(r *http.Request) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.build.update")
+	buildGUID := routing.URLParam(r, "guid")
+
+	var payload payloads.BuildUpdate
+	if err := h.requestValidator.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to decode payload")
+	}
+
+	build, err := h.buildRepo.GetBuild(r.Context(), authInfo, buildGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "failed to fetch "+repositories.BuildResourceType, "guid", buildGUID)
+	}
+
+	if build.State != repositories.BuildStateQueued && build.State != repositories.BuildStateStaging {
+		return nil, apierrors.LogAndReturn(
+			logger,
+			apierrors.NewUnprocessableEntityError(errors.New("build is not staging"), "Attempted to update a build that is not staging."),
+			"failed to cancel build", "guid", buildGUID, "state", build.State,
+		)
+	}
+
+	build, err = h.buildRepo.CancelBuild(r.Context(), authInfo, buildGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to cancel build", "guid", buildGUID)
+	}
+
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForBuild(build, h.serverURL)), nil
 }
 
 func (h *Build) UnauthenticatedRoutes() []routing.Route {
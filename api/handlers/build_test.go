@@ -1,7 +1,6 @@
 package handlers_test
 
 import (
-	"context"
 	"errors"
 	"net/http"
 	"strings"
@@ -199,7 +198,7 @@ var _ = Describe("Build", func() {
 			Expect(actualCreate.SpaceGUID).To(Equal(spaceGUID))
 			Expect(actualCreate.AppGUID).To(Equal(appGUID))
 			Expect(actualCreate.PackageGUID).To(Equal(packageGUID))
-			Expect(actualCreate.StagingMemoryMB).To(Equal(expectedStagingMem))
+			Expect(actualCreate.StagingMemoryMB).To(BeZero())
 			Expect(actualCreate.Lifecycle.Type).To(Equal(expectedLifecycleType))
 			Expect(actualCreate.Lifecycle.Data.Buildpacks).To(Equal(expectedLifecycleBuildpacks))
 			Expect(actualCreate.Lifecycle.Data.Stack).To(Equal(expectedLifecycleStack))
@@ -302,13 +301,104 @@ var _ = Describe("Build", func() {
 
 	Describe("the PATCH /v3/builds endpoint", func() {
 		BeforeEach(func() {
+			requestValidator.DecodeAndValidateJSONPayloadStub = decodeAndValidatePayloadStub(&payloads.BuildUpdate{
+				State: "FAILED",
+			})
+
+			buildRepo.GetBuildReturns(repositories.BuildRecord{
+				GUID:  "build-guid",
+				State: "STAGING",
+			}, nil)
+
+			buildRepo.CancelBuildReturns(repositories.BuildRecord{
+				GUID:            "build-guid",
+				State:           "FAILED",
+				StagingErrorMsg: "The build has been canceled",
+			}, nil)
+
 			var err error
-			req, err = http.NewRequestWithContext(context.Background(), "PATCH", "/v3/builds/build-guid", strings.NewReader(`{}`))
+			req, err = http.NewRequestWithContext(ctx, "PATCH", "/v3/builds/build-guid", strings.NewReader(`{"state":"FAILED"}`))
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		It("returns an unprocessable entity error", func() {
-			expectUnprocessableEntityError("Labels and annotations are not supported for builds.")
+		It("validates the payload", func() {
+			Expect(requestValidator.DecodeAndValidateJSONPayloadCallCount()).To(Equal(1))
+			actualReq, _ := requestValidator.DecodeAndValidateJSONPayloadArgsForCall(0)
+			Expect(bodyString(actualReq)).To(Equal(`{"state":"FAILED"}`))
+		})
+
+		It("cancels the build", func() {
+			Expect(buildRepo.CancelBuildCallCount()).To(Equal(1))
+			_, actualAuthInfo, actualBuildGUID := buildRepo.CancelBuildArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(actualBuildGUID).To(Equal("build-guid"))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.guid", "build-guid"),
+				MatchJSONPath("$.state", "FAILED"),
+				MatchJSONPath("$.error", "The build has been canceled"),
+			)))
+		})
+
+		When("the payload is invalid", func() {
+			BeforeEach(func() {
+				requestValidator.DecodeAndValidateJSONPayloadReturns(apierrors.NewUnprocessableEntityError(errors.New("foo"), "invalid"))
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("invalid")
+				Expect(buildRepo.CancelBuildCallCount()).To(BeZero())
+			})
+		})
+
+		When("the user does not have access to the build", func() {
+			BeforeEach(func() {
+				buildRepo.GetBuildReturns(repositories.BuildRecord{}, apierrors.NewForbiddenError(nil, repositories.BuildResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError("Build")
+			})
+		})
+
+		When("the build is queued", func() {
+			BeforeEach(func() {
+				buildRepo.GetBuildReturns(repositories.BuildRecord{
+					GUID:  "build-guid",
+					State: "QUEUED",
+				}, nil)
+			})
+
+			It("cancels the build", func() {
+				Expect(buildRepo.CancelBuildCallCount()).To(Equal(1))
+				Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			})
+		})
+
+		When("the build is not staging", func() {
+			BeforeEach(func() {
+				buildRepo.GetBuildReturns(repositories.BuildRecord{
+					GUID:  "build-guid",
+					State: "STAGED",
+				}, nil)
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("Attempted to update a build that is not staging.")
+				Expect(buildRepo.CancelBuildCallCount()).To(BeZero())
+			})
+		})
+
+		When("canceling the build fails", func() {
+			BeforeEach(func() {
+				buildRepo.CancelBuildReturns(repositories.BuildRecord{}, errors.New("cancel-err"))
+			})
+
+			It("returns an error", func() {
+				expectUnknownError()
+			})
 		})
 	})
 })
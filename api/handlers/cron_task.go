@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/api/routing"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	CronTasksPath = "/v3/apps/{appGUID}/cron_tasks"
+	CronTaskRoot  = "/v3/cron_tasks"
+	CronTaskPath  = CronTaskRoot + "/{guid}"
+)
+
+//counterfeiter:generate -o fake -fake-name CFCronTaskRepository . CFCronTaskRepository
+type CFCronTaskRepository interface {
+	CreateCronTask(context.Context, authorization.Info, repositories.CreateCronTaskMessage) (repositories.CronTaskRecord, error)
+	GetCronTask(context.Context, authorization.Info, string) (repositories.CronTaskRecord, error)
+	ListCronTasks(context.Context, authorization.Info, repositories.ListCronTasksMessage) ([]repositories.CronTaskRecord, error)
+	PatchCronTask(context.Context, authorization.Info, repositories.PatchCronTaskMessage) (repositories.CronTaskRecord, error)
+	DeleteCronTask(context.Context, authorization.Info, repositories.DeleteCronTaskMessage) error
+}
+
+// CronTask serves the korifi extension API for tasks that run on a recurring
+// schedule, e.g. nightly database migrations
+type CronTask struct {
+	serverURL        url.URL
+	appRepo          CFAppRepository
+	cronTaskRepo     CFCronTaskRepository
+	requestValidator RequestValidator
+}
+
+func NewCronTask(
+	serverURL url.URL,
+	appRepo CFAppRepository,
+	cronTaskRepo CFCronTaskRepository,
+	requestValidator RequestValidator,
+) *CronTask {
+	return &CronTask{
+		serverURL:        serverURL,
+		appRepo:          appRepo,
+		cronTaskRepo:     cronTaskRepo,
+		requestValidator: requestValidator,
+	}
+}
+
+func (h *CronTask) get(r *http.Request) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.cron-task.get")
+
+	cronTaskGUID := routing.URLParam(r, "guid")
+
+	cronTaskRecord, err := h.cronTaskRepo.GetCronTask(r.Context(), authInfo, cronTaskGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "failed to get cron task", "cronTaskGUID", cronTaskGUID)
+	}
+
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForCronTask(cronTaskRecord, h.serverURL)), nil
+}
+
+func (h *CronTask) list(r *http.Request) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.cron-task.list")
+
+	payload := new(payloads.CronTaskList)
+	if err := h.requestValidator.DecodeAndValidateURLValues(r, payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "unable to decode request query parameters")
+	}
+
+	cronTasks, err := h.cronTaskRepo.ListCronTasks(r.Context(), authInfo, payload.ToMessage())
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to list cron tasks")
+	}
+
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForList(presenter.ForCronTask, cronTasks, h.serverURL, *r.URL)), nil
+}
+
+func (h *CronTask) create(r *http.Request) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.cron-task.create")
+
+	appGUID := routing.URLParam(r, "appGUID")
+
+	var payload payloads.CronTaskCreate
+	if err := h.requestValidator.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to decode payload")
+	}
+
+	appRecord, err := h.appRepo.GetApp(r.Context(), authInfo, appGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "error finding app", "appGUID", appGUID)
+	}
+
+	if !appRecord.IsStaged {
+		return nil, apierrors.LogAndReturn(
+			logger,
+			apierrors.NewUnprocessableEntityError(nil, "Cron task must have a droplet. Assign current droplet to app."),
+			"app is not staged", "App GUID", appGUID,
+		)
+	}
+
+	cronTaskRecord, err := h.cronTaskRepo.CreateCronTask(r.Context(), authInfo, payload.ToMessage(appRecord))
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to create cron task")
+	}
+
+	return routing.NewResponse(http.StatusCreated).WithBody(presenter.ForCronTask(cronTaskRecord, h.serverURL)), nil
+}
+
+func (h *CronTask) listForApp(r *http.Request) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.cron-task.list-for-app")
+
+	appGUID := routing.URLParam(r, "appGUID")
+
+	if _, err := h.appRepo.GetApp(r.Context(), authInfo, appGUID); err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "error finding app", "appGUID", appGUID)
+	}
+
+	cronTasks, err := h.cronTaskRepo.ListCronTasks(r.Context(), authInfo, repositories.ListCronTasksMessage{
+		AppGUIDs: []string{appGUID},
+	})
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to list cron tasks")
+	}
+
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForList(presenter.ForCronTask, cronTasks, h.serverURL, *r.URL)), nil
+}
+
+func (h *CronTask) update(r *http.Request) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.cron-task.update")
+
+	cronTaskGUID := routing.URLParam(r, "guid")
+
+	cronTaskRecord, err := h.cronTaskRepo.GetCronTask(r.Context(), authInfo, cronTaskGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "failed to get cron task", "cronTaskGUID", cronTaskGUID)
+	}
+
+	var payload payloads.CronTaskUpdate
+	if err = h.requestValidator.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to decode payload")
+	}
+
+	cronTaskRecord, err = h.cronTaskRepo.PatchCronTask(r.Context(), authInfo, payload.ToMessage(cronTaskGUID, cronTaskRecord.SpaceGUID))
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to patch cron task", "cronTaskGUID", cronTaskGUID)
+	}
+
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForCronTask(cronTaskRecord, h.serverURL)), nil
+}
+
+func (h *CronTask) delete(r *http.Request) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.cron-task.delete")
+
+	cronTaskGUID := routing.URLParam(r, "guid")
+
+	cronTaskRecord, err := h.cronTaskRepo.GetCronTask(r.Context(), authInfo, cronTaskGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "failed to get cron task", "cronTaskGUID", cronTaskGUID)
+	}
+
+	err = h.cronTaskRepo.DeleteCronTask(r.Context(), authInfo, repositories.DeleteCronTaskMessage{
+		GUID:      cronTaskGUID,
+		SpaceGUID: cronTaskRecord.SpaceGUID,
+	})
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to delete cron task", "cronTaskGUID", cronTaskGUID)
+	}
+
+	return routing.NewResponse(http.StatusNoContent), nil
+}
+
+func (h *CronTask) UnauthenticatedRoutes() []routing.Route {
+	return nil
+}
+
+func (h *CronTask) AuthenticatedRoutes() []routing.Route {
+	return []routing.Route{
+		{Method: "GET", Pattern: CronTaskRoot, Handler: h.list},
+		{Method: "GET", Pattern: CronTaskPath, Handler: h.get},
+		{Method: "PATCH", Pattern: CronTaskPath, Handler: h.update},
+		{Method: "DELETE", Pattern: CronTaskPath, Handler: h.delete},
+		{Method: "POST", Pattern: CronTasksPath, Handler: h.create},
+		{Method: "GET", Pattern: CronTasksPath, Handler: h.listForApp},
+	}
+}
@@ -0,0 +1,324 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	. "code.cloudfoundry.org/korifi/tests/matchers"
+	"code.cloudfoundry.org/korifi/tools"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CronTask", func() {
+	var (
+		requestMethod    string
+		requestPath      string
+		appRepo          *fake.CFAppRepository
+		cronTaskRepo     *fake.CFCronTaskRepository
+		requestValidator *fake.RequestValidator
+	)
+
+	BeforeEach(func() {
+		appRepo = new(fake.CFAppRepository)
+		appRepo.GetAppReturns(repositories.AppRecord{
+			GUID:      "the-app-guid",
+			SpaceGUID: "the-space-guid",
+			IsStaged:  true,
+		}, nil)
+
+		cronTaskRepo = new(fake.CFCronTaskRepository)
+		cronTaskRepo.GetCronTaskReturns(repositories.CronTaskRecord{
+			GUID:      "the-cron-task-guid",
+			SpaceGUID: "the-space-guid",
+			AppGUID:   "the-app-guid",
+		}, nil)
+
+		requestValidator = new(fake.RequestValidator)
+
+		apiHandler := handlers.NewCronTask(*serverURL, appRepo, cronTaskRepo, requestValidator)
+		routerBuilder.LoadRoutes(apiHandler)
+	})
+
+	JustBeforeEach(func() {
+		req, err := http.NewRequestWithContext(ctx, requestMethod, requestPath, strings.NewReader("the-json-body"))
+		Expect(err).NotTo(HaveOccurred())
+		routerBuilder.Build().ServeHTTP(rr, req)
+	})
+
+	Describe("POST /v3/apps/:app-guid/cron_tasks", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPost
+			requestPath = "/v3/apps/the-app-guid/cron_tasks"
+
+			requestValidator.DecodeAndValidateJSONPayloadStub = decodeAndValidatePayloadStub(&payloads.CronTaskCreate{
+				Command:  "rake db:migrate",
+				Schedule: "0 2 * * *",
+			})
+
+			cronTaskRepo.CreateCronTaskReturns(repositories.CronTaskRecord{
+				GUID:    "the-cron-task-guid",
+				AppGUID: "the-app-guid",
+			}, nil)
+		})
+
+		It("creates a cron task", func() {
+			Expect(requestValidator.DecodeAndValidateJSONPayloadCallCount()).To(Equal(1))
+			actualReq, _ := requestValidator.DecodeAndValidateJSONPayloadArgsForCall(0)
+			Expect(bodyString(actualReq)).To(Equal("the-json-body"))
+
+			Expect(cronTaskRepo.CreateCronTaskCallCount()).To(Equal(1))
+			_, actualAuthInfo, createMessage := cronTaskRepo.CreateCronTaskArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(createMessage.Command).To(Equal("rake db:migrate"))
+			Expect(createMessage.Schedule).To(Equal("0 2 * * *"))
+			Expect(createMessage.Enabled).To(BeTrue())
+			Expect(createMessage.AppGUID).To(Equal("the-app-guid"))
+			Expect(createMessage.SpaceGUID).To(Equal("the-space-guid"))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusCreated))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.guid", "the-cron-task-guid"),
+				MatchJSONPath("$.links.self.href", "https://api.example.org/v3/cron_tasks/the-cron-task-guid"),
+			)))
+		})
+
+		When("the user cannot see the app", func() {
+			BeforeEach(func() {
+				appRepo.GetAppReturns(repositories.AppRecord{}, apierrors.NewForbiddenError(nil, repositories.AppResourceType))
+			})
+
+			It("returns a Not Found error", func() {
+				expectNotFoundError("App")
+			})
+		})
+
+		When("the app is not staged", func() {
+			BeforeEach(func() {
+				appRepo.GetAppReturns(repositories.AppRecord{GUID: "the-app-guid", IsStaged: false}, nil)
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("Cron task must have a droplet. Assign current droplet to app.")
+			})
+		})
+
+		When("the payload is invalid", func() {
+			BeforeEach(func() {
+				requestValidator.DecodeAndValidateJSONPayloadReturns(apierrors.NewUnprocessableEntityError(nil, "oops"))
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("oops")
+			})
+		})
+
+		When("creating the cron task fails", func() {
+			BeforeEach(func() {
+				cronTaskRepo.CreateCronTaskReturns(repositories.CronTaskRecord{}, errors.New("boom"))
+			})
+
+			It("returns an Internal Server Error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+
+	Describe("GET /v3/apps/:app-guid/cron_tasks", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodGet
+			requestPath = "/v3/apps/the-app-guid/cron_tasks"
+
+			cronTaskRepo.ListCronTasksReturns([]repositories.CronTaskRecord{
+				{GUID: "cron-task-1"},
+				{GUID: "cron-task-2"},
+			}, nil)
+		})
+
+		It("lists the cron tasks of the app", func() {
+			Expect(cronTaskRepo.ListCronTasksCallCount()).To(Equal(1))
+			_, _, listMessage := cronTaskRepo.ListCronTasksArgsForCall(0)
+			Expect(listMessage.AppGUIDs).To(ConsistOf("the-app-guid"))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.pagination.total_results", BeEquivalentTo(2)),
+				MatchJSONPath("$.resources[0].guid", "cron-task-1"),
+				MatchJSONPath("$.resources[1].guid", "cron-task-2"),
+			)))
+		})
+
+		When("the app does not exist", func() {
+			BeforeEach(func() {
+				appRepo.GetAppReturns(repositories.AppRecord{}, apierrors.NewNotFoundError(nil, repositories.AppResourceType))
+			})
+
+			It("returns a Not Found error", func() {
+				expectNotFoundError("App")
+			})
+		})
+	})
+
+	Describe("GET /v3/cron_tasks", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodGet
+			requestPath = "/v3/cron_tasks?app_guids=the-app-guid"
+
+			requestValidator.DecodeAndValidateURLValuesStub = decodeAndValidateURLValuesStub(&payloads.CronTaskList{
+				AppGUIDs: "the-app-guid",
+			})
+
+			cronTaskRepo.ListCronTasksReturns([]repositories.CronTaskRecord{
+				{GUID: "cron-task-1"},
+			}, nil)
+		})
+
+		It("lists the cron tasks", func() {
+			Expect(cronTaskRepo.ListCronTasksCallCount()).To(Equal(1))
+			_, actualAuthInfo, listMessage := cronTaskRepo.ListCronTasksArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(listMessage.AppGUIDs).To(ConsistOf("the-app-guid"))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.pagination.total_results", BeEquivalentTo(1)),
+				MatchJSONPath("$.resources[0].guid", "cron-task-1"),
+			)))
+		})
+
+		When("listing the cron tasks fails", func() {
+			BeforeEach(func() {
+				cronTaskRepo.ListCronTasksReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an Internal Server Error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+
+	Describe("GET /v3/cron_tasks/:guid", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodGet
+			requestPath = "/v3/cron_tasks/the-cron-task-guid"
+		})
+
+		It("returns the cron task", func() {
+			Expect(cronTaskRepo.GetCronTaskCallCount()).To(Equal(1))
+			_, actualAuthInfo, actualGUID := cronTaskRepo.GetCronTaskArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(actualGUID).To(Equal("the-cron-task-guid"))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.guid", "the-cron-task-guid"),
+				MatchJSONPath("$.links.app.href", "https://api.example.org/v3/apps/the-app-guid"),
+			)))
+		})
+
+		When("the user cannot see the cron task", func() {
+			BeforeEach(func() {
+				cronTaskRepo.GetCronTaskReturns(repositories.CronTaskRecord{}, apierrors.NewForbiddenError(nil, repositories.CronTaskResourceType))
+			})
+
+			It("returns a Not Found error", func() {
+				expectNotFoundError(repositories.CronTaskResourceType)
+			})
+		})
+	})
+
+	Describe("PATCH /v3/cron_tasks/:guid", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPatch
+			requestPath = "/v3/cron_tasks/the-cron-task-guid"
+
+			requestValidator.DecodeAndValidateJSONPayloadStub = decodeAndValidatePayloadStub(&payloads.CronTaskUpdate{
+				Enabled: tools.PtrTo(false),
+			})
+
+			cronTaskRepo.PatchCronTaskReturns(repositories.CronTaskRecord{
+				GUID:    "the-cron-task-guid",
+				Enabled: false,
+			}, nil)
+		})
+
+		It("patches the cron task", func() {
+			Expect(cronTaskRepo.PatchCronTaskCallCount()).To(Equal(1))
+			_, actualAuthInfo, patchMessage := cronTaskRepo.PatchCronTaskArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(patchMessage.GUID).To(Equal("the-cron-task-guid"))
+			Expect(patchMessage.SpaceGUID).To(Equal("the-space-guid"))
+			Expect(patchMessage.Enabled).To(Equal(tools.PtrTo(false)))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(MatchJSONPath("$.enabled", false)))
+		})
+
+		When("the cron task does not exist", func() {
+			BeforeEach(func() {
+				cronTaskRepo.GetCronTaskReturns(repositories.CronTaskRecord{}, apierrors.NewNotFoundError(nil, repositories.CronTaskResourceType))
+			})
+
+			It("returns a Not Found error", func() {
+				expectNotFoundError(repositories.CronTaskResourceType)
+			})
+		})
+
+		When("patching the cron task fails", func() {
+			BeforeEach(func() {
+				cronTaskRepo.PatchCronTaskReturns(repositories.CronTaskRecord{}, errors.New("boom"))
+			})
+
+			It("returns an Internal Server Error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+
+	Describe("DELETE /v3/cron_tasks/:guid", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodDelete
+			requestPath = "/v3/cron_tasks/the-cron-task-guid"
+		})
+
+		It("deletes the cron task", func() {
+			Expect(cronTaskRepo.DeleteCronTaskCallCount()).To(Equal(1))
+			_, actualAuthInfo, deleteMessage := cronTaskRepo.DeleteCronTaskArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(deleteMessage).To(Equal(repositories.DeleteCronTaskMessage{
+				GUID:      "the-cron-task-guid",
+				SpaceGUID: "the-space-guid",
+			}))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusNoContent))
+		})
+
+		When("the user cannot see the cron task", func() {
+			BeforeEach(func() {
+				cronTaskRepo.GetCronTaskReturns(repositories.CronTaskRecord{}, apierrors.NewForbiddenError(nil, repositories.CronTaskResourceType))
+			})
+
+			It("returns a Not Found error", func() {
+				expectNotFoundError(repositories.CronTaskResourceType)
+				Expect(cronTaskRepo.DeleteCronTaskCallCount()).To(BeZero())
+			})
+		})
+
+		When("deleting the cron task fails", func() {
+			BeforeEach(func() {
+				cronTaskRepo.DeleteCronTaskReturns(errors.New("boom"))
+			})
+
+			It("returns an Internal Server Error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+})
@@ -18,8 +18,9 @@ import (
 )
 
 const (
-	DeploymentsPath = "/v3/deployments"
-	DeploymentPath  = "/v3/deployments/{guid}"
+	DeploymentsPath      = "/v3/deployments"
+	DeploymentPath       = "/v3/deployments/{guid}"
+	DeploymentCancelPath = "/v3/deployments/{guid}/actions/cancel"
 )
 
 //counterfeiter:generate -o fake -fake-name CFDeploymentRepository . CFDeploymentRepository
@@ -28,6 +29,7 @@ type CFDeploymentRepository interface {
 	GetDeployment(context.Context, authorization.Info, string) (repositories.DeploymentRecord, error)
 	CreateDeployment(context.Context, authorization.Info, repositories.CreateDeploymentMessage) (repositories.DeploymentRecord, error)
 	ListDeployments(context.Context, authorization.Info, repositories.ListDeploymentsMessage) ([]repositories.DeploymentRecord, error)
+	CancelDeployment(context.Context, authorization.Info, string) (repositories.DeploymentRecord, error)
 }
 
 //counterfeiter:generate -o fake -fake-name RunnerInfoRepository . RunnerInfoRepository
@@ -124,6 +126,25 @@ func (h *Deployment) list(r *http.Request) (*routing.Response, error) {
 	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForList(presenter.ForDeployment, deployments, h.serverURL, *r.URL)), nil
 }
 
+func (h *Deployment) cancel(r *http.Request) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.deployment.cancel")
+
+	deploymentGUID := routing.URLParam(r, "guid")
+
+	_, err := h.deploymentRepo.GetDeployment(r.Context(), authInfo, deploymentGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Error getting deployment in repository")
+	}
+
+	_, err = h.deploymentRepo.CancelDeployment(r.Context(), authInfo, deploymentGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Error canceling deployment in repository")
+	}
+
+	return routing.NewResponse(http.StatusOK), nil
+}
+
 func (h *Deployment) UnauthenticatedRoutes() []routing.Route {
 	return nil
 }
@@ -133,5 +154,6 @@ func (h *Deployment) AuthenticatedRoutes() []routing.Route {
 		{Method: "GET", Pattern: DeploymentPath, Handler: h.get},
 		{Method: "POST", Pattern: DeploymentsPath, Handler: h.create},
 		{Method: "GET", Pattern: DeploymentsPath, Handler: h.list},
+		{Method: "POST", Pattern: DeploymentCancelPath, Handler: h.cancel},
 	}
 }
@@ -260,4 +260,52 @@ var _ = Describe("Deployment", func() {
 			})
 		})
 	})
+
+	Describe("POST /v3/deployments/{guid}/actions/cancel", func() {
+		BeforeEach(func() {
+			deploymentsRepo.GetDeploymentReturns(repositories.DeploymentRecord{GUID: appGUID}, nil)
+			deploymentsRepo.CancelDeploymentReturns(repositories.DeploymentRecord{GUID: appGUID}, nil)
+			req = createHttpRequest("POST", "/v3/deployments/"+appGUID+"/actions/cancel", nil)
+		})
+
+		It("cancels the deployment", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+
+			Expect(deploymentsRepo.CancelDeploymentCallCount()).To(Equal(1))
+			_, actualAuthInfo, deploymentGUID := deploymentsRepo.CancelDeploymentArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(deploymentGUID).To(Equal(appGUID))
+		})
+
+		When("getting the deployment is forbidden", func() {
+			BeforeEach(func() {
+				deploymentsRepo.GetDeploymentReturns(repositories.DeploymentRecord{}, apierrors.NewForbiddenError(nil, repositories.DeploymentResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError(repositories.DeploymentResourceType)
+				Expect(deploymentsRepo.CancelDeploymentCallCount()).To(BeZero())
+			})
+		})
+
+		When("the deployment cannot be canceled", func() {
+			BeforeEach(func() {
+				deploymentsRepo.CancelDeploymentReturns(repositories.DeploymentRecord{}, apierrors.NewUnprocessableEntityError(nil, "Cannot cancel a DEPLOYED deployment"))
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("Cannot cancel a DEPLOYED deployment")
+			})
+		})
+
+		When("canceling the deployment fails", func() {
+			BeforeEach(func() {
+				deploymentsRepo.CancelDeploymentReturns(repositories.DeploymentRecord{}, errors.New("cancel-deployment-error"))
+			})
+
+			It("returns an unknown error", func() {
+				expectUnknownError()
+			})
+		})
+	})
 })
@@ -114,6 +114,21 @@ type CFAppRepository struct {
 		result1 repositories.AppEnvVarsRecord
 		result2 error
 	}
+	PatchAppStagingEnvVarsStub        func(context.Context, authorization.Info, repositories.PatchAppEnvVarsMessage) (repositories.AppEnvVarsRecord, error)
+	patchAppStagingEnvVarsMutex       sync.RWMutex
+	patchAppStagingEnvVarsArgsForCall []struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 repositories.PatchAppEnvVarsMessage
+	}
+	patchAppStagingEnvVarsReturns struct {
+		result1 repositories.AppEnvVarsRecord
+		result2 error
+	}
+	patchAppStagingEnvVarsReturnsOnCall map[int]struct {
+		result1 repositories.AppEnvVarsRecord
+		result2 error
+	}
 	SetAppDesiredStateStub        func(context.Context, authorization.Info, repositories.SetAppDesiredStateMessage) (repositories.AppRecord, error)
 	setAppDesiredStateMutex       sync.RWMutex
 	setAppDesiredStateArgsForCall []struct {
@@ -607,6 +622,72 @@ func (fake *CFAppRepository) PatchAppEnvVarsReturnsOnCall(i int, result1 reposit
 	}{result1, result2}
 }
 
+func (fake *CFAppRepository) PatchAppStagingEnvVars(arg1 context.Context, arg2 authorization.Info, arg3 repositories.PatchAppEnvVarsMessage) (repositories.AppEnvVarsRecord, error) {
+	fake.patchAppStagingEnvVarsMutex.Lock()
+	ret, specificReturn := fake.patchAppStagingEnvVarsReturnsOnCall[len(fake.patchAppStagingEnvVarsArgsForCall)]
+	fake.patchAppStagingEnvVarsArgsForCall = append(fake.patchAppStagingEnvVarsArgsForCall, struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 repositories.PatchAppEnvVarsMessage
+	}{arg1, arg2, arg3})
+	stub := fake.PatchAppStagingEnvVarsStub
+	fakeReturns := fake.patchAppStagingEnvVarsReturns
+	fake.recordInvocation("PatchAppStagingEnvVars", []interface{}{arg1, arg2, arg3})
+	fake.patchAppStagingEnvVarsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CFAppRepository) PatchAppStagingEnvVarsCallCount() int {
+	fake.patchAppStagingEnvVarsMutex.RLock()
+	defer fake.patchAppStagingEnvVarsMutex.RUnlock()
+	return len(fake.patchAppStagingEnvVarsArgsForCall)
+}
+
+func (fake *CFAppRepository) PatchAppStagingEnvVarsCalls(stub func(context.Context, authorization.Info, repositories.PatchAppEnvVarsMessage) (repositories.AppEnvVarsRecord, error)) {
+	fake.patchAppStagingEnvVarsMutex.Lock()
+	defer fake.patchAppStagingEnvVarsMutex.Unlock()
+	fake.PatchAppStagingEnvVarsStub = stub
+}
+
+func (fake *CFAppRepository) PatchAppStagingEnvVarsArgsForCall(i int) (context.Context, authorization.Info, repositories.PatchAppEnvVarsMessage) {
+	fake.patchAppStagingEnvVarsMutex.RLock()
+	defer fake.patchAppStagingEnvVarsMutex.RUnlock()
+	argsForCall := fake.patchAppStagingEnvVarsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *CFAppRepository) PatchAppStagingEnvVarsReturns(result1 repositories.AppEnvVarsRecord, result2 error) {
+	fake.patchAppStagingEnvVarsMutex.Lock()
+	defer fake.patchAppStagingEnvVarsMutex.Unlock()
+	fake.PatchAppStagingEnvVarsStub = nil
+	fake.patchAppStagingEnvVarsReturns = struct {
+		result1 repositories.AppEnvVarsRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFAppRepository) PatchAppStagingEnvVarsReturnsOnCall(i int, result1 repositories.AppEnvVarsRecord, result2 error) {
+	fake.patchAppStagingEnvVarsMutex.Lock()
+	defer fake.patchAppStagingEnvVarsMutex.Unlock()
+	fake.PatchAppStagingEnvVarsStub = nil
+	if fake.patchAppStagingEnvVarsReturnsOnCall == nil {
+		fake.patchAppStagingEnvVarsReturnsOnCall = make(map[int]struct {
+			result1 repositories.AppEnvVarsRecord
+			result2 error
+		})
+	}
+	fake.patchAppStagingEnvVarsReturnsOnCall[i] = struct {
+		result1 repositories.AppEnvVarsRecord
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *CFAppRepository) SetAppDesiredState(arg1 context.Context, arg2 authorization.Info, arg3 repositories.SetAppDesiredStateMessage) (repositories.AppRecord, error) {
 	fake.setAppDesiredStateMutex.Lock()
 	ret, specificReturn := fake.setAppDesiredStateReturnsOnCall[len(fake.setAppDesiredStateArgsForCall)]
@@ -756,6 +837,8 @@ func (fake *CFAppRepository) Invocations() map[string][][]interface{} {
 	defer fake.patchAppMutex.RUnlock()
 	fake.patchAppEnvVarsMutex.RLock()
 	defer fake.patchAppEnvVarsMutex.RUnlock()
+	fake.patchAppStagingEnvVarsMutex.RLock()
+	defer fake.patchAppStagingEnvVarsMutex.RUnlock()
 	fake.setAppDesiredStateMutex.RLock()
 	defer fake.setAppDesiredStateMutex.RUnlock()
 	fake.setCurrentDropletMutex.RLock()
@@ -11,6 +11,21 @@ import (
 )
 
 type CFBuildRepository struct {
+	CancelBuildStub        func(context.Context, authorization.Info, string) (repositories.BuildRecord, error)
+	cancelBuildMutex       sync.RWMutex
+	cancelBuildArgsForCall []struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 string
+	}
+	cancelBuildReturns struct {
+		result1 repositories.BuildRecord
+		result2 error
+	}
+	cancelBuildReturnsOnCall map[int]struct {
+		result1 repositories.BuildRecord
+		result2 error
+	}
 	CreateBuildStub        func(context.Context, authorization.Info, repositories.CreateBuildMessage) (repositories.BuildRecord, error)
 	createBuildMutex       sync.RWMutex
 	createBuildArgsForCall []struct {
@@ -61,6 +76,72 @@ type CFBuildRepository struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *CFBuildRepository) CancelBuild(arg1 context.Context, arg2 authorization.Info, arg3 string) (repositories.BuildRecord, error) {
+	fake.cancelBuildMutex.Lock()
+	ret, specificReturn := fake.cancelBuildReturnsOnCall[len(fake.cancelBuildArgsForCall)]
+	fake.cancelBuildArgsForCall = append(fake.cancelBuildArgsForCall, struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.CancelBuildStub
+	fakeReturns := fake.cancelBuildReturns
+	fake.recordInvocation("CancelBuild", []interface{}{arg1, arg2, arg3})
+	fake.cancelBuildMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CFBuildRepository) CancelBuildCallCount() int {
+	fake.cancelBuildMutex.RLock()
+	defer fake.cancelBuildMutex.RUnlock()
+	return len(fake.cancelBuildArgsForCall)
+}
+
+func (fake *CFBuildRepository) CancelBuildCalls(stub func(context.Context, authorization.Info, string) (repositories.BuildRecord, error)) {
+	fake.cancelBuildMutex.Lock()
+	defer fake.cancelBuildMutex.Unlock()
+	fake.CancelBuildStub = stub
+}
+
+func (fake *CFBuildRepository) CancelBuildArgsForCall(i int) (context.Context, authorization.Info, string) {
+	fake.cancelBuildMutex.RLock()
+	defer fake.cancelBuildMutex.RUnlock()
+	argsForCall := fake.cancelBuildArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *CFBuildRepository) CancelBuildReturns(result1 repositories.BuildRecord, result2 error) {
+	fake.cancelBuildMutex.Lock()
+	defer fake.cancelBuildMutex.Unlock()
+	fake.CancelBuildStub = nil
+	fake.cancelBuildReturns = struct {
+		result1 repositories.BuildRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFBuildRepository) CancelBuildReturnsOnCall(i int, result1 repositories.BuildRecord, result2 error) {
+	fake.cancelBuildMutex.Lock()
+	defer fake.cancelBuildMutex.Unlock()
+	fake.CancelBuildStub = nil
+	if fake.cancelBuildReturnsOnCall == nil {
+		fake.cancelBuildReturnsOnCall = make(map[int]struct {
+			result1 repositories.BuildRecord
+			result2 error
+		})
+	}
+	fake.cancelBuildReturnsOnCall[i] = struct {
+		result1 repositories.BuildRecord
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *CFBuildRepository) CreateBuild(arg1 context.Context, arg2 authorization.Info, arg3 repositories.CreateBuildMessage) (repositories.BuildRecord, error) {
 	fake.createBuildMutex.Lock()
 	ret, specificReturn := fake.createBuildReturnsOnCall[len(fake.createBuildArgsForCall)]
@@ -263,6 +344,8 @@ func (fake *CFBuildRepository) GetLatestBuildByAppGUIDReturnsOnCall(i int, resul
 func (fake *CFBuildRepository) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.cancelBuildMutex.RLock()
+	defer fake.cancelBuildMutex.RUnlock()
 	fake.createBuildMutex.RLock()
 	defer fake.createBuildMutex.RUnlock()
 	fake.getBuildMutex.RLock()
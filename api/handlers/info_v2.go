@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/config"
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/routing"
+)
+
+const (
+	InfoV2Path = "/v2/info"
+)
+
+type InfoV2 struct {
+	baseURL          url.URL
+	infoConfig       config.InfoConfig
+	oauthShimEnabled bool
+}
+
+func NewInfoV2(baseURL url.URL, infoConfig config.InfoConfig, oauthShimEnabled bool) *InfoV2 {
+	return &InfoV2{
+		baseURL:          baseURL,
+		infoConfig:       infoConfig,
+		oauthShimEnabled: oauthShimEnabled,
+	}
+}
+
+func (h *InfoV2) get(r *http.Request) (*routing.Response, error) {
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForInfoV2(h.baseURL, h.infoConfig, h.oauthShimEnabled)), nil
+}
+
+func (h *InfoV2) UnauthenticatedRoutes() []routing.Route {
+	return []routing.Route{
+		{Method: "GET", Pattern: InfoV2Path, Handler: h.get},
+	}
+}
+
+func (h *InfoV2) AuthenticatedRoutes() []routing.Route {
+	return nil
+}
@@ -0,0 +1,48 @@
+package handlers_test
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/korifi/api/config"
+	"code.cloudfoundry.org/korifi/api/handlers"
+	. "code.cloudfoundry.org/korifi/tests/matchers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InfoV2", func() {
+	var req *http.Request
+
+	BeforeEach(func() {
+		apiHandler := handlers.NewInfoV2(
+			*serverURL,
+			config.InfoConfig{Name: "korifi"},
+			true,
+		)
+		routerBuilder.LoadRoutes(apiHandler)
+	})
+
+	JustBeforeEach(func() {
+		routerBuilder.Build().ServeHTTP(rr, req)
+	})
+
+	Describe("the GET /v2/info endpoint", func() {
+		BeforeEach(func() {
+			var err error
+			req, err = http.NewRequest("GET", "/v2/info", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the expected response", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
+
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.name", "korifi"),
+				MatchJSONPath("$.authorization_endpoint", "https://api.example.org"),
+				MatchJSONPath("$.token_endpoint", "https://api.example.org"),
+				MatchJSONPath("$.api_version", "2.164.0+cf-k8s"),
+			)))
+		})
+	})
+})
@@ -14,12 +14,16 @@ import (
 	"code.cloudfoundry.org/korifi/api/routing"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
 )
 
 const (
-	LogCacheInfoPath = "/api/v1/info"
-	LogCacheReadPath = "/api/v1/read/{guid}"
-	logCacheVersion  = "2.11.4+cf-k8s"
+	LogCacheInfoPath       = "/api/v1/info"
+	LogCacheReadPath       = "/api/v1/read/{guid}"
+	LogCacheQueryPath      = "/api/v1/query"
+	LogCacheQueryRangePath = "/api/v1/query_range"
+	logCacheVersion        = "2.11.4+cf-k8s"
 )
 
 //counterfeiter:generate -o fake -fake-name LogRepository . LogRepository
@@ -27,6 +31,12 @@ type LogRepository interface {
 	GetAppLogs(context.Context, authorization.Info, repositories.GetLogsMessage) ([]repositories.LogRecord, error)
 }
 
+//counterfeiter:generate -o fake -fake-name PromQLRepository . PromQLRepository
+type PromQLRepository interface {
+	Query(context.Context, repositories.PromQLQueryMessage) (repositories.PromQLRecord, error)
+	QueryRange(context.Context, repositories.PromQLRangeQueryMessage) (repositories.PromQLRecord, error)
+}
+
 // LogCache implements the minimal set of log-cache API endpoints/features necessary
 // to support the "cf push" workfloh.handlerWrapper.
 type LogCache struct {
@@ -34,6 +44,7 @@ type LogCache struct {
 	appRepo          CFAppRepository
 	buildRepo        CFBuildRepository
 	logRepo          LogRepository
+	promQLRepo       PromQLRepository
 }
 
 func NewLogCache(
@@ -41,12 +52,14 @@ func NewLogCache(
 	appRepo CFAppRepository,
 	buildRepository CFBuildRepository,
 	logRepo LogRepository,
+	promQLRepo PromQLRepository,
 ) *LogCache {
 	return &LogCache{
 		requestValidator: requestValidator,
 		appRepo:          appRepo,
 		buildRepo:        buildRepository,
 		logRepo:          logRepo,
+		promQLRepo:       promQLRepo,
 	}
 }
 
@@ -104,6 +117,73 @@ func (h *LogCache) getAppLogs(ctx context.Context, logger logr.Logger, authInfo
 	return logs, nil
 }
 
+func (h *LogCache) query(r *http.Request) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.log-cache.query")
+
+	payload := payloads.LogCacheQuery{}
+	if err := h.requestValidator.DecodeAndValidateURLValues(r, &payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to decode payload")
+	}
+
+	if err := h.authorizeQuery(r.Context(), authInfo, payload.Query); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to authorize query", "query", payload.Query)
+	}
+
+	result, err := h.promQLRepo.Query(r.Context(), repositories.PromQLQueryMessage{
+		Query: payload.Query,
+		Time:  payload.Time,
+	})
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to query metrics", "query", payload.Query)
+	}
+
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForPromQL(result)), nil
+}
+
+func (h *LogCache) queryRange(r *http.Request) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.log-cache.query-range")
+
+	payload := payloads.LogCacheQueryRange{}
+	if err := h.requestValidator.DecodeAndValidateURLValues(r, &payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to decode payload")
+	}
+
+	if err := h.authorizeQuery(r.Context(), authInfo, payload.Query); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to authorize query", "query", payload.Query)
+	}
+
+	result, err := h.promQLRepo.QueryRange(r.Context(), repositories.PromQLRangeQueryMessage{
+		Query: payload.Query,
+		Start: payload.Start,
+		End:   payload.End,
+		Step:  payload.Step,
+	})
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to query metrics range", "query", payload.Query)
+	}
+
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForPromQL(result)), nil
+}
+
+// authorizeQuery ensures that every selector in the query is scoped to an
+// app (via `source_id`) that the user is allowed to see
+func (h *LogCache) authorizeQuery(ctx context.Context, authInfo authorization.Info, query string) error {
+	sourceIDs, err := promQLSourceIDs(query)
+	if err != nil {
+		return apierrors.NewUnprocessableEntityError(err, err.Error())
+	}
+
+	for _, sourceID := range sourceIDs {
+		if _, err := h.appRepo.GetApp(ctx, authInfo, sourceID); err != nil {
+			return apierrors.ForbiddenAsNotFound(err)
+		}
+	}
+
+	return nil
+}
+
 func (h *LogCache) UnauthenticatedRoutes() []routing.Route {
 	return []routing.Route{
 		{Method: "GET", Pattern: LogCacheInfoPath, Handler: h.info},
@@ -113,5 +193,54 @@ func (h *LogCache) UnauthenticatedRoutes() []routing.Route {
 func (h *LogCache) AuthenticatedRoutes() []routing.Route {
 	return []routing.Route{
 		{Method: "GET", Pattern: LogCacheReadPath, Handler: h.read},
+		{Method: "GET", Pattern: LogCacheQueryPath, Handler: h.query},
+		{Method: "GET", Pattern: LogCacheQueryRangePath, Handler: h.queryRange},
 	}
 }
+
+var errPromQLUnscopedMetrics = errors.New("every metric selector in the query must select a source_id")
+
+// promQLSourceIDs returns the source IDs selected by a PromQL query. It fails
+// if any of the metric selectors in the query is not scoped to source IDs
+// via equality matchers.
+func promQLSourceIDs(query string) ([]string, error) {
+	expr, err := parser.ParseExpr(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	sourceIDs := []string{}
+	for _, selector := range parser.ExtractSelectors(expr) {
+		selectorSourceIDs, err := promQLSelectorSourceIDs(selector)
+		if err != nil {
+			return nil, err
+		}
+		sourceIDs = append(sourceIDs, selectorSourceIDs...)
+	}
+
+	if len(sourceIDs) == 0 {
+		return nil, errPromQLUnscopedMetrics
+	}
+
+	return sourceIDs, nil
+}
+
+func promQLSelectorSourceIDs(matchers []*labels.Matcher) ([]string, error) {
+	sourceIDs := []string{}
+	for _, matcher := range matchers {
+		if matcher.Name != "source_id" {
+			continue
+		}
+
+		if matcher.Type != labels.MatchEqual {
+			return nil, errors.New("source_id can only be selected with the equality operator")
+		}
+		sourceIDs = append(sourceIDs, matcher.Value)
+	}
+
+	if len(sourceIDs) == 0 {
+		return nil, errPromQLUnscopedMetrics
+	}
+
+	return sourceIDs, nil
+}
@@ -2,6 +2,7 @@ package handlers_test
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"net/http"
 
@@ -23,6 +24,7 @@ var _ = Describe("LogCache", func() {
 		appRepo          *fake.CFAppRepository
 		buildRepo        *fake.CFBuildRepository
 		logRepo          *fake.LogRepository
+		promQLRepo       *fake.PromQLRepository
 		req              *http.Request
 		requestValidator *fake.RequestValidator
 	)
@@ -32,6 +34,21 @@ var _ = Describe("LogCache", func() {
 		appRepo = new(fake.CFAppRepository)
 		buildRepo = new(fake.CFBuildRepository)
 		logRepo = new(fake.LogRepository)
+		promQLRepo = new(fake.PromQLRepository)
+		promQLRepo.QueryReturns(repositories.PromQLRecord{
+			Status: "success",
+			Data: repositories.PromQLData{
+				ResultType: "vector",
+				Result:     json.RawMessage(`[{"metric":{"source_id":"app-guid"},"value":[1,"0.5"]}]`),
+			},
+		}, nil)
+		promQLRepo.QueryRangeReturns(repositories.PromQLRecord{
+			Status: "success",
+			Data: repositories.PromQLData{
+				ResultType: "matrix",
+				Result:     json.RawMessage(`[{"metric":{"source_id":"app-guid"},"values":[[1,"0.5"]]}]`),
+			},
+		}, nil)
 
 		appRepo.GetAppReturns(repositories.AppRecord{
 			GUID:      "app-guid",
@@ -47,6 +64,7 @@ var _ = Describe("LogCache", func() {
 			appRepo,
 			buildRepo,
 			logRepo,
+			promQLRepo,
 		)
 		routerBuilder.LoadRoutes(apiHandler)
 	})
@@ -198,4 +216,211 @@ var _ = Describe("LogCache", func() {
 			)))
 		})
 	})
+
+	Describe("GET /api/v1/query", func() {
+		var payload *payloads.LogCacheQuery
+
+		BeforeEach(func() {
+			var err error
+			req, err = http.NewRequestWithContext(ctx, "GET", "/api/v1/query", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload = &payloads.LogCacheQuery{
+				Query: `sum by (instance_id) (rate(cpu{source_id="app-guid"}[1m] offset 5m))`,
+				Time:  "123",
+			}
+			requestValidator.DecodeAndValidateURLValuesStub = decodeAndValidateURLValuesStub(payload)
+		})
+
+		It("validates the payload", func() {
+			Expect(requestValidator.DecodeAndValidateURLValuesCallCount()).To(Equal(1))
+			_, actualPayload := requestValidator.DecodeAndValidateURLValuesArgsForCall(0)
+			Expect(actualPayload).To(Equal(payload))
+		})
+
+		It("checks that the user can see the queried app", func() {
+			Expect(appRepo.GetAppCallCount()).To(Equal(1))
+			_, actualAuthInfo, actualAppGUID := appRepo.GetAppArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(actualAppGUID).To(Equal("app-guid"))
+		})
+
+		It("queries the metrics and returns them", func() {
+			Expect(promQLRepo.QueryCallCount()).To(Equal(1))
+			_, actualMessage := promQLRepo.QueryArgsForCall(0)
+			Expect(actualMessage).To(Equal(repositories.PromQLQueryMessage{
+				Query: `sum by (instance_id) (rate(cpu{source_id="app-guid"}[1m] offset 5m))`,
+				Time:  "123",
+			}))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.status", "success"),
+				MatchJSONPath("$.data.resultType", "vector"),
+				MatchJSONPath("$.data.result[0].metric.source_id", "app-guid"),
+			)))
+		})
+
+		When("the query selects multiple apps", func() {
+			BeforeEach(func() {
+				payload.Query = `cpu{source_id="app-guid"} + memory{source_id="another-app-guid", instance_id="0"}`
+			})
+
+			It("checks all of them", func() {
+				Expect(appRepo.GetAppCallCount()).To(Equal(2))
+				_, _, actualAppGUID := appRepo.GetAppArgsForCall(1)
+				Expect(actualAppGUID).To(Equal("another-app-guid"))
+			})
+		})
+
+		When("the query selects a metric without source_id", func() {
+			BeforeEach(func() {
+				payload.Query = `cpu{source_id="app-guid"} or memory{instance_id="0"}`
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("every metric selector in the query must select a source_id")
+				Expect(promQLRepo.QueryCallCount()).To(BeZero())
+			})
+		})
+
+		When("the query selects a bare metric", func() {
+			BeforeEach(func() {
+				payload.Query = `cpu{source_id="app-guid"} / memory`
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("every metric selector in the query must select a source_id")
+				Expect(promQLRepo.QueryCallCount()).To(BeZero())
+			})
+		})
+
+		When("the query matches the source_id with a regex", func() {
+			BeforeEach(func() {
+				payload.Query = `cpu{source_id=~".+"}`
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("source_id can only be selected with the equality operator")
+				Expect(promQLRepo.QueryCallCount()).To(BeZero())
+			})
+		})
+
+		When("the query hides a selector behind an escaped quote", func() {
+			BeforeEach(func() {
+				payload.Query = `label_replace(cpu{source_id="app-guid"}, "a", "\"", "b", "c") + memory{source_id="another-app-guid"}`
+			})
+
+			It("authorizes the hidden selector", func() {
+				Expect(appRepo.GetAppCallCount()).To(Equal(2))
+				_, _, secondAppGUID := appRepo.GetAppArgsForCall(1)
+				Expect(secondAppGUID).To(Equal("another-app-guid"))
+			})
+		})
+
+		When("the source_id is also matched with a regex", func() {
+			BeforeEach(func() {
+				payload.Query = `cpu{source_id="app-guid", source_id=~".+"}`
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("source_id can only be selected with the equality operator")
+				Expect(promQLRepo.QueryCallCount()).To(BeZero())
+			})
+		})
+
+		When("the query is invalid", func() {
+			BeforeEach(func() {
+				payload.Query = `cpu{source_id="app-guid"`
+			})
+
+			It("returns an unprocessable entity error", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusUnprocessableEntity))
+				Expect(promQLRepo.QueryCallCount()).To(BeZero())
+			})
+		})
+
+		When("the app is not accessible", func() {
+			BeforeEach(func() {
+				appRepo.GetAppReturns(repositories.AppRecord{}, apierrors.NewForbiddenError(nil, repositories.AppResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError("App")
+				Expect(promQLRepo.QueryCallCount()).To(BeZero())
+			})
+		})
+
+		When("querying the metrics fails", func() {
+			BeforeEach(func() {
+				promQLRepo.QueryReturns(repositories.PromQLRecord{}, errors.New("query-error"))
+			})
+
+			It("returns an error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+
+	Describe("GET /api/v1/query_range", func() {
+		var payload *payloads.LogCacheQueryRange
+
+		BeforeEach(func() {
+			var err error
+			req, err = http.NewRequestWithContext(ctx, "GET", "/api/v1/query_range", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload = &payloads.LogCacheQueryRange{
+				Query: `memory{source_id="app-guid"}`,
+				Start: "1",
+				End:   "2",
+				Step:  "3s",
+			}
+			requestValidator.DecodeAndValidateURLValuesStub = decodeAndValidateURLValuesStub(payload)
+		})
+
+		It("checks that the user can see the queried app", func() {
+			Expect(appRepo.GetAppCallCount()).To(Equal(1))
+			_, _, actualAppGUID := appRepo.GetAppArgsForCall(0)
+			Expect(actualAppGUID).To(Equal("app-guid"))
+		})
+
+		It("queries the metrics range and returns it", func() {
+			Expect(promQLRepo.QueryRangeCallCount()).To(Equal(1))
+			_, actualMessage := promQLRepo.QueryRangeArgsForCall(0)
+			Expect(actualMessage).To(Equal(repositories.PromQLRangeQueryMessage{
+				Query: `memory{source_id="app-guid"}`,
+				Start: "1",
+				End:   "2",
+				Step:  "3s",
+			}))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.data.resultType", "matrix"),
+				MatchJSONPath("$.data.result[0].metric.source_id", "app-guid"),
+			)))
+		})
+
+		When("the payload is invalid", func() {
+			BeforeEach(func() {
+				requestValidator.DecodeAndValidateURLValuesReturns(apierrors.NewUnprocessableEntityError(nil, "invalid-payload"))
+			})
+
+			It("returns an error", func() {
+				expectUnprocessableEntityError("invalid-payload")
+			})
+		})
+
+		When("querying the metrics fails", func() {
+			BeforeEach(func() {
+				promQLRepo.QueryRangeReturns(repositories.PromQLRecord{}, errors.New("query-error"))
+			})
+
+			It("returns an error", func() {
+				expectUnknownError()
+			})
+		})
+	})
 })
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/routing"
+	"code.cloudfoundry.org/korifi/version"
+	"github.com/go-logr/logr"
+)
+
+const (
+	LoginPath = "/login"
+)
+
+//counterfeiter:generate -o fake -fake-name TokenExchanger . TokenExchanger
+
+type TokenExchanger interface {
+	ExchangeToken(context.Context, authorization.TokenRequest) (authorization.TokenResponse, error)
+}
+
+// OAuthShim serves the subset of the UAA login and token endpoints used by
+// `cf login` and `cf auth`, exchanging the credentials with the configured
+// OIDC provider, so that the CLI can log in without the kubectl auth plugin
+type OAuthShim struct {
+	apiBaseURL     url.URL
+	tokenExchanger TokenExchanger
+}
+
+func NewOAuthShim(apiBaseURL url.URL, tokenExchanger TokenExchanger) *OAuthShim {
+	return &OAuthShim{
+		apiBaseURL:     apiBaseURL,
+		tokenExchanger: tokenExchanger,
+	}
+}
+
+func (h *OAuthShim) login(r *http.Request) (*routing.Response, error) {
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForLogin(h.apiBaseURL, version.Version)), nil
+}
+
+func (h *OAuthShim) token(r *http.Request) (*routing.Response, error) {
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.oauth-shim.token")
+
+	if err := r.ParseForm(); err != nil {
+		return oauthErrorResponse(http.StatusBadRequest, "invalid_request", "the request body is not a valid form"), nil
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+
+	tokenResp, err := h.tokenExchanger.ExchangeToken(r.Context(), authorization.TokenRequest{
+		GrantType:    r.PostForm.Get("grant_type"),
+		Username:     r.PostForm.Get("username"),
+		Password:     r.PostForm.Get("password"),
+		RefreshToken: r.PostForm.Get("refresh_token"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scope:        r.PostForm.Get("scope"),
+	})
+	if err != nil {
+		if errors.As(err, &apierrors.InvalidAuthError{}) {
+			logger.Info("credentials rejected", "reason", err)
+			return oauthErrorResponse(http.StatusUnauthorized, "unauthorized", "Bad credentials"), nil
+		}
+
+		return nil, apierrors.LogAndReturn(logger, err, "failed to exchange token")
+	}
+
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForToken(tokenResp)), nil
+}
+
+func oauthErrorResponse(status int, oauthError, description string) *routing.Response {
+	return routing.NewResponse(status).WithBody(map[string]string{
+		"error":             oauthError,
+		"error_description": description,
+	})
+}
+
+func (h *OAuthShim) UnauthenticatedRoutes() []routing.Route {
+	return []routing.Route{
+		{Method: "GET", Pattern: LoginPath, Handler: h.login},
+		{Method: "POST", Pattern: OAuthTokenPath, Handler: h.token},
+	}
+}
+
+func (h *OAuthShim) AuthenticatedRoutes() []routing.Route {
+	return nil
+}
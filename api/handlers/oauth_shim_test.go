@@ -0,0 +1,146 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	. "code.cloudfoundry.org/korifi/tests/matchers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OAuthShim", func() {
+	var tokenExchanger *fake.TokenExchanger
+
+	BeforeEach(func() {
+		tokenExchanger = new(fake.TokenExchanger)
+		tokenExchanger.ExchangeTokenReturns(authorization.TokenResponse{
+			AccessToken:  "the-access-token",
+			RefreshToken: "the-refresh-token",
+			ExpiresIn:    300,
+		}, nil)
+
+		apiHandler := handlers.NewOAuthShim(*serverURL, tokenExchanger)
+		routerBuilder.LoadRoutes(apiHandler)
+	})
+
+	Describe("GET /login", func() {
+		JustBeforeEach(func() {
+			req, err := http.NewRequest(http.MethodGet, "/login", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			routerBuilder.Build().ServeHTTP(rr, req)
+		})
+
+		It("returns the login prompts", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.links.uaa", "https://api.example.org"),
+				MatchJSONPath("$.prompts.username[0]", "text"),
+				MatchJSONPath("$.prompts.password[0]", "password"),
+			)))
+		})
+	})
+
+	Describe("POST /oauth/token", func() {
+		var (
+			form                url.Values
+			basicAuthClientID   string
+			basicAuthClientPass string
+		)
+
+		BeforeEach(func() {
+			form = url.Values{
+				"grant_type": {"password"},
+				"username":   {"alice"},
+				"password":   {"alice-password"},
+				"scope":      {"openid email"},
+			}
+			basicAuthClientID = "cf"
+			basicAuthClientPass = ""
+		})
+
+		JustBeforeEach(func() {
+			req, err := http.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if basicAuthClientID != "" {
+				req.SetBasicAuth(basicAuthClientID, basicAuthClientPass)
+			}
+
+			routerBuilder.Build().ServeHTTP(rr, req)
+		})
+
+		It("exchanges the credentials for a token", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.access_token", "the-access-token"),
+				MatchJSONPath("$.token_type", "bearer"),
+				MatchJSONPath("$.refresh_token", "the-refresh-token"),
+				MatchJSONPath("$.expires_in", BeEquivalentTo(300)),
+			)))
+
+			Expect(tokenExchanger.ExchangeTokenCallCount()).To(Equal(1))
+			_, tokenRequest := tokenExchanger.ExchangeTokenArgsForCall(0)
+			Expect(tokenRequest).To(Equal(authorization.TokenRequest{
+				GrantType: "password",
+				Username:  "alice",
+				Password:  "alice-password",
+				ClientID:  "cf",
+				Scope:     "openid email",
+			}))
+		})
+
+		When("the client credentials are in the form", func() {
+			BeforeEach(func() {
+				basicAuthClientID = ""
+				form = url.Values{
+					"grant_type":    {"client_credentials"},
+					"client_id":     {"my-client"},
+					"client_secret": {"my-client-secret"},
+				}
+			})
+
+			It("passes them to the token exchanger", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+
+				Expect(tokenExchanger.ExchangeTokenCallCount()).To(Equal(1))
+				_, tokenRequest := tokenExchanger.ExchangeTokenArgsForCall(0)
+				Expect(tokenRequest.GrantType).To(Equal("client_credentials"))
+				Expect(tokenRequest.ClientID).To(Equal("my-client"))
+				Expect(tokenRequest.ClientSecret).To(Equal("my-client-secret"))
+			})
+		})
+
+		When("the credentials are rejected", func() {
+			BeforeEach(func() {
+				tokenExchanger.ExchangeTokenReturns(authorization.TokenResponse{}, apierrors.NewInvalidAuthError(errors.New("invalid_grant")))
+			})
+
+			It("returns an oauth error", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusUnauthorized))
+				Expect(rr).To(HaveHTTPBody(SatisfyAll(
+					MatchJSONPath("$.error", "unauthorized"),
+					MatchJSONPath("$.error_description", "Bad credentials"),
+				)))
+			})
+		})
+
+		When("exchanging the token fails", func() {
+			BeforeEach(func() {
+				tokenExchanger.ExchangeTokenReturns(authorization.TokenResponse{}, errors.New("boom"))
+			})
+
+			It("returns an unknown error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+})
@@ -12,6 +12,7 @@ import (
 	"github.com/go-logr/logr"
 
 	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/config"
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	"code.cloudfoundry.org/korifi/api/payloads"
 	"code.cloudfoundry.org/korifi/api/presenter"
@@ -43,10 +44,10 @@ type Org struct {
 	domainRepo                               CFDomainRepository
 	requestValidator                         RequestValidator
 	userCertificateExpirationWarningDuration time.Duration
-	defaultDomainName                        string
+	defaultDomainName                        *config.Reloadable[string]
 }
 
-func NewOrg(apiBaseURL url.URL, orgRepo CFOrgRepository, domainRepo CFDomainRepository, requestValidator RequestValidator, userCertificateExpirationWarningDuration time.Duration, defaultDomainName string) *Org {
+func NewOrg(apiBaseURL url.URL, orgRepo CFOrgRepository, domainRepo CFDomainRepository, requestValidator RequestValidator, userCertificateExpirationWarningDuration time.Duration, defaultDomainName *config.Reloadable[string]) *Org {
 	return &Org{
 		apiBaseURL:                               apiBaseURL,
 		orgRepo:                                  orgRepo,
@@ -175,7 +176,7 @@ func (h *Org) defaultDomain(r *http.Request) (*routing.Response, error) {
 	}
 
 	domains, err := h.domainRepo.ListDomains(r.Context(), authInfo, repositories.ListDomainsMessage{
-		Names: []string{h.defaultDomainName},
+		Names: []string{h.defaultDomainName.Get()},
 	})
 	if err != nil {
 		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Unable to list domains")
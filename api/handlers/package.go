@@ -2,11 +2,17 @@ package handlers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"sort"
+	"strings"
 
 	"code.cloudfoundry.org/korifi/api/authorization"
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
@@ -26,7 +32,7 @@ const (
 )
 
 //counterfeiter:generate -o fake -fake-name CFPackageRepository . CFPackageRepository
-//counterfeiter:generate -o fake -fake-name ImageRepository . ImageRepository
+//counterfeiter:generate -o fake -fake-name PackageBlobstore . PackageBlobstore
 //counterfeiter:generate -o fake -fake-name RequestValidator . RequestValidator
 
 type CFPackageRepository interface {
@@ -37,8 +43,8 @@ type CFPackageRepository interface {
 	UpdatePackage(context.Context, authorization.Info, repositories.UpdatePackageMessage) (repositories.PackageRecord, error)
 }
 
-type ImageRepository interface {
-	UploadSourceImage(ctx context.Context, authInfo authorization.Info, imageRef string, srcReader io.Reader, spaceGUID string, tags ...string) (imageRefWithDigest string, err error)
+type PackageBlobstore interface {
+	UploadPackageBits(context.Context, authorization.Info, repositories.UploadPackageBitsMessage) (repositories.PackageBitsRecord, error)
 }
 
 type Package struct {
@@ -46,9 +52,10 @@ type Package struct {
 	packageRepo         CFPackageRepository
 	appRepo             CFAppRepository
 	dropletRepo         CFDropletRepository
-	imageRepo           ImageRepository
+	packageBlobstore    PackageBlobstore
 	requestValidator    RequestValidator
 	registrySecretNames []string
+	maxUploadSize       int64
 }
 
 func NewPackage(
@@ -56,18 +63,20 @@ func NewPackage(
 	packageRepo CFPackageRepository,
 	appRepo CFAppRepository,
 	dropletRepo CFDropletRepository,
-	imageRepo ImageRepository,
+	packageBlobstore PackageBlobstore,
 	requestValidator RequestValidator,
 	registrySecretNames []string,
+	maxUploadSize int64,
 ) *Package {
 	return &Package{
 		serverURL:           serverURL,
 		packageRepo:         packageRepo,
 		appRepo:             appRepo,
 		dropletRepo:         dropletRepo,
-		imageRepo:           imageRepo,
+		packageBlobstore:    packageBlobstore,
 		registrySecretNames: registrySecretNames,
 		requestValidator:    requestValidator,
+		maxUploadSize:       maxUploadSize,
 	}
 }
 
@@ -174,16 +183,15 @@ func (h Package) upload(r *http.Request) (*routing.Response, error) {
 	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.package.upload")
 
 	packageGUID := routing.URLParam(r, "guid")
-	err := r.ParseForm()
-	if err != nil {
-		return nil, apierrors.LogAndReturn(logger, apierrors.NewInvalidRequestError(err, "Unable to parse body as multipart form"), "Error parsing multipart form")
-	}
 
-	bitsFile, _, err := r.FormFile("bits")
+	// The bits are streamed from the request body to the blobstore, rather
+	// than parsed as a form, so that large packages are not buffered
+	r.Body = http.MaxBytesReader(nil, r.Body, h.maxUploadSize)
+	form, err := readPackageUploadForm(r)
 	if err != nil {
-		return nil, apierrors.LogAndReturn(logger, apierrors.NewUnprocessableEntityError(err, "Upload must include bits"), "Error reading form file \"bits\"")
+		return nil, apierrors.LogAndReturn(logger, h.uploadError(err), "Error reading form file \"bits\"")
 	}
-	defer bitsFile.Close()
+	defer form.bits.Close()
 
 	packageRecord, err := h.packageRepo.GetPackage(r.Context(), authInfo, packageGUID)
 	if err != nil {
@@ -202,16 +210,32 @@ func (h Package) upload(r *http.Request) (*routing.Response, error) {
 		return nil, apierrors.LogAndReturn(logger, apierrors.NewPackageBitsAlreadyUploadedError(err), "Error, cannot call package upload state was not AWAITING_UPLOAD", "packageGUID", packageGUID)
 	}
 
-	uploadedImageRef, err := h.imageRepo.UploadSourceImage(r.Context(), authInfo, packageRecord.ImageRef, bitsFile, packageRecord.SpaceGUID, packageGUID)
+	// the checksum is verified as the bits are streamed, so that the
+	// blobstore fails to read mismatching bits before storing them
+	bits := newChecksumReader(form.bits, form.checksum)
+	bitsRecord, err := h.packageBlobstore.UploadPackageBits(r.Context(), authInfo, repositories.UploadPackageBitsMessage{
+		PackageGUID: packageGUID,
+		SpaceGUID:   packageRecord.SpaceGUID,
+		ImageRef:    packageRecord.ImageRef,
+		Bits:        bits,
+	})
 	if err != nil {
-		return nil, apierrors.LogAndReturn(logger, err, "Error calling uploadSourceImage")
+		return nil, apierrors.LogAndReturn(logger, h.uploadError(err), "Error uploading package bits")
+	}
+
+	// make sure the digest covers the whole archive, even if the blobstore
+	// stopped reading at the end of the zip
+	if _, err = io.Copy(io.Discard, bits); err != nil {
+		return nil, apierrors.LogAndReturn(logger, h.uploadError(err), "Error reading package bits")
 	}
 
 	packageRecord, err = h.packageRepo.UpdatePackageSource(r.Context(), authInfo, repositories.UpdatePackageSourceMessage{
 		GUID:                packageGUID,
 		SpaceGUID:           packageRecord.SpaceGUID,
-		ImageRef:            uploadedImageRef,
+		ImageRef:            bitsRecord.ImageRef,
 		RegistrySecretNames: h.registrySecretNames,
+		BlobURL:             bitsRecord.BlobURL,
+		Checksum:            bits.checksum(),
 	})
 	if err != nil {
 		return nil, apierrors.LogAndReturn(logger, err, "Error calling UpdatePackageSource")
@@ -220,6 +244,94 @@ func (h Package) upload(r *http.Request) (*routing.Response, error) {
 	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForPackage(packageRecord, h.serverURL)), nil
 }
 
+type packageUploadForm struct {
+	bits *multipart.Part
+	// checksum is the optional hex encoded sha256 digest of the bits. It is
+	// only taken into account when sent before the bits.
+	checksum string
+}
+
+func readPackageUploadForm(r *http.Request) (packageUploadForm, error) {
+	multipartReader, err := r.MultipartReader()
+	if err != nil {
+		return packageUploadForm{}, apierrors.NewInvalidRequestError(err, "Unable to parse body as multipart form")
+	}
+
+	form := packageUploadForm{}
+	for {
+		part, err := multipartReader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return packageUploadForm{}, apierrors.NewUnprocessableEntityError(err, "Upload must include bits")
+		}
+		if err != nil {
+			return packageUploadForm{}, apierrors.NewInvalidRequestError(err, "Unable to parse body as multipart form")
+		}
+
+		switch part.FormName() {
+		case "bits":
+			form.bits = part
+			return form, nil
+		case "checksum":
+			checksum, err := io.ReadAll(io.LimitReader(part, sha256.Size*2+1))
+			if err != nil {
+				return packageUploadForm{}, apierrors.NewInvalidRequestError(err, "Unable to parse body as multipart form")
+			}
+
+			if _, err = hex.DecodeString(string(checksum)); err != nil || len(checksum) != sha256.Size*2 {
+				return packageUploadForm{}, apierrors.NewUnprocessableEntityError(err, "Checksum must be a hex encoded sha256 digest")
+			}
+			form.checksum = string(checksum)
+		}
+	}
+}
+
+func (h Package) uploadError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return apierrors.NewUnprocessableEntityError(err, fmt.Sprintf("Package may not be larger than %d MB", h.maxUploadSize/(1024*1024)))
+	}
+
+	if errors.Is(err, errChecksumMismatch) {
+		return apierrors.NewUnprocessableEntityError(err, "The uploaded bits do not match the checksum")
+	}
+
+	return err
+}
+
+var errChecksumMismatch = errors.New("package bits checksum mismatch")
+
+// checksumReader computes the sha256 checksum of the bits as they are read.
+// When an expected checksum is given, reading the end of mismatching bits
+// fails instead of returning io.EOF.
+type checksumReader struct {
+	bits             io.Reader
+	hash             hash.Hash
+	expectedChecksum string
+}
+
+func newChecksumReader(bits io.Reader, expectedChecksum string) *checksumReader {
+	return &checksumReader{
+		bits:             bits,
+		hash:             sha256.New(),
+		expectedChecksum: expectedChecksum,
+	}
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.bits.Read(p)
+	r.hash.Write(p[:n])
+
+	if errors.Is(err, io.EOF) && r.expectedChecksum != "" && !strings.EqualFold(r.expectedChecksum, r.checksum()) {
+		return n, fmt.Errorf("%w: expected %s, got %s", errChecksumMismatch, r.expectedChecksum, r.checksum())
+	}
+
+	return n, err
+}
+
+func (r *checksumReader) checksum() string {
+	return hex.EncodeToString(r.hash.Sum(nil))
+}
+
 func (h Package) listDroplets(r *http.Request) (*routing.Response, error) {
 	authInfo, _ := authorization.InfoFromContext(r.Context())
 	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.package.list-droplets")
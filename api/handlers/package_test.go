@@ -2,6 +2,7 @@ package handlers_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -11,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"code.cloudfoundry.org/korifi/api/authorization"
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	. "code.cloudfoundry.org/korifi/api/handlers"
 	"code.cloudfoundry.org/korifi/api/handlers/fake"
@@ -29,7 +31,7 @@ var _ = Describe("Package", func() {
 		packageRepo                 *fake.CFPackageRepository
 		appRepo                     *fake.CFAppRepository
 		dropletRepo                 *fake.CFDropletRepository
-		imageRepo                   *fake.ImageRepository
+		packageBlobstore            *fake.PackageBlobstore
 		requestValidator            *fake.RequestValidator
 		packageImagePullSecretNames []string
 
@@ -44,7 +46,7 @@ var _ = Describe("Package", func() {
 		packageRepo = new(fake.CFPackageRepository)
 		appRepo = new(fake.CFAppRepository)
 		dropletRepo = new(fake.CFDropletRepository)
-		imageRepo = new(fake.ImageRepository)
+		packageBlobstore = new(fake.PackageBlobstore)
 		requestValidator = new(fake.RequestValidator)
 		packageImagePullSecretNames = []string{"package-image-pull-secret"}
 
@@ -59,9 +61,10 @@ var _ = Describe("Package", func() {
 			packageRepo,
 			appRepo,
 			dropletRepo,
-			imageRepo,
+			packageBlobstore,
 			requestValidator,
 			packageImagePullSecretNames,
+			1024*1024,
 		)
 
 		routerBuilder.LoadRoutes(apiHandler)
@@ -533,8 +536,12 @@ var _ = Describe("Package", func() {
 			imageRefWithDigest string
 			body               io.Reader
 			formDataHeader     string
+			uploadedBits       string
 		)
 
+		// sha256 of "the-src-file-contents"
+		const srcFileSHA256 = "4381a0c19bf52917eafe0cd04624453d05aa3e69e092f49e3ca06743e2b33421"
+
 		BeforeEach(func() {
 			packageRepo.GetPackageReturns(repositories.PackageRecord{
 				Type:      "bits",
@@ -558,10 +565,22 @@ var _ = Describe("Package", func() {
 			}, nil)
 
 			imageRefWithDigest = "some-org/the-package-guid@SHA256:some-sha-256"
-			imageRepo.UploadSourceImageReturns(imageRefWithDigest, nil)
+			uploadedBits = ""
+			packageBlobstore.UploadPackageBitsStub = func(_ context.Context, _ authorization.Info, message repositories.UploadPackageBitsMessage) (repositories.PackageBitsRecord, error) {
+				// the bits are streamed from the request, so they can only be
+				// read while the upload is in progress
+				bits, err := io.ReadAll(message.Bits)
+				if err != nil {
+					return repositories.PackageBitsRecord{}, apierrors.NewBlobstoreUnavailableError(err)
+				}
+				uploadedBits = string(bits)
+
+				return repositories.PackageBitsRecord{ImageRef: imageRefWithDigest}, nil
+			}
 
 			var b bytes.Buffer
 			writer := multipart.NewWriter(&b)
+			Expect(writer.WriteField("resources", "[]")).To(Succeed())
 			part, err := writer.CreateFormFile("bits", "unused.zip")
 			Expect(err).NotTo(HaveOccurred())
 			_, err = io.Copy(part, strings.NewReader("the-src-file-contents"))
@@ -587,16 +606,13 @@ var _ = Describe("Package", func() {
 			Expect(actualAuthInfo).To(Equal(authInfo))
 			Expect(actualPackageGUID).To(Equal(packageGUID))
 
-			Expect(imageRepo.UploadSourceImageCallCount()).To(Equal(1))
-			_, actualAuthInfo, repoRef, srcFile, actualSpaceGUID, actualTags := imageRepo.UploadSourceImageArgsForCall(0)
+			Expect(packageBlobstore.UploadPackageBitsCallCount()).To(Equal(1))
+			_, actualAuthInfo, uploadMessage := packageBlobstore.UploadPackageBitsArgsForCall(0)
 			Expect(actualAuthInfo).To(Equal(authInfo))
-			Expect(repoRef).To(Equal("registry.repo/foo"))
-			actualSrcContents, err := io.ReadAll(srcFile)
-			Expect(err).NotTo(HaveOccurred())
-			Expect(string(actualSrcContents)).To(Equal("the-src-file-contents"))
-			Expect(actualSpaceGUID).To(Equal(spaceGUID))
-			Expect(actualTags).To(HaveLen(1))
-			Expect(actualTags[0]).To(Equal(packageGUID))
+			Expect(uploadMessage.PackageGUID).To(Equal(packageGUID))
+			Expect(uploadMessage.SpaceGUID).To(Equal(spaceGUID))
+			Expect(uploadMessage.ImageRef).To(Equal("registry.repo/foo"))
+			Expect(uploadedBits).To(Equal("the-src-file-contents"))
 
 			Expect(packageRepo.UpdatePackageSourceCallCount()).To(Equal(1))
 			_, actualAuthInfo, message := packageRepo.UpdatePackageSourceArgsForCall(0)
@@ -604,6 +620,7 @@ var _ = Describe("Package", func() {
 			Expect(message.GUID).To(Equal(packageGUID))
 			Expect(message.ImageRef).To(Equal(imageRefWithDigest))
 			Expect(message.RegistrySecretNames).To(ConsistOf(packageImagePullSecretNames))
+			Expect(message.Checksum).To(Equal(srcFileSHA256))
 
 			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
 			Expect(rr).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
@@ -615,9 +632,22 @@ var _ = Describe("Package", func() {
 			)))
 		})
 
+		When("the bits are stored in a blobstore", func() {
+			BeforeEach(func() {
+				packageBlobstore.UploadPackageBitsReturns(repositories.PackageBitsRecord{BlobURL: "https://blobstore.example.org/the-package-guid.zip"}, nil)
+			})
+
+			It("sets the blob url on the package source", func() {
+				Expect(packageRepo.UpdatePackageSourceCallCount()).To(Equal(1))
+				_, _, message := packageRepo.UpdatePackageSourceArgsForCall(0)
+				Expect(message.BlobURL).To(Equal("https://blobstore.example.org/the-package-guid.zip"))
+				Expect(message.ImageRef).To(BeEmpty())
+			})
+		})
+
 		itDoesntUploadSourceImage := func() {
 			It("doesn't build an image from the source", func() {
-				Expect(imageRepo.UploadSourceImageCallCount()).To(Equal(0))
+				Expect(packageBlobstore.UploadPackageBitsCallCount()).To(Equal(0))
 			})
 		}
 
@@ -653,7 +683,7 @@ var _ = Describe("Package", func() {
 
 		When("uploading the package is forbidden", func() {
 			BeforeEach(func() {
-				imageRepo.UploadSourceImageReturns("", apierrors.NewForbiddenError(errors.New("Forbidden"), repositories.PackageResourceType))
+				packageBlobstore.UploadPackageBitsReturns(repositories.PackageBitsRecord{}, apierrors.NewForbiddenError(errors.New("Forbidden"), repositories.PackageResourceType))
 			})
 
 			It("returns an error", func() {
@@ -677,9 +707,92 @@ var _ = Describe("Package", func() {
 			itDoesntUpdateAnyPackages()
 		})
 
+		When("the checksum of the bits is given", func() {
+			withChecksum := func(checksum string) {
+				var b bytes.Buffer
+				writer := multipart.NewWriter(&b)
+				Expect(writer.WriteField("checksum", checksum)).To(Succeed())
+				part, err := writer.CreateFormFile("bits", "unused.zip")
+				Expect(err).NotTo(HaveOccurred())
+				_, err = io.Copy(part, strings.NewReader("the-src-file-contents"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(writer.Close()).To(Succeed())
+				formDataHeader = writer.FormDataContentType()
+				body = &b
+			}
+
+			BeforeEach(func() {
+				withChecksum(srcFileSHA256)
+			})
+
+			It("accepts the upload", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+				Expect(packageRepo.UpdatePackageSourceCallCount()).To(Equal(1))
+			})
+
+			When("the checksum does not match the bits", func() {
+				BeforeEach(func() {
+					withChecksum(strings.Repeat("a", 64))
+				})
+
+				It("returns an error", func() {
+					expectUnprocessableEntityError("The uploaded bits do not match the checksum")
+				})
+
+				It("fails the blobstore read of the bits before they are stored", func() {
+					Expect(packageBlobstore.UploadPackageBitsCallCount()).To(Equal(1))
+					Expect(uploadedBits).To(BeEmpty())
+				})
+				itDoesntUpdateAnyPackages()
+			})
+
+			When("the checksum is not a sha256 digest", func() {
+				BeforeEach(func() {
+					withChecksum("not-a-sha")
+				})
+
+				It("returns an error", func() {
+					expectUnprocessableEntityError("Checksum must be a hex encoded sha256 digest")
+				})
+				itDoesntUploadSourceImage()
+			})
+		})
+
+		When("the body is not a multipart form", func() {
+			BeforeEach(func() {
+				body = strings.NewReader("the-src-file-contents")
+				formDataHeader = "application/zip"
+			})
+
+			It("returns an error", func() {
+				expectErrorResponse(http.StatusBadRequest, "CF-InvalidRequest", "Unable to parse body as multipart form", 10004)
+			})
+			itDoesntUploadSourceImage()
+			itDoesntUpdateAnyPackages()
+		})
+
+		When("the bits exceed the max upload size", func() {
+			BeforeEach(func() {
+				var b bytes.Buffer
+				writer := multipart.NewWriter(&b)
+				part, err := writer.CreateFormFile("bits", "unused.zip")
+				Expect(err).NotTo(HaveOccurred())
+				_, err = io.Copy(part, strings.NewReader(strings.Repeat("a", 2*1024*1024)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(writer.Close()).To(Succeed())
+				formDataHeader = writer.FormDataContentType()
+				body = &b
+			})
+
+			It("returns an error", func() {
+				expectUnprocessableEntityError("Package may not be larger than 1 MB")
+			})
+			itDoesntUpdateAnyPackages()
+		})
+
 		When("preparing to upload the source image errors", func() {
 			BeforeEach(func() {
-				imageRepo.UploadSourceImageReturns("", errors.New("boom"))
+				packageBlobstore.UploadPackageBitsReturns(repositories.PackageBitsRecord{}, errors.New("boom"))
 			})
 
 			It("returns an error", func() {
@@ -690,7 +803,7 @@ var _ = Describe("Package", func() {
 
 		When("uploading the source image errors", func() {
 			BeforeEach(func() {
-				imageRepo.UploadSourceImageReturns("", apierrors.NewBlobstoreUnavailableError(errors.New("boom")))
+				packageBlobstore.UploadPackageBitsReturns(repositories.PackageBitsRecord{}, apierrors.NewBlobstoreUnavailableError(errors.New("boom")))
 			})
 
 			It("returns an error", func() {
@@ -131,26 +131,12 @@ func (h *Process) getSidecars(r *http.Request) (*routing.Response, error) {
 
 	processGUID := routing.URLParam(r, "guid")
 
-	_, err := h.processRepo.GetProcess(r.Context(), authInfo, processGUID)
+	process, err := h.processRepo.GetProcess(r.Context(), authInfo, processGUID)
 	if err != nil {
 		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch process from Kubernetes", "ProcessGUID", processGUID)
 	}
 
-	return routing.NewResponse(http.StatusOK).WithBody(map[string]interface{}{
-		"pagination": map[string]interface{}{
-			"total_results": 0,
-			"total_pages":   1,
-			"first": map[string]interface{}{
-				"href": fmt.Sprintf("%s/v3/processes/%s/sidecars", h.serverURL.String(), processGUID),
-			},
-			"last": map[string]interface{}{
-				"href": fmt.Sprintf("%s/v3/processes/%s/sidecars", h.serverURL.String(), processGUID),
-			},
-			"next":     nil,
-			"previous": nil,
-		},
-		"resources": []string{},
-	}), nil
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForProcessSidecarList(process, h.serverURL, *r.URL)), nil
 }
 
 func (h *Process) scale(r *http.Request) (*routing.Response, error) {
@@ -169,6 +155,14 @@ func (h *Process) scale(r *http.Request) (*routing.Response, error) {
 		return nil, apierrors.ForbiddenAsNotFound(err)
 	}
 
+	if payload.MemoryMB != nil && *payload.MemoryMB <= sidecarMemoryMB(process) {
+		return nil, apierrors.LogAndReturn(
+			logger,
+			apierrors.NewUnprocessableEntityError(nil, "The requested memory allocation is not large enough to run all of your sidecar processes."),
+			"process memory does not fit its sidecars", "processGUID", processGUID,
+		)
+	}
+
 	processRecord, err := h.processRepo.ScaleProcess(r.Context(), authInfo, repositories.ScaleProcessMessage{
 		GUID:               process.GUID,
 		SpaceGUID:          process.SpaceGUID,
@@ -181,6 +175,17 @@ func (h *Process) scale(r *http.Request) (*routing.Response, error) {
 	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForProcess(processRecord, h.serverURL)), nil
 }
 
+// sidecarMemoryMB returns the memory the sidecars of the process take from
+// the process memory
+func sidecarMemoryMB(process repositories.ProcessRecord) int64 {
+	memoryMB := int64(0)
+	for _, sidecar := range process.Sidecars {
+		memoryMB += sidecar.MemoryMB
+	}
+
+	return memoryMB
+}
+
 func (h *Process) getStats(r *http.Request) (*routing.Response, error) {
 	authInfo, _ := authorization.InfoFromContext(r.Context())
 	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.process.get-stats")
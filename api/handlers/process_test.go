@@ -93,7 +93,16 @@ var _ = Describe("Process", func() {
 
 	Describe("the GET /v3/processes/:guid/sidecars endpoint", func() {
 		BeforeEach(func() {
-			processRepo.GetProcessReturns(repositories.ProcessRecord{}, nil)
+			processRepo.GetProcessReturns(repositories.ProcessRecord{
+				GUID:    "process-guid",
+				AppGUID: "app-guid",
+				Type:    "web",
+				Sidecars: []repositories.Sidecar{{
+					Name:     "my-sidecar",
+					Command:  "run-sidecar",
+					MemoryMB: 256,
+				}},
+			}, nil)
 		})
 
 		JustBeforeEach(func() {
@@ -102,16 +111,19 @@ var _ = Describe("Process", func() {
 			routerBuilder.Build().ServeHTTP(rr, req)
 		})
 
-		It("returns the empty list of sidecars", func() {
+		It("returns the sidecars of the process", func() {
 			Expect(processRepo.GetProcessCallCount()).To(Equal(1))
-			_, actualAuthInfo, _ := processRepo.GetProcessArgsForCall(0)
+			_, actualAuthInfo, actualProcessGUID := processRepo.GetProcessArgsForCall(0)
 			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(actualProcessGUID).To(Equal("process-guid"))
 
 			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
 			Expect(rr).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
 			Expect(rr).To(HaveHTTPBody(SatisfyAll(
-				MatchJSONPath("$.pagination.total_results", BeZero()),
+				MatchJSONPath("$.pagination.total_results", BeEquivalentTo(1)),
 				MatchJSONPath("$.pagination.first.href", "https://api.example.org/v3/processes/process-guid/sidecars"),
+				MatchJSONPath("$.resources[0].name", "my-sidecar"),
+				MatchJSONPath("$.resources[0].process_types", ConsistOf("web")),
 			)))
 		})
 
@@ -223,6 +235,27 @@ var _ = Describe("Process", func() {
 			})
 		})
 
+		When("the memory does not fit the sidecars of the process", func() {
+			BeforeEach(func() {
+				processRepo.GetProcessReturns(repositories.ProcessRecord{
+					GUID:      "process-guid",
+					SpaceGUID: spaceGUID,
+					Sidecars: []repositories.Sidecar{
+						{Name: "my-sidecar", MemoryMB: 256},
+						{Name: "other-sidecar", MemoryMB: 256},
+					},
+				}, nil)
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("The requested memory allocation is not large enough to run all of your sidecar processes.")
+			})
+
+			It("does not scale the process", func() {
+				Expect(processRepo.ScaleProcessCallCount()).To(BeZero())
+			})
+		})
+
 		When("scaling errors", func() {
 			BeforeEach(func() {
 				processRepo.ScaleProcessReturns(repositories.ProcessRecord{}, errors.New("unknown!"))
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+)
+
+const (
+	ReadyzPath = "/readyz"
+)
+
+// Readiness reports the API as not ready once it starts draining, so that
+// the API endpoints stop routing new requests to it before it shuts down
+type Readiness struct {
+	draining atomic.Bool
+}
+
+func NewReadiness() *Readiness {
+	return &Readiness{}
+}
+
+func (h *Readiness) Drain() {
+	h.draining.Store(true)
+}
+
+// Serve serves the readiness endpoint and passes any other request to next.
+// Readiness is served outside of the router, so that the frequent probes
+// neither go through the API middleware nor fill the access log.
+func (h *Readiness) Serve(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != ReadyzPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (h *Readiness) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status, body := http.StatusOK, "ok"
+	if h.draining.Load() {
+		status, body = http.StatusServiceUnavailable, "draining"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": body})
+}
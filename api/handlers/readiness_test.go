@@ -0,0 +1,61 @@
+package handlers_test
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/korifi/api/handlers"
+	. "code.cloudfoundry.org/korifi/tests/matchers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Readiness", func() {
+	var (
+		apiHandler *handlers.Readiness
+		next       http.Handler
+		req        *http.Request
+	)
+
+	BeforeEach(func() {
+		apiHandler = handlers.NewReadiness()
+		next = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})
+
+		var err error
+		req, err = http.NewRequest("GET", "/readyz", nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		apiHandler.Serve(next).ServeHTTP(rr, req)
+	})
+
+	It("reports the API as ready", func() {
+		Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+		Expect(rr).To(HaveHTTPBody(MatchJSONPath("$.status", "ok")))
+	})
+
+	When("the request is not a readiness probe", func() {
+		BeforeEach(func() {
+			var err error
+			req, err = http.NewRequest("GET", "/v3/apps", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("passes the request on", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusTeapot))
+		})
+	})
+
+	When("the API is draining", func() {
+		BeforeEach(func() {
+			apiHandler.Drain()
+		})
+
+		It("reports the API as not ready", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusServiceUnavailable))
+			Expect(rr).To(HaveHTTPBody(MatchJSONPath("$.status", "draining")))
+		})
+	})
+})
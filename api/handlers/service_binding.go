@@ -16,8 +16,9 @@ import (
 )
 
 const (
-	ServiceBindingsPath = "/v3/service_credential_bindings"
-	ServiceBindingPath  = "/v3/service_credential_bindings/{guid}"
+	ServiceBindingsPath                 = "/v3/service_credential_bindings"
+	ServiceBindingPath                  = "/v3/service_credential_bindings/{guid}"
+	ServiceBindingRotateCredentialsPath = "/v3/service_credential_bindings/{guid}/actions/rotate_credentials"
 )
 
 type ServiceBinding struct {
@@ -35,6 +36,7 @@ type CFServiceBindingRepository interface {
 	ListServiceBindings(context.Context, authorization.Info, repositories.ListServiceBindingsMessage) ([]repositories.ServiceBindingRecord, error)
 	GetServiceBinding(context.Context, authorization.Info, string) (repositories.ServiceBindingRecord, error)
 	UpdateServiceBinding(context.Context, authorization.Info, repositories.UpdateServiceBindingMessage) (repositories.ServiceBindingRecord, error)
+	RotateServiceBindingCredentials(context.Context, authorization.Info, string) (repositories.ServiceBindingRecord, error)
 }
 
 func NewServiceBinding(serverURL url.URL, serviceBindingRepo CFServiceBindingRepository, appRepo CFAppRepository, serviceInstanceRepo CFServiceInstanceRepository, requestValidator RequestValidator) *ServiceBinding {
@@ -165,6 +167,25 @@ func (h *ServiceBinding) get(r *http.Request) (*routing.Response, error) {
 	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForServiceBinding(serviceBinding, h.serverURL)), nil
 }
 
+func (h *ServiceBinding) rotateCredentials(r *http.Request) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.service-binding.rotate-credentials")
+
+	serviceBindingGUID := routing.URLParam(r, "guid")
+
+	_, err := h.serviceBindingRepo.GetServiceBinding(r.Context(), authInfo, serviceBindingGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Error getting service binding in repository")
+	}
+
+	serviceBinding, err := h.serviceBindingRepo.RotateServiceBindingCredentials(r.Context(), authInfo, serviceBindingGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Error rotating service binding credentials in repository")
+	}
+
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForServiceBinding(serviceBinding, h.serverURL)), nil
+}
+
 func (h *ServiceBinding) UnauthenticatedRoutes() []routing.Route {
 	return nil
 }
@@ -176,5 +197,6 @@ func (h *ServiceBinding) AuthenticatedRoutes() []routing.Route {
 		{Method: "DELETE", Pattern: ServiceBindingPath, Handler: h.delete},
 		{Method: "PATCH", Pattern: ServiceBindingPath, Handler: h.update},
 		{Method: "GET", Pattern: ServiceBindingPath, Handler: h.get},
+		{Method: "POST", Pattern: ServiceBindingRotateCredentialsPath, Handler: h.rotateCredentials},
 	}
 }
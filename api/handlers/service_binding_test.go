@@ -414,4 +414,54 @@ var _ = Describe("ServiceBinding", func() {
 			})
 		})
 	})
+
+	Describe("POST /v3/service_credential_bindings/:guid/actions/rotate_credentials", func() {
+		BeforeEach(func() {
+			requestMethod = "POST"
+			requestPath = "/v3/service_credential_bindings/service-binding-guid/actions/rotate_credentials"
+
+			serviceBindingRepo.RotateServiceBindingCredentialsReturns(repositories.ServiceBindingRecord{
+				GUID: "service-binding-guid",
+				LastOperation: repositories.ServiceBindingLastOperation{
+					Type:  "update",
+					State: "in progress",
+				},
+			}, nil)
+		})
+
+		It("rotates the service binding credentials", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.guid", "service-binding-guid"),
+				MatchJSONPath("$.last_operation.type", "update"),
+				MatchJSONPath("$.last_operation.state", "in progress"),
+			)))
+
+			Expect(serviceBindingRepo.RotateServiceBindingCredentialsCallCount()).To(Equal(1))
+			_, actualAuthInfo, guid := serviceBindingRepo.RotateServiceBindingCredentialsArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(guid).To(Equal("service-binding-guid"))
+		})
+
+		When("getting the service binding is forbidden", func() {
+			BeforeEach(func() {
+				serviceBindingRepo.GetServiceBindingReturns(repositories.ServiceBindingRecord{}, apierrors.NewForbiddenError(nil, repositories.ServiceBindingResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError(repositories.ServiceBindingResourceType)
+			})
+		})
+
+		When("rotating the credentials fails", func() {
+			BeforeEach(func() {
+				serviceBindingRepo.RotateServiceBindingCredentialsReturns(repositories.ServiceBindingRecord{}, errors.New("rotate-sb-error"))
+			})
+
+			It("returns an error", func() {
+				expectUnknownError()
+			})
+		})
+	})
 })
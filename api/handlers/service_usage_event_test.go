@@ -0,0 +1,135 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	. "code.cloudfoundry.org/korifi/tests/matchers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ServiceUsageEvent", func() {
+	var (
+		serviceUsageEventRepo *fake.CFServiceUsageEventRepository
+		requestValidator      *fake.RequestValidator
+		req                   *http.Request
+	)
+
+	BeforeEach(func() {
+		serviceUsageEventRepo = new(fake.CFServiceUsageEventRepository)
+		requestValidator = new(fake.RequestValidator)
+
+		apiHandler := handlers.NewServiceUsageEvent(*serverURL, serviceUsageEventRepo, requestValidator)
+		routerBuilder.LoadRoutes(apiHandler)
+	})
+
+	JustBeforeEach(func() {
+		routerBuilder.Build().ServeHTTP(rr, req)
+	})
+
+	Describe("GET /v3/service_usage_events", func() {
+		BeforeEach(func() {
+			serviceUsageEventRepo.ListServiceUsageEventsReturns([]repositories.ServiceUsageEventRecord{
+				{GUID: "service-usage-event-1", State: "CREATED"},
+			}, nil)
+
+			requestValidator.DecodeAndValidateURLValuesStub = decodeAndValidateURLValuesStub(&payloads.ServiceUsageEventList{
+				AfterGUID: "service-usage-event-0",
+			})
+
+			var err error
+			req, err = http.NewRequestWithContext(ctx, http.MethodGet, "/v3/service_usage_events?after_guid=service-usage-event-0", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("lists the service usage events", func() {
+			Expect(serviceUsageEventRepo.ListServiceUsageEventsCallCount()).To(Equal(1))
+			_, actualAuthInfo, listMessage := serviceUsageEventRepo.ListServiceUsageEventsArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(listMessage).To(Equal(repositories.ListServiceUsageEventsMessage{
+				AfterGUID: "service-usage-event-0",
+			}))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.pagination.total_results", BeEquivalentTo(1)),
+				MatchJSONPath("$.resources[0].guid", "service-usage-event-1"),
+				MatchJSONPath("$.resources[0].state", "CREATED"),
+			)))
+		})
+
+		When("the request is invalid", func() {
+			BeforeEach(func() {
+				requestValidator.DecodeAndValidateURLValuesReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				expectUnknownError()
+			})
+		})
+
+		When("listing the service usage events fails", func() {
+			BeforeEach(func() {
+				serviceUsageEventRepo.ListServiceUsageEventsReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an Internal Server Error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+
+	Describe("GET /v3/service_usage_events/{guid}", func() {
+		BeforeEach(func() {
+			serviceUsageEventRepo.GetServiceUsageEventReturns(repositories.ServiceUsageEventRecord{
+				GUID:  "service-usage-event-guid",
+				State: "DELETED",
+			}, nil)
+
+			var err error
+			req, err = http.NewRequestWithContext(ctx, http.MethodGet, "/v3/service_usage_events/service-usage-event-guid", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the service usage event", func() {
+			Expect(serviceUsageEventRepo.GetServiceUsageEventCallCount()).To(Equal(1))
+			_, actualAuthInfo, actualGUID := serviceUsageEventRepo.GetServiceUsageEventArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(actualGUID).To(Equal("service-usage-event-guid"))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.guid", "service-usage-event-guid"),
+				MatchJSONPath("$.state", "DELETED"),
+				MatchJSONPath("$.links.self.href", "https://api.example.org/v3/service_usage_events/service-usage-event-guid"),
+			)))
+		})
+
+		When("the service usage event does not exist", func() {
+			BeforeEach(func() {
+				serviceUsageEventRepo.GetServiceUsageEventReturns(repositories.ServiceUsageEventRecord{}, apierrors.NewNotFoundError(nil, repositories.ServiceUsageEventResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError(repositories.ServiceUsageEventResourceType)
+			})
+		})
+
+		When("the user is not allowed to read service usage events", func() {
+			BeforeEach(func() {
+				serviceUsageEventRepo.GetServiceUsageEventReturns(repositories.ServiceUsageEventRecord{}, apierrors.NewForbiddenError(nil, repositories.ServiceUsageEventResourceType))
+			})
+
+			It("returns a not authorized error", func() {
+				expectNotAuthorizedError()
+			})
+		})
+	})
+})
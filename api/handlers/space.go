@@ -119,7 +119,7 @@ func (h *Space) delete(r *http.Request) (*routing.Response, error) {
 
 	spaceRecord, err := h.spaceRepo.GetSpace(r.Context(), authInfo, spaceGUID)
 	if err != nil {
-		return nil, apierrors.LogAndReturn(logger, err, "Failed to fetch space", "SpaceGUID", spaceGUID)
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch space", "SpaceGUID", spaceGUID)
 	}
 
 	deleteSpaceMessage := repositories.DeleteSpaceMessage{
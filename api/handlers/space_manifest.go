@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"net/http"
 	"net/url"
 
@@ -61,6 +63,17 @@ func (h *SpaceManifest) apply(r *http.Request) (*routing.Response, error) {
 	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.space-manifest.apply")
 
 	spaceGUID := routing.URLParam(r, "spaceGUID")
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to read payload")
+	}
+	body, err = payloads.InterpolateManifestVariables(body)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to interpolate manifest variables")
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
 	var manifest payloads.Manifest
 	if err := h.requestValidator.DecodeAndValidateYAMLPayload(r, &manifest); err != nil {
 		return nil, apierrors.LogAndReturn(logger, err, "failed to decode payload")
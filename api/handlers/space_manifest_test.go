@@ -24,11 +24,13 @@ var _ = Describe("SpaceManifest", func() {
 		requestValidator *fake.RequestValidator
 		requestMethod    string
 		requestPath      string
+		requestBody      string
 	)
 
 	BeforeEach(func() {
 		requestMethod = "POST"
 		requestPath = ""
+		requestBody = "the-yaml-body"
 
 		manifestApplier = new(fake.ManifestApplier)
 		spaceRepo = new(fake.CFSpaceRepository)
@@ -44,7 +46,7 @@ var _ = Describe("SpaceManifest", func() {
 	})
 
 	JustBeforeEach(func() {
-		req, err := http.NewRequestWithContext(ctx, requestMethod, requestPath, strings.NewReader("the-yaml-body"))
+		req, err := http.NewRequestWithContext(ctx, requestMethod, requestPath, strings.NewReader(requestBody))
 		Expect(err).NotTo(HaveOccurred())
 		req.Header.Add("Content-type", "application/x-yaml")
 		routerBuilder.Build().ServeHTTP(rr, req)
@@ -106,6 +108,29 @@ var _ = Describe("SpaceManifest", func() {
 			Expect(payload.Applications[0].Processes[0].Timeout).To(PointTo(Equal(int32(10))))
 		})
 
+		When("the manifest has variables", func() {
+			BeforeEach(func() {
+				requestBody = "applications:\n- name: ((name))\n---\nname: app1\n"
+			})
+
+			It("validates the interpolated manifest", func() {
+				Expect(requestValidator.DecodeAndValidateYAMLPayloadCallCount()).To(Equal(1))
+				actualReq, _ := requestValidator.DecodeAndValidateYAMLPayloadArgsForCall(0)
+				Expect(bodyString(actualReq)).To(MatchYAML("applications:\n- name: app1\n"))
+			})
+
+			When("variables are missing", func() {
+				BeforeEach(func() {
+					requestBody = "applications:\n- name: ((name))\n---\nother: value\n"
+				})
+
+				It("returns an unprocessable entity error", func() {
+					expectUnprocessableEntityError("Expected to find variables: name")
+					Expect(manifestApplier.ApplyCallCount()).To(BeZero())
+				})
+			})
+		})
+
 		When("the manifest is invalid", func() {
 			BeforeEach(func() {
 				requestValidator.DecodeAndValidateYAMLPayloadReturns(errors.New("boom"))
@@ -210,6 +210,27 @@ var _ = Describe("Space", func() {
 			})
 		})
 
+		When("the user cannot see the space", func() {
+			BeforeEach(func() {
+				spaceRepo.GetSpaceReturns(repositories.SpaceRecord{}, apierrors.NewForbiddenError(nil, repositories.SpaceResourceType))
+			})
+
+			It("returns a not found error and does not delete the space", func() {
+				expectNotFoundError(repositories.SpaceResourceType)
+				Expect(spaceRepo.DeleteSpaceCallCount()).To(BeZero())
+			})
+		})
+
+		When("the user is not allowed to delete the space", func() {
+			BeforeEach(func() {
+				spaceRepo.DeleteSpaceReturns(apierrors.NewForbiddenError(nil, repositories.SpaceResourceType))
+			})
+
+			It("returns a forbidden error", func() {
+				expectNotAuthorizedError()
+			})
+		})
+
 		When("deleting the space errors", func() {
 			BeforeEach(func() {
 				spaceRepo.DeleteSpaceReturns(errors.New("boom"))
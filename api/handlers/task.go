@@ -155,6 +155,15 @@ func (h *Task) cancel(r *http.Request) (*routing.Response, error) {
 	return routing.NewResponse(http.StatusAccepted).WithBody(presenter.ForTask(taskRecord, h.serverURL)), nil
 }
 
+func (h *Task) cancelDeprecated(r *http.Request) (*routing.Response, error) {
+	response, err := h.cancel(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.WithWarning("PUT /v3/tasks/:guid/cancel is deprecated, use POST /v3/tasks/:guid/actions/cancel instead"), nil
+}
+
 //nolint:dupl
 func (h *Task) update(r *http.Request) (*routing.Response, error) {
 	authInfo, _ := authorization.InfoFromContext(r.Context())
@@ -190,6 +199,6 @@ func (h *Task) AuthenticatedRoutes() []routing.Route {
 		{Method: "POST", Pattern: TasksPath, Handler: h.create},
 		{Method: "GET", Pattern: TasksPath, Handler: h.listForApp},
 		{Method: "POST", Pattern: TaskCancelPath, Handler: h.cancel},
-		{Method: "PUT", Pattern: TaskCancelPathDeprecated, Handler: h.cancel},
+		{Method: "PUT", Pattern: TaskCancelPathDeprecated, Handler: h.cancelDeprecated},
 	}
 }
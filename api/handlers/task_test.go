@@ -457,6 +457,10 @@ var _ = Describe("Task", func() {
 				MatchJSONPath("$.links.self.href", "https://api.example.org/v3/tasks/the-task-guid"),
 			)))
 		})
+
+		It("warns that the endpoint is deprecated", func() {
+			Expect(rr).To(HaveHTTPHeaderWithValue("X-Cf-Warnings", ContainSubstring("deprecated")))
+		})
 	})
 
 	Describe("PATCH /v3/tasks/:guid", func() {
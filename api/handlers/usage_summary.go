@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/api/routing"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	OrgUsageSummaryPath  = "/v3/organizations/{guid}/usage_summary"
+	InfoUsageSummaryPath = "/v3/info/usage_summary"
+)
+
+//counterfeiter:generate -o fake -fake-name CFUsageSummaryRepository . CFUsageSummaryRepository
+type CFUsageSummaryRepository interface {
+	GetOrgUsageSummary(context.Context, authorization.Info, string) (repositories.UsageSummaryRecord, error)
+	GetUsageSummary(context.Context, authorization.Info) (repositories.UsageSummaryRecord, error)
+}
+
+// UsageSummary serves the started instances and memory of the spaces the user
+// can see, which capacity dashboards poll
+type UsageSummary struct {
+	serverURL        url.URL
+	orgRepo          CFOrgRepository
+	usageSummaryRepo CFUsageSummaryRepository
+}
+
+func NewUsageSummary(
+	serverURL url.URL,
+	orgRepo CFOrgRepository,
+	usageSummaryRepo CFUsageSummaryRepository,
+) *UsageSummary {
+	return &UsageSummary{
+		serverURL:        serverURL,
+		orgRepo:          orgRepo,
+		usageSummaryRepo: usageSummaryRepo,
+	}
+}
+
+func (h *UsageSummary) getForOrg(r *http.Request) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.usage-summary.get-for-org")
+
+	orgGUID := routing.URLParam(r, "guid")
+
+	if _, err := h.orgRepo.GetOrg(r.Context(), authInfo, orgGUID); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to get org", "guid", orgGUID)
+	}
+
+	usageSummary, err := h.usageSummaryRepo.GetOrgUsageSummary(r.Context(), authInfo, orgGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to get org usage summary", "guid", orgGUID)
+	}
+
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForOrgUsageSummary(usageSummary, orgGUID, h.serverURL)), nil
+}
+
+func (h *UsageSummary) get(r *http.Request) (*routing.Response, error) {
+	authInfo, _ := authorization.InfoFromContext(r.Context())
+	logger := logr.FromContextOrDiscard(r.Context()).WithName("handlers.usage-summary.get")
+
+	usageSummary, err := h.usageSummaryRepo.GetUsageSummary(r.Context(), authInfo)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to get usage summary")
+	}
+
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForUsageSummary(usageSummary, h.serverURL)), nil
+}
+
+func (h *UsageSummary) UnauthenticatedRoutes() []routing.Route {
+	return nil
+}
+
+func (h *UsageSummary) AuthenticatedRoutes() []routing.Route {
+	return []routing.Route{
+		{Method: "GET", Pattern: OrgUsageSummaryPath, Handler: h.getForOrg},
+		{Method: "GET", Pattern: InfoUsageSummaryPath, Handler: h.get},
+	}
+}
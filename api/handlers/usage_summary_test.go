@@ -0,0 +1,125 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	. "code.cloudfoundry.org/korifi/tests/matchers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UsageSummary", func() {
+	var (
+		orgRepo          *fake.CFOrgRepository
+		usageSummaryRepo *fake.CFUsageSummaryRepository
+		req              *http.Request
+	)
+
+	BeforeEach(func() {
+		orgRepo = new(fake.CFOrgRepository)
+		usageSummaryRepo = new(fake.CFUsageSummaryRepository)
+
+		apiHandler := handlers.NewUsageSummary(*serverURL, orgRepo, usageSummaryRepo)
+		routerBuilder.LoadRoutes(apiHandler)
+	})
+
+	JustBeforeEach(func() {
+		routerBuilder.Build().ServeHTTP(rr, req)
+	})
+
+	Describe("GET /v3/organizations/{guid}/usage_summary", func() {
+		BeforeEach(func() {
+			orgRepo.GetOrgReturns(repositories.OrgRecord{GUID: "org-guid"}, nil)
+			usageSummaryRepo.GetOrgUsageSummaryReturns(repositories.UsageSummaryRecord{
+				StartedInstances: 3,
+				MemoryMB:         1536,
+			}, nil)
+
+			var err error
+			req, err = http.NewRequestWithContext(ctx, http.MethodGet, "/v3/organizations/org-guid/usage_summary", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the usage summary of the org", func() {
+			Expect(orgRepo.GetOrgCallCount()).To(Equal(1))
+			_, actualAuthInfo, actualOrgGUID := orgRepo.GetOrgArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(actualOrgGUID).To(Equal("org-guid"))
+
+			Expect(usageSummaryRepo.GetOrgUsageSummaryCallCount()).To(Equal(1))
+			_, actualAuthInfo, actualOrgGUID = usageSummaryRepo.GetOrgUsageSummaryArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(actualOrgGUID).To(Equal("org-guid"))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.usage_summary.started_instances", BeEquivalentTo(3)),
+				MatchJSONPath("$.usage_summary.memory_in_mb", BeEquivalentTo(1536)),
+				MatchJSONPath("$.links.self.href", "https://api.example.org/v3/organizations/org-guid/usage_summary"),
+			)))
+		})
+
+		When("the org is not visible", func() {
+			BeforeEach(func() {
+				orgRepo.GetOrgReturns(repositories.OrgRecord{}, apierrors.NewNotFoundError(nil, repositories.OrgResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError(repositories.OrgResourceType)
+				Expect(usageSummaryRepo.GetOrgUsageSummaryCallCount()).To(BeZero())
+			})
+		})
+
+		When("getting the usage summary fails", func() {
+			BeforeEach(func() {
+				usageSummaryRepo.GetOrgUsageSummaryReturns(repositories.UsageSummaryRecord{}, errors.New("boom"))
+			})
+
+			It("returns an Internal Server Error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+
+	Describe("GET /v3/info/usage_summary", func() {
+		BeforeEach(func() {
+			usageSummaryRepo.GetUsageSummaryReturns(repositories.UsageSummaryRecord{
+				StartedInstances: 5,
+				MemoryMB:         2048,
+			}, nil)
+
+			var err error
+			req, err = http.NewRequestWithContext(ctx, http.MethodGet, "/v3/info/usage_summary", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the usage summary of the visible spaces", func() {
+			Expect(usageSummaryRepo.GetUsageSummaryCallCount()).To(Equal(1))
+			_, actualAuthInfo := usageSummaryRepo.GetUsageSummaryArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.usage_summary.started_instances", BeEquivalentTo(5)),
+				MatchJSONPath("$.usage_summary.memory_in_mb", BeEquivalentTo(2048)),
+				MatchJSONPath("$.links.self.href", "https://api.example.org/v3/info/usage_summary"),
+			)))
+		})
+
+		When("getting the usage summary fails", func() {
+			BeforeEach(func() {
+				usageSummaryRepo.GetUsageSummaryReturns(repositories.UsageSummaryRecord{}, errors.New("boom"))
+			})
+
+			It("returns an Internal Server Error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+})
@@ -3,10 +3,12 @@ package handlers
 import (
 	"net/http"
 	"net/url"
-	"strings"
 
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/payloads"
 	"code.cloudfoundry.org/korifi/api/presenter"
 	"code.cloudfoundry.org/korifi/api/routing"
+	"github.com/go-logr/logr"
 )
 
 const (
@@ -14,20 +16,28 @@ const (
 )
 
 type User struct {
-	apiBaseURL url.URL
+	apiBaseURL       url.URL
+	requestValidator RequestValidator
 }
 
-func NewUser(apiBaseURL url.URL) User {
+func NewUser(apiBaseURL url.URL, requestValidator RequestValidator) User {
 	return User{
-		apiBaseURL: apiBaseURL,
+		apiBaseURL:       apiBaseURL,
+		requestValidator: requestValidator,
 	}
 }
 
 func (h User) list(req *http.Request) (*routing.Response, error) {
-	usernames := req.URL.Query().Get("usernames")
+	logger := logr.FromContextOrDiscard(req.Context()).WithName("handlers.user.list")
+
+	payload := new(payloads.UserList)
+	if err := h.requestValidator.DecodeAndValidateURLValues(req, payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Unable to decode request query parameters")
+	}
+
 	users := []string{}
-	if len(usernames) > 0 {
-		users = strings.Split(usernames, ",")
+	if len(payload.Usernames) > 0 {
+		users = payload.Usernames
 	}
 	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForList(presenter.ForUser, users, h.apiBaseURL, *req.URL)), nil
 }
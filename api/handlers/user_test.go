@@ -1,20 +1,28 @@
 package handlers_test
 
 import (
+	"errors"
 	"net/http"
 
 	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/payloads"
 	. "code.cloudfoundry.org/korifi/tests/matchers"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("User", func() {
-	var query string
+	var (
+		query            string
+		requestValidator *fake.RequestValidator
+	)
 
 	BeforeEach(func() {
 		query = ""
-		userHandler := handlers.NewUser(*serverURL)
+		requestValidator = new(fake.RequestValidator)
+		requestValidator.DecodeAndValidateURLValuesStub = decodeAndValidateURLValuesStub(&payloads.UserList{})
+		userHandler := handlers.NewUser(*serverURL, requestValidator)
 		routerBuilder.LoadRoutes(userHandler)
 	})
 
@@ -39,6 +47,9 @@ var _ = Describe("User", func() {
 		When("usernames are passed", func() {
 			BeforeEach(func() {
 				query = "?usernames=foo,bar"
+				requestValidator.DecodeAndValidateURLValuesStub = decodeAndValidateURLValuesStub(&payloads.UserList{
+					Usernames: []string{"foo", "bar"},
+				})
 			})
 
 			It("returns a list of users matching the usernames", func() {
@@ -52,5 +63,15 @@ var _ = Describe("User", func() {
 				)))
 			})
 		})
+
+		When("the request is invalid", func() {
+			BeforeEach(func() {
+				requestValidator.DecodeAndValidateURLValuesReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				expectUnknownError()
+			})
+		})
 	})
 })
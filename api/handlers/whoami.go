@@ -4,10 +4,12 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"slices"
 
 	"code.cloudfoundry.org/korifi/api/authorization"
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
 	"code.cloudfoundry.org/korifi/api/routing"
 	"github.com/go-logr/logr"
 )
@@ -24,12 +26,14 @@ type IdentityProvider interface {
 
 type WhoAmI struct {
 	identityProvider IdentityProvider
+	roleRepo         CFRoleRepository
 	apiBaseURL       url.URL
 }
 
-func NewWhoAmI(identityProvider IdentityProvider, apiBaseURL url.URL) *WhoAmI {
+func NewWhoAmI(identityProvider IdentityProvider, roleRepo CFRoleRepository, apiBaseURL url.URL) *WhoAmI {
 	return &WhoAmI{
 		identityProvider: identityProvider,
+		roleRepo:         roleRepo,
 		apiBaseURL:       apiBaseURL,
 	}
 }
@@ -43,7 +47,18 @@ func (h *WhoAmI) whoAmI(r *http.Request) (*routing.Response, error) {
 		return nil, apierrors.LogAndReturn(logger, err, "failed to get identity")
 	}
 
-	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForWhoAmI(identity)), nil
+	roles, err := h.roleRepo.ListRoles(r.Context(), authInfo)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to list roles")
+	}
+
+	// the role users of service accounts are named like their identities,
+	// i.e. system:serviceaccount:<namespace>:<name>
+	identityRoles := slices.DeleteFunc(roles, func(role repositories.RoleRecord) bool {
+		return role.User != identity.Name || role.Kind != identity.Kind
+	})
+
+	return routing.NewResponse(http.StatusOK).WithBody(presenter.ForWhoAmI(identity, authInfo.ExpiresAt(), identityRoles)), nil
 }
 
 func (h *WhoAmI) UnauthenticatedRoutes() []routing.Route {
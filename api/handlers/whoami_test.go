@@ -7,6 +7,7 @@ import (
 	"code.cloudfoundry.org/korifi/api/authorization"
 	"code.cloudfoundry.org/korifi/api/handlers"
 	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/repositories"
 	. "code.cloudfoundry.org/korifi/tests/matchers"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -18,13 +19,21 @@ var _ = Describe("WhoAmI", func() {
 	var (
 		apiHandler       *handlers.WhoAmI
 		identityProvider *fake.IdentityProvider
+		roleRepo         *fake.CFRoleRepository
 	)
 
 	BeforeEach(func() {
 		identityProvider = new(fake.IdentityProvider)
 		identityProvider.GetIdentityReturns(authorization.Identity{Name: "the-user", Kind: rbacv1.UserKind}, nil)
 		ctx = authorization.NewContext(ctx, &authorization.Info{Token: "the-token"})
-		apiHandler = handlers.NewWhoAmI(identityProvider, *serverURL)
+		roleRepo = new(fake.CFRoleRepository)
+		roleRepo.ListRolesReturns([]repositories.RoleRecord{
+			{GUID: "org-role-guid", Type: "organization_manager", User: "the-user", Kind: rbacv1.UserKind, Org: "org-guid"},
+			{GUID: "space-role-guid", Type: "space_developer", User: "the-user", Kind: rbacv1.UserKind, Space: "space-guid"},
+			{GUID: "other-user-role-guid", Type: "space_developer", User: "another-user", Kind: rbacv1.UserKind, Space: "space-guid"},
+			{GUID: "service-account-role-guid", Type: "space_developer", User: "the-user", Kind: rbacv1.ServiceAccountKind, Space: "space-guid"},
+		}, nil)
+		apiHandler = handlers.NewWhoAmI(identityProvider, roleRepo, *serverURL)
 		routerBuilder.LoadRoutes(apiHandler)
 	})
 
@@ -46,9 +55,52 @@ var _ = Describe("WhoAmI", func() {
 			Expect(rr).To(HaveHTTPBody(SatisfyAll(
 				MatchJSONPath("$.name", "the-user"),
 				MatchJSONPath("$.kind", "User"),
+				MatchJSONPath("$.expires_at", BeNil()),
 			)))
 		})
 
+		It("returns the org and space roles of the identity", func() {
+			Expect(roleRepo.ListRolesCallCount()).To(Equal(1))
+			_, actualAuthInfo := roleRepo.ListRolesArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authorization.Info{Token: "the-token"}))
+
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.roles[*].guid", ConsistOf("org-role-guid", "space-role-guid")),
+				MatchJSONPath("$.roles[0].organization_guid", "org-guid"),
+				MatchJSONPath("$.roles[1].space_guid", "space-guid"),
+			)))
+		})
+
+		When("the identity is a service account", func() {
+			BeforeEach(func() {
+				identityProvider.GetIdentityReturns(authorization.Identity{
+					Name: authorization.ServiceAccountUserName("space-guid", "the-user"),
+					Kind: rbacv1.ServiceAccountKind,
+				}, nil)
+				roleRepo.ListRolesReturns([]repositories.RoleRecord{
+					{GUID: "user-role-guid", Type: "space_developer", User: "the-user", Kind: rbacv1.UserKind, Space: "space-guid"},
+					{GUID: "service-account-role-guid", Type: "space_developer", User: "system:serviceaccount:space-guid:the-user", Kind: rbacv1.ServiceAccountKind, Space: "space-guid"},
+					{GUID: "other-service-account-role-guid", Type: "space_developer", User: "system:serviceaccount:other-space-guid:the-user", Kind: rbacv1.ServiceAccountKind, Space: "space-guid"},
+				}, nil)
+			})
+
+			It("returns the roles of the service account", func() {
+				Expect(rr).To(HaveHTTPBody(
+					MatchJSONPath("$.roles[*].guid", ConsistOf("service-account-role-guid")),
+				))
+			})
+		})
+
+		When("listing roles fails", func() {
+			BeforeEach(func() {
+				roleRepo.ListRolesReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an unknown response", func() {
+				expectUnknownError()
+			})
+		})
+
 		When("the identity provider returns an error", func() {
 			BeforeEach(func() {
 				identityProvider.GetIdentityReturns(authorization.Identity{}, errors.New("boom"))
@@ -3,12 +3,16 @@ package main
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 	"time"
 
 	"code.cloudfoundry.org/korifi/api/actions"
@@ -28,6 +32,7 @@ import (
 	"code.cloudfoundry.org/korifi/tools/image"
 	"code.cloudfoundry.org/korifi/tools/k8s"
 	toolsregistry "code.cloudfoundry.org/korifi/tools/registry"
+	"code.cloudfoundry.org/korifi/tools/s3"
 	"code.cloudfoundry.org/korifi/version"
 
 	chiMiddlewares "github.com/go-chi/chi/middleware"
@@ -42,12 +47,16 @@ import (
 	"k8s.io/klog/v2"
 	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
-var conditionTimeout = time.Second * 120
+var (
+	conditionTimeout = time.Second * 120
+	promQLTimeout    = time.Second * 30
+)
 
 func init() {
 	utilruntime.Must(korifiv1alpha1.AddToScheme(scheme.Scheme))
@@ -66,6 +75,7 @@ func main() {
 		panic(errorMessage)
 	}
 	payloads.DefaultLifecycleConfig = cfg.DefaultLifecycleConfig
+	payloads.AssignableRoles.Set(cfg.AssignableRoles())
 	k8sClientConfig := cfg.GenerateK8sClientConfig(ctrl.GetConfigOrDie())
 
 	logger, atomicLevel, err := tools.NewZapLogger(cfg.LogLevel)
@@ -77,14 +87,17 @@ func main() {
 
 	eventChan := make(chan string)
 	go func() {
-		ctrl.Log.Info("starting to watch config file at "+configPath+" for logger level changes", "currentLevel", atomicLevel.Level())
+		ctrl.Log.Info("starting to watch config file at "+configPath+" for changes", "currentLevel", atomicLevel.Level())
 		if err2 := tools.WatchForConfigChangeEvents(context.Background(), configPath, ctrl.Log, eventChan); err2 != nil {
-			ctrl.Log.Error(err2, "error watching logging config")
+			ctrl.Log.Error(err2, "error watching config")
 			os.Exit(1)
 		}
 	}()
 
-	go tools.SyncLogLevel(context.Background(), ctrl.Log, eventChan, atomicLevel, config.GetLogLevelFromPath)
+	reloader := config.NewReloader(cfg, atomicLevel, ctrl.Log)
+	reloader.OnReload(func(_ context.Context, cfg *config.APIConfig) {
+		payloads.AssignableRoles.Set(cfg.AssignableRoles())
+	})
 
 	ctrl.Log.Info("starting Korifi API", "version", version.Version)
 
@@ -101,7 +114,6 @@ func main() {
 	if err != nil {
 		panic(fmt.Sprintf("could not create dynamic k8s client: %v", err))
 	}
-	namespaceRetriever := repositories.NewNamespaceRetriever(dynamicClient)
 
 	httpClient, err := rest.HTTPClientFor(k8sClientConfig)
 	if err != nil {
@@ -112,11 +124,62 @@ func main() {
 		panic(fmt.Sprintf("could not create kubernetes REST mapper: %v", err))
 	}
 
-	userClientFactory := authorization.NewUnprivilegedClientFactory(k8sClientConfig, mapper, k8s.NewDefaultBackoff())
+	var userClientFactory authorization.UserK8sClientFactory = authorization.NewPooledClientFactory(
+		authorization.NewUnprivilegedClientFactory(k8sClientConfig, mapper, k8s.NewDefaultBackoff()).
+			WithTransportWrapper(middleware.WithAuditID),
+		cache.NewLRUExpireCache(cfg.GetUserClientPoolSize()),
+		cfg.GetAuthCacheTTL(),
+	)
 
 	identityProvider := wireIdentityProvider(privilegedCRClient, k8sClientConfig)
-	cachingIdentityProvider := authorization.NewCachingIdentityProvider(identityProvider, cache.NewExpiring())
-	nsPermissions := authorization.NewNamespacePermissions(privilegedCRClient, cachingIdentityProvider)
+	cachingIdentityProvider := authorization.NewCachingIdentityProvider(identityProvider, cache.NewExpiring(), cfg.GetAuthCacheTTL())
+	accessCache := authorization.NewAccessCache(cache.NewExpiring(), cfg.GetAuthCacheTTL())
+	informerCache, err := ctrlcache.New(k8sClientConfig, ctrlcache.Options{
+		Scheme:           scheme.Scheme,
+		Mapper:           mapper,
+		DefaultTransform: ctrlcache.TransformStripManagedFields(),
+	})
+	if err != nil {
+		panic(fmt.Sprintf("could not create informer cache: %v", err))
+	}
+	nsPermissions, err := authorization.NewInformerNamespacePermissions(context.Background(), informerCache, cachingIdentityProvider, accessCache)
+	if err != nil {
+		panic(fmt.Sprintf("could not create namespace permissions: %v", err))
+	}
+	namespaceRetriever, err := repositories.NewIndexedNamespaceRetriever(context.Background(), dynamicClient, informerCache, mapper)
+	if err != nil {
+		panic(fmt.Sprintf("could not create namespace retriever: %v", err))
+	}
+	go func() {
+		if err := informerCache.Start(context.Background()); err != nil {
+			panic(fmt.Sprintf("informer cache failed: %v", err))
+		}
+	}()
+	if !informerCache.WaitForCacheSync(context.Background()) {
+		panic("could not sync informer cache")
+	}
+
+	if cfg.CachedReadsEnabled {
+		userClientFactory, err = authorization.NewCachedReadClientFactory(
+			userClientFactory,
+			informerCache,
+			mapper,
+			accessCache,
+			&korifiv1alpha1.CFApp{},
+			&korifiv1alpha1.CFBuild{},
+			&korifiv1alpha1.CFCronTask{},
+			&korifiv1alpha1.CFDomain{},
+			&korifiv1alpha1.CFPackage{},
+			&korifiv1alpha1.CFProcess{},
+			&korifiv1alpha1.CFRoute{},
+			&korifiv1alpha1.CFServiceBinding{},
+			&korifiv1alpha1.CFServiceInstance{},
+			&korifiv1alpha1.CFTask{},
+		)
+		if err != nil {
+			panic(fmt.Sprintf("could not create cached read client factory: %v", err))
+		}
+	}
 
 	serverURL, err := url.Parse(cfg.ServerURL)
 	if err != nil {
@@ -150,6 +213,7 @@ func main() {
 		userClientFactory,
 		nsPermissions,
 		conditions.NewConditionAwaiter[*korifiv1alpha1.CFApp, korifiv1alpha1.CFApp, korifiv1alpha1.CFAppList](conditionTimeout),
+		cfg.RootNamespace,
 	)
 	dropletRepo := repositories.NewDropletRepo(
 		userClientFactory,
@@ -175,6 +239,8 @@ func main() {
 	buildRepo := repositories.NewBuildRepo(
 		namespaceRetriever,
 		userClientFactory,
+		nsPermissions,
+		conditions.NewConditionAwaiter[*korifiv1alpha1.CFBuild, korifiv1alpha1.CFBuild, korifiv1alpha1.CFBuildList](conditionTimeout),
 	)
 	logRepo := repositories.NewLogRepo(
 		userClientFactory,
@@ -185,12 +251,16 @@ func main() {
 		cfg.RunnerName,
 		cfg.RootNamespace,
 	)
+	repositoryNamer, err := toolsregistry.NewRepositoryNamer(informerCache, cfg.ContainerRepositoryPrefix, cfg.ContainerRepositoryTemplate)
+	if err != nil {
+		panic(fmt.Sprintf("invalid containerRepositoryTemplate: %v", err))
+	}
 	packageRepo := repositories.NewPackageRepo(
 		userClientFactory,
 		namespaceRetriever,
 		nsPermissions,
 		toolsregistry.NewRepositoryCreator(cfg.ContainerRegistryType),
-		cfg.ContainerRepositoryPrefix,
+		repositoryNamer,
 		conditions.NewConditionAwaiter[*korifiv1alpha1.CFPackage, korifiv1alpha1.CFPackage, korifiv1alpha1.CFPackageList](conditionTimeout),
 	)
 	serviceInstanceRepo := repositories.NewServiceInstanceRepo(
@@ -205,34 +275,58 @@ func main() {
 		nsPermissions,
 		conditions.NewConditionAwaiter[*korifiv1alpha1.CFServiceBinding, korifiv1alpha1.CFServiceBinding, korifiv1alpha1.CFServiceBindingList](conditionTimeout),
 	)
-	buildpackRepo := repositories.NewBuildpackRepository(cfg.BuilderName,
+	buildpackRepo := repositories.NewBuildpackRepository(reloader.BuilderName,
 		userClientFactory,
 		cfg.RootNamespace,
 	)
 	roleRepo := repositories.NewRoleRepo(
 		userClientFactory,
 		spaceRepo,
-		authorization.NewNamespacePermissions(privilegedCRClient, cachingIdentityProvider),
-		authorization.NewNamespacePermissions(privilegedCRClient, cachingIdentityProvider),
+		// the parent org role binding of a new space role may have just
+		// been created, so it is not looked up in the informer cache
+		authorization.NewNamespacePermissions(privilegedCRClient, cachingIdentityProvider, accessCache),
+		nsPermissions,
 		cfg.RootNamespace,
-		cfg.RoleMappings,
+		reloader.RoleMappings,
 		namespaceRetriever,
+		accessCache,
 	)
-	imageClient := image.NewClient(privilegedK8sClient)
+	groupRoleBinder := repositories.NewGroupRoleBinder(privilegedCRClient, cfg.RootNamespace, reloader.RoleMappings)
+	if err = groupRoleBinder.BindGroups(context.Background(), cfg.GroupRoleMappings); err != nil {
+		ctrl.Log.Error(err, "failed to bind group roles")
+	}
+	reloader.OnReload(func(ctx context.Context, cfg *config.APIConfig) {
+		if err := groupRoleBinder.BindGroups(ctx, cfg.GroupRoleMappings); err != nil {
+			ctrl.Log.Error(err, "failed to bind group roles")
+		}
+	})
+	go reloader.Run(context.Background(), eventChan)
+	imageClient := image.NewClient(privilegedK8sClient, cfg.InsecureContainerRegistries...)
 	imageRepo := repositories.NewImageRepository(
 		privilegedK8sClient,
 		userClientFactory,
 		imageClient,
 		cfg.PackageRegistrySecretNames,
 		cfg.RootNamespace,
+		accessCache,
 	)
+	var packageBlobstore handlers.PackageBlobstore = imageRepo
+	if cfg.PackageBlobstore.Type == config.S3Blobstore {
+		packageBlobstore = newS3Blobstore(cfg.PackageBlobstore.S3, userClientFactory, accessCache)
+	}
 	taskRepo := repositories.NewTaskRepo(
 		userClientFactory,
 		namespaceRetriever,
 		nsPermissions,
 		conditions.NewConditionAwaiter[*korifiv1alpha1.CFTask, korifiv1alpha1.CFTask, korifiv1alpha1.CFTaskList](conditionTimeout),
 	)
+	cronTaskRepo := repositories.NewCronTaskRepo(userClientFactory, namespaceRetriever, nsPermissions)
+	auditEventRepo := repositories.NewAuditEventRepo(userClientFactory, nsPermissions)
+	appUsageEventRepo := repositories.NewAppUsageEventRepo(userClientFactory, nsPermissions, cfg.RootNamespace, repositories.UsageEventsSettleWindow)
+	serviceUsageEventRepo := repositories.NewServiceUsageEventRepo(userClientFactory, cfg.RootNamespace, repositories.UsageEventsSettleWindow)
+	usageSummaryRepo := repositories.NewUsageSummaryRepo(userClientFactory, nsPermissions)
 	metricsRepo := repositories.NewMetricsRepo(userClientFactory)
+	promQLRepo := repositories.NewPromQLRepo(cfg.PrometheusURL, &http.Client{Timeout: promQLTimeout})
 	serviceBrokerRepo := repositories.NewServiceBrokerRepo(userClientFactory, cfg.RootNamespace)
 	serviceOfferingRepo := repositories.NewServiceOfferingRepo(userClientFactory, cfg.RootNamespace, serviceBrokerRepo)
 	servicePlanRepo := repositories.NewServicePlanRepo(userClientFactory, cfg.RootNamespace, orgRepo)
@@ -240,31 +334,42 @@ func main() {
 	processStats := actions.NewProcessStats(processRepo, appRepo, metricsRepo)
 	manifest := actions.NewManifest(
 		domainRepo,
-		cfg.DefaultDomainName,
+		reloader.DefaultDomainName,
 		manifest.NewStateCollector(appRepo, domainRepo, processRepo, routeRepo, serviceInstanceRepo, serviceBindingRepo),
-		manifest.NewNormalizer(cfg.DefaultDomainName),
+		manifest.NewNormalizer(reloader.DefaultDomainName),
 		manifest.NewApplier(appRepo, domainRepo, processRepo, routeRepo, serviceInstanceRepo, serviceBindingRepo),
 	)
 
-	requestValidator := validation.NewDefaultDecoderValidator()
+	requestValidator := validation.NewDefaultDecoderValidator().WithStrictYAMLFields(cfg.StrictManifestFields)
 
 	routerBuilder := routing.NewRouterBuilder()
 	routerBuilder.UseMiddleware(
 		middleware.Correlation(ctrl.Log),
+		middleware.VcapRequestID,
 		middleware.CFCliVersion,
 		middleware.HTTPLogging,
 		chiMiddlewares.StripSlashes,
+		middleware.StrictQueryParameters,
 	)
 
+	authInfoParser := authorization.NewInfoParser()
+	if cfg.AccessLog.Enabled {
+		routerBuilder.UseMiddleware(middleware.AccessLog(
+			openAccessLog(cfg.AccessLog.Path),
+			authInfoParser,
+			cachingIdentityProvider,
+		))
+	}
+
 	if !cfg.ExperimentalManagedServicesEnabled {
 		routerBuilder.UseMiddleware(middleware.DisableManagedServices)
 	}
 
-	authInfoParser := authorization.NewInfoParser()
 	routerBuilder.UseAuthMiddleware(
 		middleware.Authentication(
 			authInfoParser,
 			cachingIdentityProvider,
+			authorization.NewImpersonationChecker(userClientFactory),
 		),
 		middleware.CFUser(
 			nsPermissions,
@@ -280,6 +385,7 @@ func main() {
 		servicePlanRepo,
 	)
 
+	readiness := handlers.NewReadiness()
 	apiHandlers := []routing.Routable{
 		handlers.NewRootV3(*serverURL),
 		handlers.NewRoot(*serverURL),
@@ -287,6 +393,11 @@ func main() {
 			*serverURL,
 			cfg.InfoConfig,
 		),
+		handlers.NewInfoV2(
+			*serverURL,
+			cfg.InfoConfig,
+			cfg.OAuthShim.Enabled(),
+		),
 		handlers.NewResourceMatches(),
 		handlers.NewApp(
 			*serverURL,
@@ -298,6 +409,7 @@ func main() {
 			domainRepo,
 			spaceRepo,
 			packageRepo,
+			buildRepo,
 			requestValidator,
 			podRepo,
 		),
@@ -317,9 +429,10 @@ func main() {
 			packageRepo,
 			appRepo,
 			dropletRepo,
-			imageRepo,
+			packageBlobstore,
 			requestValidator,
 			cfg.PackageRegistrySecretNames,
+			cfg.GetMaxPackageUploadSize(),
 		),
 		handlers.NewBuild(
 			*serverURL,
@@ -376,6 +489,7 @@ func main() {
 			appRepo,
 			buildRepo,
 			logRepo,
+			promQLRepo,
 		),
 		handlers.NewOrg(
 			*serverURL,
@@ -383,7 +497,7 @@ func main() {
 			domainRepo,
 			requestValidator,
 			cfg.GetUserCertificateDuration(),
-			cfg.DefaultDomainName,
+			reloader.DefaultDomainName,
 		),
 		handlers.NewSpace(
 			*serverURL,
@@ -401,8 +515,8 @@ func main() {
 			roleRepo,
 			requestValidator,
 		),
-		handlers.NewWhoAmI(cachingIdentityProvider, *serverURL),
-		handlers.NewUser(*serverURL),
+		handlers.NewWhoAmI(cachingIdentityProvider, roleRepo, *serverURL),
+		handlers.NewUser(*serverURL, requestValidator),
 		handlers.NewBuildpack(
 			*serverURL,
 			buildpackRepo,
@@ -428,9 +542,33 @@ func main() {
 			taskRepo,
 			requestValidator,
 		),
-		handlers.NewOAuth(
+		handlers.NewCronTask(
+			*serverURL,
+			appRepo,
+			cronTaskRepo,
+			requestValidator,
+		),
+		handlers.NewAuditEvent(
+			*serverURL,
+			auditEventRepo,
+			requestValidator,
+		),
+		handlers.NewAppUsageEvent(
+			*serverURL,
+			appUsageEventRepo,
+			requestValidator,
+		),
+		handlers.NewServiceUsageEvent(
 			*serverURL,
+			serviceUsageEventRepo,
+			requestValidator,
 		),
+		handlers.NewUsageSummary(
+			*serverURL,
+			orgRepo,
+			usageSummaryRepo,
+		),
+		newOAuthHandler(*serverURL, cfg.OAuthShim),
 		handlers.NewServiceBroker(
 			*serverURL,
 			serviceBrokerRepo,
@@ -461,7 +599,7 @@ func main() {
 
 	srv := &http.Server{
 		Addr:              portString,
-		Handler:           routerBuilder.Build(),
+		Handler:           readiness.Serve(routerBuilder.Build()),
 		IdleTimeout:       time.Duration(cfg.IdleTimeout * int(time.Second)),
 		ReadTimeout:       time.Duration(cfg.ReadTimeout * int(time.Second)),
 		ReadHeaderTimeout: time.Duration(cfg.ReadHeaderTimeout * int(time.Second)),
@@ -469,6 +607,16 @@ func main() {
 		ErrorLog:          log.New(&tools.LogrWriter{Logger: ctrl.Log, Message: "HTTP server error"}, "", 0),
 	}
 
+	signalCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		<-signalCtx.Done()
+		shutdownGracefully(srv, readiness, cfg)
+	}()
+
 	if tlsFound {
 		ctrl.Log.Info("listening with TLS on " + portString)
 		certPath := filepath.Join(tlsPath, "tls.crt")
@@ -494,18 +642,67 @@ func main() {
 			GetCertificate: certWatcher.GetCertificate,
 		}
 		err = srv.ListenAndServeTLS("", "")
-		if err != nil {
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			ctrl.Log.Error(err, "error serving TLS")
 			os.Exit(1)
 		}
 	} else {
 		ctrl.Log.Info("listening without TLS on " + portString)
 		err := srv.ListenAndServe()
-		if err != nil {
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			ctrl.Log.Error(err, "error serving HTTP")
 			os.Exit(1)
 		}
 	}
+
+	<-drained
+}
+
+// shutdownGracefully fails readiness first and keeps serving for the shutdown
+// delay, so that no new requests are routed to the API, then stops accepting
+// connections and waits up to the drain timeout for in-flight requests, e.g.
+// package uploads, to complete
+func shutdownGracefully(srv *http.Server, readiness *handlers.Readiness, cfg *config.APIConfig) {
+	ctrl.Log.Info("draining", "shutdownDelay", cfg.GetShutdownDelay(), "drainTimeout", cfg.GetDrainTimeout())
+	readiness.Drain()
+	time.Sleep(cfg.GetShutdownDelay())
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.GetDrainTimeout())
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		ctrl.Log.Error(err, "in-flight requests did not complete before the drain timeout")
+		_ = srv.Close()
+	}
+}
+
+func newOAuthHandler(serverURL url.URL, oauthShimConfig config.OAuthShimConfig) routing.Routable {
+	if !oauthShimConfig.Enabled() {
+		return handlers.NewOAuth(serverURL)
+	}
+
+	return handlers.NewOAuthShim(serverURL, authorization.NewOIDCTokenExchanger(
+		&http.Client{Timeout: 30 * time.Second},
+		oauthShimConfig.IssuerURL,
+		oauthShimConfig.ClientID,
+		os.Getenv("OAUTH_SHIM_CLIENT_SECRET"),
+	))
+}
+
+func openAccessLog(path string) io.Writer {
+	switch path {
+	case "", "stdout":
+		return os.Stdout
+	case "stderr":
+		return os.Stderr
+	}
+
+	accessLogFile, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		panic(fmt.Sprintf("could not open access log %q: %v", path, err))
+	}
+
+	return accessLogFile
 }
 
 func wireIdentityProvider(client client.Client, restConfig *rest.Config) authorization.IdentityProvider {
@@ -513,3 +710,12 @@ func wireIdentityProvider(client client.Client, restConfig *rest.Config) authori
 	certInspector := authorization.NewCertInspector(restConfig)
 	return authorization.NewCertTokenIdentityProvider(tokenReviewer, certInspector)
 }
+
+func newS3Blobstore(s3Config config.S3BlobstoreConfig, userClientFactory authorization.UserK8sClientFactory, accessCache *authorization.AccessCache) *repositories.S3Blobstore {
+	s3Client, err := s3.NewDefaultClient(context.Background(), s3Config.Endpoint, s3Config.Bucket, s3Config.Region)
+	if err != nil {
+		panic(fmt.Sprintf("could not create s3 blobstore: %v", err))
+	}
+
+	return repositories.NewS3Blobstore(s3Client, userClientFactory, accessCache)
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const accessLogTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+type accessLog struct {
+	writer           io.Writer
+	writerLock       sync.Mutex
+	authInfoParser   AuthInfoParser
+	identityProvider IdentityProvider
+}
+
+// AccessLog emits one line per request in the gorouter access log format to
+// the given writer. The identity of the requester is resolved from the
+// Authorization header and recorded as `user_guid`.
+func AccessLog(
+	writer io.Writer,
+	authInfoParser AuthInfoParser,
+	identityProvider IdentityProvider,
+) func(http.Handler) http.Handler {
+	return (&accessLog{
+		writer:           writer,
+		authInfoParser:   authInfoParser,
+		identityProvider: identityProvider,
+	}).middleware
+}
+
+func (a *accessLog) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		wrapper := &responseWriterWrapper{writer: w}
+		next.ServeHTTP(wrapper, r)
+
+		responseTime := time.Since(start)
+
+		line := a.formatLine(r, wrapper, start, responseTime)
+
+		a.writerLock.Lock()
+		defer a.writerLock.Unlock()
+		if _, err := io.WriteString(a.writer, line); err != nil {
+			logr.FromContextOrDiscard(r.Context()).WithName("access-log").Error(err, "failed to write access log line")
+		}
+	})
+}
+
+func (a *accessLog) formatLine(r *http.Request, w *responseWriterWrapper, start time.Time, responseTime time.Duration) string {
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	bytesReceived := r.ContentLength
+	if bytesReceived < 0 {
+		bytesReceived = 0
+	}
+
+	requestID := w.Header().Get(VcapRequestIDHeader)
+	if requestID == "" {
+		requestID = r.Header.Get(VcapRequestIDHeader)
+	}
+
+	return fmt.Sprintf(
+		"%s - [%s] %q %d %d %d %q %q %q %q x_forwarded_for:%q x_forwarded_proto:%q vcap_request_id:%q response_time:%.9f user_guid:%q\n",
+		r.Host,
+		start.UTC().Format(accessLogTimeFormat),
+		fmt.Sprintf("%s %s %s", r.Method, r.URL.RequestURI(), r.Proto),
+		status,
+		bytesReceived,
+		w.size,
+		orDash(r.Referer()),
+		orDash(r.UserAgent()),
+		orDash(r.RemoteAddr),
+		"-",
+		orDash(r.Header.Get("X-Forwarded-For")),
+		orDash(r.Header.Get("X-Forwarded-Proto")),
+		orDash(requestID),
+		responseTime.Seconds(),
+		orDash(a.userGUID(r)),
+	)
+}
+
+func (a *accessLog) userGUID(r *http.Request) string {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		return ""
+	}
+
+	authInfo, err := a.authInfoParser.Parse(authHeader)
+	if err != nil {
+		return ""
+	}
+
+	identity, err := a.identityProvider.GetIdentity(r.Context(), authInfo)
+	if err != nil {
+		return ""
+	}
+
+	return identity.Name
+}
+
+func orDash(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "-"
+	}
+
+	return value
+}
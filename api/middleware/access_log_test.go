@@ -0,0 +1,86 @@
+package middleware_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/middleware"
+	"code.cloudfoundry.org/korifi/api/middleware/fake"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AccessLog", func() {
+	var (
+		buf              *strings.Builder
+		authInfoParser   *fake.AuthInfoParser
+		identityProvider *fake.IdentityProvider
+		request          *http.Request
+	)
+
+	BeforeEach(func() {
+		buf = &strings.Builder{}
+		authInfoParser = new(fake.AuthInfoParser)
+		authInfoParser.ParseReturns(authorization.Info{Token: "the-token"}, nil)
+		identityProvider = new(fake.IdentityProvider)
+		identityProvider.GetIdentityReturns(authorization.Identity{Name: "the-user", Kind: "User"}, nil)
+
+		var err error
+		request, err = http.NewRequest(http.MethodPost, "http://api.example.org/v3/apps?foo=bar", strings.NewReader("request-body"))
+		Expect(err).NotTo(HaveOccurred())
+		request.RemoteAddr = "1.2.3.4:5678"
+		request.Header.Set("Authorization", "Bearer the-token")
+		request.Header.Set("User-Agent", "cf/8.7.0")
+		request.Header.Set("X-Forwarded-For", "10.0.0.1")
+		request.Header.Set("X-Forwarded-Proto", "https")
+	})
+
+	JustBeforeEach(func() {
+		middleware.AccessLog(buf, authInfoParser, identityProvider)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Vcap-Request-Id", "the-request-id")
+			w.WriteHeader(http.StatusTeapot)
+			fmt.Fprint(w, "hello, world!")
+		})).ServeHTTP(rr, request)
+	})
+
+	It("writes a single gorouter style access log line", func() {
+		Expect(strings.Count(buf.String(), "\n")).To(Equal(1))
+		Expect(buf.String()).To(MatchRegexp(
+			`^api\.example\.org - \[\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{9}Z\] "POST /v3/apps\?foo=bar HTTP/1\.1" 418 12 13 "-" "cf/8\.7\.0" "1\.2\.3\.4:5678" "-" ` +
+				`x_forwarded_for:"10\.0\.0\.1" x_forwarded_proto:"https" vcap_request_id:"the-request-id" response_time:\d+\.\d{9} user_guid:"the-user"\n$`,
+		))
+	})
+
+	It("resolves the user from the authorization header", func() {
+		Expect(authInfoParser.ParseCallCount()).To(Equal(1))
+		Expect(authInfoParser.ParseArgsForCall(0)).To(Equal("Bearer the-token"))
+
+		Expect(identityProvider.GetIdentityCallCount()).To(Equal(1))
+		_, actualAuthInfo := identityProvider.GetIdentityArgsForCall(0)
+		Expect(actualAuthInfo).To(Equal(authorization.Info{Token: "the-token"}))
+	})
+
+	When("the request is not authenticated", func() {
+		BeforeEach(func() {
+			request.Header.Del("Authorization")
+		})
+
+		It("logs a dash as user guid", func() {
+			Expect(buf.String()).To(ContainSubstring(`user_guid:"-"`))
+			Expect(authInfoParser.ParseCallCount()).To(BeZero())
+		})
+	})
+
+	When("the identity cannot be resolved", func() {
+		BeforeEach(func() {
+			identityProvider.GetIdentityReturns(authorization.Identity{}, errors.New("boom"))
+		})
+
+		It("logs a dash as user guid", func() {
+			Expect(buf.String()).To(ContainSubstring(`user_guid:"-"`))
+		})
+	})
+})
@@ -2,7 +2,9 @@ package middleware
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"code.cloudfoundry.org/korifi/api/authorization"
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
@@ -19,9 +21,25 @@ type IdentityProvider interface {
 
 //counterfeiter:generate -o fake -fake-name AuthInfoParser . AuthInfoParser
 
+//counterfeiter:generate -o fake -fake-name ImpersonationChecker . ImpersonationChecker
+
+const (
+	// ImpersonateUserHeader lets admins act with the visibility of another
+	// user, and ImpersonateGroupHeader with that of the groups of the user.
+	// Kubernetes does not know the groups of a user it does not authenticate,
+	// so the roles of the groups only apply when the groups are given.
+	ImpersonateUserHeader  = "X-Cf-Impersonate-User"
+	ImpersonateGroupHeader = "X-Cf-Impersonate-Group"
+)
+
+type ImpersonationChecker interface {
+	CanImpersonate(ctx context.Context, authInfo authorization.Info, userName string, groups []string) (bool, error)
+}
+
 type authentication struct {
-	authInfoParser   AuthInfoParser
-	identityProvider IdentityProvider
+	authInfoParser       AuthInfoParser
+	identityProvider     IdentityProvider
+	impersonationChecker ImpersonationChecker
 }
 
 type AuthInfoParser interface {
@@ -31,10 +49,12 @@ type AuthInfoParser interface {
 func Authentication(
 	authInfoParser AuthInfoParser,
 	identityProvider IdentityProvider,
+	impersonationChecker ImpersonationChecker,
 ) func(http.Handler) http.Handler {
 	return (&authentication{
-		authInfoParser:   authInfoParser,
-		identityProvider: identityProvider,
+		authInfoParser:       authInfoParser,
+		identityProvider:     identityProvider,
+		impersonationChecker: impersonationChecker,
 	}).middleware
 }
 
@@ -49,14 +69,55 @@ func (a *authentication) middleware(next http.Handler) http.Handler {
 			return
 		}
 
-		r = r.WithContext(authorization.NewContext(r.Context(), &authInfo))
-
-		_, err = a.identityProvider.GetIdentity(r.Context(), authInfo)
+		identity, err := a.identityProvider.GetIdentity(r.Context(), authInfo)
 		if err != nil {
 			routing.PresentError(logger, w, apierrors.LogAndReturn(logger, err, "failed to get identity"))
 			return
 		}
 
+		if impersonatedUser := r.Header.Get(ImpersonateUserHeader); impersonatedUser != "" {
+			impersonatedGroups := headerListValues(r.Header, ImpersonateGroupHeader)
+			authInfo, err = a.impersonate(r.Context(), authInfo, impersonatedUser, impersonatedGroups)
+			if err != nil {
+				routing.PresentError(logger, w, apierrors.LogAndReturn(logger, err, "failed to impersonate user", "user", identity.Name, "impersonatedUser", impersonatedUser, "impersonatedGroups", impersonatedGroups))
+				return
+			}
+
+			logger.Info("impersonating user", "user", identity.Name, "impersonatedUser", impersonatedUser, "impersonatedGroups", impersonatedGroups)
+		}
+
+		r = r.WithContext(authorization.NewContext(r.Context(), &authInfo))
+
 		next.ServeHTTP(w, r)
 	})
 }
+
+func (a *authentication) impersonate(ctx context.Context, authInfo authorization.Info, userName string, groups []string) (authorization.Info, error) {
+	allowed, err := a.impersonationChecker.CanImpersonate(ctx, authInfo, userName, groups)
+	if err != nil {
+		return authorization.Info{}, err
+	}
+
+	if !allowed {
+		return authorization.Info{}, apierrors.NewForbiddenError(fmt.Errorf("not allowed to impersonate user %q with groups %q", userName, groups), "")
+	}
+
+	authInfo.Impersonate = userName
+	authInfo.ImpersonateGroups = groups
+	return authInfo, nil
+}
+
+// headerListValues returns the values of a header that may be repeated or
+// hold a comma separated list
+func headerListValues(header http.Header, key string) []string {
+	var values []string
+	for _, value := range header.Values(key) {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+
+	return values
+}
@@ -21,7 +21,10 @@ var _ = Describe("Authentication Middleware", func() {
 		nextHandler      http.Handler
 		identityProvider *fake.IdentityProvider
 		authInfoParser   *fake.AuthInfoParser
+		impChecker       *fake.ImpersonationChecker
 		requestPath      string
+		impersonateUser  string
+		impersonateGroup []string
 		actualReq        *http.Request
 	)
 
@@ -39,9 +42,15 @@ var _ = Describe("Authentication Middleware", func() {
 		identityProvider = new(fake.IdentityProvider)
 		identityProvider.GetIdentityReturns(authorization.Identity{}, nil)
 
+		impChecker = new(fake.ImpersonationChecker)
+		impChecker.CanImpersonateReturns(true, nil)
+		impersonateUser = ""
+		impersonateGroup = nil
+
 		authMiddleware = middleware.Authentication(
 			authInfoParser,
 			identityProvider,
+			impChecker,
 		)
 	})
 
@@ -49,6 +58,12 @@ var _ = Describe("Authentication Middleware", func() {
 		request, err := http.NewRequest(http.MethodGet, "http://localhost"+requestPath, nil)
 		Expect(err).NotTo(HaveOccurred())
 		request.Header.Add("Authorization", authHeader)
+		if impersonateUser != "" {
+			request.Header.Add(middleware.ImpersonateUserHeader, impersonateUser)
+		}
+		for _, group := range impersonateGroup {
+			request.Header.Add(middleware.ImpersonateGroupHeader, group)
+		}
 		authMiddleware(nextHandler).ServeHTTP(rr, request)
 	})
 
@@ -63,6 +78,10 @@ var _ = Describe("Authentication Middleware", func() {
 		Expect(rr).To(HaveHTTPStatus(http.StatusTeapot))
 	})
 
+	It("does not check impersonation", func() {
+		Expect(impChecker.CanImpersonateCallCount()).To(BeZero())
+	})
+
 	It("parses the Authorization header into an authorization.Info and injects it in the request context", func() {
 		actualAuthInfo, ok := authorization.InfoFromContext(actualReq.Context())
 		Expect(ok).To(BeTrue())
@@ -125,4 +144,89 @@ var _ = Describe("Authentication Middleware", func() {
                 }`)))
 		})
 	})
+
+	When("the impersonate user header is set", func() {
+		BeforeEach(func() {
+			impersonateUser = "bob"
+		})
+
+		It("checks that the user can impersonate", func() {
+			Expect(impChecker.CanImpersonateCallCount()).To(Equal(1))
+			_, actualAuthInfo, actualUserName, actualGroups := impChecker.CanImpersonateArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authorization.Info{Token: "the-token"}))
+			Expect(actualUserName).To(Equal("bob"))
+			Expect(actualGroups).To(BeEmpty())
+		})
+
+		It("injects the impersonating authorization.Info in the request context", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusTeapot))
+			actualAuthInfo, ok := authorization.InfoFromContext(actualReq.Context())
+			Expect(ok).To(BeTrue())
+			Expect(actualAuthInfo).To(Equal(authorization.Info{Token: "the-token", Impersonate: "bob"}))
+		})
+
+		When("the impersonate group header is set", func() {
+			BeforeEach(func() {
+				impersonateGroup = []string{"developers", "auditors, managers"}
+			})
+
+			It("checks that the user can impersonate the groups", func() {
+				Expect(impChecker.CanImpersonateCallCount()).To(Equal(1))
+				_, _, actualUserName, actualGroups := impChecker.CanImpersonateArgsForCall(0)
+				Expect(actualUserName).To(Equal("bob"))
+				Expect(actualGroups).To(Equal([]string{"developers", "auditors", "managers"}))
+			})
+
+			It("injects the impersonated groups in the request context", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusTeapot))
+				actualAuthInfo, ok := authorization.InfoFromContext(actualReq.Context())
+				Expect(ok).To(BeTrue())
+				Expect(actualAuthInfo).To(Equal(authorization.Info{
+					Token:             "the-token",
+					Impersonate:       "bob",
+					ImpersonateGroups: []string{"developers", "auditors", "managers"},
+				}))
+			})
+		})
+
+		When("the user cannot impersonate", func() {
+			BeforeEach(func() {
+				impChecker.CanImpersonateReturns(false, nil)
+			})
+
+			It("returns a CF-NotAuthorized error", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusForbidden))
+				Expect(rr).To(HaveHTTPBody(MatchJSON(`{
+                    "errors": [
+                    {
+                        "detail": "You are not authorized to perform the requested action",
+                        "title": "CF-NotAuthorized",
+                        "code": 10003
+                    }
+                    ]
+                }`)))
+			})
+		})
+
+		When("checking impersonation fails", func() {
+			BeforeEach(func() {
+				impChecker.CanImpersonateReturns(false, errors.New("boom"))
+			})
+
+			It("returns a CF-Unknown error", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusInternalServerError))
+			})
+		})
+
+		When("getting the identity fails", func() {
+			BeforeEach(func() {
+				identityProvider.GetIdentityReturns(authorization.Identity{}, apierrors.NewInvalidAuthError(nil))
+			})
+
+			It("does not check impersonation", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusUnauthorized))
+				Expect(impChecker.CanImpersonateCallCount()).To(BeZero())
+			})
+		})
+	})
 })
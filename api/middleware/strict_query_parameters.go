@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/payloads/validation"
+	"code.cloudfoundry.org/korifi/api/routing"
+	"github.com/go-logr/logr"
+)
+
+// StrictQueryParameters rejects query parameters that are not supported by
+// the endpoint with CF-BadQueryParameter instead of silently ignoring them.
+// Only GET endpoints accept query parameters; list endpoints reject the
+// parameters they do not support when decoding them, all other endpoints
+// reject any query parameter.
+func StrictQueryParameters(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.URL.Query()) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			logger := logr.FromContextOrDiscard(r.Context()).WithName("strict-query-parameters")
+			routing.PresentError(logger, w, apierrors.NewUnknownKeyError(fmt.Errorf("unsupported query parameters on %s %s", r.Method, r.URL.Path), nil))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(validation.WithStrictQueryParameters(r.Context())))
+	})
+}
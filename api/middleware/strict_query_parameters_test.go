@@ -0,0 +1,120 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/middleware"
+	"code.cloudfoundry.org/korifi/api/payloads/validation"
+	"code.cloudfoundry.org/korifi/api/routing"
+	. "code.cloudfoundry.org/korifi/tests/matchers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type namesPayload struct {
+	Names string
+}
+
+func (p *namesPayload) SupportedKeys() []string {
+	return []string{"names"}
+}
+
+func (p *namesPayload) DecodeFromURLValues(values url.Values) error {
+	p.Names = values.Get("names")
+	return nil
+}
+
+var _ = Describe("StrictQueryParameters", func() {
+	var (
+		handler          routing.Handler
+		method           string
+		requestURL       string
+		handlerCallCount int
+	)
+
+	BeforeEach(func() {
+		method = http.MethodGet
+		handlerCallCount = 0
+		handler = func(r *http.Request) (*routing.Response, error) {
+			handlerCallCount++
+			return routing.NewResponse(http.StatusTeapot), nil
+		}
+	})
+
+	JustBeforeEach(func() {
+		request, err := http.NewRequest(method, requestURL, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		middleware.StrictQueryParameters(handler).ServeHTTP(rr, request)
+	})
+
+	When("the request has no query parameters", func() {
+		BeforeEach(func() {
+			requestURL = "/v3/apps/app-guid"
+		})
+
+		It("delegates to the next handler", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusTeapot))
+		})
+	})
+
+	When("the handler does not decode the query parameters", func() {
+		BeforeEach(func() {
+			requestURL = "/v3/apps/app-guid?foo=bar"
+		})
+
+		It("rejects them", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusBadRequest))
+			Expect(rr).To(HaveHTTPBody(SatisfyAll(
+				MatchJSONPath("$.errors[0].title", "CF-BadQueryParameter"),
+				MatchJSONPath("$.errors[0].detail", "The query parameter is invalid: This endpoint does not accept query parameters"),
+			)))
+		})
+	})
+
+	When("the handler decodes the query parameters", func() {
+		BeforeEach(func() {
+			handler = func(r *http.Request) (*routing.Response, error) {
+				if err := validation.NewDefaultDecoderValidator().DecodeAndValidateURLValues(r, &namesPayload{}); err != nil {
+					return nil, err
+				}
+				return routing.NewResponse(http.StatusTeapot), nil
+			}
+		})
+
+		When("they are supported", func() {
+			BeforeEach(func() {
+				requestURL = "/v3/apps?names=foo"
+			})
+
+			It("accepts them", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusTeapot))
+			})
+		})
+
+		When("they are not supported", func() {
+			BeforeEach(func() {
+				requestURL = "/v3/apps?foo=bar"
+			})
+
+			It("rejects them listing the valid ones", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusBadRequest))
+				Expect(rr).To(HaveHTTPBody(MatchJSONPath("$.errors[0].detail", "The query parameter is invalid: Valid parameters are: 'names'")))
+			})
+		})
+	})
+
+	When("a request that is not a GET has query parameters", func() {
+		BeforeEach(func() {
+			method = http.MethodDelete
+			requestURL = "/v3/service_instances/si-guid?purge=true"
+		})
+
+		It("rejects them without calling the handler", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusBadRequest))
+			Expect(rr).To(HaveHTTPBody(MatchJSONPath("$.errors[0].title", "CF-BadQueryParameter")))
+			Expect(handlerCallCount).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+)
+
+const (
+	VcapRequestIDHeader = "X-Vcap-Request-Id"
+	AuditIDHeader       = "Audit-ID"
+
+	maxVcapRequestIDLength = 255
+)
+
+type vcapRequestIDKey struct{}
+
+// VcapRequestID accepts the request ID set by the CF CLI (or any other
+// client), generating one when it is missing. The ID is returned in the
+// response headers, added to the request logger and stored in the request
+// context so that it can be correlated with server side records (e.g. audit
+// events).
+func VcapRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(VcapRequestIDHeader)
+		if id == "" || len(id) > maxVcapRequestIDLength {
+			id = uuid.NewString()
+		}
+
+		ctx := NewVcapRequestIDContext(r.Context(), id)
+		l := logr.FromContextOrDiscard(ctx).WithValues("vcap-request-id", id)
+		r = r.WithContext(logr.NewContext(ctx, l))
+
+		w.Header().Set(VcapRequestIDHeader, id)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func NewVcapRequestIDContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, vcapRequestIDKey{}, id)
+}
+
+func VcapRequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(vcapRequestIDKey{}).(string)
+	return id, ok
+}
+
+// WithAuditID sends the request ID of the API request as the Audit-ID of the
+// Kubernetes API requests made on its behalf. The Kubernetes API server
+// records it as the ID of its audit events, which correlates them with the
+// API request.
+func WithAuditID(next http.RoundTripper) http.RoundTripper {
+	return auditIDRoundTripper{next: next}
+}
+
+type auditIDRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (t auditIDRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	id, ok := VcapRequestIDFromContext(req.Context())
+	if !ok || req.Header.Get(AuditIDHeader) != "" {
+		return t.next.RoundTrip(req)
+	}
+
+	// round trippers must not modify the request they are given
+	req = req.Clone(req.Context())
+	req.Header.Set(AuditIDHeader, id)
+
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,120 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/korifi/api/middleware"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+var _ = Describe("VcapRequestID", func() {
+	var (
+		requestHeaders    http.Header
+		buf               *strings.Builder
+		contextRequestID  string
+		contextIDWasFound bool
+	)
+
+	BeforeEach(func() {
+		requestHeaders = http.Header{}
+		buf = &strings.Builder{}
+	})
+
+	JustBeforeEach(func() {
+		logger := zap.New(zap.WriteTo(buf))
+		request, err := http.NewRequestWithContext(logr.NewContext(context.Background(), logger), http.MethodGet, "http://localhost/foo", nil)
+		Expect(err).NotTo(HaveOccurred())
+		request.Header = requestHeaders
+
+		middleware.VcapRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contextRequestID, contextIDWasFound = middleware.VcapRequestIDFromContext(r.Context())
+			handler(w, r)
+		})).ServeHTTP(rr, request)
+	})
+
+	It("generates a request ID, logs it, stores it in the context and returns it in a header", func() {
+		Expect(rr).To(HaveHTTPHeaderWithValue("X-Vcap-Request-Id", Not(BeEmpty())))
+		requestID := rr.Header().Get("X-Vcap-Request-Id")
+		Expect(buf.String()).To(ContainSubstring("hello"))
+		Expect(buf.String()).To(ContainSubstring(`"vcap-request-id":"` + requestID + `"`))
+		Expect(contextIDWasFound).To(BeTrue())
+		Expect(contextRequestID).To(Equal(requestID))
+	})
+
+	When("the request ID is passed in a header", func() {
+		BeforeEach(func() {
+			requestHeaders.Set("X-Vcap-Request-Id", "my-request-id")
+		})
+
+		It("uses that ID", func() {
+			Expect(rr).To(HaveHTTPHeaderWithValue("X-Vcap-Request-Id", "my-request-id"))
+			Expect(buf.String()).To(ContainSubstring(`"vcap-request-id":"my-request-id"`))
+			Expect(contextRequestID).To(Equal("my-request-id"))
+		})
+	})
+
+	When("the passed request ID is too long", func() {
+		BeforeEach(func() {
+			requestHeaders.Set("X-Vcap-Request-Id", strings.Repeat("a", 256))
+		})
+
+		It("generates a new one", func() {
+			Expect(rr).To(HaveHTTPHeaderWithValue("X-Vcap-Request-Id", Not(HavePrefix("aaa"))))
+		})
+	})
+})
+
+var _ = Describe("WithAuditID", func() {
+	var (
+		ctx             context.Context
+		request         *http.Request
+		sentRequest     *http.Request
+		roundTripperErr error
+	)
+
+	BeforeEach(func() {
+		ctx = middleware.NewVcapRequestIDContext(context.Background(), "my-request-id")
+		sentRequest = nil
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		request, err = http.NewRequestWithContext(ctx, http.MethodGet, "http://k8s/api", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, roundTripperErr = middleware.WithAuditID(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			sentRequest = r
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		})).RoundTrip(request)
+	})
+
+	It("sends the request ID as audit ID", func() {
+		Expect(roundTripperErr).NotTo(HaveOccurred())
+		Expect(sentRequest.Header.Get("Audit-ID")).To(Equal("my-request-id"))
+	})
+
+	It("does not modify the original request", func() {
+		Expect(request.Header.Get("Audit-ID")).To(BeEmpty())
+	})
+
+	When("there is no request ID in the context", func() {
+		BeforeEach(func() {
+			ctx = context.Background()
+		})
+
+		It("does not send an audit ID", func() {
+			Expect(sentRequest.Header.Get("Audit-ID")).To(BeEmpty())
+		})
+	})
+})
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -14,9 +14,8 @@ import (
 
 // DefaultLifecycleConfig is overwritten by main.go
 var DefaultLifecycleConfig = config.DefaultLifecycleConfig{
-	Type:            "buildpack",
-	Stack:           "cflinuxfs3",
-	StagingMemoryMB: 1024,
+	Type:  "buildpack",
+	Stack: "cflinuxfs3",
 }
 
 type AppCreate struct {
@@ -34,7 +33,7 @@ func (c AppCreate) Validate() error {
 		jellidation.Field(&c.Name, jellidation.Required, jellidation.Match(appNameRegex).Error("name must consist only of letters, numbers, underscores and dashes")),
 		jellidation.Field(&c.Relationships, jellidation.NotNil),
 		jellidation.Field(&c.Lifecycle),
-		jellidation.Field(&c.Metadata),
+		jellidation.Field(&c.Metadata, jellidation.By(validateAppMetadata), jellidation.Skip),
 	)
 }
 
@@ -170,7 +169,7 @@ type AppPatch struct {
 func (p AppPatch) Validate() error {
 	return jellidation.ValidateStruct(&p,
 		jellidation.Field(&p.Name, jellidation.Match(appNameRegex).Error("name must consist only of letters, numbers, underscores and dashes")),
-		jellidation.Field(&p.Metadata),
+		jellidation.Field(&p.Metadata, jellidation.By(validateAppMetadataPatch), jellidation.Skip),
 		jellidation.Field(&p.Lifecycle),
 	)
 }
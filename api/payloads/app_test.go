@@ -158,6 +158,102 @@ var _ = Describe("App payload validation", func() {
 					expectUnprocessableEntityError(validatorErr, "label/annotation key cannot use the cloudfoundry.org domain")
 				})
 			})
+
+			When("the staging resources are overridden", func() {
+				BeforeEach(func() {
+					payload.Metadata = payloads.Metadata{
+						Annotations: map[string]string{
+							"korifi.cloudfoundry.org/staging-memory": "2G",
+						},
+					}
+				})
+
+				It("succeeds", func() {
+					Expect(validatorErr).NotTo(HaveOccurred())
+				})
+
+				When("the staging memory is invalid", func() {
+					BeforeEach(func() {
+						payload.Metadata.Annotations["korifi.cloudfoundry.org/staging-memory"] = "lots"
+					})
+
+					It("returns an appropriate error", func() {
+						expectUnprocessableEntityError(validatorErr, "korifi.cloudfoundry.org/staging-memory must be an amount of memory or disk such as 512M or 2G")
+					})
+				})
+			})
+
+			When("the build cache is disabled", func() {
+				BeforeEach(func() {
+					payload.Metadata = payloads.Metadata{
+						Annotations: map[string]string{
+							"korifi.cloudfoundry.org/disable-build-cache": "true",
+						},
+					}
+				})
+
+				It("succeeds", func() {
+					Expect(validatorErr).NotTo(HaveOccurred())
+				})
+
+				When("the value is not a boolean", func() {
+					BeforeEach(func() {
+						payload.Metadata.Annotations["korifi.cloudfoundry.org/disable-build-cache"] = "yes"
+					})
+
+					It("returns an appropriate error", func() {
+						expectUnprocessableEntityError(validatorErr, "korifi.cloudfoundry.org/disable-build-cache must be either true or false")
+					})
+				})
+			})
+
+			When("the service binding projection is set", func() {
+				BeforeEach(func() {
+					payload.Metadata = payloads.Metadata{
+						Annotations: map[string]string{
+							"korifi.cloudfoundry.org/service-binding-projection": "files",
+						},
+					}
+				})
+
+				It("succeeds", func() {
+					Expect(validatorErr).NotTo(HaveOccurred())
+				})
+
+				When("the projection is invalid", func() {
+					BeforeEach(func() {
+						payload.Metadata.Annotations["korifi.cloudfoundry.org/service-binding-projection"] = "env"
+					})
+
+					It("returns an appropriate error", func() {
+						expectUnprocessableEntityError(validatorErr, "korifi.cloudfoundry.org/service-binding-projection must be one of vcap-services, files, all")
+					})
+				})
+			})
+
+			When("the topology spread is disabled", func() {
+				BeforeEach(func() {
+					payload.Metadata = payloads.Metadata{
+						Annotations: map[string]string{
+							"korifi.cloudfoundry.org/disable-topology-spread": "true",
+						},
+					}
+				})
+
+				It("succeeds", func() {
+					Expect(validatorErr).NotTo(HaveOccurred())
+				})
+
+				When("the value is not a boolean", func() {
+					BeforeEach(func() {
+						payload.Metadata.Annotations["korifi.cloudfoundry.org/disable-topology-spread"] = "yes"
+					})
+
+					It("returns an appropriate error", func() {
+						expectUnprocessableEntityError(validatorErr, "korifi.cloudfoundry.org/disable-topology-spread must be either true or false")
+					})
+				})
+			})
 		})
 
 		Describe("ToAppCreateMessage", func() {
@@ -306,6 +402,30 @@ var _ = Describe("App payload validation", func() {
 				})
 			})
 
+			When("the staging resources are overridden", func() {
+				BeforeEach(func() {
+					payload.Metadata = payloads.MetadataPatch{
+						Annotations: map[string]*string{
+							"korifi.cloudfoundry.org/staging-disk": tools.PtrTo("4G"),
+						},
+					}
+				})
+
+				It("succeeds", func() {
+					Expect(validatorErr).NotTo(HaveOccurred())
+				})
+
+				When("the staging disk is invalid", func() {
+					BeforeEach(func() {
+						payload.Metadata.Annotations["korifi.cloudfoundry.org/staging-disk"] = tools.PtrTo("huge")
+					})
+
+					It("returns an appropriate error", func() {
+						expectUnprocessableEntityError(validatorErr, "korifi.cloudfoundry.org/staging-disk must be an amount of memory or disk such as 512M or 2G")
+					})
+				})
+			})
+
 			When("name is invalid", func() {
 				BeforeEach(func() {
 					payload.Name = "!@#"
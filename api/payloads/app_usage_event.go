@@ -0,0 +1,30 @@
+package payloads
+
+import (
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/payloads/parse"
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+type AppUsageEventList struct {
+	GUIDs     string
+	AfterGUID string
+}
+
+func (l AppUsageEventList) ToMessage() repositories.ListAppUsageEventsMessage {
+	return repositories.ListAppUsageEventsMessage{
+		GUIDs:     parse.ArrayParam(l.GUIDs),
+		AfterGUID: l.AfterGUID,
+	}
+}
+
+func (l *AppUsageEventList) SupportedKeys() []string {
+	return []string{"guids", "after_guid", "per_page", "page"}
+}
+
+func (l *AppUsageEventList) DecodeFromURLValues(values url.Values) error {
+	l.GUIDs = values.Get("guids")
+	l.AfterGUID = values.Get("after_guid")
+	return nil
+}
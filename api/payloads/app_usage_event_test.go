@@ -0,0 +1,39 @@
+package payloads_test
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AppUsageEventList", func() {
+	DescribeTable("decodes from url values",
+		func(query string, appUsageEventList payloads.AppUsageEventList) {
+			actualAppUsageEventList := payloads.AppUsageEventList{}
+			req, err := http.NewRequest("GET", "http://foo.com/?"+query, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(validator.DecodeAndValidateURLValues(req, &actualAppUsageEventList)).To(Succeed())
+			Expect(actualAppUsageEventList).To(Equal(appUsageEventList))
+		},
+		Entry("guids", "guids=e1,e2", payloads.AppUsageEventList{GUIDs: "e1,e2"}),
+		Entry("after_guid", "after_guid=e1", payloads.AppUsageEventList{AfterGUID: "e1"}),
+		Entry("no filter", "", payloads.AppUsageEventList{}),
+	)
+
+	Describe("ToMessage()", func() {
+		It("splits the guids", func() {
+			Expect(payloads.AppUsageEventList{
+				GUIDs:     "e1,e2",
+				AfterGUID: "e0",
+			}.ToMessage()).To(Equal(repositories.ListAppUsageEventsMessage{
+				GUIDs:     []string{"e1", "e2"},
+				AfterGUID: "e0",
+			}))
+		})
+	})
+})
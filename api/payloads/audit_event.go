@@ -0,0 +1,44 @@
+package payloads
+
+import (
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/payloads/parse"
+	"code.cloudfoundry.org/korifi/api/payloads/validation"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	jellidation "github.com/jellydator/validation"
+)
+
+type AuditEventList struct {
+	Types       string
+	TargetGUIDs string
+	SpaceGUIDs  string
+	OrderBy     string
+}
+
+func (l AuditEventList) ToMessage() repositories.ListAuditEventsMessage {
+	return repositories.ListAuditEventsMessage{
+		Types:       parse.ArrayParam(l.Types),
+		TargetGUIDs: parse.ArrayParam(l.TargetGUIDs),
+		SpaceGUIDs:  parse.ArrayParam(l.SpaceGUIDs),
+		OrderBy:     l.OrderBy,
+	}
+}
+
+func (l *AuditEventList) SupportedKeys() []string {
+	return []string{"types", "target_guids", "space_guids", "order_by", "per_page", "page"}
+}
+
+func (l *AuditEventList) DecodeFromURLValues(values url.Values) error {
+	l.Types = values.Get("types")
+	l.TargetGUIDs = values.Get("target_guids")
+	l.SpaceGUIDs = values.Get("space_guids")
+	l.OrderBy = values.Get("order_by")
+	return nil
+}
+
+func (l AuditEventList) Validate() error {
+	return jellidation.ValidateStruct(&l,
+		jellidation.Field(&l.OrderBy, validation.OneOfOrderBy("created_at", "updated_at")),
+	)
+}
@@ -0,0 +1,53 @@
+package payloads_test
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AuditEventList", func() {
+	DescribeTable("decodes from url values",
+		func(query string, auditEventList payloads.AuditEventList) {
+			actualAuditEventList := payloads.AuditEventList{}
+			req, err := http.NewRequest("GET", "http://foo.com/?"+query, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(validator.DecodeAndValidateURLValues(req, &actualAuditEventList)).To(Succeed())
+			Expect(actualAuditEventList).To(Equal(auditEventList))
+		},
+		Entry("types", "types=app.crash,audit.app.update", payloads.AuditEventList{Types: "app.crash,audit.app.update"}),
+		Entry("target_guids", "target_guids=a1,a2", payloads.AuditEventList{TargetGUIDs: "a1,a2"}),
+		Entry("space_guids", "space_guids=s1,s2", payloads.AuditEventList{SpaceGUIDs: "s1,s2"}),
+		Entry("created_at", "order_by=created_at", payloads.AuditEventList{OrderBy: "created_at"}),
+		Entry("-created_at", "order_by=-created_at", payloads.AuditEventList{OrderBy: "-created_at"}),
+		Entry("no filter", "", payloads.AuditEventList{}),
+	)
+
+	It("does not accept ordering by other fields", func() {
+		req, err := http.NewRequest("GET", "http://foo.com/?order_by=type", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(validator.DecodeAndValidateURLValues(req, &payloads.AuditEventList{})).To(MatchError(ContainSubstring("value must be one of")))
+	})
+
+	Describe("ToMessage()", func() {
+		It("splits the filters", func() {
+			Expect(payloads.AuditEventList{
+				Types:       "app.crash",
+				TargetGUIDs: "a1,a2",
+				SpaceGUIDs:  "s1",
+				OrderBy:     "-created_at",
+			}.ToMessage()).To(Equal(repositories.ListAuditEventsMessage{
+				Types:       []string{"app.crash"},
+				TargetGUIDs: []string{"a1", "a2"},
+				SpaceGUIDs:  []string{"s1"},
+				OrderBy:     "-created_at",
+			}))
+		})
+	})
+})
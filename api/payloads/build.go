@@ -1,8 +1,10 @@
 package payloads
 
 import (
+	"code.cloudfoundry.org/bytefmt"
 	payload_validation "code.cloudfoundry.org/korifi/api/payloads/validation"
 	"code.cloudfoundry.org/korifi/api/repositories"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"github.com/jellydator/validation"
 )
 
@@ -38,7 +40,8 @@ func (c *BuildCreate) ToMessage(appRecord repositories.AppRecord) repositories.C
 		AppGUID:         appRecord.GUID,
 		PackageGUID:     c.Package.GUID,
 		SpaceGUID:       appRecord.SpaceGUID,
-		StagingMemoryMB: DefaultLifecycleConfig.StagingMemoryMB,
+		StagingMemoryMB: stagingMB(c.StagingMemoryMB, appRecord.Annotations[korifiv1alpha1.StagingMemoryAnnotationKey]),
+		StagingDiskMB:   stagingMB(c.StagingDiskMB, appRecord.Annotations[korifiv1alpha1.StagingDiskAnnotationKey]),
 		Lifecycle:       lifecycle,
 		Labels:          c.Metadata.Labels,
 		Annotations:     c.Metadata.Annotations,
@@ -46,3 +49,32 @@ func (c *BuildCreate) ToMessage(appRecord repositories.AppRecord) repositories.C
 
 	return toReturn
 }
+
+// BuildUpdate only supports failing a build that is still staging, which
+// cancels it
+type BuildUpdate struct {
+	State    string        `json:"state"`
+	Metadata BuildMetadata `json:"metadata"`
+}
+
+func (u BuildUpdate) Validate() error {
+	return validation.ValidateStruct(&u,
+		validation.Field(&u.State, validation.Required, payload_validation.OneOf(repositories.BuildStateFailed)),
+		validation.Field(&u.Metadata),
+	)
+}
+
+// stagingMB prefers the staging resources requested for the build over the
+// ones of the app annotations. It returns 0 when neither is set, leaving the
+// staging resources to the defaults of the installation.
+func stagingMB(requested *int, annotation string) int {
+	if requested != nil {
+		return *requested
+	}
+
+	if mb, err := bytefmt.ToMegabytes(annotation); err == nil {
+		return int(mb)
+	}
+
+	return 0
+}
@@ -7,6 +7,7 @@ import (
 
 	"code.cloudfoundry.org/korifi/api/payloads"
 	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/tools"
 )
 
 var _ = Describe("BuildCreate", func() {
@@ -104,12 +105,20 @@ var _ = Describe("BuildCreate", func() {
 	})
 
 	Describe("ToMessage", func() {
-		var createMessage repositories.CreateBuildMessage
+		var (
+			createMessage  repositories.CreateBuildMessage
+			appAnnotations map[string]string
+		)
+
+		BeforeEach(func() {
+			appAnnotations = nil
+		})
 
 		JustBeforeEach(func() {
 			createMessage = createPayload.ToMessage(repositories.AppRecord{
-				GUID:      "guid",
-				SpaceGUID: "space-guid",
+				GUID:        "guid",
+				SpaceGUID:   "space-guid",
+				Annotations: appAnnotations,
 				Lifecycle: repositories.Lifecycle{
 					Type: "docker",
 				},
@@ -118,10 +127,9 @@ var _ = Describe("BuildCreate", func() {
 
 		It("translates to create build repo message", func() {
 			Expect(createMessage).To(Equal(repositories.CreateBuildMessage{
-				AppGUID:         "guid",
-				PackageGUID:     "some-build-guid",
-				SpaceGUID:       "space-guid",
-				StagingMemoryMB: payloads.DefaultLifecycleConfig.StagingMemoryMB,
+				AppGUID:     "guid",
+				PackageGUID: "some-build-guid",
+				SpaceGUID:   "space-guid",
 				Lifecycle: repositories.Lifecycle{
 					Type: "buildpack",
 					Data: repositories.LifecycleData{
@@ -143,5 +151,87 @@ var _ = Describe("BuildCreate", func() {
 				}))
 			})
 		})
+
+		When("the app overrides the staging resources", func() {
+			BeforeEach(func() {
+				appAnnotations = map[string]string{
+					"korifi.cloudfoundry.org/staging-memory": "2G",
+					"korifi.cloudfoundry.org/staging-disk":   "512M",
+				}
+			})
+
+			It("uses the staging resources of the app", func() {
+				Expect(createMessage.StagingMemoryMB).To(Equal(2048))
+				Expect(createMessage.StagingDiskMB).To(Equal(512))
+			})
+
+			When("the staging resources are requested for the build", func() {
+				BeforeEach(func() {
+					createPayload.StagingMemoryMB = tools.PtrTo(4096)
+					createPayload.StagingDiskMB = tools.PtrTo(1024)
+				})
+
+				It("uses the requested staging resources", func() {
+					Expect(createMessage.StagingMemoryMB).To(Equal(4096))
+					Expect(createMessage.StagingDiskMB).To(Equal(1024))
+				})
+			})
+		})
+	})
+})
+
+var _ = Describe("BuildUpdate", func() {
+	var (
+		updatePayload        payloads.BuildUpdate
+		decodedUpdatePayload *payloads.BuildUpdate
+		validatorErr         error
+	)
+
+	BeforeEach(func() {
+		updatePayload = payloads.BuildUpdate{
+			State: "FAILED",
+		}
+		decodedUpdatePayload = new(payloads.BuildUpdate)
+	})
+
+	JustBeforeEach(func() {
+		validatorErr = validator.DecodeAndValidateJSONPayload(createJSONRequest(updatePayload), decodedUpdatePayload)
+	})
+
+	It("succeeds", func() {
+		Expect(validatorErr).NotTo(HaveOccurred())
+		Expect(decodedUpdatePayload).To(gstruct.PointTo(Equal(updatePayload)))
+	})
+
+	When("the state is not provided", func() {
+		BeforeEach(func() {
+			updatePayload.State = ""
+		})
+
+		It("says state is required", func() {
+			expectUnprocessableEntityError(validatorErr, "state cannot be blank")
+		})
+	})
+
+	When("the state is not FAILED", func() {
+		BeforeEach(func() {
+			updatePayload.State = "STAGED"
+		})
+
+		It("says the state is invalid", func() {
+			expectUnprocessableEntityError(validatorErr, "state value must be one of: FAILED")
+		})
+	})
+
+	When("the metadata labels is not empty", func() {
+		BeforeEach(func() {
+			updatePayload.Metadata.Labels = map[string]string{
+				"foo": "bar",
+			}
+		})
+
+		It("says labels and annotations are not supported", func() {
+			expectUnprocessableEntityError(validatorErr, "metadata.labels must be blank")
+		})
 	})
 })
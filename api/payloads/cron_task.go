@@ -0,0 +1,124 @@
+package payloads
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"code.cloudfoundry.org/korifi/api/payloads/parse"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"github.com/jellydator/validation"
+	"github.com/robfig/cron/v3"
+)
+
+type CronTaskCreate struct {
+	Command  string   `json:"command"`
+	Schedule string   `json:"schedule"`
+	Enabled  *bool    `json:"enabled"`
+	MemoryMB int64    `json:"memory_in_mb"`
+	DiskMB   int64    `json:"disk_in_mb"`
+	Metadata Metadata `json:"metadata"`
+}
+
+func (c CronTaskCreate) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Command, validation.Required),
+		validation.Field(&c.Schedule, validation.Required, validation.By(validateCronSchedule)),
+		validation.Field(&c.MemoryMB, validation.Min(0)),
+		validation.Field(&c.DiskMB, validation.Min(0)),
+		validation.Field(&c.Metadata),
+	)
+}
+
+func (c CronTaskCreate) ToMessage(appRecord repositories.AppRecord) repositories.CreateCronTaskMessage {
+	enabled := true
+	if c.Enabled != nil {
+		enabled = *c.Enabled
+	}
+
+	return repositories.CreateCronTaskMessage{
+		Command:     c.Command,
+		Schedule:    c.Schedule,
+		Enabled:     enabled,
+		MemoryMB:    c.MemoryMB,
+		DiskQuotaMB: c.DiskMB,
+		SpaceGUID:   appRecord.SpaceGUID,
+		AppGUID:     appRecord.GUID,
+		Metadata:    repositories.Metadata(c.Metadata),
+	}
+}
+
+type CronTaskUpdate struct {
+	Command  *string       `json:"command"`
+	Schedule *string       `json:"schedule"`
+	Enabled  *bool         `json:"enabled"`
+	Metadata MetadataPatch `json:"metadata"`
+}
+
+func (u CronTaskUpdate) Validate() error {
+	return validation.ValidateStruct(&u,
+		validation.Field(&u.Command, validation.NilOrNotEmpty),
+		validation.Field(&u.Schedule, validation.NilOrNotEmpty, validation.By(validateCronSchedule)),
+		validation.Field(&u.Metadata),
+	)
+}
+
+func (u CronTaskUpdate) ToMessage(cronTaskGUID, spaceGUID string) repositories.PatchCronTaskMessage {
+	return repositories.PatchCronTaskMessage{
+		GUID:      cronTaskGUID,
+		SpaceGUID: spaceGUID,
+		Command:   u.Command,
+		Schedule:  u.Schedule,
+		Enabled:   u.Enabled,
+		MetadataPatch: repositories.MetadataPatch{
+			Annotations: u.Metadata.Annotations,
+			Labels:      u.Metadata.Labels,
+		},
+	}
+}
+
+type CronTaskList struct {
+	AppGUIDs string
+}
+
+func (l CronTaskList) ToMessage() repositories.ListCronTasksMessage {
+	return repositories.ListCronTasksMessage{
+		AppGUIDs: parse.ArrayParam(l.AppGUIDs),
+	}
+}
+
+func (l *CronTaskList) SupportedKeys() []string {
+	return []string{"app_guids", "per_page", "page"}
+}
+
+func (l *CronTaskList) DecodeFromURLValues(values url.Values) error {
+	l.AppGUIDs = values.Get("app_guids")
+	return nil
+}
+
+// validateCronSchedule checks the schedule with the parser kubernetes uses
+// for CronJob schedules, i.e. five fields or a descriptor such as @daily.
+// Like kubernetes, it rejects the TZ and CRON_TZ prefixes the parser accepts,
+// as schedules are evaluated in the time zone of the controller manager
+func validateCronSchedule(value any) error {
+	value, isNil := validation.Indirect(value)
+	if isNil {
+		return nil
+	}
+
+	schedule, ok := value.(string)
+	if !ok || schedule == "" {
+		return nil
+	}
+
+	if strings.HasPrefix(schedule, "TZ=") || strings.HasPrefix(schedule, "CRON_TZ=") {
+		return errors.New("must not specify a time zone")
+	}
+
+	if _, err := cron.ParseStandard(schedule); err != nil {
+		return fmt.Errorf("must be a valid cron schedule such as \"0 2 * * *\" or \"@daily\": %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package payloads_test
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/tools"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("CronTaskList", func() {
+	DescribeTable("decodes from url values",
+		func(query string, cronTaskList payloads.CronTaskList) {
+			actualCronTaskList := payloads.CronTaskList{}
+			req, err := http.NewRequest("GET", "http://foo.com/?"+query, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(validator.DecodeAndValidateURLValues(req, &actualCronTaskList)).To(Succeed())
+			Expect(actualCronTaskList).To(Equal(cronTaskList))
+		},
+		Entry("app_guids", "app_guids=a1,a2", payloads.CronTaskList{AppGUIDs: "a1,a2"}),
+		Entry("no filter", "", payloads.CronTaskList{}),
+	)
+
+	Describe("ToMessage()", func() {
+		It("splits the app guids", func() {
+			Expect(payloads.CronTaskList{AppGUIDs: "a1,a2"}.ToMessage()).To(Equal(repositories.ListCronTasksMessage{
+				AppGUIDs: []string{"a1", "a2"},
+			}))
+		})
+	})
+})
+
+var _ = Describe("CronTaskCreate", func() {
+	var payload payloads.CronTaskCreate
+
+	BeforeEach(func() {
+		payload = payloads.CronTaskCreate{
+			Command:  "rake db:migrate",
+			Schedule: "0 2 * * *",
+			Metadata: payloads.Metadata{
+				Labels: map[string]string{"foo": "bar"},
+			},
+		}
+	})
+
+	Describe("Validate", func() {
+		var (
+			decodedPayload *payloads.CronTaskCreate
+			validatorErr   error
+		)
+
+		JustBeforeEach(func() {
+			decodedPayload = new(payloads.CronTaskCreate)
+			validatorErr = validator.DecodeAndValidateJSONPayload(createJSONRequest(payload), decodedPayload)
+		})
+
+		It("succeeds", func() {
+			Expect(validatorErr).NotTo(HaveOccurred())
+			Expect(decodedPayload).To(gstruct.PointTo(Equal(payload)))
+		})
+
+		When("the schedule is a descriptor", func() {
+			BeforeEach(func() {
+				payload.Schedule = "@daily"
+			})
+
+			It("succeeds", func() {
+				Expect(validatorErr).NotTo(HaveOccurred())
+			})
+		})
+
+		When("no command is set", func() {
+			BeforeEach(func() {
+				payload.Command = ""
+			})
+
+			It("returns an appropriate error", func() {
+				expectUnprocessableEntityError(validatorErr, "command cannot be blank")
+			})
+		})
+
+		When("no schedule is set", func() {
+			BeforeEach(func() {
+				payload.Schedule = ""
+			})
+
+			It("returns an appropriate error", func() {
+				expectUnprocessableEntityError(validatorErr, "schedule cannot be blank")
+			})
+		})
+
+		When("the schedule is invalid", func() {
+			BeforeEach(func() {
+				payload.Schedule = "every night"
+			})
+
+			It("returns an appropriate error", func() {
+				expectUnprocessableEntityError(validatorErr, "schedule must be a valid cron schedule")
+			})
+		})
+
+		When("the schedule specifies a time zone", func() {
+			BeforeEach(func() {
+				payload.Schedule = "TZ=Europe/Berlin 0 2 * * *"
+			})
+
+			It("returns an appropriate error", func() {
+				expectUnprocessableEntityError(validatorErr, "schedule must not specify a time zone")
+			})
+		})
+
+		When("the memory is negative", func() {
+			BeforeEach(func() {
+				payload.MemoryMB = -1
+			})
+
+			It("returns an appropriate error", func() {
+				expectUnprocessableEntityError(validatorErr, "memory_in_mb must be no less than 0")
+			})
+		})
+
+		When("the metadata uses the cloudfoundry.org domain", func() {
+			BeforeEach(func() {
+				payload.Metadata.Labels["foo.cloudfoundry.org/bar"] = "baz"
+			})
+
+			It("returns an appropriate error", func() {
+				expectUnprocessableEntityError(validatorErr, "label/annotation key cannot use the cloudfoundry.org domain")
+			})
+		})
+	})
+
+	Describe("ToMessage()", func() {
+		var appRecord repositories.AppRecord
+
+		BeforeEach(func() {
+			appRecord = repositories.AppRecord{GUID: "app-guid", SpaceGUID: "space-guid"}
+			payload.MemoryMB = 1024
+			payload.DiskMB = 2048
+		})
+
+		It("converts to a repo message", func() {
+			Expect(payload.ToMessage(appRecord)).To(Equal(repositories.CreateCronTaskMessage{
+				Command:     "rake db:migrate",
+				Schedule:    "0 2 * * *",
+				Enabled:     true,
+				MemoryMB:    1024,
+				DiskQuotaMB: 2048,
+				SpaceGUID:   "space-guid",
+				AppGUID:     "app-guid",
+				Metadata: repositories.Metadata{
+					Labels: map[string]string{"foo": "bar"},
+				},
+			}))
+		})
+
+		When("the cron task is not enabled", func() {
+			BeforeEach(func() {
+				payload.Enabled = tools.PtrTo(false)
+			})
+
+			It("creates a disabled cron task", func() {
+				Expect(payload.ToMessage(appRecord).Enabled).To(BeFalse())
+			})
+		})
+	})
+})
+
+var _ = Describe("CronTaskUpdate", func() {
+	var payload payloads.CronTaskUpdate
+
+	BeforeEach(func() {
+		payload = payloads.CronTaskUpdate{
+			Schedule: tools.PtrTo("@hourly"),
+			Enabled:  tools.PtrTo(false),
+			Metadata: payloads.MetadataPatch{
+				Labels: map[string]*string{"foo": tools.PtrTo("bar")},
+			},
+		}
+	})
+
+	Describe("Validate", func() {
+		var (
+			decodedPayload *payloads.CronTaskUpdate
+			validatorErr   error
+		)
+
+		JustBeforeEach(func() {
+			decodedPayload = new(payloads.CronTaskUpdate)
+			validatorErr = validator.DecodeAndValidateJSONPayload(createJSONRequest(payload), decodedPayload)
+		})
+
+		It("succeeds", func() {
+			Expect(validatorErr).NotTo(HaveOccurred())
+			Expect(decodedPayload).To(gstruct.PointTo(Equal(payload)))
+		})
+
+		When("the schedule is invalid", func() {
+			BeforeEach(func() {
+				payload.Schedule = tools.PtrTo("* * *")
+			})
+
+			It("returns an appropriate error", func() {
+				expectUnprocessableEntityError(validatorErr, "schedule must be a valid cron schedule")
+			})
+		})
+
+		When("the command is empty", func() {
+			BeforeEach(func() {
+				payload.Command = tools.PtrTo("")
+			})
+
+			It("returns an appropriate error", func() {
+				expectUnprocessableEntityError(validatorErr, "command cannot be blank")
+			})
+		})
+	})
+
+	Describe("ToMessage()", func() {
+		It("converts to a repo message", func() {
+			Expect(payload.ToMessage("cron-task-guid", "space-guid")).To(Equal(repositories.PatchCronTaskMessage{
+				GUID:      "cron-task-guid",
+				SpaceGUID: "space-guid",
+				Schedule:  tools.PtrTo("@hourly"),
+				Enabled:   tools.PtrTo(false),
+				MetadataPatch: repositories.MetadataPatch{
+					Labels: map[string]*string{"foo": tools.PtrTo("bar")},
+				},
+			}))
+		})
+	})
+})
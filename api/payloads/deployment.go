@@ -19,18 +19,26 @@ type DropletGUID struct {
 
 type DeploymentCreate struct {
 	Droplet       DropletGUID              `json:"droplet"`
+	Revision      *RevisionGUID            `json:"revision"`
 	Relationships *DeploymentRelationships `json:"relationships"`
 }
 
 func (c DeploymentCreate) Validate() error {
 	return jellidation.ValidateStruct(&c,
+		jellidation.Field(&c.Revision, jellidation.When(c.Droplet.Guid != "", jellidation.Nil.Error("cannot be set together with droplet"))),
 		jellidation.Field(&c.Relationships, jellidation.NotNil))
 }
 
 func (c *DeploymentCreate) ToMessage() repositories.CreateDeploymentMessage {
+	dropletGUID := c.Droplet.Guid
+	if c.Revision != nil {
+		// revisions are identified by the GUID of their droplet
+		dropletGUID = c.Revision.Guid
+	}
+
 	return repositories.CreateDeploymentMessage{
 		AppGUID:     c.Relationships.App.Data.GUID,
-		DropletGUID: c.Droplet.Guid,
+		DropletGUID: dropletGUID,
 	}
 }
 
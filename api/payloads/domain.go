@@ -20,7 +20,7 @@ type DomainCreate struct {
 func (c DomainCreate) Validate() error {
 	return validation.ValidateStruct(&c,
 		validation.Field(&c.Name, payload_validation.StrictlyRequired),
-		validation.Field(&c.Metadata),
+		validation.Field(&c.Metadata, validation.By(validateDomainMetadata), validation.Skip),
 		validation.Field(&c.Relationships),
 	)
 }
@@ -59,7 +59,7 @@ func (c *DomainUpdate) ToMessage(domainGUID string) repositories.UpdateDomainMes
 
 func (c DomainUpdate) Validate() error {
 	return validation.ValidateStruct(&c,
-		validation.Field(&c.Metadata),
+		validation.Field(&c.Metadata, validation.By(validateDomainMetadataPatch), validation.Skip),
 	)
 }
 
@@ -68,6 +68,19 @@ var _ = Describe("DomainCreate", func() {
 			})
 		})
 
+		When("metadata has the korifi annotations of the domain certificate", func() {
+			BeforeEach(func() {
+				createPayload.Metadata.Annotations = map[string]string{
+					"korifi.cloudfoundry.org/tls-secret-name":             "my-cert",
+					"korifi.cloudfoundry.org/cert-manager-cluster-issuer": "my-issuer",
+				}
+			})
+
+			It("succeeds", func() {
+				Expect(validatorErr).NotTo(HaveOccurred())
+			})
+		})
+
 		When("relationship is invalid", func() {
 			BeforeEach(func() {
 				createPayload.Relationships = map[string]payloads.Relationship{
@@ -181,6 +194,44 @@ var _ = Describe("DomainUpdate", func() {
 			expectUnprocessableEntityError(validatorErr, "cannot use the cloudfoundry.org domain")
 		})
 	})
+
+	When("metadata sets the external-dns annotations", func() {
+		BeforeEach(func() {
+			updatePayload.Metadata.Annotations = map[string]*string{
+				"korifi.cloudfoundry.org/external-dns":        tools.PtrTo("true"),
+				"korifi.cloudfoundry.org/external-dns-ttl":    tools.PtrTo("60"),
+				"korifi.cloudfoundry.org/external-dns-target": tools.PtrTo("lb.example.com"),
+			}
+		})
+
+		It("succeeds", func() {
+			Expect(validatorErr).NotTo(HaveOccurred())
+		})
+	})
+
+	When("the external-dns ttl is not a positive number", func() {
+		BeforeEach(func() {
+			updatePayload.Metadata.Annotations = map[string]*string{
+				"korifi.cloudfoundry.org/external-dns-ttl": tools.PtrTo("1m"),
+			}
+		})
+
+		It("returns an appropriate error", func() {
+			expectUnprocessableEntityError(validatorErr, "korifi.cloudfoundry.org/external-dns-ttl must be a positive number of seconds")
+		})
+	})
+
+	When("metadata sets the tls secret name annotation", func() {
+		BeforeEach(func() {
+			updatePayload.Metadata.Annotations = map[string]*string{
+				"korifi.cloudfoundry.org/tls-secret-name": tools.PtrTo("my-cert"),
+			}
+		})
+
+		It("succeeds", func() {
+			Expect(validatorErr).NotTo(HaveOccurred())
+		})
+	})
 })
 
 var _ = Describe("DomainList", func() {
@@ -61,3 +61,52 @@ func getBool(values url.Values, key string) (bool, error) {
 	}
 	return strconv.ParseBool(s)
 }
+
+type LogCacheQuery struct {
+	Query string
+	Time  string
+}
+
+func (q LogCacheQuery) Validate() error {
+	return jellidation.ValidateStruct(&q,
+		jellidation.Field(&q.Query, jellidation.Required),
+	)
+}
+
+func (q *LogCacheQuery) SupportedKeys() []string {
+	return []string{"query", "time"}
+}
+
+func (q *LogCacheQuery) DecodeFromURLValues(values url.Values) error {
+	q.Query = values.Get("query")
+	q.Time = values.Get("time")
+	return nil
+}
+
+type LogCacheQueryRange struct {
+	Query string
+	Start string
+	End   string
+	Step  string
+}
+
+func (q LogCacheQueryRange) Validate() error {
+	return jellidation.ValidateStruct(&q,
+		jellidation.Field(&q.Query, jellidation.Required),
+		jellidation.Field(&q.Start, jellidation.Required),
+		jellidation.Field(&q.End, jellidation.Required),
+		jellidation.Field(&q.Step, jellidation.Required),
+	)
+}
+
+func (q *LogCacheQueryRange) SupportedKeys() []string {
+	return []string{"query", "start", "end", "step"}
+}
+
+func (q *LogCacheQueryRange) DecodeFromURLValues(values url.Values) error {
+	q.Query = values.Get("query")
+	q.Start = values.Get("start")
+	q.End = values.Get("end")
+	q.Step = values.Get("step")
+	return nil
+}
@@ -40,3 +40,56 @@ var _ = Describe("LogRead", func() {
 		)
 	})
 })
+
+var _ = Describe("LogCacheQuery", func() {
+	DescribeTable("valid query",
+		func(query string, expected payloads.LogCacheQuery) {
+			actual, decodeErr := decodeQuery[payloads.LogCacheQuery](query)
+
+			Expect(decodeErr).NotTo(HaveOccurred())
+			Expect(*actual).To(Equal(expected))
+		},
+		Entry("all fields", `query=cpu{source_id="app-guid"}&time=123`, payloads.LogCacheQuery{
+			Query: `cpu{source_id="app-guid"}`,
+			Time:  "123",
+		}),
+		Entry("no time", `query=cpu`, payloads.LogCacheQuery{Query: "cpu"}),
+	)
+
+	DescribeTable("invalid query",
+		func(query string, expectedErrMsg string) {
+			_, decodeErr := decodeQuery[payloads.LogCacheQuery](query)
+			Expect(decodeErr).To(MatchError(ContainSubstring(expectedErrMsg)))
+		},
+		Entry("missing query", "time=123", "Query: cannot be blank"),
+		Entry("unsupported key", "query=cpu&foo=bar", "unsupported query parameter"),
+	)
+})
+
+var _ = Describe("LogCacheQueryRange", func() {
+	DescribeTable("valid query",
+		func(query string, expected payloads.LogCacheQueryRange) {
+			actual, decodeErr := decodeQuery[payloads.LogCacheQueryRange](query)
+
+			Expect(decodeErr).NotTo(HaveOccurred())
+			Expect(*actual).To(Equal(expected))
+		},
+		Entry("all fields", `query=cpu&start=1&end=2&step=3s`, payloads.LogCacheQueryRange{
+			Query: "cpu",
+			Start: "1",
+			End:   "2",
+			Step:  "3s",
+		}),
+	)
+
+	DescribeTable("invalid query",
+		func(query string, expectedErrMsg string) {
+			_, decodeErr := decodeQuery[payloads.LogCacheQueryRange](query)
+			Expect(decodeErr).To(MatchError(ContainSubstring(expectedErrMsg)))
+		},
+		Entry("missing query", "start=1&end=2&step=3s", "Query: cannot be blank"),
+		Entry("missing start", "query=cpu&end=2&step=3s", "Start: cannot be blank"),
+		Entry("missing end", "query=cpu&start=1&step=3s", "End: cannot be blank"),
+		Entry("missing step", "query=cpu&start=1&end=2", "Step: cannot be blank"),
+	)
+})
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 
 	"code.cloudfoundry.org/korifi/api/repositories"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
@@ -33,19 +34,24 @@ type ManifestApplication struct {
 	// Do not set both DiskQuota and AltDiskQuota.
 	//
 	// Deprecated: Use DiskQuota instead
-	AltDiskQuota                 *string                      `json:"disk-quota" yaml:"disk-quota"`
-	HealthCheckHTTPEndpoint      *string                      `yaml:"health-check-http-endpoint"`
-	HealthCheckInvocationTimeout *int32                       `json:"health-check-invocation-timeout" yaml:"health-check-invocation-timeout"`
-	HealthCheckType              *string                      `json:"health-check-type" yaml:"health-check-type"`
-	Timeout                      *int32                       `json:"timeout" yaml:"timeout"`
-	Processes                    []ManifestApplicationProcess `json:"processes" yaml:"processes"`
-	Routes                       []ManifestRoute              `json:"routes" yaml:"routes"`
-	Buildpacks                   []string                     `yaml:"buildpacks"`
+	AltDiskQuota                          *string                      `json:"disk-quota" yaml:"disk-quota"`
+	HealthCheckHTTPEndpoint               *string                      `yaml:"health-check-http-endpoint"`
+	HealthCheckInvocationTimeout          *int32                       `json:"health-check-invocation-timeout" yaml:"health-check-invocation-timeout"`
+	HealthCheckType                       *string                      `json:"health-check-type" yaml:"health-check-type"`
+	ReadinessHealthCheckHTTPEndpoint      *string                      `yaml:"readiness-health-check-http-endpoint"`
+	ReadinessHealthCheckInvocationTimeout *int32                       `json:"readiness-health-check-invocation-timeout" yaml:"readiness-health-check-invocation-timeout"`
+	ReadinessHealthCheckInterval          *int32                       `json:"readiness-health-check-interval" yaml:"readiness-health-check-interval"`
+	ReadinessHealthCheckType              *string                      `json:"readiness-health-check-type" yaml:"readiness-health-check-type"`
+	Timeout                               *int32                       `json:"timeout" yaml:"timeout"`
+	Processes                             []ManifestApplicationProcess `json:"processes" yaml:"processes"`
+	Routes                                []ManifestRoute              `json:"routes" yaml:"routes"`
+	Buildpacks                            []string                     `yaml:"buildpacks"`
 	// Deprecated: Use Buildpacks instead
 	Buildpack *string                      `json:"buildpack" yaml:"buildpack"`
 	Metadata  MetadataPatch                `json:"metadata" yaml:"metadata"`
 	Services  []ManifestApplicationService `json:"services" yaml:"services"`
-	Docker    any                          `json:"docker,omitempty" yaml:"docker,omitempty"`
+	Sidecars  []ManifestApplicationSidecar `json:"sidecars" yaml:"sidecars"`
+	Docker    *ManifestApplicationDocker   `json:"docker,omitempty" yaml:"docker,omitempty"`
 }
 
 // TODO: Why is kebab-case used everywhere anyway and we have a deprecated field that claims to use
@@ -58,18 +64,30 @@ type ManifestApplicationProcess struct {
 	// Do not set both DiskQuota and AltDiskQuota.
 	//
 	// Deprecated: Use DiskQuota instead
-	AltDiskQuota                 *string `json:"disk-quota" yaml:"disk-quota"`
-	HealthCheckHTTPEndpoint      *string `yaml:"health-check-http-endpoint"`
-	HealthCheckInvocationTimeout *int32  `json:"health-check-invocation-timeout" yaml:"health-check-invocation-timeout"`
-	HealthCheckType              *string `json:"health-check-type" yaml:"health-check-type"`
-	Instances                    *int32  `json:"instances" yaml:"instances"`
-	Memory                       *string `json:"memory" yaml:"memory"`
-	Timeout                      *int32  `json:"timeout" yaml:"timeout"`
+	AltDiskQuota                          *string `json:"disk-quota" yaml:"disk-quota"`
+	HealthCheckHTTPEndpoint               *string `yaml:"health-check-http-endpoint"`
+	HealthCheckInvocationTimeout          *int32  `json:"health-check-invocation-timeout" yaml:"health-check-invocation-timeout"`
+	HealthCheckType                       *string `json:"health-check-type" yaml:"health-check-type"`
+	ReadinessHealthCheckHTTPEndpoint      *string `yaml:"readiness-health-check-http-endpoint"`
+	ReadinessHealthCheckInvocationTimeout *int32  `json:"readiness-health-check-invocation-timeout" yaml:"readiness-health-check-invocation-timeout"`
+	ReadinessHealthCheckInterval          *int32  `json:"readiness-health-check-interval" yaml:"readiness-health-check-interval"`
+	ReadinessHealthCheckType              *string `json:"readiness-health-check-type" yaml:"readiness-health-check-type"`
+	Instances                             *int32  `json:"instances" yaml:"instances"`
+	Memory                                *string `json:"memory" yaml:"memory"`
+	Timeout                               *int32  `json:"timeout" yaml:"timeout"`
+}
+
+type ManifestApplicationSidecar struct {
+	Name         string   `json:"name" yaml:"name"`
+	Command      string   `json:"command" yaml:"command"`
+	ProcessTypes []string `json:"process_types" yaml:"process_types"`
+	Memory       *string  `json:"memory" yaml:"memory"`
 }
 
 type ManifestApplicationService struct {
-	Name        string  `json:"name" yaml:"name"`
-	BindingName *string `json:"binding_name" yaml:"binding_name"`
+	Name        string         `json:"name" yaml:"name"`
+	BindingName *string        `json:"binding_name" yaml:"binding_name"`
+	Parameters  map[string]any `json:"parameters,omitempty" yaml:"parameters,omitempty"`
 }
 
 func (s *ManifestApplicationService) UnmarshalYAML(value *yaml.Node) error {
@@ -87,6 +105,15 @@ func (s *ManifestApplicationService) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+// ManifestApplicationDocker is the docker image the app runs. The registry
+// password is not part of the manifest: clients read it from the
+// CF_DOCKER_PASSWORD environment variable when they create the docker package
+// of the app.
+type ManifestApplicationDocker struct {
+	Image    string  `json:"image" yaml:"image"`
+	Username *string `json:"username,omitempty" yaml:"username,omitempty"`
+}
+
 type ManifestRoute struct {
 	Route *string `json:"route" yaml:"route"`
 }
@@ -144,6 +171,28 @@ func (a ManifestApplication) ToAppPatchMessage(appGUID, spaceGUID string) reposi
 	}
 }
 
+// SidecarsForProcess returns the sidecars of the app that run next to the
+// process of the given type
+func (a ManifestApplication) SidecarsForProcess(processType string) []repositories.Sidecar {
+	sidecars := []repositories.Sidecar{}
+	for _, sidecar := range a.Sidecars {
+		if !slices.Contains(sidecar.ProcessTypes, processType) {
+			continue
+		}
+
+		repoSidecar := repositories.Sidecar{
+			Name:    sidecar.Name,
+			Command: sidecar.Command,
+		}
+		if sidecar.Memory != nil {
+			repoSidecar.MemoryMB = parseMegabytes(*sidecar.Memory)
+		}
+		sidecars = append(sidecars, repoSidecar)
+	}
+
+	return sidecars
+}
+
 func (p ManifestApplicationProcess) ToProcessCreateMessage(appGUID, spaceGUID string) repositories.CreateProcessMessage {
 	msg := repositories.CreateProcessMessage{
 		AppGUID:   appGUID,
@@ -169,6 +218,18 @@ func (p ManifestApplicationProcess) ToProcessCreateMessage(appGUID, spaceGUID st
 			msg.HealthCheck.Type = "process"
 		}
 	}
+	if p.ReadinessHealthCheckHTTPEndpoint != nil {
+		msg.ReadinessHealthCheck.Data.HTTPEndpoint = *p.ReadinessHealthCheckHTTPEndpoint
+	}
+	if p.ReadinessHealthCheckInvocationTimeout != nil {
+		msg.ReadinessHealthCheck.Data.InvocationTimeoutSeconds = *p.ReadinessHealthCheckInvocationTimeout
+	}
+	if p.ReadinessHealthCheckInterval != nil {
+		msg.ReadinessHealthCheck.Data.IntervalSeconds = *p.ReadinessHealthCheckInterval
+	}
+	if p.ReadinessHealthCheckType != nil {
+		msg.ReadinessHealthCheck.Type = *p.ReadinessHealthCheckType
+	}
 	msg.DesiredInstances = p.Instances
 
 	if p.Memory != nil {
@@ -184,13 +245,17 @@ func (p ManifestApplicationProcess) ToProcessCreateMessage(appGUID, spaceGUID st
 
 func (p ManifestApplicationProcess) ToProcessPatchMessage(processGUID, spaceGUID string) repositories.PatchProcessMessage {
 	message := repositories.PatchProcessMessage{
-		ProcessGUID:                         processGUID,
-		SpaceGUID:                           spaceGUID,
-		Command:                             p.Command,
-		HealthCheckHTTPEndpoint:             p.HealthCheckHTTPEndpoint,
-		HealthCheckInvocationTimeoutSeconds: p.HealthCheckInvocationTimeout,
-		HealthCheckTimeoutSeconds:           p.Timeout,
-		DesiredInstances:                    p.Instances,
+		ProcessGUID:                                  processGUID,
+		SpaceGUID:                                    spaceGUID,
+		Command:                                      p.Command,
+		HealthCheckHTTPEndpoint:                      p.HealthCheckHTTPEndpoint,
+		HealthCheckInvocationTimeoutSeconds:          p.HealthCheckInvocationTimeout,
+		HealthCheckTimeoutSeconds:                    p.Timeout,
+		ReadinessHealthCheckType:                     p.ReadinessHealthCheckType,
+		ReadinessHealthCheckHTTPEndpoint:             p.ReadinessHealthCheckHTTPEndpoint,
+		ReadinessHealthCheckInvocationTimeoutSeconds: p.ReadinessHealthCheckInvocationTimeout,
+		ReadinessHealthCheckIntervalSeconds:          p.ReadinessHealthCheckInterval,
+		DesiredInstances:                             p.Instances,
 	}
 	if p.HealthCheckType != nil {
 		message.HealthCheckType = p.HealthCheckType
@@ -221,10 +286,14 @@ func (a ManifestApplication) Validate() error {
 		validation.Field(&a.Instances, validation.Min(0)),
 		validation.Field(&a.HealthCheckInvocationTimeout, validation.Min(1), validation.NilOrNotEmpty.Error("must be no less than 1")),
 		validation.Field(&a.HealthCheckType, validation.In("none", "process", "port", "http")),
+		validation.Field(&a.ReadinessHealthCheckInvocationTimeout, validation.Min(1), validation.NilOrNotEmpty.Error("must be no less than 1")),
+		validation.Field(&a.ReadinessHealthCheckInterval, validation.Min(1), validation.NilOrNotEmpty.Error("must be no less than 1")),
+		validation.Field(&a.ReadinessHealthCheckType, validation.In("process", "port", "http")),
 		validation.Field(&a.Memory, validation.By(validateAmountWithUnit)),
 		validation.Field(&a.Timeout, validation.Min(1), validation.NilOrNotEmpty.Error("must be no less than 1")),
 		validation.Field(&a.Processes),
 		validation.Field(&a.Routes),
+		validation.Field(&a.Sidecars, validation.By(validateUniqueSidecarNames), validation.By(a.validateSidecarMemory)),
 		validation.Field(&a.Docker, validation.When(len(a.Buildpacks) > 0 || a.Buildpack != nil,
 			validation.Nil.Error("must be blank when buildpacks are specified"),
 		)),
@@ -238,6 +307,9 @@ func (p ManifestApplicationProcess) Validate() error {
 		validation.Field(&p.AltDiskQuota, validation.By(validateAmountWithUnit)),
 		validation.Field(&p.HealthCheckInvocationTimeout, validation.Min(1), validation.NilOrNotEmpty.Error("must be no less than 1")),
 		validation.Field(&p.HealthCheckType, validation.In("none", "process", "port", "http")),
+		validation.Field(&p.ReadinessHealthCheckInvocationTimeout, validation.Min(1), validation.NilOrNotEmpty.Error("must be no less than 1")),
+		validation.Field(&p.ReadinessHealthCheckInterval, validation.Min(1), validation.NilOrNotEmpty.Error("must be no less than 1")),
+		validation.Field(&p.ReadinessHealthCheckType, validation.In("process", "port", "http")),
 		validation.Field(&p.Instances, validation.Min(0)),
 		validation.Field(&p.Memory, validation.By(validateAmountWithUnit)),
 		validation.Field(&p.Timeout, validation.Min(1), validation.NilOrNotEmpty.Error("must be no less than 1")),
@@ -252,8 +324,82 @@ func (m ManifestRoute) Validate() error {
 		validation.Field(&m.Route, validation.Match(routeRegex).Error("is not a valid route")))
 }
 
+func (s ManifestApplicationSidecar) Validate() error {
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.Name, validation.Required, validation.By(validateSidecarName)),
+		validation.Field(&s.Command, validation.Required),
+		validation.Field(&s.ProcessTypes, validation.Required),
+		validation.Field(&s.Memory, validation.By(validateAmountWithUnit)),
+	)
+}
+
+// sidecars run as containers named after them, so their names must be valid
+// container names once prefixed
+var sidecarName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,53}[a-z0-9])?$`)
+
+func validateSidecarName(value any) error {
+	if !sidecarName.MatchString(value.(string)) {
+		return errors.New("must consist of at most 55 lower case alphanumeric characters or '-', and must start and end with an alphanumeric character")
+	}
+
+	return nil
+}
+
+// validateSidecarMemory checks that the sidecars leave memory to the processes
+// they run next to, as their memory is taken from the memory of the process.
+// The memory of processes that the manifest does not set is checked by the
+// process controller.
+func (a ManifestApplication) validateSidecarMemory(value any) error {
+	processMemory := map[string]string{}
+	if a.Memory != nil {
+		processMemory[korifiv1alpha1.ProcessTypeWeb] = *a.Memory
+	}
+	for _, process := range a.Processes {
+		if process.Memory != nil {
+			processMemory[process.Type] = *process.Memory
+		}
+	}
+
+	for processType, memory := range processMemory {
+		sidecarMemoryMB := int64(0)
+		for _, sidecar := range a.SidecarsForProcess(processType) {
+			sidecarMemoryMB += sidecar.MemoryMB
+		}
+
+		if sidecarMemoryMB > 0 && sidecarMemoryMB >= parseMegabytes(memory) {
+			return fmt.Errorf("memory of the %q process must be greater than the total memory of its sidecars", processType)
+		}
+	}
+
+	return nil
+}
+
+func validateUniqueSidecarNames(value any) error {
+	names := map[string]bool{}
+	for _, sidecar := range value.([]ManifestApplicationSidecar) {
+		if names[sidecar.Name] {
+			return fmt.Errorf("name %q is used by more than one sidecar", sidecar.Name)
+		}
+		names[sidecar.Name] = true
+	}
+
+	return nil
+}
+
+func (d ManifestApplicationDocker) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.Image, validation.Required),
+		validation.Field(&d.Username, validation.NilOrNotEmpty),
+	)
+}
+
 func (s ManifestApplicationService) Validate() error {
-	return validation.ValidateStruct(&s, validation.Field(&s.Name, validation.Required))
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.Name, validation.Required),
+		// binding parameters are passed to service brokers, which korifi does
+		// not bind managed service instances with yet
+		validation.Field(&s.Parameters, validation.Empty.Error("are not supported")),
+	)
 }
 
 var unitAmount = regexp.MustCompile(`^\d+(?:B|K|KB|M|m|MB|mb|G|g|GB|gb|T|t|TB|tb)$`)
@@ -174,6 +174,36 @@ var _ = Describe("Manifest payload", func() {
 				})
 			})
 
+			When("ReadinessHealthCheckInvocationTimeout is not positive", func() {
+				BeforeEach(func() {
+					testManifest.ReadinessHealthCheckInvocationTimeout = tools.PtrTo(int32(0))
+				})
+
+				It("returns a validation error", func() {
+					expectUnprocessableEntityError(validateErr, "readiness-health-check-invocation-timeout must be no less than 1")
+				})
+			})
+
+			When("ReadinessHealthCheckInterval is not positive", func() {
+				BeforeEach(func() {
+					testManifest.ReadinessHealthCheckInterval = tools.PtrTo(int32(0))
+				})
+
+				It("returns a validation error", func() {
+					expectUnprocessableEntityError(validateErr, "readiness-health-check-interval must be no less than 1")
+				})
+			})
+
+			When("ReadinessHealthCheckType is invalid", func() {
+				BeforeEach(func() {
+					testManifest.ReadinessHealthCheckType = tools.PtrTo("none")
+				})
+
+				It("returns a validation error", func() {
+					expectUnprocessableEntityError(validateErr, "readiness-health-check-type must be a valid value")
+				})
+			})
+
 			When("Timeout is not positive", func() {
 				BeforeEach(func() {
 					testManifest.Timeout = tools.PtrTo(int32(0))
@@ -237,7 +267,7 @@ var _ = Describe("Manifest payload", func() {
 
 				When("docker is specified", func() {
 					BeforeEach(func() {
-						testManifest.Docker = struct{}{}
+						testManifest.Docker = &ManifestApplicationDocker{Image: "my/image"}
 					})
 				})
 			})
@@ -253,20 +283,40 @@ var _ = Describe("Manifest payload", func() {
 
 				When("docker is specified", func() {
 					BeforeEach(func() {
-						testManifest.Docker = struct{}{}
+						testManifest.Docker = &ManifestApplicationDocker{Image: "my/image"}
 					})
 				})
 			})
 
 			When("docker is specified", func() {
 				BeforeEach(func() {
-					testManifest.Docker = struct{}{}
+					testManifest.Docker = &ManifestApplicationDocker{Image: "my/image"}
 				})
 
 				It("does not return a validation error", func() {
 					Expect(validateErr).NotTo(HaveOccurred())
 				})
 
+				When("the image is not specified", func() {
+					BeforeEach(func() {
+						testManifest.Docker.Image = ""
+					})
+
+					It("returns a validation error", func() {
+						expectUnprocessableEntityError(validateErr, "image cannot be blank")
+					})
+				})
+
+				When("the username is empty", func() {
+					BeforeEach(func() {
+						testManifest.Docker.Username = tools.PtrTo("")
+					})
+
+					It("returns a validation error", func() {
+						expectUnprocessableEntityError(validateErr, "username cannot be blank")
+					})
+				})
+
 				When("buildpack is specified", func() {
 					BeforeEach(func() {
 						testManifest.Buildpack = tools.PtrTo("foo")
@@ -287,6 +337,85 @@ var _ = Describe("Manifest payload", func() {
 					})
 				})
 			})
+
+			When("sidecar names are not unique", func() {
+				BeforeEach(func() {
+					testManifest.Sidecars = []ManifestApplicationSidecar{
+						{Name: "my-sidecar", Command: "foo", ProcessTypes: []string{"web"}},
+						{Name: "my-sidecar", Command: "bar", ProcessTypes: []string{"worker"}},
+					}
+				})
+
+				It("returns a validation error", func() {
+					expectUnprocessableEntityError(validateErr, `sidecars name "my-sidecar" is used by more than one sidecar`)
+				})
+			})
+
+			When("the sidecars use all the memory of the process", func() {
+				BeforeEach(func() {
+					testManifest.Processes = []ManifestApplicationProcess{{Type: "worker", Memory: tools.PtrTo("1G")}}
+					testManifest.Sidecars = []ManifestApplicationSidecar{
+						{Name: "my-sidecar", Command: "foo", ProcessTypes: []string{"worker"}, Memory: tools.PtrTo("512M")},
+						{Name: "other-sidecar", Command: "bar", ProcessTypes: []string{"worker"}, Memory: tools.PtrTo("512M")},
+					}
+				})
+
+				It("returns a validation error", func() {
+					expectUnprocessableEntityError(validateErr, `sidecars memory of the "worker" process must be greater than the total memory of its sidecars`)
+				})
+			})
+
+			When("the sidecars use all the memory of the app", func() {
+				BeforeEach(func() {
+					testManifest.Memory = tools.PtrTo("512M")
+					testManifest.Sidecars = []ManifestApplicationSidecar{
+						{Name: "my-sidecar", Command: "foo", ProcessTypes: []string{"web"}, Memory: tools.PtrTo("1G")},
+					}
+				})
+
+				It("returns a validation error", func() {
+					expectUnprocessableEntityError(validateErr, `sidecars memory of the "web" process must be greater than the total memory of its sidecars`)
+				})
+			})
+
+			When("the sidecars leave memory to the process", func() {
+				BeforeEach(func() {
+					testManifest.Memory = tools.PtrTo("1G")
+					testManifest.Sidecars = []ManifestApplicationSidecar{
+						{Name: "my-sidecar", Command: "foo", ProcessTypes: []string{"web"}, Memory: tools.PtrTo("256M")},
+					}
+				})
+
+				It("validates", func() {
+					Expect(validateErr).NotTo(HaveOccurred())
+				})
+			})
+		})
+
+		Describe("SidecarsForProcess", func() {
+			BeforeEach(func() {
+				testManifest = ManifestApplication{
+					Name: "test-app",
+					Sidecars: []ManifestApplicationSidecar{
+						{Name: "web-sidecar", Command: "foo", ProcessTypes: []string{"web"}, Memory: tools.PtrTo("1G")},
+						{Name: "shared-sidecar", Command: "bar", ProcessTypes: []string{"web", "worker"}},
+					},
+				}
+			})
+
+			It("returns the sidecars of the process type", func() {
+				Expect(testManifest.SidecarsForProcess("web")).To(Equal([]repositories.Sidecar{
+					{Name: "web-sidecar", Command: "foo", MemoryMB: 1024},
+					{Name: "shared-sidecar", Command: "bar"},
+				}))
+				Expect(testManifest.SidecarsForProcess("worker")).To(Equal([]repositories.Sidecar{
+					{Name: "shared-sidecar", Command: "bar"},
+				}))
+			})
+
+			It("returns an empty list for process types without sidecars", func() {
+				Expect(testManifest.SidecarsForProcess("other")).To(BeEmpty())
+			})
 		})
 
 		Describe("ToAppCreateMessage", func() {
@@ -356,7 +485,7 @@ var _ = Describe("Manifest payload", func() {
 								"l2": tools.PtrTo("v2"),
 							},
 						},
-						Docker: struct{}{},
+						Docker: &ManifestApplicationDocker{Image: "my/image"},
 					}
 				})
 
@@ -487,6 +616,36 @@ var _ = Describe("Manifest payload", func() {
 				})
 			})
 
+			When("ReadinessHealthCheckInvocationTimeout is not positive", func() {
+				BeforeEach(func() {
+					testManifestProcess.ReadinessHealthCheckInvocationTimeout = tools.PtrTo(int32(0))
+				})
+
+				It("returns a validation error", func() {
+					expectUnprocessableEntityError(validateErr, "readiness-health-check-invocation-timeout must be no less than 1")
+				})
+			})
+
+			When("ReadinessHealthCheckInterval is not positive", func() {
+				BeforeEach(func() {
+					testManifestProcess.ReadinessHealthCheckInterval = tools.PtrTo(int32(0))
+				})
+
+				It("returns a validation error", func() {
+					expectUnprocessableEntityError(validateErr, "readiness-health-check-interval must be no less than 1")
+				})
+			})
+
+			When("ReadinessHealthCheckType is invalid", func() {
+				BeforeEach(func() {
+					testManifestProcess.ReadinessHealthCheckType = tools.PtrTo("none")
+				})
+
+				It("returns a validation error", func() {
+					expectUnprocessableEntityError(validateErr, "readiness-health-check-type must be a valid value")
+				})
+			})
+
 			When("Instances is negative", func() {
 				BeforeEach(func() {
 					testManifestProcess.Instances = tools.PtrTo[int32](-1)
@@ -535,15 +694,19 @@ var _ = Describe("Manifest payload", func() {
 			When("all fields are specified", func() {
 				BeforeEach(func() {
 					processInfo = ManifestApplicationProcess{
-						Type:                         "web",
-						Command:                      tools.PtrTo("start-web.sh"),
-						DiskQuota:                    tools.PtrTo("512M"),
-						HealthCheckHTTPEndpoint:      tools.PtrTo("/stuff"),
-						HealthCheckInvocationTimeout: tools.PtrTo(int32(90)),
-						HealthCheckType:              tools.PtrTo("http"),
-						Instances:                    tools.PtrTo[int32](3),
-						Memory:                       tools.PtrTo("1G"),
-						Timeout:                      tools.PtrTo(int32(60)),
+						Type:                                  "web",
+						Command:                               tools.PtrTo("start-web.sh"),
+						DiskQuota:                             tools.PtrTo("512M"),
+						HealthCheckHTTPEndpoint:               tools.PtrTo("/stuff"),
+						HealthCheckInvocationTimeout:          tools.PtrTo(int32(90)),
+						HealthCheckType:                       tools.PtrTo("http"),
+						ReadinessHealthCheckHTTPEndpoint:      tools.PtrTo("/ready"),
+						ReadinessHealthCheckInvocationTimeout: tools.PtrTo(int32(2)),
+						ReadinessHealthCheckInterval:          tools.PtrTo(int32(5)),
+						ReadinessHealthCheckType:              tools.PtrTo("http"),
+						Instances:                             tools.PtrTo[int32](3),
+						Memory:                                tools.PtrTo("1G"),
+						Timeout:                               tools.PtrTo(int32(60)),
 					}
 				})
 
@@ -564,6 +727,14 @@ var _ = Describe("Manifest payload", func() {
 								InvocationTimeoutSeconds: 90,
 							},
 						},
+						ReadinessHealthCheck: repositories.ReadinessHealthCheck{
+							Type: "http",
+							Data: repositories.ReadinessHealthCheckData{
+								HTTPEndpoint:             "/ready",
+								InvocationTimeoutSeconds: 2,
+								IntervalSeconds:          5,
+							},
+						},
 						DesiredInstances: tools.PtrTo[int32](3),
 						MemoryMB:         1024,
 					}))
@@ -652,6 +823,23 @@ var _ = Describe("Manifest payload", func() {
 				})
 			})
 
+			When("the readiness health check is specified", func() {
+				BeforeEach(func() {
+					processInfo.ReadinessHealthCheckType = tools.PtrTo("http")
+					processInfo.ReadinessHealthCheckHTTPEndpoint = tools.PtrTo("/ready")
+					processInfo.ReadinessHealthCheckInvocationTimeout = tools.PtrTo(int32(2))
+					processInfo.ReadinessHealthCheckInterval = tools.PtrTo(int32(5))
+				})
+
+				It("returns a message with the readiness health check set", func() {
+					message := processInfo.ToProcessPatchMessage(processGUID, spaceGUID)
+					Expect(message.ReadinessHealthCheckType).To(PointTo(Equal("http")))
+					Expect(message.ReadinessHealthCheckHTTPEndpoint).To(PointTo(Equal("/ready")))
+					Expect(message.ReadinessHealthCheckInvocationTimeoutSeconds).To(PointTo(BeEquivalentTo(2)))
+					Expect(message.ReadinessHealthCheckIntervalSeconds).To(PointTo(BeEquivalentTo(5)))
+				})
+			})
+
 			When("DiskQuota is specified", func() {
 				BeforeEach(func() {
 					processInfo.DiskQuota = tools.PtrTo("1G")
@@ -714,6 +902,82 @@ var _ = Describe("Manifest payload", func() {
 		})
 	})
 
+	Describe("ManifestApplicationSidecar", func() {
+		Describe("Validate", func() {
+			var (
+				validateErr         error
+				testManifestSidecar ManifestApplicationSidecar
+			)
+
+			BeforeEach(func() {
+				testManifestSidecar = ManifestApplicationSidecar{
+					Name:         "my-sidecar",
+					Command:      "run-sidecar",
+					ProcessTypes: []string{"web"},
+					Memory:       tools.PtrTo("256M"),
+				}
+			})
+
+			JustBeforeEach(func() {
+				validateErr = validator.DecodeAndValidateYAMLPayload(createYAMLRequest(testManifestSidecar), &ManifestApplicationSidecar{})
+			})
+
+			It("validates the struct", func() {
+				Expect(validateErr).NotTo(HaveOccurred())
+			})
+
+			When("name is not specified", func() {
+				BeforeEach(func() {
+					testManifestSidecar.Name = ""
+				})
+
+				It("returns a validation error", func() {
+					expectUnprocessableEntityError(validateErr, "name cannot be blank")
+				})
+			})
+
+			When("name is not a valid container name", func() {
+				BeforeEach(func() {
+					testManifestSidecar.Name = "My_Sidecar"
+				})
+
+				It("returns a validation error", func() {
+					expectUnprocessableEntityError(validateErr, "name must consist of at most 55 lower case alphanumeric characters")
+				})
+			})
+
+			When("command is not specified", func() {
+				BeforeEach(func() {
+					testManifestSidecar.Command = ""
+				})
+
+				It("returns a validation error", func() {
+					expectUnprocessableEntityError(validateErr, "command cannot be blank")
+				})
+			})
+
+			When("process_types is not specified", func() {
+				BeforeEach(func() {
+					testManifestSidecar.ProcessTypes = nil
+				})
+
+				It("returns a validation error", func() {
+					expectUnprocessableEntityError(validateErr, "process_types cannot be blank")
+				})
+			})
+
+			When("memory units are not valid", func() {
+				BeforeEach(func() {
+					testManifestSidecar.Memory = tools.PtrTo("5CUPS")
+				})
+
+				It("returns a validation error", func() {
+					expectUnprocessableEntityError(validateErr, "memory must use a supported unit")
+				})
+			})
+		})
+	})
+
 	Describe("ManifestRoute", func() {
 		var (
 			validateErr       error
@@ -764,6 +1028,21 @@ var _ = Describe("Manifest payload", func() {
 				}))
 			})
 
+			When("binding parameters are specified", func() {
+				BeforeEach(func() {
+					serviceString = "name: my-svc\nbinding_name: my-binding\nparameters:\n  foo: bar\n  count: 2"
+				})
+
+				It("unmarshals them", func() {
+					Expect(unmarshalErr).NotTo(HaveOccurred())
+					Expect(unmarshalledService).To(Equal(ManifestApplicationService{
+						Name:        "my-svc",
+						BindingName: tools.PtrTo("my-binding"),
+						Parameters:  map[string]any{"foo": "bar", "count": 2},
+					}))
+				})
+			})
+
 			When("the name tag is missing", func() {
 				BeforeEach(func() {
 					serviceString = "my-svc"
@@ -817,6 +1096,16 @@ var _ = Describe("Manifest payload", func() {
 					expectUnprocessableEntityError(validateErr, "name cannot be blank")
 				})
 			})
+
+			When("binding parameters are specified", func() {
+				BeforeEach(func() {
+					testManifestServices.Parameters = map[string]any{"foo": "bar"}
+				})
+
+				It("returns a validation error", func() {
+					expectUnprocessableEntityError(validateErr, "parameters are not supported")
+				})
+			})
 		})
 	})
 })
@@ -0,0 +1,97 @@
+package payloads
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// manifestVariable matches `((name))` placeholders, as interpolated by the CF
+// CLI for `--var` and `--vars-file`
+var manifestVariable = regexp.MustCompile(`\(\(([-/.\w\pL]+)\)\)`)
+
+// InterpolateManifestVariables resolves the `((name))` placeholders of a
+// manifest. The variables are read from an optional second YAML document in
+// the manifest, a map of names to values like a CLI vars file. Manifests
+// without variables are returned unchanged, so that placeholders resolved by
+// the client are not touched.
+func InterpolateManifestVariables(manifest []byte) ([]byte, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(manifest))
+
+	var manifestNode yaml.Node
+	if err := decoder.Decode(&manifestNode); err != nil {
+		if errors.Is(err, io.EOF) {
+			return manifest, nil
+		}
+		return nil, apierrors.NewMessageParseError(err)
+	}
+
+	var variables map[string]any
+	if err := decoder.Decode(&variables); err != nil {
+		if errors.Is(err, io.EOF) {
+			return manifest, nil
+		}
+		return nil, apierrors.NewMessageParseError(fmt.Errorf("invalid manifest variables: %w", err))
+	}
+
+	missing := map[string]bool{}
+	if err := interpolateNode(&manifestNode, variables, missing); err != nil {
+		return nil, apierrors.NewUnprocessableEntityError(err, err.Error())
+	}
+
+	if len(missing) > 0 {
+		names := slices.Sorted(maps.Keys(missing))
+		return nil, apierrors.NewUnprocessableEntityError(nil, "Expected to find variables: "+strings.Join(names, ", "))
+	}
+
+	return yaml.Marshal(&manifestNode)
+}
+
+func interpolateNode(node *yaml.Node, variables map[string]any, missing map[string]bool) error {
+	if node.Kind != yaml.ScalarNode {
+		for _, child := range node.Content {
+			if err := interpolateNode(child, variables, missing); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if match := manifestVariable.FindStringSubmatch(node.Value); match != nil && match[0] == node.Value {
+		value, ok := variables[match[1]]
+		if !ok {
+			missing[match[1]] = true
+			return nil
+		}
+
+		return node.Encode(value)
+	}
+
+	var interpolationErr error
+	node.Value = manifestVariable.ReplaceAllStringFunc(node.Value, func(placeholder string) string {
+		name := manifestVariable.FindStringSubmatch(placeholder)[1]
+		value, ok := variables[name]
+		if !ok {
+			missing[name] = true
+			return placeholder
+		}
+
+		switch value.(type) {
+		case map[string]any, []any:
+			interpolationErr = fmt.Errorf("variable %q cannot be interpolated into a string as it is not a primitive value", name)
+			return placeholder
+		}
+
+		return fmt.Sprint(value)
+	})
+
+	return interpolationErr
+}
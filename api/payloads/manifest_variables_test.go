@@ -0,0 +1,120 @@
+package payloads_test
+
+import (
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/payloads"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InterpolateManifestVariables", func() {
+	var (
+		manifest             string
+		interpolatedManifest []byte
+		interpolateErr       error
+	)
+
+	BeforeEach(func() {
+		manifest = `applications:
+- name: ((app-name))
+  instances: ((instances))
+  env:
+    GREETING: hello ((who)), ((who))!
+    LITERAL: ((not-a-variable
+---
+app-name: my-app
+instances: 3
+who: world
+`
+	})
+
+	JustBeforeEach(func() {
+		interpolatedManifest, interpolateErr = payloads.InterpolateManifestVariables([]byte(manifest))
+	})
+
+	It("resolves the placeholders", func() {
+		Expect(interpolateErr).NotTo(HaveOccurred())
+		Expect(interpolatedManifest).To(MatchYAML(`applications:
+- name: my-app
+  instances: 3
+  env:
+    GREETING: hello world, world!
+    LITERAL: ((not-a-variable
+`))
+	})
+
+	When("there are no variables", func() {
+		BeforeEach(func() {
+			manifest = "applications:\n- name: ((app-name))\n"
+		})
+
+		It("returns the manifest unchanged", func() {
+			Expect(interpolateErr).NotTo(HaveOccurred())
+			Expect(string(interpolatedManifest)).To(Equal(manifest))
+		})
+	})
+
+	When("variables are missing", func() {
+		BeforeEach(func() {
+			manifest = `applications:
+- name: ((app-name))
+  memory: ((memory))
+  disk_quota: ((disk))
+---
+app-name: my-app
+`
+		})
+
+		It("returns an error listing them", func() {
+			Expect(interpolateErr).To(BeAssignableToTypeOf(apierrors.UnprocessableEntityError{}))
+			Expect(interpolateErr.(apierrors.UnprocessableEntityError).Detail()).To(Equal("Expected to find variables: disk, memory"))
+		})
+	})
+
+	When("a variable that is not a primitive value is part of a string", func() {
+		BeforeEach(func() {
+			manifest = `applications:
+- name: my-app-((suffix))
+---
+suffix:
+  foo: bar
+`
+		})
+
+		It("returns an error", func() {
+			Expect(interpolateErr).To(BeAssignableToTypeOf(apierrors.UnprocessableEntityError{}))
+			Expect(interpolateErr.(apierrors.UnprocessableEntityError).Detail()).To(ContainSubstring(`variable "suffix" cannot be interpolated`))
+		})
+	})
+
+	When("a variable that is not a primitive value is a whole value", func() {
+		BeforeEach(func() {
+			manifest = `applications:
+- name: my-app
+  env: ((env))
+---
+env:
+  FOO: bar
+`
+		})
+
+		It("inserts the value", func() {
+			Expect(interpolateErr).NotTo(HaveOccurred())
+			Expect(interpolatedManifest).To(MatchYAML(`applications:
+- name: my-app
+  env:
+    FOO: bar
+`))
+		})
+	})
+
+	When("the variables are not a map", func() {
+		BeforeEach(func() {
+			manifest = "applications: []\n---\n- foo\n"
+		})
+
+		It("returns a message parse error", func() {
+			Expect(interpolateErr).To(BeAssignableToTypeOf(apierrors.MessageParseError{}))
+		})
+	})
+})
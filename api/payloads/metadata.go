@@ -3,12 +3,58 @@ package payloads
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 
+	"code.cloudfoundry.org/bytefmt"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools"
 	"github.com/jellydator/validation"
 )
 
+var (
+	stagingAnnotationKeys = []string{
+		korifiv1alpha1.StagingMemoryAnnotationKey,
+		korifiv1alpha1.StagingDiskAnnotationKey,
+	}
+	booleanAppAnnotationKeys = []string{
+		korifiv1alpha1.DisableBuildCacheAnnotationKey,
+		korifiv1alpha1.DisableTopologySpreadAnnotationKey,
+		korifiv1alpha1.DisableEnvRestartAnnotationKey,
+	}
+	appAnnotationKeys = slices.Concat(
+		stagingAnnotationKeys,
+		booleanAppAnnotationKeys,
+		[]string{korifiv1alpha1.ServiceBindingProjectionAnnotationKey},
+	)
+	taskAnnotationKeys = []string{
+		korifiv1alpha1.TaskBackoffLimitAnnotationKey,
+		korifiv1alpha1.TaskJobTTLAnnotationKey,
+	}
+	processAnnotationKeys = []string{
+		korifiv1alpha1.AutoscalingMaxInstancesAnnotationKey,
+		korifiv1alpha1.AutoscalingCPUTargetAnnotationKey,
+		korifiv1alpha1.AutoscalingMemoryTargetAnnotationKey,
+		korifiv1alpha1.IdleTimeoutAnnotationKey,
+	}
+	routeAnnotationKeys = []string{
+		korifiv1alpha1.RouteRequestTimeoutAnnotationKey,
+		korifiv1alpha1.RouteRetryAttemptsAnnotationKey,
+		korifiv1alpha1.RouteSessionAffinityAnnotationKey,
+		korifiv1alpha1.RouteSessionIdleTimeoutAnnotationKey,
+	}
+	domainAnnotationKeys = []string{
+		korifiv1alpha1.TLSSecretNameAnnotationKey,
+		korifiv1alpha1.CertManagerClusterIssuerAnnotationKey,
+		korifiv1alpha1.ExternalDNSAnnotationKey,
+		korifiv1alpha1.ExternalDNSTTLAnnotationKey,
+		korifiv1alpha1.ExternalDNSTargetAnnotationKey,
+	}
+)
+
 type BuildMetadata struct {
 	Annotations map[string]string `json:"annotations"`
 	Labels      map[string]string `json:"labels"`
@@ -27,8 +73,12 @@ type Metadata struct {
 }
 
 func (m Metadata) Validate() error {
+	return m.validate()
+}
+
+func (m Metadata) validate(allowedAnnotationKeys ...string) error {
 	return validation.ValidateStruct(&m,
-		validation.Field(&m.Annotations, validation.Map().Keys(validation.By(cloudfoundryKeyCheck)).AllowExtraKeys()),
+		validation.Field(&m.Annotations, validation.Map().Keys(validation.By(cloudfoundryKeyCheckAllowing(allowedAnnotationKeys))).AllowExtraKeys()),
 		validation.Field(&m.Labels, validation.Map().Keys(validation.By(cloudfoundryKeyCheck)).AllowExtraKeys()),
 	)
 }
@@ -39,12 +89,291 @@ type MetadataPatch struct {
 }
 
 func (p MetadataPatch) Validate() error {
+	return p.validate()
+}
+
+func (p MetadataPatch) validate(allowedAnnotationKeys ...string) error {
 	return validation.ValidateStruct(&p,
-		validation.Field(&p.Annotations, validation.Map().Keys(validation.By(cloudfoundryKeyCheck)).AllowExtraKeys()),
+		validation.Field(&p.Annotations, validation.Map().Keys(validation.By(cloudfoundryKeyCheckAllowing(allowedAnnotationKeys))).AllowExtraKeys()),
 		validation.Field(&p.Labels, validation.Map().Keys(validation.By(cloudfoundryKeyCheck)).AllowExtraKeys()),
 	)
 }
 
+// validateOrgSpaceMetadataPatch additionally allows the korifi annotations
+// that configure how the apps of an org or space are built
+func validateOrgSpaceMetadataPatch(value any) error {
+	patch, ok := value.(MetadataPatch)
+	if !ok {
+		return fmt.Errorf("expected metadata patch, got %T", value)
+	}
+
+	return patch.validate(korifiv1alpha1.KpackBuilderAnnotationKey)
+}
+
+// validateAppMetadata additionally allows the korifi annotations that
+// override the staging resources of the app builds or disable their cache
+func validateAppMetadata(value any) error {
+	metadata, ok := value.(Metadata)
+	if !ok {
+		return fmt.Errorf("expected metadata, got %T", value)
+	}
+
+	if err := metadata.validate(appAnnotationKeys...); err != nil {
+		return err
+	}
+
+	return validateAppAnnotations(metadata.Annotations)
+}
+
+func validateAppMetadataPatch(value any) error {
+	patch, ok := value.(MetadataPatch)
+	if !ok {
+		return fmt.Errorf("expected metadata patch, got %T", value)
+	}
+
+	if err := patch.validate(appAnnotationKeys...); err != nil {
+		return err
+	}
+
+	return validateAppAnnotations(ignoreNilKeys(patch.Annotations))
+}
+
+func validateAppAnnotations(annotations map[string]string) error {
+	for _, key := range booleanAppAnnotationKeys {
+		if value, ok := annotations[key]; ok && value != "true" && value != "false" {
+			return validation.Errors{
+				"annotations": fmt.Errorf("%s must be either true or false", key),
+			}
+		}
+	}
+
+	for _, key := range stagingAnnotationKeys {
+		value, ok := annotations[key]
+		if !ok {
+			continue
+		}
+
+		if _, err := bytefmt.ToMegabytes(value); err != nil {
+			return validation.Errors{
+				"annotations": fmt.Errorf("%s must be an amount of memory or disk such as 512M or 2G", key),
+			}
+		}
+	}
+
+	return validateServiceBindingProjection(annotations)
+}
+
+// validateServiceBindingMetadataPatch additionally allows the korifi
+// annotation that selects how the binding credentials are projected
+func validateServiceBindingMetadataPatch(value any) error {
+	patch, ok := value.(MetadataPatch)
+	if !ok {
+		return fmt.Errorf("expected metadata patch, got %T", value)
+	}
+
+	if err := patch.validate(korifiv1alpha1.ServiceBindingProjectionAnnotationKey); err != nil {
+		return err
+	}
+
+	return validateServiceBindingProjection(ignoreNilKeys(patch.Annotations))
+}
+
+func validateServiceBindingProjection(annotations map[string]string) error {
+	value, ok := annotations[korifiv1alpha1.ServiceBindingProjectionAnnotationKey]
+	if !ok {
+		return nil
+	}
+
+	projections := []string{
+		korifiv1alpha1.ServiceBindingProjectionVCAPServices,
+		korifiv1alpha1.ServiceBindingProjectionFiles,
+		korifiv1alpha1.ServiceBindingProjectionAll,
+	}
+	if !slices.Contains(projections, value) {
+		return validation.Errors{
+			"annotations": fmt.Errorf("%s must be one of %s", korifiv1alpha1.ServiceBindingProjectionAnnotationKey, strings.Join(projections, ", ")),
+		}
+	}
+
+	return nil
+}
+
+// validateRouteMetadata and validateRouteMetadataPatch additionally allow
+// the korifi annotations that set the timeouts, retries and session affinity
+// of the route
+func validateRouteMetadata(value any) error {
+	metadata, ok := value.(Metadata)
+	if !ok {
+		return fmt.Errorf("expected metadata, got %T", value)
+	}
+
+	if err := metadata.validate(routeAnnotationKeys...); err != nil {
+		return err
+	}
+
+	return validateRouteAnnotations(metadata.Annotations)
+}
+
+func validateRouteMetadataPatch(value any) error {
+	patch, ok := value.(MetadataPatch)
+	if !ok {
+		return fmt.Errorf("expected metadata patch, got %T", value)
+	}
+
+	if err := patch.validate(routeAnnotationKeys...); err != nil {
+		return err
+	}
+
+	return validateRouteAnnotations(ignoreNilKeys(patch.Annotations))
+}
+
+func validateRouteAnnotations(annotations map[string]string) error {
+	for _, key := range []string{korifiv1alpha1.RouteRequestTimeoutAnnotationKey, korifiv1alpha1.RouteSessionIdleTimeoutAnnotationKey} {
+		if value, ok := annotations[key]; ok && tools.ValidateGatewayDuration(value) != nil {
+			return validation.Errors{
+				"annotations": fmt.Errorf("%s must be a duration such as 30s or 1m30s", key),
+			}
+		}
+	}
+
+	if value, ok := annotations[korifiv1alpha1.RouteRetryAttemptsAnnotationKey]; ok {
+		if attempts, err := strconv.Atoi(value); err != nil || attempts < 0 {
+			return validation.Errors{
+				"annotations": fmt.Errorf("%s must be a non-negative integer", korifiv1alpha1.RouteRetryAttemptsAnnotationKey),
+			}
+		}
+	}
+
+	if value, ok := annotations[korifiv1alpha1.RouteSessionAffinityAnnotationKey]; ok && value != "true" && value != "false" {
+		return validation.Errors{
+			"annotations": fmt.Errorf("%s must be either true or false", korifiv1alpha1.RouteSessionAffinityAnnotationKey),
+		}
+	}
+
+	return nil
+}
+
+// validateDomainMetadata and validateDomainMetadataPatch additionally allow
+// the korifi annotations that configure the certificate and the DNS records
+// of the domain
+func validateDomainMetadata(value any) error {
+	metadata, ok := value.(Metadata)
+	if !ok {
+		return fmt.Errorf("expected metadata, got %T", value)
+	}
+
+	if err := metadata.validate(domainAnnotationKeys...); err != nil {
+		return err
+	}
+
+	return validateDomainAnnotations(metadata.Annotations)
+}
+
+func validateDomainMetadataPatch(value any) error {
+	patch, ok := value.(MetadataPatch)
+	if !ok {
+		return fmt.Errorf("expected metadata patch, got %T", value)
+	}
+
+	if err := patch.validate(domainAnnotationKeys...); err != nil {
+		return err
+	}
+
+	return validateDomainAnnotations(ignoreNilKeys(patch.Annotations))
+}
+
+func validateDomainAnnotations(annotations map[string]string) error {
+	if value, ok := annotations[korifiv1alpha1.ExternalDNSAnnotationKey]; ok && value != "true" && value != "false" {
+		return validation.Errors{
+			"annotations": fmt.Errorf("%s must be either true or false", korifiv1alpha1.ExternalDNSAnnotationKey),
+		}
+	}
+
+	if value, ok := annotations[korifiv1alpha1.ExternalDNSTTLAnnotationKey]; ok {
+		if ttl, err := strconv.Atoi(value); err != nil || ttl <= 0 {
+			return validation.Errors{
+				"annotations": fmt.Errorf("%s must be a positive number of seconds", korifiv1alpha1.ExternalDNSTTLAnnotationKey),
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateTaskMetadata additionally allows the korifi annotations that
+// override the backoff limit and the job TTL of the task
+func validateTaskMetadata(value any) error {
+	metadata, ok := value.(Metadata)
+	if !ok {
+		return fmt.Errorf("expected metadata, got %T", value)
+	}
+
+	if err := metadata.validate(taskAnnotationKeys...); err != nil {
+		return err
+	}
+
+	if value, ok := metadata.Annotations[korifiv1alpha1.TaskBackoffLimitAnnotationKey]; ok {
+		if backoffLimit, err := strconv.ParseInt(value, 10, 32); err != nil || backoffLimit < 0 {
+			return validation.Errors{
+				"annotations": fmt.Errorf("%s must be a non-negative integer", korifiv1alpha1.TaskBackoffLimitAnnotationKey),
+			}
+		}
+	}
+
+	if value, ok := metadata.Annotations[korifiv1alpha1.TaskJobTTLAnnotationKey]; ok {
+		if ttl, err := tools.ParseDuration(value); err != nil || ttl < 0 || ttl.Seconds() > math.MaxInt32 {
+			return validation.Errors{
+				"annotations": fmt.Errorf("%s must be a duration such as 30m or 1d", korifiv1alpha1.TaskJobTTLAnnotationKey),
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateProcessMetadataPatch additionally allows the korifi annotations
+// that opt the process into autoscaling and idling
+func validateProcessMetadataPatch(value any) error {
+	patch, ok := value.(*MetadataPatch)
+	if !ok {
+		return fmt.Errorf("expected metadata patch, got %T", value)
+	}
+
+	if patch == nil {
+		return nil
+	}
+
+	if err := patch.validate(processAnnotationKeys...); err != nil {
+		return err
+	}
+
+	annotations := ignoreNilKeys(patch.Annotations)
+	for _, key := range processAnnotationKeys {
+		value, ok := annotations[key]
+		if !ok {
+			continue
+		}
+
+		if parsed, err := strconv.ParseInt(value, 10, 32); err != nil || parsed < 1 {
+			return validation.Errors{
+				"annotations": fmt.Errorf("%s must be a positive integer", key),
+			}
+		}
+	}
+
+	return nil
+}
+
+func cloudfoundryKeyCheckAllowing(allowedKeys []string) validation.RuleFunc {
+	return func(key any) error {
+		if keyStr, ok := key.(string); ok && slices.Contains(allowedKeys, keyStr) {
+			return nil
+		}
+
+		return cloudfoundryKeyCheck(key)
+	}
+}
+
 func cloudfoundryKeyCheck(key any) error {
 	keyStr, ok := key.(string)
 	if !ok {
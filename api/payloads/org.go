@@ -35,7 +35,7 @@ type OrgPatch struct {
 
 func (p OrgPatch) Validate() error {
 	return validation.ValidateStruct(&p,
-		validation.Field(&p.Metadata),
+		validation.Field(&p.Metadata, validation.By(validateOrgSpaceMetadataPatch), validation.Skip),
 	)
 }
 
@@ -98,6 +98,29 @@ var _ = Describe("Org", func() {
 				)
 			})
 		})
+
+		When("the kpack builder annotation is set", func() {
+			BeforeEach(func() {
+				payload.Metadata.Annotations["korifi.cloudfoundry.org/kpack-builder"] = tools.PtrTo("my-builder")
+			})
+
+			It("succeeds", func() {
+				Expect(validatorErr).NotTo(HaveOccurred())
+			})
+		})
+
+		When("another korifi annotation is set", func() {
+			BeforeEach(func() {
+				payload.Metadata.Annotations["korifi.cloudfoundry.org/app-guid"] = tools.PtrTo("my-app")
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError(
+					validatorErr,
+					"label/annotation key cannot use the cloudfoundry.org domain",
+				)
+			})
+		})
 	})
 
 	Describe("OrgList", func() {
@@ -1,6 +1,7 @@
 package payloads
 
 import (
+	"encoding/json"
 	"net/url"
 
 	"code.cloudfoundry.org/korifi/api/payloads/parse"
@@ -23,9 +24,44 @@ func (p ProcessScale) Validate() error {
 }
 
 type ProcessPatch struct {
-	Metadata    *MetadataPatch `json:"metadata"`
-	Command     *string        `json:"command"`
-	HealthCheck *HealthCheck   `json:"health_check"`
+	Metadata             *MetadataPatch        `json:"metadata"`
+	Command              *string               `json:"command"`
+	HealthCheck          *HealthCheck          `json:"health_check"`
+	ReadinessHealthCheck *ReadinessHealthCheck `json:"readiness_health_check"`
+}
+
+func (p ProcessPatch) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.Metadata, validation.By(validateProcessMetadataPatch), validation.Skip),
+		validation.Field(&p.HealthCheck),
+		validation.Field(&p.ReadinessHealthCheck),
+	)
+}
+
+// UnmarshalJSON decodes a null command as an empty one, which resets the
+// process command to the one detected by the build
+func (p *ProcessPatch) UnmarshalJSON(data []byte) error {
+	type alias ProcessPatch
+
+	var patch alias
+	err := json.Unmarshal(data, &patch)
+	if err != nil {
+		return err
+	}
+
+	var patchMap map[string]any
+	err = json.Unmarshal(data, &patchMap)
+	if err != nil {
+		return err
+	}
+
+	if v, ok := patchMap["command"]; ok && v == nil {
+		patch.Command = new(string)
+	}
+
+	*p = ProcessPatch(patch)
+
+	return nil
 }
 
 type HealthCheck struct {
@@ -33,12 +69,51 @@ type HealthCheck struct {
 	Data *Data   `json:"data"`
 }
 
+func (c HealthCheck) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Type, validation.In("http", "port", "process")),
+		validation.Field(&c.Data),
+	)
+}
+
 type Data struct {
 	Timeout           *int32  `json:"timeout"`
 	Endpoint          *string `json:"endpoint"`
 	InvocationTimeout *int32  `json:"invocation_timeout"`
 }
 
+func (d Data) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.Timeout, validation.Min(1), validation.NilOrNotEmpty.Error("must be no less than 1")),
+		validation.Field(&d.InvocationTimeout, validation.Min(1), validation.NilOrNotEmpty.Error("must be no less than 1")),
+	)
+}
+
+type ReadinessHealthCheck struct {
+	Type *string        `json:"type"`
+	Data *ReadinessData `json:"data"`
+}
+
+func (c ReadinessHealthCheck) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Type, validation.In("http", "port", "process")),
+		validation.Field(&c.Data),
+	)
+}
+
+type ReadinessData struct {
+	Endpoint          *string `json:"endpoint"`
+	InvocationTimeout *int32  `json:"invocation_timeout"`
+	Interval          *int32  `json:"interval"`
+}
+
+func (d ReadinessData) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.InvocationTimeout, validation.Min(1), validation.NilOrNotEmpty.Error("must be no less than 1")),
+		validation.Field(&d.Interval, validation.Min(1), validation.NilOrNotEmpty.Error("must be no less than 1")),
+	)
+}
+
 func (p ProcessScale) ToRecord() repositories.ProcessScaleValues {
 	return repositories.ProcessScaleValues{
 		Instances: p.Instances,
@@ -83,6 +158,16 @@ func (p ProcessPatch) ToProcessPatchMessage(processGUID, spaceGUID string) repos
 		}
 	}
 
+	if p.ReadinessHealthCheck != nil {
+		message.ReadinessHealthCheckType = p.ReadinessHealthCheck.Type
+
+		if p.ReadinessHealthCheck.Data != nil {
+			message.ReadinessHealthCheckHTTPEndpoint = p.ReadinessHealthCheck.Data.Endpoint
+			message.ReadinessHealthCheckInvocationTimeoutSeconds = p.ReadinessHealthCheck.Data.InvocationTimeout
+			message.ReadinessHealthCheckIntervalSeconds = p.ReadinessHealthCheck.Data.Interval
+		}
+	}
+
 	if p.Metadata != nil {
 		message.MetadataPatch = &repositories.MetadataPatch{
 			Annotations: p.Metadata.Annotations,
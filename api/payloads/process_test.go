@@ -1,9 +1,11 @@
 package payloads_test
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"code.cloudfoundry.org/korifi/api/payloads"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/tools"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -84,4 +86,188 @@ var _ = Describe("Process payload validation", func() {
 			})
 		})
 	})
+	Describe("ProcessPatch", func() {
+		var (
+			payload        payloads.ProcessPatch
+			decodedPayload *payloads.ProcessPatch
+		)
+
+		BeforeEach(func() {
+			payload = payloads.ProcessPatch{
+				Command: tools.PtrTo("bob"),
+				ReadinessHealthCheck: &payloads.ReadinessHealthCheck{
+					Type: tools.PtrTo("http"),
+					Data: &payloads.ReadinessData{
+						Endpoint:          tools.PtrTo("/ready"),
+						InvocationTimeout: tools.PtrTo[int32](2),
+						Interval:          tools.PtrTo[int32](5),
+					},
+				},
+			}
+
+			decodedPayload = new(payloads.ProcessPatch)
+		})
+
+		JustBeforeEach(func() {
+			validatorErr = validator.DecodeAndValidateJSONPayload(createJSONRequest(payload), decodedPayload)
+		})
+
+		It("succeeds", func() {
+			Expect(validatorErr).NotTo(HaveOccurred())
+			Expect(decodedPayload).To(gstruct.PointTo(Equal(payload)))
+		})
+
+		When("the readiness health check type is invalid", func() {
+			BeforeEach(func() {
+				payload.ReadinessHealthCheck.Type = tools.PtrTo("none")
+			})
+
+			It("returns an error", func() {
+				expectUnprocessableEntityError(validatorErr, "type must be a valid value")
+			})
+		})
+
+		When("the readiness health check interval is not positive", func() {
+			BeforeEach(func() {
+				payload.ReadinessHealthCheck.Data.Interval = tools.PtrTo[int32](0)
+			})
+
+			It("returns an error", func() {
+				expectUnprocessableEntityError(validatorErr, "interval must be no less than 1")
+			})
+		})
+
+		When("the readiness health check invocation timeout is not positive", func() {
+			BeforeEach(func() {
+				payload.ReadinessHealthCheck.Data.InvocationTimeout = tools.PtrTo[int32](0)
+			})
+
+			It("returns an error", func() {
+				expectUnprocessableEntityError(validatorErr, "invocation_timeout must be no less than 1")
+			})
+		})
+
+		When("the health check type is invalid", func() {
+			BeforeEach(func() {
+				payload.HealthCheck = &payloads.HealthCheck{Type: tools.PtrTo("none")}
+			})
+
+			It("returns an error", func() {
+				expectUnprocessableEntityError(validatorErr, "type must be a valid value")
+			})
+		})
+
+		When("the health check timeout is not positive", func() {
+			BeforeEach(func() {
+				payload.HealthCheck = &payloads.HealthCheck{
+					Type: tools.PtrTo("port"),
+					Data: &payloads.Data{Timeout: tools.PtrTo[int32](0)},
+				}
+			})
+
+			It("returns an error", func() {
+				expectUnprocessableEntityError(validatorErr, "timeout must be no less than 1")
+			})
+		})
+
+		When("the metadata annotations use the cloudfoundry.org domain", func() {
+			BeforeEach(func() {
+				payload.Metadata = &payloads.MetadataPatch{
+					Annotations: map[string]*string{"foo.cloudfoundry.org/bar": tools.PtrTo("baz")},
+				}
+			})
+
+			It("returns an error", func() {
+				expectUnprocessableEntityError(validatorErr, "cannot use the cloudfoundry.org domain")
+			})
+		})
+
+		When("the metadata sets the autoscaling annotations", func() {
+			BeforeEach(func() {
+				payload.Metadata = &payloads.MetadataPatch{
+					Annotations: map[string]*string{
+						korifiv1alpha1.AutoscalingMaxInstancesAnnotationKey: tools.PtrTo("10"),
+						korifiv1alpha1.AutoscalingCPUTargetAnnotationKey:    tools.PtrTo("70"),
+						korifiv1alpha1.AutoscalingMemoryTargetAnnotationKey: nil,
+					},
+				}
+			})
+
+			It("succeeds", func() {
+				Expect(validatorErr).NotTo(HaveOccurred())
+				Expect(decodedPayload).To(gstruct.PointTo(Equal(payload)))
+			})
+
+			When("an autoscaling annotation is not a positive integer", func() {
+				BeforeEach(func() {
+					payload.Metadata.Annotations[korifiv1alpha1.AutoscalingMaxInstancesAnnotationKey] = tools.PtrTo("0")
+				})
+
+				It("returns an error", func() {
+					expectUnprocessableEntityError(validatorErr, korifiv1alpha1.AutoscalingMaxInstancesAnnotationKey+" must be a positive integer")
+				})
+			})
+		})
+
+		When("the metadata sets the idle timeout annotation", func() {
+			BeforeEach(func() {
+				payload.Metadata = &payloads.MetadataPatch{
+					Annotations: map[string]*string{
+						korifiv1alpha1.IdleTimeoutAnnotationKey: tools.PtrTo("30"),
+					},
+				}
+			})
+
+			It("succeeds", func() {
+				Expect(validatorErr).NotTo(HaveOccurred())
+				Expect(decodedPayload).To(gstruct.PointTo(Equal(payload)))
+			})
+
+			When("the idle timeout is not a positive integer", func() {
+				BeforeEach(func() {
+					payload.Metadata.Annotations[korifiv1alpha1.IdleTimeoutAnnotationKey] = tools.PtrTo("soon")
+				})
+
+				It("returns an error", func() {
+					expectUnprocessableEntityError(validatorErr, korifiv1alpha1.IdleTimeoutAnnotationKey+" must be a positive integer")
+				})
+			})
+		})
+	})
+
+	Describe("ProcessPatch.UnmarshalJSON", func() {
+		var patch payloads.ProcessPatch
+
+		It("decodes a null command as an empty one", func() {
+			Expect(json.Unmarshal([]byte(`{"command": null}`), &patch)).To(Succeed())
+			Expect(patch.Command).To(gstruct.PointTo(BeEmpty()))
+		})
+
+		It("leaves a missing command unset", func() {
+			Expect(json.Unmarshal([]byte(`{}`), &patch)).To(Succeed())
+			Expect(patch.Command).To(BeNil())
+		})
+	})
+
+	Describe("ProcessPatch.ToProcessPatchMessage", func() {
+		It("converts the readiness health check", func() {
+			message := payloads.ProcessPatch{
+				ReadinessHealthCheck: &payloads.ReadinessHealthCheck{
+					Type: tools.PtrTo("http"),
+					Data: &payloads.ReadinessData{
+						Endpoint:          tools.PtrTo("/ready"),
+						InvocationTimeout: tools.PtrTo[int32](2),
+						Interval:          tools.PtrTo[int32](5),
+					},
+				},
+			}.ToProcessPatchMessage("process-guid", "space-guid")
+
+			Expect(message.ProcessGUID).To(Equal("process-guid"))
+			Expect(message.SpaceGUID).To(Equal("space-guid"))
+			Expect(message.ReadinessHealthCheckType).To(gstruct.PointTo(Equal("http")))
+			Expect(message.ReadinessHealthCheckHTTPEndpoint).To(gstruct.PointTo(Equal("/ready")))
+			Expect(message.ReadinessHealthCheckInvocationTimeoutSeconds).To(gstruct.PointTo(BeEquivalentTo(2)))
+			Expect(message.ReadinessHealthCheckIntervalSeconds).To(gstruct.PointTo(BeEquivalentTo(5)))
+		})
+	})
 })
@@ -0,0 +1,62 @@
+package payloads
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"code.cloudfoundry.org/korifi/api/payloads/parse"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	jellidation "github.com/jellydator/validation"
+)
+
+type RevisionGUID struct {
+	Guid string `json:"guid"`
+}
+
+type AppRevisionList struct {
+	Versions string
+}
+
+func (l *AppRevisionList) SupportedKeys() []string {
+	return []string{"versions"}
+}
+
+func (l *AppRevisionList) IgnoredKeys() []*regexp.Regexp {
+	return []*regexp.Regexp{
+		regexp.MustCompile("page"),
+		regexp.MustCompile("per_page"),
+		regexp.MustCompile("order_by"),
+	}
+}
+
+func (l *AppRevisionList) DecodeFromURLValues(values url.Values) error {
+	l.Versions = values.Get("versions")
+	return nil
+}
+
+func (l AppRevisionList) Validate() error {
+	return jellidation.ValidateStruct(&l,
+		jellidation.Field(&l.Versions, jellidation.By(func(value any) error {
+			for _, version := range parse.ArrayParam(value.(string)) {
+				if _, err := strconv.Atoi(version); err != nil {
+					return fmt.Errorf("%q is not an integer", version)
+				}
+			}
+			return nil
+		})),
+	)
+}
+
+func (l *AppRevisionList) ToMessage() repositories.ListRevisionsMessage {
+	versions := []int{}
+	for _, version := range parse.ArrayParam(l.Versions) {
+		v, _ := strconv.Atoi(version)
+		versions = append(versions, v)
+	}
+
+	return repositories.ListRevisionsMessage{
+		Versions: versions,
+	}
+}
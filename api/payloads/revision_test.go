@@ -0,0 +1,43 @@
+package payloads_test
+
+import (
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AppRevisionList", func() {
+	Describe("Validation", func() {
+		DescribeTable("valid query",
+			func(query string, expectedRevisionList payloads.AppRevisionList) {
+				actualRevisionList, decodeErr := decodeQuery[payloads.AppRevisionList](query)
+
+				Expect(decodeErr).NotTo(HaveOccurred())
+				Expect(*actualRevisionList).To(Equal(expectedRevisionList))
+			},
+
+			Entry("versions", "versions=1,2", payloads.AppRevisionList{Versions: "1,2"}),
+			Entry("page", "page=3", payloads.AppRevisionList{}),
+		)
+
+		DescribeTable("invalid query",
+			func(query string, expectedErrMsg string) {
+				_, decodeErr := decodeQuery[payloads.AppRevisionList](query)
+				Expect(decodeErr).To(MatchError(ContainSubstring(expectedErrMsg)))
+			},
+			Entry("non-integer versions", "versions=1,foo", `"foo" is not an integer`),
+			Entry("unsupported key", "foo=bar", "unsupported query parameter: foo"),
+		)
+	})
+
+	Describe("ToMessage", func() {
+		It("translates to repository message", func() {
+			revisionList := payloads.AppRevisionList{Versions: "1,3"}
+			Expect(revisionList.ToMessage()).To(Equal(repositories.ListRevisionsMessage{
+				Versions: []int{1, 3},
+			}))
+		})
+	})
+})
@@ -2,10 +2,13 @@ package payloads
 
 import (
 	"context"
+	"maps"
 	"net/url"
+	"slices"
 	"strings"
 
 	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/config"
 	"code.cloudfoundry.org/korifi/api/payloads/validation"
 	jellidation "github.com/jellydator/validation"
 
@@ -27,6 +30,20 @@ const (
 	RoleSpaceSupporter             = "space_supporter"
 )
 
+// AssignableRoles is set by main.go to the org and space roles of the
+// configured role mappings, which may include custom roles, and updated
+// whenever the config is reloaded
+var AssignableRoles = config.NewReloadable(map[string]config.RoleLevel{
+	RoleOrganizationAuditor:        config.OrgRole,
+	RoleOrganizationBillingManager: config.OrgRole,
+	RoleOrganizationManager:        config.OrgRole,
+	RoleOrganizationUser:           config.OrgRole,
+	RoleSpaceAuditor:               config.SpaceRole,
+	RoleSpaceDeveloper:             config.SpaceRole,
+	RoleSpaceManager:               config.SpaceRole,
+	RoleSpaceSupporter:             config.SpaceRole,
+})
+
 type RoleCreate struct {
 	Type          string            `json:"type"`
 	Relationships RoleRelationships `json:"relationships"`
@@ -42,8 +59,7 @@ func (p RoleCreate) Validate() error {
 	return jellidation.ValidateStructWithContext(ctx, &p,
 		jellidation.Field(&p.Type,
 			jellidation.Required,
-			validation.OneOf(RoleSpaceManager, RoleSpaceAuditor, RoleSpaceDeveloper, RoleSpaceSupporter,
-				RoleOrganizationUser, RoleOrganizationAuditor, RoleOrganizationManager, RoleOrganizationBillingManager),
+			validation.OneOf(assignableRoleTypes()...),
 		),
 		jellidation.Field(&p.Relationships, validation.StrictlyRequired),
 	)
@@ -85,8 +101,19 @@ type RoleRelationships struct {
 	Organization *Relationship    `json:"organization"`
 }
 
+func assignableRoleTypes() []any {
+	roleTypes := slices.Sorted(maps.Keys(AssignableRoles.Get()))
+
+	result := make([]any, 0, len(roleTypes))
+	for _, roleType := range roleTypes {
+		result = append(result, roleType)
+	}
+	return result
+}
+
 func (r RoleRelationships) ValidateWithContext(ctx context.Context) error {
-	roleType := ctx.Value(typeKey)
+	roleType, _ := ctx.Value(typeKey).(string)
+	roleLevel := AssignableRoles.Get()[roleType]
 
 	return jellidation.ValidateStruct(&r,
 		jellidation.Field(&r.User, validation.StrictlyRequired),
@@ -96,18 +123,10 @@ func (r RoleRelationships) ValidateWithContext(ctx context.Context) error {
 				jellidation.Nil.Error("cannot pass both 'organization' and 'space' in a create role request"))),
 
 		jellidation.Field(&r.Space,
-			jellidation.When(
-				roleType == RoleSpaceAuditor || roleType == RoleSpaceDeveloper ||
-					roleType == RoleSpaceManager || roleType == RoleSpaceSupporter,
-				jellidation.NotNil,
-			)),
+			jellidation.When(roleLevel == config.SpaceRole, jellidation.NotNil)),
 
 		jellidation.Field(&r.Organization,
-			jellidation.When(
-				roleType == RoleOrganizationAuditor || roleType == RoleOrganizationBillingManager ||
-					roleType == RoleOrganizationManager || roleType == RoleOrganizationUser,
-				jellidation.NotNil,
-			)),
+			jellidation.When(roleLevel == config.OrgRole, jellidation.NotNil)),
 	)
 }
 
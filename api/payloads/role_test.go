@@ -7,6 +7,7 @@ import (
 
 	rbacv1 "k8s.io/api/rbac/v1"
 
+	"code.cloudfoundry.org/korifi/api/config"
 	"code.cloudfoundry.org/korifi/api/errors"
 	"code.cloudfoundry.org/korifi/api/payloads"
 
@@ -178,6 +179,53 @@ var _ = DescribeTable("Role org / space combination validation",
 	Entry("invalid role name", "does-not-exist", "organization", false, "type value must be one of"),
 )
 
+var _ = Describe("RoleCreate with custom roles", func() {
+	BeforeEach(func() {
+		originalAssignableRoles := payloads.AssignableRoles.Get()
+		DeferCleanup(func() {
+			payloads.AssignableRoles.Set(originalAssignableRoles)
+		})
+
+		payloads.AssignableRoles.Set(map[string]config.RoleLevel{
+			"space_operator": config.SpaceRole,
+			"org_operator":   config.OrgRole,
+		})
+	})
+
+	DescribeTable("validation",
+		func(role, orgOrSpace string, succeeds bool, errMsg string) {
+			createRoleRequestBody := fmt.Sprintf(`{
+				"type": "%s",
+				"relationships": {
+					"user": { "data": { "username": "my-user" } },
+					"%s": { "data": { "guid": "some-guid" } }
+				}
+			}`, role, orgOrSpace)
+
+			req, err := http.NewRequest("", "", bytes.NewReader([]byte(createRoleRequestBody)))
+			Expect(err).NotTo(HaveOccurred())
+
+			var roleCreate payloads.RoleCreate
+			err = validator.DecodeAndValidateJSONPayload(req, &roleCreate)
+
+			if succeeds {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+				apiError, ok := err.(errors.ApiError)
+				Expect(ok).To(BeTrue(), "didn't get an errors.ApiError")
+				Expect(apiError.Detail()).To(ContainSubstring(errMsg))
+			}
+		},
+
+		Entry("custom space role w space", "space_operator", "space", true, ""),
+		Entry("custom space role w org", "space_operator", "organization", false, "relationships.space is required"),
+		Entry("custom org role w org", "org_operator", "organization", true, ""),
+		Entry("custom org role w space", "org_operator", "space", false, "relationships.organization is required"),
+		Entry("unmapped built-in role", payloads.RoleSpaceDeveloper, "space", false, "type value must be one of: org_operator, space_operator"),
+	)
+})
+
 var _ = Describe("role list", func() {
 	DescribeTable("valid query",
 		func(query string, expectedRoleListQueryParameters payloads.RoleList) {
@@ -13,15 +13,18 @@ import (
 type RouteCreate struct {
 	Host          string              `json:"host"`
 	Path          string              `json:"path"`
+	Port          *int32              `json:"port"`
 	Relationships *RouteRelationships `json:"relationships"`
 	Metadata      Metadata            `json:"metadata"`
 }
 
 func (p RouteCreate) Validate() error {
 	return jellidation.ValidateStruct(&p,
-		jellidation.Field(&p.Host, jellidation.Required),
+		jellidation.Field(&p.Host, jellidation.When(p.Port == nil, jellidation.Required).Else(jellidation.Empty.Error("must be blank for tcp routes"))),
+		jellidation.Field(&p.Path, jellidation.When(p.Port != nil, jellidation.Empty.Error("must be blank for tcp routes"))),
+		jellidation.Field(&p.Port, jellidation.Min(1), jellidation.Max(65535)),
 		jellidation.Field(&p.Relationships, jellidation.NotNil),
-		jellidation.Field(&p.Metadata),
+		jellidation.Field(&p.Metadata, jellidation.By(validateRouteMetadata), jellidation.Skip),
 	)
 }
 
@@ -29,6 +32,7 @@ func (p RouteCreate) ToMessage(domainNamespace, domainName string) repositories.
 	return repositories.CreateRouteMessage{
 		Host:            p.Host,
 		Path:            p.Path,
+		Port:            p.Port,
 		SpaceGUID:       p.Relationships.Space.Data.GUID,
 		DomainGUID:      p.Relationships.Domain.Data.GUID,
 		DomainNamespace: domainNamespace,
@@ -87,7 +91,7 @@ type RoutePatch struct {
 
 func (p RoutePatch) Validate() error {
 	return jellidation.ValidateStruct(&p,
-		jellidation.Field(&p.Metadata),
+		jellidation.Field(&p.Metadata, jellidation.By(validateRouteMetadataPatch), jellidation.Skip),
 	)
 }
 
@@ -121,6 +125,7 @@ type RouteDestination struct {
 func (r RouteDestination) Validate() error {
 	return jellidation.ValidateStruct(&r,
 		jellidation.Field(&r.App),
+		jellidation.Field(&r.Port, jellidation.Min(1), jellidation.Max(65535)),
 		jellidation.Field(&r.Protocol, validation.OneOf("http1")),
 	)
 }
@@ -163,9 +168,8 @@ func (dc RouteDestinationCreate) ToMessage(routeRecord repositories.RouteRecord)
 		})
 	}
 	return repositories.AddDestinationsMessage{
-		RouteGUID:            routeRecord.GUID,
-		SpaceGUID:            routeRecord.SpaceGUID,
-		ExistingDestinations: routeRecord.Destinations,
-		NewDestinations:      addDestinations,
+		RouteGUID:       routeRecord.GUID,
+		SpaceGUID:       routeRecord.SpaceGUID,
+		NewDestinations: addDestinations,
 	}
 }
@@ -106,6 +106,47 @@ var _ = Describe("RouteCreate", func() {
 		})
 	})
 
+	When("the route is a tcp route", func() {
+		BeforeEach(func() {
+			createPayload.Host = ""
+			createPayload.Path = ""
+			createPayload.Port = tools.PtrTo[int32](1024)
+		})
+
+		It("succeeds", func() {
+			Expect(validatorErr).NotTo(HaveOccurred())
+			Expect(routeCreate).To(gstruct.PointTo(Equal(createPayload)))
+		})
+
+		It("creates a tcp route message", func() {
+			Expect(routeCreate.ToMessage("domain-ns", "domain-name").Port).To(gstruct.PointTo(BeEquivalentTo(1024)))
+		})
+
+		When("the route has a host and a path", func() {
+			BeforeEach(func() {
+				createPayload.Host = "h1"
+				createPayload.Path = "p1"
+			})
+
+			It("fails", func() {
+				Expect(apiError).To(HaveOccurred())
+				Expect(apiError.Detail()).To(ContainSubstring("host must be blank for tcp routes"))
+				Expect(apiError.Detail()).To(ContainSubstring("path must be blank for tcp routes"))
+			})
+		})
+
+		When("the port is out of range", func() {
+			BeforeEach(func() {
+				createPayload.Port = tools.PtrTo[int32](65536)
+			})
+
+			It("fails", func() {
+				Expect(apiError).To(HaveOccurred())
+				Expect(apiError.Detail()).To(ContainSubstring("port must be no greater than 65535"))
+			})
+		})
+	})
+
 	When("relationships is empty", func() {
 		BeforeEach(func() {
 			createPayload.Relationships = nil
@@ -149,6 +190,43 @@ var _ = Describe("RouteCreate", func() {
 			Expect(apiError.Detail()).To(ContainSubstring("cannot use the cloudfoundry.org domain"))
 		})
 	})
+
+	When("metadata sets the route options annotations", func() {
+		BeforeEach(func() {
+			createPayload.Metadata.Annotations = map[string]string{
+				"korifi.cloudfoundry.org/route-request-timeout":      "1m30s",
+				"korifi.cloudfoundry.org/route-retry-attempts":       "3",
+				"korifi.cloudfoundry.org/route-session-affinity":     "true",
+				"korifi.cloudfoundry.org/route-session-idle-timeout": "30m",
+			}
+		})
+
+		It("succeeds", func() {
+			Expect(validatorErr).NotTo(HaveOccurred())
+		})
+	})
+
+	When("the request timeout is not a duration", func() {
+		BeforeEach(func() {
+			createPayload.Metadata.Annotations["korifi.cloudfoundry.org/route-request-timeout"] = "1.5m"
+		})
+
+		It("fails", func() {
+			Expect(apiError).To(HaveOccurred())
+			Expect(apiError.Detail()).To(ContainSubstring("korifi.cloudfoundry.org/route-request-timeout must be a duration"))
+		})
+	})
+
+	When("the retry attempts are negative", func() {
+		BeforeEach(func() {
+			createPayload.Metadata.Annotations["korifi.cloudfoundry.org/route-retry-attempts"] = "-1"
+		})
+
+		It("fails", func() {
+			Expect(apiError).To(HaveOccurred())
+			Expect(apiError.Detail()).To(ContainSubstring("korifi.cloudfoundry.org/route-retry-attempts must be a non-negative integer"))
+		})
+	})
 })
 
 var _ = Describe("RoutePatch", func() {
@@ -189,6 +267,17 @@ var _ = Describe("RoutePatch", func() {
 			Expect(apiError.Detail()).To(ContainSubstring("cannot use the cloudfoundry.org domain"))
 		})
 	})
+
+	When("session affinity is not a boolean", func() {
+		BeforeEach(func() {
+			patchPayload.Metadata.Annotations["korifi.cloudfoundry.org/route-session-affinity"] = tools.PtrTo("yes")
+		})
+
+		It("fails", func() {
+			Expect(apiError).To(HaveOccurred())
+			Expect(apiError.Detail()).To(ContainSubstring("korifi.cloudfoundry.org/route-session-affinity must be either true or false"))
+		})
+	})
 })
 
 var _ = Describe("Add destination", func() {
@@ -254,6 +343,17 @@ var _ = Describe("Add destination", func() {
 		})
 	})
 
+	When("port is not a valid port", func() {
+		BeforeEach(func() {
+			addPayload.Destinations[1].Port = tools.PtrTo[int32](70000)
+		})
+
+		It("fails", func() {
+			Expect(apiError).To(HaveOccurred())
+			Expect(apiError.Detail()).To(ContainSubstring("port must be no greater than 65535"))
+		})
+	})
+
 	When("protocol is not http1", func() {
 		BeforeEach(func() {
 			addPayload.Destinations[1].Protocol = tools.PtrTo("http")
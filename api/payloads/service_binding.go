@@ -76,7 +76,7 @@ type ServiceBindingUpdate struct {
 
 func (u ServiceBindingUpdate) Validate() error {
 	return jellidation.ValidateStruct(&u,
-		jellidation.Field(&u.Metadata),
+		jellidation.Field(&u.Metadata, jellidation.By(validateServiceBindingMetadataPatch), jellidation.Skip),
 	)
 }
 
@@ -195,4 +195,25 @@ var _ = Describe("ServiceBindingUpdate", func() {
 			Expect(apiError.Detail()).To(ContainSubstring("cannot use the cloudfoundry.org domain"))
 		})
 	})
+
+	When("the credentials projection is set", func() {
+		BeforeEach(func() {
+			patchPayload.Metadata.Annotations["korifi.cloudfoundry.org/service-binding-projection"] = tools.PtrTo("vcap-services")
+		})
+
+		It("succeeds", func() {
+			Expect(validatorErr).NotTo(HaveOccurred())
+		})
+
+		When("the projection is invalid", func() {
+			BeforeEach(func() {
+				patchPayload.Metadata.Annotations["korifi.cloudfoundry.org/service-binding-projection"] = tools.PtrTo("env")
+			})
+
+			It("fails", func() {
+				Expect(apiError).To(HaveOccurred())
+				Expect(apiError.Detail()).To(ContainSubstring("korifi.cloudfoundry.org/service-binding-projection must be one of vcap-services, files, all"))
+			})
+		})
+	})
 })
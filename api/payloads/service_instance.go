@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"slices"
 	"strings"
 
 	"code.cloudfoundry.org/korifi/api/payloads/params"
@@ -16,13 +17,14 @@ import (
 )
 
 type ServiceInstanceCreate struct {
-	Name          string                        `json:"name"`
-	Type          string                        `json:"type"`
-	Tags          []string                      `json:"tags"`
-	Credentials   map[string]any                `json:"credentials"`
-	Parameters    map[string]any                `json:"parameters"`
-	Relationships *ServiceInstanceRelationships `json:"relationships"`
-	Metadata      Metadata                      `json:"metadata"`
+	Name           string                        `json:"name"`
+	Type           string                        `json:"type"`
+	Tags           []string                      `json:"tags"`
+	Credentials    map[string]any                `json:"credentials"`
+	SyslogDrainURL *string                       `json:"syslog_drain_url"`
+	Parameters     map[string]any                `json:"parameters"`
+	Relationships  *ServiceInstanceRelationships `json:"relationships"`
+	Metadata       Metadata                      `json:"metadata"`
 }
 
 const maxTagsLength = 2048
@@ -44,11 +46,36 @@ func validateTagLength(tags any) error {
 	return nil
 }
 
+var syslogDrainURLSchemes = []string{"syslog", "syslog-tls", "https"}
+
+func validateSyslogDrainURL(value any) error {
+	drainURL, ok := value.(*string)
+	if !ok {
+		return errors.New("wrong input")
+	}
+
+	// an empty URL removes the syslog drain of the service instance
+	if drainURL == nil || *drainURL == "" {
+		return nil
+	}
+
+	u, err := url.ParseRequestURI(*drainURL)
+	if err != nil || u.Host == "" || !slices.Contains(syslogDrainURLSchemes, u.Scheme) {
+		return fmt.Errorf("must be a URL with one of the schemes %s", strings.Join(syslogDrainURLSchemes, ", "))
+	}
+
+	return nil
+}
+
 func (c ServiceInstanceCreate) Validate() error {
 	return jellidation.ValidateStruct(&c,
 		jellidation.Field(&c.Name, jellidation.Required),
 		jellidation.Field(&c.Type, jellidation.Required, validation.OneOf("user-provided", "managed")),
 		jellidation.Field(&c.Tags, jellidation.By(validateTagLength)),
+		jellidation.Field(&c.SyslogDrainURL,
+			jellidation.By(validateSyslogDrainURL),
+			jellidation.When(c.Type == "managed", jellidation.Nil.Error("is only supported by user-provided service instances")),
+		),
 		jellidation.Field(&c.Relationships, jellidation.NotNil, jellidation.By(func(r any) error {
 			rel := r.(*ServiceInstanceRelationships)
 			if c.Type == "user-provided" {
@@ -63,12 +90,13 @@ func (c ServiceInstanceCreate) Validate() error {
 
 func (p ServiceInstanceCreate) ToUPSICreateMessage() repositories.CreateUPSIMessage {
 	return repositories.CreateUPSIMessage{
-		Name:        p.Name,
-		SpaceGUID:   p.Relationships.Space.Data.GUID,
-		Credentials: p.Credentials,
-		Tags:        p.Tags,
-		Labels:      p.Metadata.Labels,
-		Annotations: p.Metadata.Annotations,
+		Name:           p.Name,
+		SpaceGUID:      p.Relationships.Space.Data.GUID,
+		Credentials:    p.Credentials,
+		Tags:           p.Tags,
+		SyslogDrainURL: p.SyslogDrainURL,
+		Labels:         p.Metadata.Labels,
+		Annotations:    p.Metadata.Annotations,
 	}
 }
 
@@ -103,25 +131,28 @@ func (r ServiceInstanceRelationships) ValidateManagedRelationships() error {
 }
 
 type ServiceInstancePatch struct {
-	Name        *string         `json:"name,omitempty"`
-	Tags        *[]string       `json:"tags,omitempty"`
-	Credentials *map[string]any `json:"credentials,omitempty"`
-	Metadata    MetadataPatch   `json:"metadata"`
+	Name           *string         `json:"name,omitempty"`
+	Tags           *[]string       `json:"tags,omitempty"`
+	Credentials    *map[string]any `json:"credentials,omitempty"`
+	SyslogDrainURL *string         `json:"syslog_drain_url,omitempty"`
+	Metadata       MetadataPatch   `json:"metadata"`
 }
 
 func (p ServiceInstancePatch) Validate() error {
 	return jellidation.ValidateStruct(&p,
+		jellidation.Field(&p.SyslogDrainURL, jellidation.By(validateSyslogDrainURL)),
 		jellidation.Field(&p.Metadata),
 	)
 }
 
 func (p ServiceInstancePatch) ToServiceInstancePatchMessage(spaceGUID, appGUID string) repositories.PatchServiceInstanceMessage {
 	return repositories.PatchServiceInstanceMessage{
-		SpaceGUID:   spaceGUID,
-		GUID:        appGUID,
-		Name:        p.Name,
-		Credentials: p.Credentials,
-		Tags:        p.Tags,
+		SpaceGUID:      spaceGUID,
+		GUID:           appGUID,
+		Name:           p.Name,
+		Credentials:    p.Credentials,
+		Tags:           p.Tags,
+		SyslogDrainURL: p.SyslogDrainURL,
 		MetadataPatch: repositories.MetadataPatch{
 			Labels:      p.Metadata.Labels,
 			Annotations: p.Metadata.Annotations,
@@ -152,6 +183,10 @@ func (p *ServiceInstancePatch) UnmarshalJSON(data []byte) error {
 		patch.Credentials = &map[string]any{}
 	}
 
+	if v, ok := patchMap["syslog_drain_url"]; ok && v == nil {
+		patch.SyslogDrainURL = new(string)
+	}
+
 	*p = ServiceInstancePatch(patch)
 
 	return nil
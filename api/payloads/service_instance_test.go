@@ -207,6 +207,42 @@ var _ = Describe("ServiceInstanceCreate", func() {
 			})
 		})
 
+		When("the syslog drain url is set", func() {
+			BeforeEach(func() {
+				createPayload.SyslogDrainURL = tools.PtrTo("syslog-tls://logs.example.com:6514")
+			})
+
+			It("succeeds", func() {
+				Expect(validatorErr).NotTo(HaveOccurred())
+				Expect(serviceInstanceCreate).To(PointTo(Equal(createPayload)))
+			})
+
+			When("the syslog drain url is not a syslog or https url", func() {
+				BeforeEach(func() {
+					createPayload.SyslogDrainURL = tools.PtrTo("ftp://logs.example.com")
+				})
+
+				It("returns an appropriate error", func() {
+					expectUnprocessableEntityError(validatorErr, "syslog_drain_url must be a URL with one of the schemes syslog, syslog-tls, https")
+				})
+			})
+
+			When("the instance type is managed", func() {
+				BeforeEach(func() {
+					createPayload.Type = "managed"
+					createPayload.Relationships.ServicePlan = &payloads.Relationship{
+						Data: &payloads.RelationshipData{
+							GUID: "plan_guid",
+						},
+					}
+				})
+
+				It("returns an appropriate error", func() {
+					expectUnprocessableEntityError(validatorErr, "syslog_drain_url is only supported by user-provided service instances")
+				})
+			})
+		})
+
 		When("the instance type is managed", func() {
 			BeforeEach(func() {
 				createPayload.Type = "managed"
@@ -250,6 +286,7 @@ var _ = Describe("ServiceInstanceCreate", func() {
 						"a": "b",
 					},
 				},
+				SyslogDrainURL: tools.PtrTo("syslog://logs.example.com"),
 				Relationships: &payloads.ServiceInstanceRelationships{
 					Space: &payloads.Relationship{
 						Data: &payloads.RelationshipData{
@@ -272,6 +309,7 @@ var _ = Describe("ServiceInstanceCreate", func() {
 			Expect(msg.Name).To(Equal("service-instance-name"))
 			Expect(msg.SpaceGUID).To(Equal("space-guid"))
 			Expect(msg.Tags).To(ConsistOf("foo", "bar"))
+			Expect(msg.SyslogDrainURL).To(PointTo(Equal("syslog://logs.example.com")))
 			Expect(msg.Annotations).To(HaveLen(1))
 			Expect(msg.Annotations).To(HaveKeyWithValue("ann1", "val_ann1"))
 			Expect(msg.Labels).To(HaveLen(1))
@@ -388,6 +426,16 @@ var _ = Describe("ServiceInstancePatch custom unmarshalling", func() {
 			Expect(patch.Credentials).To(PointTo(HaveLen(0)))
 		})
 	})
+
+	When("the syslog drain url is present but null", func() {
+		BeforeEach(func() {
+			payload = `{"syslog_drain_url": null}`
+		})
+
+		It("defaults it to an empty string", func() {
+			Expect(patch.SyslogDrainURL).To(PointTo(BeEmpty()))
+		})
+	})
 })
 
 var _ = Describe("ServiceInstancePatch", func() {
@@ -444,6 +492,26 @@ var _ = Describe("ServiceInstancePatch", func() {
 		})
 	})
 
+	When("the syslog drain url is invalid", func() {
+		BeforeEach(func() {
+			patchPayload.SyslogDrainURL = tools.PtrTo("not a url")
+		})
+
+		It("returns an appropriate error", func() {
+			expectUnprocessableEntityError(validatorErr, "syslog_drain_url must be a URL with one of the schemes syslog, syslog-tls, https")
+		})
+	})
+
+	When("the syslog drain url is empty", func() {
+		BeforeEach(func() {
+			patchPayload.SyslogDrainURL = tools.PtrTo("")
+		})
+
+		It("succeeds", func() {
+			Expect(validatorErr).NotTo(HaveOccurred())
+		})
+	})
+
 	Context("ToServiceInstancePatchMessage", func() {
 		It("converts to repo message correctly", func() {
 			msg := serviceInstancePatch.ToServiceInstancePatchMessage("space-guid", "app-guid")
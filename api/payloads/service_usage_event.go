@@ -0,0 +1,33 @@
+package payloads
+
+import (
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/payloads/parse"
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+type ServiceUsageEventList struct {
+	GUIDs                string
+	AfterGUID            string
+	ServiceInstanceTypes string
+}
+
+func (l ServiceUsageEventList) ToMessage() repositories.ListServiceUsageEventsMessage {
+	return repositories.ListServiceUsageEventsMessage{
+		GUIDs:                parse.ArrayParam(l.GUIDs),
+		AfterGUID:            l.AfterGUID,
+		ServiceInstanceTypes: parse.ArrayParam(l.ServiceInstanceTypes),
+	}
+}
+
+func (l *ServiceUsageEventList) SupportedKeys() []string {
+	return []string{"guids", "after_guid", "service_instance_types", "per_page", "page"}
+}
+
+func (l *ServiceUsageEventList) DecodeFromURLValues(values url.Values) error {
+	l.GUIDs = values.Get("guids")
+	l.AfterGUID = values.Get("after_guid")
+	l.ServiceInstanceTypes = values.Get("service_instance_types")
+	return nil
+}
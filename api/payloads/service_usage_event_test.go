@@ -0,0 +1,42 @@
+package payloads_test
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ServiceUsageEventList", func() {
+	DescribeTable("decodes from url values",
+		func(query string, serviceUsageEventList payloads.ServiceUsageEventList) {
+			actualServiceUsageEventList := payloads.ServiceUsageEventList{}
+			req, err := http.NewRequest("GET", "http://foo.com/?"+query, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(validator.DecodeAndValidateURLValues(req, &actualServiceUsageEventList)).To(Succeed())
+			Expect(actualServiceUsageEventList).To(Equal(serviceUsageEventList))
+		},
+		Entry("guids", "guids=e1,e2", payloads.ServiceUsageEventList{GUIDs: "e1,e2"}),
+		Entry("after_guid", "after_guid=e1", payloads.ServiceUsageEventList{AfterGUID: "e1"}),
+		Entry("service_instance_types", "service_instance_types=managed_service_instance", payloads.ServiceUsageEventList{ServiceInstanceTypes: "managed_service_instance"}),
+		Entry("no filter", "", payloads.ServiceUsageEventList{}),
+	)
+
+	Describe("ToMessage()", func() {
+		It("splits the lists", func() {
+			Expect(payloads.ServiceUsageEventList{
+				GUIDs:                "e1,e2",
+				AfterGUID:            "e0",
+				ServiceInstanceTypes: "managed_service_instance,user_provided_service_instance",
+			}.ToMessage()).To(Equal(repositories.ListServiceUsageEventsMessage{
+				GUIDs:                []string{"e1", "e2"},
+				AfterGUID:            "e0",
+				ServiceInstanceTypes: []string{"managed_service_instance", "user_provided_service_instance"},
+			}))
+		})
+	})
+})
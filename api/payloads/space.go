@@ -45,7 +45,7 @@ type SpacePatch struct {
 
 func (p SpacePatch) Validate() error {
 	return validation.ValidateStruct(&p,
-		validation.Field(&p.Metadata),
+		validation.Field(&p.Metadata, validation.By(validateOrgSpaceMetadataPatch), validation.Skip),
 	)
 }
 
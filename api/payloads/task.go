@@ -6,30 +6,68 @@ import (
 	"strings"
 
 	"code.cloudfoundry.org/korifi/api/repositories"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools"
 	"github.com/jellydator/validation"
 )
 
 type TaskCreate struct {
 	Command  string   `json:"command"`
+	MemoryMB int64    `json:"memory_in_mb"`
+	DiskMB   int64    `json:"disk_in_mb"`
 	Metadata Metadata `json:"metadata"`
 }
 
 func (c TaskCreate) Validate() error {
 	return validation.ValidateStruct(&c,
 		validation.Field(&c.Command, validation.Required),
-		validation.Field(&c.Metadata),
+		validation.Field(&c.MemoryMB, validation.Min(0)),
+		validation.Field(&c.DiskMB, validation.Min(0)),
+		validation.Field(&c.Metadata, validation.By(validateTaskMetadata), validation.Skip),
 	)
 }
 
 func (p TaskCreate) ToMessage(appRecord repositories.AppRecord) repositories.CreateTaskMessage {
 	return repositories.CreateTaskMessage{
-		Command:   p.Command,
-		SpaceGUID: appRecord.SpaceGUID,
-		AppGUID:   appRecord.GUID,
-		Metadata:  repositories.Metadata(p.Metadata),
+		Command:                 p.Command,
+		SpaceGUID:               appRecord.SpaceGUID,
+		AppGUID:                 appRecord.GUID,
+		MemoryMB:                p.MemoryMB,
+		DiskQuotaMB:             p.DiskMB,
+		BackoffLimit:            taskBackoffLimit(p.Metadata.Annotations),
+		TTLSecondsAfterFinished: taskJobTTLSeconds(p.Metadata.Annotations),
+		Metadata:                repositories.Metadata(p.Metadata),
 	}
 }
 
+func taskBackoffLimit(annotations map[string]string) *int32 {
+	value, ok := annotations[korifiv1alpha1.TaskBackoffLimitAnnotationKey]
+	if !ok {
+		return nil
+	}
+
+	backoffLimit, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return nil
+	}
+
+	return tools.PtrTo(int32(backoffLimit))
+}
+
+func taskJobTTLSeconds(annotations map[string]string) *int32 {
+	value, ok := annotations[korifiv1alpha1.TaskJobTTLAnnotationKey]
+	if !ok {
+		return nil
+	}
+
+	ttl, err := tools.ParseDuration(value)
+	if err != nil {
+		return nil
+	}
+
+	return tools.PtrTo(int32(ttl.Seconds()))
+}
+
 type TaskList struct {
 	SequenceIDs []int64
 }
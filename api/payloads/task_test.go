@@ -94,6 +94,57 @@ var _ = Describe("TaskCreate", func() {
 				expectUnprocessableEntityError(validatorErr, "label/annotation key cannot use the cloudfoundry.org domain")
 			})
 		})
+
+		When("the memory is negative", func() {
+			BeforeEach(func() {
+				payload.MemoryMB = -1
+			})
+
+			It("returns an appropriate error", func() {
+				expectUnprocessableEntityError(validatorErr, "memory_in_mb must be no less than 0")
+			})
+		})
+
+		When("the backoff limit and job TTL annotations are set", func() {
+			BeforeEach(func() {
+				payload.Metadata.Annotations["korifi.cloudfoundry.org/task-backoff-limit"] = "3"
+				payload.Metadata.Annotations["korifi.cloudfoundry.org/task-job-ttl"] = "1d"
+			})
+
+			It("succeeds", func() {
+				Expect(validatorErr).NotTo(HaveOccurred())
+			})
+		})
+
+		When("the backoff limit annotation is not a non-negative integer", func() {
+			BeforeEach(func() {
+				payload.Metadata.Annotations["korifi.cloudfoundry.org/task-backoff-limit"] = "-1"
+			})
+
+			It("returns an appropriate error", func() {
+				expectUnprocessableEntityError(validatorErr, "korifi.cloudfoundry.org/task-backoff-limit must be a non-negative integer")
+			})
+		})
+
+		When("the job TTL annotation is not a duration", func() {
+			BeforeEach(func() {
+				payload.Metadata.Annotations["korifi.cloudfoundry.org/task-job-ttl"] = "forever"
+			})
+
+			It("returns an appropriate error", func() {
+				expectUnprocessableEntityError(validatorErr, "korifi.cloudfoundry.org/task-job-ttl must be a duration such as 30m or 1d")
+			})
+		})
+
+		When("other korifi annotations are set", func() {
+			BeforeEach(func() {
+				payload.Metadata.Annotations["korifi.cloudfoundry.org/staging-memory"] = "1G"
+			})
+
+			It("returns an appropriate error", func() {
+				expectUnprocessableEntityError(validatorErr, "label/annotation key cannot use the cloudfoundry.org domain")
+			})
+		})
 	})
 
 	Describe("ToMessage()", func() {
@@ -101,6 +152,10 @@ var _ = Describe("TaskCreate", func() {
 			msg := payload.ToMessage(repositories.AppRecord{GUID: "appGUID", SpaceGUID: "spaceGUID"})
 			Expect(msg.AppGUID).To(Equal("appGUID"))
 			Expect(msg.SpaceGUID).To(Equal("spaceGUID"))
+			Expect(msg.MemoryMB).To(BeZero())
+			Expect(msg.DiskQuotaMB).To(BeZero())
+			Expect(msg.BackoffLimit).To(BeNil())
+			Expect(msg.TTLSecondsAfterFinished).To(BeNil())
 			Expect(msg.Metadata.Labels).To(Equal(map[string]string{
 				"foo": "bar",
 				"bar": "baz",
@@ -109,6 +164,23 @@ var _ = Describe("TaskCreate", func() {
 				"example.org/jim": "hello",
 			}))
 		})
+
+		When("the task overrides the resources, backoff limit and job TTL", func() {
+			BeforeEach(func() {
+				payload.MemoryMB = 1024
+				payload.DiskMB = 2048
+				payload.Metadata.Annotations["korifi.cloudfoundry.org/task-backoff-limit"] = "3"
+				payload.Metadata.Annotations["korifi.cloudfoundry.org/task-job-ttl"] = "1h30m"
+			})
+
+			It("sets them on the message", func() {
+				msg := payload.ToMessage(repositories.AppRecord{GUID: "appGUID", SpaceGUID: "spaceGUID"})
+				Expect(msg.MemoryMB).To(BeEquivalentTo(1024))
+				Expect(msg.DiskQuotaMB).To(BeEquivalentTo(2048))
+				Expect(msg.BackoffLimit).To(Equal(tools.PtrTo[int32](3)))
+				Expect(msg.TTLSecondsAfterFinished).To(Equal(tools.PtrTo[int32](5400)))
+			})
+		})
 	})
 })
 
@@ -0,0 +1,21 @@
+package payloads
+
+import (
+	"net/url"
+	"strings"
+)
+
+type UserList struct {
+	Usernames []string
+}
+
+func (l UserList) SupportedKeys() []string {
+	return []string{"usernames", "origins", "per_page", "page"}
+}
+
+func (l *UserList) DecodeFromURLValues(values url.Values) error {
+	if usernames := values.Get("usernames"); usernames != "" {
+		l.Usernames = strings.Split(usernames, ",")
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package payloads_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/korifi/api/payloads"
+)
+
+var _ = Describe("UserList", func() {
+	DescribeTable("valid query",
+		func(query string, expectedUserList payloads.UserList) {
+			actualUserList, decodeErr := decodeQuery[payloads.UserList](query)
+
+			Expect(decodeErr).NotTo(HaveOccurred())
+			Expect(*actualUserList).To(Equal(expectedUserList))
+		},
+		Entry("usernames", "usernames=foo,bar", payloads.UserList{Usernames: []string{"foo", "bar"}}),
+		Entry("origins", "origins=uaa", payloads.UserList{}),
+		Entry("empty", "usernames=", payloads.UserList{}),
+	)
+
+	It("rejects unsupported keys", func() {
+		_, decodeErr := decodeQuery[payloads.UserList]("foo=bar")
+		Expect(decodeErr).To(MatchError(ContainSubstring("unsupported query parameter: foo")))
+	})
+})
@@ -0,0 +1,37 @@
+package validation
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"slices"
+	"sync/atomic"
+)
+
+type queryParametersDecodedKey struct{}
+
+// WithStrictQueryParameters makes the handler of the request fail with
+// CF-BadQueryParameter when the request has query parameters and the handler
+// has not decoded them, instead of silently ignoring them
+func WithStrictQueryParameters(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryParametersDecodedKey{}, &atomic.Bool{})
+}
+
+// markQueryParametersDecoded records that the handler decoded, and thus
+// validated, the query parameters of the request
+func markQueryParametersDecoded(ctx context.Context) {
+	if decoded, ok := ctx.Value(queryParametersDecodedKey{}).(*atomic.Bool); ok {
+		decoded.Store(true)
+	}
+}
+
+// UndecodedQueryParameters returns the query parameters of a request with
+// strict query parameters that its handler has not decoded
+func UndecodedQueryParameters(r *http.Request) []string {
+	decoded, ok := r.Context().Value(queryParametersDecodedKey{}).(*atomic.Bool)
+	if !ok || decoded.Load() {
+		return nil
+	}
+
+	return slices.Sorted(maps.Keys(r.URL.Query()))
+}
@@ -27,12 +27,21 @@ type IgnoredKeysPayload interface {
 	IgnoredKeys() []*regexp.Regexp
 }
 
-type DecoderValidator struct{}
+type DecoderValidator struct {
+	strictYAMLFields bool
+}
 
 func NewDefaultDecoderValidator() DecoderValidator {
 	return DecoderValidator{}
 }
 
+// WithStrictYAMLFields makes YAML payloads, i.e. manifests, fail validation
+// when they have fields that are not supported, as JSON payloads do
+func (dv DecoderValidator) WithStrictYAMLFields(strict bool) DecoderValidator {
+	dv.strictYAMLFields = strict
+	return dv
+}
+
 func (dv DecoderValidator) DecodeAndValidateJSONPayload(r *http.Request, object any) error {
 	decoder := json.NewDecoder(r.Body)
 	defer r.Body.Close()
@@ -58,9 +67,15 @@ func (dv DecoderValidator) DecodeAndValidateJSONPayload(r *http.Request, object
 func (dv DecoderValidator) DecodeAndValidateYAMLPayload(r *http.Request, object any) error {
 	decoder := yaml.NewDecoder(r.Body)
 	defer r.Body.Close()
-	decoder.KnownFields(false) // TODO: change this to true once we've added all manifest fields to payloads.Manifest
+	decoder.KnownFields(dv.strictYAMLFields) // TODO: always set this once we've added all manifest fields to payloads.Manifest
 	err := decoder.Decode(object)
 	if err != nil {
+		// Unknown fields are reported as type errors, along with fields of
+		// the wrong type, which the decoder skips to decode the rest
+		var typeErr *yaml.TypeError
+		if dv.strictYAMLFields && errors.As(err, &typeErr) {
+			return apierrors.NewUnprocessableEntityError(err, fmt.Sprintf("invalid request body: %s", strings.Join(typeErr.Errors, ", ")))
+		}
 		return apierrors.NewMessageParseError(err)
 	}
 
@@ -71,6 +86,7 @@ func (dv DecoderValidator) DecodeAndValidateURLValues(r *http.Request, object Ke
 	if err := r.ParseForm(); err != nil {
 		return err
 	}
+	markQueryParametersDecoded(r.Context())
 	if err := checkKeysAreSupported(object, r.Form); err != nil {
 		return apierrors.NewUnknownKeyError(err, object.SupportedKeys())
 	}
@@ -1,10 +1,12 @@
 package validation_test
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strconv"
+	"strings"
 
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	"code.cloudfoundry.org/korifi/api/payloads/validation"
@@ -99,9 +101,100 @@ var _ = Describe("Validator", func() {
 				Expect(decoded.Key).To(Equal(3))
 			})
 		})
+
+		When("the request has strict query parameters", func() {
+			var ctx context.Context
+
+			JustBeforeEach(func() {
+				ctx = validation.WithStrictQueryParameters(context.Background())
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestUrl, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(validation.UndecodedQueryParameters(req)).To(ConsistOf("key"))
+
+				decodeErr = requestValidator.DecodeAndValidateURLValues(req, &decoded)
+				Expect(validation.UndecodedQueryParameters(req)).To(BeEmpty())
+			})
+
+			It("marks the query parameters as decoded", func() {
+				Expect(decodeErr).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("DecodeAndValidateYAMLPayload", func() {
+		var (
+			requestValidator validation.DecoderValidator
+			decoded          DecodeTestYAMLPayload
+			decodeErr        error
+			body             string
+		)
+
+		BeforeEach(func() {
+			requestValidator = validation.NewDefaultDecoderValidator()
+			body = "key: 3\nfoo: bar\n"
+			decoded = DecodeTestYAMLPayload{}
+		})
+
+		JustBeforeEach(func() {
+			req, err := http.NewRequest(http.MethodPost, "http://foo.com", strings.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+			decodeErr = requestValidator.DecodeAndValidateYAMLPayload(req, &decoded)
+		})
+
+		It("ignores unknown fields", func() {
+			Expect(decodeErr).NotTo(HaveOccurred())
+			Expect(decoded.Key).To(Equal(3))
+		})
+
+		When("yaml fields are strict", func() {
+			BeforeEach(func() {
+				requestValidator = requestValidator.WithStrictYAMLFields(true)
+			})
+
+			It("returns an unprocessable entity error", func() {
+				Expect(decodeErr).To(BeAssignableToTypeOf(apierrors.UnprocessableEntityError{}))
+				Expect(decodeErr.(apierrors.UnprocessableEntityError).Detail()).To(ContainSubstring("field foo not found"))
+			})
+
+			When("a field has the wrong type", func() {
+				BeforeEach(func() {
+					body = "key: three\n"
+				})
+
+				It("returns an unprocessable entity error", func() {
+					Expect(decodeErr).To(BeAssignableToTypeOf(apierrors.UnprocessableEntityError{}))
+					Expect(decodeErr.(apierrors.UnprocessableEntityError).Detail()).To(ContainSubstring("cannot unmarshal"))
+				})
+			})
+
+			When("the payload is not valid yaml", func() {
+				BeforeEach(func() {
+					body = "key: [3\n"
+				})
+
+				It("returns a message parse error", func() {
+					Expect(decodeErr).To(BeAssignableToTypeOf(apierrors.MessageParseError{}))
+				})
+			})
+
+			When("the payload has no unknown fields", func() {
+				BeforeEach(func() {
+					body = "key: 3\n"
+				})
+
+				It("decodes into the payload object", func() {
+					Expect(decodeErr).NotTo(HaveOccurred())
+					Expect(decoded.Key).To(Equal(3))
+				})
+			})
+		})
 	})
 })
 
+type DecodeTestYAMLPayload struct {
+	Key int `yaml:"key"`
+}
+
 type DecodeTestPayload struct {
 	Key int
 }
@@ -1,6 +1,7 @@
 package presenter
 
 import (
+	"maps"
 	"net/url"
 
 	"code.cloudfoundry.org/korifi/api/repositories"
@@ -142,11 +143,19 @@ type AppEnvVarsLinks struct {
 }
 
 func ForAppEnvVars(record repositories.AppEnvVarsRecord, baseURL url.URL) AppEnvVarsResponse {
+	return forAppEnvVars(record, baseURL, "environment_variables")
+}
+
+func ForAppStagingEnvVars(record repositories.AppEnvVarsRecord, baseURL url.URL) AppEnvVarsResponse {
+	return forAppEnvVars(record, baseURL, "staging_environment_variables")
+}
+
+func forAppEnvVars(record repositories.AppEnvVarsRecord, baseURL url.URL, path string) AppEnvVarsResponse {
 	return AppEnvVarsResponse{
 		Var: record.EnvironmentVariables,
 		Links: AppEnvVarsLinks{
 			Self: Link{
-				HRef: buildURL(baseURL).appendPath(appsBase, record.AppGUID, "environment_variables").build(),
+				HRef: buildURL(baseURL).appendPath(appsBase, record.AppGUID, path).build(),
 			},
 			App: Link{
 				HRef: buildURL(baseURL).appendPath(appsBase, record.AppGUID).build(),
@@ -163,11 +172,18 @@ type AppEnvResponse struct {
 	ApplicationEnvJSON   map[string]any    `json:"application_env_json"`
 }
 
+// ForAppEnv presents the env of an app. The staging-only env vars of the app
+// are presented with the staging env var group, as they override it in the
+// same way when staging the app.
 func ForAppEnv(envVarRecord repositories.AppEnvRecord) AppEnvResponse {
+	stagingEnv := map[string]string{}
+	maps.Copy(stagingEnv, envVarRecord.StagingEnvVarGroup)
+	maps.Copy(stagingEnv, envVarRecord.StagingEnvVariables)
+
 	return AppEnvResponse{
 		EnvironmentVariables: envVarRecord.EnvironmentVariables,
-		StagingEnvJSON:       map[string]string{},
-		RunningEnvJSON:       map[string]string{},
+		StagingEnvJSON:       stagingEnv,
+		RunningEnvJSON:       emptyMapIfNil(envVarRecord.RunningEnvVarGroup),
 		SystemEnvJSON:        emptyMapToAnyIfEmpty(envVarRecord.SystemEnv),
 		ApplicationEnvJSON:   emptyMapToAnyIfEmpty(envVarRecord.AppEnv),
 	}
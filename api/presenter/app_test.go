@@ -215,6 +215,12 @@ var _ = Describe("App", func() {
 		BeforeEach(func() {
 			record = repositories.AppEnvRecord{
 				EnvironmentVariables: map[string]string{"VAR": "VAL"},
+				StagingEnvVariables:  map[string]string{"STAGING_VAR": "STAGING_VAL"},
+				RunningEnvVarGroup:   map[string]string{"RUNNING_GROUP_VAR": "RUNNING_GROUP_VAL"},
+				StagingEnvVarGroup: map[string]string{
+					"STAGING_VAR":       "STAGING_GROUP_VAL",
+					"STAGING_GROUP_VAR": "STAGING_GROUP_VAL",
+				},
 				SystemEnv: map[string]any{
 					"VCAP_SERVICES": map[string]any{
 						"mysql": map[string]any{
@@ -239,8 +245,13 @@ var _ = Describe("App", func() {
 
 		It("returns the expected output", func() {
 			Expect(output).To(MatchJSON(`{
-				"staging_env_json": {},
-				"running_env_json": {},
+				"staging_env_json": {
+					"STAGING_VAR": "STAGING_VAL",
+					"STAGING_GROUP_VAR": "STAGING_GROUP_VAL"
+				},
+				"running_env_json": {
+					"RUNNING_GROUP_VAR": "RUNNING_GROUP_VAL"
+				},
 				"environment_variables": {
 					"VAR": "VAL"
 				},
@@ -259,6 +270,19 @@ var _ = Describe("App", func() {
 			}`))
 		})
 
+		When("the env var groups are nil", func() {
+			BeforeEach(func() {
+				record.RunningEnvVarGroup = nil
+				record.StagingEnvVarGroup = nil
+				record.StagingEnvVariables = nil
+			})
+
+			It("returns empty maps", func() {
+				Expect(output).To(MatchJSONPath("$.running_env_json", Not(BeNil())))
+				Expect(output).To(MatchJSONPath("$.staging_env_json", Not(BeNil())))
+			})
+		})
+
 		When("system env is nil", func() {
 			BeforeEach(func() {
 				record.SystemEnv = nil
@@ -319,4 +343,42 @@ var _ = Describe("App", func() {
 			}`))
 		})
 	})
+
+	Describe("App Staging Env Vars", func() {
+		var record repositories.AppEnvVarsRecord
+
+		BeforeEach(func() {
+			record = repositories.AppEnvVarsRecord{
+				Name:      "my-app-staging-env",
+				AppGUID:   "app-guid",
+				SpaceGUID: "space-guid",
+				EnvironmentVariables: map[string]string{
+					"KEY0": "VAL0",
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			response := presenter.ForAppStagingEnvVars(record, *baseURL)
+			var err error
+			output, err = json.Marshal(response)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the expected output", func() {
+			Expect(output).To(MatchJSON(`{
+				"var": {
+					"KEY0": "VAL0"
+				},
+				"links": {
+					"self": {
+						"href": "https://api.example.org/v3/apps/app-guid/staging_environment_variables"
+					},
+					"app": {
+						"href": "https://api.example.org/v3/apps/app-guid"
+					}
+				}
+			}`))
+		})
+	})
 })
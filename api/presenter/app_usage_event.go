@@ -0,0 +1,99 @@
+package presenter
+
+import (
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/tools"
+)
+
+const (
+	appUsageEventsBase = "/v3/app_usage_events"
+)
+
+type AppUsageEventResponse struct {
+	GUID                  string                     `json:"guid"`
+	CreatedAt             string                     `json:"created_at"`
+	UpdatedAt             string                     `json:"updated_at"`
+	State                 AppUsageEventValue[string] `json:"state"`
+	App                   AppUsageEventResource      `json:"app"`
+	Process               *AppUsageEventProcess      `json:"process"`
+	Space                 AppUsageEventResource      `json:"space"`
+	Organization          AppUsageEventOrganization  `json:"organization"`
+	MemoryInMBPerInstance AppUsageEventValue[int64]  `json:"memory_in_mb_per_instance"`
+	InstanceCount         AppUsageEventValue[int32]  `json:"instance_count"`
+	Links                 AppUsageEventLinks         `json:"links"`
+}
+
+// AppUsageEventValue is a value of the usage after and before the change. The
+// previous value is null for events that do not follow a recorded usage.
+type AppUsageEventValue[T any] struct {
+	Current  T  `json:"current"`
+	Previous *T `json:"previous"`
+}
+
+type AppUsageEventResource struct {
+	GUID string `json:"guid"`
+	Name string `json:"name"`
+}
+
+type AppUsageEventProcess struct {
+	GUID string `json:"guid"`
+	Type string `json:"type"`
+}
+
+type AppUsageEventOrganization struct {
+	GUID string `json:"guid"`
+}
+
+type AppUsageEventLinks struct {
+	Self Link `json:"self"`
+}
+
+func ForAppUsageEvent(event repositories.AppUsageEventRecord, baseURL url.URL) AppUsageEventResponse {
+	response := AppUsageEventResponse{
+		GUID:      event.GUID,
+		CreatedAt: formatTimestamp(&event.CreatedAt),
+		UpdatedAt: formatTimestamp(&event.CreatedAt),
+		State: AppUsageEventValue[string]{
+			Current: event.State,
+		},
+		App: AppUsageEventResource{
+			GUID: event.AppGUID,
+			Name: event.AppName,
+		},
+		Space: AppUsageEventResource{
+			GUID: event.SpaceGUID,
+			Name: event.SpaceName,
+		},
+		Organization: AppUsageEventOrganization{
+			GUID: event.OrgGUID,
+		},
+		MemoryInMBPerInstance: AppUsageEventValue[int64]{
+			Current: event.MemoryMB,
+		},
+		InstanceCount: AppUsageEventValue[int32]{
+			Current: event.InstanceCount,
+		},
+		Links: AppUsageEventLinks{
+			Self: Link{
+				HRef: buildURL(baseURL).appendPath(appUsageEventsBase, event.GUID).build(),
+			},
+		},
+	}
+
+	if event.ProcessGUID != "" {
+		response.Process = &AppUsageEventProcess{
+			GUID: event.ProcessGUID,
+			Type: event.ProcessType,
+		}
+	}
+
+	if event.PreviousState != "" {
+		response.State.Previous = tools.PtrTo(event.PreviousState)
+		response.MemoryInMBPerInstance.Previous = tools.PtrTo(event.PreviousMemoryMB)
+		response.InstanceCount.Previous = tools.PtrTo(event.PreviousInstanceCount)
+	}
+
+	return response
+}
@@ -0,0 +1,110 @@
+package presenter_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	. "code.cloudfoundry.org/korifi/tests/matchers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AppUsageEvent", func() {
+	var (
+		baseURL *url.URL
+		output  []byte
+		record  repositories.AppUsageEventRecord
+	)
+
+	BeforeEach(func() {
+		var err error
+		baseURL, err = url.Parse("https://api.example.org")
+		Expect(err).NotTo(HaveOccurred())
+		record = repositories.AppUsageEventRecord{
+			GUID:                  "app-usage-event-guid",
+			CreatedAt:             time.UnixMilli(1000),
+			State:                 "STARTED",
+			PreviousState:         "STOPPED",
+			AppGUID:               "app-guid",
+			AppName:               "app-name",
+			ProcessGUID:           "process-guid",
+			ProcessType:           "web",
+			SpaceGUID:             "space-guid",
+			SpaceName:             "space-name",
+			OrgGUID:               "org-guid",
+			InstanceCount:         2,
+			PreviousInstanceCount: 1,
+			MemoryMB:              256,
+			PreviousMemoryMB:      128,
+		}
+	})
+
+	JustBeforeEach(func() {
+		response := presenter.ForAppUsageEvent(record, *baseURL)
+		var err error
+		output, err = json.Marshal(response)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("produces expected app usage event json", func() {
+		Expect(output).To(MatchJSON(`{
+			"guid": "app-usage-event-guid",
+			"created_at": "1970-01-01T00:00:01Z",
+			"updated_at": "1970-01-01T00:00:01Z",
+			"state": {
+				"current": "STARTED",
+				"previous": "STOPPED"
+			},
+			"app": {
+				"guid": "app-guid",
+				"name": "app-name"
+			},
+			"process": {
+				"guid": "process-guid",
+				"type": "web"
+			},
+			"space": {
+				"guid": "space-guid",
+				"name": "space-name"
+			},
+			"organization": {
+				"guid": "org-guid"
+			},
+			"memory_in_mb_per_instance": {
+				"current": 256,
+				"previous": 128
+			},
+			"instance_count": {
+				"current": 2,
+				"previous": 1
+			},
+			"links": {
+				"self": {
+					"href": "https://api.example.org/v3/app_usage_events/app-usage-event-guid"
+				}
+			}
+		}`))
+	})
+
+	When("the event is a staging event", func() {
+		BeforeEach(func() {
+			record.State = "STAGING_STARTED"
+			record.PreviousState = ""
+			record.ProcessGUID = ""
+			record.ProcessType = ""
+		})
+
+		It("presents no process and no previous values", func() {
+			Expect(output).To(SatisfyAll(
+				MatchJSONPath("$.process", BeNil()),
+				MatchJSONPath("$.state.previous", BeNil()),
+				MatchJSONPath("$.instance_count.previous", BeNil()),
+				MatchJSONPath("$.memory_in_mb_per_instance.previous", BeNil()),
+			))
+		})
+	})
+})
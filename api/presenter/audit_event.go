@@ -0,0 +1,60 @@
+package presenter
+
+import (
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+const (
+	auditEventsBase = "/v3/audit_events"
+)
+
+type AuditEventResponse struct {
+	GUID      string           `json:"guid"`
+	CreatedAt string           `json:"created_at"`
+	UpdatedAt string           `json:"updated_at"`
+	Type      string           `json:"type"`
+	Actor     AuditEventActor  `json:"actor"`
+	Target    AuditEventActor  `json:"target"`
+	Data      map[string]any   `json:"data"`
+	Space     RelationshipData `json:"space"`
+	Links     AuditEventLinks  `json:"links"`
+}
+
+type AuditEventActor struct {
+	GUID string `json:"guid"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type AuditEventLinks struct {
+	Self Link `json:"self"`
+}
+
+func ForAuditEvent(auditEvent repositories.AuditEventRecord, baseURL url.URL) AuditEventResponse {
+	return AuditEventResponse{
+		GUID:      auditEvent.GUID,
+		CreatedAt: formatTimestamp(&auditEvent.CreatedAt),
+		UpdatedAt: formatTimestamp(auditEvent.UpdatedAt),
+		Type:      auditEvent.Type,
+		Actor: AuditEventActor{
+			GUID: auditEvent.ActorGUID,
+			Type: auditEvent.ActorType,
+			Name: auditEvent.ActorName,
+		},
+		Target: AuditEventActor{
+			GUID: auditEvent.TargetGUID,
+			Type: auditEvent.TargetType,
+		},
+		Data: emptyMapIfNil(auditEvent.Data),
+		Space: RelationshipData{
+			GUID: auditEvent.SpaceGUID,
+		},
+		Links: AuditEventLinks{
+			Self: Link{
+				HRef: buildURL(baseURL).appendPath(auditEventsBase, auditEvent.GUID).build(),
+			},
+		},
+	}
+}
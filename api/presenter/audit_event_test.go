@@ -0,0 +1,82 @@
+package presenter_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/tools"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AuditEvent", func() {
+	var (
+		baseURL *url.URL
+		output  []byte
+		record  repositories.AuditEventRecord
+	)
+
+	BeforeEach(func() {
+		var err error
+		baseURL, err = url.Parse("https://api.example.org")
+		Expect(err).NotTo(HaveOccurred())
+		record = repositories.AuditEventRecord{
+			GUID:       "audit-event-guid",
+			Type:       "app.crash",
+			ActorGUID:  "process-guid",
+			ActorType:  "process",
+			ActorName:  "web",
+			TargetGUID: "app-guid",
+			TargetType: "app",
+			SpaceGUID:  "space-guid",
+			Data: map[string]any{
+				"index":       1,
+				"crash_count": 3,
+			},
+			CreatedAt: time.UnixMilli(1000),
+			UpdatedAt: tools.PtrTo(time.UnixMilli(2000)),
+		}
+	})
+
+	JustBeforeEach(func() {
+		response := presenter.ForAuditEvent(record, *baseURL)
+		var err error
+		output, err = json.Marshal(response)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("produces expected audit event json", func() {
+		Expect(output).To(MatchJSON(`{
+			"guid": "audit-event-guid",
+			"created_at": "1970-01-01T00:00:01Z",
+			"updated_at": "1970-01-01T00:00:02Z",
+			"type": "app.crash",
+			"actor": {
+				"guid": "process-guid",
+				"type": "process",
+				"name": "web"
+			},
+			"target": {
+				"guid": "app-guid",
+				"type": "app",
+				"name": ""
+			},
+			"data": {
+				"index": 1,
+				"crash_count": 3
+			},
+			"space": {
+				"guid": "space-guid"
+			},
+			"links": {
+				"self": {
+					"href": "https://api.example.org/v3/audit_events/audit-event-guid"
+				}
+			}
+		}`))
+	})
+})
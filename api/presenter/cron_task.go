@@ -0,0 +1,65 @@
+package presenter
+
+import (
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/model"
+)
+
+const (
+	cronTasksBase = "/v3/cron_tasks"
+)
+
+type CronTaskResponse struct {
+	GUID             string                             `json:"guid"`
+	Command          string                             `json:"command"`
+	Schedule         string                             `json:"schedule"`
+	Enabled          bool                               `json:"enabled"`
+	MemoryMB         int64                              `json:"memory_in_mb"`
+	DiskMB           int64                              `json:"disk_in_mb"`
+	LastScheduleTime *string                            `json:"last_schedule_time"`
+	CreatedAt        string                             `json:"created_at"`
+	UpdatedAt        string                             `json:"updated_at"`
+	Metadata         Metadata                           `json:"metadata"`
+	Relationships    map[string]model.ToOneRelationship `json:"relationships"`
+	Links            CronTaskLinks                      `json:"links"`
+}
+
+type CronTaskLinks struct {
+	Self Link `json:"self"`
+	App  Link `json:"app"`
+}
+
+func ForCronTask(cronTask repositories.CronTaskRecord, baseURL url.URL) CronTaskResponse {
+	var lastScheduleTime *string
+	if cronTask.LastScheduleTime != nil {
+		formatted := formatTimestamp(cronTask.LastScheduleTime)
+		lastScheduleTime = &formatted
+	}
+
+	return CronTaskResponse{
+		GUID:             cronTask.GUID,
+		Command:          cronTask.Command,
+		Schedule:         cronTask.Schedule,
+		Enabled:          cronTask.Enabled,
+		MemoryMB:         cronTask.MemoryMB,
+		DiskMB:           cronTask.DiskMB,
+		LastScheduleTime: lastScheduleTime,
+		CreatedAt:        formatTimestamp(&cronTask.CreatedAt),
+		UpdatedAt:        formatTimestamp(cronTask.UpdatedAt),
+		Metadata: Metadata{
+			Labels:      emptyMapIfNil(cronTask.Labels),
+			Annotations: emptyMapIfNil(cronTask.Annotations),
+		},
+		Relationships: ForRelationships(cronTask.Relationships()),
+		Links: CronTaskLinks{
+			Self: Link{
+				HRef: buildURL(baseURL).appendPath(cronTasksBase, cronTask.GUID).build(),
+			},
+			App: Link{
+				HRef: buildURL(baseURL).appendPath(appsBase, cronTask.AppGUID).build(),
+			},
+		},
+	}
+}
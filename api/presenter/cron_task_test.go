@@ -0,0 +1,98 @@
+package presenter_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	. "code.cloudfoundry.org/korifi/tests/matchers"
+	"code.cloudfoundry.org/korifi/tools"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CronTask", func() {
+	var (
+		baseURL *url.URL
+		output  []byte
+		record  repositories.CronTaskRecord
+	)
+
+	BeforeEach(func() {
+		var err error
+		baseURL, err = url.Parse("https://api.example.org")
+		Expect(err).NotTo(HaveOccurred())
+		record = repositories.CronTaskRecord{
+			GUID:             "cron-task-guid",
+			SpaceGUID:        "space-guid",
+			AppGUID:          "app-guid",
+			Command:          "rake db:migrate",
+			Schedule:         "0 2 * * *",
+			Enabled:          true,
+			MemoryMB:         100,
+			DiskMB:           200,
+			LastScheduleTime: tools.PtrTo(time.UnixMilli(3000)),
+			Labels:           map[string]string{"l": "l1"},
+			Annotations:      map[string]string{"a": "a1"},
+			CreatedAt:        time.UnixMilli(1000),
+			UpdatedAt:        tools.PtrTo(time.UnixMilli(2000)),
+		}
+	})
+
+	JustBeforeEach(func() {
+		response := presenter.ForCronTask(record, *baseURL)
+		var err error
+		output, err = json.Marshal(response)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("produces expected cron task json", func() {
+		Expect(output).To(MatchJSON(`{
+			"guid": "cron-task-guid",
+			"command": "rake db:migrate",
+			"schedule": "0 2 * * *",
+			"enabled": true,
+			"memory_in_mb": 100,
+			"disk_in_mb": 200,
+			"last_schedule_time": "1970-01-01T00:00:03Z",
+			"created_at": "1970-01-01T00:00:01Z",
+			"updated_at": "1970-01-01T00:00:02Z",
+			"metadata": {
+				"labels": {
+					"l": "l1"
+				},
+				"annotations": {
+					"a": "a1"
+				}
+			},
+			"relationships": {
+				"app": {
+					"data": {
+						"guid": "app-guid"
+					}
+				}
+			},
+			"links": {
+				"self": {
+					"href": "https://api.example.org/v3/cron_tasks/cron-task-guid"
+				},
+				"app": {
+					"href": "https://api.example.org/v3/apps/app-guid"
+				}
+			}
+		}`))
+	})
+
+	When("the cron task has never been scheduled", func() {
+		BeforeEach(func() {
+			record.LastScheduleTime = nil
+		})
+
+		It("presents a null last schedule time", func() {
+			Expect(output).To(MatchJSONPath("$.last_schedule_time", BeNil()))
+		})
+	})
+})
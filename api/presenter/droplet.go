@@ -51,7 +51,7 @@ func ForDroplet(dropletRecord repositories.DropletRecord, baseURL url.URL) Dropl
 			},
 		},
 		ExecutionMetadata: "",
-		Buildpacks:        []BuildpackData{},
+		Buildpacks:        forDropletBuildpacks(dropletRecord.Buildpacks),
 		ProcessTypes:      dropletRecord.ProcessTypes,
 		Stack:             dropletRecord.Stack,
 		Relationships:     ForRelationships(dropletRecord.Relationships()),
@@ -84,3 +84,16 @@ func ForDroplet(dropletRecord repositories.DropletRecord, baseURL url.URL) Dropl
 	}
 	return toReturn
 }
+
+func forDropletBuildpacks(buildpacks []repositories.DropletBuildpackRecord) []BuildpackData {
+	result := []BuildpackData{}
+	for _, bp := range buildpacks {
+		result = append(result, BuildpackData{
+			Name:          bp.Name,
+			BuildpackName: bp.Name,
+			Version:       bp.Version,
+		})
+	}
+
+	return result
+}
@@ -170,6 +170,25 @@ var _ = Describe("Droplet", func() {
 		})
 	})
 
+	When("the droplet was built with buildpacks", func() {
+		BeforeEach(func() {
+			record.Lifecycle.Data.Buildpacks = []string{"paketo-buildpacks/ruby"}
+			record.Buildpacks = []repositories.DropletBuildpackRecord{
+				{Name: "paketo-buildpacks/ruby", Version: "1.2.3"},
+			}
+		})
+
+		It("lists the buildpacks", func() {
+			Expect(output).To(MatchJSONPath("$.lifecycle.data.buildpacks", ConsistOf("paketo-buildpacks/ruby")))
+			Expect(output).To(MatchJSONPath("$.buildpacks", ConsistOf(map[string]interface{}{
+				"name":           "paketo-buildpacks/ruby",
+				"buildpack_name": "paketo-buildpacks/ruby",
+				"detect_output":  "",
+				"version":        "1.2.3",
+			})))
+		})
+	})
+
 	When("labels is nil", func() {
 		BeforeEach(func() {
 			record.Labels = nil
@@ -1,17 +1,46 @@
 package presenter
 
 import (
+	"time"
+
 	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/repositories"
 )
 
 type IdentityResponse struct {
-	Name string `json:"name"`
-	Kind string `json:"kind"`
+	Name      string                 `json:"name"`
+	Kind      string                 `json:"kind"`
+	ExpiresAt *string                `json:"expires_at"`
+	Roles     []IdentityRoleResponse `json:"roles"`
+}
+
+type IdentityRoleResponse struct {
+	GUID             string `json:"guid"`
+	Type             string `json:"type"`
+	OrganizationGUID string `json:"organization_guid,omitempty"`
+	SpaceGUID        string `json:"space_guid,omitempty"`
 }
 
-func ForWhoAmI(identity authorization.Identity) IdentityResponse {
-	return IdentityResponse{
-		Name: identity.Name,
-		Kind: identity.Kind,
+func ForWhoAmI(identity authorization.Identity, expiresAt *time.Time, roles []repositories.RoleRecord) IdentityResponse {
+	response := IdentityResponse{
+		Name:  identity.Name,
+		Kind:  identity.Kind,
+		Roles: []IdentityRoleResponse{},
+	}
+
+	if expiresAt != nil {
+		formattedExpiresAt := formatTimestamp(expiresAt)
+		response.ExpiresAt = &formattedExpiresAt
+	}
+
+	for _, role := range roles {
+		response.Roles = append(response.Roles, IdentityRoleResponse{
+			GUID:             role.GUID,
+			Type:             role.Type,
+			OrganizationGUID: role.Org,
+			SpaceGUID:        role.Space,
+		})
 	}
+
+	return response
 }
@@ -2,9 +2,11 @@ package presenter_test
 
 import (
 	"encoding/json"
+	"time"
 
 	"code.cloudfoundry.org/korifi/api/authorization"
 	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -12,8 +14,10 @@ import (
 
 var _ = Describe("Identity", func() {
 	var (
-		output []byte
-		id     authorization.Identity
+		output    []byte
+		id        authorization.Identity
+		expiresAt *time.Time
+		roles     []repositories.RoleRecord
 	)
 
 	BeforeEach(func() {
@@ -21,10 +25,12 @@ var _ = Describe("Identity", func() {
 			Name: "the-user",
 			Kind: "User",
 		}
+		expiresAt = nil
+		roles = nil
 	})
 
 	JustBeforeEach(func() {
-		response := presenter.ForWhoAmI(id)
+		response := presenter.ForWhoAmI(id, expiresAt, roles)
 		var err error
 		output, err = json.Marshal(response)
 		Expect(err).NotTo(HaveOccurred())
@@ -33,7 +39,40 @@ var _ = Describe("Identity", func() {
 	It("produces expected identity json", func() {
 		Expect(output).To(MatchJSON(`{
 			"name": "the-user",
-			"kind": "User"
+			"kind": "User",
+			"expires_at": null,
+			"roles": []
 		}`))
 	})
+
+	When("the expiry and roles are known", func() {
+		BeforeEach(func() {
+			expiry := time.UnixMilli(1000).UTC()
+			expiresAt = &expiry
+			roles = []repositories.RoleRecord{
+				{GUID: "org-role-guid", Type: "organization_manager", Org: "org-guid"},
+				{GUID: "space-role-guid", Type: "space_developer", Space: "space-guid"},
+			}
+		})
+
+		It("includes them in the json", func() {
+			Expect(output).To(MatchJSON(`{
+				"name": "the-user",
+				"kind": "User",
+				"expires_at": "1970-01-01T00:00:01Z",
+				"roles": [
+					{
+						"guid": "org-role-guid",
+						"type": "organization_manager",
+						"organization_guid": "org-guid"
+					},
+					{
+						"guid": "space-role-guid",
+						"type": "space_developer",
+						"space_guid": "space-guid"
+					}
+				]
+			}`))
+		})
+	})
 })
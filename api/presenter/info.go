@@ -4,6 +4,7 @@ import (
 	"net/url"
 
 	"code.cloudfoundry.org/korifi/api/config"
+	"code.cloudfoundry.org/korifi/tools"
 	"code.cloudfoundry.org/korifi/version"
 )
 
@@ -44,3 +45,59 @@ func ForInfoV3(baseURL url.URL, infoConfig config.InfoConfig) InfoV3Response {
 		},
 	}
 }
+
+// OSBAPIVersion is the version of the Open Service Broker API the
+// controllers use to talk to service brokers
+const OSBAPIVersion = "2.17"
+
+type InfoV2Response struct {
+	Name                     string  `json:"name"`
+	Build                    string  `json:"build"`
+	Support                  string  `json:"support"`
+	Version                  int     `json:"version"`
+	Description              string  `json:"description"`
+	AuthorizationEndpoint    *string `json:"authorization_endpoint"`
+	TokenEndpoint            *string `json:"token_endpoint"`
+	MinCLIVersion            *string `json:"min_cli_version"`
+	MinRecommendedCLIVersion *string `json:"min_recommended_cli_version"`
+	AppSSHEndpoint           *string `json:"app_ssh_endpoint"`
+	AppSSHHostKeyFingerprint *string `json:"app_ssh_host_key_fingerprint"`
+	AppSSHOAuthClient        *string `json:"app_ssh_oauth_client"`
+	DopplerLoggingEndpoint   *string `json:"doppler_logging_endpoint"`
+	APIVersion               string  `json:"api_version"`
+	OSBAPIVersion            string  `json:"osbapi_version"`
+	RoutingEndpoint          *string `json:"routing_endpoint"`
+}
+
+// ForInfoV2 presents the v2 info of the Cloud Controller for tools that
+// still call it. The login and token endpoints are only advertised when the
+// OAuth shim serves them. Korifi does not provide ssh, doppler or a routing
+// API.
+func ForInfoV2(baseURL url.URL, infoConfig config.InfoConfig, oauthShimEnabled bool) InfoV2Response {
+	var oauthEndpoint *string
+	if oauthShimEnabled {
+		oauthEndpoint = tools.PtrTo(buildURL(baseURL).build())
+	}
+
+	return InfoV2Response{
+		Name:                     infoConfig.Name,
+		Build:                    version.Version,
+		Support:                  infoConfig.SupportAddress,
+		Version:                  0,
+		Description:              infoConfig.Description,
+		AuthorizationEndpoint:    oauthEndpoint,
+		TokenEndpoint:            oauthEndpoint,
+		MinCLIVersion:            nilIfEmpty(infoConfig.MinCLIVersion),
+		MinRecommendedCLIVersion: nilIfEmpty(infoConfig.RecommendedCLIVersion),
+		APIVersion:               V2APIVersion,
+		OSBAPIVersion:            OSBAPIVersion,
+	}
+}
+
+func nilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
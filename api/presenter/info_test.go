@@ -6,6 +6,7 @@ import (
 
 	"code.cloudfoundry.org/korifi/api/config"
 	"code.cloudfoundry.org/korifi/api/presenter"
+	. "code.cloudfoundry.org/korifi/tests/matchers"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -58,4 +59,63 @@ var _ = Describe("Info endpoints", func() {
 			}`))
 		})
 	})
+
+	Context("/v2/info", func() {
+		var oauthShimEnabled bool
+
+		BeforeEach(func() {
+			oauthShimEnabled = false
+		})
+
+		JustBeforeEach(func() {
+			response := presenter.ForInfoV2(*baseURL, infoConfig, oauthShimEnabled)
+			var err error
+			output, err = json.Marshal(response)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("produces expected info v2 json", func() {
+			Expect(output).To(MatchJSON(`{
+				"name": "",
+				"build": "v9999.99.99-local.dev",
+				"support": "https://www.cloudfoundry.org/technology/korifi/",
+				"version": 0,
+				"description": "",
+				"authorization_endpoint": null,
+				"token_endpoint": null,
+				"min_cli_version": null,
+				"min_recommended_cli_version": null,
+				"app_ssh_endpoint": null,
+				"app_ssh_host_key_fingerprint": null,
+				"app_ssh_oauth_client": null,
+				"doppler_logging_endpoint": null,
+				"api_version": "2.164.0+cf-k8s",
+				"osbapi_version": "2.17",
+				"routing_endpoint": null
+			}`))
+		})
+
+		When("the OAuth shim is enabled", func() {
+			BeforeEach(func() {
+				oauthShimEnabled = true
+			})
+
+			It("advertises the api as the authorization and token endpoint", func() {
+				Expect(output).To(MatchJSONPath("$.authorization_endpoint", "https://api.example.org"))
+				Expect(output).To(MatchJSONPath("$.token_endpoint", "https://api.example.org"))
+			})
+		})
+
+		When("the cli versions are configured", func() {
+			BeforeEach(func() {
+				infoConfig.MinCLIVersion = "8.0.0"
+				infoConfig.RecommendedCLIVersion = "8.7.0"
+			})
+
+			It("includes them", func() {
+				Expect(output).To(MatchJSONPath("$.min_cli_version", "8.0.0"))
+				Expect(output).To(MatchJSONPath("$.min_recommended_cli_version", "8.7.0"))
+			})
+		})
+	})
 })
@@ -1,6 +1,8 @@
 package presenter
 
 import (
+	"encoding/json"
+
 	"code.cloudfoundry.org/go-loggregator/v8/rpc/loggregator_v2"
 	"code.cloudfoundry.org/korifi/api/repositories"
 )
@@ -45,3 +47,28 @@ func ForLogs(logRecords []repositories.LogRecord) LogCacheReadResponse {
 		},
 	}
 }
+
+type LogCachePromQLResponse struct {
+	Status string                     `json:"status"`
+	Data   LogCachePromQLResponseData `json:"data"`
+}
+
+type LogCachePromQLResponseData struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+func ForPromQL(record repositories.PromQLRecord) LogCachePromQLResponse {
+	result := record.Data.Result
+	if len(result) == 0 {
+		result = json.RawMessage("[]")
+	}
+
+	return LogCachePromQLResponse{
+		Status: record.Status,
+		Data: LogCachePromQLResponseData{
+			ResultType: record.Data.ResultType,
+			Result:     result,
+		},
+	}
+}
@@ -65,3 +65,46 @@ var _ = Describe("LogCache", func() {
 		}`))
 	})
 })
+
+var _ = Describe("PromQL", func() {
+	var (
+		output []byte
+		record repositories.PromQLRecord
+	)
+
+	BeforeEach(func() {
+		record = repositories.PromQLRecord{
+			Status: "success",
+			Data: repositories.PromQLData{
+				ResultType: "vector",
+				Result:     json.RawMessage(`[{"metric":{"source_id":"app-guid"},"value":[1,"0.5"]}]`),
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		output, err = json.Marshal(presenter.ForPromQL(record))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("produces the expected promql json", func() {
+		Expect(output).To(MatchJSON(`{
+			"status": "success",
+			"data": {
+				"resultType": "vector",
+				"result": [{"metric": {"source_id": "app-guid"}, "value": [1, "0.5"]}]
+			}
+		}`))
+	})
+
+	When("the result is empty", func() {
+		BeforeEach(func() {
+			record.Data.Result = nil
+		})
+
+		It("presents an empty result list", func() {
+			Expect(output).To(MatchJSON(`{"status": "success", "data": {"resultType": "vector", "result": []}}`))
+		})
+	})
+})
@@ -0,0 +1,52 @@
+package presenter
+
+import (
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+)
+
+type LoginResponse struct {
+	App      LoginApp            `json:"app"`
+	Links    map[string]string   `json:"links"`
+	Prompts  map[string][]string `json:"prompts"`
+	ZoneName string              `json:"zone_name"`
+}
+
+type LoginApp struct {
+	Version string `json:"version"`
+}
+
+// ForLogin presents the UAA login info the CLI reads the login prompts from
+func ForLogin(baseURL url.URL, version string) LoginResponse {
+	return LoginResponse{
+		App: LoginApp{
+			Version: version,
+		},
+		Links: map[string]string{
+			"uaa":   buildURL(baseURL).build(),
+			"login": buildURL(baseURL).build(),
+		},
+		Prompts: map[string][]string{
+			"username": {"text", "Username"},
+			"password": {"password", "Password"},
+		},
+		ZoneName: "uaa",
+	}
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"`
+}
+
+func ForToken(tokenResp authorization.TokenResponse) TokenResponse {
+	return TokenResponse{
+		AccessToken:  tokenResp.AccessToken,
+		TokenType:    "bearer",
+		RefreshToken: tokenResp.RefreshToken,
+		ExpiresIn:    tokenResp.ExpiresIn,
+	}
+}
@@ -0,0 +1,64 @@
+package presenter_test
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/presenter"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OAuth", func() {
+	var output []byte
+
+	Describe("ForLogin", func() {
+		JustBeforeEach(func() {
+			baseURL, err := url.Parse("https://api.example.org")
+			Expect(err).NotTo(HaveOccurred())
+
+			output, err = json.Marshal(presenter.ForLogin(*baseURL, "v1.2.3"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("produces the expected login json", func() {
+			Expect(output).To(MatchJSON(`{
+				"app": {
+					"version": "v1.2.3"
+				},
+				"links": {
+					"uaa": "https://api.example.org",
+					"login": "https://api.example.org"
+				},
+				"prompts": {
+					"username": ["text", "Username"],
+					"password": ["password", "Password"]
+				},
+				"zone_name": "uaa"
+			}`))
+		})
+	})
+
+	Describe("ForToken", func() {
+		JustBeforeEach(func() {
+			var err error
+			output, err = json.Marshal(presenter.ForToken(authorization.TokenResponse{
+				AccessToken:  "the-access-token",
+				RefreshToken: "the-refresh-token",
+				ExpiresIn:    300,
+			}))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("produces the expected token json", func() {
+			Expect(output).To(MatchJSON(`{
+				"access_token": "the-access-token",
+				"token_type": "bearer",
+				"refresh_token": "the-refresh-token",
+				"expires_in": 300
+			}`))
+		})
+	})
+})
@@ -24,7 +24,13 @@ type PackageResponse struct {
 }
 
 type PackageData struct {
-	Image string `json:"image,omitempty"`
+	Image    string           `json:"image,omitempty"`
+	Checksum *PackageChecksum `json:"checksum,omitempty"`
+}
+
+type PackageChecksum struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
 }
 
 type PackageLinks struct {
@@ -63,7 +69,19 @@ func ForPackage(record repositories.PackageRecord, baseURL url.URL) PackageRespo
 			Annotations: emptyMapIfNil(record.Annotations),
 		},
 		Data: PackageData{
-			Image: record.ImageRef,
+			Image:    record.ImageRef,
+			Checksum: forPackageChecksum(record),
 		},
 	}
 }
+
+func forPackageChecksum(record repositories.PackageRecord) *PackageChecksum {
+	if record.Checksum == "" {
+		return nil
+	}
+
+	return &PackageChecksum{
+		Type:  "sha256",
+		Value: record.Checksum,
+	}
+}
@@ -111,6 +111,17 @@ var _ = Describe("Package", func() {
 		})
 	})
 
+	When("the package has a checksum", func() {
+		BeforeEach(func() {
+			record.Checksum = "the-sha256"
+		})
+
+		It("presents it", func() {
+			Expect(output).To(MatchJSONPath("$.data.checksum.type", "sha256"))
+			Expect(output).To(MatchJSONPath("$.data.checksum.value", "the-sha256"))
+		})
+	})
+
 	When("the package type is docker", func() {
 		BeforeEach(func() {
 			record.Type = "docker"
@@ -15,18 +15,19 @@ const (
 )
 
 type ProcessResponse struct {
-	GUID          string                             `json:"guid"`
-	Type          string                             `json:"type"`
-	Command       string                             `json:"command"`
-	Instances     int32                              `json:"instances"`
-	MemoryMB      int64                              `json:"memory_in_mb"`
-	DiskQuotaMB   int64                              `json:"disk_in_mb"`
-	HealthCheck   ProcessResponseHealthCheck         `json:"health_check"`
-	Relationships map[string]model.ToOneRelationship `json:"relationships"`
-	Metadata      Metadata                           `json:"metadata"`
-	CreatedAt     string                             `json:"created_at"`
-	UpdatedAt     string                             `json:"updated_at"`
-	Links         ProcessLinks                       `json:"links"`
+	GUID                 string                              `json:"guid"`
+	Type                 string                              `json:"type"`
+	Command              string                              `json:"command"`
+	Instances            int32                               `json:"instances"`
+	MemoryMB             int64                               `json:"memory_in_mb"`
+	DiskQuotaMB          int64                               `json:"disk_in_mb"`
+	HealthCheck          ProcessResponseHealthCheck          `json:"health_check"`
+	ReadinessHealthCheck ProcessResponseReadinessHealthCheck `json:"readiness_health_check"`
+	Relationships        map[string]model.ToOneRelationship  `json:"relationships"`
+	Metadata             Metadata                            `json:"metadata"`
+	CreatedAt            string                              `json:"created_at"`
+	UpdatedAt            string                              `json:"updated_at"`
+	Links                ProcessLinks                        `json:"links"`
 }
 
 type ProcessLinks struct {
@@ -98,6 +99,39 @@ type ProcessResponseProcessHealthCheckData struct {
 	Timeout *int32 `json:"timeout"`
 }
 
+type ProcessResponseReadinessHealthCheck struct {
+	Type string                                  `json:"type"`
+	Data ProcessResponseReadinessHealthCheckData `json:"data"`
+}
+
+type ProcessResponseReadinessHealthCheckData struct {
+	Type              string `json:"-"`
+	InvocationTimeout int32  `json:"invocation_timeout"`
+	Interval          int32  `json:"interval"`
+	HTTPEndpoint      string `json:"endpoint"`
+}
+
+func (h ProcessResponseReadinessHealthCheckData) MarshalJSON() ([]byte, error) {
+	data := map[string]any{
+		"invocation_timeout": nilIfZero(h.InvocationTimeout),
+		"interval":           nilIfZero(h.Interval),
+	}
+
+	if h.Type == "http" {
+		data["endpoint"] = h.HTTPEndpoint
+	}
+
+	return json.Marshal(data)
+}
+
+func nilIfZero(value int32) *int32 {
+	if value == 0 {
+		return nil
+	}
+
+	return &value
+}
+
 func ForProcess(responseProcess repositories.ProcessRecord, baseURL url.URL) ProcessResponse {
 	return ProcessResponse{
 		GUID:        responseProcess.GUID,
@@ -115,6 +149,15 @@ func ForProcess(responseProcess repositories.ProcessRecord, baseURL url.URL) Pro
 				HTTPEndpoint:      responseProcess.HealthCheck.Data.HTTPEndpoint,
 			},
 		},
+		ReadinessHealthCheck: ProcessResponseReadinessHealthCheck{
+			Type: responseProcess.ReadinessHealthCheck.Type,
+			Data: ProcessResponseReadinessHealthCheckData{
+				Type:              responseProcess.ReadinessHealthCheck.Type,
+				InvocationTimeout: responseProcess.ReadinessHealthCheck.Data.InvocationTimeoutSeconds,
+				Interval:          responseProcess.ReadinessHealthCheck.Data.IntervalSeconds,
+				HTTPEndpoint:      responseProcess.ReadinessHealthCheck.Data.HTTPEndpoint,
+			},
+		},
 		Relationships: ForRelationships(responseProcess.Relationships()),
 		Metadata: Metadata{
 			Labels:      responseProcess.Labels,
@@ -35,9 +35,10 @@ var _ = Describe("Process Stats", func() {
 				DiskQuota: tools.PtrTo(int64(2048)),
 			},
 			{
-				Type:  "web",
-				Index: 1,
-				State: "RUNNING",
+				Type:    "web",
+				Index:   1,
+				State:   "RUNNING",
+				Details: tools.PtrTo("crash count: 3"),
 				Usage: actions.Usage{
 					Time: tools.PtrTo("t2"),
 					CPU:  tools.PtrTo(501.0),
@@ -89,7 +90,7 @@ var _ = Describe("Process Stats", func() {
 					"disk_quota": 2048,
 					"fds_quota": null,
 					"isolation_segment": null,
-					"details": null,
+					"details": "crash count: 3",
 					"instance_ports": [],
 					"usage": {
 						"time": "t2",
@@ -7,6 +7,7 @@ import (
 
 	"code.cloudfoundry.org/korifi/api/presenter"
 	"code.cloudfoundry.org/korifi/api/repositories"
+	. "code.cloudfoundry.org/korifi/tests/matchers"
 	"code.cloudfoundry.org/korifi/tools"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -40,6 +41,12 @@ var _ = Describe("Process", func() {
 				HealthCheck: repositories.HealthCheck{
 					Type: "port",
 				},
+				ReadinessHealthCheck: repositories.ReadinessHealthCheck{
+					Type: "port",
+					Data: repositories.ReadinessHealthCheckData{
+						IntervalSeconds: 5,
+					},
+				},
 				Labels: map[string]string{
 					"label-key": "label-val",
 				},
@@ -73,6 +80,13 @@ var _ = Describe("Process", func() {
 						"invocation_timeout": null
 					}
 				},
+				"readiness_health_check": {
+					"type": "port",
+					"data": {
+						"invocation_timeout": null,
+						"interval": 5
+					}
+				},
 				"relationships": {
 					"app": {
 						"data": {
@@ -110,5 +124,24 @@ var _ = Describe("Process", func() {
 				}
 			}`))
 		})
+
+		When("the readiness health check type is http", func() {
+			BeforeEach(func() {
+				record.ReadinessHealthCheck = repositories.ReadinessHealthCheck{
+					Type: "http",
+					Data: repositories.ReadinessHealthCheckData{
+						HTTPEndpoint:             "/ready",
+						InvocationTimeoutSeconds: 2,
+					},
+				}
+			})
+
+			It("presents the endpoint", func() {
+				Expect(output).To(MatchJSONPath("$.readiness_health_check.type", Equal("http")))
+				Expect(output).To(MatchJSONPath("$.readiness_health_check.data.endpoint", Equal("/ready")))
+				Expect(output).To(MatchJSONPath("$.readiness_health_check.data.invocation_timeout", BeEquivalentTo(2)))
+				Expect(output).To(MatchJSONPath("$.readiness_health_check.data.interval", BeNil()))
+			})
+		})
 	})
 })
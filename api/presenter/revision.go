@@ -0,0 +1,70 @@
+package presenter
+
+import (
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/model"
+)
+
+type RevisionResponse struct {
+	GUID          string                             `json:"guid"`
+	Version       int                                `json:"version"`
+	Droplet       RevisionDroplet                    `json:"droplet"`
+	Processes     map[string]RevisionProcess         `json:"processes"`
+	Sidecars      []any                              `json:"sidecars"`
+	Description   string                             `json:"description"`
+	Deployable    bool                               `json:"deployable"`
+	Relationships map[string]model.ToOneRelationship `json:"relationships"`
+	Metadata      Metadata                           `json:"metadata"`
+	CreatedAt     string                             `json:"created_at"`
+	UpdatedAt     string                             `json:"updated_at"`
+	Links         map[string]Link                    `json:"links"`
+}
+
+type RevisionDroplet struct {
+	GUID string `json:"guid"`
+}
+
+type RevisionProcess struct {
+	Command string `json:"command"`
+}
+
+func ForRevision(revision repositories.RevisionRecord, baseURL url.URL) RevisionResponse {
+	processes := map[string]RevisionProcess{}
+	for processType, command := range revision.ProcessTypes {
+		processes[processType] = RevisionProcess{Command: command}
+	}
+
+	description := "New droplet deployed."
+	if revision.Version == 1 {
+		description = "Initial revision."
+	}
+
+	return RevisionResponse{
+		GUID:    revision.GUID,
+		Version: revision.Version,
+		Droplet: RevisionDroplet{
+			GUID: revision.DropletGUID,
+		},
+		Processes:     processes,
+		Sidecars:      []any{},
+		Description:   description,
+		Deployable:    true,
+		Relationships: ForRelationships(revision.Relationships()),
+		Metadata: Metadata{
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		CreatedAt: formatTimestamp(&revision.CreatedAt),
+		UpdatedAt: formatTimestamp(revision.UpdatedAt),
+		Links: map[string]Link{
+			"app": {
+				HRef: buildURL(baseURL).appendPath(appsBase, revision.AppGUID).build(),
+			},
+			"droplet": {
+				HRef: buildURL(baseURL).appendPath(dropletsBase, revision.DropletGUID).build(),
+			},
+		},
+	}
+}
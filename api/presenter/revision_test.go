@@ -0,0 +1,96 @@
+package presenter_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	. "code.cloudfoundry.org/korifi/tests/matchers"
+	"code.cloudfoundry.org/korifi/tools"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Revision", func() {
+	var (
+		baseURL *url.URL
+		record  repositories.RevisionRecord
+		output  []byte
+	)
+
+	BeforeEach(func() {
+		var err error
+		baseURL, err = url.Parse("https://api.example.org")
+		Expect(err).NotTo(HaveOccurred())
+
+		record = repositories.RevisionRecord{
+			GUID:        "droplet-guid",
+			Version:     2,
+			AppGUID:     "app-guid",
+			DropletGUID: "droplet-guid",
+			ProcessTypes: map[string]string{
+				"web": "bundle exec rackup",
+			},
+			Deployed:  true,
+			CreatedAt: time.UnixMilli(1000),
+			UpdatedAt: tools.PtrTo(time.UnixMilli(2000)),
+		}
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		output, err = json.Marshal(presenter.ForRevision(record, *baseURL))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("produces the expected JSON", func() {
+		Expect(output).To(MatchJSON(`{
+			"guid": "droplet-guid",
+			"version": 2,
+			"droplet": {
+				"guid": "droplet-guid"
+			},
+			"processes": {
+				"web": {
+					"command": "bundle exec rackup"
+				}
+			},
+			"sidecars": [],
+			"description": "New droplet deployed.",
+			"deployable": true,
+			"relationships": {
+				"app": {
+					"data": {
+						"guid": "app-guid"
+					}
+				}
+			},
+			"metadata": {
+				"labels": {},
+				"annotations": {}
+			},
+			"created_at": "1970-01-01T00:00:01Z",
+			"updated_at": "1970-01-01T00:00:02Z",
+			"links": {
+				"app": {
+					"href": "https://api.example.org/v3/apps/app-guid"
+				},
+				"droplet": {
+					"href": "https://api.example.org/v3/droplets/droplet-guid"
+				}
+			}
+		}`))
+	})
+
+	When("it is the first revision", func() {
+		BeforeEach(func() {
+			record.Version = 1
+		})
+
+		It("describes it as the initial revision", func() {
+			Expect(output).To(MatchJSONPath("$.description", "Initial revision."))
+		})
+	})
+})
@@ -16,7 +16,12 @@ type RootResponse struct {
 	CFOnK8s bool                `json:"cf_on_k8s"`
 }
 
-const V3APIVersion = "3.117.0+cf-k8s"
+const (
+	V3APIVersion = "3.117.0+cf-k8s"
+	// V2APIVersion is the version reported to tools that still call the v2
+	// API. Only /v2/info is served, so the root does not link the v2 API.
+	V2APIVersion = "2.164.0+cf-k8s"
+)
 
 func ForRoot(baseURL url.URL) RootResponse {
 	return RootResponse{
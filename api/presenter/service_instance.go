@@ -62,9 +62,10 @@ func ForServiceInstance(serviceInstanceRecord repositories.ServiceInstanceRecord
 			State:       "succeeded",
 			Type:        lastOperationType,
 		},
-		CreatedAt:     formatTimestamp(&serviceInstanceRecord.CreatedAt),
-		UpdatedAt:     formatTimestamp(serviceInstanceRecord.UpdatedAt),
-		Relationships: ForRelationships(serviceInstanceRecord.Relationships()),
+		SyslogDrainURL: serviceInstanceRecord.SyslogDrainURL,
+		CreatedAt:      formatTimestamp(&serviceInstanceRecord.CreatedAt),
+		UpdatedAt:      formatTimestamp(serviceInstanceRecord.UpdatedAt),
+		Relationships:  ForRelationships(serviceInstanceRecord.Relationships()),
 		Metadata: Metadata{
 			Labels:      emptyMapIfNil(serviceInstanceRecord.Labels),
 			Annotations: emptyMapIfNil(serviceInstanceRecord.Annotations),
@@ -0,0 +1,94 @@
+package presenter
+
+import (
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+const (
+	serviceUsageEventsBase = "/v3/service_usage_events"
+)
+
+type ServiceUsageEventResponse struct {
+	GUID            string                           `json:"guid"`
+	CreatedAt       string                           `json:"created_at"`
+	UpdatedAt       string                           `json:"updated_at"`
+	State           string                           `json:"state"`
+	Space           AppUsageEventResource            `json:"space"`
+	Organization    AppUsageEventOrganization        `json:"organization"`
+	ServiceInstance ServiceUsageEventServiceInstance `json:"service_instance"`
+	ServicePlan     *AppUsageEventResource           `json:"service_plan"`
+	ServiceOffering *AppUsageEventResource           `json:"service_offering"`
+	ServiceBroker   *AppUsageEventResource           `json:"service_broker"`
+	// ServiceBinding is only set for the BINDING_CREATED and BINDING_DELETED
+	// events, which Korifi records in addition to the CF ones
+	ServiceBinding *ServiceUsageEventServiceBinding `json:"service_binding"`
+	Links          ServiceUsageEventLinks           `json:"links"`
+}
+
+type ServiceUsageEventServiceInstance struct {
+	GUID string `json:"guid"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type ServiceUsageEventServiceBinding struct {
+	GUID    string `json:"guid"`
+	AppGUID string `json:"app_guid"`
+}
+
+type ServiceUsageEventLinks struct {
+	Self Link `json:"self"`
+}
+
+func ForServiceUsageEvent(event repositories.ServiceUsageEventRecord, baseURL url.URL) ServiceUsageEventResponse {
+	response := ServiceUsageEventResponse{
+		GUID:      event.GUID,
+		CreatedAt: formatTimestamp(&event.CreatedAt),
+		UpdatedAt: formatTimestamp(&event.CreatedAt),
+		State:     event.State,
+		Space: AppUsageEventResource{
+			GUID: event.SpaceGUID,
+			Name: event.SpaceName,
+		},
+		Organization: AppUsageEventOrganization{
+			GUID: event.OrgGUID,
+		},
+		ServiceInstance: ServiceUsageEventServiceInstance{
+			GUID: event.ServiceInstanceGUID,
+			Name: event.ServiceInstanceName,
+			Type: event.ServiceInstanceType,
+		},
+		Links: ServiceUsageEventLinks{
+			Self: Link{
+				HRef: buildURL(baseURL).appendPath(serviceUsageEventsBase, event.GUID).build(),
+			},
+		},
+	}
+
+	// user-provided service instances have no plan, offering or broker
+	if event.ServicePlanGUID != "" {
+		response.ServicePlan = &AppUsageEventResource{
+			GUID: event.ServicePlanGUID,
+			Name: event.ServicePlanName,
+		}
+		response.ServiceOffering = &AppUsageEventResource{
+			GUID: event.ServiceOfferingGUID,
+			Name: event.ServiceOfferingName,
+		}
+		response.ServiceBroker = &AppUsageEventResource{
+			GUID: event.ServiceBrokerGUID,
+			Name: event.ServiceBrokerName,
+		}
+	}
+
+	if event.ServiceBindingGUID != "" {
+		response.ServiceBinding = &ServiceUsageEventServiceBinding{
+			GUID:    event.ServiceBindingGUID,
+			AppGUID: event.AppGUID,
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,127 @@
+package presenter_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	. "code.cloudfoundry.org/korifi/tests/matchers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ServiceUsageEvent", func() {
+	var (
+		baseURL *url.URL
+		output  []byte
+		record  repositories.ServiceUsageEventRecord
+	)
+
+	BeforeEach(func() {
+		var err error
+		baseURL, err = url.Parse("https://api.example.org")
+		Expect(err).NotTo(HaveOccurred())
+		record = repositories.ServiceUsageEventRecord{
+			GUID:                "service-usage-event-guid",
+			CreatedAt:           time.UnixMilli(1000),
+			State:               "CREATED",
+			ServiceInstanceGUID: "service-instance-guid",
+			ServiceInstanceName: "service-instance-name",
+			ServiceInstanceType: "managed_service_instance",
+			ServicePlanGUID:     "plan-guid",
+			ServicePlanName:     "plan-name",
+			ServiceOfferingGUID: "offering-guid",
+			ServiceOfferingName: "offering-name",
+			ServiceBrokerGUID:   "broker-guid",
+			ServiceBrokerName:   "broker-name",
+			SpaceGUID:           "space-guid",
+			SpaceName:           "space-name",
+			OrgGUID:             "org-guid",
+		}
+	})
+
+	JustBeforeEach(func() {
+		response := presenter.ForServiceUsageEvent(record, *baseURL)
+		var err error
+		output, err = json.Marshal(response)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("produces expected service usage event json", func() {
+		Expect(output).To(MatchJSON(`{
+			"guid": "service-usage-event-guid",
+			"created_at": "1970-01-01T00:00:01Z",
+			"updated_at": "1970-01-01T00:00:01Z",
+			"state": "CREATED",
+			"space": {
+				"guid": "space-guid",
+				"name": "space-name"
+			},
+			"organization": {
+				"guid": "org-guid"
+			},
+			"service_instance": {
+				"guid": "service-instance-guid",
+				"name": "service-instance-name",
+				"type": "managed_service_instance"
+			},
+			"service_plan": {
+				"guid": "plan-guid",
+				"name": "plan-name"
+			},
+			"service_offering": {
+				"guid": "offering-guid",
+				"name": "offering-name"
+			},
+			"service_broker": {
+				"guid": "broker-guid",
+				"name": "broker-name"
+			},
+			"service_binding": null,
+			"links": {
+				"self": {
+					"href": "https://api.example.org/v3/service_usage_events/service-usage-event-guid"
+				}
+			}
+		}`))
+	})
+
+	When("the service instance is user-provided", func() {
+		BeforeEach(func() {
+			record.ServiceInstanceType = "user_provided_service_instance"
+			record.ServicePlanGUID = ""
+			record.ServicePlanName = ""
+			record.ServiceOfferingGUID = ""
+			record.ServiceOfferingName = ""
+			record.ServiceBrokerGUID = ""
+			record.ServiceBrokerName = ""
+		})
+
+		It("presents no plan, offering and broker", func() {
+			Expect(output).To(SatisfyAll(
+				MatchJSONPath("$.service_plan", BeNil()),
+				MatchJSONPath("$.service_offering", BeNil()),
+				MatchJSONPath("$.service_broker", BeNil()),
+			))
+		})
+	})
+
+	When("the event is a binding event", func() {
+		BeforeEach(func() {
+			record.State = "BINDING_CREATED"
+			record.ServiceBindingGUID = "binding-guid"
+			record.AppGUID = "app-guid"
+		})
+
+		It("presents the binding", func() {
+			Expect(output).To(SatisfyAll(
+				MatchJSONPath("$.state", "BINDING_CREATED"),
+				MatchJSONPath("$.service_binding.guid", "binding-guid"),
+				MatchJSONPath("$.service_binding.app_guid", "app-guid"),
+			))
+		})
+	})
+})
@@ -0,0 +1,44 @@
+package presenter
+
+import (
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/model"
+)
+
+type SidecarResponse struct {
+	GUID          string                             `json:"guid"`
+	Name          string                             `json:"name"`
+	Command       string                             `json:"command"`
+	ProcessTypes  []string                           `json:"process_types"`
+	MemoryMB      *int64                             `json:"memory_in_mb"`
+	Origin        string                             `json:"origin"`
+	Relationships map[string]model.ToOneRelationship `json:"relationships"`
+	CreatedAt     string                             `json:"created_at"`
+	UpdatedAt     string                             `json:"updated_at"`
+}
+
+// ForProcessSidecarList presents the sidecars of a process. Sidecars are
+// stored on their processes, so they are identified by the process GUID and
+// their name.
+func ForProcessSidecarList(process repositories.ProcessRecord, baseURL, requestURL url.URL) ListResponse[SidecarResponse] {
+	return ForList(func(sidecar repositories.Sidecar, _ url.URL) SidecarResponse {
+		var memoryMB *int64
+		if sidecar.MemoryMB > 0 {
+			memoryMB = &sidecar.MemoryMB
+		}
+
+		return SidecarResponse{
+			GUID:          process.GUID + "-" + sidecar.Name,
+			Name:          sidecar.Name,
+			Command:       sidecar.Command,
+			ProcessTypes:  []string{process.Type},
+			MemoryMB:      memoryMB,
+			Origin:        "user",
+			Relationships: ForRelationships(process.Relationships()),
+			CreatedAt:     formatTimestamp(&process.CreatedAt),
+			UpdatedAt:     formatTimestamp(process.UpdatedAt),
+		}
+	}, process.Sidecars, baseURL, requestURL)
+}
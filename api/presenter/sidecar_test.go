@@ -0,0 +1,95 @@
+package presenter_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/tools"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sidecar", func() {
+	var (
+		baseURL *url.URL
+		output  []byte
+	)
+
+	BeforeEach(func() {
+		var err error
+		baseURL, err = url.Parse("https://api.example.org")
+		Expect(err).NotTo(HaveOccurred())
+
+		record := repositories.ProcessRecord{
+			GUID:    "process-guid",
+			AppGUID: "app-guid",
+			Type:    "web",
+			Sidecars: []repositories.Sidecar{
+				{Name: "my-sidecar", Command: "run-sidecar", MemoryMB: 256},
+				{Name: "other-sidecar", Command: "run-other-sidecar"},
+			},
+			CreatedAt: time.UnixMilli(1000),
+			UpdatedAt: tools.PtrTo(time.UnixMilli(2000)),
+		}
+
+		response := presenter.ForProcessSidecarList(record, *baseURL, *baseURL.JoinPath("/v3/processes/process-guid/sidecars"))
+		output, err = json.Marshal(response)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("produces the expected JSON", func() {
+		Expect(output).To(MatchJSON(`{
+			"pagination": {
+				"total_results": 2,
+				"total_pages": 1,
+				"first": {
+					"href": "https://api.example.org/v3/processes/process-guid/sidecars"
+				},
+				"last": {
+					"href": "https://api.example.org/v3/processes/process-guid/sidecars"
+				},
+				"next": null,
+				"previous": null
+			},
+			"resources": [
+				{
+					"guid": "process-guid-my-sidecar",
+					"name": "my-sidecar",
+					"command": "run-sidecar",
+					"process_types": ["web"],
+					"memory_in_mb": 256,
+					"origin": "user",
+					"relationships": {
+						"app": {
+							"data": {
+								"guid": "app-guid"
+							}
+						}
+					},
+					"created_at": "1970-01-01T00:00:01Z",
+					"updated_at": "1970-01-01T00:00:02Z"
+				},
+				{
+					"guid": "process-guid-other-sidecar",
+					"name": "other-sidecar",
+					"command": "run-other-sidecar",
+					"process_types": ["web"],
+					"memory_in_mb": null,
+					"origin": "user",
+					"relationships": {
+						"app": {
+							"data": {
+								"guid": "app-guid"
+							}
+						}
+					},
+					"created_at": "1970-01-01T00:00:01Z",
+					"updated_at": "1970-01-01T00:00:02Z"
+				}
+			]
+		}`))
+	})
+})
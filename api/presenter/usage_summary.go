@@ -0,0 +1,54 @@
+package presenter
+
+import (
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+type UsageSummaryResponse struct {
+	UsageSummary UsageSummary      `json:"usage_summary"`
+	Links        UsageSummaryLinks `json:"links"`
+}
+
+type UsageSummary struct {
+	StartedInstances int32 `json:"started_instances"`
+	MemoryInMB       int64 `json:"memory_in_mb"`
+}
+
+type UsageSummaryLinks struct {
+	Self         Link  `json:"self"`
+	Organization *Link `json:"organization,omitempty"`
+}
+
+func ForOrgUsageSummary(usageSummary repositories.UsageSummaryRecord, orgGUID string, baseURL url.URL) UsageSummaryResponse {
+	return UsageSummaryResponse{
+		UsageSummary: forUsageSummary(usageSummary),
+		Links: UsageSummaryLinks{
+			Self: Link{
+				HRef: buildURL(baseURL).appendPath(orgsBase, orgGUID, "usage_summary").build(),
+			},
+			Organization: &Link{
+				HRef: buildURL(baseURL).appendPath(orgsBase, orgGUID).build(),
+			},
+		},
+	}
+}
+
+func ForUsageSummary(usageSummary repositories.UsageSummaryRecord, baseURL url.URL) UsageSummaryResponse {
+	return UsageSummaryResponse{
+		UsageSummary: forUsageSummary(usageSummary),
+		Links: UsageSummaryLinks{
+			Self: Link{
+				HRef: buildURL(baseURL).appendPath("v3/info/usage_summary").build(),
+			},
+		},
+	}
+}
+
+func forUsageSummary(usageSummary repositories.UsageSummaryRecord) UsageSummary {
+	return UsageSummary{
+		StartedInstances: usageSummary.StartedInstances,
+		MemoryInMB:       usageSummary.MemoryMB,
+	}
+}
@@ -0,0 +1,68 @@
+package presenter_test
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UsageSummary", func() {
+	var (
+		baseURL *url.URL
+		record  repositories.UsageSummaryRecord
+	)
+
+	BeforeEach(func() {
+		var err error
+		baseURL, err = url.Parse("https://api.example.org")
+		Expect(err).NotTo(HaveOccurred())
+		record = repositories.UsageSummaryRecord{
+			StartedInstances: 3,
+			MemoryMB:         1536,
+		}
+	})
+
+	Describe("ForOrgUsageSummary", func() {
+		It("produces expected org usage summary json", func() {
+			output, err := json.Marshal(presenter.ForOrgUsageSummary(record, "org-guid", *baseURL))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(MatchJSON(`{
+				"usage_summary": {
+					"started_instances": 3,
+					"memory_in_mb": 1536
+				},
+				"links": {
+					"self": {
+						"href": "https://api.example.org/v3/organizations/org-guid/usage_summary"
+					},
+					"organization": {
+						"href": "https://api.example.org/v3/organizations/org-guid"
+					}
+				}
+			}`))
+		})
+	})
+
+	Describe("ForUsageSummary", func() {
+		It("produces expected platform usage summary json", func() {
+			output, err := json.Marshal(presenter.ForUsageSummary(record, *baseURL))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(MatchJSON(`{
+				"usage_summary": {
+					"started_instances": 3,
+					"memory_in_mb": 1536
+				},
+				"links": {
+					"self": {
+						"href": "https://api.example.org/v3/info/usage_summary"
+					}
+				}
+			}`))
+		})
+	})
+})
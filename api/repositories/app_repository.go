@@ -47,6 +47,7 @@ type AppRepo struct {
 	userClientFactory    authorization.UserK8sClientFactory
 	namespacePermissions *authorization.NamespacePermissions
 	appAwaiter           Awaiter[*korifiv1alpha1.CFApp]
+	rootNamespace        string
 }
 
 func NewAppRepo(
@@ -54,12 +55,14 @@ func NewAppRepo(
 	userClientFactory authorization.UserK8sClientFactory,
 	authPerms *authorization.NamespacePermissions,
 	appAwaiter Awaiter[*korifiv1alpha1.CFApp],
+	rootNamespace string,
 ) *AppRepo {
 	return &AppRepo{
 		namespaceRetriever:   namespaceRetriever,
 		userClientFactory:    userClientFactory,
 		namespacePermissions: authPerms,
 		appAwaiter:           appAwaiter,
+		rootNamespace:        rootNamespace,
 	}
 }
 
@@ -79,6 +82,7 @@ type AppRecord struct {
 	DeletedAt             *time.Time
 	IsStaged              bool
 	envSecretName         string
+	stagingEnvSecretName  string
 	vcapServiceSecretName string
 	vcapAppSecretName     string
 }
@@ -126,6 +130,9 @@ type AppEnvRecord struct {
 	AppGUID              string
 	SpaceGUID            string
 	EnvironmentVariables map[string]string
+	StagingEnvVariables  map[string]string
+	RunningEnvVarGroup   map[string]string
+	StagingEnvVarGroup   map[string]string
 	SystemEnv            map[string]interface{}
 	AppEnv               map[string]interface{}
 }
@@ -279,7 +286,7 @@ func (f *AppRepo) PatchApp(ctx context.Context, authInfo authorization.Info, app
 		appPatchMessage.Apply(cfApp)
 	})
 	if err != nil {
-		return AppRecord{}, apierrors.FromK8sError(err, AppResourceType)
+		return AppRecord{}, f.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, appPatchMessage.SpaceGUID, apierrors.FromK8sError(err, AppResourceType))
 	}
 
 	envSecret := &corev1.Secret{
@@ -319,19 +326,13 @@ func (f *AppRepo) ListApps(ctx context.Context, authInfo authorization.Info, mes
 	}
 
 	nsList := authorisedSpaceNamespacesIter.Filter(message.matchesNamespace).Collect()
-	var apps []korifiv1alpha1.CFApp
-	for _, ns := range nsList {
+	apps, err := listInNamespaces(ctx, nsList, func(ctx context.Context, ns string) ([]korifiv1alpha1.CFApp, error) {
 		appList := &korifiv1alpha1.CFAppList{}
 		err := userClient.List(ctx, appList, client.InNamespace(ns), &client.ListOptions{LabelSelector: labelSelector})
-
-		if k8serrors.IsForbidden(err) {
-			continue
-		}
-		if err != nil {
-			return []AppRecord{}, fmt.Errorf("failed to list apps in namespace %s: %w", ns, apierrors.FromK8sError(err, AppResourceType))
-		}
-
-		apps = append(apps, appList.Items...)
+		return appList.Items, err
+	})
+	if err != nil {
+		return []AppRecord{}, fmt.Errorf("failed to list apps: %w", apierrors.FromK8sError(err, AppResourceType))
 	}
 
 	// By default sort it by App.DisplayName
@@ -359,24 +360,75 @@ func (f *AppRepo) PatchAppEnvVars(ctx context.Context, authInfo authorization.In
 	}
 
 	err = PatchResource(ctx, userClient, &secretObj, func() {
-		if secretObj.Data == nil {
-			secretObj.Data = map[string][]byte{}
-		}
-		for k, v := range message.EnvironmentVariables {
-			if v == nil {
-				delete(secretObj.Data, k)
-			} else {
-				secretObj.Data[k] = []byte(*v)
-			}
-		}
+		secretObj.Data = patchEnvVarsData(secretObj.Data, message.EnvironmentVariables)
 	})
 	if err != nil {
-		return AppEnvVarsRecord{}, apierrors.FromK8sError(err, AppEnvResourceType)
+		return AppEnvVarsRecord{}, f.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, message.SpaceGUID, apierrors.FromK8sError(err, AppEnvResourceType))
 	}
 
 	return appEnvVarsSecretToRecord(secretObj), nil
 }
 
+// PatchAppStagingEnvVars patches the staging-only env vars of the app. They
+// are stored in their own secret, which is created on first use, and are only
+// set on the app builds.
+func (f *AppRepo) PatchAppStagingEnvVars(ctx context.Context, authInfo authorization.Info, message PatchAppEnvVarsMessage) (AppEnvVarsRecord, error) {
+	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return AppEnvVarsRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfApp := &korifiv1alpha1.CFApp{}
+	err = userClient.Get(ctx, types.NamespacedName{Namespace: message.SpaceGUID, Name: message.AppGUID}, cfApp)
+	if err != nil {
+		return AppEnvVarsRecord{}, apierrors.ForbiddenAsNotFound(apierrors.FromK8sError(err, AppResourceType))
+	}
+
+	secretObj := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GenerateStagingEnvSecretName(message.AppGUID),
+			Namespace: message.SpaceGUID,
+		},
+	}
+	err = userClient.Get(ctx, client.ObjectKeyFromObject(secretObj), secretObj)
+	if client.IgnoreNotFound(err) != nil {
+		return AppEnvVarsRecord{}, apierrors.FromK8sError(err, AppEnvResourceType)
+	}
+
+	if err != nil {
+		secretObj.Labels = map[string]string{CFAppGUIDLabel: cfApp.Name}
+		secretObj.Data = patchEnvVarsData(nil, message.EnvironmentVariables)
+		err = controllerutil.SetOwnerReference(cfApp, secretObj, scheme.Scheme)
+		if err != nil {
+			return AppEnvVarsRecord{}, fmt.Errorf("failed to set ownership from the app to the staging env secret: %w", err)
+		}
+		err = userClient.Create(ctx, secretObj)
+	} else {
+		err = PatchResource(ctx, userClient, secretObj, func() {
+			secretObj.Data = patchEnvVarsData(secretObj.Data, message.EnvironmentVariables)
+		})
+	}
+	if err != nil {
+		return AppEnvVarsRecord{}, apierrors.FromK8sError(err, AppEnvResourceType)
+	}
+
+	if cfApp.Spec.StagingEnvSecretName != secretObj.Name {
+		err = PatchResource(ctx, userClient, cfApp, func() {
+			cfApp.Spec.StagingEnvSecretName = secretObj.Name
+		})
+		if err != nil {
+			return AppEnvVarsRecord{}, apierrors.FromK8sError(err, AppResourceType)
+		}
+	}
+
+	return AppEnvVarsRecord{
+		Name:                 secretObj.Name,
+		AppGUID:              message.AppGUID,
+		SpaceGUID:            message.SpaceGUID,
+		EnvironmentVariables: convertByteSliceValuesToStrings(secretObj.Data),
+	}, nil
+}
+
 func (f *AppRepo) SetCurrentDroplet(ctx context.Context, authInfo authorization.Info, message SetCurrentDropletMessage) (CurrentDropletRecord, error) {
 	userClient, err := f.userClientFactory.BuildClient(authInfo)
 	if err != nil {
@@ -394,7 +446,8 @@ func (f *AppRepo) SetCurrentDroplet(ctx context.Context, authInfo authorization.
 		cfApp.Spec.CurrentDropletRef = corev1.LocalObjectReference{Name: message.DropletGUID}
 	})
 	if err != nil {
-		return CurrentDropletRecord{}, fmt.Errorf("failed to set app droplet: %w", apierrors.FromK8sError(err, AppResourceType))
+		return CurrentDropletRecord{}, fmt.Errorf("failed to set app droplet: %w",
+			f.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, message.SpaceGUID, apierrors.FromK8sError(err, AppResourceType)))
 	}
 
 	_, err = f.appAwaiter.AwaitCondition(ctx, userClient, cfApp, korifiv1alpha1.StatusConditionReady)
@@ -425,7 +478,8 @@ func (f *AppRepo) SetAppDesiredState(ctx context.Context, authInfo authorization
 		cfApp.Spec.DesiredState = korifiv1alpha1.AppState(message.DesiredState)
 	})
 	if err != nil {
-		return AppRecord{}, fmt.Errorf("failed to set app desired state: %w", apierrors.FromK8sError(err, AppResourceType))
+		return AppRecord{}, fmt.Errorf("failed to set app desired state: %w",
+			f.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, message.SpaceGUID, apierrors.FromK8sError(err, AppResourceType)))
 	}
 
 	_, err = f.appAwaiter.AwaitState(ctx, userClient, cfApp, func(a *korifiv1alpha1.CFApp) error {
@@ -459,16 +513,16 @@ func (f *AppRepo) DeleteApp(ctx context.Context, authInfo authorization.Info, me
 		return fmt.Errorf("failed to build user client: %w", err)
 	}
 
-	return apierrors.FromK8sError(
+	return f.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, message.SpaceGUID, apierrors.FromK8sError(
 		userClient.Delete(ctx, cfApp, client.PropagationPolicy(metav1.DeletePropagationForeground)),
 		AppResourceType,
-	)
+	))
 }
 
 func (f *AppRepo) GetAppEnv(ctx context.Context, authInfo authorization.Info, appGUID string) (AppEnvRecord, error) {
 	app, err := f.GetApp(ctx, authInfo, appGUID)
 	if err != nil {
-		return AppEnvRecord{}, err
+		return AppEnvRecord{}, apierrors.ForbiddenAsNotFound(err)
 	}
 
 	userClient, err := f.userClientFactory.BuildClient(authInfo)
@@ -489,6 +543,29 @@ func (f *AppRepo) GetAppEnv(ctx context.Context, authInfo authorization.Info, ap
 		appEnvVarMap = convertByteSliceValuesToStrings(appEnvVarSecret.Data)
 	}
 
+	stagingEnvVarMap := map[string]string{}
+	if app.stagingEnvSecretName != "" {
+		stagingEnvVarSecret := new(corev1.Secret)
+		err = userClient.Get(ctx, types.NamespacedName{Name: app.stagingEnvSecretName, Namespace: app.SpaceGUID}, stagingEnvVarSecret)
+		if err != nil {
+			return AppEnvRecord{}, fmt.Errorf("error finding staging environment variable Secret %q for App %q: %w",
+				app.stagingEnvSecretName,
+				app.GUID,
+				apierrors.FromK8sError(err, AppEnvResourceType))
+		}
+		stagingEnvVarMap = convertByteSliceValuesToStrings(stagingEnvVarSecret.Data)
+	}
+
+	runningEnvVarGroup, err := f.getEnvVarGroup(ctx, userClient, korifiv1alpha1.RunningEnvVarGroupName)
+	if err != nil {
+		return AppEnvRecord{}, err
+	}
+
+	stagingEnvVarGroup, err := f.getEnvVarGroup(ctx, userClient, korifiv1alpha1.StagingEnvVarGroupName)
+	if err != nil {
+		return AppEnvRecord{}, err
+	}
+
 	systemEnvMap, err := getSystemEnv(ctx, userClient, app)
 	if err != nil {
 		return AppEnvRecord{}, err
@@ -503,6 +580,9 @@ func (f *AppRepo) GetAppEnv(ctx context.Context, authInfo authorization.Info, ap
 		AppGUID:              appGUID,
 		SpaceGUID:            app.SpaceGUID,
 		EnvironmentVariables: appEnvVarMap,
+		StagingEnvVariables:  stagingEnvVarMap,
+		RunningEnvVarGroup:   runningEnvVarGroup,
+		StagingEnvVarGroup:   stagingEnvVarGroup,
 		SystemEnv:            systemEnvMap,
 		AppEnv:               appEnvMap,
 	}
@@ -518,6 +598,23 @@ func (f *AppRepo) GetDeletedAt(ctx context.Context, authInfo authorization.Info,
 	return app.DeletedAt, nil
 }
 
+func (f *AppRepo) getEnvVarGroup(ctx context.Context, userClient client.Client, name string) (map[string]string, error) {
+	envVarGroup := new(corev1.ConfigMap)
+	err := userClient.Get(ctx, types.NamespacedName{Name: name, Namespace: f.rootNamespace}, envVarGroup)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("error finding environment variable group %q: %w", name, apierrors.FromK8sError(err, AppEnvResourceType))
+	}
+
+	if envVarGroup.Data == nil {
+		return map[string]string{}, nil
+	}
+
+	return envVarGroup.Data, nil
+}
+
 func getSystemEnv(ctx context.Context, userClient client.Client, app AppRecord) (map[string]any, error) {
 	systemEnvMap := map[string]any{}
 	if app.vcapServiceSecretName != "" {
@@ -579,6 +676,25 @@ func GenerateEnvSecretName(appGUID string) string {
 	return appGUID + "-env"
 }
 
+func GenerateStagingEnvSecretName(appGUID string) string {
+	return appGUID + "-staging-env"
+}
+
+func patchEnvVarsData(data map[string][]byte, envVars map[string]*string) map[string][]byte {
+	if data == nil {
+		data = map[string][]byte{}
+	}
+	for k, v := range envVars {
+		if v == nil {
+			delete(data, k)
+		} else {
+			data[k] = []byte(*v)
+		}
+	}
+
+	return data
+}
+
 func (m *CreateAppMessage) toCFApp() korifiv1alpha1.CFApp {
 	guid := uuid.NewString()
 	return korifiv1alpha1.CFApp{
@@ -648,6 +764,7 @@ func cfAppToAppRecord(cfApp korifiv1alpha1.CFApp) AppRecord {
 		DeletedAt:             golangTime(cfApp.DeletionTimestamp),
 		IsStaged:              meta.IsStatusConditionTrue(cfApp.Status.Conditions, korifiv1alpha1.StatusConditionReady),
 		envSecretName:         cfApp.Spec.EnvSecretName,
+		stagingEnvSecretName:  cfApp.Spec.StagingEnvSecretName,
 		vcapServiceSecretName: cfApp.Status.VCAPServicesSecretName,
 		vcapAppSecretName:     cfApp.Status.VCAPApplicationSecretName,
 	}
@@ -55,7 +55,7 @@ var _ = Describe("AppRepository", func() {
 			korifiv1alpha1.CFAppList,
 			*korifiv1alpha1.CFAppList,
 		]{}
-		appRepo = repositories.NewAppRepo(namespaceRetriever, userClientFactory, nsPerms, appAwaiter)
+		appRepo = repositories.NewAppRepo(namespaceRetriever, userClientFactory, nsPerms, appAwaiter, rootNamespace)
 
 		cfOrg = createOrgWithCleanup(ctx, prefixedGUID("org"))
 		cfSpace = createSpaceWithCleanup(ctx, cfOrg.Name, prefixedGUID("space1"))
@@ -707,8 +707,8 @@ var _ = Describe("AppRepository", func() {
 		})
 
 		When("the user is not authorized in the space", func() {
-			It("returns a forbidden error", func() {
-				Expect(patchErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			It("returns a not found error", func() {
+				Expect(patchErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
 			})
 		})
 
@@ -801,12 +801,102 @@ var _ = Describe("AppRepository", func() {
 		})
 
 		When("the user is not authorized", func() {
-			It("return a forbidden error", func() {
+			It("returns a not found error", func() {
+				Expect(patchErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+			})
+		})
+
+		When("the user can see the app but not modify it", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceManagerRole.Name, cfSpace.Name)
+			})
+
+			It("returns a forbidden error", func() {
 				Expect(patchErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
 			})
 		})
 	})
 
+	Describe("PatchAppStagingEnvVars", func() {
+		var (
+			secretRecord repositories.AppEnvVarsRecord
+			patchErr     error
+		)
+
+		JustBeforeEach(func() {
+			secretRecord, patchErr = appRepo.PatchAppStagingEnvVars(ctx, authInfo, repositories.PatchAppEnvVarsMessage{
+				AppGUID:   cfApp.Name,
+				SpaceGUID: cfSpace.Name,
+				EnvironmentVariables: map[string]*string{
+					"KEY0": tools.PtrTo("VAL0"),
+				},
+			})
+		})
+
+		When("the user is authorized", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceDeveloperRole.Name, cfSpace.Name)
+			})
+
+			It("returns the staging env vars record", func() {
+				Expect(patchErr).NotTo(HaveOccurred())
+				Expect(secretRecord.Name).To(Equal(repositories.GenerateStagingEnvSecretName(cfApp.Name)))
+				Expect(secretRecord.AppGUID).To(Equal(cfApp.Name))
+				Expect(secretRecord.SpaceGUID).To(Equal(cfSpace.Name))
+				Expect(secretRecord.EnvironmentVariables).To(Equal(map[string]string{"KEY0": "VAL0"}))
+			})
+
+			It("creates the staging env secret, owned by the app", func() {
+				var secret corev1.Secret
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: cfSpace.Name, Name: cfApp.Name + "-staging-env"}, &secret)).To(Succeed())
+				Expect(asMapOfStrings(secret.Data)).To(Equal(map[string]string{"KEY0": "VAL0"}))
+				Expect(secret.OwnerReferences).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+					"Kind": Equal("CFApp"),
+					"Name": Equal(cfApp.Name),
+				})))
+			})
+
+			It("sets the staging env secret on the app", func() {
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
+				Expect(cfApp.Spec.StagingEnvSecretName).To(Equal(cfApp.Name + "-staging-env"))
+			})
+
+			It("does not change the app env secret", func() {
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
+				Expect(cfApp.Spec.EnvSecretName).NotTo(Equal(cfApp.Spec.StagingEnvSecretName))
+			})
+
+			When("the staging env secret already exists", func() {
+				BeforeEach(func() {
+					Expect(k8sClient.Create(ctx, &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      repositories.GenerateStagingEnvSecretName(cfApp.Name),
+							Namespace: cfSpace.Name,
+						},
+						StringData: map[string]string{
+							"KEY0": "original-value",
+							"KEY1": "VAL1",
+						},
+					})).To(Succeed())
+				})
+
+				It("patches it", func() {
+					Expect(patchErr).NotTo(HaveOccurred())
+					Expect(secretRecord.EnvironmentVariables).To(Equal(map[string]string{
+						"KEY0": "VAL0",
+						"KEY1": "VAL1",
+					}))
+				})
+			})
+		})
+
+		When("the user is not authorized", func() {
+			It("returns a not found error", func() {
+				Expect(patchErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+			})
+		})
+	})
+
 	Describe("SetCurrentDroplet", func() {
 		var (
 			dropletGUID string
@@ -880,8 +970,8 @@ var _ = Describe("AppRepository", func() {
 		})
 
 		When("the user is not authorized", func() {
-			It("errors", func() {
-				Expect(setDropletErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			It("returns a not found error", func() {
+				Expect(setDropletErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
 			})
 		})
 	})
@@ -1022,8 +1112,8 @@ var _ = Describe("AppRepository", func() {
 		})
 
 		When("not allowed to set the application state", func() {
-			It("returns a forbidden error", func() {
-				Expect(returnedErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			It("returns a not found error", func() {
+				Expect(returnedErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
 			})
 		})
 	})
@@ -1036,7 +1126,6 @@ var _ = Describe("AppRepository", func() {
 
 		BeforeEach(func() {
 			appGUID = cfApp.Name
-			createRoleBinding(ctx, userName, spaceDeveloperRole.Name, cfSpace.Name)
 		})
 
 		JustBeforeEach(func() {
@@ -1046,19 +1135,41 @@ var _ = Describe("AppRepository", func() {
 			})
 		})
 
-		It("deletes the CFApp resource", func() {
-			Expect(deleteAppErr).NotTo(HaveOccurred())
-			app, err := appRepo.GetApp(ctx, authInfo, appGUID)
-			Expect(err).NotTo(HaveOccurred())
-			Expect(app.DeletedAt).To(PointTo(BeTemporally("~", time.Now(), 5*time.Second)))
+		When("the user is a space developer", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceDeveloperRole.Name, cfSpace.Name)
+			})
+
+			It("deletes the CFApp resource", func() {
+				Expect(deleteAppErr).NotTo(HaveOccurred())
+				app, err := appRepo.GetApp(ctx, authInfo, appGUID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(app.DeletedAt).To(PointTo(BeTemporally("~", time.Now(), 5*time.Second)))
+			})
+
+			When("the app doesn't exist", func() {
+				BeforeEach(func() {
+					appGUID = "no-such-app"
+				})
+
+				It("errors", func() {
+					Expect(deleteAppErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+				})
+			})
 		})
 
-		When("the app doesn't exist", func() {
+		When("the user can see the app but not delete it", func() {
 			BeforeEach(func() {
-				appGUID = "no-such-app"
+				createRoleBinding(ctx, userName, spaceManagerRole.Name, cfSpace.Name)
 			})
 
-			It("errors", func() {
+			It("returns a forbidden error", func() {
+				Expect(deleteAppErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			})
+		})
+
+		When("the user cannot see the app", func() {
+			It("returns a not found error", func() {
 				Expect(deleteAppErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
 			})
 		})
@@ -1115,6 +1226,58 @@ var _ = Describe("AppRepository", func() {
 				Expect(appEnvRecord.AppEnv).To(BeEmpty())
 			})
 
+			When("the app has staging env vars", func() {
+				BeforeEach(func() {
+					Expect(k8sClient.Create(ctx, &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "the-staging-env-secret",
+							Namespace: cfSpace.Name,
+						},
+						StringData: map[string]string{"MAVEN_MIRROR": "https://maven.example.com"},
+					})).To(Succeed())
+					Expect(k8s.PatchResource(ctx, k8sClient, cfApp, func() {
+						cfApp.Spec.StagingEnvSecretName = "the-staging-env-secret"
+					})).To(Succeed())
+				})
+
+				It("returns them separately from the env vars", func() {
+					Expect(getAppEnvErr).NotTo(HaveOccurred())
+					Expect(appEnvRecord.EnvironmentVariables).To(Equal(envVars))
+					Expect(appEnvRecord.StagingEnvVariables).To(Equal(map[string]string{"MAVEN_MIRROR": "https://maven.example.com"}))
+				})
+			})
+
+			It("returns empty env var groups", func() {
+				Expect(getAppEnvErr).NotTo(HaveOccurred())
+				Expect(appEnvRecord.RunningEnvVarGroup).To(BeEmpty())
+				Expect(appEnvRecord.StagingEnvVarGroup).To(BeEmpty())
+			})
+
+			When("the env var groups are set", func() {
+				BeforeEach(func() {
+					Expect(k8sClient.Create(ctx, &corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      korifiv1alpha1.RunningEnvVarGroupName,
+							Namespace: rootNamespace,
+						},
+						Data: map[string]string{"RUNNING": "running-value"},
+					})).To(Succeed())
+					Expect(k8sClient.Create(ctx, &corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      korifiv1alpha1.StagingEnvVarGroupName,
+							Namespace: rootNamespace,
+						},
+						Data: map[string]string{"STAGING": "staging-value"},
+					})).To(Succeed())
+				})
+
+				It("returns them", func() {
+					Expect(getAppEnvErr).NotTo(HaveOccurred())
+					Expect(appEnvRecord.RunningEnvVarGroup).To(Equal(map[string]string{"RUNNING": "running-value"}))
+					Expect(appEnvRecord.StagingEnvVarGroup).To(Equal(map[string]string{"STAGING": "staging-value"}))
+				})
+			})
+
 			When("the app has a service-binding secret", func() {
 				var (
 					vcapServiceSecretDataByte map[string][]byte
@@ -1282,7 +1445,21 @@ var _ = Describe("AppRepository", func() {
 			})
 		})
 
+		When("the user cannot see the app", func() {
+			It("returns a not found error", func() {
+				Expect(getAppEnvErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+			})
+		})
+
 		When("the user doesn't have permission to get secrets in the space", func() {
+			BeforeEach(func() {
+				Expect(k8s.PatchResource(ctx, k8sClient, cfApp, func() {
+					cfApp.Spec.EnvSecretName = secretName
+				})).To(Succeed())
+
+				createRoleBinding(ctx, userName, spaceManagerRole.Name, cfSpace.Name)
+			})
+
 			It("errors", func() {
 				Expect(getAppEnvErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
 			})
@@ -0,0 +1,219 @@
+package repositories
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	AppUsageEventResourceType = "App Usage Event"
+
+	// UsageEventsSettleWindow is how long usage events are held back before
+	// being listed. The controllers stamp the events right before creating
+	// them, so once the window has passed no event with an earlier timestamp
+	// can show up anymore and consumers polling after the last event they
+	// have seen do not miss any.
+	UsageEventsSettleWindow = 30 * time.Second
+)
+
+type AppUsageEventRecord struct {
+	GUID                  string
+	CreatedAt             time.Time
+	State                 string
+	PreviousState         string
+	AppGUID               string
+	AppName               string
+	ProcessGUID           string
+	ProcessType           string
+	SpaceGUID             string
+	SpaceName             string
+	OrgGUID               string
+	InstanceCount         int32
+	PreviousInstanceCount int32
+	MemoryMB              int64
+	PreviousMemoryMB      int64
+}
+
+type ListAppUsageEventsMessage struct {
+	GUIDs []string
+	// AfterGUID only lists the events recorded after the event with this guid,
+	// so that consumers can poll for new events
+	AfterGUID string
+}
+
+// AppUsageEventRepo reads the app usage events the controllers record in the
+// root namespace. Only users allowed to list them there, i.e. admins, can
+// read them.
+type AppUsageEventRepo struct {
+	userClientFactory    authorization.UserK8sClientFactory
+	namespacePermissions *authorization.NamespacePermissions
+	rootNamespace        string
+	settleWindow         time.Duration
+}
+
+func NewAppUsageEventRepo(
+	userClientFactory authorization.UserK8sClientFactory,
+	namespacePermissions *authorization.NamespacePermissions,
+	rootNamespace string,
+	settleWindow time.Duration,
+) *AppUsageEventRepo {
+	return &AppUsageEventRepo{
+		userClientFactory:    userClientFactory,
+		namespacePermissions: namespacePermissions,
+		rootNamespace:        rootNamespace,
+		settleWindow:         settleWindow,
+	}
+}
+
+func (r *AppUsageEventRepo) ListAppUsageEvents(ctx context.Context, authInfo authorization.Info, message ListAppUsageEventsMessage) ([]AppUsageEventRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	eventList := &korifiv1alpha1.CFAppUsageEventList{}
+	err = userClient.List(ctx, eventList, client.InNamespace(r.rootNamespace))
+	if err != nil {
+		return nil, apierrors.FromK8sError(err, AppUsageEventResourceType)
+	}
+
+	events := eventList.Items
+	slices.SortFunc(events, compareAppUsageEvents)
+
+	if message.AfterGUID != "" {
+		afterIndex := slices.IndexFunc(events, func(event korifiv1alpha1.CFAppUsageEvent) bool {
+			return event.Name == message.AfterGUID
+		})
+		if afterIndex < 0 {
+			return nil, apierrors.NewInvalidRequestError(nil, "After guid filter must be a valid app usage event guid.")
+		}
+		events = events[afterIndex+1:]
+	}
+	events = settledUsageEvents(events, r.settleWindow, func(event korifiv1alpha1.CFAppUsageEvent) time.Time {
+		return event.Spec.Timestamp.Time
+	})
+
+	records := []AppUsageEventRecord{}
+	for _, event := range events {
+		if tools.EmptyOrContains(message.GUIDs, event.Name) {
+			records = append(records, appUsageEventToRecord(event))
+		}
+	}
+
+	return records, nil
+}
+
+func (r *AppUsageEventRepo) GetAppUsageEvent(ctx context.Context, authInfo authorization.Info, guid string) (AppUsageEventRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return AppUsageEventRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	event := &korifiv1alpha1.CFAppUsageEvent{}
+	err = userClient.Get(ctx, client.ObjectKey{Namespace: r.rootNamespace, Name: guid}, event)
+	if err != nil {
+		return AppUsageEventRecord{}, apierrors.FromK8sError(err, AppUsageEventResourceType)
+	}
+
+	return appUsageEventToRecord(*event), nil
+}
+
+// PurgeAndReseedAppUsageEvents deletes all app usage events and makes the
+// controllers record a STARTED event for every started process again, by
+// dropping the usage they last recorded on the processes
+func (r *AppUsageEventRepo) PurgeAndReseedAppUsageEvents(ctx context.Context, authInfo authorization.Info) error {
+	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	err = userClient.DeleteAllOf(ctx, &korifiv1alpha1.CFAppUsageEvent{}, client.InNamespace(r.rootNamespace))
+	if err != nil {
+		return apierrors.FromK8sError(err, AppUsageEventResourceType)
+	}
+
+	nsList, err := authorizedSpaceNamespaces(ctx, authInfo, r.namespacePermissions)
+	if err != nil {
+		return err
+	}
+
+	processes, err := listInNamespaces(ctx, nsList.Collect(), func(ctx context.Context, ns string) ([]korifiv1alpha1.CFProcess, error) {
+		processList := &korifiv1alpha1.CFProcessList{}
+		err := userClient.List(ctx, processList, client.InNamespace(ns))
+		return processList.Items, err
+	})
+	if err != nil {
+		return apierrors.FromK8sError(err, ProcessResourceType)
+	}
+
+	for i := range processes {
+		cfProcess := &processes[i]
+		if _, ok := cfProcess.Annotations[korifiv1alpha1.AppUsageAnnotationKey]; !ok {
+			continue
+		}
+
+		err = k8s.PatchResource(ctx, userClient, cfProcess, func() {
+			delete(cfProcess.Annotations, korifiv1alpha1.AppUsageAnnotationKey)
+		})
+		if err != nil {
+			return apierrors.FromK8sError(err, ProcessResourceType)
+		}
+	}
+
+	return nil
+}
+
+// compareAppUsageEvents orders the events by the time they were recorded.
+// Events recorded at the same time are ordered by guid, so that the order is
+// stable across requests.
+func compareAppUsageEvents(e1, e2 korifiv1alpha1.CFAppUsageEvent) int {
+	if c := e1.Spec.Timestamp.Compare(e2.Spec.Timestamp.Time); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(e1.Name, e2.Name)
+}
+
+// settledUsageEvents drops the events recorded within the settle window from
+// the sorted events
+func settledUsageEvents[T any](events []T, settleWindow time.Duration, timestamp func(T) time.Time) []T {
+	cutoff := time.Now().Add(-settleWindow)
+	settledCount := slices.IndexFunc(events, func(event T) bool {
+		return timestamp(event).After(cutoff)
+	})
+	if settledCount < 0 {
+		return events
+	}
+
+	return events[:settledCount]
+}
+
+func appUsageEventToRecord(event korifiv1alpha1.CFAppUsageEvent) AppUsageEventRecord {
+	return AppUsageEventRecord{
+		GUID:                  event.Name,
+		CreatedAt:             event.Spec.Timestamp.Time,
+		State:                 event.Spec.State,
+		PreviousState:         event.Spec.PreviousState,
+		AppGUID:               event.Spec.AppGUID,
+		AppName:               event.Spec.AppName,
+		ProcessGUID:           event.Spec.ProcessGUID,
+		ProcessType:           event.Spec.ProcessType,
+		SpaceGUID:             event.Spec.SpaceGUID,
+		SpaceName:             event.Spec.SpaceName,
+		OrgGUID:               event.Spec.OrgGUID,
+		InstanceCount:         event.Spec.InstanceCount,
+		PreviousInstanceCount: event.Spec.PreviousInstanceCount,
+		MemoryMB:              event.Spec.MemoryMB,
+		PreviousMemoryMB:      event.Spec.PreviousMemoryMB,
+	}
+}
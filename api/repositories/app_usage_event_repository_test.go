@@ -0,0 +1,240 @@
+package repositories_test
+
+import (
+	"time"
+
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("AppUsageEventRepository", func() {
+	var (
+		appUsageEventRepo *repositories.AppUsageEventRepo
+		event1            *korifiv1alpha1.CFAppUsageEvent
+		event2            *korifiv1alpha1.CFAppUsageEvent
+	)
+
+	createAppUsageEvent := func(timestamp time.Time, state string) *korifiv1alpha1.CFAppUsageEvent {
+		event := &korifiv1alpha1.CFAppUsageEvent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      uuid.NewString(),
+				Namespace: rootNamespace,
+			},
+			Spec: korifiv1alpha1.CFAppUsageEventSpec{
+				Timestamp:             metav1.NewMicroTime(timestamp),
+				State:                 state,
+				PreviousState:         korifiv1alpha1.AppUsageStateStopped,
+				AppGUID:               "app-guid",
+				AppName:               "app-name",
+				ProcessGUID:           "process-guid",
+				ProcessType:           "web",
+				SpaceGUID:             "space-guid",
+				SpaceName:             "space-name",
+				OrgGUID:               "org-guid",
+				InstanceCount:         2,
+				PreviousInstanceCount: 1,
+				MemoryMB:              256,
+				PreviousMemoryMB:      128,
+			},
+		}
+		Expect(k8sClient.Create(ctx, event)).To(Succeed())
+
+		return event
+	}
+
+	BeforeEach(func() {
+		appUsageEventRepo = repositories.NewAppUsageEventRepo(userClientFactory, nsPerms, rootNamespace, 0)
+
+		now := time.Now()
+		event2 = createAppUsageEvent(now, korifiv1alpha1.AppUsageStateStopped)
+		event1 = createAppUsageEvent(now.Add(-time.Minute), korifiv1alpha1.AppUsageStateStarted)
+	})
+
+	Describe("ListAppUsageEvents", func() {
+		var (
+			listMsg        repositories.ListAppUsageEventsMessage
+			appUsageEvents []repositories.AppUsageEventRecord
+			listErr        error
+		)
+
+		BeforeEach(func() {
+			listMsg = repositories.ListAppUsageEventsMessage{
+				GUIDs: []string{event1.Name, event2.Name},
+			}
+		})
+
+		JustBeforeEach(func() {
+			appUsageEvents, listErr = appUsageEventRepo.ListAppUsageEvents(ctx, authInfo, listMsg)
+		})
+
+		It("returns a forbidden error", func() {
+			Expect(listErr).To(BeAssignableToTypeOf(apierrors.ForbiddenError{}))
+		})
+
+		When("the user is an admin", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, adminRole.Name, rootNamespace)
+			})
+
+			It("lists the events in the order they were recorded", func() {
+				Expect(listErr).NotTo(HaveOccurred())
+				Expect(appUsageEvents).To(HaveLen(2))
+				Expect(appUsageEvents[0].GUID).To(Equal(event1.Name))
+				Expect(appUsageEvents[1].GUID).To(Equal(event2.Name))
+			})
+
+			It("presents the events", func() {
+				Expect(listErr).NotTo(HaveOccurred())
+				Expect(appUsageEvents[0]).To(MatchAllFields(Fields{
+					"GUID":                  Equal(event1.Name),
+					"CreatedAt":             BeTemporally("~", event1.Spec.Timestamp.Time, time.Millisecond),
+					"State":                 Equal(korifiv1alpha1.AppUsageStateStarted),
+					"PreviousState":         Equal(korifiv1alpha1.AppUsageStateStopped),
+					"AppGUID":               Equal("app-guid"),
+					"AppName":               Equal("app-name"),
+					"ProcessGUID":           Equal("process-guid"),
+					"ProcessType":           Equal("web"),
+					"SpaceGUID":             Equal("space-guid"),
+					"SpaceName":             Equal("space-name"),
+					"OrgGUID":               Equal("org-guid"),
+					"InstanceCount":         BeEquivalentTo(2),
+					"PreviousInstanceCount": BeEquivalentTo(1),
+					"MemoryMB":              BeEquivalentTo(256),
+					"PreviousMemoryMB":      BeEquivalentTo(128),
+				}))
+			})
+
+			When("listing the events after a guid", func() {
+				BeforeEach(func() {
+					listMsg.AfterGUID = event1.Name
+				})
+
+				It("returns the events recorded after that event", func() {
+					Expect(listErr).NotTo(HaveOccurred())
+					Expect(appUsageEvents).To(ConsistOf(HaveField("GUID", event2.Name)))
+				})
+			})
+
+			When("events have been recorded within the settle window", func() {
+				BeforeEach(func() {
+					appUsageEventRepo = repositories.NewAppUsageEventRepo(userClientFactory, nsPerms, rootNamespace, 30*time.Second)
+				})
+
+				It("holds them back", func() {
+					Expect(listErr).NotTo(HaveOccurred())
+					Expect(appUsageEvents).To(ConsistOf(HaveField("GUID", event1.Name)))
+				})
+			})
+
+			When("the after guid is not an event", func() {
+				BeforeEach(func() {
+					listMsg.AfterGUID = "not-an-event"
+				})
+
+				It("returns an invalid request error", func() {
+					Expect(listErr).To(BeAssignableToTypeOf(apierrors.InvalidRequestError{}))
+				})
+			})
+		})
+	})
+
+	Describe("GetAppUsageEvent", func() {
+		var (
+			appUsageEvent repositories.AppUsageEventRecord
+			getErr        error
+			guid          string
+		)
+
+		BeforeEach(func() {
+			guid = event1.Name
+		})
+
+		JustBeforeEach(func() {
+			appUsageEvent, getErr = appUsageEventRepo.GetAppUsageEvent(ctx, authInfo, guid)
+		})
+
+		It("returns a forbidden error", func() {
+			Expect(getErr).To(BeAssignableToTypeOf(apierrors.ForbiddenError{}))
+		})
+
+		When("the user is an admin", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, adminRole.Name, rootNamespace)
+			})
+
+			It("returns the event", func() {
+				Expect(getErr).NotTo(HaveOccurred())
+				Expect(appUsageEvent.GUID).To(Equal(event1.Name))
+				Expect(appUsageEvent.State).To(Equal(korifiv1alpha1.AppUsageStateStarted))
+			})
+
+			When("the event does not exist", func() {
+				BeforeEach(func() {
+					guid = "not-an-event"
+				})
+
+				It("returns a not found error", func() {
+					Expect(getErr).To(BeAssignableToTypeOf(apierrors.NotFoundError{}))
+				})
+			})
+		})
+	})
+
+	Describe("PurgeAndReseedAppUsageEvents", func() {
+		var (
+			cfProcess *korifiv1alpha1.CFProcess
+			purgeErr  error
+		)
+
+		BeforeEach(func() {
+			org := createOrgWithCleanup(ctx, prefixedGUID("org"))
+			space := createSpaceWithCleanup(ctx, org.Name, prefixedGUID("space"))
+
+			cfProcess = createProcessCR(ctx, k8sClient, prefixedGUID("process"), space.Name, "app-guid")
+			Expect(k8s.PatchResource(ctx, k8sClient, cfProcess, func() {
+				cfProcess.Annotations = map[string]string{
+					korifiv1alpha1.AppUsageAnnotationKey: `{"sequence":1,"state":"STARTED"}`,
+				}
+			})).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			purgeErr = appUsageEventRepo.PurgeAndReseedAppUsageEvents(ctx, authInfo)
+		})
+
+		It("returns a forbidden error", func() {
+			Expect(purgeErr).To(BeAssignableToTypeOf(apierrors.ForbiddenError{}))
+		})
+
+		When("the user is an admin", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, adminRole.Name, rootNamespace)
+				createRoleBinding(ctx, userName, adminRole.Name, cfProcess.Namespace)
+			})
+
+			It("deletes all events", func() {
+				Expect(purgeErr).NotTo(HaveOccurred())
+
+				eventList := &korifiv1alpha1.CFAppUsageEventList{}
+				Expect(k8sClient.List(ctx, eventList, client.InNamespace(rootNamespace))).To(Succeed())
+				Expect(eventList.Items).To(BeEmpty())
+			})
+
+			It("drops the recorded usage of the processes, so that it is recorded again", func() {
+				Expect(purgeErr).NotTo(HaveOccurred())
+
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cfProcess), cfProcess)).To(Succeed())
+				Expect(cfProcess.Annotations).NotTo(HaveKey(korifiv1alpha1.AppUsageAnnotationKey))
+			})
+		})
+	})
+})
@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/repositories/compare"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools"
+	"github.com/BooleanCat/go-functional/v2/it"
+	"github.com/BooleanCat/go-functional/v2/it/itx"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	AuditEventResourceType = "Audit Event"
+
+	// AuditEventTypeAppCrash is the type of the audit events of crashed app
+	// instances, which the runners record as CFAuditEvents
+	AuditEventTypeAppCrash = korifiv1alpha1.AuditEventTypeAppCrash
+)
+
+type AuditEventRecord struct {
+	GUID       string
+	Type       string
+	ActorGUID  string
+	ActorType  string
+	ActorName  string
+	TargetGUID string
+	TargetType string
+	SpaceGUID  string
+	Data       map[string]any
+	CreatedAt  time.Time
+	UpdatedAt  *time.Time
+}
+
+type ListAuditEventsMessage struct {
+	Types       []string
+	TargetGUIDs []string
+	SpaceGUIDs  []string
+	OrderBy     string
+}
+
+func (m *ListAuditEventsMessage) matches(event korifiv1alpha1.CFAuditEvent) bool {
+	return tools.EmptyOrContains(m.Types, event.Spec.Type) &&
+		tools.EmptyOrContains(m.TargetGUIDs, event.Spec.TargetGUID) &&
+		tools.EmptyOrContains(m.SpaceGUIDs, event.Namespace)
+}
+
+type AuditEventRepo struct {
+	userClientFactory    authorization.UserK8sClientFactory
+	namespacePermissions *authorization.NamespacePermissions
+	sorter               *compare.Sorter[AuditEventRecord]
+}
+
+func NewAuditEventRepo(
+	userClientFactory authorization.UserK8sClientFactory,
+	namespacePermissions *authorization.NamespacePermissions,
+) *AuditEventRepo {
+	return &AuditEventRepo{
+		userClientFactory:    userClientFactory,
+		namespacePermissions: namespacePermissions,
+		sorter:               compare.NewSorter(AuditEventComparator),
+	}
+}
+
+func AuditEventComparator(fieldName string) func(AuditEventRecord, AuditEventRecord) int {
+	return func(e1, e2 AuditEventRecord) int {
+		switch fieldName {
+		case "updated_at":
+			return tools.CompareTimePtr(e1.UpdatedAt, e2.UpdatedAt)
+		default:
+			return tools.CompareTimePtr(&e1.CreatedAt, &e2.CreatedAt)
+		}
+	}
+}
+
+func (r *AuditEventRepo) ListAuditEvents(ctx context.Context, authInfo authorization.Info, message ListAuditEventsMessage) ([]AuditEventRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	nsList, err := authorizedSpaceNamespaces(ctx, authInfo, r.namespacePermissions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces for spaces with user role bindings: %w", err)
+	}
+
+	var events []korifiv1alpha1.CFAuditEvent
+	for _, ns := range nsList.Collect() {
+		eventList := &korifiv1alpha1.CFAuditEventList{}
+		err := userClient.List(ctx, eventList, client.InNamespace(ns))
+		if k8serrors.IsForbidden(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list audit events in namespace %s: %w", ns, apierrors.FromK8sError(err, AuditEventResourceType))
+		}
+		events = append(events, eventList.Items...)
+	}
+
+	filteredEvents := itx.FromSlice(events).Filter(message.matches)
+	return r.sorter.Sort(slices.Collect(it.Map(filteredEvents, auditEventToRecord)), message.OrderBy), nil
+}
+
+func auditEventToRecord(event korifiv1alpha1.CFAuditEvent) AuditEventRecord {
+	return AuditEventRecord{
+		GUID:       event.Name,
+		Type:       event.Spec.Type,
+		ActorGUID:  event.Spec.ActorGUID,
+		ActorType:  event.Spec.ActorType,
+		ActorName:  event.Spec.ActorName,
+		TargetGUID: event.Spec.TargetGUID,
+		TargetType: event.Spec.TargetType,
+		SpaceGUID:  event.Namespace,
+		Data:       auditEventData(event.Spec),
+		CreatedAt:  event.CreationTimestamp.Time,
+		UpdatedAt:  getLastUpdatedTime(&event),
+	}
+}
+
+func auditEventData(spec korifiv1alpha1.CFAuditEventSpec) map[string]any {
+	if spec.AppCrash == nil {
+		return map[string]any{}
+	}
+
+	return map[string]any{
+		"index":            int(spec.AppCrash.InstanceIndex),
+		"instance":         spec.AppCrash.InstanceName,
+		"reason":           "CRASHED",
+		"exit_status":      int(spec.AppCrash.ExitStatus),
+		"exit_reason":      spec.AppCrash.ExitReason,
+		"exit_description": spec.AppCrash.ExitDescription,
+		"crash_count":      int(spec.AppCrash.CrashCount),
+	}
+}
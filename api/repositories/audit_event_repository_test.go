@@ -0,0 +1,156 @@
+package repositories_test
+
+import (
+	"code.cloudfoundry.org/korifi/api/repositories"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("AuditEventRepository", func() {
+	var (
+		auditEventRepo *repositories.AuditEventRepo
+		org            *korifiv1alpha1.CFOrg
+		space          *korifiv1alpha1.CFSpace
+		space2         *korifiv1alpha1.CFSpace
+		crashEvent1    *korifiv1alpha1.CFAuditEvent
+		crashEvent2    *korifiv1alpha1.CFAuditEvent
+		listMsg        repositories.ListAuditEventsMessage
+		auditEvents    []repositories.AuditEventRecord
+		listErr        error
+	)
+
+	createAuditEvent := func(namespace, appGUID string) *korifiv1alpha1.CFAuditEvent {
+		event := &korifiv1alpha1.CFAuditEvent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      uuid.NewString(),
+				Namespace: namespace,
+			},
+			Spec: korifiv1alpha1.CFAuditEventSpec{
+				Type:       korifiv1alpha1.AuditEventTypeAppCrash,
+				ActorGUID:  "process-guid",
+				ActorType:  "process",
+				ActorName:  "web",
+				TargetGUID: appGUID,
+				TargetType: "app",
+				AppCrash: &korifiv1alpha1.AppCrash{
+					InstanceName:    "my-app-web-1",
+					InstanceIndex:   1,
+					ExitStatus:      137,
+					ExitReason:      "OOMKilled",
+					ExitDescription: "Instance my-app-web-1 exited with status 137 (OOMKilled), crash count: 3",
+					CrashCount:      3,
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, event)).To(Succeed())
+
+		return event
+	}
+
+	BeforeEach(func() {
+		auditEventRepo = repositories.NewAuditEventRepo(userClientFactory, nsPerms)
+
+		org = createOrgWithCleanup(ctx, prefixedGUID("org"))
+		space = createSpaceWithCleanup(ctx, org.Name, prefixedGUID("space"))
+		space2 = createSpaceWithCleanup(ctx, org.Name, prefixedGUID("space2"))
+		listMsg = repositories.ListAuditEventsMessage{}
+
+		crashEvent1 = createAuditEvent(space.Name, "app-guid-1")
+		crashEvent2 = createAuditEvent(space2.Name, "app-guid-2")
+	})
+
+	JustBeforeEach(func() {
+		auditEvents, listErr = auditEventRepo.ListAuditEvents(ctx, authInfo, listMsg)
+	})
+
+	It("returns an empty list due to no permissions", func() {
+		Expect(listErr).NotTo(HaveOccurred())
+		Expect(auditEvents).To(BeEmpty())
+	})
+
+	When("the user has the space developer role in space", func() {
+		BeforeEach(func() {
+			createRoleBinding(ctx, userName, spaceDeveloperRole.Name, space.Name)
+		})
+
+		It("lists the crash events from that namespace only", func() {
+			Expect(listErr).NotTo(HaveOccurred())
+			Expect(auditEvents).To(ConsistOf(HaveField("GUID", crashEvent1.Name)))
+		})
+
+		It("presents the crash", func() {
+			Expect(listErr).NotTo(HaveOccurred())
+			Expect(auditEvents).To(HaveLen(1))
+
+			auditEvent := auditEvents[0]
+			Expect(auditEvent.Type).To(Equal("app.crash"))
+			Expect(auditEvent.ActorGUID).To(Equal("process-guid"))
+			Expect(auditEvent.ActorType).To(Equal("process"))
+			Expect(auditEvent.ActorName).To(Equal("web"))
+			Expect(auditEvent.TargetGUID).To(Equal("app-guid-1"))
+			Expect(auditEvent.TargetType).To(Equal("app"))
+			Expect(auditEvent.SpaceGUID).To(Equal(space.Name))
+			Expect(auditEvent.CreatedAt).To(BeTemporally("~", crashEvent1.CreationTimestamp.Time, timeCheckThreshold))
+			Expect(auditEvent.Data).To(Equal(map[string]any{
+				"index":            1,
+				"instance":         "my-app-web-1",
+				"reason":           "CRASHED",
+				"exit_status":      137,
+				"exit_reason":      "OOMKilled",
+				"exit_description": "Instance my-app-web-1 exited with status 137 (OOMKilled), crash count: 3",
+				"crash_count":      3,
+			}))
+		})
+
+		When("the user has the space auditor role in space2", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceAuditorRole.Name, space2.Name)
+			})
+
+			It("lists the crash events from both namespaces", func() {
+				Expect(listErr).NotTo(HaveOccurred())
+				Expect(auditEvents).To(ConsistOf(
+					HaveField("GUID", crashEvent1.Name),
+					HaveField("GUID", crashEvent2.Name),
+				))
+			})
+
+			When("filtering by target guid", func() {
+				BeforeEach(func() {
+					listMsg.TargetGUIDs = []string{"app-guid-2"}
+				})
+
+				It("returns the events of that app only", func() {
+					Expect(listErr).NotTo(HaveOccurred())
+					Expect(auditEvents).To(ConsistOf(HaveField("GUID", crashEvent2.Name)))
+				})
+			})
+
+			When("filtering by space guid", func() {
+				BeforeEach(func() {
+					listMsg.SpaceGUIDs = []string{space.Name}
+				})
+
+				It("returns the events in that space only", func() {
+					Expect(listErr).NotTo(HaveOccurred())
+					Expect(auditEvents).To(ConsistOf(HaveField("GUID", crashEvent1.Name)))
+				})
+			})
+
+			When("filtering by another type", func() {
+				BeforeEach(func() {
+					listMsg.Types = []string{"audit.app.update"}
+				})
+
+				It("returns no events", func() {
+					Expect(listErr).NotTo(HaveOccurred())
+					Expect(auditEvents).To(BeEmpty())
+				})
+			})
+		})
+	})
+})
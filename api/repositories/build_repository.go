@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"code.cloudfoundry.org/korifi/api/authorization"
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/k8s"
 
 	"github.com/google/uuid"
 	corev1 "k8s.io/api/core/v1"
@@ -19,10 +21,13 @@ import (
 )
 
 const (
+	BuildStateQueued  = "QUEUED"
 	BuildStateStaging = "STAGING"
 	BuildStateStaged  = "STAGED"
 	BuildStateFailed  = "FAILED"
 
+	BuildQueuedReason = "BuildQueued"
+
 	StagingConditionType   = "Staging"
 	SucceededConditionType = "Succeeded"
 
@@ -54,17 +59,23 @@ func (r BuildRecord) Relationships() map[string]string {
 }
 
 type BuildRepo struct {
-	namespaceRetriever NamespaceRetriever
-	userClientFactory  authorization.UserK8sClientFactory
+	namespaceRetriever   NamespaceRetriever
+	userClientFactory    authorization.UserK8sClientFactory
+	namespacePermissions *authorization.NamespacePermissions
+	buildAwaiter         Awaiter[*korifiv1alpha1.CFBuild]
 }
 
 func NewBuildRepo(
 	namespaceRetriever NamespaceRetriever,
 	userClientFactory authorization.UserK8sClientFactory,
+	namespacePermissions *authorization.NamespacePermissions,
+	buildAwaiter Awaiter[*korifiv1alpha1.CFBuild],
 ) *BuildRepo {
 	return &BuildRepo{
-		namespaceRetriever: namespaceRetriever,
-		userClientFactory:  userClientFactory,
+		namespaceRetriever:   namespaceRetriever,
+		userClientFactory:    userClientFactory,
+		namespacePermissions: namespacePermissions,
+		buildAwaiter:         buildAwaiter,
 	}
 }
 
@@ -158,6 +169,10 @@ func (b *BuildRepo) cfBuildToBuildRecord(cfBuild korifiv1alpha1.CFBuild) BuildRe
 	// TODO: Consider moving this logic to CRDs repo in case Status Conditions change later?
 	if stagingStatus == metav1.ConditionFalse {
 		switch succeededStatus {
+		case metav1.ConditionUnknown:
+			if meta.FindStatusCondition(cfBuild.Status.Conditions, StagingConditionType).Reason == BuildQueuedReason {
+				toReturn.State = BuildStateQueued
+			}
 		case metav1.ConditionTrue:
 			toReturn.State = BuildStateStaged
 			toReturn.DropletGUID = cfBuild.Name
@@ -185,6 +200,43 @@ func (b *BuildRepo) CreateBuild(ctx context.Context, authInfo authorization.Info
 	return b.cfBuildToBuildRecord(cfBuild), nil
 }
 
+func (b *BuildRepo) CancelBuild(ctx context.Context, authInfo authorization.Info, buildGUID string) (BuildRecord, error) {
+	ns, err := b.namespaceRetriever.NamespaceFor(ctx, buildGUID, BuildResourceType)
+	if err != nil {
+		return BuildRecord{}, err
+	}
+
+	userClient, err := b.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return BuildRecord{}, fmt.Errorf("failed to build user k8s client: %w", err)
+	}
+
+	cfBuild := &korifiv1alpha1.CFBuild{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      buildGUID,
+		},
+	}
+	err = k8s.PatchResource(ctx, userClient, cfBuild, func() {
+		cfBuild.Spec.Canceled = true
+	})
+	if err != nil {
+		return BuildRecord{}, b.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, ns, apierrors.FromK8sError(err, BuildResourceType))
+	}
+
+	cfBuild, err = b.buildAwaiter.AwaitState(ctx, userClient, cfBuild, func(build *korifiv1alpha1.CFBuild) error {
+		if build.Status.ObservedGeneration != build.Generation || meta.FindStatusCondition(build.Status.Conditions, SucceededConditionType) == nil {
+			return errors.New("build has not completed yet")
+		}
+		return nil
+	})
+	if err != nil {
+		return BuildRecord{}, fmt.Errorf("failed waiting for build to get canceled: %w", apierrors.FromK8sError(err, BuildResourceType))
+	}
+
+	return b.cfBuildToBuildRecord(*cfBuild), nil
+}
+
 type CreateBuildMessage struct {
 	AppGUID         string
 	PackageGUID     string
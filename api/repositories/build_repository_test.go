@@ -2,6 +2,7 @@ package repositories_test
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,20 +13,39 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/api/repositories/fakeawaiter"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/tests/matchers"
+	"code.cloudfoundry.org/korifi/tools/k8s"
 )
 
 var _ = Describe("BuildRepository", func() {
-	var buildRepo *repositories.BuildRepo
+	var (
+		buildAwaiter *fakeawaiter.FakeAwaiter[
+			*korifiv1alpha1.CFBuild,
+			korifiv1alpha1.CFBuild,
+			korifiv1alpha1.CFBuildList,
+			*korifiv1alpha1.CFBuildList,
+		]
+		buildRepo *repositories.BuildRepo
+	)
 
 	BeforeEach(func() {
+		buildAwaiter = &fakeawaiter.FakeAwaiter[
+			*korifiv1alpha1.CFBuild,
+			korifiv1alpha1.CFBuild,
+			korifiv1alpha1.CFBuildList,
+			*korifiv1alpha1.CFBuildList,
+		]{}
 		buildRepo = repositories.NewBuildRepo(
 			namespaceRetriever,
 			userClientFactory,
+			nsPerms,
+			buildAwaiter,
 		)
 	})
 
@@ -180,6 +200,26 @@ var _ = Describe("BuildRepository", func() {
 					})
 				})
 
+				When("the build is queued", func() {
+					BeforeEach(func() {
+						meta.SetStatusCondition(&build2.Status.Conditions, metav1.Condition{
+							Type:    StagingConditionType,
+							Status:  metav1.ConditionFalse,
+							Reason:  "BuildQueued",
+							Message: "Waiting for other builds to complete",
+						})
+						Expect(k8sClient.Status().Update(ctx, build2)).To(Succeed())
+					})
+
+					It("should return a record with State: \"QUEUED\" and no DropletGUID", func() {
+						buildRecord, err := buildRepo.GetBuild(ctx, authInfo, build2GUID)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(buildRecord.State).To(Equal("QUEUED"))
+						Expect(buildRecord.DropletGUID).To(BeEmpty())
+						Expect(buildRecord.StagingErrorMsg).To(BeEmpty())
+					})
+				})
+
 				When("status.Conditions \"Staging\": False, \"Succeeded\": True, is set", func() {
 					BeforeEach(func() {
 						meta.SetStatusCondition(&build2.Status.Conditions, metav1.Condition{
@@ -504,6 +544,115 @@ var _ = Describe("BuildRepository", func() {
 			})
 		})
 	})
+
+	Describe("CancelBuild", func() {
+		var (
+			spaceGUID   string
+			cfBuild     *korifiv1alpha1.CFBuild
+			buildRecord repositories.BuildRecord
+			cancelErr   error
+		)
+
+		BeforeEach(func() {
+			spaceGUID = uuid.NewString()
+			Expect(k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: spaceGUID}})).To(Succeed())
+
+			cfBuild = &korifiv1alpha1.CFBuild{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      uuid.NewString(),
+					Namespace: spaceGUID,
+				},
+				Spec: korifiv1alpha1.CFBuildSpec{
+					PackageRef: corev1.LocalObjectReference{Name: "the-package-guid"},
+					AppRef:     corev1.LocalObjectReference{Name: "the-app-guid"},
+					Lifecycle: korifiv1alpha1.Lifecycle{
+						Type: "buildpack",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, cfBuild)).To(Succeed())
+
+			buildAwaiter.AwaitStateStub = func(ctx context.Context, _ client.WithWatch, object client.Object, _ func(*korifiv1alpha1.CFBuild) error) (*korifiv1alpha1.CFBuild, error) {
+				build, ok := object.(*korifiv1alpha1.CFBuild)
+				Expect(ok).To(BeTrue())
+
+				Expect(k8s.Patch(ctx, k8sClient, build, func() {
+					meta.SetStatusCondition(&build.Status.Conditions, metav1.Condition{
+						Type:   "Staging",
+						Status: metav1.ConditionFalse,
+						Reason: "BuildNotRunning",
+					})
+					meta.SetStatusCondition(&build.Status.Conditions, metav1.Condition{
+						Type:    "Succeeded",
+						Status:  metav1.ConditionFalse,
+						Reason:  "BuildCanceled",
+						Message: "The build has been canceled",
+					})
+				})).To(Succeed())
+
+				return build, nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			buildRecord, cancelErr = buildRepo.CancelBuild(ctx, authInfo, cfBuild.Name)
+		})
+
+		It("returns a not found error", func() {
+			Expect(cancelErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+		})
+
+		When("the user is a space manager", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceManagerRole.Name, spaceGUID)
+			})
+
+			It("returns a forbidden error", func() {
+				Expect(cancelErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			})
+		})
+
+		When("the user is a space developer", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceDeveloperRole.Name, spaceGUID)
+			})
+
+			It("cancels the build", func() {
+				Expect(cancelErr).NotTo(HaveOccurred())
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cfBuild), cfBuild)).To(Succeed())
+				Expect(cfBuild.Spec.Canceled).To(BeTrue())
+			})
+
+			It("waits for the build to complete", func() {
+				Expect(buildAwaiter.AwaitStateCallCount()).To(Equal(1))
+				actualBuild, actualStateCheck := buildAwaiter.AwaitStateArgsForCall(0)
+				Expect(actualBuild.GetName()).To(Equal(cfBuild.Name))
+
+				Expect(actualStateCheck(&korifiv1alpha1.CFBuild{})).To(MatchError(ContainSubstring("not completed")))
+				Expect(actualStateCheck(&korifiv1alpha1.CFBuild{
+					Status: korifiv1alpha1.CFBuildStatus{
+						Conditions: []metav1.Condition{{Type: "Succeeded", Status: metav1.ConditionFalse}},
+					},
+				})).To(Succeed())
+			})
+
+			It("returns the failed build", func() {
+				Expect(buildRecord.GUID).To(Equal(cfBuild.Name))
+				Expect(buildRecord.State).To(Equal("FAILED"))
+				Expect(buildRecord.StagingErrorMsg).To(Equal("The build has been canceled"))
+			})
+
+			When("the build does not complete in time", func() {
+				BeforeEach(func() {
+					buildAwaiter.AwaitStateReturns(nil, errors.New("time-out-err"))
+				})
+
+				It("returns an error", func() {
+					Expect(cancelErr).To(MatchError(ContainSubstring("time-out-err")))
+				})
+			})
+		})
+	})
 })
 
 func cleanupBuild(ctx context.Context, buildGUID, namespace string) error {
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/config"
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"github.com/BooleanCat/go-functional/v2/it"
@@ -20,7 +21,7 @@ const (
 )
 
 type BuildpackRepository struct {
-	builderName       string
+	builderName       *config.Reloadable[string]
 	userClientFactory authorization.UserK8sClientFactory
 	rootNamespace     string
 }
@@ -34,7 +35,7 @@ type BuildpackRecord struct {
 	UpdatedAt *time.Time
 }
 
-func NewBuildpackRepository(builderName string, userClientFactory authorization.UserK8sClientFactory, rootNamespace string) *BuildpackRepository {
+func NewBuildpackRepository(builderName *config.Reloadable[string], userClientFactory authorization.UserK8sClientFactory, rootNamespace string) *BuildpackRepository {
 	return &BuildpackRepository{
 		builderName:       builderName,
 		userClientFactory: userClientFactory,
@@ -54,13 +55,13 @@ func (r *BuildpackRepository) ListBuildpacks(ctx context.Context, authInfo autho
 		ctx,
 		types.NamespacedName{
 			Namespace: r.rootNamespace,
-			Name:      r.builderName,
+			Name:      r.builderName.Get(),
 		},
 		&builderInfo,
 	)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			return nil, apierrors.NewResourceNotReadyError(fmt.Errorf("BuilderInfo %q not found in namespace %q", r.builderName, r.rootNamespace))
+			return nil, apierrors.NewResourceNotReadyError(fmt.Errorf("BuilderInfo %q not found in namespace %q", r.builderName.Get(), r.rootNamespace))
 		}
 
 		return nil, apierrors.FromK8sError(err, BuildpackResourceType)
@@ -78,7 +79,7 @@ func (r *BuildpackRepository) ListBuildpacks(ctx context.Context, authInfo autho
 			conditionNotReadyMessage = "resource not reconciled"
 		}
 
-		return nil, apierrors.NewResourceNotReadyError(fmt.Errorf("BuilderInfo %q not ready: %s", r.builderName, conditionNotReadyMessage))
+		return nil, apierrors.NewResourceNotReadyError(fmt.Errorf("BuilderInfo %q not ready: %s", r.builderName.Get(), conditionNotReadyMessage))
 	}
 
 	return builderInfoToBuildpackRecords(builderInfo), nil
@@ -7,6 +7,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/meta"
 
+	"code.cloudfoundry.org/korifi/api/config"
 	. "code.cloudfoundry.org/korifi/api/repositories"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 
@@ -20,7 +21,7 @@ var _ = Describe("BuildpackRepository", func() {
 	var buildpackRepo *BuildpackRepository
 
 	BeforeEach(func() {
-		buildpackRepo = NewBuildpackRepository(builderName, userClientFactory, rootNamespace)
+		buildpackRepo = NewBuildpackRepository(config.NewReloadable(builderName), userClientFactory, rootNamespace)
 	})
 
 	Describe("ListBuildpacks", func() {
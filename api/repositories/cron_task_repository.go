@@ -0,0 +1,254 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools"
+	"github.com/BooleanCat/go-functional/v2/it"
+	"github.com/BooleanCat/go-functional/v2/it/itx"
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const CronTaskResourceType = "Cron Task"
+
+type CronTaskRecord struct {
+	GUID             string
+	SpaceGUID        string
+	AppGUID          string
+	Command          string
+	Schedule         string
+	Enabled          bool
+	MemoryMB         int64
+	DiskMB           int64
+	LastScheduleTime *time.Time
+	Labels           map[string]string
+	Annotations      map[string]string
+	CreatedAt        time.Time
+	UpdatedAt        *time.Time
+}
+
+func (t CronTaskRecord) Relationships() map[string]string {
+	return map[string]string{
+		"app": t.AppGUID,
+	}
+}
+
+type CreateCronTaskMessage struct {
+	Command     string
+	Schedule    string
+	Enabled     bool
+	MemoryMB    int64
+	DiskQuotaMB int64
+	SpaceGUID   string
+	AppGUID     string
+	Metadata
+}
+
+func (m *CreateCronTaskMessage) toCFCronTask() *korifiv1alpha1.CFCronTask {
+	return &korifiv1alpha1.CFCronTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        uuid.NewString(),
+			Namespace:   m.SpaceGUID,
+			Labels:      m.Labels,
+			Annotations: m.Annotations,
+		},
+		Spec: korifiv1alpha1.CFCronTaskSpec{
+			Command:  m.Command,
+			Schedule: m.Schedule,
+			Suspend:  !m.Enabled,
+			AppRef: corev1.LocalObjectReference{
+				Name: m.AppGUID,
+			},
+			MemoryMB:    m.MemoryMB,
+			DiskQuotaMB: m.DiskQuotaMB,
+		},
+	}
+}
+
+type ListCronTasksMessage struct {
+	AppGUIDs []string
+}
+
+func (m *ListCronTasksMessage) matches(cronTask korifiv1alpha1.CFCronTask) bool {
+	return tools.EmptyOrContains(m.AppGUIDs, cronTask.Spec.AppRef.Name)
+}
+
+type PatchCronTaskMessage struct {
+	GUID      string
+	SpaceGUID string
+	Command   *string
+	Schedule  *string
+	Enabled   *bool
+	MetadataPatch
+}
+
+func (m *PatchCronTaskMessage) apply(cronTask *korifiv1alpha1.CFCronTask) {
+	if m.Command != nil {
+		cronTask.Spec.Command = *m.Command
+	}
+
+	if m.Schedule != nil {
+		cronTask.Spec.Schedule = *m.Schedule
+	}
+
+	if m.Enabled != nil {
+		cronTask.Spec.Suspend = !*m.Enabled
+	}
+
+	m.Apply(cronTask)
+}
+
+type DeleteCronTaskMessage struct {
+	GUID      string
+	SpaceGUID string
+}
+
+type CronTaskRepo struct {
+	userClientFactory    authorization.UserK8sClientFactory
+	namespaceRetriever   NamespaceRetriever
+	namespacePermissions *authorization.NamespacePermissions
+}
+
+func NewCronTaskRepo(
+	userClientFactory authorization.UserK8sClientFactory,
+	namespaceRetriever NamespaceRetriever,
+	namespacePermissions *authorization.NamespacePermissions,
+) *CronTaskRepo {
+	return &CronTaskRepo{
+		userClientFactory:    userClientFactory,
+		namespaceRetriever:   namespaceRetriever,
+		namespacePermissions: namespacePermissions,
+	}
+}
+
+func (r *CronTaskRepo) CreateCronTask(ctx context.Context, authInfo authorization.Info, message CreateCronTaskMessage) (CronTaskRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return CronTaskRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cronTask := message.toCFCronTask()
+	if err = userClient.Create(ctx, cronTask); err != nil {
+		return CronTaskRecord{}, apierrors.FromK8sError(err, CronTaskResourceType)
+	}
+
+	return cronTaskToRecord(*cronTask), nil
+}
+
+func (r *CronTaskRepo) GetCronTask(ctx context.Context, authInfo authorization.Info, guid string) (CronTaskRecord, error) {
+	ns, err := r.namespaceRetriever.NamespaceFor(ctx, guid, CronTaskResourceType)
+	if err != nil {
+		return CronTaskRecord{}, err
+	}
+
+	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return CronTaskRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cronTask := &korifiv1alpha1.CFCronTask{}
+	if err = userClient.Get(ctx, client.ObjectKey{Namespace: ns, Name: guid}, cronTask); err != nil {
+		return CronTaskRecord{}, apierrors.FromK8sError(err, CronTaskResourceType)
+	}
+
+	return cronTaskToRecord(*cronTask), nil
+}
+
+func (r *CronTaskRepo) ListCronTasks(ctx context.Context, authInfo authorization.Info, message ListCronTasksMessage) ([]CronTaskRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	nsList, err := authorizedSpaceNamespaces(ctx, authInfo, r.namespacePermissions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces for spaces with user role bindings: %w", err)
+	}
+
+	var cronTasks []korifiv1alpha1.CFCronTask
+	for _, ns := range nsList.Collect() {
+		cronTaskList := &korifiv1alpha1.CFCronTaskList{}
+		err := userClient.List(ctx, cronTaskList, client.InNamespace(ns))
+		if k8serrors.IsForbidden(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list cron tasks in namespace %s: %w", ns, apierrors.FromK8sError(err, CronTaskResourceType))
+		}
+		cronTasks = append(cronTasks, cronTaskList.Items...)
+	}
+
+	filteredCronTasks := itx.FromSlice(cronTasks).Filter(message.matches)
+	return slices.Collect(it.Map(filteredCronTasks, cronTaskToRecord)), nil
+}
+
+func (r *CronTaskRepo) PatchCronTask(ctx context.Context, authInfo authorization.Info, message PatchCronTaskMessage) (CronTaskRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return CronTaskRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cronTask := &korifiv1alpha1.CFCronTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: message.SpaceGUID,
+			Name:      message.GUID,
+		},
+	}
+	err = PatchResource(ctx, userClient, cronTask, func() {
+		message.apply(cronTask)
+	})
+	if err != nil {
+		return CronTaskRecord{}, r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, message.SpaceGUID, apierrors.FromK8sError(err, CronTaskResourceType))
+	}
+
+	return cronTaskToRecord(*cronTask), nil
+}
+
+func (r *CronTaskRepo) DeleteCronTask(ctx context.Context, authInfo authorization.Info, message DeleteCronTaskMessage) error {
+	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	err = userClient.Delete(ctx, &korifiv1alpha1.CFCronTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      message.GUID,
+			Namespace: message.SpaceGUID,
+		},
+	})
+
+	return r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, message.SpaceGUID, apierrors.FromK8sError(err, CronTaskResourceType))
+}
+
+func cronTaskToRecord(cronTask korifiv1alpha1.CFCronTask) CronTaskRecord {
+	record := CronTaskRecord{
+		GUID:        cronTask.Name,
+		SpaceGUID:   cronTask.Namespace,
+		AppGUID:     cronTask.Spec.AppRef.Name,
+		Command:     cronTask.Spec.Command,
+		Schedule:    cronTask.Spec.Schedule,
+		Enabled:     !cronTask.Spec.Suspend,
+		MemoryMB:    cronTask.Status.MemoryMB,
+		DiskMB:      cronTask.Status.DiskQuotaMB,
+		Labels:      cronTask.Labels,
+		Annotations: cronTask.Annotations,
+		CreatedAt:   cronTask.CreationTimestamp.Time,
+		UpdatedAt:   getLastUpdatedTime(&cronTask),
+	}
+
+	if cronTask.Status.LastScheduleTime != nil {
+		record.LastScheduleTime = tools.PtrTo(cronTask.Status.LastScheduleTime.Time)
+	}
+
+	return record
+}
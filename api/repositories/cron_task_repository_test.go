@@ -0,0 +1,376 @@
+package repositories_test
+
+import (
+	"context"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tests/matchers"
+	"code.cloudfoundry.org/korifi/tools"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("CronTaskRepository", func() {
+	var (
+		cronTaskRepo *repositories.CronTaskRepo
+		org          *korifiv1alpha1.CFOrg
+		space        *korifiv1alpha1.CFSpace
+		cfApp        *korifiv1alpha1.CFApp
+	)
+
+	BeforeEach(func() {
+		cronTaskRepo = repositories.NewCronTaskRepo(userClientFactory, namespaceRetriever, nsPerms)
+
+		org = createOrgWithCleanup(ctx, prefixedGUID("org"))
+		space = createSpaceWithCleanup(ctx, org.Name, prefixedGUID("space"))
+
+		cfApp = createApp(space.Name)
+	})
+
+	createCronTask := func(namespace, appGUID string) *korifiv1alpha1.CFCronTask {
+		cfCronTask := &korifiv1alpha1.CFCronTask{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      uuid.NewString(),
+				Namespace: namespace,
+			},
+			Spec: korifiv1alpha1.CFCronTaskSpec{
+				Command:  "rake db:migrate",
+				Schedule: "0 2 * * *",
+				AppRef: corev1.LocalObjectReference{
+					Name: appGUID,
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, cfCronTask)).To(Succeed())
+
+		return cfCronTask
+	}
+
+	Describe("CreateCronTask", func() {
+		var (
+			createMessage  repositories.CreateCronTaskMessage
+			cronTaskRecord repositories.CronTaskRecord
+			createErr      error
+		)
+
+		BeforeEach(func() {
+			createMessage = repositories.CreateCronTaskMessage{
+				Command:     "rake db:migrate",
+				Schedule:    "0 2 * * *",
+				Enabled:     true,
+				MemoryMB:    1024,
+				DiskQuotaMB: 2048,
+				SpaceGUID:   space.Name,
+				AppGUID:     cfApp.Name,
+				Metadata: repositories.Metadata{
+					Labels:      map[string]string{"color": "blue"},
+					Annotations: map[string]string{"extra-bugs": "true"},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			cronTaskRecord, createErr = cronTaskRepo.CreateCronTask(ctx, authInfo, createMessage)
+		})
+
+		It("returns forbidden error", func() {
+			Expect(createErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+		})
+
+		When("the user can create cron tasks", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceDeveloperRole.Name, space.Name)
+			})
+
+			It("returns the cron task record", func() {
+				Expect(createErr).NotTo(HaveOccurred())
+
+				Expect(cronTaskRecord.GUID).NotTo(BeEmpty())
+				Expect(cronTaskRecord.SpaceGUID).To(Equal(space.Name))
+				Expect(cronTaskRecord.AppGUID).To(Equal(cfApp.Name))
+				Expect(cronTaskRecord.Command).To(Equal("rake db:migrate"))
+				Expect(cronTaskRecord.Schedule).To(Equal("0 2 * * *"))
+				Expect(cronTaskRecord.Enabled).To(BeTrue())
+				Expect(cronTaskRecord.CreatedAt).To(BeTemporally("~", time.Now(), timeCheckThreshold))
+				Expect(cronTaskRecord.Labels).To(Equal(map[string]string{"color": "blue"}))
+				Expect(cronTaskRecord.Annotations).To(Equal(map[string]string{"extra-bugs": "true"}))
+			})
+
+			It("creates the CFCronTask", func() {
+				Expect(createErr).NotTo(HaveOccurred())
+
+				cfCronTask := &korifiv1alpha1.CFCronTask{}
+				Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: space.Name, Name: cronTaskRecord.GUID}, cfCronTask)).To(Succeed())
+				Expect(cfCronTask.Spec).To(Equal(korifiv1alpha1.CFCronTaskSpec{
+					Command:  "rake db:migrate",
+					Schedule: "0 2 * * *",
+					Suspend:  false,
+					AppRef: corev1.LocalObjectReference{
+						Name: cfApp.Name,
+					},
+					MemoryMB:    1024,
+					DiskQuotaMB: 2048,
+				}))
+			})
+
+			When("the cron task is not enabled", func() {
+				BeforeEach(func() {
+					createMessage.Enabled = false
+				})
+
+				It("creates a suspended CFCronTask", func() {
+					Expect(createErr).NotTo(HaveOccurred())
+					Expect(cronTaskRecord.Enabled).To(BeFalse())
+
+					cfCronTask := &korifiv1alpha1.CFCronTask{}
+					Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: space.Name, Name: cronTaskRecord.GUID}, cfCronTask)).To(Succeed())
+					Expect(cfCronTask.Spec.Suspend).To(BeTrue())
+				})
+			})
+		})
+
+		When("unprivileged client creation fails", func() {
+			BeforeEach(func() {
+				authInfo = authorization.Info{}
+			})
+
+			It("returns an error", func() {
+				Expect(createErr).To(MatchError(ContainSubstring("failed to build user client")))
+			})
+		})
+	})
+
+	Describe("GetCronTask", func() {
+		var (
+			cfCronTask     *korifiv1alpha1.CFCronTask
+			cronTaskGUID   string
+			cronTaskRecord repositories.CronTaskRecord
+			getErr         error
+		)
+
+		BeforeEach(func() {
+			cfCronTask = createCronTask(space.Name, cfApp.Name)
+			cronTaskGUID = cfCronTask.Name
+
+			Expect(k8s.Patch(ctx, k8sClient, cfCronTask, func() {
+				cfCronTask.Status.MemoryMB = 256
+				cfCronTask.Status.DiskQuotaMB = 128
+				cfCronTask.Status.LastScheduleTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+			})).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			cronTaskRecord, getErr = cronTaskRepo.GetCronTask(ctx, authInfo, cronTaskGUID)
+		})
+
+		It("returns forbidden error", func() {
+			Expect(getErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+		})
+
+		When("the user can get cron tasks", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceDeveloperRole.Name, space.Name)
+			})
+
+			It("returns the cron task", func() {
+				Expect(getErr).NotTo(HaveOccurred())
+				Expect(cronTaskRecord.GUID).To(Equal(cronTaskGUID))
+				Expect(cronTaskRecord.AppGUID).To(Equal(cfApp.Name))
+				Expect(cronTaskRecord.Command).To(Equal("rake db:migrate"))
+				Expect(cronTaskRecord.Schedule).To(Equal("0 2 * * *"))
+				Expect(cronTaskRecord.Enabled).To(BeTrue())
+				Expect(cronTaskRecord.MemoryMB).To(BeEquivalentTo(256))
+				Expect(cronTaskRecord.DiskMB).To(BeEquivalentTo(128))
+				Expect(cronTaskRecord.LastScheduleTime).To(gstruct.PointTo(BeTemporally("~", time.Now().Add(-time.Hour), timeCheckThreshold)))
+			})
+		})
+
+		When("the cron task doesn't exist", func() {
+			BeforeEach(func() {
+				cronTaskGUID = "i-dont-exist"
+			})
+
+			It("returns a not found error", func() {
+				Expect(getErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+			})
+		})
+	})
+
+	Describe("ListCronTasks", func() {
+		var (
+			space2    *korifiv1alpha1.CFSpace
+			cfApp2    *korifiv1alpha1.CFApp
+			cronTask1 *korifiv1alpha1.CFCronTask
+			cronTask2 *korifiv1alpha1.CFCronTask
+			listMsg   repositories.ListCronTasksMessage
+			cronTasks []repositories.CronTaskRecord
+			listErr   error
+		)
+
+		BeforeEach(func() {
+			space2 = createSpaceWithCleanup(ctx, org.Name, prefixedGUID("space2"))
+			cfApp2 = createApp(space2.Name)
+			listMsg = repositories.ListCronTasksMessage{}
+
+			cronTask1 = createCronTask(space.Name, cfApp.Name)
+			cronTask2 = createCronTask(space2.Name, cfApp2.Name)
+		})
+
+		JustBeforeEach(func() {
+			cronTasks, listErr = cronTaskRepo.ListCronTasks(ctx, authInfo, listMsg)
+		})
+
+		It("returns an empty list due to no permissions", func() {
+			Expect(listErr).NotTo(HaveOccurred())
+			Expect(cronTasks).To(BeEmpty())
+		})
+
+		When("the user has the space developer role in space2", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceDeveloperRole.Name, space2.Name)
+			})
+
+			It("lists cron tasks from that namespace only", func() {
+				Expect(listErr).NotTo(HaveOccurred())
+				Expect(cronTasks).To(ConsistOf(HaveField("GUID", cronTask2.Name)))
+			})
+
+			When("the user has the space developer role in both spaces", func() {
+				BeforeEach(func() {
+					createRoleBinding(ctx, userName, spaceDeveloperRole.Name, space.Name)
+				})
+
+				It("lists cron tasks from both namespaces", func() {
+					Expect(listErr).NotTo(HaveOccurred())
+					Expect(cronTasks).To(ConsistOf(
+						HaveField("GUID", cronTask1.Name),
+						HaveField("GUID", cronTask2.Name),
+					))
+				})
+
+				When("filtering by app guid", func() {
+					BeforeEach(func() {
+						listMsg.AppGUIDs = []string{cfApp.Name}
+					})
+
+					It("returns the cron tasks of that app only", func() {
+						Expect(listErr).NotTo(HaveOccurred())
+						Expect(cronTasks).To(ConsistOf(HaveField("GUID", cronTask1.Name)))
+					})
+				})
+			})
+		})
+	})
+
+	Describe("PatchCronTask", func() {
+		var (
+			cfCronTask     *korifiv1alpha1.CFCronTask
+			patchMsg       repositories.PatchCronTaskMessage
+			cronTaskRecord repositories.CronTaskRecord
+			patchErr       error
+		)
+
+		BeforeEach(func() {
+			cfCronTask = createCronTask(space.Name, cfApp.Name)
+
+			patchMsg = repositories.PatchCronTaskMessage{
+				GUID:      cfCronTask.Name,
+				SpaceGUID: space.Name,
+				Schedule:  tools.PtrTo("@hourly"),
+				Enabled:   tools.PtrTo(false),
+				MetadataPatch: repositories.MetadataPatch{
+					Labels: map[string]*string{"color": tools.PtrTo("red")},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			cronTaskRecord, patchErr = cronTaskRepo.PatchCronTask(ctx, authInfo, patchMsg)
+		})
+
+		It("returns a not found error", func() {
+			Expect(patchErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+		})
+
+		When("the user is a space developer", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceDeveloperRole.Name, space.Name)
+			})
+
+			It("returns the patched cron task", func() {
+				Expect(patchErr).NotTo(HaveOccurred())
+				Expect(cronTaskRecord.Command).To(Equal("rake db:migrate"))
+				Expect(cronTaskRecord.Schedule).To(Equal("@hourly"))
+				Expect(cronTaskRecord.Enabled).To(BeFalse())
+				Expect(cronTaskRecord.Labels).To(HaveKeyWithValue("color", "red"))
+			})
+
+			It("patches the CFCronTask", func() {
+				Expect(patchErr).NotTo(HaveOccurred())
+
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cfCronTask), cfCronTask)).To(Succeed())
+				Expect(cfCronTask.Spec.Command).To(Equal("rake db:migrate"))
+				Expect(cfCronTask.Spec.Schedule).To(Equal("@hourly"))
+				Expect(cfCronTask.Spec.Suspend).To(BeTrue())
+				Expect(cfCronTask.Labels).To(HaveKeyWithValue("color", "red"))
+			})
+
+			When("the cron task does not exist", func() {
+				BeforeEach(func() {
+					patchMsg.GUID = "i-dont-exist"
+				})
+
+				It("returns a not found error", func() {
+					Expect(patchErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+				})
+			})
+		})
+	})
+
+	Describe("DeleteCronTask", func() {
+		var (
+			cfCronTask *korifiv1alpha1.CFCronTask
+			deleteErr  error
+		)
+
+		BeforeEach(func() {
+			cfCronTask = createCronTask(space.Name, cfApp.Name)
+		})
+
+		JustBeforeEach(func() {
+			deleteErr = cronTaskRepo.DeleteCronTask(ctx, authInfo, repositories.DeleteCronTaskMessage{
+				GUID:      cfCronTask.Name,
+				SpaceGUID: space.Name,
+			})
+		})
+
+		It("returns a not found error", func() {
+			Expect(deleteErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+		})
+
+		When("the user is a space developer", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceDeveloperRole.Name, space.Name)
+			})
+
+			It("deletes the CFCronTask", func() {
+				Expect(deleteErr).NotTo(HaveOccurred())
+
+				err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cfCronTask), &korifiv1alpha1.CFCronTask{})
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+	})
+})
@@ -89,6 +89,7 @@ type DeploymentStatusReason string
 const (
 	DeploymentStatusReasonDeploying DeploymentStatusReason = "DEPLOYING"
 	DeploymentStatusReasonDeployed  DeploymentStatusReason = "DEPLOYED"
+	DeploymentStatusReasonCanceled  DeploymentStatusReason = "CANCELED"
 )
 
 type DeploymentStatus struct {
@@ -163,7 +164,7 @@ func (r *DeploymentRepo) CreateDeployment(ctx context.Context, authInfo authoriz
 	app := &korifiv1alpha1.CFApp{}
 	err = userClient.Get(ctx, client.ObjectKey{Namespace: ns, Name: message.AppGUID}, app)
 	if err != nil {
-		return DeploymentRecord{}, apierrors.FromK8sError(err, DeploymentResourceType)
+		return DeploymentRecord{}, r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, ns, apierrors.FromK8sError(err, DeploymentResourceType))
 	}
 
 	if err = ensureSupport(ctx, userClient, app); err != nil {
@@ -172,6 +173,9 @@ func (r *DeploymentRepo) CreateDeployment(ctx context.Context, authInfo authoriz
 
 	dropletGUID := app.Spec.CurrentDropletRef.Name
 	if message.DropletGUID != "" {
+		if err = ensureDropletOfApp(ctx, userClient, app, message.DropletGUID); err != nil {
+			return DeploymentRecord{}, err
+		}
 		dropletGUID = message.DropletGUID
 	}
 
@@ -181,16 +185,88 @@ func (r *DeploymentRepo) CreateDeployment(ctx context.Context, authInfo authoriz
 		return DeploymentRecord{}, fmt.Errorf("expected app-rev to be an integer: %w", err)
 	}
 
+	previousDropletGUID := app.Spec.CurrentDropletRef.Name
+
 	err = k8s.PatchResource(ctx, userClient, app, func() {
 		app.Spec.CurrentDropletRef.Name = dropletGUID
 		if app.Annotations == nil {
 			app.Annotations = map[string]string{}
 		}
 		app.Annotations[korifiv1alpha1.CFAppRevisionKey] = newRev
+		app.Annotations[korifiv1alpha1.PreviousDropletAnnotationKey] = previousDropletGUID
+		delete(app.Annotations, korifiv1alpha1.DeploymentCanceledAnnotationKey)
 		app.Spec.DesiredState = korifiv1alpha1.StartedState
 	})
 	if err != nil {
-		return DeploymentRecord{}, apierrors.FromK8sError(err, DeploymentResourceType)
+		return DeploymentRecord{}, r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, ns, apierrors.FromK8sError(err, DeploymentResourceType))
+	}
+
+	return appToDeploymentRecord(*app), nil
+}
+
+// ensureDropletOfApp fails with an unprocessable entity error unless the
+// droplet, e.g. the droplet of a revision, exists and belongs to the app
+func ensureDropletOfApp(ctx context.Context, userClient client.Client, app *korifiv1alpha1.CFApp, dropletGUID string) error {
+	const invalidDropletMsg = "Unable to assign current droplet. Ensure the droplet exists and belongs to this app."
+
+	cfBuild := &korifiv1alpha1.CFBuild{}
+	err := userClient.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: dropletGUID}, cfBuild)
+	if err != nil {
+		return apierrors.AsUnprocessableEntity(
+			apierrors.FromK8sError(err, DropletResourceType),
+			invalidDropletMsg,
+			apierrors.ForbiddenError{}, apierrors.NotFoundError{},
+		)
+	}
+
+	if cfBuild.Spec.AppRef.Name != app.Name {
+		return apierrors.NewUnprocessableEntityError(
+			fmt.Errorf("droplet %s does not belong to app %s", dropletGUID, app.Name),
+			invalidDropletMsg,
+		)
+	}
+
+	return nil
+}
+
+// CancelDeployment rolls the app back to the droplet it ran before the
+// deployment. Only active deployments can be canceled.
+func (r *DeploymentRepo) CancelDeployment(ctx context.Context, authInfo authorization.Info, deploymentGUID string) (DeploymentRecord, error) {
+	ns, err := r.namespaceRetriever.NamespaceFor(ctx, deploymentGUID, AppResourceType)
+	if err != nil {
+		return DeploymentRecord{}, err
+	}
+
+	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return DeploymentRecord{}, fmt.Errorf("cancel-deployment failed to create user client: %w", err)
+	}
+
+	app := &korifiv1alpha1.CFApp{}
+	err = userClient.Get(ctx, client.ObjectKey{Namespace: ns, Name: deploymentGUID}, app)
+	if err != nil {
+		return DeploymentRecord{}, r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, ns, apierrors.FromK8sError(err, DeploymentResourceType))
+	}
+
+	deployment := appToDeploymentRecord(*app)
+	if deployment.Status.Value == DeploymentStatusValueFinalized {
+		return DeploymentRecord{}, apierrors.NewUnprocessableEntityError(nil, fmt.Sprintf("Cannot cancel a %s deployment", deployment.Status.Reason))
+	}
+
+	newRev, err := bumpAppRev(app.Annotations[korifiv1alpha1.CFAppRevisionKey])
+	if err != nil {
+		return DeploymentRecord{}, fmt.Errorf("expected app-rev to be an integer: %w", err)
+	}
+
+	err = k8s.PatchResource(ctx, userClient, app, func() {
+		if previousDropletGUID := app.Annotations[korifiv1alpha1.PreviousDropletAnnotationKey]; previousDropletGUID != "" {
+			app.Spec.CurrentDropletRef.Name = previousDropletGUID
+		}
+		app.Annotations[korifiv1alpha1.CFAppRevisionKey] = newRev
+		app.Annotations[korifiv1alpha1.DeploymentCanceledAnnotationKey] = "true"
+	})
+	if err != nil {
+		return DeploymentRecord{}, r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, ns, apierrors.FromK8sError(err, DeploymentResourceType))
 	}
 
 	return appToDeploymentRecord(*app), nil
@@ -248,6 +324,16 @@ func appToDeploymentRecord(cfApp korifiv1alpha1.CFApp) DeploymentRecord {
 		},
 	}
 
+	// a canceled deployment is over once it is canceled, whether or not the
+	// app becomes ready again on the previous droplet
+	if cfApp.Annotations[korifiv1alpha1.DeploymentCanceledAnnotationKey] == "true" {
+		deploymentRecord.Status = DeploymentStatus{
+			Value:  DeploymentStatusValueFinalized,
+			Reason: DeploymentStatusReasonCanceled,
+		}
+		return deploymentRecord
+	}
+
 	if meta.IsStatusConditionTrue(cfApp.Status.Conditions, korifiv1alpha1.StatusConditionReady) {
 		deploymentRecord.Status = DeploymentStatus{
 			Value:  DeploymentStatusValueFinalized,
@@ -144,8 +144,8 @@ var _ = Describe("DeploymentRepository", func() {
 			deployment, createErr = deploymentRepo.CreateDeployment(ctx, authInfo, createDeploymentMessage)
 		})
 
-		It("returns a forbidden error (as the user is not allowed to get apps)", func() {
-			Expect(createErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+		It("returns a not found error (as the user cannot see the space)", func() {
+			Expect(createErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
 		})
 
 		When("authorized in the space", func() {
@@ -211,14 +211,45 @@ var _ = Describe("DeploymentRepository", func() {
 
 				BeforeEach(func() {
 					newDropletGUID = uuid.NewString()
+					createBuild(ctx, k8sClient, cfApp.Namespace, newDropletGUID, uuid.NewString(), cfApp.Name)
 					createDeploymentMessage.DropletGUID = newDropletGUID
 				})
 
 				It("sets the new droplet guid on the app", func() {
 					Expect(createErr).NotTo(HaveOccurred())
 
+					currentDropletGUID := cfApp.Spec.CurrentDropletRef.Name
 					Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
 					Expect(cfApp.Spec.CurrentDropletRef.Name).To(Equal(newDropletGUID))
+					Expect(cfApp.Annotations).To(HaveKeyWithValue(korifiv1alpha1.PreviousDropletAnnotationKey, currentDropletGUID))
+				})
+
+				When("the droplet does not exist", func() {
+					BeforeEach(func() {
+						createDeploymentMessage.DropletGUID = "i-do-not-exist"
+					})
+
+					It("returns an unprocessable entity error", func() {
+						Expect(createErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.UnprocessableEntityError{}))
+					})
+				})
+
+				When("the droplet belongs to another app", func() {
+					BeforeEach(func() {
+						anotherApp := createApp(cfSpace.Name)
+						createDeploymentMessage.DropletGUID = uuid.NewString()
+						createBuild(ctx, k8sClient, cfApp.Namespace, createDeploymentMessage.DropletGUID, uuid.NewString(), anotherApp.Name)
+					})
+
+					It("returns an unprocessable entity error", func() {
+						Expect(createErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.UnprocessableEntityError{}))
+					})
+
+					It("does not change the app droplet", func() {
+						currentDropletGUID := cfApp.Spec.CurrentDropletRef.Name
+						Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
+						Expect(cfApp.Spec.CurrentDropletRef.Name).To(Equal(currentDropletGUID))
+					})
 				})
 			})
 
@@ -252,6 +283,89 @@ var _ = Describe("DeploymentRepository", func() {
 		})
 	})
 
+	Describe("CancelDeployment", func() {
+		var (
+			deployment          repositories.DeploymentRecord
+			cancelErr           error
+			previousDropletGUID string
+		)
+
+		BeforeEach(func() {
+			previousDropletGUID = uuid.NewString()
+			Expect(k8s.PatchResource(ctx, k8sClient, cfApp, func() {
+				cfApp.Annotations[korifiv1alpha1.PreviousDropletAnnotationKey] = previousDropletGUID
+			})).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			deployment, cancelErr = deploymentRepo.CancelDeployment(ctx, authInfo, cfApp.Name)
+		})
+
+		It("returns a not found error (as the user cannot see the space)", func() {
+			Expect(cancelErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+		})
+
+		When("authorized in the space", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, orgUserRole.Name, cfOrg.Name)
+				createRoleBinding(ctx, userName, spaceDeveloperRole.Name, cfSpace.Name)
+			})
+
+			It("returns a canceled deployment", func() {
+				Expect(cancelErr).NotTo(HaveOccurred())
+
+				Expect(deployment.GUID).To(Equal(cfApp.Name))
+				Expect(deployment.DropletGUID).To(Equal(previousDropletGUID))
+				Expect(deployment.Status.Value).To(Equal(repositories.DeploymentStatusValueFinalized))
+				Expect(deployment.Status.Reason).To(Equal(repositories.DeploymentStatusReasonCanceled))
+			})
+
+			It("reverts the app to the previous droplet", func() {
+				Expect(cancelErr).NotTo(HaveOccurred())
+
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
+				Expect(cfApp.Spec.CurrentDropletRef.Name).To(Equal(previousDropletGUID))
+				Expect(cfApp.Annotations).To(HaveKeyWithValue(CFAppRevisionKey, "2"))
+				Expect(cfApp.Annotations).To(HaveKeyWithValue(korifiv1alpha1.DeploymentCanceledAnnotationKey, "true"))
+			})
+
+			When("the deployment is finalized", func() {
+				BeforeEach(func() {
+					Expect(k8s.Patch(ctx, k8sClient, cfApp, func() {
+						meta.SetStatusCondition(&cfApp.Status.Conditions, metav1.Condition{
+							Type:   korifiv1alpha1.StatusConditionReady,
+							Status: metav1.ConditionTrue,
+							Reason: "ready",
+						})
+					})).To(Succeed())
+				})
+
+				It("returns an error", func() {
+					Expect(cancelErr).To(BeAssignableToTypeOf(apierrors.UnprocessableEntityError{}))
+					Expect(cancelErr.(apierrors.UnprocessableEntityError).Detail()).To(Equal("Cannot cancel a DEPLOYED deployment"))
+				})
+			})
+
+			When("the deployment is already canceled", func() {
+				BeforeEach(func() {
+					Expect(k8s.PatchResource(ctx, k8sClient, cfApp, func() {
+						cfApp.Annotations[korifiv1alpha1.DeploymentCanceledAnnotationKey] = "true"
+					})).To(Succeed())
+				})
+
+				It("returns an error", func() {
+					Expect(cancelErr).To(BeAssignableToTypeOf(apierrors.UnprocessableEntityError{}))
+					Expect(cancelErr.(apierrors.UnprocessableEntityError).Detail()).To(Equal("Cannot cancel a CANCELED deployment"))
+				})
+
+				It("does not bump the app-rev annotation again", func() {
+					Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
+					Expect(cfApp.Annotations).To(HaveKeyWithValue(CFAppRevisionKey, "1"))
+				})
+			})
+		})
+	})
+
 	Describe("ListDeployments", func() {
 		var (
 			message     repositories.ListDeploymentsMessage
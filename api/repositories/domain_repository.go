@@ -11,7 +11,6 @@ import (
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/tools"
-	"code.cloudfoundry.org/korifi/tools/k8s"
 	"github.com/BooleanCat/go-functional/v2/it"
 	"github.com/BooleanCat/go-functional/v2/it/itx"
 	"github.com/google/uuid"
@@ -135,12 +134,7 @@ func (r *DomainRepo) UpdateDomain(ctx context.Context, authInfo authorization.In
 		},
 	}
 
-	err = userClient.Get(ctx, client.ObjectKeyFromObject(domain), domain)
-	if err != nil {
-		return DomainRecord{}, fmt.Errorf("update-domain failed: %w", apierrors.FromK8sError(err, DomainResourceType))
-	}
-
-	err = k8s.PatchResource(ctx, userClient, domain, func() {
+	err = PatchResource(ctx, userClient, domain, func() {
 		message.MetadataPatch.Apply(domain)
 	})
 	if err != nil {
@@ -4,12 +4,12 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strconv"
 	"time"
 
 	"code.cloudfoundry.org/korifi/tools"
 	"code.cloudfoundry.org/korifi/tools/k8s"
 	"github.com/BooleanCat/go-functional/v2/it"
-	"github.com/BooleanCat/go-functional/v2/it/itx"
 
 	"code.cloudfoundry.org/korifi/api/authorization"
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
@@ -60,6 +60,15 @@ type DropletRecord struct {
 	Annotations     map[string]string
 	Image           string
 	Ports           []int32
+	Buildpacks      []DropletBuildpackRecord
+	// RevisionVersion is the version of the app revision the droplet is, 0
+	// until the droplet has been numbered
+	RevisionVersion int
+}
+
+type DropletBuildpackRecord struct {
+	Name    string
+	Version string
 }
 
 func (r DropletRecord) Relationships() map[string]string {
@@ -70,10 +79,12 @@ func (r DropletRecord) Relationships() map[string]string {
 
 type ListDropletsMessage struct {
 	PackageGUIDs []string
+	AppGUIDs     []string
 }
 
 func (m *ListDropletsMessage) matches(b korifiv1alpha1.CFBuild) bool {
 	return tools.EmptyOrContains(m.PackageGUIDs, b.Spec.PackageRef.Name) &&
+		tools.EmptyOrContains(m.AppGUIDs, b.Spec.AppRef.Name) &&
 		meta.IsStatusConditionFalse(b.Status.Conditions, StagingConditionType) &&
 		meta.IsStatusConditionTrue(b.Status.Conditions, SucceededConditionType)
 }
@@ -124,6 +135,13 @@ func cfBuildToDropletRecord(cfBuild korifiv1alpha1.CFBuild) DropletRecord {
 		processTypesMap[processTypesArrayObject[index].Type] = processTypesArrayObject[index].Command
 	}
 
+	buildpacks := []DropletBuildpackRecord{}
+	buildpackNames := []string{}
+	for _, bp := range cfBuild.Status.Droplet.Buildpacks {
+		buildpacks = append(buildpacks, DropletBuildpackRecord{Name: bp.Name, Version: bp.Version})
+		buildpackNames = append(buildpackNames, bp.Name)
+	}
+
 	result := DropletRecord{
 		GUID:      cfBuild.Name,
 		State:     "STAGED",
@@ -132,8 +150,8 @@ func cfBuildToDropletRecord(cfBuild korifiv1alpha1.CFBuild) DropletRecord {
 		Lifecycle: Lifecycle{
 			Type: string(cfBuild.Spec.Lifecycle.Type),
 			Data: LifecycleData{
-				Buildpacks: []string{},
-				Stack:      cfBuild.Spec.Lifecycle.Data.Stack,
+				Buildpacks: buildpackNames,
+				Stack:      cfBuild.Status.Droplet.Stack,
 			},
 		},
 		Stack:        cfBuild.Status.Droplet.Stack,
@@ -143,6 +161,11 @@ func cfBuildToDropletRecord(cfBuild korifiv1alpha1.CFBuild) DropletRecord {
 		Labels:       cfBuild.Labels,
 		Annotations:  cfBuild.Annotations,
 		Ports:        cfBuild.Status.Droplet.Ports,
+		Buildpacks:   buildpacks,
+	}
+
+	if version, err := strconv.Atoi(cfBuild.Annotations[korifiv1alpha1.RevisionVersionAnnotationKey]); err == nil {
+		result.RevisionVersion = version
 	}
 
 	if cfBuild.Spec.Lifecycle.Type == "docker" {
@@ -166,20 +189,21 @@ func (r *DropletRepo) ListDroplets(ctx context.Context, authInfo authorization.I
 		return []DropletRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
 
-	var allBuilds []korifiv1alpha1.CFBuild
+	var filteredBuilds []korifiv1alpha1.CFBuild
 	for ns := range namespaces {
-		err := userClient.List(ctx, buildList, client.InNamespace(ns))
+		nsBuilds, err := listFiltered(ctx, userClient, buildList, func() []korifiv1alpha1.CFBuild {
+			return buildList.Items
+		}, message.matches, client.InNamespace(ns))
 		if k8serrors.IsForbidden(err) {
 			continue
 		}
 		if err != nil {
 			return []DropletRecord{}, apierrors.FromK8sError(err, BuildResourceType)
 		}
-		allBuilds = append(allBuilds, buildList.Items...)
+		filteredBuilds = append(filteredBuilds, nsBuilds...)
 	}
 
-	filteredBuilds := itx.FromSlice(allBuilds).Filter(message.matches)
-	return slices.Collect(it.Map(filteredBuilds, cfBuildToDropletRecord)), nil
+	return slices.Collect(it.Map(slices.Values(filteredBuilds), cfBuildToDropletRecord)), nil
 }
 
 type UpdateDropletMessage struct {
@@ -197,7 +221,8 @@ func (r *DropletRepo) UpdateDroplet(ctx context.Context, authInfo authorization.
 		message.MetadataPatch.Apply(build)
 	})
 	if err != nil {
-		return DropletRecord{}, fmt.Errorf("failed to patch droplet metadata: %w", apierrors.FromK8sError(err, DropletResourceType))
+		return DropletRecord{}, fmt.Errorf("failed to patch droplet metadata: %w",
+			r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, build.Namespace, apierrors.FromK8sError(err, DropletResourceType)))
 	}
 
 	return cfBuildToDroplet(build)
@@ -137,6 +137,9 @@ var _ = Describe("DropletRepository", func() {
 								},
 							},
 							Ports: []int32{1234, 2345},
+							Buildpacks: []korifiv1alpha1.DropletBuildpack{
+								{Name: "paketo-buildpacks/ruby", Version: "1.2.3"},
+							},
 						}
 					})).To(Succeed())
 				})
@@ -149,8 +152,11 @@ var _ = Describe("DropletRepository", func() {
 					Expect(dropletRecord.UpdatedAt).To(gstruct.PointTo(BeTemporally("~", time.Now(), timeCheckThreshold)))
 					Expect(dropletRecord.Stack).To(Equal(build.Status.Droplet.Stack))
 					Expect(dropletRecord.Lifecycle.Type).To(Equal(string(build.Spec.Lifecycle.Type)))
-					Expect(dropletRecord.Lifecycle.Data.Buildpacks).To(BeEmpty())
-					Expect(dropletRecord.Lifecycle.Data.Stack).To(Equal(build.Spec.Lifecycle.Data.Stack))
+					Expect(dropletRecord.Lifecycle.Data.Buildpacks).To(Equal([]string{"paketo-buildpacks/ruby"}))
+					Expect(dropletRecord.Lifecycle.Data.Stack).To(Equal(dropletStack))
+					Expect(dropletRecord.Buildpacks).To(Equal([]repositories.DropletBuildpackRecord{
+						{Name: "paketo-buildpacks/ruby", Version: "1.2.3"},
+					}))
 					Expect(dropletRecord.Image).To(BeEmpty())
 					Expect(dropletRecord.Ports).To(ConsistOf(int32(1234), int32(2345)))
 					Expect(dropletRecord.AppGUID).To(Equal(build.Spec.AppRef.Name))
@@ -174,6 +180,22 @@ var _ = Describe("DropletRepository", func() {
 					}))
 				})
 
+				It("returns a droplet that has not been numbered as a revision yet", func() {
+					Expect(dropletRecord.RevisionVersion).To(BeZero())
+				})
+
+				When("the droplet has been numbered as a revision", func() {
+					BeforeEach(func() {
+						Expect(k8s.PatchResource(ctx, k8sClient, build, func() {
+							build.Annotations[korifiv1alpha1.RevisionVersionAnnotationKey] = "3"
+						})).To(Succeed())
+					})
+
+					It("returns its revision version", func() {
+						Expect(dropletRecord.RevisionVersion).To(Equal(3))
+					})
+				})
+
 				When("the droplet is of type docker", func() {
 					BeforeEach(func() {
 						Expect(k8s.Patch(ctx, k8sClient, build, func() {
@@ -316,10 +338,15 @@ var _ = Describe("DropletRepository", func() {
 	Describe("ListDroplets", func() {
 		var (
 			dropletRecords []repositories.DropletRecord
+			listMessage    repositories.ListDropletsMessage
 			listErr        error
 		)
 
 		BeforeEach(func() {
+			listMessage = repositories.ListDropletsMessage{
+				PackageGUIDs: []string{packageGUID},
+			}
+
 			meta.SetStatusCondition(&build.Status.Conditions, metav1.Condition{
 				Type:    "Staging",
 				Status:  metav1.ConditionFalse,
@@ -359,9 +386,7 @@ var _ = Describe("DropletRepository", func() {
 		})
 
 		JustBeforeEach(func() {
-			dropletRecords, listErr = dropletRepo.ListDroplets(testCtx, authInfo, repositories.ListDropletsMessage{
-				PackageGUIDs: []string{packageGUID},
-			})
+			dropletRecords, listErr = dropletRepo.ListDroplets(testCtx, authInfo, listMessage)
 		})
 
 		When("the user is not authorized to list the droplet", func() {
@@ -393,6 +418,33 @@ var _ = Describe("DropletRepository", func() {
 					Expect(dropletRecords).To(HaveLen(1))
 				})
 			})
+
+			When("filtering by app guids", func() {
+				BeforeEach(func() {
+					listMessage = repositories.ListDropletsMessage{
+						AppGUIDs: []string{appGUID},
+					}
+				})
+
+				It("returns the droplets of the apps", func() {
+					Expect(listErr).NotTo(HaveOccurred())
+					Expect(dropletRecords).To(HaveLen(1))
+					Expect(dropletRecords[0].GUID).To(Equal(build.Name))
+				})
+
+				When("the app has no droplets", func() {
+					BeforeEach(func() {
+						listMessage = repositories.ListDropletsMessage{
+							AppGUIDs: []string{"another-app-guid"},
+						}
+					})
+
+					It("returns an empty list", func() {
+						Expect(listErr).NotTo(HaveOccurred())
+						Expect(dropletRecords).To(BeEmpty())
+					})
+				})
+			})
 		})
 	})
 
@@ -502,7 +554,7 @@ var _ = Describe("DropletRepository", func() {
 					By("returning a record with Lifecycle fields matching the CR", func() {
 						Expect(dropletRecord.Lifecycle.Type).To(Equal(string(build.Spec.Lifecycle.Type)), "returned record lifecycle.type did not match CR")
 						Expect(dropletRecord.Lifecycle.Data.Buildpacks).To(BeEmpty(), "returned record lifecycle.data.buildpacks did not match CR")
-						Expect(dropletRecord.Lifecycle.Data.Stack).To(Equal(build.Spec.Lifecycle.Data.Stack), "returned record lifecycle.data.stack did not match CR")
+						Expect(dropletRecord.Lifecycle.Data.Stack).To(Equal(dropletStack), "returned record lifecycle.data.stack did not match the droplet stack")
 					})
 
 					By("returning a record with an AppGUID field matching the CR", func() {
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"code.cloudfoundry.org/korifi/api/config"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+)
+
+// GroupRoleMappingLabel marks the role bindings of group role mappings
+const GroupRoleMappingLabel = "korifi.cloudfoundry.org/group-role-mapping"
+
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=create;patch;delete
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=bind
+
+// GroupRoleBinder grants the CF roles configured for identity provider groups
+// by binding the role ClusterRoles to Group subjects. Kubernetes authorizes
+// group members through these bindings, and NamespacePermissions matches them
+// against the groups of the authenticated identity, so members see the orgs
+// and spaces of their groups without per-user role assignments.
+//
+// The bindings are labelled with GroupRoleMappingLabel, so that the bindings
+// of mappings that have been removed from the configuration can be found and
+// deleted, along with their copies propagated to the spaces of an org.
+type GroupRoleBinder struct {
+	privilegedClient client.Client
+	rootNamespace    string
+	roleMappings     *config.Reloadable[map[string]config.Role]
+}
+
+func NewGroupRoleBinder(privilegedClient client.Client, rootNamespace string, roleMappings *config.Reloadable[map[string]config.Role]) *GroupRoleBinder {
+	return &GroupRoleBinder{
+		privilegedClient: privilegedClient,
+		rootNamespace:    rootNamespace,
+		roleMappings:     roleMappings,
+	}
+}
+
+// BindGroups binds every mapping it can, so that a mapping to an org or
+// space that does not exist (yet) does not prevent the others from applying.
+// It then unbinds the mappings that are no longer configured, so that members
+// of a group lose the roles revoked from the group.
+func (b *GroupRoleBinder) BindGroups(ctx context.Context, groupRoleMappings []config.GroupRoleMapping) error {
+	var errs []error
+	desired := map[client.ObjectKey]bool{}
+	for _, mapping := range groupRoleMappings {
+		ns := mapping.Space
+		if ns == "" {
+			ns = mapping.Org
+		}
+
+		desired[groupRoleBindingKey(ns, mapping.Group, mapping.Role)] = true
+		if err := b.bind(ctx, ns, mapping.Group, mapping.Role); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		desired[groupRoleBindingKey(b.rootNamespace, mapping.Group, cfUserRoleType)] = true
+		if err := b.bind(ctx, b.rootNamespace, mapping.Group, cfUserRoleType); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := b.unbindStale(ctx, desired); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// unbindStale deletes the group role bindings that are not desired. Copies
+// propagated to the spaces of an org are deleted along with the org binding
+// they have been copied from.
+func (b *GroupRoleBinder) unbindStale(ctx context.Context, desired map[client.ObjectKey]bool) error {
+	roleBindings := &rbacv1.RoleBindingList{}
+	err := b.privilegedClient.List(ctx, roleBindings, client.MatchingLabels{GroupRoleMappingLabel: "true"})
+	if err != nil {
+		return fmt.Errorf("failed to list group role bindings: %w", err)
+	}
+
+	var errs []error
+	for i := range roleBindings.Items {
+		roleBinding := &roleBindings.Items[i]
+
+		key := client.ObjectKeyFromObject(roleBinding)
+		if sourceNamespace, ok := roleBinding.Labels[korifiv1alpha1.PropagatedFromLabel]; ok {
+			key.Namespace = sourceNamespace
+		}
+		if desired[key] {
+			continue
+		}
+
+		if err = b.privilegedClient.Delete(ctx, roleBinding); client.IgnoreNotFound(err) != nil {
+			errs = append(errs, fmt.Errorf("failed to delete group role binding %q in namespace %q: %w", roleBinding.Name, roleBinding.Namespace, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (b *GroupRoleBinder) bind(ctx context.Context, namespace, group, roleType string) error {
+	k8sRoleConfig, ok := b.roleMappings.Get()[roleType]
+	if !ok {
+		return fmt.Errorf("invalid role type: %q", roleType)
+	}
+
+	propagate := k8sRoleConfig.Propagate && roleType != cfUserRoleType
+	roleBinding := createRoleBinding(namespace, roleType, rbacv1.GroupKind, group, "", groupRoleGUID(namespace, roleType, group), k8sRoleConfig.Name, propagate)
+	roleBinding.Subjects[0].APIGroup = rbacv1.GroupName
+	roleBinding.Name = groupRoleBindingKey(namespace, group, roleType).Name
+	roleBinding.Labels[GroupRoleMappingLabel] = "true"
+
+	err := b.privilegedClient.Create(ctx, &roleBinding)
+	if k8serrors.IsAlreadyExists(err) {
+		// label bindings created before they were labelled, so that they
+		// are unbound once their mapping is removed
+		existing := &rbacv1.RoleBinding{}
+		err = b.privilegedClient.Get(ctx, client.ObjectKeyFromObject(&roleBinding), existing)
+		if err == nil && existing.Labels[GroupRoleMappingLabel] != "true" {
+			err = k8s.PatchResource(ctx, b.privilegedClient, existing, func() {
+				if existing.Labels == nil {
+					existing.Labels = map[string]string{}
+				}
+				existing.Labels[GroupRoleMappingLabel] = "true"
+			})
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to assign group %q to role %q in namespace %q: %w", group, roleType, namespace, err)
+	}
+
+	return nil
+}
+
+func groupRoleBindingKey(namespace, group, roleType string) client.ObjectKey {
+	return client.ObjectKey{
+		Namespace: namespace,
+		Name:      calculateRoleBindingName(roleType, "", rbacv1.GroupKind+":"+group),
+	}
+}
+
+func groupRoleGUID(namespace, roleType, group string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(namespace+"/"+roleType+"/"+group)).String()
+}
@@ -0,0 +1,176 @@
+package repositories_test
+
+import (
+	"code.cloudfoundry.org/korifi/api/config"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("GroupRoleBinder", func() {
+	var (
+		binder            *repositories.GroupRoleBinder
+		cfOrg             *korifiv1alpha1.CFOrg
+		cfSpace           *korifiv1alpha1.CFSpace
+		groupRoleMappings []config.GroupRoleMapping
+		bindErr           error
+	)
+
+	groupRoleBindings := func(namespace string) []rbacv1.RoleBinding {
+		GinkgoHelper()
+
+		roleBindings := rbacv1.RoleBindingList{}
+		Expect(k8sClient.List(ctx, &roleBindings, client.InNamespace(namespace))).To(Succeed())
+
+		var result []rbacv1.RoleBinding
+		for _, rb := range roleBindings.Items {
+			if rb.Subjects[0].Kind == rbacv1.GroupKind {
+				result = append(result, rb)
+			}
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		binder = repositories.NewGroupRoleBinder(k8sClient, rootNamespace, config.NewReloadable(map[string]config.Role{
+			"space_developer":      {Name: spaceDeveloperRole.Name, Level: config.SpaceRole},
+			"organization_manager": {Name: orgManagerRole.Name, Level: config.OrgRole, Propagate: true},
+			"cf_user":              {Name: rootNamespaceUserRole.Name},
+		}))
+
+		cfOrg = createOrgWithCleanup(ctx, uuid.NewString())
+		cfSpace = createSpaceWithCleanup(ctx, cfOrg.Name, uuid.NewString())
+
+		groupRoleMappings = []config.GroupRoleMapping{
+			{Group: "managers", Role: "organization_manager", Org: cfOrg.Name},
+			{Group: "developers", Role: "space_developer", Space: cfSpace.Name},
+		}
+	})
+
+	JustBeforeEach(func() {
+		bindErr = binder.BindGroups(ctx, groupRoleMappings)
+	})
+
+	It("binds the org role to the group in the org namespace", func() {
+		Expect(bindErr).NotTo(HaveOccurred())
+
+		bindings := groupRoleBindings(cfOrg.Name)
+		Expect(bindings).To(HaveLen(1))
+		Expect(bindings[0].Subjects).To(ConsistOf(rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "managers"}))
+		Expect(bindings[0].RoleRef.Name).To(Equal(orgManagerRole.Name))
+		Expect(bindings[0].Labels).To(HaveKey(repositories.RoleGuidLabel))
+		Expect(bindings[0].Annotations).To(HaveKeyWithValue(korifiv1alpha1.PropagateRoleBindingAnnotation, "true"))
+	})
+
+	It("binds the space role to the group in the space namespace", func() {
+		Expect(bindErr).NotTo(HaveOccurred())
+
+		bindings := groupRoleBindings(cfSpace.Name)
+		Expect(bindings).To(HaveLen(1))
+		Expect(bindings[0].Subjects[0].Name).To(Equal("developers"))
+		Expect(bindings[0].RoleRef.Name).To(Equal(spaceDeveloperRole.Name))
+		Expect(bindings[0].Annotations).To(HaveKeyWithValue(korifiv1alpha1.PropagateRoleBindingAnnotation, "false"))
+	})
+
+	It("makes the groups cf users", func() {
+		Expect(bindErr).NotTo(HaveOccurred())
+
+		var groups []string
+		for _, rb := range groupRoleBindings(rootNamespace) {
+			Expect(rb.RoleRef.Name).To(Equal(rootNamespaceUserRole.Name))
+			groups = append(groups, rb.Subjects[0].Name)
+		}
+		Expect(groups).To(ContainElements("managers", "developers"))
+	})
+
+	When("the groups are already bound", func() {
+		BeforeEach(func() {
+			Expect(binder.BindGroups(ctx, groupRoleMappings)).To(Succeed())
+		})
+
+		It("succeeds", func() {
+			Expect(bindErr).NotTo(HaveOccurred())
+			Expect(groupRoleBindings(cfOrg.Name)).To(HaveLen(1))
+		})
+	})
+
+	When("a mapping is removed", func() {
+		BeforeEach(func() {
+			Expect(binder.BindGroups(ctx, groupRoleMappings)).To(Succeed())
+
+			propagatedBinding := groupRoleBindings(cfOrg.Name)[0].DeepCopy()
+			propagatedBinding.ObjectMeta = metav1.ObjectMeta{
+				Namespace: cfSpace.Name,
+				Name:      propagatedBinding.Name,
+				Labels: map[string]string{
+					repositories.GroupRoleMappingLabel: "true",
+					korifiv1alpha1.PropagatedFromLabel: cfOrg.Name,
+				},
+			}
+			Expect(k8sClient.Create(ctx, propagatedBinding)).To(Succeed())
+
+			groupRoleMappings = []config.GroupRoleMapping{
+				{Group: "developers", Role: "space_developer", Space: cfSpace.Name},
+			}
+		})
+
+		It("unbinds the role of the mapping", func() {
+			Expect(bindErr).NotTo(HaveOccurred())
+			Expect(groupRoleBindings(cfOrg.Name)).To(BeEmpty())
+		})
+
+		It("unbinds the copies of the role propagated to the spaces", func() {
+			Expect(bindErr).NotTo(HaveOccurred())
+
+			bindings := groupRoleBindings(cfSpace.Name)
+			Expect(bindings).To(HaveLen(1))
+			Expect(bindings[0].Subjects[0].Name).To(Equal("developers"))
+		})
+
+		It("no longer makes the group a cf user", func() {
+			Expect(bindErr).NotTo(HaveOccurred())
+
+			var groups []string
+			for _, rb := range groupRoleBindings(rootNamespace) {
+				groups = append(groups, rb.Subjects[0].Name)
+			}
+			Expect(groups).To(ContainElement("developers"))
+			Expect(groups).NotTo(ContainElement("managers"))
+		})
+	})
+
+	When("a binding has been created before bindings were labelled", func() {
+		BeforeEach(func() {
+			Expect(binder.BindGroups(ctx, groupRoleMappings)).To(Succeed())
+
+			binding := groupRoleBindings(cfOrg.Name)[0]
+			Expect(k8s.PatchResource(ctx, k8sClient, &binding, func() {
+				delete(binding.Labels, repositories.GroupRoleMappingLabel)
+			})).To(Succeed())
+		})
+
+		It("labels it", func() {
+			Expect(bindErr).NotTo(HaveOccurred())
+			Expect(groupRoleBindings(cfOrg.Name)[0].Labels).To(HaveKeyWithValue(repositories.GroupRoleMappingLabel, "true"))
+		})
+	})
+
+	When("the role is not mapped", func() {
+		BeforeEach(func() {
+			groupRoleMappings = []config.GroupRoleMapping{
+				{Group: "auditors", Role: "space_auditor", Space: cfSpace.Name},
+			}
+		})
+
+		It("returns an error", func() {
+			Expect(bindErr).To(MatchError(ContainSubstring(`invalid role type: "space_auditor"`)))
+		})
+	})
+})
@@ -11,7 +11,6 @@ import (
 	"code.cloudfoundry.org/korifi/tools/image"
 	"github.com/google/go-containerregistry/pkg/name"
 
-	authv1 "k8s.io/api/authorization/v1"
 	k8sclient "k8s.io/client-go/kubernetes"
 )
 
@@ -32,6 +31,7 @@ type ImageRepository struct {
 	pusher              ImagePusher
 	pushSecretNames     []string
 	pushSecretNamespace string
+	accessCache         *authorization.AccessCache
 }
 
 func NewImageRepository(
@@ -40,6 +40,7 @@ func NewImageRepository(
 	pusher ImagePusher,
 	pushSecretNames []string,
 	pushSecretNamespace string,
+	accessCache *authorization.AccessCache,
 ) *ImageRepository {
 	return &ImageRepository{
 		privilegedK8sClient: privilegedK8sClient,
@@ -47,11 +48,23 @@ func NewImageRepository(
 		pusher:              pusher,
 		pushSecretNames:     pushSecretNames,
 		pushSecretNamespace: pushSecretNamespace,
+		accessCache:         accessCache,
 	}
 }
 
+// UploadPackageBits pushes the package bits to the registry as a source image,
+// tagged with the package GUID
+func (r *ImageRepository) UploadPackageBits(ctx context.Context, authInfo authorization.Info, message UploadPackageBitsMessage) (PackageBitsRecord, error) {
+	imageRef, err := r.UploadSourceImage(ctx, authInfo, message.ImageRef, message.Bits, message.SpaceGUID, message.PackageGUID)
+	if err != nil {
+		return PackageBitsRecord{}, err
+	}
+
+	return PackageBitsRecord{ImageRef: imageRef}, nil
+}
+
 func (r *ImageRepository) UploadSourceImage(ctx context.Context, authInfo authorization.Info, imageRef string, srcReader io.Reader, spaceGUID string, tags ...string) (string, error) {
-	authorized, err := r.canIPatchCFPackage(ctx, authInfo, spaceGUID)
+	authorized, err := canIPatchCFPackage(ctx, r.userClientFactory, r.accessCache, authInfo, spaceGUID)
 	if err != nil {
 		return "", fmt.Errorf("checking auth to upload source image for failed: %w", err)
 	}
@@ -75,26 +88,3 @@ func (r *ImageRepository) UploadSourceImage(ctx context.Context, authInfo author
 
 	return pushedRef, nil
 }
-
-func (r *ImageRepository) canIPatchCFPackage(ctx context.Context, authInfo authorization.Info, spaceGUID string) (bool, error) {
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
-	if err != nil {
-		return false, fmt.Errorf("canIPatchCFPackage: failed to create user k8s client: %w", err)
-	}
-
-	review := authv1.SelfSubjectAccessReview{
-		Spec: authv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authv1.ResourceAttributes{
-				Namespace: spaceGUID,
-				Verb:      "patch",
-				Group:     "korifi.cloudfoundry.org",
-				Resource:  "cfpackages",
-			},
-		},
-	}
-	if err := userClient.Create(ctx, &review); err != nil {
-		return false, fmt.Errorf("canIPatchCFPackage: failed to create self subject access review: %w", apierrors.FromK8sError(err, PackageResourceType))
-	}
-
-	return review.Status.Allowed, nil
-}
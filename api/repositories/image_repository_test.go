@@ -6,6 +6,9 @@ import (
 	"errors"
 	"io"
 	"path/filepath"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
 
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	"code.cloudfoundry.org/korifi/api/repositories"
@@ -15,6 +18,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/cache"
 	k8sclient "k8s.io/client-go/kubernetes"
 )
 
@@ -54,60 +58,90 @@ var _ = Describe("ImageRepository", func() {
 			imagePusher,
 			[]string{"push-secret-name"},
 			rootNamespace,
+			authorization.NewAccessCache(cache.NewExpiring(), time.Minute),
 		)
 	})
 
-	JustBeforeEach(func() {
-		imageRef, uploadErr = imageRepo.UploadSourceImage(context.Background(), authInfo, imageName, imageSource, space.Name, tags...)
-	})
-
-	It("fails with unauthorized error without a valid role in the space", func() {
-		Expect(uploadErr).To(BeAssignableToTypeOf(apierrors.ForbiddenError{}))
-	})
-
-	When("user has role SpaceDeveloper", func() {
-		BeforeEach(func() {
-			createRoleBinding(context.Background(), userName, spaceDeveloperRole.Name, space.Name)
-		})
-
-		It("succeeds", func() {
-			Expect(uploadErr).NotTo(HaveOccurred())
-			Expect(imageRef).To(Equal("my-pushed-image"))
+	Describe("UploadSourceImage", func() {
+		JustBeforeEach(func() {
+			imageRef, uploadErr = imageRepo.UploadSourceImage(context.Background(), authInfo, imageName, imageSource, space.Name, tags...)
 		})
 
-		It("uploads the image to the registry", func() {
-			Expect(imagePusher.PushCallCount()).To(Equal(1))
-			_, creds, actualRef, zipReader, actualTags := imagePusher.PushArgsForCall(0)
-			Expect(creds.Namespace).To(Equal(rootNamespace))
-			Expect(creds.SecretNames).To(ConsistOf("push-secret-name"))
-			Expect(actualRef).To(Equal("my-image"))
-			Expect(zipReader).To(Equal(imageSource))
-			Expect(actualTags).To(Equal(tags))
+		It("fails with unauthorized error without a valid role in the space", func() {
+			Expect(uploadErr).To(BeAssignableToTypeOf(apierrors.ForbiddenError{}))
 		})
 
-		When("the image name is invalid", func() {
+		When("user has role SpaceDeveloper", func() {
 			BeforeEach(func() {
-				imageName = "invAlid-image"
+				createRoleBinding(context.Background(), userName, spaceDeveloperRole.Name, space.Name)
 			})
 
-			It("fails with an easy to understand unprocessible entity error ", func() {
-				var apiError apierrors.UnprocessableEntityError
-				Expect(errors.As(uploadErr, &apiError)).To(BeTrue())
-				Expect(apiError.Detail()).To(Equal(`invalid image ref: "invAlid-image"`))
+			It("succeeds", func() {
+				Expect(uploadErr).NotTo(HaveOccurred())
+				Expect(imageRef).To(Equal("my-pushed-image"))
 			})
-		})
 
-		When("pushing the image fails", func() {
-			BeforeEach(func() {
-				imagePusher.PushReturns("", errors.New("push-error"))
+			It("uploads the image to the registry", func() {
+				Expect(imagePusher.PushCallCount()).To(Equal(1))
+				_, creds, actualRef, zipReader, actualTags := imagePusher.PushArgsForCall(0)
+				Expect(creds.Namespace).To(Equal(rootNamespace))
+				Expect(creds.SecretNames).To(ConsistOf("push-secret-name"))
+				Expect(actualRef).To(Equal("my-image"))
+				Expect(zipReader).To(Equal(imageSource))
+				Expect(actualTags).To(Equal(tags))
+			})
+
+			When("the image name is invalid", func() {
+				BeforeEach(func() {
+					imageName = "invAlid-image"
+				})
+
+				It("fails with an easy to understand unprocessible entity error ", func() {
+					var apiError apierrors.UnprocessableEntityError
+					Expect(errors.As(uploadErr, &apiError)).To(BeTrue())
+					Expect(apiError.Detail()).To(Equal(`invalid image ref: "invAlid-image"`))
+				})
+			})
+
+			When("pushing the image fails", func() {
+				BeforeEach(func() {
+					imagePusher.PushReturns("", errors.New("push-error"))
+				})
+
+				It("fails with a blobstore unavailable error", func() {
+					Expect(uploadErr).To(MatchError(ContainSubstring("push-error")))
+					var apiError apierrors.BlobstoreUnavailableError
+					Expect(errors.As(uploadErr, &apiError)).To(BeTrue())
+					Expect(apiError.Detail()).To(Equal("Error uploading source package to the container registry"))
+				})
 			})
+		})
+	})
+
+	Describe("UploadPackageBits", func() {
+		var bitsRecord repositories.PackageBitsRecord
+
+		BeforeEach(func() {
+			createRoleBinding(context.Background(), userName, spaceDeveloperRole.Name, space.Name)
+		})
 
-			It("fails with a blobstore unavailable error", func() {
-				Expect(uploadErr).To(MatchError(ContainSubstring("push-error")))
-				var apiError apierrors.BlobstoreUnavailableError
-				Expect(errors.As(uploadErr, &apiError)).To(BeTrue())
-				Expect(apiError.Detail()).To(Equal("Error uploading source package to the container registry"))
+		JustBeforeEach(func() {
+			bitsRecord, uploadErr = imageRepo.UploadPackageBits(context.Background(), authInfo, repositories.UploadPackageBitsMessage{
+				PackageGUID: "the-package-guid",
+				SpaceGUID:   space.Name,
+				ImageRef:    imageName,
+				Bits:        imageSource,
 			})
 		})
+
+		It("pushes the bits as an image tagged with the package guid", func() {
+			Expect(uploadErr).NotTo(HaveOccurred())
+			Expect(bitsRecord).To(Equal(repositories.PackageBitsRecord{ImageRef: "my-pushed-image"}))
+
+			Expect(imagePusher.PushCallCount()).To(Equal(1))
+			_, _, actualRef, _, actualTags := imagePusher.PushArgsForCall(0)
+			Expect(actualRef).To(Equal("my-image"))
+			Expect(actualTags).To(ConsistOf("the-package-guid"))
+		})
 	})
 })
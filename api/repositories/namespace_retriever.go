@@ -6,14 +6,19 @@ import (
 	"strings"
 
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfapps;cfbuilds;cfpackages;cfprocesses;cfspaces;cftasks,verbs=list
-//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfdomains;cfroutes,verbs=list
-//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfservicebindings;cfserviceinstances,verbs=list
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfapps;cfbuilds;cfcrontasks;cfpackages;cfprocesses;cfspaces;cftasks,verbs=list;watch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfdomains;cfroutes,verbs=list;watch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfservicebindings;cfserviceinstances,verbs=list;watch
+
+const guidIndex = "guid"
 
 var (
 	CFAppsGVR = schema.GroupVersionResource{
@@ -82,6 +87,12 @@ var (
 		Resource: "cftasks",
 	}
 
+	CFCronTasksGVR = schema.GroupVersionResource{
+		Group:    "korifi.cloudfoundry.org",
+		Version:  "v1alpha1",
+		Resource: "cfcrontasks",
+	}
+
 	ResourceMap = map[string]schema.GroupVersionResource{
 		AppResourceType:             CFAppsGVR,
 		BuildResourceType:           CFBuildsGVR,
@@ -94,11 +105,14 @@ var (
 		ServiceInstanceResourceType: CFServiceInstancesGVR,
 		SpaceResourceType:           CFSpacesGVR,
 		TaskResourceType:            CFTasksGVR,
+		CronTaskResourceType:        CFCronTasksGVR,
 	}
 )
 
 type NamespaceRetriever struct {
 	client dynamic.Interface
+	index  client.Reader
+	kinds  map[schema.GroupVersionResource]schema.GroupVersionKind
 }
 
 func NewNamespaceRetriever(client dynamic.Interface) NamespaceRetriever {
@@ -107,12 +121,59 @@ func NewNamespaceRetriever(client dynamic.Interface) NamespaceRetriever {
 	}
 }
 
+// NewIndexedNamespaceRetriever returns a NamespaceRetriever that looks up
+// namespaces in an index of the resource metadata kept by the informer cache,
+// rather than listing the resources across all namespaces. It falls back to
+// listing for resources that are not in the cache yet. The index must be
+// created before the cache is started.
+func NewIndexedNamespaceRetriever(
+	ctx context.Context,
+	dynamicClient dynamic.Interface,
+	informerCache cache.Cache,
+	mapper meta.RESTMapper,
+) (NamespaceRetriever, error) {
+	kinds := map[schema.GroupVersionResource]schema.GroupVersionKind{}
+	for _, gvr := range ResourceMap {
+		if _, ok := kinds[gvr]; ok {
+			continue
+		}
+
+		gvk, err := mapper.KindFor(gvr)
+		if err != nil {
+			return NamespaceRetriever{}, fmt.Errorf("failed to get the kind of %v: %w", gvr, err)
+		}
+
+		obj := &metav1.PartialObjectMetadata{}
+		obj.SetGroupVersionKind(gvk)
+		if err := informerCache.IndexField(ctx, obj, guidIndex, func(o client.Object) []string {
+			return []string{o.GetName()}
+		}); err != nil {
+			return NamespaceRetriever{}, fmt.Errorf("failed to index %v by guid: %w", gvr, err)
+		}
+
+		kinds[gvr] = gvk
+	}
+
+	return NamespaceRetriever{
+		client: dynamicClient,
+		index:  informerCache,
+		kinds:  kinds,
+	}, nil
+}
+
 func (nr NamespaceRetriever) NamespaceFor(ctx context.Context, resourceGUID, resourceType string) (string, error) {
 	gvr, ok := ResourceMap[resourceType]
 	if !ok {
 		return "", fmt.Errorf("resource type %q unknown", resourceType)
 	}
 
+	if nr.index != nil {
+		ns, found, err := nr.indexedNamespaceFor(ctx, resourceGUID, resourceType, gvr)
+		if err != nil || found {
+			return ns, err
+		}
+	}
+
 	opts := metav1.ListOptions{
 		FieldSelector: fmt.Sprintf("metadata.name=%s", resourceGUID),
 	}
@@ -140,3 +201,23 @@ func (nr NamespaceRetriever) NamespaceFor(ctx context.Context, resourceGUID, res
 
 	return ns, nil
 }
+
+func (nr NamespaceRetriever) indexedNamespaceFor(ctx context.Context, resourceGUID, resourceType string, gvr schema.GroupVersionResource) (string, bool, error) {
+	list := &metav1.PartialObjectMetadataList{}
+	list.SetGroupVersionKind(nr.kinds[gvr].GroupVersion().WithKind(nr.kinds[gvr].Kind + "List"))
+	if err := nr.index.List(ctx, list, client.MatchingFields{guidIndex: resourceGUID}); err != nil {
+		return "", false, fmt.Errorf("failed to list %v: %w", resourceType, err)
+	}
+
+	switch len(list.Items) {
+	case 0:
+		return "", false, nil
+	case 1:
+		if list.Items[0].Namespace == "" {
+			return "", false, fmt.Errorf("get-%s: resource is not namespace-scoped", strings.ToLower(resourceType))
+		}
+		return list.Items[0].Namespace, true, nil
+	default:
+		return "", false, fmt.Errorf("get-%s duplicate records exist", strings.ToLower(resourceType))
+	}
+}
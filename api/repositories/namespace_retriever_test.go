@@ -1,11 +1,18 @@
 package repositories_test
 
 import (
+	"context"
+
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	"code.cloudfoundry.org/korifi/api/repositories"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
 var _ = Describe("NamespaceRetriever", func() {
@@ -68,3 +75,57 @@ var _ = Describe("NamespaceRetriever", func() {
 		})
 	})
 })
+
+var _ = Describe("Indexed NamespaceRetriever", func() {
+	var (
+		indexedRetriever repositories.NamespaceRetriever
+		appGUID          string
+		spaceGUID        string
+		cacheCancel      context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		httpClient, err := rest.HTTPClientFor(testEnv.Config)
+		Expect(err).NotTo(HaveOccurred())
+		mapper, err := apiutil.NewDynamicRESTMapper(testEnv.Config, httpClient)
+		Expect(err).NotTo(HaveOccurred())
+		dynamicClient, err := dynamic.NewForConfig(testEnv.Config)
+		Expect(err).NotTo(HaveOccurred())
+
+		informerCache, err := ctrlcache.New(testEnv.Config, ctrlcache.Options{Scheme: scheme.Scheme, Mapper: mapper})
+		Expect(err).NotTo(HaveOccurred())
+		indexedRetriever, err = repositories.NewIndexedNamespaceRetriever(ctx, dynamicClient, informerCache, mapper)
+		Expect(err).NotTo(HaveOccurred())
+
+		var cacheCtx context.Context
+		cacheCtx, cacheCancel = context.WithCancel(ctx)
+		go func() {
+			defer GinkgoRecover()
+			Expect(informerCache.Start(cacheCtx)).To(Succeed())
+		}()
+		Expect(informerCache.WaitForCacheSync(ctx)).To(BeTrue())
+
+		appGUID = prefixedGUID("app")
+		org := createOrgWithCleanup(ctx, prefixedGUID("org"))
+		space := createSpaceWithCleanup(ctx, org.Name, prefixedGUID("space"))
+		spaceGUID = space.Name
+		_ = createAppCR(ctx, k8sClient, "app1", appGUID, space.Name, "STOPPED")
+	})
+
+	AfterEach(func() {
+		cacheCancel()
+	})
+
+	It("returns the namespace for a unique GUID", func() {
+		Eventually(func(g Gomega) {
+			ns, err := indexedRetriever.NamespaceFor(ctx, appGUID, repositories.AppResourceType)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(ns).To(Equal(spaceGUID))
+		}).Should(Succeed())
+	})
+
+	It("returns a not found error for guids that do not exist", func() {
+		_, err := indexedRetriever.NamespaceFor(ctx, "does-not-exist", repositories.AppResourceType)
+		Expect(err).To(BeAssignableToTypeOf(apierrors.NotFoundError{}))
+	})
+})
@@ -10,7 +10,6 @@ import (
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/tools"
-	"code.cloudfoundry.org/korifi/tools/k8s"
 
 	"github.com/BooleanCat/go-functional/v2/it"
 	"github.com/BooleanCat/go-functional/v2/it/itx"
@@ -166,7 +165,7 @@ func (r *OrgRepo) DeleteOrg(ctx context.Context, info authorization.Info, messag
 		},
 	})
 
-	return apierrors.FromK8sError(err, OrgResourceType)
+	return r.nsPerms.ForbiddenOrNotFound(ctx, info, message.GUID, apierrors.FromK8sError(err, OrgResourceType))
 }
 
 func (r *OrgRepo) PatchOrgMetadata(ctx context.Context, authInfo authorization.Info, message PatchOrgMetadataMessage) (OrgRecord, error) {
@@ -175,17 +174,17 @@ func (r *OrgRepo) PatchOrgMetadata(ctx context.Context, authInfo authorization.I
 		return OrgRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
 
-	cfOrg := new(korifiv1alpha1.CFOrg)
-	err = userClient.Get(ctx, client.ObjectKey{Namespace: r.rootNamespace, Name: message.GUID}, cfOrg)
-	if err != nil {
-		return OrgRecord{}, fmt.Errorf("failed to get org: %w", apierrors.FromK8sError(err, OrgResourceType))
+	cfOrg := &korifiv1alpha1.CFOrg{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: r.rootNamespace,
+			Name:      message.GUID,
+		},
 	}
-
-	err = k8s.PatchResource(ctx, userClient, cfOrg, func() {
+	err = PatchResource(ctx, userClient, cfOrg, func() {
 		message.Apply(cfOrg)
 	})
 	if err != nil {
-		return OrgRecord{}, apierrors.FromK8sError(err, OrgResourceType)
+		return OrgRecord{}, r.nsPerms.ForbiddenOrNotFound(ctx, authInfo, message.GUID, apierrors.FromK8sError(err, OrgResourceType))
 	}
 
 	return cfOrgToOrgRecord(*cfOrg), nil
@@ -452,21 +452,34 @@ var _ = Describe("OrgRepository", func() {
 			})
 		})
 
-		When("the user does not have permission to delete orgs", func() {
-			It("errors with forbidden", func() {
+		When("the user does not have a role in the org", func() {
+			It("errors with not found", func() {
 				err := orgRepo.DeleteOrg(ctx, authInfo, repositories.DeleteOrgMessage{
 					GUID: cfOrg.Name,
 				})
-				Expect(err).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+				Expect(err).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
 			})
 
 			When("the org doesn't exist", func() {
-				It("errors with forbidden", func() {
+				It("errors with not found", func() {
 					err := orgRepo.DeleteOrg(ctx, authInfo, repositories.DeleteOrgMessage{
 						GUID: "non-existent-org",
 					})
-					Expect(err).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+					Expect(err).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+				})
+			})
+		})
+
+		When("the user can see the org but does not have permission to delete it", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, orgUserRole.Name, cfOrg.Name)
+			})
+
+			It("errors with forbidden", func() {
+				err := orgRepo.DeleteOrg(ctx, authInfo, repositories.DeleteOrgMessage{
+					GUID: cfOrg.Name,
 				})
+				Expect(err).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
 			})
 		})
 	})
@@ -671,8 +684,8 @@ var _ = Describe("OrgRepository", func() {
 		})
 
 		When("the user is not authorized", func() {
-			It("return a forbidden error", func() {
-				Expect(patchErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			It("returns a not found error", func() {
+				Expect(patchErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
 			})
 		})
 	})
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	authv1 "k8s.io/api/authorization/v1"
+)
+
+type UploadPackageBitsMessage struct {
+	PackageGUID string
+	SpaceGUID   string
+	// ImageRef is the repository the bits are pushed to when they are
+	// stored in the registry
+	ImageRef string
+	Bits     io.Reader
+}
+
+// PackageBitsRecord describes where uploaded package bits are stored. Only
+// one of its fields is set, depending on the blobstore the bits went to.
+type PackageBitsRecord struct {
+	ImageRef string
+	BlobURL  string
+}
+
+func canIPatchCFPackage(
+	ctx context.Context,
+	userClientFactory authorization.UserK8sClientFactory,
+	accessCache *authorization.AccessCache,
+	authInfo authorization.Info,
+	spaceGUID string,
+) (bool, error) {
+	access := authorization.Access{
+		Namespace: spaceGUID,
+		Verb:      "patch",
+		Group:     "korifi.cloudfoundry.org",
+		Resource:  "cfpackages",
+	}
+	if allowed, ok := accessCache.Get(authInfo.Hash(), access); ok {
+		return allowed, nil
+	}
+
+	userClient, err := userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return false, fmt.Errorf("canIPatchCFPackage: failed to create user k8s client: %w", err)
+	}
+
+	review := authv1.SelfSubjectAccessReview{
+		Spec: authv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Namespace: access.Namespace,
+				Verb:      access.Verb,
+				Group:     access.Group,
+				Resource:  access.Resource,
+			},
+		},
+	}
+	if err := userClient.Create(ctx, &review); err != nil {
+		return false, fmt.Errorf("canIPatchCFPackage: failed to create self subject access review: %w", apierrors.FromK8sError(err, PackageResourceType))
+	}
+
+	accessCache.Set(authInfo.Hash(), access, review.Status.Allowed)
+	return review.Status.Allowed, nil
+}
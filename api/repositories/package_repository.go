@@ -15,7 +15,6 @@ import (
 	"code.cloudfoundry.org/korifi/tools/k8s"
 
 	"github.com/BooleanCat/go-functional/v2/it"
-	"github.com/BooleanCat/go-functional/v2/it/itx"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/uuid"
 	corev1 "k8s.io/api/core/v1"
@@ -47,7 +46,7 @@ type PackageRepo struct {
 	namespaceRetriever   NamespaceRetriever
 	namespacePermissions *authorization.NamespacePermissions
 	repositoryCreator    RepositoryCreator
-	repositoryPrefix     string
+	repositoryNamer      PackageRepositoryNamer
 	awaiter              Awaiter[*korifiv1alpha1.CFPackage]
 }
 
@@ -56,7 +55,7 @@ func NewPackageRepo(
 	namespaceRetriever NamespaceRetriever,
 	authPerms *authorization.NamespacePermissions,
 	repositoryCreator RepositoryCreator,
-	repositoryPrefix string,
+	repositoryNamer PackageRepositoryNamer,
 	awaiter Awaiter[*korifiv1alpha1.CFPackage],
 ) *PackageRepo {
 	return &PackageRepo{
@@ -64,7 +63,7 @@ func NewPackageRepo(
 		namespaceRetriever:   namespaceRetriever,
 		namespacePermissions: authPerms,
 		repositoryCreator:    repositoryCreator,
-		repositoryPrefix:     repositoryPrefix,
+		repositoryNamer:      repositoryNamer,
 		awaiter:              awaiter,
 	}
 }
@@ -81,6 +80,8 @@ type PackageRecord struct {
 	Labels      map[string]string
 	Annotations map[string]string
 	ImageRef    string
+	// Checksum is the hex encoded sha256 digest of the uploaded bits
+	Checksum string
 }
 
 func (r PackageRecord) Relationships() map[string]string {
@@ -169,6 +170,11 @@ type UpdatePackageSourceMessage struct {
 	SpaceGUID           string
 	ImageRef            string
 	RegistrySecretNames []string
+	// BlobURL is set instead of the ImageRef when the bits are stored in a
+	// blobstore other than the registry
+	BlobURL string
+	// Checksum is the hex encoded sha256 digest of the uploaded bits
+	Checksum string
 }
 
 func (r *PackageRepo) CreatePackage(ctx context.Context, authInfo authorization.Info, message CreatePackageMessage) (PackageRecord, error) {
@@ -206,7 +212,13 @@ func (r *PackageRepo) CreatePackage(ctx context.Context, authInfo authorization.
 	}
 
 	if cfPackage.Spec.Type == "bits" {
-		err = r.repositoryCreator.CreateRepository(ctx, r.repositoryRef(*cfPackage))
+		var repositoryRef string
+		repositoryRef, err = r.repositoryRef(ctx, *cfPackage)
+		if err != nil {
+			return PackageRecord{}, err
+		}
+
+		err = r.repositoryCreator.CreateRepository(ctx, repositoryRef)
 		if err != nil {
 			return PackageRecord{}, fmt.Errorf("failed to create package repository: %w", err)
 		}
@@ -224,7 +236,7 @@ func (r *PackageRepo) CreatePackage(ctx context.Context, authInfo authorization.
 		return PackageRecord{}, fmt.Errorf("failed waiting for Initialized condition: %w", err)
 	}
 
-	return r.cfPackageToPackageRecord(*cfPackage), nil
+	return r.cfPackageToPackageRecord(ctx, *cfPackage)
 }
 
 func isPrivateDockerImage(message CreatePackageMessage) bool {
@@ -283,21 +295,21 @@ func (r *PackageRepo) UpdatePackage(ctx context.Context, authInfo authorization.
 		return PackageRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
 
-	cfPackage := &korifiv1alpha1.CFPackage{}
-
-	err = userClient.Get(ctx, client.ObjectKey{Namespace: ns, Name: updateMessage.GUID}, cfPackage)
-	if err != nil {
-		return PackageRecord{}, fmt.Errorf("failed to get package: %w", apierrors.ForbiddenAsNotFound(apierrors.FromK8sError(err, PackageResourceType)))
+	cfPackage := &korifiv1alpha1.CFPackage{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      updateMessage.GUID,
+		},
 	}
-
-	err = k8s.PatchResource(ctx, userClient, cfPackage, func() {
+	err = PatchResource(ctx, userClient, cfPackage, func() {
 		updateMessage.MetadataPatch.Apply(cfPackage)
 	})
 	if err != nil {
-		return PackageRecord{}, fmt.Errorf("failed to patch package metadata: %w", apierrors.FromK8sError(err, PackageResourceType))
+		return PackageRecord{}, fmt.Errorf("failed to patch package metadata: %w",
+			r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, ns, apierrors.FromK8sError(err, PackageResourceType)))
 	}
 
-	return r.cfPackageToPackageRecord(*cfPackage), nil
+	return r.cfPackageToPackageRecord(ctx, *cfPackage)
 }
 
 func (r *PackageRepo) GetPackage(ctx context.Context, authInfo authorization.Info, guid string) (PackageRecord, error) {
@@ -316,7 +328,7 @@ func (r *PackageRepo) GetPackage(ctx context.Context, authInfo authorization.Inf
 		return PackageRecord{}, fmt.Errorf("failed to get package %q: %w", guid, apierrors.FromK8sError(err, PackageResourceType))
 	}
 
-	return r.cfPackageToPackageRecord(*cfPackage), nil
+	return r.cfPackageToPackageRecord(ctx, *cfPackage)
 }
 
 func (r *PackageRepo) ListPackages(ctx context.Context, authInfo authorization.Info, message ListPackagesMessage) ([]PackageRecord, error) {
@@ -332,18 +344,28 @@ func (r *PackageRepo) ListPackages(ctx context.Context, authInfo authorization.I
 	packages := []korifiv1alpha1.CFPackage{}
 	for ns := range nsList {
 		packageList := &korifiv1alpha1.CFPackageList{}
-		err = userClient.List(ctx, packageList, client.InNamespace(ns))
+		nsPackages, err := listFiltered(ctx, userClient, packageList, func() []korifiv1alpha1.CFPackage {
+			return packageList.Items
+		}, message.matches, client.InNamespace(ns))
 		if k8serrors.IsForbidden(err) {
 			continue
 		}
 		if err != nil {
 			return []PackageRecord{}, fmt.Errorf("failed to list packages in namespace %s: %w", ns, apierrors.FromK8sError(err, PackageResourceType))
 		}
-		packages = append(packages, packageList.Items...)
+		packages = append(packages, nsPackages...)
+	}
+
+	records := make([]PackageRecord, 0, len(packages))
+	for _, cfPackage := range packages {
+		record, err := r.cfPackageToPackageRecord(ctx, cfPackage)
+		if err != nil {
+			return []PackageRecord{}, err
+		}
+		records = append(records, record)
 	}
 
-	filteredPackages := itx.FromSlice(packages).Filter(message.matches)
-	return slices.Collect(it.Map(filteredPackages, r.cfPackageToPackageRecord)), nil
+	return records, nil
 }
 
 func (r *PackageRepo) UpdatePackageSource(ctx context.Context, authInfo authorization.Info, message UpdatePackageSourceMessage) (PackageRecord, error) {
@@ -354,10 +376,23 @@ func (r *PackageRepo) UpdatePackageSource(ctx context.Context, authInfo authoriz
 
 	cfPackage := &korifiv1alpha1.CFPackage{}
 	if err = userClient.Get(ctx, client.ObjectKey{Name: message.GUID, Namespace: message.SpaceGUID}, cfPackage); err != nil {
-		return PackageRecord{}, fmt.Errorf("failed to get cf package: %w", apierrors.FromK8sError(err, PackageResourceType))
+		return PackageRecord{}, fmt.Errorf("failed to get cf package: %w",
+			r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, message.SpaceGUID, apierrors.FromK8sError(err, PackageResourceType)))
 	}
 
 	if err = k8s.PatchResource(ctx, userClient, cfPackage, func() {
+		if message.Checksum != "" {
+			cfPackage.Spec.Source.Checksum = &korifiv1alpha1.Checksum{
+				Type:  korifiv1alpha1.SHA256ChecksumType,
+				Value: message.Checksum,
+			}
+		}
+
+		if message.BlobURL != "" {
+			cfPackage.Spec.Source.Blob = &korifiv1alpha1.Blob{URL: message.BlobURL}
+			return
+		}
+
 		cfPackage.Spec.Source.Registry.Image = message.ImageRef
 		cfPackage.Spec.Source.Registry.ImagePullSecrets = slices.Collect(
 			it.Map(slices.Values(message.RegistrySecretNames), func(secret string) corev1.LocalObjectReference {
@@ -365,7 +400,8 @@ func (r *PackageRepo) UpdatePackageSource(ctx context.Context, authInfo authoriz
 			}),
 		)
 	}); err != nil {
-		return PackageRecord{}, fmt.Errorf("failed to update package source: %w", apierrors.FromK8sError(err, PackageResourceType))
+		return PackageRecord{}, fmt.Errorf("failed to update package source: %w",
+			r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, message.SpaceGUID, apierrors.FromK8sError(err, PackageResourceType)))
 	}
 
 	cfPackage, err = r.awaiter.AwaitCondition(ctx, userClient, cfPackage, korifiv1alpha1.StatusConditionReady)
@@ -373,11 +409,15 @@ func (r *PackageRepo) UpdatePackageSource(ctx context.Context, authInfo authoriz
 		return PackageRecord{}, fmt.Errorf("failed awaiting Ready status condition: %w", err)
 	}
 
-	record := r.cfPackageToPackageRecord(*cfPackage)
-	return record, nil
+	return r.cfPackageToPackageRecord(ctx, *cfPackage)
 }
 
-func (r *PackageRepo) cfPackageToPackageRecord(cfPackage korifiv1alpha1.CFPackage) PackageRecord {
+func (r *PackageRepo) cfPackageToPackageRecord(ctx context.Context, cfPackage korifiv1alpha1.CFPackage) (PackageRecord, error) {
+	imageRef, err := r.repositoryRef(ctx, cfPackage)
+	if err != nil {
+		return PackageRecord{}, err
+	}
+
 	state := PackageStateAwaitingUpload
 	if meta.IsStatusConditionTrue(cfPackage.Status.Conditions, korifiv1alpha1.StatusConditionReady) {
 		state = PackageStateReady
@@ -393,14 +433,28 @@ func (r *PackageRepo) cfPackageToPackageRecord(cfPackage korifiv1alpha1.CFPackag
 		UpdatedAt:   getLastUpdatedTime(&cfPackage),
 		Labels:      cfPackage.Labels,
 		Annotations: cfPackage.Annotations,
-		ImageRef:    r.repositoryRef(cfPackage),
+		ImageRef:    imageRef,
+		Checksum:    packageChecksum(cfPackage),
+	}, nil
+}
+
+func packageChecksum(cfPackage korifiv1alpha1.CFPackage) string {
+	if cfPackage.Status.Checksum == nil {
+		return ""
 	}
+
+	return cfPackage.Status.Checksum.Value
 }
 
-func (r *PackageRepo) repositoryRef(cfPackage korifiv1alpha1.CFPackage) string {
+func (r *PackageRepo) repositoryRef(ctx context.Context, cfPackage korifiv1alpha1.CFPackage) (string, error) {
 	if cfPackage.Spec.Type == "docker" {
-		return cfPackage.Spec.Source.Registry.Image
+		return cfPackage.Spec.Source.Registry.Image, nil
+	}
+
+	repositoryRef, err := r.repositoryNamer.PackagesRepository(ctx, cfPackage.Namespace, cfPackage.Spec.AppRef.Name)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute the package repository: %w", err)
 	}
 
-	return r.repositoryPrefix + cfPackage.Spec.AppRef.Name + "-packages"
+	return repositoryRef, nil
 }
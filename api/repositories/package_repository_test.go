@@ -14,6 +14,7 @@ import (
 	"code.cloudfoundry.org/korifi/tests/matchers"
 	"code.cloudfoundry.org/korifi/tools"
 	"code.cloudfoundry.org/korifi/tools/k8s"
+	"code.cloudfoundry.org/korifi/tools/registry"
 
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
@@ -50,12 +51,14 @@ var _ = Describe("PackageRepository", func() {
 			korifiv1alpha1.CFPackageList,
 			*korifiv1alpha1.CFPackageList,
 		]{}
+		repoNamer, err := registry.NewRepositoryNamer(k8sClient, "container.registry/foo/my/prefix-", registry.DefaultRepositoryTemplate)
+		Expect(err).NotTo(HaveOccurred())
 		packageRepo = repositories.NewPackageRepo(
 			userClientFactory,
 			namespaceRetriever,
 			nsPerms,
 			repoCreator,
-			"container.registry/foo/my/prefix-",
+			repoNamer,
 			conditionAwaiter,
 		)
 		org = createOrgWithCleanup(ctx, prefixedGUID("org"))
@@ -759,9 +762,48 @@ var _ = Describe("PackageRepository", func() {
 					})
 				})
 			})
+
+			When("the bits are stored in a blobstore", func() {
+				BeforeEach(func() {
+					updateMessage.ImageRef = ""
+					updateMessage.BlobURL = "https://blobstore.example.org/packages/the-package-guid.zip"
+				})
+
+				It("sets the blob source on the package", func() {
+					Expect(updateErr).NotTo(HaveOccurred())
+					Expect(updatedCFPackage.Spec.Source.Blob).To(Equal(&korifiv1alpha1.Blob{
+						URL: "https://blobstore.example.org/packages/the-package-guid.zip",
+					}))
+					Expect(updatedCFPackage.Spec.Source.Registry.Image).To(BeEmpty())
+				})
+			})
+
+			When("the checksum of the bits is given", func() {
+				BeforeEach(func() {
+					updateMessage.Checksum = "the-sha256"
+				})
+
+				It("sets the checksum on the package source", func() {
+					Expect(updateErr).NotTo(HaveOccurred())
+					Expect(updatedCFPackage.Spec.Source.Checksum).To(Equal(&korifiv1alpha1.Checksum{
+						Type:  "sha256",
+						Value: "the-sha256",
+					}))
+				})
+			})
+		})
+
+		When("user is not authorized in the space", func() {
+			It("returns a not found error", func() {
+				Expect(updateErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+			})
 		})
 
-		When("user is not authorized to update a package", func() {
+		When("the user is authorized read-only in the space", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceManagerRole.Name, space.Name)
+			})
+
 			It("returns a forbidden error", func() {
 				Expect(updateErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
 			})
@@ -6,6 +6,8 @@ import (
 
 	"code.cloudfoundry.org/korifi/tools/k8s"
 	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -15,13 +17,28 @@ func PatchResource[T any, PT k8s.ObjectWithDeepCopy[T]](
 	obj PT,
 	modify func(),
 ) error {
-	err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)
-	if err != nil {
-		return fmt.Errorf("failed to get %T %v: %w", obj, client.ObjectKeyFromObject(obj), err)
-	}
+	key := client.ObjectKeyFromObject(obj)
 
-	return errors.Wrapf(
-		k8s.PatchResource(ctx, k8sClient, obj, modify),
-		"failed to patch %T %v", obj, client.ObjectKeyFromObject(obj),
-	)
+	return retryOnConflict(func() error {
+		// Start from scratch on every attempt so that modifications of a
+		// previous, conflicting attempt do not leak into this one
+		*obj = *new(T)
+
+		err := k8sClient.Get(ctx, key, obj)
+		if err != nil {
+			return fmt.Errorf("failed to get %T %v: %w", obj, key, err)
+		}
+
+		return errors.Wrapf(
+			k8s.PatchResourceWithOptimisticLock(ctx, k8sClient, obj, modify),
+			"failed to patch %T %v", obj, key,
+		)
+	})
+}
+
+// retryOnConflict runs fn again with an exponential, jittered backoff for as
+// long as it fails with a conflict, so that the read-modify-write operations
+// of the repositories are not failed by concurrent updates of the resource
+func retryOnConflict(fn func() error) error {
+	return retry.OnError(retry.DefaultBackoff, k8serrors.IsConflict, fn)
 }
@@ -10,12 +10,10 @@ import (
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/tools"
-	"code.cloudfoundry.org/korifi/tools/k8s"
 	"github.com/BooleanCat/go-functional/v2/it"
 	"github.com/BooleanCat/go-functional/v2/it/itx"
 
 	corev1 "k8s.io/api/core/v1"
-	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -37,19 +35,21 @@ type ProcessRepo struct {
 }
 
 type ProcessRecord struct {
-	GUID             string
-	SpaceGUID        string
-	AppGUID          string
-	Type             string
-	Command          string
-	DesiredInstances int32
-	MemoryMB         int64
-	DiskQuotaMB      int64
-	HealthCheck      HealthCheck
-	Labels           map[string]string
-	Annotations      map[string]string
-	CreatedAt        time.Time
-	UpdatedAt        *time.Time
+	GUID                 string
+	SpaceGUID            string
+	AppGUID              string
+	Type                 string
+	Command              string
+	DesiredInstances     int32
+	MemoryMB             int64
+	DiskQuotaMB          int64
+	HealthCheck          HealthCheck
+	ReadinessHealthCheck ReadinessHealthCheck
+	Sidecars             []Sidecar
+	Labels               map[string]string
+	Annotations          map[string]string
+	CreatedAt            time.Time
+	UpdatedAt            *time.Time
 }
 
 func (r ProcessRecord) Relationships() map[string]string {
@@ -73,6 +73,23 @@ type HealthCheckData struct {
 	TimeoutSeconds           int32
 }
 
+type ReadinessHealthCheck struct {
+	Type string
+	Data ReadinessHealthCheckData
+}
+
+type ReadinessHealthCheckData struct {
+	HTTPEndpoint             string
+	InvocationTimeoutSeconds int32
+	IntervalSeconds          int32
+}
+
+type Sidecar struct {
+	Name     string
+	Command  string
+	MemoryMB int64
+}
+
 type ScaleProcessMessage struct {
 	GUID      string
 	SpaceGUID string
@@ -86,28 +103,35 @@ type ProcessScaleValues struct {
 }
 
 type CreateProcessMessage struct {
-	AppGUID          string
-	SpaceGUID        string
-	Type             string
-	Command          string
-	DiskQuotaMB      int64
-	HealthCheck      HealthCheck
-	DesiredInstances *int32
-	MemoryMB         int64
+	AppGUID              string
+	SpaceGUID            string
+	Type                 string
+	Command              string
+	DiskQuotaMB          int64
+	HealthCheck          HealthCheck
+	ReadinessHealthCheck ReadinessHealthCheck
+	DesiredInstances     *int32
+	MemoryMB             int64
+	Sidecars             []Sidecar
 }
 
 type PatchProcessMessage struct {
-	SpaceGUID                           string
-	ProcessGUID                         string
-	Command                             *string
-	DiskQuotaMB                         *int64
-	HealthCheckHTTPEndpoint             *string
-	HealthCheckInvocationTimeoutSeconds *int32
-	HealthCheckTimeoutSeconds           *int32
-	HealthCheckType                     *string
-	DesiredInstances                    *int32
-	MemoryMB                            *int64
-	MetadataPatch                       *MetadataPatch
+	SpaceGUID                                    string
+	ProcessGUID                                  string
+	Command                                      *string
+	DiskQuotaMB                                  *int64
+	HealthCheckHTTPEndpoint                      *string
+	HealthCheckInvocationTimeoutSeconds          *int32
+	HealthCheckTimeoutSeconds                    *int32
+	HealthCheckType                              *string
+	ReadinessHealthCheckHTTPEndpoint             *string
+	ReadinessHealthCheckInvocationTimeoutSeconds *int32
+	ReadinessHealthCheckIntervalSeconds          *int32
+	ReadinessHealthCheckType                     *string
+	DesiredInstances                             *int32
+	MemoryMB                                     *int64
+	Sidecars                                     *[]Sidecar
+	MetadataPatch                                *MetadataPatch
 }
 
 type ListProcessesMessage struct {
@@ -159,19 +183,14 @@ func (r *ProcessRepo) ListProcesses(ctx context.Context, authInfo authorization.
 		return nil, fmt.Errorf("failed to list namespaces for spaces with user role bindings: %w", err)
 	}
 
-	processes := []korifiv1alpha1.CFProcess{}
 	nsList := authorisedSpaceNamespacesIter.Filter(message.matchesNamespace).Collect()
-	for _, ns := range nsList {
+	processes, err := listInNamespaces(ctx, nsList, func(ctx context.Context, ns string) ([]korifiv1alpha1.CFProcess, error) {
 		processList := &korifiv1alpha1.CFProcessList{}
-		err = userClient.List(ctx, processList, client.InNamespace(ns))
-		if k8serrors.IsForbidden(err) {
-			continue
-		}
-		if err != nil {
-			return nil, fmt.Errorf("failed to list pods: %w", apierrors.FromK8sError(err, PodResourceType))
-		}
-
-		processes = append(processes, processList.Items...)
+		err := userClient.List(ctx, processList, client.InNamespace(ns))
+		return processList.Items, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", apierrors.FromK8sError(err, ProcessResourceType))
 	}
 
 	filteredProcesses := itx.FromSlice(processes).Filter(message.matches)
@@ -190,7 +209,7 @@ func (r *ProcessRepo) ScaleProcess(ctx context.Context, authInfo authorization.I
 			Namespace: scaleProcessMessage.SpaceGUID,
 		},
 	}
-	err = k8s.PatchResource(ctx, userClient, cfProcess, func() {
+	err = PatchResource(ctx, userClient, cfProcess, func() {
 		if scaleProcessMessage.Instances != nil {
 			cfProcess.Spec.DesiredInstances = scaleProcessMessage.Instances
 		}
@@ -202,7 +221,8 @@ func (r *ProcessRepo) ScaleProcess(ctx context.Context, authInfo authorization.I
 		}
 	})
 	if err != nil {
-		return ProcessRecord{}, fmt.Errorf("failed to scale process %q: %w", scaleProcessMessage.GUID, apierrors.FromK8sError(err, ProcessResourceType))
+		return ProcessRecord{}, fmt.Errorf("failed to scale process %q: %w", scaleProcessMessage.GUID,
+			r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, scaleProcessMessage.SpaceGUID, apierrors.FromK8sError(err, ProcessResourceType)))
 	}
 
 	return cfProcessToProcessRecord(*cfProcess), nil
@@ -226,9 +246,14 @@ func (r *ProcessRepo) CreateProcess(ctx context.Context, authInfo authorization.
 				Type: korifiv1alpha1.HealthCheckType(message.HealthCheck.Type),
 				Data: korifiv1alpha1.HealthCheckData(message.HealthCheck.Data),
 			},
+			ReadinessHealthCheck: korifiv1alpha1.ReadinessHealthCheck{
+				Type: korifiv1alpha1.HealthCheckType(message.ReadinessHealthCheck.Type),
+				Data: korifiv1alpha1.ReadinessHealthCheckData(message.ReadinessHealthCheck.Data),
+			},
 			DesiredInstances: message.DesiredInstances,
 			MemoryMB:         message.MemoryMB,
 			DiskQuotaMB:      message.DiskQuotaMB,
+			Sidecars:         toCFSidecars(message.Sidecars),
 		},
 	}
 	process.SetStableName(message.AppGUID)
@@ -273,7 +298,7 @@ func (r *ProcessRepo) PatchProcess(ctx context.Context, authInfo authorization.I
 			Namespace: message.SpaceGUID,
 		},
 	}
-	err = k8s.PatchResource(ctx, userClient, updatedProcess, func() {
+	err = PatchResource(ctx, userClient, updatedProcess, func() {
 		if message.Command != nil {
 			updatedProcess.Spec.Command = *message.Command
 		}
@@ -287,8 +312,11 @@ func (r *ProcessRepo) PatchProcess(ctx context.Context, authInfo authorization.I
 			updatedProcess.Spec.DiskQuotaMB = *message.DiskQuotaMB
 		}
 		if message.HealthCheckType != nil {
-			// TODO: how do we handle when the type changes? Clear the HTTPEndpoint when type != http? Should we require the endpoint when type == http?
 			updatedProcess.Spec.HealthCheck.Type = korifiv1alpha1.HealthCheckType(*message.HealthCheckType)
+			// only http health checks have an endpoint
+			if updatedProcess.Spec.HealthCheck.Type != korifiv1alpha1.HTTPHealthCheckType {
+				updatedProcess.Spec.HealthCheck.Data.HTTPEndpoint = ""
+			}
 		}
 		if message.HealthCheckHTTPEndpoint != nil {
 			updatedProcess.Spec.HealthCheck.Data.HTTPEndpoint = *message.HealthCheckHTTPEndpoint
@@ -299,12 +327,27 @@ func (r *ProcessRepo) PatchProcess(ctx context.Context, authInfo authorization.I
 		if message.HealthCheckTimeoutSeconds != nil {
 			updatedProcess.Spec.HealthCheck.Data.TimeoutSeconds = *message.HealthCheckTimeoutSeconds
 		}
+		if message.ReadinessHealthCheckType != nil {
+			updatedProcess.Spec.ReadinessHealthCheck.Type = korifiv1alpha1.HealthCheckType(*message.ReadinessHealthCheckType)
+		}
+		if message.ReadinessHealthCheckHTTPEndpoint != nil {
+			updatedProcess.Spec.ReadinessHealthCheck.Data.HTTPEndpoint = *message.ReadinessHealthCheckHTTPEndpoint
+		}
+		if message.ReadinessHealthCheckInvocationTimeoutSeconds != nil {
+			updatedProcess.Spec.ReadinessHealthCheck.Data.InvocationTimeoutSeconds = *message.ReadinessHealthCheckInvocationTimeoutSeconds
+		}
+		if message.ReadinessHealthCheckIntervalSeconds != nil {
+			updatedProcess.Spec.ReadinessHealthCheck.Data.IntervalSeconds = *message.ReadinessHealthCheckIntervalSeconds
+		}
+		if message.Sidecars != nil {
+			updatedProcess.Spec.Sidecars = toCFSidecars(*message.Sidecars)
+		}
 		if message.MetadataPatch != nil {
 			message.MetadataPatch.Apply(updatedProcess)
 		}
 	})
 	if err != nil {
-		return ProcessRecord{}, apierrors.FromK8sError(err, ProcessResourceType)
+		return ProcessRecord{}, r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, message.SpaceGUID, apierrors.FromK8sError(err, ProcessResourceType))
 	}
 
 	return cfProcessToProcessRecord(*updatedProcess), nil
@@ -333,9 +376,43 @@ func cfProcessToProcessRecord(cfProcess korifiv1alpha1.CFProcess) ProcessRecord
 				TimeoutSeconds:           cfProcess.Spec.HealthCheck.Data.TimeoutSeconds,
 			},
 		},
-		Labels:      cfProcess.Labels,
-		Annotations: cfProcess.Annotations,
-		CreatedAt:   cfProcess.CreationTimestamp.Time,
-		UpdatedAt:   getLastUpdatedTime(&cfProcess),
+		ReadinessHealthCheck: toReadinessHealthCheck(cfProcess.Spec),
+		Sidecars:             toSidecars(cfProcess.Spec.Sidecars),
+		Labels:               cfProcess.Labels,
+		Annotations:          cfProcess.Annotations,
+		CreatedAt:            cfProcess.CreationTimestamp.Time,
+		UpdatedAt:            getLastUpdatedTime(&cfProcess),
 	}
 }
+
+// toReadinessHealthCheck returns the health check the readiness of the process
+// instances is checked with, which is the process health check unless the
+// process has a readiness health check of its own
+func toReadinessHealthCheck(processSpec korifiv1alpha1.CFProcessSpec) ReadinessHealthCheck {
+	if processSpec.ReadinessHealthCheck.Type == "" {
+		return ReadinessHealthCheck{
+			Type: string(processSpec.HealthCheck.Type),
+			Data: ReadinessHealthCheckData{
+				HTTPEndpoint:             processSpec.HealthCheck.Data.HTTPEndpoint,
+				InvocationTimeoutSeconds: processSpec.HealthCheck.Data.InvocationTimeoutSeconds,
+			},
+		}
+	}
+
+	return ReadinessHealthCheck{
+		Type: string(processSpec.ReadinessHealthCheck.Type),
+		Data: ReadinessHealthCheckData(processSpec.ReadinessHealthCheck.Data),
+	}
+}
+
+func toSidecars(cfSidecars []korifiv1alpha1.Sidecar) []Sidecar {
+	return slices.Collect(it.Map(slices.Values(cfSidecars), func(sidecar korifiv1alpha1.Sidecar) Sidecar {
+		return Sidecar(sidecar)
+	}))
+}
+
+func toCFSidecars(sidecars []Sidecar) []korifiv1alpha1.Sidecar {
+	return slices.Collect(it.Map(slices.Values(sidecars), func(sidecar Sidecar) korifiv1alpha1.Sidecar {
+		return korifiv1alpha1.Sidecar(sidecar)
+	}))
+}
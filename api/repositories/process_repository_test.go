@@ -9,6 +9,7 @@ import (
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/tests/matchers"
 	"code.cloudfoundry.org/korifi/tools"
+	"code.cloudfoundry.org/korifi/tools/k8s"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -84,6 +85,42 @@ var _ = Describe("ProcessRepo", func() {
 					"app": app1GUID,
 				}))
 			})
+
+			It("returns the health check as the readiness health check", func() {
+				Expect(processRecord.ReadinessHealthCheck).To(Equal(repositories.ReadinessHealthCheck{
+					Type: string(cfProcess1.Spec.HealthCheck.Type),
+					Data: repositories.ReadinessHealthCheckData{
+						HTTPEndpoint:             cfProcess1.Spec.HealthCheck.Data.HTTPEndpoint,
+						InvocationTimeoutSeconds: cfProcess1.Spec.HealthCheck.Data.InvocationTimeoutSeconds,
+					},
+				}))
+			})
+
+			When("the process has a readiness health check", func() {
+				BeforeEach(func() {
+					Expect(k8s.PatchResource(ctx, k8sClient, cfProcess1, func() {
+						cfProcess1.Spec.ReadinessHealthCheck = korifiv1alpha1.ReadinessHealthCheck{
+							Type: "http",
+							Data: korifiv1alpha1.ReadinessHealthCheckData{
+								HTTPEndpoint:             "/ready",
+								InvocationTimeoutSeconds: 2,
+								IntervalSeconds:          5,
+							},
+						}
+					})).To(Succeed())
+				})
+
+				It("returns the readiness health check", func() {
+					Expect(processRecord.ReadinessHealthCheck).To(Equal(repositories.ReadinessHealthCheck{
+						Type: "http",
+						Data: repositories.ReadinessHealthCheckData{
+							HTTPEndpoint:             "/ready",
+							InvocationTimeoutSeconds: 2,
+							IntervalSeconds:          5,
+						},
+					}))
+				})
+			})
 		})
 
 		When("the privileged list call fails", func() {
@@ -241,9 +278,20 @@ var _ = Describe("ProcessRepo", func() {
 			memoryScaleMB = 900
 		})
 
-		It("returns a forbidden error to unauthorized users", func() {
+		It("returns a not found error to users that cannot see the space", func() {
 			_, err := processRepo.ScaleProcess(ctx, authInfo, *scaleProcessMessage)
-			Expect(err).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			Expect(err).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+		})
+
+		When("the user has the SpaceManager role", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceManagerRole.Name, space1.Name)
+			})
+
+			It("returns a forbidden error", func() {
+				_, err := processRepo.ScaleProcess(ctx, authInfo, *scaleProcessMessage)
+				Expect(err).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			})
 		})
 
 		When("the user has the SpaceDeveloper role", func() {
@@ -346,8 +394,19 @@ var _ = Describe("ProcessRepo", func() {
 						TimeoutSeconds:           10,
 					},
 				},
+				ReadinessHealthCheck: repositories.ReadinessHealthCheck{
+					Type: "http",
+					Data: repositories.ReadinessHealthCheckData{
+						HTTPEndpoint:             "/ready",
+						InvocationTimeoutSeconds: 2,
+						IntervalSeconds:          5,
+					},
+				},
 				DesiredInstances: tools.PtrTo[int32](42),
 				MemoryMB:         456,
+				Sidecars: []repositories.Sidecar{
+					{Name: "agent", Command: "run-agent", MemoryMB: 64},
+				},
 			})
 		})
 
@@ -377,9 +436,20 @@ var _ = Describe("ProcessRepo", func() {
 							TimeoutSeconds:           10,
 						},
 					},
+					ReadinessHealthCheck: korifiv1alpha1.ReadinessHealthCheck{
+						Type: "http",
+						Data: korifiv1alpha1.ReadinessHealthCheckData{
+							HTTPEndpoint:             "/ready",
+							InvocationTimeoutSeconds: 2,
+							IntervalSeconds:          5,
+						},
+					},
 					DesiredInstances: tools.PtrTo[int32](42),
 					MemoryMB:         456,
 					DiskQuotaMB:      123,
+					Sidecars: []korifiv1alpha1.Sidecar{
+						{Name: "agent", Command: "run-agent", MemoryMB: 64},
+					},
 				}))
 			})
 		})
@@ -501,9 +571,9 @@ var _ = Describe("ProcessRepo", func() {
 					}
 				})
 
-				It("returns a forbidden error to unauthorized users", func() {
+				It("returns a not found error to users that cannot see the space", func() {
 					_, err := processRepo.PatchProcess(ctx, authInfo, message)
-					Expect(err).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+					Expect(err).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
 				})
 			})
 
@@ -523,9 +593,16 @@ var _ = Describe("ProcessRepo", func() {
 							HealthCheckHTTPEndpoint:             tools.PtrTo("/healthz"),
 							HealthCheckInvocationTimeoutSeconds: tools.PtrTo(int32(20)),
 							HealthCheckTimeoutSeconds:           tools.PtrTo(int32(10)),
-							DesiredInstances:                    tools.PtrTo[int32](42),
-							MemoryMB:                            tools.PtrTo(int64(456)),
-							DiskQuotaMB:                         tools.PtrTo(int64(123)),
+							ReadinessHealthCheckType:            tools.PtrTo("http"),
+							ReadinessHealthCheckHTTPEndpoint:    tools.PtrTo("/ready"),
+							ReadinessHealthCheckInvocationTimeoutSeconds: tools.PtrTo(int32(2)),
+							ReadinessHealthCheckIntervalSeconds:          tools.PtrTo(int32(5)),
+							DesiredInstances:                             tools.PtrTo[int32](42),
+							MemoryMB:                                     tools.PtrTo(int64(456)),
+							DiskQuotaMB:                                  tools.PtrTo(int64(123)),
+							Sidecars: &[]repositories.Sidecar{
+								{Name: "agent", Command: "run-agent", MemoryMB: 64},
+							},
 							MetadataPatch: &repositories.MetadataPatch{
 								Labels:      map[string]*string{"foo": &barValue},
 								Annotations: map[string]*string{"foo": &barValue},
@@ -543,9 +620,18 @@ var _ = Describe("ProcessRepo", func() {
 						Expect(updatedProcessRecord.HealthCheck.Data.HTTPEndpoint).To(Equal(*message.HealthCheckHTTPEndpoint))
 						Expect(updatedProcessRecord.HealthCheck.Data.TimeoutSeconds).To(Equal(*message.HealthCheckTimeoutSeconds))
 						Expect(updatedProcessRecord.HealthCheck.Data.InvocationTimeoutSeconds).To(Equal(*message.HealthCheckInvocationTimeoutSeconds))
+						Expect(updatedProcessRecord.ReadinessHealthCheck).To(Equal(repositories.ReadinessHealthCheck{
+							Type: "http",
+							Data: repositories.ReadinessHealthCheckData{
+								HTTPEndpoint:             "/ready",
+								InvocationTimeoutSeconds: 2,
+								IntervalSeconds:          5,
+							},
+						}))
 						Expect(updatedProcessRecord.DesiredInstances).To(Equal(*message.DesiredInstances))
 						Expect(updatedProcessRecord.MemoryMB).To(Equal(*message.MemoryMB))
 						Expect(updatedProcessRecord.DiskQuotaMB).To(Equal(*message.DiskQuotaMB))
+						Expect(updatedProcessRecord.Sidecars).To(Equal(*message.Sidecars))
 						Expect(updatedProcessRecord.Labels).To(HaveKey("foo"))
 						Expect(updatedProcessRecord.Annotations).To(HaveKey("foo"))
 
@@ -563,9 +649,20 @@ var _ = Describe("ProcessRepo", func() {
 									TimeoutSeconds:           10,
 								},
 							},
+							ReadinessHealthCheck: korifiv1alpha1.ReadinessHealthCheck{
+								Type: "http",
+								Data: korifiv1alpha1.ReadinessHealthCheckData{
+									HTTPEndpoint:             "/ready",
+									InvocationTimeoutSeconds: 2,
+									IntervalSeconds:          5,
+								},
+							},
 							DesiredInstances: tools.PtrTo[int32](42),
 							MemoryMB:         456,
 							DiskQuotaMB:      123,
+							Sidecars: []korifiv1alpha1.Sidecar{
+								{Name: "agent", Command: "run-agent", MemoryMB: 64},
+							},
 						}))
 						Expect(process.Labels).To(HaveKey("foo"))
 						Expect(process.Annotations).To(HaveKey("foo"))
@@ -618,6 +715,52 @@ var _ = Describe("ProcessRepo", func() {
 						}))
 					})
 				})
+
+				When("the command is reset", func() {
+					BeforeEach(func() {
+						Expect(k8s.PatchResource(ctx, k8sClient, cfProcess, func() {
+							cfProcess.Spec.DetectedCommand = "detected-command"
+						})).To(Succeed())
+
+						message = repositories.PatchProcessMessage{
+							ProcessGUID: process1GUID,
+							SpaceGUID:   space.Name,
+							Command:     tools.PtrTo(""),
+						}
+					})
+
+					It("uses the detected command", func() {
+						updatedProcessRecord, err := processRepo.PatchProcess(ctx, authInfo, message)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(updatedProcessRecord.Command).To(Equal("detected-command"))
+
+						var process korifiv1alpha1.CFProcess
+						Expect(k8sClient.Get(ctx, types.NamespacedName{Name: process1GUID, Namespace: space.Name}, &process)).To(Succeed())
+						Expect(process.Spec.Command).To(BeEmpty())
+					})
+				})
+
+				When("the health check type changes from http", func() {
+					BeforeEach(func() {
+						Expect(k8s.PatchResource(ctx, k8sClient, cfProcess, func() {
+							cfProcess.Spec.HealthCheck.Type = korifiv1alpha1.HTTPHealthCheckType
+							cfProcess.Spec.HealthCheck.Data.HTTPEndpoint = "/healthz"
+						})).To(Succeed())
+
+						message = repositories.PatchProcessMessage{
+							ProcessGUID:     process1GUID,
+							SpaceGUID:       space.Name,
+							HealthCheckType: tools.PtrTo("port"),
+						}
+					})
+
+					It("clears the health check endpoint", func() {
+						updatedProcessRecord, err := processRepo.PatchProcess(ctx, authInfo, message)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(updatedProcessRecord.HealthCheck.Type).To(Equal("port"))
+						Expect(updatedProcessRecord.HealthCheck.Data.HTTPEndpoint).To(BeEmpty())
+					})
+				})
 			})
 		})
 	})
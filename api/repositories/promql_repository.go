@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	PromQLResultTypeVector = "vector"
+	PromQLResultTypeMatrix = "matrix"
+)
+
+type PromQLQueryMessage struct {
+	Query string
+	Time  string
+}
+
+type PromQLRangeQueryMessage struct {
+	Query string
+	Start string
+	End   string
+	Step  string
+}
+
+type PromQLRecord struct {
+	Status string     `json:"status"`
+	Data   PromQLData `json:"data"`
+	Error  string     `json:"error,omitempty"`
+}
+
+type PromQLData struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+// PromQLRepo forwards log-cache PromQL queries to the cluster Prometheus.
+// Prometheus is expected to label app container metrics with the app GUID
+// as `source_id`, which is what log-cache clients select by. When no
+// Prometheus URL is configured every query yields an empty result.
+type PromQLRepo struct {
+	prometheusURL string
+	httpClient    *http.Client
+}
+
+func NewPromQLRepo(prometheusURL string, httpClient *http.Client) *PromQLRepo {
+	return &PromQLRepo{
+		prometheusURL: strings.TrimSuffix(prometheusURL, "/"),
+		httpClient:    httpClient,
+	}
+}
+
+func (r *PromQLRepo) Query(ctx context.Context, message PromQLQueryMessage) (PromQLRecord, error) {
+	params := url.Values{"query": []string{message.Query}}
+	if message.Time != "" {
+		params.Set("time", message.Time)
+	}
+
+	return r.query(ctx, "/api/v1/query", params, PromQLResultTypeVector)
+}
+
+func (r *PromQLRepo) QueryRange(ctx context.Context, message PromQLRangeQueryMessage) (PromQLRecord, error) {
+	params := url.Values{
+		"query": []string{message.Query},
+		"start": []string{message.Start},
+		"end":   []string{message.End},
+		"step":  []string{message.Step},
+	}
+
+	return r.query(ctx, "/api/v1/query_range", params, PromQLResultTypeMatrix)
+}
+
+func (r *PromQLRepo) query(ctx context.Context, path string, params url.Values, emptyResultType string) (PromQLRecord, error) {
+	if r.prometheusURL == "" {
+		return PromQLRecord{
+			Status: "success",
+			Data: PromQLData{
+				ResultType: emptyResultType,
+				Result:     json.RawMessage("[]"),
+			},
+		}, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.prometheusURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return PromQLRecord{}, fmt.Errorf("failed to create prometheus request: %w", err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return PromQLRecord{}, fmt.Errorf("prometheus request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return PromQLRecord{}, fmt.Errorf("failed to read prometheus response: %w", err)
+	}
+
+	var record PromQLRecord
+	if err = json.Unmarshal(respBytes, &record); err != nil {
+		return PromQLRecord{}, fmt.Errorf("failed to unmarshal prometheus response (status code %d): %w", resp.StatusCode, err)
+	}
+
+	if resp.StatusCode >= 300 || record.Status != "success" {
+		return PromQLRecord{}, fmt.Errorf("prometheus query failed with status code %d: %s", resp.StatusCode, record.Error)
+	}
+
+	return record, nil
+}
@@ -0,0 +1,144 @@
+package repositories_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PromQLRepo", func() {
+	var (
+		repo             *repositories.PromQLRepo
+		prometheusServer *httptest.Server
+		prometheusURL    string
+		requestedPath    string
+		requestedQuery   url.Values
+		responseStatus   int
+		responseBody     string
+	)
+
+	BeforeEach(func() {
+		responseStatus = http.StatusOK
+		responseBody = `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"source_id":"app-guid"},"value":[1,"0.5"]}]}}`
+
+		prometheusServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestedPath = r.URL.Path
+			requestedQuery = r.URL.Query()
+			w.WriteHeader(responseStatus)
+			_, _ = w.Write([]byte(responseBody))
+		}))
+		DeferCleanup(prometheusServer.Close)
+
+		prometheusURL = prometheusServer.URL + "/"
+	})
+
+	JustBeforeEach(func() {
+		repo = repositories.NewPromQLRepo(prometheusURL, prometheusServer.Client())
+	})
+
+	Describe("Query", func() {
+		var (
+			record   repositories.PromQLRecord
+			queryErr error
+		)
+
+		JustBeforeEach(func() {
+			record, queryErr = repo.Query(ctx, repositories.PromQLQueryMessage{
+				Query: `cpu{source_id="app-guid"}`,
+				Time:  "123",
+			})
+		})
+
+		It("forwards the query to prometheus", func() {
+			Expect(queryErr).NotTo(HaveOccurred())
+			Expect(requestedPath).To(Equal("/api/v1/query"))
+			Expect(requestedQuery.Get("query")).To(Equal(`cpu{source_id="app-guid"}`))
+			Expect(requestedQuery.Get("time")).To(Equal("123"))
+
+			Expect(record.Status).To(Equal("success"))
+			Expect(record.Data.ResultType).To(Equal("vector"))
+			Expect(record.Data.Result).To(MatchJSON(`[{"metric":{"source_id":"app-guid"},"value":[1,"0.5"]}]`))
+		})
+
+		When("prometheus returns an error", func() {
+			BeforeEach(func() {
+				responseStatus = http.StatusBadRequest
+				responseBody = `{"status":"error","errorType":"bad_data","error":"parse error"}`
+			})
+
+			It("returns an error", func() {
+				Expect(queryErr).To(MatchError(ContainSubstring("parse error")))
+			})
+		})
+
+		When("prometheus returns invalid json", func() {
+			BeforeEach(func() {
+				responseBody = "not-json"
+			})
+
+			It("returns an error", func() {
+				Expect(queryErr).To(MatchError(ContainSubstring("failed to unmarshal")))
+			})
+		})
+
+		When("the prometheus URL is not configured", func() {
+			BeforeEach(func() {
+				prometheusURL = ""
+			})
+
+			It("returns an empty vector", func() {
+				Expect(queryErr).NotTo(HaveOccurred())
+				Expect(requestedPath).To(BeEmpty())
+				Expect(record.Status).To(Equal("success"))
+				Expect(record.Data.ResultType).To(Equal("vector"))
+				Expect(record.Data.Result).To(MatchJSON(`[]`))
+			})
+		})
+	})
+
+	Describe("QueryRange", func() {
+		var (
+			record   repositories.PromQLRecord
+			queryErr error
+		)
+
+		BeforeEach(func() {
+			responseBody = `{"status":"success","data":{"resultType":"matrix","result":[]}}`
+		})
+
+		JustBeforeEach(func() {
+			record, queryErr = repo.QueryRange(ctx, repositories.PromQLRangeQueryMessage{
+				Query: `memory{source_id="app-guid"}`,
+				Start: "1",
+				End:   "2",
+				Step:  "3s",
+			})
+		})
+
+		It("forwards the range query to prometheus", func() {
+			Expect(queryErr).NotTo(HaveOccurred())
+			Expect(requestedPath).To(Equal("/api/v1/query_range"))
+			Expect(requestedQuery.Get("query")).To(Equal(`memory{source_id="app-guid"}`))
+			Expect(requestedQuery.Get("start")).To(Equal("1"))
+			Expect(requestedQuery.Get("end")).To(Equal("2"))
+			Expect(requestedQuery.Get("step")).To(Equal("3s"))
+			Expect(record.Data.ResultType).To(Equal("matrix"))
+		})
+
+		When("the prometheus URL is not configured", func() {
+			BeforeEach(func() {
+				prometheusURL = ""
+			})
+
+			It("returns an empty matrix", func() {
+				Expect(queryErr).NotTo(HaveOccurred())
+				Expect(json.Marshal(record)).To(MatchJSON(`{"status":"success","data":{"resultType":"matrix","result":[]}}`))
+			})
+		})
+	})
+})
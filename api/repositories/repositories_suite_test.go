@@ -63,6 +63,8 @@ var (
 	orgManagerRole        *rbacv1.ClusterRole
 	orgUserRole           *rbacv1.ClusterRole
 	spaceAuditorRole      *rbacv1.ClusterRole
+	spaceSupporterRole    *rbacv1.ClusterRole
+	orgBillingManagerRole *rbacv1.ClusterRole
 	rootNamespaceUserRole *rbacv1.ClusterRole
 )
 
@@ -102,6 +104,8 @@ var _ = BeforeSuite(func() {
 	spaceDeveloperRole = createClusterRole(context.Background(), "cf_space_developer")
 	spaceManagerRole = createClusterRole(context.Background(), "cf_space_manager")
 	spaceAuditorRole = createClusterRole(context.Background(), "cf_space_auditor")
+	spaceSupporterRole = createClusterRole(context.Background(), "cf_space_supporter")
+	orgBillingManagerRole = createClusterRole(context.Background(), "cf_org_billing_manager")
 	rootNamespaceUserRole = createClusterRole(context.Background(), "cf_root_namespace_user")
 })
 
@@ -120,8 +124,8 @@ var _ = BeforeEach(func() {
 	tokenInspector := authorization.NewTokenReviewer(k8sClient)
 	certInspector := authorization.NewCertInspector(testEnv.Config)
 	baseIDProvider := authorization.NewCertTokenIdentityProvider(tokenInspector, certInspector)
-	idProvider = authorization.NewCachingIdentityProvider(baseIDProvider, cache.NewExpiring())
-	nsPerms = authorization.NewNamespacePermissions(k8sClient, idProvider)
+	idProvider = authorization.NewCachingIdentityProvider(baseIDProvider, cache.NewExpiring(), time.Minute)
+	nsPerms = authorization.NewNamespacePermissions(k8sClient, idProvider, authorization.NewAccessCache(cache.NewExpiring(), 0))
 
 	httpClient, err := rest.HTTPClientFor(testEnv.Config)
 	Expect(err).NotTo(HaveOccurred())
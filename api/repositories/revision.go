@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"code.cloudfoundry.org/korifi/tools"
+)
+
+const RevisionResourceType = "Revision"
+
+// Korifi does not keep a separate history of app revisions. Instead, every
+// staged droplet of an app is one of its revisions, identified by the droplet
+// GUID. The revision versions are counted per app when the droplets are
+// staged, so that they do not shift when older droplets are cleaned up.
+// Rolling back to a revision is therefore a deployment of its droplet.
+type RevisionRecord struct {
+	GUID         string
+	Version      int
+	AppGUID      string
+	DropletGUID  string
+	ProcessTypes map[string]string
+	Deployed     bool
+	CreatedAt    time.Time
+	UpdatedAt    *time.Time
+}
+
+func (r RevisionRecord) Relationships() map[string]string {
+	return map[string]string{
+		"app": r.AppGUID,
+	}
+}
+
+type ListRevisionsMessage struct {
+	Versions []int
+	Deployed bool
+}
+
+func (m *ListRevisionsMessage) matches(r RevisionRecord) bool {
+	return tools.EmptyOrContains(m.Versions, r.Version) &&
+		(!m.Deployed || r.Deployed)
+}
+
+// AppRevisions returns the revisions of an app given its droplets and its
+// current droplet, which is the deployed revision. Droplets that have not been
+// numbered yet are not revisions yet.
+func AppRevisions(droplets []DropletRecord, currentDropletGUID string, message ListRevisionsMessage) []RevisionRecord {
+	droplets = slices.SortedStableFunc(slices.Values(droplets), func(a, b DropletRecord) int {
+		return cmp.Compare(a.RevisionVersion, b.RevisionVersion)
+	})
+
+	revisions := []RevisionRecord{}
+	for _, droplet := range droplets {
+		if droplet.RevisionVersion == 0 {
+			continue
+		}
+
+		revision := RevisionRecord{
+			GUID:         droplet.GUID,
+			Version:      droplet.RevisionVersion,
+			AppGUID:      droplet.AppGUID,
+			DropletGUID:  droplet.GUID,
+			ProcessTypes: droplet.ProcessTypes,
+			Deployed:     droplet.GUID == currentDropletGUID,
+			CreatedAt:    droplet.CreatedAt,
+			UpdatedAt:    droplet.UpdatedAt,
+		}
+
+		if message.matches(revision) {
+			revisions = append(revisions, revision)
+		}
+	}
+
+	return revisions
+}
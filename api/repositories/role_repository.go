@@ -86,12 +86,13 @@ func (r RoleRecord) GetResourceType() string {
 
 type RoleRepo struct {
 	rootNamespace        string
-	roleMappings         map[string]config.Role
+	roleMappings         *config.Reloadable[map[string]config.Role]
 	authorizedInChecker  AuthorizedInChecker
 	namespacePermissions *authorization.NamespacePermissions
 	userClientFactory    authorization.UserK8sClientFactory
 	spaceRepo            *SpaceRepo
 	namespaceRetriever   NamespaceRetriever
+	accessCache          *authorization.AccessCache
 }
 
 func NewRoleRepo(
@@ -100,8 +101,9 @@ func NewRoleRepo(
 	authorizedInChecker AuthorizedInChecker,
 	namespacePermissions *authorization.NamespacePermissions,
 	rootNamespace string,
-	roleMappings map[string]config.Role,
+	roleMappings *config.Reloadable[map[string]config.Role],
 	namespaceRetriever NamespaceRetriever,
+	accessCache *authorization.AccessCache,
 ) *RoleRepo {
 	return &RoleRepo{
 		rootNamespace:        rootNamespace,
@@ -111,6 +113,7 @@ func NewRoleRepo(
 		userClientFactory:    userClientFactory,
 		spaceRepo:            spaceRepo,
 		namespaceRetriever:   namespaceRetriever,
+		accessCache:          accessCache,
 	}
 }
 
@@ -120,7 +123,7 @@ func (r *RoleRepo) CreateRole(ctx context.Context, authInfo authorization.Info,
 		return RoleRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
 
-	k8sRoleConfig, ok := r.roleMappings[role.Type]
+	k8sRoleConfig, ok := r.roleMappings.Get()[role.Type]
 	if !ok {
 		return RoleRecord{}, fmt.Errorf("invalid role type: %q", role.Type)
 	}
@@ -131,7 +134,7 @@ func (r *RoleRepo) CreateRole(ctx context.Context, authInfo authorization.Info,
 	}
 
 	if role.Kind == rbacv1.ServiceAccountKind {
-		userIdentity.Name = fmt.Sprintf("system:serviceaccount:%s:%s", role.ServiceAccountNamespace, role.User)
+		userIdentity.Name = authorization.ServiceAccountUserName(role.ServiceAccountNamespace, role.User)
 	}
 
 	if role.Space != "" {
@@ -159,7 +162,7 @@ func (r *RoleRepo) CreateRole(ctx context.Context, authInfo authorization.Info,
 		return RoleRecord{}, fmt.Errorf("failed to assign user %q to role %q: %w", role.User, role.Type, apierrors.FromK8sError(err, RoleResourceType))
 	}
 
-	cfUserk8sRoleConfig, ok := r.roleMappings[cfUserRoleType]
+	cfUserk8sRoleConfig, ok := r.roleMappings.Get()[cfUserRoleType]
 	if !ok {
 		return RoleRecord{}, fmt.Errorf("invalid role type: %q", cfUserRoleType)
 	}
@@ -172,6 +175,8 @@ func (r *RoleRepo) CreateRole(ctx context.Context, authInfo authorization.Info,
 		}
 	}
 
+	r.accessCache.Invalidate()
+
 	roleRecord := RoleRecord{
 		GUID:      role.GUID,
 		CreatedAt: roleBinding.CreationTimestamp.Time,
@@ -179,7 +184,7 @@ func (r *RoleRepo) CreateRole(ctx context.Context, authInfo authorization.Info,
 		Type:      role.Type,
 		Space:     role.Space,
 		Org:       role.Org,
-		User:      role.User,
+		User:      userIdentity.Name,
 		Kind:      role.Kind,
 	}
 
@@ -279,11 +284,18 @@ func (r *RoleRepo) ListRoles(ctx context.Context, authInfo authorization.Info) (
 
 func (r *RoleRepo) isCFRole(rb rbacv1.RoleBinding) bool {
 	return rb.Labels[korifiv1alpha1.PropagatedFromLabel] == "" &&
+		!isGroupRoleBinding(rb) &&
 		slices.Contains(r.getCFRoleNames(), rb.RoleRef.Name)
 }
 
+// Group role bindings are managed from the API configuration and have no CF
+// user to relate to, so they are not listed as roles
+func isGroupRoleBinding(rb rbacv1.RoleBinding) bool {
+	return len(rb.Subjects) > 0 && rb.Subjects[0].Kind == rbacv1.GroupKind
+}
+
 func (r *RoleRepo) getCFRoleName(k8sRoleName string) string {
-	for cfRole, k8sRole := range r.roleMappings {
+	for cfRole, k8sRole := range r.roleMappings.Get() {
 		if k8sRole.Name == k8sRoleName {
 			return cfRole
 		}
@@ -292,7 +304,7 @@ func (r *RoleRepo) getCFRoleName(k8sRoleName string) string {
 }
 
 func (r *RoleRepo) getCFRoleNames() []string {
-	return slices.Collect(it.Map(maps.Values(r.roleMappings), func(r config.Role) string {
+	return slices.Collect(it.Map(maps.Values(r.roleMappings.Get()), func(r config.Role) string {
 		return r.Name
 	}))
 }
@@ -309,11 +321,13 @@ func (r *RoleRepo) toRoleRecord(roleBinding rbacv1.RoleBinding) RoleRecord {
 		Kind:      roleBinding.Subjects[0].Kind,
 	}
 
+	// service account users are named like their identities, so that the
+	// roles of an identity can be matched by name
 	if record.Kind == rbacv1.ServiceAccountKind {
-		record.User = fmt.Sprintf("system:serviceaccount:%s:%s", roleBinding.Subjects[0].Namespace, roleBinding.Subjects[0].Name)
+		record.User = authorization.ServiceAccountUserName(roleBinding.Subjects[0].Namespace, roleBinding.Subjects[0].Name)
 	}
 
-	switch r.roleMappings[cfRoleName].Level {
+	switch r.roleMappings.Get()[cfRoleName].Level {
 	case config.OrgRole:
 		record.Org = roleBinding.Namespace
 	case config.SpaceRole:
@@ -362,6 +376,7 @@ func (r *RoleRepo) DeleteRole(ctx context.Context, authInfo authorization.Info,
 		if err != nil {
 			return fmt.Errorf("failed to delete role binding %s/%s: %w", rb.Namespace, rb.Name, apierrors.FromK8sError(err, RoleResourceType))
 		}
+		r.accessCache.Invalidate()
 	default:
 		return fmt.Errorf("multiple role bindings with guid %q found", deleteMsg.GUID)
 	}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"code.cloudfoundry.org/korifi/api/authorization"
 	"code.cloudfoundry.org/korifi/api/config"
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	"code.cloudfoundry.org/korifi/api/repositories"
@@ -22,6 +23,7 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -38,11 +40,13 @@ var _ = Describe("RoleRepository", func() {
 	BeforeEach(func() {
 		authorizedInChecker = new(fake.AuthorizedInChecker)
 		roleMappings := map[string]config.Role{
-			"space_developer":      {Name: spaceDeveloperRole.Name, Level: config.SpaceRole},
-			"organization_manager": {Name: orgManagerRole.Name, Level: config.OrgRole, Propagate: true},
-			"organization_user":    {Name: orgUserRole.Name, Level: config.OrgRole},
-			"cf_user":              {Name: rootNamespaceUserRole.Name},
-			"admin":                {Name: adminRole.Name, Propagate: true},
+			"space_developer":              {Name: spaceDeveloperRole.Name, Level: config.SpaceRole},
+			"space_supporter":              {Name: spaceSupporterRole.Name, Level: config.SpaceRole},
+			"organization_manager":         {Name: orgManagerRole.Name, Level: config.OrgRole, Propagate: true},
+			"organization_user":            {Name: orgUserRole.Name, Level: config.OrgRole},
+			"organization_billing_manager": {Name: orgBillingManagerRole.Name, Level: config.OrgRole},
+			"cf_user":                      {Name: rootNamespaceUserRole.Name},
+			"admin":                        {Name: adminRole.Name, Propagate: true},
 		}
 		orgRepo := repositories.NewOrgRepo(rootNamespace, k8sClient, userClientFactory, nsPerms, &fakeawaiter.FakeAwaiter[
 			*korifiv1alpha1.CFOrg,
@@ -62,8 +66,9 @@ var _ = Describe("RoleRepository", func() {
 			authorizedInChecker,
 			nsPerms,
 			rootNamespace,
-			roleMappings,
+			config.NewReloadable(roleMappings),
 			namespaceRetriever,
+			authorization.NewAccessCache(cache.NewExpiring(), time.Minute),
 		)
 
 		roleCreateMessage = repositories.CreateRoleMessage{}
@@ -191,6 +196,11 @@ var _ = Describe("RoleRepository", func() {
 					Expect(roleBinding.Subjects[0].Kind).To(Equal(rbacv1.ServiceAccountKind))
 					Expect(roleBinding.Subjects[0].Namespace).To(Equal("my-namespace"))
 				})
+
+				It("names the role user like the service account identity", func() {
+					Expect(createErr).NotTo(HaveOccurred())
+					Expect(createdRole.User).To(Equal("system:serviceaccount:my-namespace:my-service-account"))
+				})
 			})
 
 			When("the org does not exist", func() {
@@ -203,6 +213,21 @@ var _ = Describe("RoleRepository", func() {
 				})
 			})
 
+			When("creating an org billing manager role", func() {
+				BeforeEach(func() {
+					roleCreateMessage.Type = "organization_billing_manager"
+				})
+
+				It("binds the org billing manager cluster role in the org namespace", func() {
+					Expect(createErr).NotTo(HaveOccurred())
+					Expect(createdRole.Type).To(Equal("organization_billing_manager"))
+
+					// Sha256 sum of "organization_billing_manager::myuser@example.com"
+					roleBinding := getTheRoleBinding("cf-8a935d6f570688ac3b8a859bf62a0ae59157266197c7221065113554292784c1", cfOrg.Name)
+					Expect(roleBinding.RoleRef.Name).To(Equal(orgBillingManagerRole.Name))
+				})
+			})
+
 			When("the role type is invalid", func() {
 				BeforeEach(func() {
 					roleCreateMessage.Type = "i-am-invalid"
@@ -307,6 +332,21 @@ var _ = Describe("RoleRepository", func() {
 			Expect(createdRole.UpdatedAt).To(PointTo(Equal(createdRole.CreatedAt)))
 		})
 
+		When("creating a space supporter role", func() {
+			BeforeEach(func() {
+				roleCreateMessage.Type = "space_supporter"
+			})
+
+			It("binds the space supporter cluster role in the space namespace", func() {
+				Expect(createErr).NotTo(HaveOccurred())
+				Expect(createdRole.Type).To(Equal("space_supporter"))
+
+				// Sha256 sum of "space_supporter::myuser@example.com"
+				roleBinding := getTheRoleBinding("cf-84022b045125560d3115dd150d18513a3ec220f7a28bf692d6844a59ff373f45", cfSpace.Name)
+				Expect(roleBinding.RoleRef.Name).To(Equal(spaceSupporterRole.Name))
+			})
+		})
+
 		When("using service accounts", func() {
 			BeforeEach(func() {
 				// Sha256 sum of "space_developer::my-namespace/my-service-account"
@@ -56,6 +56,7 @@ type RouteRecord struct {
 	Host         string
 	Path         string
 	Protocol     string
+	Port         *int
 	Destinations []DestinationRecord
 	Labels       map[string]string
 	Annotations  map[string]string
@@ -80,10 +81,9 @@ type DesiredDestination struct {
 }
 
 type AddDestinationsMessage struct {
-	RouteGUID            string
-	SpaceGUID            string
-	ExistingDestinations []DestinationRecord
-	NewDestinations      []DesiredDestination
+	RouteGUID       string
+	SpaceGUID       string
+	NewDestinations []DesiredDestination
 }
 
 type RemoveDestinationMessage struct {
@@ -134,6 +134,7 @@ func (m *ListRoutesMessage) matchesApp(r korifiv1alpha1.CFRoute) bool {
 type CreateRouteMessage struct {
 	Host            string
 	Path            string
+	Port            *int32
 	SpaceGUID       string
 	DomainGUID      string
 	DomainName      string
@@ -148,7 +149,7 @@ type DeleteRouteMessage struct {
 }
 
 func (m CreateRouteMessage) toCFRoute() korifiv1alpha1.CFRoute {
-	return korifiv1alpha1.CFRoute{
+	cfRoute := korifiv1alpha1.CFRoute{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       Kind,
 			APIVersion: APIVersion,
@@ -162,13 +163,20 @@ func (m CreateRouteMessage) toCFRoute() korifiv1alpha1.CFRoute {
 		Spec: korifiv1alpha1.CFRouteSpec{
 			Host:     m.Host,
 			Path:     m.Path,
-			Protocol: "http",
+			Protocol: korifiv1alpha1.ProtocolHTTP,
 			DomainRef: v1.ObjectReference{
 				Name:      m.DomainGUID,
 				Namespace: m.DomainNamespace,
 			},
 		},
 	}
+
+	if m.Port != nil {
+		cfRoute.Spec.Protocol = korifiv1alpha1.ProtocolTCP
+		cfRoute.Spec.Port = *m.Port
+	}
+
+	return cfRoute
 }
 
 func (r *RouteRepo) GetRoute(ctx context.Context, authInfo authorization.Info, routeGUID string) (RouteRecord, error) {
@@ -221,7 +229,7 @@ func (r *RouteRepo) ListRoutes(ctx context.Context, authInfo authorization.Info,
 }
 
 func cfRouteToRouteRecord(cfRoute korifiv1alpha1.CFRoute) RouteRecord {
-	return RouteRecord{
+	record := RouteRecord{
 		GUID:      cfRoute.Name,
 		SpaceGUID: cfRoute.Namespace,
 		Domain: DomainRecord{
@@ -237,6 +245,13 @@ func cfRouteToRouteRecord(cfRoute korifiv1alpha1.CFRoute) RouteRecord {
 		Labels:       cfRoute.Labels,
 		Annotations:  cfRoute.Annotations,
 	}
+
+	if cfRoute.Spec.Protocol == korifiv1alpha1.ProtocolTCP {
+		record.Protocol = string(korifiv1alpha1.ProtocolTCP)
+		record.Port = tools.PtrTo(int(cfRoute.Spec.Port))
+	}
+
+	return record
 }
 
 func cfRouteDestinationsToDestinationRecords(cfRoute korifiv1alpha1.CFRoute) []DestinationRecord {
@@ -305,7 +320,7 @@ func (r *RouteRepo) DeleteRoute(ctx context.Context, authInfo authorization.Info
 		},
 	})
 
-	return apierrors.FromK8sError(err, RouteResourceType)
+	return r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, message.SpaceGUID, apierrors.FromK8sError(err, RouteResourceType))
 }
 
 func (r *RouteRepo) GetOrCreateRoute(ctx context.Context, authInfo authorization.Info, message CreateRouteMessage) (RouteRecord, error) {
@@ -333,11 +348,12 @@ func (r *RouteRepo) AddDestinationsToRoute(ctx context.Context, authInfo authori
 			Namespace: message.SpaceGUID,
 		},
 	}
-	err = k8s.PatchResource(ctx, userClient, cfRoute, func() {
-		cfRoute.Spec.Destinations = mergeDestinations(message.ExistingDestinations, message.NewDestinations)
+	err = PatchResource(ctx, userClient, cfRoute, func() {
+		cfRoute.Spec.Destinations = mergeDestinations(cfRouteDestinationsToDestinationRecords(*cfRoute), message.NewDestinations)
 	})
 	if err != nil {
-		return RouteRecord{}, fmt.Errorf("failed to add destination to route %q: %w", message.RouteGUID, apierrors.FromK8sError(err, RouteResourceType))
+		return RouteRecord{}, fmt.Errorf("failed to add destination to route %q: %w", message.RouteGUID,
+			r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, message.SpaceGUID, apierrors.FromK8sError(err, RouteResourceType)))
 	}
 
 	return cfRouteToRouteRecord(*cfRoute), err
@@ -349,27 +365,28 @@ func (r *RouteRepo) RemoveDestinationFromRoute(ctx context.Context, authInfo aut
 		return RouteRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
 
-	cfRoute := &korifiv1alpha1.CFRoute{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      message.RouteGUID,
-			Namespace: message.SpaceGUID,
-		},
-	}
-	err = userClient.Get(ctx, client.ObjectKeyFromObject(cfRoute), cfRoute)
-	if err != nil {
-		return RouteRecord{}, fmt.Errorf("failed to get route: %w", apierrors.FromK8sError(err, RouteResourceType))
-	}
+	var cfRoute *korifiv1alpha1.CFRoute
+	err = retryOnConflict(func() error {
+		// Read the route afresh on every attempt so that the destinations are
+		// removed from its latest version rather than from a conflicting one
+		cfRoute = new(korifiv1alpha1.CFRoute)
+		err := userClient.Get(ctx, client.ObjectKey{Namespace: message.SpaceGUID, Name: message.RouteGUID}, cfRoute)
+		if err != nil {
+			return fmt.Errorf("failed to get route: %w", err)
+		}
 
-	updatedDestinations := itx.FromSlice(cfRoute.Spec.Destinations).Exclude(message.matches).Collect()
-	if len(updatedDestinations) == len(cfRoute.Spec.Destinations) {
-		return RouteRecord{}, apierrors.NewUnprocessableEntityError(nil, "Unable to unmap route from destination. Ensure the route has a destination with this guid.")
-	}
+		updatedDestinations := itx.FromSlice(cfRoute.Spec.Destinations).Exclude(message.matches).Collect()
+		if len(updatedDestinations) == len(cfRoute.Spec.Destinations) {
+			return apierrors.NewUnprocessableEntityError(nil, "Unable to unmap route from destination. Ensure the route has a destination with this guid.")
+		}
 
-	err = k8s.PatchResource(ctx, userClient, cfRoute, func() {
-		cfRoute.Spec.Destinations = updatedDestinations
+		return k8s.PatchResourceWithOptimisticLock(ctx, userClient, cfRoute, func() {
+			cfRoute.Spec.Destinations = updatedDestinations
+		})
 	})
 	if err != nil {
-		return RouteRecord{}, fmt.Errorf("failed to remove destination from route %q: %w", message.RouteGUID, apierrors.FromK8sError(err, RouteResourceType))
+		return RouteRecord{}, fmt.Errorf("failed to remove destination from route %q: %w", message.RouteGUID,
+			r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, message.SpaceGUID, apierrors.FromK8sError(err, RouteResourceType)))
 	}
 
 	return cfRouteToRouteRecord(*cfRoute), err
@@ -468,16 +485,11 @@ func (r *RouteRepo) PatchRouteMetadata(ctx context.Context, authInfo authorizati
 			Name:      message.RouteGUID,
 		},
 	}
-	err = userClient.Get(ctx, client.ObjectKeyFromObject(route), route)
-	if err != nil {
-		return RouteRecord{}, fmt.Errorf("failed to get route: %w", apierrors.FromK8sError(err, RouteResourceType))
-	}
-
-	err = k8s.PatchResource(ctx, userClient, route, func() {
+	err = PatchResource(ctx, userClient, route, func() {
 		message.Apply(route)
 	})
 	if err != nil {
-		return RouteRecord{}, apierrors.FromK8sError(err, RouteResourceType)
+		return RouteRecord{}, r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, message.SpaceGUID, apierrors.FromK8sError(err, RouteResourceType))
 	}
 
 	return cfRouteToRouteRecord(*route), nil
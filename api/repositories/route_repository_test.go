@@ -140,6 +140,27 @@ var _ = Describe("RouteRepository", func() {
 				Expect(route.Domain).To(Equal(DomainRecord{GUID: domainGUID}))
 			})
 
+			It("does not set a port", func() {
+				Expect(getErr).ToNot(HaveOccurred())
+				Expect(route.Port).To(BeNil())
+			})
+
+			When("the route is a tcp route", func() {
+				BeforeEach(func() {
+					Expect(k8s.PatchResource(ctx, k8sClient, cfRoute, func() {
+						cfRoute.Spec.Host = ""
+						cfRoute.Spec.Protocol = korifiv1alpha1.ProtocolTCP
+						cfRoute.Spec.Port = 1024
+					})).To(Succeed())
+				})
+
+				It("returns the tcp protocol and the route port", func() {
+					Expect(getErr).ToNot(HaveOccurred())
+					Expect(route.Protocol).To(Equal("tcp"))
+					Expect(route.Port).To(PointTo(Equal(1024)))
+				})
+			})
+
 			When("the route destination does not have a port", func() {
 				BeforeEach(func() {
 					Expect(k8s.PatchResource(ctx, k8sClient, cfRoute, func() {
@@ -531,6 +552,7 @@ var _ = Describe("RouteRepository", func() {
 			createdRouteErr    error
 			routeHost          string
 			routePath          string
+			routePort          *int32
 			routeNamespace     string
 		)
 
@@ -538,6 +560,7 @@ var _ = Describe("RouteRepository", func() {
 			routeNamespace = space.Name
 			routeHost = prefixedGUID("route-host-")
 			routePath = prefixedGUID("/test/route/")
+			routePort = nil
 			createdRouteRecord = RouteRecord{}
 			createdRouteErr = nil
 		})
@@ -546,6 +569,7 @@ var _ = Describe("RouteRepository", func() {
 			createdRouteRecord, createdRouteErr = routeRepo.CreateRoute(ctx, authInfo, CreateRouteMessage{
 				Host:            routeHost,
 				Path:            routePath,
+				Port:            routePort,
 				SpaceGUID:       routeNamespace,
 				DomainGUID:      domainGUID,
 				DomainNamespace: rootNamespace,
@@ -586,6 +610,25 @@ var _ = Describe("RouteRepository", func() {
 				Expect(createdRouteRecord.UpdatedAt).To(PointTo(BeTemporally("~", time.Now(), timeCheckThreshold)))
 			})
 
+			When("the route has a port", func() {
+				BeforeEach(func() {
+					routeHost = ""
+					routePath = ""
+					routePort = tools.PtrTo[int32](1024)
+				})
+
+				It("creates a tcp route", func() {
+					Expect(createdRouteErr).NotTo(HaveOccurred())
+					createdCFRoute := new(korifiv1alpha1.CFRoute)
+					Expect(k8sClient.Get(ctx, types.NamespacedName{Name: createdRouteRecord.GUID, Namespace: space.Name}, createdCFRoute)).To(Succeed())
+
+					Expect(createdCFRoute.Spec.Protocol).To(Equal(korifiv1alpha1.ProtocolTCP))
+					Expect(createdCFRoute.Spec.Port).To(BeEquivalentTo(1024))
+					Expect(createdRouteRecord.Protocol).To(Equal("tcp"))
+					Expect(createdRouteRecord.Port).To(PointTo(Equal(1024)))
+				})
+			})
+
 			When("target namespace isn't set", func() {
 				BeforeEach(func() {
 					routeNamespace = ""
@@ -645,8 +688,18 @@ var _ = Describe("RouteRepository", func() {
 			})
 		})
 
-		It("errors with forbidden", func() {
-			Expect(deleteErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+		It("errors with not found", func() {
+			Expect(deleteErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+		})
+
+		When("the user is a space manager", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceManagerRole.Name, space.Name)
+			})
+
+			It("errors with forbidden", func() {
+				Expect(deleteErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			})
 		})
 
 		When("the user has permission to delete routes and", func() {
@@ -915,14 +968,6 @@ var _ = Describe("RouteRepository", func() {
 					Expect(k8s.Patch(ctx, k8sClient, cfRoute, func() {
 						cfRoute.Spec.Destinations = []korifiv1alpha1.Destination{routeDestination}
 					})).To(Succeed())
-
-					addDestinationsMessage.ExistingDestinations = []DestinationRecord{{
-						GUID:        routeDestination.GUID,
-						AppGUID:     routeDestination.AppRef.Name,
-						ProcessType: routeDestination.ProcessType,
-						Port:        routeDestination.Port,
-						Protocol:    routeDestination.Protocol,
-					}}
 				})
 
 				When("the destinations are all new", func() {
@@ -1096,7 +1141,7 @@ var _ = Describe("RouteRepository", func() {
 		})
 
 		It("returns an error as the user is not authorized", func() {
-			Expect(removeDestinationErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			Expect(removeDestinationErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
 
 			cfRoute := &korifiv1alpha1.CFRoute{
 				ObjectMeta: metav1.ObjectMeta{
@@ -1178,8 +1223,8 @@ var _ = Describe("RouteRepository", func() {
 			routeRecord, patchErr = routeRepo.PatchRouteMetadata(ctx, authInfo, patchMsg)
 		})
 
-		It("return a forbidden error as the user is not authorized", func() {
-			Expect(patchErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+		It("returns a not found error as the user is not authorized", func() {
+			Expect(patchErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
 		})
 
 		When("the user is authorized", func() {
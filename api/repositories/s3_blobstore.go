@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/tools/s3"
+)
+
+// S3Blobstore stores package bits as objects in an S3-compatible bucket. The
+// objects are private: builds download them from presigned URLs and they are
+// deleted along with their package, both by the controllers.
+type S3Blobstore struct {
+	s3Client          *s3.Client
+	userClientFactory authorization.UserK8sClientFactory
+	accessCache       *authorization.AccessCache
+}
+
+func NewS3Blobstore(
+	s3Client *s3.Client,
+	userClientFactory authorization.UserK8sClientFactory,
+	accessCache *authorization.AccessCache,
+) *S3Blobstore {
+	return &S3Blobstore{
+		s3Client:          s3Client,
+		userClientFactory: userClientFactory,
+		accessCache:       accessCache,
+	}
+}
+
+func (b *S3Blobstore) UploadPackageBits(ctx context.Context, authInfo authorization.Info, message UploadPackageBitsMessage) (PackageBitsRecord, error) {
+	authorized, err := canIPatchCFPackage(ctx, b.userClientFactory, b.accessCache, authInfo, message.SpaceGUID)
+	if err != nil {
+		return PackageBitsRecord{}, fmt.Errorf("checking auth to upload package bits failed: %w", err)
+	}
+
+	if !authorized {
+		return PackageBitsRecord{}, apierrors.NewForbiddenError(errors.New("not authorized to patch cfpackage"), PackageResourceType)
+	}
+
+	objectURL := b.s3Client.ObjectURL(message.SpaceGUID, message.PackageGUID+".zip")
+	if err = b.putObject(ctx, objectURL, message.Bits); err != nil {
+		return PackageBitsRecord{}, apierrors.NewBlobstoreUnavailableError(fmt.Errorf("uploading package bits to %q failed: %w", objectURL, err))
+	}
+
+	return PackageBitsRecord{BlobURL: objectURL}, nil
+}
+
+func (b *S3Blobstore) putObject(ctx context.Context, objectURL string, bits io.Reader) error {
+	body, size, err := sizedBody(bits)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return b.s3Client.Put(ctx, objectURL, "application/zip", body, size)
+}
+
+// sizedBody returns the bits along with their size, as S3 does not accept
+// uploads of unknown length. Bits that cannot be seeked (e.g. when streamed
+// from the upload request) are spooled to a temporary file rather than
+// buffered in memory.
+func sizedBody(bits io.Reader) (io.ReadCloser, int64, error) {
+	if seeker, ok := bits.(io.Seeker); ok {
+		size, err := seeker.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, 0, err
+		}
+		if _, err = seeker.Seek(0, io.SeekStart); err != nil {
+			return nil, 0, err
+		}
+
+		return io.NopCloser(bits), size, nil
+	}
+
+	spoolFile, err := os.CreateTemp("", "package-bits-")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create a temp file for the package bits: %w", err)
+	}
+
+	body := &tempFileBody{File: spoolFile}
+	size, err := io.Copy(spoolFile, bits)
+	if err != nil {
+		body.Close()
+		return nil, 0, fmt.Errorf("failed to read the package bits: %w", err)
+	}
+
+	if _, err = spoolFile.Seek(0, io.SeekStart); err != nil {
+		body.Close()
+		return nil, 0, err
+	}
+
+	return body, size, nil
+}
+
+// tempFileBody removes the underlying temporary file once closed
+type tempFileBody struct {
+	*os.File
+}
+
+func (b *tempFileBody) Close() error {
+	return errors.Join(b.File.Close(), os.Remove(b.Name()))
+}
@@ -0,0 +1,135 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/s3"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+var _ = Describe("S3Blobstore", func() {
+	var (
+		blobstoreServer  *httptest.Server
+		responseStatus   int
+		uploadedRequests []*http.Request
+		uploadedBodies   []string
+		space            *korifiv1alpha1.CFSpace
+		bits             io.Reader
+		bitsRecord       repositories.PackageBitsRecord
+		uploadErr        error
+	)
+
+	BeforeEach(func() {
+		responseStatus = http.StatusOK
+		uploadedRequests = nil
+		uploadedBodies = nil
+		blobstoreServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+
+			body, err := io.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			uploadedRequests = append(uploadedRequests, r)
+			uploadedBodies = append(uploadedBodies, string(body))
+
+			w.WriteHeader(responseStatus)
+		}))
+		DeferCleanup(blobstoreServer.Close)
+
+		org := createOrgWithCleanup(ctx, prefixedGUID("org"))
+		space = createSpaceWithCleanup(ctx, org.Name, prefixedGUID("space"))
+		bits = strings.NewReader("the-bits")
+	})
+
+	JustBeforeEach(func() {
+		s3Client, err := s3.NewClient(
+			blobstoreServer.Client(),
+			credentials.NewStaticCredentialsProvider("the-access-key", "the-secret-key", ""),
+			blobstoreServer.URL,
+			"the-bucket",
+			"the-region",
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		blobstore := repositories.NewS3Blobstore(
+			s3Client,
+			userClientFactory,
+			authorization.NewAccessCache(cache.NewExpiring(), time.Minute),
+		)
+
+		bitsRecord, uploadErr = blobstore.UploadPackageBits(context.Background(), authInfo, repositories.UploadPackageBitsMessage{
+			PackageGUID: "the-package-guid",
+			SpaceGUID:   space.Name,
+			Bits:        bits,
+		})
+	})
+
+	It("fails with unauthorized error without a valid role in the space", func() {
+		Expect(uploadErr).To(BeAssignableToTypeOf(apierrors.ForbiddenError{}))
+		Expect(uploadedRequests).To(BeEmpty())
+	})
+
+	When("user has role SpaceDeveloper", func() {
+		BeforeEach(func() {
+			createRoleBinding(context.Background(), userName, spaceDeveloperRole.Name, space.Name)
+		})
+
+		It("uploads the bits to the bucket", func() {
+			Expect(uploadErr).NotTo(HaveOccurred())
+			Expect(uploadedRequests).To(HaveLen(1))
+			Expect(uploadedRequests[0].Method).To(Equal(http.MethodPut))
+			Expect(uploadedRequests[0].URL.Path).To(Equal("/the-bucket/" + space.Name + "/the-package-guid.zip"))
+			Expect(uploadedRequests[0].ContentLength).To(BeEquivalentTo(len("the-bits")))
+			Expect(uploadedBodies[0]).To(Equal("the-bits"))
+		})
+
+		It("signs the upload request", func() {
+			Expect(uploadErr).NotTo(HaveOccurred())
+			Expect(uploadedRequests[0].Header.Get("Authorization")).To(HavePrefix("AWS4-HMAC-SHA256 Credential=the-access-key/"))
+			Expect(uploadedRequests[0].Header.Get("Authorization")).To(ContainSubstring("/the-region/s3/aws4_request"))
+		})
+
+		It("returns the url of the uploaded bits", func() {
+			Expect(uploadErr).NotTo(HaveOccurred())
+			Expect(bitsRecord).To(Equal(repositories.PackageBitsRecord{
+				BlobURL: blobstoreServer.URL + "/the-bucket/" + space.Name + "/the-package-guid.zip",
+			}))
+		})
+
+		When("the bits cannot be seeked", func() {
+			BeforeEach(func() {
+				bits = io.MultiReader(strings.NewReader("the-"), strings.NewReader("bits"))
+			})
+
+			It("uploads them with their size", func() {
+				Expect(uploadErr).NotTo(HaveOccurred())
+				Expect(uploadedRequests[0].ContentLength).To(BeEquivalentTo(len("the-bits")))
+				Expect(uploadedBodies[0]).To(Equal("the-bits"))
+			})
+		})
+
+		When("the blobstore rejects the upload", func() {
+			BeforeEach(func() {
+				responseStatus = http.StatusForbidden
+			})
+
+			It("fails with a blobstore unavailable error", func() {
+				var apiError apierrors.BlobstoreUnavailableError
+				Expect(errors.As(uploadErr, &apiError)).To(BeTrue())
+				Expect(uploadErr).To(MatchError(ContainSubstring("unexpected status 403")))
+			})
+		})
+	})
+})
@@ -183,12 +183,12 @@ func (r *ServiceBindingRepo) DeleteServiceBinding(ctx context.Context, authInfo
 
 	err = userClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: guid}, binding)
 	if err != nil {
-		return apierrors.ForbiddenAsNotFound(apierrors.FromK8sError(err, ServiceBindingResourceType))
+		return r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, namespace, apierrors.FromK8sError(err, ServiceBindingResourceType))
 	}
 
 	err = userClient.Delete(ctx, binding)
 	if err != nil {
-		return apierrors.FromK8sError(err, ServiceBindingResourceType)
+		return r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, namespace, apierrors.FromK8sError(err, ServiceBindingResourceType))
 	}
 	return nil
 }
@@ -231,16 +231,47 @@ func (r *ServiceBindingRepo) UpdateServiceBinding(ctx context.Context, authInfo
 		},
 	}
 
-	err = userClient.Get(ctx, client.ObjectKeyFromObject(serviceBinding), serviceBinding)
+	err = PatchResource(ctx, userClient, serviceBinding, func() {
+		updateMsg.MetadataPatch.Apply(serviceBinding)
+	})
+	if err != nil {
+		return ServiceBindingRecord{}, fmt.Errorf("failed to patch service binding metadata: %w",
+			r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, ns, apierrors.FromK8sError(err, ServiceBindingResourceType)))
+	}
+
+	return cfServiceBindingToRecord(*serviceBinding), nil
+}
+
+// RotateServiceBindingCredentials requests the credentials of the binding to
+// be refreshed into a new binding secret, which rolls the instances of the
+// bound app
+func (r *ServiceBindingRepo) RotateServiceBindingCredentials(ctx context.Context, authInfo authorization.Info, guid string) (ServiceBindingRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return ServiceBindingRecord{}, fmt.Errorf("failed to create user client: %w", err)
+	}
+
+	ns, err := r.namespaceRetriever.NamespaceFor(ctx, guid, ServiceBindingResourceType)
+	if err != nil {
+		return ServiceBindingRecord{}, err
+	}
+
+	serviceBinding := &korifiv1alpha1.CFServiceBinding{}
+	err = userClient.Get(ctx, client.ObjectKey{Namespace: ns, Name: guid}, serviceBinding)
 	if err != nil {
-		return ServiceBindingRecord{}, fmt.Errorf("failed to get service binding: %w", apierrors.FromK8sError(err, ServiceBindingResourceType))
+		return ServiceBindingRecord{}, fmt.Errorf("failed to get service binding: %w",
+			r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, ns, apierrors.FromK8sError(err, ServiceBindingResourceType)))
 	}
 
 	err = k8s.PatchResource(ctx, userClient, serviceBinding, func() {
-		updateMsg.MetadataPatch.Apply(serviceBinding)
+		if serviceBinding.Annotations == nil {
+			serviceBinding.Annotations = map[string]string{}
+		}
+		serviceBinding.Annotations[korifiv1alpha1.CredentialsRotationAnnotationKey] = time.Now().UTC().Format(time.RFC3339Nano)
 	})
 	if err != nil {
-		return ServiceBindingRecord{}, fmt.Errorf("failed to patch service binding metadata: %w", apierrors.FromK8sError(err, ServiceBindingResourceType))
+		return ServiceBindingRecord{}, fmt.Errorf("failed to request service binding credentials rotation: %w",
+			r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, ns, apierrors.FromK8sError(err, ServiceBindingResourceType)))
 	}
 
 	return cfServiceBindingToRecord(*serviceBinding), nil
@@ -258,16 +289,30 @@ func cfServiceBindingToRecord(binding korifiv1alpha1.CFServiceBinding) ServiceBi
 		Annotations:         binding.Annotations,
 		CreatedAt:           binding.CreationTimestamp.Time,
 		UpdatedAt:           getLastUpdatedTime(&binding),
-		LastOperation: ServiceBindingLastOperation{
-			Type:        "create",
-			State:       "succeeded",
-			Description: nil,
-			CreatedAt:   binding.CreationTimestamp.Time,
-			UpdatedAt:   getLastUpdatedTime(&binding),
-		},
+		LastOperation:       serviceBindingLastOperation(binding),
 	}
 }
 
+func serviceBindingLastOperation(binding korifiv1alpha1.CFServiceBinding) ServiceBindingLastOperation {
+	lastOperation := ServiceBindingLastOperation{
+		Type:        "create",
+		State:       "succeeded",
+		Description: nil,
+		CreatedAt:   binding.CreationTimestamp.Time,
+		UpdatedAt:   getLastUpdatedTime(&binding),
+	}
+
+	if rotation, ok := binding.Annotations[korifiv1alpha1.CredentialsRotationAnnotationKey]; ok {
+		lastOperation.Type = "update"
+		if rotation != binding.Status.CredentialsRotation {
+			lastOperation.State = "in progress"
+			lastOperation.Description = tools.PtrTo("Rotating credentials")
+		}
+	}
+
+	return lastOperation
+}
+
 // nolint:dupl
 func (r *ServiceBindingRepo) ListServiceBindings(ctx context.Context, authInfo authorization.Info, message ListServiceBindingsMessage) ([]ServiceBindingRecord, error) {
 	userClient, err := r.userClientFactory.BuildClient(authInfo)
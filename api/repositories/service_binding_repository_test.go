@@ -614,8 +614,8 @@ var _ = Describe("ServiceBindingRepo", func() {
 			updatedServiceBinding, updateErr = repo.UpdateServiceBinding(ctx, authInfo, updateMessage)
 		})
 
-		It("fails because the user has no bindings", func() {
-			Expect(updateErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+		It("fails with not found because the user has no bindings", func() {
+			Expect(updateErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
 		})
 
 		When("the user is a CFAdmin", func() {
@@ -649,4 +649,75 @@ var _ = Describe("ServiceBindingRepo", func() {
 			})
 		})
 	})
+
+	Describe("RotateServiceBindingCredentials", func() {
+		var (
+			serviceBinding        *korifiv1alpha1.CFServiceBinding
+			serviceBindingGUID    string
+			rotatedServiceBinding repositories.ServiceBindingRecord
+			rotateErr             error
+		)
+
+		BeforeEach(func() {
+			serviceBinding = &korifiv1alpha1.CFServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      prefixedGUID("binding"),
+					Namespace: space.Name,
+				},
+				Spec: korifiv1alpha1.CFServiceBindingSpec{
+					Service: corev1.ObjectReference{
+						Kind:       "CFServiceInstance",
+						APIVersion: korifiv1alpha1.GroupVersion.Identifier(),
+						Name:       serviceInstanceGUID,
+					},
+					AppRef: corev1.LocalObjectReference{
+						Name: appGUID,
+					},
+				},
+			}
+			Expect(
+				k8sClient.Create(testCtx, serviceBinding),
+			).To(Succeed())
+			serviceBindingGUID = serviceBinding.Name
+		})
+
+		JustBeforeEach(func() {
+			rotatedServiceBinding, rotateErr = repo.RotateServiceBindingCredentials(ctx, authInfo, serviceBindingGUID)
+		})
+
+		It("fails with not found because the user has no bindings", func() {
+			Expect(rotateErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+		})
+
+		When("the user is a space developer", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceDeveloperRole.Name, space.Name)
+			})
+
+			It("requests the credentials rotation", func() {
+				Expect(rotateErr).NotTo(HaveOccurred())
+
+				updatedServiceBinding := new(korifiv1alpha1.CFServiceBinding)
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(serviceBinding), updatedServiceBinding)).To(Succeed())
+				Expect(updatedServiceBinding.Annotations).To(HaveKeyWithValue(korifiv1alpha1.CredentialsRotationAnnotationKey, Not(BeEmpty())))
+			})
+
+			It("returns an in progress update operation", func() {
+				Expect(rotateErr).NotTo(HaveOccurred())
+				Expect(rotatedServiceBinding.GUID).To(Equal(serviceBinding.Name))
+				Expect(rotatedServiceBinding.LastOperation.Type).To(Equal("update"))
+				Expect(rotatedServiceBinding.LastOperation.State).To(Equal("in progress"))
+			})
+
+			When("the service binding does not exist", func() {
+				BeforeEach(func() {
+					serviceBindingGUID = "i-do-not-exist"
+				})
+
+				It("returns a not found error", func() {
+					Expect(rotateErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+				})
+			})
+		})
+	})
 })
@@ -60,12 +60,13 @@ func NewServiceInstanceRepo(
 }
 
 type CreateUPSIMessage struct {
-	Name        string
-	SpaceGUID   string
-	Credentials map[string]any
-	Tags        []string
-	Labels      map[string]string
-	Annotations map[string]string
+	Name           string
+	SpaceGUID      string
+	Credentials    map[string]any
+	Tags           []string
+	SyslogDrainURL *string
+	Labels         map[string]string
+	Annotations    map[string]string
 }
 
 type CreateManagedSIMessage struct {
@@ -79,11 +80,12 @@ type CreateManagedSIMessage struct {
 }
 
 type PatchServiceInstanceMessage struct {
-	GUID        string
-	SpaceGUID   string
-	Name        *string
-	Credentials *map[string]any
-	Tags        *[]string
+	GUID           string
+	SpaceGUID      string
+	Name           *string
+	Credentials    *map[string]any
+	Tags           *[]string
+	SyslogDrainURL *string
 	MetadataPatch
 }
 
@@ -94,6 +96,12 @@ func (p PatchServiceInstanceMessage) Apply(cfServiceInstance *korifiv1alpha1.CFS
 	if p.Tags != nil {
 		cfServiceInstance.Spec.Tags = *p.Tags
 	}
+	if p.SyslogDrainURL != nil {
+		cfServiceInstance.Spec.SyslogDrainURL = nil
+		if *p.SyslogDrainURL != "" {
+			cfServiceInstance.Spec.SyslogDrainURL = p.SyslogDrainURL
+		}
+	}
 	p.MetadataPatch.Apply(cfServiceInstance)
 }
 
@@ -119,19 +127,20 @@ type DeleteServiceInstanceMessage struct {
 }
 
 type ServiceInstanceRecord struct {
-	Name        string
-	GUID        string
-	SpaceGUID   string
-	PlanGUID    string
-	SecretName  string
-	Tags        []string
-	Type        string
-	Labels      map[string]string
-	Annotations map[string]string
-	CreatedAt   time.Time
-	UpdatedAt   *time.Time
-	DeletedAt   *time.Time
-	Ready       bool
+	Name           string
+	GUID           string
+	SpaceGUID      string
+	PlanGUID       string
+	SecretName     string
+	Tags           []string
+	SyslogDrainURL *string
+	Type           string
+	Labels         map[string]string
+	Annotations    map[string]string
+	CreatedAt      time.Time
+	UpdatedAt      *time.Time
+	DeletedAt      *time.Time
+	Ready          bool
 }
 
 func (r ServiceInstanceRecord) Relationships() map[string]string {
@@ -160,10 +169,11 @@ func (r *ServiceInstanceRepo) CreateUserProvidedServiceInstance(ctx context.Cont
 			Annotations: message.Annotations,
 		},
 		Spec: korifiv1alpha1.CFServiceInstanceSpec{
-			DisplayName: message.Name,
-			SecretName:  guid,
-			Type:        korifiv1alpha1.UserProvidedType,
-			Tags:        message.Tags,
+			DisplayName:    message.Name,
+			SecretName:     guid,
+			Type:           korifiv1alpha1.UserProvidedType,
+			Tags:           message.Tags,
+			SyslogDrainURL: message.SyslogDrainURL,
 		},
 	}
 	err = userClient.Create(ctx, cfServiceInstance)
@@ -224,15 +234,11 @@ func (r *ServiceInstanceRepo) PatchServiceInstance(ctx context.Context, authInfo
 	cfServiceInstance := &korifiv1alpha1.CFServiceInstance{}
 	cfServiceInstance.Namespace = message.SpaceGUID
 	cfServiceInstance.Name = message.GUID
-	if err = userClient.Get(ctx, client.ObjectKeyFromObject(cfServiceInstance), cfServiceInstance); err != nil {
-		return ServiceInstanceRecord{}, apierrors.FromK8sError(err, ServiceInstanceResourceType)
-	}
-
-	err = k8s.PatchResource(ctx, userClient, cfServiceInstance, func() {
+	err = PatchResource(ctx, userClient, cfServiceInstance, func() {
 		message.Apply(cfServiceInstance)
 	})
 	if err != nil {
-		return ServiceInstanceRecord{}, apierrors.FromK8sError(err, ServiceInstanceResourceType)
+		return ServiceInstanceRecord{}, r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, message.SpaceGUID, apierrors.FromK8sError(err, ServiceInstanceResourceType))
 	}
 
 	if message.Credentials != nil {
@@ -383,7 +389,8 @@ func (r *ServiceInstanceRepo) DeleteServiceInstance(ctx context.Context, authInf
 	}
 
 	if err := userClient.Delete(ctx, serviceInstance); err != nil {
-		return fmt.Errorf("failed to delete service instance: %w", apierrors.FromK8sError(err, ServiceInstanceResourceType))
+		return fmt.Errorf("failed to delete service instance: %w",
+			r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, message.SpaceGUID, apierrors.FromK8sError(err, ServiceInstanceResourceType)))
 	}
 
 	return nil
@@ -416,19 +423,20 @@ func (r *ServiceInstanceRepo) GetDeletedAt(ctx context.Context, authInfo authori
 
 func cfServiceInstanceToRecord(cfServiceInstance korifiv1alpha1.CFServiceInstance) ServiceInstanceRecord {
 	return ServiceInstanceRecord{
-		Name:        cfServiceInstance.Spec.DisplayName,
-		GUID:        cfServiceInstance.Name,
-		SpaceGUID:   cfServiceInstance.Namespace,
-		PlanGUID:    cfServiceInstance.Spec.PlanGUID,
-		SecretName:  cfServiceInstance.Spec.SecretName,
-		Tags:        cfServiceInstance.Spec.Tags,
-		Type:        string(cfServiceInstance.Spec.Type),
-		Labels:      cfServiceInstance.Labels,
-		Annotations: cfServiceInstance.Annotations,
-		CreatedAt:   cfServiceInstance.CreationTimestamp.Time,
-		UpdatedAt:   getLastUpdatedTime(&cfServiceInstance),
-		DeletedAt:   golangTime(cfServiceInstance.DeletionTimestamp),
-		Ready:       isReady(cfServiceInstance),
+		Name:           cfServiceInstance.Spec.DisplayName,
+		GUID:           cfServiceInstance.Name,
+		SpaceGUID:      cfServiceInstance.Namespace,
+		PlanGUID:       cfServiceInstance.Spec.PlanGUID,
+		SecretName:     cfServiceInstance.Spec.SecretName,
+		Tags:           cfServiceInstance.Spec.Tags,
+		SyslogDrainURL: cfServiceInstance.Spec.SyslogDrainURL,
+		Type:           string(cfServiceInstance.Spec.Type),
+		Labels:         cfServiceInstance.Labels,
+		Annotations:    cfServiceInstance.Annotations,
+		CreatedAt:      cfServiceInstance.CreationTimestamp.Time,
+		UpdatedAt:      getLastUpdatedTime(&cfServiceInstance),
+		DeletedAt:      golangTime(cfServiceInstance.DeletionTimestamp),
+		Ready:          isReady(cfServiceInstance),
 	}
 }
 
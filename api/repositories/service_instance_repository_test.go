@@ -71,7 +71,8 @@ var _ = Describe("ServiceInstanceRepository", func() {
 				Credentials: map[string]any{
 					"object": map[string]any{"a": "b"},
 				},
-				Tags: []string{"foo", "bar"},
+				Tags:           []string{"foo", "bar"},
+				SyslogDrainURL: tools.PtrTo("syslog://logs.example.com"),
 			}
 		})
 
@@ -96,6 +97,7 @@ var _ = Describe("ServiceInstanceRepository", func() {
 				Expect(record.Name).To(Equal(serviceInstanceName))
 				Expect(record.Type).To(Equal("user-provided"))
 				Expect(record.Tags).To(ConsistOf([]string{"foo", "bar"}))
+				Expect(record.SyslogDrainURL).To(PointTo(Equal("syslog://logs.example.com")))
 				Expect(record.SecretName).NotTo(BeEmpty())
 				Expect(record.Relationships()).To(Equal(map[string]string{
 					"space": space.Name,
@@ -120,6 +122,7 @@ var _ = Describe("ServiceInstanceRepository", func() {
 				Expect(cfServiceInstance.Spec.SecretName).NotTo(BeEmpty())
 				Expect(cfServiceInstance.Spec.Type).To(BeEquivalentTo(korifiv1alpha1.UserProvidedType))
 				Expect(cfServiceInstance.Spec.Tags).To(ConsistOf("foo", "bar"))
+				Expect(cfServiceInstance.Spec.SyslogDrainURL).To(PointTo(Equal("syslog://logs.example.com")))
 			})
 
 			It("creates the credentials secret", func() {
@@ -450,6 +453,30 @@ var _ = Describe("ServiceInstanceRepository", func() {
 				})
 			})
 
+			When("the syslog drain url is set", func() {
+				BeforeEach(func() {
+					patchMessage.SyslogDrainURL = tools.PtrTo("syslog-tls://logs.example.com:6514")
+				})
+
+				It("updates the syslog drain url", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(serviceInstanceRecord.SyslogDrainURL).To(PointTo(Equal("syslog-tls://logs.example.com:6514")))
+				})
+
+				When("the syslog drain url is then cleared", func() {
+					JustBeforeEach(func() {
+						Expect(err).NotTo(HaveOccurred())
+						patchMessage.SyslogDrainURL = tools.PtrTo("")
+						serviceInstanceRecord, err = serviceInstanceRepo.PatchServiceInstance(ctx, authInfo, patchMessage)
+					})
+
+					It("removes the syslog drain url", func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(serviceInstanceRecord.SyslogDrainURL).To(BeNil())
+					})
+				})
+			})
+
 			When("tags is nil", func() {
 				BeforeEach(func() {
 					patchMessage.Tags = nil
@@ -907,7 +934,17 @@ var _ = Describe("ServiceInstanceRepository", func() {
 			})
 		})
 
-		When("there are no permissions on service instances", func() {
+		When("the user cannot see the space", func() {
+			It("returns a not found error", func() {
+				Expect(errors.As(deleteErr, &apierrors.NotFoundError{})).To(BeTrue())
+			})
+		})
+
+		When("the user can see the service instance but not delete it", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceManagerRole.Name, space.Name)
+			})
+
 			It("returns a forbidden error", func() {
 				Expect(errors.As(deleteErr, &apierrors.ForbiddenError{})).To(BeTrue())
 			})
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const ServiceUsageEventResourceType = "Service Usage Event"
+
+type ServiceUsageEventRecord struct {
+	GUID                string
+	CreatedAt           time.Time
+	State               string
+	ServiceInstanceGUID string
+	ServiceInstanceName string
+	ServiceInstanceType string
+	ServicePlanGUID     string
+	ServicePlanName     string
+	ServiceOfferingGUID string
+	ServiceOfferingName string
+	ServiceBrokerGUID   string
+	ServiceBrokerName   string
+	ServiceBindingGUID  string
+	AppGUID             string
+	SpaceGUID           string
+	SpaceName           string
+	OrgGUID             string
+}
+
+type ListServiceUsageEventsMessage struct {
+	GUIDs []string
+	// AfterGUID only lists the events recorded after the event with this guid,
+	// so that consumers can poll for new events
+	AfterGUID            string
+	ServiceInstanceTypes []string
+}
+
+// ServiceUsageEventRepo reads the service usage events the controllers record
+// in the root namespace. Only users allowed to list them there, i.e. admins,
+// can read them.
+type ServiceUsageEventRepo struct {
+	userClientFactory authorization.UserK8sClientFactory
+	rootNamespace     string
+	settleWindow      time.Duration
+}
+
+func NewServiceUsageEventRepo(
+	userClientFactory authorization.UserK8sClientFactory,
+	rootNamespace string,
+	settleWindow time.Duration,
+) *ServiceUsageEventRepo {
+	return &ServiceUsageEventRepo{
+		userClientFactory: userClientFactory,
+		rootNamespace:     rootNamespace,
+		settleWindow:      settleWindow,
+	}
+}
+
+func (r *ServiceUsageEventRepo) ListServiceUsageEvents(ctx context.Context, authInfo authorization.Info, message ListServiceUsageEventsMessage) ([]ServiceUsageEventRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	eventList := &korifiv1alpha1.CFServiceUsageEventList{}
+	err = userClient.List(ctx, eventList, client.InNamespace(r.rootNamespace))
+	if err != nil {
+		return nil, apierrors.FromK8sError(err, ServiceUsageEventResourceType)
+	}
+
+	events := eventList.Items
+	slices.SortFunc(events, compareServiceUsageEvents)
+
+	if message.AfterGUID != "" {
+		afterIndex := slices.IndexFunc(events, func(event korifiv1alpha1.CFServiceUsageEvent) bool {
+			return event.Name == message.AfterGUID
+		})
+		if afterIndex < 0 {
+			return nil, apierrors.NewInvalidRequestError(nil, "After guid filter must be a valid service usage event guid.")
+		}
+		events = events[afterIndex+1:]
+	}
+	events = settledUsageEvents(events, r.settleWindow, func(event korifiv1alpha1.CFServiceUsageEvent) time.Time {
+		return event.Spec.Timestamp.Time
+	})
+
+	records := []ServiceUsageEventRecord{}
+	for _, event := range events {
+		if tools.EmptyOrContains(message.GUIDs, event.Name) &&
+			tools.EmptyOrContains(message.ServiceInstanceTypes, event.Spec.ServiceInstanceType) {
+			records = append(records, serviceUsageEventToRecord(event))
+		}
+	}
+
+	return records, nil
+}
+
+func (r *ServiceUsageEventRepo) GetServiceUsageEvent(ctx context.Context, authInfo authorization.Info, guid string) (ServiceUsageEventRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return ServiceUsageEventRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	event := &korifiv1alpha1.CFServiceUsageEvent{}
+	err = userClient.Get(ctx, client.ObjectKey{Namespace: r.rootNamespace, Name: guid}, event)
+	if err != nil {
+		return ServiceUsageEventRecord{}, apierrors.FromK8sError(err, ServiceUsageEventResourceType)
+	}
+
+	return serviceUsageEventToRecord(*event), nil
+}
+
+// compareServiceUsageEvents orders the events by the time they were recorded
+// and then by guid, like compareAppUsageEvents
+func compareServiceUsageEvents(e1, e2 korifiv1alpha1.CFServiceUsageEvent) int {
+	if c := e1.Spec.Timestamp.Compare(e2.Spec.Timestamp.Time); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(e1.Name, e2.Name)
+}
+
+func serviceUsageEventToRecord(event korifiv1alpha1.CFServiceUsageEvent) ServiceUsageEventRecord {
+	return ServiceUsageEventRecord{
+		GUID:                event.Name,
+		CreatedAt:           event.Spec.Timestamp.Time,
+		State:               event.Spec.State,
+		ServiceInstanceGUID: event.Spec.ServiceInstanceGUID,
+		ServiceInstanceName: event.Spec.ServiceInstanceName,
+		ServiceInstanceType: event.Spec.ServiceInstanceType,
+		ServicePlanGUID:     event.Spec.ServicePlanGUID,
+		ServicePlanName:     event.Spec.ServicePlanName,
+		ServiceOfferingGUID: event.Spec.ServiceOfferingGUID,
+		ServiceOfferingName: event.Spec.ServiceOfferingName,
+		ServiceBrokerGUID:   event.Spec.ServiceBrokerGUID,
+		ServiceBrokerName:   event.Spec.ServiceBrokerName,
+		ServiceBindingGUID:  event.Spec.ServiceBindingGUID,
+		AppGUID:             event.Spec.AppGUID,
+		SpaceGUID:           event.Spec.SpaceGUID,
+		SpaceName:           event.Spec.SpaceName,
+		OrgGUID:             event.Spec.OrgGUID,
+	}
+}
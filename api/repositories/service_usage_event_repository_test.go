@@ -0,0 +1,202 @@
+package repositories_test
+
+import (
+	"time"
+
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ServiceUsageEventRepository", func() {
+	var (
+		serviceUsageEventRepo *repositories.ServiceUsageEventRepo
+		event1                *korifiv1alpha1.CFServiceUsageEvent
+		event2                *korifiv1alpha1.CFServiceUsageEvent
+	)
+
+	createServiceUsageEvent := func(timestamp time.Time, state, instanceType string) *korifiv1alpha1.CFServiceUsageEvent {
+		event := &korifiv1alpha1.CFServiceUsageEvent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      uuid.NewString(),
+				Namespace: rootNamespace,
+			},
+			Spec: korifiv1alpha1.CFServiceUsageEventSpec{
+				Timestamp:           metav1.NewMicroTime(timestamp),
+				State:               state,
+				ServiceInstanceGUID: "service-instance-guid",
+				ServiceInstanceName: "service-instance-name",
+				ServiceInstanceType: instanceType,
+				ServicePlanGUID:     "plan-guid",
+				ServicePlanName:     "plan-name",
+				ServiceOfferingGUID: "offering-guid",
+				ServiceOfferingName: "offering-name",
+				ServiceBrokerGUID:   "broker-guid",
+				ServiceBrokerName:   "broker-name",
+				SpaceGUID:           "space-guid",
+				SpaceName:           "space-name",
+				OrgGUID:             "org-guid",
+			},
+		}
+		Expect(k8sClient.Create(ctx, event)).To(Succeed())
+
+		return event
+	}
+
+	BeforeEach(func() {
+		serviceUsageEventRepo = repositories.NewServiceUsageEventRepo(userClientFactory, rootNamespace, 0)
+
+		now := time.Now()
+		event2 = createServiceUsageEvent(now, korifiv1alpha1.ServiceUsageStateDeleted, korifiv1alpha1.UserProvidedServiceInstanceUsageType)
+		event1 = createServiceUsageEvent(now.Add(-time.Minute), korifiv1alpha1.ServiceUsageStateCreated, korifiv1alpha1.ManagedServiceInstanceUsageType)
+	})
+
+	Describe("ListServiceUsageEvents", func() {
+		var (
+			listMsg            repositories.ListServiceUsageEventsMessage
+			serviceUsageEvents []repositories.ServiceUsageEventRecord
+			listErr            error
+		)
+
+		BeforeEach(func() {
+			listMsg = repositories.ListServiceUsageEventsMessage{
+				GUIDs: []string{event1.Name, event2.Name},
+			}
+		})
+
+		JustBeforeEach(func() {
+			serviceUsageEvents, listErr = serviceUsageEventRepo.ListServiceUsageEvents(ctx, authInfo, listMsg)
+		})
+
+		It("returns a forbidden error", func() {
+			Expect(listErr).To(BeAssignableToTypeOf(apierrors.ForbiddenError{}))
+		})
+
+		When("the user is an admin", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, adminRole.Name, rootNamespace)
+			})
+
+			It("lists the events in the order they were recorded", func() {
+				Expect(listErr).NotTo(HaveOccurred())
+				Expect(serviceUsageEvents).To(HaveLen(2))
+				Expect(serviceUsageEvents[0].GUID).To(Equal(event1.Name))
+				Expect(serviceUsageEvents[1].GUID).To(Equal(event2.Name))
+			})
+
+			It("presents the events", func() {
+				Expect(listErr).NotTo(HaveOccurred())
+				Expect(serviceUsageEvents[0]).To(MatchAllFields(Fields{
+					"GUID":                Equal(event1.Name),
+					"CreatedAt":           BeTemporally("~", event1.Spec.Timestamp.Time, time.Millisecond),
+					"State":               Equal(korifiv1alpha1.ServiceUsageStateCreated),
+					"ServiceInstanceGUID": Equal("service-instance-guid"),
+					"ServiceInstanceName": Equal("service-instance-name"),
+					"ServiceInstanceType": Equal(korifiv1alpha1.ManagedServiceInstanceUsageType),
+					"ServicePlanGUID":     Equal("plan-guid"),
+					"ServicePlanName":     Equal("plan-name"),
+					"ServiceOfferingGUID": Equal("offering-guid"),
+					"ServiceOfferingName": Equal("offering-name"),
+					"ServiceBrokerGUID":   Equal("broker-guid"),
+					"ServiceBrokerName":   Equal("broker-name"),
+					"ServiceBindingGUID":  BeEmpty(),
+					"AppGUID":             BeEmpty(),
+					"SpaceGUID":           Equal("space-guid"),
+					"SpaceName":           Equal("space-name"),
+					"OrgGUID":             Equal("org-guid"),
+				}))
+			})
+
+			When("listing the events after a guid", func() {
+				BeforeEach(func() {
+					listMsg.AfterGUID = event1.Name
+				})
+
+				It("returns the events recorded after that event", func() {
+					Expect(listErr).NotTo(HaveOccurred())
+					Expect(serviceUsageEvents).To(ConsistOf(HaveField("GUID", event2.Name)))
+				})
+			})
+
+			When("events have been recorded within the settle window", func() {
+				BeforeEach(func() {
+					serviceUsageEventRepo = repositories.NewServiceUsageEventRepo(userClientFactory, rootNamespace, 30*time.Second)
+				})
+
+				It("holds them back", func() {
+					Expect(listErr).NotTo(HaveOccurred())
+					Expect(serviceUsageEvents).To(ConsistOf(HaveField("GUID", event1.Name)))
+				})
+			})
+
+			When("the after guid is not an event", func() {
+				BeforeEach(func() {
+					listMsg.AfterGUID = "not-an-event"
+				})
+
+				It("returns an invalid request error", func() {
+					Expect(listErr).To(BeAssignableToTypeOf(apierrors.InvalidRequestError{}))
+				})
+			})
+
+			When("filtering by service instance type", func() {
+				BeforeEach(func() {
+					listMsg.ServiceInstanceTypes = []string{korifiv1alpha1.UserProvidedServiceInstanceUsageType}
+				})
+
+				It("returns the events of that type", func() {
+					Expect(listErr).NotTo(HaveOccurred())
+					Expect(serviceUsageEvents).To(ConsistOf(HaveField("GUID", event2.Name)))
+				})
+			})
+		})
+	})
+
+	Describe("GetServiceUsageEvent", func() {
+		var (
+			serviceUsageEvent repositories.ServiceUsageEventRecord
+			getErr            error
+			guid              string
+		)
+
+		BeforeEach(func() {
+			guid = event1.Name
+		})
+
+		JustBeforeEach(func() {
+			serviceUsageEvent, getErr = serviceUsageEventRepo.GetServiceUsageEvent(ctx, authInfo, guid)
+		})
+
+		It("returns a forbidden error", func() {
+			Expect(getErr).To(BeAssignableToTypeOf(apierrors.ForbiddenError{}))
+		})
+
+		When("the user is an admin", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, adminRole.Name, rootNamespace)
+			})
+
+			It("returns the event", func() {
+				Expect(getErr).NotTo(HaveOccurred())
+				Expect(serviceUsageEvent.GUID).To(Equal(event1.Name))
+				Expect(serviceUsageEvent.State).To(Equal(korifiv1alpha1.ServiceUsageStateCreated))
+			})
+
+			When("the event does not exist", func() {
+				BeforeEach(func() {
+					guid = "not-an-event"
+				})
+
+				It("returns a not found error", func() {
+					Expect(getErr).To(BeAssignableToTypeOf(apierrors.NotFoundError{}))
+				})
+			})
+		})
+	})
+})
@@ -2,12 +2,16 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"maps"
+	"slices"
+	"sync"
 	"time"
 
 	"code.cloudfoundry.org/korifi/api/authorization"
 	"github.com/BooleanCat/go-functional/v2/it/itx"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -21,6 +25,10 @@ type RepositoryCreator interface {
 	CreateRepository(ctx context.Context, name string) error
 }
 
+type PackageRepositoryNamer interface {
+	PackagesRepository(ctx context.Context, spaceGUID string, appGUID string) (string, error)
+}
+
 type Awaiter[T runtime.Object] interface {
 	AwaitCondition(context.Context, client.WithWatch, client.Object, string) (T, error)
 	AwaitState(context.Context, client.WithWatch, client.Object, func(T) error) (T, error)
@@ -88,3 +96,85 @@ func authorizedOrgNamespaces(ctx context.Context, authInfo authorization.Info, n
 
 	return itx.From(maps.Keys(nsList)), nil
 }
+
+// maxConcurrentNamespaceLists bounds the number of namespaces listed at the
+// same time when listing resources across all spaces of a user
+const maxConcurrentNamespaceLists = 16
+
+// listInNamespaces lists resources in all the namespaces in parallel, at most
+// maxConcurrentNamespaceLists at a time, and returns the resources in the
+// order of the namespaces. Namespaces the user is not allowed to list in are
+// skipped, any other errors are all returned joined together.
+func listInNamespaces[T any](ctx context.Context, namespaces []string, list func(ctx context.Context, namespace string) ([]T, error)) ([]T, error) {
+	results := make([][]T, len(namespaces))
+	errs := make([]error, len(namespaces))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, maxConcurrentNamespaceLists)
+	for i, ns := range namespaces {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			items, err := list(ctx, ns)
+			if k8serrors.IsForbidden(err) {
+				return
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("namespace %s: %w", ns, err)
+				return
+			}
+
+			results[i] = items
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	var items []T
+	for _, result := range results {
+		items = append(items, result...)
+	}
+	return items, nil
+}
+
+// listPageSize is the number of objects requested at once when listing
+// resources that can pile up in a namespace, such as builds and packages
+const listPageSize = 500
+
+// listFiltered lists objects page by page into list and keeps only the items
+// matching the filter, so that the unfiltered objects of a namespace are
+// never all held in memory at the same time
+func listFiltered[T any](
+	ctx context.Context,
+	userClient client.Client,
+	list client.ObjectList,
+	items func() []T,
+	filter func(T) bool,
+	opts ...client.ListOption,
+) ([]T, error) {
+	var result []T
+	continueToken := ""
+	for {
+		pageOpts := append(slices.Clone(opts), client.Limit(listPageSize), client.Continue(continueToken))
+		if err := userClient.List(ctx, list, pageOpts...); err != nil {
+			return nil, err
+		}
+
+		for _, item := range items() {
+			if filter(item) {
+				result = append(result, item)
+			}
+		}
+
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			return result, nil
+		}
+	}
+}
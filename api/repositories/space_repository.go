@@ -10,7 +10,6 @@ import (
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/tools"
-	"code.cloudfoundry.org/korifi/tools/k8s"
 
 	"github.com/BooleanCat/go-functional/v2/it"
 	"github.com/BooleanCat/go-functional/v2/it/itx"
@@ -216,7 +215,7 @@ func (r *SpaceRepo) DeleteSpace(ctx context.Context, info authorization.Info, me
 		},
 	})
 
-	return apierrors.FromK8sError(err, SpaceResourceType)
+	return r.nsPerms.ForbiddenOrNotFound(ctx, info, message.OrganizationGUID, apierrors.FromK8sError(err, SpaceResourceType))
 }
 
 func (r *SpaceRepo) PatchSpaceMetadata(ctx context.Context, authInfo authorization.Info, message PatchSpaceMetadataMessage) (SpaceRecord, error) {
@@ -225,17 +224,17 @@ func (r *SpaceRepo) PatchSpaceMetadata(ctx context.Context, authInfo authorizati
 		return SpaceRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
 
-	cfSpace := new(korifiv1alpha1.CFSpace)
-	err = userClient.Get(ctx, client.ObjectKey{Namespace: message.OrgGUID, Name: message.GUID}, cfSpace)
-	if err != nil {
-		return SpaceRecord{}, fmt.Errorf("failed to get space: %w", apierrors.FromK8sError(err, SpaceResourceType))
+	cfSpace := &korifiv1alpha1.CFSpace{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: message.OrgGUID,
+			Name:      message.GUID,
+		},
 	}
-
-	err = k8s.PatchResource(ctx, userClient, cfSpace, func() {
+	err = PatchResource(ctx, userClient, cfSpace, func() {
 		message.Apply(cfSpace)
 	})
 	if err != nil {
-		return SpaceRecord{}, apierrors.FromK8sError(err, SpaceResourceType)
+		return SpaceRecord{}, r.nsPerms.ForbiddenOrNotFound(ctx, authInfo, message.OrgGUID, apierrors.FromK8sError(err, SpaceResourceType))
 	}
 
 	return cfSpaceToSpaceRecord(*cfSpace), nil
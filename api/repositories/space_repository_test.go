@@ -455,25 +455,39 @@ var _ = Describe("SpaceRepository", func() {
 			})
 		})
 
-		When("the user does not have permission to delete spaces", func() {
-			It("errors with forbidden", func() {
+		When("the user does not have a role in the org", func() {
+			It("errors with not found", func() {
 				err := spaceRepo.DeleteSpace(ctx, authInfo, repositories.DeleteSpaceMessage{
 					GUID:             cfSpace.Name,
 					OrganizationGUID: cfOrg.Name,
 				})
-				Expect(err).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+				Expect(err).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
 			})
 
 			When("the space doesn't exist", func() {
-				It("errors with forbidden", func() {
+				It("errors with not found", func() {
 					err := spaceRepo.DeleteSpace(ctx, authInfo, repositories.DeleteSpaceMessage{
 						GUID:             "non-existent-space",
 						OrganizationGUID: cfOrg.Name,
 					})
-					Expect(err).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+					Expect(err).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
 				})
 			})
 		})
+
+		When("the user can see the space but does not have permission to delete it", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, orgUserRole.Name, cfOrg.Name)
+			})
+
+			It("errors with forbidden", func() {
+				err := spaceRepo.DeleteSpace(ctx, authInfo, repositories.DeleteSpaceMessage{
+					GUID:             cfSpace.Name,
+					OrganizationGUID: cfOrg.Name,
+				})
+				Expect(err).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			})
+		})
 	})
 
 	Describe("PatchSpaceMetadata", func() {
@@ -684,8 +698,8 @@ var _ = Describe("SpaceRepository", func() {
 		})
 
 		When("the user is not authorized", func() {
-			It("return a forbidden error", func() {
-				Expect(patchErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			It("returns a not found error", func() {
+				Expect(patchErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
 			})
 		})
 	})
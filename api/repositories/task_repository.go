@@ -58,9 +58,13 @@ func (t TaskRecord) Relationships() map[string]string {
 }
 
 type CreateTaskMessage struct {
-	Command   string
-	SpaceGUID string
-	AppGUID   string
+	Command                 string
+	SpaceGUID               string
+	AppGUID                 string
+	MemoryMB                int64
+	DiskQuotaMB             int64
+	BackoffLimit            *int32
+	TTLSecondsAfterFinished *int32
 	Metadata
 }
 
@@ -95,6 +99,10 @@ func (m *CreateTaskMessage) toCFTask() *korifiv1alpha1.CFTask {
 			AppRef: v1.LocalObjectReference{
 				Name: m.AppGUID,
 			},
+			MemoryMB:                m.MemoryMB,
+			DiskQuotaMB:             m.DiskQuotaMB,
+			BackoffLimit:            m.BackoffLimit,
+			TTLSecondsAfterFinished: m.TTLSecondsAfterFinished,
 		},
 	}
 }
@@ -224,7 +232,7 @@ func (r *TaskRepo) CancelTask(ctx context.Context, authInfo authorization.Info,
 		task.Spec.Canceled = true
 	})
 	if err != nil {
-		return TaskRecord{}, apierrors.FromK8sError(err, TaskResourceType)
+		return TaskRecord{}, r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, taskNamespace, apierrors.FromK8sError(err, TaskResourceType))
 	}
 
 	task, err = r.awaitCondition(ctx, userClient, task, korifiv1alpha1.TaskCanceledConditionType)
@@ -241,17 +249,17 @@ func (r *TaskRepo) PatchTaskMetadata(ctx context.Context, authInfo authorization
 		return TaskRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
 
-	task := new(korifiv1alpha1.CFTask)
-	err = userClient.Get(ctx, client.ObjectKey{Namespace: message.SpaceGUID, Name: message.TaskGUID}, task)
-	if err != nil {
-		return TaskRecord{}, fmt.Errorf("failed to get task: %w", apierrors.FromK8sError(err, TaskResourceType))
+	task := &korifiv1alpha1.CFTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: message.SpaceGUID,
+			Name:      message.TaskGUID,
+		},
 	}
-
-	err = k8s.PatchResource(ctx, userClient, task, func() {
+	err = PatchResource(ctx, userClient, task, func() {
 		message.Apply(task)
 	})
 	if err != nil {
-		return TaskRecord{}, apierrors.FromK8sError(err, TaskResourceType)
+		return TaskRecord{}, r.namespacePermissions.ForbiddenOrNotFound(ctx, authInfo, message.SpaceGUID, apierrors.FromK8sError(err, TaskResourceType))
 	}
 
 	return taskToRecord(*task), nil
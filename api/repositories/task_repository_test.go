@@ -137,6 +137,26 @@ var _ = Describe("TaskRepository", func() {
 				Expect(taskRecord.Annotations).To(Equal(map[string]string{"extra-bugs": "true"}))
 			})
 
+			When("the task overrides the resources, backoff limit and job TTL", func() {
+				BeforeEach(func() {
+					createMessage.MemoryMB = 1024
+					createMessage.DiskQuotaMB = 2048
+					createMessage.BackoffLimit = tools.PtrTo[int32](3)
+					createMessage.TTLSecondsAfterFinished = tools.PtrTo[int32](60)
+				})
+
+				It("sets them on the task spec", func() {
+					Expect(createErr).NotTo(HaveOccurred())
+
+					cfTask := &korifiv1alpha1.CFTask{}
+					Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: space.Name, Name: taskRecord.GUID}, cfTask)).To(Succeed())
+					Expect(cfTask.Spec.MemoryMB).To(BeEquivalentTo(1024))
+					Expect(cfTask.Spec.DiskQuotaMB).To(BeEquivalentTo(2048))
+					Expect(cfTask.Spec.BackoffLimit).To(Equal(tools.PtrTo[int32](3)))
+					Expect(cfTask.Spec.TTLSecondsAfterFinished).To(Equal(tools.PtrTo[int32](60)))
+				})
+			})
+
 			When("the task never becomes initialized", func() {
 				BeforeEach(func() {
 					conditionAwaiter.AwaitConditionReturns(&korifiv1alpha1.CFTask{}, errors.New("timed-out-error"))
@@ -561,8 +581,18 @@ var _ = Describe("TaskRepository", func() {
 			taskRecord, cancelErr = taskRepo.CancelTask(ctx, authInfo, taskGUID)
 		})
 
-		It("returns forbidden", func() {
-			Expect(cancelErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+		It("returns not found", func() {
+			Expect(cancelErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+		})
+
+		When("the user is a space manager", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceManagerRole.Name, space.Name)
+			})
+
+			It("returns forbidden", func() {
+				Expect(cancelErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			})
 		})
 
 		When("the user is a space developer", func() {
@@ -834,8 +864,8 @@ var _ = Describe("TaskRepository", func() {
 		})
 
 		When("the user is not authorized", func() {
-			It("return a forbidden error", func() {
-				Expect(patchErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			It("returns a not found error", func() {
+				Expect(patchErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
 			})
 		})
 	})
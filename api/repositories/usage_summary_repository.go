@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type UsageSummaryRecord struct {
+	StartedInstances int32
+	MemoryMB         int64
+}
+
+// UsageSummaryRepo sums up the instances and memory of the started processes
+// in the spaces the user can see. Idle processes run no instances.
+type UsageSummaryRepo struct {
+	userClientFactory    authorization.UserK8sClientFactory
+	namespacePermissions *authorization.NamespacePermissions
+}
+
+func NewUsageSummaryRepo(
+	userClientFactory authorization.UserK8sClientFactory,
+	namespacePermissions *authorization.NamespacePermissions,
+) *UsageSummaryRepo {
+	return &UsageSummaryRepo{
+		userClientFactory:    userClientFactory,
+		namespacePermissions: namespacePermissions,
+	}
+}
+
+// GetOrgUsageSummary sums up the usage of the spaces of the org the user can
+// see. The caller is expected to have checked that the user can see the org.
+func (r *UsageSummaryRepo) GetOrgUsageSummary(ctx context.Context, authInfo authorization.Info, orgGUID string) (UsageSummaryRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return UsageSummaryRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	spaceList := &korifiv1alpha1.CFSpaceList{}
+	err = userClient.List(ctx, spaceList, client.InNamespace(orgGUID))
+	if err != nil {
+		return UsageSummaryRecord{}, apierrors.FromK8sError(err, SpaceResourceType)
+	}
+
+	orgSpaces := map[string]bool{}
+	for _, space := range spaceList.Items {
+		orgSpaces[space.Name] = true
+	}
+
+	nsList, err := authorizedSpaceNamespaces(ctx, authInfo, r.namespacePermissions)
+	if err != nil {
+		return UsageSummaryRecord{}, err
+	}
+
+	return r.summarize(ctx, userClient, nsList.Filter(func(ns string) bool {
+		return orgSpaces[ns]
+	}).Collect())
+}
+
+// GetUsageSummary sums up the usage of all the spaces the user can see
+func (r *UsageSummaryRepo) GetUsageSummary(ctx context.Context, authInfo authorization.Info) (UsageSummaryRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	if err != nil {
+		return UsageSummaryRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	nsList, err := authorizedSpaceNamespaces(ctx, authInfo, r.namespacePermissions)
+	if err != nil {
+		return UsageSummaryRecord{}, err
+	}
+
+	return r.summarize(ctx, userClient, nsList.Collect())
+}
+
+func (r *UsageSummaryRepo) summarize(ctx context.Context, userClient client.Client, namespaces []string) (UsageSummaryRecord, error) {
+	apps, err := listInNamespaces(ctx, namespaces, func(ctx context.Context, ns string) ([]korifiv1alpha1.CFApp, error) {
+		appList := &korifiv1alpha1.CFAppList{}
+		err := userClient.List(ctx, appList, client.InNamespace(ns))
+		return appList.Items, err
+	})
+	if err != nil {
+		return UsageSummaryRecord{}, apierrors.FromK8sError(err, AppResourceType)
+	}
+
+	startedApps := map[client.ObjectKey]bool{}
+	for _, app := range apps {
+		if app.Spec.DesiredState == korifiv1alpha1.StartedState {
+			startedApps[client.ObjectKeyFromObject(&app)] = true
+		}
+	}
+
+	processes, err := listInNamespaces(ctx, namespaces, func(ctx context.Context, ns string) ([]korifiv1alpha1.CFProcess, error) {
+		processList := &korifiv1alpha1.CFProcessList{}
+		err := userClient.List(ctx, processList, client.InNamespace(ns))
+		return processList.Items, err
+	})
+	if err != nil {
+		return UsageSummaryRecord{}, apierrors.FromK8sError(err, ProcessResourceType)
+	}
+
+	summary := UsageSummaryRecord{}
+	for _, process := range processes {
+		if !startedApps[client.ObjectKey{Namespace: process.Namespace, Name: process.Spec.AppRef.Name}] {
+			continue
+		}
+		if process.Spec.DesiredInstances == nil || meta.IsStatusConditionTrue(process.Status.Conditions, korifiv1alpha1.IdleConditionType) {
+			continue
+		}
+
+		summary.StartedInstances += *process.Spec.DesiredInstances
+		summary.MemoryMB += int64(*process.Spec.DesiredInstances) * process.Spec.MemoryMB
+	}
+
+	return summary, nil
+}
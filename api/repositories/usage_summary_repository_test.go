@@ -0,0 +1,111 @@
+package repositories_test
+
+import (
+	"code.cloudfoundry.org/korifi/api/repositories"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("UsageSummaryRepository", func() {
+	var (
+		usageSummaryRepo *repositories.UsageSummaryRepo
+		org              *korifiv1alpha1.CFOrg
+		space1           *korifiv1alpha1.CFSpace
+		space2           *korifiv1alpha1.CFSpace
+		otherOrgSpace    *korifiv1alpha1.CFSpace
+	)
+
+	createStartedProcess := func(spaceGUID string) *korifiv1alpha1.CFProcess {
+		app := createAppCR(ctx, k8sClient, prefixedGUID("app"), prefixedGUID("app"), spaceGUID, string(korifiv1alpha1.StartedState))
+		return createProcessCR(ctx, k8sClient, prefixedGUID("process"), spaceGUID, app.Name)
+	}
+
+	BeforeEach(func() {
+		usageSummaryRepo = repositories.NewUsageSummaryRepo(userClientFactory, nsPerms)
+
+		org = createOrgWithCleanup(ctx, prefixedGUID("org"))
+		space1 = createSpaceWithCleanup(ctx, org.Name, prefixedGUID("space1"))
+		space2 = createSpaceWithCleanup(ctx, org.Name, prefixedGUID("space2"))
+		otherOrg := createOrgWithCleanup(ctx, prefixedGUID("other-org"))
+		otherOrgSpace = createSpaceWithCleanup(ctx, otherOrg.Name, prefixedGUID("other-space"))
+
+		createStartedProcess(space1.Name)
+		createStartedProcess(space2.Name)
+		createStartedProcess(otherOrgSpace.Name)
+
+		stoppedApp := createAppCR(ctx, k8sClient, prefixedGUID("app"), prefixedGUID("app"), space1.Name, string(korifiv1alpha1.StoppedState))
+		createProcessCR(ctx, k8sClient, prefixedGUID("process"), space1.Name, stoppedApp.Name)
+
+		idleProcess := createStartedProcess(space1.Name)
+		meta.SetStatusCondition(&idleProcess.Status.Conditions, metav1.Condition{
+			Type:   korifiv1alpha1.IdleConditionType,
+			Status: metav1.ConditionTrue,
+			Reason: "Idle",
+		})
+		Expect(k8sClient.Status().Update(ctx, idleProcess)).To(Succeed())
+
+		createRoleBinding(ctx, userName, orgUserRole.Name, org.Name)
+		createRoleBinding(ctx, userName, spaceDeveloperRole.Name, space1.Name)
+	})
+
+	Describe("GetOrgUsageSummary", func() {
+		var (
+			usageSummary repositories.UsageSummaryRecord
+			getErr       error
+		)
+
+		JustBeforeEach(func() {
+			usageSummary, getErr = usageSummaryRepo.GetOrgUsageSummary(ctx, authInfo, org.Name)
+		})
+
+		It("sums up the started processes in the visible spaces of the org", func() {
+			Expect(getErr).NotTo(HaveOccurred())
+			Expect(usageSummary).To(Equal(repositories.UsageSummaryRecord{
+				StartedInstances: 1,
+				MemoryMB:         500,
+			}))
+		})
+
+		When("the user can see all spaces", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceDeveloperRole.Name, space2.Name)
+				createRoleBinding(ctx, userName, spaceDeveloperRole.Name, otherOrgSpace.Name)
+			})
+
+			It("only sums up the spaces of the org", func() {
+				Expect(getErr).NotTo(HaveOccurred())
+				Expect(usageSummary).To(Equal(repositories.UsageSummaryRecord{
+					StartedInstances: 2,
+					MemoryMB:         1000,
+				}))
+			})
+		})
+	})
+
+	Describe("GetUsageSummary", func() {
+		var (
+			usageSummary repositories.UsageSummaryRecord
+			getErr       error
+		)
+
+		BeforeEach(func() {
+			createRoleBinding(ctx, userName, spaceDeveloperRole.Name, otherOrgSpace.Name)
+		})
+
+		JustBeforeEach(func() {
+			usageSummary, getErr = usageSummaryRepo.GetUsageSummary(ctx, authInfo)
+		})
+
+		It("sums up the started processes in all visible spaces", func() {
+			Expect(getErr).NotTo(HaveOccurred())
+			Expect(usageSummary).To(Equal(repositories.UsageSummaryRecord{
+				StartedInstances: 2,
+				MemoryMB:         1000,
+			}))
+		})
+	})
+})
@@ -5,17 +5,25 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
+	"code.cloudfoundry.org/korifi/api/payloads/validation"
 	"code.cloudfoundry.org/korifi/api/presenter"
 
 	"github.com/go-logr/logr"
 )
 
+// WarningsHeader is the header the CF CLI displays warnings of to the user
+const WarningsHeader = "X-Cf-Warnings"
+
 type Response struct {
 	httpStatus int
 	body       interface{}
 	headers    map[string][]string
+	warnings   []string
 }
 
 func NewResponse(httpStatus int) *Response {
@@ -30,6 +38,13 @@ func (r *Response) WithHeader(key, value string) *Response {
 	return r
 }
 
+// WithWarning adds a warning for the user to the response, such as the use of
+// a deprecated endpoint or parameter
+func (r *Response) WithWarning(warning string) *Response {
+	r.warnings = append(r.warnings, warning)
+	return r
+}
+
 func (r *Response) WithBody(body interface{}) *Response {
 	r.body = body
 	return r
@@ -49,6 +64,11 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if keys := validation.UndecodedQueryParameters(r); len(keys) > 0 {
+		PresentError(logger, w, apierrors.NewUnknownKeyError(fmt.Errorf("unsupported query parameters: %v", keys), nil))
+		return
+	}
+
 	if err := handlerResponse.writeTo(w); err != nil {
 		_ = apierrors.LogAndReturn(logger, err, "failed to write result to the HTTP response", "handlerResponse", handlerResponse, "method", r.Method, "URL", r.URL)
 	}
@@ -57,7 +77,14 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func PresentError(logger logr.Logger, w http.ResponseWriter, err error) {
 	var apiError apierrors.ApiError
 	if errors.As(err, &apiError) {
-		writeErr := NewResponse(apiError.HttpStatus()).
+		response := NewResponse(apiError.HttpStatus())
+
+		var unavailableErr apierrors.ServiceUnavailableError
+		if errors.As(err, &unavailableErr) {
+			response = response.WithHeader("Retry-After", strconv.Itoa(unavailableErr.RetryAfterSeconds()))
+		}
+
+		writeErr := response.
 			WithBody(presenter.ErrorsResponse{
 				Errors: []presenter.PresentedError{
 					{
@@ -76,6 +103,11 @@ func PresentError(logger logr.Logger, w http.ResponseWriter, err error) {
 		return
 	}
 
+	if apierrors.IsThrottled(err) {
+		PresentError(logger, w, apierrors.ThrottledAsServiceUnavailable(err))
+		return
+	}
+
 	PresentError(logger, w, apierrors.NewUnknownError(err))
 }
 
@@ -86,6 +118,10 @@ func (response *Response) writeTo(w http.ResponseWriter) error {
 		}
 	}
 
+	if len(response.warnings) > 0 {
+		w.Header().Set(WarningsHeader, encodeWarnings(response.warnings))
+	}
+
 	if response.body == nil {
 		w.WriteHeader(response.httpStatus)
 		return nil
@@ -104,3 +140,14 @@ func (response *Response) writeTo(w http.ResponseWriter) error {
 
 	return nil
 }
+
+// encodeWarnings encodes the warnings the way the CF CLI decodes them, i.e.
+// query escaped and comma separated
+func encodeWarnings(warnings []string) string {
+	encodedWarnings := make([]string, 0, len(warnings))
+	for _, warning := range warnings {
+		encodedWarnings = append(encodedWarnings, url.QueryEscape(warning))
+	}
+
+	return strings.Join(encodedWarnings, ",")
+}
@@ -2,13 +2,16 @@ package routing_test
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	apierrors "code.cloudfoundry.org/korifi/api/errors"
 	"code.cloudfoundry.org/korifi/api/routing"
 	"code.cloudfoundry.org/korifi/api/routing/fake"
+	. "code.cloudfoundry.org/korifi/tests/matchers"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 var _ = Describe("Handler", func() {
@@ -71,6 +74,21 @@ var _ = Describe("Handler", func() {
 		})
 	})
 
+	When("the response has warnings", func() {
+		BeforeEach(func() {
+			response = response.WithWarning("stack cflinuxfs3 is deprecated")
+			response = response.WithWarning("memory quota, 90% used")
+		})
+
+		It("sets the encoded warnings header", func() {
+			Expect(rr).To(HaveHTTPHeaderWithValue("X-Cf-Warnings", "stack+cflinuxfs3+is+deprecated,memory+quota%2C+90%25+used"))
+		})
+	})
+
+	It("does not set the warnings header", func() {
+		Expect(rr.Header()).NotTo(HaveKey("X-Cf-Warnings"))
+	})
+
 	When("the delegate returns an unknown error", func() {
 		BeforeEach(func() {
 			delegate.Stub = func(*http.Request) (*routing.Response, error) {
@@ -114,4 +132,40 @@ var _ = Describe("Handler", func() {
 			}`)))
 		})
 	})
+
+	When("the delegate returns a service unavailable error", func() {
+		BeforeEach(func() {
+			delegate.Stub = func(*http.Request) (*routing.Response, error) {
+				return nil, apierrors.NewServiceUnavailableError(errors.New("foo"), 5)
+			}
+		})
+
+		It("presents the error with a retry after header", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusServiceUnavailable))
+			Expect(rr).To(HaveHTTPHeaderWithValue("Retry-After", "5"))
+			Expect(rr).To(HaveHTTPBody(MatchJSONPath("$.errors[0].title", "CF-ServiceUnavailable")))
+		})
+	})
+
+	When("the delegate returns a Kubernetes throttling error", func() {
+		BeforeEach(func() {
+			delegate.Stub = func(*http.Request) (*routing.Response, error) {
+				return nil, fmt.Errorf("failed to get app: %w", k8serrors.NewTooManyRequests("slow down", 3))
+			}
+		})
+
+		It("presents a service unavailable error", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusServiceUnavailable))
+			Expect(rr).To(HaveHTTPHeaderWithValue("Retry-After", "3"))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(`{
+				"errors": [
+					{
+						"title": "CF-ServiceUnavailable",
+						"detail": "The Kubernetes API is throttling requests. Please retry later.",
+						"code": 10015
+					}
+				]
+			}`)))
+		})
+	})
 })
@@ -0,0 +1,258 @@
+package activator
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+
+	"github.com/go-logr/logr"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DestinationHeader and SignatureHeader are set by the HTTPRoutes of
+	// idling processes on the requests they send to the activator.
+	// DestinationHeader holds the route destination as
+	// <namespace>/<route name>/<destination guid>, SignatureHeader its
+	// signature with the activator signing key, so that the activator only
+	// serves requests coming through the gateway.
+	DestinationHeader = "X-Korifi-Destination"
+	SignatureHeader   = "X-Korifi-Signature"
+
+	// requests are recorded at most once per interval, which is well below
+	// the idle timeout granularity of a minute
+	recordInterval = 30 * time.Second
+	pollInterval   = 500 * time.Millisecond
+)
+
+// Sign returns the signature of the destination header value
+func Sign(signingKey []byte, destination string) string {
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write([]byte(destination))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Activator proxies the requests to processes that scale to zero when idle.
+// It records the time of the last request on the CFProcess and, when the
+// process has been scaled to zero, wakes it up and holds the request until an
+// instance is ready to serve it. The process and the service the request is
+// proxied to are derived from the signed route destination, never taken from
+// the request.
+type Activator struct {
+	k8sClient   client.Client
+	reader      client.Reader
+	port        int32
+	wakeTimeout time.Duration
+	signingKey  []byte
+	log         logr.Logger
+}
+
+func New(k8sClient client.Client, reader client.Reader, port int32, wakeTimeout time.Duration, signingKey []byte, log logr.Logger) *Activator {
+	return &Activator{
+		k8sClient:   k8sClient,
+		reader:      reader,
+		port:        port,
+		wakeTimeout: wakeTimeout,
+		signingKey:  signingKey,
+		log:         log,
+	}
+}
+
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=list
+
+// Start implements manager.Runnable
+func (a *Activator) Start(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", a.port),
+		Handler:           a,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		a.log.Info("starting activator", "port", a.port)
+		errChan <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, every replica
+// of the controllers serves requests
+func (a *Activator) NeedLeaderElection() bool {
+	return false
+}
+
+func (a *Activator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	log := a.log.WithValues("destination", r.Header.Get(DestinationHeader))
+
+	if !hmac.Equal([]byte(Sign(a.signingKey, r.Header.Get(DestinationHeader))), []byte(r.Header.Get(SignatureHeader))) {
+		log.Info("request with an invalid signature")
+		http.Error(w, "invalid activator request", http.StatusForbidden)
+		return
+	}
+
+	routeName, destinationGUID, err := parseDestination(r.Header.Get(DestinationHeader))
+	if err != nil {
+		log.Info("invalid activator request", "reason", err)
+		http.Error(w, "invalid activator request", http.StatusBadRequest)
+		return
+	}
+
+	destination, err := a.getDestination(r.Context(), routeName, destinationGUID)
+	if err != nil {
+		log.Info("failed to get route destination", "reason", err)
+		http.Error(w, "route destination not found", http.StatusBadGateway)
+		return
+	}
+
+	cfProcess, err := a.getIdlingProcess(r.Context(), routeName.Namespace, destination)
+	if err != nil {
+		log.Info("failed to get idling process", "reason", err)
+		http.Error(w, "process not found", http.StatusBadGateway)
+		return
+	}
+
+	if err = a.recordRequest(r.Context(), cfProcess); err != nil {
+		log.Info("failed to record request", "reason", err)
+	}
+
+	serviceName := fmt.Sprintf("s-%s", destination.GUID)
+	if isIdle(cfProcess) {
+		log.V(1).Info("waking up idle process")
+		if err = a.waitForReadyEndpoints(r.Context(), routeName.Namespace, serviceName); err != nil {
+			log.Info("process did not wake up", "reason", err)
+			http.Error(w, "the app is starting, try again later", http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	r.Header.Del(DestinationHeader)
+	r.Header.Del(SignatureHeader)
+
+	httputil.NewSingleHostReverseProxy(&url.URL{
+		Scheme: "http",
+		Host:   fmt.Sprintf("%s.%s.svc:%d", serviceName, routeName.Namespace, *destination.Port),
+	}).ServeHTTP(w, r)
+}
+
+func parseDestination(value string) (types.NamespacedName, string, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return types.NamespacedName{}, "", fmt.Errorf("%s must be <namespace>/<route>/<destination guid>", DestinationHeader)
+	}
+
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, parts[2], nil
+}
+
+// getDestination returns the effective route destination, which has a port
+func (a *Activator) getDestination(ctx context.Context, routeName types.NamespacedName, destinationGUID string) (korifiv1alpha1.Destination, error) {
+	cfRoute := &korifiv1alpha1.CFRoute{}
+	if err := a.k8sClient.Get(ctx, routeName, cfRoute); err != nil {
+		return korifiv1alpha1.Destination{}, err
+	}
+
+	for _, destination := range cfRoute.Status.Destinations {
+		if destination.GUID == destinationGUID && destination.Port != nil {
+			return destination, nil
+		}
+	}
+
+	return korifiv1alpha1.Destination{}, fmt.Errorf("route %s has no destination %q", routeName, destinationGUID)
+}
+
+// getIdlingProcess returns the process of the destination, which must have
+// opted into idling
+func (a *Activator) getIdlingProcess(ctx context.Context, namespace string, destination korifiv1alpha1.Destination) (*korifiv1alpha1.CFProcess, error) {
+	cfProcess := &korifiv1alpha1.CFProcess{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+		},
+		Spec: korifiv1alpha1.CFProcessSpec{
+			ProcessType: destination.ProcessType,
+		},
+	}
+	cfProcess.SetStableName(destination.AppRef.Name)
+
+	if err := a.k8sClient.Get(ctx, client.ObjectKeyFromObject(cfProcess), cfProcess); err != nil {
+		return nil, err
+	}
+
+	if _, ok := cfProcess.Annotations[korifiv1alpha1.IdleTimeoutAnnotationKey]; !ok {
+		return nil, fmt.Errorf("process %s/%s has not opted into idling", cfProcess.Namespace, cfProcess.Name)
+	}
+
+	return cfProcess, nil
+}
+
+// recordRequest updates the last request time of the process, which also
+// wakes it up when it is idle
+func (a *Activator) recordRequest(ctx context.Context, cfProcess *korifiv1alpha1.CFProcess) error {
+	now := time.Now()
+	lastRequestTime, err := time.Parse(time.RFC3339, cfProcess.Annotations[korifiv1alpha1.LastRequestTimeAnnotationKey])
+	if err == nil && now.Sub(lastRequestTime) < recordInterval {
+		return nil
+	}
+
+	originalProcess := cfProcess.DeepCopy()
+	if cfProcess.Annotations == nil {
+		cfProcess.Annotations = map[string]string{}
+	}
+	cfProcess.Annotations[korifiv1alpha1.LastRequestTimeAnnotationKey] = now.UTC().Format(time.RFC3339)
+
+	return a.k8sClient.Patch(ctx, cfProcess, client.MergeFrom(originalProcess))
+}
+
+func isIdle(cfProcess *korifiv1alpha1.CFProcess) bool {
+	return meta.IsStatusConditionTrue(cfProcess.Status.Conditions, korifiv1alpha1.IdleConditionType) ||
+		cfProcess.Status.ActualInstances == 0
+}
+
+func (a *Activator) waitForReadyEndpoints(ctx context.Context, namespace, serviceName string) error {
+	return wait.PollUntilContextTimeout(ctx, pollInterval, a.wakeTimeout, true, func(ctx context.Context) (bool, error) {
+		endpointSlices := &discoveryv1.EndpointSliceList{}
+		err := a.reader.List(ctx, endpointSlices,
+			client.InNamespace(namespace),
+			client.MatchingLabels{discoveryv1.LabelServiceName: serviceName},
+		)
+		if err != nil {
+			return false, err
+		}
+
+		return hasReadyEndpoint(endpointSlices.Items), nil
+	})
+}
+
+func hasReadyEndpoint(endpointSlices []discoveryv1.EndpointSlice) bool {
+	for _, endpointSlice := range endpointSlices {
+		for _, endpoint := range endpointSlice.Endpoints {
+			// a nil ready condition is to be interpreted as ready
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				return true
+			}
+		}
+	}
+
+	return false
+}
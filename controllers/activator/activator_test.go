@@ -0,0 +1,189 @@
+package activator_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/korifi/controllers/activator"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Activator", func() {
+	var (
+		namespace   string
+		signingKey  []byte
+		cfProcess   *korifiv1alpha1.CFProcess
+		cfRoute     *korifiv1alpha1.CFRoute
+		destination string
+		request     *http.Request
+		response    *httptest.ResponseRecorder
+	)
+
+	signRequest := func() {
+		request.Header.Set(activator.DestinationHeader, destination)
+		request.Header.Set(activator.SignatureHeader, activator.Sign(signingKey, destination))
+	}
+
+	BeforeEach(func() {
+		namespace = uuid.NewString()
+		Expect(k8sClient.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		})).To(Succeed())
+
+		appGUID := uuid.NewString()
+		cfProcess = &korifiv1alpha1.CFProcess{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Annotations: map[string]string{
+					korifiv1alpha1.IdleTimeoutAnnotationKey:     "30",
+					korifiv1alpha1.LastRequestTimeAnnotationKey: time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+				},
+			},
+			Spec: korifiv1alpha1.CFProcessSpec{
+				AppRef:      corev1.LocalObjectReference{Name: appGUID},
+				ProcessType: korifiv1alpha1.ProcessTypeWeb,
+				MemoryMB:    1024,
+				DiskQuotaMB: 100,
+			},
+		}
+		cfProcess.SetStableName(appGUID)
+		Expect(k8sClient.Create(ctx, cfProcess)).To(Succeed())
+
+		cfRoute = &korifiv1alpha1.CFRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      uuid.NewString(),
+				Namespace: namespace,
+			},
+			Spec: korifiv1alpha1.CFRouteSpec{
+				Host: "my-app",
+				DomainRef: corev1.ObjectReference{
+					Name:      "my-domain",
+					Namespace: namespace,
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, cfRoute)).To(Succeed())
+		Expect(k8s.Patch(ctx, k8sClient, cfRoute, func() {
+			cfRoute.Status.Destinations = []korifiv1alpha1.Destination{{
+				GUID:        "destination-guid",
+				AppRef:      corev1.LocalObjectReference{Name: appGUID},
+				ProcessType: korifiv1alpha1.ProcessTypeWeb,
+				Port:        tools.PtrTo[int32](8080),
+			}}
+		})).To(Succeed())
+
+		signingKey = []byte("the-signing-key")
+		destination = namespace + "/" + cfRoute.Name + "/destination-guid"
+		request = httptest.NewRequest(http.MethodGet, "http://my-app.apps.example.com/", nil)
+		signRequest()
+		response = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		activator.New(controllersClient, controllersClient, 8000, time.Second, []byte("the-signing-key"), log.Log).ServeHTTP(response, request)
+	})
+
+	getLastRequestTime := func() time.Time {
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cfProcess), cfProcess)).To(Succeed())
+		lastRequestTime, err := time.Parse(time.RFC3339, cfProcess.Annotations[korifiv1alpha1.LastRequestTimeAnnotationKey])
+		Expect(err).NotTo(HaveOccurred())
+		return lastRequestTime
+	}
+
+	When("the process is idle", func() {
+		BeforeEach(func() {
+			Expect(k8s.Patch(ctx, k8sClient, cfProcess, func() {
+				meta.SetStatusCondition(&cfProcess.Status.Conditions, metav1.Condition{
+					Type:   korifiv1alpha1.IdleConditionType,
+					Status: metav1.ConditionTrue,
+					Reason: "NoRequests",
+				})
+			})).To(Succeed())
+		})
+
+		It("wakes the process up by recording the request", func() {
+			Expect(getLastRequestTime()).To(BeTemporally("~", time.Now(), 5*time.Second))
+		})
+
+		It("responds with service unavailable when no instance gets ready within the wake timeout", func() {
+			Expect(response.Code).To(Equal(http.StatusServiceUnavailable))
+		})
+	})
+
+	When("the request is not signed", func() {
+		BeforeEach(func() {
+			request.Header.Del(activator.SignatureHeader)
+		})
+
+		It("responds with forbidden", func() {
+			Expect(response.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("does not record the request", func() {
+			Expect(getLastRequestTime()).To(BeTemporally("<", time.Now().Add(-time.Minute)))
+		})
+	})
+
+	When("the request is signed with another key", func() {
+		BeforeEach(func() {
+			signingKey = []byte("another-key")
+			signRequest()
+		})
+
+		It("responds with forbidden", func() {
+			Expect(response.Code).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	When("the destination is malformed", func() {
+		BeforeEach(func() {
+			destination = namespace + "/" + cfRoute.Name
+			signRequest()
+		})
+
+		It("responds with bad request", func() {
+			Expect(response.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	When("the route has no such destination", func() {
+		BeforeEach(func() {
+			destination = namespace + "/" + cfRoute.Name + "/another-destination-guid"
+			signRequest()
+		})
+
+		It("responds with bad gateway", func() {
+			Expect(response.Code).To(Equal(http.StatusBadGateway))
+		})
+	})
+
+	When("the process has not opted into idling", func() {
+		BeforeEach(func() {
+			Expect(k8s.PatchResource(ctx, k8sClient, cfProcess, func() {
+				delete(cfProcess.Annotations, korifiv1alpha1.IdleTimeoutAnnotationKey)
+			})).To(Succeed())
+		})
+
+		It("responds with bad gateway", func() {
+			Expect(response.Code).To(Equal(http.StatusBadGateway))
+		})
+
+		It("does not record the request", func() {
+			Expect(getLastRequestTime()).To(BeTemporally("<", time.Now().Add(-time.Minute)))
+		})
+	})
+})
@@ -52,6 +52,38 @@ type AppWorkloadSpec struct {
 
 	// +kubebuilder:validation:Optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// The number of seconds the instances are given to shut down gracefully before they are killed
+	// +kubebuilder:validation:Optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// The node labels the instances must be scheduled on, e.g. to place them on a dedicated node pool
+	// +kubebuilder:validation:Optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// The tolerations of the instances, allowing them to be scheduled on tainted nodes
+	// +kubebuilder:validation:Optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// The name of the PriorityClass of the instances, e.g. to run them in a QoS tier
+	// +kubebuilder:validation:Optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// The name of the RuntimeClass the instances are run with, e.g. to sandbox them
+	// +kubebuilder:validation:Optional
+	RuntimeClassName string `json:"runtimeClassName,omitempty"`
+
+	// Additional containers run on the image next to the app container in every instance
+	// +kubebuilder:validation:Optional
+	Sidecars []AppWorkloadSidecar `json:"sidecars,omitempty"`
+}
+
+type AppWorkloadSidecar struct {
+	Name    string   `json:"name"`
+	Command []string `json:"command"`
+
+	// +kubebuilder:validation:Optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // AppWorkloadStatus defines the observed state of AppWorkload
@@ -64,10 +96,16 @@ type AppWorkloadStatus struct {
 
 	//+kubebuilder:validation:Optional
 	ActualInstances int32 `json:"actualInstances"`
+
+	// The label selector of the instances, used by the scale subresource to
+	// let e.g. horizontal pod autoscalers find the instances of the workload
+	//+kubebuilder:validation:Optional
+	Selector string `json:"selector,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:subresource:scale:specpath=.spec.instances,statuspath=.status.actualInstances,selectorpath=.status.selector
 
 // AppWorkload is the Schema for the appworkloads API
 type AppWorkload struct {
@@ -33,7 +33,7 @@ type BuildWorkloadSpec struct {
 	// The details necessary to pull the image containing the application source
 	Source PackageSource `json:"source,omitempty"`
 
-	// Buildpacks to include in auto-detection when building the app image.
+	// Buildpacks to build the app image with, in the order they should run.
 	// If no values are specified, then all available buildpacks will be used for auto-detection
 	Buildpacks []string `json:"buildpacks,omitempty"`
 
@@ -42,7 +42,17 @@ type BuildWorkloadSpec struct {
 
 	Services []v1.ObjectReference `json:"services,omitempty"`
 
-	// The name of the builder that should reconcile this BuildWorkload resource and execute the image building
+	// The memory in MB requested for building the image. Defaults to the staging memory configured for the installation
+	StagingMemoryMB int `json:"stagingMemoryMB,omitempty"`
+
+	// The ephemeral disk in MB requested for building the image. Defaults to the staging disk configured for the installation
+	StagingDiskMB int `json:"stagingDiskMB,omitempty"`
+
+	// Whether to build the image without the cache of previous builds
+	DisableBuildCache bool `json:"disableBuildCache,omitempty"`
+
+	// The name of the builder that should reconcile this BuildWorkload resource and execute the image building.
+	// Builders must ignore BuildWorkloads that name another builder
 	// +kubebuilder:validation:Required
 	BuilderName string `json:"builderName"`
 }
@@ -61,7 +71,10 @@ type BuildWorkloadStatus struct {
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 
-// BuildWorkload is the Schema for the buildworkloads API
+// BuildWorkload is the Schema for the buildworkloads API. BuildWorkloads are
+// the interface to the build system: they are reconciled by the build
+// reconciler named in Spec.BuilderName, which reports the built droplet back
+// via the status. See docs/build-reconciler-contract.md for the details.
 type BuildWorkload struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -42,6 +42,9 @@ type CFAppSpec struct {
 	// The name of a Secret in the same namespace, which contains the environment variables to be set on every one of its running containers (via AppWorkload)
 	EnvSecretName string `json:"envSecretName,omitempty"`
 
+	// The name of a Secret in the same namespace, which contains the environment variables to be set on the app builds only (via BuildWorkload). They override the EnvSecretName environment variables with the same name
+	StagingEnvSecretName string `json:"stagingEnvSecretName,omitempty"`
+
 	// A reference to the CFBuild currently assigned to the app. The CFBuild must be in the same namespace.
 	CurrentDropletRef v1.LocalObjectReference `json:"currentDropletRef,omitempty"`
 }
@@ -76,6 +79,11 @@ type CFAppStatus struct {
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Display Name",type=string,JSONPath=`.spec.displayName`
+//+kubebuilder:printcolumn:name="Desired State",type=string,JSONPath=`.spec.desiredState`
+//+kubebuilder:printcolumn:name="Actual State",type=string,JSONPath=`.status.actualState`
+//+kubebuilder:printcolumn:name="Staged",type=string,JSONPath=`.status.conditions[?(@.type=='Staged')].status`
+//+kubebuilder:printcolumn:name="Running",type=string,JSONPath=`.status.conditions[?(@.type=='Running')].status`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=='Ready')].status`
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`
 
 // CFApp is the Schema for the cfapps API
@@ -0,0 +1,88 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	AppUsageStateStarted        = "STARTED"
+	AppUsageStateStopped        = "STOPPED"
+	AppUsageStateStagingStarted = "STAGING_STARTED"
+	AppUsageStateStagingStopped = "STAGING_STOPPED"
+)
+
+// CFAppUsageEventSpec defines a change in the usage of an app process
+type CFAppUsageEventSpec struct {
+	// The time the usage changed, with the precision the events are ordered by
+	Timestamp metav1.MicroTime `json:"timestamp"`
+	// The usage state of the process, one of STARTED, STOPPED, STAGING_STARTED or STAGING_STOPPED
+	State string `json:"state"`
+	// The usage state of the process before the change
+	// +optional
+	PreviousState string `json:"previousState,omitempty"`
+
+	AppGUID string `json:"appGUID"`
+	AppName string `json:"appName"`
+	// +optional
+	ProcessGUID string `json:"processGUID,omitempty"`
+	// +optional
+	ProcessType string `json:"processType,omitempty"`
+	SpaceGUID   string `json:"spaceGUID"`
+	SpaceName   string `json:"spaceName"`
+	OrgGUID     string `json:"orgGUID"`
+
+	// +optional
+	InstanceCount int32 `json:"instanceCount"`
+	// +optional
+	PreviousInstanceCount int32 `json:"previousInstanceCount"`
+	// +optional
+	MemoryMB int64 `json:"memoryMB"`
+	// +optional
+	PreviousMemoryMB int64 `json:"previousMemoryMB"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="State",type=string,JSONPath=`.spec.state`
+//+kubebuilder:printcolumn:name="App",type=string,JSONPath=`.spec.appName`
+//+kubebuilder:printcolumn:name="Process Type",type=string,JSONPath=`.spec.processType`
+//+kubebuilder:printcolumn:name="Instances",type=integer,JSONPath=`.spec.instanceCount`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// CFAppUsageEvent is the Schema for the cfappusageevents API. The events are
+// recorded by the controllers in the root namespace and deleted once they are
+// older than the configured retention
+type CFAppUsageEvent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CFAppUsageEventSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CFAppUsageEventList contains a list of CFAppUsageEvent
+type CFAppUsageEventList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CFAppUsageEvent `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CFAppUsageEvent{}, &CFAppUsageEventList{})
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const AuditEventTypeAppCrash = "app.crash"
+
+// CFAuditEventSpec defines an action on a resource of a space
+type CFAuditEventSpec struct {
+	// The type of the event, e.g. app.crash
+	Type string `json:"type"`
+
+	ActorGUID string `json:"actorGUID"`
+	ActorType string `json:"actorType"`
+	// +optional
+	ActorName string `json:"actorName,omitempty"`
+
+	TargetGUID string `json:"targetGUID"`
+	TargetType string `json:"targetType"`
+
+	// The details of app.crash events
+	// +optional
+	AppCrash *AppCrash `json:"appCrash,omitempty"`
+}
+
+// AppCrash describes the crash of an app instance
+type AppCrash struct {
+	InstanceName string `json:"instanceName"`
+	// The index of the instance, set by runners that index their instances
+	// +optional
+	InstanceIndex int32 `json:"instanceIndex"`
+	ExitStatus    int32 `json:"exitStatus"`
+	// +optional
+	ExitReason string `json:"exitReason,omitempty"`
+	// +optional
+	ExitDescription string `json:"exitDescription,omitempty"`
+	// How many times the instance has crashed
+	CrashCount int32 `json:"crashCount"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+//+kubebuilder:printcolumn:name="Actor",type=string,JSONPath=`.spec.actorName`
+//+kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.spec.targetGUID`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// CFAuditEvent is the Schema for the cfauditevents API. The events are
+// recorded in the space namespace of their target and deleted once they are
+// older than the configured retention
+type CFAuditEvent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CFAuditEventSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CFAuditEventList contains a list of CFAuditEvent
+type CFAuditEventList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CFAuditEvent `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CFAuditEvent{}, &CFAuditEventList{})
+}
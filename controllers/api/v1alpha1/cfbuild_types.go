@@ -28,13 +28,17 @@ type CFBuildSpec struct {
 	// The CFApp associated with this build. Must be in the same namespace
 	AppRef v1.LocalObjectReference `json:"appRef"`
 
-	// The memory limit for the pod that will stage the image
+	// The memory request for the pod that will stage the image
 	StagingMemoryMB int `json:"stagingMemoryMB"`
-	// Unimplemented: StagingDiskMB is the ephemeral-disk size request for the pod that will stage the image
+	// The ephemeral-disk size request for the pod that will stage the image
 	StagingDiskMB int `json:"stagingDiskMB"`
 
 	// Specifies the buildpacks and stack for the build
 	Lifecycle Lifecycle `json:"lifecycle"`
+
+	// A boolean describing whether the CFBuild has been canceled
+	// +optional
+	Canceled bool `json:"canceled"`
 }
 
 // CFBuildStatus defines the observed state of CFBuild
@@ -63,6 +67,16 @@ type BuildDropletStatus struct {
 	// The exposed ports for the application
 	//+kubebuilder:validation:Optional
 	Ports []int32 `json:"ports"`
+
+	// The buildpacks that took part in building the Droplet, in order
+	//+kubebuilder:validation:Optional
+	Buildpacks []DropletBuildpack `json:"buildpacks,omitempty"`
+}
+
+// DropletBuildpack is a buildpack that took part in building the Droplet
+type DropletBuildpack struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
 }
 
 // ProcessType is a map of process names and associated start commands for the Droplet
@@ -74,7 +88,8 @@ type ProcessType struct {
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="AppGUID",type=string,JSONPath=`.spec.appRef.name`
-//+kubebuilder:printcolumn:name="Staged",type=string,JSONPath=`.status.conditions[?(@.type=='Succeeded')].status`
+//+kubebuilder:printcolumn:name="Staged",type=string,JSONPath=`.status.conditions[?(@.type=='Staged')].status`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=='Ready')].status`
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`
 
 // CFBuild is the Schema for the cfbuilds API
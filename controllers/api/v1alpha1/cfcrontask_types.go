@@ -0,0 +1,96 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CFCronTaskSpec defines the desired state of CFCronTask
+type CFCronTaskSpec struct {
+	// The command used to start the task process
+	Command string `json:"command"`
+	// A reference to the CFApp containing the code or script for this CFCronTask
+	AppRef corev1.LocalObjectReference `json:"appRef"`
+	// The cron schedule to run the task on, e.g. "0 2 * * *" or "@daily"
+	Schedule string `json:"schedule"`
+	// A boolean describing whether scheduled runs of the task are suspended
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// The memory limit of every run of the task in MB. Defaults to the installation task memory
+	// +optional
+	MemoryMB int64 `json:"memoryMB,omitempty"`
+	// The disk limit of every run of the task in MB. Defaults to the installation task disk quota
+	// +optional
+	DiskQuotaMB int64 `json:"diskQuotaMB,omitempty"`
+}
+
+// CFCronTaskStatus defines the observed state of CFCronTask
+type CFCronTaskStatus struct {
+	//+kubebuilder:validation:Optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// +optional
+	MemoryMB int64 `json:"memoryMB"`
+	// +optional
+	DiskQuotaMB int64 `json:"diskQuotaMB"`
+	// The droplet the task currently runs with
+	// +optional
+	DropletRef corev1.LocalObjectReference `json:"dropletRef"`
+	// The last time the task was run
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// ObservedGeneration captures the latest generation of the CFCronTask that has been reconciled
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="AppGUID",type=string,JSONPath=`.spec.appRef.name`
+//+kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+//+kubebuilder:printcolumn:name="Suspended",type=boolean,JSONPath=`.spec.suspend`
+//+kubebuilder:printcolumn:name="Last Schedule",type="date",JSONPath=`.status.lastScheduleTime`
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=='Ready')].status`
+
+// CFCronTask is the Schema for the cfcrontasks API. It runs a task of an app
+// on a recurring schedule
+type CFCronTask struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CFCronTaskSpec   `json:"spec,omitempty"`
+	Status CFCronTaskStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CFCronTaskList contains a list of CFCronTask
+type CFCronTaskList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CFCronTask `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CFCronTask{}, &CFCronTaskList{})
+}
+
+func (t *CFCronTask) StatusConditions() *[]metav1.Condition {
+	return &t.Status.Conditions
+}
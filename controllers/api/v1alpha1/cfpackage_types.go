@@ -23,6 +23,8 @@ import (
 
 const (
 	CFPackageFinalizerName = "korifi.cloudfoundry.org/cfPackageController"
+
+	SHA256ChecksumType = "sha256"
 )
 
 // CFPackageSpec defines the desired state of CFPackage
@@ -43,7 +45,33 @@ type PackageType string
 
 type PackageSource struct {
 	// registry (i.e an OCI image in a registry that contains application source)
+	//+kubebuilder:validation:Optional
 	Registry Registry `json:"registry"`
+
+	// blob (i.e. an archive in a blobstore that contains application source).
+	// Takes precedence over the registry when set
+	//+kubebuilder:validation:Optional
+	Blob *Blob `json:"blob,omitempty"`
+
+	// The digest of the uploaded source archive
+	//+kubebuilder:validation:Optional
+	Checksum *Checksum `json:"checksum,omitempty"`
+}
+
+// Blob identifies an application source archive in a blobstore
+type Blob struct {
+	// The URL the source archive can be downloaded from
+	URL string `json:"url"`
+}
+
+// Checksum is the digest of an application source archive
+type Checksum struct {
+	// The digest algorithm
+	//+kubebuilder:validation:Enum=sha256
+	Type string `json:"type"`
+
+	// The hex encoded digest
+	Value string `json:"value"`
 }
 
 // CFPackageStatus defines the observed state of CFPackage
@@ -51,6 +79,10 @@ type CFPackageStatus struct {
 	//+kubebuilder:validation:Optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
+	// The digest of the source archive of the ready package
+	//+kubebuilder:validation:Optional
+	Checksum *Checksum `json:"checksum,omitempty"`
+
 	// ObservedGeneration captures the latest generation of the CFPackage that has been reconciled
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
@@ -45,6 +45,10 @@ type CFProcessSpec struct {
 	// Used to build the Liveness and Readiness Probes for the process' AppWorkload.
 	HealthCheck HealthCheck `json:"healthCheck"`
 
+	// Used to build the Readiness Probe for the process' AppWorkload. When its type is not set, the Readiness Probe is built from the HealthCheck
+	// +kubebuilder:validation:Optional
+	ReadinessHealthCheck ReadinessHealthCheck `json:"readinessHealthCheck,omitempty"`
+
 	// The desired number of replicas to deploy
 	DesiredInstances *int32 `json:"desiredInstances,omitempty"`
 
@@ -54,10 +58,33 @@ type CFProcessSpec struct {
 	// The disk limit in MiB
 	DiskQuotaMB int64 `json:"diskQuotaMB"`
 
+	// The number of seconds the process instances are given to shut down gracefully before they are killed
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
 	// The ports to expose
 	// Deprecated: No longer used
 	// +kubebuilder:validation:Optional
 	Ports []int32 `json:"ports,omitempty"`
+
+	// Additional commands run next to the process in every instance
+	// +kubebuilder:validation:Optional
+	Sidecars []Sidecar `json:"sidecars,omitempty"`
+}
+
+// Sidecar is a command run on the app image next to the process, e.g. an
+// agent the app talks to over localhost
+type Sidecar struct {
+	// The name of the sidecar, unique within the process
+	Name string `json:"name"`
+
+	// The command to run the sidecar with
+	Command string `json:"command"`
+
+	// The memory limit in MiB, taken from the memory of the process. The sidecar shares the memory left to the process when not set
+	// +kubebuilder:validation:Optional
+	MemoryMB int64 `json:"memoryMB,omitempty"`
 }
 
 type HealthCheck struct {
@@ -83,6 +110,26 @@ type HealthCheckData struct {
 	TimeoutSeconds           int32 `json:"timeoutSeconds"`
 }
 
+type ReadinessHealthCheck struct {
+	// The type of Readiness Health Check the App process will use
+	// Valid values are "http", "port", and "process".
+	Type HealthCheckType `json:"type,omitempty"`
+
+	// The input parameters for the readiness probe in kubernetes
+	Data ReadinessHealthCheckData `json:"data,omitempty"`
+}
+
+// ReadinessHealthCheckData used to pass through input parameters to readiness probe
+type ReadinessHealthCheckData struct {
+	// The http endpoint to use with "http" readiness healthchecks
+	HTTPEndpoint string `json:"httpEndpoint,omitempty"`
+
+	InvocationTimeoutSeconds int32 `json:"invocationTimeoutSeconds,omitempty"`
+
+	// The number of seconds between readiness checks
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+}
+
 // CFProcessStatus defines the observed state of CFProcess
 type CFProcessStatus struct {
 	//+kubebuilder:validation:Optional
@@ -97,6 +144,13 @@ type CFProcessStatus struct {
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="AppGUID",type=string,JSONPath=`.spec.appRef.name`
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.processType`
+//+kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=`.spec.desiredInstances`
+//+kubebuilder:printcolumn:name="Actual",type=integer,JSONPath=`.status.actualInstances`
+//+kubebuilder:printcolumn:name="Running",type=string,JSONPath=`.status.conditions[?(@.type=='Running')].status`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=='Ready')].status`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`
 
 // CFProcess is the Schema for the cfprocesses API
 type CFProcess struct {
@@ -18,27 +18,48 @@ package v1alpha1
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	"code.cloudfoundry.org/korifi/tools"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // log is for logging in this package.
 var cfprocesslog = logf.Log.WithName("cfprocess-resource")
 
+// +kubebuilder:object:generate=false
 type CFProcessDefaulter struct {
-	defaultMemoryMB    int64
-	defaultDiskQuotaMB int64
-	defaultTimeout     int32
+	k8sClient                            client.Reader
+	rootNamespace                        string
+	defaultMemoryMB                      int64
+	defaultDiskQuotaMB                   int64
+	defaultWebInstances                  int32
+	defaultTimeout                       int32
+	defaultTerminationGracePeriodSeconds int64
 }
 
-func NewCFProcessDefaulter(defaultMemoryMB, defaultDiskQuotaMB int64, defaultTimeout int32) *CFProcessDefaulter {
+func NewCFProcessDefaulter(
+	k8sClient client.Reader,
+	rootNamespace string,
+	defaultMemoryMB, defaultDiskQuotaMB int64,
+	defaultWebInstances int32,
+	defaultTimeout int32,
+	defaultTerminationGracePeriodSeconds int64,
+) *CFProcessDefaulter {
 	return &CFProcessDefaulter{
-		defaultMemoryMB:    defaultMemoryMB,
-		defaultDiskQuotaMB: defaultDiskQuotaMB,
-		defaultTimeout:     defaultTimeout,
+		k8sClient:                            k8sClient,
+		rootNamespace:                        rootNamespace,
+		defaultMemoryMB:                      defaultMemoryMB,
+		defaultDiskQuotaMB:                   defaultDiskQuotaMB,
+		defaultWebInstances:                  defaultWebInstances,
+		defaultTimeout:                       defaultTimeout,
+		defaultTerminationGracePeriodSeconds: defaultTerminationGracePeriodSeconds,
 	}
 }
 
@@ -56,9 +77,12 @@ func (d *CFProcessDefaulter) Default(ctx context.Context, obj runtime.Object) er
 	cfprocesslog.V(1).Info("mutating CFProcess webhook handler", "name", process.Name)
 
 	d.defaultLabels(process)
-	d.defaultResources(process)
+	if err := d.defaultResources(ctx, process); err != nil {
+		return err
+	}
 	d.defaultInstances(process)
 	d.defaultHealthCheck(process)
+	d.defaultTerminationGracePeriod(process)
 
 	return nil
 }
@@ -77,14 +101,91 @@ func (d *CFProcessDefaulter) defaultLabels(process *CFProcess) {
 	process.SetLabels(processLabels)
 }
 
-func (d *CFProcessDefaulter) defaultResources(process *CFProcess) {
+func (d *CFProcessDefaulter) defaultResources(ctx context.Context, process *CFProcess) error {
+	if process.Spec.MemoryMB != 0 && process.Spec.DiskQuotaMB != 0 {
+		return nil
+	}
+
+	cfOrg, cfSpace, err := d.getOrgAndSpace(ctx, process.Namespace)
+	if err != nil {
+		return err
+	}
+
 	if process.Spec.MemoryMB == 0 {
-		process.Spec.MemoryMB = d.defaultMemoryMB
+		process.Spec.MemoryMB, err = orgOrSpaceDefault(cfOrg, cfSpace, DefaultProcessMemoryMBAnnotationKey, d.defaultMemoryMB)
+		if err != nil {
+			return err
+		}
 	}
 
 	if process.Spec.DiskQuotaMB == 0 {
-		process.Spec.DiskQuotaMB = d.defaultDiskQuotaMB
+		process.Spec.DiskQuotaMB, err = orgOrSpaceDefault(cfOrg, cfSpace, DefaultProcessDiskQuotaMBAnnotationKey, d.defaultDiskQuotaMB)
+		if err != nil {
+			return err
+		}
 	}
+
+	return nil
+}
+
+// getOrgAndSpace returns the CFOrg and CFSpace of the space namespace. Either
+// of them is empty when it cannot be found, e.g. when the namespace is not a
+// space namespace, in which case the installation defaults apply.
+func (d *CFProcessDefaulter) getOrgAndSpace(ctx context.Context, spaceGUID string) (*CFOrg, *CFSpace, error) {
+	cfOrg := &CFOrg{}
+	cfSpace := &CFSpace{}
+
+	spaceNamespace := &corev1.Namespace{}
+	if err := d.getIgnoringNotFound(ctx, client.ObjectKey{Name: spaceGUID}, spaceNamespace); err != nil {
+		return nil, nil, err
+	}
+
+	orgGUID := spaceNamespace.Labels[OrgGUIDKey]
+	if orgGUID == "" {
+		return cfOrg, cfSpace, nil
+	}
+
+	if err := d.getIgnoringNotFound(ctx, client.ObjectKey{Namespace: orgGUID, Name: spaceGUID}, cfSpace); err != nil {
+		return nil, nil, err
+	}
+
+	if err := d.getIgnoringNotFound(ctx, client.ObjectKey{Namespace: d.rootNamespace, Name: orgGUID}, cfOrg); err != nil {
+		return nil, nil, err
+	}
+
+	return cfOrg, cfSpace, nil
+}
+
+func (d *CFProcessDefaulter) getIgnoringNotFound(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	err := d.k8sClient.Get(ctx, key, obj)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %T %q: %w", obj, key, err)
+	}
+
+	return nil
+}
+
+// orgOrSpaceDefault returns the value of the annotation on the space, the org
+// or the installation default, in this order of precedence
+func orgOrSpaceDefault(cfOrg *CFOrg, cfSpace *CFSpace, key string, installationDefault int64) (int64, error) {
+	for _, obj := range []client.Object{cfSpace, cfOrg} {
+		value, ok := obj.GetAnnotations()[key]
+		if !ok {
+			continue
+		}
+
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			return 0, fmt.Errorf("invalid %s annotation %q on %T %q: must be a positive integer", key, value, obj, obj.GetName())
+		}
+
+		return parsed, nil
+	}
+
+	return installationDefault, nil
 }
 
 func (d *CFProcessDefaulter) defaultInstances(process *CFProcess) {
@@ -94,11 +195,19 @@ func (d *CFProcessDefaulter) defaultInstances(process *CFProcess) {
 
 	defaultInstances := int32(0)
 	if process.Spec.ProcessType == ProcessTypeWeb {
-		defaultInstances = 1
+		defaultInstances = d.defaultWebInstances
 	}
 	process.Spec.DesiredInstances = tools.PtrTo[int32](defaultInstances)
 }
 
+func (d *CFProcessDefaulter) defaultTerminationGracePeriod(process *CFProcess) {
+	if process.Spec.TerminationGracePeriodSeconds != nil {
+		return
+	}
+
+	process.Spec.TerminationGracePeriodSeconds = tools.PtrTo(d.defaultTerminationGracePeriodSeconds)
+}
+
 func (d *CFProcessDefaulter) defaultHealthCheck(process *CFProcess) {
 	if process.Spec.HealthCheck.Data.TimeoutSeconds == 0 {
 		process.Spec.HealthCheck.Data.TimeoutSeconds = d.defaultTimeout
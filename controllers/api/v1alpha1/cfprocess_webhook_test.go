@@ -5,6 +5,7 @@ import (
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/tools"
+	"code.cloudfoundry.org/korifi/tools/k8s"
 
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
@@ -104,6 +105,120 @@ var _ = Describe("CFProcessMutatingWebhook", func() {
 		})
 	})
 
+	Describe("org and space defaults", func() {
+		var (
+			cfOrg   *korifiv1alpha1.CFOrg
+			cfSpace *korifiv1alpha1.CFSpace
+		)
+
+		BeforeEach(func() {
+			orgGUID := uuid.NewString()
+			spaceGUID := uuid.NewString()
+
+			Expect(adminClient.Create(ctx, &v1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: orgGUID},
+			})).To(Succeed())
+			Expect(adminClient.Create(ctx, &v1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   spaceGUID,
+					Labels: map[string]string{korifiv1alpha1.OrgGUIDKey: orgGUID},
+				},
+			})).To(Succeed())
+
+			cfOrg = &korifiv1alpha1.CFOrg{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      orgGUID,
+					Namespace: namespace,
+					Annotations: map[string]string{
+						korifiv1alpha1.DefaultProcessMemoryMBAnnotationKey:    "512",
+						korifiv1alpha1.DefaultProcessDiskQuotaMBAnnotationKey: "2048",
+					},
+				},
+				Spec: korifiv1alpha1.CFOrgSpec{DisplayName: uuid.NewString()},
+			}
+			Expect(adminClient.Create(ctx, cfOrg)).To(Succeed())
+
+			cfSpace = &korifiv1alpha1.CFSpace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      spaceGUID,
+					Namespace: orgGUID,
+				},
+				Spec: korifiv1alpha1.CFSpaceSpec{DisplayName: uuid.NewString()},
+			}
+			Expect(adminClient.Create(ctx, cfSpace)).To(Succeed())
+
+			cfProcess.Namespace = spaceGUID
+		})
+
+		It("uses the org defaults", func() {
+			Expect(cfProcess.Spec.MemoryMB).To(BeEquivalentTo(512))
+			Expect(cfProcess.Spec.DiskQuotaMB).To(BeEquivalentTo(2048))
+		})
+
+		When("the space overrides the default memory", func() {
+			BeforeEach(func() {
+				Expect(k8s.PatchResource(ctx, adminClient, cfSpace, func() {
+					cfSpace.Annotations = map[string]string{
+						korifiv1alpha1.DefaultProcessMemoryMBAnnotationKey: "256",
+					}
+				})).To(Succeed())
+			})
+
+			It("uses the space default memory and the org default disk", func() {
+				Expect(cfProcess.Spec.MemoryMB).To(BeEquivalentTo(256))
+				Expect(cfProcess.Spec.DiskQuotaMB).To(BeEquivalentTo(2048))
+			})
+		})
+
+		When("the process already has memory and disk set", func() {
+			BeforeEach(func() {
+				cfProcess.Spec.MemoryMB = 42
+				cfProcess.Spec.DiskQuotaMB = 43
+			})
+
+			It("preserves them", func() {
+				Expect(cfProcess.Spec.MemoryMB).To(BeEquivalentTo(42))
+				Expect(cfProcess.Spec.DiskQuotaMB).To(BeEquivalentTo(43))
+			})
+		})
+
+		When("the org default is invalid", func() {
+			It("denies the process", func() {
+				Expect(k8s.PatchResource(ctx, adminClient, cfOrg, func() {
+					cfOrg.Annotations[korifiv1alpha1.DefaultProcessMemoryMBAnnotationKey] = "lots"
+				})).To(Succeed())
+
+				anotherProcess := &korifiv1alpha1.CFProcess{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      uuid.NewString(),
+						Namespace: cfProcess.Namespace,
+					},
+					Spec: korifiv1alpha1.CFProcessSpec{
+						AppRef:      v1.LocalObjectReference{Name: cfAppGUID},
+						ProcessType: "web",
+					},
+				}
+				Expect(adminClient.Create(ctx, anotherProcess)).To(MatchError(ContainSubstring("must be a positive integer")))
+			})
+		})
+	})
+
+	Describe("termination grace period", func() {
+		It("sets the configured default termination grace period", func() {
+			Expect(cfProcess.Spec.TerminationGracePeriodSeconds).To(gstruct.PointTo(BeEquivalentTo(defaultTerminationGracePeriodSeconds)))
+		})
+
+		When("the process has the termination grace period set", func() {
+			BeforeEach(func() {
+				cfProcess.Spec.TerminationGracePeriodSeconds = tools.PtrTo[int64](0)
+			})
+
+			It("preserves it", func() {
+				Expect(cfProcess.Spec.TerminationGracePeriodSeconds).To(gstruct.PointTo(BeZero()))
+			})
+		})
+	})
+
 	Describe("instances", func() {
 		It("defaults desired instances to zero", func() {
 			Expect(cfProcess.Spec.DesiredInstances).To(gstruct.PointTo(BeZero()))
@@ -124,8 +239,8 @@ var _ = Describe("CFProcessMutatingWebhook", func() {
 				cfProcess.Spec.ProcessType = "web"
 			})
 
-			It("defaults instances to 1", func() {
-				Expect(cfProcess.Spec.DesiredInstances).To(gstruct.PointTo(BeEquivalentTo(1)))
+			It("defaults instances to the configured web instances", func() {
+				Expect(cfProcess.Spec.DesiredInstances).To(gstruct.PointTo(BeEquivalentTo(defaultWebInstances)))
 			})
 
 			When("the process has the instance number set", func() {
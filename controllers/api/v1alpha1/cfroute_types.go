@@ -42,8 +42,8 @@ type Destination struct {
 	AppRef v1.LocalObjectReference `json:"appRef"`
 	// The process type on the CFApp app which will receive traffic
 	ProcessType string `json:"processType"`
-	// Protocol is optional, when set must be "http1"
-	// +kubebuilder:validation:Enum=http1
+	// Protocol is optional, when set must be "http1", or "tcp" for tcp routes
+	// +kubebuilder:validation:Enum=http1;tcp
 	//+kubebuilder:validation:Optional
 	Protocol *string `json:"protocol,omitempty"`
 }
@@ -52,6 +52,11 @@ type Destination struct {
 // +kubebuilder:validation:Enum=http;tcp
 type Protocol string
 
+const (
+	ProtocolHTTP Protocol = "http"
+	ProtocolTCP  Protocol = "tcp"
+)
+
 // CFRouteSpec defines the desired state of CFRoute
 type CFRouteSpec struct {
 	// The subdomain of the route within the domain. Host is optional and defaults to empty.
@@ -59,8 +64,14 @@ type CFRouteSpec struct {
 	Host string `json:"host,omitempty"`
 	// Path is optional, defaults to empty
 	Path string `json:"path,omitempty"`
-	// Protocol is optional and defaults to http. Currently only http is supported
+	// Protocol is optional and defaults to http. Routes with the tcp protocol
+	// have no host nor path, they forward all connections to their port on the
+	// gateway to their destinations
 	Protocol Protocol `json:"protocol,omitempty"`
+	// Port of tcp routes on the gateway, which must be one of the tcp ports
+	// Korifi is configured with
+	//+kubebuilder:validation:Optional
+	Port int32 `json:"port,omitempty"`
 	// A reference to the CFDomain this CFRoute is assigned to, including name and namespace
 	DomainRef v1.ObjectReference `json:"domainRef"`
 	// Destinations are optional. A route can exist without any destinations, independently of any CFApps
@@ -88,6 +99,7 @@ type CFRouteStatus struct {
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="URI",type=string,JSONPath=`.status.uri`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=='Ready')].status`
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`
 
 // CFRoute is the Schema for the cfroutes API
@@ -113,10 +125,20 @@ func init() {
 }
 
 func (r CFRoute) UniqueName() string {
+	// all tcp routes share the tcp listeners of the gateway, so a port can
+	// only be used by a single route, whatever its domain
+	if r.Spec.Protocol == ProtocolTCP {
+		return strings.Join([]string{string(ProtocolTCP), fmt.Sprint(r.Spec.Port)}, "::")
+	}
+
 	return strings.Join([]string{strings.ToLower(r.Spec.Host), r.Spec.DomainRef.Namespace, r.Spec.DomainRef.Name, r.Spec.Path}, "::")
 }
 
 func (r CFRoute) UniqueValidationErrorMessage() string {
+	if r.Spec.Protocol == ProtocolTCP {
+		return fmt.Sprintf("Port '%d' is already used by another route.", r.Spec.Port)
+	}
+
 	pathDetails := ""
 
 	if r.Spec.Path != "" {
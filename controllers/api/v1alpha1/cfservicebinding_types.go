@@ -50,16 +50,29 @@ type CFServiceBindingStatus struct {
 	// +optional
 	Credentials v1.LocalObjectReference `json:"credentials"`
 
+	// The value of the credentials rotation annotation the binding secret
+	// was last generated for
+	// +optional
+	CredentialsRotation string `json:"credentialsRotation,omitempty"`
+
 	//+kubebuilder:validation:Optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
 	// ObservedGeneration captures the latest generation of the CFServiceBinding that has been reconciled
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// UsageRecorded is set once the BINDING_CREATED service usage event of
+	// the service binding has been recorded
+	// +optional
+	UsageRecorded bool `json:"usageRecorded,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Display Name",type=string,JSONPath=`.spec.displayName`
+//+kubebuilder:printcolumn:name="AppGUID",type=string,JSONPath=`.spec.appRef.name`
+//+kubebuilder:printcolumn:name="Service",type=string,JSONPath=`.spec.service.name`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=='Ready')].status`
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`
 
 // CFServiceBinding is the Schema for the cfservicebindings API
@@ -93,6 +106,28 @@ func (b CFServiceBinding) UniqueValidationErrorMessage() string {
 	return fmt.Sprintf("Service binding already exists: App: %s Service Instance: %s", b.Spec.AppRef.Name, b.Spec.Service.Name)
 }
 
+// Projection returns how the credentials of the binding are exposed to the
+// bound app, see ServiceBindingProjectionAnnotationKey
+func (b CFServiceBinding) Projection(cfApp *CFApp) string {
+	if projection, ok := b.Annotations[ServiceBindingProjectionAnnotationKey]; ok {
+		return projection
+	}
+
+	if projection, ok := cfApp.Annotations[ServiceBindingProjectionAnnotationKey]; ok {
+		return projection
+	}
+
+	return ServiceBindingProjectionAll
+}
+
+func (b CFServiceBinding) ProjectsVCAPServices(cfApp *CFApp) bool {
+	return b.Projection(cfApp) != ServiceBindingProjectionFiles
+}
+
+func (b CFServiceBinding) ProjectsFiles(cfApp *CFApp) bool {
+	return b.Projection(cfApp) != ServiceBindingProjectionVCAPServices
+}
+
 func init() {
 	SchemeBuilder.Register(&CFServiceBinding{}, &CFServiceBindingList{})
 }
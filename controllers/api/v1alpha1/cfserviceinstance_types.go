@@ -54,6 +54,11 @@ type CFServiceInstanceSpec struct {
 	// Tags are used by apps to identify service instances
 	Tags []string `json:"tags,omitempty"`
 
+	// URL of the syslog drain of the service instance, exposed to the bound
+	// apps in VCAP_SERVICES
+	// +optional
+	SyslogDrainURL *string `json:"syslogDrainURL,omitempty"`
+
 	PlanGUID string `json:"plan_guid"`
 
 	Parameters *runtime.RawExtension `json:"parameters,omitempty"`
@@ -83,6 +88,11 @@ type CFServiceInstanceStatus struct {
 
 	ProvisionOperation   string `json:"provisionOperation,omitempty"`
 	DeprovisionOperation string `json:"deprovisionOperation,omitempty"`
+
+	// UsageRecorded is set once the CREATED service usage event of the
+	// service instance has been recorded
+	//+kubebuilder:validation:Optional
+	UsageRecorded bool `json:"usageRecorded,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,106 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ServiceUsageStateCreated = "CREATED"
+	ServiceUsageStateDeleted = "DELETED"
+	// Bindings have states of their own, so that consumers counting service
+	// instances by their CREATED and DELETED events are not affected by them
+	ServiceUsageStateBindingCreated = "BINDING_CREATED"
+	ServiceUsageStateBindingDeleted = "BINDING_DELETED"
+
+	ManagedServiceInstanceUsageType      = "managed_service_instance"
+	UserProvidedServiceInstanceUsageType = "user_provided_service_instance"
+
+	// CFServiceUsageFinalizerName holds service instances and bindings until
+	// their deletion has been recorded
+	CFServiceUsageFinalizerName = "serviceUsage.korifi.cloudfoundry.org"
+)
+
+// CFServiceUsageEventSpec defines a change in the usage of a service instance
+type CFServiceUsageEventSpec struct {
+	// The time the usage changed, with the precision the events are ordered by
+	Timestamp metav1.MicroTime `json:"timestamp"`
+	// One of CREATED, DELETED, BINDING_CREATED or BINDING_DELETED
+	State string `json:"state"`
+
+	ServiceInstanceGUID string `json:"serviceInstanceGUID"`
+	// +optional
+	ServiceInstanceName string `json:"serviceInstanceName,omitempty"`
+	// One of managed_service_instance or user_provided_service_instance
+	// +optional
+	ServiceInstanceType string `json:"serviceInstanceType,omitempty"`
+
+	// +optional
+	ServicePlanGUID string `json:"servicePlanGUID,omitempty"`
+	// +optional
+	ServicePlanName string `json:"servicePlanName,omitempty"`
+	// +optional
+	ServiceOfferingGUID string `json:"serviceOfferingGUID,omitempty"`
+	// +optional
+	ServiceOfferingName string `json:"serviceOfferingName,omitempty"`
+	// +optional
+	ServiceBrokerGUID string `json:"serviceBrokerGUID,omitempty"`
+	// +optional
+	ServiceBrokerName string `json:"serviceBrokerName,omitempty"`
+
+	// The binding and the app it binds the service instance to, only set for
+	// the BINDING_CREATED and BINDING_DELETED events
+	// +optional
+	ServiceBindingGUID string `json:"serviceBindingGUID,omitempty"`
+	// +optional
+	AppGUID string `json:"appGUID,omitempty"`
+
+	SpaceGUID string `json:"spaceGUID"`
+	// +optional
+	SpaceName string `json:"spaceName,omitempty"`
+	OrgGUID   string `json:"orgGUID"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="State",type=string,JSONPath=`.spec.state`
+//+kubebuilder:printcolumn:name="Service Instance",type=string,JSONPath=`.spec.serviceInstanceName`
+//+kubebuilder:printcolumn:name="Plan",type=string,JSONPath=`.spec.servicePlanName`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// CFServiceUsageEvent is the Schema for the cfserviceusageevents API. The
+// events are recorded by the controllers in the root namespace and deleted
+// once they are older than the configured retention
+type CFServiceUsageEvent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CFServiceUsageEventSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CFServiceUsageEventList contains a list of CFServiceUsageEvent
+type CFServiceUsageEventList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CFServiceUsageEvent `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CFServiceUsageEvent{}, &CFServiceUsageEventList{})
+}
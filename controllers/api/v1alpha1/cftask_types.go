@@ -27,6 +27,12 @@ const (
 	TaskSucceededConditionType   = "Succeeded"
 	TaskFailedConditionType      = "Failed"
 	TaskCanceledConditionType    = "Canceled"
+
+	// TaskBackoffLimitAnnotationKey and TaskJobTTLAnnotationKey can be set
+	// in the metadata of task create requests to override the installation
+	// default backoff limit and job TTL of the task
+	TaskBackoffLimitAnnotationKey = "korifi.cloudfoundry.org/task-backoff-limit"
+	TaskJobTTLAnnotationKey       = "korifi.cloudfoundry.org/task-job-ttl"
 )
 
 // CFTaskSpec defines the desired state of CFTask
@@ -38,6 +44,18 @@ type CFTaskSpec struct {
 	// A boolean describing whether the CFTask has been canceled
 	// +optional
 	Canceled bool `json:"canceled"`
+	// The memory limit of the task in MB. Defaults to the installation task memory
+	// +optional
+	MemoryMB int64 `json:"memoryMB,omitempty"`
+	// The disk limit of the task in MB. Defaults to the installation task disk quota
+	// +optional
+	DiskQuotaMB int64 `json:"diskQuotaMB,omitempty"`
+	// How many times to retry the task when it fails. Defaults to the installation backoff limit
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+	// How long to keep the task workload after the task has finished. Defaults to the installation job TTL
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
 }
 
 // CFTaskStatus defines the observed state of CFTask
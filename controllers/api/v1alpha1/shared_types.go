@@ -18,12 +18,198 @@ const (
 	CFDomainGUIDLabelKey     = "korifi.cloudfoundry.org/domain-guid"
 	CFRouteGUIDLabelKey      = "korifi.cloudfoundry.org/route-guid"
 	CFTaskGUIDLabelKey       = "korifi.cloudfoundry.org/task-guid"
+	CFCronTaskGUIDLabelKey   = "korifi.cloudfoundry.org/cron-task-guid"
 
 	PodIndexLabelKey = "apps.kubernetes.io/pod-index"
 
+	// KpackBuilderAnnotationKey selects the kpack builder the apps of an org
+	// or space are built with: either the name of a ClusterBuilder or
+	// Builder/<name> for a Builder in the space namespace
+	KpackBuilderAnnotationKey = "korifi.cloudfoundry.org/kpack-builder"
+
+	// StagingMemoryAnnotationKey and StagingDiskAnnotationKey override the
+	// memory and disk (e.g. 2G) requested by the builds of an app
+	StagingMemoryAnnotationKey = "korifi.cloudfoundry.org/staging-memory"
+	StagingDiskAnnotationKey   = "korifi.cloudfoundry.org/staging-disk"
+
+	// DisableBuildCacheAnnotationKey set to "true" builds the app from
+	// scratch rather than reusing the layers cached by its previous builds
+	DisableBuildCacheAnnotationKey = "korifi.cloudfoundry.org/disable-build-cache"
+
+	// DisableTopologySpreadAnnotationKey set to "true" on an app opts its
+	// instances out of the topology spread constraints of the runner
+	DisableTopologySpreadAnnotationKey = "korifi.cloudfoundry.org/disable-topology-spread"
+
+	// EnvChecksumAnnotationKey on an AppWorkload holds a checksum of the env
+	// and service binding credentials of its app. The runners set it on the
+	// pod template, so that changing either rolls the app instances, unless
+	// the app opted out by setting DisableEnvRestartAnnotationKey to "true"
+	EnvChecksumAnnotationKey       = "korifi.cloudfoundry.org/env-checksum"
+	DisableEnvRestartAnnotationKey = "korifi.cloudfoundry.org/disable-env-restart"
+
+	// ProcessRunConfigAnnotationKey on an AppWorkload holds the command and
+	// health checks its process and the running env var group had when the
+	// app was last restarted, so that changing them only takes effect on the
+	// next restart, as in CF
+	ProcessRunConfigAnnotationKey = "korifi.cloudfoundry.org/process-run-config"
+
+	// NodeSelectorAnnotationKey on a CFSpace holds a JSON object of node
+	// labels the app instances in the space are scheduled on
+	NodeSelectorAnnotationKey = "korifi.cloudfoundry.org/node-selector"
+
+	// TolerationsAnnotationKey on a CFSpace holds a JSON list of Kubernetes
+	// tolerations that are added to the app instances in the space
+	TolerationsAnnotationKey = "korifi.cloudfoundry.org/tolerations"
+
+	// PriorityClassNameAnnotationKey and RuntimeClassNameAnnotationKey on a
+	// CFOrg or CFSpace select the PriorityClass and RuntimeClass (e.g. gVisor)
+	// of the app instances in the org or space, with the space taking precedence
+	PriorityClassNameAnnotationKey = "korifi.cloudfoundry.org/priority-class-name"
+	RuntimeClassNameAnnotationKey  = "korifi.cloudfoundry.org/runtime-class-name"
+
+	// DefaultProcessMemoryMBAnnotationKey and DefaultProcessDiskQuotaMBAnnotationKey
+	// on a CFOrg or CFSpace override the installation default memory and disk
+	// quota of the processes in the org or space, with the space taking precedence
+	DefaultProcessMemoryMBAnnotationKey    = "korifi.cloudfoundry.org/default-process-memory-mb"
+	DefaultProcessDiskQuotaMBAnnotationKey = "korifi.cloudfoundry.org/default-process-disk-quota-mb"
+
+	// AutoscalingMaxInstancesAnnotationKey on a CFProcess opts it into
+	// autoscaling between its desired instances and the given maximum. The
+	// target average CPU and memory utilization of the instances, as
+	// percentages of their requests, are set via AutoscalingCPUTargetAnnotationKey
+	// and AutoscalingMemoryTargetAnnotationKey
+	AutoscalingMaxInstancesAnnotationKey = "korifi.cloudfoundry.org/autoscaling-max-instances"
+	AutoscalingCPUTargetAnnotationKey    = "korifi.cloudfoundry.org/autoscaling-cpu-target"
+	AutoscalingMemoryTargetAnnotationKey = "korifi.cloudfoundry.org/autoscaling-memory-target"
+
+	// IdleTimeoutAnnotationKey on a CFProcess opts it into idling when idling
+	// is enabled: the process is scaled to zero when its routes have not
+	// received any requests for the given number of minutes, and woken up by
+	// the activator on the next request. The activator records the time of
+	// the last request in LastRequestTimeAnnotationKey
+	IdleTimeoutAnnotationKey     = "korifi.cloudfoundry.org/idle-timeout-minutes"
+	LastRequestTimeAnnotationKey = "korifi.cloudfoundry.org/last-request-time"
+	IdleConditionType            = "Idle"
+
+	// AppCrashEventReason is the reason of the events the runners record on
+	// an AppWorkload when one of its instances crashes. The details of the
+	// crash are set as annotations of the event, the app and process guids
+	// and the process type under the label keys above
+	AppCrashEventReason             = "AppCrash"
+	CrashInstanceIndexAnnotationKey = "korifi.cloudfoundry.org/instance-index"
+	CrashInstanceNameAnnotationKey  = "korifi.cloudfoundry.org/instance-name"
+	CrashExitStatusAnnotationKey    = "korifi.cloudfoundry.org/exit-status"
+	CrashExitReasonAnnotationKey    = "korifi.cloudfoundry.org/exit-reason"
+	CrashCountAnnotationKey         = "korifi.cloudfoundry.org/crash-count"
+
+	// AuditEventDeliveredAnnotationKey is set to "true" on a CFAuditEvent once
+	// the audit event sink has accepted it
+	AuditEventDeliveredAnnotationKey = "korifi.cloudfoundry.org/audit-event-delivered"
+
+	// PreviousDropletAnnotationKey on a CFApp holds the droplet the app ran
+	// before its latest deployment, which canceling the deployment reverts
+	// to. DeploymentCanceledAnnotationKey is set to "true" once the deployment
+	// is canceled
+	PreviousDropletAnnotationKey    = "korifi.cloudfoundry.org/previous-droplet-guid"
+	DeploymentCanceledAnnotationKey = "korifi.cloudfoundry.org/deployment-canceled"
+
+	// AppUsageAnnotationKey holds the usage of a CFProcess or CFBuild last
+	// recorded as a CFAppUsageEvent, as JSON
+	AppUsageAnnotationKey = "korifi.cloudfoundry.org/app-usage"
+
+	// ServiceBindingProjectionAnnotationKey on a CFApp or a CFServiceBinding,
+	// with the binding taking precedence, selects how the binding credentials
+	// are exposed to the app: only in VCAP_SERVICES, only as servicebinding.io
+	// files under SERVICE_BINDING_ROOT, or both, which is the default
+	ServiceBindingProjectionAnnotationKey = "korifi.cloudfoundry.org/service-binding-projection"
+	ServiceBindingProjectionVCAPServices  = "vcap-services"
+	ServiceBindingProjectionFiles         = "files"
+	ServiceBindingProjectionAll           = "all"
+
+	// CredentialsRotationAnnotationKey on a CFServiceBinding requests its
+	// credentials to be rotated. Whenever its value changes the binding
+	// credentials are refreshed into a new secret, which rolls the instances
+	// of the bound app. The previous secrets are deleted once the new one is
+	// projected into the app workloads
+	CredentialsRotationAnnotationKey = "korifi.cloudfoundry.org/credentials-rotation"
+
+	// BuildBindingLabelKey set to "true" on a secret in a space namespace
+	// binds the secret into the builds of all apps in the space, e.g. to
+	// provide the credentials of private dependency repositories
+	BuildBindingLabelKey = "korifi.cloudfoundry.org/build-binding"
+
+	// RouteRequestTimeoutAnnotationKey on a CFRoute sets the maximum time the
+	// gateway waits for the response of the destinations, as a Gateway API
+	// duration such as 30s or 1m30s. RouteRetryAttemptsAnnotationKey sets the
+	// number of times a failed request is retried. RouteSessionAffinityAnnotationKey
+	// set to "true" pins clients to an instance with the RouteSessionCookieName
+	// cookie, for the time set in RouteSessionIdleTimeoutAnnotationKey since
+	// their last request
+	RouteRequestTimeoutAnnotationKey     = "korifi.cloudfoundry.org/route-request-timeout"
+	RouteRetryAttemptsAnnotationKey      = "korifi.cloudfoundry.org/route-retry-attempts"
+	RouteSessionAffinityAnnotationKey    = "korifi.cloudfoundry.org/route-session-affinity"
+	RouteSessionIdleTimeoutAnnotationKey = "korifi.cloudfoundry.org/route-session-idle-timeout"
+	RouteSessionCookieName               = "__VCAP_ID__"
+
+	// TLSSecretNameAnnotationKey on a CFDomain names a TLS secret in the
+	// domain namespace that the gateway serves for the routes of the domain
+	// instead of the default wildcard certificate. Alternatively,
+	// CertManagerClusterIssuerAnnotationKey names a cert-manager ClusterIssuer
+	// that issues the certificate of the domain
+	TLSSecretNameAnnotationKey            = "korifi.cloudfoundry.org/tls-secret-name"
+	CertManagerClusterIssuerAnnotationKey = "korifi.cloudfoundry.org/cert-manager-cluster-issuer"
+
+	// ExternalDNSAnnotationKey set to "true" on a CFDomain publishes the DNS
+	// records of its routes via ExternalDNS: the HTTPRoutes and TCPRoutes of
+	// the domain get the same annotation, which ExternalDNS is expected to
+	// filter on, as well as the ExternalDNS annotations below. The TTL (in
+	// seconds) and the target of the records can be overridden per domain
+	// with ExternalDNSTTLAnnotationKey and ExternalDNSTargetAnnotationKey
+	ExternalDNSAnnotationKey       = "korifi.cloudfoundry.org/external-dns"
+	ExternalDNSTTLAnnotationKey    = "korifi.cloudfoundry.org/external-dns-ttl"
+	ExternalDNSTargetAnnotationKey = "korifi.cloudfoundry.org/external-dns-target"
+
+	// SetCurrentDropletAnnotationKey set to "true" on a CFBuild sets its
+	// droplet as the current droplet of the app once the build succeeds, as
+	// done for the builds of app restages
+	SetCurrentDropletAnnotationKey = "korifi.cloudfoundry.org/set-current-droplet"
+
+	// RevisionVersionAnnotationKey on a succeeded CFBuild is the version of
+	// the app revision its droplet is. Versions are counted per app by
+	// LastRevisionVersionAnnotationKey on the CFApp, so that they stay stable
+	// when older droplets are cleaned up
+	RevisionVersionAnnotationKey     = "korifi.cloudfoundry.org/revision-version"
+	LastRevisionVersionAnnotationKey = "korifi.cloudfoundry.org/last-revision-version"
+
+	// RunningEnvVarGroupName and StagingEnvVarGroupName are the ConfigMaps in
+	// the root namespace holding the environment variable groups, which are
+	// set on all app processes and tasks, and on all app builds respectively.
+	// The environment variables of the apps override the ones of the groups
+	RunningEnvVarGroupName = "running-environment-variable-group"
+	StagingEnvVarGroupName = "staging-environment-variable-group"
+
+	// CFMetadataPrefix prefixes the unprefixed labels and annotations set
+	// via the CF API on CFApps and CFProcesses when they are propagated to
+	// the AppWorkloads, and from there to the StatefulSets and pods of the
+	// app, so that e.g. cost allocation tooling can select pods by them
+	CFMetadataPrefix = "metadata.korifi.cloudfoundry.org/"
+
+	// InstancesFailingConditionType is true on AppWorkloads, CFProcesses and
+	// CFApps with instances that cannot start, e.g. because their image
+	// cannot be pulled or they cannot be scheduled. The reason and message of
+	// the condition are the ones of the first failing instance
+	InstancesFailingConditionType = "InstancesFailing"
+
 	StagingConditionType   = "Staging"
 	SucceededConditionType = "Succeeded"
 
+	// StagedConditionType is true on CFBuilds that staged successfully and on
+	// CFApps with a current droplet
+	StagedConditionType = "Staged"
+	// RunningConditionType is true on CFProcesses and CFApps with at least one
+	// running instance
+	RunningConditionType = "Running"
+
 	PropagateRoleBindingAnnotation    = "cloudfoundry.org/propagate-cf-role"
 	PropagateServiceAccountAnnotation = "cloudfoundry.org/propagate-service-account"
 	PropagateDeletionAnnotation       = "cloudfoundry.org/propagate-deletion"
@@ -34,6 +220,7 @@ const (
 	RelServiceBrokerNameLabel   = RelationshipsLabelPrefix + "service-broker-name"
 	RelServiceOfferingGUIDLabel = RelationshipsLabelPrefix + "service-offering-guid"
 	RelServiceOfferingNameLabel = RelationshipsLabelPrefix + "service-offering-name"
+	RelServicePlanNameLabel     = RelationshipsLabelPrefix + "service-plan-name"
 )
 
 type Lifecycle struct {
@@ -51,7 +238,7 @@ type LifecycleType string
 
 // LifecycleData is shared by CFApp and CFBuild
 type LifecycleData struct {
-	// Buildpacks to include in auto-detection when building the app image.
+	// Buildpacks to build the app image with, in the order they should run.
 	// If no values are specified, then all available buildpacks will be used for auto-detection
 	Buildpacks []string `json:"buildpacks,omitempty"`
 
@@ -37,6 +37,22 @@ type TaskWorkloadSpec struct {
 
 	// +kubebuilder:validation:Optional
 	Env []corev1.EnvVar `json:"env"`
+
+	// How many times to retry the task when it fails. The task runner default applies when not set
+	// +kubebuilder:validation:Optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// How long to keep the workload after the task has finished. The task runner default applies when not set
+	// +kubebuilder:validation:Optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// The cron schedule to run the task on, e.g. "0 2 * * *". The task runs once when not set
+	// +kubebuilder:validation:Optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// Whether scheduled runs of the task are suspended. Only applies to scheduled tasks
+	// +kubebuilder:validation:Optional
+	Suspend bool `json:"suspend,omitempty"`
 }
 
 // TaskWorkloadStatus defines the observed state of TaskWorkload
@@ -46,6 +62,10 @@ type TaskWorkloadStatus struct {
 
 	// ObservedGeneration captures the latest generation of the TaskWorkload that has been reconciled
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// The last time a scheduled task was run
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -52,9 +52,12 @@ import (
 )
 
 const (
-	defaultMemoryMB    = 128
-	defaultDiskQuotaMB = 256
-	defaultTimeout     = 60
+	defaultMemoryMB     = 128
+	defaultDiskQuotaMB  = 256
+	defaultWebInstances = 2
+	defaultTimeout      = 60
+
+	defaultTerminationGracePeriodSeconds = 10
 )
 
 var (
@@ -113,14 +116,22 @@ var _ = BeforeSuite(func() {
 		validation.NewDuplicateValidator(coordination.NewNameRegistry(uncachedClient, routes.RouteEntityType)),
 		namespace,
 		uncachedClient,
+		nil,
 	).SetupWebhookWithManager(k8sManager)).To(Succeed())
 
 	Expect(domains.NewValidator(uncachedClient).SetupWebhookWithManager(k8sManager)).To(Succeed())
 
 	Expect((&korifiv1alpha1.CFPackage{}).SetupWebhookWithManager(k8sManager)).To(Succeed())
 
-	Expect(korifiv1alpha1.NewCFProcessDefaulter(defaultMemoryMB, defaultDiskQuotaMB, defaultTimeout).
-		SetupWebhookWithManager(k8sManager)).To(Succeed())
+	Expect(korifiv1alpha1.NewCFProcessDefaulter(
+		k8sManager.GetAPIReader(),
+		namespace,
+		defaultMemoryMB,
+		defaultDiskQuotaMB,
+		defaultWebInstances,
+		defaultTimeout,
+		defaultTerminationGracePeriodSeconds,
+	).SetupWebhookWithManager(k8sManager)).To(Succeed())
 
 	Expect((&korifiv1alpha1.CFBuild{}).SetupWebhookWithManager(k8sManager)).To(Succeed())
 
@@ -0,0 +1,94 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+//counterfeiter:generate -o fake -fake-name AppCleaner . AppCleaner
+
+type AppCleaner interface {
+	Clean(ctx context.Context, app types.NamespacedName) error
+}
+
+// AppReconciler periodically runs the cleaners against every app. Packages
+// and builds are otherwise only pruned when a new one is staged, so the
+// retention limits would never apply to apps that are not pushed again.
+// Registry images are deleted by the finalizers of the pruned resources.
+type AppReconciler struct {
+	k8sClient client.Client
+	log       logr.Logger
+	interval  time.Duration
+	cleaners  []AppCleaner
+}
+
+func NewAppReconciler(
+	k8sClient client.Client,
+	log logr.Logger,
+	interval time.Duration,
+	cleaners ...AppCleaner,
+) *AppReconciler {
+	return &AppReconciler{
+		k8sClient: k8sClient,
+		log:       log,
+		interval:  interval,
+		cleaners:  cleaners,
+	}
+}
+
+func (r *AppReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("cfapp-cleanup").
+		For(&korifiv1alpha1.CFApp{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+func (r *AppReconciler) Reconcile(ctx context.Context, req reconcile.Request) (ctrl.Result, error) {
+	log := r.log.WithName("AppCleanup").
+		WithValues("namespace", req.Namespace).
+		WithValues("name", req.Name).
+		WithValues("logID", uuid.NewString())
+	ctx = logr.NewContext(ctx, log)
+
+	cfApp := &korifiv1alpha1.CFApp{}
+	err := r.k8sClient.Get(ctx, req.NamespacedName, cfApp)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Info("unable to fetch app", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	if !cfApp.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	var cleanErrs []error
+	for _, cleaner := range r.cleaners {
+		if err = cleaner.Clean(ctx, req.NamespacedName); err != nil {
+			log.Info("failed to clean up app", "reason", err)
+			cleanErrs = append(cleanErrs, err)
+		}
+	}
+
+	if len(cleanErrs) > 0 {
+		return ctrl.Result{}, errors.Join(cleanErrs...)
+	}
+
+	return ctrl.Result{RequeueAfter: r.interval}, nil
+}
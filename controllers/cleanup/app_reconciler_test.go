@@ -0,0 +1,102 @@
+package cleanup_test
+
+import (
+	"errors"
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/cleanup"
+	"code.cloudfoundry.org/korifi/controllers/cleanup/fake"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("AppReconciler", func() {
+	var (
+		packageCleaner *fake.AppCleaner
+		buildCleaner   *fake.AppCleaner
+		reconciler     *cleanup.AppReconciler
+		appName        types.NamespacedName
+		result         ctrl.Result
+		reconcileErr   error
+	)
+
+	BeforeEach(func() {
+		packageCleaner = new(fake.AppCleaner)
+		buildCleaner = new(fake.AppCleaner)
+		reconciler = cleanup.NewAppReconciler(controllersClient, logf.Log, time.Hour, packageCleaner, buildCleaner)
+
+		namespace := uuid.NewString()
+		Expect(k8sClient.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		})).To(Succeed())
+
+		appName = types.NamespacedName{Namespace: namespace, Name: uuid.NewString()}
+		Expect(k8sClient.Create(ctx, &korifiv1alpha1.CFApp{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      appName.Name,
+				Namespace: appName.Namespace,
+			},
+			Spec: korifiv1alpha1.CFAppSpec{
+				DisplayName: "an-app",
+				Lifecycle: korifiv1alpha1.Lifecycle{
+					Type: "buildpack",
+				},
+				DesiredState: "STOPPED",
+			},
+		})).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		result, reconcileErr = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: appName})
+	})
+
+	It("cleans up the app", func() {
+		Expect(reconcileErr).NotTo(HaveOccurred())
+
+		Expect(packageCleaner.CleanCallCount()).To(Equal(1))
+		_, actualApp := packageCleaner.CleanArgsForCall(0)
+		Expect(actualApp).To(Equal(appName))
+
+		Expect(buildCleaner.CleanCallCount()).To(Equal(1))
+		_, actualApp = buildCleaner.CleanArgsForCall(0)
+		Expect(actualApp).To(Equal(appName))
+	})
+
+	It("cleans up the app again after the interval", func() {
+		Expect(result.RequeueAfter).To(Equal(time.Hour))
+	})
+
+	When("a cleaner fails", func() {
+		BeforeEach(func() {
+			packageCleaner.CleanReturns(errors.New("clean-err"))
+		})
+
+		It("runs the other cleaners and returns the error", func() {
+			Expect(reconcileErr).To(MatchError(ContainSubstring("clean-err")))
+			Expect(buildCleaner.CleanCallCount()).To(Equal(1))
+		})
+	})
+
+	When("the app does not exist", func() {
+		BeforeEach(func() {
+			appName.Name = "not-an-app"
+		})
+
+		It("does not clean up", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(packageCleaner.CleanCallCount()).To(BeZero())
+			Expect(buildCleaner.CleanCallCount()).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,79 @@
+package cleanup_test
+
+import (
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/cleanup"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("AppUsageEventReconciler", func() {
+	var (
+		reconciler   *cleanup.AppUsageEventReconciler
+		event        *korifiv1alpha1.CFAppUsageEvent
+		timestamp    time.Time
+		result       ctrl.Result
+		reconcileErr error
+	)
+
+	BeforeEach(func() {
+		reconciler = cleanup.NewAppUsageEventReconciler(controllersClient, logf.Log, time.Hour)
+		timestamp = time.Now().Add(-30 * time.Minute)
+	})
+
+	JustBeforeEach(func() {
+		namespace := uuid.NewString()
+		Expect(k8sClient.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		})).To(Succeed())
+
+		event = &korifiv1alpha1.CFAppUsageEvent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      uuid.NewString(),
+				Namespace: namespace,
+			},
+			Spec: korifiv1alpha1.CFAppUsageEventSpec{
+				Timestamp: metav1.NewMicroTime(timestamp),
+				State:     korifiv1alpha1.AppUsageStateStarted,
+				AppGUID:   "app-guid",
+				AppName:   "app-name",
+				SpaceGUID: "space-guid",
+				SpaceName: "space-name",
+				OrgGUID:   "org-guid",
+			},
+		}
+		Expect(k8sClient.Create(ctx, event)).To(Succeed())
+
+		result, reconcileErr = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(event)})
+	})
+
+	It("keeps the event until it expires", func() {
+		Expect(reconcileErr).NotTo(HaveOccurred())
+		Expect(event).To(BeFound())
+		Expect(result.RequeueAfter).To(BeNumerically("~", 30*time.Minute, time.Minute))
+	})
+
+	When("the event is older than the retention", func() {
+		BeforeEach(func() {
+			timestamp = time.Now().Add(-2 * time.Hour)
+		})
+
+		It("deletes it", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(event).To(BeNotFound())
+			Expect(result.RequeueAfter).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,74 @@
+package cleanup
+
+import (
+	"context"
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// AuditEventReconciler deletes audit events once they are older than the
+// retention, so that apps crashing repeatedly do not grow the event store
+// unbounded
+type AuditEventReconciler struct {
+	k8sClient client.Client
+	log       logr.Logger
+	retention time.Duration
+}
+
+func NewAuditEventReconciler(
+	k8sClient client.Client,
+	log logr.Logger,
+	retention time.Duration,
+) *AuditEventReconciler {
+	return &AuditEventReconciler{
+		k8sClient: k8sClient,
+		log:       log,
+		retention: retention,
+	}
+}
+
+func (r *AuditEventReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("cfauditevent-cleanup").
+		For(&korifiv1alpha1.CFAuditEvent{}).
+		Complete(r)
+}
+
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfauditevents,verbs=get;list;watch;delete
+
+func (r *AuditEventReconciler) Reconcile(ctx context.Context, req reconcile.Request) (ctrl.Result, error) {
+	log := r.log.WithName("AuditEventCleanup").
+		WithValues("namespace", req.Namespace).
+		WithValues("name", req.Name).
+		WithValues("logID", uuid.NewString())
+
+	event := &korifiv1alpha1.CFAuditEvent{}
+	err := r.k8sClient.Get(ctx, req.NamespacedName, event)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Info("unable to fetch audit event", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	expiresIn := time.Until(event.CreationTimestamp.Add(r.retention))
+	if expiresIn > 0 {
+		return ctrl.Result{RequeueAfter: expiresIn}, nil
+	}
+
+	err = r.k8sClient.Delete(ctx, event)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		log.Info("unable to delete expired audit event", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,76 @@
+package cleanup_test
+
+import (
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/cleanup"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("AuditEventReconciler", func() {
+	var (
+		retention    time.Duration
+		event        *korifiv1alpha1.CFAuditEvent
+		result       ctrl.Result
+		reconcileErr error
+	)
+
+	BeforeEach(func() {
+		retention = time.Hour
+	})
+
+	JustBeforeEach(func() {
+		namespace := uuid.NewString()
+		Expect(k8sClient.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		})).To(Succeed())
+
+		event = &korifiv1alpha1.CFAuditEvent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      uuid.NewString(),
+				Namespace: namespace,
+			},
+			Spec: korifiv1alpha1.CFAuditEventSpec{
+				Type:       korifiv1alpha1.AuditEventTypeAppCrash,
+				ActorGUID:  "process-guid",
+				ActorType:  "process",
+				TargetGUID: "app-guid",
+				TargetType: "app",
+			},
+		}
+		Expect(k8sClient.Create(ctx, event)).To(Succeed())
+
+		reconciler := cleanup.NewAuditEventReconciler(controllersClient, logf.Log, retention)
+		result, reconcileErr = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(event)})
+	})
+
+	It("keeps the event until it expires", func() {
+		Expect(reconcileErr).NotTo(HaveOccurred())
+		Expect(event).To(BeFound())
+		Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+	})
+
+	When("the event is older than the retention", func() {
+		BeforeEach(func() {
+			retention = 0
+		})
+
+		It("deletes it", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(event).To(BeNotFound())
+			Expect(result.RequeueAfter).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,73 @@
+package cleanup
+
+import (
+	"context"
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ServiceUsageEventReconciler deletes service usage events once they are older
+// than the retention, so that the event store does not grow unbounded
+type ServiceUsageEventReconciler struct {
+	k8sClient client.Client
+	log       logr.Logger
+	retention time.Duration
+}
+
+func NewServiceUsageEventReconciler(
+	k8sClient client.Client,
+	log logr.Logger,
+	retention time.Duration,
+) *ServiceUsageEventReconciler {
+	return &ServiceUsageEventReconciler{
+		k8sClient: k8sClient,
+		log:       log,
+		retention: retention,
+	}
+}
+
+func (r *ServiceUsageEventReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("cfserviceusageevent-cleanup").
+		For(&korifiv1alpha1.CFServiceUsageEvent{}).
+		Complete(r)
+}
+
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfserviceusageevents,verbs=get;list;watch;create;delete
+
+func (r *ServiceUsageEventReconciler) Reconcile(ctx context.Context, req reconcile.Request) (ctrl.Result, error) {
+	log := r.log.WithName("ServiceUsageEventCleanup").
+		WithValues("namespace", req.Namespace).
+		WithValues("name", req.Name).
+		WithValues("logID", uuid.NewString())
+
+	event := &korifiv1alpha1.CFServiceUsageEvent{}
+	err := r.k8sClient.Get(ctx, req.NamespacedName, event)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Info("unable to fetch service usage event", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	expiresIn := time.Until(event.Spec.Timestamp.Add(r.retention))
+	if expiresIn > 0 {
+		return ctrl.Result{RequeueAfter: expiresIn}, nil
+	}
+
+	err = r.k8sClient.Delete(ctx, event)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		log.Info("unable to delete expired service usage event", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
@@ -1,11 +1,16 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
 	"time"
 
 	"go.uber.org/zap/zapcore"
 
 	"code.cloudfoundry.org/korifi/tools"
+	"code.cloudfoundry.org/korifi/tools/image"
 )
 
 type ControllerConfig struct {
@@ -13,12 +18,16 @@ type ControllerConfig struct {
 	IncludeKpackImageBuilder bool `yaml:"includeKpackImageBuilder"`
 	IncludeJobTaskRunner     bool `yaml:"includeJobTaskRunner"`
 	IncludeStatefulsetRunner bool `yaml:"includeStatefulsetRunner"`
+	IncludeDeploymentRunner  bool `yaml:"includeDeploymentRunner"`
 
 	// core controllers
 	CFProcessDefaults                CFProcessDefaults  `yaml:"cfProcessDefaults"`
+	CFTaskDefaults                   CFTaskDefaults     `yaml:"cfTaskDefaults"`
 	CFStagingResources               CFStagingResources `yaml:"cfStagingResources"`
 	CFRootNamespace                  string             `yaml:"cfRootNamespace"`
 	ContainerRegistrySecretNames     []string           `yaml:"containerRegistrySecretNames"`
+	InsecureContainerRegistries      []string           `yaml:"insecureContainerRegistries"`
+	ContainerRegistryImageDeletion   string             `yaml:"containerRegistryImageDeletion"`
 	TaskTTL                          string             `yaml:"taskTTL"`
 	BuilderName                      string             `yaml:"builderName"`
 	RunnerName                       string             `yaml:"runnerName"`
@@ -26,50 +35,274 @@ type ControllerConfig struct {
 	ExtraVCAPApplicationValues       map[string]any     `yaml:"extraVCAPApplicationValues"`
 	MaxRetainedPackagesPerApp        int                `yaml:"maxRetainedPackagesPerApp"`
 	MaxRetainedBuildsPerApp          int                `yaml:"maxRetainedBuildsPerApp"`
+	MaxConcurrentBuilds              int                `yaml:"maxConcurrentBuilds"`
+	MaxConcurrentBuildsPerSpace      int                `yaml:"maxConcurrentBuildsPerSpace"`
+	MaxConcurrentTasksPerSpace       int                `yaml:"maxConcurrentTasksPerSpace"`
+	RetentionCleanupInterval         string             `yaml:"retentionCleanupInterval"`
+	AppUsageEventRetention           string             `yaml:"appUsageEventRetention"`
+	ServiceUsageEventRetention       string             `yaml:"serviceUsageEventRetention"`
+	AuditEventRetention              string             `yaml:"auditEventRetention"`
 	LogLevel                         zapcore.Level      `yaml:"logLevel"`
 	SpaceFinalizerAppDeletionTimeout *int32             `yaml:"spaceFinalizerAppDeletionTimeout"`
+	// TrustedCAConfigMapName is a ConfigMap in the root namespace whose ca.crt
+	// bundle is propagated to the spaces and mounted into the app and task
+	// pods, e.g. with the CAs of internal services
+	TrustedCAConfigMapName string `yaml:"trustedCAConfigMapName"`
+	// ReservedRouteHosts are hosts that routes cannot use on any domain, e.g.
+	// the hosts of platform services sharing the apps domain
+	ReservedRouteHosts []string `yaml:"reservedRouteHosts"`
+	// NameUniqueness selects whether the webhooks reject duplicate app names,
+	// service instance names and routes
+	NameUniqueness NameUniqueness `yaml:"nameUniqueness"`
+	// PackageBlobstore is the S3-compatible blobstore the API uploads package
+	// bits to, instead of the registry, when configured
+	PackageBlobstore S3Blobstore    `yaml:"packageBlobstore"`
+	AuditEventSink   AuditEventSink `yaml:"auditEventSink"`
+	Sharding         Sharding       `yaml:"sharding"`
 
 	// job-task-runner
 	JobTTL                                     string `yaml:"jobTTL"`
+	JobBackoffLimit                            int32  `yaml:"jobBackoffLimit"`
 	JobTaskRunnerTemporarySetPodSeccompProfile bool   `yaml:"jobTaskRunnerTemporarySetPodSeccompProfile"`
 
 	// statefulset-runner
-	StatefulsetRunnerTemporarySetPodSeccompProfile bool `yaml:"statefulsetRunnerTemporarySetPodSeccompProfile"`
+	StatefulsetRunnerTemporarySetPodSeccompProfile bool                `yaml:"statefulsetRunnerTemporarySetPodSeccompProfile"`
+	StatefulsetRunnerTopologySpread                TopologySpread      `yaml:"statefulsetRunnerTopologySpread"`
+	StatefulsetRunnerPodDisruptionBudget           PodDisruptionBudget `yaml:"statefulsetRunnerPodDisruptionBudget"`
+	StatefulsetRunnerNodePlacement                 NodePlacement       `yaml:"statefulsetRunnerNodePlacement"`
+	StatefulsetRunnerPriorityClassName             string              `yaml:"statefulsetRunnerPriorityClassName"`
+	StatefulsetRunnerRuntimeClassName              string              `yaml:"statefulsetRunnerRuntimeClassName"`
+	StatefulsetRunnerSecurityContext               SecurityContext     `yaml:"statefulsetRunnerSecurityContext"`
+	StatefulsetRunnerInstanceIdentity              InstanceIdentity    `yaml:"statefulsetRunnerInstanceIdentity"`
 
 	// kpack-image-builder
-	ClusterBuilderName        string     `yaml:"clusterBuilderName"`
-	BuilderServiceAccount     string     `yaml:"builderServiceAccount"`
-	BuilderReadinessTimeout   string     `yaml:"builderReadinessTimeout"`
-	ContainerRepositoryPrefix string     `yaml:"containerRepositoryPrefix"`
-	ContainerRegistryType     string     `yaml:"containerRegistryType"`
-	Networking                Networking `yaml:"networking"`
+	ClusterBuilderName          string     `yaml:"clusterBuilderName"`
+	BuilderServiceAccount       string     `yaml:"builderServiceAccount"`
+	BuilderReadinessTimeout     string     `yaml:"builderReadinessTimeout"`
+	StagingTimeout              string     `yaml:"stagingTimeout"`
+	ContainerRepositoryPrefix   string     `yaml:"containerRepositoryPrefix"`
+	ContainerRepositoryTemplate string     `yaml:"containerRepositoryTemplate"`
+	ContainerRegistryType       string     `yaml:"containerRegistryType"`
+	BuildCacheType              string     `yaml:"buildCacheType"`
+	RebaseOnStackUpdate         bool       `yaml:"rebaseOnStackUpdate"`
+	TransientBuildRetries       int        `yaml:"transientBuildRetries"`
+	Networking                  Networking `yaml:"networking"`
+	Idling                      Idling     `yaml:"idling"`
 
 	ExperimentalManagedServicesEnabled bool `yaml:"experimentalManagedServicesEnabled"`
 	TrustInsecureServiceBrokers        bool `yaml:"trustInsecureServiceBrokers"`
 }
 
 type CFProcessDefaults struct {
-	MemoryMB    int64  `yaml:"memoryMB"`
-	DiskQuotaMB int64  `yaml:"diskQuotaMB"`
-	Timeout     *int32 `yaml:"timeout"`
+	MemoryMB                      int64  `yaml:"memoryMB"`
+	DiskQuotaMB                   int64  `yaml:"diskQuotaMB"`
+	WebInstances                  *int32 `yaml:"webInstances"`
+	Timeout                       *int32 `yaml:"timeout"`
+	TerminationGracePeriodSeconds *int64 `yaml:"terminationGracePeriodSeconds"`
+}
+
+// NameUniqueness is either UniquenessEnforced or UniquenessRelaxed for every
+// resource. App and service instance names are unique per space and routes
+// per installation when enforced, like on CF for VMs.
+type NameUniqueness struct {
+	Apps             string `yaml:"apps"`
+	ServiceInstances string `yaml:"serviceInstances"`
+	Routes           string `yaml:"routes"`
+}
+
+type CFTaskDefaults struct {
+	MemoryMB    int64 `yaml:"memoryMB"`
+	DiskQuotaMB int64 `yaml:"diskQuotaMB"`
 }
 
 type CFStagingResources struct {
-	BuildCacheMB int64 `yaml:"buildCacheMB"`
-	DiskMB       int64 `yaml:"diskMB"`
-	MemoryMB     int64 `yaml:"memoryMB"`
+	BuildCacheMB  int64                   `yaml:"buildCacheMB"`
+	DiskMB        int64                   `yaml:"diskMB"`
+	MemoryMB      int64                   `yaml:"memoryMB"`
+	CPUMillicores int64                   `yaml:"cpuMillicores"`
+	Limits        CFStagingResourceLimits `yaml:"limits"`
+}
+
+type CFStagingResourceLimits struct {
+	DiskMB        int64 `yaml:"diskMB"`
+	MemoryMB      int64 `yaml:"memoryMB"`
+	CPUMillicores int64 `yaml:"cpuMillicores"`
+}
+
+// TopologySpread configures how the instances of apps are spread across the
+// failure domains named by TopologyKeys, e.g. topology.kubernetes.io/zone
+type TopologySpread struct {
+	TopologyKeys      []string `yaml:"topologyKeys"`
+	MaxSkew           int32    `yaml:"maxSkew"`
+	WhenUnsatisfiable string   `yaml:"whenUnsatisfiable"`
+}
+
+// PodDisruptionBudget configures how many instances of multi-instance apps
+// must stay available (or may be unavailable) while nodes are drained, either
+// as a number of instances or as a percentage, e.g. 50%
+type PodDisruptionBudget struct {
+	MinAvailable   string `yaml:"minAvailable"`
+	MaxUnavailable string `yaml:"maxUnavailable"`
+}
+
+// NodePlacement configures the nodes the instances of all apps are scheduled
+// on. Spaces can add to it via the node placement annotations of their CFSpace.
+type NodePlacement struct {
+	NodeSelector map[string]string `yaml:"nodeSelector"`
+	Tolerations  []Toleration      `yaml:"tolerations"`
+}
+
+// Toleration mirrors the Kubernetes pod toleration, see
+// https://kubernetes.io/docs/concepts/scheduling-eviction/taint-and-toleration/
+type Toleration struct {
+	Key               string `yaml:"key"`
+	Operator          string `yaml:"operator"`
+	Value             string `yaml:"value"`
+	Effect            string `yaml:"effect"`
+	TolerationSeconds *int64 `yaml:"tolerationSeconds"`
+}
+
+// SecurityContext configures the hardening of app pods, e.g. to meet the
+// restricted Pod Security Standard. The defaults run apps as non-root with the
+// RuntimeDefault seccomp profile and all capabilities dropped.
+type SecurityContext struct {
+	RunAsNonRoot           *bool    `yaml:"runAsNonRoot"`
+	SeccompProfile         string   `yaml:"seccompProfile"`
+	DropCapabilities       []string `yaml:"dropCapabilities"`
+	ReadOnlyRootFilesystem bool     `yaml:"readOnlyRootFilesystem"`
+}
+
+// InstanceIdentity configures issuing instance identity certificates to app
+// instances, which apps find via CF_INSTANCE_CERT and CF_INSTANCE_KEY, e.g.
+// for mTLS to backing services. The certificates are signed by the CA in the
+// CASecretName secret (with tls.crt and tls.key) and renewed before they
+// expire. Instance identity is disabled when no CA secret is set.
+type InstanceIdentity struct {
+	CASecretName        string `yaml:"caSecretName"`
+	CASecretNamespace   string `yaml:"caSecretNamespace"`
+	CertificateValidity string `yaml:"certificateValidity"`
+}
+
+func (i InstanceIdentity) Enabled() bool {
+	return i.CASecretName != ""
+}
+
+// S3Blobstore identifies a bucket in an S3-compatible blobstore. The
+// credentials for the bucket are taken from the environment.
+type S3Blobstore struct {
+	Endpoint string `yaml:"endpoint"`
+	Bucket   string `yaml:"bucket"`
+	Region   string `yaml:"region"`
+}
+
+func (b S3Blobstore) Enabled() bool {
+	return b.Endpoint != ""
+}
+
+// AuditEventSink configures delivering every audit event to URL, e.g. the
+// HTTP collector of a SIEM. Failed deliveries are retried with backoff until
+// the endpoint accepts the event. Delivery is disabled when no URL is set.
+type AuditEventSink struct {
+	URL string `yaml:"url"`
+	// Format is either AuditEventFormatJSON, posting the events as presented
+	// by the API, or AuditEventFormatCloudEvents, wrapping them in structured
+	// CloudEvents
+	Format string `yaml:"format"`
+	// AuthorizationSecretName is a secret in the root namespace whose
+	// authorization key is sent as the Authorization header of the requests
+	AuthorizationSecretName string `yaml:"authorizationSecretName"`
+}
+
+func (s AuditEventSink) Enabled() bool {
+	return s.URL != ""
+}
+
+// Sharding splits the reconciliation of apps, builds and routes across
+// Shards controllers deployments by the hash of the space namespace. Every
+// deployment started with a shard index elects its own leader. Sharding is
+// disabled with fewer than two shards.
+type Sharding struct {
+	Shards int `yaml:"shards"`
 }
 
 type Networking struct {
 	GatewayName      string `yaml:"gatewayName"`
 	GatewayNamespace string `yaml:"gatewayNamespace"`
+	// TCPPorts are the ports of the tcp listeners of the gateway, named
+	// tcp-<port>. Routes with the tcp protocol are only programmed on these
+	// ports, as TCPRoutes. TCP routing is disabled when there are none.
+	TCPPorts []int32 `yaml:"tcpPorts"`
+	// SpaceIsolation configures the default-deny NetworkPolicy of the space
+	// namespaces
+	SpaceIsolation SpaceIsolation `yaml:"spaceIsolation"`
+}
+
+// SpaceIsolation denies ingress to the pods of a space from outside the
+// space, except from the gateway namespace, the activator namespace when
+// idling is enabled, and AllowedIngressNamespaces, e.g. the namespaces of
+// shared services or monitoring
+type SpaceIsolation struct {
+	Enabled                  bool     `yaml:"enabled"`
+	AllowedIngressNamespaces []string `yaml:"allowedIngressNamespaces"`
 }
 
+// Idling configures scaling processes that opted in via the idle timeout
+// annotation to zero. The routes to such processes go through the activator,
+// which records their traffic and wakes them up on the next request, holding
+// the request for up to WakeTimeout. The routes sign the requests they send
+// to the activator with the key in the ActivatorSigningKeyPath file.
+type Idling struct {
+	Enabled                   bool   `yaml:"enabled"`
+	ActivatorServiceName      string `yaml:"activatorServiceName"`
+	ActivatorServiceNamespace string `yaml:"activatorServiceNamespace"`
+	ActivatorPort             int32  `yaml:"activatorPort"`
+	ActivatorSigningKeyPath   string `yaml:"activatorSigningKeyPath"`
+	WakeTimeout               string `yaml:"wakeTimeout"`
+}
+
+const (
+	defaultTaskTTL                             = 30 * 24 * time.Hour
+	defaultTimeout                       int32 = 60
+	defaultTerminationGracePeriodSeconds int64 = 10
+	defaultWebInstances                  int32 = 1
+	defaultJobTTL                              = 24 * time.Hour
+	defaultCleanupInterval                     = time.Hour
+	defaultAppUsageEventRetention              = 31 * 24 * time.Hour
+	defaultServiceUsageEventRetention          = 31 * 24 * time.Hour
+	defaultAuditEventRetention                 = 31 * 24 * time.Hour
+	defaultBuildCacheMB                        = 2048
+	defaultStagingTimeout                      = 15 * time.Minute
+	defaultTopologySpreadMaxSkew         int32 = 1
+	defaultPDBMinAvailable                     = "50%"
+	defaultActivatorPort                 int32 = 8000
+	defaultWakeTimeout                         = time.Minute
+	defaultCertificateValidity                 = 24 * time.Hour
+)
+
+const (
+	VolumeBuildCache   = "volume"
+	RegistryBuildCache = "registry"
+	NoBuildCache       = "none"
+)
+
+const (
+	UniquenessEnforced = "enforced"
+	UniquenessRelaxed  = "relaxed"
+)
+
+const (
+	AuditEventFormatJSON        = "json"
+	AuditEventFormatCloudEvents = "cloudevents"
+)
+
+const (
+	ScheduleAnyway = "ScheduleAnyway"
+	DoNotSchedule  = "DoNotSchedule"
+)
+
 const (
-	defaultTaskTTL            = 30 * 24 * time.Hour
-	defaultTimeout      int32 = 60
-	defaultJobTTL             = 24 * time.Hour
-	defaultBuildCacheMB       = 2048
+	SeccompProfileRuntimeDefault = "RuntimeDefault"
+	SeccompProfileUnconfined     = "Unconfined"
 )
 
 func LoadFromPath(path string) (*ControllerConfig, error) {
@@ -83,6 +316,22 @@ func LoadFromPath(path string) (*ControllerConfig, error) {
 		config.CFProcessDefaults.Timeout = tools.PtrTo(defaultTimeout)
 	}
 
+	if config.CFProcessDefaults.WebInstances == nil {
+		config.CFProcessDefaults.WebInstances = tools.PtrTo(defaultWebInstances)
+	}
+
+	if config.CFProcessDefaults.TerminationGracePeriodSeconds == nil {
+		config.CFProcessDefaults.TerminationGracePeriodSeconds = tools.PtrTo(defaultTerminationGracePeriodSeconds)
+	}
+
+	if config.CFTaskDefaults.MemoryMB == 0 {
+		config.CFTaskDefaults.MemoryMB = config.CFProcessDefaults.MemoryMB
+	}
+
+	if config.CFTaskDefaults.DiskQuotaMB == 0 {
+		config.CFTaskDefaults.DiskQuotaMB = config.CFProcessDefaults.DiskQuotaMB
+	}
+
 	if config.SpaceFinalizerAppDeletionTimeout == nil {
 		config.SpaceFinalizerAppDeletionTimeout = tools.PtrTo(defaultTimeout)
 	}
@@ -91,6 +340,99 @@ func LoadFromPath(path string) (*ControllerConfig, error) {
 		config.CFStagingResources.BuildCacheMB = defaultBuildCacheMB
 	}
 
+	if config.StatefulsetRunnerTopologySpread.MaxSkew == 0 {
+		config.StatefulsetRunnerTopologySpread.MaxSkew = defaultTopologySpreadMaxSkew
+	}
+
+	switch config.StatefulsetRunnerTopologySpread.WhenUnsatisfiable {
+	case "":
+		config.StatefulsetRunnerTopologySpread.WhenUnsatisfiable = ScheduleAnyway
+	case ScheduleAnyway, DoNotSchedule:
+	default:
+		return nil, fmt.Errorf("invalid topology spread whenUnsatisfiable %q: must be one of %s or %s", config.StatefulsetRunnerTopologySpread.WhenUnsatisfiable, ScheduleAnyway, DoNotSchedule)
+	}
+
+	pdb := &config.StatefulsetRunnerPodDisruptionBudget
+	if pdb.MinAvailable != "" && pdb.MaxUnavailable != "" {
+		return nil, errors.New("invalid pod disruption budget: only one of minAvailable or maxUnavailable can be set")
+	}
+
+	if pdb.MinAvailable == "" && pdb.MaxUnavailable == "" {
+		pdb.MinAvailable = defaultPDBMinAvailable
+	}
+
+	securityContext := &config.StatefulsetRunnerSecurityContext
+	if securityContext.RunAsNonRoot == nil {
+		securityContext.RunAsNonRoot = tools.PtrTo(true)
+	}
+
+	if len(securityContext.DropCapabilities) == 0 {
+		securityContext.DropCapabilities = []string{"ALL"}
+	}
+
+	switch securityContext.SeccompProfile {
+	case "":
+		securityContext.SeccompProfile = SeccompProfileRuntimeDefault
+	case SeccompProfileRuntimeDefault, SeccompProfileUnconfined:
+	default:
+		return nil, fmt.Errorf("invalid security context seccompProfile %q: must be one of %s or %s", securityContext.SeccompProfile, SeccompProfileRuntimeDefault, SeccompProfileUnconfined)
+	}
+
+	instanceIdentity := config.StatefulsetRunnerInstanceIdentity
+	if instanceIdentity.Enabled() && instanceIdentity.CASecretNamespace == "" {
+		return nil, errors.New("invalid instance identity: caSecretNamespace is required when caSecretName is set")
+	}
+
+	if config.Idling.Enabled {
+		if config.Idling.ActivatorServiceName == "" || config.Idling.ActivatorServiceNamespace == "" {
+			return nil, errors.New("invalid idling: activatorServiceName and activatorServiceNamespace are required when idling is enabled")
+		}
+
+		if config.Idling.ActivatorSigningKeyPath == "" {
+			return nil, errors.New("invalid idling: activatorSigningKeyPath is required when idling is enabled")
+		}
+
+		if config.Idling.ActivatorPort == 0 {
+			config.Idling.ActivatorPort = defaultActivatorPort
+		}
+	}
+
+	for _, uniqueness := range []*string{
+		&config.NameUniqueness.Apps,
+		&config.NameUniqueness.ServiceInstances,
+		&config.NameUniqueness.Routes,
+	} {
+		switch *uniqueness {
+		case "":
+			*uniqueness = UniquenessEnforced
+		case UniquenessEnforced, UniquenessRelaxed:
+		default:
+			return nil, fmt.Errorf("invalid name uniqueness %q: must be one of %s or %s", *uniqueness, UniquenessEnforced, UniquenessRelaxed)
+		}
+	}
+
+	if config.AuditEventSink.Enabled() {
+		switch config.AuditEventSink.Format {
+		case "":
+			config.AuditEventSink.Format = AuditEventFormatJSON
+		case AuditEventFormatJSON, AuditEventFormatCloudEvents:
+		default:
+			return nil, fmt.Errorf("invalid audit event sink format %q: must be one of %s or %s", config.AuditEventSink.Format, AuditEventFormatJSON, AuditEventFormatCloudEvents)
+		}
+	}
+
+	if config.Sharding.Shards < 0 {
+		return nil, fmt.Errorf("invalid number of shards %d: must not be negative", config.Sharding.Shards)
+	}
+
+	switch config.BuildCacheType {
+	case "":
+		config.BuildCacheType = VolumeBuildCache
+	case VolumeBuildCache, RegistryBuildCache, NoBuildCache:
+	default:
+		return nil, fmt.Errorf("invalid build cache type %q: must be one of %s, %s or %s", config.BuildCacheType, VolumeBuildCache, RegistryBuildCache, NoBuildCache)
+	}
+
 	return &config, nil
 }
 
@@ -103,6 +445,23 @@ func GetLogLevelFromPath(path string) (zapcore.Level, error) {
 	return cfg.LogLevel, nil
 }
 
+// SpaceIngressNamespaces returns the namespaces allowed to send traffic to
+// the pods of the spaces, or nil when space isolation is disabled
+func (c ControllerConfig) SpaceIngressNamespaces() []string {
+	if !c.Networking.SpaceIsolation.Enabled {
+		return nil
+	}
+
+	namespaces := []string{c.Networking.GatewayNamespace}
+	if c.Idling.Enabled {
+		namespaces = append(namespaces, c.Idling.ActivatorServiceNamespace)
+	}
+	namespaces = append(namespaces, c.Networking.SpaceIsolation.AllowedIngressNamespaces...)
+
+	slices.Sort(namespaces)
+	return slices.Compact(namespaces)
+}
+
 func (c ControllerConfig) ParseTaskTTL() (time.Duration, error) {
 	if c.TaskTTL == "" {
 		return defaultTaskTTL, nil
@@ -111,10 +470,22 @@ func (c ControllerConfig) ParseTaskTTL() (time.Duration, error) {
 	return tools.ParseDuration(c.TaskTTL)
 }
 
+func (c ControllerConfig) ParseContainerRegistryImageDeletion() (image.DeletionPolicy, error) {
+	return image.ParseDeletionPolicy(c.ContainerRegistryImageDeletion)
+}
+
 func (c ControllerConfig) ParseBuilderReadinessTimeout() (time.Duration, error) {
 	return tools.ParseDuration(c.BuilderReadinessTimeout)
 }
 
+func (c ControllerConfig) ParseStagingTimeout() (time.Duration, error) {
+	if c.StagingTimeout == "" {
+		return defaultStagingTimeout, nil
+	}
+
+	return tools.ParseDuration(c.StagingTimeout)
+}
+
 func (c ControllerConfig) ParseJobTTL() (time.Duration, error) {
 	if c.JobTTL == "" {
 		return defaultJobTTL, nil
@@ -122,3 +493,66 @@ func (c ControllerConfig) ParseJobTTL() (time.Duration, error) {
 
 	return tools.ParseDuration(c.JobTTL)
 }
+
+func (c ControllerConfig) ParseRetentionCleanupInterval() (time.Duration, error) {
+	if c.RetentionCleanupInterval == "" {
+		return defaultCleanupInterval, nil
+	}
+
+	return tools.ParseDuration(c.RetentionCleanupInterval)
+}
+
+func (c ControllerConfig) ParseAppUsageEventRetention() (time.Duration, error) {
+	if c.AppUsageEventRetention == "" {
+		return defaultAppUsageEventRetention, nil
+	}
+
+	return tools.ParseDuration(c.AppUsageEventRetention)
+}
+
+func (c ControllerConfig) ParseServiceUsageEventRetention() (time.Duration, error) {
+	if c.ServiceUsageEventRetention == "" {
+		return defaultServiceUsageEventRetention, nil
+	}
+
+	return tools.ParseDuration(c.ServiceUsageEventRetention)
+}
+
+func (c ControllerConfig) ParseAuditEventRetention() (time.Duration, error) {
+	if c.AuditEventRetention == "" {
+		return defaultAuditEventRetention, nil
+	}
+
+	return tools.ParseDuration(c.AuditEventRetention)
+}
+
+// LoadActivatorSigningKey reads the key the requests sent to the activator
+// are signed with
+func (c ControllerConfig) LoadActivatorSigningKey() ([]byte, error) {
+	signingKey, err := os.ReadFile(c.Idling.ActivatorSigningKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the activator signing key: %w", err)
+	}
+
+	if len(signingKey) == 0 {
+		return nil, errors.New("the activator signing key is empty")
+	}
+
+	return signingKey, nil
+}
+
+func (c ControllerConfig) ParseWakeTimeout() (time.Duration, error) {
+	if c.Idling.WakeTimeout == "" {
+		return defaultWakeTimeout, nil
+	}
+
+	return tools.ParseDuration(c.Idling.WakeTimeout)
+}
+
+func (c ControllerConfig) ParseCertificateValidity() (time.Duration, error) {
+	if c.StatefulsetRunnerInstanceIdentity.CertificateValidity == "" {
+		return defaultCertificateValidity, nil
+	}
+
+	return tools.ParseDuration(c.StatefulsetRunnerInstanceIdentity.CertificateValidity)
+}
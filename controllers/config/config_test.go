@@ -7,6 +7,7 @@ import (
 
 	"code.cloudfoundry.org/korifi/controllers/config"
 	"code.cloudfoundry.org/korifi/tools"
+	"code.cloudfoundry.org/korifi/tools/image"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -31,9 +32,15 @@ var _ = Describe("LoadFromPath", func() {
 
 		cfg = config.ControllerConfig{
 			CFProcessDefaults: config.CFProcessDefaults{
-				MemoryMB:    1024,
-				DiskQuotaMB: 512,
-				Timeout:     tools.PtrTo(int32(30)),
+				MemoryMB:                      1024,
+				DiskQuotaMB:                   512,
+				WebInstances:                  tools.PtrTo(int32(2)),
+				Timeout:                       tools.PtrTo(int32(30)),
+				TerminationGracePeriodSeconds: tools.PtrTo(int64(20)),
+			},
+			CFTaskDefaults: config.CFTaskDefaults{
+				MemoryMB:    256,
+				DiskQuotaMB: 128,
 			},
 			CFStagingResources: config.CFStagingResources{
 				BuildCacheMB: 1024,
@@ -42,15 +49,60 @@ var _ = Describe("LoadFromPath", func() {
 			},
 			CFRootNamespace:                  "rootNamespace",
 			ContainerRegistrySecretNames:     []string{"packageRegistrySecretName"},
+			InsecureContainerRegistries:      []string{"registry.internal:5000"},
 			TaskTTL:                          "taskTTL",
 			BuilderName:                      "buildReconciler",
 			RunnerName:                       "statefulset-runner",
 			JobTTL:                           "jobTTL",
+			JobBackoffLimit:                  3,
 			LogLevel:                         zapcore.DebugLevel,
 			SpaceFinalizerAppDeletionTimeout: tools.PtrTo(int32(42)),
+			BuildCacheType:                   "registry",
+			RebaseOnStackUpdate:              true,
+			TransientBuildRetries:            2,
+			MaxConcurrentBuilds:              10,
+			MaxConcurrentBuildsPerSpace:      2,
+			MaxConcurrentTasksPerSpace:       5,
+			StatefulsetRunnerTopologySpread: config.TopologySpread{
+				TopologyKeys:      []string{"topology.kubernetes.io/zone"},
+				MaxSkew:           2,
+				WhenUnsatisfiable: "DoNotSchedule",
+			},
+			StatefulsetRunnerPodDisruptionBudget: config.PodDisruptionBudget{
+				MaxUnavailable: "1",
+			},
+			StatefulsetRunnerNodePlacement: config.NodePlacement{
+				NodeSelector: map[string]string{"pool": "apps"},
+				Tolerations: []config.Toleration{{
+					Key:               "dedicated",
+					Operator:          "Equal",
+					Value:             "apps",
+					Effect:            "NoExecute",
+					TolerationSeconds: tools.PtrTo(int64(60)),
+				}},
+			},
+			StatefulsetRunnerPriorityClassName: "apps",
+			StatefulsetRunnerRuntimeClassName:  "gvisor",
+			StatefulsetRunnerSecurityContext: config.SecurityContext{
+				RunAsNonRoot:           tools.PtrTo(false),
+				SeccompProfile:         "Unconfined",
+				DropCapabilities:       []string{"NET_RAW"},
+				ReadOnlyRootFilesystem: true,
+			},
 			Networking: config.Networking{
 				GatewayName:      "gw-name",
 				GatewayNamespace: "gw-ns",
+				TCPPorts:         []int32{1024},
+				SpaceIsolation: config.SpaceIsolation{
+					Enabled:                  true,
+					AllowedIngressNamespaces: []string{"monitoring"},
+				},
+			},
+			ReservedRouteHosts: []string{"login"},
+			NameUniqueness: config.NameUniqueness{
+				Apps:             "relaxed",
+				ServiceInstances: "enforced",
+				Routes:           "enforced",
 			},
 			ExperimentalManagedServicesEnabled: true,
 			TrustInsecureServiceBrokers:        true,
@@ -73,9 +125,15 @@ var _ = Describe("LoadFromPath", func() {
 		Expect(retErr).NotTo(HaveOccurred())
 		Expect(*retConfig).To(Equal(config.ControllerConfig{
 			CFProcessDefaults: config.CFProcessDefaults{
-				MemoryMB:    1024,
-				DiskQuotaMB: 512,
-				Timeout:     tools.PtrTo(int32(30)),
+				MemoryMB:                      1024,
+				DiskQuotaMB:                   512,
+				WebInstances:                  tools.PtrTo(int32(2)),
+				Timeout:                       tools.PtrTo(int32(30)),
+				TerminationGracePeriodSeconds: tools.PtrTo(int64(20)),
+			},
+			CFTaskDefaults: config.CFTaskDefaults{
+				MemoryMB:    256,
+				DiskQuotaMB: 128,
 			},
 			CFStagingResources: config.CFStagingResources{
 				BuildCacheMB: 1024,
@@ -84,17 +142,62 @@ var _ = Describe("LoadFromPath", func() {
 			},
 			CFRootNamespace:                  "rootNamespace",
 			ContainerRegistrySecretNames:     []string{"packageRegistrySecretName"},
+			InsecureContainerRegistries:      []string{"registry.internal:5000"},
 			TaskTTL:                          "taskTTL",
 			BuilderName:                      "buildReconciler",
 			RunnerName:                       "statefulset-runner",
 			NamespaceLabels:                  map[string]string{},
 			ExtraVCAPApplicationValues:       map[string]any{},
 			JobTTL:                           "jobTTL",
+			JobBackoffLimit:                  3,
 			LogLevel:                         zapcore.DebugLevel,
 			SpaceFinalizerAppDeletionTimeout: tools.PtrTo(int32(42)),
+			BuildCacheType:                   "registry",
+			RebaseOnStackUpdate:              true,
+			TransientBuildRetries:            2,
+			MaxConcurrentBuilds:              10,
+			MaxConcurrentBuildsPerSpace:      2,
+			MaxConcurrentTasksPerSpace:       5,
+			StatefulsetRunnerTopologySpread: config.TopologySpread{
+				TopologyKeys:      []string{"topology.kubernetes.io/zone"},
+				MaxSkew:           2,
+				WhenUnsatisfiable: "DoNotSchedule",
+			},
+			StatefulsetRunnerPodDisruptionBudget: config.PodDisruptionBudget{
+				MaxUnavailable: "1",
+			},
+			StatefulsetRunnerNodePlacement: config.NodePlacement{
+				NodeSelector: map[string]string{"pool": "apps"},
+				Tolerations: []config.Toleration{{
+					Key:               "dedicated",
+					Operator:          "Equal",
+					Value:             "apps",
+					Effect:            "NoExecute",
+					TolerationSeconds: tools.PtrTo(int64(60)),
+				}},
+			},
+			StatefulsetRunnerPriorityClassName: "apps",
+			StatefulsetRunnerRuntimeClassName:  "gvisor",
+			StatefulsetRunnerSecurityContext: config.SecurityContext{
+				RunAsNonRoot:           tools.PtrTo(false),
+				SeccompProfile:         "Unconfined",
+				DropCapabilities:       []string{"NET_RAW"},
+				ReadOnlyRootFilesystem: true,
+			},
 			Networking: config.Networking{
 				GatewayName:      "gw-name",
 				GatewayNamespace: "gw-ns",
+				TCPPorts:         []int32{1024},
+				SpaceIsolation: config.SpaceIsolation{
+					Enabled:                  true,
+					AllowedIngressNamespaces: []string{"monitoring"},
+				},
+			},
+			ReservedRouteHosts: []string{"login"},
+			NameUniqueness: config.NameUniqueness{
+				Apps:             "relaxed",
+				ServiceInstances: "enforced",
+				Routes:           "enforced",
 			},
 			ExperimentalManagedServicesEnabled: true,
 			TrustInsecureServiceBrokers:        true,
@@ -111,6 +214,39 @@ var _ = Describe("LoadFromPath", func() {
 		})
 	})
 
+	When("the CFProcess default web instances are not set", func() {
+		BeforeEach(func() {
+			cfg.CFProcessDefaults.WebInstances = nil
+		})
+
+		It("uses the CF default", func() {
+			Expect(retConfig.CFProcessDefaults.WebInstances).To(gstruct.PointTo(BeEquivalentTo(1)))
+		})
+	})
+
+	When("the CFProcess default termination grace period is not set", func() {
+		BeforeEach(func() {
+			cfg.CFProcessDefaults.TerminationGracePeriodSeconds = nil
+		})
+
+		It("uses the CF default", func() {
+			Expect(retConfig.CFProcessDefaults.TerminationGracePeriodSeconds).To(gstruct.PointTo(BeEquivalentTo(10)))
+		})
+	})
+
+	When("the task defaults are not set", func() {
+		BeforeEach(func() {
+			cfg.CFTaskDefaults = config.CFTaskDefaults{}
+		})
+
+		It("uses the process defaults", func() {
+			Expect(retConfig.CFTaskDefaults).To(Equal(config.CFTaskDefaults{
+				MemoryMB:    1024,
+				DiskQuotaMB: 512,
+			}))
+		})
+	})
+
 	When("log level is not set", func() {
 		BeforeEach(func() {
 			cfg.LogLevel = 0
@@ -140,6 +276,200 @@ var _ = Describe("LoadFromPath", func() {
 			Expect(retConfig.CFStagingResources.BuildCacheMB).To(Equal(int64(2048)))
 		})
 	})
+
+	When("the topology spread max skew and action are not set", func() {
+		BeforeEach(func() {
+			cfg.StatefulsetRunnerTopologySpread.MaxSkew = 0
+			cfg.StatefulsetRunnerTopologySpread.WhenUnsatisfiable = ""
+		})
+
+		It("uses the defaults", func() {
+			Expect(retConfig.StatefulsetRunnerTopologySpread).To(Equal(config.TopologySpread{
+				TopologyKeys:      []string{"topology.kubernetes.io/zone"},
+				MaxSkew:           1,
+				WhenUnsatisfiable: config.ScheduleAnyway,
+			}))
+		})
+	})
+
+	When("the topology spread action is unknown", func() {
+		BeforeEach(func() {
+			cfg.StatefulsetRunnerTopologySpread.WhenUnsatisfiable = "Maybe"
+		})
+
+		It("returns an error", func() {
+			Expect(retErr).To(MatchError(ContainSubstring(`invalid topology spread whenUnsatisfiable "Maybe"`)))
+		})
+	})
+
+	When("the pod disruption budget is not set", func() {
+		BeforeEach(func() {
+			cfg.StatefulsetRunnerPodDisruptionBudget = config.PodDisruptionBudget{}
+		})
+
+		It("keeps half of the instances available", func() {
+			Expect(retConfig.StatefulsetRunnerPodDisruptionBudget).To(Equal(config.PodDisruptionBudget{
+				MinAvailable: "50%",
+			}))
+		})
+	})
+
+	When("both min available and max unavailable instances are set", func() {
+		BeforeEach(func() {
+			cfg.StatefulsetRunnerPodDisruptionBudget.MinAvailable = "50%"
+		})
+
+		It("returns an error", func() {
+			Expect(retErr).To(MatchError(ContainSubstring("only one of minAvailable or maxUnavailable can be set")))
+		})
+	})
+
+	When("the security context is not set", func() {
+		BeforeEach(func() {
+			cfg.StatefulsetRunnerSecurityContext = config.SecurityContext{}
+		})
+
+		It("uses the restricted defaults", func() {
+			Expect(retConfig.StatefulsetRunnerSecurityContext).To(Equal(config.SecurityContext{
+				RunAsNonRoot:     tools.PtrTo(true),
+				SeccompProfile:   config.SeccompProfileRuntimeDefault,
+				DropCapabilities: []string{"ALL"},
+			}))
+		})
+	})
+
+	When("the security context seccomp profile is unknown", func() {
+		BeforeEach(func() {
+			cfg.StatefulsetRunnerSecurityContext.SeccompProfile = "Localhost"
+		})
+
+		It("returns an error", func() {
+			Expect(retErr).To(MatchError(ContainSubstring(`invalid security context seccompProfile "Localhost"`)))
+		})
+	})
+
+	When("the build cache type is not set", func() {
+		BeforeEach(func() {
+			cfg.BuildCacheType = ""
+		})
+
+		It("caches builds on a volume", func() {
+			Expect(retConfig.BuildCacheType).To(Equal(config.VolumeBuildCache))
+		})
+	})
+
+	When("the build cache type is unknown", func() {
+		BeforeEach(func() {
+			cfg.BuildCacheType = "s3"
+		})
+
+		It("returns an error", func() {
+			Expect(retErr).To(MatchError(ContainSubstring(`invalid build cache type "s3"`)))
+		})
+	})
+
+	When("the name uniqueness is not set", func() {
+		BeforeEach(func() {
+			cfg.NameUniqueness = config.NameUniqueness{}
+		})
+
+		It("enforces unique names", func() {
+			Expect(retConfig.NameUniqueness).To(Equal(config.NameUniqueness{
+				Apps:             config.UniquenessEnforced,
+				ServiceInstances: config.UniquenessEnforced,
+				Routes:           config.UniquenessEnforced,
+			}))
+		})
+	})
+
+	When("the name uniqueness is unknown", func() {
+		BeforeEach(func() {
+			cfg.NameUniqueness.Routes = "global"
+		})
+
+		It("returns an error", func() {
+			Expect(retErr).To(MatchError(ContainSubstring(`invalid name uniqueness "global"`)))
+		})
+	})
+
+	When("idling is enabled", func() {
+		BeforeEach(func() {
+			cfg.Idling = config.Idling{
+				Enabled:                   true,
+				ActivatorServiceName:      "korifi-activator",
+				ActivatorServiceNamespace: "korifi",
+				ActivatorSigningKeyPath:   "/etc/korifi-activator/key",
+			}
+		})
+
+		It("defaults the activator port", func() {
+			Expect(retConfig.Idling.ActivatorPort).To(BeEquivalentTo(8000))
+		})
+
+		When("the activator signing key path is not set", func() {
+			BeforeEach(func() {
+				cfg.Idling.ActivatorSigningKeyPath = ""
+			})
+
+			It("returns an error", func() {
+				Expect(retErr).To(MatchError(ContainSubstring("activatorSigningKeyPath is required")))
+			})
+		})
+
+		When("the activator service is not set", func() {
+			BeforeEach(func() {
+				cfg.Idling.ActivatorServiceName = ""
+			})
+
+			It("returns an error", func() {
+				Expect(retErr).To(MatchError(ContainSubstring("activatorServiceName and activatorServiceNamespace are required")))
+			})
+		})
+	})
+
+	When("instance identity is enabled without a CA secret namespace", func() {
+		BeforeEach(func() {
+			cfg.StatefulsetRunnerInstanceIdentity = config.InstanceIdentity{
+				CASecretName: "instance-identity-ca",
+			}
+		})
+
+		It("returns an error", func() {
+			Expect(retErr).To(MatchError(ContainSubstring("caSecretNamespace is required")))
+		})
+	})
+
+	When("the audit event sink is enabled", func() {
+		BeforeEach(func() {
+			cfg.AuditEventSink = config.AuditEventSink{
+				URL: "https://siem.example.com/events",
+			}
+		})
+
+		It("posts the events as json by default", func() {
+			Expect(retConfig.AuditEventSink.Format).To(Equal(config.AuditEventFormatJSON))
+		})
+
+		When("the format is unknown", func() {
+			BeforeEach(func() {
+				cfg.AuditEventSink.Format = "kafka"
+			})
+
+			It("returns an error", func() {
+				Expect(retErr).To(MatchError(ContainSubstring(`invalid audit event sink format "kafka"`)))
+			})
+		})
+	})
+
+	When("the number of shards is negative", func() {
+		BeforeEach(func() {
+			cfg.Sharding.Shards = -1
+		})
+
+		It("returns an error", func() {
+			Expect(retErr).To(MatchError(ContainSubstring("invalid number of shards -1")))
+		})
+	})
 })
 
 var _ = Describe("ParseTaskTTL", func() {
@@ -188,6 +518,51 @@ var _ = Describe("ParseTaskTTL", func() {
 	})
 })
 
+var _ = Describe("ParseStagingTimeout", func() {
+	var (
+		stagingTimeout    time.Duration
+		parseErr          error
+		stagingTimeoutStr string
+	)
+
+	BeforeEach(func() {
+		stagingTimeoutStr = ""
+	})
+
+	JustBeforeEach(func() {
+		cfg := config.ControllerConfig{
+			StagingTimeout: stagingTimeoutStr,
+		}
+		stagingTimeout, parseErr = cfg.ParseStagingTimeout()
+	})
+
+	It("returns 15 minutes by default", func() {
+		Expect(parseErr).NotTo(HaveOccurred())
+		Expect(stagingTimeout).To(Equal(15 * time.Minute))
+	})
+
+	When("the timeout is set", func() {
+		BeforeEach(func() {
+			stagingTimeoutStr = "1h30m"
+		})
+
+		It("parses it", func() {
+			Expect(parseErr).NotTo(HaveOccurred())
+			Expect(stagingTimeout).To(Equal(90 * time.Minute))
+		})
+	})
+
+	When("the timeout cannot be parsed", func() {
+		BeforeEach(func() {
+			stagingTimeoutStr = "forever"
+		})
+
+		It("returns an error", func() {
+			Expect(parseErr).To(HaveOccurred())
+		})
+	})
+})
+
 var _ = Describe("ParseJobTTL", func() {
 	var (
 		jobTTL    time.Duration
@@ -232,3 +607,375 @@ var _ = Describe("ParseJobTTL", func() {
 		})
 	})
 })
+
+var _ = Describe("ParseRetentionCleanupInterval", func() {
+	var (
+		interval    time.Duration
+		parseErr    error
+		intervalStr string
+	)
+
+	BeforeEach(func() {
+		intervalStr = ""
+	})
+
+	JustBeforeEach(func() {
+		cfg := config.ControllerConfig{
+			RetentionCleanupInterval: intervalStr,
+		}
+
+		interval, parseErr = cfg.ParseRetentionCleanupInterval()
+	})
+
+	It("returns an hour by default", func() {
+		Expect(parseErr).NotTo(HaveOccurred())
+		Expect(interval).To(Equal(time.Hour))
+	})
+
+	When("the interval is set", func() {
+		BeforeEach(func() {
+			intervalStr = "30m"
+		})
+
+		It("parses it", func() {
+			Expect(parseErr).NotTo(HaveOccurred())
+			Expect(interval).To(Equal(30 * time.Minute))
+		})
+	})
+
+	When("the interval cannot be parsed", func() {
+		BeforeEach(func() {
+			intervalStr = "often"
+		})
+
+		It("returns an error", func() {
+			Expect(parseErr).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("ParseAppUsageEventRetention", func() {
+	var (
+		retention    time.Duration
+		parseErr     error
+		retentionStr string
+	)
+
+	BeforeEach(func() {
+		retentionStr = ""
+	})
+
+	JustBeforeEach(func() {
+		cfg := config.ControllerConfig{
+			AppUsageEventRetention: retentionStr,
+		}
+
+		retention, parseErr = cfg.ParseAppUsageEventRetention()
+	})
+
+	It("returns 31 days by default", func() {
+		Expect(parseErr).NotTo(HaveOccurred())
+		Expect(retention).To(Equal(31 * 24 * time.Hour))
+	})
+
+	When("the retention is set", func() {
+		BeforeEach(func() {
+			retentionStr = "7d"
+		})
+
+		It("parses it", func() {
+			Expect(parseErr).NotTo(HaveOccurred())
+			Expect(retention).To(Equal(7 * 24 * time.Hour))
+		})
+	})
+
+	When("the retention cannot be parsed", func() {
+		BeforeEach(func() {
+			retentionStr = "forever"
+		})
+
+		It("returns an error", func() {
+			Expect(parseErr).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("ParseServiceUsageEventRetention", func() {
+	var (
+		retention    time.Duration
+		parseErr     error
+		retentionStr string
+	)
+
+	BeforeEach(func() {
+		retentionStr = ""
+	})
+
+	JustBeforeEach(func() {
+		cfg := config.ControllerConfig{
+			ServiceUsageEventRetention: retentionStr,
+		}
+
+		retention, parseErr = cfg.ParseServiceUsageEventRetention()
+	})
+
+	It("returns 31 days by default", func() {
+		Expect(parseErr).NotTo(HaveOccurred())
+		Expect(retention).To(Equal(31 * 24 * time.Hour))
+	})
+
+	When("the retention is set", func() {
+		BeforeEach(func() {
+			retentionStr = "7d"
+		})
+
+		It("parses it", func() {
+			Expect(parseErr).NotTo(HaveOccurred())
+			Expect(retention).To(Equal(7 * 24 * time.Hour))
+		})
+	})
+
+	When("the retention cannot be parsed", func() {
+		BeforeEach(func() {
+			retentionStr = "forever"
+		})
+
+		It("returns an error", func() {
+			Expect(parseErr).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("ParseAuditEventRetention", func() {
+	var (
+		retention    time.Duration
+		parseErr     error
+		retentionStr string
+	)
+
+	BeforeEach(func() {
+		retentionStr = ""
+	})
+
+	JustBeforeEach(func() {
+		cfg := config.ControllerConfig{
+			AuditEventRetention: retentionStr,
+		}
+
+		retention, parseErr = cfg.ParseAuditEventRetention()
+	})
+
+	It("returns 31 days by default", func() {
+		Expect(parseErr).NotTo(HaveOccurred())
+		Expect(retention).To(Equal(31 * 24 * time.Hour))
+	})
+
+	When("the retention is set", func() {
+		BeforeEach(func() {
+			retentionStr = "7d"
+		})
+
+		It("parses it", func() {
+			Expect(parseErr).NotTo(HaveOccurred())
+			Expect(retention).To(Equal(7 * 24 * time.Hour))
+		})
+	})
+
+	When("the retention cannot be parsed", func() {
+		BeforeEach(func() {
+			retentionStr = "forever"
+		})
+
+		It("returns an error", func() {
+			Expect(parseErr).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("ParseWakeTimeout", func() {
+	var (
+		timeout    time.Duration
+		parseErr   error
+		timeoutStr string
+	)
+
+	BeforeEach(func() {
+		timeoutStr = ""
+	})
+
+	JustBeforeEach(func() {
+		cfg := config.ControllerConfig{
+			Idling: config.Idling{WakeTimeout: timeoutStr},
+		}
+
+		timeout, parseErr = cfg.ParseWakeTimeout()
+	})
+
+	It("returns a minute by default", func() {
+		Expect(parseErr).NotTo(HaveOccurred())
+		Expect(timeout).To(Equal(time.Minute))
+	})
+
+	When("the timeout is set", func() {
+		BeforeEach(func() {
+			timeoutStr = "90s"
+		})
+
+		It("parses it", func() {
+			Expect(parseErr).NotTo(HaveOccurred())
+			Expect(timeout).To(Equal(90 * time.Second))
+		})
+	})
+
+	When("the timeout cannot be parsed", func() {
+		BeforeEach(func() {
+			timeoutStr = "eventually"
+		})
+
+		It("returns an error", func() {
+			Expect(parseErr).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("SpaceIngressNamespaces", func() {
+	var (
+		cfg        config.ControllerConfig
+		namespaces []string
+	)
+
+	BeforeEach(func() {
+		cfg = config.ControllerConfig{
+			Networking: config.Networking{
+				GatewayNamespace: "korifi-gateway",
+				SpaceIsolation: config.SpaceIsolation{
+					Enabled:                  true,
+					AllowedIngressNamespaces: []string{"monitoring", "korifi-gateway"},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		namespaces = cfg.SpaceIngressNamespaces()
+	})
+
+	It("returns the gateway and allowed namespaces", func() {
+		Expect(namespaces).To(Equal([]string{"korifi-gateway", "monitoring"}))
+	})
+
+	When("idling is enabled", func() {
+		BeforeEach(func() {
+			cfg.Idling = config.Idling{
+				Enabled:                   true,
+				ActivatorServiceNamespace: "korifi",
+			}
+		})
+
+		It("includes the activator namespace", func() {
+			Expect(namespaces).To(Equal([]string{"korifi", "korifi-gateway", "monitoring"}))
+		})
+	})
+
+	When("space isolation is disabled", func() {
+		BeforeEach(func() {
+			cfg.Networking.SpaceIsolation.Enabled = false
+		})
+
+		It("returns nil", func() {
+			Expect(namespaces).To(BeNil())
+		})
+	})
+})
+
+var _ = Describe("ParseContainerRegistryImageDeletion", func() {
+	var (
+		policy    image.DeletionPolicy
+		parseErr  error
+		policyStr string
+	)
+
+	BeforeEach(func() {
+		policyStr = ""
+	})
+
+	JustBeforeEach(func() {
+		cfg := config.ControllerConfig{
+			ContainerRegistryImageDeletion: policyStr,
+		}
+
+		policy, parseErr = cfg.ParseContainerRegistryImageDeletion()
+	})
+
+	It("deletes manifests by default", func() {
+		Expect(parseErr).NotTo(HaveOccurred())
+		Expect(policy).To(Equal(image.DeleteManifests))
+	})
+
+	When("the policy is set", func() {
+		BeforeEach(func() {
+			policyStr = "tags"
+		})
+
+		It("parses it", func() {
+			Expect(parseErr).NotTo(HaveOccurred())
+			Expect(policy).To(Equal(image.DeleteTags))
+		})
+	})
+
+	When("the policy is unknown", func() {
+		BeforeEach(func() {
+			policyStr = "everything"
+		})
+
+		It("returns an error", func() {
+			Expect(parseErr).To(MatchError(ContainSubstring(`invalid image deletion policy "everything"`)))
+		})
+	})
+})
+
+var _ = Describe("ParseCertificateValidity", func() {
+	var (
+		validity    time.Duration
+		parseErr    error
+		validityStr string
+	)
+
+	BeforeEach(func() {
+		validityStr = ""
+	})
+
+	JustBeforeEach(func() {
+		cfg := config.ControllerConfig{
+			StatefulsetRunnerInstanceIdentity: config.InstanceIdentity{CertificateValidity: validityStr},
+		}
+
+		validity, parseErr = cfg.ParseCertificateValidity()
+	})
+
+	It("returns a day by default", func() {
+		Expect(parseErr).NotTo(HaveOccurred())
+		Expect(validity).To(Equal(24 * time.Hour))
+	})
+
+	When("the validity is set", func() {
+		BeforeEach(func() {
+			validityStr = "6h"
+		})
+
+		It("parses it", func() {
+			Expect(parseErr).NotTo(HaveOccurred())
+			Expect(validity).To(Equal(6 * time.Hour))
+		})
+	})
+
+	When("the validity cannot be parsed", func() {
+		BeforeEach(func() {
+			validityStr = "forever"
+		})
+
+		It("returns an error", func() {
+			Expect(parseErr).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,195 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const authorizationSecretKey = "authorization"
+
+// Reconciler delivers the CFAuditEvents to the sink. Events that fail to be
+// delivered are retried with the backoff of the controller until the sink
+// accepts them, and are annotated as delivered once it does.
+type Reconciler struct {
+	k8sClient               client.Client
+	sink                    *Sink
+	rootNamespace           string
+	authorizationSecretName string
+	log                     logr.Logger
+}
+
+func NewReconciler(
+	k8sClient client.Client,
+	sink *Sink,
+	rootNamespace string,
+	authorizationSecretName string,
+	log logr.Logger,
+) *Reconciler {
+	return &Reconciler{
+		k8sClient:               k8sClient,
+		sink:                    sink,
+		rootNamespace:           rootNamespace,
+		authorizationSecretName: authorizationSecretName,
+		log:                     log,
+	}
+}
+
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("audit-event-sink").
+		For(&korifiv1alpha1.CFAuditEvent{}).
+		WithEventFilter(predicate.NewPredicateFuncs(isUndelivered)).
+		Complete(r)
+}
+
+func isUndelivered(object client.Object) bool {
+	return object.GetAnnotations()[korifiv1alpha1.AuditEventDeliveredAnnotationKey] != "true"
+}
+
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfauditevents,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile delivers the audit event to the sink and marks it as delivered
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (ctrl.Result, error) {
+	log := r.log.WithName("AuditEventSink").
+		WithValues("namespace", req.Namespace).
+		WithValues("name", req.Name).
+		WithValues("logID", uuid.NewString())
+
+	auditEvent := &korifiv1alpha1.CFAuditEvent{}
+	err := r.k8sClient.Get(ctx, req.NamespacedName, auditEvent)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Info("unable to fetch audit event", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	if !isUndelivered(auditEvent) {
+		return ctrl.Result{}, nil
+	}
+
+	event, err := r.presentAuditEvent(ctx, auditEvent)
+	if err != nil {
+		log.Info("unable to present audit event", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	authorization, err := r.authorization(ctx)
+	if err != nil {
+		log.Info("unable to get the sink authorization", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	err = r.sink.Deliver(ctx, event, authorization)
+	if err != nil {
+		log.Info("unable to deliver audit event", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	err = k8s.PatchResource(ctx, r.k8sClient, auditEvent, func() {
+		if auditEvent.Annotations == nil {
+			auditEvent.Annotations = map[string]string{}
+		}
+		auditEvent.Annotations[korifiv1alpha1.AuditEventDeliveredAnnotationKey] = "true"
+	})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		log.Info("unable to mark audit event as delivered", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *Reconciler) authorization(ctx context.Context) (string, error) {
+	if r.authorizationSecretName == "" {
+		return "", nil
+	}
+
+	secret := &corev1.Secret{}
+	err := r.k8sClient.Get(ctx, client.ObjectKey{Namespace: r.rootNamespace, Name: r.authorizationSecretName}, secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %q: %w", r.authorizationSecretName, err)
+	}
+
+	return string(secret.Data[authorizationSecretKey]), nil
+}
+
+// presentAuditEvent presents the audit event like the API does
+func (r *Reconciler) presentAuditEvent(ctx context.Context, auditEvent *korifiv1alpha1.CFAuditEvent) (Event, error) {
+	orgGUID, err := r.orgGUID(ctx, auditEvent.Namespace)
+	if err != nil {
+		return Event{}, err
+	}
+
+	createdAt := formatTimestamp(auditEvent.CreationTimestamp.Time)
+
+	return Event{
+		GUID:      auditEvent.Name,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		Type:      auditEvent.Spec.Type,
+		Actor: EventActor{
+			GUID: auditEvent.Spec.ActorGUID,
+			Type: auditEvent.Spec.ActorType,
+			Name: auditEvent.Spec.ActorName,
+		},
+		Target: EventActor{
+			GUID: auditEvent.Spec.TargetGUID,
+			Type: auditEvent.Spec.TargetType,
+		},
+		Data:         auditEventData(auditEvent.Spec),
+		Space:        Relationship{GUID: auditEvent.Namespace},
+		Organization: Relationship{GUID: orgGUID},
+	}, nil
+}
+
+func auditEventData(spec korifiv1alpha1.CFAuditEventSpec) map[string]any {
+	if spec.AppCrash == nil {
+		return map[string]any{}
+	}
+
+	return map[string]any{
+		"index":            int(spec.AppCrash.InstanceIndex),
+		"instance":         spec.AppCrash.InstanceName,
+		"reason":           "CRASHED",
+		"exit_status":      int(spec.AppCrash.ExitStatus),
+		"exit_reason":      spec.AppCrash.ExitReason,
+		"exit_description": spec.AppCrash.ExitDescription,
+		"crash_count":      int(spec.AppCrash.CrashCount),
+	}
+}
+
+func (r *Reconciler) orgGUID(ctx context.Context, ns string) (string, error) {
+	spaces := korifiv1alpha1.CFSpaceList{}
+	if err := r.k8sClient.List(ctx, &spaces, client.MatchingFields{
+		shared.IndexSpaceNamespaceName: ns,
+	}); err != nil {
+		return "", fmt.Errorf("error listing cfSpaces: %w", err)
+	}
+
+	// the event is still delivered when its space is gone
+	if len(spaces.Items) == 0 {
+		return "", nil
+	}
+
+	return spaces.Items[0].Namespace, nil
+}
+
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
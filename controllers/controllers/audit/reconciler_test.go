@@ -0,0 +1,154 @@
+package audit_test
+
+import (
+	"net/http"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/controllers/audit"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Audit event sink", func() {
+	var (
+		orgNamespace string
+		spaceGUID    string
+		auditEvent   *korifiv1alpha1.CFAuditEvent
+	)
+
+	BeforeEach(func() {
+		orgNamespace = uuid.NewString()
+		spaceGUID = uuid.NewString()
+		for _, ns := range []string{orgNamespace, spaceGUID} {
+			Expect(adminClient.Create(ctx, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: ns,
+				},
+			})).To(Succeed())
+		}
+
+		Expect(adminClient.Create(ctx, &korifiv1alpha1.CFSpace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      spaceGUID,
+				Namespace: orgNamespace,
+			},
+			Spec: korifiv1alpha1.CFSpaceSpec{
+				DisplayName: "space-name",
+			},
+		})).To(Succeed())
+
+		auditEvent = &korifiv1alpha1.CFAuditEvent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      uuid.NewString(),
+				Namespace: spaceGUID,
+			},
+			Spec: korifiv1alpha1.CFAuditEventSpec{
+				Type:       korifiv1alpha1.AuditEventTypeAppCrash,
+				ActorGUID:  "process-guid",
+				ActorType:  "process",
+				ActorName:  "web",
+				TargetGUID: "app-guid",
+				TargetType: "app",
+				AppCrash: &korifiv1alpha1.AppCrash{
+					InstanceName:    "app-instance-1",
+					InstanceIndex:   1,
+					ExitStatus:      137,
+					ExitReason:      "OOMKilled",
+					ExitDescription: "out of memory",
+					CrashCount:      3,
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		Expect(adminClient.Create(ctx, auditEvent)).To(Succeed())
+	})
+
+	It("delivers the event to the sink", func() {
+		Eventually(func(g Gomega) {
+			g.Expect(delivered(auditEvent.Name)).To(ConsistOf(MatchAllFields(Fields{
+				"Authorization": Equal("Bearer sink-token"),
+				"Event": MatchFields(IgnoreExtras, Fields{
+					"Type": Equal("app.crash"),
+					"Actor": Equal(audit.EventActor{
+						GUID: "process-guid",
+						Type: "process",
+						Name: "web",
+					}),
+					"Target": Equal(audit.EventActor{
+						GUID: "app-guid",
+						Type: "app",
+					}),
+					"Data": MatchAllKeys(Keys{
+						"index":            BeNumerically("==", 1),
+						"instance":         Equal("app-instance-1"),
+						"reason":           Equal("CRASHED"),
+						"exit_status":      BeNumerically("==", 137),
+						"exit_reason":      Equal("OOMKilled"),
+						"exit_description": Equal("out of memory"),
+						"crash_count":      BeNumerically("==", 3),
+					}),
+					"Space":        Equal(audit.Relationship{GUID: spaceGUID}),
+					"Organization": Equal(audit.Relationship{GUID: orgNamespace}),
+				}),
+			})))
+		}).Should(Succeed())
+	})
+
+	It("marks the event as delivered", func() {
+		Eventually(func(g Gomega) {
+			g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(auditEvent), auditEvent)).To(Succeed())
+			g.Expect(auditEvent.Annotations).To(HaveKeyWithValue(korifiv1alpha1.AuditEventDeliveredAnnotationKey, "true"))
+		}).Should(Succeed())
+
+		Consistently(func(g Gomega) {
+			g.Expect(delivered(auditEvent.Name)).To(HaveLen(1))
+		}, "1s").Should(Succeed())
+	})
+
+	When("the event has already been delivered", func() {
+		BeforeEach(func() {
+			auditEvent.Annotations = map[string]string{
+				korifiv1alpha1.AuditEventDeliveredAnnotationKey: "true",
+			}
+		})
+
+		It("does not deliver it again", func() {
+			Consistently(func(g Gomega) {
+				g.Expect(delivered(auditEvent.Name)).To(BeEmpty())
+			}, "1s").Should(Succeed())
+		})
+	})
+
+	When("the sink is unavailable", func() {
+		BeforeEach(func() {
+			setSinkStatus(http.StatusServiceUnavailable)
+		})
+
+		It("retries the delivery until the sink accepts the event", func() {
+			Consistently(func(g Gomega) {
+				g.Expect(delivered(auditEvent.Name)).To(BeEmpty())
+			}, "1s").Should(Succeed())
+
+			setSinkStatus(http.StatusOK)
+
+			Eventually(func(g Gomega) {
+				g.Expect(delivered(auditEvent.Name)).To(HaveLen(1))
+			}).Should(Succeed())
+		})
+
+		It("does not mark the event as delivered", func() {
+			Consistently(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(auditEvent), auditEvent)).To(Succeed())
+				g.Expect(auditEvent.Annotations).NotTo(HaveKey(korifiv1alpha1.AuditEventDeliveredAnnotationKey))
+			}, "1s").Should(Succeed())
+		})
+	})
+})
@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"code.cloudfoundry.org/korifi/controllers/config"
+)
+
+// Event is an audit event as presented by the API, with the organization of
+// its space so that consumers need not look it up
+type Event struct {
+	GUID         string         `json:"guid"`
+	CreatedAt    string         `json:"created_at"`
+	UpdatedAt    string         `json:"updated_at"`
+	Type         string         `json:"type"`
+	Actor        EventActor     `json:"actor"`
+	Target       EventActor     `json:"target"`
+	Data         map[string]any `json:"data"`
+	Space        Relationship   `json:"space"`
+	Organization Relationship   `json:"organization"`
+}
+
+type EventActor struct {
+	GUID string `json:"guid"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type Relationship struct {
+	GUID string `json:"guid"`
+}
+
+// cloudEvent is a structured mode CloudEvent, see
+// https://github.com/cloudevents/spec/blob/v1.0.2/cloudevents/formats/json-format.md
+type cloudEvent struct {
+	SpecVersion     string `json:"specversion"`
+	ID              string `json:"id"`
+	Source          string `json:"source"`
+	Type            string `json:"type"`
+	Subject         string `json:"subject"`
+	Time            string `json:"time"`
+	DataContentType string `json:"datacontenttype"`
+	Data            Event  `json:"data"`
+}
+
+// Sink posts audit events to an HTTP endpoint, e.g. the collector of a SIEM
+type Sink struct {
+	httpClient *http.Client
+	url        string
+	format     string
+}
+
+func NewSink(httpClient *http.Client, url, format string) *Sink {
+	return &Sink{
+		httpClient: httpClient,
+		url:        url,
+		format:     format,
+	}
+}
+
+// Deliver posts the event, sending authorization as the Authorization header
+// unless it is empty. Any response but a 2xx is an error.
+func (s *Sink) Deliver(ctx context.Context, event Event, authorization string) error {
+	body, contentType, err := s.encode(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post audit event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("audit event sink responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (s *Sink) encode(event Event) ([]byte, string, error) {
+	if s.format != config.AuditEventFormatCloudEvents {
+		body, err := json.Marshal(event)
+		return body, "application/json", err
+	}
+
+	body, err := json.Marshal(cloudEvent{
+		SpecVersion: "1.0",
+		// the runners update the kubernetes event of an instance that keeps
+		// crashing, so the guid alone does not identify a delivery
+		ID:              event.GUID + "/" + event.UpdatedAt,
+		Source:          "/v3/spaces/" + event.Space.GUID,
+		Type:            "org.cloudfoundry.audit." + event.Type,
+		Subject:         event.Target.GUID,
+		Time:            event.UpdatedAt,
+		DataContentType: "application/json",
+		Data:            event,
+	})
+	return body, "application/cloudevents+json", err
+}
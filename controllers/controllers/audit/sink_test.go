@@ -0,0 +1,85 @@
+package audit_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/korifi/controllers/config"
+	"code.cloudfoundry.org/korifi/controllers/controllers/audit"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sink", func() {
+	var (
+		server      *httptest.Server
+		status      int
+		contentType string
+		body        map[string]any
+		format      string
+		deliverErr  error
+	)
+
+	BeforeEach(func() {
+		status = http.StatusAccepted
+		format = config.AuditEventFormatJSON
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contentType = r.Header.Get("Content-Type")
+			rawBody, err := io.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Unmarshal(rawBody, &body)).To(Succeed())
+
+			w.WriteHeader(status)
+		}))
+		DeferCleanup(server.Close)
+	})
+
+	JustBeforeEach(func() {
+		sink := audit.NewSink(server.Client(), server.URL, format)
+		deliverErr = sink.Deliver(ctx, audit.Event{
+			GUID:      "event-guid",
+			UpdatedAt: "2024-01-02T03:04:05Z",
+			Type:      "app.crash",
+			Target:    audit.EventActor{GUID: "app-guid", Type: "app"},
+			Space:     audit.Relationship{GUID: "space-guid"},
+		}, "")
+	})
+
+	It("posts the event as json", func() {
+		Expect(deliverErr).NotTo(HaveOccurred())
+		Expect(contentType).To(Equal("application/json"))
+		Expect(body).To(HaveKeyWithValue("guid", "event-guid"))
+		Expect(body).To(HaveKeyWithValue("type", "app.crash"))
+	})
+
+	When("the format is cloudevents", func() {
+		BeforeEach(func() {
+			format = config.AuditEventFormatCloudEvents
+		})
+
+		It("posts the event as a structured cloud event", func() {
+			Expect(deliverErr).NotTo(HaveOccurred())
+			Expect(contentType).To(Equal("application/cloudevents+json"))
+			Expect(body).To(HaveKeyWithValue("specversion", "1.0"))
+			Expect(body).To(HaveKeyWithValue("id", "event-guid/2024-01-02T03:04:05Z"))
+			Expect(body).To(HaveKeyWithValue("source", "/v3/spaces/space-guid"))
+			Expect(body).To(HaveKeyWithValue("type", "org.cloudfoundry.audit.app.crash"))
+			Expect(body).To(HaveKeyWithValue("subject", "app-guid"))
+			Expect(body).To(HaveKeyWithValue("data", HaveKeyWithValue("guid", "event-guid")))
+		})
+	})
+
+	When("the sink rejects the event", func() {
+		BeforeEach(func() {
+			status = http.StatusInternalServerError
+		})
+
+		It("returns an error", func() {
+			Expect(deliverErr).To(MatchError(ContainSubstring("status 500")))
+		})
+	})
+})
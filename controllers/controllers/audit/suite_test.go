@@ -0,0 +1,162 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/config"
+	"code.cloudfoundry.org/korifi/controllers/controllers/audit"
+	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
+	"code.cloudfoundry.org/korifi/tests/helpers"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+var (
+	ctx             context.Context
+	stopManager     context.CancelFunc
+	stopClientCache context.CancelFunc
+	testEnv         *envtest.Environment
+	adminClient     client.Client
+	rootNamespace   string
+	sinkServer      *httptest.Server
+	sinkMutex       sync.Mutex
+	sinkStatus      int
+	deliveredEvents []deliveredEvent
+)
+
+type deliveredEvent struct {
+	Authorization string
+	Event         audit.Event
+}
+
+func TestAudit(t *testing.T) {
+	SetDefaultEventuallyTimeout(30 * time.Second)
+	SetDefaultEventuallyPollingInterval(250 * time.Millisecond)
+
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Audit Controllers Integration Suite")
+}
+
+var _ = BeforeSuite(func() {
+	logf.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
+
+	ctx = context.Background()
+
+	testEnv = &envtest.Environment{
+		CRDDirectoryPaths: []string{
+			filepath.Join("..", "..", "..", "helm", "korifi", "controllers", "crds"),
+		},
+		ErrorIfCRDPathMissing: true,
+	}
+
+	_, err := testEnv.Start()
+	Expect(err).NotTo(HaveOccurred())
+
+	Expect(korifiv1alpha1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	k8sManager := helpers.NewK8sManager(testEnv, filepath.Join("helm", "korifi", "controllers", "role.yaml"))
+	Expect(shared.SetupIndexWithManager(k8sManager)).To(Succeed())
+
+	adminClient, stopClientCache = helpers.NewCachedClient(testEnv.Config)
+
+	rootNamespace = uuid.NewString()
+	Expect(adminClient.Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: rootNamespace,
+		},
+	})).To(Succeed())
+
+	Expect(adminClient.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sink-authorization",
+			Namespace: rootNamespace,
+		},
+		StringData: map[string]string{
+			"authorization": "Bearer sink-token",
+		},
+	})).To(Succeed())
+
+	sinkServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sinkMutex.Lock()
+		defer sinkMutex.Unlock()
+
+		if sinkStatus != http.StatusOK {
+			w.WriteHeader(sinkStatus)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		Expect(err).NotTo(HaveOccurred())
+
+		event := audit.Event{}
+		Expect(json.Unmarshal(body, &event)).To(Succeed())
+		deliveredEvents = append(deliveredEvents, deliveredEvent{
+			Authorization: r.Header.Get("Authorization"),
+			Event:         event,
+		})
+	}))
+
+	Expect(audit.NewReconciler(
+		k8sManager.GetClient(),
+		audit.NewSink(sinkServer.Client(), sinkServer.URL, config.AuditEventFormatJSON),
+		rootNamespace,
+		"sink-authorization",
+		ctrl.Log.WithName("controllers").WithName("AuditEventSink"),
+	).SetupWithManager(k8sManager)).To(Succeed())
+
+	stopManager = helpers.StartK8sManager(k8sManager)
+})
+
+var _ = AfterSuite(func() {
+	sinkServer.Close()
+	stopClientCache()
+	stopManager()
+	Expect(testEnv.Stop()).To(Succeed())
+})
+
+var _ = BeforeEach(func() {
+	sinkMutex.Lock()
+	defer sinkMutex.Unlock()
+
+	sinkStatus = http.StatusOK
+	deliveredEvents = nil
+})
+
+func setSinkStatus(status int) {
+	sinkMutex.Lock()
+	defer sinkMutex.Unlock()
+
+	sinkStatus = status
+}
+
+func delivered(guid string) []deliveredEvent {
+	sinkMutex.Lock()
+	defer sinkMutex.Unlock()
+
+	result := []deliveredEvent{}
+	for _, e := range deliveredEvents {
+		if e.Event.GUID == guid {
+			result = append(result, e)
+		}
+	}
+	return result
+}
@@ -18,44 +18,100 @@ package domains
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"time"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/config"
 	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
+	"code.cloudfoundry.org/korifi/tools"
 	"code.cloudfoundry.org/korifi/tools/k8s"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
+var certificateGVK = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
 type Reconciler struct {
-	client client.Client
-	scheme *runtime.Scheme
-	log    logr.Logger
+	client           client.Client
+	scheme           *runtime.Scheme
+	log              logr.Logger
+	controllerConfig *config.ControllerConfig
 }
 
 func NewReconciler(
 	client client.Client,
 	scheme *runtime.Scheme,
 	log logr.Logger,
+	controllerConfig *config.ControllerConfig,
 ) *k8s.PatchingReconciler[korifiv1alpha1.CFDomain, *korifiv1alpha1.CFDomain] {
-	routeReconciler := Reconciler{client: client, scheme: scheme, log: log}
+	routeReconciler := Reconciler{client: client, scheme: scheme, log: log, controllerConfig: controllerConfig}
 	return k8s.NewPatchingReconciler[korifiv1alpha1.CFDomain, *korifiv1alpha1.CFDomain](log, client, &routeReconciler)
 }
 
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) *builder.Builder {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&korifiv1alpha1.CFDomain{})
+		For(&korifiv1alpha1.CFDomain{}).
+		Watches(
+			&gatewayv1beta1.Gateway{},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueGatewayRequests),
+		)
+}
+
+// enqueueGatewayRequests reconciles the domains with their own certificate
+// when the gateway changes, so that their listeners are restored e.g. after
+// the gateway has been upgraded
+func (r *Reconciler) enqueueGatewayRequests(ctx context.Context, o client.Object) []reconcile.Request {
+	if o.GetNamespace() != r.controllerConfig.Networking.GatewayNamespace || o.GetName() != r.controllerConfig.Networking.GatewayName {
+		return []reconcile.Request{}
+	}
+
+	var cfDomains korifiv1alpha1.CFDomainList
+	if err := r.client.List(ctx, &cfDomains); err != nil {
+		return []reconcile.Request{}
+	}
+
+	var requests []reconcile.Request
+	for _, cfDomain := range cfDomains.Items {
+		if tlsSecretName(&cfDomain) == "" {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&cfDomain),
+		})
+	}
+
+	return requests
 }
 
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfdomains,verbs=get;list;watch;patch;create;delete
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfdomains/status,verbs=patch
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfdomains/finalizers,verbs=update
 
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;create;patch
+
 func (r *Reconciler) ReconcileResource(ctx context.Context, cfDomain *korifiv1alpha1.CFDomain) (ctrl.Result, error) {
 	log := logr.FromContextOrDiscard(ctx)
 
@@ -66,9 +122,201 @@ func (r *Reconciler) ReconcileResource(ctx context.Context, cfDomain *korifiv1al
 	cfDomain.Status.ObservedGeneration = cfDomain.Generation
 	log.V(1).Info("set observed generation", "generation", cfDomain.Status.ObservedGeneration)
 
+	err := r.reconcileTLS(ctx, cfDomain)
+	if err != nil {
+		return ctrl.Result{}, k8s.NewNotReadyError().WithCause(err).WithReason("ReconcileTLS")
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// tlsSecretName is the name of the secret holding the certificate of the
+// domain, or empty if the domain uses the default certificate of the gateway
+func tlsSecretName(cfDomain *korifiv1alpha1.CFDomain) string {
+	annotations := cfDomain.GetAnnotations()
+	if secretName := annotations[korifiv1alpha1.TLSSecretNameAnnotationKey]; secretName != "" {
+		return secretName
+	}
+
+	if annotations[korifiv1alpha1.CertManagerClusterIssuerAnnotationKey] != "" {
+		return cfDomain.Name + "-tls"
+	}
+
+	return ""
+}
+
+// ListenerName is the name of the gateway listener serving the certificate
+// of the domain
+func ListenerName(cfDomain *korifiv1alpha1.CFDomain) string {
+	return "https-" + cfDomain.Name
+}
+
+// ApexListenerName is the name of the gateway listener serving the
+// certificate of the domain for routes without a host, as the wildcard
+// hostname of the domain listener does not match the domain itself
+func ApexListenerName(cfDomain *korifiv1alpha1.CFDomain) string {
+	return "https-apex-" + cfDomain.Name
+}
+
+func (r *Reconciler) reconcileTLS(ctx context.Context, cfDomain *korifiv1alpha1.CFDomain) error {
+	secretName := tlsSecretName(cfDomain)
+	if secretName == "" {
+		return r.patchGatewayListeners(ctx, cfDomain, nil)
+	}
+
+	if issuer := cfDomain.Annotations[korifiv1alpha1.CertManagerClusterIssuerAnnotationKey]; issuer != "" {
+		err := r.createOrPatchCertificate(ctx, cfDomain, issuer, secretName)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := r.createOrPatchReferenceGrant(ctx, cfDomain, secretName)
+	if err != nil {
+		return err
+	}
+
+	return r.patchGatewayListeners(ctx, cfDomain, []gatewayv1.Listener{
+		domainListener(cfDomain, ListenerName(cfDomain), "*."+cfDomain.Spec.Name, secretName),
+		domainListener(cfDomain, ApexListenerName(cfDomain), cfDomain.Spec.Name, secretName),
+	})
+}
+
+func domainListener(cfDomain *korifiv1alpha1.CFDomain, name string, hostname string, secretName string) gatewayv1.Listener {
+	return gatewayv1.Listener{
+		Name:     gatewayv1.SectionName(name),
+		Hostname: tools.PtrTo(gatewayv1.Hostname(hostname)),
+		Port:     gatewayv1.PortNumber(443),
+		Protocol: gatewayv1.HTTPSProtocolType,
+		TLS: &gatewayv1.GatewayTLSConfig{
+			Mode: tools.PtrTo(gatewayv1.TLSModeTerminate),
+			CertificateRefs: []gatewayv1.SecretObjectReference{{
+				Group:     tools.PtrTo(gatewayv1.Group("")),
+				Kind:      tools.PtrTo(gatewayv1.Kind("Secret")),
+				Name:      gatewayv1.ObjectName(secretName),
+				Namespace: tools.PtrTo(gatewayv1.Namespace(cfDomain.Namespace)),
+			}},
+		},
+		AllowedRoutes: &gatewayv1.AllowedRoutes{
+			Namespaces: &gatewayv1.RouteNamespaces{
+				From: tools.PtrTo(gatewayv1.NamespacesFromAll),
+			},
+		},
+	}
+}
+
+// createOrPatchCertificate requests the certificate of the domain and its
+// subdomains from a cert-manager ClusterIssuer. cert-manager is not a go
+// dependency of korifi, hence the unstructured object.
+func (r *Reconciler) createOrPatchCertificate(ctx context.Context, cfDomain *korifiv1alpha1.CFDomain, issuer string, secretName string) error {
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certificateGVK)
+	certificate.SetNamespace(cfDomain.Namespace)
+	certificate.SetName(cfDomain.Name + "-tls")
+
+	_, err := controllerutil.CreateOrPatch(ctx, r.client, certificate, func() error {
+		certificate.Object["spec"] = map[string]any{
+			"secretName": secretName,
+			"dnsNames":   []any{"*." + cfDomain.Spec.Name, cfDomain.Spec.Name},
+			"issuerRef": map[string]any{
+				"group": "cert-manager.io",
+				"kind":  "ClusterIssuer",
+				"name":  issuer,
+			},
+		}
+
+		return controllerutil.SetControllerReference(cfDomain, certificate, r.scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or patch certificate: %w", err)
+	}
+
+	return nil
+}
+
+// createOrPatchReferenceGrant allows the gateway to refer to the TLS secret
+// of the domain
+func (r *Reconciler) createOrPatchReferenceGrant(ctx context.Context, cfDomain *korifiv1alpha1.CFDomain, secretName string) error {
+	referenceGrant := &gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cfDomain.Namespace,
+			Name:      cfDomain.Name + "-tls",
+		},
+	}
+
+	_, err := controllerutil.CreateOrPatch(ctx, r.client, referenceGrant, func() error {
+		referenceGrant.Spec = gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{{
+				Group:     gatewayv1beta1.Group("gateway.networking.k8s.io"),
+				Kind:      gatewayv1beta1.Kind("Gateway"),
+				Namespace: gatewayv1beta1.Namespace(r.controllerConfig.Networking.GatewayNamespace),
+			}},
+			To: []gatewayv1beta1.ReferenceGrantTo{{
+				Group: gatewayv1beta1.Group(""),
+				Kind:  gatewayv1beta1.Kind("Secret"),
+				Name:  tools.PtrTo(gatewayv1beta1.ObjectName(secretName)),
+			}},
+		}
+
+		return controllerutil.SetControllerReference(cfDomain, referenceGrant, r.scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or patch reference grant: %w", err)
+	}
+
+	return nil
+}
+
+// patchGatewayListeners replaces the listeners of the domain on the gateway
+// with the given ones, or removes them when none are given. Listeners are
+// replaced in place, so that the order of the gateway listeners is stable.
+func (r *Reconciler) patchGatewayListeners(ctx context.Context, cfDomain *korifiv1alpha1.CFDomain, domainListeners []gatewayv1.Listener) error {
+	gateway := &gatewayv1beta1.Gateway{}
+	err := r.client.Get(ctx, types.NamespacedName{
+		Namespace: r.controllerConfig.Networking.GatewayNamespace,
+		Name:      r.controllerConfig.Networking.GatewayName,
+	}, gateway)
+	if err != nil {
+		if k8serrors.IsNotFound(err) && len(domainListeners) == 0 {
+			return nil
+		}
+		return fmt.Errorf("failed to get gateway: %w", err)
+	}
+
+	listenerNames := []gatewayv1.SectionName{
+		gatewayv1.SectionName(ListenerName(cfDomain)),
+		gatewayv1.SectionName(ApexListenerName(cfDomain)),
+	}
+	listeners := slices.DeleteFunc(slices.Clone(gateway.Spec.Listeners), func(l gatewayv1.Listener) bool {
+		return slices.Contains(listenerNames, l.Name) && !slices.ContainsFunc(domainListeners, func(dl gatewayv1.Listener) bool {
+			return dl.Name == l.Name
+		})
+	})
+	for _, listener := range domainListeners {
+		index := slices.IndexFunc(listeners, func(l gatewayv1.Listener) bool {
+			return l.Name == listener.Name
+		})
+		if index < 0 {
+			listeners = append(listeners, listener)
+			continue
+		}
+		listeners[index] = listener
+	}
+
+	if equality.Semantic.DeepEqual(listeners, gateway.Spec.Listeners) {
+		return nil
+	}
+
+	err = k8s.PatchResource(ctx, r.client, gateway, func() {
+		gateway.Spec.Listeners = listeners
+	})
+	if err != nil {
+		return fmt.Errorf("failed to patch gateway listeners: %w", err)
+	}
+
+	return nil
+}
+
 func (r *Reconciler) finalizeCFDomain(ctx context.Context, cfDomain *korifiv1alpha1.CFDomain) (ctrl.Result, error) {
 	log := logr.FromContextOrDiscard(ctx).WithName("finalizeCFDomain")
 
@@ -76,6 +324,12 @@ func (r *Reconciler) finalizeCFDomain(ctx context.Context, cfDomain *korifiv1alp
 		return ctrl.Result{}, nil
 	}
 
+	err := r.patchGatewayListeners(ctx, cfDomain, nil)
+	if err != nil {
+		log.Info("failed to remove gateway listener", "reason", err)
+		return ctrl.Result{}, err
+	}
+
 	domainRoutes, err := r.listRoutesForDomain(ctx, cfDomain)
 	if err != nil {
 		log.Info("failed to list CFRoutes", "reason", err)
@@ -2,14 +2,21 @@ package domains_test
 
 import (
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/controllers/networking/domains"
+	"code.cloudfoundry.org/korifi/tools/k8s"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 var _ = Describe("CFDomainReconciler Integration Tests", func() {
@@ -45,6 +52,165 @@ var _ = Describe("CFDomainReconciler Integration Tests", func() {
 		}).Should(Succeed())
 	})
 
+	It("does not add a listener to the gateway", func() {
+		Consistently(func(g Gomega) {
+			g.Expect(getGatewayListeners(g)).NotTo(ContainElement(HaveField("Name", gatewayv1.SectionName(domains.ListenerName(cfDomain)))))
+		}, "1s").Should(Succeed())
+	})
+
+	Describe("TLS", func() {
+		var annotations map[string]string
+
+		BeforeEach(func() {
+			annotations = map[string]string{
+				korifiv1alpha1.TLSSecretNameAnnotationKey: "my-cert",
+			}
+		})
+
+		JustBeforeEach(func() {
+			Expect(k8s.PatchResource(ctx, adminClient, cfDomain, func() {
+				cfDomain.Annotations = annotations
+			})).To(Succeed())
+		})
+
+		It("adds a listener serving the domain certificate to the gateway", func() {
+			Eventually(func(g Gomega) {
+				listeners := getGatewayListeners(g)
+				g.Expect(listeners).To(ContainElement(SatisfyAll(
+					HaveField("Name", gatewayv1.SectionName(domains.ListenerName(cfDomain))),
+					HaveField("Hostname", PointTo(Equal(gatewayv1.Hostname("*."+cfDomain.Spec.Name)))),
+					HaveField("Port", gatewayv1.PortNumber(443)),
+					HaveField("Protocol", gatewayv1.HTTPSProtocolType),
+					HaveField("TLS.CertificateRefs", ConsistOf(SatisfyAll(
+						HaveField("Name", gatewayv1.ObjectName("my-cert")),
+						HaveField("Namespace", PointTo(Equal(gatewayv1.Namespace(cfDomain.Namespace)))),
+					))),
+				)))
+			}).Should(Succeed())
+		})
+
+		It("adds a listener serving the domain certificate for the domain itself", func() {
+			Eventually(func(g Gomega) {
+				listeners := getGatewayListeners(g)
+				g.Expect(listeners).To(ContainElement(SatisfyAll(
+					HaveField("Name", gatewayv1.SectionName(domains.ApexListenerName(cfDomain))),
+					HaveField("Hostname", PointTo(Equal(gatewayv1.Hostname(cfDomain.Spec.Name)))),
+					HaveField("Port", gatewayv1.PortNumber(443)),
+					HaveField("Protocol", gatewayv1.HTTPSProtocolType),
+					HaveField("TLS.CertificateRefs", ConsistOf(HaveField("Name", gatewayv1.ObjectName("my-cert")))),
+				)))
+			}).Should(Succeed())
+		})
+
+		It("allows the gateway to refer to the certificate secret", func() {
+			Eventually(func(g Gomega) {
+				referenceGrant := &gatewayv1beta1.ReferenceGrant{}
+				g.Expect(adminClient.Get(ctx, client.ObjectKey{Namespace: cfDomain.Namespace, Name: cfDomain.Name + "-tls"}, referenceGrant)).To(Succeed())
+				g.Expect(referenceGrant.Spec.From).To(ConsistOf(SatisfyAll(
+					HaveField("Kind", gatewayv1beta1.Kind("Gateway")),
+					HaveField("Namespace", gatewayv1beta1.Namespace("korifi-gateway")),
+				)))
+				g.Expect(referenceGrant.Spec.To).To(ConsistOf(SatisfyAll(
+					HaveField("Kind", gatewayv1beta1.Kind("Secret")),
+					HaveField("Name", PointTo(Equal(gatewayv1beta1.ObjectName("my-cert")))),
+				)))
+			}).Should(Succeed())
+		})
+
+		When("the listener is removed from the gateway", func() {
+			JustBeforeEach(func() {
+				gateway := &gatewayv1beta1.Gateway{}
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, client.ObjectKey{Namespace: "korifi-gateway", Name: "korifi"}, gateway)).To(Succeed())
+					g.Expect(gateway.Spec.Listeners).To(ContainElement(HaveField("Name", gatewayv1.SectionName(domains.ListenerName(cfDomain)))))
+				}).Should(Succeed())
+
+				Expect(k8s.PatchResource(ctx, adminClient, gateway, func() {
+					gateway.Spec.Listeners = []gatewayv1.Listener{{
+						Name:     "http-apps",
+						Port:     80,
+						Protocol: gatewayv1.HTTPProtocolType,
+					}}
+				})).To(Succeed())
+			})
+
+			It("restores it", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(getGatewayListeners(g)).To(ContainElement(HaveField("Name", gatewayv1.SectionName(domains.ListenerName(cfDomain)))))
+				}).Should(Succeed())
+			})
+		})
+
+		When("the domain uses a cert-manager cluster issuer", func() {
+			BeforeEach(func() {
+				annotations = map[string]string{
+					korifiv1alpha1.CertManagerClusterIssuerAnnotationKey: "my-issuer",
+				}
+			})
+
+			It("requests a certificate for the domain", func() {
+				Eventually(func(g Gomega) {
+					certificate := &unstructured.Unstructured{}
+					certificate.SetGroupVersionKind(schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"})
+					g.Expect(adminClient.Get(ctx, client.ObjectKey{Namespace: cfDomain.Namespace, Name: cfDomain.Name + "-tls"}, certificate)).To(Succeed())
+					g.Expect(certificate.Object["spec"]).To(MatchKeys(IgnoreExtras, Keys{
+						"secretName": Equal(cfDomain.Name + "-tls"),
+						"dnsNames":   ConsistOf("*."+cfDomain.Spec.Name, cfDomain.Spec.Name),
+						"issuerRef": MatchKeys(IgnoreExtras, Keys{
+							"kind": Equal("ClusterIssuer"),
+							"name": Equal("my-issuer"),
+						}),
+					}))
+				}).Should(Succeed())
+			})
+
+			It("serves the issued certificate", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(getGatewayListeners(g)).To(ContainElement(SatisfyAll(
+						HaveField("Name", gatewayv1.SectionName(domains.ListenerName(cfDomain))),
+						HaveField("TLS.CertificateRefs", ConsistOf(HaveField("Name", gatewayv1.ObjectName(cfDomain.Name+"-tls")))),
+					)))
+				}).Should(Succeed())
+			})
+		})
+
+		When("the annotations are removed", func() {
+			JustBeforeEach(func() {
+				Eventually(func(g Gomega) {
+					g.Expect(getGatewayListeners(g)).To(ContainElement(HaveField("Name", gatewayv1.SectionName(domains.ListenerName(cfDomain)))))
+				}).Should(Succeed())
+
+				Expect(k8s.PatchResource(ctx, adminClient, cfDomain, func() {
+					cfDomain.Annotations = nil
+				})).To(Succeed())
+			})
+
+			It("removes the listeners from the gateway", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(getGatewayListeners(g)).NotTo(ContainElement(HaveField("Name", gatewayv1.SectionName(domains.ListenerName(cfDomain)))))
+					g.Expect(getGatewayListeners(g)).NotTo(ContainElement(HaveField("Name", gatewayv1.SectionName(domains.ApexListenerName(cfDomain)))))
+				}).Should(Succeed())
+			})
+		})
+
+		When("the domain is deleted", func() {
+			JustBeforeEach(func() {
+				Eventually(func(g Gomega) {
+					g.Expect(getGatewayListeners(g)).To(ContainElement(HaveField("Name", gatewayv1.SectionName(domains.ListenerName(cfDomain)))))
+				}).Should(Succeed())
+
+				Expect(adminClient.Delete(ctx, cfDomain)).To(Succeed())
+			})
+
+			It("removes the listeners from the gateway", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(getGatewayListeners(g)).NotTo(ContainElement(HaveField("Name", gatewayv1.SectionName(domains.ListenerName(cfDomain)))))
+					g.Expect(getGatewayListeners(g)).NotTo(ContainElement(HaveField("Name", gatewayv1.SectionName(domains.ApexListenerName(cfDomain)))))
+				}).Should(Succeed())
+			})
+		})
+	})
+
 	Describe("finalization", func() {
 		var (
 			route1Namespace string
@@ -109,3 +275,10 @@ var _ = Describe("CFDomainReconciler Integration Tests", func() {
 		})
 	})
 })
+
+func getGatewayListeners(g Gomega) []gatewayv1.Listener {
+	gateway := &gatewayv1beta1.Gateway{}
+	g.Expect(adminClient.Get(ctx, client.ObjectKey{Namespace: "korifi-gateway", Name: "korifi"}, gateway)).To(Succeed())
+
+	return gateway.Spec.Listeners
+}
@@ -7,18 +7,23 @@ import (
 	"time"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/config"
 	"code.cloudfoundry.org/korifi/controllers/controllers/networking/domains"
 	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
 	"code.cloudfoundry.org/korifi/tests/helpers"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -47,6 +52,8 @@ var _ = BeforeSuite(func() {
 	testEnv = &envtest.Environment{
 		CRDDirectoryPaths: []string{
 			filepath.Join("..", "..", "..", "..", "helm", "korifi", "controllers", "crds"),
+			filepath.Join("..", "..", "..", "..", "tests", "vendor", "gateway-api"),
+			filepath.Join("..", "..", "..", "..", "tests", "vendor", "cert-manager", "cert-manager.crds.yaml"),
 		},
 		ErrorIfCRDPathMissing: true,
 	}
@@ -55,6 +62,7 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 
 	Expect(korifiv1alpha1.AddToScheme(scheme.Scheme)).To(Succeed())
+	Expect(gatewayv1beta1.Install(scheme.Scheme)).To(Succeed())
 
 	k8sManager := helpers.NewK8sManager(testEnv, filepath.Join("helm", "korifi", "controllers", "role.yaml"))
 	Expect(shared.SetupIndexWithManager(k8sManager)).To(Succeed())
@@ -65,10 +73,36 @@ var _ = BeforeSuite(func() {
 		k8sManager.GetClient(),
 		k8sManager.GetScheme(),
 		ctrl.Log.WithName("controllers").WithName("CFDomain"),
+		&config.ControllerConfig{
+			Networking: config.Networking{
+				GatewayName:      "korifi",
+				GatewayNamespace: "korifi-gateway",
+			},
+		},
 	).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
 	stopManager = helpers.StartK8sManager(k8sManager)
+
+	Expect(adminClient.Create(context.Background(), &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "korifi-gateway",
+		},
+	})).To(Succeed())
+	Expect(adminClient.Create(context.Background(), &gatewayv1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "korifi-gateway",
+			Name:      "korifi",
+		},
+		Spec: gatewayv1beta1.GatewaySpec{
+			GatewayClassName: "korifi",
+			Listeners: []gatewayv1beta1.Listener{{
+				Name:     "http-apps",
+				Port:     80,
+				Protocol: gatewayv1.HTTPProtocolType,
+			}},
+		},
+	})).To(Succeed())
 })
 
 var _ = BeforeEach(func() {
@@ -19,16 +19,21 @@ package routes
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
+	"code.cloudfoundry.org/korifi/controllers/activator"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/controllers/config"
 	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
+	"code.cloudfoundry.org/korifi/controllers/metrics"
 	"code.cloudfoundry.org/korifi/tools"
 	"code.cloudfoundry.org/korifi/tools/k8s"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -40,14 +45,22 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
+const (
+	ExternalDNSHostnameAnnotationKey = "external-dns.alpha.kubernetes.io/hostname"
+	ExternalDNSTTLAnnotationKey      = "external-dns.alpha.kubernetes.io/ttl"
+	ExternalDNSTargetAnnotationKey   = "external-dns.alpha.kubernetes.io/target"
+)
+
 type Reconciler struct {
-	client           client.Client
-	scheme           *runtime.Scheme
-	log              logr.Logger
-	controllerConfig *config.ControllerConfig
+	client              client.Client
+	scheme              *runtime.Scheme
+	log                 logr.Logger
+	controllerConfig    *config.ControllerConfig
+	activatorSigningKey []byte
 }
 
 func NewReconciler(
@@ -55,8 +68,9 @@ func NewReconciler(
 	scheme *runtime.Scheme,
 	log logr.Logger,
 	controllerConfig *config.ControllerConfig,
+	activatorSigningKey []byte,
 ) *k8s.PatchingReconciler[korifiv1alpha1.CFRoute, *korifiv1alpha1.CFRoute] {
-	routeReconciler := Reconciler{client: client, scheme: scheme, log: log, controllerConfig: controllerConfig}
+	routeReconciler := Reconciler{client: client, scheme: scheme, log: log, controllerConfig: controllerConfig, activatorSigningKey: activatorSigningKey}
 	return k8s.NewPatchingReconciler[korifiv1alpha1.CFRoute, *korifiv1alpha1.CFRoute](log, client, &routeReconciler)
 }
 
@@ -66,23 +80,69 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) *builder.Builder {
 		Watches(
 			&korifiv1alpha1.CFApp{},
 			handler.EnqueueRequestsFromMapFunc(r.enqueueCFAppRequests),
+		).
+		Watches(
+			&korifiv1alpha1.CFProcess{},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueCFProcessRequests),
+		).
+		Watches(
+			&korifiv1alpha1.CFDomain{},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueCFDomainRequests),
 		)
 }
 
-func (r *Reconciler) enqueueCFAppRequests(ctx context.Context, o client.Object) []reconcile.Request {
+// enqueueCFDomainRequests reconciles the routes of the domain when it opts
+// into or out of ExternalDNS
+func (r *Reconciler) enqueueCFDomainRequests(ctx context.Context, o client.Object) []reconcile.Request {
+	var domainRoutes korifiv1alpha1.CFRouteList
+	err := r.client.List(
+		ctx,
+		&domainRoutes,
+		client.MatchingFields{shared.IndexRouteDomainQualifiedName: o.GetNamespace() + "." + o.GetName()},
+	)
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
 	var requests []reconcile.Request
+	for _, domainRoute := range domainRoutes.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&domainRoute),
+		})
+	}
+
+	return requests
+}
 
+func (r *Reconciler) enqueueCFAppRequests(ctx context.Context, o client.Object) []reconcile.Request {
 	cfApp, ok := o.(*korifiv1alpha1.CFApp)
 	if !ok {
 		return []reconcile.Request{}
 	}
 
+	return r.routeRequestsForApp(ctx, cfApp.Namespace, cfApp.Name)
+}
+
+// enqueueCFProcessRequests reconciles the routes of the app when a process
+// opts into or out of idling
+func (r *Reconciler) enqueueCFProcessRequests(ctx context.Context, o client.Object) []reconcile.Request {
+	cfProcess, ok := o.(*korifiv1alpha1.CFProcess)
+	if !ok || !r.controllerConfig.Idling.Enabled {
+		return []reconcile.Request{}
+	}
+
+	return r.routeRequestsForApp(ctx, cfProcess.Namespace, cfProcess.Spec.AppRef.Name)
+}
+
+func (r *Reconciler) routeRequestsForApp(ctx context.Context, appNamespace, appGUID string) []reconcile.Request {
+	var requests []reconcile.Request
+
 	var appRoutes korifiv1alpha1.CFRouteList
 	err := r.client.List(
 		ctx,
 		&appRoutes,
-		client.InNamespace(cfApp.Namespace),
-		client.MatchingFields{shared.IndexRouteDestinationAppName: cfApp.Name},
+		client.InNamespace(appNamespace),
+		client.MatchingFields{shared.IndexRouteDestinationAppName: appGUID},
 	)
 	if err != nil {
 		return []reconcile.Request{}
@@ -106,6 +166,8 @@ func (r *Reconciler) enqueueCFAppRequests(ctx context.Context, o client.Object)
 
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes/status,verbs=get
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=tcproutes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=referencegrants,verbs=get;list;watch;create;update;patch;delete
 
 //+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 
@@ -135,14 +197,30 @@ func (r *Reconciler) ReconcileResource(ctx context.Context, cfRoute *korifiv1alp
 		return ctrl.Result{}, k8s.NewNotReadyError().WithCause(err).WithReason("CreatePatchServices")
 	}
 
-	err = r.reconcileHTTPRoute(ctx, cfRoute, cfDomain)
-	if err != nil {
-		return ctrl.Result{}, k8s.NewNotReadyError().WithCause(err).WithReason("ReconcileHTTPRoute")
-	}
+	if cfRoute.Spec.Protocol == korifiv1alpha1.ProtocolTCP {
+		if !slices.Contains(r.controllerConfig.Networking.TCPPorts, cfRoute.Spec.Port) {
+			return ctrl.Result{}, k8s.NewNotReadyError().
+				WithReason("InvalidTCPPort").
+				WithMessage(fmt.Sprintf("Port %d is not one of the tcp ports of the gateway", cfRoute.Spec.Port))
+		}
 
-	fqdn := buildFQDN(cfRoute, cfDomain)
-	cfRoute.Status.FQDN = fqdn
-	cfRoute.Status.URI = fqdn + cfRoute.Spec.Path
+		err = r.reconcileTCPRoute(ctx, cfRoute, cfDomain)
+		if err != nil {
+			return ctrl.Result{}, k8s.NewNotReadyError().WithCause(err).WithReason("ReconcileTCPRoute")
+		}
+
+		cfRoute.Status.FQDN = cfDomain.Spec.Name
+		cfRoute.Status.URI = fmt.Sprintf("%s:%d", cfDomain.Spec.Name, cfRoute.Spec.Port)
+	} else {
+		err = r.reconcileHTTPRoute(ctx, cfRoute, cfDomain)
+		if err != nil {
+			return ctrl.Result{}, k8s.NewNotReadyError().WithCause(err).WithReason("ReconcileHTTPRoute")
+		}
+
+		fqdn := buildFQDN(cfRoute, cfDomain)
+		cfRoute.Status.FQDN = fqdn
+		cfRoute.Status.URI = fqdn + cfRoute.Spec.Path
+	}
 
 	effectiveDestinations, err := r.buildEffectiveDestinations(ctx, cfRoute)
 	if err != nil {
@@ -157,6 +235,8 @@ func (r *Reconciler) ReconcileResource(ctx context.Context, cfRoute *korifiv1alp
 		return ctrl.Result{}, cleanupErr
 	}
 
+	metrics.ObserveRouteProgrammed(cfRoute)
+
 	return ctrl.Result{}, nil
 }
 
@@ -234,6 +314,9 @@ func (r *Reconciler) buildEffectiveDestinations(ctx context.Context, cfRoute *ko
 
 		if effectiveDest.Protocol == nil {
 			effectiveDest.Protocol = tools.PtrTo("http1")
+			if cfRoute.Spec.Protocol == korifiv1alpha1.ProtocolTCP {
+				effectiveDest.Protocol = tools.PtrTo("tcp")
+			}
 		}
 
 		if effectiveDest.Port == nil {
@@ -308,7 +391,22 @@ func (r *Reconciler) reconcileHTTPRoute(ctx context.Context, cfRoute *korifiv1al
 		return nil
 	}
 
+	backendRefs, err := r.toBackendRefs(ctx, cfRoute)
+	if err != nil {
+		log.Info("failed to build HTTPRoute backend refs", "reason", err)
+		return err
+	}
+
+	rule, err := toHTTPRouteRule(cfRoute)
+	if err != nil {
+		log.Info("invalid route options", "reason", err)
+		return err
+	}
+	rule.BackendRefs = backendRefs
+
 	result, err := controllerutil.CreateOrPatch(ctx, r.client, httpRoute, func() error {
+		setExternalDNSAnnotations(httpRoute, cfDomain, "")
+
 		httpRoute.Spec.ParentRefs = []gatewayv1beta1.ParentReference{{
 			Group:     tools.PtrTo(gatewayv1beta1.Group("gateway.networking.k8s.io")),
 			Kind:      tools.PtrTo(gatewayv1beta1.Kind("Gateway")),
@@ -320,9 +418,7 @@ func (r *Reconciler) reconcileHTTPRoute(ctx context.Context, cfRoute *korifiv1al
 			gatewayv1beta1.Hostname(fqdn),
 		}
 
-		httpRoute.Spec.Rules = []gatewayv1beta1.HTTPRouteRule{{
-			BackendRefs: toBackendRefs(cfRoute.Status.Destinations),
-		}}
+		httpRoute.Spec.Rules = []gatewayv1beta1.HTTPRouteRule{rule}
 		if cfRoute.Spec.Path != "" {
 			httpRoute.Spec.Rules[0].Matches = []gatewayv1beta1.HTTPRouteMatch{{
 				Path: &gatewayv1beta1.HTTPPathMatch{
@@ -343,6 +439,146 @@ func (r *Reconciler) reconcileHTTPRoute(ctx context.Context, cfRoute *korifiv1al
 	return nil
 }
 
+// toHTTPRouteRule translates the timeout, retry and session affinity
+// annotations of the route into the options of its HTTPRoute rule
+func toHTTPRouteRule(cfRoute *korifiv1alpha1.CFRoute) (gatewayv1beta1.HTTPRouteRule, error) {
+	rule := gatewayv1beta1.HTTPRouteRule{}
+
+	if timeout, ok := cfRoute.Annotations[korifiv1alpha1.RouteRequestTimeoutAnnotationKey]; ok {
+		if err := tools.ValidateGatewayDuration(timeout); err != nil {
+			return rule, fmt.Errorf("invalid %s: %w", korifiv1alpha1.RouteRequestTimeoutAnnotationKey, err)
+		}
+		rule.Timeouts = &gatewayv1.HTTPRouteTimeouts{
+			Request: tools.PtrTo(gatewayv1.Duration(timeout)),
+		}
+	}
+
+	if attempts, ok := cfRoute.Annotations[korifiv1alpha1.RouteRetryAttemptsAnnotationKey]; ok {
+		retryAttempts, err := strconv.Atoi(attempts)
+		if err != nil || retryAttempts < 0 {
+			return rule, fmt.Errorf("invalid %s %q: must be a non-negative integer", korifiv1alpha1.RouteRetryAttemptsAnnotationKey, attempts)
+		}
+		rule.Retry = &gatewayv1.HTTPRouteRetry{
+			Attempts: tools.PtrTo(retryAttempts),
+		}
+	}
+
+	if cfRoute.Annotations[korifiv1alpha1.RouteSessionAffinityAnnotationKey] == "true" {
+		rule.SessionPersistence = &gatewayv1.SessionPersistence{
+			SessionName: tools.PtrTo(korifiv1alpha1.RouteSessionCookieName),
+			Type:        tools.PtrTo(gatewayv1.CookieBasedSessionPersistence),
+		}
+
+		if idleTimeout, ok := cfRoute.Annotations[korifiv1alpha1.RouteSessionIdleTimeoutAnnotationKey]; ok {
+			if err := tools.ValidateGatewayDuration(idleTimeout); err != nil {
+				return rule, fmt.Errorf("invalid %s: %w", korifiv1alpha1.RouteSessionIdleTimeoutAnnotationKey, err)
+			}
+			rule.SessionPersistence.IdleTimeout = tools.PtrTo(gatewayv1.Duration(idleTimeout))
+		}
+	}
+
+	return rule, nil
+}
+
+// reconcileTCPRoute forwards the connections to the tcp listener of the
+// gateway on the route port to the route destinations. Unlike http routes,
+// tcp routes do not go through the activator, as it only proxies http.
+func (r *Reconciler) reconcileTCPRoute(ctx context.Context, cfRoute *korifiv1alpha1.CFRoute, cfDomain *korifiv1alpha1.CFDomain) error {
+	log := logr.FromContextOrDiscard(ctx).WithName("createOrPatchTCPRoute").WithValues("port", cfRoute.Spec.Port)
+
+	tcpRoute := &gatewayv1alpha2.TCPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cfRoute.Name,
+			Namespace: cfRoute.Namespace,
+		},
+	}
+
+	if len(cfRoute.Status.Destinations) == 0 {
+		err := r.client.Delete(ctx, tcpRoute)
+		if client.IgnoreNotFound(err) != nil {
+			log.Info("failed to delete existing TCPRoute", "reason", err)
+			return err
+		}
+		return nil
+	}
+
+	result, err := controllerutil.CreateOrPatch(ctx, r.client, tcpRoute, func() error {
+		// unlike HTTPRoutes, TCPRoutes have no hostnames ExternalDNS could use
+		setExternalDNSAnnotations(tcpRoute, cfDomain, cfDomain.Spec.Name)
+
+		tcpRoute.Spec.ParentRefs = []gatewayv1alpha2.ParentReference{{
+			Group:       tools.PtrTo(gatewayv1alpha2.Group("gateway.networking.k8s.io")),
+			Kind:        tools.PtrTo(gatewayv1alpha2.Kind("Gateway")),
+			Namespace:   tools.PtrTo(gatewayv1alpha2.Namespace(r.controllerConfig.Networking.GatewayNamespace)),
+			Name:        gatewayv1alpha2.ObjectName(r.controllerConfig.Networking.GatewayName),
+			SectionName: tools.PtrTo(gatewayv1alpha2.SectionName(TCPListenerName(cfRoute.Spec.Port))),
+		}}
+
+		backendRefs := []gatewayv1alpha2.BackendRef{}
+		for _, destination := range cfRoute.Status.Destinations {
+			backendRefs = append(backendRefs, gatewayv1alpha2.BackendRef{
+				BackendObjectReference: gatewayv1alpha2.BackendObjectReference{
+					Kind: tools.PtrTo(gatewayv1alpha2.Kind("Service")),
+					Name: gatewayv1alpha2.ObjectName(generateServiceName(destination)),
+					Port: tools.PtrTo(gatewayv1alpha2.PortNumber(*destination.Port)),
+				},
+			})
+		}
+		tcpRoute.Spec.Rules = []gatewayv1alpha2.TCPRouteRule{{
+			BackendRefs: backendRefs,
+		}}
+
+		return controllerutil.SetControllerReference(cfRoute, tcpRoute, r.scheme)
+	})
+	if err != nil {
+		log.Info("failed to create/patch TCPRoute", "reason", err)
+		return err
+	}
+
+	log.V(1).Info("TCPRoute reconciled", "operation", result)
+	return nil
+}
+
+// setExternalDNSAnnotations sets the annotations ExternalDNS reads on the
+// gateway route when the domain opts into ExternalDNS, or removes them
+// otherwise. The hostname annotation is only set when not empty.
+func setExternalDNSAnnotations(route client.Object, cfDomain *korifiv1alpha1.CFDomain, hostname string) {
+	annotations := route.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	for _, key := range []string{
+		korifiv1alpha1.ExternalDNSAnnotationKey,
+		ExternalDNSHostnameAnnotationKey,
+		ExternalDNSTTLAnnotationKey,
+		ExternalDNSTargetAnnotationKey,
+	} {
+		delete(annotations, key)
+	}
+
+	if cfDomain.Annotations[korifiv1alpha1.ExternalDNSAnnotationKey] == "true" {
+		annotations[korifiv1alpha1.ExternalDNSAnnotationKey] = "true"
+		if hostname != "" {
+			annotations[ExternalDNSHostnameAnnotationKey] = hostname
+		}
+		if ttl := cfDomain.Annotations[korifiv1alpha1.ExternalDNSTTLAnnotationKey]; ttl != "" {
+			annotations[ExternalDNSTTLAnnotationKey] = ttl
+		}
+		if target := cfDomain.Annotations[korifiv1alpha1.ExternalDNSTargetAnnotationKey]; target != "" {
+			annotations[ExternalDNSTargetAnnotationKey] = target
+		}
+	}
+
+	route.SetAnnotations(annotations)
+}
+
+// TCPListenerName is the name of the gateway listener tcp routes on the port
+// attach to
+func TCPListenerName(port int32) string {
+	return fmt.Sprintf("tcp-%d", port)
+}
+
 func (r *Reconciler) deleteOrphanedServices(ctx context.Context, cfRoute *korifiv1alpha1.CFRoute) error {
 	log := logr.FromContextOrDiscard(ctx).WithName("deleteOrphanedServices")
 
@@ -406,10 +642,27 @@ func buildFQDN(cfRoute *korifiv1alpha1.CFRoute, cfDomain *korifiv1alpha1.CFDomai
 	return fmt.Sprintf("%s.%s", strings.ToLower(cfRoute.Spec.Host), cfDomain.Spec.Name)
 }
 
-func toBackendRefs(destinations []korifiv1alpha1.Destination) []gatewayv1beta1.HTTPBackendRef {
+// toBackendRefs sends the requests to destinations whose process idles to the
+// activator, which wakes the process up after it has been scaled to zero
+func (r *Reconciler) toBackendRefs(ctx context.Context, cfRoute *korifiv1alpha1.CFRoute) ([]gatewayv1beta1.HTTPBackendRef, error) {
 	backendRefs := []gatewayv1beta1.HTTPBackendRef{}
 
-	for _, destination := range destinations {
+	for _, destination := range cfRoute.Status.Destinations {
+		idlingProcess, err := r.getIdlingProcess(ctx, cfRoute.Namespace, destination)
+		if err != nil {
+			return nil, err
+		}
+
+		if idlingProcess != nil {
+			err = r.createOrPatchActivatorReferenceGrant(ctx, cfRoute.Namespace)
+			if err != nil {
+				return nil, err
+			}
+
+			backendRefs = append(backendRefs, r.toActivatorBackendRef(cfRoute, destination))
+			continue
+		}
+
 		backendRefs = append(backendRefs, gatewayv1beta1.HTTPBackendRef{
 			BackendRef: gatewayv1beta1.BackendRef{
 				BackendObjectReference: gatewayv1beta1.BackendObjectReference{
@@ -421,5 +674,98 @@ func toBackendRefs(destinations []korifiv1alpha1.Destination) []gatewayv1beta1.H
 		})
 	}
 
-	return backendRefs
+	return backendRefs, nil
+}
+
+// getIdlingProcess returns the process of the destination if it opted into
+// idling, nil otherwise
+func (r *Reconciler) getIdlingProcess(ctx context.Context, namespace string, destination korifiv1alpha1.Destination) (*korifiv1alpha1.CFProcess, error) {
+	if !r.controllerConfig.Idling.Enabled {
+		return nil, nil
+	}
+
+	cfProcess := &korifiv1alpha1.CFProcess{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+		},
+		Spec: korifiv1alpha1.CFProcessSpec{
+			ProcessType: destination.ProcessType,
+		},
+	}
+	cfProcess.SetStableName(destination.AppRef.Name)
+
+	err := r.client.Get(ctx, client.ObjectKeyFromObject(cfProcess), cfProcess)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get process for destination %q: %w", destination.GUID, err)
+	}
+
+	if _, ok := cfProcess.Annotations[korifiv1alpha1.IdleTimeoutAnnotationKey]; !ok {
+		return nil, nil
+	}
+
+	return cfProcess, nil
+}
+
+func (r *Reconciler) toActivatorBackendRef(cfRoute *korifiv1alpha1.CFRoute, destination korifiv1alpha1.Destination) gatewayv1beta1.HTTPBackendRef {
+	idling := r.controllerConfig.Idling
+	activatorDestination := fmt.Sprintf("%s/%s/%s", cfRoute.Namespace, cfRoute.Name, destination.GUID)
+
+	return gatewayv1beta1.HTTPBackendRef{
+		BackendRef: gatewayv1beta1.BackendRef{
+			BackendObjectReference: gatewayv1beta1.BackendObjectReference{
+				Kind:      tools.PtrTo(gatewayv1beta1.Kind("Service")),
+				Namespace: tools.PtrTo(gatewayv1beta1.Namespace(idling.ActivatorServiceNamespace)),
+				Name:      gatewayv1beta1.ObjectName(idling.ActivatorServiceName),
+				Port:      tools.PtrTo(gatewayv1beta1.PortNumber(idling.ActivatorPort)),
+			},
+		},
+		Filters: []gatewayv1beta1.HTTPRouteFilter{{
+			Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+			RequestHeaderModifier: &gatewayv1beta1.HTTPHeaderFilter{
+				Set: []gatewayv1beta1.HTTPHeader{
+					{Name: activator.DestinationHeader, Value: activatorDestination},
+					{Name: activator.SignatureHeader, Value: activator.Sign(r.activatorSigningKey, activatorDestination)},
+				},
+			},
+		}},
+	}
+}
+
+// createOrPatchActivatorReferenceGrant allows the HTTPRoutes in the space
+// namespace to refer to the activator service in its namespace. The grant is
+// kept for other routes in the space when the route is deleted.
+func (r *Reconciler) createOrPatchActivatorReferenceGrant(ctx context.Context, namespace string) error {
+	idling := r.controllerConfig.Idling
+
+	referenceGrant := &gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: idling.ActivatorServiceNamespace,
+			Name:      "activator-" + namespace,
+		},
+	}
+
+	_, err := controllerutil.CreateOrPatch(ctx, r.client, referenceGrant, func() error {
+		referenceGrant.Spec = gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{{
+				Group:     gatewayv1beta1.Group("gateway.networking.k8s.io"),
+				Kind:      gatewayv1beta1.Kind("HTTPRoute"),
+				Namespace: gatewayv1beta1.Namespace(namespace),
+			}},
+			To: []gatewayv1beta1.ReferenceGrantTo{{
+				Group: gatewayv1beta1.Group(""),
+				Kind:  gatewayv1beta1.Kind("Service"),
+				Name:  tools.PtrTo(gatewayv1beta1.ObjectName(idling.ActivatorServiceName)),
+			}},
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or patch activator reference grant: %w", err)
+	}
+
+	return nil
 }
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"strings"
 
+	"code.cloudfoundry.org/korifi/controllers/activator"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/controllers/networking/routes"
 	"code.cloudfoundry.org/korifi/tools"
 	"code.cloudfoundry.org/korifi/tools/k8s"
 
@@ -19,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
@@ -176,6 +179,166 @@ var _ = Describe("CFRouteReconciler Integration Tests", func() {
 			}))
 		})
 
+		It("does not set the external-dns annotations on the HTTPRoute", func() {
+			Expect(getHTTPRoute().Annotations).NotTo(HaveKey(korifiv1alpha1.ExternalDNSAnnotationKey))
+		})
+
+		When("the domain opts into external-dns", func() {
+			BeforeEach(func() {
+				Expect(k8s.PatchResource(ctx, adminClient, cfDomain, func() {
+					cfDomain.Annotations = map[string]string{
+						korifiv1alpha1.ExternalDNSAnnotationKey:    "true",
+						korifiv1alpha1.ExternalDNSTTLAnnotationKey: "60",
+					}
+				})).To(Succeed())
+			})
+
+			It("sets the external-dns annotations on the HTTPRoute", func() {
+				Eventually(func(g Gomega) {
+					httpRoute := getHTTPRoute()
+					g.Expect(httpRoute.Annotations).To(HaveKeyWithValue(korifiv1alpha1.ExternalDNSAnnotationKey, "true"))
+					g.Expect(httpRoute.Annotations).To(HaveKeyWithValue(routes.ExternalDNSTTLAnnotationKey, "60"))
+					g.Expect(httpRoute.Annotations).NotTo(HaveKey(routes.ExternalDNSTargetAnnotationKey))
+				}).Should(Succeed())
+			})
+
+			When("the domain opts out of external-dns", func() {
+				JustBeforeEach(func() {
+					Eventually(func(g Gomega) {
+						g.Expect(getHTTPRoute().Annotations).To(HaveKey(korifiv1alpha1.ExternalDNSAnnotationKey))
+					}).Should(Succeed())
+
+					Expect(k8s.PatchResource(ctx, adminClient, cfDomain, func() {
+						cfDomain.Annotations = nil
+					})).To(Succeed())
+				})
+
+				It("removes the external-dns annotations from the HTTPRoute", func() {
+					Eventually(func(g Gomega) {
+						httpRoute := getHTTPRoute()
+						g.Expect(httpRoute.Annotations).NotTo(HaveKey(korifiv1alpha1.ExternalDNSAnnotationKey))
+						g.Expect(httpRoute.Annotations).NotTo(HaveKey(routes.ExternalDNSTTLAnnotationKey))
+					}).Should(Succeed())
+				})
+			})
+		})
+
+		When("the route sets timeouts, retries and session affinity", func() {
+			BeforeEach(func() {
+				cfRoute.Annotations = map[string]string{
+					korifiv1alpha1.RouteRequestTimeoutAnnotationKey:     "1m30s",
+					korifiv1alpha1.RouteRetryAttemptsAnnotationKey:      "3",
+					korifiv1alpha1.RouteSessionAffinityAnnotationKey:    "true",
+					korifiv1alpha1.RouteSessionIdleTimeoutAnnotationKey: "30m",
+				}
+			})
+
+			It("configures the HTTPRoute rule", func() {
+				httpRoute := getHTTPRoute()
+				Expect(httpRoute.Spec.Rules).To(HaveLen(1))
+
+				rule := httpRoute.Spec.Rules[0]
+				Expect(rule.Timeouts).To(PointTo(HaveField("Request", PointTo(Equal(gatewayv1.Duration("1m30s"))))))
+				Expect(rule.Retry).To(PointTo(HaveField("Attempts", PointTo(Equal(3)))))
+				Expect(rule.SessionPersistence).To(PointTo(SatisfyAll(
+					HaveField("SessionName", PointTo(Equal("__VCAP_ID__"))),
+					HaveField("Type", PointTo(Equal(gatewayv1.CookieBasedSessionPersistence))),
+					HaveField("IdleTimeout", PointTo(Equal(gatewayv1.Duration("30m")))),
+				)))
+			})
+
+			When("the request timeout is invalid", func() {
+				BeforeEach(func() {
+					cfRoute.Annotations[korifiv1alpha1.RouteRequestTimeoutAnnotationKey] = "forever"
+				})
+
+				It("sets the ready condition to false", func() {
+					Eventually(func(g Gomega) {
+						g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfRoute), cfRoute)).To(Succeed())
+						g.Expect(cfRoute.Status.Conditions).To(ContainElement(SatisfyAll(
+							HaveField("Type", korifiv1alpha1.StatusConditionReady),
+							HaveField("Status", metav1.ConditionFalse),
+							HaveField("Reason", "ReconcileHTTPRoute"),
+						)))
+					}).Should(Succeed())
+				})
+			})
+		})
+
+		When("the destination process opted into idling", func() {
+			BeforeEach(func() {
+				cfProcess := &korifiv1alpha1.CFProcess{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: ns.Name,
+						Annotations: map[string]string{
+							korifiv1alpha1.IdleTimeoutAnnotationKey: "30",
+						},
+					},
+					Spec: korifiv1alpha1.CFProcessSpec{
+						AppRef:      corev1.LocalObjectReference{Name: cfApp.Name},
+						ProcessType: "web",
+						MemoryMB:    1024,
+						DiskQuotaMB: 100,
+					},
+				}
+				cfProcess.SetStableName(cfApp.Name)
+				Expect(adminClient.Create(ctx, cfProcess)).To(Succeed())
+			})
+
+			activatorDestination := func() string {
+				return fmt.Sprintf("%s/%s/%s", ns.Name, cfRoute.Name, cfRoute.Spec.Destinations[0].GUID)
+			}
+
+			It("sends the requests through the activator", func() {
+				Eventually(func(g Gomega) {
+					httpRoute := getHTTPRoute()
+					g.Expect(httpRoute.Spec.Rules).To(HaveLen(1))
+					g.Expect(httpRoute.Spec.Rules[0].BackendRefs).To(HaveLen(1))
+
+					backendRef := httpRoute.Spec.Rules[0].BackendRefs[0]
+					g.Expect(backendRef.BackendRef.BackendObjectReference).To(Equal(gatewayv1beta1.BackendObjectReference{
+						Group:     tools.PtrTo(gatewayv1beta1.Group("")),
+						Kind:      tools.PtrTo(gatewayv1beta1.Kind("Service")),
+						Namespace: tools.PtrTo(gatewayv1beta1.Namespace(activatorNamespace)),
+						Name:      gatewayv1beta1.ObjectName("korifi-activator"),
+						Port:      tools.PtrTo(gatewayv1beta1.PortNumber(8000)),
+					}))
+					g.Expect(backendRef.Filters).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+						"Type": Equal(gatewayv1.HTTPRouteFilterRequestHeaderModifier),
+						"RequestHeaderModifier": PointTo(MatchFields(IgnoreExtras, Fields{
+							"Set": ConsistOf(
+								gatewayv1beta1.HTTPHeader{Name: "X-Korifi-Destination", Value: activatorDestination()},
+								gatewayv1beta1.HTTPHeader{Name: "X-Korifi-Signature", Value: activator.Sign([]byte("the-signing-key"), activatorDestination())},
+							),
+						})),
+					})))
+				}).Should(Succeed())
+			})
+
+			It("allows the HTTPRoute to refer to the activator service", func() {
+				Eventually(func(g Gomega) {
+					referenceGrant := &gatewayv1beta1.ReferenceGrant{}
+					g.Expect(adminClient.Get(ctx, types.NamespacedName{Namespace: activatorNamespace, Name: "activator-" + ns.Name}, referenceGrant)).To(Succeed())
+					g.Expect(referenceGrant.Spec.From).To(ConsistOf(gatewayv1beta1.ReferenceGrantFrom{
+						Group:     "gateway.networking.k8s.io",
+						Kind:      "HTTPRoute",
+						Namespace: gatewayv1beta1.Namespace(ns.Name),
+					}))
+					g.Expect(referenceGrant.Spec.To).To(ConsistOf(gatewayv1beta1.ReferenceGrantTo{
+						Group: "",
+						Kind:  "Service",
+						Name:  tools.PtrTo(gatewayv1beta1.ObjectName("korifi-activator")),
+					}))
+				}).Should(Succeed())
+			})
+
+			It("still creates a service for the destination", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, types.NamespacedName{Namespace: ns.Name, Name: "s-" + cfRoute.Spec.Destinations[0].GUID}, &corev1.Service{})).To(Succeed())
+				}).Should(Succeed())
+			})
+		})
+
 		When("the route's path contains upper case characters", func() {
 			BeforeEach(func() {
 				cfRoute.Spec.Path = "/Hello"
@@ -233,6 +396,27 @@ var _ = Describe("CFRouteReconciler Integration Tests", func() {
 			}).Should(Succeed())
 		})
 
+		When("the destination targets another process on a custom port", func() {
+			BeforeEach(func() {
+				cfRoute.Spec.Destinations[0].ProcessType = "worker"
+				cfRoute.Spec.Destinations[0].Port = tools.PtrTo[int32](5000)
+			})
+
+			It("sends the traffic to that port of the process", func() {
+				serviceName := fmt.Sprintf("s-%s", cfRoute.Spec.Destinations[0].GUID)
+				Eventually(func(g Gomega) {
+					var svc corev1.Service
+					g.Expect(adminClient.Get(ctx, types.NamespacedName{Name: serviceName, Namespace: ns.Name}, &svc)).To(Succeed())
+					g.Expect(svc.Spec.Selector).To(HaveKeyWithValue("korifi.cloudfoundry.org/process-type", "worker"))
+					g.Expect(svc.Spec.Ports).To(ConsistOf(HaveField("Port", int32(5000))))
+				}).Should(Succeed())
+
+				Expect(getHTTPRoute().Spec.Rules[0].BackendRefs).To(ConsistOf(
+					HaveField("BackendRef.BackendObjectReference.Port", PointTo(Equal(gatewayv1beta1.PortNumber(5000)))),
+				))
+			})
+		})
+
 		It("sets effective destinations to the cfroute status", func() {
 			Eventually(func(g Gomega) {
 				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfRoute), cfRoute)).To(Succeed())
@@ -416,6 +600,115 @@ var _ = Describe("CFRouteReconciler Integration Tests", func() {
 		})
 	})
 
+	When("the CFRoute is a tcp route", func() {
+		var cfApp *korifiv1alpha1.CFApp
+
+		BeforeEach(func() {
+			cfApp = &korifiv1alpha1.CFApp{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: ns.Name,
+					Name:      uuid.NewString(),
+				},
+				Spec: korifiv1alpha1.CFAppSpec{
+					Lifecycle: korifiv1alpha1.Lifecycle{
+						Type: "buildpack",
+					},
+					DesiredState: "STARTED",
+					DisplayName:  uuid.NewString(),
+				},
+			}
+			Expect(adminClient.Create(ctx, cfApp)).To(Succeed())
+
+			cfRoute.Spec.Protocol = korifiv1alpha1.ProtocolTCP
+			cfRoute.Spec.Port = 1024
+			cfRoute.Spec.Host = ""
+			cfRoute.Spec.Path = ""
+			cfRoute.Spec.Destinations = []korifiv1alpha1.Destination{{
+				GUID:        uuid.NewString(),
+				AppRef:      corev1.LocalObjectReference{Name: cfApp.Name},
+				ProcessType: "web",
+				Port:        tools.PtrTo[int32](9000),
+			}}
+		})
+
+		It("creates a TCPRoute attached to the tcp listener of the gateway on the route port", func() {
+			tcpRoute := &gatewayv1alpha2.TCPRoute{}
+			Eventually(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfRoute), tcpRoute)).To(Succeed())
+			}).Should(Succeed())
+
+			Expect(tcpRoute.Spec.ParentRefs).To(ConsistOf(gatewayv1alpha2.ParentReference{
+				Group:       tools.PtrTo(gatewayv1alpha2.Group("gateway.networking.k8s.io")),
+				Kind:        tools.PtrTo(gatewayv1alpha2.Kind("Gateway")),
+				Namespace:   tools.PtrTo(gatewayv1alpha2.Namespace("korifi-gateway")),
+				Name:        gatewayv1alpha2.ObjectName("korifi"),
+				SectionName: tools.PtrTo(gatewayv1alpha2.SectionName("tcp-1024")),
+			}))
+			Expect(tcpRoute.Spec.Rules).To(HaveLen(1))
+			Expect(tcpRoute.Spec.Rules[0].BackendRefs).To(ConsistOf(HaveField("BackendObjectReference", gatewayv1alpha2.BackendObjectReference{
+				Group: tools.PtrTo(gatewayv1alpha2.Group("")),
+				Kind:  tools.PtrTo(gatewayv1alpha2.Kind("Service")),
+				Name:  gatewayv1alpha2.ObjectName(fmt.Sprintf("s-%s", cfRoute.Spec.Destinations[0].GUID)),
+				Port:  tools.PtrTo(gatewayv1alpha2.PortNumber(9000)),
+			})))
+			Expect(tcpRoute.OwnerReferences).To(ConsistOf(HaveField("UID", cfRoute.GetUID())))
+		})
+
+		When("the domain opts into external-dns", func() {
+			BeforeEach(func() {
+				Expect(k8s.PatchResource(ctx, adminClient, cfDomain, func() {
+					cfDomain.Annotations = map[string]string{
+						korifiv1alpha1.ExternalDNSAnnotationKey: "true",
+					}
+				})).To(Succeed())
+			})
+
+			It("sets the domain as the external-dns hostname of the TCPRoute", func() {
+				Eventually(func(g Gomega) {
+					tcpRoute := &gatewayv1alpha2.TCPRoute{}
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfRoute), tcpRoute)).To(Succeed())
+					g.Expect(tcpRoute.Annotations).To(HaveKeyWithValue(korifiv1alpha1.ExternalDNSAnnotationKey, "true"))
+					g.Expect(tcpRoute.Annotations).To(HaveKeyWithValue(routes.ExternalDNSHostnameAnnotationKey, cfDomain.Spec.Name))
+				}).Should(Succeed())
+			})
+		})
+
+		It("does not create an HTTPRoute", func() {
+			Consistently(func(g Gomega) {
+				httpRoutes := &gatewayv1beta1.HTTPRouteList{}
+				g.Expect(adminClient.List(ctx, httpRoutes, client.InNamespace(ns.Name))).To(Succeed())
+				g.Expect(httpRoutes.Items).To(BeEmpty())
+			}).Should(Succeed())
+		})
+
+		It("sets the domain and port as the route uri", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfRoute), cfRoute)).To(Succeed())
+				g.Expect(meta.IsStatusConditionTrue(cfRoute.Status.Conditions, korifiv1alpha1.StatusConditionReady)).To(BeTrue())
+				g.Expect(cfRoute.Status.FQDN).To(Equal(cfDomain.Spec.Name))
+				g.Expect(cfRoute.Status.URI).To(Equal(cfDomain.Spec.Name + ":1024"))
+				g.Expect(cfRoute.Status.Destinations).To(ConsistOf(HaveField("Protocol", PointTo(Equal("tcp")))))
+			}).Should(Succeed())
+		})
+
+		When("the port is not one of the tcp ports of the gateway", func() {
+			BeforeEach(func() {
+				cfRoute.Spec.Port = 2048
+			})
+
+			It("sets the ready condition to false", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfRoute), cfRoute)).To(Succeed())
+					g.Expect(cfRoute.Status.Conditions).To(ContainElement(SatisfyAll(
+						HaveField("Type", korifiv1alpha1.StatusConditionReady),
+						HaveField("Status", metav1.ConditionFalse),
+						HaveField("Reason", "InvalidTCPPort"),
+					)))
+				}).Should(Succeed())
+			})
+		})
+	})
+
 	When("a route has a legacy finalizer", func() {
 		BeforeEach(func() {
 			cfRoute.Finalizers = []string{
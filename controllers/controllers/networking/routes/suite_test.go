@@ -12,14 +12,18 @@ import (
 	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
 	"code.cloudfoundry.org/korifi/tests/helpers"
 
+	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 	//+kubebuilder:scaffold:imports
 )
@@ -30,6 +34,8 @@ var (
 	testEnv         *envtest.Environment
 	adminClient     client.Client
 	ctx             context.Context
+
+	activatorNamespace string
 )
 
 func TestNetworkingControllers(t *testing.T) {
@@ -59,12 +65,20 @@ var _ = BeforeSuite(func() {
 
 	Expect(korifiv1alpha1.AddToScheme(scheme.Scheme)).To(Succeed())
 	Expect(gatewayv1beta1.Install(scheme.Scheme)).To(Succeed())
+	Expect(gatewayv1alpha2.Install(scheme.Scheme)).To(Succeed())
 
 	k8sManager := helpers.NewK8sManager(testEnv, filepath.Join("helm", "korifi", "controllers", "role.yaml"))
 	Expect(shared.SetupIndexWithManager(k8sManager)).To(Succeed())
 
 	adminClient, stopClientCache = helpers.NewCachedClient(testEnv.Config)
 
+	activatorNamespace = uuid.NewString()
+	Expect(adminClient.Create(context.Background(), &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: activatorNamespace,
+		},
+	})).To(Succeed())
+
 	Expect(routes.NewReconciler(
 		k8sManager.GetClient(),
 		k8sManager.GetScheme(),
@@ -77,8 +91,16 @@ var _ = BeforeSuite(func() {
 			Networking: config.Networking{
 				GatewayName:      "korifi",
 				GatewayNamespace: "korifi-gateway",
+				TCPPorts:         []int32{1024},
+			},
+			Idling: config.Idling{
+				Enabled:                   true,
+				ActivatorServiceName:      "korifi-activator",
+				ActivatorServiceNamespace: activatorNamespace,
+				ActivatorPort:             8000,
 			},
 		},
+		[]byte("the-signing-key"),
 	).SetupWithManager(k8sManager)).To(Succeed())
 
 	stopManager = helpers.StartK8sManager(k8sManager)
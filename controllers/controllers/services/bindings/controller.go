@@ -29,6 +29,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -54,15 +55,26 @@ type Reconciler struct {
 	scheme                    *runtime.Scheme
 	log                       logr.Logger
 	upsiCredentialsReconciler CredentialsReconciler
+	workloadKind              string
 }
 
+// NewReconciler creates a reconciler that projects the binding credentials
+// into the workloads of kind workloadKind (e.g. StatefulSet) that the
+// configured runner creates for the app
 func NewReconciler(
 	k8sClient client.Client,
 	scheme *runtime.Scheme,
 	log logr.Logger,
 	upsiCredentialsReconciler CredentialsReconciler,
+	workloadKind string,
 ) *k8s.PatchingReconciler[korifiv1alpha1.CFServiceBinding, *korifiv1alpha1.CFServiceBinding] {
-	cfBindingReconciler := &Reconciler{k8sClient: k8sClient, scheme: scheme, log: log, upsiCredentialsReconciler: upsiCredentialsReconciler}
+	cfBindingReconciler := &Reconciler{
+		k8sClient:                 k8sClient,
+		scheme:                    scheme,
+		log:                       log,
+		upsiCredentialsReconciler: upsiCredentialsReconciler,
+		workloadKind:              workloadKind,
+	}
 	return k8s.NewPatchingReconciler(log, k8sClient, cfBindingReconciler)
 }
 
@@ -77,6 +89,10 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) *builder.Builder {
 		Watches(
 			&korifiv1alpha1.CFApp{},
 			handler.EnqueueRequestsFromMapFunc(r.appToServiceBindings),
+		).
+		Watches(
+			r.newWorkload(),
+			handler.EnqueueRequestsFromMapFunc(r.workloadToServiceBindings),
 		)
 }
 
@@ -105,13 +121,24 @@ func (r *Reconciler) serviceInstanceToServiceBindings(ctx context.Context, o cli
 }
 
 func (r *Reconciler) appToServiceBindings(ctx context.Context, o client.Object) []reconcile.Request {
-	cfApp := o.(*korifiv1alpha1.CFApp)
+	return r.appGUIDToServiceBindings(ctx, o.GetNamespace(), o.GetName())
+}
 
+func (r *Reconciler) workloadToServiceBindings(ctx context.Context, o client.Object) []reconcile.Request {
+	appGUID, ok := o.GetLabels()[korifiv1alpha1.CFAppGUIDLabelKey]
+	if !ok {
+		return []reconcile.Request{}
+	}
+
+	return r.appGUIDToServiceBindings(ctx, o.GetNamespace(), appGUID)
+}
+
+func (r *Reconciler) appGUIDToServiceBindings(ctx context.Context, namespace, appGUID string) []reconcile.Request {
 	serviceBindings := &korifiv1alpha1.CFServiceBindingList{}
 
 	if err := r.k8sClient.List(ctx, serviceBindings,
-		client.InNamespace(cfApp.Namespace),
-		client.MatchingFields{shared.IndexServiceBindingAppGUID: cfApp.Name},
+		client.InNamespace(namespace),
+		client.MatchingFields{shared.IndexServiceBindingAppGUID: appGUID},
 	); err != nil {
 		return []reconcile.Request{}
 	}
@@ -131,7 +158,8 @@ func (r *Reconciler) appToServiceBindings(ctx context.Context, o client.Object)
 
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfservicebindings,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfservicebindings/status,verbs=get;update;patch
-//+kubebuilder:rbac:groups=servicebinding.io,resources=servicebindings,verbs=get;list;create;update;patch;watch
+//+kubebuilder:rbac:groups=servicebinding.io,resources=servicebindings,verbs=get;list;create;update;patch;watch;delete
+//+kubebuilder:rbac:groups=apps,resources=statefulsets;deployments,verbs=get;list;watch
 
 func (r *Reconciler) ReconcileResource(ctx context.Context, cfServiceBinding *korifiv1alpha1.CFServiceBinding) (ctrl.Result, error) {
 	log := logr.FromContextOrDiscard(ctx)
@@ -164,6 +192,22 @@ func (r *Reconciler) ReconcileResource(ctx context.Context, cfServiceBinding *ko
 		return ctrl.Result{}, err
 	}
 
+	if !cfServiceBinding.ProjectsFiles(cfApp) {
+		err = r.k8sClient.Delete(ctx, r.toSBServiceBinding(cfServiceBinding))
+		if client.IgnoreNotFound(err) != nil {
+			log.Info("error deleting servicebinding.io servicebinding", "reason", err)
+			return ctrl.Result{}, err
+		}
+
+		err = r.deleteRotatedBindingSecrets(ctx, cfServiceBinding)
+		if err != nil {
+			log.Info("error deleting rotated binding secrets", "reason", err)
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
 	sbServiceBinding, err := r.reconcileSBServiceBinding(ctx, cfServiceBinding)
 	if err != nil {
 		log.Info("error creating/updating servicebinding.io servicebinding", "reason", err)
@@ -174,9 +218,112 @@ func (r *Reconciler) ReconcileResource(ctx context.Context, cfServiceBinding *ko
 		return ctrl.Result{}, k8s.NewNotReadyError().WithReason("ServiceBindingNotReady")
 	}
 
+	if sbServiceBinding.Status.Binding == nil || sbServiceBinding.Status.Binding.Name != cfServiceBinding.Status.Binding.Name {
+		return ctrl.Result{}, nil
+	}
+
+	err = r.deleteRotatedBindingSecrets(ctx, cfServiceBinding)
+	if err != nil {
+		log.Info("error deleting rotated binding secrets", "reason", err)
+		return ctrl.Result{}, err
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// deleteRotatedBindingSecrets deletes the binding secrets of previous
+// credentials rotations once the current one is projected into the app
+// workloads. Running instances keep the credentials they were started with
+// until they are replaced by the rolling restart of the app, so the secrets
+// are only deleted once all the app workloads have rolled out. The workload
+// watch retriggers the reconciliation when the rollout completes.
+func (r *Reconciler) deleteRotatedBindingSecrets(ctx context.Context, cfServiceBinding *korifiv1alpha1.CFServiceBinding) error {
+	log := logr.FromContextOrDiscard(ctx)
+
+	bindingSecrets := &corev1.SecretList{}
+	err := r.k8sClient.List(ctx, bindingSecrets,
+		client.InNamespace(cfServiceBinding.Namespace),
+		client.MatchingLabels{ServiceBindingGUIDLabel: cfServiceBinding.Name},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to list binding secrets: %w", err)
+	}
+
+	rotatedSecrets := []corev1.Secret{}
+	for _, bindingSecret := range bindingSecrets.Items {
+		if bindingSecret.Name != cfServiceBinding.Status.Binding.Name {
+			rotatedSecrets = append(rotatedSecrets, bindingSecret)
+		}
+	}
+
+	if len(rotatedSecrets) == 0 {
+		return nil
+	}
+
+	rolledOut, err := r.isAppRolledOut(ctx, cfServiceBinding)
+	if err != nil {
+		return err
+	}
+
+	if !rolledOut {
+		log.V(1).Info("keeping rotated binding secrets until the app workloads have rolled out")
+		return nil
+	}
+
+	for i := range rotatedSecrets {
+		if err = r.k8sClient.Delete(ctx, &rotatedSecrets[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete binding secret %q: %w", rotatedSecrets[i].Name, err)
+		}
+	}
+
+	return nil
+}
+
+// isAppRolledOut checks that all the workloads of the app have observed their
+// latest spec and that all their replicas are updated and ready
+func (r *Reconciler) isAppRolledOut(ctx context.Context, cfServiceBinding *korifiv1alpha1.CFServiceBinding) (bool, error) {
+	workloads := &unstructured.UnstructuredList{}
+	workloads.SetGroupVersionKind(r.newWorkload().GroupVersionKind().GroupVersion().WithKind(r.workloadKind + "List"))
+
+	err := r.k8sClient.List(ctx, workloads,
+		client.InNamespace(cfServiceBinding.Namespace),
+		client.MatchingLabels{korifiv1alpha1.CFAppGUIDLabelKey: cfServiceBinding.Spec.AppRef.Name},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to list app workloads: %w", err)
+	}
+
+	for _, workload := range workloads.Items {
+		observedGeneration, _, _ := unstructured.NestedInt64(workload.Object, "status", "observedGeneration")
+		if observedGeneration != workload.GetGeneration() {
+			return false, nil
+		}
+
+		desiredReplicas, found, _ := unstructured.NestedInt64(workload.Object, "spec", "replicas")
+		if !found {
+			desiredReplicas = 1
+		}
+
+		// during a rollout the replicas also count the instances that
+		// still run the previous spec
+		replicas, _, _ := unstructured.NestedInt64(workload.Object, "status", "replicas")
+		updatedReplicas, _, _ := unstructured.NestedInt64(workload.Object, "status", "updatedReplicas")
+		readyReplicas, _, _ := unstructured.NestedInt64(workload.Object, "status", "readyReplicas")
+		if replicas != desiredReplicas || updatedReplicas != desiredReplicas || readyReplicas != desiredReplicas {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (r *Reconciler) newWorkload() *unstructured.Unstructured {
+	workload := &unstructured.Unstructured{}
+	workload.SetAPIVersion("apps/v1")
+	workload.SetKind(r.workloadKind)
+	return workload
+}
+
 func needsRequeue(res ctrl.Result) bool {
 	return !res.IsZero()
 }
@@ -197,7 +344,7 @@ func isSbServiceBindingReady(sbServiceBinding *servicebindingv1beta1.ServiceBind
 func (r *Reconciler) reconcileSBServiceBinding(ctx context.Context, cfServiceBinding *korifiv1alpha1.CFServiceBinding) (*servicebindingv1beta1.ServiceBinding, error) {
 	credentialsSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      cfServiceBinding.Name,
+			Name:      cfServiceBinding.Status.Binding.Name,
 			Namespace: cfServiceBinding.Namespace,
 		},
 	}
@@ -245,7 +392,7 @@ func (r *Reconciler) toSBServiceBinding(cfServiceBinding *korifiv1alpha1.CFServi
 			Type: "user-provided",
 			Workload: servicebindingv1beta1.ServiceBindingWorkloadReference{
 				APIVersion: "apps/v1",
-				Kind:       "StatefulSet",
+				Kind:       r.workloadKind,
 				Selector: &metav1.LabelSelector{
 					MatchLabels: map[string]string{
 						korifiv1alpha1.CFAppGUIDLabelKey: cfServiceBinding.Spec.AppRef.Name,
@@ -266,5 +413,11 @@ func getSBServiceBindingName(cfServiceBinding *korifiv1alpha1.CFServiceBinding)
 		return *cfServiceBinding.Spec.DisplayName
 	}
 
+	// the binding secret name changes on credentials rotation, while the
+	// credentials should be projected into the same directory
+	if cfServiceBinding.Status.CredentialsRotation != "" {
+		return cfServiceBinding.Name
+	}
+
 	return cfServiceBinding.Status.Binding.Name
 }
@@ -14,10 +14,14 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
+	servicebindingv1 "github.com/servicebinding/runtime/apis/v1"
 	servicebindingv1beta1 "github.com/servicebinding/runtime/apis/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -248,6 +252,92 @@ var _ = Describe("CFServiceBinding", func() {
 		})
 	})
 
+	When("the binding is only projected into VCAP_SERVICES", func() {
+		BeforeEach(func() {
+			Expect(k8s.PatchResource(ctx, adminClient, binding, func() {
+				binding.Annotations = map[string]string{
+					korifiv1alpha1.ServiceBindingProjectionAnnotationKey: korifiv1alpha1.ServiceBindingProjectionVCAPServices,
+				}
+			})).To(Succeed())
+		})
+
+		It("does not create a servicebinding.io ServiceBinding", func() {
+			Consistently(func(g Gomega) {
+				sbServiceBinding := &servicebindingv1beta1.ServiceBinding{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: testNamespace,
+						Name:      fmt.Sprintf("cf-binding-%s", binding.Name),
+					},
+				}
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(sbServiceBinding), sbServiceBinding)).To(MatchError(ContainSubstring("not found")))
+			}).Should(Succeed())
+		})
+
+		It("sets the binding Ready status condition to true", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(binding), binding)).To(Succeed())
+				g.Expect(binding.Status.Conditions).To(ContainElement(SatisfyAll(
+					HasType(Equal(korifiv1alpha1.StatusConditionReady)),
+					HasStatus(Equal(metav1.ConditionTrue)),
+				)))
+			}).Should(Succeed())
+		})
+	})
+
+	When("the app projects its bindings into VCAP_SERVICES only", func() {
+		BeforeEach(func() {
+			Eventually(func(g Gomega) {
+				sbServiceBinding := &servicebindingv1beta1.ServiceBinding{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: testNamespace,
+						Name:      fmt.Sprintf("cf-binding-%s", binding.Name),
+					},
+				}
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(sbServiceBinding), sbServiceBinding)).To(Succeed())
+			}).Should(Succeed())
+
+			Expect(k8s.PatchResource(ctx, adminClient, cfApp, func() {
+				cfApp.Annotations = map[string]string{
+					korifiv1alpha1.ServiceBindingProjectionAnnotationKey: korifiv1alpha1.ServiceBindingProjectionVCAPServices,
+				}
+			})).To(Succeed())
+		})
+
+		It("deletes the servicebinding.io ServiceBinding", func() {
+			Eventually(func(g Gomega) {
+				sbServiceBinding := &servicebindingv1beta1.ServiceBinding{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: testNamespace,
+						Name:      fmt.Sprintf("cf-binding-%s", binding.Name),
+					},
+				}
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(sbServiceBinding), sbServiceBinding)).To(MatchError(ContainSubstring("not found")))
+			}).Should(Succeed())
+		})
+
+		When("the binding overrides the projection", func() {
+			BeforeEach(func() {
+				Expect(k8s.PatchResource(ctx, adminClient, binding, func() {
+					binding.Annotations = map[string]string{
+						korifiv1alpha1.ServiceBindingProjectionAnnotationKey: korifiv1alpha1.ServiceBindingProjectionAll,
+					}
+				})).To(Succeed())
+			})
+
+			It("keeps the servicebinding.io ServiceBinding", func() {
+				Consistently(func(g Gomega) {
+					sbServiceBinding := &servicebindingv1beta1.ServiceBinding{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: testNamespace,
+							Name:      fmt.Sprintf("cf-binding-%s", binding.Name),
+						},
+					}
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(sbServiceBinding), sbServiceBinding)).To(Succeed())
+				}).Should(Succeed())
+			})
+		})
+	})
+
 	When("the servicebinding.io binding is ready", func() {
 		var sbBinding *servicebindingv1beta1.ServiceBinding
 
@@ -472,6 +562,148 @@ var _ = Describe("CFServiceBinding", func() {
 		})
 	})
 
+	When("the credentials are rotated", func() {
+		JustBeforeEach(func() {
+			Eventually(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(binding), binding)).To(Succeed())
+				g.Expect(binding.Status.Binding.Name).To(Equal(binding.Name))
+			}).Should(Succeed())
+
+			Expect(k8s.PatchResource(ctx, adminClient, binding, func() {
+				binding.Annotations = map[string]string{
+					korifiv1alpha1.CredentialsRotationAnnotationKey: "2024-10-16T10:00:00Z",
+				}
+			})).To(Succeed())
+		})
+
+		It("refreshes the credentials into a new binding secret", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(binding), binding)).To(Succeed())
+				g.Expect(binding.Status.CredentialsRotation).To(Equal("2024-10-16T10:00:00Z"))
+				g.Expect(binding.Status.Binding.Name).To(HavePrefix(binding.Name + "-"))
+
+				bindingSecret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: binding.Namespace,
+						Name:      binding.Status.Binding.Name,
+					},
+				}
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(bindingSecret), bindingSecret)).To(Succeed())
+				g.Expect(bindingSecret.Labels).To(HaveKeyWithValue(bindings.ServiceBindingGUIDLabel, binding.Name))
+				g.Expect(bindingSecret.Data).To(MatchAllKeys(Keys{
+					"type": BeEquivalentTo("user-provided"),
+					"obj":  BeEquivalentTo(`{"foo":"bar"}`),
+				}))
+			}).Should(Succeed())
+		})
+
+		It("keeps the previous binding secret", func() {
+			Consistently(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, types.NamespacedName{Namespace: binding.Namespace, Name: binding.Name}, &corev1.Secret{})).To(Succeed())
+			}).Should(Succeed())
+		})
+
+		It("keeps projecting the credentials under the binding name", func() {
+			Eventually(func(g Gomega) {
+				sbServiceBinding := &servicebindingv1beta1.ServiceBinding{}
+				g.Expect(adminClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: fmt.Sprintf("cf-binding-%s", binding.Name)}, sbServiceBinding)).To(Succeed())
+				g.Expect(sbServiceBinding.Spec.Name).To(Equal(binding.Name))
+			}).Should(Succeed())
+		})
+
+		When("the new binding secret is projected into the app workloads", func() {
+			JustBeforeEach(func() {
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(binding), binding)).To(Succeed())
+					g.Expect(binding.Status.CredentialsRotation).NotTo(BeEmpty())
+				}).Should(Succeed())
+
+				sbBinding := &servicebindingv1beta1.ServiceBinding{}
+				Expect(adminClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: fmt.Sprintf("cf-binding-%s", binding.Name)}, sbBinding)).To(Succeed())
+				Expect(k8s.Patch(ctx, adminClient, sbBinding, func() {
+					meta.SetStatusCondition(&sbBinding.Status.Conditions, metav1.Condition{
+						Type:   "Ready",
+						Status: metav1.ConditionTrue,
+						Reason: "whatever",
+					})
+					sbBinding.Status.Binding = &servicebindingv1.ServiceBindingSecretReference{Name: binding.Status.Binding.Name}
+					sbBinding.Status.ObservedGeneration = sbBinding.Generation + 1
+				})).To(Succeed())
+			})
+
+			It("deletes the previous binding secret", func() {
+				Eventually(func(g Gomega) {
+					err := adminClient.Get(ctx, types.NamespacedName{Namespace: binding.Namespace, Name: binding.Name}, &corev1.Secret{})
+					g.Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+				}).Should(Succeed())
+			})
+
+			It("keeps the current binding secret", func() {
+				Consistently(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, types.NamespacedName{Namespace: binding.Namespace, Name: binding.Status.Binding.Name}, &corev1.Secret{})).To(Succeed())
+				}).Should(Succeed())
+			})
+
+			When("the app workload is still rolling out", func() {
+				var statefulSet *appsv1.StatefulSet
+
+				BeforeEach(func() {
+					statefulSet = &appsv1.StatefulSet{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      uuid.NewString(),
+							Namespace: testNamespace,
+							Labels: map[string]string{
+								korifiv1alpha1.CFAppGUIDLabelKey: cfApp.Name,
+							},
+						},
+						Spec: appsv1.StatefulSetSpec{
+							Replicas: tools.PtrTo[int32](2),
+							Selector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"app": cfApp.Name},
+							},
+							Template: corev1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"app": cfApp.Name},
+								},
+								Spec: corev1.PodSpec{
+									Containers: []corev1.Container{{Name: "application", Image: "app-image"}},
+								},
+							},
+						},
+					}
+					Expect(adminClient.Create(ctx, statefulSet)).To(Succeed())
+					Expect(k8s.Patch(ctx, adminClient, statefulSet, func() {
+						statefulSet.Status.ObservedGeneration = statefulSet.Generation
+						statefulSet.Status.Replicas = 2
+						statefulSet.Status.UpdatedReplicas = 1
+						statefulSet.Status.ReadyReplicas = 2
+					})).To(Succeed())
+				})
+
+				It("keeps the previous binding secret", func() {
+					Consistently(func(g Gomega) {
+						g.Expect(adminClient.Get(ctx, types.NamespacedName{Namespace: binding.Namespace, Name: binding.Name}, &corev1.Secret{})).To(Succeed())
+					}).Should(Succeed())
+				})
+
+				When("all the workload replicas are updated and ready", func() {
+					JustBeforeEach(func() {
+						Expect(k8s.Patch(ctx, adminClient, statefulSet, func() {
+							statefulSet.Status.UpdatedReplicas = 2
+						})).To(Succeed())
+					})
+
+					It("deletes the previous binding secret", func() {
+						Eventually(func(g Gomega) {
+							err := adminClient.Get(ctx, types.NamespacedName{Namespace: binding.Namespace, Name: binding.Name}, &corev1.Secret{})
+							g.Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+						}).Should(Succeed())
+					})
+				})
+			})
+		})
+	})
+
 	When("the binding references a 'legacy' instance credentials secret", func() {
 		JustBeforeEach(func() {
 			Expect(k8s.Patch(ctx, adminClient, instance, func() {
@@ -92,6 +92,7 @@ var _ = BeforeEach(func() {
 		k8sManager.GetScheme(),
 		ctrl.Log.WithName("controllers").WithName("CFServiceBinding"),
 		upsi.NewReconciler(k8sManager.GetClient(), k8sManager.GetScheme()),
+		"StatefulSet",
 	).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 })
@@ -2,6 +2,7 @@ package upsi
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/controllers/services/bindings"
 	"code.cloudfoundry.org/korifi/controllers/controllers/services/credentials"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -56,7 +58,7 @@ func (r *CredentialsReconciler) ReconcileResource(ctx context.Context, cfService
 		if k8serrors.IsInvalid(err) {
 			err = r.k8sClient.Delete(ctx, &corev1.Secret{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      cfServiceBinding.Name,
+					Name:      bindingSecretName(cfServiceBinding),
 					Namespace: cfServiceBinding.Namespace,
 				},
 			})
@@ -87,7 +89,7 @@ func isLegacyServiceBinding(cfServiceBinding *korifiv1alpha1.CFServiceBinding, c
 func (r *CredentialsReconciler) reconcileCredentials(ctx context.Context, cfServiceInstance *korifiv1alpha1.CFServiceInstance, cfServiceBinding *korifiv1alpha1.CFServiceBinding) error {
 	cfServiceBinding.Status.Credentials.Name = cfServiceInstance.Status.Credentials.Name
 
-	if isLegacyServiceBinding(cfServiceBinding, cfServiceInstance) {
+	if isLegacyServiceBinding(cfServiceBinding, cfServiceInstance) && !isRotationRequested(cfServiceBinding) {
 		bindingSecret := &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      cfServiceBinding.Status.Binding.Name,
@@ -118,12 +120,17 @@ func (r *CredentialsReconciler) reconcileCredentials(ctx context.Context, cfServ
 
 	bindingSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      cfServiceBinding.Name,
+			Name:      bindingSecretName(cfServiceBinding),
 			Namespace: cfServiceBinding.Namespace,
 		},
 	}
 
 	_, err = controllerutil.CreateOrPatch(ctx, r.k8sClient, bindingSecret, func() error {
+		if bindingSecret.Labels == nil {
+			bindingSecret.Labels = map[string]string{}
+		}
+		bindingSecret.Labels[bindings.ServiceBindingGUIDLabel] = cfServiceBinding.Name
+
 		bindingSecret.Type, err = credentials.GetBindingSecretType(credentialsSecret)
 		if err != nil {
 			return err
@@ -140,6 +147,24 @@ func (r *CredentialsReconciler) reconcileCredentials(ctx context.Context, cfServ
 	}
 
 	cfServiceBinding.Status.Binding.Name = bindingSecret.Name
+	cfServiceBinding.Status.CredentialsRotation = cfServiceBinding.Annotations[korifiv1alpha1.CredentialsRotationAnnotationKey]
 
 	return nil
 }
+
+func isRotationRequested(cfServiceBinding *korifiv1alpha1.CFServiceBinding) bool {
+	return cfServiceBinding.Annotations[korifiv1alpha1.CredentialsRotationAnnotationKey] != cfServiceBinding.Status.CredentialsRotation
+}
+
+// bindingSecretName changes on every credentials rotation, so that the
+// previous credentials remain available to the bound app until the new ones
+// are projected into its workloads
+func bindingSecretName(cfServiceBinding *korifiv1alpha1.CFServiceBinding) string {
+	rotation, ok := cfServiceBinding.Annotations[korifiv1alpha1.CredentialsRotationAnnotationKey]
+	if !ok {
+		return cfServiceBinding.Name
+	}
+
+	rotationHash := sha256.Sum256([]byte(rotation))
+	return fmt.Sprintf("%s-%x", cfServiceBinding.Name, rotationHash[:4])
+}
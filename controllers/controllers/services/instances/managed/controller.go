@@ -123,6 +123,13 @@ func (r *Reconciler) ReconcileResource(ctx context.Context, serviceInstance *kor
 		return ctrl.Result{}, err
 	}
 
+	// the plan and offering names are exposed to the bound apps in VCAP_SERVICES
+	if serviceInstance.Labels == nil {
+		serviceInstance.Labels = map[string]string{}
+	}
+	serviceInstance.Labels[korifiv1alpha1.RelServicePlanNameLabel] = servicePlan.Spec.Name
+	serviceInstance.Labels[korifiv1alpha1.RelServiceOfferingNameLabel] = serviceOffering.Spec.Name
+
 	osbapiClient, err := r.osbapiClientFactory.CreateClient(ctx, serviceBroker)
 	if err != nil {
 		log.Error(err, "failed to create broker client", "broker", serviceBroker.Name)
@@ -79,6 +79,7 @@ var _ = Describe("CFServiceInstance", func() {
 			},
 			Spec: korifiv1alpha1.CFServiceOfferingSpec{
 				ServiceOffering: services.ServiceOffering{
+					Name: "service-offering-name",
 					BrokerCatalog: services.ServiceBrokerCatalog{
 						ID: "service-offering-id",
 					},
@@ -101,6 +102,7 @@ var _ = Describe("CFServiceInstance", func() {
 					Type: "public",
 				},
 				ServicePlan: services.ServicePlan{
+					Name: "service-plan-name",
 					BrokerCatalog: services.ServicePlanBrokerCatalog{
 						ID: "service-plan-id",
 					},
@@ -152,6 +154,16 @@ var _ = Describe("CFServiceInstance", func() {
 		}).Should(Succeed())
 	})
 
+	It("labels the instance with the plan and offering names", func() {
+		Eventually(func(g Gomega) {
+			g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(instance), instance)).To(Succeed())
+			g.Expect(instance.Labels).To(SatisfyAll(
+				HaveKeyWithValue(korifiv1alpha1.RelServicePlanNameLabel, "service-plan-name"),
+				HaveKeyWithValue(korifiv1alpha1.RelServiceOfferingNameLabel, "service-offering-name"),
+			))
+		}).Should(Succeed())
+	})
+
 	It("provisions the service", func() {
 		Eventually(func(g Gomega) {
 			g.Expect(brokerClient.ProvisionCallCount()).To(Equal(1))
@@ -0,0 +1,276 @@
+package usage
+
+import (
+	"context"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ServiceInstanceReconciler records a CREATED service usage event for every
+// service instance and a DELETED event once it is gone. The instances are
+// finalized until their deletion has been recorded and remember the creation
+// has been recorded in their status, so that neither event is missed or
+// recorded twice, even after the events have been cleaned up.
+type ServiceInstanceReconciler struct {
+	recorder recorder
+	log      logr.Logger
+}
+
+func NewServiceInstanceReconciler(
+	k8sClient client.Client,
+	rootNamespace string,
+	log logr.Logger,
+) *ServiceInstanceReconciler {
+	return &ServiceInstanceReconciler{
+		recorder: recorder{k8sClient: k8sClient, rootNamespace: rootNamespace},
+		log:      log,
+	}
+}
+
+func (r *ServiceInstanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("cfserviceinstance-usage").
+		For(&korifiv1alpha1.CFServiceInstance{}).
+		Complete(r)
+}
+
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfserviceusageevents,verbs=get;list;watch;create
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfserviceinstances,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfserviceinstances/status,verbs=get;patch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfserviceinstances/finalizers,verbs=update
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfserviceplans,verbs=get;list;watch
+
+func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req reconcile.Request) (ctrl.Result, error) {
+	log := r.log.WithName("ServiceInstanceUsage").
+		WithValues("namespace", req.Namespace).
+		WithValues("name", req.Name).
+		WithValues("logID", uuid.NewString())
+
+	serviceInstance := &korifiv1alpha1.CFServiceInstance{}
+	err := r.recorder.k8sClient.Get(ctx, req.NamespacedName, serviceInstance)
+	if k8serrors.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		log.Info("unable to fetch service instance", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	if !serviceInstance.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, r.recordDeleted(ctx, log, serviceInstance)
+	}
+
+	if !controllerutil.ContainsFinalizer(serviceInstance, korifiv1alpha1.CFServiceUsageFinalizerName) {
+		err = k8s.PatchResource(ctx, r.recorder.k8sClient, serviceInstance, func() {
+			controllerutil.AddFinalizer(serviceInstance, korifiv1alpha1.CFServiceUsageFinalizerName)
+		})
+		if err != nil {
+			log.Info("unable to add the usage finalizer", "reason", err)
+			return ctrl.Result{}, err
+		}
+	}
+
+	if serviceInstance.Status.UsageRecorded {
+		return ctrl.Result{}, nil
+	}
+
+	spec, err := r.recorder.serviceInstanceSpec(ctx, serviceInstance)
+	if err != nil {
+		log.Info("unable to describe service instance", "reason", err)
+		return ctrl.Result{}, err
+	}
+	spec.State = korifiv1alpha1.ServiceUsageStateCreated
+
+	err = r.recorder.record(ctx, serviceInstance.Name, spec)
+	if err != nil {
+		log.Info("unable to record service instance creation", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	err = k8s.Patch(ctx, r.recorder.k8sClient, serviceInstance, func() {
+		serviceInstance.Status.UsageRecorded = true
+	})
+	if err != nil {
+		log.Info("unable to mark service instance creation as recorded", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *ServiceInstanceReconciler) recordDeleted(ctx context.Context, log logr.Logger, serviceInstance *korifiv1alpha1.CFServiceInstance) error {
+	if !isGone(serviceInstance) {
+		return nil
+	}
+
+	spec, err := r.recorder.serviceInstanceSpec(ctx, serviceInstance)
+	if err != nil {
+		log.Info("unable to describe service instance", "reason", err)
+		return err
+	}
+	spec.State = korifiv1alpha1.ServiceUsageStateDeleted
+
+	err = r.recorder.record(ctx, serviceInstance.Name, spec)
+	if err != nil {
+		log.Info("unable to record service instance deletion", "reason", err)
+		return err
+	}
+
+	err = k8s.PatchResource(ctx, r.recorder.k8sClient, serviceInstance, func() {
+		controllerutil.RemoveFinalizer(serviceInstance, korifiv1alpha1.CFServiceUsageFinalizerName)
+	})
+	if err != nil {
+		log.Info("unable to remove the usage finalizer", "reason", err)
+		return client.IgnoreNotFound(err)
+	}
+
+	return nil
+}
+
+// ServiceBindingReconciler records a BINDING_CREATED service usage event for
+// every service binding and a BINDING_DELETED event once it is gone, the same
+// way ServiceInstanceReconciler does for service instances
+type ServiceBindingReconciler struct {
+	recorder recorder
+	log      logr.Logger
+}
+
+func NewServiceBindingReconciler(
+	k8sClient client.Client,
+	rootNamespace string,
+	log logr.Logger,
+) *ServiceBindingReconciler {
+	return &ServiceBindingReconciler{
+		recorder: recorder{k8sClient: k8sClient, rootNamespace: rootNamespace},
+		log:      log,
+	}
+}
+
+func (r *ServiceBindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("cfservicebinding-usage").
+		For(&korifiv1alpha1.CFServiceBinding{}).
+		Complete(r)
+}
+
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfservicebindings,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfservicebindings/status,verbs=get;patch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfservicebindings/finalizers,verbs=update
+
+func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req reconcile.Request) (ctrl.Result, error) {
+	log := r.log.WithName("ServiceBindingUsage").
+		WithValues("namespace", req.Namespace).
+		WithValues("name", req.Name).
+		WithValues("logID", uuid.NewString())
+
+	serviceBinding := &korifiv1alpha1.CFServiceBinding{}
+	err := r.recorder.k8sClient.Get(ctx, req.NamespacedName, serviceBinding)
+	if k8serrors.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		log.Info("unable to fetch service binding", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	if !serviceBinding.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, r.recordDeleted(ctx, log, serviceBinding)
+	}
+
+	if !controllerutil.ContainsFinalizer(serviceBinding, korifiv1alpha1.CFServiceUsageFinalizerName) {
+		err = k8s.PatchResource(ctx, r.recorder.k8sClient, serviceBinding, func() {
+			controllerutil.AddFinalizer(serviceBinding, korifiv1alpha1.CFServiceUsageFinalizerName)
+		})
+		if err != nil {
+			log.Info("unable to add the usage finalizer", "reason", err)
+			return ctrl.Result{}, err
+		}
+	}
+
+	if serviceBinding.Status.UsageRecorded {
+		return ctrl.Result{}, nil
+	}
+
+	spec, err := r.serviceBindingSpec(ctx, serviceBinding)
+	if err != nil {
+		log.Info("unable to describe service binding", "reason", err)
+		return ctrl.Result{}, err
+	}
+	spec.State = korifiv1alpha1.ServiceUsageStateBindingCreated
+
+	err = r.recorder.record(ctx, serviceBinding.Name, spec)
+	if err != nil {
+		log.Info("unable to record service binding creation", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	err = k8s.Patch(ctx, r.recorder.k8sClient, serviceBinding, func() {
+		serviceBinding.Status.UsageRecorded = true
+	})
+	if err != nil {
+		log.Info("unable to mark service binding creation as recorded", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *ServiceBindingReconciler) recordDeleted(ctx context.Context, log logr.Logger, serviceBinding *korifiv1alpha1.CFServiceBinding) error {
+	if !isGone(serviceBinding) {
+		return nil
+	}
+
+	spec, err := r.serviceBindingSpec(ctx, serviceBinding)
+	if err != nil {
+		log.Info("unable to describe service binding", "reason", err)
+		return err
+	}
+	spec.State = korifiv1alpha1.ServiceUsageStateBindingDeleted
+
+	err = r.recorder.record(ctx, serviceBinding.Name, spec)
+	if err != nil {
+		log.Info("unable to record service binding deletion", "reason", err)
+		return err
+	}
+
+	err = k8s.PatchResource(ctx, r.recorder.k8sClient, serviceBinding, func() {
+		controllerutil.RemoveFinalizer(serviceBinding, korifiv1alpha1.CFServiceUsageFinalizerName)
+	})
+	if err != nil {
+		log.Info("unable to remove the usage finalizer", "reason", err)
+		return client.IgnoreNotFound(err)
+	}
+
+	return nil
+}
+func (r *ServiceBindingReconciler) serviceBindingSpec(ctx context.Context, serviceBinding *korifiv1alpha1.CFServiceBinding) (korifiv1alpha1.CFServiceUsageEventSpec, error) {
+	serviceInstance := &korifiv1alpha1.CFServiceInstance{}
+	err := r.recorder.k8sClient.Get(ctx, client.ObjectKey{Namespace: serviceBinding.Namespace, Name: serviceBinding.Spec.Service.Name}, serviceInstance)
+	if client.IgnoreNotFound(err) != nil {
+		return korifiv1alpha1.CFServiceUsageEventSpec{}, err
+	}
+
+	var spec korifiv1alpha1.CFServiceUsageEventSpec
+	if k8serrors.IsNotFound(err) {
+		spec, err = r.recorder.spaceSpec(ctx, serviceBinding.Namespace)
+		spec.ServiceInstanceGUID = serviceBinding.Spec.Service.Name
+	} else {
+		spec, err = r.recorder.serviceInstanceSpec(ctx, serviceInstance)
+	}
+	if err != nil {
+		return korifiv1alpha1.CFServiceUsageEventSpec{}, err
+	}
+
+	spec.ServiceBindingGUID = serviceBinding.Name
+	spec.AppGUID = serviceBinding.Spec.AppRef.Name
+
+	return spec, nil
+}
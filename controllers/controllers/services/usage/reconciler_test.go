@@ -0,0 +1,242 @@
+package usage_test
+
+import (
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/model/services"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ = Describe("Service usage", func() {
+	var (
+		testNamespace   string
+		servicePlan     *korifiv1alpha1.CFServicePlan
+		serviceInstance *korifiv1alpha1.CFServiceInstance
+	)
+
+	BeforeEach(func() {
+		testNamespace = uuid.NewString()
+		Expect(adminClient.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: testNamespace,
+			},
+		})).To(Succeed())
+
+		servicePlan = &korifiv1alpha1.CFServicePlan{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      uuid.NewString(),
+				Namespace: rootNamespace,
+				Labels: map[string]string{
+					korifiv1alpha1.RelServiceOfferingGUIDLabel: "offering-guid",
+					korifiv1alpha1.RelServiceOfferingNameLabel: "offering-name",
+					korifiv1alpha1.RelServiceBrokerGUIDLabel:   "broker-guid",
+					korifiv1alpha1.RelServiceBrokerNameLabel:   "broker-name",
+				},
+			},
+			Spec: korifiv1alpha1.CFServicePlanSpec{
+				ServicePlan: services.ServicePlan{
+					Name: "plan-name",
+				},
+				Visibility: korifiv1alpha1.ServicePlanVisibility{
+					Type: korifiv1alpha1.PublicServicePlanVisibilityType,
+				},
+			},
+		}
+		Expect(adminClient.Create(ctx, servicePlan)).To(Succeed())
+
+		serviceInstance = &korifiv1alpha1.CFServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      uuid.NewString(),
+				Namespace: testNamespace,
+			},
+			Spec: korifiv1alpha1.CFServiceInstanceSpec{
+				DisplayName: "service-instance-name",
+				Type:        korifiv1alpha1.ManagedType,
+				PlanGUID:    servicePlan.Name,
+			},
+		}
+		Expect(adminClient.Create(ctx, serviceInstance)).To(Succeed())
+	})
+
+	It("records a CREATED event for the service instance", func() {
+		Eventually(func(g Gomega) {
+			g.Expect(serviceUsageEvents(g, serviceInstance.Name)).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"State":               Equal(korifiv1alpha1.ServiceUsageStateCreated),
+				"ServiceInstanceGUID": Equal(serviceInstance.Name),
+				"ServiceInstanceName": Equal("service-instance-name"),
+				"ServiceInstanceType": Equal(korifiv1alpha1.ManagedServiceInstanceUsageType),
+				"ServicePlanGUID":     Equal(servicePlan.Name),
+				"ServicePlanName":     Equal("plan-name"),
+				"ServiceOfferingGUID": Equal("offering-guid"),
+				"ServiceOfferingName": Equal("offering-name"),
+				"ServiceBrokerGUID":   Equal("broker-guid"),
+				"ServiceBrokerName":   Equal("broker-name"),
+				"SpaceGUID":           Equal(testNamespace),
+			})))
+		}).Should(Succeed())
+	})
+
+	It("finalizes the service instance and marks its creation as recorded", func() {
+		Eventually(func(g Gomega) {
+			g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(serviceInstance), serviceInstance)).To(Succeed())
+			g.Expect(serviceInstance.Finalizers).To(ContainElement(korifiv1alpha1.CFServiceUsageFinalizerName))
+			g.Expect(serviceInstance.Status.UsageRecorded).To(BeTrue())
+		}).Should(Succeed())
+	})
+
+	When("the CREATED event has been cleaned up", func() {
+		BeforeEach(func() {
+			Eventually(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(serviceInstance), serviceInstance)).To(Succeed())
+				g.Expect(serviceInstance.Status.UsageRecorded).To(BeTrue())
+			}).Should(Succeed())
+
+			Expect(adminClient.DeleteAllOf(ctx, &korifiv1alpha1.CFServiceUsageEvent{}, client.InNamespace(rootNamespace))).To(Succeed())
+			Expect(k8s.PatchResource(ctx, adminClient, serviceInstance, func() {
+				serviceInstance.Labels = map[string]string{"reconcile": "again"}
+			})).To(Succeed())
+		})
+
+		It("does not record it again", func() {
+			Consistently(func(g Gomega) {
+				g.Expect(serviceUsageEvents(g, serviceInstance.Name)).To(BeEmpty())
+			}).Should(Succeed())
+		})
+	})
+
+	When("the service instance is being deleted by another finalizer", func() {
+		BeforeEach(func() {
+			Eventually(func(g Gomega) {
+				g.Expect(serviceUsageEvents(g, serviceInstance.Name)).To(HaveLen(1))
+			}).Should(Succeed())
+
+			Expect(k8s.PatchResource(ctx, adminClient, serviceInstance, func() {
+				serviceInstance.Finalizers = append(serviceInstance.Finalizers, "test.korifi.cloudfoundry.org/deprovision")
+			})).To(Succeed())
+			Expect(adminClient.Delete(ctx, serviceInstance)).To(Succeed())
+		})
+
+		It("records the deletion once the other finalizer is done", func() {
+			Consistently(func(g Gomega) {
+				g.Expect(serviceUsageEvents(g, serviceInstance.Name)).To(HaveLen(1))
+			}).Should(Succeed())
+
+			Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(serviceInstance), serviceInstance)).To(Succeed())
+			Expect(k8s.PatchResource(ctx, adminClient, serviceInstance, func() {
+				controllerutil.RemoveFinalizer(serviceInstance, "test.korifi.cloudfoundry.org/deprovision")
+			})).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				g.Expect(serviceUsageEvents(g, serviceInstance.Name)).To(ContainElement(HaveField("State", korifiv1alpha1.ServiceUsageStateDeleted)))
+			}).Should(Succeed())
+		})
+	})
+
+	When("the service instance is deleted", func() {
+		BeforeEach(func() {
+			Eventually(func(g Gomega) {
+				g.Expect(serviceUsageEvents(g, serviceInstance.Name)).To(HaveLen(1))
+			}).Should(Succeed())
+
+			Expect(adminClient.Delete(ctx, serviceInstance)).To(Succeed())
+		})
+
+		It("records a DELETED event with the details of the service instance and removes it", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(serviceUsageEvents(g, serviceInstance.Name)).To(ConsistOf(
+					MatchFields(IgnoreExtras, Fields{
+						"State": Equal(korifiv1alpha1.ServiceUsageStateCreated),
+					}),
+					MatchFields(IgnoreExtras, Fields{
+						"State":               Equal(korifiv1alpha1.ServiceUsageStateDeleted),
+						"ServiceInstanceGUID": Equal(serviceInstance.Name),
+						"ServiceInstanceName": Equal("service-instance-name"),
+						"ServicePlanName":     Equal("plan-name"),
+					}),
+				))
+
+				err := adminClient.Get(ctx, client.ObjectKeyFromObject(serviceInstance), &korifiv1alpha1.CFServiceInstance{})
+				g.Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			}).Should(Succeed())
+		})
+	})
+
+	When("the service instance is bound to an app", func() {
+		var serviceBinding *korifiv1alpha1.CFServiceBinding
+
+		BeforeEach(func() {
+			serviceBinding = &korifiv1alpha1.CFServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      uuid.NewString(),
+					Namespace: testNamespace,
+				},
+				Spec: korifiv1alpha1.CFServiceBindingSpec{
+					Service: corev1.ObjectReference{
+						Kind:       "CFServiceInstance",
+						APIVersion: "korifi.cloudfoundry.org/v1alpha1",
+						Name:       serviceInstance.Name,
+					},
+					AppRef: corev1.LocalObjectReference{
+						Name: "app-guid",
+					},
+				},
+			}
+			Expect(adminClient.Create(ctx, serviceBinding)).To(Succeed())
+		})
+
+		It("records a BINDING_CREATED event", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(serviceUsageEvents(g, serviceInstance.Name)).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+					"State":               Equal(korifiv1alpha1.ServiceUsageStateBindingCreated),
+					"ServiceInstanceGUID": Equal(serviceInstance.Name),
+					"ServicePlanName":     Equal("plan-name"),
+					"ServiceBindingGUID":  Equal(serviceBinding.Name),
+					"AppGUID":             Equal("app-guid"),
+				})))
+			}).Should(Succeed())
+		})
+
+		When("the binding is deleted", func() {
+			BeforeEach(func() {
+				Eventually(func(g Gomega) {
+					g.Expect(serviceUsageEvents(g, serviceInstance.Name)).To(HaveLen(2))
+				}).Should(Succeed())
+
+				Expect(adminClient.Delete(ctx, serviceBinding)).To(Succeed())
+			})
+
+			It("records a BINDING_DELETED event", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(serviceUsageEvents(g, serviceInstance.Name)).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+						"State":              Equal(korifiv1alpha1.ServiceUsageStateBindingDeleted),
+						"ServiceBindingGUID": Equal(serviceBinding.Name),
+						"AppGUID":            Equal("app-guid"),
+					})))
+				}).Should(Succeed())
+			})
+		})
+	})
+})
+
+func serviceUsageEvents(g Gomega, serviceInstanceGUID string) []korifiv1alpha1.CFServiceUsageEventSpec {
+	eventList := &korifiv1alpha1.CFServiceUsageEventList{}
+	g.Expect(adminClient.List(ctx, eventList, client.InNamespace(rootNamespace))).To(Succeed())
+
+	specs := []korifiv1alpha1.CFServiceUsageEventSpec{}
+	for _, event := range eventList.Items {
+		if event.Spec.ServiceInstanceGUID == serviceInstanceGUID {
+			specs = append(specs, event.Spec)
+		}
+	}
+
+	return specs
+}
@@ -0,0 +1,114 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
+	"github.com/google/uuid"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// recorder records service usage events in the root namespace. The guid of an
+// event is derived from the guid of the service instance or binding and the
+// state, so that recording it again, e.g. when a reconciliation is retried,
+// does not duplicate it.
+type recorder struct {
+	k8sClient     client.Client
+	rootNamespace string
+}
+
+func (r *recorder) record(ctx context.Context, guid string, spec korifiv1alpha1.CFServiceUsageEventSpec) error {
+	spec.Timestamp = metav1.NowMicro()
+
+	err := r.k8sClient.Create(ctx, &korifiv1alpha1.CFServiceUsageEvent{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: r.rootNamespace,
+			Name:      eventName(guid, spec.State),
+		},
+		Spec: spec,
+	})
+	if k8serrors.IsAlreadyExists(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record service usage event: %w", err)
+	}
+
+	return nil
+}
+
+// isGone tells whether the object being deleted only waits for the usage
+// finalizer anymore, i.e. whether its deletion can be recorded. The other
+// finalizers, e.g. the one deprovisioning managed service instances, may
+// still fail and keep the object around.
+func isGone(obj client.Object) bool {
+	return !obj.GetDeletionTimestamp().IsZero() &&
+		slices.Equal(obj.GetFinalizers(), []string{korifiv1alpha1.CFServiceUsageFinalizerName})
+}
+
+// serviceInstanceSpec describes the service instance and, for managed
+// instances, its plan, offering and broker
+func (r *recorder) serviceInstanceSpec(ctx context.Context, serviceInstance *korifiv1alpha1.CFServiceInstance) (korifiv1alpha1.CFServiceUsageEventSpec, error) {
+	spec, err := r.spaceSpec(ctx, serviceInstance.Namespace)
+	if err != nil {
+		return korifiv1alpha1.CFServiceUsageEventSpec{}, err
+	}
+
+	spec.ServiceInstanceGUID = serviceInstance.Name
+	spec.ServiceInstanceName = serviceInstance.Spec.DisplayName
+	spec.ServiceInstanceType = korifiv1alpha1.UserProvidedServiceInstanceUsageType
+	if serviceInstance.Spec.Type != korifiv1alpha1.ManagedType {
+		return spec, nil
+	}
+
+	spec.ServiceInstanceType = korifiv1alpha1.ManagedServiceInstanceUsageType
+	spec.ServicePlanGUID = serviceInstance.Spec.PlanGUID
+
+	servicePlan := &korifiv1alpha1.CFServicePlan{}
+	err = r.k8sClient.Get(ctx, client.ObjectKey{Namespace: r.rootNamespace, Name: serviceInstance.Spec.PlanGUID}, servicePlan)
+	if k8serrors.IsNotFound(err) {
+		// the plan is gone when its broker has been deleted
+		return spec, nil
+	}
+	if err != nil {
+		return korifiv1alpha1.CFServiceUsageEventSpec{}, fmt.Errorf("failed to get service plan %q: %w", serviceInstance.Spec.PlanGUID, err)
+	}
+
+	spec.ServicePlanName = servicePlan.Spec.Name
+	spec.ServiceOfferingGUID = servicePlan.Labels[korifiv1alpha1.RelServiceOfferingGUIDLabel]
+	spec.ServiceOfferingName = servicePlan.Labels[korifiv1alpha1.RelServiceOfferingNameLabel]
+	spec.ServiceBrokerGUID = servicePlan.Labels[korifiv1alpha1.RelServiceBrokerGUIDLabel]
+	spec.ServiceBrokerName = servicePlan.Labels[korifiv1alpha1.RelServiceBrokerNameLabel]
+
+	return spec, nil
+}
+
+func (r *recorder) spaceSpec(ctx context.Context, ns string) (korifiv1alpha1.CFServiceUsageEventSpec, error) {
+	spaces := korifiv1alpha1.CFSpaceList{}
+	if err := r.k8sClient.List(ctx, &spaces, client.MatchingFields{
+		shared.IndexSpaceNamespaceName: ns,
+	}); err != nil {
+		return korifiv1alpha1.CFServiceUsageEventSpec{}, fmt.Errorf("error listing cfSpaces: %w", err)
+	}
+
+	// the events are still recorded when the space is gone, e.g. when its
+	// service instances are deleted while it is being deleted
+	if len(spaces.Items) == 0 {
+		return korifiv1alpha1.CFServiceUsageEventSpec{SpaceGUID: ns}, nil
+	}
+
+	return korifiv1alpha1.CFServiceUsageEventSpec{
+		SpaceGUID: spaces.Items[0].Name,
+		SpaceName: spaces.Items[0].Spec.DisplayName,
+		OrgGUID:   spaces.Items[0].Namespace,
+	}, nil
+}
+
+func eventName(guid, state string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(guid+"/"+state)).String()
+}
@@ -17,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -29,21 +30,29 @@ type EnvValueBuilder interface {
 	BuildEnvValue(context.Context, *korifiv1alpha1.CFApp) (map[string][]byte, error)
 }
 
+type UsageRecorder interface {
+	RecordAppStopped(context.Context, *korifiv1alpha1.CFApp) error
+}
+
 type Reconciler struct {
 	log                       logr.Logger
 	k8sClient                 client.Client
 	scheme                    *runtime.Scheme
 	vcapServicesEnvBuilder    EnvValueBuilder
 	vcapApplicationEnvBuilder EnvValueBuilder
+	usageRecorder             UsageRecorder
+	recorder                  record.EventRecorder
 }
 
-func NewReconciler(k8sClient client.Client, scheme *runtime.Scheme, log logr.Logger, vcapServicesBuilder, vcapApplicationBuilder EnvValueBuilder) *k8s.PatchingReconciler[korifiv1alpha1.CFApp, *korifiv1alpha1.CFApp] {
+func NewReconciler(k8sClient client.Client, scheme *runtime.Scheme, log logr.Logger, vcapServicesBuilder, vcapApplicationBuilder EnvValueBuilder, usageRecorder UsageRecorder, recorder record.EventRecorder) *k8s.PatchingReconciler[korifiv1alpha1.CFApp, *korifiv1alpha1.CFApp] {
 	appReconciler := Reconciler{
 		log:                       log,
 		k8sClient:                 k8sClient,
 		scheme:                    scheme,
 		vcapServicesEnvBuilder:    vcapServicesBuilder,
 		vcapApplicationEnvBuilder: vcapApplicationBuilder,
+		usageRecorder:             usageRecorder,
+		recorder:                  recorder,
 	}
 	return k8s.NewPatchingReconciler[korifiv1alpha1.CFApp, *korifiv1alpha1.CFApp](log, k8sClient, &appReconciler)
 }
@@ -59,6 +68,10 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) *builder.Builder {
 		Watches(
 			&korifiv1alpha1.CFServiceBinding{},
 			handler.EnqueueRequestsFromMapFunc(serviceBindingToApp),
+		).
+		Watches(
+			&korifiv1alpha1.CFRoute{},
+			handler.EnqueueRequestsFromMapFunc(routeToApps),
 		)
 }
 
@@ -94,11 +107,34 @@ func serviceBindingToApp(ctx context.Context, o client.Object) []reconcile.Reque
 	}
 }
 
+// routeToApps reconciles the destination apps of routes, so that their
+// VCAP_APPLICATION lists the URIs of their current routes
+func routeToApps(ctx context.Context, o client.Object) []reconcile.Request {
+	cfRoute, ok := o.(*korifiv1alpha1.CFRoute)
+	if !ok {
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, destination := range cfRoute.Spec.Destinations {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      destination.AppRef.Name,
+				Namespace: o.GetNamespace(),
+			},
+		})
+	}
+
+	return requests
+}
+
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfapps,verbs=get;list;watch;create;update;patch;delete;deletecollection
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfapps/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfapps/finalizers,verbs=update
 
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfappusageevents,verbs=create
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *Reconciler) ReconcileResource(ctx context.Context, cfApp *korifiv1alpha1.CFApp) (ctrl.Result, error) {
 	log := logr.FromContextOrDiscard(ctx)
@@ -106,6 +142,7 @@ func (r *Reconciler) ReconcileResource(ctx context.Context, cfApp *korifiv1alpha
 	cfApp.Status.ObservedGeneration = cfApp.Generation
 	log.V(1).Info("set observed generation", "generation", cfApp.Status.ObservedGeneration)
 
+	previousState := cfApp.Status.ActualState
 	cfApp.Status.ActualState = korifiv1alpha1.StoppedState
 
 	if !cfApp.GetDeletionTimestamp().IsZero() {
@@ -144,20 +181,27 @@ func (r *Reconciler) ReconcileResource(ctx context.Context, cfApp *korifiv1alpha
 	cfApp.Status.VCAPServicesSecretName = secretName
 
 	if cfApp.Spec.CurrentDropletRef.Name == "" {
+		meta.SetStatusCondition(&cfApp.Status.Conditions, stagedCondition(cfApp, metav1.ConditionFalse, "DropletNotAssigned"))
 		return ctrl.Result{}, k8s.NewNotReadyError().WithReason("DropletNotAssigned")
 	}
 
 	droplet, err := r.getDroplet(ctx, cfApp)
 	if err != nil {
+		meta.SetStatusCondition(&cfApp.Status.Conditions, stagedCondition(cfApp, metav1.ConditionFalse, "CannotResolveCurrentDropletRef"))
 		return ctrl.Result{}, k8s.NewNotReadyError().WithReason("CannotResolveCurrentDropletRef")
 	}
+	meta.SetStatusCondition(&cfApp.Status.Conditions, stagedCondition(cfApp, metav1.ConditionTrue, "DropletAssigned"))
 
 	reconciledProcesses, err := r.reconcileProcesses(ctx, cfApp, droplet)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	cfApp.Status.ActualState = getActualState(reconciledProcesses)
+	actualInstances := getActualInstances(reconciledProcesses)
+	cfApp.Status.ActualState = getActualState(actualInstances)
+	r.recordStateChange(cfApp, previousState)
+	meta.SetStatusCondition(&cfApp.Status.Conditions, k8s.InstancesFailingCondition(cfApp, processConditions(reconciledProcesses)...))
+	meta.SetStatusCondition(&cfApp.Status.Conditions, k8s.RunningCondition(cfApp, actualInstances))
 	if cfApp.Status.ActualState != cfApp.Spec.DesiredState {
 		return ctrl.Result{}, k8s.NewNotReadyError().WithReason("DesiredStateNotReached")
 	}
@@ -183,18 +227,54 @@ func (r *Reconciler) serviceBindingsReady(ctx context.Context, cfApp *korifiv1al
 	return true, nil
 }
 
-func getActualState(processes []*korifiv1alpha1.CFProcess) korifiv1alpha1.AppState {
+// recordStateChange records an event on the app when its instances start or
+// all stop running, so that its lifecycle shows up in kubectl describe
+func (r *Reconciler) recordStateChange(cfApp *korifiv1alpha1.CFApp, previousState korifiv1alpha1.AppState) {
+	if previousState == "" || previousState == cfApp.Status.ActualState {
+		return
+	}
+
+	if cfApp.Status.ActualState == korifiv1alpha1.StartedState {
+		r.recorder.Eventf(cfApp, corev1.EventTypeNormal, "Started", "App %s started", cfApp.Spec.DisplayName)
+		return
+	}
+
+	r.recorder.Eventf(cfApp, corev1.EventTypeNormal, "Stopped", "App %s stopped", cfApp.Spec.DisplayName)
+}
+
+func getActualInstances(processes []*korifiv1alpha1.CFProcess) int32 {
 	processInstances := int32(0)
 	for _, p := range processes {
 		processInstances += p.Status.ActualInstances
 	}
+	return processInstances
+}
 
-	if processInstances == 0 {
+func getActualState(actualInstances int32) korifiv1alpha1.AppState {
+	if actualInstances == 0 {
 		return korifiv1alpha1.StoppedState
 	}
 	return korifiv1alpha1.StartedState
 }
 
+// stagedCondition is true when the current droplet of the app can be resolved
+func stagedCondition(cfApp *korifiv1alpha1.CFApp, status metav1.ConditionStatus, reason string) metav1.Condition {
+	return metav1.Condition{
+		Type:               korifiv1alpha1.StagedConditionType,
+		Status:             status,
+		Reason:             reason,
+		ObservedGeneration: cfApp.Generation,
+	}
+}
+
+func processConditions(processes []*korifiv1alpha1.CFProcess) [][]metav1.Condition {
+	conditions := [][]metav1.Condition{}
+	for _, p := range processes {
+		conditions = append(conditions, p.Status.Conditions)
+	}
+	return conditions
+}
+
 func (r *Reconciler) getDroplet(ctx context.Context, cfApp *korifiv1alpha1.CFApp) (*korifiv1alpha1.BuildDropletStatus, error) {
 	log := logr.FromContextOrDiscard(ctx).WithName("getDroplet").WithValues("dropletName", cfApp.Spec.CurrentDropletRef.Name)
 
@@ -326,7 +406,15 @@ func (r *Reconciler) finalizeCFApp(ctx context.Context, cfApp *korifiv1alpha1.CF
 		return ctrl.Result{}, nil
 	}
 
-	err := r.finalizeCFAppRoutes(ctx, cfApp)
+	// the processes are garbage collected with the app, so their usage has to
+	// be stopped here
+	err := r.usageRecorder.RecordAppStopped(ctx, cfApp)
+	if err != nil {
+		log.Info("failed to record the app usage", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	err = r.finalizeCFAppRoutes(ctx, cfApp)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -1,6 +1,8 @@
 package apps_test
 
 import (
+	"encoding/json"
+
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	. "code.cloudfoundry.org/korifi/tests/matchers"
 	"code.cloudfoundry.org/korifi/tools"
@@ -146,6 +148,45 @@ var _ = Describe("CFAppReconciler Integration Tests", func() {
 		}).Should(Succeed())
 	})
 
+	When("a route to the app is added", func() {
+		BeforeEach(func() {
+			cfRoute := &korifiv1alpha1.CFRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      uuid.NewString(),
+					Namespace: testNamespace,
+				},
+				Spec: korifiv1alpha1.CFRouteSpec{
+					Destinations: []korifiv1alpha1.Destination{{
+						GUID:        uuid.NewString(),
+						AppRef:      corev1.LocalObjectReference{Name: cfApp.Name},
+						ProcessType: "web",
+					}},
+				},
+			}
+			Expect(adminClient.Create(ctx, cfRoute)).To(Succeed())
+			Expect(k8s.Patch(ctx, adminClient, cfRoute, func() {
+				cfRoute.Status.URI = "my-app.example.com"
+			})).To(Succeed())
+		})
+
+		It("adds its uri to VCAP_APPLICATION", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
+				g.Expect(cfApp.Status.VCAPApplicationSecretName).NotTo(BeEmpty())
+
+				vcapApplicationSecret := &corev1.Secret{}
+				g.Expect(adminClient.Get(ctx, client.ObjectKey{
+					Namespace: cfApp.Namespace,
+					Name:      cfApp.Status.VCAPApplicationSecretName,
+				}, vcapApplicationSecret)).To(Succeed())
+
+				vcapApplication := map[string]any{}
+				g.Expect(json.Unmarshal(vcapApplicationSecret.Data["VCAP_APPLICATION"], &vcapApplication)).To(Succeed())
+				g.Expect(vcapApplication).To(HaveKeyWithValue("application_uris", ConsistOf("my-app.example.com")))
+			}).Should(Succeed())
+		})
+	})
+
 	It("set status.VCAPServicesSecretName", func() {
 		Eventually(func(g Gomega) {
 			g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
@@ -290,6 +331,61 @@ var _ = Describe("CFAppReconciler Integration Tests", func() {
 				)))
 			}).Should(Succeed())
 		})
+
+		It("sets the staged and running conditions", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
+				g.Expect(cfApp.Status.Conditions).To(ContainElements(
+					SatisfyAll(
+						HasType(Equal(korifiv1alpha1.StagedConditionType)),
+						HasStatus(Equal(metav1.ConditionTrue)),
+					),
+					SatisfyAll(
+						HasType(Equal(korifiv1alpha1.RunningConditionType)),
+						HasStatus(Equal(metav1.ConditionTrue)),
+					),
+				))
+			}).Should(Succeed())
+		})
+
+		It("records a Started event", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(recordedEvents(cfApp)).To(ContainElement("Started"))
+			}).Should(Succeed())
+		})
+	})
+
+	When("the instances of a process are failing", func() {
+		BeforeEach(func() {
+			Eventually(func(g Gomega) {
+				cfProcessList := &korifiv1alpha1.CFProcessList{}
+				g.Expect(adminClient.List(ctx, cfProcessList, &client.ListOptions{
+					Namespace: cfApp.Namespace,
+				})).To(Succeed())
+				g.Expect(cfProcessList.Items).To(HaveLen(1))
+
+				process := &cfProcessList.Items[0]
+				g.Expect(k8s.Patch(ctx, adminClient, process, func() {
+					meta.SetStatusCondition(&process.Status.Conditions, metav1.Condition{
+						Type:    korifiv1alpha1.InstancesFailingConditionType,
+						Status:  metav1.ConditionTrue,
+						Reason:  "Unschedulable",
+						Message: "Instance my-app-web-0 cannot start: 0/3 nodes are available",
+					})
+				})).To(Succeed())
+			}).Should(Succeed())
+		})
+
+		It("sets the instances failing condition on the app", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
+				g.Expect(cfApp.Status.Conditions).To(ContainElement(SatisfyAll(
+					HasType(Equal(korifiv1alpha1.InstancesFailingConditionType)),
+					HasStatus(Equal(metav1.ConditionTrue)),
+					HasReason(Equal("Unschedulable")),
+				)))
+			}).Should(Succeed())
+		})
 	})
 
 	When("the cfapp droplet ref is not set", func() {
@@ -309,6 +405,17 @@ var _ = Describe("CFAppReconciler Integration Tests", func() {
 				)))
 			}).Should(Succeed())
 		})
+
+		It("sets the staged condition to false", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
+				g.Expect(cfApp.Status.Conditions).To(ContainElement(SatisfyAll(
+					HasType(Equal(korifiv1alpha1.StagedConditionType)),
+					HasStatus(Equal(metav1.ConditionFalse)),
+					HasReason(Equal("DropletNotAssigned")),
+				)))
+			}).Should(Succeed())
+		})
 	})
 
 	When("the current droplet does not exist", func() {
@@ -3,6 +3,7 @@ package apps_test
 import (
 	"context"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,6 +11,8 @@ import (
 	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
 	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/apps"
 	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/env"
+	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/usage"
+	controllerfake "code.cloudfoundry.org/korifi/controllers/fake"
 	"code.cloudfoundry.org/korifi/tests/helpers"
 	"code.cloudfoundry.org/korifi/tools/k8s"
 
@@ -19,6 +22,7 @@ import (
 	"go.uber.org/zap/zapcore"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,6 +38,9 @@ var (
 	testEnv         *envtest.Environment
 	adminClient     client.Client
 	testNamespace   string
+	rootNamespace   string
+
+	recordedEventsSync sync.Map
 )
 
 func TestWorkloadsControllers(t *testing.T) {
@@ -67,18 +74,46 @@ var _ = BeforeSuite(func() {
 
 	adminClient, stopClientCache = helpers.NewCachedClient(testEnv.Config)
 
+	rootNamespace = uuid.NewString()
+	Expect(adminClient.Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: rootNamespace,
+		},
+	})).To(Succeed())
+
+	eventRecorder := new(controllerfake.EventRecorder)
+	eventRecorder.EventfStub = func(object runtime.Object, _, reason, _ string, _ ...any) {
+		cfApp := object.(*korifiv1alpha1.CFApp)
+		currentValue, ok := recordedEventsSync.Load(cfApp.Name)
+		reasons := []string{}
+		if ok {
+			reasons = currentValue.([]string)
+		}
+		recordedEventsSync.Store(cfApp.Name, append(reasons, reason))
+	}
+
 	err = apps.NewReconciler(
 		k8sManager.GetClient(),
 		k8sManager.GetScheme(),
 		ctrl.Log.WithName("controllers").WithName("CFApp"),
 		env.NewVCAPServicesEnvValueBuilder(k8sManager.GetClient()),
 		env.NewVCAPApplicationEnvValueBuilder(k8sManager.GetClient(), nil),
+		usage.NewRecorder(k8sManager.GetClient(), rootNamespace),
+		eventRecorder,
 	).SetupWithManager(k8sManager)
 	Expect(err).NotTo(HaveOccurred())
 
 	stopManager = helpers.StartK8sManager(k8sManager)
 })
 
+func recordedEvents(cfApp *korifiv1alpha1.CFApp) []string {
+	reasons, ok := recordedEventsSync.Load(cfApp.Name)
+	if !ok {
+		return nil
+	}
+	return reasons.([]string)
+}
+
 var _ = AfterSuite(func() {
 	stopManager()
 	stopClientCache()
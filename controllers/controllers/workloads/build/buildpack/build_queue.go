@@ -0,0 +1,120 @@
+package buildpack
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	BuildQueuedReason = "BuildQueued"
+
+	// queuedBuildRecheckInterval is how often queued builds check whether
+	// they can start. Builds are not notified when a slot frees up.
+	queuedBuildRecheckInterval = 10 * time.Second
+)
+
+// canStartBuild tells whether the build can start without exceeding the
+// configured concurrent build limits. Pending builds start in the order
+// they were created: a build only starts once all the older pending builds
+// that fit the limits have started. Older builds held back by the limit of
+// their space do not hold back builds of other spaces.
+func (r *buildpackBuildReconciler) canStartBuild(ctx context.Context, cfBuild *korifiv1alpha1.CFBuild) (bool, error) {
+	maxBuilds := r.controllerConfig.MaxConcurrentBuilds
+	maxBuildsPerSpace := r.controllerConfig.MaxConcurrentBuildsPerSpace
+	if maxBuilds <= 0 && maxBuildsPerSpace <= 0 {
+		return true, nil
+	}
+
+	var cfBuilds korifiv1alpha1.CFBuildList
+	if err := r.k8sClient.List(ctx, &cfBuilds); err != nil {
+		return false, fmt.Errorf("failed to list builds: %w", err)
+	}
+
+	running := 0
+	runningPerSpace := map[string]int{}
+	pending := []korifiv1alpha1.CFBuild{*cfBuild}
+	for _, b := range cfBuilds.Items {
+		if b.Spec.Lifecycle.Type != "buildpack" || isSameBuild(b, cfBuild) {
+			continue
+		}
+
+		if isRunning(b) {
+			running++
+			runningPerSpace[b.Namespace]++
+			continue
+		}
+
+		if isPending(b) {
+			pending = append(pending, b)
+		}
+	}
+
+	slices.SortFunc(pending, func(a, b korifiv1alpha1.CFBuild) int {
+		if c := a.CreationTimestamp.Compare(b.CreationTimestamp.Time); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+
+	for _, b := range pending {
+		if maxBuilds > 0 && running >= maxBuilds {
+			return false, nil
+		}
+
+		if maxBuildsPerSpace > 0 && runningPerSpace[b.Namespace] >= maxBuildsPerSpace {
+			if isSameBuild(b, cfBuild) {
+				return false, nil
+			}
+			continue
+		}
+
+		if isSameBuild(b, cfBuild) {
+			return true, nil
+		}
+		running++
+		runningPerSpace[b.Namespace]++
+	}
+
+	return false, nil
+}
+
+func queueBuild(cfBuild *korifiv1alpha1.CFBuild) {
+	meta.SetStatusCondition(&cfBuild.Status.Conditions, metav1.Condition{
+		Type:               korifiv1alpha1.StagingConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             BuildQueuedReason,
+		Message:            "Waiting for other builds to complete",
+		ObservedGeneration: cfBuild.Generation,
+	})
+}
+
+func isQueued(cfBuild korifiv1alpha1.CFBuild) bool {
+	stagingStatus := meta.FindStatusCondition(cfBuild.Status.Conditions, korifiv1alpha1.StagingConditionType)
+	return stagingStatus != nil && stagingStatus.Reason == BuildQueuedReason
+}
+
+func isRunning(cfBuild korifiv1alpha1.CFBuild) bool {
+	return meta.IsStatusConditionTrue(cfBuild.Status.Conditions, korifiv1alpha1.StagingConditionType)
+}
+
+// isPending tells whether the build is waiting to start, i.e. it is either
+// queued or has not been reconciled yet
+func isPending(cfBuild korifiv1alpha1.CFBuild) bool {
+	if cfBuild.Spec.Canceled || meta.FindStatusCondition(cfBuild.Status.Conditions, korifiv1alpha1.SucceededConditionType) != nil {
+		return false
+	}
+
+	return isQueued(cfBuild) || meta.FindStatusCondition(cfBuild.Status.Conditions, korifiv1alpha1.StagingConditionType) == nil
+}
+
+func isSameBuild(b korifiv1alpha1.CFBuild, cfBuild *korifiv1alpha1.CFBuild) bool {
+	return types.NamespacedName{Namespace: b.Namespace, Name: b.Name} == types.NamespacedName{Namespace: cfBuild.Namespace, Name: cfBuild.Name}
+}
@@ -19,6 +19,9 @@ package buildpack
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/controllers/config"
@@ -33,6 +36,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -46,6 +50,11 @@ type BuildpackEnvBuilder interface {
 	Build(context.Context, *korifiv1alpha1.CFApp) ([]corev1.EnvVar, error)
 }
 
+// BlobSigner returns a URL builds can download private package bits from
+type BlobSigner interface {
+	PresignGet(ctx context.Context, spaceGUID string, objectURL string) (string, error)
+}
+
 func NewReconciler(
 	k8sClient client.Client,
 	buildCleaner build.BuildCleaner,
@@ -53,6 +62,9 @@ func NewReconciler(
 	log logr.Logger,
 	controllerConfig *config.ControllerConfig,
 	envBuilder BuildpackEnvBuilder,
+	usageRecorder build.UsageRecorder,
+	recorder record.EventRecorder,
+	blobSigner BlobSigner,
 ) *k8s.PatchingReconciler[korifiv1alpha1.CFBuild, *korifiv1alpha1.CFBuild] {
 	return k8s.NewPatchingReconciler[korifiv1alpha1.CFBuild, *korifiv1alpha1.CFBuild](
 		log,
@@ -62,11 +74,14 @@ func NewReconciler(
 			k8sClient,
 			scheme,
 			buildCleaner,
+			usageRecorder,
+			recorder,
 			&buildpackBuildReconciler{
 				k8sClient:        k8sClient,
 				controllerConfig: controllerConfig,
 				envBuilder:       envBuilder,
 				scheme:           scheme,
+				blobSigner:       blobSigner,
 			},
 		))
 }
@@ -76,6 +91,7 @@ type buildpackBuildReconciler struct {
 	controllerConfig *config.ControllerConfig
 	envBuilder       BuildpackEnvBuilder
 	scheme           *runtime.Scheme
+	blobSigner       BlobSigner
 }
 
 func (r *buildpackBuildReconciler) SetupWithManager(mgr ctrl.Manager) *builder.Builder {
@@ -123,6 +139,7 @@ func buildworkloadToBuild(ctx context.Context, o client.Object) []reconcile.Requ
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=buildworkloads,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=buildworkloads/status,verbs=get
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=buildworkloads/finalizers,verbs=update
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfappusageevents,verbs=create
 
 func (r *buildpackBuildReconciler) ReconcileBuild(
 	ctx context.Context,
@@ -133,8 +150,20 @@ func (r *buildpackBuildReconciler) ReconcileBuild(
 	log := logr.FromContextOrDiscard(ctx)
 
 	stagingStatus := meta.FindStatusCondition(cfBuild.Status.Conditions, korifiv1alpha1.StagingConditionType)
-	if stagingStatus == nil {
-		err := r.createBuildWorkload(ctx, cfBuild, cfApp, cfPackage)
+	if stagingStatus == nil || stagingStatus.Reason == BuildQueuedReason {
+		canStart, err := r.canStartBuild(ctx, cfBuild)
+		if err != nil {
+			log.Info("failed to check the concurrent build limits", "reason", err)
+			return ctrl.Result{}, err
+		}
+
+		if !canStart {
+			log.V(1).Info("concurrent build limit reached, queueing build")
+			queueBuild(cfBuild)
+			return ctrl.Result{RequeueAfter: queuedBuildRecheckInterval}, nil
+		}
+
+		err = r.createBuildWorkload(ctx, cfBuild, cfApp, cfPackage)
 		if err != nil {
 			log.Info("failed to create BuildWorkload", "reason", err)
 			return ctrl.Result{}, err
@@ -205,6 +234,60 @@ func (r *buildpackBuildReconciler) ReconcileBuild(
 	return ctrl.Result{}, nil
 }
 
+// ReconcileStagedBuild picks up droplet updates of succeeded BuildWorkloads,
+// which build reconcilers report when they rebuild the droplet image (e.g. on
+// a patched stack). Apps running the droplet are rolled out by bumping their
+// revision.
+func (r *buildpackBuildReconciler) ReconcileStagedBuild(ctx context.Context, cfBuild *korifiv1alpha1.CFBuild, cfApp *korifiv1alpha1.CFApp) error {
+	log := logr.FromContextOrDiscard(ctx).WithName("reconcileStagedBuild")
+
+	var buildWorkload korifiv1alpha1.BuildWorkload
+	err := r.k8sClient.Get(ctx, client.ObjectKeyFromObject(cfBuild), &buildWorkload)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		log.Info("error when fetching BuildWorkload", "reason", err)
+		return err
+	}
+
+	if !meta.IsStatusConditionTrue(buildWorkload.Status.Conditions, korifiv1alpha1.SucceededConditionType) ||
+		buildWorkload.Status.Droplet == nil ||
+		cfBuild.Status.Droplet == nil ||
+		buildWorkload.Status.Droplet.Registry.Image == cfBuild.Status.Droplet.Registry.Image {
+		return nil
+	}
+
+	log.Info("droplet image has been rebuilt", "image", buildWorkload.Status.Droplet.Registry.Image)
+	cfBuild.Status.Droplet = buildWorkload.Status.Droplet
+
+	if cfApp.Spec.CurrentDropletRef.Name != cfBuild.Name {
+		return nil
+	}
+
+	err = k8s.Patch(ctx, r.k8sClient, cfApp, func() {
+		if cfApp.Annotations == nil {
+			cfApp.Annotations = map[string]string{}
+		}
+		cfApp.Annotations[korifiv1alpha1.CFAppRevisionKey] = bumpAppRev(cfApp.Annotations[korifiv1alpha1.CFAppRevisionKey])
+	})
+	if err != nil {
+		log.Info("failed to bump the app revision", "reason", err)
+		return err
+	}
+
+	return nil
+}
+
+func bumpAppRev(appRev string) string {
+	revValue, err := strconv.Atoi(appRev)
+	if err != nil || revValue < 0 {
+		return korifiv1alpha1.CFAppRevisionKeyDefault
+	}
+
+	return strconv.Itoa(revValue + 1)
+}
+
 func (r *buildpackBuildReconciler) createBuildWorkload(ctx context.Context, cfBuild *korifiv1alpha1.CFBuild, cfApp *korifiv1alpha1.CFApp, cfPackage *korifiv1alpha1.CFPackage) error {
 	log := logr.FromContextOrDiscard(ctx).WithName("createBuildWorkload")
 
@@ -228,11 +311,26 @@ func (r *buildpackBuildReconciler) createBuildWorkload(ctx context.Context, cfBu
 					ImagePullSecrets: cfPackage.Spec.Source.Registry.ImagePullSecrets,
 				},
 			},
-			BuilderName: r.controllerConfig.BuilderName,
-			Buildpacks:  cfBuild.Spec.Lifecycle.Data.Buildpacks,
+			BuilderName:       r.controllerConfig.BuilderName,
+			Buildpacks:        cfBuild.Spec.Lifecycle.Data.Buildpacks,
+			StagingMemoryMB:   cfBuild.Spec.StagingMemoryMB,
+			StagingDiskMB:     cfBuild.Spec.StagingDiskMB,
+			DisableBuildCache: cfApp.Annotations[korifiv1alpha1.DisableBuildCacheAnnotationKey] == "true",
 		},
 	}
 
+	// package bits in a blobstore are private, so the build downloads them
+	// from a presigned URL, which is only issued once the build is about to
+	// start
+	if cfPackage.Spec.Source.Blob != nil {
+		blobURL, err := r.blobSigner.PresignGet(ctx, cfPackage.Namespace, cfPackage.Spec.Source.Blob.URL)
+		if err != nil {
+			log.Info("failed to presign package bits url", "reason", err)
+			return err
+		}
+		desiredWorkload.Spec.Source.Blob = &korifiv1alpha1.Blob{URL: blobURL}
+	}
+
 	buildServices, err := r.prepareBuildServices(ctx, namespace, cfApp.Name)
 	if err != nil {
 		return err
@@ -289,6 +387,29 @@ func (r *buildpackBuildReconciler) prepareBuildServices(ctx context.Context, nam
 		buildServices = append(buildServices, objRef)
 	}
 
+	buildBindingSecrets := &corev1.SecretList{}
+	err = r.k8sClient.List(ctx, buildBindingSecrets,
+		client.InNamespace(namespace),
+		client.MatchingLabels{korifiv1alpha1.BuildBindingLabelKey: "true"},
+	)
+	if err != nil {
+		log.Info("error listing build binding secrets", "reason", err)
+		return nil, err
+	}
+
+	// sort the secrets so that the workload spec is stable
+	slices.SortFunc(buildBindingSecrets.Items, func(a, b corev1.Secret) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for _, secret := range buildBindingSecrets.Items {
+		buildServices = append(buildServices, corev1.ObjectReference{
+			Kind:       "Secret",
+			Name:       secret.Name,
+			APIVersion: "v1",
+		})
+	}
+
 	return buildServices, nil
 }
 
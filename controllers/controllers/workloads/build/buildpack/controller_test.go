@@ -2,6 +2,7 @@ package buildpack_test
 
 import (
 	"context"
+	"time"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/tools"
@@ -13,6 +14,7 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -123,6 +125,8 @@ var _ = Describe("CFBuildpackBuildReconciler Integration Tests", func() {
 				AppRef: corev1.LocalObjectReference{
 					Name: cfApp.Name,
 				},
+				StagingMemoryMB: 2048,
+				StagingDiskMB:   4096,
 				Lifecycle: korifiv1alpha1.Lifecycle{
 					Type: "buildpack",
 					Data: korifiv1alpha1.LifecycleData{
@@ -137,7 +141,7 @@ var _ = Describe("CFBuildpackBuildReconciler Integration Tests", func() {
 		Expect(adminClient.Create(context.Background(), cfBuild)).To(Succeed())
 	})
 
-	It("creates a BuildWorkload with the buildRef, source, env, and buildpacks set", func() {
+	It("creates a BuildWorkload with the buildRef, source, env, buildpacks and staging resources set", func() {
 		eventuallyBuildWorkloadShould(func(workload *korifiv1alpha1.BuildWorkload, g Gomega) {
 			g.Expect(workload.Spec.BuilderName).To(Equal("buildpack-builder-name"))
 			g.Expect(workload.Spec.BuildRef.Name).To(Equal(cfBuild.Name))
@@ -189,6 +193,9 @@ var _ = Describe("CFBuildpackBuildReconciler Integration Tests", func() {
 				}),
 			))
 			g.Expect(workload.Spec.Buildpacks).To(ConsistOf("first-buildpack", "second-buildpack"))
+			g.Expect(workload.Spec.StagingMemoryMB).To(Equal(2048))
+			g.Expect(workload.Spec.StagingDiskMB).To(Equal(4096))
+			g.Expect(workload.Spec.DisableBuildCache).To(BeFalse())
 			g.Expect(workload.GetOwnerReferences()).To(ConsistOf(metav1.OwnerReference{
 				UID:                cfBuild.UID,
 				Kind:               "CFBuild",
@@ -200,6 +207,43 @@ var _ = Describe("CFBuildpackBuildReconciler Integration Tests", func() {
 		})
 	})
 
+	When("the app disables the build cache", func() {
+		BeforeEach(func() {
+			Expect(k8s.Patch(ctx, adminClient, cfApp, func() {
+				cfApp.Annotations = map[string]string{
+					korifiv1alpha1.DisableBuildCacheAnnotationKey: "true",
+				}
+			})).To(Succeed())
+		})
+
+		It("creates a BuildWorkload that disables the build cache", func() {
+			eventuallyBuildWorkloadShould(func(workload *korifiv1alpha1.BuildWorkload, g Gomega) {
+				g.Expect(workload.Spec.DisableBuildCache).To(BeTrue())
+			})
+		})
+	})
+
+	When("the package bits are in the blobstore", func() {
+		var blobURL string
+
+		BeforeEach(func() {
+			blobURL = "https://s3.example.com/packages/" + testNamespace + "/" + cfPackage.Name + ".zip"
+			Expect(k8s.PatchResource(ctx, adminClient, cfPackage, func() {
+				cfPackage.Spec.Source = korifiv1alpha1.PackageSource{
+					Blob: &korifiv1alpha1.Blob{URL: blobURL},
+				}
+			})).To(Succeed())
+		})
+
+		It("creates a BuildWorkload that downloads them from a presigned url", func() {
+			eventuallyBuildWorkloadShould(func(workload *korifiv1alpha1.BuildWorkload, g Gomega) {
+				g.Expect(workload.Spec.Source.Blob).NotTo(BeNil())
+				g.Expect(workload.Spec.Source.Blob.URL).To(HavePrefix(blobURL + "?"))
+				g.Expect(workload.Spec.Source.Blob.URL).To(ContainSubstring("X-Amz-Signature="))
+			})
+		})
+	})
+
 	It("sets the 'build-running' status conditions on CFBuild", func() {
 		Eventually(func(g Gomega) {
 			g.Expect(adminClient.Get(context.Background(), client.ObjectKeyFromObject(cfBuild), cfBuild)).To(Succeed())
@@ -248,6 +292,98 @@ var _ = Describe("CFBuildpackBuildReconciler Integration Tests", func() {
 		})
 	})
 
+	When("the space has build binding secrets", func() {
+		BeforeEach(func() {
+			for _, name := range []string{"npm-registry", "maven-repository"} {
+				Expect(adminClient.Create(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: testNamespace,
+						Labels: map[string]string{
+							korifiv1alpha1.BuildBindingLabelKey: "true",
+						},
+					},
+					StringData: map[string]string{
+						"type": name,
+					},
+				})).To(Succeed())
+			}
+
+			Expect(adminClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "unrelated-secret",
+					Namespace: testNamespace,
+				},
+			})).To(Succeed())
+		})
+
+		It("adds the build binding secrets to the workload services", func() {
+			eventuallyBuildWorkloadShould(func(workload *korifiv1alpha1.BuildWorkload, g Gomega) {
+				g.Expect(workload.Spec.Services).To(Equal([]corev1.ObjectReference{
+					{Kind: "Secret", Name: "maven-repository", APIVersion: "v1"},
+					{Kind: "Secret", Name: "npm-registry", APIVersion: "v1"},
+				}))
+			})
+		})
+	})
+
+	When("the space has reached its concurrent build limit", func() {
+		var runningBuild *korifiv1alpha1.CFBuild
+
+		BeforeEach(func() {
+			runningBuild = cfBuild.DeepCopy()
+			runningBuild.Name = uuid.NewString()
+			Expect(adminClient.Create(ctx, runningBuild)).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(runningBuild), runningBuild)).To(Succeed())
+				g.Expect(meta.IsStatusConditionTrue(runningBuild.Status.Conditions, korifiv1alpha1.StagingConditionType)).To(BeTrue())
+			}).Should(Succeed())
+		})
+
+		It("queues the build", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfBuild), cfBuild)).To(Succeed())
+
+				stagingCondition := meta.FindStatusCondition(cfBuild.Status.Conditions, korifiv1alpha1.StagingConditionType)
+				g.Expect(stagingCondition).NotTo(BeNil())
+				g.Expect(stagingCondition.Status).To(Equal(metav1.ConditionFalse))
+				g.Expect(stagingCondition.Reason).To(Equal("BuildQueued"))
+			}).Should(Succeed())
+
+			Consistently(func(g Gomega) {
+				err := adminClient.Get(ctx, client.ObjectKeyFromObject(cfBuild), new(korifiv1alpha1.BuildWorkload))
+				g.Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			}, "2s").Should(Succeed())
+		})
+
+		When("the running build completes", func() {
+			JustBeforeEach(func() {
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfBuild), cfBuild)).To(Succeed())
+					g.Expect(meta.FindStatusCondition(cfBuild.Status.Conditions, korifiv1alpha1.StagingConditionType)).NotTo(BeNil())
+				}).Should(Succeed())
+
+				runningWorkload := new(korifiv1alpha1.BuildWorkload)
+				Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(runningBuild), runningWorkload)).To(Succeed())
+				Expect(k8s.Patch(ctx, adminClient, runningWorkload, func() {
+					meta.SetStatusCondition(&runningWorkload.Status.Conditions, metav1.Condition{
+						Type:   korifiv1alpha1.SucceededConditionType,
+						Status: metav1.ConditionFalse,
+						Reason: "BuildFailed",
+					})
+				})).To(Succeed())
+			})
+
+			It("starts the queued build", func() {
+				Eventually(func(g Gomega) {
+					workload := new(korifiv1alpha1.BuildWorkload)
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfBuild), workload)).To(Succeed())
+				}).WithTimeout(20 * time.Second).Should(Succeed())
+			})
+		})
+	})
+
 	When("a BuildWorkload with CFBuild GUID already exists", func() {
 		var existingBuildWorkload *korifiv1alpha1.BuildWorkload
 
@@ -382,5 +518,65 @@ var _ = Describe("CFBuildpackBuildReconciler Integration Tests", func() {
 				g.Expect(cfBuild.Status.Droplet.Ports).To(ConsistOf(BeEquivalentTo(42)))
 			}).Should(Succeed())
 		})
+
+		When("the droplet image is rebuilt", func() {
+			var isCurrentDroplet bool
+
+			BeforeEach(func() {
+				isCurrentDroplet = true
+			})
+
+			JustBeforeEach(func() {
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfBuild), cfBuild)).To(Succeed())
+					g.Expect(cfBuild.Status.Droplet).NotTo(BeNil())
+				}).Should(Succeed())
+
+				Expect(k8s.Patch(ctx, adminClient, cfApp, func() {
+					if isCurrentDroplet {
+						cfApp.Spec.CurrentDropletRef.Name = cfBuild.Name
+					}
+					cfApp.Annotations = map[string]string{korifiv1alpha1.CFAppRevisionKey: "3"}
+				})).To(Succeed())
+
+				workload := new(korifiv1alpha1.BuildWorkload)
+				Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfBuild), workload)).To(Succeed())
+				Expect(k8s.Patch(ctx, adminClient, workload, func() {
+					workload.Status.Droplet.Registry.Image = "some-org/my-image@sha256:rebuilt-sha"
+				})).To(Succeed())
+			})
+
+			It("updates CFBuild.status.droplet", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfBuild), cfBuild)).To(Succeed())
+					g.Expect(cfBuild.Status.Droplet.Registry.Image).To(Equal("some-org/my-image@sha256:rebuilt-sha"))
+				}).Should(Succeed())
+			})
+
+			It("bumps the app revision to roll out the rebuilt droplet", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
+					g.Expect(cfApp.Annotations).To(HaveKeyWithValue(korifiv1alpha1.CFAppRevisionKey, "4"))
+				}).Should(Succeed())
+			})
+
+			When("the droplet is not the current droplet of the app", func() {
+				BeforeEach(func() {
+					isCurrentDroplet = false
+				})
+
+				It("does not bump the app revision", func() {
+					Eventually(func(g Gomega) {
+						g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfBuild), cfBuild)).To(Succeed())
+						g.Expect(cfBuild.Status.Droplet.Registry.Image).To(Equal("some-org/my-image@sha256:rebuilt-sha"))
+					}).Should(Succeed())
+
+					Consistently(func(g Gomega) {
+						g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
+						g.Expect(cfApp.Annotations).To(HaveKeyWithValue(korifiv1alpha1.CFAppRevisionKey, "3"))
+					}).Should(Succeed())
+				})
+			})
+		})
 	})
 })
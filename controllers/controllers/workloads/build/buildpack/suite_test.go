@@ -2,6 +2,7 @@ package buildpack_test
 
 import (
 	"context"
+	"net/http"
 	"path/filepath"
 	"testing"
 	"time"
@@ -13,6 +14,8 @@ import (
 	buildfake "code.cloudfoundry.org/korifi/controllers/controllers/workloads/build/fake"
 	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/env"
 	"code.cloudfoundry.org/korifi/tests/helpers"
+	"code.cloudfoundry.org/korifi/tools/s3"
+	"github.com/aws/aws-sdk-go-v2/credentials"
 
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
@@ -68,8 +71,18 @@ var _ = BeforeSuite(func() {
 
 	adminClient, stopClientCache = helpers.NewCachedClient(testEnv.Config)
 
+	blobstore, err := s3.NewClient(
+		http.DefaultClient,
+		credentials.NewStaticCredentialsProvider("the-access-key", "the-secret-key", ""),
+		"https://s3.example.com",
+		"packages",
+		"the-region",
+	)
+	Expect(err).NotTo(HaveOccurred())
+
 	controllerConfig := &config.ControllerConfig{
-		BuilderName: "buildpack-builder-name",
+		BuilderName:                 "buildpack-builder-name",
+		MaxConcurrentBuildsPerSpace: 1,
 	}
 
 	cfBuildpackBuildReconciler := buildpack.NewReconciler(
@@ -78,7 +91,10 @@ var _ = BeforeSuite(func() {
 		k8sManager.GetScheme(),
 		ctrl.Log.WithName("controllers").WithName("CFBuildpackBuild"),
 		controllerConfig,
-		env.NewAppEnvBuilder(k8sManager.GetClient()),
+		env.NewAppEnvBuilder(k8sManager.GetClient(), "cf"),
+		new(buildfake.UsageRecorder),
+		k8sManager.GetEventRecorderFor("cfbuild-controller"),
+		blobstore,
 	)
 	err = (cfBuildpackBuildReconciler).SetupWithManager(k8sManager)
 	Expect(err).NotTo(HaveOccurred())
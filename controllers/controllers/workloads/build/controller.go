@@ -3,14 +3,20 @@ package build
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/metrics"
+	"code.cloudfoundry.org/korifi/tools/k8s"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -27,17 +33,30 @@ type BuildCleaner interface {
 
 type DelegateReconciler interface {
 	ReconcileBuild(context.Context, *korifiv1alpha1.CFBuild, *korifiv1alpha1.CFApp, *korifiv1alpha1.CFPackage) (ctrl.Result, error)
+	// ReconcileStagedBuild keeps the droplet of a successfully staged build up
+	// to date, e.g. when the build system rebases it onto an updated stack
+	ReconcileStagedBuild(context.Context, *korifiv1alpha1.CFBuild, *korifiv1alpha1.CFApp) error
 	SetupWithManager(ctrl.Manager) *builder.Builder
 }
 
+//counterfeiter:generate -o fake -fake-name UsageRecorder . UsageRecorder
+
+type UsageRecorder interface {
+	RecordBuildUsage(context.Context, *korifiv1alpha1.CFApp, *korifiv1alpha1.CFBuild, string) error
+}
+
 type Reconciler struct {
-	log          logr.Logger
-	k8sClient    client.Client
-	scheme       *runtime.Scheme
-	buildCleaner BuildCleaner
-	delegate     DelegateReconciler
+	log           logr.Logger
+	k8sClient     client.Client
+	scheme        *runtime.Scheme
+	buildCleaner  BuildCleaner
+	usageRecorder UsageRecorder
+	recorder      record.EventRecorder
+	delegate      DelegateReconciler
 }
 
+const BuildCanceledReason = "BuildCanceled"
+
 var packageTypeToLifecycleType = map[korifiv1alpha1.PackageType]korifiv1alpha1.LifecycleType{
 	"bits":   "buildpack",
 	"docker": "docker",
@@ -48,14 +67,18 @@ func NewReconciler(
 	k8sClient client.Client,
 	scheme *runtime.Scheme,
 	buildCleaner BuildCleaner,
+	usageRecorder UsageRecorder,
+	recorder record.EventRecorder,
 	delegate DelegateReconciler,
 ) *Reconciler {
 	return &Reconciler{
-		log:          log,
-		k8sClient:    k8sClient,
-		scheme:       scheme,
-		buildCleaner: buildCleaner,
-		delegate:     delegate,
+		log:           log,
+		k8sClient:     k8sClient,
+		scheme:        scheme,
+		buildCleaner:  buildCleaner,
+		usageRecorder: usageRecorder,
+		recorder:      recorder,
+		delegate:      delegate,
 	}
 }
 
@@ -63,7 +86,22 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) *builder.Builder {
 	return r.delegate.SetupWithManager(mgr)
 }
 
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
 func (r *Reconciler) ReconcileResource(ctx context.Context, cfBuild *korifiv1alpha1.CFBuild) (ctrl.Result, error) {
+	completed := meta.FindStatusCondition(cfBuild.Status.Conditions, korifiv1alpha1.SucceededConditionType) != nil
+
+	result, err := r.reconcileBuild(ctx, cfBuild)
+	meta.SetStatusCondition(&cfBuild.Status.Conditions, stagedCondition(cfBuild))
+
+	if !completed {
+		r.recordCompletion(cfBuild)
+	}
+
+	return result, err
+}
+
+func (r *Reconciler) reconcileBuild(ctx context.Context, cfBuild *korifiv1alpha1.CFBuild) (ctrl.Result, error) {
 	log := logr.FromContextOrDiscard(ctx)
 
 	cfBuild.Status.ObservedGeneration = cfBuild.Generation
@@ -84,9 +122,27 @@ func (r *Reconciler) ReconcileResource(ctx context.Context, cfBuild *korifiv1alp
 	succeededStatus := meta.FindStatusCondition(cfBuild.Status.Conditions, korifiv1alpha1.SucceededConditionType)
 	if succeededStatus != nil {
 		log.Info("build status indicates completion", "status", succeededStatus)
+		if err = r.usageRecorder.RecordBuildUsage(ctx, cfApp, cfBuild, korifiv1alpha1.AppUsageStateStagingStopped); err != nil {
+			log.Info("error when recording the staging usage", "reason", err)
+			return ctrl.Result{}, err
+		}
+
+		if succeededStatus.Status == metav1.ConditionTrue {
+			if err = r.setRevisionVersion(ctx, cfBuild, cfApp); err != nil {
+				return ctrl.Result{}, err
+			}
+			if err = r.setCurrentDroplet(ctx, cfBuild, cfApp); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, r.delegate.ReconcileStagedBuild(ctx, cfBuild, cfApp)
+		}
 		return ctrl.Result{}, nil
 	}
 
+	if cfBuild.Spec.Canceled {
+		return ctrl.Result{}, r.handleCancelation(ctx, cfBuild)
+	}
+
 	err = controllerutil.SetControllerReference(cfApp, cfBuild, r.scheme)
 	if err != nil {
 		log.Info("unable to set owner reference on CFBuild", "reason", err)
@@ -120,9 +176,157 @@ func (r *Reconciler) ReconcileResource(ctx context.Context, cfBuild *korifiv1alp
 		return ctrl.Result{}, nil
 	}
 
+	err = r.usageRecorder.RecordBuildUsage(ctx, cfApp, cfBuild, korifiv1alpha1.AppUsageStateStagingStarted)
+	if err != nil {
+		log.Info("error when recording the staging usage", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	if meta.FindStatusCondition(cfBuild.Status.Conditions, korifiv1alpha1.StagingConditionType) == nil {
+		r.recorder.Eventf(cfBuild, corev1.EventTypeNormal, "StagingStarted", "Staging of build %s started", cfBuild.Name)
+	}
+
 	return r.delegate.ReconcileBuild(ctx, cfBuild, cfApp, cfPackage)
 }
 
+// recordCompletion records an event on a build that completed during the
+// reconciliation, so that its outcome shows up in kubectl describe, and
+// observes its staging duration
+func (r *Reconciler) recordCompletion(cfBuild *korifiv1alpha1.CFBuild) {
+	succeededStatus := meta.FindStatusCondition(cfBuild.Status.Conditions, korifiv1alpha1.SucceededConditionType)
+	switch {
+	case succeededStatus == nil:
+	case succeededStatus.Status == metav1.ConditionTrue:
+		r.recorder.Eventf(cfBuild, corev1.EventTypeNormal, "StagingSucceeded", "Build %s staged successfully", cfBuild.Name)
+		metrics.ObserveStaging(cfBuild, metrics.ResultSucceeded)
+	case succeededStatus.Reason == BuildCanceledReason:
+		r.recorder.Eventf(cfBuild, corev1.EventTypeNormal, "StagingCanceled", "Staging of build %s was canceled", cfBuild.Name)
+		metrics.ObserveStaging(cfBuild, metrics.ResultCanceled)
+	default:
+		r.recorder.Eventf(cfBuild, corev1.EventTypeWarning, "StagingFailed", "Staging of build %s failed: %s", cfBuild.Name, succeededStatus.Message)
+		metrics.ObserveStaging(cfBuild, metrics.ResultFailed)
+	}
+}
+
+// stagedCondition mirrors the Succeeded condition of the build, so that
+// builds and apps can be triaged by the same condition type
+func stagedCondition(cfBuild *korifiv1alpha1.CFBuild) metav1.Condition {
+	succeededStatus := meta.FindStatusCondition(cfBuild.Status.Conditions, korifiv1alpha1.SucceededConditionType)
+	if succeededStatus == nil {
+		return metav1.Condition{
+			Type:               korifiv1alpha1.StagedConditionType,
+			Status:             metav1.ConditionFalse,
+			Reason:             "StagingNotCompleted",
+			ObservedGeneration: cfBuild.Generation,
+		}
+	}
+
+	return metav1.Condition{
+		Type:               korifiv1alpha1.StagedConditionType,
+		Status:             succeededStatus.Status,
+		Reason:             succeededStatus.Reason,
+		Message:            succeededStatus.Message,
+		ObservedGeneration: cfBuild.Generation,
+	}
+}
+
+// setCurrentDroplet sets the droplet of a succeeded build as the current
+// droplet of the app when requested via SetCurrentDropletAnnotationKey. The
+// annotation is removed once the droplet is set, so that the droplet is only
+// set once and later changes of the current droplet, e.g. rollbacks, are not
+// undone when the build is reconciled again.
+func (r *Reconciler) setCurrentDroplet(ctx context.Context, cfBuild *korifiv1alpha1.CFBuild, cfApp *korifiv1alpha1.CFApp) error {
+	if cfBuild.Annotations[korifiv1alpha1.SetCurrentDropletAnnotationKey] != "true" {
+		return nil
+	}
+
+	if cfApp.Spec.CurrentDropletRef.Name != cfBuild.Name {
+		err := k8s.Patch(ctx, r.k8sClient, cfApp, func() {
+			cfApp.Spec.CurrentDropletRef.Name = cfBuild.Name
+		})
+		if err != nil {
+			logr.FromContextOrDiscard(ctx).Info("failed to set the current droplet of the app", "reason", err)
+			return err
+		}
+	}
+
+	delete(cfBuild.Annotations, korifiv1alpha1.SetCurrentDropletAnnotationKey)
+
+	return nil
+}
+
+// setRevisionVersion numbers the droplet of a succeeded build as the next
+// revision of the app. The last version is counted on the app with an
+// optimistic lock, so that concurrently staged builds get distinct versions.
+func (r *Reconciler) setRevisionVersion(ctx context.Context, cfBuild *korifiv1alpha1.CFBuild, cfApp *korifiv1alpha1.CFApp) error {
+	if _, ok := cfBuild.Annotations[korifiv1alpha1.RevisionVersionAnnotationKey]; ok {
+		return nil
+	}
+
+	lastVersion := 0
+	if lastVersionValue, ok := cfApp.Annotations[korifiv1alpha1.LastRevisionVersionAnnotationKey]; ok {
+		var err error
+		lastVersion, err = strconv.Atoi(lastVersionValue)
+		if err != nil {
+			return fmt.Errorf("expected the last revision version of the app to be an integer: %w", err)
+		}
+	}
+	version := strconv.Itoa(lastVersion + 1)
+
+	err := k8s.PatchResourceWithOptimisticLock(ctx, r.k8sClient, cfApp, func() {
+		if cfApp.Annotations == nil {
+			cfApp.Annotations = map[string]string{}
+		}
+		cfApp.Annotations[korifiv1alpha1.LastRevisionVersionAnnotationKey] = version
+	})
+	if err != nil {
+		logr.FromContextOrDiscard(ctx).Info("failed to count the revision of the app", "reason", err)
+		return err
+	}
+
+	if cfBuild.Annotations == nil {
+		cfBuild.Annotations = map[string]string{}
+	}
+	cfBuild.Annotations[korifiv1alpha1.RevisionVersionAnnotationKey] = version
+
+	return nil
+}
+
+// handleCancelation deletes the BuildWorkload of the build, which stops its
+// staging pods, and fails the build
+func (r *Reconciler) handleCancelation(ctx context.Context, cfBuild *korifiv1alpha1.CFBuild) error {
+	log := logr.FromContextOrDiscard(ctx).WithName("handleCancelation")
+
+	buildWorkload := &korifiv1alpha1.BuildWorkload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cfBuild.Name,
+			Namespace: cfBuild.Namespace,
+		},
+	}
+	err := r.k8sClient.Delete(ctx, buildWorkload)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		log.Info("error deleting BuildWorkload", "reason", err)
+		return err
+	}
+
+	meta.SetStatusCondition(&cfBuild.Status.Conditions, metav1.Condition{
+		Type:               korifiv1alpha1.StagingConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             "BuildNotRunning",
+		ObservedGeneration: cfBuild.Generation,
+	})
+
+	meta.SetStatusCondition(&cfBuild.Status.Conditions, metav1.Condition{
+		Type:               korifiv1alpha1.SucceededConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             BuildCanceledReason,
+		Message:            "The build has been canceled",
+		ObservedGeneration: cfBuild.Generation,
+	})
+
+	return nil
+}
+
 func validateLifecycleTypes(
 	cfApp *korifiv1alpha1.CFApp,
 	cfPackage *korifiv1alpha1.CFPackage,
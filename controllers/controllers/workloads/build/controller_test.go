@@ -2,12 +2,14 @@ package build_test
 
 import (
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/build"
 	"code.cloudfoundry.org/korifi/tools/k8s"
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -30,6 +32,15 @@ var _ = Describe("CFBuildReconciler", func() {
 		return result
 	}
 
+	stagedBuilds := func() map[string]int {
+		result := map[string]int{}
+		stagedBuildsSync.Range(func(k, v any) bool {
+			result[k.(string)] = v.(int)
+			return true
+		})
+		return result
+	}
+
 	buildCleanups := func() map[types.NamespacedName]int {
 		result := map[types.NamespacedName]int{}
 		buildCleanupsSync.Range(func(k, v any) bool {
@@ -39,6 +50,24 @@ var _ = Describe("CFBuildReconciler", func() {
 		return result
 	}
 
+	recordedUsages := func() map[string][]string {
+		result := map[string][]string{}
+		recordedUsagesSync.Range(func(k, v any) bool {
+			result[k.(string)] = v.([]string)
+			return true
+		})
+		return result
+	}
+
+	recordedEvents := func() map[string][]string {
+		result := map[string][]string{}
+		recordedEventsSync.Range(func(k, v any) bool {
+			result[k.(string)] = v.([]string)
+			return true
+		})
+		return result
+	}
+
 	BeforeEach(func() {
 		cfApp = &korifiv1alpha1.CFApp{
 			ObjectMeta: metav1.ObjectMeta{
@@ -126,6 +155,19 @@ var _ = Describe("CFBuildReconciler", func() {
 		}).Should(Succeed())
 	})
 
+	It("records the staging usage", func() {
+		Eventually(func(g Gomega) {
+			g.Expect(recordedUsages()).To(HaveKeyWithValue(cfBuild.Name, ContainElement(korifiv1alpha1.AppUsageStateStagingStarted)))
+		}).Should(Succeed())
+		Expect(recordedUsages()[cfBuild.Name]).NotTo(ContainElement(korifiv1alpha1.AppUsageStateStagingStopped))
+	})
+
+	It("records a StagingStarted event", func() {
+		Eventually(func(g Gomega) {
+			g.Expect(recordedEvents()).To(HaveKeyWithValue(cfBuild.Name, ContainElement("StagingStarted")))
+		}).Should(Succeed())
+	})
+
 	Describe("package type and build type mismatch", func() {
 		When("the package type is bits and build type is docker", func() {
 			BeforeEach(func() {
@@ -141,6 +183,12 @@ var _ = Describe("CFBuildReconciler", func() {
 					g.Expect(meta.IsStatusConditionFalse(cfBuild.Status.Conditions, korifiv1alpha1.StagingConditionType)).To(BeTrue())
 				}).Should(Succeed())
 			})
+
+			It("records a StagingFailed event", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(recordedEvents()).To(HaveKeyWithValue(cfBuild.Name, ConsistOf("StagingFailed")))
+				}).Should(Succeed())
+			})
 		})
 
 		When("the package type is docker and build type is buildpack", func() {
@@ -194,6 +242,58 @@ var _ = Describe("CFBuildReconciler", func() {
 		})
 	})
 
+	When("the build is canceled", func() {
+		var buildWorkload *korifiv1alpha1.BuildWorkload
+
+		BeforeEach(func() {
+			cfBuild.Spec.Canceled = true
+
+			buildWorkload = &korifiv1alpha1.BuildWorkload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNamespace,
+					Name:      cfBuild.Name,
+				},
+				Spec: korifiv1alpha1.BuildWorkloadSpec{
+					BuildRef: korifiv1alpha1.RequiredLocalObjectReference{
+						Name: cfBuild.Name,
+					},
+					BuilderName: "some-builder",
+				},
+			}
+			Expect(adminClient.Create(ctx, buildWorkload)).To(Succeed())
+		})
+
+		It("fails the build", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfBuild), cfBuild)).To(Succeed())
+				g.Expect(meta.IsStatusConditionFalse(cfBuild.Status.Conditions, korifiv1alpha1.StagingConditionType)).To(BeTrue())
+				succeededCondition := meta.FindStatusCondition(cfBuild.Status.Conditions, korifiv1alpha1.SucceededConditionType)
+				g.Expect(succeededCondition).NotTo(BeNil())
+				g.Expect(succeededCondition.Status).To(Equal(metav1.ConditionFalse))
+				g.Expect(succeededCondition.Reason).To(Equal(build.BuildCanceledReason))
+			}).Should(Succeed())
+		})
+
+		It("records a StagingCanceled event", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(recordedEvents()).To(HaveKeyWithValue(cfBuild.Name, ConsistOf("StagingCanceled")))
+			}).Should(Succeed())
+		})
+
+		It("deletes the build workload", func() {
+			Eventually(func(g Gomega) {
+				err := adminClient.Get(ctx, client.ObjectKeyFromObject(buildWorkload), buildWorkload)
+				g.Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			}).Should(Succeed())
+		})
+
+		It("does not delegate the reconciliation", func() {
+			Consistently(func(g Gomega) {
+				g.Expect(reconciledBuilds()).NotTo(HaveKey(cfBuild.Name))
+			}).Should(Succeed())
+		})
+	})
+
 	When("the build succeeds", func() {
 		JustBeforeEach(func() {
 			Eventually(func(g Gomega) {
@@ -213,11 +313,128 @@ var _ = Describe("CFBuildReconciler", func() {
 			})).To(Succeed())
 		})
 
+		It("records the end of the staging usage", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(recordedUsages()).To(HaveKeyWithValue(cfBuild.Name, ContainElement(korifiv1alpha1.AppUsageStateStagingStopped)))
+			}).Should(Succeed())
+		})
+
+		It("sets the staged condition", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfBuild), cfBuild)).To(Succeed())
+				stagedCondition := meta.FindStatusCondition(cfBuild.Status.Conditions, korifiv1alpha1.StagedConditionType)
+				g.Expect(stagedCondition).NotTo(BeNil())
+				g.Expect(stagedCondition.Status).To(Equal(metav1.ConditionTrue))
+				g.Expect(stagedCondition.ObservedGeneration).To(Equal(cfBuild.Generation))
+			}).Should(Succeed())
+		})
+
+		It("keeps reconciling the staged build", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(stagedBuilds()).To(HaveKey(cfBuild.Name))
+			}).Should(Succeed())
+		})
+
 		It("stops reconciling", func() {
 			reoncileCount := reconciledBuilds()[cfBuild.Name]
 			Consistently(func(g Gomega) {
 				g.Expect(reconciledBuilds()[cfBuild.Name]).To(Equal(reoncileCount))
 			}).Should(Succeed())
 		})
+
+		It("numbers the droplet as the first revision of the app", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfBuild), cfBuild)).To(Succeed())
+				g.Expect(cfBuild.Annotations).To(HaveKeyWithValue(korifiv1alpha1.RevisionVersionAnnotationKey, "1"))
+
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
+				g.Expect(cfApp.Annotations).To(HaveKeyWithValue(korifiv1alpha1.LastRevisionVersionAnnotationKey, "1"))
+			}).Should(Succeed())
+		})
+
+		When("the app already has revisions", func() {
+			BeforeEach(func() {
+				cfApp.Annotations = map[string]string{
+					korifiv1alpha1.LastRevisionVersionAnnotationKey: "41",
+				}
+			})
+
+			It("numbers the droplet as the next revision of the app", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfBuild), cfBuild)).To(Succeed())
+					g.Expect(cfBuild.Annotations).To(HaveKeyWithValue(korifiv1alpha1.RevisionVersionAnnotationKey, "42"))
+				}).Should(Succeed())
+			})
+		})
+
+		When("the droplet has already been numbered", func() {
+			BeforeEach(func() {
+				cfBuild.Annotations = map[string]string{
+					korifiv1alpha1.RevisionVersionAnnotationKey: "7",
+				}
+			})
+
+			It("keeps its revision version", func() {
+				Consistently(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfBuild), cfBuild)).To(Succeed())
+					g.Expect(cfBuild.Annotations).To(HaveKeyWithValue(korifiv1alpha1.RevisionVersionAnnotationKey, "7"))
+
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
+					g.Expect(cfApp.Annotations).NotTo(HaveKey(korifiv1alpha1.LastRevisionVersionAnnotationKey))
+				}).Should(Succeed())
+			})
+		})
+
+		It("does not set the current droplet of the app", func() {
+			Consistently(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
+				g.Expect(cfApp.Spec.CurrentDropletRef.Name).To(BeEmpty())
+			}).Should(Succeed())
+		})
+
+		When("the build requests to set the current droplet", func() {
+			BeforeEach(func() {
+				cfBuild.Annotations = map[string]string{
+					korifiv1alpha1.SetCurrentDropletAnnotationKey: "true",
+				}
+			})
+
+			It("sets the build as the current droplet of the app", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
+					g.Expect(cfApp.Spec.CurrentDropletRef.Name).To(Equal(cfBuild.Name))
+				}).Should(Succeed())
+			})
+
+			It("removes the annotation from the build", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfBuild), cfBuild)).To(Succeed())
+					g.Expect(cfBuild.Annotations).NotTo(HaveKey(korifiv1alpha1.SetCurrentDropletAnnotationKey))
+				}).Should(Succeed())
+			})
+
+			When("another droplet is set as the current droplet afterwards", func() {
+				BeforeEach(func() {
+					Eventually(func(g Gomega) {
+						g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfBuild), cfBuild)).To(Succeed())
+						g.Expect(cfBuild.Annotations).NotTo(HaveKey(korifiv1alpha1.SetCurrentDropletAnnotationKey))
+					}).Should(Succeed())
+
+					Expect(k8s.PatchResource(ctx, adminClient, cfApp, func() {
+						cfApp.Spec.CurrentDropletRef.Name = "another-droplet"
+					})).To(Succeed())
+					Expect(k8s.PatchResource(ctx, adminClient, cfBuild, func() {
+						cfBuild.Labels = map[string]string{"reconcile": "again"}
+					})).To(Succeed())
+				})
+
+				It("does not set the build as the current droplet again", func() {
+					Consistently(func(g Gomega) {
+						g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
+						g.Expect(cfApp.Spec.CurrentDropletRef.Name).To(Equal("another-droplet"))
+					}).Should(Succeed())
+				})
+			})
+		})
 	})
 })
@@ -29,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -45,6 +46,8 @@ func NewReconciler(
 	imageConfigGetter ImageConfigGetter,
 	scheme *runtime.Scheme,
 	log logr.Logger,
+	usageRecorder build.UsageRecorder,
+	recorder record.EventRecorder,
 ) *k8s.PatchingReconciler[korifiv1alpha1.CFBuild, *korifiv1alpha1.CFBuild] {
 	return k8s.NewPatchingReconciler[korifiv1alpha1.CFBuild, *korifiv1alpha1.CFBuild](
 		log,
@@ -54,6 +57,8 @@ func NewReconciler(
 			k8sClient,
 			scheme,
 			buildCleaner,
+			usageRecorder,
+			recorder,
 			&dockerBuildReconciler{
 				k8sClient:         k8sClient,
 				imageConfigGetter: imageConfigGetter,
@@ -85,6 +90,7 @@ func dockerBuildFilter(object client.Object) bool {
 // +kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfbuilds,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfbuilds/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfbuilds/finalizers,verbs=update
+// +kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfappusageevents,verbs=create
 func (r *dockerBuildReconciler) ReconcileBuild(
 	ctx context.Context,
 	cfBuild *korifiv1alpha1.CFBuild,
@@ -169,6 +175,12 @@ func (r *dockerBuildReconciler) ReconcileBuild(
 	return ctrl.Result{}, nil
 }
 
+// ReconcileStagedBuild is a no-op as docker droplets are never updated once
+// staged
+func (r *dockerBuildReconciler) ReconcileStagedBuild(context.Context, *korifiv1alpha1.CFBuild, *korifiv1alpha1.CFApp) error {
+	return nil
+}
+
 func isRoot(user string) bool {
 	user = strings.Split(user, ":")[0]
 	return user == "" || user == "root" || user == "0"
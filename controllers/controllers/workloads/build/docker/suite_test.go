@@ -81,6 +81,8 @@ var _ = BeforeSuite(func() {
 		image.NewClient(k8sClient),
 		k8sManager.GetScheme(),
 		ctrl.Log.WithName("controllers").WithName("CFDockerBuild"),
+		new(buildfake.UsageRecorder),
+		k8sManager.GetEventRecorderFor("cfbuild-controller"),
 	).SetupWithManager(k8sManager)
 	Expect(err).NotTo(HaveOccurred())
 
@@ -29,6 +29,19 @@ type DelegateReconciler struct {
 		result1 reconcile.Result
 		result2 error
 	}
+	ReconcileStagedBuildStub        func(context.Context, *v1alpha1.CFBuild, *v1alpha1.CFApp) error
+	reconcileStagedBuildMutex       sync.RWMutex
+	reconcileStagedBuildArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1alpha1.CFBuild
+		arg3 *v1alpha1.CFApp
+	}
+	reconcileStagedBuildReturns struct {
+		result1 error
+	}
+	reconcileStagedBuildReturnsOnCall map[int]struct {
+		result1 error
+	}
 	SetupWithManagerStub        func(manager.Manager) *builder.Builder
 	setupWithManagerMutex       sync.RWMutex
 	setupWithManagerArgsForCall []struct {
@@ -111,6 +124,69 @@ func (fake *DelegateReconciler) ReconcileBuildReturnsOnCall(i int, result1 recon
 	}{result1, result2}
 }
 
+func (fake *DelegateReconciler) ReconcileStagedBuild(arg1 context.Context, arg2 *v1alpha1.CFBuild, arg3 *v1alpha1.CFApp) error {
+	fake.reconcileStagedBuildMutex.Lock()
+	ret, specificReturn := fake.reconcileStagedBuildReturnsOnCall[len(fake.reconcileStagedBuildArgsForCall)]
+	fake.reconcileStagedBuildArgsForCall = append(fake.reconcileStagedBuildArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1alpha1.CFBuild
+		arg3 *v1alpha1.CFApp
+	}{arg1, arg2, arg3})
+	stub := fake.ReconcileStagedBuildStub
+	fakeReturns := fake.reconcileStagedBuildReturns
+	fake.recordInvocation("ReconcileStagedBuild", []interface{}{arg1, arg2, arg3})
+	fake.reconcileStagedBuildMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *DelegateReconciler) ReconcileStagedBuildCallCount() int {
+	fake.reconcileStagedBuildMutex.RLock()
+	defer fake.reconcileStagedBuildMutex.RUnlock()
+	return len(fake.reconcileStagedBuildArgsForCall)
+}
+
+func (fake *DelegateReconciler) ReconcileStagedBuildCalls(stub func(context.Context, *v1alpha1.CFBuild, *v1alpha1.CFApp) error) {
+	fake.reconcileStagedBuildMutex.Lock()
+	defer fake.reconcileStagedBuildMutex.Unlock()
+	fake.ReconcileStagedBuildStub = stub
+}
+
+func (fake *DelegateReconciler) ReconcileStagedBuildArgsForCall(i int) (context.Context, *v1alpha1.CFBuild, *v1alpha1.CFApp) {
+	fake.reconcileStagedBuildMutex.RLock()
+	defer fake.reconcileStagedBuildMutex.RUnlock()
+	argsForCall := fake.reconcileStagedBuildArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *DelegateReconciler) ReconcileStagedBuildReturns(result1 error) {
+	fake.reconcileStagedBuildMutex.Lock()
+	defer fake.reconcileStagedBuildMutex.Unlock()
+	fake.ReconcileStagedBuildStub = nil
+	fake.reconcileStagedBuildReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *DelegateReconciler) ReconcileStagedBuildReturnsOnCall(i int, result1 error) {
+	fake.reconcileStagedBuildMutex.Lock()
+	defer fake.reconcileStagedBuildMutex.Unlock()
+	fake.ReconcileStagedBuildStub = nil
+	if fake.reconcileStagedBuildReturnsOnCall == nil {
+		fake.reconcileStagedBuildReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.reconcileStagedBuildReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *DelegateReconciler) SetupWithManager(arg1 manager.Manager) *builder.Builder {
 	fake.setupWithManagerMutex.Lock()
 	ret, specificReturn := fake.setupWithManagerReturnsOnCall[len(fake.setupWithManagerArgsForCall)]
@@ -177,6 +253,8 @@ func (fake *DelegateReconciler) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.reconcileBuildMutex.RLock()
 	defer fake.reconcileBuildMutex.RUnlock()
+	fake.reconcileStagedBuildMutex.RLock()
+	defer fake.reconcileStagedBuildMutex.RUnlock()
 	fake.setupWithManagerMutex.RLock()
 	defer fake.setupWithManagerMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
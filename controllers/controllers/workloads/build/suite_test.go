@@ -11,6 +11,7 @@ import (
 	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
 	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/build"
 	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/build/fake"
+	controllerfake "code.cloudfoundry.org/korifi/controllers/fake"
 	"code.cloudfoundry.org/korifi/tests/helpers"
 	"code.cloudfoundry.org/korifi/tools/k8s"
 
@@ -22,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -42,7 +44,10 @@ var (
 	testNamespace   string
 
 	reconciledBuildsSync sync.Map
+	stagedBuildsSync     sync.Map
 	buildCleanupsSync    sync.Map
+	recordedUsagesSync   sync.Map
+	recordedEventsSync   sync.Map
 )
 
 func TestWorkloadsControllers(t *testing.T) {
@@ -105,6 +110,17 @@ var _ = BeforeSuite(func() {
 		return reconcile.Result{}, nil
 	}
 
+	stagedBuildsSync = sync.Map{}
+	delegateReconciler.ReconcileStagedBuildStub = func(_ context.Context, cfBuild *korifiv1alpha1.CFBuild, _ *korifiv1alpha1.CFApp) error {
+		currentValue, ok := stagedBuildsSync.Load(cfBuild.Name)
+		currentCount := 0
+		if ok {
+			currentCount = currentValue.(int)
+		}
+		stagedBuildsSync.Store(cfBuild.Name, currentCount+1)
+		return nil
+	}
+
 	buildCleanupsSync = sync.Map{}
 	buildCleaner := new(fake.BuildCleaner)
 	buildCleaner.CleanStub = func(_ context.Context, nsname types.NamespacedName) error {
@@ -117,6 +133,30 @@ var _ = BeforeSuite(func() {
 		return nil
 	}
 
+	recordedUsagesSync = sync.Map{}
+	usageRecorder := new(fake.UsageRecorder)
+	usageRecorder.RecordBuildUsageStub = func(_ context.Context, _ *korifiv1alpha1.CFApp, cfBuild *korifiv1alpha1.CFBuild, state string) error {
+		currentValue, ok := recordedUsagesSync.Load(cfBuild.Name)
+		states := []string{}
+		if ok {
+			states = currentValue.([]string)
+		}
+		recordedUsagesSync.Store(cfBuild.Name, append(states, state))
+		return nil
+	}
+
+	recordedEventsSync = sync.Map{}
+	eventRecorder := new(controllerfake.EventRecorder)
+	eventRecorder.EventfStub = func(object runtime.Object, _, reason, _ string, _ ...any) {
+		cfBuild := object.(*korifiv1alpha1.CFBuild)
+		currentValue, ok := recordedEventsSync.Load(cfBuild.Name)
+		reasons := []string{}
+		if ok {
+			reasons = currentValue.([]string)
+		}
+		recordedEventsSync.Store(cfBuild.Name, append(reasons, reason))
+	}
+
 	Expect(k8s.NewPatchingReconciler[korifiv1alpha1.CFBuild, *korifiv1alpha1.CFBuild](
 		ctrl.Log.WithName("controllers").WithName("CFBuild"),
 		k8sManager.GetClient(),
@@ -125,6 +165,8 @@ var _ = BeforeSuite(func() {
 			k8sManager.GetClient(),
 			scheme.Scheme,
 			buildCleaner,
+			usageRecorder,
+			eventRecorder,
 			delegateReconciler,
 		),
 	).SetupWithManager(k8sManager)).To(Succeed())
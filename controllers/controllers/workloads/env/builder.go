@@ -2,6 +2,7 @@ package env
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"slices"
 	"strconv"
@@ -12,6 +13,7 @@ import (
 	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/ports"
 
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -22,6 +24,7 @@ type ServiceDetails struct {
 	Label          string         `json:"label"`
 	Name           string         `json:"name"`
 	Tags           []string       `json:"tags"`
+	Plan           string         `json:"plan,omitempty"`
 	InstanceGUID   string         `json:"instance_guid"`
 	InstanceName   string         `json:"instance_name"`
 	BindingGUID    string         `json:"binding_guid"`
@@ -32,14 +35,29 @@ type ServiceDetails struct {
 }
 
 type AppEnvBuilder struct {
-	k8sClient client.Client
+	k8sClient       client.Client
+	rootNamespace   string
+	envVarGroupName string
 }
 
-func NewAppEnvBuilder(k8sClient client.Client) *AppEnvBuilder {
-	return &AppEnvBuilder{k8sClient: k8sClient}
+func NewAppEnvBuilder(k8sClient client.Client, rootNamespace string) *AppEnvBuilder {
+	return &AppEnvBuilder{
+		k8sClient:       k8sClient,
+		rootNamespace:   rootNamespace,
+		envVarGroupName: korifiv1alpha1.RunningEnvVarGroupName,
+	}
 }
 
 func (b *AppEnvBuilder) Build(ctx context.Context, cfApp *korifiv1alpha1.CFApp) ([]corev1.EnvVar, error) {
+	envVarGroup, err := b.EnvVarGroup(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.build(ctx, cfApp, envVarGroup)
+}
+
+func (b *AppEnvBuilder) build(ctx context.Context, cfApp *korifiv1alpha1.CFApp, envVarGroup map[string]string) ([]corev1.EnvVar, error) {
 	var appEnvSecret, vcapServicesSecret, vcapApplicationSecret corev1.Secret
 
 	if cfApp.Spec.EnvSecretName != "" {
@@ -64,7 +82,35 @@ func (b *AppEnvBuilder) Build(ctx context.Context, cfApp *korifiv1alpha1.CFApp)
 	}
 
 	// We explicitly order the vcapServicesSecret last so that its "VCAP_*" contents win
-	return sortEnvVars(envVarsFromSecrets(appEnvSecret, vcapServicesSecret, vcapApplicationSecret)), nil
+	env := envVarsFromSecrets(appEnvSecret, vcapServicesSecret, vcapApplicationSecret)
+
+	var groupEnv []corev1.EnvVar
+	for k, v := range envVarGroup {
+		groupEnv = append(groupEnv, corev1.EnvVar{Name: k, Value: v})
+	}
+
+	// The app env vars override the ones of the env var group
+	groupEnv = slices.DeleteFunc(groupEnv, func(groupEnvVar corev1.EnvVar) bool {
+		return slices.ContainsFunc(env, func(envVar corev1.EnvVar) bool {
+			return envVar.Name == groupEnvVar.Name
+		})
+	})
+
+	return sortEnvVars(append(env, groupEnv...)), nil
+}
+
+// EnvVarGroup returns the env vars of the env var group of the builder
+func (b *AppEnvBuilder) EnvVarGroup(ctx context.Context) (map[string]string, error) {
+	var envVarGroup corev1.ConfigMap
+	err := b.k8sClient.Get(ctx, types.NamespacedName{Namespace: b.rootNamespace, Name: b.envVarGroupName}, &envVarGroup)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error when trying to fetch env var group ConfigMap %s/%s: %w", b.rootNamespace, b.envVarGroupName, err)
+	}
+
+	return envVarGroup.Data, nil
 }
 
 func sortEnvVars(envVars []corev1.EnvVar) []corev1.EnvVar {
@@ -93,20 +139,72 @@ func envVarsFromSecrets(secrets ...corev1.Secret) []corev1.EnvVar {
 	return envVars
 }
 
+// BuildEnvBuilder builds the env of the app builds. It uses the staging env
+// var group rather than the running one and, on top of the app env, it sets
+// the staging-only env vars of the app, which are never set on the app
+// processes.
+type BuildEnvBuilder struct {
+	appEnvBuilder *AppEnvBuilder
+	k8sClient     client.Client
+}
+
+func NewBuildEnvBuilder(k8sClient client.Client, rootNamespace string) *BuildEnvBuilder {
+	return &BuildEnvBuilder{
+		appEnvBuilder: &AppEnvBuilder{
+			k8sClient:       k8sClient,
+			rootNamespace:   rootNamespace,
+			envVarGroupName: korifiv1alpha1.StagingEnvVarGroupName,
+		},
+		k8sClient: k8sClient,
+	}
+}
+
+func (b *BuildEnvBuilder) Build(ctx context.Context, cfApp *korifiv1alpha1.CFApp) ([]corev1.EnvVar, error) {
+	env, err := b.appEnvBuilder.Build(ctx, cfApp)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfApp.Spec.StagingEnvSecretName == "" {
+		return env, nil
+	}
+
+	var stagingEnvSecret corev1.Secret
+	err = b.k8sClient.Get(ctx, types.NamespacedName{Namespace: cfApp.Namespace, Name: cfApp.Spec.StagingEnvSecretName}, &stagingEnvSecret)
+	if err != nil {
+		return nil, fmt.Errorf("error when trying to fetch app staging env Secret %s/%s: %w", cfApp.Namespace, cfApp.Spec.StagingEnvSecretName, err)
+	}
+
+	env = slices.DeleteFunc(env, func(envVar corev1.EnvVar) bool {
+		_, isStagingEnvVar := stagingEnvSecret.Data[envVar.Name]
+		return isStagingEnvVar
+	})
+
+	return sortEnvVars(append(env, envVarsFromSecrets(stagingEnvSecret)...)), nil
+}
+
 type ProcessEnvBuilder struct {
 	appEnvBuilder *AppEnvBuilder
 	k8sClient     client.Client
 }
 
-func NewProcessEnvBuilder(k8sClient client.Client) *ProcessEnvBuilder {
+func NewProcessEnvBuilder(k8sClient client.Client, rootNamespace string) *ProcessEnvBuilder {
 	return &ProcessEnvBuilder{
-		appEnvBuilder: NewAppEnvBuilder(k8sClient),
+		appEnvBuilder: NewAppEnvBuilder(k8sClient, rootNamespace),
 		k8sClient:     k8sClient,
 	}
 }
 
-func (b *ProcessEnvBuilder) Build(ctx context.Context, cfApp *korifiv1alpha1.CFApp, cfProcess *korifiv1alpha1.CFProcess) ([]corev1.EnvVar, error) {
-	env, err := b.appEnvBuilder.Build(ctx, cfApp)
+// EnvVarGroup returns the env vars of the running env var group
+func (b *ProcessEnvBuilder) EnvVarGroup(ctx context.Context) (map[string]string, error) {
+	return b.appEnvBuilder.EnvVarGroup(ctx)
+}
+
+// Build builds the env of the process with the given running env var group,
+// which the caller snapshots, so that changing the group only affects the
+// process on its next restart
+func (b *ProcessEnvBuilder) Build(ctx context.Context, cfApp *korifiv1alpha1.CFApp, cfProcess *korifiv1alpha1.CFProcess, envVarGroup map[string]string) ([]corev1.EnvVar, error) {
+	env, err := b.appEnvBuilder.build(ctx, cfApp, envVarGroup)
 	if err != nil {
 		return nil, err
 	}
@@ -138,13 +236,35 @@ func (b *ProcessEnvBuilder) buildPortEnv(ctx context.Context, cfApp *korifiv1alp
 	processPorts := ports.FromRoutes(cfRoutesForProcess.Items, cfApp.Name, cfProcess.Spec.ProcessType)
 	if len(processPorts) > 0 {
 		portString := strconv.FormatInt(int64(processPorts[0]), 10)
-		cfInstancePorts := fmt.Sprintf("[{\"internal\":%s}]", portString)
+		cfInstancePorts, err := json.Marshal(toInstancePorts(processPorts))
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal CF_INSTANCE_PORTS: %w", err)
+		}
+
 		return []corev1.EnvVar{
 			{Name: "VCAP_APP_PORT", Value: portString},
 			{Name: "PORT", Value: portString},
-			{Name: "CF_INSTANCE_PORTS", Value: cfInstancePorts},
+			{Name: "CF_INSTANCE_PORT", Value: portString},
+			{Name: "CF_INSTANCE_PORTS", Value: string(cfInstancePorts)},
 		}, nil
 	}
 
 	return nil, nil
 }
+
+type instancePort struct {
+	Internal int32 `json:"internal"`
+}
+
+// toInstancePorts lists every distinct port of the process, as the process
+// may be the destination of several routes on different ports
+func toInstancePorts(processPorts []int32) []instancePort {
+	instancePorts := []instancePort{}
+	for _, port := range processPorts {
+		if !slices.Contains(instancePorts, instancePort{Internal: port}) {
+			instancePorts = append(instancePorts, instancePort{Internal: port})
+		}
+	}
+
+	return instancePorts
+}
@@ -100,7 +100,7 @@ var _ = Describe("EnvBuilder", func() {
 		var builder *env.AppEnvBuilder
 
 		BeforeEach(func() {
-			builder = env.NewAppEnvBuilder(controllersClient)
+			builder = env.NewAppEnvBuilder(controllersClient, rootNamespace)
 		})
 
 		JustBeforeEach(func() {
@@ -256,12 +256,128 @@ var _ = Describe("EnvBuilder", func() {
 				))
 			})
 		})
+
+		When("the running env var group is set", func() {
+			BeforeEach(func() {
+				createEnvVarGroup(korifiv1alpha1.RunningEnvVarGroupName, map[string]string{
+					"app-secret": "group-value",
+					"group-only": "group-value",
+				})
+				createEnvVarGroup(korifiv1alpha1.StagingEnvVarGroupName, map[string]string{
+					"staging-group-only": "group-value",
+				})
+			})
+
+			It("adds its env vars, which are overridden by the app ones", func() {
+				Expect(buildErr).NotTo(HaveOccurred())
+				Expect(envVars).To(ConsistOf(
+					appSecretEnv,
+					vcapServicesEnv,
+					vcapApplicationEnv,
+					Equal(corev1.EnvVar{Name: "group-only", Value: "group-value"}),
+				))
+			})
+		})
+	})
+
+	Describe("BuildEnvBuilder", func() {
+		var builder *env.BuildEnvBuilder
+
+		BeforeEach(func() {
+			builder = env.NewBuildEnvBuilder(controllersClient, rootNamespace)
+		})
+
+		JustBeforeEach(func() {
+			envVars, buildErr = builder.Build(context.Background(), cfApp)
+		})
+
+		It("builds the app env vars", func() {
+			Expect(buildErr).NotTo(HaveOccurred())
+			Expect(envVars).To(ConsistOf(
+				appSecretEnv,
+				vcapServicesEnv,
+				vcapApplicationEnv,
+			))
+		})
+
+		When("the app has staging env vars", func() {
+			BeforeEach(func() {
+				helpers.EnsureCreate(controllersClient, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: cfSpace.Status.GUID,
+						Name:      "app-staging-env-secret",
+					},
+					Data: map[string][]byte{
+						"app-secret":   []byte("staging-secret"),
+						"staging-only": []byte("staging-value"),
+					},
+				})
+				helpers.EnsurePatch(controllersClient, cfApp, func(app *korifiv1alpha1.CFApp) {
+					app.Spec.StagingEnvSecretName = "app-staging-env-secret"
+				})
+			})
+
+			It("adds them to the app env vars, overriding the ones with the same name", func() {
+				Expect(buildErr).NotTo(HaveOccurred())
+				Expect(envVars).To(ConsistOf(
+					vcapServicesEnv,
+					vcapApplicationEnv,
+					stagingEnvVar("app-secret"),
+					stagingEnvVar("staging-only"),
+				))
+			})
+
+			It("sorts the env vars by name", func() {
+				Expect(buildErr).NotTo(HaveOccurred())
+				envVarNames := []string{}
+				for _, v := range envVars {
+					envVarNames = append(envVarNames, v.Name)
+				}
+
+				Expect(slices.IsSorted(envVarNames)).To(BeTrue())
+			})
+		})
+
+		When("the env var groups are set", func() {
+			BeforeEach(func() {
+				createEnvVarGroup(korifiv1alpha1.RunningEnvVarGroupName, map[string]string{
+					"running-group-only": "group-value",
+				})
+				createEnvVarGroup(korifiv1alpha1.StagingEnvVarGroupName, map[string]string{
+					"app-secret":         "group-value",
+					"staging-group-only": "group-value",
+				})
+			})
+
+			It("adds the env vars of the staging group, which are overridden by the app ones", func() {
+				Expect(buildErr).NotTo(HaveOccurred())
+				Expect(envVars).To(ConsistOf(
+					appSecretEnv,
+					vcapServicesEnv,
+					vcapApplicationEnv,
+					Equal(corev1.EnvVar{Name: "staging-group-only", Value: "group-value"}),
+				))
+			})
+		})
+
+		When("the app staging env secret does not exist", func() {
+			BeforeEach(func() {
+				helpers.EnsurePatch(controllersClient, cfApp, func(app *korifiv1alpha1.CFApp) {
+					app.Spec.StagingEnvSecretName = "not-a-secret"
+				})
+			})
+
+			It("errors", func() {
+				Expect(buildErr).To(MatchError(ContainSubstring("not-a-secret")))
+			})
+		})
 	})
 
 	Describe("ProcessEnvBuilder", func() {
 		var (
-			builder   *env.ProcessEnvBuilder
-			cfProcess *korifiv1alpha1.CFProcess
+			builder     *env.ProcessEnvBuilder
+			cfProcess   *korifiv1alpha1.CFProcess
+			envVarGroup map[string]string
 		)
 
 		BeforeEach(func() {
@@ -276,11 +392,12 @@ var _ = Describe("EnvBuilder", func() {
 				},
 			}
 			helpers.EnsureCreate(controllersClient, cfProcess)
-			builder = env.NewProcessEnvBuilder(controllersClient)
+			builder = env.NewProcessEnvBuilder(controllersClient, rootNamespace)
+			envVarGroup = nil
 		})
 
 		JustBeforeEach(func() {
-			envVars, buildErr = builder.Build(context.Background(), cfApp, cfProcess)
+			envVars, buildErr = builder.Build(context.Background(), cfApp, cfProcess, envVarGroup)
 		})
 
 		It("returns the process env vars", func() {
@@ -310,6 +427,56 @@ var _ = Describe("EnvBuilder", func() {
 			Expect(slices.IsSorted(envVarNames)).To(BeTrue())
 		})
 
+		When("an env var group is given", func() {
+			BeforeEach(func() {
+				createEnvVarGroup(korifiv1alpha1.RunningEnvVarGroupName, map[string]string{
+					"running-group-only": "group-value",
+				})
+
+				envVarGroup = map[string]string{
+					"app-secret":          "group-value",
+					"snapshot-group-only": "group-value",
+				}
+			})
+
+			It("adds the env vars of the given group, which are overridden by the app ones", func() {
+				Expect(buildErr).NotTo(HaveOccurred())
+				Expect(envVars).To(ContainElement(Equal(corev1.EnvVar{Name: "snapshot-group-only", Value: "group-value"})))
+				Expect(envVars).NotTo(ContainElement(MatchFields(IgnoreExtras, Fields{"Name": Equal("running-group-only")})))
+				Expect(envVars).To(ContainElement(appSecretEnv))
+				Expect(envVars).NotTo(ContainElement(Equal(corev1.EnvVar{Name: "app-secret", Value: "group-value"})))
+			})
+		})
+
+		Describe("EnvVarGroup", func() {
+			var (
+				group    map[string]string
+				groupErr error
+			)
+
+			JustBeforeEach(func() {
+				group, groupErr = builder.EnvVarGroup(context.Background())
+			})
+
+			It("returns no env vars when the running env var group does not exist", func() {
+				Expect(groupErr).NotTo(HaveOccurred())
+				Expect(group).To(BeEmpty())
+			})
+
+			When("the running env var group is set", func() {
+				BeforeEach(func() {
+					createEnvVarGroup(korifiv1alpha1.RunningEnvVarGroupName, map[string]string{
+						"running-group-only": "group-value",
+					})
+				})
+
+				It("returns its env vars", func() {
+					Expect(groupErr).NotTo(HaveOccurred())
+					Expect(group).To(Equal(map[string]string{"running-group-only": "group-value"}))
+				})
+			})
+		})
+
 		Describe("ports env vars", func() {
 			var cfRoute *korifiv1alpha1.CFRoute
 
@@ -350,12 +517,45 @@ var _ = Describe("EnvBuilder", func() {
 						"Name":  Equal("PORT"),
 						"Value": Equal("1234"),
 					}),
+					MatchFields(IgnoreExtras, Fields{
+						"Name":  Equal("CF_INSTANCE_PORT"),
+						"Value": Equal("1234"),
+					}),
 					MatchFields(IgnoreExtras, Fields{
 						"Name":  Equal("CF_INSTANCE_PORTS"),
 						"Value": MatchJSON("[{\"internal\":1234}]"),
 					}),
 				))
 			})
+
+			When("the process is the destination of routes on several ports", func() {
+				BeforeEach(func() {
+					helpers.EnsurePatch(controllersClient, cfRoute, func(cfRoute *korifiv1alpha1.CFRoute) {
+						cfRoute.Status.Destinations = append(cfRoute.Status.Destinations,
+							korifiv1alpha1.Destination{
+								GUID:        "dest-guid-2",
+								Port:        tools.PtrTo[int32](5678),
+								AppRef:      corev1.LocalObjectReference{Name: cfApp.Name},
+								ProcessType: "web",
+							},
+							korifiv1alpha1.Destination{
+								GUID:        "dest-guid-3",
+								Port:        tools.PtrTo[int32](1234),
+								AppRef:      corev1.LocalObjectReference{Name: cfApp.Name},
+								ProcessType: "web",
+							},
+						)
+					})
+				})
+
+				It("lists every distinct port in CF_INSTANCE_PORTS", func() {
+					Expect(buildErr).NotTo(HaveOccurred())
+					Expect(envVars).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+						"Name":  Equal("CF_INSTANCE_PORTS"),
+						"Value": MatchJSON("[{\"internal\":1234},{\"internal\":5678}]"),
+					})))
+				})
+			})
 			When("the route does not have destinations", func() {
 				BeforeEach(func() {
 					helpers.EnsurePatch(controllersClient, cfRoute, func(cfRoute *korifiv1alpha1.CFRoute) {
@@ -373,3 +573,29 @@ var _ = Describe("EnvBuilder", func() {
 		})
 	})
 })
+
+func createEnvVarGroup(name string, envVars map[string]string) {
+	GinkgoHelper()
+
+	helpers.EnsureCreate(controllersClient, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: rootNamespace,
+			Name:      name,
+		},
+		Data: envVars,
+	})
+}
+
+func stagingEnvVar(name string) types.GomegaMatcher {
+	return MatchFields(IgnoreExtras, Fields{
+		"Name": Equal(name),
+		"ValueFrom": PointTo(MatchFields(IgnoreExtras, Fields{
+			"SecretKeyRef": PointTo(Equal(corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "app-staging-env-secret",
+				},
+				Key: name,
+			})),
+		})),
+	})
+}
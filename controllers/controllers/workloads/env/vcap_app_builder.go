@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
@@ -11,6 +13,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// fileDescriptorsLimit is the file descriptors limit CF reports for app
+// instances
+const fileDescriptorsLimit = 16384
+
 type VCAPApplicationEnvValueBuilder struct {
 	k8sClient   client.Client
 	extraValues map[string]any
@@ -38,7 +44,13 @@ func (b *VCAPApplicationEnvValueBuilder) BuildEnvValue(ctx context.Context, cfAp
 		return nil, fmt.Errorf("failed retrieving app routes: %w", err)
 	}
 
-	vars := b.extraValues
+	limits, err := b.getAppLimits(ctx, cfApp)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving app limits: %w", err)
+	}
+
+	// The extra values are shared by all apps, so they must not be modified
+	vars := maps.Clone(b.extraValues)
 	if vars == nil {
 		vars = map[string]any{}
 	}
@@ -51,6 +63,9 @@ func (b *VCAPApplicationEnvValueBuilder) BuildEnvValue(ctx context.Context, cfAp
 	vars["space_name"] = space.Spec.DisplayName
 	vars["uris"] = appURIs
 	vars["application_uris"] = appURIs
+	if limits != nil {
+		vars["limits"] = limits
+	}
 
 	marshalledVars, _ := json.Marshal(vars)
 
@@ -71,12 +86,42 @@ func (b *VCAPApplicationEnvValueBuilder) getAppURIs(ctx context.Context, cfApp *
 		return nil, err
 	}
 
-	uris := make([]string, len(appRoutes.Items))
-	for i := range appRoutes.Items {
-		uris[i] = appRoutes.Items[i].Status.URI
+	uris := []string{}
+	for _, route := range appRoutes.Items {
+		if route.Status.URI != "" {
+			uris = append(uris, route.Status.URI)
+		}
+	}
+	slices.Sort(uris)
+
+	return slices.Compact(uris), nil
+}
+
+// getAppLimits returns the limits of the web process of the app, as
+// VCAP_APPLICATION is shared by all the processes of the app. Apps without
+// a web process have no limits.
+func (b *VCAPApplicationEnvValueBuilder) getAppLimits(ctx context.Context, cfApp *korifiv1alpha1.CFApp) (map[string]any, error) {
+	var processes korifiv1alpha1.CFProcessList
+	err := b.k8sClient.List(ctx, &processes,
+		client.InNamespace(cfApp.Namespace),
+		client.MatchingLabels{
+			korifiv1alpha1.CFAppGUIDLabelKey:     cfApp.Name,
+			korifiv1alpha1.CFProcessTypeLabelKey: korifiv1alpha1.ProcessTypeWeb,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(processes.Items) == 0 {
+		return nil, nil
 	}
 
-	return uris, nil
+	return map[string]any{
+		"fds":  fileDescriptorsLimit,
+		"mem":  processes.Items[0].Spec.MemoryMB,
+		"disk": processes.Items[0].Spec.DiskQuotaMB,
+	}, nil
 }
 
 func (b *VCAPApplicationEnvValueBuilder) getSpaceFromNamespace(ctx context.Context, ns string) (korifiv1alpha1.CFSpace, error) {
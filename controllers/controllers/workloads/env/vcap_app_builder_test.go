@@ -44,6 +44,7 @@ var _ = Describe("VCAP_APPLICATION env value builder", func() {
 			Expect(vcapAppValue).To(HaveKeyWithValue("organization_name", cfOrg.Spec.DisplayName))
 			Expect(vcapAppValue).To(HaveKeyWithValue("uris", BeEmpty()))
 			Expect(vcapAppValue).To(HaveKeyWithValue("application_uris", BeEmpty()))
+			Expect(vcapAppValue).NotTo(HaveKey("limits"))
 		})
 
 		When("extra values are provided", func() {
@@ -68,6 +69,48 @@ var _ = Describe("VCAP_APPLICATION env value builder", func() {
 				Expect(vcapAppValue).To(HaveKeyWithValue("innit", true))
 				Expect(vcapAppValue).To(HaveKeyWithValue("x", HaveKeyWithValue("y", "z")))
 			})
+
+			It("does not modify them", func() {
+				Expect(buildVCAPApplicationEnvValueErr).ToNot(HaveOccurred())
+				vcapApplication, buildVCAPApplicationEnvValueErr = builder.BuildEnvValue(ctx, cfApp)
+				Expect(buildVCAPApplicationEnvValueErr).ToNot(HaveOccurred())
+				vcapAppValue := map[string]any{}
+				Expect(json.Unmarshal([]byte(vcapApplication["VCAP_APPLICATION"]), &vcapAppValue)).To(Succeed())
+				Expect(vcapAppValue).To(HaveKeyWithValue("foo", "bar"))
+				Expect(vcapAppValue).To(HaveKeyWithValue("application_id", cfApp.Name))
+			})
+		})
+
+		When("the app has a web process", func() {
+			BeforeEach(func() {
+				helpers.EnsureCreate(controllersClient, &korifiv1alpha1.CFProcess{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: cfApp.Namespace,
+						Name:      "vcap-application-web-process",
+						Labels: map[string]string{
+							korifiv1alpha1.CFAppGUIDLabelKey:     cfApp.Name,
+							korifiv1alpha1.CFProcessTypeLabelKey: korifiv1alpha1.ProcessTypeWeb,
+						},
+					},
+					Spec: korifiv1alpha1.CFProcessSpec{
+						AppRef:      v1.LocalObjectReference{Name: cfApp.Name},
+						ProcessType: korifiv1alpha1.ProcessTypeWeb,
+						MemoryMB:    256,
+						DiskQuotaMB: 1024,
+					},
+				})
+			})
+
+			It("includes the process limits", func() {
+				Expect(buildVCAPApplicationEnvValueErr).ToNot(HaveOccurred())
+				vcapAppValue := map[string]any{}
+				Expect(json.Unmarshal([]byte(vcapApplication["VCAP_APPLICATION"]), &vcapAppValue)).To(Succeed())
+				Expect(vcapAppValue).To(HaveKeyWithValue("limits", SatisfyAll(
+					HaveKeyWithValue("fds", 16384.0),
+					HaveKeyWithValue("mem", 256.0),
+					HaveKeyWithValue("disk", 1024.0),
+				)))
+			})
 		})
 
 		When("application routes are provided", func() {
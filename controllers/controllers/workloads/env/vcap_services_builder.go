@@ -46,6 +46,10 @@ func (b *VCAPServicesEnvValueBuilder) BuildEnvValue(ctx context.Context, cfApp *
 			continue
 		}
 
+		if !currentServiceBinding.ProjectsVCAPServices(cfApp) {
+			continue
+		}
+
 		var serviceEnv ServiceDetails
 		var serviceLabel string
 		serviceEnv, serviceLabel, err = buildSingleServiceEnv(ctx, b.k8sClient, currentServiceBinding)
@@ -90,6 +94,13 @@ func buildSingleServiceEnv(ctx context.Context, k8sClient client.Client, service
 		return ServiceDetails{}, "", fmt.Errorf("error fetching CFServiceBinding Secret: %w", err)
 	}
 
+	// managed service instances are labelled with their offering name, which
+	// CF uses as label in VCAP_SERVICES
+	offeringName := serviceInstance.Labels[korifiv1alpha1.RelServiceOfferingNameLabel]
+	if serviceInstance.Spec.Type == korifiv1alpha1.ManagedType && offeringName != "" {
+		serviceLabel = offeringName
+	}
+
 	if serviceInstance.Spec.ServiceLabel != nil && *serviceInstance.Spec.ServiceLabel != "" {
 		serviceLabel = *serviceInstance.Spec.ServiceLabel
 	}
@@ -130,16 +141,22 @@ func fromServiceBinding(
 		return ServiceDetails{}, fmt.Errorf("failed to get credentials for service binding %q: %w", serviceBinding.Name, err)
 	}
 
+	var plan string
+	if serviceInstance.Spec.Type == korifiv1alpha1.ManagedType {
+		plan = serviceInstance.Labels[korifiv1alpha1.RelServicePlanNameLabel]
+	}
+
 	return ServiceDetails{
 		Label:          serviceLabel,
 		Name:           serviceName,
 		Tags:           tags,
+		Plan:           plan,
 		InstanceGUID:   serviceInstance.Name,
 		InstanceName:   serviceInstance.Spec.DisplayName,
 		BindingGUID:    serviceBinding.Name,
 		BindingName:    bindingName,
 		Credentials:    creds,
-		SyslogDrainURL: nil,
+		SyslogDrainURL: serviceInstance.Spec.SyslogDrainURL,
 		VolumeMounts:   []string{},
 	}, nil
 }
@@ -216,6 +216,59 @@ var _ = Describe("Builder", func() {
 			})
 		})
 
+		When("the service instance has a syslog drain", func() {
+			BeforeEach(func() {
+				helpers.EnsurePatch(controllersClient, serviceInstance, func(s *korifiv1alpha1.CFServiceInstance) {
+					s.Spec.SyslogDrainURL = tools.PtrTo("syslog-tls://logs.example.com:6514")
+				})
+			})
+
+			It("sets the syslog drain url", func() {
+				Expect(parseVcapServices(vcapServices)).To(MatchKeys(IgnoreExtras, Keys{
+					"user-provided": ConsistOf(MatchKeys(IgnoreExtras, Keys{
+						"syslog_drain_url": Equal("syslog-tls://logs.example.com:6514"),
+					})),
+				}))
+			})
+		})
+
+		When("the service instance is managed", func() {
+			BeforeEach(func() {
+				helpers.EnsurePatch(controllersClient, serviceInstance, func(s *korifiv1alpha1.CFServiceInstance) {
+					s.Spec.Type = korifiv1alpha1.ManagedType
+					s.Labels = map[string]string{
+						korifiv1alpha1.RelServiceOfferingNameLabel: "my-offering",
+						korifiv1alpha1.RelServicePlanNameLabel:     "my-plan",
+					}
+				})
+			})
+
+			It("uses the offering name as label and sets the plan name", func() {
+				Expect(parseVcapServices(vcapServices)).To(MatchKeys(IgnoreExtras, Keys{
+					"my-offering": ConsistOf(MatchKeys(IgnoreExtras, Keys{
+						"label": Equal("my-offering"),
+						"plan":  Equal("my-plan"),
+					})),
+				}))
+			})
+
+			When("the service instance has a service label", func() {
+				BeforeEach(func() {
+					helpers.EnsurePatch(controllersClient, serviceInstance, func(s *korifiv1alpha1.CFServiceInstance) {
+						s.Spec.ServiceLabel = tools.PtrTo("custom-label")
+					})
+				})
+
+				It("uses the service label", func() {
+					Expect(parseVcapServices(vcapServices)).To(MatchKeys(IgnoreExtras, Keys{
+						"custom-label": ConsistOf(MatchKeys(IgnoreExtras, Keys{
+							"plan": Equal("my-plan"),
+						})),
+					}))
+				})
+			})
+		})
+
 		When("serviceLabel is set but blank", func() {
 			BeforeEach(func() {
 				helpers.EnsurePatch(controllersClient, serviceInstance, func(s *korifiv1alpha1.CFServiceInstance) {
@@ -244,6 +297,45 @@ var _ = Describe("Builder", func() {
 			})
 		})
 
+		When("a service binding is only projected as files", func() {
+			BeforeEach(func() {
+				helpers.EnsurePatch(controllersClient, serviceBinding, func(sb *korifiv1alpha1.CFServiceBinding) {
+					sb.Annotations = map[string]string{
+						korifiv1alpha1.ServiceBindingProjectionAnnotationKey: korifiv1alpha1.ServiceBindingProjectionFiles,
+					}
+				})
+			})
+
+			It("omits it from VCAP_SERVICES", func() {
+				Expect(buildVCAPServicesEnvValueErr).NotTo(HaveOccurred())
+				Expect(parseVcapServices(vcapServices)).To(MatchAllKeys(Keys{
+					"custom-service-2": HaveLen(1),
+				}))
+			})
+		})
+
+		When("the app projects its service bindings as files only", func() {
+			BeforeEach(func() {
+				helpers.EnsurePatch(controllersClient, cfApp, func(app *korifiv1alpha1.CFApp) {
+					app.Annotations = map[string]string{
+						korifiv1alpha1.ServiceBindingProjectionAnnotationKey: korifiv1alpha1.ServiceBindingProjectionFiles,
+					}
+				})
+				helpers.EnsurePatch(controllersClient, serviceBinding, func(sb *korifiv1alpha1.CFServiceBinding) {
+					sb.Annotations = map[string]string{
+						korifiv1alpha1.ServiceBindingProjectionAnnotationKey: korifiv1alpha1.ServiceBindingProjectionAll,
+					}
+				})
+			})
+
+			It("only includes the bindings that override the projection", func() {
+				Expect(buildVCAPServicesEnvValueErr).NotTo(HaveOccurred())
+				Expect(parseVcapServices(vcapServices)).To(MatchAllKeys(Keys{
+					"user-provided": HaveLen(1),
+				}))
+			})
+		})
+
 		When("there are no service bindings for the app", func() {
 			BeforeEach(func() {
 				Expect(adminClient.DeleteAllOf(ctx, &korifiv1alpha1.CFServiceBinding{}, client.InNamespace(cfSpace.Status.GUID))).To(Succeed())
@@ -44,6 +44,12 @@ type ImageDeleter interface {
 	Delete(ctx context.Context, creds image.Creds, imageRef string, tagsToDelete ...string) error
 }
 
+//counterfeiter:generate -o fake -fake-name BlobDeleter . BlobDeleter
+
+type BlobDeleter interface {
+	Delete(ctx context.Context, spaceGUID string, objectURL string) error
+}
+
 //counterfeiter:generate -o fake -fake-name PackageCleaner . PackageCleaner
 
 type PackageCleaner interface {
@@ -54,6 +60,7 @@ type Reconciler struct {
 	k8sClient              client.Client
 	scheme                 *runtime.Scheme
 	imageDeleter           ImageDeleter
+	blobDeleter            BlobDeleter
 	packageCleaner         PackageCleaner
 	packageRepoSecretNames []string
 	log                    logr.Logger
@@ -64,6 +71,7 @@ func NewReconciler(
 	scheme *runtime.Scheme,
 	log logr.Logger,
 	imageDeleter ImageDeleter,
+	blobDeleter BlobDeleter,
 	packageCleaner PackageCleaner,
 	packageRepoSecretNames []string,
 ) *k8s.PatchingReconciler[korifiv1alpha1.CFPackage, *korifiv1alpha1.CFPackage] {
@@ -72,6 +80,7 @@ func NewReconciler(
 		scheme:                 scheme,
 		log:                    log,
 		imageDeleter:           imageDeleter,
+		blobDeleter:            blobDeleter,
 		packageCleaner:         packageCleaner,
 		packageRepoSecretNames: packageRepoSecretNames,
 	})
@@ -125,10 +134,12 @@ func (r *Reconciler) ReconcileResource(ctx context.Context, cfPackage *korifiv1a
 		}
 	}()
 
-	if cfPackage.Spec.Source.Registry.Image == "" {
+	if cfPackage.Spec.Source.Registry.Image == "" && cfPackage.Spec.Source.Blob == nil {
 		return ctrl.Result{}, k8s.NewNotReadyError().WithReason("Initialized").WithNoRequeue()
 	}
 
+	cfPackage.Status.Checksum = cfPackage.Spec.Source.Checksum
+
 	return ctrl.Result{}, nil
 }
 
@@ -148,6 +159,12 @@ func (r *Reconciler) finalize(ctx context.Context, cfPackage *korifiv1alpha1.CFP
 		}
 	}
 
+	if cfPackage.Spec.Source.Blob != nil {
+		if err := r.blobDeleter.Delete(ctx, cfPackage.Namespace, cfPackage.Spec.Source.Blob.URL); err != nil {
+			log.Info("failed to delete package bits", "reason", err)
+		}
+	}
+
 	if controllerutil.RemoveFinalizer(cfPackage, korifiv1alpha1.CFPackageFinalizerName) {
 		log.V(1).Info("finalizer removed")
 	}
@@ -133,6 +133,40 @@ var _ = Describe("CFPackageReconciler Integration Tests", func() {
 				}).Should(Succeed())
 			})
 		})
+
+		When("the package source is a blob", func() {
+			BeforeEach(func() {
+				cfPackage.Spec.Source = korifiv1alpha1.PackageSource{
+					Blob: &korifiv1alpha1.Blob{URL: "https://blobstore.example.org/packages/the-package.zip"},
+				}
+			})
+
+			It("sets the ready condition to true", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(context.Background(), client.ObjectKeyFromObject(cfPackage), cfPackage)).To(Succeed())
+					g.Expect(meta.IsStatusConditionTrue(cfPackage.Status.Conditions, korifiv1alpha1.StatusConditionReady)).To(BeTrue())
+				}).Should(Succeed())
+			})
+		})
+
+		When("the package source has a checksum", func() {
+			BeforeEach(func() {
+				cfPackage.Spec.Source.Checksum = &korifiv1alpha1.Checksum{
+					Type:  "sha256",
+					Value: "the-sha",
+				}
+			})
+
+			It("records the checksum in the status", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(context.Background(), client.ObjectKeyFromObject(cfPackage), cfPackage)).To(Succeed())
+					g.Expect(cfPackage.Status.Checksum).To(Equal(&korifiv1alpha1.Checksum{
+						Type:  "sha256",
+						Value: "the-sha",
+					}))
+				}).Should(Succeed())
+			})
+		})
 	})
 
 	Describe("finalization", func() {
@@ -167,6 +201,37 @@ var _ = Describe("CFPackageReconciler Integration Tests", func() {
 			}).Should(Succeed())
 		})
 
+		When("the package bits are in the blobstore", func() {
+			var blobDeleteCount int
+
+			BeforeEach(func() {
+				blobDeleteCount = blobDeleter.DeleteCallCount()
+				cfPackage.Spec.Source = korifiv1alpha1.PackageSource{
+					Blob: &korifiv1alpha1.Blob{URL: "https://s3.example.com/packages/the-package.zip"},
+				}
+			})
+
+			It("deletes them", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(blobDeleter.DeleteCallCount()).To(BeNumerically(">", blobDeleteCount))
+
+					_, spaceGUID, objectURL := blobDeleter.DeleteArgsForCall(blobDeleteCount)
+					g.Expect(spaceGUID).To(Equal(testNamespace))
+					g.Expect(objectURL).To(Equal("https://s3.example.com/packages/the-package.zip"))
+				}).Should(Succeed())
+
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(context.Background(), client.ObjectKeyFromObject(cfPackage), cfPackage)).To(MatchError(ContainSubstring("not found")))
+				}).Should(Succeed())
+			})
+
+			It("does not delete any image", func() {
+				Consistently(func(g Gomega) {
+					g.Expect(imageDeleter.DeleteCallCount()).To(Equal(deleteCount))
+				}).Should(Succeed())
+			})
+		})
+
 		When("the package type is docker", func() {
 			BeforeEach(func() {
 				cfPackage.Spec.Type = "docker"
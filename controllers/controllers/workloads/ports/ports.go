@@ -6,6 +6,8 @@ import (
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 )
 
+// FromRoutes lists the distinct ports the destinations of the routes send
+// traffic to on the process of the app, oldest route first
 func FromRoutes(cfRoutes []korifiv1alpha1.CFRoute, appGUID, processType string) []int32 {
 	// In case there are multiple routes, prefer the oldest one
 	slices.SortStableFunc(cfRoutes, func(r1, r2 korifiv1alpha1.CFRoute) int {
@@ -16,8 +18,9 @@ func FromRoutes(cfRoutes []korifiv1alpha1.CFRoute, appGUID, processType string)
 		for _, destination := range cfRoute.Status.Destinations {
 			if destination.AppRef.Name == appGUID &&
 				destination.ProcessType == processType &&
-				destination.Port != nil {
-				ports = append(ports, int32(*destination.Port))
+				destination.Port != nil &&
+				!slices.Contains(ports, *destination.Port) {
+				ports = append(ports, *destination.Port)
 			}
 		}
 	}
@@ -68,6 +68,48 @@ var _ = Describe("FromRoutes", func() {
 		})
 	})
 
+	When("several routes send traffic to the same port", func() {
+		BeforeEach(func() {
+			cfRoutes = append(cfRoutes, korifiv1alpha1.CFRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: metav1.NewTime(time.UnixMilli(3000)),
+				},
+				Status: korifiv1alpha1.CFRouteStatus{
+					Destinations: []korifiv1alpha1.Destination{{
+						GUID: "another-dest-guid",
+						Port: tools.PtrTo[int32](9876),
+						AppRef: corev1.LocalObjectReference{
+							Name: "my-app",
+						},
+						ProcessType: "web",
+					}},
+				},
+			})
+		})
+
+		It("returns the port once", func() {
+			Expect(processPorts).To(Equal([]int32{9876}))
+		})
+	})
+
+	When("the destination targets another process of the app", func() {
+		BeforeEach(func() {
+			cfRoutes[0].Status.Destinations = append(cfRoutes[0].Status.Destinations, korifiv1alpha1.Destination{
+				GUID: "worker-dest-guid",
+				Port: tools.PtrTo[int32](5000),
+				AppRef: corev1.LocalObjectReference{
+					Name: "my-app",
+				},
+				ProcessType: "worker",
+			})
+		})
+
+		It("only returns the ports of the given process", func() {
+			Expect(processPorts).To(Equal([]int32{9876}))
+			Expect(ports.FromRoutes(cfRoutes, "my-app", "worker")).To(Equal([]int32{5000}))
+		})
+	})
+
 	When("the destination does not reference the process app", func() {
 		BeforeEach(func() {
 			cfRoutes[0].Status.Destinations[0].AppRef.Name = "foo"
@@ -0,0 +1,162 @@
+package processes
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const defaultAutoscalingCPUTarget int32 = 80
+
+// autoscaling holds the settings of processes that opted into autoscaling via
+// the AutoscalingMaxInstancesAnnotationKey annotation. The desired instances
+// of the process, as set by `cf scale`, are the minimum the autoscaler scales
+// down to.
+type autoscaling struct {
+	maxInstances int32
+	cpuTarget    *int32
+	memoryTarget *int32
+}
+
+func autoscalingFor(cfProcess *korifiv1alpha1.CFProcess) (*autoscaling, error) {
+	maxInstances, ok := cfProcess.Annotations[korifiv1alpha1.AutoscalingMaxInstancesAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	result := &autoscaling{}
+	var err error
+	if result.maxInstances, err = parsePositiveInt32(maxInstances); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", korifiv1alpha1.AutoscalingMaxInstancesAnnotationKey, err)
+	}
+
+	if result.cpuTarget, err = parseTarget(cfProcess, korifiv1alpha1.AutoscalingCPUTargetAnnotationKey); err != nil {
+		return nil, err
+	}
+
+	if result.memoryTarget, err = parseTarget(cfProcess, korifiv1alpha1.AutoscalingMemoryTargetAnnotationKey); err != nil {
+		return nil, err
+	}
+
+	if result.cpuTarget == nil && result.memoryTarget == nil {
+		result.cpuTarget = tools.PtrTo(defaultAutoscalingCPUTarget)
+	}
+
+	return result, nil
+}
+
+func parseTarget(cfProcess *korifiv1alpha1.CFProcess, annotationKey string) (*int32, error) {
+	value, ok := cfProcess.Annotations[annotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	target, err := parsePositiveInt32(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", annotationKey, err)
+	}
+
+	return &target, nil
+}
+
+func parsePositiveInt32(value string) (int32, error) {
+	parsed, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	if parsed < 1 {
+		return 0, fmt.Errorf("%d is not a positive integer", parsed)
+	}
+
+	return int32(parsed), nil
+}
+
+// instances keeps the instances the autoscaler scaled the workload to, as long
+// as they are within the desired instances of the process and the maximum
+func (a *autoscaling) instances(currentInstances, desiredInstances int32) int32 {
+	return min(max(currentInstances, desiredInstances), max(a.maxInstances, desiredInstances))
+}
+
+func (a *autoscaling) metrics() []autoscalingv2.MetricSpec {
+	var metrics []autoscalingv2.MetricSpec
+	if a.cpuTarget != nil {
+		metrics = append(metrics, utilizationMetric(corev1.ResourceCPU, *a.cpuTarget))
+	}
+
+	if a.memoryTarget != nil {
+		metrics = append(metrics, utilizationMetric(corev1.ResourceMemory, *a.memoryTarget))
+	}
+
+	return metrics
+}
+
+func utilizationMetric(resourceName corev1.ResourceName, target int32) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: resourceName,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: tools.PtrTo(target),
+			},
+		},
+	}
+}
+
+// reconcileAutoscaler creates a horizontal pod autoscaler that scales the
+// AppWorkload of the process via its scale subresource, or deletes it when
+// the process does not (or no longer) autoscale
+func (r *Reconciler) reconcileAutoscaler(ctx context.Context, cfProcess *korifiv1alpha1.CFProcess, autoscaling *autoscaling, appWorkloadName string) error {
+	if autoscaling == nil {
+		return r.deleteAutoscaler(ctx, cfProcess)
+	}
+
+	hpa := autoscalerFor(cfProcess)
+	minInstances := *cfProcess.Spec.DesiredInstances
+	_, err := controllerutil.CreateOrPatch(ctx, r.k8sClient, hpa, func() error {
+		hpa.Spec = autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: korifiv1alpha1.GroupVersion.String(),
+				Kind:       "AppWorkload",
+				Name:       appWorkloadName,
+			},
+			MinReplicas: tools.PtrTo(minInstances),
+			MaxReplicas: max(autoscaling.maxInstances, minInstances),
+			Metrics:     autoscaling.metrics(),
+		}
+
+		return controllerutil.SetControllerReference(cfProcess, hpa, r.scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or patch horizontal pod autoscaler: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) deleteAutoscaler(ctx context.Context, cfProcess *korifiv1alpha1.CFProcess) error {
+	if err := r.k8sClient.Delete(ctx, autoscalerFor(cfProcess)); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete horizontal pod autoscaler: %w", err)
+	}
+
+	return nil
+}
+
+func autoscalerFor(cfProcess *korifiv1alpha1.CFProcess) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cfProcess.Namespace,
+			Name:      cfProcess.Name,
+		},
+	}
+}
@@ -19,22 +19,33 @@ package processes
 import (
 	"context"
 	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/controllers/config"
 	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
 	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/ports"
+	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/usage"
 	"code.cloudfoundry.org/korifi/tools/k8s"
 
 	"github.com/go-logr/logr"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -44,7 +55,12 @@ import (
 )
 
 type ProcessEnvBuilder interface {
-	Build(context.Context, *korifiv1alpha1.CFApp, *korifiv1alpha1.CFProcess) ([]corev1.EnvVar, error)
+	EnvVarGroup(context.Context) (map[string]string, error)
+	Build(context.Context, *korifiv1alpha1.CFApp, *korifiv1alpha1.CFProcess, map[string]string) ([]corev1.EnvVar, error)
+}
+
+type UsageRecorder interface {
+	RecordProcessUsage(context.Context, *korifiv1alpha1.CFApp, *korifiv1alpha1.CFProcess, usage.Usage) error
 }
 
 type Reconciler struct {
@@ -53,6 +69,8 @@ type Reconciler struct {
 	log              logr.Logger
 	controllerConfig *config.ControllerConfig
 	envBuilder       ProcessEnvBuilder
+	usageRecorder    UsageRecorder
+	recorder         record.EventRecorder
 }
 
 func NewReconciler(
@@ -61,8 +79,10 @@ func NewReconciler(
 	log logr.Logger,
 	controllerConfig *config.ControllerConfig,
 	envBuilder ProcessEnvBuilder,
+	usageRecorder UsageRecorder,
+	recorder record.EventRecorder,
 ) *k8s.PatchingReconciler[korifiv1alpha1.CFProcess, *korifiv1alpha1.CFProcess] {
-	processReconciler := Reconciler{k8sClient: client, scheme: scheme, log: log, controllerConfig: controllerConfig, envBuilder: envBuilder}
+	processReconciler := Reconciler{k8sClient: client, scheme: scheme, log: log, controllerConfig: controllerConfig, envBuilder: envBuilder, usageRecorder: usageRecorder, recorder: recorder}
 	return k8s.NewPatchingReconciler[korifiv1alpha1.CFProcess, *korifiv1alpha1.CFProcess](log, client, &processReconciler)
 }
 
@@ -70,13 +90,26 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) *builder.Builder {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&korifiv1alpha1.CFProcess{}).
 		Owns(&korifiv1alpha1.AppWorkload{}).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
 		Watches(
 			&korifiv1alpha1.CFApp{},
 			handler.EnqueueRequestsFromMapFunc(r.enqueueCFProcessRequestsForApp),
 		).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueCFProcessRequestsForSecret),
+		).
 		Watches(
 			&korifiv1alpha1.CFRoute{},
 			handler.EnqueueRequestsFromMapFunc(r.enqueueCFProcessRequestsForRoute),
+		).
+		Watches(
+			&korifiv1alpha1.CFSpace{},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueCFProcessRequestsForSpace),
+		).
+		Watches(
+			&korifiv1alpha1.CFOrg{},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueCFProcessRequestsForOrg),
 		)
 }
 
@@ -100,6 +133,27 @@ func (r *Reconciler) cfProcessRequestsForAppGUID(ctx context.Context, cfAppNames
 	return requests
 }
 
+// enqueueCFProcessRequestsForSecret enqueues the processes of the apps whose
+// env or service binding credentials are stored in the secret, so that the
+// checksum of their env is kept up to date
+func (r *Reconciler) enqueueCFProcessRequestsForSecret(ctx context.Context, o client.Object) []reconcile.Request {
+	appList := &korifiv1alpha1.CFAppList{}
+	err := r.k8sClient.List(ctx, appList, client.InNamespace(o.GetNamespace()))
+	if err != nil {
+		r.log.Error(fmt.Errorf("listing CFApps for secret failed: %w", err), "secretName", o.GetName())
+		return []reconcile.Request{}
+	}
+
+	var requests []reconcile.Request
+	for _, cfApp := range appList.Items {
+		if slices.Contains(envSecretNames(&cfApp), o.GetName()) {
+			requests = append(requests, r.cfProcessRequestsForAppGUID(ctx, cfApp.Namespace, cfApp.Name)...)
+		}
+	}
+
+	return requests
+}
+
 func (r *Reconciler) enqueueCFProcessRequestsForRoute(ctx context.Context, o client.Object) []reconcile.Request {
 	cfRoute, ok := o.(*korifiv1alpha1.CFRoute)
 	if !ok {
@@ -115,6 +169,38 @@ func (r *Reconciler) enqueueCFProcessRequestsForRoute(ctx context.Context, o cli
 	return result
 }
 
+func (r *Reconciler) enqueueCFProcessRequestsForSpace(ctx context.Context, o client.Object) []reconcile.Request {
+	processList := &korifiv1alpha1.CFProcessList{}
+	err := r.k8sClient.List(ctx, processList, client.InNamespace(o.GetName()))
+	if err != nil {
+		r.log.Error(fmt.Errorf("listing CFProcesses for CFSpace failed: %w", err), "cfSpaceGUID", o.GetName())
+		return []reconcile.Request{}
+	}
+
+	var requests []reconcile.Request
+	for i := range processList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&processList.Items[i])})
+	}
+
+	return requests
+}
+
+func (r *Reconciler) enqueueCFProcessRequestsForOrg(ctx context.Context, o client.Object) []reconcile.Request {
+	spaceList := &korifiv1alpha1.CFSpaceList{}
+	err := r.k8sClient.List(ctx, spaceList, client.InNamespace(o.GetName()))
+	if err != nil {
+		r.log.Error(fmt.Errorf("listing CFSpaces for CFOrg failed: %w", err), "cfOrgGUID", o.GetName())
+		return []reconcile.Request{}
+	}
+
+	var requests []reconcile.Request
+	for i := range spaceList.Items {
+		requests = append(requests, r.enqueueCFProcessRequestsForSpace(ctx, &spaceList.Items[i])...)
+	}
+
+	return requests
+}
+
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfprocesses,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfprocesses/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfprocesses/finalizers,verbs=update
@@ -122,6 +208,12 @@ func (r *Reconciler) enqueueCFProcessRequestsForRoute(ctx context.Context, o cli
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=appworkloads,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=appworkloads/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfspaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cforgs,verbs=get;list;watch
+//+kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfappusageevents,verbs=create
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *Reconciler) ReconcileResource(ctx context.Context, cfProcess *korifiv1alpha1.CFProcess) (ctrl.Result, error) {
 	log := logr.FromContextOrDiscard(ctx)
@@ -151,11 +243,53 @@ func (r *Reconciler) ReconcileResource(ctx context.Context, cfProcess *korifiv1a
 		cfLastStopAppRev = foundValue
 	}
 
+	now := time.Now()
+	var idling *idling
 	if needsAppWorkload(cfApp, cfProcess) {
-		err = r.createOrPatchAppWorkload(ctx, cfApp, cfProcess, cfAppRev, cfLastStopAppRev)
+		autoscaling, err := autoscalingFor(cfProcess)
+		if err != nil {
+			log.Info("error when reading the autoscaling settings of the process", "reason", err)
+			return ctrl.Result{}, err
+		}
+
+		if r.controllerConfig.Idling.Enabled {
+			idling, err = idlingFor(cfProcess, now)
+			if err != nil {
+				log.Info("error when reading the idling settings of the process", "reason", err)
+				return ctrl.Result{}, err
+			}
+		}
+
+		// idle processes are scaled to zero, which autoscalers cannot do
+		if idling.isIdle(now) {
+			autoscaling = nil
+		}
+
+		err = r.createOrPatchAppWorkload(ctx, cfApp, cfProcess, autoscaling, idling.isIdle(now), cfAppRev, cfLastStopAppRev)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
+
+		err = r.reconcileAutoscaler(ctx, cfProcess, autoscaling, generateAppWorkloadName(cfLastStopAppRev, cfProcess.Name))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	} else {
+		err = r.deleteAutoscaler(ctx, cfProcess)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		// the idle timeout starts over when the process is started again
+		delete(cfProcess.Annotations, korifiv1alpha1.LastRequestTimeAnnotationKey)
+	}
+
+	setIdleCondition(cfProcess, idling, now)
+
+	err = r.usageRecorder.RecordProcessUsage(ctx, cfApp, cfProcess, currentUsage(cfApp, cfProcess, idling.isIdle(now)))
+	if err != nil {
+		log.Info("error when recording the usage of the process", "reason", err)
+		return ctrl.Result{}, err
 	}
 
 	err = r.cleanUpAppWorkloads(ctx, cfProcess, cfApp.Spec.DesiredState, cfLastStopAppRev)
@@ -169,8 +303,28 @@ func (r *Reconciler) ReconcileResource(ctx context.Context, cfProcess *korifiv1a
 	}
 
 	cfProcess.Status.ActualInstances = getActualInstances(appWorkloads)
+	meta.SetStatusCondition(&cfProcess.Status.Conditions, k8s.InstancesFailingCondition(cfProcess, workloadConditions(appWorkloads)...))
+	meta.SetStatusCondition(&cfProcess.Status.Conditions, k8s.RunningCondition(cfProcess, cfProcess.Status.ActualInstances))
+
+	return ctrl.Result{RequeueAfter: idling.requeueAfter(now)}, nil
+}
+
+// currentUsage returns the usage of the process as billed by the app usage
+// events: the desired instances of a started app, none while it idles
+func currentUsage(cfApp *korifiv1alpha1.CFApp, cfProcess *korifiv1alpha1.CFProcess, idle bool) usage.Usage {
+	if cfApp.Spec.DesiredState != korifiv1alpha1.StartedState {
+		return usage.Usage{State: korifiv1alpha1.AppUsageStateStopped}
+	}
+
+	current := usage.Usage{
+		State:    korifiv1alpha1.AppUsageStateStarted,
+		MemoryMB: cfProcess.Spec.MemoryMB,
+	}
+	if cfProcess.Spec.DesiredInstances != nil && !idle {
+		current.Instances = *cfProcess.Spec.DesiredInstances
+	}
 
-	return ctrl.Result{}, nil
+	return current
 }
 
 func getActualInstances(appWorkloads []korifiv1alpha1.AppWorkload) int32 {
@@ -181,6 +335,14 @@ func getActualInstances(appWorkloads []korifiv1alpha1.AppWorkload) int32 {
 	return actualInstances
 }
 
+func workloadConditions(appWorkloads []korifiv1alpha1.AppWorkload) [][]metav1.Condition {
+	conditions := [][]metav1.Condition{}
+	for _, w := range appWorkloads {
+		conditions = append(conditions, w.Status.Conditions)
+	}
+	return conditions
+}
+
 func needsAppWorkload(cfApp *korifiv1alpha1.CFApp, cfProcess *korifiv1alpha1.CFProcess) bool {
 	if cfApp.Spec.DesiredState != korifiv1alpha1.StartedState {
 		return false
@@ -190,7 +352,7 @@ func needsAppWorkload(cfApp *korifiv1alpha1.CFApp, cfProcess *korifiv1alpha1.CFP
 	return cfProcess.Spec.DesiredInstances != nil && *cfProcess.Spec.DesiredInstances > 0
 }
 
-func (r *Reconciler) createOrPatchAppWorkload(ctx context.Context, cfApp *korifiv1alpha1.CFApp, cfProcess *korifiv1alpha1.CFProcess, cfAppRev, cfLastStopAppRev string) error {
+func (r *Reconciler) createOrPatchAppWorkload(ctx context.Context, cfApp *korifiv1alpha1.CFApp, cfProcess *korifiv1alpha1.CFProcess, autoscaling *autoscaling, idle bool, cfAppRev, cfLastStopAppRev string) error {
 	log := logr.FromContextOrDiscard(ctx).WithName("createOrPatchAppWorkload")
 
 	cfBuild := new(korifiv1alpha1.CFBuild)
@@ -216,9 +378,9 @@ func (r *Reconciler) createOrPatchAppWorkload(ctx context.Context, cfApp *korifi
 
 	appPorts := ports.FromRoutes(cfRoutesForProcess.Items, cfApp.Name, cfProcess.Spec.ProcessType)
 
-	envVars, err := r.envBuilder.Build(ctx, cfApp, cfProcess)
+	scheduling, err := r.getSpaceScheduling(ctx, cfProcess.Namespace)
 	if err != nil {
-		log.Info("error when trying build the process environment for app", "namespace", cfProcess.Namespace, "name", cfApp.Spec.DisplayName, "reason", err)
+		log.Info("error when trying to get the scheduling options of the space", "namespace", cfProcess.Namespace, "reason", err)
 		return err
 	}
 
@@ -229,21 +391,215 @@ func (r *Reconciler) createOrPatchAppWorkload(ctx context.Context, cfApp *korifi
 		},
 	}
 
+	runConfig, err := r.processRunConfig(ctx, client.ObjectKeyFromObject(actualAppWorkload), cfProcess, cfAppRev)
+	if err != nil {
+		log.Info("error when trying to get the run config of the process", "reason", err)
+		return err
+	}
+	runningProcess := cfProcess.DeepCopy()
+	runConfig.applyTo(runningProcess)
+
+	envVars, err := r.envBuilder.Build(ctx, cfApp, runningProcess, runConfig.EnvVarGroup)
+	if err != nil {
+		log.Info("error when trying build the process environment for app", "namespace", cfProcess.Namespace, "name", cfApp.Spec.DisplayName, "reason", err)
+		return err
+	}
+
 	var desiredAppWorkload *korifiv1alpha1.AppWorkload
-	desiredAppWorkload, err = r.generateAppWorkload(actualAppWorkload, cfApp, cfProcess, cfBuild, appPorts, envVars, cfAppRev, cfLastStopAppRev)
+	desiredAppWorkload, err = r.generateAppWorkload(actualAppWorkload, cfApp, runningProcess, cfBuild, appPorts, envVars, scheduling, cfAppRev, cfLastStopAppRev)
 	if err != nil {
 		log.Info("error when initializing AppWorkload", "reason", err)
 		return err
 	}
 
-	_, err = controllerutil.CreateOrPatch(ctx, r.k8sClient, actualAppWorkload, appWorkloadMutateFunction(actualAppWorkload, desiredAppWorkload))
+	runConfigJSON, err := json.Marshal(runConfig)
+	if err != nil {
+		return err
+	}
+	desiredAppWorkload.Annotations[korifiv1alpha1.ProcessRunConfigAnnotationKey] = string(runConfigJSON)
+
+	if idle {
+		desiredAppWorkload.Spec.Instances = 0
+	}
+
+	if cfApp.Annotations[korifiv1alpha1.DisableEnvRestartAnnotationKey] != "true" {
+		envChecksum, err := r.envChecksum(ctx, cfApp)
+		if err != nil {
+			log.Info("error when computing the env checksum of the app", "namespace", cfApp.Namespace, "name", cfApp.Spec.DisplayName, "reason", err)
+			return err
+		}
+		desiredAppWorkload.Annotations[korifiv1alpha1.EnvChecksumAnnotationKey] = envChecksum
+	}
+
+	var previousInstances int32
+	mutate := appWorkloadMutateFunction(actualAppWorkload, desiredAppWorkload, autoscaling)
+	opResult, err := controllerutil.CreateOrPatch(ctx, r.k8sClient, actualAppWorkload, func() error {
+		previousInstances = actualAppWorkload.Spec.Instances
+		return mutate()
+	})
 	if err != nil {
 		log.Info("error calling CreateOrPatch on AppWorkload", "reason", err)
 		return err
 	}
+
+	// the app is not scaled but started when its workload is created
+	if opResult != controllerutil.OperationResultCreated && actualAppWorkload.Spec.Instances != previousInstances {
+		r.recorder.Eventf(cfApp, corev1.EventTypeNormal, "Scaled", "Scaled process %s from %d to %d instances",
+			cfProcess.Spec.ProcessType, previousInstances, actualAppWorkload.Spec.Instances)
+	}
+
+	return nil
+}
+
+// processRunConfig is the part of the process spec, along with the running
+// env var group, that only takes effect on the next restart of the app
+type processRunConfig struct {
+	Command              string                              `json:"command,omitempty"`
+	HealthCheck          korifiv1alpha1.HealthCheck          `json:"healthCheck"`
+	ReadinessHealthCheck korifiv1alpha1.ReadinessHealthCheck `json:"readinessHealthCheck"`
+	EnvVarGroup          map[string]string                   `json:"envVarGroup,omitempty"`
+}
+
+func (c processRunConfig) applyTo(cfProcess *korifiv1alpha1.CFProcess) {
+	cfProcess.Spec.Command = c.Command
+	cfProcess.Spec.HealthCheck = c.HealthCheck
+	cfProcess.Spec.ReadinessHealthCheck = c.ReadinessHealthCheck
+}
+
+// processRunConfig returns the run config recorded on the AppWorkload while
+// the app has not been restarted since, and the current one of the process
+// otherwise
+func (r *Reconciler) processRunConfig(ctx context.Context, appWorkloadKey client.ObjectKey, cfProcess *korifiv1alpha1.CFProcess, cfAppRev string) (processRunConfig, error) {
+	appWorkload := &korifiv1alpha1.AppWorkload{}
+	err := r.k8sClient.Get(ctx, appWorkloadKey, appWorkload)
+	if client.IgnoreNotFound(err) != nil {
+		return processRunConfig{}, err
+	}
+
+	if err == nil && appWorkload.Spec.Version == cfAppRev {
+		var recordedConfig processRunConfig
+		recordedConfigJSON, ok := appWorkload.Annotations[korifiv1alpha1.ProcessRunConfigAnnotationKey]
+		if ok && json.Unmarshal([]byte(recordedConfigJSON), &recordedConfig) == nil {
+			return recordedConfig, nil
+		}
+	}
+
+	envVarGroup, err := r.envBuilder.EnvVarGroup(ctx)
+	if err != nil {
+		return processRunConfig{}, err
+	}
+
+	return processRunConfig{
+		Command:              cfProcess.Spec.Command,
+		HealthCheck:          cfProcess.Spec.HealthCheck,
+		ReadinessHealthCheck: cfProcess.Spec.ReadinessHealthCheck,
+		EnvVarGroup:          envVarGroup,
+	}, nil
+}
+
+// envChecksum hashes the content of the secrets holding the env and the
+// service binding credentials (VCAP_SERVICES) of the app. Other env vars, e.g.
+// VCAP_APPLICATION, do not restart the app when they change, as in CF.
+func (r *Reconciler) envChecksum(ctx context.Context, cfApp *korifiv1alpha1.CFApp) (string, error) {
+	hash := sha256.New()
+	for _, secretName := range envSecretNames(cfApp) {
+		secret := &corev1.Secret{}
+		if err := r.k8sClient.Get(ctx, client.ObjectKey{Namespace: cfApp.Namespace, Name: secretName}, secret); err != nil {
+			return "", fmt.Errorf("failed to get secret %q: %w", secretName, err)
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(secret.Data)) {
+			fmt.Fprintf(hash, "%s/%s=%q\n", secretName, key, secret.Data[key])
+		}
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+func envSecretNames(cfApp *korifiv1alpha1.CFApp) []string {
+	var secretNames []string
+	for _, secretName := range []string{cfApp.Spec.EnvSecretName, cfApp.Status.VCAPServicesSecretName} {
+		if secretName != "" {
+			secretNames = append(secretNames, secretName)
+		}
+	}
+
+	return secretNames
+}
+
+type schedulingOptions struct {
+	nodeSelector      map[string]string
+	tolerations       []corev1.Toleration
+	priorityClassName string
+	runtimeClassName  string
+}
+
+// getSpaceScheduling returns the scheduling options set by operators via
+// annotations of the CFSpace and its CFOrg, e.g. to run the apps of a tenant
+// on a dedicated node pool. The node selector and tolerations can only be set
+// on the CFSpace, while the priority and runtime class of the space take
+// precedence over the org ones. These annotations cannot be set via the CF API.
+func (r *Reconciler) getSpaceScheduling(ctx context.Context, spaceGUID string) (schedulingOptions, error) {
+	spaceNamespace := &corev1.Namespace{}
+	if err := r.k8sClient.Get(ctx, client.ObjectKey{Name: spaceGUID}, spaceNamespace); err != nil {
+		return schedulingOptions{}, fmt.Errorf("failed to get namespace %q: %w", spaceGUID, err)
+	}
+
+	orgGUID := spaceNamespace.Labels[korifiv1alpha1.OrgGUIDKey]
+	if orgGUID == "" {
+		return schedulingOptions{}, nil
+	}
+
+	cfSpace := &korifiv1alpha1.CFSpace{}
+	if err := r.getIgnoringNotFound(ctx, client.ObjectKey{Namespace: orgGUID, Name: spaceGUID}, cfSpace); err != nil {
+		return schedulingOptions{}, err
+	}
+
+	cfOrg := &korifiv1alpha1.CFOrg{}
+	if err := r.getIgnoringNotFound(ctx, client.ObjectKey{Namespace: r.controllerConfig.CFRootNamespace, Name: orgGUID}, cfOrg); err != nil {
+		return schedulingOptions{}, err
+	}
+
+	scheduling := schedulingOptions{
+		priorityClassName: orgOrSpaceAnnotation(cfOrg, cfSpace, korifiv1alpha1.PriorityClassNameAnnotationKey),
+		runtimeClassName:  orgOrSpaceAnnotation(cfOrg, cfSpace, korifiv1alpha1.RuntimeClassNameAnnotationKey),
+	}
+
+	if nodeSelector, ok := cfSpace.Annotations[korifiv1alpha1.NodeSelectorAnnotationKey]; ok {
+		if err := json.Unmarshal([]byte(nodeSelector), &scheduling.nodeSelector); err != nil {
+			return schedulingOptions{}, fmt.Errorf("invalid %s annotation on CFSpace %q: %w", korifiv1alpha1.NodeSelectorAnnotationKey, spaceGUID, err)
+		}
+	}
+
+	if tolerations, ok := cfSpace.Annotations[korifiv1alpha1.TolerationsAnnotationKey]; ok {
+		if err := json.Unmarshal([]byte(tolerations), &scheduling.tolerations); err != nil {
+			return schedulingOptions{}, fmt.Errorf("invalid %s annotation on CFSpace %q: %w", korifiv1alpha1.TolerationsAnnotationKey, spaceGUID, err)
+		}
+	}
+
+	return scheduling, nil
+}
+
+func (r *Reconciler) getIgnoringNotFound(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	err := r.k8sClient.Get(ctx, key, obj)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %T %q: %w", obj, key, err)
+	}
+
 	return nil
 }
 
+func orgOrSpaceAnnotation(cfOrg *korifiv1alpha1.CFOrg, cfSpace *korifiv1alpha1.CFSpace, key string) string {
+	if value := cfSpace.Annotations[key]; value != "" {
+		return value
+	}
+
+	return cfOrg.Annotations[key]
+}
+
 func (r *Reconciler) cleanUpAppWorkloads(ctx context.Context, cfProcess *korifiv1alpha1.CFProcess, desiredState korifiv1alpha1.AppState, cfLastStopAppRev string) error {
 	log := logr.FromContextOrDiscard(ctx).WithName("cleanUpAppWorkloads")
 
@@ -276,17 +632,25 @@ func needsToDeleteAppWorkload(
 		appWorkload.Name != generateAppWorkloadName(cfLastStopAppRev, cfProcess.Name)
 }
 
-func appWorkloadMutateFunction(actualAppWorkload, desiredAppWorkload *korifiv1alpha1.AppWorkload) controllerutil.MutateFn {
+func appWorkloadMutateFunction(actualAppWorkload, desiredAppWorkload *korifiv1alpha1.AppWorkload, autoscaling *autoscaling) controllerutil.MutateFn {
 	return func() error {
+		currentInstances := actualAppWorkload.Spec.Instances
+
 		actualAppWorkload.Labels = desiredAppWorkload.Labels
 		actualAppWorkload.Annotations = desiredAppWorkload.Annotations
 		actualAppWorkload.OwnerReferences = desiredAppWorkload.OwnerReferences
 		actualAppWorkload.Spec = desiredAppWorkload.Spec
+
+		// the instances of autoscaled workloads are owned by the autoscaler
+		if autoscaling != nil && !actualAppWorkload.CreationTimestamp.IsZero() {
+			actualAppWorkload.Spec.Instances = autoscaling.instances(currentInstances, desiredAppWorkload.Spec.Instances)
+		}
+
 		return nil
 	}
 }
 
-func (r *Reconciler) generateAppWorkload(actualAppWorkload *korifiv1alpha1.AppWorkload, cfApp *korifiv1alpha1.CFApp, cfProcess *korifiv1alpha1.CFProcess, cfBuild *korifiv1alpha1.CFBuild, appPorts []int32, envVars []corev1.EnvVar, cfAppRev, cfLastStopAppRev string) (*korifiv1alpha1.AppWorkload, error) {
+func (r *Reconciler) generateAppWorkload(actualAppWorkload *korifiv1alpha1.AppWorkload, cfApp *korifiv1alpha1.CFApp, cfProcess *korifiv1alpha1.CFProcess, cfBuild *korifiv1alpha1.CFBuild, appPorts []int32, envVars []corev1.EnvVar, scheduling schedulingOptions, cfAppRev, cfLastStopAppRev string) (*korifiv1alpha1.AppWorkload, error) {
 	var desiredAppWorkload korifiv1alpha1.AppWorkload
 	actualAppWorkload.DeepCopyInto(&desiredAppWorkload)
 
@@ -295,23 +659,39 @@ func (r *Reconciler) generateAppWorkload(actualAppWorkload *korifiv1alpha1.AppWo
 	desiredAppWorkload.Labels[korifiv1alpha1.CFAppRevisionKey] = cfAppRev
 	desiredAppWorkload.Labels[korifiv1alpha1.CFProcessGUIDLabelKey] = cfProcess.Name
 	desiredAppWorkload.Labels[korifiv1alpha1.CFProcessTypeLabelKey] = cfProcess.Spec.ProcessType
+	maps.Copy(desiredAppWorkload.Labels, cfMetadata(cfApp.Labels, cfProcess.Labels))
 
 	desiredAppWorkload.Annotations = make(map[string]string)
 	desiredAppWorkload.Annotations[korifiv1alpha1.CFAppLastStopRevisionKey] = cfLastStopAppRev
+	maps.Copy(desiredAppWorkload.Annotations, cfMetadata(cfApp.Annotations, cfProcess.Annotations))
+	if disableTopologySpread, ok := cfApp.Annotations[korifiv1alpha1.DisableTopologySpreadAnnotationKey]; ok {
+		desiredAppWorkload.Annotations[korifiv1alpha1.DisableTopologySpreadAnnotationKey] = disableTopologySpread
+	}
+
+	// as in CF, the memory of the sidecars is taken from the memory of the process
+	sidecarMemory := sidecarMemoryMB(cfProcess)
+	processMemoryMB := cfProcess.Spec.MemoryMB - sidecarMemory
+	if sidecarMemory > 0 && processMemoryMB <= 0 {
+		return nil, k8s.NewNotReadyError().
+			WithReason("SidecarMemoryExceeded").
+			WithMessage("The memory of the process must be greater than the total memory of its sidecars").
+			WithNoRequeue()
+	}
 
 	desiredAppWorkload.Spec.GUID = cfProcess.Name
 	desiredAppWorkload.Spec.Version = cfAppRev
 	desiredAppWorkload.Spec.Resources.Requests = corev1.ResourceList{
 		corev1.ResourceCPU:              calculateCPURequest(cfProcess.Spec.MemoryMB),
 		corev1.ResourceEphemeralStorage: mebibyteQuantity(cfProcess.Spec.DiskQuotaMB),
-		corev1.ResourceMemory:           mebibyteQuantity(cfProcess.Spec.MemoryMB),
+		corev1.ResourceMemory:           mebibyteQuantity(processMemoryMB),
 	}
 	desiredAppWorkload.Spec.Resources.Limits = corev1.ResourceList{
 		corev1.ResourceEphemeralStorage: mebibyteQuantity(cfProcess.Spec.DiskQuotaMB),
-		corev1.ResourceMemory:           mebibyteQuantity(cfProcess.Spec.MemoryMB),
+		corev1.ResourceMemory:           mebibyteQuantity(processMemoryMB),
 	}
 	desiredAppWorkload.Spec.ProcessType = cfProcess.Spec.ProcessType
 	desiredAppWorkload.Spec.Command = commandForProcess(cfProcess, cfApp)
+	desiredAppWorkload.Spec.Sidecars = sidecarsForProcess(cfProcess, cfApp, processMemoryMB)
 	desiredAppWorkload.Spec.AppGUID = cfApp.Name
 	desiredAppWorkload.Spec.Image = cfBuild.Status.Droplet.Registry.Image
 	desiredAppWorkload.Spec.ImagePullSecrets = cfBuild.Status.Droplet.Registry.ImagePullSecrets
@@ -322,9 +702,15 @@ func (r *Reconciler) generateAppWorkload(actualAppWorkload *korifiv1alpha1.AppWo
 	}
 
 	desiredAppWorkload.Spec.Env = envVars
+	desiredAppWorkload.Spec.TerminationGracePeriodSeconds = cfProcess.Spec.TerminationGracePeriodSeconds
+	desiredAppWorkload.Spec.NodeSelector = scheduling.nodeSelector
+	desiredAppWorkload.Spec.Tolerations = scheduling.tolerations
+	desiredAppWorkload.Spec.PriorityClassName = scheduling.priorityClassName
+	desiredAppWorkload.Spec.RuntimeClassName = scheduling.runtimeClassName
 
 	desiredAppWorkload.Spec.StartupProbe = startupProbe(cfProcess, appPorts)
 	desiredAppWorkload.Spec.LivenessProbe = livenessProbe(cfProcess, appPorts)
+	desiredAppWorkload.Spec.ReadinessProbe = readinessProbe(cfProcess, appPorts)
 	desiredAppWorkload.Spec.RunnerName = r.controllerConfig.RunnerName
 
 	err := controllerutil.SetControllerReference(cfProcess, &desiredAppWorkload, r.scheme)
@@ -335,6 +721,22 @@ func (r *Reconciler) generateAppWorkload(actualAppWorkload *korifiv1alpha1.AppWo
 	return &desiredAppWorkload, err
 }
 
+// cfMetadata returns the unprefixed labels or annotations of the app and
+// process, i.e. the ones users set via the CF API, under the CF metadata
+// prefix. The process metadata takes precedence over the app metadata.
+func cfMetadata(appMetadata, processMetadata map[string]string) map[string]string {
+	metadata := map[string]string{}
+	for _, source := range []map[string]string{appMetadata, processMetadata} {
+		for key, value := range source {
+			if !strings.Contains(key, "/") {
+				metadata[korifiv1alpha1.CFMetadataPrefix+key] = value
+			}
+		}
+	}
+
+	return metadata
+}
+
 func calculateCPURequest(memoryMiB int64) resource.Quantity {
 	const (
 		cpuRequestRatio         int64 = 1024
@@ -382,6 +784,10 @@ func commandForProcess(process *korifiv1alpha1.CFProcess, app *korifiv1alpha1.CF
 		return []string{}
 	}
 
+	return launchCommand(cmd, app)
+}
+
+func launchCommand(cmd string, app *korifiv1alpha1.CFApp) []string {
 	if app.Spec.Lifecycle.Type == korifiv1alpha1.BuildpackLifecycle {
 		return []string{"/cnb/lifecycle/launcher", cmd}
 	}
@@ -389,13 +795,47 @@ func commandForProcess(process *korifiv1alpha1.CFProcess, app *korifiv1alpha1.CF
 	return []string{"/bin/sh", "-c", cmd}
 }
 
-func makeProbeHandler(cfProcess *korifiv1alpha1.CFProcess, port int32) corev1.ProbeHandler {
+// sidecarsForProcess runs the sidecars with the memory they reserve. Sidecars
+// that reserve none share the memory left to the process in CF, so they are
+// limited to it without requesting any memory of their own.
+func sidecarsForProcess(process *korifiv1alpha1.CFProcess, app *korifiv1alpha1.CFApp, processMemoryMB int64) []korifiv1alpha1.AppWorkloadSidecar {
+	var sidecars []korifiv1alpha1.AppWorkloadSidecar
+	for _, sidecar := range process.Spec.Sidecars {
+		appWorkloadSidecar := korifiv1alpha1.AppWorkloadSidecar{
+			Name:    sidecar.Name,
+			Command: launchCommand(sidecar.Command, app),
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: mebibyteQuantity(sidecar.MemoryMB)},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: mebibyteQuantity(sidecar.MemoryMB)},
+			},
+		}
+
+		if sidecar.MemoryMB == 0 {
+			appWorkloadSidecar.Resources.Limits[corev1.ResourceMemory] = mebibyteQuantity(processMemoryMB)
+		}
+
+		sidecars = append(sidecars, appWorkloadSidecar)
+	}
+
+	return sidecars
+}
+
+func sidecarMemoryMB(process *korifiv1alpha1.CFProcess) int64 {
+	memoryMB := int64(0)
+	for _, sidecar := range process.Spec.Sidecars {
+		memoryMB += sidecar.MemoryMB
+	}
+
+	return memoryMB
+}
+
+func makeProbeHandler(healthCheckType korifiv1alpha1.HealthCheckType, httpEndpoint string, port int32) corev1.ProbeHandler {
 	var probeHandler corev1.ProbeHandler
 
-	switch cfProcess.Spec.HealthCheck.Type {
+	switch healthCheckType {
 	case korifiv1alpha1.HTTPHealthCheckType:
 		probeHandler.HTTPGet = &corev1.HTTPGetAction{
-			Path: cfProcess.Spec.HealthCheck.Data.HTTPEndpoint,
+			Path: httpEndpoint,
 			Port: intstr.FromInt32(port),
 		}
 	case korifiv1alpha1.PortHealthCheckType:
@@ -417,7 +857,7 @@ func startupProbe(cfProcess *korifiv1alpha1.CFProcess, ports []int32) *corev1.Pr
 	}
 
 	return &corev1.Probe{
-		ProbeHandler:   makeProbeHandler(cfProcess, ports[0]),
+		ProbeHandler:   makeProbeHandler(cfProcess.Spec.HealthCheck.Type, cfProcess.Spec.HealthCheck.Data.HTTPEndpoint, ports[0]),
 		TimeoutSeconds: int32(cfProcess.Spec.HealthCheck.Data.InvocationTimeoutSeconds),
 		PeriodSeconds:  2,
 		FailureThreshold: int32(cfProcess.Spec.HealthCheck.Data.TimeoutSeconds/2 +
@@ -435,13 +875,50 @@ func livenessProbe(cfProcess *korifiv1alpha1.CFProcess, ports []int32) *corev1.P
 	}
 
 	return &corev1.Probe{
-		ProbeHandler:     makeProbeHandler(cfProcess, ports[0]),
+		ProbeHandler:     makeProbeHandler(cfProcess.Spec.HealthCheck.Type, cfProcess.Spec.HealthCheck.Data.HTTPEndpoint, ports[0]),
 		TimeoutSeconds:   int32(cfProcess.Spec.HealthCheck.Data.InvocationTimeoutSeconds),
 		PeriodSeconds:    30,
 		FailureThreshold: 1,
 	}
 }
 
+// readinessProbe takes the process instance out of the routing while its
+// readiness health check is failing. Processes without a readiness health check
+// use their health check, so that they are taken out of the routing before the
+// liveness probe restarts them.
+func readinessProbe(cfProcess *korifiv1alpha1.CFProcess, ports []int32) *corev1.Probe {
+	readinessCheck := cfProcess.Spec.ReadinessHealthCheck
+	if readinessCheck.Type == "" {
+		readinessCheck = korifiv1alpha1.ReadinessHealthCheck{
+			Type: cfProcess.Spec.HealthCheck.Type,
+			Data: korifiv1alpha1.ReadinessHealthCheckData{
+				HTTPEndpoint:             cfProcess.Spec.HealthCheck.Data.HTTPEndpoint,
+				InvocationTimeoutSeconds: cfProcess.Spec.HealthCheck.Data.InvocationTimeoutSeconds,
+			},
+		}
+	}
+
+	if readinessCheck.Type == korifiv1alpha1.ProcessHealthCheckType {
+		return nil
+	}
+
+	if len(ports) == 0 {
+		return nil
+	}
+
+	periodSeconds := readinessCheck.Data.IntervalSeconds
+	if periodSeconds == 0 {
+		periodSeconds = 10
+	}
+
+	return &corev1.Probe{
+		ProbeHandler:     makeProbeHandler(readinessCheck.Type, readinessCheck.Data.HTTPEndpoint, ports[0]),
+		TimeoutSeconds:   readinessCheck.Data.InvocationTimeoutSeconds,
+		PeriodSeconds:    periodSeconds,
+		FailureThreshold: 1,
+	}
+}
+
 func mebibyteQuantity(miB int64) resource.Quantity {
 	return *resource.NewQuantity(miB*1024*1024, resource.BinarySI)
 }
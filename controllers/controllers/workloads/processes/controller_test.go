@@ -2,6 +2,7 @@ package processes_test
 
 import (
 	"context"
+	"time"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/tests/matchers"
@@ -12,21 +13,27 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("CFProcessReconciler Integration Tests", func() {
 	var (
-		cfProcess *korifiv1alpha1.CFProcess
-		cfApp     *korifiv1alpha1.CFApp
-		cfBuild   *korifiv1alpha1.CFBuild
-		cfRoute   *korifiv1alpha1.CFRoute
+		cfProcess    *korifiv1alpha1.CFProcess
+		cfApp        *korifiv1alpha1.CFApp
+		cfBuild      *korifiv1alpha1.CFBuild
+		cfRoute      *korifiv1alpha1.CFRoute
+		appEnvSecret *corev1.Secret
 	)
 
 	BeforeEach(func() {
-		appEnvSecret := &corev1.Secret{
+		appEnvSecret = &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace: testNamespace,
 				Name:      uuid.NewString(),
@@ -234,6 +241,7 @@ var _ = Describe("CFProcessReconciler Integration Tests", func() {
 					MatchFields(IgnoreExtras, Fields{"Name": Equal("VCAP_APP_PORT")}),
 					MatchFields(IgnoreExtras, Fields{"Name": Equal("VCAP_SERVICES")}),
 					MatchFields(IgnoreExtras, Fields{"Name": Equal("env-key")}),
+					MatchFields(IgnoreExtras, Fields{"Name": Equal("CF_INSTANCE_PORT")}),
 					MatchFields(IgnoreExtras, Fields{"Name": Equal("CF_INSTANCE_PORTS")}),
 				))
 
@@ -241,6 +249,133 @@ var _ = Describe("CFProcessReconciler Integration Tests", func() {
 			})
 		})
 
+		When("the CFApp opted out of topology spread", func() {
+			BeforeEach(func() {
+				Expect(k8s.PatchResource(ctx, adminClient, cfApp, func() {
+					cfApp.Annotations[korifiv1alpha1.DisableTopologySpreadAnnotationKey] = "true"
+				})).To(Succeed())
+			})
+
+			It("propagates the annotation to the AppWorkload", func() {
+				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(appWorkload.Annotations).To(HaveKeyWithValue(korifiv1alpha1.DisableTopologySpreadAnnotationKey, "true"))
+				})
+			})
+		})
+
+		When("the CFApp and CFProcess have CF metadata", func() {
+			BeforeEach(func() {
+				Expect(k8s.PatchResource(ctx, adminClient, cfApp, func() {
+					if cfApp.Labels == nil {
+						cfApp.Labels = map[string]string{}
+					}
+					cfApp.Labels["team"] = "app-team"
+					cfApp.Labels["env"] = "prod"
+					cfApp.Labels["example.com/owner"] = "me"
+					cfApp.Annotations["cost-center"] = "1234"
+				})).To(Succeed())
+				Expect(k8s.PatchResource(ctx, adminClient, cfProcess, func() {
+					cfProcess.Labels["team"] = "process-team"
+				})).To(Succeed())
+			})
+
+			It("propagates the unprefixed metadata to the AppWorkload under the CF metadata prefix", func() {
+				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(appWorkload.Labels).To(SatisfyAll(
+						HaveKeyWithValue("metadata.korifi.cloudfoundry.org/team", "process-team"),
+						HaveKeyWithValue("metadata.korifi.cloudfoundry.org/env", "prod"),
+						Not(HaveKey(ContainSubstring("example.com"))),
+					))
+					g.Expect(appWorkload.Annotations).To(HaveKeyWithValue("metadata.korifi.cloudfoundry.org/cost-center", "1234"))
+				})
+			})
+		})
+
+		When("the CFSpace has scheduling annotations", func() {
+			var cfOrg *korifiv1alpha1.CFOrg
+
+			BeforeEach(func() {
+				orgGUID := uuid.NewString()
+				cfOrg = &korifiv1alpha1.CFOrg{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: rootNamespace,
+						Name:      orgGUID,
+						Annotations: map[string]string{
+							korifiv1alpha1.PriorityClassNameAnnotationKey: "org-priority",
+							korifiv1alpha1.RuntimeClassNameAnnotationKey:  "gvisor",
+						},
+					},
+					Spec: korifiv1alpha1.CFOrgSpec{
+						DisplayName: "the-org",
+					},
+				}
+				Expect(adminClient.Create(ctx, cfOrg)).To(Succeed())
+
+				Expect(adminClient.Create(ctx, &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: orgGUID,
+					},
+				})).To(Succeed())
+
+				spaceNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}}
+				Expect(k8s.PatchResource(ctx, adminClient, spaceNamespace, func() {
+					spaceNamespace.Labels = map[string]string{korifiv1alpha1.OrgGUIDKey: orgGUID}
+				})).To(Succeed())
+
+				Expect(adminClient.Create(ctx, &korifiv1alpha1.CFSpace{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: orgGUID,
+						Name:      testNamespace,
+						Annotations: map[string]string{
+							korifiv1alpha1.NodeSelectorAnnotationKey:      `{"pool":"tenant-a"}`,
+							korifiv1alpha1.TolerationsAnnotationKey:       `[{"key":"dedicated","operator":"Equal","value":"tenant-a","effect":"NoSchedule"}]`,
+							korifiv1alpha1.PriorityClassNameAnnotationKey: "space-priority",
+						},
+					},
+					Spec: korifiv1alpha1.CFSpaceSpec{
+						DisplayName: "the-space",
+					},
+				})).To(Succeed())
+			})
+
+			It("sets the node selector and tolerations on the AppWorkload", func() {
+				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(appWorkload.Spec.NodeSelector).To(Equal(map[string]string{"pool": "tenant-a"}))
+					g.Expect(appWorkload.Spec.Tolerations).To(ConsistOf(corev1.Toleration{
+						Key:      "dedicated",
+						Operator: corev1.TolerationOpEqual,
+						Value:    "tenant-a",
+						Effect:   corev1.TaintEffectNoSchedule,
+					}))
+				})
+			})
+
+			It("sets the priority and runtime class on the AppWorkload, preferring the space ones", func() {
+				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(appWorkload.Spec.PriorityClassName).To(Equal("space-priority"))
+					g.Expect(appWorkload.Spec.RuntimeClassName).To(Equal("gvisor"))
+				})
+			})
+
+			When("the CFOrg annotations change", func() {
+				JustBeforeEach(func() {
+					eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+						g.Expect(appWorkload.Spec.RuntimeClassName).To(Equal("gvisor"))
+					})
+
+					Expect(k8s.PatchResource(ctx, adminClient, cfOrg, func() {
+						cfOrg.Annotations[korifiv1alpha1.RuntimeClassNameAnnotationKey] = "kata"
+					})).To(Succeed())
+				})
+
+				It("updates the AppWorkload", func() {
+					eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+						g.Expect(appWorkload.Spec.RuntimeClassName).To(Equal("kata"))
+					})
+				})
+			})
+		})
+
 		When("the CFProcess has an http health check", func() {
 			BeforeEach(func() {
 				cfProcess.Spec.HealthCheck = korifiv1alpha1.HealthCheck{
@@ -253,7 +388,7 @@ var _ = Describe("CFProcessReconciler Integration Tests", func() {
 				}
 			})
 
-			It("sets the startup and liveness probes on the AppWorkload", func() {
+			It("sets the startup, liveness and readiness probes on the AppWorkload", func() {
 				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
 					g.Expect(appWorkload.Spec.StartupProbe).ToNot(BeNil())
 					g.Expect(appWorkload.Spec.StartupProbe.HTTPGet).ToNot(BeNil())
@@ -269,6 +404,14 @@ var _ = Describe("CFProcessReconciler Integration Tests", func() {
 					g.Expect(appWorkload.Spec.LivenessProbe.PeriodSeconds).To(BeEquivalentTo(30))
 					g.Expect(appWorkload.Spec.LivenessProbe.TimeoutSeconds).To(BeEquivalentTo(3))
 					g.Expect(appWorkload.Spec.LivenessProbe.FailureThreshold).To(BeEquivalentTo(1))
+
+					g.Expect(appWorkload.Spec.ReadinessProbe).ToNot(BeNil())
+					g.Expect(appWorkload.Spec.ReadinessProbe.HTTPGet).ToNot(BeNil())
+					g.Expect(appWorkload.Spec.ReadinessProbe.HTTPGet.Path).To(Equal("/healthy"))
+					g.Expect(appWorkload.Spec.ReadinessProbe.HTTPGet.Port.IntValue()).To(Equal(8080))
+					g.Expect(appWorkload.Spec.ReadinessProbe.PeriodSeconds).To(BeEquivalentTo(10))
+					g.Expect(appWorkload.Spec.ReadinessProbe.TimeoutSeconds).To(BeEquivalentTo(3))
+					g.Expect(appWorkload.Spec.ReadinessProbe.FailureThreshold).To(BeEquivalentTo(1))
 				})
 			})
 		})
@@ -284,7 +427,7 @@ var _ = Describe("CFProcessReconciler Integration Tests", func() {
 				}
 			})
 
-			It("sets the startup and liveness probes on the AppWorkload", func() {
+			It("sets the startup, liveness and readiness probes on the AppWorkload", func() {
 				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
 					g.Expect(appWorkload.Spec.StartupProbe).ToNot(BeNil())
 					g.Expect(appWorkload.Spec.StartupProbe.TCPSocket).ToNot(BeNil())
@@ -299,6 +442,73 @@ var _ = Describe("CFProcessReconciler Integration Tests", func() {
 					g.Expect(appWorkload.Spec.LivenessProbe.PeriodSeconds).To(BeEquivalentTo(30))
 					g.Expect(appWorkload.Spec.LivenessProbe.TimeoutSeconds).To(BeEquivalentTo(3))
 					g.Expect(appWorkload.Spec.LivenessProbe.FailureThreshold).To(BeEquivalentTo(1))
+
+					g.Expect(appWorkload.Spec.ReadinessProbe).ToNot(BeNil())
+					g.Expect(appWorkload.Spec.ReadinessProbe.TCPSocket).ToNot(BeNil())
+					g.Expect(appWorkload.Spec.ReadinessProbe.TCPSocket.Port.IntValue()).To(Equal(8080))
+					g.Expect(appWorkload.Spec.ReadinessProbe.PeriodSeconds).To(BeEquivalentTo(10))
+					g.Expect(appWorkload.Spec.ReadinessProbe.TimeoutSeconds).To(BeEquivalentTo(3))
+					g.Expect(appWorkload.Spec.ReadinessProbe.FailureThreshold).To(BeEquivalentTo(1))
+				})
+			})
+		})
+
+		When("the CFProcess has a readiness health check", func() {
+			BeforeEach(func() {
+				cfProcess.Spec.HealthCheck = korifiv1alpha1.HealthCheck{
+					Type: "port",
+					Data: korifiv1alpha1.HealthCheckData{
+						InvocationTimeoutSeconds: 3,
+						TimeoutSeconds:           9,
+					},
+				}
+				cfProcess.Spec.ReadinessHealthCheck = korifiv1alpha1.ReadinessHealthCheck{
+					Type: "http",
+					Data: korifiv1alpha1.ReadinessHealthCheckData{
+						HTTPEndpoint:             "/ready",
+						InvocationTimeoutSeconds: 2,
+						IntervalSeconds:          5,
+					},
+				}
+			})
+
+			It("builds the readiness probe from the readiness health check", func() {
+				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(appWorkload.Spec.LivenessProbe).ToNot(BeNil())
+					g.Expect(appWorkload.Spec.LivenessProbe.TCPSocket).ToNot(BeNil())
+
+					g.Expect(appWorkload.Spec.ReadinessProbe).ToNot(BeNil())
+					g.Expect(appWorkload.Spec.ReadinessProbe.HTTPGet).ToNot(BeNil())
+					g.Expect(appWorkload.Spec.ReadinessProbe.HTTPGet.Path).To(Equal("/ready"))
+					g.Expect(appWorkload.Spec.ReadinessProbe.HTTPGet.Port.IntValue()).To(Equal(8080))
+					g.Expect(appWorkload.Spec.ReadinessProbe.PeriodSeconds).To(BeEquivalentTo(5))
+					g.Expect(appWorkload.Spec.ReadinessProbe.TimeoutSeconds).To(BeEquivalentTo(2))
+					g.Expect(appWorkload.Spec.ReadinessProbe.FailureThreshold).To(BeEquivalentTo(1))
+				})
+			})
+
+			When("the readiness health check type is process", func() {
+				BeforeEach(func() {
+					cfProcess.Spec.ReadinessHealthCheck = korifiv1alpha1.ReadinessHealthCheck{Type: "process"}
+				})
+
+				It("does not set a readiness probe on the AppWorkload", func() {
+					eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+						g.Expect(appWorkload.Spec.LivenessProbe).ToNot(BeNil())
+						g.Expect(appWorkload.Spec.ReadinessProbe).To(BeNil())
+					})
+				})
+			})
+		})
+
+		When("the CFProcess has a termination grace period", func() {
+			BeforeEach(func() {
+				cfProcess.Spec.TerminationGracePeriodSeconds = tools.PtrTo[int64](30)
+			})
+
+			It("sets it on the AppWorkload", func() {
+				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(appWorkload.Spec.TerminationGracePeriodSeconds).To(PointTo(BeEquivalentTo(30)))
 				})
 			})
 		})
@@ -308,10 +518,100 @@ var _ = Describe("CFProcessReconciler Integration Tests", func() {
 				cfProcess.Spec.HealthCheck = korifiv1alpha1.HealthCheck{Type: "process"}
 			})
 
-			It("does not set liveness and startup probes on the AppWorkload", func() {
+			It("does not set any probes on the AppWorkload", func() {
 				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
 					g.Expect(appWorkload.Spec.StartupProbe).To(BeNil())
 					g.Expect(appWorkload.Spec.LivenessProbe).To(BeNil())
+					g.Expect(appWorkload.Spec.ReadinessProbe).To(BeNil())
+				})
+			})
+		})
+
+		It("records a STARTED app usage event", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(processUsageEvents(g, cfProcess.Name)).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+					"Spec": MatchFields(IgnoreExtras, Fields{
+						"State":         Equal(korifiv1alpha1.AppUsageStateStarted),
+						"PreviousState": Equal(korifiv1alpha1.AppUsageStateStopped),
+						"AppGUID":       Equal(cfApp.Name),
+						"AppName":       Equal("test-app-name"),
+						"ProcessType":   Equal(korifiv1alpha1.ProcessTypeWeb),
+						"SpaceGUID":     Equal(testNamespace),
+						"InstanceCount": BeEquivalentTo(1),
+						"MemoryMB":      BeEquivalentTo(1024),
+					}),
+				})))
+			}).Should(Succeed())
+		})
+
+		When("the process is scaled", func() {
+			JustBeforeEach(func() {
+				Eventually(func(g Gomega) {
+					g.Expect(processUsageEvents(g, cfProcess.Name)).To(HaveLen(1))
+				}).Should(Succeed())
+
+				Expect(k8s.PatchResource(ctx, adminClient, cfProcess, func() {
+					cfProcess.Spec.DesiredInstances = tools.PtrTo[int32](3)
+				})).To(Succeed())
+			})
+
+			It("records another STARTED app usage event", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(processUsageEvents(g, cfProcess.Name)).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+						"Spec": MatchFields(IgnoreExtras, Fields{
+							"State":                 Equal(korifiv1alpha1.AppUsageStateStarted),
+							"PreviousState":         Equal(korifiv1alpha1.AppUsageStateStarted),
+							"InstanceCount":         BeEquivalentTo(3),
+							"PreviousInstanceCount": BeEquivalentTo(1),
+						}),
+					})))
+				}).Should(Succeed())
+			})
+
+			It("records a Scaled event on the app", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(recordedEvents(cfApp)).To(ContainElement("Scaled"))
+				}).Should(Succeed())
+			})
+		})
+
+		It("sets the env checksum on the AppWorkload", func() {
+			eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+				g.Expect(appWorkload.Annotations).To(HaveKeyWithValue(korifiv1alpha1.EnvChecksumAnnotationKey, Not(BeEmpty())))
+			})
+		})
+
+		When("the app env changes", func() {
+			var envChecksum string
+
+			JustBeforeEach(func() {
+				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					envChecksum = appWorkload.Annotations[korifiv1alpha1.EnvChecksumAnnotationKey]
+					g.Expect(envChecksum).NotTo(BeEmpty())
+				})
+
+				Expect(k8s.PatchResource(ctx, adminClient, appEnvSecret, func() {
+					appEnvSecret.Data["env-key"] = []byte("another-env-val")
+				})).To(Succeed())
+			})
+
+			It("updates the env checksum", func() {
+				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(appWorkload.Annotations).To(HaveKeyWithValue(korifiv1alpha1.EnvChecksumAnnotationKey, Not(Equal(envChecksum))))
+				})
+			})
+		})
+
+		When("the app opted out of restarts on env changes", func() {
+			BeforeEach(func() {
+				Expect(k8s.PatchResource(ctx, adminClient, cfApp, func() {
+					cfApp.Annotations[korifiv1alpha1.DisableEnvRestartAnnotationKey] = "true"
+				})).To(Succeed())
+			})
+
+			It("does not set the env checksum on the AppWorkload", func() {
+				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(appWorkload.Annotations).NotTo(HaveKey(korifiv1alpha1.EnvChecksumAnnotationKey))
 				})
 			})
 		})
@@ -331,6 +631,187 @@ var _ = Describe("CFProcessReconciler Integration Tests", func() {
 					g.Expect(cfProcess.Status.ActualInstances).To(BeEquivalentTo(3))
 				}).Should(Succeed())
 			})
+
+			It("sets the running condition on the process", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfProcess), cfProcess)).To(Succeed())
+					g.Expect(cfProcess.Status.Conditions).To(ContainElement(SatisfyAll(
+						HaveField("Type", korifiv1alpha1.RunningConditionType),
+						HaveField("Status", metav1.ConditionTrue),
+						HaveField("ObservedGeneration", cfProcess.Generation),
+					)))
+				}).Should(Succeed())
+			})
+		})
+
+		When("the app workload instances are failing", func() {
+			JustBeforeEach(func() {
+				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(k8s.Patch(ctx, adminClient, &appWorkload, func() {
+						meta.SetStatusCondition(&appWorkload.Status.Conditions, metav1.Condition{
+							Type:    korifiv1alpha1.InstancesFailingConditionType,
+							Status:  metav1.ConditionTrue,
+							Reason:  "ImagePullBackOff",
+							Message: "Instance my-app-web-0 cannot start: Back-off pulling image",
+						})
+					})).To(Succeed())
+				})
+			})
+
+			It("sets the instances failing condition on the process", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfProcess), cfProcess)).To(Succeed())
+					g.Expect(cfProcess.Status.Conditions).To(ContainElement(SatisfyAll(
+						HaveField("Type", korifiv1alpha1.InstancesFailingConditionType),
+						HaveField("Status", metav1.ConditionTrue),
+						HaveField("Reason", "ImagePullBackOff"),
+						HaveField("Message", "Instance my-app-web-0 cannot start: Back-off pulling image"),
+					)))
+				}).Should(Succeed())
+			})
+		})
+
+		When("the CFProcess opted into autoscaling", func() {
+			BeforeEach(func() {
+				cfProcess.Spec.DesiredInstances = tools.PtrTo[int32](2)
+				cfProcess.Annotations = map[string]string{
+					korifiv1alpha1.AutoscalingMaxInstancesAnnotationKey: "5",
+					korifiv1alpha1.AutoscalingMemoryTargetAnnotationKey: "60",
+				}
+			})
+
+			It("creates a horizontal pod autoscaler for the AppWorkload", func() {
+				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+					g.Expect(adminClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: cfProcess.Name}, hpa)).To(Succeed())
+
+					g.Expect(hpa.OwnerReferences).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+						"Kind": Equal("CFProcess"),
+						"Name": Equal(cfProcess.Name),
+					})))
+					g.Expect(hpa.Spec.ScaleTargetRef).To(Equal(autoscalingv2.CrossVersionObjectReference{
+						APIVersion: korifiv1alpha1.GroupVersion.String(),
+						Kind:       "AppWorkload",
+						Name:       appWorkload.Name,
+					}))
+					g.Expect(hpa.Spec.MinReplicas).To(PointTo(BeEquivalentTo(2)))
+					g.Expect(hpa.Spec.MaxReplicas).To(BeEquivalentTo(5))
+					g.Expect(hpa.Spec.Metrics).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+						"Resource": PointTo(MatchFields(IgnoreExtras, Fields{
+							"Name": Equal(corev1.ResourceMemory),
+							"Target": MatchFields(IgnoreExtras, Fields{
+								"AverageUtilization": PointTo(BeEquivalentTo(60)),
+							}),
+						})),
+					})))
+				})
+			})
+
+			When("the autoscaler scales the AppWorkload", func() {
+				JustBeforeEach(func() {
+					eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+						g.Expect(k8s.PatchResource(ctx, adminClient, &appWorkload, func() {
+							appWorkload.Spec.Instances = 4
+						})).To(Succeed())
+					})
+
+					Expect(k8s.PatchResource(ctx, adminClient, cfProcess, func() {
+						cfProcess.Spec.MemoryMB = 2048
+					})).To(Succeed())
+				})
+
+				It("keeps the instances chosen by the autoscaler", func() {
+					eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+						g.Expect(appWorkload.Spec.Resources.Limits.Memory()).To(matchers.RepresentResourceQuantity(2048, "Mi"))
+						g.Expect(appWorkload.Spec.Instances).To(BeEquivalentTo(4))
+					})
+				})
+			})
+
+			When("the process opts out of autoscaling", func() {
+				JustBeforeEach(func() {
+					Eventually(func(g Gomega) {
+						g.Expect(adminClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: cfProcess.Name}, &autoscalingv2.HorizontalPodAutoscaler{})).To(Succeed())
+					}).Should(Succeed())
+
+					Expect(k8s.PatchResource(ctx, adminClient, cfProcess, func() {
+						delete(cfProcess.Annotations, korifiv1alpha1.AutoscalingMaxInstancesAnnotationKey)
+					})).To(Succeed())
+				})
+
+				It("deletes the horizontal pod autoscaler", func() {
+					Eventually(func(g Gomega) {
+						err := adminClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: cfProcess.Name}, &autoscalingv2.HorizontalPodAutoscaler{})
+						g.Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+					}).Should(Succeed())
+				})
+
+				It("resets the AppWorkload instances to the process desired instances", func() {
+					eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+						g.Expect(appWorkload.Spec.Instances).To(BeEquivalentTo(2))
+					})
+				})
+			})
+		})
+
+		When("the CFProcess opted into idling", func() {
+			BeforeEach(func() {
+				cfProcess.Spec.DesiredInstances = tools.PtrTo[int32](2)
+				cfProcess.Annotations = map[string]string{
+					korifiv1alpha1.IdleTimeoutAnnotationKey: "30",
+				}
+			})
+
+			It("starts the idle timeout", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfProcess), cfProcess)).To(Succeed())
+					g.Expect(cfProcess.Annotations).To(HaveKey(korifiv1alpha1.LastRequestTimeAnnotationKey))
+					g.Expect(meta.IsStatusConditionFalse(cfProcess.Status.Conditions, korifiv1alpha1.IdleConditionType)).To(BeTrue())
+				}).Should(Succeed())
+			})
+
+			It("runs the desired instances", func() {
+				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(appWorkload.Spec.Instances).To(BeEquivalentTo(2))
+				})
+			})
+
+			When("the process received no requests for the idle timeout", func() {
+				BeforeEach(func() {
+					cfProcess.Annotations[korifiv1alpha1.LastRequestTimeAnnotationKey] = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+				})
+
+				It("scales the AppWorkload to zero", func() {
+					eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+						g.Expect(appWorkload.Spec.Instances).To(BeZero())
+					})
+				})
+
+				It("sets the idle condition", func() {
+					Eventually(func(g Gomega) {
+						g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfProcess), cfProcess)).To(Succeed())
+						g.Expect(meta.IsStatusConditionTrue(cfProcess.Status.Conditions, korifiv1alpha1.IdleConditionType)).To(BeTrue())
+					}).Should(Succeed())
+				})
+
+				When("the process receives a request", func() {
+					JustBeforeEach(func() {
+						eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+							g.Expect(appWorkload.Spec.Instances).To(BeZero())
+						})
+
+						Expect(k8s.PatchResource(ctx, adminClient, cfProcess, func() {
+							cfProcess.Annotations[korifiv1alpha1.LastRequestTimeAnnotationKey] = time.Now().UTC().Format(time.RFC3339)
+						})).To(Succeed())
+					})
+
+					It("wakes the process up", func() {
+						eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+							g.Expect(appWorkload.Spec.Instances).To(BeEquivalentTo(2))
+						})
+					})
+				})
+			})
 		})
 
 		When("The process command field isn't set", func() {
@@ -345,6 +826,122 @@ var _ = Describe("CFProcessReconciler Integration Tests", func() {
 			})
 		})
 
+		When("the command and health check of the process and the env var group change", func() {
+			BeforeEach(func() {
+				cfProcess.Spec.HealthCheck = korifiv1alpha1.HealthCheck{
+					Type: "http",
+					Data: korifiv1alpha1.HealthCheckData{HTTPEndpoint: "/healthy"},
+				}
+			})
+
+			JustBeforeEach(func() {
+				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(appWorkload.Annotations).To(HaveKey(korifiv1alpha1.ProcessRunConfigAnnotationKey))
+				})
+
+				envVarGroup := &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: rootNamespace,
+						Name:      korifiv1alpha1.RunningEnvVarGroupName,
+					},
+					Data: map[string]string{"GROUP_VAR": "group-value"},
+				}
+				Expect(adminClient.Create(ctx, envVarGroup)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(adminClient.Delete(ctx, envVarGroup)).To(Succeed())
+				})
+
+				Expect(k8s.PatchResource(ctx, adminClient, cfProcess, func() {
+					cfProcess.Spec.Command = "another command"
+					cfProcess.Spec.HealthCheck.Data.HTTPEndpoint = "/another-endpoint"
+					cfProcess.Spec.MemoryMB = 2048
+				})).To(Succeed())
+			})
+
+			It("keeps running the previous command, health check and env var group", func() {
+				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(appWorkload.Spec.Resources.Limits.Memory()).To(matchers.RepresentResourceQuantity(2048, "Mi"))
+					g.Expect(appWorkload.Spec.Command).To(ConsistOf("/cnb/lifecycle/launcher", "process command"))
+					g.Expect(appWorkload.Spec.LivenessProbe.HTTPGet.Path).To(Equal("/healthy"))
+					g.Expect(appWorkload.Spec.Env).NotTo(ContainElement(corev1.EnvVar{Name: "GROUP_VAR", Value: "group-value"}))
+				})
+			})
+
+			When("the app is restarted", func() {
+				JustBeforeEach(func() {
+					Expect(k8s.Patch(ctx, adminClient, cfApp, func() {
+						cfApp.Annotations[korifiv1alpha1.CFAppRevisionKey] = "6"
+					})).To(Succeed())
+				})
+
+				It("runs the new command, health check and env var group", func() {
+					eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+						g.Expect(appWorkload.Spec.Command).To(ConsistOf("/cnb/lifecycle/launcher", "another command"))
+						g.Expect(appWorkload.Spec.LivenessProbe.HTTPGet.Path).To(Equal("/another-endpoint"))
+						g.Expect(appWorkload.Spec.Env).To(ContainElement(corev1.EnvVar{Name: "GROUP_VAR", Value: "group-value"}))
+					})
+				})
+			})
+		})
+
+		When("the process has sidecars", func() {
+			BeforeEach(func() {
+				cfProcess.Spec.Sidecars = []korifiv1alpha1.Sidecar{
+					{Name: "agent", Command: "run-agent", MemoryMB: 64},
+					{Name: "proxy", Command: "run-proxy"},
+				}
+			})
+
+			It("adds the sidecars to the app workload", func() {
+				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(appWorkload.Spec.Sidecars).To(ConsistOf(
+						MatchAllFields(Fields{
+							"Name":    Equal("agent"),
+							"Command": Equal([]string{"/cnb/lifecycle/launcher", "run-agent"}),
+							"Resources": Equal(corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+								Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+							}),
+						}),
+						MatchAllFields(Fields{
+							"Name":    Equal("proxy"),
+							"Command": Equal([]string{"/cnb/lifecycle/launcher", "run-proxy"}),
+							"Resources": Equal(corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("0")},
+								Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("960Mi")},
+							}),
+						}),
+					))
+				})
+			})
+
+			It("takes the memory of the sidecars from the process", func() {
+				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(appWorkload.Spec.Resources.Requests.Memory()).To(matchers.RepresentResourceQuantity(960, "Mi"))
+					g.Expect(appWorkload.Spec.Resources.Limits.Memory()).To(matchers.RepresentResourceQuantity(960, "Mi"))
+				})
+			})
+
+			When("the sidecars take all the memory of the process", func() {
+				BeforeEach(func() {
+					cfProcess.Spec.Sidecars = []korifiv1alpha1.Sidecar{
+						{Name: "agent", Command: "run-agent", MemoryMB: 1024},
+					}
+				})
+
+				It("sets the ready condition to false", func() {
+					Eventually(func(g Gomega) {
+						g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfProcess), cfProcess)).To(Succeed())
+						g.Expect(cfProcess.Status.Conditions).To(ContainElement(SatisfyAll(
+							HaveField("Type", korifiv1alpha1.StatusConditionReady),
+							HaveField("Status", metav1.ConditionFalse),
+							HaveField("Reason", "SidecarMemoryExceeded"),
+						)))
+					}).Should(Succeed())
+				})
+			})
+		})
+
 		When("there are no route destinations for the process app", func() {
 			BeforeEach(func() {
 				Expect(k8s.Patch(ctx, adminClient, cfRoute, func() {
@@ -352,10 +949,11 @@ var _ = Describe("CFProcessReconciler Integration Tests", func() {
 				})).To(Succeed())
 			})
 
-			It("does not create startup and liveness probes on the app workload", func() {
+			It("does not create any probes on the app workload", func() {
 				eventuallyCreatedAppWorkloadShould(func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
 					g.Expect(appWorkload.Spec.StartupProbe).To(BeNil())
 					g.Expect(appWorkload.Spec.LivenessProbe).To(BeNil())
+					g.Expect(appWorkload.Spec.ReadinessProbe).To(BeNil())
 				})
 			})
 
@@ -474,3 +1072,17 @@ func eventuallyCreatedAppWorkloadShould(shouldFn func(Gomega, korifiv1alpha1.App
 		shouldFn(g, appWorkloads.Items[0])
 	}).Should(Succeed())
 }
+
+func processUsageEvents(g Gomega, processGUID string) []korifiv1alpha1.CFAppUsageEvent {
+	var events korifiv1alpha1.CFAppUsageEventList
+	g.Expect(adminClient.List(ctx, &events, client.InNamespace(rootNamespace))).To(Succeed())
+
+	result := []korifiv1alpha1.CFAppUsageEvent{}
+	for _, event := range events.Items {
+		if event.Spec.ProcessGUID == processGUID {
+			result = append(result, event)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,91 @@
+package processes
+
+import (
+	"fmt"
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// idling holds the settings of processes that opted into being scaled to zero
+// via the IdleTimeoutAnnotationKey annotation. The activator, which the routes
+// to such processes go through, records the time of the last request in the
+// LastRequestTimeAnnotationKey annotation.
+type idling struct {
+	timeout         time.Duration
+	lastRequestTime time.Time
+}
+
+// idlingFor returns the idling settings of the process. The idle timeout
+// starts when the process is started, i.e. when it has no last request time
+// yet.
+func idlingFor(cfProcess *korifiv1alpha1.CFProcess, now time.Time) (*idling, error) {
+	timeoutMinutes, ok := cfProcess.Annotations[korifiv1alpha1.IdleTimeoutAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	minutes, err := parsePositiveInt32(timeoutMinutes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", korifiv1alpha1.IdleTimeoutAnnotationKey, err)
+	}
+
+	lastRequestTime, ok := cfProcess.Annotations[korifiv1alpha1.LastRequestTimeAnnotationKey]
+	if !ok {
+		lastRequestTime = now.UTC().Format(time.RFC3339)
+		cfProcess.Annotations[korifiv1alpha1.LastRequestTimeAnnotationKey] = lastRequestTime
+	}
+
+	result := &idling{timeout: time.Duration(minutes) * time.Minute}
+	if result.lastRequestTime, err = time.Parse(time.RFC3339, lastRequestTime); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", korifiv1alpha1.LastRequestTimeAnnotationKey, err)
+	}
+
+	return result, nil
+}
+
+func (i *idling) idleSince() time.Time {
+	return i.lastRequestTime.Add(i.timeout)
+}
+
+// isIdle returns whether the process has not received any requests for the
+// idle timeout. Processes that do not idle are never idle.
+func (i *idling) isIdle(now time.Time) bool {
+	return i != nil && !now.Before(i.idleSince())
+}
+
+// requeueAfter returns when the process has to be reconciled again to scale it
+// to zero
+func (i *idling) requeueAfter(now time.Time) time.Duration {
+	if i == nil || i.isIdle(now) {
+		return 0
+	}
+
+	return i.idleSince().Sub(now)
+}
+
+func setIdleCondition(cfProcess *korifiv1alpha1.CFProcess, idling *idling, now time.Time) {
+	if idling == nil {
+		meta.RemoveStatusCondition(&cfProcess.Status.Conditions, korifiv1alpha1.IdleConditionType)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               korifiv1alpha1.IdleConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             "ReceivingRequests",
+		Message:            fmt.Sprintf("The process is scaled to zero after %s without requests", idling.timeout),
+		ObservedGeneration: cfProcess.Generation,
+	}
+
+	if idling.isIdle(now) {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "NoRequests"
+		condition.Message = fmt.Sprintf("The process has been scaled to zero as it received no requests since %s", idling.lastRequestTime.Format(time.RFC3339))
+	}
+
+	meta.SetStatusCondition(&cfProcess.Status.Conditions, condition)
+}
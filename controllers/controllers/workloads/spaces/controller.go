@@ -18,6 +18,7 @@ package spaces
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
@@ -28,9 +29,11 @@ import (
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8s_labels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -45,8 +48,14 @@ type Reconciler struct {
 	containerRegistrySecretNames []string
 	rootNamespace                string
 	appDeletionTimeout           int32
+	ingressNamespaces            []string
+	trustedCAConfigMapName       string
 }
 
+// NetworkPolicyName is the name of the NetworkPolicy isolating the pods of a
+// space from the rest of the cluster
+const NetworkPolicyName = "korifi-space-isolation"
+
 func NewReconciler(
 	client client.Client,
 	log logr.Logger,
@@ -54,6 +63,8 @@ func NewReconciler(
 	rootNamespace string,
 	appDeletionTimeout int32,
 	labelCompiler labels.Compiler,
+	ingressNamespaces []string,
+	trustedCAConfigMapName string,
 ) *k8s.PatchingReconciler[korifiv1alpha1.CFSpace, *korifiv1alpha1.CFSpace] {
 	namespaceController := k8sns.NewReconciler[korifiv1alpha1.CFSpace, *korifiv1alpha1.CFSpace](
 		client,
@@ -74,6 +85,8 @@ func NewReconciler(
 		rootNamespace:                rootNamespace,
 		appDeletionTimeout:           appDeletionTimeout,
 		containerRegistrySecretNames: containerRegistrySecretNames,
+		ingressNamespaces:            ingressNamespaces,
+		trustedCAConfigMapName:       trustedCAConfigMapName,
 	})
 }
 
@@ -90,7 +103,11 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) *builder.Builder {
 		).
 		Watches(
 			&corev1.ServiceAccount{},
-			handler.EnqueueRequestsFromMapFunc(r.enqueueCFSpaceRequestsForServiceAccount),
+			handler.EnqueueRequestsFromMapFunc(r.enqueueCFSpaceRequestsForRootNamespace),
+		).
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueCFSpaceRequestsForTrustedCA),
 		)
 }
 
@@ -109,7 +126,7 @@ func (r *Reconciler) enqueueCFSpaceRequests(ctx context.Context, object client.O
 	return requests
 }
 
-func (r *Reconciler) enqueueCFSpaceRequestsForServiceAccount(ctx context.Context, object client.Object) []reconcile.Request {
+func (r *Reconciler) enqueueCFSpaceRequestsForRootNamespace(ctx context.Context, object client.Object) []reconcile.Request {
 	if object.GetNamespace() != r.rootNamespace {
 		return nil
 	}
@@ -128,6 +145,14 @@ func (r *Reconciler) enqueueCFSpaceRequestsForServiceAccount(ctx context.Context
 	return requests
 }
 
+func (r *Reconciler) enqueueCFSpaceRequestsForTrustedCA(ctx context.Context, object client.Object) []reconcile.Request {
+	if r.trustedCAConfigMapName == "" || object.GetName() != r.trustedCAConfigMapName {
+		return nil
+	}
+
+	return r.enqueueCFSpaceRequestsForRootNamespace(ctx, object)
+}
+
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfspaces,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfspaces/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfspaces/finalizers,verbs=update
@@ -136,6 +161,8 @@ func (r *Reconciler) enqueueCFSpaceRequestsForServiceAccount(ctx context.Context
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=rolebindings,verbs=create;patch;delete;get;list;watch
 //+kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;patch;delete
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;patch
+//+kubebuilder:rbac:groups="networking.k8s.io",resources=networkpolicies,verbs=get;list;watch;create;patch;delete
 
 func (r *Reconciler) ReconcileResource(ctx context.Context, cfSpace *korifiv1alpha1.CFSpace) (ctrl.Result, error) {
 	nsReconcileResult, err := r.namespaceReconciler.ReconcileResource(ctx, cfSpace)
@@ -151,6 +178,18 @@ func (r *Reconciler) ReconcileResource(ctx context.Context, cfSpace *korifiv1alp
 		return ctrl.Result{}, k8s.NewNotReadyError().WithCause(err).WithReason("ServiceAccountPropagation")
 	}
 
+	err = r.reconcileTrustedCA(ctx, cfSpace)
+	if err != nil {
+		log.Info("not ready yet", "reason", "error propagating trusted CA bundle", "error", err)
+		return ctrl.Result{}, k8s.NewNotReadyError().WithCause(err).WithReason("TrustedCAPropagation")
+	}
+
+	err = r.reconcileNetworkPolicy(ctx, cfSpace)
+	if err != nil {
+		log.Info("not ready yet", "reason", "error reconciling network policy", "error", err)
+		return ctrl.Result{}, k8s.NewNotReadyError().WithCause(err).WithReason("NetworkPolicy")
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -258,6 +297,83 @@ func (r *Reconciler) reconcileServiceAccounts(ctx context.Context, space client.
 	return nil
 }
 
+// reconcileTrustedCA copies the trusted CA config map from the root namespace
+// to the space, where the app and task pods mount it
+func (r *Reconciler) reconcileTrustedCA(ctx context.Context, space client.Object) error {
+	if r.trustedCAConfigMapName == "" {
+		return nil
+	}
+
+	rootConfigMap := &corev1.ConfigMap{}
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: r.rootNamespace, Name: r.trustedCAConfigMapName}, rootConfigMap)
+	if err != nil {
+		return fmt.Errorf("error fetching config map %q from namespace %q: %w", r.trustedCAConfigMapName, r.rootNamespace, err)
+	}
+
+	spaceConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.trustedCAConfigMapName,
+			Namespace: space.GetName(),
+		},
+	}
+
+	_, err = controllerutil.CreateOrPatch(ctx, r.client, spaceConfigMap, func() error {
+		if spaceConfigMap.Labels == nil {
+			spaceConfigMap.Labels = map[string]string{}
+		}
+		spaceConfigMap.Labels[korifiv1alpha1.PropagatedFromLabel] = r.rootNamespace
+		spaceConfigMap.Data = rootConfigMap.Data
+		return nil
+	})
+
+	return err
+}
+
+// reconcileNetworkPolicy denies ingress to the pods of the space from other
+// namespaces than the ingress namespaces. The policy is removed when space
+// isolation is disabled, i.e. there are no ingress namespaces.
+func (r *Reconciler) reconcileNetworkPolicy(ctx context.Context, space client.Object) error {
+	networkPolicy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      NetworkPolicyName,
+			Namespace: space.GetName(),
+		},
+	}
+
+	if len(r.ingressNamespaces) == 0 {
+		err := r.client.Get(ctx, client.ObjectKeyFromObject(networkPolicy), networkPolicy)
+		if err != nil {
+			return client.IgnoreNotFound(err)
+		}
+
+		return client.IgnoreNotFound(r.client.Delete(ctx, networkPolicy))
+	}
+
+	_, err := controllerutil.CreateOrPatch(ctx, r.client, networkPolicy, func() error {
+		// allow traffic between the pods of the space
+		peers := []networkingv1.NetworkPolicyPeer{{
+			PodSelector: &metav1.LabelSelector{},
+		}}
+		for _, ns := range r.ingressNamespaces {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{corev1.LabelMetadataName: ns},
+				},
+			})
+		}
+
+		networkPolicy.Spec = networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     []networkingv1.NetworkPolicyIngressRule{{From: peers}},
+		}
+
+		return nil
+	})
+
+	return err
+}
+
 func keepSecrets(serviceAccountName string, secretRefs []corev1.ObjectReference) []corev1.ObjectReference {
 	var results []corev1.ObjectReference
 	for _, secretRef := range secretRefs {
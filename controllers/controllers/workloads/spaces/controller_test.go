@@ -6,6 +6,7 @@ import (
 	"time"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/spaces"
 	"code.cloudfoundry.org/korifi/tools/k8s"
 
 	"github.com/google/uuid"
@@ -13,6 +14,7 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -68,6 +70,32 @@ var _ = Describe("CFSpaceReconciler Integration Tests", func() {
 		}).Should(Succeed())
 	})
 
+	It("propagates the trusted CA config map to CFSpace", func() {
+		Eventually(func(g Gomega) {
+			var configMap corev1.ConfigMap
+			g.Expect(adminClient.Get(ctx, types.NamespacedName{Namespace: cfSpace.Name, Name: trustedCAConfigMapName}, &configMap)).To(Succeed())
+			g.Expect(configMap.Data).To(Equal(map[string]string{"ca.crt": "the-ca-bundle"}))
+		}).Should(Succeed())
+	})
+
+	It("isolates the space namespace with a network policy", func() {
+		Eventually(func(g Gomega) {
+			var networkPolicy networkingv1.NetworkPolicy
+			g.Expect(adminClient.Get(ctx, types.NamespacedName{Namespace: cfSpace.Name, Name: spaces.NetworkPolicyName}, &networkPolicy)).To(Succeed())
+
+			g.Expect(networkPolicy.Spec.PodSelector).To(Equal(metav1.LabelSelector{}))
+			g.Expect(networkPolicy.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress))
+			g.Expect(networkPolicy.Spec.Ingress).To(ConsistOf(networkingv1.NetworkPolicyIngressRule{
+				From: []networkingv1.NetworkPolicyPeer{
+					{PodSelector: &metav1.LabelSelector{}},
+					{NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{corev1.LabelMetadataName: "korifi-gateway"},
+					}},
+				},
+			}))
+		}).Should(Succeed())
+	})
+
 	Describe("service account propagation", func() {
 		var serviceAccount *corev1.ServiceAccount
 
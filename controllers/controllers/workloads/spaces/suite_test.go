@@ -39,6 +39,7 @@ var (
 
 const (
 	packageRegistrySecretName = "test-package-registry-secret"
+	trustedCAConfigMapName    = "test-trusted-ca-bundle"
 )
 
 func TestWorkloadsControllers(t *testing.T) {
@@ -84,6 +85,14 @@ var _ = BeforeSuite(func() {
 		},
 	})).To(Succeed())
 
+	Expect(adminClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      trustedCAConfigMapName,
+			Namespace: cfRootNamespace,
+		},
+		Data: map[string]string{"ca.crt": "the-ca-bundle"},
+	})).To(Succeed())
+
 	err = spaces.NewReconciler(
 		k8sManager.GetClient(),
 		ctrl.Log.WithName("controllers").WithName("CFSpace"),
@@ -91,6 +100,8 @@ var _ = BeforeSuite(func() {
 		cfRootNamespace,
 		int32(2),
 		labelCompiler,
+		[]string{"korifi-gateway"},
+		trustedCAConfigMapName,
 	).SetupWithManager(k8sManager)
 	Expect(err).NotTo(HaveOccurred())
 
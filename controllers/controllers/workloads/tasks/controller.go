@@ -23,6 +23,7 @@ import (
 	"time"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/metrics"
 	"code.cloudfoundry.org/korifi/tools/k8s"
 
 	"github.com/go-logr/logr"
@@ -159,11 +160,29 @@ func (r *Reconciler) setTaskStatus(cfTask *korifiv1alpha1.CFTask, taskWorkloadCo
 		if cond == nil {
 			continue
 		}
+		if cond.Status == metav1.ConditionTrue && !meta.IsStatusConditionTrue(cfTask.Status.Conditions, conditionType) {
+			r.recordTransition(cfTask, *cond)
+		}
 		cond.ObservedGeneration = cfTask.Generation
 		meta.SetStatusCondition(&cfTask.Status.Conditions, *cond)
 	}
 }
 
+// recordTransition records an event on the task when it starts, succeeds or
+// fails, so that its lifecycle shows up in kubectl describe
+func (r *Reconciler) recordTransition(cfTask *korifiv1alpha1.CFTask, cond metav1.Condition) {
+	switch cond.Type {
+	case korifiv1alpha1.TaskStartedConditionType:
+		r.recorder.Eventf(cfTask, corev1.EventTypeNormal, "TaskStarted", "Task %s started", cfTask.Name)
+	case korifiv1alpha1.TaskSucceededConditionType:
+		r.recorder.Eventf(cfTask, corev1.EventTypeNormal, "TaskSucceeded", "Task %s succeeded", cfTask.Name)
+		metrics.RecordTaskCompletion(metrics.ResultSucceeded)
+	case korifiv1alpha1.TaskFailedConditionType:
+		r.recorder.Eventf(cfTask, corev1.EventTypeWarning, "TaskFailed", "Task %s failed: %s", cfTask.Name, cond.Message)
+		metrics.RecordTaskCompletion(metrics.ResultFailed)
+	}
+}
+
 func (r *Reconciler) getApp(ctx context.Context, cfTask *korifiv1alpha1.CFTask) (*korifiv1alpha1.CFApp, error) {
 	log := logr.FromContextOrDiscard(ctx).WithName("getApp").WithValues("appName", cfTask.Spec.AppRef.Name)
 
@@ -279,6 +298,8 @@ func (r *Reconciler) createOrPatchTaskWorkload(ctx context.Context, cfTask *kori
 		taskWorkload.Spec.Resources.Limits[corev1.ResourceEphemeralStorage] = *resource.NewScaledQuantity(cfTask.Status.DiskQuotaMB, resource.Mega)
 		taskWorkload.Spec.Resources.Requests[corev1.ResourceCPU] = *resource.NewScaledQuantity(calculateDefaultCPURequestMillicores(webProcess.Spec.MemoryMB), resource.Milli)
 		taskWorkload.Spec.Env = env
+		taskWorkload.Spec.BackoffLimit = cfTask.Spec.BackoffLimit
+		taskWorkload.Spec.TTLSecondsAfterFinished = cfTask.Spec.TTLSecondsAfterFinished
 
 		if err := ctrl.SetControllerReference(cfTask, taskWorkload, r.scheme); err != nil {
 			log.Info("failed to set owner ref", "reason", err)
@@ -2,6 +2,7 @@ package tasks_test
 
 import (
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools"
 	"code.cloudfoundry.org/korifi/tools/k8s"
 
 	"github.com/google/uuid"
@@ -277,6 +278,24 @@ var _ = Describe("CFTaskReconciler Integration Tests", func() {
 			))
 		})
 
+		When("the task sets a backoff limit and a TTL", func() {
+			BeforeEach(func() {
+				Expect(k8s.PatchResource(ctx, adminClient, cfTask, func() {
+					cfTask.Spec.BackoffLimit = tools.PtrTo[int32](2)
+					cfTask.Spec.TTLSecondsAfterFinished = tools.PtrTo[int32](60)
+				})).To(Succeed())
+			})
+
+			It("sets them on the TaskWorkload", func() {
+				Eventually(func(g Gomega) {
+					taskWorkload := &korifiv1alpha1.TaskWorkload{}
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfTask), taskWorkload)).To(Succeed())
+					g.Expect(taskWorkload.Spec.BackoffLimit).To(PointTo(BeEquivalentTo(2)))
+					g.Expect(taskWorkload.Spec.TTLSecondsAfterFinished).To(PointTo(BeEquivalentTo(60)))
+				}).Should(Succeed())
+			})
+		})
+
 		It("records a TaskWorkloadCreated event", func() {
 			Expect(eventRecorder.EventfCallCount()).To(Equal(eventCallCount+1), "eventRecorder.Eventf call count mismatch")
 			eventTaskObj, eventType, eventReason, eventMessage, eventMessageArgs := eventRecorder.EventfArgsForCall(eventCallCount)
@@ -317,6 +336,18 @@ var _ = Describe("CFTaskReconciler Integration Tests", func() {
 					g.Expect(meta.IsStatusConditionTrue(cfTask.Status.Conditions, korifiv1alpha1.TaskStartedConditionType)).To(BeTrue())
 				}).Should(Succeed())
 			})
+
+			It("records a TaskStarted event", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(eventRecorder.EventfCallCount()).To(BeNumerically(">", eventCallCount+1))
+					eventTaskObj, eventType, eventReason, eventMessage, eventMessageArgs := eventRecorder.EventfArgsForCall(eventCallCount + 1)
+					g.Expect(client.ObjectKeyFromObject(eventTaskObj.(*korifiv1alpha1.CFTask))).To(Equal(client.ObjectKeyFromObject(cfTask)))
+					g.Expect(eventType).To(Equal("Normal"))
+					g.Expect(eventReason).To(Equal("TaskStarted"))
+					g.Expect(eventMessage).To(Equal("Task %s started"))
+					g.Expect(eventMessageArgs).To(Equal([]interface{}{cfTask.Name}))
+				}).Should(Succeed())
+			})
 		})
 	})
 
@@ -0,0 +1,194 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/config"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// CronReconciler reconciles CFCronTasks into scheduled TaskWorkloads, which
+// task runners run on the schedule of the CFCronTask. The TaskWorkload is
+// updated whenever the app changes, so that every run uses the current
+// droplet and environment of the app.
+type CronReconciler struct {
+	k8sClient      client.Client
+	scheme         *runtime.Scheme
+	log            logr.Logger
+	envBuilder     TaskEnvBuilder
+	cfTaskDefaults config.CFTaskDefaults
+}
+
+func NewCronReconciler(
+	client client.Client,
+	scheme *runtime.Scheme,
+	log logr.Logger,
+	envBuilder TaskEnvBuilder,
+	cfTaskDefaults config.CFTaskDefaults,
+) *k8s.PatchingReconciler[korifiv1alpha1.CFCronTask, *korifiv1alpha1.CFCronTask] {
+	cronTaskReconciler := CronReconciler{
+		k8sClient:      client,
+		scheme:         scheme,
+		log:            log,
+		envBuilder:     envBuilder,
+		cfTaskDefaults: cfTaskDefaults,
+	}
+	return k8s.NewPatchingReconciler[korifiv1alpha1.CFCronTask, *korifiv1alpha1.CFCronTask](log, client, &cronTaskReconciler)
+}
+
+func (r *CronReconciler) SetupWithManager(mgr ctrl.Manager) *builder.Builder {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&korifiv1alpha1.CFCronTask{}).
+		Owns(&korifiv1alpha1.TaskWorkload{}).
+		Watches(
+			&korifiv1alpha1.CFApp{},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueCFCronTaskRequestsForApp),
+		)
+}
+
+func (r *CronReconciler) enqueueCFCronTaskRequestsForApp(ctx context.Context, o client.Object) []reconcile.Request {
+	cronTaskList := &korifiv1alpha1.CFCronTaskList{}
+	err := r.k8sClient.List(ctx, cronTaskList, client.InNamespace(o.GetNamespace()), client.MatchingLabels{korifiv1alpha1.CFAppGUIDLabelKey: o.GetName()})
+	if err != nil {
+		r.log.Error(fmt.Errorf("listing CFCronTasks for CFApp guid failed: %w", err), "cfAppGUID", o.GetName())
+		return []reconcile.Request{}
+	}
+
+	var requests []reconcile.Request
+	for i := range cronTaskList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&cronTaskList.Items[i])})
+	}
+
+	return requests
+}
+
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfcrontasks,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfcrontasks/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfcrontasks/finalizers,verbs=update
+
+func (r *CronReconciler) ReconcileResource(ctx context.Context, cfCronTask *korifiv1alpha1.CFCronTask) (ctrl.Result, error) {
+	log := logr.FromContextOrDiscard(ctx)
+
+	cfCronTask.Status.ObservedGeneration = cfCronTask.Generation
+	log.V(1).Info("set observed generation", "generation", cfCronTask.Status.ObservedGeneration)
+
+	if cfCronTask.Labels == nil {
+		cfCronTask.Labels = map[string]string{}
+	}
+	cfCronTask.Labels[korifiv1alpha1.CFAppGUIDLabelKey] = cfCronTask.Spec.AppRef.Name
+
+	cfApp := new(korifiv1alpha1.CFApp)
+	err := r.k8sClient.Get(ctx, types.NamespacedName{Namespace: cfCronTask.Namespace, Name: cfCronTask.Spec.AppRef.Name}, cfApp)
+	if err != nil {
+		log.Info("error getting CFApp", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	err = controllerutil.SetControllerReference(cfApp, cfCronTask, r.scheme)
+	if err != nil {
+		log.Info("unable to set owner reference on CFCronTask", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	if !meta.IsStatusConditionTrue(cfApp.Status.Conditions, korifiv1alpha1.StatusConditionReady) {
+		return ctrl.Result{}, k8s.NewNotReadyError().WithReason("AppNotReady").WithMessage(fmt.Sprintf("App %s is not ready", cfApp.Name))
+	}
+
+	if cfApp.Spec.CurrentDropletRef.Name == "" {
+		return ctrl.Result{}, k8s.NewNotReadyError().WithReason("AppCurrentDropletRefNotSet").WithMessage(fmt.Sprintf("App %s does not have a current droplet", cfApp.Name))
+	}
+
+	cfDroplet := new(korifiv1alpha1.CFBuild)
+	err = r.k8sClient.Get(ctx, types.NamespacedName{Namespace: cfApp.Namespace, Name: cfApp.Spec.CurrentDropletRef.Name}, cfDroplet)
+	if err != nil {
+		log.Info("error getting CFDroplet", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	if cfDroplet.Status.Droplet == nil {
+		return ctrl.Result{}, k8s.NewNotReadyError().WithReason("DropletBuildStatusNotSet").WithMessage(fmt.Sprintf("Current droplet %s from app %s does not have a droplet image", cfDroplet.Name, cfApp.Name))
+	}
+
+	env, err := r.envBuilder.Build(ctx, cfApp)
+	if err != nil {
+		log.Info("failed to build env", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	cfCronTask.Status.DropletRef.Name = cfDroplet.Name
+	cfCronTask.Status.MemoryMB = r.cfTaskDefaults.MemoryMB
+	if cfCronTask.Spec.MemoryMB > 0 {
+		cfCronTask.Status.MemoryMB = cfCronTask.Spec.MemoryMB
+	}
+	cfCronTask.Status.DiskQuotaMB = r.cfTaskDefaults.DiskQuotaMB
+	if cfCronTask.Spec.DiskQuotaMB > 0 {
+		cfCronTask.Status.DiskQuotaMB = cfCronTask.Spec.DiskQuotaMB
+	}
+
+	taskWorkload, err := r.createOrPatchTaskWorkload(ctx, cfCronTask, cfDroplet, env)
+	if err != nil {
+		log.Info("failed to create or patch task workload", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	cfCronTask.Status.LastScheduleTime = taskWorkload.Status.LastScheduleTime
+
+	return ctrl.Result{}, nil
+}
+
+func (r *CronReconciler) createOrPatchTaskWorkload(ctx context.Context, cfCronTask *korifiv1alpha1.CFCronTask, cfDroplet *korifiv1alpha1.CFBuild, env []corev1.EnvVar) (*korifiv1alpha1.TaskWorkload, error) {
+	taskWorkload := &korifiv1alpha1.TaskWorkload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cfCronTask.Name,
+			Namespace: cfCronTask.Namespace,
+		},
+	}
+
+	_, err := controllerutil.CreateOrPatch(ctx, r.k8sClient, taskWorkload, func() error {
+		if taskWorkload.Labels == nil {
+			taskWorkload.Labels = map[string]string{}
+		}
+		taskWorkload.Labels[korifiv1alpha1.CFCronTaskGUIDLabelKey] = cfCronTask.Name
+
+		taskWorkload.Spec.Command = []string{LifecycleLauncherPath, cfCronTask.Spec.Command}
+		taskWorkload.Spec.Image = cfDroplet.Status.Droplet.Registry.Image
+		taskWorkload.Spec.ImagePullSecrets = cfDroplet.Status.Droplet.Registry.ImagePullSecrets
+		taskWorkload.Spec.Resources = corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceMemory:           *resource.NewScaledQuantity(cfCronTask.Status.MemoryMB, resource.Mega),
+				corev1.ResourceEphemeralStorage: *resource.NewScaledQuantity(cfCronTask.Status.DiskQuotaMB, resource.Mega),
+				corev1.ResourceCPU:              *resource.NewScaledQuantity(calculateDefaultCPURequestMillicores(cfCronTask.Status.MemoryMB), resource.Milli),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory:           *resource.NewScaledQuantity(cfCronTask.Status.MemoryMB, resource.Mega),
+				corev1.ResourceEphemeralStorage: *resource.NewScaledQuantity(cfCronTask.Status.DiskQuotaMB, resource.Mega),
+			},
+		}
+		taskWorkload.Spec.Env = env
+		taskWorkload.Spec.Schedule = cfCronTask.Spec.Schedule
+		taskWorkload.Spec.Suspend = cfCronTask.Spec.Suspend
+
+		return ctrl.SetControllerReference(cfCronTask, taskWorkload, r.scheme)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return taskWorkload, nil
+}
@@ -0,0 +1,224 @@
+package tasks_test
+
+import (
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("CFCronTaskReconciler Integration Tests", func() {
+	var (
+		cfApp      *korifiv1alpha1.CFApp
+		cfDroplet  *korifiv1alpha1.CFBuild
+		cfCronTask *korifiv1alpha1.CFCronTask
+	)
+
+	BeforeEach(func() {
+		cfAppName := uuid.NewString()
+
+		cfDroplet = &korifiv1alpha1.CFBuild{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testNamespace,
+				Name:      uuid.NewString(),
+			},
+			Spec: korifiv1alpha1.CFBuildSpec{
+				PackageRef: corev1.LocalObjectReference{
+					Name: uuid.NewString(),
+				},
+				AppRef: corev1.LocalObjectReference{
+					Name: cfAppName,
+				},
+				Lifecycle: korifiv1alpha1.Lifecycle{Type: "buildpack"},
+			},
+		}
+		Expect(adminClient.Create(ctx, cfDroplet)).To(Succeed())
+		Expect(k8s.Patch(ctx, adminClient, cfDroplet, func() {
+			cfDroplet.Status.Droplet = &korifiv1alpha1.BuildDropletStatus{
+				Registry: korifiv1alpha1.Registry{
+					Image: "registry.io/my/image",
+					ImagePullSecrets: []corev1.LocalObjectReference{{
+						Name: "registry-secret",
+					}},
+				},
+			}
+		})).To(Succeed())
+
+		envSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      uuid.NewString(),
+				Namespace: testNamespace,
+			},
+			StringData: map[string]string{
+				"BOB": "flemming",
+			},
+		}
+		Expect(adminClient.Create(ctx, envSecret)).To(Succeed())
+
+		cfApp = &korifiv1alpha1.CFApp{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testNamespace,
+				Name:      cfAppName,
+			},
+			Spec: korifiv1alpha1.CFAppSpec{
+				Lifecycle: korifiv1alpha1.Lifecycle{Type: "buildpack"},
+				CurrentDropletRef: corev1.LocalObjectReference{
+					Name: cfDroplet.Name,
+				},
+				DesiredState:  "STOPPED",
+				DisplayName:   "app",
+				EnvSecretName: envSecret.Name,
+			},
+		}
+		Expect(adminClient.Create(ctx, cfApp)).To(Succeed())
+		Expect(k8s.Patch(ctx, adminClient, cfApp, func() {
+			meta.SetStatusCondition(&cfApp.Status.Conditions, k8s.NewReadyConditionBuilder(cfApp).Ready().Build())
+		})).To(Succeed())
+
+		cfCronTask = &korifiv1alpha1.CFCronTask{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testNamespace,
+				Name:      uuid.NewString(),
+			},
+			Spec: korifiv1alpha1.CFCronTaskSpec{
+				Command:  "rake db:migrate",
+				Schedule: "0 2 * * *",
+				AppRef: corev1.LocalObjectReference{
+					Name: cfApp.Name,
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		Expect(adminClient.Create(ctx, cfCronTask)).To(Succeed())
+	})
+
+	getTaskWorkload := func(g Gomega) *korifiv1alpha1.TaskWorkload {
+		taskWorkload := &korifiv1alpha1.TaskWorkload{}
+		g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfCronTask), taskWorkload)).To(Succeed())
+		return taskWorkload
+	}
+
+	It("becomes ready", func() {
+		Eventually(func(g Gomega) {
+			g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfCronTask), cfCronTask)).To(Succeed())
+			g.Expect(meta.IsStatusConditionTrue(cfCronTask.Status.Conditions, korifiv1alpha1.StatusConditionReady)).To(BeTrue())
+			g.Expect(cfCronTask.Status.ObservedGeneration).To(Equal(cfCronTask.Generation))
+			g.Expect(cfCronTask.Status.DropletRef.Name).To(Equal(cfDroplet.Name))
+			g.Expect(cfCronTask.Status.MemoryMB).To(BeEquivalentTo(256))
+			g.Expect(cfCronTask.Status.DiskQuotaMB).To(BeEquivalentTo(512))
+			g.Expect(cfCronTask.Labels).To(HaveKeyWithValue(korifiv1alpha1.CFAppGUIDLabelKey, cfApp.Name))
+			g.Expect(cfCronTask.GetOwnerReferences()).To(ConsistOf(HaveField("Name", cfApp.Name)))
+		}).Should(Succeed())
+	})
+
+	It("creates a scheduled TaskWorkload", func() {
+		Eventually(func(g Gomega) {
+			taskWorkload := getTaskWorkload(g)
+			g.Expect(taskWorkload.Labels).To(HaveKeyWithValue(korifiv1alpha1.CFCronTaskGUIDLabelKey, cfCronTask.Name))
+			g.Expect(taskWorkload.Spec.Command).To(Equal([]string{"/cnb/lifecycle/launcher", "rake db:migrate"}))
+			g.Expect(taskWorkload.Spec.Image).To(Equal("registry.io/my/image"))
+			g.Expect(taskWorkload.Spec.ImagePullSecrets).To(Equal([]corev1.LocalObjectReference{{Name: "registry-secret"}}))
+			g.Expect(taskWorkload.Spec.Schedule).To(Equal("0 2 * * *"))
+			g.Expect(taskWorkload.Spec.Suspend).To(BeFalse())
+			g.Expect(taskWorkload.Spec.Resources.Limits.Memory().String()).To(Equal("256M"))
+			g.Expect(taskWorkload.Spec.Resources.Limits.StorageEphemeral().String()).To(Equal("512M"))
+			g.Expect(taskWorkload.Spec.Env).To(ContainElement(HaveField("Name", "BOB")))
+			g.Expect(taskWorkload.GetOwnerReferences()).To(ConsistOf(SatisfyAll(
+				HaveField("Name", cfCronTask.Name),
+				HaveField("Controller", PointTo(BeTrue())),
+			)))
+		}).Should(Succeed())
+	})
+
+	When("the cron task sets its resources", func() {
+		BeforeEach(func() {
+			cfCronTask.Spec.MemoryMB = 1024
+			cfCronTask.Spec.DiskQuotaMB = 2048
+		})
+
+		It("uses them instead of the defaults", func() {
+			Eventually(func(g Gomega) {
+				taskWorkload := getTaskWorkload(g)
+				g.Expect(taskWorkload.Spec.Resources.Limits.Memory().String()).To(Equal("1024M"))
+				g.Expect(taskWorkload.Spec.Resources.Limits.StorageEphemeral().String()).To(Equal("2048M"))
+			}).Should(Succeed())
+		})
+	})
+
+	When("the cron task is suspended", func() {
+		JustBeforeEach(func() {
+			Eventually(func(g Gomega) {
+				g.Expect(getTaskWorkload(g).Spec.Suspend).To(BeFalse())
+			}).Should(Succeed())
+
+			Expect(k8s.PatchResource(ctx, adminClient, cfCronTask, func() {
+				cfCronTask.Spec.Suspend = true
+			})).To(Succeed())
+		})
+
+		It("suspends the TaskWorkload", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(getTaskWorkload(g).Spec.Suspend).To(BeTrue())
+			}).Should(Succeed())
+		})
+	})
+
+	When("the app droplet changes", func() {
+		JustBeforeEach(func() {
+			Eventually(func(g Gomega) {
+				g.Expect(getTaskWorkload(g).Spec.Image).To(Equal("registry.io/my/image"))
+			}).Should(Succeed())
+
+			newDroplet := &korifiv1alpha1.CFBuild{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNamespace,
+					Name:      uuid.NewString(),
+				},
+				Spec: cfDroplet.Spec,
+			}
+			Expect(adminClient.Create(ctx, newDroplet)).To(Succeed())
+			Expect(k8s.Patch(ctx, adminClient, newDroplet, func() {
+				newDroplet.Status.Droplet = &korifiv1alpha1.BuildDropletStatus{
+					Registry: korifiv1alpha1.Registry{Image: "registry.io/my/new-image"},
+				}
+			})).To(Succeed())
+
+			Expect(k8s.PatchResource(ctx, adminClient, cfApp, func() {
+				cfApp.Spec.CurrentDropletRef.Name = newDroplet.Name
+			})).To(Succeed())
+		})
+
+		It("runs the task with the new droplet", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(getTaskWorkload(g).Spec.Image).To(Equal("registry.io/my/new-image"))
+			}).Should(Succeed())
+		})
+	})
+
+	When("the app has no droplet", func() {
+		BeforeEach(func() {
+			Expect(k8s.PatchResource(ctx, adminClient, cfApp, func() {
+				cfApp.Spec.CurrentDropletRef.Name = ""
+			})).To(Succeed())
+		})
+
+		It("is not ready", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(cfCronTask), cfCronTask)).To(Succeed())
+				readyCondition := meta.FindStatusCondition(cfCronTask.Status.Conditions, korifiv1alpha1.StatusConditionReady)
+				g.Expect(readyCondition).NotTo(BeNil())
+				g.Expect(readyCondition.Status).To(Equal(metav1.ConditionFalse))
+				g.Expect(readyCondition.Reason).To(Equal("AppCurrentDropletRefNotSet"))
+			}).Should(Succeed())
+		})
+	})
+})
@@ -0,0 +1,216 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
+	"github.com/google/uuid"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Usage is the usage of a process or build last recorded as an app usage
+// event. It is stored on the process or build under
+// korifiv1alpha1.AppUsageAnnotationKey.
+type Usage struct {
+	// Sequence counts the events recorded for a process, so that each of its
+	// events has a distinct guid
+	Sequence  int    `json:"sequence"`
+	State     string `json:"state"`
+	Instances int32  `json:"instances"`
+	MemoryMB  int64  `json:"memoryMB"`
+}
+
+// Recorder records app usage events in the root namespace. The guids of the
+// events are derived from the process or build and its usage, so that
+// recording the same change again, e.g. when a reconcile is retried, does not
+// duplicate the event.
+type Recorder struct {
+	k8sClient     client.Client
+	rootNamespace string
+}
+
+func NewRecorder(k8sClient client.Client, rootNamespace string) *Recorder {
+	return &Recorder{
+		k8sClient:     k8sClient,
+		rootNamespace: rootNamespace,
+	}
+}
+
+// RecordProcessUsage records an event when the usage of the process changed
+// since the last recorded event: the process was started or stopped, or it was
+// scaled while started. The recorded usage is set on the process annotations,
+// which the caller is expected to persist.
+func (r *Recorder) RecordProcessUsage(ctx context.Context, cfApp *korifiv1alpha1.CFApp, cfProcess *korifiv1alpha1.CFProcess, current Usage) error {
+	last := lastUsage(cfProcess)
+	if current.State == last.State &&
+		(current.State == korifiv1alpha1.AppUsageStateStopped || current.Instances == last.Instances && current.MemoryMB == last.MemoryMB) {
+		return nil
+	}
+
+	current.Sequence = last.Sequence + 1
+	err := r.record(ctx, cfApp, string(cfProcess.UID)+"/"+strconv.Itoa(current.Sequence), korifiv1alpha1.CFAppUsageEventSpec{
+		State:                 current.State,
+		PreviousState:         last.State,
+		ProcessGUID:           cfProcess.Name,
+		ProcessType:           cfProcess.Spec.ProcessType,
+		InstanceCount:         current.Instances,
+		PreviousInstanceCount: last.Instances,
+		MemoryMB:              current.MemoryMB,
+		PreviousMemoryMB:      last.MemoryMB,
+	})
+	if err != nil {
+		return err
+	}
+
+	return setUsage(cfProcess, current)
+}
+
+// RecordAppStopped records a STOPPED event for every started process of the
+// app, e.g. when the app is deleted
+func (r *Recorder) RecordAppStopped(ctx context.Context, cfApp *korifiv1alpha1.CFApp) error {
+	var processes korifiv1alpha1.CFProcessList
+	err := r.k8sClient.List(ctx, &processes,
+		client.InNamespace(cfApp.Namespace),
+		client.MatchingLabels{korifiv1alpha1.CFAppGUIDLabelKey: cfApp.Name},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to list the processes of the app: %w", err)
+	}
+
+	for i := range processes.Items {
+		cfProcess := &processes.Items[i]
+		last := lastUsage(cfProcess)
+		if last.State != korifiv1alpha1.AppUsageStateStarted {
+			continue
+		}
+
+		err = r.RecordProcessUsage(ctx, cfApp, cfProcess, Usage{
+			State:     korifiv1alpha1.AppUsageStateStopped,
+			Instances: last.Instances,
+			MemoryMB:  last.MemoryMB,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RecordBuildUsage records a STAGING_STARTED or STAGING_STOPPED event for the
+// build, unless it has already been recorded. The recorded usage is set on the
+// build annotations, which the caller is expected to persist.
+func (r *Recorder) RecordBuildUsage(ctx context.Context, cfApp *korifiv1alpha1.CFApp, cfBuild *korifiv1alpha1.CFBuild, state string) error {
+	last := lastUsage(cfBuild)
+	if last.State == state {
+		return nil
+	}
+	// builds have no usage before staging starts
+	if _, ok := cfBuild.Annotations[korifiv1alpha1.AppUsageAnnotationKey]; !ok {
+		last = Usage{}
+	}
+
+	current := Usage{
+		State:     state,
+		Instances: 1,
+		MemoryMB:  int64(cfBuild.Spec.StagingMemoryMB),
+	}
+	err := r.record(ctx, cfApp, string(cfBuild.UID)+"/"+state, korifiv1alpha1.CFAppUsageEventSpec{
+		State:                 current.State,
+		PreviousState:         last.State,
+		InstanceCount:         current.Instances,
+		PreviousInstanceCount: last.Instances,
+		MemoryMB:              current.MemoryMB,
+		PreviousMemoryMB:      last.MemoryMB,
+	})
+	if err != nil {
+		return err
+	}
+
+	return setUsage(cfBuild, current)
+}
+
+func (r *Recorder) record(ctx context.Context, cfApp *korifiv1alpha1.CFApp, key string, spec korifiv1alpha1.CFAppUsageEventSpec) error {
+	cfSpace, err := r.getSpace(ctx, cfApp.Namespace)
+	if err != nil {
+		return err
+	}
+
+	spec.Timestamp = metav1.NowMicro()
+	spec.AppGUID = cfApp.Name
+	spec.AppName = cfApp.Spec.DisplayName
+	spec.SpaceGUID = cfSpace.Name
+	spec.SpaceName = cfSpace.Spec.DisplayName
+	spec.OrgGUID = cfSpace.Namespace
+
+	err = r.k8sClient.Create(ctx, &korifiv1alpha1.CFAppUsageEvent{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: r.rootNamespace,
+			Name:      uuid.NewSHA1(uuid.NameSpaceOID, []byte(key)).String(),
+			Labels: map[string]string{
+				korifiv1alpha1.CFAppGUIDLabelKey: cfApp.Name,
+			},
+		},
+		Spec: spec,
+	})
+	if k8serrors.IsAlreadyExists(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record app usage event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Recorder) getSpace(ctx context.Context, ns string) (korifiv1alpha1.CFSpace, error) {
+	spaces := korifiv1alpha1.CFSpaceList{}
+	if err := r.k8sClient.List(ctx, &spaces, client.MatchingFields{
+		shared.IndexSpaceNamespaceName: ns,
+	}); err != nil {
+		return korifiv1alpha1.CFSpace{}, fmt.Errorf("error listing cfSpaces: %w", err)
+	}
+
+	// the events are still recorded when the space is gone, e.g. when its
+	// apps are stopped while it is being deleted
+	if len(spaces.Items) == 0 {
+		return korifiv1alpha1.CFSpace{ObjectMeta: metav1.ObjectMeta{Name: ns}}, nil
+	}
+
+	return spaces.Items[0], nil
+}
+
+// lastUsage returns the usage recorded on the object. Objects without a
+// recorded usage are considered stopped.
+func lastUsage(obj client.Object) Usage {
+	usage := Usage{State: korifiv1alpha1.AppUsageStateStopped}
+	if value, ok := obj.GetAnnotations()[korifiv1alpha1.AppUsageAnnotationKey]; ok {
+		// the annotation is only set by the recorder, an invalid value is
+		// treated as no recorded usage
+		_ = json.Unmarshal([]byte(value), &usage)
+	}
+
+	return usage
+}
+
+func setUsage(obj client.Object, usage Usage) error {
+	value, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[korifiv1alpha1.AppUsageAnnotationKey] = string(value)
+	obj.SetAnnotations(annotations)
+
+	return nil
+}
@@ -21,12 +21,15 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"time"
 
+	"code.cloudfoundry.org/korifi/controllers/activator"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/controllers/cleanup"
 	"code.cloudfoundry.org/korifi/controllers/config"
+	"code.cloudfoundry.org/korifi/controllers/controllers/audit"
 	"code.cloudfoundry.org/korifi/controllers/controllers/networking/domains"
 	"code.cloudfoundry.org/korifi/controllers/controllers/networking/routes"
 	"code.cloudfoundry.org/korifi/controllers/controllers/services/bindings"
@@ -35,6 +38,7 @@ import (
 	"code.cloudfoundry.org/korifi/controllers/controllers/services/instances/managed"
 	upsi_instances "code.cloudfoundry.org/korifi/controllers/controllers/services/instances/upsi"
 	"code.cloudfoundry.org/korifi/controllers/controllers/services/osbapi"
+	services_usage "code.cloudfoundry.org/korifi/controllers/controllers/services/usage"
 	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
 	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/apps"
 	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/build/buildpack"
@@ -46,7 +50,11 @@ import (
 	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/processes"
 	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/spaces"
 	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/tasks"
+	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/usage"
 	"code.cloudfoundry.org/korifi/controllers/coordination"
+	"code.cloudfoundry.org/korifi/controllers/metrics"
+	"code.cloudfoundry.org/korifi/controllers/sharding"
+	"code.cloudfoundry.org/korifi/controllers/webhooks"
 	controllersfinalizer "code.cloudfoundry.org/korifi/controllers/webhooks/finalizer"
 	domainswebhook "code.cloudfoundry.org/korifi/controllers/webhooks/networking/domains"
 	routeswebhook "code.cloudfoundry.org/korifi/controllers/webhooks/networking/routes"
@@ -60,15 +68,19 @@ import (
 	packageswebhook "code.cloudfoundry.org/korifi/controllers/webhooks/workloads/packages"
 	spaceswebhook "code.cloudfoundry.org/korifi/controllers/webhooks/workloads/spaces"
 	taskswebhook "code.cloudfoundry.org/korifi/controllers/webhooks/workloads/tasks"
+	deploymentcontrollers "code.cloudfoundry.org/korifi/deployment-runner/controllers"
 	jobtaskrunnercontrollers "code.cloudfoundry.org/korifi/job-task-runner/controllers"
 	"code.cloudfoundry.org/korifi/kpack-image-builder/controllers"
 	kpackimagebuilderfinalizer "code.cloudfoundry.org/korifi/kpack-image-builder/controllers/webhooks/finalizer"
 	statefulsetcontrollers "code.cloudfoundry.org/korifi/statefulset-runner/controllers"
+	instanceidentitywebhook "code.cloudfoundry.org/korifi/statefulset-runner/controllers/webhooks/instanceidentity"
 	"code.cloudfoundry.org/korifi/tools"
 	"code.cloudfoundry.org/korifi/tools/image"
 	"code.cloudfoundry.org/korifi/tools/registry"
+	"code.cloudfoundry.org/korifi/tools/s3"
 	"code.cloudfoundry.org/korifi/version"
 
+	"github.com/go-logr/logr"
 	buildv1alpha2 "github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	servicebindingv1beta1 "github.com/servicebinding/runtime/apis/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -80,8 +92,10 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -99,6 +113,7 @@ func init() {
 	utilruntime.Must(buildv1alpha2.AddToScheme(scheme))
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(gatewayv1beta1.Install(scheme))
+	utilruntime.Must(gatewayv1alpha2.Install(scheme))
 	utilruntime.Must(korifiv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(servicebindingv1beta1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
@@ -114,12 +129,16 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var shardIndex int
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.IntVar(&shardIndex, "shard", sharding.NoShard,
+		"The index of the shard whose apps, builds and routes this replica reconciles when sharding is enabled. "+
+			"Replicas without a shard run all other controllers and the webhooks.")
 
 	configPath, found := os.LookupEnv("CONTROLLERSCONFIG")
 	if !found {
@@ -132,6 +151,11 @@ func main() {
 		panic(errorMessage)
 	}
 
+	shard := sharding.NewShard(controllerConfig.Sharding.Shards, shardIndex)
+	if err = shard.Validate(); err != nil {
+		panic(fmt.Sprintf("invalid shard: %v", err))
+	}
+
 	logger, atomicLevel, err := tools.NewZapLogger(controllerConfig.LogLevel)
 	if err != nil {
 		panic(fmt.Sprintf("error creating new zap logger: %v", err))
@@ -160,61 +184,95 @@ func main() {
 		},
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "13c200ec.cloudfoundry.org",
+		LeaderElectionID:       shard.LeaderElectionID("13c200ec.cloudfoundry.org"),
+		Cache:                  shard.CacheOptions(),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to initialize manager")
 		os.Exit(1)
 	}
 
-	if os.Getenv("ENABLE_CONTROLLERS") != "false" {
+	if shard.IsShardReplica() {
+		setupLog.Info("running the controllers of a shard", "shard", shard.Index, "shards", shard.Count)
+
+		setupShardedControllers(
+			mgr,
+			controllerConfig,
+			ctrl.Log.WithName("controllers"),
+			newImageClient(k8sClient, controllerConfig),
+			newPackageBlobstore(controllerConfig),
+			usage.NewRecorder(mgr.GetClient(), controllerConfig.CFRootNamespace),
+			shard,
+		)
+
+		err = shared.SetupIndexWithManager(mgr)
+		if err != nil {
+			setupLog.Error(err, "unable to setup index on manager")
+			os.Exit(1)
+		}
+	} else if os.Getenv("ENABLE_CONTROLLERS") != "false" {
 		controllersLog := ctrl.Log.WithName("controllers")
-		imageClient := image.NewClient(k8sClient)
+		imageClient := newImageClient(k8sClient, controllerConfig)
+		packageBlobstore := newPackageBlobstore(controllerConfig)
 
-		if err = apps.NewReconciler(
-			mgr.GetClient(),
-			mgr.GetScheme(),
-			controllersLog,
-			env.NewVCAPServicesEnvValueBuilder(mgr.GetClient()),
-			env.NewVCAPApplicationEnvValueBuilder(mgr.GetClient(), controllerConfig.ExtraVCAPApplicationValues),
-		).SetupWithManager(mgr); err != nil {
-			setupLog.Error(err, "unable to create controller", "controller", "CFApp")
+		usageRecorder := usage.NewRecorder(mgr.GetClient(), controllerConfig.CFRootNamespace)
+		ctrlmetrics.Registry.MustRegister(metrics.NewInstancesCollector(mgr.GetClient()))
+
+		// the apps, builds and routes are reconciled by the shard replicas
+		// when sharding is enabled
+		if !shard.Enabled() {
+			setupShardedControllers(mgr, controllerConfig, controllersLog, imageClient, packageBlobstore, usageRecorder, shard)
+		} else if err = mgr.Add(sharding.NewLabeller(mgr.GetClient(), mgr.GetAPIReader(), shard)); err != nil {
+			setupLog.Error(err, "unable to add the shard labeller to the manager")
 			os.Exit(1)
 		}
 
+		packageCleaner := cleanup.NewPackageCleaner(mgr.GetClient(), controllerConfig.MaxRetainedPackagesPerApp)
 		buildCleaner := cleanup.NewBuildCleaner(mgr.GetClient(), controllerConfig.MaxRetainedBuildsPerApp)
-		if err = buildpack.NewReconciler(
+		if err = packages.NewReconciler(
 			mgr.GetClient(),
-			buildCleaner,
 			mgr.GetScheme(),
 			controllersLog,
-			controllerConfig,
-			env.NewAppEnvBuilder(mgr.GetClient()),
+			imageClient,
+			packageBlobstore,
+			packageCleaner,
+			controllerConfig.ContainerRegistrySecretNames,
 		).SetupWithManager(mgr); err != nil {
-			setupLog.Error(err, "unable to create controller", "controller", "CFBuildpackBuild")
+			setupLog.Error(err, "unable to create controller", "controller", "CFPackage")
 			os.Exit(1)
 		}
 
-		if err = docker.NewReconciler(
+		var retentionCleanupInterval time.Duration
+		retentionCleanupInterval, err = controllerConfig.ParseRetentionCleanupInterval()
+		if err != nil {
+			setupLog.Error(err, "error parsing retentionCleanupInterval")
+			os.Exit(1)
+		}
+
+		if err = cleanup.NewAppReconciler(
 			mgr.GetClient(),
-			buildCleaner,
-			imageClient,
-			mgr.GetScheme(),
 			controllersLog,
+			retentionCleanupInterval,
+			packageCleaner,
+			buildCleaner,
 		).SetupWithManager(mgr); err != nil {
-			setupLog.Error(err, "unable to create controller", "controller", "CFDockerBuild")
+			setupLog.Error(err, "unable to create controller", "controller", "CFAppCleanup")
 			os.Exit(1)
 		}
 
-		if err = packages.NewReconciler(
+		var appUsageEventRetention time.Duration
+		appUsageEventRetention, err = controllerConfig.ParseAppUsageEventRetention()
+		if err != nil {
+			setupLog.Error(err, "error parsing appUsageEventRetention")
+			os.Exit(1)
+		}
+
+		if err = cleanup.NewAppUsageEventReconciler(
 			mgr.GetClient(),
-			mgr.GetScheme(),
 			controllersLog,
-			imageClient,
-			cleanup.NewPackageCleaner(mgr.GetClient(), controllerConfig.MaxRetainedPackagesPerApp),
-			controllerConfig.ContainerRegistrySecretNames,
+			appUsageEventRetention,
 		).SetupWithManager(mgr); err != nil {
-			setupLog.Error(err, "unable to create controller", "controller", "CFPackage")
+			setupLog.Error(err, "unable to create controller", "controller", "CFAppUsageEventCleanup")
 			os.Exit(1)
 		}
 
@@ -223,7 +281,9 @@ func main() {
 			mgr.GetScheme(),
 			controllersLog,
 			controllerConfig,
-			env.NewProcessEnvBuilder(mgr.GetClient()),
+			env.NewProcessEnvBuilder(mgr.GetClient(), controllerConfig.CFRootNamespace),
+			usageRecorder,
+			mgr.GetEventRecorderFor("cfprocess-controller"),
 		).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "CFProcess")
 			os.Exit(1)
@@ -243,11 +303,75 @@ func main() {
 			mgr.GetScheme(),
 			controllersLog,
 			upsi_bindings.NewReconciler(mgr.GetClient(), mgr.GetScheme()),
+			bindingWorkloadKind(controllerConfig.RunnerName),
 		)).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "CFServiceBinding")
 			os.Exit(1)
 		}
 
+		if err = services_usage.NewServiceInstanceReconciler(
+			mgr.GetClient(),
+			controllerConfig.CFRootNamespace,
+			controllersLog,
+		).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "CFServiceInstanceUsage")
+			os.Exit(1)
+		}
+
+		if err = services_usage.NewServiceBindingReconciler(
+			mgr.GetClient(),
+			controllerConfig.CFRootNamespace,
+			controllersLog,
+		).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "CFServiceBindingUsage")
+			os.Exit(1)
+		}
+
+		var serviceUsageEventRetention time.Duration
+		serviceUsageEventRetention, err = controllerConfig.ParseServiceUsageEventRetention()
+		if err != nil {
+			setupLog.Error(err, "error parsing serviceUsageEventRetention")
+			os.Exit(1)
+		}
+
+		if err = cleanup.NewServiceUsageEventReconciler(
+			mgr.GetClient(),
+			controllersLog,
+			serviceUsageEventRetention,
+		).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "CFServiceUsageEventCleanup")
+			os.Exit(1)
+		}
+
+		var auditEventRetention time.Duration
+		auditEventRetention, err = controllerConfig.ParseAuditEventRetention()
+		if err != nil {
+			setupLog.Error(err, "error parsing auditEventRetention")
+			os.Exit(1)
+		}
+
+		if err = cleanup.NewAuditEventReconciler(
+			mgr.GetClient(),
+			controllersLog,
+			auditEventRetention,
+		).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "CFAuditEventCleanup")
+			os.Exit(1)
+		}
+
+		if sinkConfig := controllerConfig.AuditEventSink; sinkConfig.Enabled() {
+			if err = audit.NewReconciler(
+				mgr.GetClient(),
+				audit.NewSink(&http.Client{Timeout: 30 * time.Second}, sinkConfig.URL, sinkConfig.Format),
+				controllerConfig.CFRootNamespace,
+				sinkConfig.AuthorizationSecretName,
+				controllersLog,
+			).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "AuditEventSink")
+				os.Exit(1)
+			}
+		}
+
 		labelCompiler := labels.NewCompiler().
 			Defaults(map[string]string{
 				admission.EnforceLevelLabel: string(admission.LevelRestricted),
@@ -272,6 +396,8 @@ func main() {
 			controllerConfig.CFRootNamespace,
 			*controllerConfig.SpaceFinalizerAppDeletionTimeout,
 			labelCompiler,
+			controllerConfig.SpaceIngressNamespaces(),
+			controllerConfig.TrustedCAConfigMapName,
 		).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "CFSpace")
 			os.Exit(1)
@@ -289,17 +415,29 @@ func main() {
 			mgr.GetScheme(),
 			mgr.GetEventRecorderFor("cftask-controller"),
 			controllersLog,
-			env.NewAppEnvBuilder(mgr.GetClient()),
+			env.NewAppEnvBuilder(mgr.GetClient(), controllerConfig.CFRootNamespace),
 			taskTTL,
 		).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "CFTask")
 			os.Exit(1)
 		}
 
+		if err = tasks.NewCronReconciler(
+			mgr.GetClient(),
+			mgr.GetScheme(),
+			controllersLog,
+			env.NewAppEnvBuilder(mgr.GetClient(), controllerConfig.CFRootNamespace),
+			controllerConfig.CFTaskDefaults,
+		).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "CFCronTask")
+			os.Exit(1)
+		}
+
 		if err = domains.NewReconciler(
 			mgr.GetClient(),
 			mgr.GetScheme(),
 			controllersLog,
+			controllerConfig,
 		).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "CFDomain")
 			os.Exit(1)
@@ -344,6 +482,18 @@ func main() {
 				setupLog.Error(err, "error parsing builderReadinessTimeout")
 				os.Exit(1)
 			}
+			var stagingTimeout time.Duration
+			stagingTimeout, err = controllerConfig.ParseStagingTimeout()
+			if err != nil {
+				setupLog.Error(err, "error parsing stagingTimeout")
+				os.Exit(1)
+			}
+			var repositoryNamer *registry.RepositoryNamer
+			repositoryNamer, err = registry.NewRepositoryNamer(mgr.GetClient(), controllerConfig.ContainerRepositoryPrefix, controllerConfig.ContainerRepositoryTemplate)
+			if err != nil {
+				setupLog.Error(err, "invalid containerRepositoryTemplate")
+				os.Exit(1)
+			}
 			if err = controllers.NewBuildWorkloadReconciler(
 				mgr.GetClient(),
 				mgr.GetScheme(),
@@ -351,8 +501,10 @@ func main() {
 				controllerConfig,
 				imageClient,
 				controllerConfig.ContainerRepositoryPrefix,
+				repositoryNamer,
 				registry.NewRepositoryCreator(controllerConfig.ContainerRegistryType),
 				builderReadinessTimeout,
+				stagingTimeout,
 			).SetupWithManager(mgr); err != nil {
 				setupLog.Error(err, "unable to create controller", "controller", "BuildWorkload")
 				os.Exit(1)
@@ -393,7 +545,9 @@ func main() {
 				mgr.GetScheme(),
 				jobtaskrunnercontrollers.NewStatusGetter(mgr.GetClient()),
 				jobTTL,
+				controllerConfig.JobBackoffLimit,
 				controllerConfig.JobTaskRunnerTemporarySetPodSeccompProfile,
+				controllerConfig.TrustedCAConfigMapName,
 			)
 			if err = taskWorkloadReconciler.SetupWithManager(mgr); err != nil {
 				setupLog.Error(err, "unable to create controller", "controller", "TaskWorkload")
@@ -402,14 +556,29 @@ func main() {
 		}
 
 		if controllerConfig.IncludeStatefulsetRunner {
+			var certificateValidity time.Duration
+			certificateValidity, err = controllerConfig.ParseCertificateValidity()
+			if err != nil {
+				setupLog.Error(err, "error parsing instance identity certificateValidity")
+				os.Exit(1)
+			}
+
 			if err = statefulsetcontrollers.NewAppWorkloadReconciler(
 				mgr.GetClient(),
 				mgr.GetScheme(),
 				statefulsetcontrollers.NewAppWorkloadToStatefulsetConverter(
 					mgr.GetScheme(),
 					controllerConfig.StatefulsetRunnerTemporarySetPodSeccompProfile,
+					controllerConfig.StatefulsetRunnerTopologySpread,
+					controllerConfig.StatefulsetRunnerNodePlacement,
+					controllerConfig.StatefulsetRunnerPriorityClassName,
+					controllerConfig.StatefulsetRunnerRuntimeClassName,
+					controllerConfig.StatefulsetRunnerSecurityContext,
+					controllerConfig.StatefulsetRunnerInstanceIdentity.Enabled(),
+					controllerConfig.TrustedCAConfigMapName,
 				),
-				statefulsetcontrollers.NewPDBUpdater(mgr.GetClient()),
+				statefulsetcontrollers.NewPDBUpdater(mgr.GetClient(), controllerConfig.StatefulsetRunnerPodDisruptionBudget),
+				statefulsetcontrollers.NewInstanceIdentityIssuer(mgr.GetClient(), controllerConfig.StatefulsetRunnerInstanceIdentity, certificateValidity),
 				controllersLog,
 			).SetupWithManager(mgr); err != nil {
 				setupLog.Error(err, "unable to create controller", "controller", "AppWorkload")
@@ -424,23 +593,81 @@ func main() {
 				setupLog.Error(err, "unable to create controller", "controller", "RunnerInfo")
 				os.Exit(1)
 			}
+
+			if err = statefulsetcontrollers.NewCrashReporter(
+				mgr.GetClient(),
+				mgr.GetEventRecorderFor("statefulset-runner"),
+				controllersLog,
+			).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "CrashReporter")
+				os.Exit(1)
+			}
 		}
 
-		if err = routes.NewReconciler(
-			mgr.GetClient(),
-			mgr.GetScheme(),
-			controllersLog,
-			controllerConfig,
-		).SetupWithManager(mgr); err != nil {
-			setupLog.Error(err, "unable to create controller", "controller", "CFRoute")
-			os.Exit(1)
+		if controllerConfig.IncludeDeploymentRunner {
+			if err = deploymentcontrollers.NewAppWorkloadReconciler(
+				mgr.GetClient(),
+				mgr.GetScheme(),
+				deploymentcontrollers.NewAppWorkloadToDeploymentConverter(
+					// The seccomp profile is only optional in the
+					// statefulset-runner to avoid restarting existing apps
+					statefulsetcontrollers.NewAppWorkloadToStatefulsetConverter(
+						mgr.GetScheme(),
+						true,
+						controllerConfig.StatefulsetRunnerTopologySpread,
+						controllerConfig.StatefulsetRunnerNodePlacement,
+						controllerConfig.StatefulsetRunnerPriorityClassName,
+						controllerConfig.StatefulsetRunnerRuntimeClassName,
+						controllerConfig.StatefulsetRunnerSecurityContext,
+						false,
+						controllerConfig.TrustedCAConfigMapName,
+					),
+				),
+				statefulsetcontrollers.NewPDBUpdater(mgr.GetClient(), controllerConfig.StatefulsetRunnerPodDisruptionBudget),
+				controllersLog,
+			).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "DeploymentRunnerAppWorkload")
+				os.Exit(1)
+			}
+
+			if err = deploymentcontrollers.NewRunnerInfoReconciler(
+				mgr.GetClient(),
+				mgr.GetScheme(),
+				controllersLog,
+			).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "DeploymentRunnerRunnerInfo")
+				os.Exit(1)
+			}
+		}
+
+		if controllerConfig.Idling.Enabled {
+			var wakeTimeout time.Duration
+			wakeTimeout, err = controllerConfig.ParseWakeTimeout()
+			if err != nil {
+				setupLog.Error(err, "error parsing idling wakeTimeout")
+				os.Exit(1)
+			}
+
+			if err = mgr.Add(activator.New(
+				mgr.GetClient(),
+				mgr.GetAPIReader(),
+				controllerConfig.Idling.ActivatorPort,
+				wakeTimeout,
+				loadActivatorSigningKey(controllerConfig),
+				ctrl.Log.WithName("activator"),
+			)); err != nil {
+				setupLog.Error(err, "unable to add activator")
+				os.Exit(1)
+			}
 		}
 
 	}
 
 	// Setup webhooks with manager
 
-	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+	if shard.IsShardReplica() {
+		setupLog.Info("skipping webhook setup because the replica runs a controller shard")
+	} else if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		if err = (&korifiv1alpha1.CFApp{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "CFApp")
 			os.Exit(1)
@@ -458,9 +685,13 @@ func main() {
 		}
 
 		if err = korifiv1alpha1.NewCFProcessDefaulter(
+			mgr.GetAPIReader(),
+			controllerConfig.CFRootNamespace,
 			controllerConfig.CFProcessDefaults.MemoryMB,
 			controllerConfig.CFProcessDefaults.DiskQuotaMB,
+			*controllerConfig.CFProcessDefaults.WebInstances,
 			*controllerConfig.CFProcessDefaults.Timeout,
+			*controllerConfig.CFProcessDefaults.TerminationGracePeriodSeconds,
 		).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "CFProcess")
 			os.Exit(1)
@@ -475,23 +706,24 @@ func main() {
 		}
 
 		if err = appswebhook.NewValidator(
-			validation.NewDuplicateValidator(coordination.NewNameRegistry(uncachedClient, appswebhook.AppEntityType)),
+			nameValidator(controllerConfig.NameUniqueness.Apps, uncachedClient, appswebhook.AppEntityType),
 		).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "CFApp")
 			os.Exit(1)
 		}
 
 		if err = routeswebhook.NewValidator(
-			validation.NewDuplicateValidator(coordination.NewNameRegistry(uncachedClient, routeswebhook.RouteEntityType)),
+			nameValidator(controllerConfig.NameUniqueness.Routes, uncachedClient, routeswebhook.RouteEntityType),
 			controllerConfig.CFRootNamespace,
 			uncachedClient,
+			controllerConfig.ReservedRouteHosts,
 		).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "CFRoute")
 			os.Exit(1)
 		}
 
 		if err = instanceswebhook.NewValidator(
-			validation.NewDuplicateValidator(coordination.NewNameRegistry(uncachedClient, instanceswebhook.ServiceInstanceEntityType)),
+			nameValidator(controllerConfig.NameUniqueness.ServiceInstances, uncachedClient, instanceswebhook.ServiceInstanceEntityType),
 		).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "CFServiceInstance")
 			os.Exit(1)
@@ -539,17 +771,18 @@ func main() {
 			os.Exit(1)
 		}
 
-		if err = taskswebhook.NewDefaulter(controllerConfig.CFProcessDefaults).SetupWebhookWithManager(mgr); err != nil {
+		if err = taskswebhook.NewDefaulter(controllerConfig.CFTaskDefaults).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "CFTask")
 			os.Exit(1)
 		}
 
-		if err = taskswebhook.NewValidator().SetupWebhookWithManager(mgr); err != nil {
+		if err = taskswebhook.NewValidator(mgr.GetAPIReader(), controllerConfig.MaxConcurrentTasksPerSpace).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "CFTask")
 			os.Exit(1)
 		}
 
 		versionwebhook.NewVersionWebhook(version.Version).SetupWebhookWithManager(mgr)
+		sharding.NewLabelWebhook(shard).SetupWebhookWithManager(mgr)
 		controllersfinalizer.NewControllersFinalizerWebhook().SetupWebhookWithManager(mgr)
 
 		if err = packageswebhook.NewValidator().SetupWebhookWithManager(mgr); err != nil {
@@ -561,6 +794,10 @@ func main() {
 			kpackimagebuilderfinalizer.NewKpackImageBuilderFinalizerWebhook().SetupWebhookWithManager(mgr)
 		}
 
+		if controllerConfig.IncludeStatefulsetRunner {
+			instanceidentitywebhook.NewInstanceIdentityWebhook().SetupWebhookWithManager(mgr)
+		}
+
 		if err = mgr.AddReadyzCheck("readyz", mgr.GetWebhookServer().StartedChecker()); err != nil {
 			setupLog.Error(err, "unable to set up ready check")
 			os.Exit(1)
@@ -591,3 +828,137 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// newImageClient returns the client for the images in the container registry
+func newImageClient(k8sClient k8sclient.Interface, controllerConfig *config.ControllerConfig) image.Client {
+	imageDeletionPolicy, err := controllerConfig.ParseContainerRegistryImageDeletion()
+	if err != nil {
+		setupLog.Error(err, "error parsing containerRegistryImageDeletion")
+		os.Exit(1)
+	}
+
+	return image.NewClient(k8sClient, controllerConfig.InsecureContainerRegistries...).WithDeletionPolicy(imageDeletionPolicy)
+}
+
+// newPackageBlobstore returns the client for the package bits in the s3
+// blobstore, nil when the package bits are stored in the registry
+func newPackageBlobstore(controllerConfig *config.ControllerConfig) *s3.Client {
+	if !controllerConfig.PackageBlobstore.Enabled() {
+		return nil
+	}
+
+	packageBlobstore, err := s3.NewDefaultClient(
+		context.Background(),
+		controllerConfig.PackageBlobstore.Endpoint,
+		controllerConfig.PackageBlobstore.Bucket,
+		controllerConfig.PackageBlobstore.Region,
+	)
+	if err != nil {
+		setupLog.Error(err, "unable to create the package blobstore client")
+		os.Exit(1)
+	}
+
+	return packageBlobstore
+}
+
+// loadActivatorSigningKey returns the key the requests to the activator are
+// signed with, nil when idling is disabled
+func loadActivatorSigningKey(controllerConfig *config.ControllerConfig) []byte {
+	if !controllerConfig.Idling.Enabled {
+		return nil
+	}
+
+	signingKey, err := controllerConfig.LoadActivatorSigningKey()
+	if err != nil {
+		setupLog.Error(err, "unable to load the activator signing key")
+		os.Exit(1)
+	}
+
+	return signingKey
+}
+
+// setupShardedControllers sets up the controllers of apps, builds and routes,
+// which only reconcile the namespaces owned by the shard of the replica
+func setupShardedControllers(
+	mgr ctrl.Manager,
+	controllerConfig *config.ControllerConfig,
+	controllersLog logr.Logger,
+	imageClient image.Client,
+	packageBlobstore *s3.Client,
+	usageRecorder *usage.Recorder,
+	shard sharding.Shard,
+) {
+	if err := apps.NewReconciler(
+		mgr.GetClient(),
+		mgr.GetScheme(),
+		controllersLog,
+		env.NewVCAPServicesEnvValueBuilder(mgr.GetClient()),
+		env.NewVCAPApplicationEnvValueBuilder(mgr.GetClient(), controllerConfig.ExtraVCAPApplicationValues),
+		usageRecorder,
+		mgr.GetEventRecorderFor("cfapp-controller"),
+	).WithNamespaceFilter(shard.Owns).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CFApp")
+		os.Exit(1)
+	}
+
+	buildCleaner := cleanup.NewBuildCleaner(mgr.GetClient(), controllerConfig.MaxRetainedBuildsPerApp)
+	if err := buildpack.NewReconciler(
+		mgr.GetClient(),
+		buildCleaner,
+		mgr.GetScheme(),
+		controllersLog,
+		controllerConfig,
+		env.NewBuildEnvBuilder(mgr.GetClient(), controllerConfig.CFRootNamespace),
+		usageRecorder,
+		mgr.GetEventRecorderFor("cfbuild-controller"),
+		packageBlobstore,
+	).WithNamespaceFilter(shard.Owns).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CFBuildpackBuild")
+		os.Exit(1)
+	}
+
+	if err := docker.NewReconciler(
+		mgr.GetClient(),
+		buildCleaner,
+		imageClient,
+		mgr.GetScheme(),
+		controllersLog,
+		usageRecorder,
+		mgr.GetEventRecorderFor("cfbuild-controller"),
+	).WithNamespaceFilter(shard.Owns).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CFDockerBuild")
+		os.Exit(1)
+	}
+
+	if err := routes.NewReconciler(
+		mgr.GetClient(),
+		mgr.GetScheme(),
+		controllersLog,
+		controllerConfig,
+		loadActivatorSigningKey(controllerConfig),
+	).WithNamespaceFilter(shard.Owns).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CFRoute")
+		os.Exit(1)
+	}
+}
+
+// nameValidator returns the validator rejecting duplicate names of the entity
+// type, unless their uniqueness is relaxed
+func nameValidator(uniqueness string, k8sClient client.Client, entityType string) webhooks.NameValidator {
+	nameRegistry := coordination.NewNameRegistry(k8sClient, entityType)
+	if uniqueness == config.UniquenessRelaxed {
+		return validation.NewRelaxedDuplicateValidator(nameRegistry)
+	}
+
+	return validation.NewDuplicateValidator(nameRegistry)
+}
+
+// bindingWorkloadKind returns the kind of the workloads the runner creates for
+// apps, which service bindings are projected into
+func bindingWorkloadKind(runnerName string) string {
+	if runnerName == deploymentcontrollers.AppWorkloadReconcilerName {
+		return "Deployment"
+	}
+
+	return "StatefulSet"
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const collectTimeout = 10 * time.Second
+
+var runningInstancesDesc = prometheus.NewDesc(
+	"korifi_app_instances_running",
+	"Number of running app instances per org and space",
+	[]string{"org_guid", "space_guid"},
+	nil,
+)
+
+// InstancesCollector collects the running instances of the CFProcesses of
+// each space on every scrape. It is expected to read from the cache of the
+// manager, so that scraping does not hit the API server.
+type InstancesCollector struct {
+	k8sClient client.Reader
+}
+
+func NewInstancesCollector(k8sClient client.Reader) *InstancesCollector {
+	return &InstancesCollector{
+		k8sClient: k8sClient,
+	}
+}
+
+func (c *InstancesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- runningInstancesDesc
+}
+
+func (c *InstancesCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	spaceList := &korifiv1alpha1.CFSpaceList{}
+	if err := c.k8sClient.List(ctx, spaceList); err != nil {
+		ch <- prometheus.NewInvalidMetric(runningInstancesDesc, err)
+		return
+	}
+
+	processList := &korifiv1alpha1.CFProcessList{}
+	if err := c.k8sClient.List(ctx, processList); err != nil {
+		ch <- prometheus.NewInvalidMetric(runningInstancesDesc, err)
+		return
+	}
+
+	runningInstances := map[string]int32{}
+	for _, process := range processList.Items {
+		runningInstances[process.Namespace] += process.Status.ActualInstances
+	}
+
+	for _, space := range spaceList.Items {
+		if space.Status.GUID == "" {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			runningInstancesDesc,
+			prometheus.GaugeValue,
+			float64(runningInstances[space.Status.GUID]),
+			space.Namespace,
+			space.Status.GUID,
+		)
+	}
+}
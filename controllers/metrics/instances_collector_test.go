@@ -0,0 +1,85 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/fake"
+	"code.cloudfoundry.org/korifi/controllers/metrics"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("InstancesCollector", func() {
+	var (
+		k8sClient *fake.Client
+		collector *metrics.InstancesCollector
+		listErr   error
+	)
+
+	BeforeEach(func() {
+		listErr = nil
+		k8sClient = new(fake.Client)
+		k8sClient.ListStub = func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+			if listErr != nil {
+				return listErr
+			}
+
+			switch list := list.(type) {
+			case *korifiv1alpha1.CFSpaceList:
+				list.Items = []korifiv1alpha1.CFSpace{
+					{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "org-guid", Name: "space-1"},
+						Status:     korifiv1alpha1.CFSpaceStatus{GUID: "space-1"},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "org-guid", Name: "space-2"},
+						Status:     korifiv1alpha1.CFSpaceStatus{GUID: "space-2"},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "org-guid", Name: "space-not-ready"},
+					},
+				}
+			case *korifiv1alpha1.CFProcessList:
+				list.Items = []korifiv1alpha1.CFProcess{
+					{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "space-1", Name: "process-1"},
+						Status:     korifiv1alpha1.CFProcessStatus{ActualInstances: 2},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "space-1", Name: "process-2"},
+						Status:     korifiv1alpha1.CFProcessStatus{ActualInstances: 1},
+					},
+				}
+			}
+			return nil
+		}
+
+		collector = metrics.NewInstancesCollector(k8sClient)
+	})
+
+	It("collects the running instances of every space", func() {
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP korifi_app_instances_running Number of running app instances per org and space
+# TYPE korifi_app_instances_running gauge
+korifi_app_instances_running{org_guid="org-guid",space_guid="space-1"} 3
+korifi_app_instances_running{org_guid="org-guid",space_guid="space-2"} 0
+`))).To(Succeed())
+	})
+
+	When("listing fails", func() {
+		BeforeEach(func() {
+			listErr = errors.New("list-err")
+		})
+
+		It("reports an invalid metric", func() {
+			Expect(testutil.CollectAndCompare(collector, strings.NewReader(""))).To(MatchError(ContainSubstring("list-err")))
+		})
+	})
+})
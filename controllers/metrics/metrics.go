@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	ResultSucceeded = "succeeded"
+	ResultFailed    = "failed"
+	ResultCanceled  = "canceled"
+)
+
+var (
+	stagingDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "korifi_staging_duration_seconds",
+		Help:    "Time from the creation of a CFBuild until its staging completed",
+		Buckets: []float64{10, 30, 60, 120, 300, 600, 900, 1800},
+	}, []string{"lifecycle", "result"})
+
+	taskCompletions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "korifi_task_completions_total",
+		Help: "Number of CFTasks that completed",
+	}, []string{"result"})
+
+	routeProgrammingDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "korifi_route_programming_duration_seconds",
+		Help:    "Time until the gateway route of a CFRoute was programmed since the CFRoute was created or last not ready",
+		Buckets: []float64{0.1, 0.5, 1, 2, 5, 10, 30, 60},
+	}, []string{"protocol"})
+)
+
+// The metrics are registered with the controller-runtime registry, so that
+// the manager serves them next to the default controller metrics
+func init() {
+	ctrlmetrics.Registry.MustRegister(stagingDuration, taskCompletions, routeProgrammingDuration)
+}
+
+// ObserveStaging observes the staging duration of a build that just completed
+func ObserveStaging(cfBuild *korifiv1alpha1.CFBuild, result string) {
+	stagingDuration.
+		WithLabelValues(string(cfBuild.Spec.Lifecycle.Type), result).
+		Observe(time.Since(cfBuild.CreationTimestamp.Time).Seconds())
+}
+
+// RecordTaskCompletion counts a task that just succeeded or failed
+func RecordTaskCompletion(result string) {
+	taskCompletions.WithLabelValues(result).Inc()
+}
+
+// ObserveRouteProgrammed observes the programming latency of a route whose
+// gateway route was just reconciled, i.e. the time since it was created or
+// since it was last not ready. Routes that were already ready are not
+// observed.
+func ObserveRouteProgrammed(cfRoute *korifiv1alpha1.CFRoute) {
+	since := cfRoute.CreationTimestamp.Time
+	if readyCondition := meta.FindStatusCondition(cfRoute.Status.Conditions, korifiv1alpha1.StatusConditionReady); readyCondition != nil {
+		if readyCondition.Status == metav1.ConditionTrue {
+			return
+		}
+		since = readyCondition.LastTransitionTime.Time
+	}
+
+	protocol := cfRoute.Spec.Protocol
+	if protocol == "" {
+		protocol = korifiv1alpha1.ProtocolHTTP
+	}
+
+	routeProgrammingDuration.
+		WithLabelValues(string(protocol)).
+		Observe(time.Since(since).Seconds())
+}
@@ -0,0 +1,130 @@
+package metrics_test
+
+import (
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/metrics"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("Metrics", func() {
+	Describe("RecordTaskCompletion", func() {
+		var failedBefore float64
+
+		BeforeEach(func() {
+			failedBefore = countSamples("korifi_task_completions_total", map[string]string{"result": metrics.ResultFailed})
+		})
+
+		It("counts the completed tasks by result", func() {
+			metrics.RecordTaskCompletion(metrics.ResultFailed)
+			Expect(countSamples("korifi_task_completions_total", map[string]string{"result": metrics.ResultFailed})).To(Equal(failedBefore + 1))
+		})
+	})
+
+	Describe("ObserveStaging", func() {
+		var cfBuild *korifiv1alpha1.CFBuild
+
+		BeforeEach(func() {
+			cfBuild = &korifiv1alpha1.CFBuild{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Minute)),
+				},
+				Spec: korifiv1alpha1.CFBuildSpec{
+					Lifecycle: korifiv1alpha1.Lifecycle{Type: "docker"},
+				},
+			}
+		})
+
+		It("observes the staging duration by lifecycle and result", func() {
+			labels := map[string]string{"lifecycle": "docker", "result": metrics.ResultSucceeded}
+			countBefore := countSamples("korifi_staging_duration_seconds", labels)
+			metrics.ObserveStaging(cfBuild, metrics.ResultSucceeded)
+			Expect(countSamples("korifi_staging_duration_seconds", labels)).To(Equal(countBefore + 1))
+		})
+	})
+
+	Describe("ObserveRouteProgrammed", func() {
+		var (
+			cfRoute     *korifiv1alpha1.CFRoute
+			labels      map[string]string
+			countBefore float64
+		)
+
+		BeforeEach(func() {
+			cfRoute = &korifiv1alpha1.CFRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Second)),
+				},
+			}
+			labels = map[string]string{"protocol": "http"}
+			countBefore = countSamples("korifi_route_programming_duration_seconds", labels)
+		})
+
+		JustBeforeEach(func() {
+			metrics.ObserveRouteProgrammed(cfRoute)
+		})
+
+		It("observes the programming latency", func() {
+			Expect(countSamples("korifi_route_programming_duration_seconds", labels)).To(Equal(countBefore + 1))
+		})
+
+		When("the route is already ready", func() {
+			BeforeEach(func() {
+				meta.SetStatusCondition(&cfRoute.Status.Conditions, metav1.Condition{
+					Type:   korifiv1alpha1.StatusConditionReady,
+					Status: metav1.ConditionTrue,
+					Reason: "Ready",
+				})
+			})
+
+			It("does not observe it again", func() {
+				Expect(countSamples("korifi_route_programming_duration_seconds", labels)).To(Equal(countBefore))
+			})
+		})
+	})
+})
+
+// countSamples returns the value of a counter, or the sample count of a
+// histogram, with the given labels from the controller-runtime registry
+func countSamples(name string, labels map[string]string) float64 {
+	families, err := ctrlmetrics.Registry.Gather()
+	Expect(err).NotTo(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			metricLabels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				metricLabels[label.GetName()] = label.GetValue()
+			}
+			if !matchesLabels(metricLabels, labels) {
+				continue
+			}
+
+			if metric.GetHistogram() != nil {
+				return float64(metric.GetHistogram().GetSampleCount())
+			}
+			return metric.GetCounter().GetValue()
+		}
+	}
+
+	return 0
+}
+
+func matchesLabels(metricLabels, labels map[string]string) bool {
+	for k, v := range labels {
+		if metricLabels[k] != v {
+			return false
+		}
+	}
+	return true
+}
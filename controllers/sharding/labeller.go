@@ -0,0 +1,71 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/korifi/tools/k8s"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Labeller sets the ShardLabelKey label on the existing sharded objects on
+// startup, e.g. on the objects created before sharding was enabled or before
+// the number of shards changed. The LabelWebhook labels the objects created
+// afterwards.
+type Labeller struct {
+	k8sClient client.Client
+	apiReader client.Reader
+	shard     Shard
+}
+
+func NewLabeller(k8sClient client.Client, apiReader client.Reader, shard Shard) *Labeller {
+	return &Labeller{
+		k8sClient: k8sClient,
+		apiReader: apiReader,
+		shard:     shard,
+	}
+}
+
+func (l *Labeller) Start(ctx context.Context) error {
+	if !l.shard.Enabled() {
+		return nil
+	}
+
+	for _, obj := range ShardedObjects() {
+		gvk, err := apiutil.GVKForObject(obj, l.k8sClient.Scheme())
+		if err != nil {
+			return err
+		}
+
+		objList := &metav1.PartialObjectMetadataList{}
+		objList.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err = l.apiReader.List(ctx, objList); err != nil {
+			return fmt.Errorf("failed to list %s objects: %w", gvk.Kind, err)
+		}
+
+		for i := range objList.Items {
+			item := &objList.Items[i]
+			item.SetGroupVersionKind(gvk)
+
+			shardLabel := l.shard.LabelFor(item.Namespace)
+			if item.Labels[ShardLabelKey] == shardLabel {
+				continue
+			}
+
+			err = k8s.PatchResource(ctx, l.k8sClient, item, func() {
+				if item.Labels == nil {
+					item.Labels = map[string]string{}
+				}
+				item.Labels[ShardLabelKey] = shardLabel
+			})
+			if client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to label %s %s/%s with its shard: %w", gvk.Kind, item.Namespace, item.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
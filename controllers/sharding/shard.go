@@ -0,0 +1,127 @@
+package sharding
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Shard is the part of the namespaces whose apps, builds and routes a
+// controllers replica reconciles when sharding is enabled. Namespaces are
+// assigned to shards by the hash of their name, so that every replica agrees
+// on the assignment without coordination. Each shard elects its own leader.
+//
+// A replica without a shard index runs all the controllers but the sharded
+// ones.
+type Shard struct {
+	Count int
+	Index int
+}
+
+const (
+	NoShard = -1
+
+	// ShardLabelKey on the objects watched by the sharded controllers holds
+	// the index of the shard owning their namespace, so that shard replicas
+	// only cache the objects of their own shard
+	ShardLabelKey = "korifi.cloudfoundry.org/shard"
+)
+
+// ShardedObjects returns the types of the objects in the space namespaces
+// that the sharded controllers reconcile or watch
+func ShardedObjects() []client.Object {
+	return []client.Object{
+		&korifiv1alpha1.CFApp{},
+		&korifiv1alpha1.CFProcess{},
+		&korifiv1alpha1.CFBuild{},
+		&korifiv1alpha1.BuildWorkload{},
+		&korifiv1alpha1.CFRoute{},
+		&korifiv1alpha1.CFServiceBinding{},
+	}
+}
+
+func NewShard(count, index int) Shard {
+	return Shard{
+		Count: count,
+		Index: index,
+	}
+}
+
+func (s Shard) Validate() error {
+	if s.Index < NoShard || s.Enabled() && s.Index >= s.Count {
+		return fmt.Errorf("shard index %d is not between 0 and %d", s.Index, s.Count-1)
+	}
+
+	if !s.Enabled() && s.Index != NoShard {
+		return fmt.Errorf("shard index %d is set but sharding is disabled", s.Index)
+	}
+
+	return nil
+}
+
+// Enabled is true when the sharded controllers are split across more than
+// one shard
+func (s Shard) Enabled() bool {
+	return s.Count > 1
+}
+
+// IsShardReplica is true for the replicas that only run the sharded
+// controllers of one shard
+func (s Shard) IsShardReplica() bool {
+	return s.Enabled() && s.Index != NoShard
+}
+
+// Owns is true when the objects in the namespace are reconciled by this
+// shard. All namespaces are owned when sharding is disabled.
+func (s Shard) Owns(namespace string) bool {
+	if !s.Enabled() {
+		return true
+	}
+
+	return s.indexOf(namespace) == s.Index
+}
+
+// LabelFor returns the value of the ShardLabelKey label of the objects in the
+// namespace
+func (s Shard) LabelFor(namespace string) string {
+	return strconv.Itoa(s.indexOf(namespace))
+}
+
+func (s Shard) indexOf(namespace string) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(namespace))
+	return int(hash.Sum32() % uint32(s.Count))
+}
+
+// CacheOptions restricts the cache of shard replicas to the sharded objects
+// labelled with their shard. The other objects the sharded controllers read,
+// e.g. the domains in the root namespace, are cached in full.
+func (s Shard) CacheOptions() cache.Options {
+	if !s.IsShardReplica() {
+		return cache.Options{}
+	}
+
+	shardSelector := labels.SelectorFromSet(labels.Set{ShardLabelKey: strconv.Itoa(s.Index)})
+	byObject := map[client.Object]cache.ByObject{}
+	for _, obj := range ShardedObjects() {
+		byObject[obj] = cache.ByObject{Label: shardSelector}
+	}
+
+	return cache.Options{ByObject: byObject}
+}
+
+// LeaderElectionID suffixes the leader election id of shard replicas with the
+// shard index, so that each shard elects its own leader
+func (s Shard) LeaderElectionID(id string) string {
+	if !s.IsShardReplica() {
+		return id
+	}
+
+	return fmt.Sprintf("%s-shard-%d", id, s.Index)
+}
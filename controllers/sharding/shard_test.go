@@ -0,0 +1,135 @@
+package sharding_test
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/korifi/controllers/sharding"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shard", func() {
+	var shard sharding.Shard
+
+	BeforeEach(func() {
+		shard = sharding.NewShard(3, 1)
+	})
+
+	Describe("Validate", func() {
+		It("succeeds", func() {
+			Expect(shard.Validate()).To(Succeed())
+		})
+
+		When("the index is out of range", func() {
+			BeforeEach(func() {
+				shard.Index = 3
+			})
+
+			It("returns an error", func() {
+				Expect(shard.Validate()).To(MatchError(ContainSubstring("not between 0 and 2")))
+			})
+		})
+
+		When("sharding is disabled", func() {
+			BeforeEach(func() {
+				shard.Count = 1
+			})
+
+			It("returns an error", func() {
+				Expect(shard.Validate()).To(MatchError(ContainSubstring("sharding is disabled")))
+			})
+
+			When("the replica has no shard", func() {
+				BeforeEach(func() {
+					shard.Index = sharding.NoShard
+				})
+
+				It("succeeds", func() {
+					Expect(shard.Validate()).To(Succeed())
+				})
+			})
+		})
+	})
+
+	Describe("Owns", func() {
+		It("assigns every namespace to exactly one shard", func() {
+			for i := range 100 {
+				namespace := fmt.Sprintf("cf-space-%d", i)
+
+				owners := 0
+				for index := range 3 {
+					if sharding.NewShard(3, index).Owns(namespace) {
+						owners++
+					}
+				}
+				Expect(owners).To(Equal(1), namespace)
+			}
+		})
+
+		It("spreads the namespaces across the shards", func() {
+			owned := 0
+			for i := range 300 {
+				if shard.Owns(fmt.Sprintf("cf-space-%d", i)) {
+					owned++
+				}
+			}
+			Expect(owned).To(BeNumerically("~", 100, 30))
+		})
+
+		When("sharding is disabled", func() {
+			BeforeEach(func() {
+				shard = sharding.NewShard(0, sharding.NoShard)
+			})
+
+			It("owns all namespaces", func() {
+				Expect(shard.Owns("cf-space-1")).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("LabelFor", func() {
+		It("labels the namespaces owned by the shard with its index", func() {
+			for i := range 100 {
+				namespace := fmt.Sprintf("cf-space-%d", i)
+				Expect(shard.LabelFor(namespace) == "1").To(Equal(shard.Owns(namespace)), namespace)
+			}
+		})
+	})
+
+	Describe("CacheOptions", func() {
+		It("restricts the sharded objects to the ones labelled with the shard", func() {
+			cacheOptions := shard.CacheOptions()
+			Expect(cacheOptions.ByObject).To(HaveLen(len(sharding.ShardedObjects())))
+			for obj, byObject := range cacheOptions.ByObject {
+				Expect(byObject.Label.String()).To(Equal(sharding.ShardLabelKey+"=1"), fmt.Sprintf("%T", obj))
+			}
+		})
+
+		When("the replica has no shard", func() {
+			BeforeEach(func() {
+				shard.Index = sharding.NoShard
+			})
+
+			It("does not restrict the cache", func() {
+				Expect(shard.CacheOptions().ByObject).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("LeaderElectionID", func() {
+		It("suffixes the id with the shard index", func() {
+			Expect(shard.LeaderElectionID("korifi")).To(Equal("korifi-shard-1"))
+		})
+
+		When("the replica has no shard", func() {
+			BeforeEach(func() {
+				shard.Index = sharding.NoShard
+			})
+
+			It("returns the id", func() {
+				Expect(shard.LeaderElectionID("korifi")).To(Equal("korifi"))
+			})
+		})
+	})
+})
@@ -0,0 +1,66 @@
+package sharding
+
+//+kubebuilder:webhook:path=/mutate-korifi-cloudfoundry-org-v1alpha1-all-shard,mutating=true,failurePolicy=fail,sideEffects=None,groups=korifi.cloudfoundry.org,resources=cfapps;cfprocesses;cfbuilds;buildworkloads;cfroutes;cfservicebindings,verbs=create;update,versions=v1alpha1,name=mcfshard.korifi.cloudfoundry.org,admissionReviewVersions={v1,v1beta1}
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// LabelWebhook sets the ShardLabelKey label on the sharded objects when they
+// are created or updated
+type LabelWebhook struct {
+	shard   Shard
+	decoder admission.Decoder
+}
+
+func NewLabelWebhook(shard Shard) *LabelWebhook {
+	return &LabelWebhook{shard: shard}
+}
+
+func (w *LabelWebhook) SetupWebhookWithManager(mgr ctrl.Manager) {
+	mgr.GetWebhookServer().Register("/mutate-korifi-cloudfoundry-org-v1alpha1-all-shard", &admission.Webhook{
+		Handler: w,
+	})
+	w.decoder = admission.NewDecoder(mgr.GetScheme())
+}
+
+func (w *LabelWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if !w.shard.Enabled() {
+		return admission.Allowed("sharding is disabled")
+	}
+
+	var obj metav1.PartialObjectMetadata
+	if err := w.decoder.Decode(req, &obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	shardLabel := w.shard.LabelFor(req.Namespace)
+	if obj.Labels[ShardLabelKey] == shardLabel {
+		return admission.Allowed("shard label already set")
+	}
+
+	origMarshalled, err := json.Marshal(obj)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	objLabels := obj.GetLabels()
+	if objLabels == nil {
+		objLabels = map[string]string{}
+	}
+	objLabels[ShardLabelKey] = shardLabel
+	obj.SetLabels(objLabels)
+
+	marshalled, err := json.Marshal(obj)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	return admission.PatchResponseFromRaw(origMarshalled, marshalled)
+}
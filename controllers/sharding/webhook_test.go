@@ -0,0 +1,95 @@
+package sharding_test
+
+import (
+	"context"
+	"encoding/json"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/sharding"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("LabelWebhook", func() {
+	var (
+		shard    sharding.Shard
+		cfApp    *korifiv1alpha1.CFApp
+		response admission.Response
+	)
+
+	BeforeEach(func() {
+		shard = sharding.NewShard(3, sharding.NoShard)
+		cfApp = &korifiv1alpha1.CFApp{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: korifiv1alpha1.GroupVersion.String(),
+				Kind:       "CFApp",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "cf-space",
+				Name:      "app-guid",
+				Labels:    map[string]string{"foo": "bar"},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(korifiv1alpha1.AddToScheme(scheme)).To(Succeed())
+		mgr, err := ctrl.NewManager(&rest.Config{}, ctrl.Options{Scheme: scheme, WebhookServer: webhook.NewServer(webhook.Options{})})
+		Expect(err).NotTo(HaveOccurred())
+
+		labelWebhook := sharding.NewLabelWebhook(shard)
+		labelWebhook.SetupWebhookWithManager(mgr)
+
+		rawApp, err := json.Marshal(cfApp)
+		Expect(err).NotTo(HaveOccurred())
+
+		response = labelWebhook.Handle(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Namespace: cfApp.Namespace,
+				Object:    runtime.RawExtension{Raw: rawApp},
+			},
+		})
+	})
+
+	It("labels the object with the shard of its namespace", func() {
+		Expect(response.Allowed).To(BeTrue())
+		Expect(response.Patches).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Operation": Equal("add"),
+			"Path":      Equal("/metadata/labels/korifi.cloudfoundry.org~1shard"),
+			"Value":     Equal(shard.LabelFor(cfApp.Namespace)),
+		})))
+	})
+
+	When("the object is labelled with its shard", func() {
+		BeforeEach(func() {
+			cfApp.Labels[sharding.ShardLabelKey] = shard.LabelFor(cfApp.Namespace)
+		})
+
+		It("does not patch it", func() {
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Patches).To(BeEmpty())
+		})
+	})
+
+	When("sharding is disabled", func() {
+		BeforeEach(func() {
+			shard = sharding.NewShard(1, sharding.NoShard)
+		})
+
+		It("does not patch the object", func() {
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Patches).To(BeEmpty())
+		})
+	})
+})
@@ -108,6 +108,7 @@ var _ = BeforeSuite(func() {
 		validation.NewDuplicateValidator(coordination.NewNameRegistry(uncachedClient, routes.RouteEntityType)),
 		rootNamespace,
 		uncachedClient,
+		nil,
 	).SetupWebhookWithManager(k8sManager)).To(Succeed())
 	Expect(packages.NewValidator().SetupWebhookWithManager(k8sManager)).To(Succeed())
 	Expect(instances.NewValidator(validation.NewDuplicateValidator(coordination.NewNameRegistry(uncachedClient, instances.ServiceInstanceEntityType))).SetupWebhookWithManager(k8sManager)).To(Succeed())
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"regexp"
+	"slices"
 	"strings"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
@@ -32,6 +34,8 @@ const (
 	RoutePathValidationErrorType           = "RoutePathValidationError"
 	RouteSubdomainValidationErrorType      = "RouteSubdomainValidationError"
 	RouteSubdomainValidationErrorMessage   = "Subdomains must each be at most 63 characters"
+	RouteTCPValidationErrorType            = "RouteTCPValidationError"
+	RouteReservedHostErrorType             = "RouteReservedHostError"
 
 	HostEmptyError  = "host cannot be empty"
 	HostLengthError = "host is too long (maximum is 63 characters)"
@@ -41,8 +45,13 @@ const (
 	PathIsSlashError         = "Path cannot be a single slash"
 	PathHasQuestionMarkError = "Path cannot contain a question mark"
 	PathLengthExceededError  = "Path cannot exceed 128 characters"
+	PathSyntaxError          = "Path must consist of non-empty segments of letters, digits, percent-encoded or \"-._~!$&'()*+,;=:@\" characters"
 )
 
+// pathRegexp matches paths made of non-empty RFC 3986 segments, without a
+// trailing slash
+var pathRegexp = regexp.MustCompile(`^(/([A-Za-z0-9\-._~!$&'()*+,;=:@]|%[0-9A-Fa-f]{2})+)+$`)
+
 var logger = logf.Log.WithName("route-validation")
 
 //+kubebuilder:webhook:path=/validate-korifi-cloudfoundry-org-v1alpha1-cfroute,mutating=false,failurePolicy=fail,sideEffects=NoneOnDryRun,groups=korifi.cloudfoundry.org,resources=cfroutes,verbs=create;update;delete,versions=v1alpha1,name=vcfroute.korifi.cloudfoundry.org,admissionReviewVersions={v1,v1beta1}
@@ -51,6 +60,7 @@ type Validator struct {
 	duplicateValidator webhooks.NameValidator
 	rootNamespace      string
 	client             client.Client
+	reservedHosts      []string
 }
 
 var _ webhook.CustomValidator = &Validator{}
@@ -59,11 +69,13 @@ func NewValidator(
 	nameValidator webhooks.NameValidator,
 	rootNamespace string,
 	client client.Client,
+	reservedHosts []string,
 ) *Validator {
 	return &Validator{
 		duplicateValidator: nameValidator,
 		rootNamespace:      rootNamespace,
 		client:             client,
+		reservedHosts:      reservedHosts,
 	}
 }
 
@@ -124,6 +136,11 @@ func (v *Validator) ValidateUpdate(ctx context.Context, oldObj, obj runtime.Obje
 		return nil, immutableError.ExportJSONError()
 	}
 
+	if route.Spec.Port != oldRoute.Spec.Port {
+		immutableError.Message = fmt.Sprintf(validationwebhook.ImmutableFieldErrorMessageTemplate, "CFRoute.Spec.Port")
+		return nil, immutableError.ExportJSONError()
+	}
+
 	if route.Spec.DomainRef.Name != oldRoute.Spec.DomainRef.Name {
 		immutableError.Message = fmt.Sprintf(validationwebhook.ImmutableFieldErrorMessageTemplate, "CFRoute.Spec.DomainRef.Name")
 		return nil, immutableError.ExportJSONError()
@@ -157,10 +174,18 @@ func (v *Validator) validateRoute(ctx context.Context, route *korifiv1alpha1.CFR
 		return domain, err
 	}
 
+	if route.Spec.Protocol == korifiv1alpha1.ProtocolTCP {
+		return domain, validateTCPRoute(route)
+	}
+
 	if err = validateFQDN(route.Spec.Host, domain.Spec.Name); err != nil {
 		return nil, err
 	}
 
+	if err = v.validateHostNotReserved(route.Spec.Host); err != nil {
+		return nil, err
+	}
+
 	if err = validatePath(route.Spec.Path); err != nil {
 		return nil, err
 	}
@@ -202,6 +227,33 @@ func (v *Validator) validateDestinations(ctx context.Context, route *korifiv1alp
 	return nil
 }
 
+// validateTCPRoute checks that tcp routes have a port. Their connections are
+// forwarded as they are, so they cannot have a host nor a path.
+func validateTCPRoute(route *korifiv1alpha1.CFRoute) error {
+	var errStrings []string
+
+	if route.Spec.Port < 1 || route.Spec.Port > 65535 {
+		errStrings = append(errStrings, "Port must be between 1 and 65535")
+	}
+
+	if route.Spec.Host != "" {
+		errStrings = append(errStrings, "Host must be empty for tcp routes")
+	}
+
+	if route.Spec.Path != "" {
+		errStrings = append(errStrings, "Path must be empty for tcp routes")
+	}
+
+	if len(errStrings) == 0 {
+		return nil
+	}
+
+	return validationwebhook.ValidationError{
+		Type:    RouteTCPValidationErrorType,
+		Message: strings.Join(errStrings, ", "),
+	}.ExportJSONError()
+}
+
 func validateFQDN(host, domain string) error {
 	// we only need to validate that "<host>.<domain>" is not too long and that
 	// <host> is either "*" or a valid dns label. The domain webhook already
@@ -225,13 +277,30 @@ func validateFQDN(host, domain string) error {
 	return nil
 }
 
+// validateHostNotReserved denies hosts on the configured deny list, e.g. the
+// hosts of platform services sharing the apps domain
+func (v *Validator) validateHostNotReserved(host string) error {
+	if !slices.ContainsFunc(v.reservedHosts, func(reservedHost string) bool {
+		return strings.EqualFold(reservedHost, host)
+	}) {
+		return nil
+	}
+
+	return validationwebhook.ValidationError{
+		Type:    RouteReservedHostErrorType,
+		Message: fmt.Sprintf("Host %q is reserved", strings.ToLower(host)),
+	}.ExportJSONError()
+}
+
+// validateHost checks that host is "*" or an RFC 1035 label, i.e. it starts
+// with a letter
 func validateHost(host string) error {
 	if host == "*" {
 		return nil
 	}
 
 	var multiErr *multierror.Error
-	for _, err := range validation.IsDNS1123Label(host) {
+	for _, err := range validation.IsDNS1035Label(host) {
 		multiErr = multierror.Append(multiErr, errors.New(err))
 	}
 
@@ -266,6 +335,10 @@ func validatePath(path string) error {
 		errStrings = append(errStrings, PathLengthExceededError)
 	}
 
+	if len(errStrings) == 0 && !pathRegexp.MatchString(path) {
+		errStrings = append(errStrings, PathSyntaxError)
+	}
+
 	if len(errStrings) == 0 {
 		return nil
 	}
@@ -102,7 +102,7 @@ var _ = Describe("CFRouteValidator", func() {
 			}
 		}
 
-		validatingWebhook = routes.NewValidator(duplicateValidator, rootNamespace, fakeClient)
+		validatingWebhook = routes.NewValidator(duplicateValidator, rootNamespace, fakeClient, []string{"api", "login"})
 	})
 
 	Describe("ValidateCreate", func() {
@@ -137,6 +137,32 @@ var _ = Describe("CFRouteValidator", func() {
 			})
 		})
 
+		When("the host does not start with a letter", func() {
+			BeforeEach(func() {
+				cfRoute.Spec.Host = "2048-game"
+			})
+
+			It("denies the request", func() {
+				Expect(retErr).To(matchers.BeValidationError(
+					routes.RouteHostNameValidationErrorType,
+					ContainSubstring("Host \"2048-game\" is not valid"),
+				))
+			})
+		})
+
+		When("the host is reserved", func() {
+			BeforeEach(func() {
+				cfRoute.Spec.Host = "API"
+			})
+
+			It("denies the request", func() {
+				Expect(retErr).To(matchers.BeValidationError(
+					routes.RouteReservedHostErrorType,
+					Equal("Host \"api\" is reserved"),
+				))
+			})
+		})
+
 		When("retrieving the domain record fails", func() {
 			BeforeEach(func() {
 				getDomainError = errors.New("nope")
@@ -202,6 +228,54 @@ var _ = Describe("CFRouteValidator", func() {
 			})
 		})
 
+		When("the route is a tcp route", func() {
+			BeforeEach(func() {
+				cfRoute.Spec.Protocol = korifiv1alpha1.ProtocolTCP
+				cfRoute.Spec.Port = 1024
+				cfRoute.Spec.Host = ""
+				cfRoute.Spec.Path = ""
+			})
+
+			It("allows the request", func() {
+				Expect(retErr).NotTo(HaveOccurred())
+			})
+
+			It("checks the port is unique across all domains", func() {
+				Expect(duplicateValidator.ValidateCreateCallCount()).To(Equal(1))
+				_, _, actualNamespace, actualResource := duplicateValidator.ValidateCreateArgsForCall(0)
+				Expect(actualNamespace).To(Equal(rootNamespace))
+				Expect(actualResource.UniqueName()).To(Equal("tcp::1024"))
+				Expect(actualResource.UniqueValidationErrorMessage()).To(Equal("Port '1024' is already used by another route."))
+			})
+
+			When("the port is not set", func() {
+				BeforeEach(func() {
+					cfRoute.Spec.Port = 0
+				})
+
+				It("denies the request", func() {
+					Expect(retErr).To(matchers.BeValidationError(
+						routes.RouteTCPValidationErrorType,
+						Equal("Port must be between 1 and 65535"),
+					))
+				})
+			})
+
+			When("the route has a host and a path", func() {
+				BeforeEach(func() {
+					cfRoute.Spec.Host = "my-host"
+					cfRoute.Spec.Path = "/my-path"
+				})
+
+				It("denies the request", func() {
+					Expect(retErr).To(matchers.BeValidationError(
+						routes.RouteTCPValidationErrorType,
+						Equal("Host must be empty for tcp routes, Path must be empty for tcp routes"),
+					))
+				})
+			})
+		})
+
 		When("the path is a single slash", func() {
 			BeforeEach(func() {
 				cfRoute.Spec.Path = "/"
@@ -241,6 +315,28 @@ var _ = Describe("CFRouteValidator", func() {
 			})
 		})
 
+		DescribeTable("path syntax",
+			func(path string, valid bool) {
+				cfRoute.Spec.Path = path
+				_, err := validatingWebhook.ValidateCreate(ctx, cfRoute)
+				if valid {
+					Expect(err).NotTo(HaveOccurred())
+					return
+				}
+				Expect(err).To(matchers.BeValidationError(
+					routes.RoutePathValidationErrorType,
+					Equal(routes.PathSyntaxError),
+				))
+			},
+			Entry("multiple segments", "/foo/bar", true),
+			Entry("sub-delimiters", "/foo;bar=1/a,b", true),
+			Entry("percent-encoded characters", "/foo%20bar", true),
+			Entry("empty segment", "/foo//bar", false),
+			Entry("trailing slash", "/foo/", false),
+			Entry("space", "/foo bar", false),
+			Entry("fragment", "/foo#bar", false),
+		)
+
 		When("the path is longer than 128 characters", func() {
 			BeforeEach(func() {
 				cfRoute.Spec.Path = fmt.Sprintf("/%s", strings.Repeat("a", 128))
@@ -391,6 +487,19 @@ var _ = Describe("CFRouteValidator", func() {
 			})
 		})
 
+		When("the port is updated", func() {
+			BeforeEach(func() {
+				updatedCFRoute.Spec.Port = 2048
+			})
+
+			It("denies the request", func() {
+				Expect(retErr).To(matchers.BeValidationError(
+					validationwebhook.ImmutableFieldErrorType,
+					Equal("'CFRoute.Spec.Port' field is immutable"),
+				))
+			})
+		})
+
 		When("the DomainRef is updated", func() {
 			BeforeEach(func() {
 				updatedCFRoute.Spec.DomainRef = v1.ObjectReference{Name: "newDomainRef"}
@@ -56,6 +56,13 @@ func (v DuplicateValidator) ValidateUpdate(ctx context.Context, logger logr.Logg
 
 		if k8serrors.IsNotFound(err) {
 			isOwned, ownershipErr := v.nameRegistry.CheckNameOwnership(ctx, namespace, obj.UniqueName(), obj.GetNamespace(), obj.GetName())
+			if k8serrors.IsNotFound(ownershipErr) {
+				// neither name is registered, e.g. because the object was
+				// created while uniqueness was relaxed
+				logger.Info("registering new name of an unregistered object")
+				return v.ValidateCreate(ctx, logger, namespace, obj)
+			}
+
 			if ownershipErr != nil {
 				logger.Error(ownershipErr, "failed to check ownership on new name")
 				return unknownError()
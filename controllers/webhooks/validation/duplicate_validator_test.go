@@ -190,6 +190,36 @@ var _ = Describe("DuplicateValidator", func() {
 					})
 				})
 
+				When("the new name is not registered either", func() {
+					BeforeEach(func() {
+						nameRegistry.CheckNameOwnershipReturns(false, k8serrors.NewNotFound(schema.GroupResource{}, "new-unique-name"))
+					})
+
+					It("registers the new name", func() {
+						Expect(validationErr).NotTo(HaveOccurred())
+
+						Expect(nameRegistry.RegisterNameCallCount()).To(Equal(1))
+						_, namespace, name, ownerNamespace, ownerName := nameRegistry.RegisterNameArgsForCall(0)
+						Expect(namespace).To(Equal("uniqueness-namespace"))
+						Expect(name).To(Equal("new-unique-name"))
+						Expect(ownerNamespace).To(Equal("test-resource-namespace"))
+						Expect(ownerName).To(Equal("test-resource-name"))
+					})
+
+					When("the new name is taken meanwhile", func() {
+						BeforeEach(func() {
+							nameRegistry.RegisterNameReturns(k8serrors.NewAlreadyExists(schema.GroupResource{}, "new-unique-name"))
+						})
+
+						It("fails", func() {
+							Expect(validationErr).To(matchers.BeValidationError(
+								validation.DuplicateNameErrorType,
+								Equal("new-uniqueness-error"),
+							))
+						})
+					})
+				})
+
 				When("checking name ownership fails", func() {
 					BeforeEach(func() {
 						nameRegistry.CheckNameOwnershipReturns(false, errors.New("foo"))
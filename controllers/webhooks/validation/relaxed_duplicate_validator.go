@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/korifi/controllers/webhooks"
+	"github.com/go-logr/logr"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// RelaxedDuplicateValidator allows duplicate names. It does not register
+// names, but releases the names objects registered while uniqueness was
+// enforced when they are renamed or deleted, so that enforcing uniqueness
+// again does not reject those names.
+type RelaxedDuplicateValidator struct {
+	nameRegistry webhooks.NameRegistry
+}
+
+func NewRelaxedDuplicateValidator(nameRegistry webhooks.NameRegistry) *RelaxedDuplicateValidator {
+	return &RelaxedDuplicateValidator{
+		nameRegistry: nameRegistry,
+	}
+}
+
+func (v RelaxedDuplicateValidator) ValidateCreate(ctx context.Context, logger logr.Logger, namespace string, obj webhooks.UniqueClientObject) error {
+	return nil
+}
+
+func (v RelaxedDuplicateValidator) ValidateUpdate(ctx context.Context, logger logr.Logger, namespace string, oldObj, obj webhooks.UniqueClientObject) error {
+	if oldObj.UniqueName() == obj.UniqueName() {
+		return nil
+	}
+
+	return v.releaseName(ctx, logger.WithName("relaxedDuplicateValidator.ValidateUpdate"), namespace, oldObj)
+}
+
+func (v RelaxedDuplicateValidator) ValidateDelete(ctx context.Context, logger logr.Logger, namespace string, obj webhooks.UniqueClientObject) error {
+	return v.releaseName(ctx, logger.WithName("relaxedDuplicateValidator.ValidateDelete"), namespace, obj)
+}
+
+// releaseName deregisters the name of obj, unless it is not registered or
+// registered to a duplicate of obj
+func (v RelaxedDuplicateValidator) releaseName(ctx context.Context, logger logr.Logger, namespace string, obj webhooks.UniqueClientObject) error {
+	logger = logger.WithValues("namespace", namespace, "name", obj.UniqueName())
+
+	isOwned, err := v.nameRegistry.CheckNameOwnership(ctx, namespace, obj.UniqueName(), obj.GetNamespace(), obj.GetName())
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+
+		logger.Info("failed to check name ownership", "reason", err)
+		return unknownError()
+	}
+
+	if !isOwned {
+		return nil
+	}
+
+	if err = v.nameRegistry.DeregisterName(ctx, namespace, obj.UniqueName()); err != nil {
+		logger.Info("failed to deregister name", "reason", err)
+		return unknownError()
+	}
+
+	return nil
+}
+
+// check interface is implemented correctly
+var _ webhooks.NameValidator = RelaxedDuplicateValidator{}
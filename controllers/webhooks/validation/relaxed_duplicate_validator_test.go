@@ -0,0 +1,157 @@
+package validation_test
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/korifi/controllers/webhooks/fake"
+	"code.cloudfoundry.org/korifi/controllers/webhooks/validation"
+	"code.cloudfoundry.org/korifi/tests/matchers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("RelaxedDuplicateValidator", func() {
+	var (
+		ctx             context.Context
+		nameRegistry    *fake.NameRegistry
+		validator       *validation.RelaxedDuplicateValidator
+		uniqueClientObj *fake.UniqueClientObject
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		nameRegistry = new(fake.NameRegistry)
+		nameRegistry.CheckNameOwnershipReturns(true, nil)
+		validator = validation.NewRelaxedDuplicateValidator(nameRegistry)
+
+		uniqueClientObj = new(fake.UniqueClientObject)
+		uniqueClientObj.GetNameReturns("test-resource-name")
+		uniqueClientObj.GetNamespaceReturns("test-resource-namespace")
+		uniqueClientObj.UniqueNameReturns("unique-name")
+	})
+
+	Describe("ValidateCreate", func() {
+		It("allows the object without registering its name", func() {
+			Expect(validator.ValidateCreate(ctx, logf.Log, "uniqueness-namespace", uniqueClientObj)).To(Succeed())
+			Expect(nameRegistry.RegisterNameCallCount()).To(BeZero())
+		})
+	})
+
+	Describe("ValidateUpdate", func() {
+		var (
+			updatedObj    *fake.UniqueClientObject
+			validationErr error
+		)
+
+		BeforeEach(func() {
+			updatedObj = new(fake.UniqueClientObject)
+			updatedObj.GetNameReturns("test-resource-name")
+			updatedObj.GetNamespaceReturns("test-resource-namespace")
+			updatedObj.UniqueNameReturns("new-unique-name")
+		})
+
+		JustBeforeEach(func() {
+			validationErr = validator.ValidateUpdate(ctx, logf.Log, "uniqueness-namespace", uniqueClientObj, updatedObj)
+		})
+
+		It("releases the old name owned by the object", func() {
+			Expect(validationErr).NotTo(HaveOccurred())
+
+			Expect(nameRegistry.CheckNameOwnershipCallCount()).To(Equal(1))
+			_, namespace, name, ownerNamespace, ownerName := nameRegistry.CheckNameOwnershipArgsForCall(0)
+			Expect(namespace).To(Equal("uniqueness-namespace"))
+			Expect(name).To(Equal("unique-name"))
+			Expect(ownerNamespace).To(Equal("test-resource-namespace"))
+			Expect(ownerName).To(Equal("test-resource-name"))
+
+			Expect(nameRegistry.DeregisterNameCallCount()).To(Equal(1))
+			_, namespace, name = nameRegistry.DeregisterNameArgsForCall(0)
+			Expect(namespace).To(Equal("uniqueness-namespace"))
+			Expect(name).To(Equal("unique-name"))
+
+			Expect(nameRegistry.RegisterNameCallCount()).To(BeZero())
+		})
+
+		When("the name isn't changed", func() {
+			BeforeEach(func() {
+				updatedObj.UniqueNameReturns("unique-name")
+			})
+
+			It("is allowed without using the name registry", func() {
+				Expect(validationErr).NotTo(HaveOccurred())
+				Expect(nameRegistry.CheckNameOwnershipCallCount()).To(Equal(0))
+				Expect(nameRegistry.DeregisterNameCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("ValidateDelete", func() {
+		var validationErr error
+
+		JustBeforeEach(func() {
+			validationErr = validator.ValidateDelete(ctx, logf.Log, "uniqueness-namespace", uniqueClientObj)
+		})
+
+		It("releases the name owned by the object", func() {
+			Expect(validationErr).NotTo(HaveOccurred())
+
+			Expect(nameRegistry.DeregisterNameCallCount()).To(Equal(1))
+			_, namespace, name := nameRegistry.DeregisterNameArgsForCall(0)
+			Expect(namespace).To(Equal("uniqueness-namespace"))
+			Expect(name).To(Equal("unique-name"))
+		})
+
+		When("the name is not registered", func() {
+			BeforeEach(func() {
+				nameRegistry.CheckNameOwnershipReturns(false, k8serrors.NewNotFound(schema.GroupResource{}, "unique-name"))
+			})
+
+			It("succeeds without deregistering it", func() {
+				Expect(validationErr).NotTo(HaveOccurred())
+				Expect(nameRegistry.DeregisterNameCallCount()).To(BeZero())
+			})
+		})
+
+		When("the name is registered to a duplicate of the object", func() {
+			BeforeEach(func() {
+				nameRegistry.CheckNameOwnershipReturns(false, nil)
+			})
+
+			It("succeeds without deregistering it", func() {
+				Expect(validationErr).NotTo(HaveOccurred())
+				Expect(nameRegistry.DeregisterNameCallCount()).To(BeZero())
+			})
+		})
+
+		When("checking the name ownership fails", func() {
+			BeforeEach(func() {
+				nameRegistry.CheckNameOwnershipReturns(false, errors.New("boom"))
+			})
+
+			It("fails", func() {
+				Expect(validationErr).To(matchers.BeValidationError(
+					validation.UnknownErrorType,
+					Equal(validation.UnknownErrorMessage),
+				))
+			})
+		})
+
+		When("deregistering the name fails", func() {
+			BeforeEach(func() {
+				nameRegistry.DeregisterNameReturns(errors.New("boom"))
+			})
+
+			It("fails", func() {
+				Expect(validationErr).To(matchers.BeValidationError(
+					validation.UnknownErrorType,
+					Equal(validation.UnknownErrorMessage),
+				))
+			})
+		})
+	})
+})
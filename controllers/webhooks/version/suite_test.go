@@ -104,9 +104,9 @@ var _ = BeforeSuite(func() {
 
 	Expect((&korifiv1alpha1.CFPackage{}).SetupWebhookWithManager(k8sManager)).To(Succeed())
 
-	Expect(tasks.NewValidator().SetupWebhookWithManager(k8sManager)).To(Succeed())
+	Expect(tasks.NewValidator(k8sManager.GetAPIReader(), 0).SetupWebhookWithManager(k8sManager)).To(Succeed())
 
-	Expect(korifiv1alpha1.NewCFProcessDefaulter(128, 256, 60).
+	Expect(korifiv1alpha1.NewCFProcessDefaulter(k8sManager.GetAPIReader(), rootNamespace, 128, 256, 1, 60, 10).
 		SetupWebhookWithManager(k8sManager)).To(Succeed())
 	Expect((&korifiv1alpha1.CFBuild{}).SetupWebhookWithManager(k8sManager)).To(Succeed())
 	Expect((&korifiv1alpha1.CFRoute{}).SetupWebhookWithManager(k8sManager)).To(Succeed())
@@ -114,6 +114,7 @@ var _ = BeforeSuite(func() {
 		validation.NewDuplicateValidator(coordination.NewNameRegistry(uncachedClient, routes.RouteEntityType)),
 		rootNamespace,
 		uncachedClient,
+		nil,
 	).SetupWebhookWithManager(k8sManager)).To(Succeed())
 	Expect(bindings.NewCFServiceBindingValidator(
 		validation.NewDuplicateValidator(coordination.NewNameRegistry(uncachedClient, bindings.ServiceBindingEntityType)),
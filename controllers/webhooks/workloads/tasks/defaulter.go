@@ -35,12 +35,12 @@ import (
 var cfTaskLog = logf.Log.WithName("cftask-resource")
 
 type Defaulter struct {
-	cfProcessDefaults config.CFProcessDefaults
+	cfTaskDefaults config.CFTaskDefaults
 }
 
-func NewDefaulter(cfProcessDefaults config.CFProcessDefaults) *Defaulter {
+func NewDefaulter(cfTaskDefaults config.CFTaskDefaults) *Defaulter {
 	return &Defaulter{
-		cfProcessDefaults: cfProcessDefaults,
+		cfTaskDefaults: cfTaskDefaults,
 	}
 }
 
@@ -70,8 +70,15 @@ func (d *Defaulter) Default(ctx context.Context, obj runtime.Object) error {
 
 	cfTask.Status.SequenceID = seqId
 
-	cfTask.Status.MemoryMB = d.cfProcessDefaults.MemoryMB
-	cfTask.Status.DiskQuotaMB = d.cfProcessDefaults.DiskQuotaMB
+	cfTask.Status.MemoryMB = d.cfTaskDefaults.MemoryMB
+	if cfTask.Spec.MemoryMB > 0 {
+		cfTask.Status.MemoryMB = cfTask.Spec.MemoryMB
+	}
+
+	cfTask.Status.DiskQuotaMB = d.cfTaskDefaults.DiskQuotaMB
+	if cfTask.Spec.DiskQuotaMB > 0 {
+		cfTask.Status.DiskQuotaMB = cfTask.Spec.DiskQuotaMB
+	}
 
 	return nil
 }
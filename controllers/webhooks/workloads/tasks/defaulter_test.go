@@ -56,6 +56,18 @@ var _ = Describe("CFTaskMutatingWebhook", func() {
 		Expect(cfTask.Status.DiskQuotaMB).To(BeNumerically("==", 512))
 	})
 
+	When("the task spec sets the memory and disk", func() {
+		BeforeEach(func() {
+			cfTask.Spec.MemoryMB = 1024
+			cfTask.Spec.DiskQuotaMB = 2048
+		})
+
+		It("uses them instead of the defaults", func() {
+			Expect(cfTask.Status.MemoryMB).To(BeNumerically("==", 1024))
+			Expect(cfTask.Status.DiskQuotaMB).To(BeNumerically("==", 2048))
+		})
+	})
+
 	Describe("subsequent updates", func() {
 		var (
 			updateTaskFunc func()
@@ -79,11 +79,11 @@ var _ = BeforeSuite(func() {
 	version.NewVersionWebhook("some-version").SetupWebhookWithManager(k8sManager)
 	finalizer.NewControllersFinalizerWebhook().SetupWebhookWithManager(k8sManager)
 
-	Expect(tasks.NewDefaulter(config.CFProcessDefaults{
+	Expect(tasks.NewDefaulter(config.CFTaskDefaults{
 		MemoryMB:    500,
 		DiskQuotaMB: 512,
 	}).SetupWebhookWithManager(k8sManager)).To(Succeed())
-	Expect(tasks.NewValidator().SetupWebhookWithManager(k8sManager)).To(Succeed())
+	Expect(tasks.NewValidator(k8sManager.GetAPIReader(), 2).SetupWebhookWithManager(k8sManager)).To(Succeed())
 
 	stopManager = helpers.StartK8sManager(k8sManager)
 })
@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -34,6 +35,7 @@ import (
 
 const (
 	CancelationNotPossibleErrorType = "CancelationNotPossibleError"
+	TaskLimitExceededErrorType      = "TaskLimitExceededError"
 )
 
 // log is for logging in this package.
@@ -41,12 +43,18 @@ var cftasklog = logf.Log.WithName("cftask-resource")
 
 //+kubebuilder:webhook:path=/validate-korifi-cloudfoundry-org-v1alpha1-cftask,mutating=false,failurePolicy=fail,sideEffects=None,groups=korifi.cloudfoundry.org,resources=cftasks;cftasks/status,verbs=create;update,versions=v1alpha1,name=vcftask.korifi.cloudfoundry.org,admissionReviewVersions={v1,v1beta1}
 
-type Validator struct{}
+type Validator struct {
+	k8sReader                  client.Reader
+	maxConcurrentTasksPerSpace int
+}
 
 var _ webhook.CustomValidator = &Validator{}
 
-func NewValidator() *Validator {
-	return &Validator{}
+func NewValidator(k8sReader client.Reader, maxConcurrentTasksPerSpace int) *Validator {
+	return &Validator{
+		k8sReader:                  k8sReader,
+		maxConcurrentTasksPerSpace: maxConcurrentTasksPerSpace,
+	}
 }
 
 func (v *Validator) SetupWebhookWithManager(mgr ctrl.Manager) error {
@@ -80,7 +88,38 @@ func (v *Validator) ValidateCreate(ctx context.Context, obj runtime.Object) (adm
 		}.ExportJSONError()
 	}
 
-	return nil, nil
+	return nil, v.checkConcurrentTasksLimit(ctx, task.Namespace)
+}
+
+// checkConcurrentTasksLimit rejects new tasks while the space already has the
+// configured number of tasks that have neither succeeded nor failed
+func (v *Validator) checkConcurrentTasksLimit(ctx context.Context, namespace string) error {
+	if v.maxConcurrentTasksPerSpace <= 0 {
+		return nil
+	}
+
+	tasks := &v1alpha1.CFTaskList{}
+	if err := v.k8sReader.List(ctx, tasks, client.InNamespace(namespace)); err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("failed to list tasks in namespace %s: %w", namespace, err))
+	}
+
+	runningTasks := 0
+	for _, task := range tasks.Items {
+		if meta.IsStatusConditionTrue(task.Status.Conditions, v1alpha1.TaskSucceededConditionType) ||
+			meta.IsStatusConditionTrue(task.Status.Conditions, v1alpha1.TaskFailedConditionType) {
+			continue
+		}
+		runningTasks++
+	}
+
+	if runningTasks >= v.maxConcurrentTasksPerSpace {
+		return validation.ValidationError{
+			Type:    TaskLimitExceededErrorType,
+			Message: fmt.Sprintf("You have exceeded the limit of %d concurrently running tasks for your space.", v.maxConcurrentTasksPerSpace),
+		}.ExportJSONError()
+	}
+
+	return nil
 }
 
 func (v *Validator) ValidateUpdate(ctx context.Context, oldObj runtime.Object, obj runtime.Object) (admission.Warnings, error) {
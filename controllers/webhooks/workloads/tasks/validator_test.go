@@ -107,6 +107,49 @@ var _ = Describe("CFTask Validator", func() {
 				})
 			})
 		})
+
+		When("the space already runs the maximum number of tasks", func() {
+			var runningTasks []*korifiv1alpha1.CFTask
+
+			BeforeEach(func() {
+				runningTasks = nil
+				for range 2 {
+					runningTask := &korifiv1alpha1.CFTask{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      uuid.NewString(),
+							Namespace: testNamespace,
+						},
+						Spec: cfTask.Spec,
+					}
+					Expect(adminClient.Create(ctx, runningTask)).To(Succeed())
+					runningTasks = append(runningTasks, runningTask)
+				}
+			})
+
+			It("returns a validation error", func() {
+				validationErr, ok := validation.WebhookErrorToValidationError(creationErr)
+				Expect(ok).To(BeTrue())
+
+				Expect(validationErr.Type).To(Equal(tasks.TaskLimitExceededErrorType))
+				Expect(validationErr.Message).To(Equal("You have exceeded the limit of 2 concurrently running tasks for your space."))
+			})
+
+			When("one of the tasks has completed", func() {
+				BeforeEach(func() {
+					Expect(k8s.Patch(ctx, adminClient, runningTasks[0], func() {
+						meta.SetStatusCondition(&runningTasks[0].Status.Conditions, metav1.Condition{
+							Type:   korifiv1alpha1.TaskSucceededConditionType,
+							Status: metav1.ConditionTrue,
+							Reason: "succeeded",
+						})
+					})).To(Succeed())
+				})
+
+				It("suceeds", func() {
+					Expect(creationErr).NotTo(HaveOccurred())
+				})
+			})
+		})
 	})
 
 	Describe("update", func() {
@@ -0,0 +1,179 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	statefulsetcontrollers "code.cloudfoundry.org/korifi/statefulset-runner/controllers"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const AppWorkloadReconcilerName = "deployment-runner"
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+//counterfeiter:generate -o ../fake -fake-name WorkloadToDeploymentConverter . WorkloadToDeploymentConverter
+type WorkloadToDeploymentConverter interface {
+	Convert(appWorkload *korifiv1alpha1.AppWorkload) (*appsv1.Deployment, error)
+}
+
+// AppWorkloadReconciler reconciles a AppWorkload object
+type AppWorkloadReconciler struct {
+	k8sClient              client.Client
+	scheme                 *runtime.Scheme
+	workloadsToDeployments WorkloadToDeploymentConverter
+	pdb                    statefulsetcontrollers.PDB
+	log                    logr.Logger
+}
+
+func NewAppWorkloadReconciler(
+	c client.Client,
+	scheme *runtime.Scheme,
+	workloadsToDeployments WorkloadToDeploymentConverter,
+	pdb statefulsetcontrollers.PDB,
+	log logr.Logger,
+) *k8s.PatchingReconciler[korifiv1alpha1.AppWorkload, *korifiv1alpha1.AppWorkload] {
+	appWorkloadReconciler := AppWorkloadReconciler{
+		k8sClient:              c,
+		scheme:                 scheme,
+		workloadsToDeployments: workloadsToDeployments,
+		pdb:                    pdb,
+		log:                    log,
+	}
+	return k8s.NewPatchingReconciler[korifiv1alpha1.AppWorkload, *korifiv1alpha1.AppWorkload](log, c, &appWorkloadReconciler)
+}
+
+func (r *AppWorkloadReconciler) SetupWithManager(mgr ctrl.Manager) *builder.Builder {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("deployment-runner-appworkload").
+		For(&korifiv1alpha1.AppWorkload{}).
+		Owns(&appsv1.Deployment{}).
+		Watches(
+			new(appsv1.Deployment),
+			handler.EnqueueRequestsFromMapFunc(r.enqueueAppWorkloadRequests),
+		).
+		Watches(
+			new(corev1.Pod),
+			handler.EnqueueRequestsFromMapFunc(r.enqueueAppWorkloadRequests),
+		).
+		WithEventFilter(predicate.NewPredicateFuncs(filterAppWorkloads))
+}
+
+func (r *AppWorkloadReconciler) enqueueAppWorkloadRequests(ctx context.Context, o client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+
+	if appWorkloadName, ok := o.GetLabels()[statefulsetcontrollers.LabelAppWorkloadGUID]; ok {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      appWorkloadName,
+				Namespace: o.GetNamespace(),
+			},
+		})
+	}
+
+	return requests
+}
+
+func filterAppWorkloads(object client.Object) bool {
+	appWorkload, ok := object.(*korifiv1alpha1.AppWorkload)
+	if !ok {
+		return true
+	}
+
+	return appWorkload.Spec.RunnerName == AppWorkloadReconcilerName
+}
+
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=appworkloads,verbs=get;list;watch;create;patch;delete
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=appworkloads/status,verbs=get;patch
+
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=create;patch;get;list;watch
+//+kubebuilder:rbac:groups=apps,resources=deployments/finalizers,verbs=update
+
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;patch;deletecollection
+
+//+kubebuilder:rbac:groups="",resources=pods,verbs=list;watch;patch
+
+func (r *AppWorkloadReconciler) ReconcileResource(ctx context.Context, appWorkload *korifiv1alpha1.AppWorkload) (ctrl.Result, error) {
+	log := logr.FromContextOrDiscard(ctx)
+
+	appWorkload.Status.ObservedGeneration = appWorkload.Generation
+	log.V(1).Info("set observed generation", "generation", appWorkload.Status.ObservedGeneration)
+
+	deployment, err := r.workloadsToDeployments.Convert(appWorkload)
+	if err != nil {
+		log.Info("error when converting AppWorkload", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	actualDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployment.Name,
+			Namespace: deployment.Namespace,
+		},
+	}
+	_, err = controllerutil.CreateOrPatch(ctx, r.k8sClient, actualDeployment, func() error {
+		actualDeployment.Labels = deployment.Labels
+		actualDeployment.Annotations = deployment.Annotations
+		actualDeployment.OwnerReferences = deployment.OwnerReferences
+		actualDeployment.Spec = deployment.Spec
+
+		return nil
+	})
+	if err != nil {
+		log.Info("error when creating or updating Deployment", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	err = r.pdb.Update(ctx, actualDeployment, *actualDeployment.Spec.Replicas, actualDeployment.Spec.Selector)
+	if err != nil {
+		log.Info("error when creating or patching pod disruption budget", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	appWorkload.Status.ActualInstances = actualDeployment.Status.Replicas
+	appWorkload.Status.Selector = metav1.FormatLabelSelector(actualDeployment.Spec.Selector)
+
+	pods := &corev1.PodList{}
+	err = r.k8sClient.List(ctx, pods, client.InNamespace(appWorkload.Namespace), client.MatchingLabels{statefulsetcontrollers.LabelAppWorkloadGUID: appWorkload.Name})
+	if err != nil {
+		log.Info("error when listing the instances", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	err = statefulsetcontrollers.PatchPodsCFMetadata(ctx, r.k8sClient, appWorkload, pods.Items)
+	if err != nil {
+		log.Info("error when patching the metadata of the instances", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,270 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/deployment-runner/controllers"
+	"code.cloudfoundry.org/korifi/deployment-runner/fake"
+	statefulsetfake "code.cloudfoundry.org/korifi/statefulset-runner/fake"
+	"code.cloudfoundry.org/korifi/tools"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("AppWorkload Reconcile", func() {
+	var (
+		reconciler               *k8s.PatchingReconciler[korifiv1alpha1.AppWorkload, *korifiv1alpha1.AppWorkload]
+		reconcileResult          ctrl.Result
+		reconcileErr             error
+		ctx                      context.Context
+		req                      ctrl.Request
+		appWorkload              *korifiv1alpha1.AppWorkload
+		deployment               *appsv1.Deployment
+		fakeWorkloadToDeployment *fake.WorkloadToDeploymentConverter
+		fakePDB                  *statefulsetfake.PDB
+		getAppWorkloadError      error
+		getDeploymentError       error
+		createDeploymentError    error
+	)
+
+	BeforeEach(func() {
+		appWorkload = &korifiv1alpha1.AppWorkload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      uuid.NewString(),
+				Namespace: uuid.NewString(),
+			},
+		}
+
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      uuid.NewString(),
+				Namespace: appWorkload.Namespace,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: tools.PtrTo(int32(1)),
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"label": "value"},
+				},
+			},
+			Status: appsv1.DeploymentStatus{
+				Replicas: 3,
+			},
+		}
+
+		fakeWorkloadToDeployment = new(fake.WorkloadToDeploymentConverter)
+		fakeWorkloadToDeployment.ConvertReturns(deployment.DeepCopy(), nil)
+
+		fakePDB = new(statefulsetfake.PDB)
+
+		ctx = context.Background()
+		req = ctrl.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      uuid.NewString(),
+				Namespace: appWorkload.Namespace,
+			},
+		}
+
+		getAppWorkloadError = nil
+		getDeploymentError = apierrors.NewNotFound(schema.GroupResource{
+			Group:    "apps",
+			Resource: "Deployment",
+		}, "some-resource")
+		createDeploymentError = nil
+
+		fakeClient.GetStub = func(_ context.Context, _ types.NamespacedName, obj client.Object, _ ...client.GetOption) error {
+			switch obj := obj.(type) {
+			case *korifiv1alpha1.AppWorkload:
+				appWorkload.DeepCopyInto(obj)
+				return getAppWorkloadError
+			case *appsv1.Deployment:
+				if getDeploymentError == nil {
+					deployment.DeepCopyInto(obj)
+				}
+				return getDeploymentError
+			default:
+				panic("TestClient Get provided an unexpected object type")
+			}
+		}
+
+		fakeClient.CreateStub = func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+			switch obj.(type) {
+			case *appsv1.Deployment:
+				return createDeploymentError
+			default:
+				panic("TestClient Create provided an unexpected object type")
+			}
+		}
+
+		reconciler = controllers.NewAppWorkloadReconciler(
+			fakeClient,
+			scheme.Scheme,
+			fakeWorkloadToDeployment,
+			fakePDB,
+			ctrl.Log.WithName("controllers").WithName("TestAppWorkload"),
+		)
+	})
+
+	JustBeforeEach(func() {
+		reconcileResult, reconcileErr = reconciler.Reconcile(ctx, req)
+	})
+
+	When("the appworkload is being created", func() {
+		It("returns an empty result and does not return error", func() {
+			Expect(reconcileResult).To(Equal(ctrl.Result{}))
+			Expect(reconcileErr).NotTo(HaveOccurred())
+		})
+
+		It("converts the app workload to a deployment", func() {
+			Expect(fakeWorkloadToDeployment.ConvertCallCount()).To(Equal(1))
+			actualWorkload := fakeWorkloadToDeployment.ConvertArgsForCall(0)
+			Expect(actualWorkload.Name).To(Equal(appWorkload.Name))
+		})
+
+		It("creates a Deployment", func() {
+			Expect(fakeClient.CreateCallCount()).To(Equal(1), "Client.Create call count mismatch")
+			_, obj, _ := fakeClient.CreateArgsForCall(0)
+			Expect(obj).To(BeAssignableToTypeOf(new(appsv1.Deployment)))
+			Expect(obj.GetName()).To(Equal(deployment.Name))
+		})
+
+		It("sets the appworkload status", func() {
+			Expect(fakeStatusWriter.PatchCallCount()).To(Equal(1))
+			_, object, _, _ := fakeStatusWriter.PatchArgsForCall(0)
+			patchedAppWorkload, ok := object.(*korifiv1alpha1.AppWorkload)
+			Expect(ok).To(BeTrue())
+			Expect(patchedAppWorkload.Status.ObservedGeneration).To(Equal(patchedAppWorkload.Generation))
+		})
+
+		When("the app workload has CF metadata", func() {
+			BeforeEach(func() {
+				appWorkload.Labels = map[string]string{
+					"metadata.korifi.cloudfoundry.org/team": "my-team",
+				}
+				fakeClient.ListStub = func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+					podList, ok := list.(*corev1.PodList)
+					Expect(ok).To(BeTrue())
+					podList.Items = []corev1.Pod{{
+						ObjectMeta: metav1.ObjectMeta{Name: "my-app-web-abc12"},
+					}}
+					return nil
+				}
+			})
+
+			It("sets it on the instances", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+
+				var patchedPods []*corev1.Pod
+				for i := range fakeClient.PatchCallCount() {
+					_, obj, _, _ := fakeClient.PatchArgsForCall(i)
+					if pod, ok := obj.(*corev1.Pod); ok {
+						patchedPods = append(patchedPods, pod)
+					}
+				}
+
+				Expect(patchedPods).To(ConsistOf(SatisfyAll(
+					HaveField("Name", "my-app-web-abc12"),
+					HaveField("Labels", HaveKeyWithValue("metadata.korifi.cloudfoundry.org/team", "my-team")),
+				)))
+			})
+		})
+
+		When("converting the app workload to a deployment fails", func() {
+			BeforeEach(func() {
+				fakeWorkloadToDeployment.ConvertReturns(nil, errors.New("convert-error"))
+			})
+
+			It("returns the error", func() {
+				Expect(reconcileErr).To(MatchError("convert-error"))
+			})
+		})
+
+		When("creating the Deployment fails", func() {
+			BeforeEach(func() {
+				createDeploymentError = errors.New("big sad")
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError("big sad"))
+			})
+		})
+	})
+
+	When("the appworkload is being deleted", func() {
+		BeforeEach(func() {
+			getAppWorkloadError = apierrors.NewNotFound(schema.GroupResource{
+				Group:    "v1alpha1",
+				Resource: "AppWorkload",
+			}, "some-resource")
+		})
+
+		It("returns an empty result and does not return error", func() {
+			Expect(reconcileResult).To(Equal(ctrl.Result{}))
+			Expect(reconcileErr).NotTo(HaveOccurred())
+		})
+	})
+
+	When("the appworkload is being updated", func() {
+		BeforeEach(func() {
+			getDeploymentError = nil
+
+			desiredDeployment := deployment.DeepCopy()
+			desiredDeployment.Spec.Replicas = tools.PtrTo(int32(2))
+			fakeWorkloadToDeployment.ConvertReturns(desiredDeployment, nil)
+		})
+
+		It("scales instances", func() {
+			Expect(fakeClient.PatchCallCount()).To(BeNumerically(">", 1))
+			_, updatedObject, _, _ := fakeClient.PatchArgsForCall(0)
+			updatedDeployment, ok := updatedObject.(*appsv1.Deployment)
+			Expect(ok).To(BeTrue())
+			Expect(updatedDeployment.Spec.Replicas).To(Equal(tools.PtrTo(int32(2))))
+		})
+
+		It("updates the pod disruption budget of the deployment", func() {
+			Expect(fakePDB.UpdateCallCount()).To(Equal(1))
+			_, workload, replicas, selector := fakePDB.UpdateArgsForCall(0)
+			Expect(workload).To(BeAssignableToTypeOf(new(appsv1.Deployment)))
+			Expect(workload.GetName()).To(Equal(deployment.Name))
+			Expect(replicas).To(BeEquivalentTo(2))
+			Expect(selector).To(Equal(deployment.Spec.Selector))
+		})
+
+		When("updating the pod disruption budget fails", func() {
+			BeforeEach(func() {
+				fakePDB.UpdateReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError("boom"))
+			})
+		})
+
+		It("sets the actual instances from the deployment status", func() {
+			_, object, _, _ := fakeStatusWriter.PatchArgsForCall(0)
+			patchedAppWorkload, ok := object.(*korifiv1alpha1.AppWorkload)
+			Expect(ok).To(BeTrue())
+			Expect(patchedAppWorkload.Status.ActualInstances).To(BeEquivalentTo(3))
+		})
+
+		It("sets the instance selector from the deployment selector", func() {
+			_, object, _, _ := fakeStatusWriter.PatchArgsForCall(0)
+			patchedAppWorkload, ok := object.(*korifiv1alpha1.AppWorkload)
+			Expect(ok).To(BeTrue())
+			Expect(patchedAppWorkload.Status.Selector).To(Equal(metav1.FormatLabelSelector(deployment.Spec.Selector)))
+		})
+	})
+})
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"slices"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	statefulsetcontrollers "code.cloudfoundry.org/korifi/statefulset-runner/controllers"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// AppWorkloadToDeploymentConverter builds the pods of the app the same way the
+// statefulset-runner does and runs them via a Deployment, which rolls out and
+// scales all instances in parallel
+type AppWorkloadToDeploymentConverter struct {
+	workloadToStSet statefulsetcontrollers.WorkloadToStatefulsetConverter
+}
+
+func NewAppWorkloadToDeploymentConverter(workloadToStSet statefulsetcontrollers.WorkloadToStatefulsetConverter) *AppWorkloadToDeploymentConverter {
+	return &AppWorkloadToDeploymentConverter{
+		workloadToStSet: workloadToStSet,
+	}
+}
+
+func (c *AppWorkloadToDeploymentConverter) Convert(appWorkload *korifiv1alpha1.AppWorkload) (*appsv1.Deployment, error) {
+	statefulSet, err := c.workloadToStSet.Convert(appWorkload)
+	if err != nil {
+		return nil, err
+	}
+
+	template := statefulSet.Spec.Template.DeepCopy()
+	for i := range template.Spec.Containers {
+		template.Spec.Containers[i].Env = withoutInstanceIndex(template.Spec.Containers[i].Env)
+	}
+
+	// No instance is stopped before its replacement is ready
+	maxSurge := intstr.FromString("25%")
+	maxUnavailable := intstr.FromInt32(0)
+
+	return &appsv1.Deployment{
+		ObjectMeta: *statefulSet.ObjectMeta.DeepCopy(),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: statefulSet.Spec.Replicas,
+			Selector: statefulSet.Spec.Selector,
+			Template: *template,
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{
+					MaxSurge:       &maxSurge,
+					MaxUnavailable: &maxUnavailable,
+				},
+			},
+		},
+	}, nil
+}
+
+// withoutInstanceIndex drops CF_INSTANCE_INDEX and INSTANCE_INDEX as deployment pods have no
+// stable index to expose. Apps that need one should use the statefulset-runner.
+func withoutInstanceIndex(envs []corev1.EnvVar) []corev1.EnvVar {
+	return slices.DeleteFunc(slices.Clone(envs), func(env corev1.EnvVar) bool {
+		return env.Name == statefulsetcontrollers.EnvCFInstanceIndex || env.Name == statefulsetcontrollers.EnvInstanceIndex
+	})
+}
@@ -0,0 +1,141 @@
+package controllers_test
+
+import (
+	"errors"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/deployment-runner/controllers"
+	statefulsetcontrollers "code.cloudfoundry.org/korifi/statefulset-runner/controllers"
+	statefulsetfake "code.cloudfoundry.org/korifi/statefulset-runner/fake"
+	"code.cloudfoundry.org/korifi/tools"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("AppWorkloadToDeploymentConverter", func() {
+	var (
+		fakeWorkloadToStSet *statefulsetfake.WorkloadToStatefulsetConverter
+		converter           *controllers.AppWorkloadToDeploymentConverter
+		appWorkload         *korifiv1alpha1.AppWorkload
+		statefulSet         *appsv1.StatefulSet
+		deployment          *appsv1.Deployment
+		convertErr          error
+	)
+
+	BeforeEach(func() {
+		appWorkload = &korifiv1alpha1.AppWorkload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app-workload",
+				Namespace: "space-guid",
+			},
+		}
+
+		statefulSet = &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "app-workload-name",
+				Namespace:   "space-guid",
+				Labels:      map[string]string{"label": "value"},
+				Annotations: map[string]string{"annotation": "value"},
+			},
+			Spec: appsv1.StatefulSetSpec{
+				PodManagementPolicy: appsv1.ParallelPodManagement,
+				Replicas:            tools.PtrTo(int32(3)),
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"label": "value"},
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{"label": "value"},
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name: statefulsetcontrollers.ApplicationContainerName,
+							Env: []corev1.EnvVar{
+								{Name: statefulsetcontrollers.EnvCFInstanceGUID, Value: "instance-guid"},
+								{Name: statefulsetcontrollers.EnvCFInstanceIndex, Value: "instance-index"},
+								{Name: statefulsetcontrollers.EnvInstanceIndex, Value: "instance-index"},
+								{Name: "FOO", Value: "bar"},
+							},
+						}},
+					},
+				},
+			},
+		}
+
+		fakeWorkloadToStSet = new(statefulsetfake.WorkloadToStatefulsetConverter)
+		fakeWorkloadToStSet.ConvertReturns(statefulSet, nil)
+
+		converter = controllers.NewAppWorkloadToDeploymentConverter(fakeWorkloadToStSet)
+	})
+
+	JustBeforeEach(func() {
+		deployment, convertErr = converter.Convert(appWorkload)
+	})
+
+	It("builds the pods the way the statefulset-runner does", func() {
+		Expect(convertErr).NotTo(HaveOccurred())
+
+		Expect(fakeWorkloadToStSet.ConvertCallCount()).To(Equal(1))
+		Expect(fakeWorkloadToStSet.ConvertArgsForCall(0)).To(Equal(appWorkload))
+
+		Expect(deployment.ObjectMeta).To(Equal(statefulSet.ObjectMeta))
+		Expect(deployment.Spec.Replicas).To(Equal(tools.PtrTo(int32(3))))
+		Expect(deployment.Spec.Selector).To(Equal(statefulSet.Spec.Selector))
+		Expect(deployment.Spec.Template.Labels).To(Equal(map[string]string{"label": "value"}))
+	})
+
+	It("does not expose an instance index", func() {
+		Expect(convertErr).NotTo(HaveOccurred())
+		Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(1))
+		Expect(deployment.Spec.Template.Spec.Containers[0].Env).To(ConsistOf(
+			corev1.EnvVar{Name: statefulsetcontrollers.EnvCFInstanceGUID, Value: "instance-guid"},
+			corev1.EnvVar{Name: "FOO", Value: "bar"},
+		))
+		Expect(statefulSet.Spec.Template.Spec.Containers[0].Env).To(HaveLen(4))
+	})
+
+	When("the app workload has sidecars", func() {
+		BeforeEach(func() {
+			statefulSet.Spec.Template.Spec.Containers = append(statefulSet.Spec.Template.Spec.Containers, corev1.Container{
+				Name:    statefulsetcontrollers.SidecarContainerPrefix + "agent",
+				Command: []string{"run-agent"},
+				Env: []corev1.EnvVar{
+					{Name: statefulsetcontrollers.EnvCFInstanceIndex, Value: "instance-index"},
+					{Name: "FOO", Value: "bar"},
+				},
+			})
+		})
+
+		It("runs the sidecars next to the app", func() {
+			Expect(convertErr).NotTo(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(2))
+			Expect(deployment.Spec.Template.Spec.Containers[1].Name).To(Equal("sidecar-agent"))
+			Expect(deployment.Spec.Template.Spec.Containers[1].Command).To(Equal([]string{"run-agent"}))
+			Expect(deployment.Spec.Template.Spec.Containers[1].Env).To(ConsistOf(
+				corev1.EnvVar{Name: "FOO", Value: "bar"},
+			))
+		})
+	})
+
+	It("rolls out new instances before stopping old ones", func() {
+		Expect(convertErr).NotTo(HaveOccurred())
+		Expect(deployment.Spec.Strategy.Type).To(Equal(appsv1.RollingUpdateDeploymentStrategyType))
+		Expect(deployment.Spec.Strategy.RollingUpdate.MaxUnavailable).To(Equal(tools.PtrTo(intstr.FromInt32(0))))
+		Expect(deployment.Spec.Strategy.RollingUpdate.MaxSurge).To(Equal(tools.PtrTo(intstr.FromString("25%"))))
+	})
+
+	When("converting the app workload to a statefulset fails", func() {
+		BeforeEach(func() {
+			fakeWorkloadToStSet.ConvertReturns(nil, errors.New("convert-error"))
+		})
+
+		It("returns the error", func() {
+			Expect(convertErr).To(MatchError("convert-error"))
+		})
+	})
+})
@@ -0,0 +1,96 @@
+package integration_test
+
+import (
+	"context"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	statefulsetcontrollers "code.cloudfoundry.org/korifi/statefulset-runner/controllers"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gstruct"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("AppWorkloadsController", func() {
+	var (
+		ctx           context.Context
+		appWorkload   *korifiv1alpha1.AppWorkload
+		namespaceName string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespaceName = uuid.NewString()
+		Expect(k8sClient.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespaceName,
+			},
+		})).To(Succeed())
+
+		appWorkload = &korifiv1alpha1.AppWorkload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      uuid.NewString(),
+				Namespace: namespaceName,
+			},
+			Spec: korifiv1alpha1.AppWorkloadSpec{
+				GUID:    uuid.NewString(),
+				Version: uuid.NewString(),
+				AppGUID: uuid.NewString(),
+
+				ProcessType: uuid.NewString(),
+				Image:       uuid.NewString(),
+				Instances:   5,
+				RunnerName:  "deployment-runner",
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(ctx, appWorkload)).To(Succeed())
+	})
+
+	listDeployments := func(g Gomega) []appsv1.Deployment {
+		deploymentList := appsv1.DeploymentList{}
+		g.Expect(k8sClient.List(ctx, &deploymentList, client.InNamespace(namespaceName), client.MatchingLabels{
+			statefulsetcontrollers.LabelGUID: appWorkload.Spec.GUID,
+		})).To(Succeed())
+
+		return deploymentList.Items
+	}
+
+	It("creates the deployment", func() {
+		Eventually(func(g Gomega) {
+			deployments := listDeployments(g)
+			g.Expect(deployments).To(HaveLen(1))
+			g.Expect(deployments[0].Spec.Replicas).To(gstruct.PointTo(BeEquivalentTo(5)))
+			g.Expect(deployments[0].OwnerReferences).To(ConsistOf(gstruct.MatchFields(gstruct.IgnoreExtras, gstruct.Fields{
+				"Kind": Equal("AppWorkload"),
+				"Name": Equal(appWorkload.Name),
+			})))
+		}).Should(Succeed())
+	})
+
+	It("sets the observed generation of the app workload", func() {
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(appWorkload), appWorkload)).To(Succeed())
+			g.Expect(appWorkload.Status.ObservedGeneration).To(Equal(appWorkload.Generation))
+		}).Should(Succeed())
+	})
+
+	When("the app workload is targeted at another runner", func() {
+		BeforeEach(func() {
+			appWorkload.Spec.RunnerName = "statefulset-runner"
+		})
+
+		It("does not create a deployment", func() {
+			Consistently(func(g Gomega) {
+				g.Expect(listDeployments(g)).To(BeEmpty())
+			}).Should(Succeed())
+		})
+	})
+})
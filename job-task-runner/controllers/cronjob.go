@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// createOrPatchCronJob runs scheduled TaskWorkloads with a CronJob. Runs of
+// the task do not overlap: a run is skipped while the previous one is still
+// going.
+func (r *TaskWorkloadReconciler) createOrPatchCronJob(ctx context.Context, taskWorkload *korifiv1alpha1.TaskWorkload) error {
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      taskWorkload.Name,
+			Namespace: taskWorkload.Namespace,
+		},
+	}
+
+	_, err := controllerutil.CreateOrPatch(ctx, r.k8sClient, cronJob, func() error {
+		job := WorkloadToJob(taskWorkload, int32(r.jobTTL.Seconds()), r.jobBackoffLimit, r.jobTaskRunnerTemporarySetPodSeccompProfile, r.trustedCAConfigMapName)
+
+		cronJob.Spec.Schedule = taskWorkload.Spec.Schedule
+		cronJob.Spec.Suspend = &taskWorkload.Spec.Suspend
+		cronJob.Spec.ConcurrencyPolicy = batchv1.ForbidConcurrent
+		cronJob.Spec.JobTemplate = batchv1.JobTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: taskWorkload.Labels,
+			},
+			Spec: job.Spec,
+		}
+
+		return controllerutil.SetControllerReference(taskWorkload, cronJob, r.scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or patch cron job for task workload %s:%s: %w", taskWorkload.Namespace, taskWorkload.Name, err)
+	}
+
+	taskWorkload.Status.LastScheduleTime = cronJob.Status.LastScheduleTime
+
+	return nil
+}
@@ -4,6 +4,7 @@ package fake
 import (
 	"context"
 	"sync"
+	"time"
 
 	"code.cloudfoundry.org/korifi/job-task-runner/controllers"
 	v1a "k8s.io/api/batch/v1"
@@ -11,7 +12,7 @@ import (
 )
 
 type TaskStatusGetter struct {
-	GetStatusConditionsStub        func(context.Context, *v1a.Job) ([]v1.Condition, error)
+	GetStatusConditionsStub        func(context.Context, *v1a.Job) ([]v1.Condition, time.Duration, error)
 	getStatusConditionsMutex       sync.RWMutex
 	getStatusConditionsArgsForCall []struct {
 		arg1 context.Context
@@ -19,17 +20,19 @@ type TaskStatusGetter struct {
 	}
 	getStatusConditionsReturns struct {
 		result1 []v1.Condition
-		result2 error
+		result2 time.Duration
+		result3 error
 	}
 	getStatusConditionsReturnsOnCall map[int]struct {
 		result1 []v1.Condition
-		result2 error
+		result2 time.Duration
+		result3 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *TaskStatusGetter) GetStatusConditions(arg1 context.Context, arg2 *v1a.Job) ([]v1.Condition, error) {
+func (fake *TaskStatusGetter) GetStatusConditions(arg1 context.Context, arg2 *v1a.Job) ([]v1.Condition, time.Duration, error) {
 	fake.getStatusConditionsMutex.Lock()
 	ret, specificReturn := fake.getStatusConditionsReturnsOnCall[len(fake.getStatusConditionsArgsForCall)]
 	fake.getStatusConditionsArgsForCall = append(fake.getStatusConditionsArgsForCall, struct {
@@ -44,9 +47,9 @@ func (fake *TaskStatusGetter) GetStatusConditions(arg1 context.Context, arg2 *v1
 		return stub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1, ret.result2, ret.result3
 	}
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
 func (fake *TaskStatusGetter) GetStatusConditionsCallCount() int {
@@ -55,7 +58,7 @@ func (fake *TaskStatusGetter) GetStatusConditionsCallCount() int {
 	return len(fake.getStatusConditionsArgsForCall)
 }
 
-func (fake *TaskStatusGetter) GetStatusConditionsCalls(stub func(context.Context, *v1a.Job) ([]v1.Condition, error)) {
+func (fake *TaskStatusGetter) GetStatusConditionsCalls(stub func(context.Context, *v1a.Job) ([]v1.Condition, time.Duration, error)) {
 	fake.getStatusConditionsMutex.Lock()
 	defer fake.getStatusConditionsMutex.Unlock()
 	fake.GetStatusConditionsStub = stub
@@ -68,30 +71,33 @@ func (fake *TaskStatusGetter) GetStatusConditionsArgsForCall(i int) (context.Con
 	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *TaskStatusGetter) GetStatusConditionsReturns(result1 []v1.Condition, result2 error) {
+func (fake *TaskStatusGetter) GetStatusConditionsReturns(result1 []v1.Condition, result2 time.Duration, result3 error) {
 	fake.getStatusConditionsMutex.Lock()
 	defer fake.getStatusConditionsMutex.Unlock()
 	fake.GetStatusConditionsStub = nil
 	fake.getStatusConditionsReturns = struct {
 		result1 []v1.Condition
-		result2 error
-	}{result1, result2}
+		result2 time.Duration
+		result3 error
+	}{result1, result2, result3}
 }
 
-func (fake *TaskStatusGetter) GetStatusConditionsReturnsOnCall(i int, result1 []v1.Condition, result2 error) {
+func (fake *TaskStatusGetter) GetStatusConditionsReturnsOnCall(i int, result1 []v1.Condition, result2 time.Duration, result3 error) {
 	fake.getStatusConditionsMutex.Lock()
 	defer fake.getStatusConditionsMutex.Unlock()
 	fake.GetStatusConditionsStub = nil
 	if fake.getStatusConditionsReturnsOnCall == nil {
 		fake.getStatusConditionsReturnsOnCall = make(map[int]struct {
 			result1 []v1.Condition
-			result2 error
+			result2 time.Duration
+			result3 error
 		})
 	}
 	fake.getStatusConditionsReturnsOnCall[i] = struct {
 		result1 []v1.Condition
-		result2 error
-	}{result1, result2}
+		result2 time.Duration
+		result3 error
+	}{result1, result2, result3}
 }
 
 func (fake *TaskStatusGetter) Invocations() map[string][][]interface{} {
@@ -82,7 +82,9 @@ var _ = BeforeSuite(func() {
 		k8sManager.GetScheme(),
 		controllers.NewStatusGetter(k8sManager.GetClient()),
 		time.Minute,
+		0,
 		false,
+		"",
 	)
 	err = taskWorkloadReconciler.SetupWithManager(k8sManager)
 	Expect(err).NotTo(HaveOccurred())
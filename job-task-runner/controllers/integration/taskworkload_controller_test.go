@@ -104,4 +104,42 @@ var _ = Describe("Job TaskWorkload Controller Integration Test", func() {
 			g.Expect(meta.IsStatusConditionTrue(taskWorkload.Status.Conditions, korifiv1alpha1.TaskInitializedConditionType)).To(BeTrue())
 		}).Should(Succeed())
 	})
+
+	When("the task workload is scheduled", func() {
+		BeforeEach(func() {
+			taskWorkload.Spec.Schedule = "*/5 * * * *"
+			taskWorkload.Spec.Suspend = true
+		})
+
+		It("creates a cron job owned by the task workload instead of a job", func() {
+			Expect(createErr).NotTo(HaveOccurred())
+
+			cronJob := &batchv1.CronJob{}
+			Eventually(func(g Gomega) {
+				g.Expect(adminClient.Get(context.Background(), client.ObjectKeyFromObject(taskWorkload), cronJob)).To(Succeed())
+			}).Should(Succeed())
+
+			Expect(cronJob.OwnerReferences).To(ConsistOf(SatisfyAll(
+				HaveField("Name", taskWorkload.Name),
+				HaveField("Controller", Equal(tools.PtrTo(true))),
+			)))
+			Expect(cronJob.Spec.Schedule).To(Equal("*/5 * * * *"))
+			Expect(cronJob.Spec.Suspend).To(Equal(tools.PtrTo(true)))
+			Expect(cronJob.Spec.ConcurrencyPolicy).To(Equal(batchv1.ForbidConcurrent))
+
+			jobSpec := cronJob.Spec.JobTemplate.Spec
+			Expect(jobSpec.BackoffLimit).To(Equal(tools.PtrTo(int32(0))))
+			Expect(jobSpec.Template.Spec.ServiceAccountName).To(Equal("korifi-task"))
+			Expect(jobSpec.Template.Spec.Containers).To(ConsistOf(SatisfyAll(
+				HaveField("Image", "my-image"),
+				HaveField("Command", Equal([]string{"echo", "hello"})),
+			)))
+
+			Consistently(func(g Gomega) {
+				jobList := &batchv1.JobList{}
+				g.Expect(adminClient.List(context.Background(), jobList, client.InNamespace(testNamespace.Name))).To(Succeed())
+				g.Expect(jobList.Items).To(BeEmpty())
+			}, "1s").Should(Succeed())
+		})
+	})
 })
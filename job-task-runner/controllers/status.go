@@ -3,6 +3,9 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -11,6 +14,21 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// imagePullFailureReasons are the container waiting reasons that mean the
+// task image cannot be pulled. ErrImagePull is left out as it is also reported
+// for transient failures; kubelet reports ImagePullBackOff once it retries.
+var imagePullFailureReasons = []string{
+	"ImagePullBackOff",
+	"InvalidImageName",
+	"ErrImageNeverPull",
+}
+
+// imagePullBackOffTolerance is how long the pull of the task image is retried
+// before the task fails. Kubelet backs off exponentially from 10 seconds up to
+// 5 minutes between pulls, so transient registry failures get about five
+// retries to recover.
+const imagePullBackOffTolerance = 5 * time.Minute
+
 type StatusGetter struct {
 	k8sClient client.Client
 }
@@ -21,7 +39,7 @@ func NewStatusGetter(k8sClient client.Client) *StatusGetter {
 	}
 }
 
-func (s *StatusGetter) GetStatusConditions(ctx context.Context, job *batchv1.Job) ([]metav1.Condition, error) {
+func (s *StatusGetter) GetStatusConditions(ctx context.Context, job *batchv1.Job) ([]metav1.Condition, time.Duration, error) {
 	conditions := []metav1.Condition{
 		{
 			Type:   korifiv1alpha1.TaskInitializedConditionType,
@@ -31,7 +49,7 @@ func (s *StatusGetter) GetStatusConditions(ctx context.Context, job *batchv1.Job
 	}
 
 	if job.Status.StartTime == nil {
-		return conditions, nil
+		return conditions, 0, nil
 	}
 
 	conditions = append(conditions, metav1.Condition{
@@ -48,60 +66,138 @@ func (s *StatusGetter) GetStatusConditions(ctx context.Context, job *batchv1.Job
 			LastTransitionTime: *job.Status.CompletionTime,
 			Reason:             "JobSucceeded",
 		})
+
+		return conditions, 0, nil
 	}
 
-	lastFailureTimestamp := getLastFailureTimestamp(job.Status)
-	if job.Status.Failed > 0 && lastFailureTimestamp != nil {
-		terminationState, err := s.getFailedContainerStatus(ctx, job)
+	lastFailure := getLastFailureCondition(job.Status)
+	if job.Status.Failed > 0 && lastFailure != nil {
+		reason, message, err := s.getFailure(ctx, job, lastFailure)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get container status: %w", err)
+			return nil, 0, fmt.Errorf("failed to get failure reason: %w", err)
 		}
 
 		conditions = append(conditions, metav1.Condition{
 			Type:               korifiv1alpha1.TaskFailedConditionType,
 			Status:             metav1.ConditionTrue,
-			LastTransitionTime: *lastFailureTimestamp,
-			Reason:             terminationState.Reason,
-			Message:            fmt.Sprintf("Failed with exit code: %d", terminationState.ExitCode),
+			LastTransitionTime: lastFailure.LastTransitionTime,
+			Reason:             reason,
+			Message:            message,
+		})
+
+		return conditions, 0, nil
+	}
+
+	imagePullFailure, retryAfter, err := s.getImagePullFailure(ctx, job)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get container status: %w", err)
+	}
+
+	if imagePullFailure != nil {
+		conditions = append(conditions, metav1.Condition{
+			Type:               korifiv1alpha1.TaskFailedConditionType,
+			Status:             metav1.ConditionTrue,
+			LastTransitionTime: metav1.Now(),
+			Reason:             imagePullFailure.Reason,
+			Message:            fmt.Sprintf("Failed to pull image: %s", imagePullFailure.Message),
 		})
 	}
 
-	return conditions, nil
+	return conditions, retryAfter, nil
+}
+
+// getFailure returns the reason and message of the failure of the last pod of
+// the job. When the pod is gone or did not get to run the workload container,
+// the reason and message of the job failure are returned instead, e.g. when
+// the job exceeded its deadline.
+func (s *StatusGetter) getFailure(ctx context.Context, job *batchv1.Job, jobFailure *batchv1.JobCondition) (string, string, error) {
+	jobPod, err := s.getLastPod(ctx, job)
+	if err != nil {
+		return "", "", err
+	}
+
+	if jobPod != nil {
+		if terminated := getWorkloadContainerState(jobPod).Terminated; terminated != nil {
+			return terminated.Reason, terminationMessage(terminated), nil
+		}
+
+		if jobPod.Status.Reason != "" {
+			return jobPod.Status.Reason, jobPod.Status.Message, nil
+		}
+	}
+
+	return jobFailure.Reason, jobFailure.Message, nil
 }
 
-func (s *StatusGetter) getFailedContainerStatus(ctx context.Context, job *batchv1.Job) (*corev1.ContainerStateTerminated, error) {
+func terminationMessage(terminated *corev1.ContainerStateTerminated) string {
+	message := fmt.Sprintf("Failed with exit code: %d", terminated.ExitCode)
+
+	if terminated.Reason == "OOMKilled" {
+		message += " (out of memory)"
+	}
+
+	if terminationLog := strings.TrimSpace(terminated.Message); terminationLog != "" {
+		message += ": " + terminationLog
+	}
+
+	return message
+}
+
+// getImagePullFailure returns the image pull failure of the last pod of the
+// job. Pods backing off from pulling the image only fail once they have done
+// so for longer than the tolerance, which is returned as the time left
+// until then, as the pod status does not change meanwhile.
+func (s *StatusGetter) getImagePullFailure(ctx context.Context, job *batchv1.Job) (*corev1.ContainerStateWaiting, time.Duration, error) {
+	jobPod, err := s.getLastPod(ctx, job)
+	if err != nil || jobPod == nil {
+		return nil, 0, err
+	}
+
+	waiting := getWorkloadContainerState(jobPod).Waiting
+	if waiting == nil || !slices.Contains(imagePullFailureReasons, waiting.Reason) {
+		return nil, 0, nil
+	}
+
+	if waiting.Reason == "ImagePullBackOff" {
+		if remaining := imagePullBackOffTolerance - time.Since(jobPod.CreationTimestamp.Time); remaining > 0 {
+			return nil, remaining, nil
+		}
+	}
+
+	return waiting, 0, nil
+}
+
+// getLastPod returns the most recently created pod of the job, as the job
+// creates a new pod for every retry
+func (s *StatusGetter) getLastPod(ctx context.Context, job *batchv1.Job) (*corev1.Pod, error) {
 	var jobPods corev1.PodList
 	if err := s.k8sClient.List(ctx, &jobPods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
 		return nil, err
 	}
 
-	if len(jobPods.Items) > 1 {
-		return nil, fmt.Errorf("found more than one pod for job %s:%s", job.Namespace, job.Name)
-	}
-
 	if len(jobPods.Items) == 0 {
-		return nil, fmt.Errorf("no pods found for job %s:%s", job.Namespace, job.Name)
+		return nil, nil
 	}
 
-	jobPod := jobPods.Items[0]
+	lastPod := slices.MaxFunc(jobPods.Items, func(a, b corev1.Pod) int {
+		return a.CreationTimestamp.Compare(b.CreationTimestamp.Time)
+	})
 
-	for _, containerStatus := range jobPod.Status.ContainerStatuses {
-		if containerStatus.Name != workloadContainerName {
-			continue
-		}
+	return &lastPod, nil
+}
 
-		if containerStatus.State.Terminated == nil {
-			return nil, fmt.Errorf("no terminated state found for job %s:%s", job.Namespace, job.Name)
+func getWorkloadContainerState(jobPod *corev1.Pod) corev1.ContainerState {
+	for _, containerStatus := range jobPod.Status.ContainerStatuses {
+		if containerStatus.Name == workloadContainerName {
+			return containerStatus.State
 		}
-
-		return containerStatus.State.Terminated, nil
 	}
 
-	return nil, fmt.Errorf("no workload container found for job %s:%s", job.Namespace, job.Name)
+	return corev1.ContainerState{}
 }
 
-func getLastFailureTimestamp(jobStatus batchv1.JobStatus) *metav1.Time {
-	var lastFailure *metav1.Time
+func getLastFailureCondition(jobStatus batchv1.JobStatus) *batchv1.JobCondition {
+	var lastFailure *batchv1.JobCondition
 
 	for _, condition := range jobStatus.Conditions {
 		condition := condition
@@ -109,8 +205,8 @@ func getLastFailureTimestamp(jobStatus batchv1.JobStatus) *metav1.Time {
 			continue
 		}
 
-		if lastFailure == nil || condition.LastTransitionTime.After(lastFailure.Time) {
-			lastFailure = &condition.LastTransitionTime
+		if lastFailure == nil || condition.LastTransitionTime.After(lastFailure.LastTransitionTime.Time) {
+			lastFailure = &condition
 		}
 	}
 
@@ -22,6 +22,7 @@ var _ = Describe("StatusGetter", func() {
 		statusGetter  *controllers.StatusGetter
 		job           *batchv1.Job
 		conditions    []metav1.Condition
+		retryAfter    time.Duration
 		conditionsErr error
 	)
 
@@ -34,11 +35,12 @@ var _ = Describe("StatusGetter", func() {
 	})
 
 	JustBeforeEach(func() {
-		conditions, conditionsErr = statusGetter.GetStatusConditions(context.Background(), job)
+		conditions, retryAfter, conditionsErr = statusGetter.GetStatusConditions(context.Background(), job)
 	})
 
 	It("succeeds", func() {
 		Expect(conditionsErr).NotTo(HaveOccurred())
+		Expect(retryAfter).To(BeZero())
 	})
 
 	It("returns an initialized condition", func() {
@@ -48,27 +50,6 @@ var _ = Describe("StatusGetter", func() {
 		Expect(initializedStatusCondition.Reason).To(Equal("JobCreated"))
 	})
 
-	When("the job is running", func() {
-		var now metav1.Time
-
-		BeforeEach(func() {
-			now = metav1.Now()
-			job = &batchv1.Job{
-				Status: batchv1.JobStatus{
-					StartTime: &now,
-				},
-			}
-		})
-
-		It("contains a started condition with a matching timestamp", func() {
-			startedStatusCondition := meta.FindStatusCondition(conditions, korifiv1alpha1.TaskStartedConditionType)
-			Expect(startedStatusCondition).NotTo(BeNil())
-			Expect(startedStatusCondition.Status).To(Equal(metav1.ConditionTrue))
-			Expect(startedStatusCondition.Reason).To(Equal("JobStarted"))
-			Expect(startedStatusCondition.LastTransitionTime).To(Equal(now))
-		})
-	})
-
 	When("the job has succeeded", func() {
 		var (
 			now   metav1.Time
@@ -194,43 +175,215 @@ var _ = Describe("StatusGetter", func() {
 			})
 		})
 
-		When("we get more than one pod for a job", func() {
+		When("the task ran out of memory", func() {
 			BeforeEach(func() {
-				podList.Items = append(podList.Items, podList.Items[0])
+				podList.Items[0].Status.ContainerStatuses[1].State.Terminated = &corev1.ContainerStateTerminated{
+					ExitCode: 137,
+					Reason:   "OOMKilled",
+				}
 			})
 
-			It("returns an error", func() {
-				Expect(conditionsErr).To(MatchError(ContainSubstring("found more than one pod for job")))
+			It("says so in the failure message", func() {
+				failedCondition := meta.FindStatusCondition(conditions, korifiv1alpha1.TaskFailedConditionType)
+				Expect(failedCondition.Reason).To(Equal("OOMKilled"))
+				Expect(failedCondition.Message).To(Equal("Failed with exit code: 137 (out of memory)"))
+			})
+		})
+
+		When("the task wrote a termination message", func() {
+			BeforeEach(func() {
+				podList.Items[0].Status.ContainerStatuses[1].State.Terminated.Message = "database unreachable\n"
+			})
+
+			It("includes it in the failure message", func() {
+				failedCondition := meta.FindStatusCondition(conditions, korifiv1alpha1.TaskFailedConditionType)
+				Expect(failedCondition.Message).To(Equal("Failed with exit code: 42: database unreachable"))
+			})
+		})
+
+		When("the job has retried the task", func() {
+			BeforeEach(func() {
+				retryPod := *podList.Items[0].DeepCopy()
+				retryPod.CreationTimestamp = later
+				retryPod.Status.ContainerStatuses[1].State.Terminated.ExitCode = 43
+
+				podList.Items[0].CreationTimestamp = now
+				podList.Items = append(podList.Items, retryPod)
+			})
+
+			It("returns the failure of the last pod", func() {
+				failedCondition := meta.FindStatusCondition(conditions, korifiv1alpha1.TaskFailedConditionType)
+				Expect(failedCondition.Message).To(Equal("Failed with exit code: 43"))
+			})
+		})
+
+		When("the pod was evicted", func() {
+			BeforeEach(func() {
+				podList.Items[0].Status.ContainerStatuses = nil
+				podList.Items[0].Status.Reason = "Evicted"
+				podList.Items[0].Status.Message = "The node was low on resource: memory."
+			})
+
+			It("returns the pod failure", func() {
+				failedCondition := meta.FindStatusCondition(conditions, korifiv1alpha1.TaskFailedConditionType)
+				Expect(failedCondition.Reason).To(Equal("Evicted"))
+				Expect(failedCondition.Message).To(Equal("The node was low on resource: memory."))
 			})
 		})
 
 		When("there are no pods for the job", func() {
 			BeforeEach(func() {
 				podList.Items = nil
+				job.Status.Conditions[2].Reason = "DeadlineExceeded"
+				job.Status.Conditions[2].Message = "Job was active longer than specified deadline"
 			})
 
-			It("returns an error", func() {
-				Expect(conditionsErr).To(MatchError(ContainSubstring("no pods found for job")))
+			It("returns the job failure", func() {
+				Expect(conditionsErr).NotTo(HaveOccurred())
+				failedCondition := meta.FindStatusCondition(conditions, korifiv1alpha1.TaskFailedConditionType)
+				Expect(failedCondition.LastTransitionTime).To(Equal(later))
+				Expect(failedCondition.Reason).To(Equal("DeadlineExceeded"))
+				Expect(failedCondition.Message).To(Equal("Job was active longer than specified deadline"))
 			})
 		})
 
 		When("task container does not have termination status", func() {
 			BeforeEach(func() {
 				podList.Items[0].Status.ContainerStatuses[1].State.Terminated = nil
+				job.Status.Conditions[2].Reason = "BackoffLimitExceeded"
 			})
 
-			It("returns an error", func() {
-				Expect(conditionsErr).To(MatchError(ContainSubstring("no terminated state found")))
+			It("returns the job failure", func() {
+				Expect(conditionsErr).NotTo(HaveOccurred())
+				failedCondition := meta.FindStatusCondition(conditions, korifiv1alpha1.TaskFailedConditionType)
+				Expect(failedCondition.Reason).To(Equal("BackoffLimitExceeded"))
 			})
 		})
+	})
 
-		When("task container is not found", func() {
+	When("the job is running", func() {
+		var (
+			now     metav1.Time
+			podList corev1.PodList
+		)
+
+		BeforeEach(func() {
+			now = metav1.Now()
+			job = &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-job",
+					Namespace: "my-ns",
+				},
+				Status: batchv1.JobStatus{
+					StartTime: &now,
+				},
+			}
+
+			podList = corev1.PodList{
+				Items: []corev1.Pod{{
+					Status: corev1.PodStatus{
+						ContainerStatuses: []corev1.ContainerStatus{{
+							Name: "workload",
+							State: corev1.ContainerState{
+								Running: &corev1.ContainerStateRunning{},
+							},
+						}},
+					},
+				}},
+			}
+
+			fakeClient.ListStub = func(ctx context.Context, objList client.ObjectList, opts ...client.ListOption) error {
+				list, ok := objList.(*corev1.PodList)
+				Expect(ok).To(BeTrue())
+				*list = podList
+
+				return nil
+			}
+		})
+
+		It("contains a started condition with a matching timestamp", func() {
+			startedStatusCondition := meta.FindStatusCondition(conditions, korifiv1alpha1.TaskStartedConditionType)
+			Expect(startedStatusCondition).NotTo(BeNil())
+			Expect(startedStatusCondition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(startedStatusCondition.Reason).To(Equal("JobStarted"))
+			Expect(startedStatusCondition.LastTransitionTime).To(Equal(now))
+		})
+
+		It("does not return a failed condition", func() {
+			Expect(conditionsErr).NotTo(HaveOccurred())
+			Expect(meta.FindStatusCondition(conditions, korifiv1alpha1.TaskFailedConditionType)).To(BeNil())
+		})
+
+		When("the task image cannot be pulled", func() {
+			BeforeEach(func() {
+				podList.Items[0].CreationTimestamp = metav1.NewTime(time.Now().Add(-10 * time.Minute))
+				podList.Items[0].Status.ContainerStatuses[0].State = corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{
+						Reason:  "ImagePullBackOff",
+						Message: `Back-off pulling image "registry.io/my/image"`,
+					},
+				}
+			})
+
+			When("the pull has only been retried for a short while", func() {
+				BeforeEach(func() {
+					podList.Items[0].CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Minute))
+				})
+
+				It("keeps retrying", func() {
+					Expect(conditionsErr).NotTo(HaveOccurred())
+					Expect(meta.FindStatusCondition(conditions, korifiv1alpha1.TaskFailedConditionType)).To(BeNil())
+				})
+
+				It("returns the time left until the task fails", func() {
+					Expect(retryAfter).To(BeNumerically("~", 4*time.Minute, 5*time.Second))
+				})
+			})
+
+			When("the image name is invalid", func() {
+				BeforeEach(func() {
+					podList.Items[0].CreationTimestamp = metav1.Now()
+					podList.Items[0].Status.ContainerStatuses[0].State.Waiting.Reason = "InvalidImageName"
+				})
+
+				It("fails without retrying", func() {
+					Expect(conditionsErr).NotTo(HaveOccurred())
+					failedCondition := meta.FindStatusCondition(conditions, korifiv1alpha1.TaskFailedConditionType)
+					Expect(failedCondition).NotTo(BeNil())
+					Expect(failedCondition.Reason).To(Equal("InvalidImageName"))
+				})
+			})
+
+			It("returns a failed condition", func() {
+				Expect(conditionsErr).NotTo(HaveOccurred())
+				failedCondition := meta.FindStatusCondition(conditions, korifiv1alpha1.TaskFailedConditionType)
+				Expect(failedCondition).NotTo(BeNil())
+				Expect(failedCondition.Status).To(Equal(metav1.ConditionTrue))
+				Expect(failedCondition.Reason).To(Equal("ImagePullBackOff"))
+				Expect(failedCondition.Message).To(Equal(`Failed to pull image: Back-off pulling image "registry.io/my/image"`))
+				Expect(retryAfter).To(BeZero())
+			})
+		})
+
+		When("pulling the task image failed for the first time", func() {
 			BeforeEach(func() {
-				podList.Items[0].Status.ContainerStatuses[1].Name = "foo"
+				podList.Items[0].Status.ContainerStatuses[0].State = corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "ErrImagePull"},
+				}
 			})
 
-			It("returns an error", func() {
-				Expect(conditionsErr).To(MatchError(ContainSubstring("no workload container found")))
+			It("waits for the retry", func() {
+				Expect(meta.FindStatusCondition(conditions, korifiv1alpha1.TaskFailedConditionType)).To(BeNil())
+			})
+		})
+
+		When("listing the job pods fails", func() {
+			BeforeEach(func() {
+				fakeClient.ListReturns(errors.New("boom"))
+			})
+
+			It("returns the error", func() {
+				Expect(conditionsErr).To(MatchError(ContainSubstring("boom")))
 			})
 		})
 	})
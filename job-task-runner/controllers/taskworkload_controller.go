@@ -32,10 +32,14 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
@@ -45,8 +49,11 @@ const (
 
 //counterfeiter:generate -o fake -fake-name TaskStatusGetter . TaskStatusGetter
 
+// TaskStatusGetter returns the conditions of the task run by the job, and how
+// long until they are due to change even if the job and its pods do not, or
+// zero when they only change with them
 type TaskStatusGetter interface {
-	GetStatusConditions(ctx context.Context, job *batchv1.Job) ([]metav1.Condition, error)
+	GetStatusConditions(ctx context.Context, job *batchv1.Job) ([]metav1.Condition, time.Duration, error)
 }
 
 // TaskWorkloadReconciler reconciles a TaskWorkload object
@@ -56,7 +63,9 @@ type TaskWorkloadReconciler struct {
 	scheme                                     *runtime.Scheme
 	statusGetter                               TaskStatusGetter
 	jobTTL                                     time.Duration
+	jobBackoffLimit                            int32
 	jobTaskRunnerTemporarySetPodSeccompProfile bool
+	trustedCAConfigMapName                     string
 }
 
 func NewTaskWorkloadReconciler(
@@ -65,15 +74,19 @@ func NewTaskWorkloadReconciler(
 	scheme *runtime.Scheme,
 	statusGetter TaskStatusGetter,
 	jobTTL time.Duration,
+	jobBackoffLimit int32,
 	jobTaskRunnerTemporarySetPodSeccompProfile bool,
+	trustedCAConfigMapName string,
 ) *k8s.PatchingReconciler[korifiv1alpha1.TaskWorkload, *korifiv1alpha1.TaskWorkload] {
 	taskReconciler := TaskWorkloadReconciler{
-		k8sClient:    k8sClient,
-		logger:       logger,
-		scheme:       scheme,
-		statusGetter: statusGetter,
-		jobTTL:       jobTTL,
+		k8sClient:       k8sClient,
+		logger:          logger,
+		scheme:          scheme,
+		statusGetter:    statusGetter,
+		jobTTL:          jobTTL,
+		jobBackoffLimit: jobBackoffLimit,
 		jobTaskRunnerTemporarySetPodSeccompProfile: jobTaskRunnerTemporarySetPodSeccompProfile,
+		trustedCAConfigMapName:                     trustedCAConfigMapName,
 	}
 
 	return k8s.NewPatchingReconciler[korifiv1alpha1.TaskWorkload, *korifiv1alpha1.TaskWorkload](logger, k8sClient, &taskReconciler)
@@ -82,13 +95,43 @@ func NewTaskWorkloadReconciler(
 func (r *TaskWorkloadReconciler) SetupWithManager(mgr ctrl.Manager) *builder.Builder {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&korifiv1alpha1.TaskWorkload{}).
-		Owns(&batchv1.Job{})
+		Owns(&batchv1.Job{}).
+		Owns(&batchv1.CronJob{}).
+		Watches(
+			&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(enqueueTaskWorkloadRequestsForPod),
+			builder.WithPredicates(predicate.NewPredicateFuncs(isTaskJobPod)),
+		)
+}
+
+// isTaskJobPod filters the watched pods to the pods of the task jobs, which
+// are labelled with the job name and their task
+func isTaskJobPod(o client.Object) bool {
+	labels := o.GetLabels()
+	_, isJobPod := labels["job-name"]
+	_, isTaskPod := labels[korifiv1alpha1.CFTaskGUIDLabelKey]
+
+	return isJobPod && isTaskPod
+}
+
+// enqueueTaskWorkloadRequestsForPod maps job pods to the TaskWorkload of the
+// job, so that failures of pods that never get to run, such as image pull
+// failures, are reflected in the TaskWorkload status. Jobs are named after
+// their TaskWorkload.
+func enqueueTaskWorkloadRequestsForPod(ctx context.Context, o client.Object) []reconcile.Request {
+	jobName, ok := o.GetLabels()["job-name"]
+	if !ok {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: o.GetNamespace(), Name: jobName}}}
 }
 
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=taskworkloads,verbs=get;list;watch;patch
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=taskworkloads/status,verbs=get;patch
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=taskworkloads/finalizers,verbs=update
-//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=create;get;list;watch
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=create;get;list;watch;patch
+//+kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=create;get;list;watch;patch
 //+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 
 func (r *TaskWorkloadReconciler) ReconcileResource(ctx context.Context, taskWorkload *korifiv1alpha1.TaskWorkload) (ctrl.Result, error) {
@@ -97,6 +140,14 @@ func (r *TaskWorkloadReconciler) ReconcileResource(ctx context.Context, taskWork
 	taskWorkload.Status.ObservedGeneration = taskWorkload.Generation
 	log.V(1).Info("set observed generation", "generation", taskWorkload.Status.ObservedGeneration)
 
+	if taskWorkload.Spec.Schedule != "" {
+		return ctrl.Result{}, r.createOrPatchCronJob(ctx, taskWorkload)
+	}
+
+	if isFinished(taskWorkload) {
+		return ctrl.Result{}, nil
+	}
+
 	job, err := r.getOrCreateJob(ctx, log, taskWorkload)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -106,12 +157,40 @@ func (r *TaskWorkloadReconciler) ReconcileResource(ctx context.Context, taskWork
 		return ctrl.Result{}, nil
 	}
 
-	if err = r.updateTaskWorkloadStatus(ctx, taskWorkload, job); err != nil {
+	requeueAfter, err := r.updateTaskWorkloadStatus(ctx, taskWorkload, job)
+	if err != nil {
 		log.Info("failed to update task workload status", "reason", err)
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	if err = r.stopFailedJob(ctx, taskWorkload, job); err != nil {
+		log.Info("failed to stop job", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+func isFinished(taskWorkload *korifiv1alpha1.TaskWorkload) bool {
+	return meta.IsStatusConditionTrue(taskWorkload.Status.Conditions, korifiv1alpha1.TaskSucceededConditionType) ||
+		meta.IsStatusConditionTrue(taskWorkload.Status.Conditions, korifiv1alpha1.TaskFailedConditionType)
+}
+
+// stopFailedJob suspends the job of a failed TaskWorkload when the job itself
+// has not failed, e.g. because its image cannot be pulled. Otherwise the job
+// pod would keep retrying and could still run the task.
+func (r *TaskWorkloadReconciler) stopFailedJob(ctx context.Context, taskWorkload *korifiv1alpha1.TaskWorkload, job *batchv1.Job) error {
+	if !meta.IsStatusConditionTrue(taskWorkload.Status.Conditions, korifiv1alpha1.TaskFailedConditionType) {
+		return nil
+	}
+
+	if getLastFailureCondition(job.Status) != nil {
+		return nil
+	}
+
+	return k8s.Patch(ctx, r.k8sClient, job, func() {
+		job.Spec.Suspend = tools.PtrTo(true)
+	})
 }
 
 func (r TaskWorkloadReconciler) getOrCreateJob(ctx context.Context, logger logr.Logger, taskWorkload *korifiv1alpha1.TaskWorkload) (*batchv1.Job, error) {
@@ -135,7 +214,7 @@ func (r TaskWorkloadReconciler) getOrCreateJob(ctx context.Context, logger logr.
 }
 
 func (r TaskWorkloadReconciler) createJob(ctx context.Context, logger logr.Logger, taskWorkload *korifiv1alpha1.TaskWorkload) (*batchv1.Job, error) {
-	job := WorkloadToJob(taskWorkload, int32(r.jobTTL.Seconds()), r.jobTaskRunnerTemporarySetPodSeccompProfile)
+	job := WorkloadToJob(taskWorkload, int32(r.jobTTL.Seconds()), r.jobBackoffLimit, r.jobTaskRunnerTemporarySetPodSeccompProfile, r.trustedCAConfigMapName)
 	err := controllerutil.SetControllerReference(taskWorkload, job, r.scheme)
 	if err != nil {
 		return nil, err
@@ -154,22 +233,38 @@ func (r TaskWorkloadReconciler) createJob(ctx context.Context, logger logr.Logge
 	return job, nil
 }
 
+// WorkloadToJob returns the Job that runs the TaskWorkload. The backoff limit
+// and TTL set on the TaskWorkload take precedence over the given defaults.
+// The trusted CA bundle is mounted unless trustedCAConfigMapName is empty.
 func WorkloadToJob(
 	taskWorkload *korifiv1alpha1.TaskWorkload,
 	jobTTL int32,
+	jobBackoffLimit int32,
 	jobTaskRunnerTemporarySetPodSeccompProfile bool,
+	trustedCAConfigMapName string,
 ) *batchv1.Job {
+	if taskWorkload.Spec.TTLSecondsAfterFinished != nil {
+		jobTTL = *taskWorkload.Spec.TTLSecondsAfterFinished
+	}
+
+	if taskWorkload.Spec.BackoffLimit != nil {
+		jobBackoffLimit = *taskWorkload.Spec.BackoffLimit
+	}
+
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      taskWorkload.Name,
 			Namespace: taskWorkload.Namespace,
 		},
 		Spec: batchv1.JobSpec{
-			BackoffLimit:            tools.PtrTo(int32(0)),
+			BackoffLimit:            tools.PtrTo(jobBackoffLimit),
 			Parallelism:             tools.PtrTo(int32(1)),
 			Completions:             tools.PtrTo(int32(1)),
 			TTLSecondsAfterFinished: tools.PtrTo(jobTTL),
 			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: taskWorkload.Labels,
+				},
 				Spec: corev1.PodSpec{
 					RestartPolicy: corev1.RestartPolicyNever,
 					SecurityContext: &corev1.PodSecurityContext{
@@ -204,13 +299,18 @@ func WorkloadToJob(
 			Type: corev1.SeccompProfileTypeRuntimeDefault,
 		}
 	}
+
+	if trustedCAConfigMapName != "" {
+		k8s.MountTrustedCA(&job.Spec.Template.Spec, trustedCAConfigMapName)
+	}
+
 	return job
 }
 
-func (r *TaskWorkloadReconciler) updateTaskWorkloadStatus(ctx context.Context, taskWorkload *korifiv1alpha1.TaskWorkload, job *batchv1.Job) error {
-	conditions, err := r.statusGetter.GetStatusConditions(ctx, job)
+func (r *TaskWorkloadReconciler) updateTaskWorkloadStatus(ctx context.Context, taskWorkload *korifiv1alpha1.TaskWorkload, job *batchv1.Job) (time.Duration, error) {
+	conditions, requeueAfter, err := r.statusGetter.GetStatusConditions(ctx, job)
 	if err != nil {
-		return fmt.Errorf("failed to get status conditions for job %s:%s: %w", job.Namespace, job.Name, err)
+		return 0, fmt.Errorf("failed to get status conditions for job %s:%s: %w", job.Namespace, job.Name, err)
 	}
 
 	for _, condition := range conditions {
@@ -218,5 +318,5 @@ func (r *TaskWorkloadReconciler) updateTaskWorkloadStatus(ctx context.Context, t
 		meta.SetStatusCondition(&taskWorkload.Status.Conditions, condition)
 	}
 
-	return nil
+	return requeueAfter, nil
 }
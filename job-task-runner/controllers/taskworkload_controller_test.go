@@ -8,8 +8,10 @@ import (
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/job-task-runner/controllers"
 	"code.cloudfoundry.org/korifi/job-task-runner/controllers/fake"
+	"code.cloudfoundry.org/korifi/tools"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -96,7 +98,7 @@ var _ = Describe("TaskworkloadController", func() {
 			Status:             metav1.ConditionTrue,
 			LastTransitionTime: metav1.Now(),
 			Reason:             "something",
-		}}, nil)
+		}}, 0, nil)
 
 		jobTaskRunnerTemporarySetPodSeccompProfile = false
 
@@ -104,7 +106,7 @@ var _ = Describe("TaskworkloadController", func() {
 	})
 
 	JustBeforeEach(func() {
-		reconciler := controllers.NewTaskWorkloadReconciler(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)), fakeClient, scheme.Scheme, statusGetter, time.Hour, jobTaskRunnerTemporarySetPodSeccompProfile)
+		reconciler := controllers.NewTaskWorkloadReconciler(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)), fakeClient, scheme.Scheme, statusGetter, time.Hour, 0, jobTaskRunnerTemporarySetPodSeccompProfile, "")
 		reconcileResult, reconcileErr = reconciler.Reconcile(context.Background(), req)
 	})
 
@@ -216,9 +218,25 @@ var _ = Describe("TaskworkloadController", func() {
 		Expect(meta.IsStatusConditionTrue(patchedTaskWorkload.Status.Conditions, "foo")).To(BeTrue())
 	})
 
+	It("does not requeue the task workload", func() {
+		Expect(reconcileErr).NotTo(HaveOccurred())
+		Expect(reconcileResult.RequeueAfter).To(BeZero())
+	})
+
+	When("the status conditions are due to change", func() {
+		BeforeEach(func() {
+			statusGetter.GetStatusConditionsReturns(nil, 3*time.Minute, nil)
+		})
+
+		It("requeues the task workload for then", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(reconcileResult.RequeueAfter).To(Equal(3 * time.Minute))
+		})
+	})
+
 	When("getting the status conditions fails", func() {
 		BeforeEach(func() {
-			statusGetter.GetStatusConditionsReturns(nil, errors.New("get-conditions-error"))
+			statusGetter.GetStatusConditionsReturns(nil, 0, errors.New("get-conditions-error"))
 		})
 
 		It("returns the error", func() {
@@ -226,6 +244,107 @@ var _ = Describe("TaskworkloadController", func() {
 		})
 	})
 
+	When("the task has failed while the job is still running", func() {
+		patchedJobs := func() []*batchv1.Job {
+			jobs := []*batchv1.Job{}
+			for i := range fakeClient.PatchCallCount() {
+				_, object, _, _ := fakeClient.PatchArgsForCall(i)
+				if job, ok := object.(*batchv1.Job); ok {
+					jobs = append(jobs, job)
+				}
+			}
+			return jobs
+		}
+
+		BeforeEach(func() {
+			statusGetter.GetStatusConditionsReturns([]metav1.Condition{{
+				Type:               korifiv1alpha1.TaskFailedConditionType,
+				Status:             metav1.ConditionTrue,
+				LastTransitionTime: metav1.Now(),
+				Reason:             "ImagePullBackOff",
+			}}, 0, nil)
+		})
+
+		It("suspends the job", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+
+			Expect(patchedJobs()).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Spec": MatchFields(IgnoreExtras, Fields{
+					"Suspend": PointTo(BeTrue()),
+				}),
+			}))))
+		})
+
+		When("the job has failed too", func() {
+			BeforeEach(func() {
+				existingJob.Status.Conditions = []batchv1.JobCondition{{
+					Type:   batchv1.JobFailed,
+					Status: corev1.ConditionTrue,
+				}}
+			})
+
+			It("does not patch the job", func() {
+				Expect(patchedJobs()).To(BeEmpty())
+			})
+		})
+
+		When("suspending the job fails", func() {
+			BeforeEach(func() {
+				fakeClient.PatchStub = func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+					if _, ok := obj.(*batchv1.Job); ok {
+						return errors.New("patch-job-error")
+					}
+					return nil
+				}
+			})
+
+			It("returns the error", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("patch-job-error")))
+			})
+		})
+	})
+
+	When("the task has already finished", func() {
+		BeforeEach(func() {
+			meta.SetStatusCondition(&taskWorkload.Status.Conditions, metav1.Condition{
+				Type:   korifiv1alpha1.TaskSucceededConditionType,
+				Status: metav1.ConditionTrue,
+				Reason: "JobSucceeded",
+			})
+		})
+
+		It("does not recompute the task status", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(statusGetter.GetStatusConditionsCallCount()).To(Equal(0))
+			Expect(fakeClient.CreateCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("job backoff limit and TTL", func() {
+		var job *batchv1.Job
+
+		JustBeforeEach(func() {
+			job = controllers.WorkloadToJob(taskWorkload, 123, 2, false, "")
+		})
+
+		It("uses the defaults", func() {
+			Expect(job.Spec.TTLSecondsAfterFinished).To(PointTo(BeEquivalentTo(123)))
+			Expect(job.Spec.BackoffLimit).To(PointTo(BeEquivalentTo(2)))
+		})
+
+		When("the task workload overrides them", func() {
+			BeforeEach(func() {
+				taskWorkload.Spec.TTLSecondsAfterFinished = tools.PtrTo[int32](60)
+				taskWorkload.Spec.BackoffLimit = tools.PtrTo[int32](0)
+			})
+
+			It("uses the task workload values", func() {
+				Expect(job.Spec.TTLSecondsAfterFinished).To(PointTo(BeEquivalentTo(60)))
+				Expect(job.Spec.BackoffLimit).To(PointTo(BeEquivalentTo(0)))
+			})
+		})
+	})
+
 	Describe("jobTaskRunnerTemporarySetPodSeccompProfile", func() {
 		var (
 			job                                        *batchv1.Job
@@ -237,7 +356,7 @@ var _ = Describe("TaskworkloadController", func() {
 		})
 
 		JustBeforeEach(func() {
-			job = controllers.WorkloadToJob(taskWorkload, 123, jobTaskRunnerTemporarySetPodSeccompProfile)
+			job = controllers.WorkloadToJob(taskWorkload, 123, 0, jobTaskRunnerTemporarySetPodSeccompProfile, "")
 		})
 
 		It("does not set spec.securityContext.seccompProfile", func() {
@@ -254,4 +373,51 @@ var _ = Describe("TaskworkloadController", func() {
 			})
 		})
 	})
+
+	Describe("pod labels", func() {
+		var job *batchv1.Job
+
+		BeforeEach(func() {
+			taskWorkload.Labels = map[string]string{korifiv1alpha1.CFTaskGUIDLabelKey: "my-task-workload"}
+		})
+
+		JustBeforeEach(func() {
+			job = controllers.WorkloadToJob(taskWorkload, 123, 0, false, "")
+		})
+
+		It("labels the pods with the task workload labels", func() {
+			Expect(job.Spec.Template.Labels).To(Equal(map[string]string{korifiv1alpha1.CFTaskGUIDLabelKey: "my-task-workload"}))
+		})
+	})
+
+	Describe("trusted CA", func() {
+		var (
+			job                    *batchv1.Job
+			trustedCAConfigMapName string
+		)
+
+		BeforeEach(func() {
+			trustedCAConfigMapName = ""
+		})
+
+		JustBeforeEach(func() {
+			job = controllers.WorkloadToJob(taskWorkload, 123, 0, false, trustedCAConfigMapName)
+		})
+
+		It("does not mount a trusted CA bundle", func() {
+			Expect(job.Spec.Template.Spec.Volumes).To(BeEmpty())
+		})
+
+		When("a trusted CA config map is configured", func() {
+			BeforeEach(func() {
+				trustedCAConfigMapName = "trusted-ca-bundle"
+			})
+
+			It("mounts the bundle into the task container", func() {
+				Expect(job.Spec.Template.Spec.Volumes).To(ConsistOf(HaveField("ConfigMap.Name", "trusted-ca-bundle")))
+				Expect(job.Spec.Template.Spec.Containers[0].VolumeMounts).To(ConsistOf(HaveField("MountPath", "/etc/cf-system-certificates")))
+				Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "CF_SYSTEM_CERT_PATH", Value: "/etc/cf-system-certificates"}))
+			})
+		})
+	})
 })
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.cloudfoundry.org/korifi/tools/k8s"
+	"github.com/go-logr/logr"
+	buildv1alpha2 "github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+)
+
+// transientFailureMessages are the parts of kpack build failure messages that
+// point at the infrastructure rather than at the app, i.e. the build is likely
+// to succeed when run again
+var transientFailureMessages = []string{
+	// the build pod got evicted from the node
+	"The node was low on resource",
+	"Pod was terminated in response to imminent node shutdown",
+	"Preempted in order to admit critical pod",
+	// pulling or pushing images timed out
+	"i/o timeout",
+	"TLS handshake timeout",
+	"context deadline exceeded",
+	"Client.Timeout exceeded",
+	"connection reset by peer",
+}
+
+// retryTransientFailure requests another kpack build when the failed build of
+// the BuildWorkload failed because of the infrastructure, as long as the
+// configured number of retries has not been used up. It returns true when
+// the BuildWorkload should keep waiting for the retried build.
+func (r *BuildWorkloadReconciler) retryTransientFailure(ctx context.Context, log logr.Logger, kpackBuilds []buildv1alpha2.Build, failedBuild *buildv1alpha2.Build) (bool, error) {
+	if !isTransientFailure(failedBuild) {
+		return false, nil
+	}
+
+	if _, retryRequested := failedBuild.Annotations[buildv1alpha2.BuildNeededAnnotation]; retryRequested {
+		return true, nil
+	}
+
+	// every kpack build of the image generation but the first one is a retry
+	if len(kpackBuilds)-1 >= r.controllerConfig.TransientBuildRetries {
+		return false, nil
+	}
+
+	log.Info("retrying build after transient failure",
+		"build", failedBuild.Name,
+		"reason", failedBuild.Status.GetCondition(corev1alpha1.ConditionSucceeded).Message,
+		"retry", len(kpackBuilds),
+	)
+	err := k8s.Patch(ctx, r.k8sClient, failedBuild, func() {
+		if failedBuild.Annotations == nil {
+			failedBuild.Annotations = map[string]string{}
+		}
+		failedBuild.Annotations[buildv1alpha2.BuildNeededAnnotation] = "true"
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to request a retry of build %q: %w", failedBuild.Name, err)
+	}
+
+	return true, nil
+}
+
+func isTransientFailure(kpackBuild *buildv1alpha2.Build) bool {
+	succeeded := kpackBuild.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+	if !succeeded.IsFalse() {
+		return false
+	}
+
+	for _, message := range transientFailureMessages {
+		if strings.Contains(succeeded.Message, message) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// selectBuilder returns the kpack builder the BuildWorkload is built with.
+// Orgs and spaces can select a builder via the KpackBuilderAnnotationKey
+// annotation of their CFOrg or CFSpace, with the space annotation taking
+// precedence. Without any annotation the default ClusterBuilder is used.
+func (r *BuildWorkloadReconciler) selectBuilder(ctx context.Context, buildWorkload *korifiv1alpha1.BuildWorkload) (corev1.ObjectReference, error) {
+	selection, err := r.getBuilderSelection(ctx, buildWorkload.Namespace)
+	if err != nil {
+		return corev1.ObjectReference{}, err
+	}
+
+	if selection == "" {
+		return r.defaultBuilderRef(), nil
+	}
+
+	builderRef, err := parseBuilderSelection(selection, buildWorkload.Namespace)
+	if err != nil {
+		meta.SetStatusCondition(&buildWorkload.Status.Conditions, metav1.Condition{
+			Type:               korifiv1alpha1.SucceededConditionType,
+			Status:             metav1.ConditionFalse,
+			Reason:             "InvalidBuilder",
+			Message:            err.Error(),
+			ObservedGeneration: buildWorkload.Generation,
+		})
+		return corev1.ObjectReference{}, newDoNotRetryError(err)
+	}
+
+	return builderRef, nil
+}
+
+func (r *BuildWorkloadReconciler) defaultBuilderRef() corev1.ObjectReference {
+	return corev1.ObjectReference{
+		Kind:       clusterBuilderKind,
+		Name:       r.controllerConfig.ClusterBuilderName,
+		APIVersion: clusterBuilderAPIVersion,
+	}
+}
+
+func (r *BuildWorkloadReconciler) getBuilderSelection(ctx context.Context, spaceGUID string) (string, error) {
+	spaceNamespace := &corev1.Namespace{}
+	if err := r.k8sClient.Get(ctx, client.ObjectKey{Name: spaceGUID}, spaceNamespace); err != nil {
+		return "", fmt.Errorf("failed to get namespace %q: %w", spaceGUID, err)
+	}
+
+	orgGUID := spaceNamespace.Labels[korifiv1alpha1.OrgGUIDKey]
+	if orgGUID == "" {
+		return "", nil
+	}
+
+	cfSpace := &korifiv1alpha1.CFSpace{}
+	if err := r.getIgnoringNotFound(ctx, client.ObjectKey{Namespace: orgGUID, Name: spaceGUID}, cfSpace); err != nil {
+		return "", err
+	}
+
+	if selection := cfSpace.Annotations[korifiv1alpha1.KpackBuilderAnnotationKey]; selection != "" {
+		return selection, nil
+	}
+
+	cfOrg := &korifiv1alpha1.CFOrg{}
+	if err := r.getIgnoringNotFound(ctx, client.ObjectKey{Namespace: r.controllerConfig.CFRootNamespace, Name: orgGUID}, cfOrg); err != nil {
+		return "", err
+	}
+
+	return cfOrg.Annotations[korifiv1alpha1.KpackBuilderAnnotationKey], nil
+}
+
+func (r *BuildWorkloadReconciler) getIgnoringNotFound(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	err := r.k8sClient.Get(ctx, key, obj)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %T %q: %w", obj, key, err)
+	}
+
+	return nil
+}
+
+func parseBuilderSelection(selection string, namespace string) (corev1.ObjectReference, error) {
+	kind, name, found := strings.Cut(selection, "/")
+	if !found {
+		kind, name = clusterBuilderKind, selection
+	}
+
+	if name == "" {
+		return corev1.ObjectReference{}, fmt.Errorf("invalid kpack builder %q: the builder name must not be empty", selection)
+	}
+
+	switch kind {
+	case clusterBuilderKind:
+		return corev1.ObjectReference{
+			Kind:       clusterBuilderKind,
+			Name:       name,
+			APIVersion: clusterBuilderAPIVersion,
+		}, nil
+	case builderKind:
+		return corev1.ObjectReference{
+			Kind:       builderKind,
+			Name:       name,
+			Namespace:  namespace,
+			APIVersion: clusterBuilderAPIVersion,
+		}, nil
+	}
+
+	return corev1.ObjectReference{}, fmt.Errorf("invalid kpack builder %q: the kind must be either %s or %s", selection, clusterBuilderKind, builderKind)
+}
@@ -55,11 +55,13 @@ import (
 
 const (
 	clusterBuilderKind          = "ClusterBuilder"
+	builderKind                 = "Builder"
 	clusterBuilderAPIVersion    = "kpack.io/v1alpha2"
 	BuildWorkloadLabelKey       = "korifi.cloudfoundry.org/build-workload-name"
 	ImageGenerationKey          = "korifi.cloudfoundry.org/kpack-image-generation"
 	KpackReconcilerName         = "kpack-image-builder"
 	buildpackBuildMetadataLabel = "io.buildpacks.build.metadata"
+	buildCacheTag               = "build-cache"
 )
 
 //counterfeiter:generate -o fake -fake-name ImageConfigGetter . ImageConfigGetter
@@ -74,6 +76,10 @@ type RepositoryCreator interface {
 	CreateRepository(ctx context.Context, name string) error
 }
 
+type RepositoryNamer interface {
+	DropletsRepository(ctx context.Context, spaceGUID string, appGUID string) (string, error)
+}
+
 func NewBuildWorkloadReconciler(
 	c client.Client,
 	scheme *runtime.Scheme,
@@ -81,8 +87,10 @@ func NewBuildWorkloadReconciler(
 	config *config.ControllerConfig,
 	imageConfigGetter ImageConfigGetter,
 	imageRepoPrefix string,
+	imageRepoNamer RepositoryNamer,
 	imageRepoCreator RepositoryCreator,
 	builderReadinessTimeout time.Duration,
+	stagingTimeout time.Duration,
 ) *k8s.PatchingReconciler[korifiv1alpha1.BuildWorkload, *korifiv1alpha1.BuildWorkload] {
 	buildWorkloadReconciler := BuildWorkloadReconciler{
 		k8sClient:               c,
@@ -91,8 +99,10 @@ func NewBuildWorkloadReconciler(
 		controllerConfig:        config,
 		imageConfigGetter:       imageConfigGetter,
 		imageRepoPrefix:         imageRepoPrefix,
+		imageRepoNamer:          imageRepoNamer,
 		imageRepoCreator:        imageRepoCreator,
 		builderReadinessTimeout: builderReadinessTimeout,
+		stagingTimeout:          stagingTimeout,
 	}
 	return k8s.NewPatchingReconciler[korifiv1alpha1.BuildWorkload, *korifiv1alpha1.BuildWorkload](log, c, &buildWorkloadReconciler)
 }
@@ -105,8 +115,10 @@ type BuildWorkloadReconciler struct {
 	controllerConfig        *config.ControllerConfig
 	imageConfigGetter       ImageConfigGetter
 	imageRepoPrefix         string
+	imageRepoNamer          RepositoryNamer
 	imageRepoCreator        RepositoryCreator
 	builderReadinessTimeout time.Duration
+	stagingTimeout          time.Duration
 }
 
 func (r *BuildWorkloadReconciler) SetupWithManager(mgr ctrl.Manager) *builder.Builder {
@@ -159,6 +171,7 @@ func filterBuildWorkloads(object client.Object) bool {
 
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=buildworkloads,verbs=get;list;watch;create;patch;delete
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=buildworkloads/status,verbs=get;patch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cforgs;cfspaces,verbs=get;list;watch
 
 //+kubebuilder:rbac:groups=kpack.io,resources=images,verbs=get;list;watch;create;patch;delete
 //+kubebuilder:rbac:groups=kpack.io,resources=images/status,verbs=get;patch
@@ -168,6 +181,7 @@ func filterBuildWorkloads(object client.Object) bool {
 //+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=list;watch
 
 //+kubebuilder:rbac:groups="",resources=serviceaccounts;secrets,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=serviceaccounts/status;secrets/status,verbs=get
 
 func (r *BuildWorkloadReconciler) ReconcileResource(ctx context.Context, buildWorkload *korifiv1alpha1.BuildWorkload) (ctrl.Result, error) {
@@ -181,9 +195,32 @@ func (r *BuildWorkloadReconciler) ReconcileResource(ctx context.Context, buildWo
 	}
 
 	if hasCompleted(buildWorkload) {
+		if r.controllerConfig.RebaseOnStackUpdate && meta.IsStatusConditionTrue(buildWorkload.Status.Conditions, korifiv1alpha1.SucceededConditionType) {
+			return ctrl.Result{}, r.reconcileRebuild(ctx, log, buildWorkload)
+		}
 		return ctrl.Result{}, nil
 	}
 
+	stagingTimeLeft := r.stagingTimeout - time.Since(buildWorkload.CreationTimestamp.Time)
+	if r.stagingTimeout > 0 && stagingTimeLeft <= 0 {
+		return ctrl.Result{}, r.failOnStagingTimeout(ctx, log, buildWorkload)
+	}
+
+	result, err := r.reconcileBuild(ctx, log, buildWorkload)
+	if err != nil || hasCompleted(buildWorkload) {
+		return result, err
+	}
+
+	// builds stuck e.g. on unschedulable pods do not trigger any further
+	// reconciliation, so make sure the timeout is enforced
+	if r.stagingTimeout > 0 && (result.RequeueAfter == 0 || result.RequeueAfter > stagingTimeLeft) {
+		result.RequeueAfter = stagingTimeLeft
+	}
+
+	return result, nil
+}
+
+func (r *BuildWorkloadReconciler) reconcileBuild(ctx context.Context, log logr.Logger, buildWorkload *korifiv1alpha1.BuildWorkload) (ctrl.Result, error) {
 	if neverReconciledSuccessfully(buildWorkload) {
 		return r.beginImageBuild(ctx, log, buildWorkload)
 	}
@@ -249,6 +286,15 @@ func (r *BuildWorkloadReconciler) ReconcileResource(ctx context.Context, buildWo
 
 	latestBuildSuccessful := latestBuild.Status.GetCondition(corev1alpha1.ConditionSucceeded)
 	if latestBuildSuccessful.IsFalse() {
+		retrying, err := r.retryTransientFailure(ctx, log, kpackBuilds, latestBuild)
+		if err != nil {
+			log.Info("error when retrying failed build", "reason", err)
+			return ctrl.Result{}, err
+		}
+		if retrying {
+			return ctrl.Result{}, nil
+		}
+
 		meta.SetStatusCondition(&buildWorkload.Status.Conditions, metav1.Condition{
 			Type:               korifiv1alpha1.SucceededConditionType,
 			Status:             metav1.ConditionFalse,
@@ -265,24 +311,116 @@ func (r *BuildWorkloadReconciler) ReconcileResource(ctx context.Context, buildWo
 			ObservedGeneration: buildWorkload.Generation,
 		})
 
-		foundServiceAccount := corev1.ServiceAccount{}
-		err = r.k8sClient.Get(ctx, types.NamespacedName{
-			Namespace: buildWorkload.Namespace,
-			Name:      r.controllerConfig.BuilderServiceAccount,
-		}, &foundServiceAccount)
+		buildWorkload.Status.Droplet, err = r.dropletStatus(ctx, log, buildWorkload, latestBuild)
 		if err != nil {
-			log.Info("error when fetching kpack ServiceAccount", "reason", err)
 			return ctrl.Result{}, err
 		}
+	}
+
+	return ctrl.Result{}, nil
+}
 
-		buildWorkload.Status.Droplet, err = r.generateDropletStatus(ctx, latestBuild, foundServiceAccount.ImagePullSecrets)
+// reconcileRebuild updates the droplet of a succeeded BuildWorkload once kpack
+// has rebuilt (or rebased) its image because the stack or the buildpacks of
+// the builder have been updated, e.g. to roll out base image CVE fixes
+func (r *BuildWorkloadReconciler) reconcileRebuild(ctx context.Context, log logr.Logger, buildWorkload *korifiv1alpha1.BuildWorkload) error {
+	kpackBuilds, err := r.listKpackBuilds(ctx, buildWorkload)
+	if err != nil {
+		log.Info("error when listing kpack builds for build workload", "reason", err)
+		return err
+	}
+
+	latestBuild, err := latestBuild(kpackBuilds)
+	if err != nil {
+		log.Info("error when getting latest kpack build", "reason", err)
+		return err
+	}
+
+	if latestBuild == nil || !isRebuild(latestBuild) || !latestBuild.Status.GetCondition(corev1alpha1.ConditionSucceeded).IsTrue() {
+		return nil
+	}
+
+	if buildWorkload.Status.Droplet != nil && buildWorkload.Status.Droplet.Registry.Image == latestBuild.Status.LatestImage {
+		return nil
+	}
+
+	dropletStatus, err := r.dropletStatus(ctx, log, buildWorkload, latestBuild)
+	if err != nil {
+		return err
+	}
+
+	log.Info("updating droplet with rebuilt image", "build", latestBuild.Name, "reason", latestBuild.BuildReason())
+	buildWorkload.Status.Droplet = dropletStatus
+
+	return nil
+}
+
+// isRebuild tells whether kpack built the image again only because the stack
+// or the buildpacks of the builder have been updated, i.e. from the same
+// source and configuration
+func isRebuild(kpackBuild *buildv1alpha2.Build) bool {
+	reasons := kpackBuild.BuildReason()
+	if reasons == "" {
+		return false
+	}
+
+	for _, reason := range strings.Split(reasons, ",") {
+		if reason != buildv1alpha2.BuildReasonStack && reason != buildv1alpha2.BuildReasonBuildpack {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r *BuildWorkloadReconciler) dropletStatus(ctx context.Context, log logr.Logger, buildWorkload *korifiv1alpha1.BuildWorkload, kpackBuild *buildv1alpha2.Build) (*korifiv1alpha1.BuildDropletStatus, error) {
+	foundServiceAccount := corev1.ServiceAccount{}
+	err := r.k8sClient.Get(ctx, types.NamespacedName{
+		Namespace: buildWorkload.Namespace,
+		Name:      r.controllerConfig.BuilderServiceAccount,
+	}, &foundServiceAccount)
+	if err != nil {
+		log.Info("error when fetching kpack ServiceAccount", "reason", err)
+		return nil, err
+	}
+
+	dropletStatus, err := r.generateDropletStatus(ctx, kpackBuild, foundServiceAccount.ImagePullSecrets)
+	if err != nil {
+		log.Info("error when compiling the DropletStatus", "reason", err)
+		return nil, err
+	}
+
+	return dropletStatus, nil
+}
+
+// failOnStagingTimeout fails the BuildWorkload and deletes its kpack builds
+// (and hence their pods), unless they are shared with newer BuildWorkloads
+func (r *BuildWorkloadReconciler) failOnStagingTimeout(ctx context.Context, log logr.Logger, buildWorkload *korifiv1alpha1.BuildWorkload) error {
+	log.Info("failing build as staging timed out", "timeout", r.stagingTimeout)
+
+	lastBuildWorkload, err := r.isLastBuildWorkload(ctx, buildWorkload)
+	if err != nil {
+		log.Info("failed to check for last build workloads", "reason", err)
+		return err
+	}
+
+	if lastBuildWorkload {
+		err = r.deleteBuildsForBuildWorkload(ctx, buildWorkload)
 		if err != nil {
-			log.Info("error when compiling the DropletStatus", "reason", err)
-			return ctrl.Result{}, err
+			log.Info("failed to delete builds for build workload", "reason", err)
+			return err
 		}
 	}
 
-	return ctrl.Result{}, nil
+	meta.SetStatusCondition(&buildWorkload.Status.Conditions, metav1.Condition{
+		Type:               korifiv1alpha1.SucceededConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             "StagingTimeout",
+		Message:            fmt.Sprintf("Staging did not complete within %s", r.stagingTimeout),
+		ObservedGeneration: buildWorkload.Generation,
+	})
+
+	return nil
 }
 
 func (r *BuildWorkloadReconciler) recoverIfBuildCreationHasBeenSkipped(ctx context.Context, log logr.Logger, buildWorkload *korifiv1alpha1.BuildWorkload, kpackImage *buildv1alpha2.Image) error {
@@ -346,7 +484,7 @@ func (r *BuildWorkloadReconciler) getBuilderReadyCondition(ctx context.Context,
 		}
 		condition = imageBuilder.Status.GetCondition(corev1alpha1.ConditionReady)
 
-	case "Builder":
+	case builderKind:
 		var imageBuilder buildv1alpha2.Builder
 		err := r.k8sClient.Get(ctx, client.ObjectKey{Name: kpackImage.Spec.Builder.Name, Namespace: buildWorkload.Namespace}, &imageBuilder)
 		if err != nil {
@@ -374,10 +512,16 @@ func (r *BuildWorkloadReconciler) getBuilderReadyCondition(ctx context.Context,
 	return condition, nil
 }
 
-func (r *BuildWorkloadReconciler) getDefaultClusterBuilder(ctx context.Context) (*buildv1alpha2.ClusterBuilder, error) {
-	var defaultBuilder buildv1alpha2.ClusterBuilder
-	err := r.k8sClient.Get(ctx, client.ObjectKey{Name: r.controllerConfig.ClusterBuilderName}, &defaultBuilder)
-	return &defaultBuilder, err
+func (r *BuildWorkloadReconciler) getBuilder(ctx context.Context, builderRef corev1.ObjectReference) (buildv1alpha2.BuilderSpec, buildv1alpha2.BuilderStatus, error) {
+	if builderRef.Kind == builderKind {
+		var builder buildv1alpha2.Builder
+		err := r.k8sClient.Get(ctx, client.ObjectKey{Namespace: builderRef.Namespace, Name: builderRef.Name}, &builder)
+		return builder.Spec.BuilderSpec, builder.Status, err
+	}
+
+	var clusterBuilder buildv1alpha2.ClusterBuilder
+	err := r.k8sClient.Get(ctx, client.ObjectKey{Name: builderRef.Name}, &clusterBuilder)
+	return clusterBuilder.Spec.BuilderSpec, clusterBuilder.Status, err
 }
 
 type doNotRetryError struct {
@@ -405,29 +549,30 @@ func ignoreDoNotRetryError(err error) error {
 	return err
 }
 
-func (r *BuildWorkloadReconciler) ensureKpackBuilderForBuildpacks(ctx context.Context, log logr.Logger, buildWorkload *korifiv1alpha1.BuildWorkload) (string, error) {
-	var (
-		defaultBuilder *buildv1alpha2.ClusterBuilder
-		err            error
-	)
-
-	if defaultBuilder, err = r.getDefaultClusterBuilder(ctx); err != nil {
+func (r *BuildWorkloadReconciler) ensureKpackBuilderForBuildpacks(
+	ctx context.Context,
+	log logr.Logger,
+	buildWorkload *korifiv1alpha1.BuildWorkload,
+	baseBuilderRef corev1.ObjectReference,
+) (corev1.ObjectReference, error) {
+	baseBuilderSpec, baseBuilderStatus, err := r.getBuilder(ctx, baseBuilderRef)
+	if err != nil {
 		if k8serrors.IsNotFound(err) {
 			meta.SetStatusCondition(&buildWorkload.Status.Conditions, metav1.Condition{
 				Type:               korifiv1alpha1.SucceededConditionType,
 				Status:             metav1.ConditionFalse,
 				Reason:             "BuilderNotReady",
-				Message:            "Default ClusterBuilder not found",
+				Message:            fmt.Sprintf("%s %q not found", baseBuilderRef.Kind, baseBuilderRef.Name),
 				ObservedGeneration: buildWorkload.Generation,
 			})
-			return "", newDoNotRetryError(fmt.Errorf("default ClusterBuilder not found: %w", err))
+			return corev1.ObjectReference{}, newDoNotRetryError(fmt.Errorf("%s %q not found: %w", baseBuilderRef.Kind, baseBuilderRef.Name, err))
 		}
 
-		log.Info("error when fetching default ClusterBuilder", "reason", err)
-		return "", err
+		log.Info("error when fetching base builder", "kind", baseBuilderRef.Kind, "name", baseBuilderRef.Name, "reason", err)
+		return corev1.ObjectReference{}, err
 	}
 
-	if err = r.checkBuildpacks(ctx, buildWorkload, defaultBuilder); err != nil {
+	if err = checkBuildpacks(buildWorkload, baseBuilderRef, baseBuilderStatus); err != nil {
 		meta.SetStatusCondition(&buildWorkload.Status.Conditions, metav1.Condition{
 			Type:               korifiv1alpha1.SucceededConditionType,
 			Status:             metav1.ConditionFalse,
@@ -436,15 +581,15 @@ func (r *BuildWorkloadReconciler) ensureKpackBuilderForBuildpacks(ctx context.Co
 			ObservedGeneration: buildWorkload.Generation,
 		})
 
-		return "", newDoNotRetryError(err)
+		return corev1.ObjectReference{}, newDoNotRetryError(err)
 	}
 
-	builderName := ComputeBuilderName(buildWorkload.Spec.Buildpacks)
+	builderName := r.customBuilderName(baseBuilderRef, buildWorkload.Spec.Buildpacks)
 	builderRepo := fmt.Sprintf("%sbuilders-%s", r.imageRepoPrefix, builderName)
 	err = r.imageRepoCreator.CreateRepository(ctx, builderRepo)
 	if err != nil {
 		log.Info("failed creating builder repo", "reason", err)
-		return "", fmt.Errorf("failed to create builder repo: %w", err)
+		return corev1.ObjectReference{}, fmt.Errorf("failed to create builder repo: %w", err)
 	}
 
 	builder := &buildv1alpha2.Builder{
@@ -460,45 +605,67 @@ func (r *BuildWorkloadReconciler) ensureKpackBuilderForBuildpacks(ctx context.Co
 		}
 
 		builder.Spec.Tag = builderRepo
-		builder.Spec.Stack = defaultBuilder.Spec.Stack
-		builder.Spec.Store = defaultBuilder.Spec.Store
+		builder.Spec.Stack = baseBuilderSpec.Stack
+		builder.Spec.Store = baseBuilderSpec.Store
 		builder.Spec.ServiceAccountName = r.controllerConfig.BuilderServiceAccount
-		builder.Spec.Order = nil
-		for _, bp := range buildWorkload.Spec.Buildpacks {
-			builder.Spec.Order = append(builder.Spec.Order, buildv1alpha2.BuilderOrderEntry{
-				Group: []buildv1alpha2.BuilderBuildpackRef{{
-					BuildpackRef: corev1alpha1.BuildpackRef{
-						BuildpackInfo: corev1alpha1.BuildpackInfo{
-							Id: bp,
-						},
-					},
-				}},
-			})
-		}
+		builder.Spec.Order = []buildv1alpha2.BuilderOrderEntry{buildpacksGroup(buildWorkload.Spec.Buildpacks)}
 
 		return nil
 	})
 	if err != nil {
 		log.Info("failed creating or updating kpack Builder", "reason", err)
-		return "", fmt.Errorf("failed creating or updating kpack Builder: %w", err)
+		return corev1.ObjectReference{}, fmt.Errorf("failed creating or updating kpack Builder: %w", err)
 	}
 
-	return builder.Name, nil
+	return corev1.ObjectReference{
+		Kind:       builderKind,
+		Name:       builder.Name,
+		Namespace:  builder.Namespace,
+		APIVersion: clusterBuilderAPIVersion,
+	}, nil
+}
+
+// buildpacksGroup runs all the given buildpacks in their order rather than
+// detecting one of them, in line with the CF multi-buildpack semantics
+func buildpacksGroup(bps []string) buildv1alpha2.BuilderOrderEntry {
+	group := buildv1alpha2.BuilderOrderEntry{}
+	for _, bp := range bps {
+		group.Group = append(group.Group, buildv1alpha2.BuilderBuildpackRef{
+			BuildpackRef: corev1alpha1.BuildpackRef{
+				BuildpackInfo: corev1alpha1.BuildpackInfo{
+					Id: bp,
+				},
+			},
+		})
+	}
+
+	return group
 }
 
 func ComputeBuilderName(bps []string) string {
 	return uuid.NewSHA1(uuid.Nil, []byte(strings.Join(bps, "\x00"))).String()
 }
 
-func (r *BuildWorkloadReconciler) checkBuildpacks(ctx context.Context, buildWorkload *korifiv1alpha1.BuildWorkload, defaultBuilder *buildv1alpha2.ClusterBuilder) error {
+// customBuilderName keeps the names of the builders based on the default
+// ClusterBuilder stable, while builders based on selected builders get
+// distinct names (and hence distinct builder repositories)
+func (r *BuildWorkloadReconciler) customBuilderName(baseBuilderRef corev1.ObjectReference, bps []string) string {
+	if baseBuilderRef == r.defaultBuilderRef() {
+		return ComputeBuilderName(bps)
+	}
+
+	return ComputeBuilderName(append([]string{baseBuilderRef.Kind, baseBuilderRef.Namespace, baseBuilderRef.Name}, bps...))
+}
+
+func checkBuildpacks(buildWorkload *korifiv1alpha1.BuildWorkload, baseBuilderRef corev1.ObjectReference, baseBuilderStatus buildv1alpha2.BuilderStatus) error {
 	validIDs := map[string]bool{}
-	for _, bp := range clusterBuilderToBuildpacks(defaultBuilder, metav1.Now()) {
-		validIDs[bp.Name] = true
+	for _, orderEntry := range baseBuilderStatus.Order {
+		validIDs[orderEntry.Group[0].Id] = true
 	}
 
 	for _, bp := range buildWorkload.Spec.Buildpacks {
 		if !validIDs[bp] {
-			return fmt.Errorf("buildpack %q not present in default ClusterStore. See `cf buildpacks`", bp)
+			return fmt.Errorf("buildpack %q not present in %s %q. See `cf buildpacks`", bp, baseBuilderRef.Kind, baseBuilderRef.Name)
 		}
 	}
 	return nil
@@ -609,11 +776,14 @@ func latestBuild(builds []buildv1alpha2.Build) (*buildv1alpha2.Build, error) {
 }
 
 func (r *BuildWorkloadReconciler) beginImageBuild(ctx context.Context, log logr.Logger, buildWorkload *korifiv1alpha1.BuildWorkload) (ctrl.Result, error) {
-	var builderName string
-	var err error
+	builderRef, err := r.selectBuilder(ctx, buildWorkload)
+	if err != nil {
+		log.Info("failed selecting builder", "reason", err)
+		return ctrl.Result{}, ignoreDoNotRetryError(fmt.Errorf("failed selecting builder: %w", err))
+	}
 
 	if len(buildWorkload.Spec.Buildpacks) > 0 {
-		builderName, err = r.ensureKpackBuilderForBuildpacks(ctx, log, buildWorkload)
+		builderRef, err = r.ensureKpackBuilderForBuildpacks(ctx, log, buildWorkload, builderRef)
 		if err != nil {
 			log.Info("failed ensuring custom builder", "reason", err)
 			return ctrl.Result{}, ignoreDoNotRetryError(fmt.Errorf("failed ensuring custom builder: %w", err))
@@ -626,7 +796,7 @@ func (r *BuildWorkloadReconciler) beginImageBuild(ctx context.Context, log logr.
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, r.reconcileKpackImage(ctx, log, buildWorkload, builderName)
+	return ctrl.Result{}, r.reconcileKpackImage(ctx, log, buildWorkload, builderRef)
 }
 
 func (r *BuildWorkloadReconciler) ensureRegistryImagePullSecretsExist(ctx context.Context, buildWorkload *korifiv1alpha1.BuildWorkload) error {
@@ -644,11 +814,16 @@ func (r *BuildWorkloadReconciler) reconcileKpackImage(
 	ctx context.Context,
 	log logr.Logger,
 	buildWorkload *korifiv1alpha1.BuildWorkload,
-	customBuilderName string,
+	builderRef corev1.ObjectReference,
 ) error {
 	appGUID := buildWorkload.Labels[korifiv1alpha1.CFAppGUIDLabelKey]
 	kpackImageNamespace := buildWorkload.Namespace
-	kpackImageTag := r.repositoryRef(appGUID)
+	kpackImageTag, err := r.imageRepoNamer.DropletsRepository(ctx, buildWorkload.Namespace, appGUID)
+	if err != nil {
+		log.Info("failed to compute image repository", "reason", err)
+		return err
+	}
+
 	desiredKpackImage := buildv1alpha2.Image{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      appGUID,
@@ -656,24 +831,33 @@ func (r *BuildWorkloadReconciler) reconcileKpackImage(
 		},
 	}
 
-	if err := r.imageRepoCreator.CreateRepository(ctx, r.repositoryRef(appGUID)); err != nil {
+	if err = r.imageRepoCreator.CreateRepository(ctx, kpackImageTag); err != nil {
 		log.Info("failed to create image repository", "reason", err)
 		return err
 	}
 
-	cacheSize, err := resource.ParseQuantity(fmt.Sprintf("%dMi", r.controllerConfig.CFStagingResources.BuildCacheMB))
+	buildCache, err := r.buildCache(buildWorkload, kpackImageTag)
 	if err != nil {
-		log.Info("failed to parse image cache size", "reason", err)
+		log.Info("failed to compute the build cache", "reason", err)
 		return err
 	}
 
 	recreateImage := false
 	_, err = controllerutil.CreateOrPatch(ctx, r.k8sClient, &desiredKpackImage, func() error {
-		if desiredKpackImage.Spec.Cache != nil &&
-			desiredKpackImage.Spec.Cache.Volume != nil &&
-			desiredKpackImage.Spec.Cache.Volume.Size != nil &&
-			!desiredKpackImage.Spec.Cache.Volume.Size.Equal(cacheSize) {
-			// Our new request wants to resize the PV on the existing Image. Fetch the storage class to see if that is allowed.
+		if buildCache.Volume != nil &&
+			desiredKpackImage.Spec.NeedVolumeCache() &&
+			!desiredKpackImage.Spec.Cache.Volume.Size.Equal(*buildCache.Volume.Size) {
+			// Our new request wants to resize the PV on the existing Image.
+			// kpack never shrinks cache volumes, and only grows them when the
+			// storage class allows it, so the Image is recreated otherwise.
+			if buildCache.Volume.Size.Cmp(*desiredKpackImage.Spec.Cache.Volume.Size) < 0 {
+				log.V(1).Info("WARNING: build cache shrinks. Recreating.",
+					"desiredSize", buildCache.Volume.Size.String(),
+					"actualSize", desiredKpackImage.Spec.Cache.Volume.Size.String())
+				recreateImage = true
+				return nil
+			}
+
 			scName := desiredKpackImage.Spec.Cache.Volume.StorageClassName
 			scResizable, err2 := r.isResizable(ctx, log, scName)
 			if err2 != nil {
@@ -683,7 +867,7 @@ func (r *BuildWorkloadReconciler) reconcileKpackImage(
 				// Signal to recreate the Image with an error
 				log.V(1).Info("WARNING: storage class does not support PVC resize. Recreating.",
 					"storageClassName", scName,
-					"desiredSize", cacheSize.String(),
+					"desiredSize", buildCache.Volume.Size.String(),
 					"actualSize", desiredKpackImage.Spec.Cache.Volume.Size.String())
 				recreateImage = true
 				return nil
@@ -695,34 +879,16 @@ func (r *BuildWorkloadReconciler) reconcileKpackImage(
 		}
 
 		desiredKpackImage.Spec = buildv1alpha2.ImageSpec{
-			Tag: kpackImageTag,
-			Builder: corev1.ObjectReference{
-				Kind:       clusterBuilderKind,
-				Name:       r.controllerConfig.ClusterBuilderName,
-				APIVersion: clusterBuilderAPIVersion,
-			},
+			Tag:                kpackImageTag,
+			Builder:            builderRef,
 			ServiceAccountName: r.controllerConfig.BuilderServiceAccount,
-			Source: corev1alpha1.SourceConfig{
-				Registry: &corev1alpha1.Registry{
-					Image:            buildWorkload.Spec.Source.Registry.Image,
-					ImagePullSecrets: buildWorkload.Spec.Source.Registry.ImagePullSecrets,
-				},
-			},
+			Source:             kpackSourceConfig(buildWorkload.Spec.Source),
 			Build: &buildv1alpha2.ImageBuild{
 				Services:  buildWorkload.Spec.Services,
 				Env:       buildWorkload.Spec.Env,
-				Resources: GetBuildResources(r.controllerConfig.CFStagingResources.DiskMB, r.controllerConfig.CFStagingResources.MemoryMB),
+				Resources: GetBuildResources(r.controllerConfig.CFStagingResources, buildWorkload),
 			},
-			Cache: &buildv1alpha2.ImageCacheConfig{
-				Volume: &buildv1alpha2.ImagePersistentVolumeCache{
-					Size: &cacheSize,
-				},
-			},
-		}
-		if customBuilderName != "" {
-			desiredKpackImage.Spec.Builder.Kind = "Builder"
-			desiredKpackImage.Spec.Builder.Name = customBuilderName
-			desiredKpackImage.Spec.Builder.Namespace = buildWorkload.Namespace
+			Cache: buildCache,
 		}
 
 		// Cannot use SetControllerReference here as multiple BuildWorkloads can "own" the same Image.
@@ -763,7 +929,48 @@ func (r *BuildWorkloadReconciler) reconcileKpackImage(
 	return nil
 }
 
-func GetBuildResources(diskMB, memoryMB int64) corev1.ResourceRequirements {
+// buildCache returns the kpack cache of the app builds, so that repeated
+// pushes reuse the layers of previous builds. Build workloads disabling the
+// cache are built without one, which also drops the cache volume of the app.
+// The cache is disabled with an empty cache config, as kpack defaults a nil
+// one to a volume cache when the cluster has a default storage class.
+func (r *BuildWorkloadReconciler) buildCache(buildWorkload *korifiv1alpha1.BuildWorkload, kpackImageTag string) (*buildv1alpha2.ImageCacheConfig, error) {
+	if buildWorkload.Spec.DisableBuildCache {
+		return &buildv1alpha2.ImageCacheConfig{}, nil
+	}
+
+	switch r.controllerConfig.BuildCacheType {
+	case config.NoBuildCache:
+		return &buildv1alpha2.ImageCacheConfig{}, nil
+	case config.RegistryBuildCache:
+		return &buildv1alpha2.ImageCacheConfig{
+			Registry: &buildv1alpha2.RegistryCache{
+				Tag: kpackImageTag + ":" + buildCacheTag,
+			},
+		}, nil
+	}
+
+	cacheSize, err := resource.ParseQuantity(fmt.Sprintf("%dMi", r.controllerConfig.CFStagingResources.BuildCacheMB))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse image cache size: %w", err)
+	}
+
+	return &buildv1alpha2.ImageCacheConfig{
+		Volume: &buildv1alpha2.ImagePersistentVolumeCache{
+			Size: &cacheSize,
+		},
+	}, nil
+}
+
+// GetBuildResources computes the resources of the build pod from the staging
+// resources of the installation. The memory and disk requested by the
+// BuildWorkload take precedence when set, up to the limits of the
+// installation. Limits are raised to the default requests where needed, as
+// pods requesting more than their limits are rejected.
+func GetBuildResources(stagingResources config.CFStagingResources, buildWorkload *korifiv1alpha1.BuildWorkload) corev1.ResourceRequirements {
+	diskMB := requestedStagingMB(stagingResources.DiskMB, int64(buildWorkload.Spec.StagingDiskMB), stagingResources.Limits.DiskMB)
+	memoryMB := requestedStagingMB(stagingResources.MemoryMB, int64(buildWorkload.Spec.StagingMemoryMB), stagingResources.Limits.MemoryMB)
+
 	resourceRequirements := corev1.ResourceRequirements{
 		Requests: map[corev1.ResourceName]resource.Quantity{},
 	}
@@ -776,9 +983,48 @@ func GetBuildResources(diskMB, memoryMB int64) corev1.ResourceRequirements {
 		resourceRequirements.Requests[corev1.ResourceMemory] = *resource.NewScaledQuantity(memoryMB, resource.Mega)
 	}
 
+	if stagingResources.CPUMillicores != 0 {
+		resourceRequirements.Requests[corev1.ResourceCPU] = *resource.NewMilliQuantity(stagingResources.CPUMillicores, resource.DecimalSI)
+	}
+
+	limits := stagingResources.Limits
+	if limits.DiskMB != 0 {
+		setLimit(&resourceRequirements, corev1.ResourceEphemeralStorage, *resource.NewScaledQuantity(max(limits.DiskMB, diskMB), resource.Mega))
+	}
+
+	if limits.MemoryMB != 0 {
+		setLimit(&resourceRequirements, corev1.ResourceMemory, *resource.NewScaledQuantity(max(limits.MemoryMB, memoryMB), resource.Mega))
+	}
+
+	if limits.CPUMillicores != 0 {
+		setLimit(&resourceRequirements, corev1.ResourceCPU, *resource.NewMilliQuantity(max(limits.CPUMillicores, stagingResources.CPUMillicores), resource.DecimalSI))
+	}
+
 	return resourceRequirements
 }
 
+// requestedStagingMB returns the staging resource requested by the user,
+// capped to the limit of the installation, or the default when not requested
+func requestedStagingMB(defaultMB, requestedMB, limitMB int64) int64 {
+	if requestedMB == 0 {
+		return defaultMB
+	}
+
+	if limitMB != 0 {
+		return min(requestedMB, limitMB)
+	}
+
+	return requestedMB
+}
+
+func setLimit(resourceRequirements *corev1.ResourceRequirements, name corev1.ResourceName, limit resource.Quantity) {
+	if resourceRequirements.Limits == nil {
+		resourceRequirements.Limits = corev1.ResourceList{}
+	}
+
+	resourceRequirements.Limits[name] = limit
+}
+
 func (r *BuildWorkloadReconciler) isResizable(ctx context.Context, log logr.Logger, scName string) (bool, error) {
 	scList := storagev1.StorageClassList{}
 	if listErr := r.k8sClient.List(ctx, &scList); listErr != nil {
@@ -836,9 +1082,23 @@ func (r *BuildWorkloadReconciler) generateDropletStatus(ctx context.Context, kpa
 
 		ProcessTypes: processTypes,
 		Ports:        config.ExposedPorts,
+		Buildpacks:   dropletBuildpacks(kpackBuild.Status.BuildMetadata),
 	}, nil
 }
 
+// dropletBuildpacks lists the buildpacks that took part in the build
+func dropletBuildpacks(buildMetadata corev1alpha1.BuildpackMetadataList) []korifiv1alpha1.DropletBuildpack {
+	var buildpacks []korifiv1alpha1.DropletBuildpack
+	for _, bp := range buildMetadata {
+		buildpacks = append(buildpacks, korifiv1alpha1.DropletBuildpack{
+			Name:    bp.Id,
+			Version: bp.Version,
+		})
+	}
+
+	return buildpacks
+}
+
 type buildMetadata struct {
 	Processes []process `json:"processes"`
 }
@@ -929,6 +1189,19 @@ func (r *BuildWorkloadReconciler) hasRemainingBuilds(ctx context.Context, buildW
 	return len(buildList.Items) != 0, nil
 }
 
-func (r *BuildWorkloadReconciler) repositoryRef(appGUID string) string {
-	return r.imageRepoPrefix + appGUID + "-droplets"
+func kpackSourceConfig(source korifiv1alpha1.PackageSource) corev1alpha1.SourceConfig {
+	if source.Blob != nil {
+		return corev1alpha1.SourceConfig{
+			Blob: &corev1alpha1.Blob{
+				URL: source.Blob.URL,
+			},
+		}
+	}
+
+	return corev1alpha1.SourceConfig{
+		Registry: &corev1alpha1.Registry{
+			Image:            source.Registry.Image,
+			ImagePullSecrets: source.Registry.ImagePullSecrets,
+		},
+	}
 }
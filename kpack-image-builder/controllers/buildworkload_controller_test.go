@@ -4,10 +4,13 @@ import (
 	"encoding/base64"
 	"fmt"
 	"strconv"
+	"time"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/config"
 	"code.cloudfoundry.org/korifi/kpack-image-builder/controllers"
 	"code.cloudfoundry.org/korifi/tests/helpers"
+	"code.cloudfoundry.org/korifi/tools"
 	"code.cloudfoundry.org/korifi/tools/image"
 	"code.cloudfoundry.org/korifi/tools/k8s"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -19,6 +22,7 @@ import (
 	buildv1alpha2 "github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -45,6 +49,7 @@ var _ = Describe("BuildWorkloadReconciler", func() {
 		buildpacks                []string
 		imageRepoCreatorCallCount int
 		expectedCacheVolumeSize   string
+		disableBuildCache         bool
 	)
 
 	BeforeEach(func() {
@@ -123,6 +128,7 @@ var _ = Describe("BuildWorkloadReconciler", func() {
 		}
 
 		buildpacks = nil
+		disableBuildCache = false
 
 		fakeImageConfigGetter.ConfigReturns(image.Config{
 			Labels: map[string]string{
@@ -147,17 +153,18 @@ var _ = Describe("BuildWorkloadReconciler", func() {
 
 	Describe("GetBuildResources", func() {
 		var (
-			diskMB, memoryMB     int64
+			stagingResources     config.CFStagingResources
+			workload             *korifiv1alpha1.BuildWorkload
 			resourceRequirements corev1.ResourceRequirements
 		)
 
 		BeforeEach(func() {
-			diskMB = 0
-			memoryMB = 0
+			stagingResources = config.CFStagingResources{}
+			workload = &korifiv1alpha1.BuildWorkload{}
 		})
 
 		JustBeforeEach(func() {
-			resourceRequirements = controllers.GetBuildResources(diskMB, memoryMB)
+			resourceRequirements = controllers.GetBuildResources(stagingResources, workload)
 		})
 
 		It("does not set the resource requests by default", func() {
@@ -167,23 +174,100 @@ var _ = Describe("BuildWorkloadReconciler", func() {
 
 		When("staging diskMB is configured", func() {
 			BeforeEach(func() {
-				diskMB = 1234
+				stagingResources.DiskMB = 1234
 			})
 
 			It("sets the ephemeralStorage resource request", func() {
 				Expect(resourceRequirements.Limits).To(BeEmpty())
-				Expect(resourceRequirements.Requests).To(HaveKeyWithValue(corev1.ResourceEphemeralStorage, *resource.NewScaledQuantity(diskMB, resource.Mega)))
+				Expect(resourceRequirements.Requests).To(HaveKeyWithValue(corev1.ResourceEphemeralStorage, *resource.NewScaledQuantity(1234, resource.Mega)))
 			})
 		})
 
 		When("staging memoryMB is configured", func() {
 			BeforeEach(func() {
-				memoryMB = 4321
+				stagingResources.MemoryMB = 4321
 			})
 
 			It("sets the memory resource request", func() {
 				Expect(resourceRequirements.Limits).To(BeEmpty())
-				Expect(resourceRequirements.Requests).To(HaveKeyWithValue(corev1.ResourceMemory, *resource.NewScaledQuantity(memoryMB, resource.Mega)))
+				Expect(resourceRequirements.Requests).To(HaveKeyWithValue(corev1.ResourceMemory, *resource.NewScaledQuantity(4321, resource.Mega)))
+			})
+
+			When("the build workload requests memory and disk", func() {
+				BeforeEach(func() {
+					workload.Spec.StagingMemoryMB = 8192
+					workload.Spec.StagingDiskMB = 2048
+				})
+
+				It("requests the memory and disk of the build workload", func() {
+					Expect(resourceRequirements.Requests).To(HaveKeyWithValue(corev1.ResourceMemory, *resource.NewScaledQuantity(8192, resource.Mega)))
+					Expect(resourceRequirements.Requests).To(HaveKeyWithValue(corev1.ResourceEphemeralStorage, *resource.NewScaledQuantity(2048, resource.Mega)))
+				})
+			})
+		})
+
+		When("staging cpuMillicores is configured", func() {
+			BeforeEach(func() {
+				stagingResources.CPUMillicores = 500
+			})
+
+			It("sets the cpu resource request", func() {
+				Expect(resourceRequirements.Limits).To(BeEmpty())
+				Expect(resourceRequirements.Requests).To(HaveKeyWithValue(corev1.ResourceCPU, *resource.NewMilliQuantity(500, resource.DecimalSI)))
+			})
+		})
+
+		When("staging limits are configured", func() {
+			BeforeEach(func() {
+				stagingResources.MemoryMB = 1024
+				stagingResources.Limits = config.CFStagingResourceLimits{
+					DiskMB:        4096,
+					MemoryMB:      2048,
+					CPUMillicores: 2000,
+				}
+			})
+
+			It("sets the resource limits", func() {
+				Expect(resourceRequirements.Limits).To(Equal(corev1.ResourceList{
+					corev1.ResourceEphemeralStorage: *resource.NewScaledQuantity(4096, resource.Mega),
+					corev1.ResourceMemory:           *resource.NewScaledQuantity(2048, resource.Mega),
+					corev1.ResourceCPU:              *resource.NewMilliQuantity(2000, resource.DecimalSI),
+				}))
+			})
+
+			When("the build workload requests more memory and disk than the limits", func() {
+				BeforeEach(func() {
+					workload.Spec.StagingMemoryMB = 8192
+					workload.Spec.StagingDiskMB = 8192
+				})
+
+				It("caps the requests to the limits", func() {
+					Expect(resourceRequirements.Requests).To(HaveKeyWithValue(corev1.ResourceMemory, *resource.NewScaledQuantity(2048, resource.Mega)))
+					Expect(resourceRequirements.Requests).To(HaveKeyWithValue(corev1.ResourceEphemeralStorage, *resource.NewScaledQuantity(4096, resource.Mega)))
+					Expect(resourceRequirements.Limits).To(HaveKeyWithValue(corev1.ResourceMemory, *resource.NewScaledQuantity(2048, resource.Mega)))
+					Expect(resourceRequirements.Limits).To(HaveKeyWithValue(corev1.ResourceEphemeralStorage, *resource.NewScaledQuantity(4096, resource.Mega)))
+				})
+			})
+
+			When("the build workload requests less memory than the limit", func() {
+				BeforeEach(func() {
+					workload.Spec.StagingMemoryMB = 1536
+				})
+
+				It("requests the memory of the build workload", func() {
+					Expect(resourceRequirements.Requests).To(HaveKeyWithValue(corev1.ResourceMemory, *resource.NewScaledQuantity(1536, resource.Mega)))
+					Expect(resourceRequirements.Limits).To(HaveKeyWithValue(corev1.ResourceMemory, *resource.NewScaledQuantity(2048, resource.Mega)))
+				})
+			})
+
+			When("the default memory exceeds the limit", func() {
+				BeforeEach(func() {
+					stagingResources.MemoryMB = 4096
+				})
+
+				It("raises the memory limit to the default request", func() {
+					Expect(resourceRequirements.Limits).To(HaveKeyWithValue(corev1.ResourceMemory, *resource.NewScaledQuantity(4096, resource.Mega)))
+				})
 			})
 		})
 	})
@@ -191,9 +275,42 @@ var _ = Describe("BuildWorkloadReconciler", func() {
 	Describe("BuildWorkload initialization phase", func() {
 		JustBeforeEach(func() {
 			buildWorkload = buildWorkloadObject(buildWorkloadGUID, namespaceGUID, source, env, services, reconcilerName, buildpacks)
+			buildWorkload.Spec.DisableBuildCache = disableBuildCache
 			Expect(adminClient.Create(ctx, buildWorkload)).To(Succeed())
 		})
 
+		When("the build cache is disabled", func() {
+			BeforeEach(func() {
+				disableBuildCache = true
+			})
+
+			It("builds the kpack.Image without a cache", func() {
+				Eventually(func(g Gomega) {
+					kpackImage := new(buildv1alpha2.Image)
+					g.Expect(adminClient.Get(ctx, types.NamespacedName{Name: appGUID, Namespace: namespaceGUID}, kpackImage)).To(Succeed())
+					g.Expect(kpackImage.Spec.Build).NotTo(BeNil())
+					g.Expect(kpackImage.Spec.Cache).To(PointTo(BeZero()))
+				}).Should(Succeed())
+			})
+		})
+
+		When("the source is a blob", func() {
+			BeforeEach(func() {
+				source = korifiv1alpha1.PackageSource{
+					Blob: &korifiv1alpha1.Blob{URL: "https://blobstore.example.org/packages/the-package.zip"},
+				}
+			})
+
+			It("builds the kpack.Image from the blob", func() {
+				Eventually(func(g Gomega) {
+					kpackImage := new(buildv1alpha2.Image)
+					g.Expect(adminClient.Get(ctx, types.NamespacedName{Name: appGUID, Namespace: namespaceGUID}, kpackImage)).To(Succeed())
+					g.Expect(kpackImage.Spec.Source.Registry).To(BeNil())
+					g.Expect(kpackImage.Spec.Source.Blob).To(Equal(&corev1alpha1.Blob{URL: "https://blobstore.example.org/packages/the-package.zip"}))
+				}).Should(Succeed())
+			})
+		})
+
 		ItDoesInitialReconciliationWithDefaultBuilder := func() {
 			GinkgoHelper()
 
@@ -315,6 +432,61 @@ var _ = Describe("BuildWorkloadReconciler", func() {
 			ItDoesInitialReconciliationWithDefaultBuilder()
 		})
 
+		When("a kpack.Image exists with a larger cache", func() {
+			var originalImageUID types.UID
+			BeforeEach(func() {
+				storageClassName := PrefixedGUID("resizable-class")
+				Expect(adminClient.Create(ctx, &storagev1.StorageClass{
+					ObjectMeta:           metav1.ObjectMeta{Name: storageClassName},
+					Provisioner:          "kubernetes.io/no-provisioner",
+					AllowVolumeExpansion: tools.PtrTo(true),
+				})).To(Succeed())
+
+				oldSize := resource.MustParse("2048Mi")
+				Expect(adminClient.Create(ctx, &buildv1alpha2.Image{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      appGUID,
+						Namespace: namespaceGUID,
+						Labels: map[string]string{
+							controllers.BuildWorkloadLabelKey: buildWorkloadGUID,
+						},
+					},
+					Spec: buildv1alpha2.ImageSpec{
+						Tag: "my-tag-string",
+						Builder: corev1.ObjectReference{
+							Name: "my-builder",
+						},
+						ServiceAccountName: "my-service-account",
+						Source: corev1alpha1.SourceConfig{
+							Registry: &corev1alpha1.Registry{
+								Image:            "not-an-image",
+								ImagePullSecrets: nil,
+							},
+						},
+						Cache: &buildv1alpha2.ImageCacheConfig{
+							Volume: &buildv1alpha2.ImagePersistentVolumeCache{Size: &oldSize, StorageClassName: storageClassName},
+						},
+					},
+				})).To(Succeed())
+				Eventually(func(g Gomega) {
+					kpackImage := new(buildv1alpha2.Image)
+					g.Expect(adminClient.Get(ctx, types.NamespacedName{Name: appGUID, Namespace: namespaceGUID}, kpackImage)).To(Succeed())
+					originalImageUID = kpackImage.UID
+					g.Expect(originalImageUID).NotTo(BeEmpty())
+				}).Should(Succeed())
+			})
+
+			It("deletes the kpack image and recreates it, as kpack cannot shrink the cache", func() {
+				Eventually(func(g Gomega) {
+					kpackImage := new(buildv1alpha2.Image)
+					g.Expect(adminClient.Get(ctx, types.NamespacedName{Name: appGUID, Namespace: namespaceGUID}, kpackImage)).To(Succeed())
+					g.Expect(kpackImage.UID).NotTo(Equal(originalImageUID))
+				}).Should(Succeed())
+			})
+
+			ItDoesInitialReconciliationWithDefaultBuilder()
+		})
+
 		When("the source image pull secret doesn't exist", func() {
 			var nonExistentSecret string
 
@@ -387,6 +559,28 @@ var _ = Describe("BuildWorkloadReconciler", func() {
 				}).Should(Succeed())
 			})
 
+			When("multiple buildpacks are specified", func() {
+				BeforeEach(func() {
+					buildpacks = []string{"repo/another-buildpack", "repo/my-buildpack"}
+				})
+
+				It("runs all the buildpacks in the given order", func() {
+					builder := &buildv1alpha2.Builder{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      controllers.ComputeBuilderName(buildWorkload.Spec.Buildpacks),
+							Namespace: namespaceGUID,
+						},
+					}
+					Eventually(func(g Gomega) {
+						g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(builder), builder)).To(Succeed())
+						g.Expect(builder.Spec.Order).To(HaveLen(1))
+						g.Expect(builder.Spec.Order[0].Group).To(HaveLen(2))
+						g.Expect(builder.Spec.Order[0].Group[0].Id).To(Equal("repo/another-buildpack"))
+						g.Expect(builder.Spec.Order[0].Group[1].Id).To(Equal("repo/my-buildpack"))
+					}).Should(Succeed())
+				})
+			})
+
 			When("there is another buildworkload referencing the same buildpacks", func() {
 				var (
 					anotherBuildworkloadGUID string
@@ -441,6 +635,92 @@ var _ = Describe("BuildWorkloadReconciler", func() {
 			})
 		})
 
+		When("the org and space select kpack builders", func() {
+			var (
+				orgGUID string
+				cfOrg   *korifiv1alpha1.CFOrg
+				cfSpace *korifiv1alpha1.CFSpace
+			)
+
+			BeforeEach(func() {
+				orgGUID = PrefixedGUID("org")
+				cfOrg = &korifiv1alpha1.CFOrg{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      orgGUID,
+						Namespace: rootNamespace.Name,
+						Annotations: map[string]string{
+							korifiv1alpha1.KpackBuilderAnnotationKey: "org-cluster-builder",
+						},
+					},
+					Spec: korifiv1alpha1.CFOrgSpec{DisplayName: orgGUID},
+				}
+				Expect(adminClient.Create(ctx, cfOrg)).To(Succeed())
+				Expect(adminClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: orgGUID}})).To(Succeed())
+
+				cfSpace = &korifiv1alpha1.CFSpace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      namespaceGUID,
+						Namespace: orgGUID,
+						Annotations: map[string]string{
+							korifiv1alpha1.KpackBuilderAnnotationKey: "Builder/space-builder",
+						},
+					},
+					Spec: korifiv1alpha1.CFSpaceSpec{DisplayName: namespaceGUID},
+				}
+				Expect(adminClient.Create(ctx, cfSpace)).To(Succeed())
+
+				spaceNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespaceGUID}}
+				Expect(k8s.PatchResource(ctx, adminClient, spaceNamespace, func() {
+					spaceNamespace.Labels = map[string]string{korifiv1alpha1.OrgGUIDKey: orgGUID}
+				})).To(Succeed())
+			})
+
+			It("builds with the builder selected by the space", func() {
+				Eventually(func(g Gomega) {
+					kpackImage := new(buildv1alpha2.Image)
+					g.Expect(adminClient.Get(ctx, types.NamespacedName{Name: appGUID, Namespace: namespaceGUID}, kpackImage)).To(Succeed())
+					g.Expect(kpackImage.Spec.Builder.Kind).To(Equal("Builder"))
+					g.Expect(kpackImage.Spec.Builder.Name).To(Equal("space-builder"))
+					g.Expect(kpackImage.Spec.Builder.Namespace).To(Equal(namespaceGUID))
+				}).Should(Succeed())
+			})
+
+			When("the space does not select a builder", func() {
+				BeforeEach(func() {
+					Expect(k8s.PatchResource(ctx, adminClient, cfSpace, func() {
+						cfSpace.Annotations = nil
+					})).To(Succeed())
+				})
+
+				It("builds with the ClusterBuilder selected by the org", func() {
+					Eventually(func(g Gomega) {
+						kpackImage := new(buildv1alpha2.Image)
+						g.Expect(adminClient.Get(ctx, types.NamespacedName{Name: appGUID, Namespace: namespaceGUID}, kpackImage)).To(Succeed())
+						g.Expect(kpackImage.Spec.Builder.Kind).To(Equal("ClusterBuilder"))
+						g.Expect(kpackImage.Spec.Builder.Name).To(Equal("org-cluster-builder"))
+					}).Should(Succeed())
+				})
+			})
+
+			When("the selected builder is invalid", func() {
+				BeforeEach(func() {
+					Expect(k8s.PatchResource(ctx, adminClient, cfSpace, func() {
+						cfSpace.Annotations[korifiv1alpha1.KpackBuilderAnnotationKey] = "Stack/my-stack"
+					})).To(Succeed())
+				})
+
+				It("fails the build", func() {
+					Eventually(func(g Gomega) {
+						updatedWorkload := new(korifiv1alpha1.BuildWorkload)
+						g.Expect(adminClient.Get(ctx, types.NamespacedName{Name: buildWorkloadGUID, Namespace: namespaceGUID}, updatedWorkload)).To(Succeed())
+						succeeded := mustHaveCondition(g, updatedWorkload.Status.Conditions, "Succeeded")
+						g.Expect(succeeded.Status).To(Equal(metav1.ConditionFalse))
+						g.Expect(succeeded.Reason).To(Equal("InvalidBuilder"))
+					}).Should(Succeed())
+				})
+			})
+		})
+
 		When("reconciler name on BuildWorkload is not kpack-image-builder", func() {
 			BeforeEach(func() {
 				reconcilerName = "notkpackreconciler"
@@ -505,12 +785,13 @@ var _ = Describe("BuildWorkloadReconciler", func() {
 
 	Describe("once the kpack.Image has been created", func() {
 		var (
-			createdKpackImage    *buildv1alpha2.Image
-			build, build1        *buildv1alpha2.Build
-			buildSucceededStatus metav1.ConditionStatus
-			buildSucceededReason string
-			kpackBuildImageRef   string
-			kpackBuildStack      string
+			createdKpackImage     *buildv1alpha2.Image
+			build, build1         *buildv1alpha2.Build
+			buildSucceededStatus  metav1.ConditionStatus
+			buildSucceededReason  string
+			buildSucceededMessage string
+			kpackBuildImageRef    string
+			kpackBuildStack       string
 		)
 
 		BeforeEach(func() {
@@ -539,18 +820,23 @@ var _ = Describe("BuildWorkloadReconciler", func() {
 
 			buildSucceededStatus = ""
 			buildSucceededReason = ""
+			buildSucceededMessage = ""
 		})
 
 		JustBeforeEach(func() {
 			Expect(k8s.Patch(ctx, adminClient, build1, func() {
 				build1.Status.Conditions = append(build1.Status.Conditions, corev1alpha1.Condition{
-					Type:   corev1alpha1.ConditionType("Succeeded"),
-					Status: corev1.ConditionStatus(buildSucceededStatus),
-					Reason: buildSucceededReason,
+					Type:    corev1alpha1.ConditionType("Succeeded"),
+					Status:  corev1.ConditionStatus(buildSucceededStatus),
+					Reason:  buildSucceededReason,
+					Message: buildSucceededMessage,
 				})
 
 				build1.Status.Stack.ID = kpackBuildStack
 				build1.Status.LatestImage = kpackBuildImageRef
+				build1.Status.BuildMetadata = corev1alpha1.BuildpackMetadataList{
+					{Id: "repo/my-buildpack", Version: "1.2.3"},
+				}
 			})).To(Succeed())
 		})
 
@@ -619,6 +905,74 @@ var _ = Describe("BuildWorkloadReconciler", func() {
 					g.Expect(mustHaveCondition(g, updatedWorkload.Status.Conditions, "Succeeded").Reason).To(Equal("BuildFailed"))
 				}).Should(Succeed())
 			})
+
+			It("does not retry the build", func() {
+				Consistently(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(build1), build1)).To(Succeed())
+					g.Expect(build1.Annotations).NotTo(HaveKey(buildv1alpha2.BuildNeededAnnotation))
+				}, "1s").Should(Succeed())
+			})
+
+			When("the build pod got evicted", func() {
+				BeforeEach(func() {
+					buildSucceededReason = "PodFailed"
+					buildSucceededMessage = "The node was low on resource: memory."
+				})
+
+				It("requests another kpack build", func() {
+					Eventually(func(g Gomega) {
+						g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(build1), build1)).To(Succeed())
+						g.Expect(build1.Annotations).To(HaveKeyWithValue(buildv1alpha2.BuildNeededAnnotation, "true"))
+					}).Should(Succeed())
+				})
+
+				It("does not fail the BuildWorkload", func() {
+					Consistently(func(g Gomega) {
+						updatedWorkload := new(korifiv1alpha1.BuildWorkload)
+						g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(buildWorkload), updatedWorkload)).To(Succeed())
+						g.Expect(meta.FindStatusCondition(updatedWorkload.Status.Conditions, "Succeeded")).To(BeNil())
+					}, "1s").Should(Succeed())
+				})
+
+				When("the retries have been used up", func() {
+					BeforeEach(func() {
+						build0 := build.DeepCopy()
+						build0.Name = "build-0"
+						build0.Labels[buildv1alpha2.BuildNumberLabel] = "0"
+						Expect(adminClient.Create(ctx, build0)).To(Succeed())
+					})
+
+					It("fails the BuildWorkload", func() {
+						Eventually(func(g Gomega) {
+							updatedWorkload := new(korifiv1alpha1.BuildWorkload)
+							g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(buildWorkload), updatedWorkload)).To(Succeed())
+							g.Expect(mustHaveCondition(g, updatedWorkload.Status.Conditions, "Succeeded").Status).To(Equal(metav1.ConditionFalse))
+						}).Should(Succeed())
+					})
+				})
+			})
+		})
+
+		When("the kpack.Build does not complete within the staging timeout", func() {
+			BeforeEach(func() {
+				buildSucceededStatus = metav1.ConditionUnknown
+			})
+
+			It("fails the BuildWorkload and deletes the kpack.Build", func() {
+				lookupKey := types.NamespacedName{Name: buildWorkloadGUID, Namespace: namespaceGUID}
+				updatedWorkload := new(korifiv1alpha1.BuildWorkload)
+				Eventually(func(g Gomega) {
+					g.Expect(adminClient.Get(ctx, lookupKey, updatedWorkload)).To(Succeed())
+					succeededCondition := mustHaveCondition(g, updatedWorkload.Status.Conditions, "Succeeded")
+					g.Expect(succeededCondition.Status).To(Equal(metav1.ConditionFalse))
+					g.Expect(succeededCondition.Reason).To(Equal("StagingTimeout"))
+				}).WithTimeout(30 * time.Second).Should(Succeed())
+
+				Eventually(func(g Gomega) {
+					err := adminClient.Get(ctx, client.ObjectKeyFromObject(build1), new(buildv1alpha2.Build))
+					g.Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+				}).Should(Succeed())
+			})
 		})
 
 		When("the kpack.Build succeeded", func() {
@@ -665,6 +1019,75 @@ var _ = Describe("BuildWorkloadReconciler", func() {
 					{Type: "db", Command: "my-command2"},
 				}))
 				Expect(updatedBuildWorkload.Status.Droplet.Ports).To(Equal([]int32{8080, 8443}))
+				Expect(updatedBuildWorkload.Status.Droplet.Buildpacks).To(Equal([]korifiv1alpha1.DropletBuildpack{
+					{Name: "repo/my-buildpack", Version: "1.2.3"},
+				}))
+			})
+
+			When("kpack rebuilds the image after the build workload succeeded", func() {
+				var (
+					rebuild       *buildv1alpha2.Build
+					rebuildReason string
+				)
+
+				BeforeEach(func() {
+					rebuildReason = buildv1alpha2.BuildReasonStack
+				})
+
+				JustBeforeEach(func() {
+					Eventually(func(g Gomega) {
+						g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(buildWorkload), buildWorkload)).To(Succeed())
+						g.Expect(buildWorkload.Status.Droplet).NotTo(BeNil())
+						g.Expect(buildWorkload.Status.Droplet.Registry.Image).To(Equal(kpackBuildImageRef))
+					}).Should(Succeed())
+
+					rebuild = &buildv1alpha2.Build{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "rebuild",
+							Namespace: namespaceGUID,
+							Labels: map[string]string{
+								buildv1alpha2.ImageLabel:           appGUID,
+								buildv1alpha2.ImageGenerationLabel: "1",
+								buildv1alpha2.BuildNumberLabel:     "2",
+							},
+							Annotations: map[string]string{
+								buildv1alpha2.BuildReasonAnnotation: rebuildReason,
+							},
+						},
+					}
+					Expect(adminClient.Create(ctx, rebuild)).To(Succeed())
+
+					Expect(k8s.Patch(ctx, adminClient, rebuild, func() {
+						rebuild.Status.Conditions = append(rebuild.Status.Conditions, corev1alpha1.Condition{
+							Type:   corev1alpha1.ConditionType("Succeeded"),
+							Status: corev1.ConditionStatus(corev1.ConditionTrue),
+							Reason: "OK",
+						})
+						rebuild.Status.Stack.ID = kpackBuildStack
+						rebuild.Status.LatestImage = "foo.bar/baz@sha256:rebased"
+					})).To(Succeed())
+				})
+
+				It("updates the droplet with the rebuilt image", func() {
+					Eventually(func(g Gomega) {
+						g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(buildWorkload), buildWorkload)).To(Succeed())
+						g.Expect(mustHaveCondition(g, buildWorkload.Status.Conditions, "Succeeded").Status).To(Equal(metav1.ConditionTrue))
+						g.Expect(buildWorkload.Status.Droplet.Registry.Image).To(Equal("foo.bar/baz@sha256:rebased"))
+					}).Should(Succeed())
+				})
+
+				When("the image is rebuilt for other reasons", func() {
+					BeforeEach(func() {
+						rebuildReason = buildv1alpha2.BuildReasonTrigger
+					})
+
+					It("keeps the droplet", func() {
+						Consistently(func(g Gomega) {
+							g.Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(buildWorkload), buildWorkload)).To(Succeed())
+							g.Expect(buildWorkload.Status.Droplet.Registry.Image).To(Equal(kpackBuildImageRef))
+						}).Should(Succeed())
+					})
+				})
 			})
 
 			When("there are two kpack.Builds for the kpack.Image", func() {
@@ -30,6 +30,7 @@ import (
 	"code.cloudfoundry.org/korifi/tests/helpers"
 	"code.cloudfoundry.org/korifi/tools"
 	"code.cloudfoundry.org/korifi/tools/k8s"
+	"code.cloudfoundry.org/korifi/tools/registry"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -110,6 +111,8 @@ var _ = BeforeSuite(func() {
 		ClusterBuilderName:        "cf-kpack-builder",
 		ContainerRepositoryPrefix: "image/registry/tag",
 		BuilderServiceAccount:     "builder-service-account",
+		RebaseOnStackUpdate:       true,
+		TransientBuildRetries:     1,
 		CFStagingResources: config.CFStagingResources{
 			BuildCacheMB: 1024,
 			DiskMB:       2048,
@@ -119,6 +122,8 @@ var _ = BeforeSuite(func() {
 
 	imageRepoCreator = new(fake.RepositoryCreator)
 	fakeImageConfigGetter = new(fake.ImageConfigGetter)
+	imageRepoNamer, err := registry.NewRepositoryNamer(k8sManager.GetClient(), "my.repository/my-prefix/", registry.DefaultRepositoryTemplate)
+	Expect(err).NotTo(HaveOccurred())
 	buildWorkloadReconciler = controllers.NewBuildWorkloadReconciler(
 		k8sManager.GetClient(),
 		k8sManager.GetScheme(),
@@ -126,8 +131,10 @@ var _ = BeforeSuite(func() {
 		controllerConfig,
 		fakeImageConfigGetter,
 		"my.repository/my-prefix/",
+		imageRepoNamer,
 		imageRepoCreator,
 		4*time.Second,
+		20*time.Second,
 	)
 	err = buildWorkloadReconciler.SetupWithManager(k8sManager)
 	Expect(err).NotTo(HaveOccurred())
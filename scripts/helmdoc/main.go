@@ -33,16 +33,20 @@ func printDocForSchema(schema map[string]any, indentLevel int) {
 			desc = " " + descAny.(string)
 		}
 
+		// objects without properties, e.g. maps described by additionalProperties,
+		// are documented as a single value
+		properties, hasProperties := value["properties"].(map[string]any)
+
 		typeStr := value["type"].(string)
-		if typeStr == "object" {
+		if typeStr == "object" && hasProperties {
 			typeStr = ""
 		} else {
 			typeStr = " (_" + cases.Title(language.AmericanEnglish).String(typeStr) + "_)"
 		}
 
 		fmt.Printf("%s- `%s`%s:%s\n", indentStr, name, typeStr, desc)
-		if value["type"].(string) == "object" {
-			printDocForSchema(value["properties"].(map[string]any), indentLevel+1)
+		if hasProperties {
+			printDocForSchema(properties, indentLevel+1)
 		}
 	}
 }
@@ -18,12 +18,17 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"time"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/tools/k8s"
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -43,6 +48,9 @@ const (
 	EnvCFInstanceGUID       = "CF_INSTANCE_GUID"
 	EnvCFInstanceInternalIP = "CF_INSTANCE_INTERNAL_IP"
 	EnvCFInstanceIndex      = "CF_INSTANCE_INDEX"
+	EnvCFInstanceAddr       = "CF_INSTANCE_ADDR"
+	EnvInstanceGUID         = "INSTANCE_GUID"
+	EnvInstanceIndex        = "INSTANCE_INDEX"
 
 	// StatefulSet Keys
 	AnnotationVersion     = "korifi.cloudfoundry.org/version"
@@ -56,19 +64,31 @@ const (
 	LabelProcessType     = "korifi.cloudfoundry.org/process-type"
 
 	ApplicationContainerName  = "application"
+	SidecarContainerPrefix    = "sidecar-"
 	AppWorkloadReconcilerName = "statefulset-runner"
 	ServiceAccountName        = "korifi-app"
+	TmpVolumeName             = "tmp"
 
 	LivenessFailureThreshold  = 4
 	ReadinessFailureThreshold = 1
 
 	PodAffinityTermWeight = 100
+
+	// PreStopDelaySeconds is how long routable instances keep running after
+	// they are asked to stop, so that they are taken out of the routing
+	// before the app is sent SIGTERM
+	PreStopDelaySeconds = 5
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 //counterfeiter:generate -o ../fake -fake-name PDB . PDB
 type PDB interface {
-	Update(ctx context.Context, statefulSet *appsv1.StatefulSet) error
+	Update(ctx context.Context, workload client.Object, replicas int32, selector *metav1.LabelSelector) error
+}
+
+//counterfeiter:generate -o ../fake -fake-name InstanceIdentity . InstanceIdentity
+type InstanceIdentity interface {
+	Update(ctx context.Context, statefulSet *appsv1.StatefulSet) (time.Duration, error)
 }
 
 //counterfeiter:generate -o ../fake -fake-name WorkloadToStatefulsetConverter . WorkloadToStatefulsetConverter
@@ -82,6 +102,7 @@ type AppWorkloadReconciler struct {
 	scheme           *runtime.Scheme
 	workloadsToStSet WorkloadToStatefulsetConverter
 	pdb              PDB
+	instanceIdentity InstanceIdentity
 	log              logr.Logger
 }
 
@@ -90,6 +111,7 @@ func NewAppWorkloadReconciler(
 	scheme *runtime.Scheme,
 	workloadsToStSet WorkloadToStatefulsetConverter,
 	pdb PDB,
+	instanceIdentity InstanceIdentity,
 	log logr.Logger,
 ) *k8s.PatchingReconciler[korifiv1alpha1.AppWorkload, *korifiv1alpha1.AppWorkload] {
 	appWorkloadReconciler := AppWorkloadReconciler{
@@ -97,6 +119,7 @@ func NewAppWorkloadReconciler(
 		scheme:           scheme,
 		workloadsToStSet: workloadsToStSet,
 		pdb:              pdb,
+		instanceIdentity: instanceIdentity,
 		log:              log,
 	}
 	return k8s.NewPatchingReconciler[korifiv1alpha1.AppWorkload, *korifiv1alpha1.AppWorkload](log, c, &appWorkloadReconciler)
@@ -110,6 +133,10 @@ func (r *AppWorkloadReconciler) SetupWithManager(mgr ctrl.Manager) *builder.Buil
 			new(appsv1.StatefulSet),
 			handler.EnqueueRequestsFromMapFunc(r.enqueueAppWorkloadRequests),
 		).
+		Watches(
+			new(corev1.Pod),
+			handler.EnqueueRequestsFromMapFunc(r.enqueueAppWorkloadRequests),
+		).
 		WithEventFilter(predicate.NewPredicateFuncs(filterAppWorkloads))
 }
 
@@ -143,7 +170,10 @@ func filterAppWorkloads(object client.Object) bool {
 //+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=create;patch;get;list;watch
 //+kubebuilder:rbac:groups=apps,resources=statefulsets/finalizers,verbs=update
 
-//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=create;patch;deletecollection
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;patch;deletecollection
+
+//+kubebuilder:rbac:groups="",resources=pods,verbs=list;watch;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;patch;delete
 
 func (r *AppWorkloadReconciler) ReconcileResource(ctx context.Context, appWorkload *korifiv1alpha1.AppWorkload) (ctrl.Result, error) {
 	log := logr.FromContextOrDiscard(ctx)
@@ -177,13 +207,81 @@ func (r *AppWorkloadReconciler) ReconcileResource(ctx context.Context, appWorklo
 		return ctrl.Result{}, err
 	}
 
-	err = r.pdb.Update(ctx, createdStSet)
+	err = r.pdb.Update(ctx, createdStSet, *createdStSet.Spec.Replicas, createdStSet.Spec.Selector)
 	if err != nil {
 		log.Info("error when creating or patching pod disruption budget", "reason", err)
 		return ctrl.Result{}, err
 	}
 
+	renewIdentityAfter, err := r.instanceIdentity.Update(ctx, createdStSet)
+	if err != nil {
+		log.Info("error when issuing instance identity certificates", "reason", err)
+		return ctrl.Result{}, err
+	}
+
 	appWorkload.Status.ActualInstances = createdStSet.Status.Replicas
+	appWorkload.Status.Selector = metav1.FormatLabelSelector(createdStSet.Spec.Selector)
+
+	pods := &corev1.PodList{}
+	err = r.k8sClient.List(ctx, pods, client.InNamespace(appWorkload.Namespace), client.MatchingLabels{LabelAppWorkloadGUID: appWorkload.Name})
+	if err != nil {
+		log.Info("error when listing the instances", "reason", err)
+		return ctrl.Result{}, err
+	}
+	meta.SetStatusCondition(&appWorkload.Status.Conditions, instancesFailingCondition(appWorkload, pods.Items))
 
-	return ctrl.Result{}, nil
+	err = PatchPodsCFMetadata(ctx, r.k8sClient, appWorkload, pods.Items)
+	if err != nil {
+		log.Info("error when patching the metadata of the instances", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: renewIdentityAfter}, nil
+}
+
+// PatchPodsCFMetadata sets the CF metadata of the workload on its pods. The
+// metadata is kept out of the pod template, as changing the template would
+// restart the instances, while the metadata of a pod can change in place.
+func PatchPodsCFMetadata(ctx context.Context, k8sClient client.Client, appWorkload *korifiv1alpha1.AppWorkload, pods []corev1.Pod) error {
+	for i := range pods {
+		pod := &pods[i]
+		labels := withCFMetadata(pod.Labels, appWorkload.Labels)
+		annotations := withCFMetadata(pod.Annotations, appWorkload.Annotations)
+		if maps.Equal(labels, pod.Labels) && maps.Equal(annotations, pod.Annotations) {
+			continue
+		}
+
+		err := k8s.PatchResource(ctx, k8sClient, pod, func() {
+			pod.Labels = labels
+			pod.Annotations = annotations
+		})
+		if err != nil {
+			return fmt.Errorf("failed to patch pod %s: %w", pod.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// instancesFailingCondition reports the first instance that cannot start, so
+// that the failure is not hidden behind the instance starting forever
+func instancesFailingCondition(appWorkload *korifiv1alpha1.AppWorkload, pods []corev1.Pod) metav1.Condition {
+	for _, pod := range pods {
+		if reason, message, failing := k8s.PodStartFailure(pod); failing {
+			return metav1.Condition{
+				Type:               korifiv1alpha1.InstancesFailingConditionType,
+				Status:             metav1.ConditionTrue,
+				Reason:             reason,
+				Message:            fmt.Sprintf("Instance %s cannot start: %s", pod.Name, message),
+				ObservedGeneration: appWorkload.Generation,
+			}
+		}
+	}
+
+	return metav1.Condition{
+		Type:               korifiv1alpha1.InstancesFailingConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoFailures",
+		ObservedGeneration: appWorkload.Generation,
+	}
 }
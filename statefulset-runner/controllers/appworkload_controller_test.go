@@ -3,6 +3,7 @@ package controllers_test
 import (
 	"context"
 	"errors"
+	"time"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/statefulset-runner/controllers"
@@ -16,6 +17,7 @@ import (
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -35,6 +37,7 @@ var _ = Describe("AppWorkload Reconcile", func() {
 		statefulSet            *v1.StatefulSet
 		fakeWorkloadToStSet    *fake.WorkloadToStatefulsetConverter
 		fakePDB                *fake.PDB
+		fakeInstanceIdentity   *fake.InstanceIdentity
 		getAppWorkloadError    error
 		getStatefulSetError    error
 		createStatefulSetError error
@@ -53,6 +56,12 @@ var _ = Describe("AppWorkload Reconcile", func() {
 				Name:      uuid.NewString(),
 				Namespace: appWorkload.Namespace,
 			},
+			Spec: v1.StatefulSetSpec{
+				Replicas: tools.PtrTo(int32(1)),
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{controllers.LabelGUID: "guid"},
+				},
+			},
 		}
 
 		fakeWorkloadToStSet = new(fake.WorkloadToStatefulsetConverter)
@@ -60,6 +69,8 @@ var _ = Describe("AppWorkload Reconcile", func() {
 
 		fakePDB = new(fake.PDB)
 
+		fakeInstanceIdentity = new(fake.InstanceIdentity)
+
 		ctx = context.Background()
 		req = ctrl.Request{
 			NamespacedName: types.NamespacedName{
@@ -104,6 +115,7 @@ var _ = Describe("AppWorkload Reconcile", func() {
 			scheme.Scheme,
 			fakeWorkloadToStSet,
 			fakePDB,
+			fakeInstanceIdentity,
 			ctrl.Log.WithName("controllers").WithName("TestAppWorkload"),
 		)
 	})
@@ -130,6 +142,100 @@ var _ = Describe("AppWorkload Reconcile", func() {
 			patchedAppWorkload, ok := object.(*korifiv1alpha1.AppWorkload)
 			Expect(ok).To(BeTrue())
 			Expect(patchedAppWorkload.Status.ObservedGeneration).To(Equal(patchedAppWorkload.Generation))
+			Expect(patchedAppWorkload.Status.Selector).To(Equal(controllers.LabelGUID + "=guid"))
+		})
+
+		It("reports that no instances are failing", func() {
+			_, object, _, _ := fakeStatusWriter.PatchArgsForCall(0)
+			patchedAppWorkload := object.(*korifiv1alpha1.AppWorkload)
+			Expect(meta.IsStatusConditionFalse(patchedAppWorkload.Status.Conditions, korifiv1alpha1.InstancesFailingConditionType)).To(BeTrue())
+		})
+
+		When("an instance cannot pull its image", func() {
+			BeforeEach(func() {
+				fakeClient.ListStub = func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+					podList, ok := list.(*corev1.PodList)
+					Expect(ok).To(BeTrue())
+					podList.Items = []corev1.Pod{{
+						ObjectMeta: metav1.ObjectMeta{Name: "my-app-web-0"},
+						Status: corev1.PodStatus{
+							ContainerStatuses: []corev1.ContainerStatus{{
+								Name: controllers.ApplicationContainerName,
+								State: corev1.ContainerState{
+									Waiting: &corev1.ContainerStateWaiting{
+										Reason:  "ImagePullBackOff",
+										Message: "Back-off pulling image",
+									},
+								},
+							}},
+						},
+					}}
+					return nil
+				}
+			})
+
+			It("reports the failing instance", func() {
+				_, object, _, _ := fakeStatusWriter.PatchArgsForCall(0)
+				patchedAppWorkload := object.(*korifiv1alpha1.AppWorkload)
+				condition := meta.FindStatusCondition(patchedAppWorkload.Status.Conditions, korifiv1alpha1.InstancesFailingConditionType)
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+				Expect(condition.Reason).To(Equal("ImagePullBackOff"))
+				Expect(condition.Message).To(Equal("Instance my-app-web-0 cannot start: Back-off pulling image"))
+			})
+		})
+
+		When("the app workload has CF metadata", func() {
+			BeforeEach(func() {
+				appWorkload.Labels = map[string]string{
+					"metadata.korifi.cloudfoundry.org/team": "my-team",
+				}
+				fakeClient.ListStub = func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+					podList, ok := list.(*corev1.PodList)
+					Expect(ok).To(BeTrue())
+					podList.Items = []corev1.Pod{
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "my-app-web-0",
+								Labels: map[string]string{
+									controllers.LabelGUID:                   "guid",
+									"metadata.korifi.cloudfoundry.org/team": "my-team",
+								},
+							},
+						},
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "my-app-web-1",
+								Labels: map[string]string{
+									controllers.LabelGUID:                  "guid",
+									"metadata.korifi.cloudfoundry.org/env": "prod",
+								},
+							},
+						},
+					}
+					return nil
+				}
+			})
+
+			It("sets it on the instances that do not have it", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+
+				var patchedPods []*corev1.Pod
+				for i := range fakeClient.PatchCallCount() {
+					_, obj, _, _ := fakeClient.PatchArgsForCall(i)
+					if pod, ok := obj.(*corev1.Pod); ok {
+						patchedPods = append(patchedPods, pod)
+					}
+				}
+
+				Expect(patchedPods).To(ConsistOf(SatisfyAll(
+					HaveField("Name", "my-app-web-1"),
+					HaveField("Labels", Equal(map[string]string{
+						controllers.LabelGUID:                   "guid",
+						"metadata.korifi.cloudfoundry.org/team": "my-team",
+					})),
+				)))
+			})
 		})
 
 		When("coverting the app workload to statefulset fails", func() {
@@ -190,6 +296,15 @@ var _ = Describe("AppWorkload Reconcile", func() {
 			Expect(updatedStSet.Spec.Replicas).To(Equal(tools.PtrTo(int32(2))))
 		})
 
+		It("updates the pod disruption budget of the statefulset", func() {
+			Expect(fakePDB.UpdateCallCount()).To(Equal(1))
+			_, workload, replicas, selector := fakePDB.UpdateArgsForCall(0)
+			Expect(workload).To(BeAssignableToTypeOf(new(v1.StatefulSet)))
+			Expect(workload.GetName()).To(Equal(statefulSet.Name))
+			Expect(replicas).To(BeEquivalentTo(2))
+			Expect(selector).To(Equal(statefulSet.Spec.Selector))
+		})
+
 		When("updating the pod disruption budget fails", func() {
 			BeforeEach(func() {
 				fakePDB.UpdateReturns(errors.New("boom"))
@@ -199,6 +314,34 @@ var _ = Describe("AppWorkload Reconcile", func() {
 				Expect(reconcileErr).To(MatchError("boom"))
 			})
 		})
+
+		It("issues the instance identity certificates of the statefulset", func() {
+			Expect(fakeInstanceIdentity.UpdateCallCount()).To(Equal(1))
+			_, updatedStSet := fakeInstanceIdentity.UpdateArgsForCall(0)
+			Expect(updatedStSet.Name).To(Equal(statefulSet.Name))
+			Expect(updatedStSet.Spec.Replicas).To(Equal(tools.PtrTo(int32(2))))
+		})
+
+		When("the instance identity certificates need renewing", func() {
+			BeforeEach(func() {
+				fakeInstanceIdentity.UpdateReturns(time.Hour, nil)
+			})
+
+			It("requeues the appworkload when they are due", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(reconcileResult.RequeueAfter).To(Equal(time.Hour))
+			})
+		})
+
+		When("issuing the instance identity certificates fails", func() {
+			BeforeEach(func() {
+				fakeInstanceIdentity.UpdateReturns(0, errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError("boom"))
+			})
+		})
 	})
 })
 
@@ -5,13 +5,17 @@ import (
 	"encoding/hex"
 	"fmt"
 	"maps"
+	"path"
 	"regexp"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/config"
 	"code.cloudfoundry.org/korifi/tools"
+	"code.cloudfoundry.org/korifi/tools/k8s"
 	"github.com/BooleanCat/go-functional/v2/it"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -23,15 +27,36 @@ import (
 type AppWorkloadToStatefulsetConverter struct {
 	scheme                                         *runtime.Scheme
 	statefulsetRunnerTemporarySetPodSeccompProfile bool
+	topologySpread                                 config.TopologySpread
+	nodePlacement                                  config.NodePlacement
+	priorityClassName                              string
+	runtimeClassName                               string
+	securityContext                                config.SecurityContext
+	instanceIdentity                               bool
+	trustedCAConfigMapName                         string
 }
 
 func NewAppWorkloadToStatefulsetConverter(
 	scheme *runtime.Scheme,
 	statefulsetRunnerTemporarySetPodSeccompProfile bool,
+	topologySpread config.TopologySpread,
+	nodePlacement config.NodePlacement,
+	priorityClassName string,
+	runtimeClassName string,
+	securityContext config.SecurityContext,
+	instanceIdentity bool,
+	trustedCAConfigMapName string,
 ) *AppWorkloadToStatefulsetConverter {
 	return &AppWorkloadToStatefulsetConverter{
 		scheme: scheme,
 		statefulsetRunnerTemporarySetPodSeccompProfile: statefulsetRunnerTemporarySetPodSeccompProfile,
+		topologySpread:         topologySpread,
+		nodePlacement:          nodePlacement,
+		priorityClassName:      priorityClassName,
+		runtimeClassName:       runtimeClassName,
+		securityContext:        securityContext,
+		instanceIdentity:       instanceIdentity,
+		trustedCAConfigMapName: trustedCAConfigMapName,
 	}
 }
 
@@ -71,6 +96,14 @@ func (r *AppWorkloadToStatefulsetConverter) Convert(appWorkload *korifiv1alpha1.
 				},
 			},
 		},
+		{
+			Name: EnvInstanceGUID,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "metadata.uid",
+				},
+			},
+		},
 		{
 			Name: EnvCFInstanceIndex,
 			ValueFrom: &corev1.EnvVarSource{
@@ -79,6 +112,14 @@ func (r *AppWorkloadToStatefulsetConverter) Convert(appWorkload *korifiv1alpha1.
 				},
 			},
 		},
+		{
+			Name: EnvInstanceIndex,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: fmt.Sprintf("metadata.labels['%s']", korifiv1alpha1.PodIndexLabelKey),
+				},
+			},
+		},
 		{
 			Name: EnvCFInstanceIP,
 			ValueFrom: &corev1.EnvVarSource{
@@ -104,6 +145,28 @@ func (r *AppWorkloadToStatefulsetConverter) Convert(appWorkload *korifiv1alpha1.
 		return envs[i].Name < envs[j].Name
 	})
 
+	// CF_INSTANCE_ADDR refers to CF_INSTANCE_INTERNAL_IP, so it has to come
+	// after it for kubernetes to expand the reference
+	if len(appWorkload.Spec.Ports) > 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  EnvCFInstanceAddr,
+			Value: fmt.Sprintf("$(%s):%d", EnvCFInstanceInternalIP, appWorkload.Spec.Ports[0]),
+		})
+	}
+
+	if r.instanceIdentity {
+		envs = append(envs,
+			corev1.EnvVar{
+				Name:  EnvCFInstanceCert,
+				Value: path.Join(InstanceIdentityMountPath, corev1.TLSCertKey),
+			},
+			corev1.EnvVar{
+				Name:  EnvCFInstanceKey,
+				Value: path.Join(InstanceIdentityMountPath, corev1.TLSPrivateKeyKey),
+			},
+		)
+	}
+
 	containers := []corev1.Container{
 		{
 			Name:            ApplicationContainerName,
@@ -114,18 +177,12 @@ func (r *AppWorkloadToStatefulsetConverter) Convert(appWorkload *korifiv1alpha1.
 			Ports: slices.Collect(it.Map(slices.Values(appWorkload.Spec.Ports), func(port int32) corev1.ContainerPort {
 				return corev1.ContainerPort{ContainerPort: port}
 			})),
-			SecurityContext: &corev1.SecurityContext{
-				AllowPrivilegeEscalation: tools.PtrTo(false),
-				Capabilities: &corev1.Capabilities{
-					Drop: []corev1.Capability{"ALL"},
-				},
-				SeccompProfile: &corev1.SeccompProfile{
-					Type: corev1.SeccompProfileTypeRuntimeDefault,
-				},
-			},
-			Resources:     appWorkload.Spec.Resources,
-			StartupProbe:  appWorkload.Spec.StartupProbe,
-			LivenessProbe: appWorkload.Spec.LivenessProbe,
+			SecurityContext: r.containerSecurityContext(),
+			Resources:       appWorkload.Spec.Resources,
+			StartupProbe:    appWorkload.Spec.StartupProbe,
+			LivenessProbe:   appWorkload.Spec.LivenessProbe,
+			ReadinessProbe:  appWorkload.Spec.ReadinessProbe,
+			Lifecycle:       preStopLifecycle(appWorkload),
 		},
 	}
 
@@ -147,18 +204,65 @@ func (r *AppWorkloadToStatefulsetConverter) Convert(appWorkload *korifiv1alpha1.
 					Containers:       containers,
 					ImagePullSecrets: appWorkload.Spec.ImagePullSecrets,
 					SecurityContext: &corev1.PodSecurityContext{
-						RunAsNonRoot: tools.PtrTo(true),
+						RunAsNonRoot: r.securityContext.RunAsNonRoot,
 					},
-					ServiceAccountName: ServiceAccountName,
+					ServiceAccountName:            ServiceAccountName,
+					TerminationGracePeriodSeconds: terminationGracePeriodSeconds(appWorkload),
 				},
 			},
 		},
 	}
 
 	if r.statefulsetRunnerTemporarySetPodSeccompProfile {
-		statefulSet.Spec.Template.Spec.SecurityContext.SeccompProfile = &corev1.SeccompProfile{
-			Type: corev1.SeccompProfileTypeRuntimeDefault,
-		}
+		statefulSet.Spec.Template.Spec.SecurityContext.SeccompProfile = r.seccompProfile()
+	}
+
+	if r.securityContext.ReadOnlyRootFilesystem {
+		// apps can still write temporary files, within their disk quota
+		statefulSet.Spec.Template.Spec.Volumes = []corev1.Volume{{
+			Name:         TmpVolumeName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		}}
+		statefulSet.Spec.Template.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{
+			Name:      TmpVolumeName,
+			MountPath: "/tmp",
+		}}
+	}
+
+	if r.instanceIdentity {
+		// each pod mounts the secret holding its own certificate, which the
+		// instance identity webhook sets when the pod is created
+		statefulSet.Spec.Template.Spec.Volumes = append(statefulSet.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: InstanceIdentityVolumeName,
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+				SecretName: InstanceIdentitySecretName(statefulsetName),
+			}},
+		})
+		statefulSet.Spec.Template.Spec.Containers[0].VolumeMounts = append(statefulSet.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      InstanceIdentityVolumeName,
+			MountPath: InstanceIdentityMountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	// sidecars share the environment and volumes of the app container, so
+	// they are added once the app container is complete
+	for _, sidecar := range appWorkload.Spec.Sidecars {
+		appContainer := statefulSet.Spec.Template.Spec.Containers[0]
+		statefulSet.Spec.Template.Spec.Containers = append(statefulSet.Spec.Template.Spec.Containers, corev1.Container{
+			Name:            SidecarContainerPrefix + sidecar.Name,
+			Image:           appContainer.Image,
+			ImagePullPolicy: appContainer.ImagePullPolicy,
+			Command:         sidecar.Command,
+			Env:             appContainer.Env,
+			SecurityContext: appContainer.SecurityContext,
+			Resources:       sidecar.Resources,
+			VolumeMounts:    appContainer.VolumeMounts,
+		})
+	}
+
+	if r.trustedCAConfigMapName != "" {
+		k8s.MountTrustedCA(&statefulSet.Spec.Template.Spec, r.trustedCAConfigMapName)
 	}
 
 	statefulSet.Spec.Template.Spec.AutomountServiceAccountToken = tools.PtrTo(false)
@@ -180,6 +284,14 @@ func (r *AppWorkloadToStatefulsetConverter) Convert(appWorkload *korifiv1alpha1.
 		},
 	}
 
+	statefulSet.Spec.Template.Spec.TopologySpreadConstraints = r.topologySpreadConstraints(appWorkload)
+	statefulSet.Spec.Template.Spec.NodeSelector = r.nodeSelector(appWorkload)
+	statefulSet.Spec.Template.Spec.Tolerations = r.tolerations(appWorkload)
+	statefulSet.Spec.Template.Spec.PriorityClassName = orDefault(appWorkload.Spec.PriorityClassName, r.priorityClassName)
+	if runtimeClassName := orDefault(appWorkload.Spec.RuntimeClassName, r.runtimeClassName); runtimeClassName != "" {
+		statefulSet.Spec.Template.Spec.RuntimeClassName = tools.PtrTo(runtimeClassName)
+	}
+
 	err = controllerutil.SetControllerReference(appWorkload, statefulSet, r.scheme)
 	if err != nil {
 		return nil, fmt.Errorf("failed to set OwnerRef on StatefulSet :%w", err)
@@ -193,8 +305,12 @@ func (r *AppWorkloadToStatefulsetConverter) Convert(appWorkload *korifiv1alpha1.
 		LabelAppWorkloadGUID: appWorkload.Name,
 	}
 
+	if r.instanceIdentity {
+		labels[LabelInstanceIdentity] = "true"
+	}
+
 	statefulSet.Spec.Template.Labels = labels
-	statefulSet.Labels = labels
+	statefulSet.Labels = withCFMetadata(labels, appWorkload.Labels)
 
 	annotations := map[string]string{
 		AnnotationAppID:       appWorkload.Spec.AppGUID,
@@ -202,12 +318,162 @@ func (r *AppWorkloadToStatefulsetConverter) Convert(appWorkload *korifiv1alpha1.
 		AnnotationProcessGUID: fmt.Sprintf("%s-%s", appWorkload.Spec.GUID, appWorkload.Spec.Version),
 	}
 
-	statefulSet.Annotations = annotations
+	statefulSet.Annotations = withCFMetadata(annotations, appWorkload.Annotations)
 	statefulSet.Spec.Template.Annotations = annotations
 
+	// a new env checksum changes the pod template and rolls the instances
+	if envChecksum, ok := appWorkload.Annotations[korifiv1alpha1.EnvChecksumAnnotationKey]; ok {
+		statefulSet.Spec.Template.Annotations[korifiv1alpha1.EnvChecksumAnnotationKey] = envChecksum
+	}
+
 	return statefulSet, nil
 }
 
+// withCFMetadata returns the labels or annotations with their CF metadata
+// replaced by the CF metadata of the workload, i.e. the labels or annotations
+// set via the CF API. It is not part of the pod template, so that changing it
+// does not restart the instances; the AppWorkloadReconciler sets it on the
+// pods instead.
+func withCFMetadata(metadata, workloadMetadata map[string]string) map[string]string {
+	result := maps.Clone(metadata)
+	if result == nil {
+		result = map[string]string{}
+	}
+
+	maps.DeleteFunc(result, func(key, _ string) bool {
+		return strings.HasPrefix(key, korifiv1alpha1.CFMetadataPrefix)
+	})
+	for key, value := range workloadMetadata {
+		if strings.HasPrefix(key, korifiv1alpha1.CFMetadataPrefix) {
+			result[key] = value
+		}
+	}
+
+	return result
+}
+
+// topologySpreadConstraints spreads the instances of the workload evenly
+// across each of the configured topology domains, unless the app opted out
+func (r *AppWorkloadToStatefulsetConverter) topologySpreadConstraints(appWorkload *korifiv1alpha1.AppWorkload) []corev1.TopologySpreadConstraint {
+	if appWorkload.Annotations[korifiv1alpha1.DisableTopologySpreadAnnotationKey] == "true" {
+		return nil
+	}
+
+	return slices.Collect(it.Map(slices.Values(r.topologySpread.TopologyKeys), func(topologyKey string) corev1.TopologySpreadConstraint {
+		return corev1.TopologySpreadConstraint{
+			MaxSkew:           r.topologySpread.MaxSkew,
+			TopologyKey:       topologyKey,
+			WhenUnsatisfiable: corev1.UnsatisfiableConstraintAction(r.topologySpread.WhenUnsatisfiable),
+			LabelSelector:     statefulSetLabelSelector(appWorkload),
+		}
+	}))
+}
+
+// nodeSelector merges the node selector of the workload space into the
+// configured one, with the space taking precedence on conflicting labels
+func (r *AppWorkloadToStatefulsetConverter) nodeSelector(appWorkload *korifiv1alpha1.AppWorkload) map[string]string {
+	if len(r.nodePlacement.NodeSelector) == 0 && len(appWorkload.Spec.NodeSelector) == 0 {
+		return nil
+	}
+
+	nodeSelector := maps.Clone(r.nodePlacement.NodeSelector)
+	if nodeSelector == nil {
+		nodeSelector = map[string]string{}
+	}
+	maps.Copy(nodeSelector, appWorkload.Spec.NodeSelector)
+
+	return nodeSelector
+}
+
+func (r *AppWorkloadToStatefulsetConverter) tolerations(appWorkload *korifiv1alpha1.AppWorkload) []corev1.Toleration {
+	tolerations := slices.Collect(it.Map(slices.Values(r.nodePlacement.Tolerations), func(toleration config.Toleration) corev1.Toleration {
+		return corev1.Toleration{
+			Key:               toleration.Key,
+			Operator:          corev1.TolerationOperator(toleration.Operator),
+			Value:             toleration.Value,
+			Effect:            corev1.TaintEffect(toleration.Effect),
+			TolerationSeconds: toleration.TolerationSeconds,
+		}
+	}))
+
+	return append(tolerations, appWorkload.Spec.Tolerations...)
+}
+
+func (r *AppWorkloadToStatefulsetConverter) containerSecurityContext() *corev1.SecurityContext {
+	securityContext := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: tools.PtrTo(false),
+		SeccompProfile:           r.seccompProfile(),
+	}
+
+	if len(r.securityContext.DropCapabilities) > 0 {
+		securityContext.Capabilities = &corev1.Capabilities{
+			Drop: slices.Collect(it.Map(slices.Values(r.securityContext.DropCapabilities), func(capability string) corev1.Capability {
+				return corev1.Capability(capability)
+			})),
+		}
+	}
+
+	if r.securityContext.ReadOnlyRootFilesystem {
+		securityContext.ReadOnlyRootFilesystem = tools.PtrTo(true)
+	}
+
+	return securityContext
+}
+
+func (r *AppWorkloadToStatefulsetConverter) seccompProfile() *corev1.SeccompProfile {
+	if r.securityContext.SeccompProfile == "" {
+		return nil
+	}
+
+	return &corev1.SeccompProfile{
+		Type: corev1.SeccompProfileType(r.securityContext.SeccompProfile),
+	}
+}
+
+// orDefault returns the value set on the workload by its org or space, falling
+// back to the one configured for the installation
+func orDefault(workloadValue, defaultValue string) string {
+	if workloadValue != "" {
+		return workloadValue
+	}
+
+	return defaultValue
+}
+
+// preStopLifecycle delays stopping routable instances with an exec sleep
+// rather than a sleep action, as the latter is only available on Kubernetes
+// 1.30 or later. Should the image have no sleep binary, the hook fails and the
+// instance is stopped straight away.
+func preStopLifecycle(appWorkload *korifiv1alpha1.AppWorkload) *corev1.Lifecycle {
+	if len(appWorkload.Spec.Ports) == 0 {
+		return nil
+	}
+
+	return &corev1.Lifecycle{
+		PreStop: &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"sleep", strconv.Itoa(PreStopDelaySeconds)},
+			},
+		},
+	}
+}
+
+// terminationGracePeriodSeconds extends the grace period of the workload with
+// the pre-stop delay, so that the app is given the whole grace period to shut
+// down after it is sent SIGTERM
+func terminationGracePeriodSeconds(appWorkload *korifiv1alpha1.AppWorkload) *int64 {
+	if appWorkload.Spec.TerminationGracePeriodSeconds == nil {
+		return nil
+	}
+
+	gracePeriod := *appWorkload.Spec.TerminationGracePeriodSeconds
+	if preStopLifecycle(appWorkload) != nil {
+		gracePeriod += PreStopDelaySeconds
+	}
+
+	return &gracePeriod
+}
+
 func sanitizeName(name, fallback string) string {
 	const sanitizedNameMaxLen = 40
 	return sanitizeNameWithMaxStringLen(name, fallback, sanitizedNameMaxLen)
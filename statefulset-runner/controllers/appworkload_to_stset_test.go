@@ -2,13 +2,16 @@ package controllers_test
 
 import (
 	"fmt"
+	"strconv"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/config"
 	"code.cloudfoundry.org/korifi/statefulset-runner/controllers"
 	"code.cloudfoundry.org/korifi/tools"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -23,6 +26,13 @@ var _ = Describe("AppWorkload to StatefulSet Converter", func() {
 		appWorkload                                    *korifiv1alpha1.AppWorkload
 		converter                                      *controllers.AppWorkloadToStatefulsetConverter
 		statefulsetRunnerTemporarySetPodSeccompProfile bool
+		topologySpread                                 config.TopologySpread
+		nodePlacement                                  config.NodePlacement
+		priorityClassName                              string
+		runtimeClassName                               string
+		securityContext                                config.SecurityContext
+		instanceIdentity                               bool
+		trustedCAConfigMapName                         string
 	)
 
 	BeforeEach(func() {
@@ -69,6 +79,16 @@ var _ = Describe("AppWorkload to StatefulSet Converter", func() {
 					PeriodSeconds:    30,
 					FailureThreshold: 1,
 				},
+				ReadinessProbe: &corev1.Probe{
+					ProbeHandler: corev1.ProbeHandler{
+						HTTPGet: &corev1.HTTPGetAction{
+							Path: "/healthz",
+							Port: intstr.IntOrString{Type: intstr.Int, IntVal: int32(8080)},
+						},
+					},
+					PeriodSeconds:    10,
+					FailureThreshold: 1,
+				},
 				Ports:      []int32{8888, 9999},
 				Instances:  1,
 				RunnerName: "statefulset-runner",
@@ -86,6 +106,17 @@ var _ = Describe("AppWorkload to StatefulSet Converter", func() {
 		}
 
 		statefulsetRunnerTemporarySetPodSeccompProfile = false
+		topologySpread = config.TopologySpread{}
+		nodePlacement = config.NodePlacement{}
+		priorityClassName = ""
+		runtimeClassName = ""
+		securityContext = config.SecurityContext{
+			RunAsNonRoot:     tools.PtrTo(true),
+			SeccompProfile:   "RuntimeDefault",
+			DropCapabilities: []string{"ALL"},
+		}
+		instanceIdentity = false
+		trustedCAConfigMapName = ""
 	})
 
 	JustBeforeEach(func() {
@@ -93,6 +124,13 @@ var _ = Describe("AppWorkload to StatefulSet Converter", func() {
 		converter = controllers.NewAppWorkloadToStatefulsetConverter(
 			scheme.Scheme,
 			statefulsetRunnerTemporarySetPodSeccompProfile,
+			topologySpread,
+			nodePlacement,
+			priorityClassName,
+			runtimeClassName,
+			securityContext,
+			instanceIdentity,
+			trustedCAConfigMapName,
 		)
 		statefulSet, err = converter.Convert(appWorkload)
 
@@ -117,6 +155,21 @@ var _ = Describe("AppWorkload to StatefulSet Converter", func() {
 		Entry("Version", controllers.AnnotationVersion, "version_1234"),
 	)
 
+	It("should not set the env checksum on the template when the AppWorkload has none", func() {
+		Expect(statefulSet.Spec.Template.Annotations).NotTo(HaveKey(korifiv1alpha1.EnvChecksumAnnotationKey))
+	})
+
+	When("the AppWorkload has an env checksum", func() {
+		BeforeEach(func() {
+			appWorkload.Annotations[korifiv1alpha1.EnvChecksumAnnotationKey] = "env-checksum"
+		})
+
+		It("sets it on the template only", func() {
+			Expect(statefulSet.Spec.Template.Annotations).To(HaveKeyWithValue(korifiv1alpha1.EnvChecksumAnnotationKey, "env-checksum"))
+			Expect(statefulSet.Annotations).NotTo(HaveKey(korifiv1alpha1.EnvChecksumAnnotationKey))
+		})
+	})
+
 	It("should be owned by the AppWorkload", func() {
 		Expect(statefulSet.OwnerReferences).To(HaveLen(1))
 		Expect(statefulSet.OwnerReferences[0].Kind).To(Equal("AppWorkload"))
@@ -183,6 +236,153 @@ var _ = Describe("AppWorkload to StatefulSet Converter", func() {
 		Expect(*statefulSet.Spec.Template.Spec.Containers[0].SecurityContext.SeccompProfile).To(Equal(corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}))
 	})
 
+	It("does not make the root filesystem read-only", func() {
+		Expect(statefulSet.Spec.Template.Spec.Containers[0].SecurityContext.ReadOnlyRootFilesystem).To(BeNil())
+		Expect(statefulSet.Spec.Template.Spec.Volumes).To(BeEmpty())
+	})
+
+	When("the security context is customized", func() {
+		BeforeEach(func() {
+			securityContext = config.SecurityContext{
+				RunAsNonRoot:           tools.PtrTo(false),
+				SeccompProfile:         "Unconfined",
+				DropCapabilities:       []string{"NET_RAW", "SYS_ADMIN"},
+				ReadOnlyRootFilesystem: true,
+			}
+		})
+
+		It("applies it to the pod and container", func() {
+			Expect(statefulSet.Spec.Template.Spec.SecurityContext.RunAsNonRoot).To(PointTo(BeFalse()))
+
+			containerSecurityContext := statefulSet.Spec.Template.Spec.Containers[0].SecurityContext
+			Expect(containerSecurityContext.SeccompProfile).To(PointTo(Equal(corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined})))
+			Expect(containerSecurityContext.Capabilities.Drop).To(Equal([]corev1.Capability{"NET_RAW", "SYS_ADMIN"}))
+			Expect(containerSecurityContext.ReadOnlyRootFilesystem).To(PointTo(BeTrue()))
+		})
+
+		It("mounts a writable tmp directory", func() {
+			Expect(statefulSet.Spec.Template.Spec.Volumes).To(ConsistOf(corev1.Volume{
+				Name:         "tmp",
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			}))
+			Expect(statefulSet.Spec.Template.Spec.Containers[0].VolumeMounts).To(ConsistOf(corev1.VolumeMount{
+				Name:      "tmp",
+				MountPath: "/tmp",
+			}))
+		})
+	})
+
+	It("does not expose instance identity certificates", func() {
+		Expect(statefulSet.Spec.Template.Spec.Containers[0].Env).NotTo(ContainElement(HaveField("Name", controllers.EnvCFInstanceCert)))
+		Expect(statefulSet.Spec.Template.Spec.Containers[0].Env).NotTo(ContainElement(HaveField("Name", controllers.EnvCFInstanceKey)))
+	})
+
+	When("instance identity is enabled", func() {
+		BeforeEach(func() {
+			instanceIdentity = true
+			securityContext.ReadOnlyRootFilesystem = true
+		})
+
+		It("labels the pods for the instance identity webhook", func() {
+			Expect(statefulSet.Spec.Template.Labels).To(HaveKeyWithValue("korifi.cloudfoundry.org/instance-identity", "true"))
+		})
+
+		It("mounts the instance identity secret, for the webhook to set per pod", func() {
+			Expect(statefulSet.Spec.Template.Spec.Volumes).To(ConsistOf(
+				HaveField("Name", "tmp"),
+				corev1.Volume{
+					Name: "instance-identity",
+					VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+						SecretName: statefulSet.Name + "-instance-identity",
+					}},
+				},
+			))
+			Expect(statefulSet.Spec.Template.Spec.Containers[0].VolumeMounts).To(ConsistOf(
+				HaveField("Name", "tmp"),
+				corev1.VolumeMount{
+					Name:      "instance-identity",
+					MountPath: "/etc/cf-instance-credentials",
+					ReadOnly:  true,
+				},
+			))
+		})
+
+		It("points CF_INSTANCE_CERT and CF_INSTANCE_KEY to the files of the instance", func() {
+			Expect(statefulSet.Spec.Template.Spec.Containers[0].Env).To(ContainElements(
+				corev1.EnvVar{Name: "CF_INSTANCE_CERT", Value: "/etc/cf-instance-credentials/tls.crt"},
+				corev1.EnvVar{Name: "CF_INSTANCE_KEY", Value: "/etc/cf-instance-credentials/tls.key"},
+			))
+		})
+	})
+
+	When("the app workload has CF metadata", func() {
+		BeforeEach(func() {
+			appWorkload.Labels = map[string]string{
+				"metadata.korifi.cloudfoundry.org/team": "my-team",
+				"example.com/owner":                     "me",
+			}
+			appWorkload.Annotations["metadata.korifi.cloudfoundry.org/cost-center"] = "1234"
+		})
+
+		It("propagates it to the statefulset", func() {
+			Expect(statefulSet.Labels).To(HaveKeyWithValue("metadata.korifi.cloudfoundry.org/team", "my-team"))
+			Expect(statefulSet.Labels).NotTo(HaveKey("example.com/owner"))
+			Expect(statefulSet.Annotations).To(HaveKeyWithValue("metadata.korifi.cloudfoundry.org/cost-center", "1234"))
+		})
+
+		It("keeps it out of the pod template, so that changing it does not restart the instances", func() {
+			Expect(statefulSet.Spec.Template.Labels).NotTo(HaveKey("metadata.korifi.cloudfoundry.org/team"))
+			Expect(statefulSet.Spec.Template.Annotations).NotTo(HaveKey("metadata.korifi.cloudfoundry.org/cost-center"))
+		})
+
+		It("does not add it to the selector", func() {
+			Expect(statefulSet.Spec.Selector.MatchLabels).NotTo(HaveKey("metadata.korifi.cloudfoundry.org/team"))
+		})
+	})
+
+	When("a trusted CA config map is configured", func() {
+		BeforeEach(func() {
+			trustedCAConfigMapName = "trusted-ca-bundle"
+		})
+
+		It("mounts the bundle into the application container", func() {
+			Expect(statefulSet.Spec.Template.Spec.Volumes).To(ConsistOf(HaveField("ConfigMap.Name", "trusted-ca-bundle")))
+			Expect(statefulSet.Spec.Template.Spec.Containers[0].VolumeMounts).To(ConsistOf(HaveField("MountPath", "/etc/cf-system-certificates")))
+			Expect(statefulSet.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "CF_SYSTEM_CERT_PATH", Value: "/etc/cf-system-certificates"}))
+		})
+	})
+
+	When("the app workload has sidecars", func() {
+		BeforeEach(func() {
+			securityContext.ReadOnlyRootFilesystem = true
+			appWorkload.Spec.Sidecars = []korifiv1alpha1.AppWorkloadSidecar{{
+				Name:    "agent",
+				Command: []string{"/cnb/lifecycle/launcher", "run-agent"},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+				},
+			}}
+		})
+
+		It("runs them next to the application container, sharing its image, env and volumes", func() {
+			containers := statefulSet.Spec.Template.Spec.Containers
+			Expect(containers).To(HaveLen(2))
+			Expect(containers[1].Name).To(Equal("sidecar-agent"))
+			Expect(containers[1].Image).To(Equal(containers[0].Image))
+			Expect(containers[1].Command).To(Equal([]string{"/cnb/lifecycle/launcher", "run-agent"}))
+			Expect(containers[1].Env).To(Equal(containers[0].Env))
+			Expect(containers[1].VolumeMounts).To(Equal(containers[0].VolumeMounts))
+			Expect(containers[1].SecurityContext).To(Equal(containers[0].SecurityContext))
+			Expect(containers[1].Resources.Limits).To(HaveKeyWithValue(corev1.ResourceMemory, resource.MustParse("64Mi")))
+		})
+
+		It("does not probe them", func() {
+			Expect(statefulSet.Spec.Template.Spec.Containers[1].ReadinessProbe).To(BeNil())
+			Expect(statefulSet.Spec.Template.Spec.Containers[1].LivenessProbe).To(BeNil())
+			Expect(statefulSet.Spec.Template.Spec.Containers[1].Ports).To(BeEmpty())
+		})
+	})
+
 	It("should set the startup probe", func() {
 		Expect(statefulSet.Spec.Template.Spec.Containers[0].StartupProbe).To(Equal(appWorkload.Spec.StartupProbe))
 	})
@@ -191,6 +391,45 @@ var _ = Describe("AppWorkload to StatefulSet Converter", func() {
 		Expect(statefulSet.Spec.Template.Spec.Containers[0].LivenessProbe).To(Equal(appWorkload.Spec.LivenessProbe))
 	})
 
+	It("should set the readiness probe", func() {
+		Expect(statefulSet.Spec.Template.Spec.Containers[0].ReadinessProbe).To(Equal(appWorkload.Spec.ReadinessProbe))
+	})
+
+	It("should delay stopping the instances until they are taken out of the routing", func() {
+		Expect(statefulSet.Spec.Template.Spec.Containers[0].Lifecycle).To(Equal(&corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{
+					Command: []string{"sleep", strconv.Itoa(controllers.PreStopDelaySeconds)},
+				},
+			},
+		}))
+	})
+
+	It("should use the default termination grace period", func() {
+		Expect(statefulSet.Spec.Template.Spec.TerminationGracePeriodSeconds).To(BeNil())
+	})
+
+	When("the app workload has a termination grace period", func() {
+		BeforeEach(func() {
+			appWorkload.Spec.TerminationGracePeriodSeconds = tools.PtrTo[int64](10)
+		})
+
+		It("extends it with the pre-stop delay", func() {
+			Expect(statefulSet.Spec.Template.Spec.TerminationGracePeriodSeconds).To(PointTo(BeEquivalentTo(10 + controllers.PreStopDelaySeconds)))
+		})
+
+		When("the app workload has no ports", func() {
+			BeforeEach(func() {
+				appWorkload.Spec.Ports = nil
+			})
+
+			It("does not delay stopping the instances", func() {
+				Expect(statefulSet.Spec.Template.Spec.Containers[0].Lifecycle).To(BeNil())
+				Expect(statefulSet.Spec.Template.Spec.TerminationGracePeriodSeconds).To(PointTo(BeEquivalentTo(10)))
+			})
+		})
+	})
+
 	It("should not automount service account token", func() {
 		Expect(statefulSet.Spec.Template.Spec.AutomountServiceAccountToken).To(Equal(tools.PtrTo(false)))
 	})
@@ -287,17 +526,163 @@ var _ = Describe("AppWorkload to StatefulSet Converter", func() {
 		))
 	})
 
+	It("does not set topology spread constraints", func() {
+		Expect(statefulSet.Spec.Template.Spec.TopologySpreadConstraints).To(BeEmpty())
+	})
+
+	When("topology spread is configured", func() {
+		BeforeEach(func() {
+			topologySpread = config.TopologySpread{
+				TopologyKeys:      []string{"topology.kubernetes.io/zone", "kubernetes.io/hostname"},
+				MaxSkew:           2,
+				WhenUnsatisfiable: "DoNotSchedule",
+			}
+		})
+
+		It("spreads the instances across each topology domain", func() {
+			selector := &metav1.LabelSelector{
+				MatchLabels: map[string]string{controllers.LabelGUID: "guid_1234"},
+			}
+			Expect(statefulSet.Spec.Template.Spec.TopologySpreadConstraints).To(Equal([]corev1.TopologySpreadConstraint{
+				{
+					MaxSkew:           2,
+					TopologyKey:       "topology.kubernetes.io/zone",
+					WhenUnsatisfiable: corev1.DoNotSchedule,
+					LabelSelector:     selector,
+				},
+				{
+					MaxSkew:           2,
+					TopologyKey:       "kubernetes.io/hostname",
+					WhenUnsatisfiable: corev1.DoNotSchedule,
+					LabelSelector:     selector,
+				},
+			}))
+		})
+
+		When("the app opted out of topology spread", func() {
+			BeforeEach(func() {
+				appWorkload.Annotations[korifiv1alpha1.DisableTopologySpreadAnnotationKey] = "true"
+			})
+
+			It("does not set topology spread constraints", func() {
+				Expect(statefulSet.Spec.Template.Spec.TopologySpreadConstraints).To(BeEmpty())
+			})
+		})
+	})
+
+	It("does not set a node selector or tolerations", func() {
+		Expect(statefulSet.Spec.Template.Spec.NodeSelector).To(BeEmpty())
+		Expect(statefulSet.Spec.Template.Spec.Tolerations).To(BeEmpty())
+	})
+
+	When("node placement is configured", func() {
+		BeforeEach(func() {
+			nodePlacement = config.NodePlacement{
+				NodeSelector: map[string]string{"pool": "apps", "arch": "amd64"},
+				Tolerations: []config.Toleration{{
+					Key:               "dedicated",
+					Operator:          "Equal",
+					Value:             "apps",
+					Effect:            "NoExecute",
+					TolerationSeconds: tools.PtrTo(int64(60)),
+				}},
+			}
+		})
+
+		It("sets the node selector and tolerations", func() {
+			Expect(statefulSet.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"pool": "apps", "arch": "amd64"}))
+			Expect(statefulSet.Spec.Template.Spec.Tolerations).To(Equal([]corev1.Toleration{{
+				Key:               "dedicated",
+				Operator:          corev1.TolerationOpEqual,
+				Value:             "apps",
+				Effect:            corev1.TaintEffectNoExecute,
+				TolerationSeconds: tools.PtrTo(int64(60)),
+			}}))
+		})
+
+		When("the app workload has a node selector and tolerations", func() {
+			BeforeEach(func() {
+				appWorkload.Spec.NodeSelector = map[string]string{"pool": "tenant-a"}
+				appWorkload.Spec.Tolerations = []corev1.Toleration{{
+					Key:      "tenant",
+					Operator: corev1.TolerationOpExists,
+					Effect:   corev1.TaintEffectNoSchedule,
+				}}
+			})
+
+			It("merges them into the configured ones", func() {
+				Expect(statefulSet.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"pool": "tenant-a", "arch": "amd64"}))
+				Expect(statefulSet.Spec.Template.Spec.Tolerations).To(ConsistOf(
+					MatchFields(IgnoreExtras, Fields{"Key": Equal("dedicated")}),
+					MatchFields(IgnoreExtras, Fields{"Key": Equal("tenant")}),
+				))
+			})
+		})
+	})
+
+	It("does not set a priority or runtime class", func() {
+		Expect(statefulSet.Spec.Template.Spec.PriorityClassName).To(BeEmpty())
+		Expect(statefulSet.Spec.Template.Spec.RuntimeClassName).To(BeNil())
+	})
+
+	When("a priority and runtime class are configured", func() {
+		BeforeEach(func() {
+			priorityClassName = "apps"
+			runtimeClassName = "gvisor"
+		})
+
+		It("sets them on the pod template", func() {
+			Expect(statefulSet.Spec.Template.Spec.PriorityClassName).To(Equal("apps"))
+			Expect(statefulSet.Spec.Template.Spec.RuntimeClassName).To(PointTo(Equal("gvisor")))
+		})
+
+		When("the app workload has a priority and runtime class", func() {
+			BeforeEach(func() {
+				appWorkload.Spec.PriorityClassName = "tenant-priority"
+				appWorkload.Spec.RuntimeClassName = "kata"
+			})
+
+			It("prefers the ones of the app workload", func() {
+				Expect(statefulSet.Spec.Template.Spec.PriorityClassName).To(Equal("tenant-priority"))
+				Expect(statefulSet.Spec.Template.Spec.RuntimeClassName).To(PointTo(Equal("kata")))
+			})
+		})
+	})
+
 	It("should set the container environment variables", func() {
 		Expect(statefulSet.Spec.Template.Spec.Containers).To(HaveLen(1))
 		container := statefulSet.Spec.Template.Spec.Containers[0]
 		Expect(container.Env).To(ContainElements(
 			corev1.EnvVar{Name: controllers.EnvPodName, ValueFrom: expectedValFrom("metadata.name")},
 			corev1.EnvVar{Name: controllers.EnvCFInstanceGUID, ValueFrom: expectedValFrom("metadata.uid")},
+			corev1.EnvVar{Name: controllers.EnvInstanceGUID, ValueFrom: expectedValFrom("metadata.uid")},
+			corev1.EnvVar{Name: controllers.EnvCFInstanceIndex, ValueFrom: expectedValFrom("metadata.labels['apps.kubernetes.io/pod-index']")},
+			corev1.EnvVar{Name: controllers.EnvInstanceIndex, ValueFrom: expectedValFrom("metadata.labels['apps.kubernetes.io/pod-index']")},
 			corev1.EnvVar{Name: controllers.EnvCFInstanceInternalIP, ValueFrom: expectedValFrom("status.podIP")},
 			corev1.EnvVar{Name: controllers.EnvCFInstanceIP, ValueFrom: expectedValFrom("status.hostIP")},
 		))
 	})
 
+	It("sets the instance address to the internal IP and the first port", func() {
+		container := statefulSet.Spec.Template.Spec.Containers[0]
+		Expect(container.Env[len(container.Env)-1]).To(Equal(corev1.EnvVar{
+			Name:  controllers.EnvCFInstanceAddr,
+			Value: "$(CF_INSTANCE_INTERNAL_IP):8888",
+		}))
+	})
+
+	When("the app workload has no ports", func() {
+		BeforeEach(func() {
+			appWorkload.Spec.Ports = nil
+		})
+
+		It("does not set the instance address", func() {
+			Expect(statefulSet.Spec.Template.Spec.Containers[0].Env).NotTo(ContainElement(
+				MatchFields(IgnoreExtras, Fields{"Name": Equal(controllers.EnvCFInstanceAddr)}),
+			))
+		})
+	})
+
 	It("should set the container ports", func() {
 		Expect(statefulSet.Spec.Template.Spec.Containers).To(HaveLen(1))
 		container := statefulSet.Spec.Template.Spec.Containers[0]
@@ -359,10 +744,13 @@ var _ = Describe("AppWorkload to StatefulSet Converter", func() {
 				{Name: "CF_INSTANCE_INDEX", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.labels['apps.kubernetes.io/pod-index']"}}},
 				{Name: "CF_INSTANCE_INTERNAL_IP", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"}}},
 				{Name: "CF_INSTANCE_IP", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.hostIP"}}},
+				{Name: "INSTANCE_GUID", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.uid"}}},
+				{Name: "INSTANCE_INDEX", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.labels['apps.kubernetes.io/pod-index']"}}},
 				{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
 				{Name: "a-first", Value: "first"},
 				{Name: "b-second", Value: "second"},
 				{Name: "c-third", Value: "third"},
+				{Name: "CF_INSTANCE_ADDR", Value: "$(CF_INSTANCE_INTERNAL_IP):8888"},
 			}))
 		})
 	})
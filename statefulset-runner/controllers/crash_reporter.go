@@ -0,0 +1,238 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// AnnotationReportedCrashes on an app instance pod holds the restart count
+// of its application container when its last crash was reported
+const AnnotationReportedCrashes = "korifi.cloudfoundry.org/reported-crashes"
+
+// CrashReporter records an app.crash CFAuditEvent every time the application
+// container of an app instance restarts, e.g. because the app exited or failed
+// its liveness probe. The CFAuditEvent is the durable record of the crash, as
+// kubernetes deletes Events after an hour. It also records an AppCrash event
+// on the AppWorkload of the instance and an InstanceCrashed event on the
+// CFApp, so that the crash shows up in kubectl describe of the app.
+type CrashReporter struct {
+	k8sClient client.Client
+	recorder  record.EventRecorder
+	log       logr.Logger
+}
+
+func NewCrashReporter(k8sClient client.Client, recorder record.EventRecorder, log logr.Logger) *CrashReporter {
+	return &CrashReporter{
+		k8sClient: k8sClient,
+		recorder:  recorder,
+		log:       log,
+	}
+}
+
+func (r *CrashReporter) SetupWithManager(mgr ctrl.Manager) error {
+	// ignoring error as this construction is not dynamic
+	labelSelector, _ := predicate.LabelSelectorPredicate(metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      LabelAppWorkloadGUID,
+				Operator: metav1.LabelSelectorOpExists,
+				Values:   []string{},
+			},
+		},
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("crashreporter").
+		For(&corev1.Pod{}).
+		WithEventFilter(labelSelector).
+		Complete(r)
+}
+
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfapps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfauditevents,verbs=create
+
+func (r *CrashReporter) Reconcile(ctx context.Context, req reconcile.Request) (ctrl.Result, error) {
+	log := r.log.WithName("CrashReporter").
+		WithValues("namespace", req.Namespace).
+		WithValues("name", req.Name).
+		WithValues("logID", uuid.NewString())
+
+	pod := &corev1.Pod{}
+	err := r.k8sClient.Get(ctx, req.NamespacedName, pod)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Info("unable to fetch pod", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	containerStatus, ok := applicationContainerStatus(pod)
+	if !ok {
+		return ctrl.Result{}, nil
+	}
+
+	// an invalid annotation is treated as no crashes having been reported
+	reportedCrashes, _ := strconv.ParseInt(pod.Annotations[AnnotationReportedCrashes], 10, 32)
+	if int64(containerStatus.RestartCount) <= reportedCrashes {
+		return ctrl.Result{}, nil
+	}
+
+	appWorkload := &korifiv1alpha1.AppWorkload{}
+	err = r.k8sClient.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: pod.Labels[LabelAppWorkloadGUID]}, appWorkload)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Info("unable to fetch app workload", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	exitStatus, exitReason := lastTermination(containerStatus)
+	description := fmt.Sprintf("Instance %s exited with status %d (%s), crash count: %d", pod.Name, exitStatus, exitReason, containerStatus.RestartCount)
+	err = r.recordAuditEvent(ctx, pod, containerStatus.RestartCount, exitStatus, exitReason, description)
+	if err != nil {
+		log.Info("unable to record crash audit event", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	r.recorder.AnnotatedEventf(
+		appWorkload,
+		crashAnnotations(pod, containerStatus.RestartCount, exitStatus, exitReason),
+		corev1.EventTypeWarning,
+		korifiv1alpha1.AppCrashEventReason,
+		"%s", description,
+	)
+	log.V(1).Info("reported crash", "restartCount", containerStatus.RestartCount, "exitStatus", exitStatus)
+
+	r.recordAppCrash(ctx, log, pod, exitStatus, exitReason)
+
+	err = k8s.PatchResource(ctx, r.k8sClient, pod, func() {
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[AnnotationReportedCrashes] = strconv.Itoa(int(containerStatus.RestartCount))
+	})
+	if err != nil {
+		log.Info("unable to record reported crashes on pod", "reason", err)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// recordAuditEvent records the crash as an app.crash audit event in the
+// namespace of the app. Its name is derived from the pod and the restart
+// count, so that a retried reconcile does not record the crash twice.
+func (r *CrashReporter) recordAuditEvent(ctx context.Context, pod *corev1.Pod, crashCount, exitStatus int32, exitReason, description string) error {
+	// an invalid index label is recorded as the first instance, as the runners
+	// set the label themselves
+	index, _ := strconv.ParseInt(pod.Labels[korifiv1alpha1.PodIndexLabelKey], 10, 32)
+
+	err := r.k8sClient.Create(ctx, &korifiv1alpha1.CFAuditEvent{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: pod.Namespace,
+			Name:      uuid.NewSHA1(uuid.NameSpaceOID, []byte(fmt.Sprintf("%s/%d", pod.UID, crashCount))).String(),
+			Labels: map[string]string{
+				korifiv1alpha1.CFAppGUIDLabelKey: pod.Labels[LabelAppGUID],
+			},
+		},
+		Spec: korifiv1alpha1.CFAuditEventSpec{
+			Type:       korifiv1alpha1.AuditEventTypeAppCrash,
+			ActorGUID:  pod.Labels[LabelGUID],
+			ActorType:  "process",
+			ActorName:  pod.Labels[LabelProcessType],
+			TargetGUID: pod.Labels[LabelAppGUID],
+			TargetType: "app",
+			AppCrash: &korifiv1alpha1.AppCrash{
+				InstanceName:    pod.Name,
+				InstanceIndex:   int32(index),
+				ExitStatus:      exitStatus,
+				ExitReason:      exitReason,
+				ExitDescription: description,
+				CrashCount:      crashCount,
+			},
+		},
+	})
+	if k8serrors.IsAlreadyExists(err) {
+		return nil
+	}
+
+	return err
+}
+
+// recordAppCrash records the crash on the CFApp of the instance. Failing to do
+// so is only logged, as the crash has already been reported.
+func (r *CrashReporter) recordAppCrash(ctx context.Context, log logr.Logger, pod *corev1.Pod, exitStatus int32, exitReason string) {
+	cfApp := &korifiv1alpha1.CFApp{}
+	err := r.k8sClient.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: pod.Labels[LabelAppGUID]}, cfApp)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			log.Info("unable to fetch cf app", "reason", err)
+		}
+		return
+	}
+
+	r.recorder.Eventf(
+		cfApp,
+		corev1.EventTypeWarning,
+		"InstanceCrashed",
+		"Instance %s of process %s exited with status %d (%s)",
+		pod.Name, pod.Labels[LabelProcessType], exitStatus, exitReason,
+	)
+}
+
+func applicationContainerStatus(pod *corev1.Pod) (corev1.ContainerStatus, bool) {
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name == ApplicationContainerName {
+			return containerStatus, true
+		}
+	}
+
+	return corev1.ContainerStatus{}, false
+}
+
+func lastTermination(containerStatus corev1.ContainerStatus) (int32, string) {
+	terminated := containerStatus.LastTerminationState.Terminated
+	if terminated == nil {
+		return 0, "Unknown"
+	}
+
+	return terminated.ExitCode, terminated.Reason
+}
+
+func crashAnnotations(pod *corev1.Pod, crashCount, exitStatus int32, exitReason string) map[string]string {
+	annotations := map[string]string{
+		korifiv1alpha1.CFAppGUIDLabelKey:              pod.Labels[LabelAppGUID],
+		korifiv1alpha1.CFProcessGUIDLabelKey:          pod.Labels[LabelGUID],
+		korifiv1alpha1.CFProcessTypeLabelKey:          pod.Labels[LabelProcessType],
+		korifiv1alpha1.CrashInstanceNameAnnotationKey: pod.Name,
+		korifiv1alpha1.CrashExitStatusAnnotationKey:   fmt.Sprint(exitStatus),
+		korifiv1alpha1.CrashExitReasonAnnotationKey:   exitReason,
+		korifiv1alpha1.CrashCountAnnotationKey:        fmt.Sprint(crashCount),
+	}
+
+	if index, ok := pod.Labels[korifiv1alpha1.PodIndexLabelKey]; ok {
+		annotations[korifiv1alpha1.CrashInstanceIndexAnnotationKey] = index
+	}
+
+	return annotations
+}
@@ -0,0 +1,222 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/statefulset-runner/controllers"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("CrashReporter", func() {
+	var (
+		reconciler     *controllers.CrashReporter
+		recorder       *record.FakeRecorder
+		reconcileErr   error
+		ctx            context.Context
+		pod            *corev1.Pod
+		appWorkload    *korifiv1alpha1.AppWorkload
+		getWorkloadErr error
+		getAppErr      error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		recorder = record.NewFakeRecorder(10)
+
+		appWorkload = &korifiv1alpha1.AppWorkload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      uuid.NewString(),
+				Namespace: uuid.NewString(),
+			},
+		}
+		getWorkloadErr = nil
+		getAppErr = nil
+
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-app-web-1",
+				Namespace: appWorkload.Namespace,
+				Labels: map[string]string{
+					controllers.LabelAppWorkloadGUID: appWorkload.Name,
+					controllers.LabelAppGUID:         "app-guid",
+					controllers.LabelGUID:            "process-guid",
+					controllers.LabelProcessType:     "web",
+					korifiv1alpha1.PodIndexLabelKey:  "1",
+				},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:         controllers.ApplicationContainerName,
+					RestartCount: 2,
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 137,
+							Reason:   "OOMKilled",
+						},
+					},
+				}},
+			},
+		}
+
+		fakeClient.GetStub = func(_ context.Context, _ types.NamespacedName, obj client.Object, _ ...client.GetOption) error {
+			switch obj := obj.(type) {
+			case *corev1.Pod:
+				pod.DeepCopyInto(obj)
+				return nil
+			case *korifiv1alpha1.AppWorkload:
+				appWorkload.DeepCopyInto(obj)
+				return getWorkloadErr
+			case *korifiv1alpha1.CFApp:
+				obj.Name = "app-guid"
+				obj.Namespace = appWorkload.Namespace
+				return getAppErr
+			default:
+				panic("TestClient Get provided an unexpected object type")
+			}
+		}
+
+		reconciler = controllers.NewCrashReporter(fakeClient, recorder, ctrl.Log.WithName("controllers").WithName("TestCrashReporter"))
+	})
+
+	JustBeforeEach(func() {
+		_, reconcileErr = reconciler.Reconcile(ctx, ctrl.Request{
+			NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name},
+		})
+	})
+
+	It("records a crash audit event", func() {
+		Expect(reconcileErr).NotTo(HaveOccurred())
+		Expect(fakeClient.CreateCallCount()).To(Equal(1))
+		_, obj, _ := fakeClient.CreateArgsForCall(0)
+		auditEvent, ok := obj.(*korifiv1alpha1.CFAuditEvent)
+		Expect(ok).To(BeTrue())
+		Expect(auditEvent.Namespace).To(Equal(pod.Namespace))
+		Expect(auditEvent.Labels).To(HaveKeyWithValue(korifiv1alpha1.CFAppGUIDLabelKey, "app-guid"))
+		Expect(auditEvent.Spec).To(Equal(korifiv1alpha1.CFAuditEventSpec{
+			Type:       korifiv1alpha1.AuditEventTypeAppCrash,
+			ActorGUID:  "process-guid",
+			ActorType:  "process",
+			ActorName:  "web",
+			TargetGUID: "app-guid",
+			TargetType: "app",
+			AppCrash: &korifiv1alpha1.AppCrash{
+				InstanceName:    "my-app-web-1",
+				InstanceIndex:   1,
+				ExitStatus:      137,
+				ExitReason:      "OOMKilled",
+				ExitDescription: "Instance my-app-web-1 exited with status 137 (OOMKilled), crash count: 2",
+				CrashCount:      2,
+			},
+		}))
+	})
+
+	When("the crash audit event has already been recorded", func() {
+		BeforeEach(func() {
+			fakeClient.CreateReturns(apierrors.NewAlreadyExists(schema.GroupResource{}, "audit-event"))
+		})
+
+		It("reports the crash", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("AppCrash")))
+			Expect(fakeClient.PatchCallCount()).To(Equal(1))
+		})
+	})
+
+	When("recording the crash audit event fails", func() {
+		BeforeEach(func() {
+			fakeClient.CreateReturns(errors.New("create-error"))
+		})
+
+		It("returns the error without recording the crash as reported", func() {
+			Expect(reconcileErr).To(MatchError("create-error"))
+			Expect(recorder.Events).NotTo(Receive())
+			Expect(fakeClient.PatchCallCount()).To(BeZero())
+		})
+	})
+
+	It("records a crash event on the app workload", func() {
+		Expect(reconcileErr).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(SatisfyAll(
+			ContainSubstring("Warning AppCrash Instance my-app-web-1 exited with status 137 (OOMKilled), crash count: 2"),
+			ContainSubstring("korifi.cloudfoundry.org/app-guid:app-guid"),
+			ContainSubstring("korifi.cloudfoundry.org/process-guid:process-guid"),
+			ContainSubstring("korifi.cloudfoundry.org/instance-index:1"),
+			ContainSubstring("korifi.cloudfoundry.org/exit-status:137"),
+		)))
+	})
+
+	It("records a crash event on the app", func() {
+		Expect(reconcileErr).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive())
+		Expect(recorder.Events).To(Receive(
+			Equal("Warning InstanceCrashed Instance my-app-web-1 of process web exited with status 137 (OOMKilled)"),
+		))
+	})
+
+	When("the app does not exist", func() {
+		BeforeEach(func() {
+			getAppErr = apierrors.NewNotFound(schema.GroupResource{}, "app-guid")
+		})
+
+		It("only records the crash event on the app workload", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("AppCrash")))
+			Expect(recorder.Events).NotTo(Receive())
+		})
+	})
+
+	It("records the restart count on the pod", func() {
+		Expect(reconcileErr).NotTo(HaveOccurred())
+		Expect(fakeClient.PatchCallCount()).To(Equal(1))
+		_, obj, _, _ := fakeClient.PatchArgsForCall(0)
+		Expect(obj.GetAnnotations()).To(HaveKeyWithValue(controllers.AnnotationReportedCrashes, "2"))
+	})
+
+	When("the crashes have already been reported", func() {
+		BeforeEach(func() {
+			pod.Annotations = map[string]string{controllers.AnnotationReportedCrashes: "2"}
+		})
+
+		It("does not record an event", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive())
+			Expect(fakeClient.CreateCallCount()).To(BeZero())
+			Expect(fakeClient.PatchCallCount()).To(BeZero())
+		})
+	})
+
+	When("the application container has not restarted", func() {
+		BeforeEach(func() {
+			pod.Status.ContainerStatuses[0].RestartCount = 0
+		})
+
+		It("does not record an event", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive())
+		})
+	})
+
+	When("the app workload does not exist", func() {
+		BeforeEach(func() {
+			getWorkloadErr = apierrors.NewNotFound(schema.GroupResource{}, appWorkload.Name)
+		})
+
+		It("does not record an event", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive())
+		})
+	})
+})
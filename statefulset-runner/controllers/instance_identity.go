@@ -0,0 +1,249 @@
+package controllers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"code.cloudfoundry.org/korifi/controllers/config"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	InstanceIdentityVolumeName = "instance-identity"
+	InstanceIdentityMountPath  = "/etc/cf-instance-credentials"
+
+	EnvCFInstanceCert = "CF_INSTANCE_CERT"
+	EnvCFInstanceKey  = "CF_INSTANCE_KEY"
+
+	LabelInstanceIdentity            = "korifi.cloudfoundry.org/instance-identity"
+	LabelInstanceIdentityStatefulSet = "korifi.cloudfoundry.org/instance-identity-statefulset"
+	LabelInstanceIdentityIndex       = "korifi.cloudfoundry.org/instance-identity-index"
+)
+
+// InstanceIdentitySecretName is the name of the secret holding the instance
+// identity certificate of a single pod. The pods only ever mount their own
+// secret, which the instance identity webhook sets on pod creation.
+func InstanceIdentitySecretName(podName string) string {
+	return podName + "-instance-identity"
+}
+
+// InstanceIdentityIssuer issues short-lived certificates identifying each
+// instance of an app, signed by the configured CA. As the statefulset pod
+// names are stable, the certificate of each instance index is issued for its
+// pod name into a secret of its own, so that each pod only mounts its own
+// private key, and renewed once two thirds of its validity have passed.
+type InstanceIdentityIssuer struct {
+	client   client.Client
+	caSecret types.NamespacedName
+	validity time.Duration
+}
+
+func NewInstanceIdentityIssuer(client client.Client, instanceIdentityConfig config.InstanceIdentity, validity time.Duration) *InstanceIdentityIssuer {
+	return &InstanceIdentityIssuer{
+		client: client,
+		caSecret: types.NamespacedName{
+			Namespace: instanceIdentityConfig.CASecretNamespace,
+			Name:      instanceIdentityConfig.CASecretName,
+		},
+		validity: validity,
+	}
+}
+
+// Update issues the certificates of the statefulset instances that do not
+// have a valid one yet, deletes the secrets of the instances that are gone and
+// returns how long until the earliest certificate needs renewing. It does
+// nothing when instance identity is not configured.
+func (i *InstanceIdentityIssuer) Update(ctx context.Context, statefulSet *appsv1.StatefulSet) (time.Duration, error) {
+	if i.caSecret.Name == "" {
+		return 0, nil
+	}
+
+	ca, err := i.loadCA(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	renewAfter := i.validity
+	for index := range *statefulSet.Spec.Replicas {
+		var notAfter time.Time
+		notAfter, err = i.updateInstance(ctx, ca, statefulSet, index, now)
+		if err != nil {
+			return 0, err
+		}
+
+		renewAfter = min(renewAfter, renewalTime(notAfter, i.validity).Sub(now))
+	}
+
+	if err = i.deleteStaleInstances(ctx, statefulSet); err != nil {
+		return 0, err
+	}
+
+	return renewAfter, nil
+}
+
+// updateInstance makes sure the secret of the instance holds a valid
+// certificate and returns its expiry
+func (i *InstanceIdentityIssuer) updateInstance(ctx context.Context, ca tls.Certificate, statefulSet *appsv1.StatefulSet, index int32, now time.Time) (time.Time, error) {
+	podName := fmt.Sprintf("%s-%d", statefulSet.Name, index)
+	identitySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      InstanceIdentitySecretName(podName),
+			Namespace: statefulSet.Namespace,
+		},
+	}
+
+	var notAfter time.Time
+	_, err := controllerutil.CreateOrPatch(ctx, i.client, identitySecret, func() error {
+		identitySecret.Labels = map[string]string{
+			LabelInstanceIdentityStatefulSet: statefulSet.Name,
+			LabelInstanceIdentityIndex:       strconv.Itoa(int(index)),
+		}
+
+		var valid bool
+		notAfter, valid = validUntil(identitySecret.Data[corev1.TLSCertKey], ca.Leaf)
+		if !valid || now.After(renewalTime(notAfter, i.validity)) {
+			certPEM, keyPEM, err := i.issue(ca, statefulSet, podName, now)
+			if err != nil {
+				return err
+			}
+
+			identitySecret.Data = map[string][]byte{
+				corev1.TLSCertKey:       certPEM,
+				corev1.TLSPrivateKeyKey: keyPEM,
+			}
+			notAfter = now.Add(i.validity)
+		}
+
+		return controllerutil.SetControllerReference(statefulSet, identitySecret, scheme.Scheme)
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to create or patch instance identity secret %q: %w", identitySecret.Name, err)
+	}
+
+	return notAfter, nil
+}
+
+func (i *InstanceIdentityIssuer) deleteStaleInstances(ctx context.Context, statefulSet *appsv1.StatefulSet) error {
+	identitySecrets := &corev1.SecretList{}
+	err := i.client.List(ctx, identitySecrets,
+		client.InNamespace(statefulSet.Namespace),
+		client.MatchingLabels{LabelInstanceIdentityStatefulSet: statefulSet.Name},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to list instance identity secrets: %w", err)
+	}
+
+	for _, identitySecret := range identitySecrets.Items {
+		index, err := strconv.Atoi(identitySecret.Labels[LabelInstanceIdentityIndex])
+		if err == nil && index < int(*statefulSet.Spec.Replicas) {
+			continue
+		}
+
+		if err = i.client.Delete(ctx, &identitySecret); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete instance identity secret %q: %w", identitySecret.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (i *InstanceIdentityIssuer) loadCA(ctx context.Context) (tls.Certificate, error) {
+	caSecret := &corev1.Secret{}
+	if err := i.client.Get(ctx, i.caSecret, caSecret); err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to get instance identity CA secret %q: %w", i.caSecret, err)
+	}
+
+	ca, err := tls.X509KeyPair(caSecret.Data[corev1.TLSCertKey], caSecret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to parse instance identity CA: %w", err)
+	}
+
+	return ca, nil
+}
+
+func (i *InstanceIdentityIssuer) issue(ca tls.Certificate, statefulSet *appsv1.StatefulSet, podName string, now time.Time) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate instance identity key: %w", err)
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate instance identity serial number: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			CommonName: podName,
+			OrganizationalUnit: []string{
+				"app:" + statefulSet.Labels[LabelAppGUID],
+				"space:" + statefulSet.Namespace,
+			},
+		},
+		DNSNames:    []string{podName},
+		NotBefore:   now.Add(-time.Minute),
+		NotAfter:    now.Add(i.validity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, ca.Leaf, key.Public(), ca.PrivateKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create instance identity certificate: %w", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal instance identity key: %w", err)
+	}
+
+	// the certificate is followed by the CA, so that peers only need to
+	// trust the CA
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Leaf.Raw})...)
+
+	return certPEM, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// validUntil returns the expiry of the certificate, unless it is missing or
+// it is not signed by the current CA, e.g. because the CA has been rotated
+func validUntil(certPEM []byte, ca *x509.Certificate) (time.Time, bool) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return time.Time{}, false
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	if cert.CheckSignatureFrom(ca) != nil {
+		return time.Time{}, false
+	}
+
+	return cert.NotAfter, true
+}
+
+func renewalTime(notAfter time.Time, validity time.Duration) time.Time {
+	return notAfter.Add(-validity / 3)
+}
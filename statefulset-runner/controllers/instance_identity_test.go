@@ -0,0 +1,293 @@
+package controllers_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"time"
+
+	"code.cloudfoundry.org/korifi/controllers/config"
+	"code.cloudfoundry.org/korifi/statefulset-runner/controllers"
+	"code.cloudfoundry.org/korifi/statefulset-runner/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("InstanceIdentityIssuer", func() {
+	var (
+		ctx                    context.Context
+		instanceIdentityConfig config.InstanceIdentity
+		stSet                  *appsv1.StatefulSet
+		instances              int32
+		caSecret               *corev1.Secret
+		caCert                 *x509.Certificate
+		existingSecrets        map[string]*corev1.Secret
+		getCASecretErr         error
+		renewAfter             time.Duration
+		updateErr              error
+	)
+
+	generateCA := func() (*corev1.Secret, *x509.Certificate) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: "instance-identity-ca"},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(24 * time.Hour),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}
+		certDER, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+		Expect(err).NotTo(HaveOccurred())
+		cert, err := x509.ParseCertificate(certDER)
+		Expect(err).NotTo(HaveOccurred())
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		Expect(err).NotTo(HaveOccurred())
+
+		return &corev1.Secret{
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+				corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+			},
+		}, cert
+	}
+
+	parseCert := func(certPEM []byte) *x509.Certificate {
+		block, _ := pem.Decode(certPEM)
+		Expect(block).NotTo(BeNil())
+		cert, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+
+		return cert
+	}
+
+	// written returns the secrets created or patched by the issuer, by name
+	written := func() map[string]*corev1.Secret {
+		secrets := map[string]*corev1.Secret{}
+		for i := range fakeClient.CreateCallCount() {
+			_, obj, _ := fakeClient.CreateArgsForCall(i)
+			secrets[obj.GetName()] = obj.(*corev1.Secret)
+		}
+		for i := range fakeClient.PatchCallCount() {
+			_, obj, _, _ := fakeClient.PatchArgsForCall(i)
+			secrets[obj.GetName()] = obj.(*corev1.Secret)
+		}
+
+		return secrets
+	}
+
+	deleted := func() []string {
+		names := []string{}
+		for i := range fakeClient.DeleteCallCount() {
+			_, obj, _ := fakeClient.DeleteArgsForCall(i)
+			names = append(names, obj.GetName())
+		}
+
+		return names
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		instanceIdentityConfig = config.InstanceIdentity{
+			CASecretName:      "instance-identity-ca",
+			CASecretNamespace: "korifi",
+		}
+		instances = 2
+		existingSecrets = map[string]*corev1.Secret{}
+		getCASecretErr = nil
+		caSecret, caCert = generateCA()
+
+		stSet = &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "name",
+				Namespace: "namespace",
+				UID:       "uid",
+				Labels: map[string]string{
+					controllers.LabelAppGUID: "app-guid",
+				},
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: &instances,
+			},
+		}
+
+		fakeClient.GetStub = func(_ context.Context, key types.NamespacedName, obj client.Object, _ ...client.GetOption) error {
+			if key == (types.NamespacedName{Namespace: "korifi", Name: "instance-identity-ca"}) {
+				if getCASecretErr != nil {
+					return getCASecretErr
+				}
+				caSecret.DeepCopyInto(obj.(*corev1.Secret))
+				return nil
+			}
+
+			existingSecret, ok := existingSecrets[key.Name]
+			if !ok {
+				return k8serrors.NewNotFound(schema.GroupResource{}, key.Name)
+			}
+			existingSecret.DeepCopyInto(obj.(*corev1.Secret))
+			return nil
+		}
+
+		fakeClient.ListStub = func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+			secretList := list.(*corev1.SecretList)
+			for _, existingSecret := range existingSecrets {
+				secretList.Items = append(secretList.Items, *existingSecret.DeepCopy())
+			}
+			return nil
+		}
+	})
+
+	JustBeforeEach(func() {
+		renewAfter, updateErr = controllers.NewInstanceIdentityIssuer(fakeClient, instanceIdentityConfig, 3*time.Hour).Update(ctx, stSet)
+	})
+
+	It("creates an instance identity secret per instance", func() {
+		Expect(updateErr).NotTo(HaveOccurred())
+
+		secrets := written()
+		Expect(secrets).To(HaveLen(2))
+		Expect(secrets).To(HaveKey("name-0-instance-identity"))
+
+		secret := secrets["name-1-instance-identity"]
+		Expect(secret.Namespace).To(Equal("namespace"))
+		Expect(secret.Labels).To(Equal(map[string]string{
+			controllers.LabelInstanceIdentityStatefulSet: "name",
+			controllers.LabelInstanceIdentityIndex:       "1",
+		}))
+		Expect(secret.OwnerReferences).To(ConsistOf(HaveField("UID", stSet.UID)))
+		Expect(secret.Data).To(HaveLen(2))
+	})
+
+	It("issues the certificate of the instance, signed by the CA", func() {
+		Expect(updateErr).NotTo(HaveOccurred())
+
+		secret := written()["name-1-instance-identity"]
+		cert := parseCert(secret.Data["tls.crt"])
+		Expect(cert.Subject.CommonName).To(Equal("name-1"))
+		Expect(cert.Subject.OrganizationalUnit).To(ConsistOf("app:app-guid", "space:namespace"))
+		Expect(cert.DNSNames).To(ConsistOf("name-1"))
+		Expect(cert.ExtKeyUsage).To(ConsistOf(x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth))
+		Expect(cert.NotAfter).To(BeTemporally("~", time.Now().Add(3*time.Hour), time.Minute))
+		Expect(cert.CheckSignatureFrom(caCert)).To(Succeed())
+
+		_, rest := pem.Decode(secret.Data["tls.crt"])
+		Expect(parseCert(rest).Equal(caCert)).To(BeTrue())
+
+		block, _ := pem.Decode(secret.Data["tls.key"])
+		Expect(block).NotTo(BeNil())
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(key.PublicKey.Equal(cert.PublicKey)).To(BeTrue())
+	})
+
+	It("renews the certificates after two thirds of their validity", func() {
+		Expect(renewAfter).To(BeNumerically("~", 2*time.Hour, time.Minute))
+	})
+
+	When("the instances already have valid certificates", func() {
+		BeforeEach(func() {
+			setupClient := new(fake.Client)
+			setupClient.GetStub = fakeClient.GetStub
+			setupClient.ListStub = fakeClient.ListStub
+
+			instances = 3
+			_, err := controllers.NewInstanceIdentityIssuer(setupClient, instanceIdentityConfig, 3*time.Hour).Update(ctx, stSet)
+			Expect(err).NotTo(HaveOccurred())
+			for i := range setupClient.CreateCallCount() {
+				_, obj, _ := setupClient.CreateArgsForCall(i)
+				existingSecrets[obj.GetName()] = obj.(*corev1.Secret).DeepCopy()
+			}
+			instances = 2
+		})
+
+		It("keeps them", func() {
+			Expect(updateErr).NotTo(HaveOccurred())
+
+			Expect(written()).To(BeEmpty())
+		})
+
+		It("deletes the secrets of the instances that are gone", func() {
+			Expect(updateErr).NotTo(HaveOccurred())
+			Expect(deleted()).To(ConsistOf("name-2-instance-identity"))
+		})
+
+		When("the CA has been rotated", func() {
+			BeforeEach(func() {
+				caSecret, caCert = generateCA()
+			})
+
+			It("issues new certificates signed by the new CA", func() {
+				Expect(updateErr).NotTo(HaveOccurred())
+
+				cert := parseCert(written()["name-0-instance-identity"].Data["tls.crt"])
+				Expect(cert.CheckSignatureFrom(caCert)).To(Succeed())
+			})
+		})
+	})
+
+	When("getting the CA secret fails", func() {
+		BeforeEach(func() {
+			getCASecretErr = errors.New("get-ca-error")
+		})
+
+		It("returns an error", func() {
+			Expect(updateErr).To(MatchError(ContainSubstring("get-ca-error")))
+		})
+	})
+
+	When("creating the instance identity secret fails", func() {
+		BeforeEach(func() {
+			fakeClient.CreateReturns(errors.New("create-error"))
+		})
+
+		It("returns an error", func() {
+			Expect(updateErr).To(MatchError(ContainSubstring("create-error")))
+		})
+	})
+
+	When("deleting a stale instance identity secret fails", func() {
+		BeforeEach(func() {
+			existingSecrets["name-5-instance-identity"] = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "name-5-instance-identity",
+					Labels: map[string]string{controllers.LabelInstanceIdentityIndex: "5"},
+				},
+			}
+			fakeClient.DeleteReturns(errors.New("delete-error"))
+		})
+
+		It("returns an error", func() {
+			Expect(updateErr).To(MatchError(ContainSubstring("delete-error")))
+		})
+	})
+
+	When("instance identity is disabled", func() {
+		BeforeEach(func() {
+			instanceIdentityConfig = config.InstanceIdentity{}
+		})
+
+		It("does nothing", func() {
+			Expect(updateErr).NotTo(HaveOccurred())
+			Expect(renewAfter).To(BeZero())
+			Expect(fakeClient.GetCallCount()).To(BeZero())
+			Expect(fakeClient.CreateCallCount()).To(BeZero())
+		})
+	})
+})
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/config"
 	. "code.cloudfoundry.org/korifi/statefulset-runner/controllers"
 	"code.cloudfoundry.org/korifi/tests/helpers"
 	"go.uber.org/zap/zapcore"
@@ -74,8 +75,9 @@ var _ = BeforeSuite(func() {
 	appWorkloadReconciler := NewAppWorkloadReconciler(
 		k8sManager.GetClient(),
 		k8sManager.GetScheme(),
-		NewAppWorkloadToStatefulsetConverter(k8sManager.GetScheme(), false),
-		NewPDBUpdater(k8sManager.GetClient()),
+		NewAppWorkloadToStatefulsetConverter(k8sManager.GetScheme(), false, config.TopologySpread{}, config.NodePlacement{}, "", "", config.SecurityContext{}, false, ""),
+		NewPDBUpdater(k8sManager.GetClient(), config.PodDisruptionBudget{MinAvailable: "50%"}),
+		NewInstanceIdentityIssuer(k8sManager.GetClient(), config.InstanceIdentity{}, 0),
 		ctrl.Log.WithName("statefulset-runner").WithName("AppWorkload"),
 	)
 	err = appWorkloadReconciler.SetupWithManager(k8sManager)
@@ -4,9 +4,9 @@ import (
 	"context"
 	"fmt"
 
-	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"code.cloudfoundry.org/korifi/controllers/config"
+	"code.cloudfoundry.org/korifi/tools"
 
-	appsv1 "k8s.io/api/apps/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -15,64 +15,72 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-const PdbMinAvailableInstances = "50%"
-
+// PDBUpdater keeps a pod disruption budget for workloads with more than one
+// instance, so that draining nodes does not stop all instances of an app at
+// once
 type PDBUpdater struct {
 	client client.Client
+	config config.PodDisruptionBudget
 }
 
-func NewPDBUpdater(client client.Client) *PDBUpdater {
+func NewPDBUpdater(client client.Client, pdbConfig config.PodDisruptionBudget) *PDBUpdater {
 	return &PDBUpdater{
 		client: client,
+		config: pdbConfig,
 	}
 }
 
-func (c *PDBUpdater) Update(ctx context.Context, statefulSet *appsv1.StatefulSet) error {
-	if *statefulSet.Spec.Replicas > 1 {
-		return c.createPDB(ctx, statefulSet)
+func (c *PDBUpdater) Update(ctx context.Context, workload client.Object, replicas int32, selector *metav1.LabelSelector) error {
+	if replicas > 1 {
+		return c.createOrPatchPDB(ctx, workload, selector)
 	}
 
-	return c.deletePDB(ctx, statefulSet)
+	return c.deletePDB(ctx, workload)
 }
 
-func (c *PDBUpdater) createPDB(ctx context.Context, statefulSet *appsv1.StatefulSet) error {
-	minAvailable := intstr.FromString(PdbMinAvailableInstances)
-
+func (c *PDBUpdater) createOrPatchPDB(ctx context.Context, workload client.Object, selector *metav1.LabelSelector) error {
 	pdb := &policyv1.PodDisruptionBudget{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      statefulSet.Name,
-			Namespace: statefulSet.Namespace,
-			Labels: map[string]string{
-				LabelGUID:    statefulSet.Labels[LabelGUID],
-				LabelVersion: statefulSet.Labels[LabelVersion],
-			},
-		},
-		Spec: policyv1.PodDisruptionBudgetSpec{
-			MinAvailable: &minAvailable,
-			Selector:     statefulSet.Spec.Selector,
+			Name:      workload.GetName(),
+			Namespace: workload.GetNamespace(),
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(statefulSet, pdb, scheme.Scheme); err != nil {
-		return fmt.Errorf("pdb updater failed to set owner ref: %w", err)
-	}
+	_, err := controllerutil.CreateOrPatch(ctx, c.client, pdb, func() error {
+		pdb.Labels = map[string]string{
+			LabelGUID:    workload.GetLabels()[LabelGUID],
+			LabelVersion: workload.GetLabels()[LabelVersion],
+		}
+		pdb.Spec.Selector = selector
+		pdb.Spec.MinAvailable = intOrStringOrNil(c.config.MinAvailable)
+		pdb.Spec.MaxUnavailable = intOrStringOrNil(c.config.MaxUnavailable)
 
-	err := c.client.Create(ctx, pdb)
-	if err != nil {
-		if k8serrors.IsAlreadyExists(err) {
-			return nil
+		if err := controllerutil.SetControllerReference(workload, pdb, scheme.Scheme); err != nil {
+			return fmt.Errorf("pdb updater failed to set owner ref: %w", err)
 		}
-		return fmt.Errorf("failed to create pod distruption budget: %w", err)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or patch pod distruption budget: %w", err)
 	}
 
 	return nil
 }
 
-func (c *PDBUpdater) deletePDB(ctx context.Context, statefulSet *appsv1.StatefulSet) error {
-	err := c.client.DeleteAllOf(ctx, &policyv1.PodDisruptionBudget{}, client.InNamespace(statefulSet.Namespace), client.MatchingFields{"metadata.name": statefulSet.Name})
+func (c *PDBUpdater) deletePDB(ctx context.Context, workload client.Object) error {
+	err := c.client.DeleteAllOf(ctx, &policyv1.PodDisruptionBudget{}, client.InNamespace(workload.GetNamespace()), client.MatchingFields{"metadata.name": workload.GetName()})
 	if err != nil {
 		return fmt.Errorf("failed to delete pod distruption budget: %w", err)
 	}
 
 	return nil
 }
+
+func intOrStringOrNil(value string) *intstr.IntOrString {
+	if value == "" {
+		return nil
+	}
+
+	return tools.PtrTo(intstr.Parse(value))
+}
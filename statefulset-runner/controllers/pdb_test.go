@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"code.cloudfoundry.org/korifi/controllers/config"
 	"code.cloudfoundry.org/korifi/statefulset-runner/controllers"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -14,20 +15,24 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("PDB", func() {
 	var (
-		creator   *controllers.PDBUpdater
-		stSet     *appsv1.StatefulSet
-		ctx       context.Context
-		instances int32
+		pdbConfig   config.PodDisruptionBudget
+		stSet       *appsv1.StatefulSet
+		ctx         context.Context
+		instances   int32
+		existingPDB *policyv1.PodDisruptionBudget
 	)
 
 	BeforeEach(func() {
-		creator = controllers.NewPDBUpdater(fakeClient)
+		pdbConfig = config.PodDisruptionBudget{MinAvailable: "50%"}
 		instances = 2
+		existingPDB = nil
 
 		stSet = &appsv1.StatefulSet{
 			ObjectMeta: metav1.ObjectMeta{
@@ -50,13 +55,22 @@ var _ = Describe("PDB", func() {
 			},
 		}
 
+		fakeClient.GetStub = func(_ context.Context, _ types.NamespacedName, obj client.Object, _ ...client.GetOption) error {
+			if existingPDB == nil {
+				return k8serrors.NewNotFound(schema.GroupResource{}, "pdb")
+			}
+
+			existingPDB.DeepCopyInto(obj.(*policyv1.PodDisruptionBudget))
+			return nil
+		}
+
 		ctx = context.Background()
 	})
 
 	Describe("Update", func() {
 		var updateErr error
 		JustBeforeEach(func() {
-			updateErr = creator.Update(ctx, stSet)
+			updateErr = controllers.NewPDBUpdater(fakeClient, pdbConfig).Update(ctx, stSet, *stSet.Spec.Replicas, stSet.Spec.Selector)
 		})
 
 		It("succeeds", func() {
@@ -74,6 +88,7 @@ var _ = Describe("PDB", func() {
 			Expect(pdb.Namespace).To(Equal("namespace"))
 			Expect(pdb.Name).To(Equal("name"))
 			Expect(pdb.Spec.MinAvailable).To(PointTo(Equal(intstr.FromString("50%"))))
+			Expect(pdb.Spec.MaxUnavailable).To(BeNil())
 			Expect(pdb.Spec.Selector.MatchLabels).To(HaveKeyWithValue(controllers.LabelGUID, stSet.Labels[controllers.LabelGUID]))
 			Expect(pdb.Spec.Selector.MatchLabels).To(HaveKeyWithValue(controllers.LabelVersion, stSet.Labels[controllers.LabelVersion]))
 			Expect(pdb.OwnerReferences).To(HaveLen(1))
@@ -83,6 +98,21 @@ var _ = Describe("PDB", func() {
 			Expect(createOpts).To(BeEmpty())
 		})
 
+		When("the budget is configured as max unavailable instances", func() {
+			BeforeEach(func() {
+				pdbConfig = config.PodDisruptionBudget{MaxUnavailable: "1"}
+			})
+
+			It("creates a pod disruption budget with max unavailable instances", func() {
+				Expect(fakeClient.CreateCallCount()).To(Equal(1))
+				_, obj, _ := fakeClient.CreateArgsForCall(0)
+				pdb := obj.(*policyv1.PodDisruptionBudget)
+
+				Expect(pdb.Spec.MinAvailable).To(BeNil())
+				Expect(pdb.Spec.MaxUnavailable).To(PointTo(Equal(intstr.FromInt32(1))))
+			})
+		})
+
 		When("pod disruption budget creation fails", func() {
 			BeforeEach(func() {
 				fakeClient.CreateReturns(errors.New("boom"))
@@ -129,11 +159,37 @@ var _ = Describe("PDB", func() {
 
 		When("the pod distruption budget already exists", func() {
 			BeforeEach(func() {
-				fakeClient.CreateReturns(k8serrors.NewAlreadyExists(schema.GroupResource{}, "boom"))
+				existingPDB = &policyv1.PodDisruptionBudget{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "name",
+						Namespace: "namespace",
+					},
+					Spec: policyv1.PodDisruptionBudgetSpec{
+						MinAvailable: &intstr.IntOrString{Type: intstr.String, StrVal: "50%"},
+					},
+				}
+				pdbConfig = config.PodDisruptionBudget{MaxUnavailable: "25%"}
 			})
 
-			It("succeeds", func() {
+			It("patches it to the configured budget", func() {
 				Expect(updateErr).NotTo(HaveOccurred())
+				Expect(fakeClient.CreateCallCount()).To(BeZero())
+				Expect(fakeClient.PatchCallCount()).To(Equal(1))
+
+				_, obj, _, _ := fakeClient.PatchArgsForCall(0)
+				pdb := obj.(*policyv1.PodDisruptionBudget)
+				Expect(pdb.Spec.MinAvailable).To(BeNil())
+				Expect(pdb.Spec.MaxUnavailable).To(PointTo(Equal(intstr.FromString("25%"))))
+			})
+
+			When("patching it fails", func() {
+				BeforeEach(func() {
+					fakeClient.PatchReturns(errors.New("patch-err"))
+				})
+
+				It("returns the error", func() {
+					Expect(updateErr).To(MatchError(ContainSubstring("patch-err")))
+				})
 			})
 		})
 	})
@@ -0,0 +1,69 @@
+package instanceidentity
+
+//+kubebuilder:webhook:path=/mutate-v1-pod-instance-identity,mutating=true,failurePolicy=fail,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mpod-instance-identity.korifi.cloudfoundry.org,admissionReviewVersions={v1,v1beta1}
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/korifi/statefulset-runner/controllers"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// InstanceIdentityWebhook points the instance identity volume of app pods to
+// the secret holding the certificate of the pod. As all pods of a statefulset
+// share the same template, this cannot be done in the statefulset itself
+// without every pod mounting the private keys of all instances.
+type InstanceIdentityWebhook struct {
+	decoder admission.Decoder
+}
+
+func NewInstanceIdentityWebhook() *InstanceIdentityWebhook {
+	return &InstanceIdentityWebhook{}
+}
+
+var instanceidentitylog = logf.Log.WithName("instance-identity-webhook")
+
+func (r *InstanceIdentityWebhook) SetupWebhookWithManager(mgr ctrl.Manager) {
+	mgr.GetWebhookServer().Register("/mutate-v1-pod-instance-identity", &admission.Webhook{
+		Handler: r,
+	})
+	r.decoder = admission.NewDecoder(mgr.GetScheme())
+}
+
+func (r *InstanceIdentityWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	pod := &corev1.Pod{}
+	if err := r.decoder.Decode(req, pod); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	origMarshalled, err := json.Marshal(pod)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	mutated := false
+	for i, volume := range pod.Spec.Volumes {
+		if volume.Name == controllers.InstanceIdentityVolumeName && volume.Secret != nil {
+			pod.Spec.Volumes[i].Secret.SecretName = controllers.InstanceIdentitySecretName(pod.Name)
+			mutated = true
+		}
+	}
+
+	if !mutated {
+		return admission.Allowed("no instance identity volume")
+	}
+
+	instanceidentitylog.V(1).Info("setting-instance-identity-secret", "namespace", pod.Namespace, "name", pod.Name)
+
+	marshalled, err := json.Marshal(pod)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	return admission.PatchResponseFromRaw(origMarshalled, marshalled)
+}
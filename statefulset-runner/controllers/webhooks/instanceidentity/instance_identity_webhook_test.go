@@ -0,0 +1,59 @@
+package instanceidentity_test
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/korifi/statefulset-runner/controllers"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("InstanceIdentityWebhook", func() {
+	var (
+		pod    *corev1.Pod
+		labels map[string]string
+	)
+
+	BeforeEach(func() {
+		labels = map[string]string{
+			controllers.LabelInstanceIdentity: "true",
+		}
+	})
+
+	JustBeforeEach(func() {
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      uuid.NewString() + "-1",
+				Labels:    labels,
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "application", Image: "app-image"}},
+				Volumes: []corev1.Volume{{
+					Name: controllers.InstanceIdentityVolumeName,
+					VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+						SecretName: "statefulset-instance-identity",
+					}},
+				}},
+			},
+		}
+		Expect(adminClient.Create(context.Background(), pod)).To(Succeed())
+	})
+
+	It("mounts the instance identity secret of the pod", func() {
+		Expect(pod.Spec.Volumes[0].Secret.SecretName).To(Equal(pod.Name + "-instance-identity"))
+	})
+
+	When("the pod is not labelled for instance identity", func() {
+		BeforeEach(func() {
+			labels = nil
+		})
+
+		It("leaves the pod alone", func() {
+			Expect(pod.Spec.Volumes[0].Secret.SecretName).To(Equal("statefulset-instance-identity"))
+		})
+	})
+})
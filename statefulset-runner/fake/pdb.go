@@ -6,15 +6,18 @@ import (
 	"sync"
 
 	"code.cloudfoundry.org/korifi/statefulset-runner/controllers"
-	v1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type PDB struct {
-	UpdateStub        func(context.Context, *v1.StatefulSet) error
+	UpdateStub        func(context.Context, client.Object, int32, *v1.LabelSelector) error
 	updateMutex       sync.RWMutex
 	updateArgsForCall []struct {
 		arg1 context.Context
-		arg2 *v1.StatefulSet
+		arg2 client.Object
+		arg3 int32
+		arg4 *v1.LabelSelector
 	}
 	updateReturns struct {
 		result1 error
@@ -26,19 +29,21 @@ type PDB struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *PDB) Update(arg1 context.Context, arg2 *v1.StatefulSet) error {
+func (fake *PDB) Update(arg1 context.Context, arg2 client.Object, arg3 int32, arg4 *v1.LabelSelector) error {
 	fake.updateMutex.Lock()
 	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
 	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
 		arg1 context.Context
-		arg2 *v1.StatefulSet
-	}{arg1, arg2})
+		arg2 client.Object
+		arg3 int32
+		arg4 *v1.LabelSelector
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.UpdateStub
 	fakeReturns := fake.updateReturns
-	fake.recordInvocation("Update", []interface{}{arg1, arg2})
+	fake.recordInvocation("Update", []interface{}{arg1, arg2, arg3, arg4})
 	fake.updateMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -52,17 +57,17 @@ func (fake *PDB) UpdateCallCount() int {
 	return len(fake.updateArgsForCall)
 }
 
-func (fake *PDB) UpdateCalls(stub func(context.Context, *v1.StatefulSet) error) {
+func (fake *PDB) UpdateCalls(stub func(context.Context, client.Object, int32, *v1.LabelSelector) error) {
 	fake.updateMutex.Lock()
 	defer fake.updateMutex.Unlock()
 	fake.UpdateStub = stub
 }
 
-func (fake *PDB) UpdateArgsForCall(i int) (context.Context, *v1.StatefulSet) {
+func (fake *PDB) UpdateArgsForCall(i int) (context.Context, client.Object, int32, *v1.LabelSelector) {
 	fake.updateMutex.RLock()
 	defer fake.updateMutex.RUnlock()
 	argsForCall := fake.updateArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *PDB) UpdateReturns(result1 error) {
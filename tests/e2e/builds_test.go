@@ -65,23 +65,43 @@ var _ = Describe("Builds", func() {
 	})
 
 	Describe("update", func() {
-		var buildGUID string
+		var (
+			buildGUID string
+			body      map[string]any
+		)
 
 		BeforeEach(func() {
 			buildGUID = createBuild(pkgGUID)
+			body = map[string]any{"state": "FAILED"}
 		})
 
 		JustBeforeEach(func() {
 			var err error
 			resp, err = adminClient.R().
+				SetBody(body).
 				SetResult(&result).
 				Patch("/v3/builds/" + buildGUID)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		It("throws an unprocessable entity error", func() {
-			Expect(resp).To(HaveRestyStatusCode(http.StatusUnprocessableEntity))
-			Expect(resp).To(HaveRestyBody(ContainSubstring("Labels and annotations are not supported for builds")))
+		It("cancels the build", func() {
+			Expect(resp).To(HaveRestyStatusCode(http.StatusOK))
+			Expect(result.GUID).To(Equal(buildGUID))
+			Expect(result.State).To(Equal("FAILED"))
+		})
+
+		When("updating the build metadata", func() {
+			BeforeEach(func() {
+				body = map[string]any{
+					"metadata": map[string]any{
+						"labels": map[string]string{"foo": "bar"},
+					},
+				}
+			})
+
+			It("throws an unprocessable entity error", func() {
+				Expect(resp).To(HaveRestyStatusCode(http.StatusUnprocessableEntity))
+			})
 		})
 	})
 })
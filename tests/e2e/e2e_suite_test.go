@@ -138,6 +138,7 @@ type destinationRef struct {
 type buildResource struct {
 	resource `json:",inline"`
 	Package  resource `json:"package"`
+	State    string   `json:"state,omitempty"`
 }
 
 type dropletResource struct {
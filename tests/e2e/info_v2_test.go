@@ -0,0 +1,30 @@
+package e2e_test
+
+import (
+	"net/http"
+
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InfoV2", func() {
+	var (
+		httpResp    *resty.Response
+		requestPath string
+	)
+
+	BeforeEach(func() {
+		requestPath = "/v2/info"
+	})
+	JustBeforeEach(func() {
+		var err error
+		httpResp, err = adminClient.R().
+			Get(requestPath)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("succeeds", func() {
+		Expect(httpResp).To(HaveRestyStatusCode(http.StatusOK))
+	})
+})
@@ -16,7 +16,16 @@ import (
 
 type identityResource struct {
 	resource
-	Kind string `json:"kind"`
+	Kind      string                 `json:"kind"`
+	ExpiresAt *string                `json:"expires_at"`
+	Roles     []identityRoleResource `json:"roles"`
+}
+
+type identityRoleResource struct {
+	GUID             string `json:"guid"`
+	Type             string `json:"type"`
+	OrganizationGUID string `json:"organization_guid"`
+	SpaceGUID        string `json:"space_guid"`
 }
 
 var _ = Describe("WhoAmI", func() {
@@ -78,6 +87,17 @@ var _ = Describe("WhoAmI", func() {
 			Expect(result.Kind).To(Equal(rbacv1.UserKind))
 		})
 
+		It("returns the certificate expiry", func() {
+			Expect(result.ExpiresAt).NotTo(BeNil())
+			expiresAt, err := time.Parse(time.RFC3339, *result.ExpiresAt)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expiresAt).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+		})
+
+		It("returns no roles for a user without roles", func() {
+			Expect(result.Roles).To(BeEmpty())
+		})
+
 		When("the cert auth header is invalid", func() {
 			BeforeEach(func() {
 				client = client.SetAuthToken("not-valid")
@@ -92,13 +92,21 @@ func NewContainerRegistry(username, password string) *Registry {
 
 func NewNoAuthContainerRegistry() *Registry {
 	registry := &Registry{
-		server: httptest.NewServer(handlers.NewApp(context.Background(), &configuration.Configuration{
-			Storage: configuration.Storage{
-				"inmemory": configuration.Parameters{},
-				"delete":   configuration.Parameters{"enabled": true},
-			},
-			Loglevel: "debug",
-		})),
+		server: httptest.NewServer(noAuthRegistryHandler()),
+	}
+
+	DeferCleanup(func() {
+		registry.server.Close()
+	})
+
+	return registry
+}
+
+// NewNoAuthTLSContainerRegistry serves the registry over https with a
+// certificate that is not trusted by default
+func NewNoAuthTLSContainerRegistry() *Registry {
+	registry := &Registry{
+		server: httptest.NewTLSServer(noAuthRegistryHandler()),
 	}
 
 	DeferCleanup(func() {
@@ -108,6 +116,16 @@ func NewNoAuthContainerRegistry() *Registry {
 	return registry
 }
 
+func noAuthRegistryHandler() *handlers.App {
+	return handlers.NewApp(context.Background(), &configuration.Configuration{
+		Storage: configuration.Storage{
+			"inmemory": configuration.Parameters{},
+			"delete":   configuration.Parameters{"enabled": true},
+		},
+		Loglevel: "debug",
+	})
+}
+
 func generateHtpasswdFile(username, password string) string {
 	htpasswdFile, err := os.CreateTemp("", "")
 	Expect(err).NotTo(HaveOccurred())
@@ -2,10 +2,13 @@ package tools
 
 import (
 	"errors"
+	"regexp"
 	"strings"
 	"time"
 )
 
+var gatewayDurationRegexp = regexp.MustCompile(`^([0-9]{1,5}(h|m|s|ms)){1,4}$`)
+
 func ParseDuration(dur string) (time.Duration, error) {
 	splitByDays := strings.Split(dur, "d")
 	switch len(splitByDays) {
@@ -30,3 +33,13 @@ func ParseDuration(dur string) (time.Duration, error) {
 		return 0, errors.New("failed to parse " + dur)
 	}
 }
+
+// ValidateGatewayDuration checks that dur is a Gateway API duration
+// (GEP-2257), e.g. 30s or 1h30m
+func ValidateGatewayDuration(dur string) error {
+	if !gatewayDurationRegexp.MatchString(dur) {
+		return errors.New("invalid duration " + dur)
+	}
+
+	return nil
+}
@@ -80,3 +80,16 @@ var _ = Describe("ParseDuration", func() {
 		})
 	})
 })
+
+var _ = Describe("ValidateGatewayDuration", func() {
+	It("accepts gateway durations", func() {
+		Expect(tools.ValidateGatewayDuration("1h30m")).To(Succeed())
+		Expect(tools.ValidateGatewayDuration("500ms")).To(Succeed())
+	})
+
+	It("rejects other durations", func() {
+		Expect(tools.ValidateGatewayDuration("1.5h")).NotTo(Succeed())
+		Expect(tools.ValidateGatewayDuration("30")).NotTo(Succeed())
+		Expect(tools.ValidateGatewayDuration("1d")).NotTo(Succeed())
+	})
+})
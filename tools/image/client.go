@@ -2,6 +2,7 @@ package image
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -24,8 +25,11 @@ import (
 )
 
 type Client struct {
-	k8sClient kubernetes.Interface
-	logger    logr.Logger
+	k8sClient          kubernetes.Interface
+	logger             logr.Logger
+	insecureRegistries map[string]bool
+	transport          http.RoundTripper
+	deletionPolicy     DeletionPolicy
 }
 
 type Creds struct {
@@ -42,11 +46,62 @@ type Config struct {
 	ExposedPorts []int32
 }
 
-func NewClient(k8sClient kubernetes.Interface) Client {
-	return Client{
-		k8sClient: k8sClient,
-		logger:    ctrl.Log.WithName("image.client"),
+// NewClient creates an image client. The insecure registries, given as
+// "host[:port]", are accessed without verifying their TLS certificates, and
+// over plain http if they do not serve https at all.
+func NewClient(k8sClient kubernetes.Interface, insecureRegistries ...string) Client {
+	client := Client{
+		k8sClient:          k8sClient,
+		logger:             ctrl.Log.WithName("image.client"),
+		insecureRegistries: map[string]bool{},
+		transport:          remote.DefaultTransport,
+		deletionPolicy:     DeleteManifests,
 	}
+
+	for _, registry := range insecureRegistries {
+		client.insecureRegistries[registry] = true
+	}
+
+	if len(client.insecureRegistries) > 0 {
+		insecureTransport := remote.DefaultTransport.(*http.Transport).Clone()
+		insecureTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} // #nosec G402
+
+		client.transport = registryTransport{
+			secure:             remote.DefaultTransport,
+			insecure:           insecureTransport,
+			insecureRegistries: client.insecureRegistries,
+		}
+	}
+
+	return client
+}
+
+// registryTransport skips TLS verification for insecure registries only
+type registryTransport struct {
+	secure             http.RoundTripper
+	insecure           http.RoundTripper
+	insecureRegistries map[string]bool
+}
+
+func (t registryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.insecureRegistries[req.URL.Host] {
+		return t.insecure.RoundTrip(req)
+	}
+
+	return t.secure.RoundTrip(req)
+}
+
+func (c Client) parseReference(imageRef string) (name.Reference, error) {
+	ref, err := name.ParseReference(imageRef)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.insecureRegistries[ref.Context().RegistryStr()] {
+		return name.ParseReference(imageRef, name.Insecure)
+	}
+
+	return ref, nil
 }
 
 func (c Client) Push(ctx context.Context, creds Creds, repoRef string, zipReader io.Reader, tags ...string) (string, error) {
@@ -54,6 +109,7 @@ func (c Client) Push(ctx context.Context, creds Creds, repoRef string, zipReader
 	if err != nil {
 		return "", fmt.Errorf("failed to create a temp file for image: %w", err)
 	}
+	defer os.Remove(tmpFile.Name())
 	defer tmpFile.Close()
 
 	if _, err = io.Copy(tmpFile, zipReader); err != nil {
@@ -72,22 +128,22 @@ func (c Client) Push(ctx context.Context, creds Creds, repoRef string, zipReader
 		return "", fmt.Errorf("failed to append layer: %w", err)
 	}
 
-	ref, err := name.ParseReference(repoRef)
+	ref, err := c.parseReference(repoRef)
 	if err != nil {
 		return "", fmt.Errorf("error parsing repository reference %s: %w", repoRef, err)
 	}
 
-	authOpt, err := c.authOpt(ctx, creds)
+	remoteOpts, err := c.remoteOpts(ctx, creds)
 	if err != nil {
 		return "", fmt.Errorf("error creating keychain: %w", err)
 	}
 
-	if err = remote.Write(ref, image, authOpt); err != nil {
+	if err = remote.Write(ref, image, remoteOpts...); err != nil {
 		return "", fmt.Errorf("failed to upload image: %w", err)
 	}
 
 	for _, tag := range tags {
-		err = remote.Tag(ref.Context().Tag(tag), image, authOpt)
+		err = remote.Tag(ref.Context().Tag(tag), image, remoteOpts...)
 		if err != nil {
 			return "", fmt.Errorf("failed to tag image: %w", err)
 		}
@@ -107,17 +163,17 @@ func (c Client) Push(ctx context.Context, creds Creds, repoRef string, zipReader
 }
 
 func (c Client) Config(ctx context.Context, creds Creds, imageRef string) (Config, error) {
-	ref, err := name.ParseReference(imageRef)
+	ref, err := c.parseReference(imageRef)
 	if err != nil {
 		return Config{}, fmt.Errorf("error parsing repository reference %s: %w", imageRef, err)
 	}
 
-	authOpt, err := c.authOpt(ctx, creds)
+	remoteOpts, err := c.remoteOpts(ctx, creds)
 	if err != nil {
 		return Config{}, fmt.Errorf("error creating keychain: %w", err)
 	}
 
-	img, err := remote.Image(ref, authOpt)
+	img, err := remote.Image(ref, remoteOpts...)
 	if err != nil {
 		return Config{}, fmt.Errorf("failed to get image: %w", err)
 	}
@@ -153,18 +209,23 @@ func parseExposedPorts(ports map[string]struct{}) []string {
 }
 
 func (c Client) Delete(ctx context.Context, creds Creds, imageRef string, tagsToDelete ...string) error {
+	if c.deletionPolicy == DeleteNothing {
+		c.logger.V(1).Info("image deletion disabled - skipping", "ref", imageRef)
+		return nil
+	}
+
 	c.logger.V(1).Info("deleting", "ref", imageRef)
-	ref, err := name.ParseReference(imageRef)
+	ref, err := c.parseReference(imageRef)
 	if err != nil {
 		return err
 	}
 
-	authOpt, err := c.authOpt(ctx, creds)
+	remoteOpts, err := c.remoteOpts(ctx, creds)
 	if err != nil {
 		return fmt.Errorf("error creating keychain: %w", err)
 	}
 
-	allTagSet, err := c.getTagSet(ref, authOpt)
+	allTagSet, err := c.getTagSet(ref, remoteOpts)
 	if err != nil {
 		return fmt.Errorf("failed to list tags: %w", err)
 	}
@@ -175,7 +236,7 @@ func (c Client) Delete(ctx context.Context, creds Creds, imageRef string, tagsTo
 			continue
 		}
 
-		if err = c.deleteTag(ref, tag, authOpt); err != nil {
+		if err = c.deleteTag(ref, tag, remoteOpts); err != nil {
 			c.logger.Info("failed to delete tag", "reason", err)
 			continue
 		}
@@ -186,28 +247,39 @@ func (c Client) Delete(ctx context.Context, creds Creds, imageRef string, tagsTo
 	// remaining tag, remove it to prevent digest deletion errors
 	latestTag := "latest"
 	if len(allTagSet) == 1 && allTagSet[latestTag] {
-		if err = c.deleteTag(ref, latestTag, authOpt); err != nil {
+		if err = c.deleteTag(ref, latestTag, remoteOpts); err != nil {
 			c.logger.Info("failed to delete tag", "reason", err)
 		} else {
 			delete(allTagSet, latestTag)
 		}
 	}
 
+	if c.deletionPolicy == DeleteTags {
+		return nil
+	}
+
 	if len(allTagSet) == 0 {
-		err = remote.Delete(ref, authOpt)
+		err = remote.Delete(ref, remoteOpts...)
 		if err != nil {
 			if structuredErr, ok := err.(*transport.Error); ok && structuredErr.StatusCode == http.StatusNotFound {
 				c.logger.V(1).Info("manifest disappeared - continuing", "reason", err)
 				return nil
 			}
+
+			// the tags are gone, so the registry garbage collection takes
+			// care of the manifest
+			if isUnsupportedDeletion(err) {
+				c.logger.Info("registry does not support manifest deletion - leaving the untagged manifest", "ref", imageRef, "reason", err)
+				return nil
+			}
 		}
 	}
 
 	return err
 }
 
-func (c Client) getTagSet(ref name.Reference, authOpt remote.Option) (map[string]bool, error) {
-	allTags, err := remote.List(ref.Context(), authOpt)
+func (c Client) getTagSet(ref name.Reference, remoteOpts []remote.Option) (map[string]bool, error) {
+	allTags, err := remote.List(ref.Context(), remoteOpts...)
 	if err != nil {
 		c.logger.V(1).Info("failed to list tags - skipping tag deletion", "reason", err)
 		return nil, err
@@ -215,14 +287,8 @@ func (c Client) getTagSet(ref name.Reference, authOpt remote.Option) (map[string
 
 	allTagSet := map[string]bool{}
 	for _, t := range allTags {
-		var tagRef name.Reference
-		tagRef, err = name.ParseReference(ref.Context().String() + ":" + t)
-		if err != nil {
-			return nil, fmt.Errorf("couldn't create a tag ref: %w", err)
-		}
-
 		var descriptor *remote.Descriptor
-		descriptor, err = remote.Get(tagRef, authOpt)
+		descriptor, err = remote.Get(ref.Context().Tag(t), remoteOpts...)
 		if err != nil {
 			return nil, fmt.Errorf("couldn't get tag: %w", err)
 		}
@@ -235,13 +301,9 @@ func (c Client) getTagSet(ref name.Reference, authOpt remote.Option) (map[string
 	return allTagSet, nil
 }
 
-func (c Client) deleteTag(ref name.Reference, tag string, authOpt remote.Option) error {
-	tagRef, err := name.ParseReference(ref.Context().String() + ":" + tag)
-	if err != nil {
-		return fmt.Errorf("couldn't create a tag ref: %w", err)
-	}
-	var descriptor *remote.Descriptor
-	descriptor, err = remote.Get(tagRef, authOpt)
+func (c Client) deleteTag(ref name.Reference, tag string, remoteOpts []remote.Option) error {
+	tagRef := ref.Context().Tag(tag)
+	descriptor, err := remote.Get(tagRef, remoteOpts...)
 	if err != nil {
 		c.logger.V(1).Info("failed get tag - continuing", "reason", err)
 		return nil
@@ -249,7 +311,7 @@ func (c Client) deleteTag(ref name.Reference, tag string, authOpt remote.Option)
 
 	if descriptor.Digest.String() == ref.Identifier() {
 		c.logger.V(1).Info("deleting tag", "tag", tag)
-		err = remote.Delete(tagRef, authOpt)
+		err = remote.Delete(tagRef, remoteOpts...)
 		if err != nil {
 			c.logger.V(1).Info("failed to delete tag", "reason", err)
 		}
@@ -258,7 +320,7 @@ func (c Client) deleteTag(ref name.Reference, tag string, authOpt remote.Option)
 	return nil
 }
 
-func (c Client) authOpt(ctx context.Context, creds Creds) (remote.Option, error) {
+func (c Client) remoteOpts(ctx context.Context, creds Creds) ([]remote.Option, error) {
 	var keychain authn.Keychain
 	var err error
 
@@ -279,5 +341,8 @@ func (c Client) authOpt(ctx context.Context, creds Creds) (remote.Option, error)
 		return nil, err
 	}
 
-	return remote.WithAuthFromKeychain(keychain), nil
+	return []remote.Option{
+		remote.WithAuthFromKeychain(keychain),
+		remote.WithTransport(c.transport),
+	}, nil
 }
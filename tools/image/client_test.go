@@ -2,6 +2,7 @@ package image_test
 
 import (
 	"os"
+	"strings"
 
 	"code.cloudfoundry.org/korifi/tests/helpers/oci"
 	"code.cloudfoundry.org/korifi/tools/image"
@@ -127,6 +128,34 @@ var _ = Describe("Client", func() {
 				Expect(imgRef).To(HavePrefix(pushRef))
 			})
 		})
+
+		When("the registry certificate is not trusted", func() {
+			var tlsRegistry *oci.Registry
+
+			BeforeEach(func() {
+				tlsRegistry = oci.NewNoAuthTLSContainerRegistry()
+				pushRef = tlsRegistry.ImageRef("foo/bar")
+				creds.SecretNames = []string{}
+			})
+
+			It("fails", func() {
+				Expect(testErr).To(MatchError(ContainSubstring("certificate")))
+			})
+
+			When("the registry is configured as insecure", func() {
+				BeforeEach(func() {
+					imgClient = image.NewClient(k8sClientset, strings.TrimPrefix(tlsRegistry.URL(), "https://"))
+				})
+
+				It("pushes the image without verifying the certificate", func() {
+					Expect(testErr).NotTo(HaveOccurred())
+					Expect(imgRef).To(HavePrefix(pushRef))
+
+					_, err := imgClient.Config(ctx, creds, imgRef)
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+		})
 	})
 
 	Describe("Config", func() {
@@ -258,6 +287,35 @@ var _ = Describe("Client", func() {
 			})
 		})
 
+		When("the deletion policy is to delete tags only", func() {
+			BeforeEach(func() {
+				imgClient = imgClient.WithDeletionPolicy(image.DeleteTags)
+			})
+
+			It("deletes the tags but not the manifest", func() {
+				Expect(testErr).NotTo(HaveOccurred())
+
+				_, err := imgClient.Config(ctx, creds, pushRef+":jim")
+				Expect(err).To(MatchError(ContainSubstring("MANIFEST_UNKNOWN")))
+
+				_, err = imgClient.Config(ctx, creds, imgRef)
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		When("the deletion policy is to delete nothing", func() {
+			BeforeEach(func() {
+				imgClient = imgClient.WithDeletionPolicy(image.DeleteNothing)
+			})
+
+			It("keeps the image and its tags", func() {
+				Expect(testErr).NotTo(HaveOccurred())
+
+				_, err := imgClient.Config(ctx, creds, pushRef+":jim")
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
 		When("the secret doesn't exist", func() {
 			BeforeEach(func() {
 				creds.SecretNames = []string{"not-a-secret"}
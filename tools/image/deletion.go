@@ -0,0 +1,62 @@
+package image
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+// DeletionPolicy controls what Delete removes from the registry
+type DeletionPolicy string
+
+const (
+	// DeleteManifests deletes the given tags and then the manifest, once no
+	// tag refers to it anymore
+	DeleteManifests DeletionPolicy = "manifests"
+	// DeleteTags only deletes the given tags and leaves untagged manifests
+	// to the garbage collection of the registry. Some registries (e.g. Docker
+	// Hub or Harbor with immutable artifacts) do not allow deleting manifests
+	// through the registry API at all
+	DeleteTags DeletionPolicy = "tags"
+	// DeleteNothing leaves images in the registry
+	DeleteNothing DeletionPolicy = "none"
+)
+
+func ParseDeletionPolicy(policy string) (DeletionPolicy, error) {
+	switch DeletionPolicy(policy) {
+	case "":
+		return DeleteManifests, nil
+	case DeleteManifests, DeleteTags, DeleteNothing:
+		return DeletionPolicy(policy), nil
+	}
+
+	return "", fmt.Errorf("invalid image deletion policy %q: must be one of %q, %q or %q", policy, DeleteManifests, DeleteTags, DeleteNothing)
+}
+
+// WithDeletionPolicy returns a copy of the client deleting images according
+// to the given policy
+func (c Client) WithDeletionPolicy(policy DeletionPolicy) Client {
+	c.deletionPolicy = policy
+	return c
+}
+
+func isUnsupportedDeletion(err error) bool {
+	var transportErr *transport.Error
+	if !errors.As(err, &transportErr) {
+		return false
+	}
+
+	if transportErr.StatusCode == http.StatusMethodNotAllowed {
+		return true
+	}
+
+	for _, diagnostic := range transportErr.Errors {
+		if diagnostic.Code == transport.UnsupportedErrorCode {
+			return true
+		}
+	}
+
+	return false
+}
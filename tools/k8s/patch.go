@@ -98,6 +98,23 @@ func PatchResource[T any, PT ObjectWithDeepCopy[T]](
 	return k8sClient.Patch(ctx, obj, client.MergeFrom(originalObj))
 }
 
+// PatchResourceWithOptimisticLock is like PatchResource, but the patch also
+// carries the resource version of `obj`. The patch therefore fails with a
+// conflict if the object has been changed since it was read, instead of
+// silently overwriting the change.
+func PatchResourceWithOptimisticLock[T any, PT ObjectWithDeepCopy[T]](
+	ctx context.Context,
+	k8sClient client.Client,
+	obj PT,
+	modify func(),
+) error {
+	originalObj := PT(obj.DeepCopy())
+
+	modify()
+
+	return k8sClient.Patch(ctx, obj, client.MergeFromWithOptions(originalObj, client.MergeFromWithOptimisticLock{}))
+}
+
 func hasStatus(obj runtime.Object) (bool, error) {
 	unstructuredObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
 	if err != nil {
@@ -10,6 +10,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -197,4 +198,61 @@ var _ = Describe("Kubernetes Patch", func() {
 			Expect(patchedPod.Status.Message).To(Equal("hello"))
 		})
 	})
+
+	Describe("PatchResourceWithOptimisticLock", func() {
+		var (
+			pod        *corev1.Pod
+			patchedPod *corev1.Pod
+			patchErr   error
+		)
+		BeforeEach(func() {
+			pod = &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNamespace.Name,
+					Name:      uuid.NewString(),
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:    "foo",
+						Image:   "busybox",
+						Command: []string{"echo", "hi"},
+					}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			patchErr = k8s.PatchResourceWithOptimisticLock(ctx, k8sClient, pod, func() {
+				pod.Spec.Containers[0].Image = "alpine"
+			})
+
+			patchedPod = &corev1.Pod{}
+			err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(pod), patchedPod)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("patches the object via the k8s client", func() {
+			Expect(patchErr).NotTo(HaveOccurred())
+			Expect(patchedPod.Spec.Containers[0].Image).To(Equal("alpine"))
+		})
+
+		When("the object has been changed since it was read", func() {
+			BeforeEach(func() {
+				concurrentPod := pod.DeepCopy()
+				Expect(k8s.PatchResource(ctx, k8sClient, concurrentPod, func() {
+					concurrentPod.Labels = map[string]string{"foo": "bar"}
+				})).To(Succeed())
+			})
+
+			It("returns a conflict error", func() {
+				Expect(k8serrors.IsConflict(patchErr)).To(BeTrue())
+			})
+
+			It("does not patch the object", func() {
+				Expect(patchedPod.Spec.Containers[0].Image).To(Equal("busybox"))
+				Expect(patchedPod.Labels).To(HaveKeyWithValue("foo", "bar"))
+			})
+		})
+	})
 })
@@ -0,0 +1,136 @@
+package k8s
+
+import (
+	"fmt"
+	"slices"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	TrustedCAVolumeName = "trusted-ca"
+	TrustedCAMountPath  = "/etc/cf-system-certificates"
+	TrustedCAKey        = "ca.crt"
+	TrustedCAFileName   = "trusted-ca.crt"
+)
+
+// containerStartFailures are the reasons of waiting containers that do not
+// start without the user changing the app, e.g. pushing a different image
+var containerStartFailures = []string{
+	"ErrImagePull",
+	"ImagePullBackOff",
+	"InvalidImageName",
+	"CreateContainerConfigError",
+	"CreateContainerError",
+}
+
+// PodStartFailure returns the reason and a human readable message when the
+// pod cannot start because it cannot be scheduled or one of its containers
+// cannot be created
+func PodStartFailure(pod corev1.Pod) (string, string, bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled &&
+			condition.Status == corev1.ConditionFalse &&
+			condition.Reason == corev1.PodReasonUnschedulable {
+			return condition.Reason, condition.Message, true
+		}
+	}
+
+	for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		waiting := status.State.Waiting
+		if waiting != nil && slices.Contains(containerStartFailures, waiting.Reason) {
+			return waiting.Reason, waiting.Message, true
+		}
+	}
+
+	return "", "", false
+}
+
+// InstancesFailingCondition propagates the first true InstancesFailing
+// condition of the children of an object, e.g. of the AppWorkloads of a
+// CFProcess, to the object
+func InstancesFailingCondition(obj metav1.Object, childrenConditions ...[]metav1.Condition) metav1.Condition {
+	for _, conditions := range childrenConditions {
+		if failing := meta.FindStatusCondition(conditions, korifiv1alpha1.InstancesFailingConditionType); failing != nil && failing.Status == metav1.ConditionTrue {
+			return metav1.Condition{
+				Type:               korifiv1alpha1.InstancesFailingConditionType,
+				Status:             metav1.ConditionTrue,
+				Reason:             failing.Reason,
+				Message:            failing.Message,
+				ObservedGeneration: obj.GetGeneration(),
+			}
+		}
+	}
+
+	return metav1.Condition{
+		Type:               korifiv1alpha1.InstancesFailingConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoFailures",
+		ObservedGeneration: obj.GetGeneration(),
+	}
+}
+
+// RunningCondition is true when at least one instance of the object, e.g. a
+// CFProcess or a CFApp, is running
+func RunningCondition(obj metav1.Object, actualInstances int32) metav1.Condition {
+	if actualInstances > 0 {
+		return metav1.Condition{
+			Type:               korifiv1alpha1.RunningConditionType,
+			Status:             metav1.ConditionTrue,
+			Reason:             "InstancesRunning",
+			Message:            fmt.Sprintf("%d instances running", actualInstances),
+			ObservedGeneration: obj.GetGeneration(),
+		}
+	}
+
+	return metav1.Condition{
+		Type:               korifiv1alpha1.RunningConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoInstancesRunning",
+		ObservedGeneration: obj.GetGeneration(),
+	}
+}
+
+// MountTrustedCA mounts the ca.crt bundle of the trusted CA config map into
+// the containers of the pod at CF_SYSTEM_CERT_PATH, like CF for VMs does. The
+// bundle is also added to the trust store of OpenSSL, Go, and the JVM on the
+// Paketo stacks via SSL_CERT_FILE, and of Node.js via NODE_EXTRA_CA_CERTS.
+// SSL_CERT_FILE only replaces the system bundle file, so the public CAs keep
+// being trusted via the hashed links in the system certificate directory.
+// Env vars set by the user are left as they are.
+func MountTrustedCA(podSpec *corev1.PodSpec, configMapName string) {
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: TrustedCAVolumeName,
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+			Items:                []corev1.KeyToPath{{Key: TrustedCAKey, Path: TrustedCAFileName}},
+		}},
+	})
+
+	trustedCAEnv := []corev1.EnvVar{
+		{Name: "CF_SYSTEM_CERT_PATH", Value: TrustedCAMountPath},
+		{Name: "SSL_CERT_FILE", Value: TrustedCAMountPath + "/" + TrustedCAFileName},
+		{Name: "NODE_EXTRA_CA_CERTS", Value: TrustedCAMountPath + "/" + TrustedCAFileName},
+	}
+
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      TrustedCAVolumeName,
+			MountPath: TrustedCAMountPath,
+			ReadOnly:  true,
+		})
+
+		// copy the env, which may be shared with the workload spec
+		env := slices.Clone(container.Env)
+		for _, envVar := range trustedCAEnv {
+			if !slices.ContainsFunc(container.Env, func(e corev1.EnvVar) bool { return e.Name == envVar.Name }) {
+				env = append(env, envVar)
+			}
+		}
+		container.Env = env
+	}
+}
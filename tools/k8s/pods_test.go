@@ -0,0 +1,239 @@
+package k8s_test
+
+import (
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("PodStartFailure", func() {
+	var (
+		pod     corev1.Pod
+		reason  string
+		message string
+		failing bool
+	)
+
+	BeforeEach(func() {
+		pod = corev1.Pod{
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{
+					Type:   corev1.PodScheduled,
+					Status: corev1.ConditionTrue,
+				}},
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: "application",
+					State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					},
+				}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		reason, message, failing = k8s.PodStartFailure(pod)
+	})
+
+	It("does not report a failure for a running pod", func() {
+		Expect(failing).To(BeFalse())
+	})
+
+	When("the pod cannot be scheduled", func() {
+		BeforeEach(func() {
+			pod.Status.Conditions[0] = corev1.PodCondition{
+				Type:    corev1.PodScheduled,
+				Status:  corev1.ConditionFalse,
+				Reason:  corev1.PodReasonUnschedulable,
+				Message: "0/3 nodes are available: 3 Insufficient memory.",
+			}
+		})
+
+		It("reports the scheduling failure", func() {
+			Expect(failing).To(BeTrue())
+			Expect(reason).To(Equal("Unschedulable"))
+			Expect(message).To(Equal("0/3 nodes are available: 3 Insufficient memory."))
+		})
+	})
+
+	When("the image of a container cannot be pulled", func() {
+		BeforeEach(func() {
+			pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{
+					Reason:  "ImagePullBackOff",
+					Message: `Back-off pulling image "my/app"`,
+				},
+			}
+		})
+
+		It("reports the image pull failure", func() {
+			Expect(failing).To(BeTrue())
+			Expect(reason).To(Equal("ImagePullBackOff"))
+			Expect(message).To(Equal(`Back-off pulling image "my/app"`))
+		})
+	})
+
+	When("a container is waiting for another reason", func() {
+		BeforeEach(func() {
+			pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{
+					Reason: "ContainerCreating",
+				},
+			}
+		})
+
+		It("does not report a failure", func() {
+			Expect(failing).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("InstancesFailingCondition", func() {
+	var (
+		cfProcess          *korifiv1alpha1.CFProcess
+		childrenConditions [][]metav1.Condition
+		condition          metav1.Condition
+	)
+
+	BeforeEach(func() {
+		cfProcess = &korifiv1alpha1.CFProcess{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		}
+		childrenConditions = [][]metav1.Condition{
+			{{Type: korifiv1alpha1.InstancesFailingConditionType, Status: metav1.ConditionFalse, Reason: "NoFailures"}},
+			{{Type: korifiv1alpha1.InstancesFailingConditionType, Status: metav1.ConditionTrue, Reason: "Unschedulable", Message: "no nodes"}},
+		}
+	})
+
+	JustBeforeEach(func() {
+		condition = k8s.InstancesFailingCondition(cfProcess, childrenConditions...)
+	})
+
+	It("propagates the failing condition", func() {
+		Expect(condition).To(Equal(metav1.Condition{
+			Type:               korifiv1alpha1.InstancesFailingConditionType,
+			Status:             metav1.ConditionTrue,
+			Reason:             "Unschedulable",
+			Message:            "no nodes",
+			ObservedGeneration: 2,
+		}))
+	})
+
+	When("no child is failing", func() {
+		BeforeEach(func() {
+			childrenConditions = childrenConditions[:1]
+		})
+
+		It("is false", func() {
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("NoFailures"))
+		})
+	})
+})
+
+var _ = Describe("RunningCondition", func() {
+	var (
+		cfProcess       *korifiv1alpha1.CFProcess
+		actualInstances int32
+		condition       metav1.Condition
+	)
+
+	BeforeEach(func() {
+		cfProcess = &korifiv1alpha1.CFProcess{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		}
+		actualInstances = 3
+	})
+
+	JustBeforeEach(func() {
+		condition = k8s.RunningCondition(cfProcess, actualInstances)
+	})
+
+	It("is true", func() {
+		Expect(condition).To(Equal(metav1.Condition{
+			Type:               korifiv1alpha1.RunningConditionType,
+			Status:             metav1.ConditionTrue,
+			Reason:             "InstancesRunning",
+			Message:            "3 instances running",
+			ObservedGeneration: 2,
+		}))
+	})
+
+	When("no instance is running", func() {
+		BeforeEach(func() {
+			actualInstances = 0
+		})
+
+		It("is false", func() {
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("NoInstancesRunning"))
+		})
+	})
+})
+
+var _ = Describe("MountTrustedCA", func() {
+	var podSpec corev1.PodSpec
+
+	BeforeEach(func() {
+		podSpec = corev1.PodSpec{
+			Volumes: []corev1.Volume{{Name: "tmp"}},
+			Containers: []corev1.Container{{
+				Name: "application",
+				Env:  []corev1.EnvVar{{Name: "FOO", Value: "bar"}},
+			}},
+		}
+	})
+
+	JustBeforeEach(func() {
+		k8s.MountTrustedCA(&podSpec, "trusted-ca-bundle")
+	})
+
+	It("adds the bundle of the config map as a volume", func() {
+		Expect(podSpec.Volumes).To(ConsistOf(
+			corev1.Volume{Name: "tmp"},
+			corev1.Volume{
+				Name: "trusted-ca",
+				VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "trusted-ca-bundle"},
+					Items:                []corev1.KeyToPath{{Key: "ca.crt", Path: "trusted-ca.crt"}},
+				}},
+			},
+		))
+	})
+
+	It("mounts the bundle into the containers", func() {
+		Expect(podSpec.Containers[0].VolumeMounts).To(ConsistOf(corev1.VolumeMount{
+			Name:      "trusted-ca",
+			MountPath: "/etc/cf-system-certificates",
+			ReadOnly:  true,
+		}))
+	})
+
+	It("points the containers to the bundle", func() {
+		Expect(podSpec.Containers[0].Env).To(Equal([]corev1.EnvVar{
+			{Name: "FOO", Value: "bar"},
+			{Name: "CF_SYSTEM_CERT_PATH", Value: "/etc/cf-system-certificates"},
+			{Name: "SSL_CERT_FILE", Value: "/etc/cf-system-certificates/trusted-ca.crt"},
+			{Name: "NODE_EXTRA_CA_CERTS", Value: "/etc/cf-system-certificates/trusted-ca.crt"},
+		}))
+	})
+
+	When("the user has set one of the env vars", func() {
+		BeforeEach(func() {
+			podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, corev1.EnvVar{Name: "SSL_CERT_FILE", Value: "/my/ca.crt"})
+		})
+
+		It("keeps the value of the user", func() {
+			Expect(podSpec.Containers[0].Env).To(Equal([]corev1.EnvVar{
+				{Name: "FOO", Value: "bar"},
+				{Name: "SSL_CERT_FILE", Value: "/my/ca.crt"},
+				{Name: "CF_SYSTEM_CERT_PATH", Value: "/etc/cf-system-certificates"},
+				{Name: "NODE_EXTRA_CA_CERTS", Value: "/etc/cf-system-certificates/trusted-ca.crt"},
+			}))
+		})
+	})
+})
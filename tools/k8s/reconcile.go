@@ -32,6 +32,7 @@ type PatchingReconciler[T any, PT RuntimeObjectWithStatusConditions[T]] struct {
 	log              logr.Logger
 	k8sClient        client.Client
 	objectReconciler ObjectReconciler[T, PT]
+	namespaceFilter  func(namespace string) bool
 }
 
 func NewPatchingReconciler[T any, PT RuntimeObjectWithStatusConditions[T]](log logr.Logger, k8sClient client.Client, objectReconciler ObjectReconciler[T, PT]) *PatchingReconciler[T, PT] {
@@ -42,7 +43,19 @@ func NewPatchingReconciler[T any, PT RuntimeObjectWithStatusConditions[T]](log l
 	}
 }
 
+// WithNamespaceFilter makes the reconciler ignore the objects in the
+// namespaces the filter rejects, e.g. the namespaces of other controller
+// shards
+func (r *PatchingReconciler[T, PT]) WithNamespaceFilter(filter func(namespace string) bool) *PatchingReconciler[T, PT] {
+	r.namespaceFilter = filter
+	return r
+}
+
 func (r *PatchingReconciler[T, PT]) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if r.namespaceFilter != nil && !r.namespaceFilter(req.Namespace) {
+		return ctrl.Result{}, nil
+	}
+
 	log := r.log.
 		WithName(reflect.TypeFor[T]().Name()).
 		WithValues("namespace", req.Namespace, "name", req.Name, "logID", uuid.NewString())
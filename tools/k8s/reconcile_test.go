@@ -124,6 +124,21 @@ var _ = Describe("Reconcile", func() {
 		})
 	})
 
+	When("the namespace of the object is filtered out", func() {
+		BeforeEach(func() {
+			patchingReconciler.WithNamespaceFilter(func(namespace string) bool {
+				return namespace != org.Namespace
+			})
+		})
+
+		It("ignores the object", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(fakeClient.GetCallCount()).To(BeZero())
+			Expect(objectReconciler.reconcileResourceCallCount).To(Equal(0))
+		})
+	})
+
 	When("the getting the object fails", func() {
 		BeforeEach(func() {
 			fakeClient.GetReturns(errors.New("get-error"))
@@ -0,0 +1,163 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	RegistryBasePlaceholder = "{registryBase}"
+	OrgGUIDPlaceholder      = "{orgGUID}"
+	OrgNamePlaceholder      = "{orgName}"
+	SpaceGUIDPlaceholder    = "{spaceGUID}"
+	SpaceNamePlaceholder    = "{spaceName}"
+	AppGUIDPlaceholder      = "{appGUID}"
+
+	// DefaultRepositoryTemplate keeps all app repositories flat under the
+	// container repository prefix
+	DefaultRepositoryTemplate = RegistryBasePlaceholder + AppGUIDPlaceholder
+
+	packagesRepositorySuffix = "-packages"
+	dropletsRepositorySuffix = "-droplets"
+)
+
+var (
+	placeholderRegex       = regexp.MustCompile(`{[^{}]*}`)
+	invalidComponentRegex  = regexp.MustCompile(`[^a-z0-9._-]+`)
+	repeatedSeparatorRegex = regexp.MustCompile(`/{2,}`)
+
+	knownPlaceholders = map[string]bool{
+		RegistryBasePlaceholder: true,
+		OrgGUIDPlaceholder:      true,
+		OrgNamePlaceholder:      true,
+		SpaceGUIDPlaceholder:    true,
+		SpaceNamePlaceholder:    true,
+		AppGUIDPlaceholder:      true,
+	}
+)
+
+// RepositoryNamer builds the names of the repositories holding the packages
+// and droplets of an app from a template, so that operators can lay out
+// repositories per org or space (e.g. to apply per-tenant quotas or retention
+// policies in the registry). Org and space names are read from the
+// annotations of their namespaces and are only looked up when the template
+// refers to them.
+type RepositoryNamer struct {
+	k8sClient    client.Reader
+	registryBase string
+	template     string
+}
+
+func NewRepositoryNamer(k8sClient client.Reader, registryBase string, template string) (*RepositoryNamer, error) {
+	if template == "" {
+		template = DefaultRepositoryTemplate
+	}
+
+	if err := validateRepositoryTemplate(template); err != nil {
+		return nil, err
+	}
+
+	return &RepositoryNamer{
+		k8sClient:    k8sClient,
+		registryBase: registryBase,
+		template:     template,
+	}, nil
+}
+
+func validateRepositoryTemplate(template string) error {
+	for _, placeholder := range placeholderRegex.FindAllString(template, -1) {
+		if !knownPlaceholders[placeholder] {
+			return fmt.Errorf("unknown placeholder %q in repository template %q", placeholder, template)
+		}
+	}
+
+	if !strings.Contains(template, AppGUIDPlaceholder) {
+		return fmt.Errorf("repository template %q must contain the %s placeholder", template, AppGUIDPlaceholder)
+	}
+
+	return nil
+}
+
+func (n *RepositoryNamer) PackagesRepository(ctx context.Context, spaceGUID string, appGUID string) (string, error) {
+	repo, err := n.appRepository(ctx, spaceGUID, appGUID)
+	if err != nil {
+		return "", err
+	}
+
+	return repo + packagesRepositorySuffix, nil
+}
+
+func (n *RepositoryNamer) DropletsRepository(ctx context.Context, spaceGUID string, appGUID string) (string, error) {
+	repo, err := n.appRepository(ctx, spaceGUID, appGUID)
+	if err != nil {
+		return "", err
+	}
+
+	return repo + dropletsRepositorySuffix, nil
+}
+
+func (n *RepositoryNamer) appRepository(ctx context.Context, spaceGUID string, appGUID string) (string, error) {
+	values := map[string]string{
+		AppGUIDPlaceholder:   appGUID,
+		SpaceGUIDPlaceholder: spaceGUID,
+	}
+
+	if n.refersTo(OrgGUIDPlaceholder, OrgNamePlaceholder, SpaceNamePlaceholder) {
+		spaceNamespace, err := n.getNamespace(ctx, spaceGUID)
+		if err != nil {
+			return "", err
+		}
+		values[SpaceNamePlaceholder] = repositoryComponent(spaceNamespace.Annotations[korifiv1alpha1.SpaceNameKey])
+		values[OrgGUIDPlaceholder] = spaceNamespace.Labels[korifiv1alpha1.OrgGUIDKey]
+	}
+
+	if n.refersTo(OrgNamePlaceholder) {
+		orgNamespace, err := n.getNamespace(ctx, values[OrgGUIDPlaceholder])
+		if err != nil {
+			return "", err
+		}
+		values[OrgNamePlaceholder] = repositoryComponent(orgNamespace.Annotations[korifiv1alpha1.OrgNameKey])
+	}
+
+	values[RegistryBasePlaceholder] = n.registryBase
+
+	repo := placeholderRegex.ReplaceAllStringFunc(n.template, func(placeholder string) string {
+		return values[placeholder]
+	})
+
+	// the registry base usually ends with a slash, so templates such as
+	// {registryBase}/{orgName}/{appGUID} would otherwise yield empty path
+	// components
+	return repeatedSeparatorRegex.ReplaceAllString(repo, "/"), nil
+}
+
+func (n *RepositoryNamer) refersTo(placeholders ...string) bool {
+	for _, placeholder := range placeholders {
+		if strings.Contains(n.template, placeholder) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (n *RepositoryNamer) getNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	namespace := &corev1.Namespace{}
+	if err := n.k8sClient.Get(ctx, client.ObjectKey{Name: name}, namespace); err != nil {
+		return nil, fmt.Errorf("failed to get namespace %q: %w", name, err)
+	}
+
+	return namespace, nil
+}
+
+// repositoryComponent turns an org or space display name into a valid
+// repository path component
+func repositoryComponent(name string) string {
+	return strings.Trim(invalidComponentRegex.ReplaceAllString(strings.ToLower(name), "-"), "._-")
+}
@@ -0,0 +1,131 @@
+package registry_test
+
+import (
+	"context"
+	"errors"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/registry"
+	"code.cloudfoundry.org/korifi/tools/registry/fake"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("RepositoryNamer", func() {
+	var (
+		k8sReader *fake.Reader
+		template  string
+		namer     *registry.RepositoryNamer
+		newErr    error
+	)
+
+	BeforeEach(func() {
+		k8sReader = new(fake.Reader)
+		k8sReader.GetStub = func(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+			namespace, ok := obj.(*corev1.Namespace)
+			Expect(ok).To(BeTrue())
+
+			switch key.Name {
+			case "space-guid":
+				namespace.Labels = map[string]string{korifiv1alpha1.OrgGUIDKey: "org-guid"}
+				namespace.Annotations = map[string]string{korifiv1alpha1.SpaceNameKey: "My Space"}
+			case "org-guid":
+				namespace.Annotations = map[string]string{korifiv1alpha1.OrgNameKey: "ACME_Corp!"}
+			default:
+				return errors.New("not-found")
+			}
+
+			return nil
+		}
+		template = ""
+	})
+
+	JustBeforeEach(func() {
+		namer, newErr = registry.NewRepositoryNamer(k8sReader, "my.registry/korifi/", template)
+	})
+
+	It("names the repositories after the app guid by default", func() {
+		Expect(newErr).NotTo(HaveOccurred())
+
+		packagesRepo, err := namer.PackagesRepository(context.Background(), "space-guid", "app-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(packagesRepo).To(Equal("my.registry/korifi/app-guid-packages"))
+
+		dropletsRepo, err := namer.DropletsRepository(context.Background(), "space-guid", "app-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dropletsRepo).To(Equal("my.registry/korifi/app-guid-droplets"))
+	})
+
+	It("does not look up namespaces", func() {
+		_, err := namer.PackagesRepository(context.Background(), "space-guid", "app-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sReader.GetCallCount()).To(BeZero())
+	})
+
+	When("the template refers to guids only", func() {
+		BeforeEach(func() {
+			template = "{registryBase}/{spaceGUID}/{appGUID}"
+		})
+
+		It("renders the template without looking up namespaces", func() {
+			repo, err := namer.DropletsRepository(context.Background(), "space-guid", "app-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo).To(Equal("my.registry/korifi/space-guid/app-guid-droplets"))
+			Expect(k8sReader.GetCallCount()).To(BeZero())
+		})
+	})
+
+	When("the template refers to org and space names", func() {
+		BeforeEach(func() {
+			template = "{registryBase}/{orgName}/{spaceName}/{appGUID}"
+		})
+
+		It("renders the names as valid repository path components", func() {
+			repo, err := namer.PackagesRepository(context.Background(), "space-guid", "app-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo).To(Equal("my.registry/korifi/acme_corp/my-space/app-guid-packages"))
+		})
+
+		When("the space namespace cannot be read", func() {
+			It("returns an error", func() {
+				_, err := namer.PackagesRepository(context.Background(), "unknown-space-guid", "app-guid")
+				Expect(err).To(MatchError(ContainSubstring("not-found")))
+			})
+		})
+	})
+
+	When("the template refers to the org guid", func() {
+		BeforeEach(func() {
+			template = "{registryBase}{orgGUID}/{appGUID}"
+		})
+
+		It("reads the org guid from the space namespace", func() {
+			repo, err := namer.PackagesRepository(context.Background(), "space-guid", "app-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo).To(Equal("my.registry/korifi/org-guid/app-guid-packages"))
+			Expect(k8sReader.GetCallCount()).To(Equal(1))
+		})
+	})
+
+	When("the template contains an unknown placeholder", func() {
+		BeforeEach(func() {
+			template = "{registryBase}{appName}/{appGUID}"
+		})
+
+		It("returns an error", func() {
+			Expect(newErr).To(MatchError(ContainSubstring(`unknown placeholder "{appName}"`)))
+		})
+	})
+
+	When("the template does not contain the app guid", func() {
+		BeforeEach(func() {
+			template = "{registryBase}{spaceGUID}"
+		})
+
+		It("returns an error", func() {
+			Expect(newErr).To(MatchError(ContainSubstring("must contain the {appGUID} placeholder")))
+		})
+	})
+})
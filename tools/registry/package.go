@@ -1,3 +1,5 @@
 package registry
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+//counterfeiter:generate -o fake -fake-name Reader sigs.k8s.io/controller-runtime/pkg/client.Reader
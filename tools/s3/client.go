@@ -0,0 +1,184 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+const (
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+
+	// PresignedURLExpiry is how long presigned download URLs are valid. It
+	// must cover the time a build waits to be scheduled and stays below the
+	// one week limit of signature version 4.
+	PresignedURLExpiry = 24 * time.Hour
+)
+
+// Client uploads, downloads and deletes the objects of a bucket in an
+// S3-compatible blobstore (e.g. AWS S3, GCS in interoperability mode or
+// MinIO). Objects are addressed path-style, i.e. <endpoint>/<bucket>/<key>,
+// which every S3-compatible store supports. The objects of a space are stored
+// under the <space-guid>/ prefix, so that a space cannot access the objects of
+// another space by their URL.
+type Client struct {
+	httpClient  *http.Client
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	endpoint    *url.URL
+	bucket      string
+	region      string
+}
+
+func NewClient(
+	httpClient *http.Client,
+	credentials aws.CredentialsProvider,
+	endpoint string,
+	bucket string,
+	region string,
+) (*Client, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blobstore endpoint %q: %w", endpoint, err)
+	}
+
+	return &Client{
+		httpClient:  httpClient,
+		credentials: credentials,
+		signer:      v4.NewSigner(),
+		endpoint:    endpointURL,
+		bucket:      bucket,
+		region:      region,
+	}, nil
+}
+
+// ObjectURL returns the URL of the object of the space
+func (c *Client) ObjectURL(spaceGUID, name string) string {
+	return c.endpoint.JoinPath(c.bucket, spaceGUID, name).String()
+}
+
+func (c *Client) Put(ctx context.Context, objectURL string, contentType string, body io.Reader, size int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	return c.do(ctx, req, http.StatusOK)
+}
+
+// Delete deletes the object of the space at objectURL. Deleting an object
+// that does not exist succeeds.
+func (c *Client) Delete(ctx context.Context, spaceGUID, objectURL string) error {
+	if err := c.checkSpaceObject(spaceGUID, objectURL); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, objectURL, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.do(ctx, req, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+}
+
+// PresignGet returns a URL the object of the space at objectURL can be
+// downloaded from without credentials until PresignedURLExpiry has passed
+func (c *Client) PresignGet(ctx context.Context, spaceGUID, objectURL string) (string, error) {
+	if err := c.checkSpaceObject(spaceGUID, objectURL); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, objectURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	query := req.URL.Query()
+	query.Set("X-Amz-Expires", strconv.Itoa(int(PresignedURLExpiry.Seconds())))
+	req.URL.RawQuery = query.Encode()
+
+	creds, err := c.credentials.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve blobstore credentials: %w", err)
+	}
+
+	presignedURL, _, err := c.signer.PresignHTTP(ctx, creds, req, unsignedPayload, "s3", c.region, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("failed to presign %q: %w", objectURL, err)
+	}
+
+	return presignedURL, nil
+}
+
+// checkSpaceObject also fails on a nil client, which controllers get when no
+// blobstore is configured
+func (c *Client) checkSpaceObject(spaceGUID, objectURL string) error {
+	if c == nil {
+		return errors.New("no s3 blobstore is configured")
+	}
+
+	u, err := url.Parse(objectURL)
+	if err != nil {
+		return fmt.Errorf("invalid object url %q: %w", objectURL, err)
+	}
+
+	spacePrefix := path.Join("/", c.endpoint.Path, c.bucket, spaceGUID) + "/"
+	if u.Scheme != c.endpoint.Scheme || u.Host != c.endpoint.Host || u.RawQuery != "" ||
+		u.Path != path.Clean(u.Path) || !strings.HasPrefix(u.Path, spacePrefix) {
+		return fmt.Errorf("%q is not an object of space %q in the blobstore", objectURL, spaceGUID)
+	}
+
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, expectedStatuses ...int) error {
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	creds, err := c.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve blobstore credentials: %w", err)
+	}
+
+	if err = c.signer.SignHTTP(ctx, creds, req, unsignedPayload, "s3", c.region, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	for _, status := range expectedStatuses {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, respBody)
+}
+
+// NewDefaultClient returns a client using the default AWS credentials chain,
+// e.g. the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables
+func NewDefaultClient(ctx context.Context, endpoint, bucket, region string) (*Client, error) {
+	awsConfig, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("could not load blobstore credentials: %w", err)
+	}
+
+	return NewClient(&http.Client{}, awsConfig.Credentials, endpoint, bucket, region)
+}
@@ -0,0 +1,196 @@
+package s3_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"code.cloudfoundry.org/korifi/tools/s3"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		blobstoreServer *httptest.Server
+		responseStatus  int
+		requests        []*http.Request
+		bodies          []string
+		client          *s3.Client
+		objectURL       string
+	)
+
+	BeforeEach(func() {
+		responseStatus = http.StatusOK
+		requests = nil
+		bodies = nil
+		blobstoreServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+
+			body, err := io.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			requests = append(requests, r)
+			bodies = append(bodies, string(body))
+
+			w.WriteHeader(responseStatus)
+		}))
+		DeferCleanup(blobstoreServer.Close)
+
+		var err error
+		client, err = s3.NewClient(
+			blobstoreServer.Client(),
+			credentials.NewStaticCredentialsProvider("the-access-key", "the-secret-key", ""),
+			blobstoreServer.URL,
+			"the-bucket",
+			"the-region",
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		objectURL = client.ObjectURL("the-space", "the-object.zip")
+	})
+
+	Describe("ObjectURL", func() {
+		It("addresses the object path-style, under the space prefix", func() {
+			Expect(objectURL).To(Equal(blobstoreServer.URL + "/the-bucket/the-space/the-object.zip"))
+		})
+	})
+
+	Describe("Put", func() {
+		var putErr error
+
+		JustBeforeEach(func() {
+			putErr = client.Put(context.Background(), objectURL, "application/zip", strings.NewReader("the-bits"), int64(len("the-bits")))
+		})
+
+		It("uploads the object with a signed request", func() {
+			Expect(putErr).NotTo(HaveOccurred())
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Method).To(Equal(http.MethodPut))
+			Expect(requests[0].URL.Path).To(Equal("/the-bucket/the-space/the-object.zip"))
+			Expect(requests[0].Header.Get("Content-Type")).To(Equal("application/zip"))
+			Expect(requests[0].Header.Get("Authorization")).To(HavePrefix("AWS4-HMAC-SHA256 Credential=the-access-key/"))
+			Expect(requests[0].Header.Get("Authorization")).To(ContainSubstring("/the-region/s3/aws4_request"))
+			Expect(bodies[0]).To(Equal("the-bits"))
+		})
+
+		When("the blobstore rejects the upload", func() {
+			BeforeEach(func() {
+				responseStatus = http.StatusForbidden
+			})
+
+			It("returns an error", func() {
+				Expect(putErr).To(MatchError(ContainSubstring("unexpected status 403")))
+			})
+		})
+	})
+
+	Describe("Delete", func() {
+		var deleteErr error
+
+		BeforeEach(func() {
+			responseStatus = http.StatusNoContent
+		})
+
+		JustBeforeEach(func() {
+			deleteErr = client.Delete(context.Background(), "the-space", objectURL)
+		})
+
+		It("deletes the object with a signed request", func() {
+			Expect(deleteErr).NotTo(HaveOccurred())
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Method).To(Equal(http.MethodDelete))
+			Expect(requests[0].URL.Path).To(Equal("/the-bucket/the-space/the-object.zip"))
+			Expect(requests[0].Header.Get("Authorization")).To(HavePrefix("AWS4-HMAC-SHA256 Credential=the-access-key/"))
+		})
+
+		When("the object does not exist", func() {
+			BeforeEach(func() {
+				responseStatus = http.StatusNotFound
+			})
+
+			It("succeeds", func() {
+				Expect(deleteErr).NotTo(HaveOccurred())
+			})
+		})
+
+		When("the object belongs to another space", func() {
+			BeforeEach(func() {
+				objectURL = client.ObjectURL("another-space", "the-object.zip")
+			})
+
+			It("refuses to delete it", func() {
+				Expect(deleteErr).To(MatchError(ContainSubstring("is not an object of space")))
+				Expect(requests).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("PresignGet", func() {
+		var (
+			presignedURL string
+			presignErr   error
+		)
+
+		JustBeforeEach(func() {
+			presignedURL, presignErr = client.PresignGet(context.Background(), "the-space", objectURL)
+		})
+
+		It("returns a url of the object that is valid for a day", func() {
+			Expect(presignErr).NotTo(HaveOccurred())
+			Expect(presignedURL).To(HavePrefix(objectURL + "?"))
+
+			parsedURL, err := url.Parse(presignedURL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsedURL.Query().Get("X-Amz-Expires")).To(Equal("86400"))
+			Expect(parsedURL.Query().Get("X-Amz-Credential")).To(HavePrefix("the-access-key/"))
+			Expect(parsedURL.Query().Get("X-Amz-Signature")).NotTo(BeEmpty())
+		})
+
+		It("does not contact the blobstore", func() {
+			Expect(requests).To(BeEmpty())
+		})
+
+		When("the object belongs to another space", func() {
+			BeforeEach(func() {
+				objectURL = client.ObjectURL("another-space", "the-object.zip")
+			})
+
+			It("returns an error", func() {
+				Expect(presignErr).To(MatchError(ContainSubstring("is not an object of space")))
+			})
+		})
+
+		When("the object url escapes the space prefix", func() {
+			BeforeEach(func() {
+				objectURL = blobstoreServer.URL + "/the-bucket/the-space/../another-space/the-object.zip"
+			})
+
+			It("returns an error", func() {
+				Expect(presignErr).To(MatchError(ContainSubstring("is not an object of space")))
+			})
+		})
+
+		When("the object is in another blobstore", func() {
+			BeforeEach(func() {
+				objectURL = "https://example.com/the-bucket/the-space/the-object.zip"
+			})
+
+			It("returns an error", func() {
+				Expect(presignErr).To(MatchError(ContainSubstring("is not an object of space")))
+			})
+		})
+	})
+})
+
+var _ = Describe("nil Client", func() {
+	It("fails to presign or delete objects", func() {
+		var client *s3.Client
+		_, err := client.PresignGet(context.Background(), "the-space", "https://s3/the-bucket/the-space/the-object.zip")
+		Expect(err).To(MatchError(ContainSubstring("no s3 blobstore is configured")))
+		Expect(client.Delete(context.Background(), "the-space", "https://s3/the-bucket/the-space/the-object.zip")).To(MatchError(ContainSubstring("no s3 blobstore is configured")))
+	})
+})